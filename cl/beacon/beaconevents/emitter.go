@@ -62,7 +62,9 @@ func (e *Emitters) Subscribe(topics []string, cb func(topic string, item any)) (
 	for _, v := range topics {
 		sub.topics[v] = struct{}{}
 	}
+	e.mu.Lock()
 	e.cbs[subid] = sub
+	e.mu.Unlock()
 	return func() {
 		e.mu.Lock()
 		defer e.mu.Unlock()
@@ -7,9 +7,8 @@ import (
 	"net/http"
 	"reflect"
 	"slices"
-	"strings"
 
-	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/fork_graph"
 	"github.com/ledgerwatch/log/v3"
 )
@@ -95,31 +94,37 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 			endpointError.WriteTo(w)
 			return
 		}
-		// TODO: potentially add a context option to buffer these
-		contentType := r.Header.Get("Accept")
-		contentTypes := strings.Split(contentType, ",")
-
 		// early return for event stream
-		if slices.Contains(w.Header().Values("Content-Type"), "text/event-stream") {
+		if slices.Contains(w.Header().Values("Content-Type"), ContentTypeEventStream) {
+			return
+		}
+		contentType, ok := NegotiateContentType(r.Header.Get("Accept"))
+		if !ok {
+			http.Error(w, "content type must be application/json, application/octet-stream, or text/event-stream", http.StatusNotAcceptable)
 			return
 		}
-		switch {
-		case slices.Contains(contentTypes, "application/octet-stream"):
-			sszMarshaler, ok := any(ans).(ssz.Marshaler)
-			if !ok {
-				NewEndpointError(http.StatusBadRequest, ErrorSszNotSupported).WriteTo(w)
+		if resp, ok := any(ans).(*BeaconResponse); ok && resp != nil && resp.Version != nil {
+			w.Header().Set("Eth-Consensus-Version", clparams.ClVersionToString(*resp.Version))
+		}
+		switch contentType {
+		case ContentTypeSSZ:
+			written, err := writeSSZ(w, ans)
+			if err == nil {
 				return
 			}
-			// TODO: we should probably figure out some way to stream this in the future :)
-			encoded, err := sszMarshaler.EncodeSSZ(nil)
-			if err != nil {
-				WrapEndpointError(err).WriteTo(w)
+			if written {
+				// the status is already sent
+				log.Warn("beaconapi failed to write ssz", "type", reflect.TypeOf(ans), "err", err)
 				return
 			}
-			w.Write(encoded)
-		case contentType == "*/*", contentType == "", slices.Contains(contentTypes, "text/html"), slices.Contains(contentTypes, "application/json"):
+			var endpointError *EndpointError
+			if !errors.As(err, &endpointError) {
+				endpointError = WrapEndpointError(err)
+			}
+			endpointError.WriteTo(w)
+		case ContentTypeJSON:
 			if !isNil(ans) {
-				w.Header().Add("content-type", "application/json")
+				w.Header().Add("content-type", ContentTypeJSON)
 				err := json.NewEncoder(w).Encode(ans)
 				if err != nil {
 					// this error is fatal, log to console
@@ -128,10 +133,6 @@ func HandleEndpoint[T any](h EndpointHandler[T]) http.HandlerFunc {
 			} else {
 				w.WriteHeader(200)
 			}
-		case slices.Contains(contentTypes, "text/event-stream"):
-			return
-		default:
-			http.Error(w, "content type must be application/json, application/octet-stream, or text/event-stream", http.StatusBadRequest)
 		}
 	})
 }
@@ -2,6 +2,7 @@ package beaconhttp
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
@@ -96,3 +97,16 @@ func (b *BeaconResponse) EncodingSizeSSZ() int {
 	}
 	return marshaler.EncodingSizeSSZ()
 }
+
+// WriteSSZ writes the ssz encoding of the data, data implementing SSZWriter is streamed
+func (b *BeaconResponse) WriteSSZ(w io.Writer) error {
+	if writer, ok := b.Data.(SSZWriter); ok {
+		return writer.WriteSSZ(w)
+	}
+	encoded, err := b.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
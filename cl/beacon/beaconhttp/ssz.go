@@ -0,0 +1,164 @@
+package beaconhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+)
+
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeSSZ         = "application/octet-stream"
+	ContentTypeEventStream = "text/event-stream"
+
+	// maxSSZRequestSize - limit of ssz request bodies, blocks with blobs are the largest objects submitted
+	maxSSZRequestSize = 64 << 20
+)
+
+// SSZWriter is implemented by objects too large to be encoded in memory at once, such as beacon states
+type SSZWriter interface {
+	WriteSSZ(w io.Writer) error
+}
+
+// NegotiateContentType picks the response content type from the Accept header: the supported type
+// with the highest quality, json is preferred on ties. Returns false if none of the types is acceptable.
+func NegotiateContentType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeJSON, true
+	}
+	supported := []string{ContentTypeJSON, ContentTypeSSZ, ContentTypeEventStream}
+	best, bestQuality := "", 0.0
+	for _, contentType := range supported {
+		if quality := acceptQuality(accept, contentType); quality > bestQuality {
+			best, bestQuality = contentType, quality
+		}
+	}
+	return best, best != ""
+}
+
+// acceptQuality returns the quality of the content type in the Accept header, the most specific media range wins
+func acceptQuality(accept string, contentType string) float64 {
+	quality, specificity := 0.0, -1
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch {
+		case mediaType == contentType:
+			s = 2
+		case mediaType == "*/*":
+			s = 0
+		case strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mediaType, "*")):
+			s = 1
+		case mediaType == "text/html" && contentType == ContentTypeJSON:
+			// browsers
+			s = 1
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		quality, specificity = q, s
+	}
+	return quality
+}
+
+// IsSSZRequest reports whether the request body is ssz encoded
+func IsSSZRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == ContentTypeSSZ
+}
+
+// DecodeRequestBody decodes the request body into v from ssz if the request content type is
+// application/octet-stream, from json otherwise.
+func DecodeRequestBody(r *http.Request, v ssz.Unmarshaler, version int) error {
+	if !IsSSZRequest(r) {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+	body, err := readSSZBody(r)
+	if err != nil {
+		return err
+	}
+	return v.DecodeSSZ(body, version)
+}
+
+// DecodeRequestList decodes a list of objects from json or ssz, like DecodeRequestBody. elementSize is
+// the ssz size of the elements of static size, zero for elements of variable size.
+func DecodeRequestList[T ssz.Unmarshaler](r *http.Request, limit uint64, elementSize int, version int) ([]T, error) {
+	if !IsSSZRequest(r) {
+		var list []T
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	body, err := readSSZBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if elementSize > 0 {
+		return ssz.DecodeStaticList[T](body, 0, uint32(len(body)), uint32(elementSize), limit, version)
+	}
+	return ssz.DecodeDynamicList[T](body, 0, uint32(len(body)), limit, version)
+}
+
+func readSSZBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSSZRequestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSSZRequestSize {
+		return nil, fmt.Errorf("ssz request body exceeds %d bytes", maxSSZRequestSize)
+	}
+	return body, nil
+}
+
+// sszResponseWriter sets the content type on the first write, so errors occurring before anything is written
+// may still be reported as json
+type sszResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *sszResponseWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.Header().Set("Content-Type", ContentTypeSSZ)
+		w.written = true
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// writeSSZ writes the ssz response, large objects are streamed. Returns whether anything was written.
+func writeSSZ(w http.ResponseWriter, ans any) (bool, error) {
+	sw := &sszResponseWriter{ResponseWriter: w}
+	if writer, ok := ans.(SSZWriter); ok {
+		err := writer.WriteSSZ(sw)
+		return sw.written, err
+	}
+	marshaler, ok := ans.(ssz.Marshaler)
+	if !ok {
+		return false, NewEndpointError(http.StatusBadRequest, ErrorSszNotSupported)
+	}
+	encoded, err := marshaler.EncodeSSZ(nil)
+	if err != nil {
+		return false, err
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(encoded)))
+	_, err = sw.Write(encoded)
+	return true, err
+}
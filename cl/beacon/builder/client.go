@@ -0,0 +1,169 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const (
+	getHeaderTimeout   = time.Second // bids arriving later than this are too late for the slot anyway
+	requestTimeout     = 10 * time.Second
+	maxResponseSize    = 16 * 1024 * 1024 // a deneb payload with blobs is well below
+	consensusVersionHd = "Eth-Consensus-Version"
+)
+
+// relayClient talks to a single relay.
+type relayClient struct {
+	httpClient   *http.Client
+	url          *url.URL
+	beaconConfig *clparams.BeaconChainConfig
+	// pubKey - the relay's builder public key given in its url, if any: bids signed by another key are refused
+	pubKey libcommon.Bytes48
+	// bidDomain - DOMAIN_APPLICATION_BUILDER, bids are signed with the genesis fork version and a zero genesis validators root
+	bidDomain []byte
+}
+
+func newRelayClient(rawUrl string, beaconConfig *clparams.BeaconChainConfig) (*relayClient, error) {
+	u, err := url.Parse(strings.TrimSuffix(rawUrl, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid relay url %q: %w", rawUrl, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid relay url %q: scheme must be http or https", rawUrl)
+	}
+	// relays are given as https://<relay pubkey>@<host>, the pubkey is not part of requests
+	var pubKey libcommon.Bytes48
+	if u.User != nil {
+		if err := pubKey.UnmarshalText([]byte(u.User.Username())); err != nil {
+			return nil, fmt.Errorf("invalid relay url %q: pubkey: %w", rawUrl, err)
+		}
+	}
+	u.User = nil
+	bidDomain, err := fork.ComputeDomain(beaconConfig.DomainApplicationBuilder[:], utils.Uint32ToBytes4(uint32(beaconConfig.GenesisForkVersion)), libcommon.Hash{})
+	if err != nil {
+		return nil, err
+	}
+	return &relayClient{
+		httpClient:   &http.Client{Timeout: requestTimeout},
+		url:          u,
+		beaconConfig: beaconConfig,
+		pubKey:       pubKey,
+		bidDomain:    bidDomain,
+	}, nil
+}
+
+func (r *relayClient) String() string {
+	return r.url.Host
+}
+
+func (r *relayClient) RegisterValidator(ctx context.Context, registrations []*cltypes.ValidatorRegistration) error {
+	body, err := json.Marshal(registrations)
+	if err != nil {
+		return err
+	}
+	_, _, err = r.do(ctx, http.MethodPost, "/eth/v1/builder/validators", body, nil)
+	return err
+}
+
+func (r *relayClient) GetHeader(ctx context.Context, slot uint64, parentHash libcommon.Hash, pubKey libcommon.Bytes48) (*ExecutionHeader, error) {
+	ctx, cancel := context.WithTimeout(ctx, getHeaderTimeout)
+	defer cancel()
+	path := fmt.Sprintf("/eth/v1/builder/header/%d/%s/%s", slot, parentHash.Hex(), pubKey.Hex())
+	status, body, err := r.do(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNoContent {
+		return nil, nil
+	}
+	header := &ExecutionHeader{}
+	if err := json.Unmarshal(body, header); err != nil {
+		return nil, fmt.Errorf("decoding bid: %w", err)
+	}
+	if err := r.verifyBid(header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// verifyBid checks the builder's signature of the bid.
+func (r *relayClient) verifyBid(bid *ExecutionHeader) error {
+	if r.pubKey != (libcommon.Bytes48{}) && bid.PubKey != r.pubKey {
+		return fmt.Errorf("relay %s: bid signed by %x instead of %x", r, bid.PubKey, r.pubKey)
+	}
+	signingRoot, err := fork.ComputeSigningRoot(bid, r.bidDomain)
+	if err != nil {
+		return err
+	}
+	ok, err := bls.Verify(bid.Signature[:], signingRoot[:], bid.PubKey[:])
+	if err != nil {
+		return fmt.Errorf("relay %s: verifying bid signature: %w", r, err)
+	}
+	if !ok {
+		return fmt.Errorf("relay %s: %w", r, errInvalidBidSignature)
+	}
+	return nil
+}
+
+func (r *relayClient) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*BlindedBlockResponse, error) {
+	body, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{consensusVersionHd: clparams.ClVersionToString(block.Version())}
+	_, respBody, err := r.do(ctx, http.MethodPost, "/eth/v1/builder/blinded_blocks", body, headers)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := decodeBlindedBlockResponse(respBody, r.beaconConfig)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *relayClient) GetStatus(ctx context.Context) error {
+	_, _, err := r.do(ctx, http.MethodGet, "/eth/v1/builder/status", nil, nil)
+	return err
+}
+
+func (r *relayClient) do(ctx context.Context, method, path string, body []byte, headers map[string]string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.url.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, nil, fmt.Errorf("relay %s: %s %s: %d %s", r, method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return resp.StatusCode, respBody, nil
+}
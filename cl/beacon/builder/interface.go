@@ -0,0 +1,20 @@
+package builder
+
+import (
+	"context"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+// BuilderClient is the proposer side of the builder API (https://github.com/ethereum/builder-specs).
+type BuilderClient interface {
+	// RegisterValidator forwards validators' registrations, so that builders pay to their fee recipients.
+	RegisterValidator(ctx context.Context, registrations []*cltypes.ValidatorRegistration) error
+	// GetHeader returns the best bid for the slot on top of parentHash, or nil if there is none.
+	GetHeader(ctx context.Context, slot uint64, parentHash libcommon.Hash, pubKey libcommon.Bytes48) (*ExecutionHeader, error)
+	// SubmitBlindedBlocks submits a signed blinded block and returns the payload it commits to.
+	SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*BlindedBlockResponse, error)
+	// GetStatus returns an error if no relay is available.
+	GetStatus(ctx context.Context) error
+}
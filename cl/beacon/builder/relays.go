@@ -0,0 +1,174 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+var (
+	errNoRelays            = errors.New("no relays configured")
+	errInvalidBidSignature = errors.New("invalid bid signature")
+)
+
+// relays fans requests out to all configured relays: registrations go to every relay, the highest bid wins
+// and the blinded block is submitted to the relays which offered it.
+type relays struct {
+	clients []*relayClient
+	minBid  *big.Int // bids below are ignored
+	logger  log.Logger
+
+	mu        sync.Mutex
+	bidsSlot  uint64
+	bidRelays map[libcommon.Hash][]*relayClient // block hash -> relays which offered it in bidsSlot
+}
+
+// NewBlockBuilderClient creates a client of the relays at relayUrls. minBid (wei) is the value below which bids are
+// ignored in favour of the local payload.
+func NewBlockBuilderClient(relayUrls []string, minBid *big.Int, beaconConfig *clparams.BeaconChainConfig, logger log.Logger) (BuilderClient, error) {
+	if len(relayUrls) == 0 {
+		return nil, errNoRelays
+	}
+	clients := make([]*relayClient, 0, len(relayUrls))
+	for _, u := range relayUrls {
+		c, err := newRelayClient(u, beaconConfig)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	if minBid == nil {
+		minBid = new(big.Int)
+	}
+	return &relays{
+		clients:   clients,
+		minBid:    minBid,
+		logger:    logger,
+		bidRelays: map[libcommon.Hash][]*relayClient{},
+	}, nil
+}
+
+// forEach runs f against all relays concurrently and returns their errors, in relays order.
+func forEach(clients []*relayClient, f func(i int, c *relayClient) error) []error {
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c *relayClient) {
+			defer wg.Done()
+			errs[i] = f(i, c)
+		}(i, c)
+	}
+	wg.Wait()
+	return errs
+}
+
+// anySucceeded returns nil if at least one relay succeeded, otherwise all errors joined.
+func anySucceeded(errs []error) error {
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *relays) RegisterValidator(ctx context.Context, registrations []*cltypes.ValidatorRegistration) error {
+	errs := forEach(r.clients, func(_ int, c *relayClient) error {
+		return c.RegisterValidator(ctx, registrations)
+	})
+	for i, err := range errs {
+		if err != nil {
+			r.logger.Debug("[Builder] Failed to register validators", "relay", r.clients[i], "count", len(registrations), "err", err)
+		}
+	}
+	return anySucceeded(errs)
+}
+
+func (r *relays) GetHeader(ctx context.Context, slot uint64, parentHash libcommon.Hash, pubKey libcommon.Bytes48) (*ExecutionHeader, error) {
+	bids := make([]*ExecutionHeader, len(r.clients))
+	errs := forEach(r.clients, func(i int, c *relayClient) (err error) {
+		bids[i], err = c.GetHeader(ctx, slot, parentHash, pubKey)
+		return err
+	})
+
+	var best *ExecutionHeader
+	offeredBy := map[libcommon.Hash][]*relayClient{}
+	for i, bid := range bids {
+		if errs[i] != nil {
+			r.logger.Debug("[Builder] Failed to get bid", "relay", r.clients[i], "slot", slot, "err", errs[i])
+			continue
+		}
+		if bid == nil {
+			continue
+		}
+		if bid.Header.ParentHash != parentHash {
+			r.logger.Debug("[Builder] Ignoring bid on top of a wrong parent", "relay", r.clients[i], "slot", slot, "parent", bid.Header.ParentHash)
+			continue
+		}
+		if bid.Value.Cmp(r.minBid) < 0 {
+			r.logger.Debug("[Builder] Ignoring bid below minimum", "relay", r.clients[i], "slot", slot, "value", bid.Value)
+			continue
+		}
+		offeredBy[bid.Header.BlockHash] = append(offeredBy[bid.Header.BlockHash], r.clients[i])
+		if best == nil || bid.Value.Cmp(best.Value) > 0 {
+			best = bid
+		}
+	}
+	if best == nil {
+		return nil, anySucceeded(errs)
+	}
+
+	r.mu.Lock()
+	if slot != r.bidsSlot {
+		r.bidsSlot = slot
+		r.bidRelays = map[libcommon.Hash][]*relayClient{}
+	}
+	for blockHash, clients := range offeredBy {
+		r.bidRelays[blockHash] = append(r.bidRelays[blockHash], clients...)
+	}
+	r.mu.Unlock()
+	return best, nil
+}
+
+func (r *relays) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*BlindedBlockResponse, error) {
+	blockHash := block.Block.Body.ExecutionPayload.BlockHash
+	r.mu.Lock()
+	clients := r.bidRelays[blockHash]
+	r.mu.Unlock()
+	if len(clients) == 0 {
+		// e.g. the bid was fetched before a restart, any relay knowing the payload can reveal it
+		clients = r.clients
+	}
+
+	responses := make([]*BlindedBlockResponse, len(clients))
+	errs := forEach(clients, func(i int, c *relayClient) (err error) {
+		responses[i], err = c.SubmitBlindedBlocks(ctx, block)
+		return err
+	})
+	for i, resp := range responses {
+		if errs[i] != nil {
+			continue
+		}
+		if resp.ExecutionPayload.BlockHash != blockHash {
+			errs[i] = fmt.Errorf("relay %s revealed payload %x instead of %x", clients[i], resp.ExecutionPayload.BlockHash, blockHash)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (r *relays) GetStatus(ctx context.Context) error {
+	return anySucceeded(forEach(r.clients, func(_ int, c *relayClient) error {
+		return c.GetStatus(ctx)
+	}))
+}
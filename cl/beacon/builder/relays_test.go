@@ -0,0 +1,193 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+type testRelay struct {
+	*httptest.Server
+	pubKey       libcommon.Bytes48
+	registered   atomic.Int64
+	submitted    atomic.Int64
+	badSignature atomic.Bool // bids are signed by another key
+}
+
+// newTestRelay serves a bid of the given value for a payload with blockHash, and reveals it when submitted.
+func newTestRelay(t *testing.T, parentHash, blockHash libcommon.Hash, value string) *testRelay {
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := bls.GenerateKey()
+	require.NoError(t, err)
+	relay := &testRelay{}
+	copy(relay.pubKey[:], bls.CompressPublicKey(key.PublicKey()))
+	domain, err := fork.ComputeDomain(clparams.MainnetBeaconConfig.DomainApplicationBuilder[:], utils.Uint32ToBytes4(uint32(clparams.MainnetBeaconConfig.GenesisForkVersion)), libcommon.Hash{})
+	require.NoError(t, err)
+	relay.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/eth/v1/builder/validators":
+			var registrations []*cltypes.ValidatorRegistration
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&registrations))
+			relay.registered.Add(int64(len(registrations)))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/builder/header/"):
+			if value == "" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			header := cltypes.NewEth1Header(clparams.DenebVersion)
+			header.ParentHash = parentHash
+			header.BlockHash = blockHash
+			header.BaseFeePerGas[0] = 7
+			headerJson, err := header.MarshalJSON()
+			require.NoError(t, err)
+			bidValue, _ := new(big.Int).SetString(value, 10)
+			signingRoot, err := fork.ComputeSigningRoot(&ExecutionHeader{
+				Version:            clparams.DenebVersion,
+				Header:             header,
+				BlobKzgCommitments: solid.NewStaticListSSZ[*cltypes.KZGCommitment](cltypes.MaxBlobsCommittmentsPerBlock, 48),
+				Value:              bidValue,
+				PubKey:             relay.pubKey,
+			}, domain)
+			require.NoError(t, err)
+			signer := key
+			if relay.badSignature.Load() {
+				signer = otherKey
+			}
+			_, err = io.WriteString(w, `{"version":"deneb","data":{"message":{"header":`+string(headerJson)+
+				`,"blob_kzg_commitments":[],"value":"`+value+`","pubkey":"`+relay.pubKey.Hex()+`"},"signature":"`+hexutility.Encode(signer.Sign(signingRoot[:]).Bytes())+`"}}`)
+			require.NoError(t, err)
+		case r.URL.Path == "/eth/v1/builder/blinded_blocks":
+			require.Equal(t, "deneb", r.Header.Get("Eth-Consensus-Version"))
+			relay.submitted.Add(1)
+			payload := cltypes.NewEth1Block(clparams.DenebVersion, &clparams.MainnetBeaconConfig)
+			payload.ParentHash = parentHash
+			payload.BlockHash = blockHash
+			payloadJson, err := payload.MarshalJSON()
+			require.NoError(t, err)
+			_, err = io.WriteString(w, `{"version":"deneb","data":{"execution_payload":`+string(payloadJson)+
+				`,"blobs_bundle":{"commitments":[],"proofs":[],"blobs":[]}}}`)
+			require.NoError(t, err)
+		case r.URL.Path == "/eth/v1/builder/status":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(relay.Close)
+	return relay
+}
+
+func blindedBlock(blockHash libcommon.Hash) *cltypes.SignedBlindedBeaconBlock {
+	block := cltypes.NewSignedBlindedBeaconBlock(&clparams.MainnetBeaconConfig)
+	block.Block.Body.Version = clparams.DenebVersion
+	block.Block.Body.ExecutionPayload = cltypes.NewEth1Header(clparams.DenebVersion)
+	block.Block.Body.ExecutionPayload.BlockHash = blockHash
+	return block
+}
+
+func TestRelaysBestBid(t *testing.T) {
+	ctx := context.Background()
+	parentHash := libcommon.HexToHash("0x01")
+	lowHash, highHash := libcommon.HexToHash("0x02"), libcommon.HexToHash("0x03")
+
+	low := newTestRelay(t, parentHash, lowHash, "1000")
+	high := newTestRelay(t, parentHash, highHash, "2000")
+	empty := newTestRelay(t, parentHash, libcommon.Hash{}, "")
+
+	client, err := NewBlockBuilderClient([]string{low.URL, high.URL, empty.URL}, big.NewInt(500), &clparams.MainnetBeaconConfig, log.New())
+	require.NoError(t, err)
+	require.NoError(t, client.GetStatus(ctx))
+
+	require.NoError(t, client.RegisterValidator(ctx, []*cltypes.ValidatorRegistration{{}, {}}))
+	require.EqualValues(t, 2, low.registered.Load())
+	require.EqualValues(t, 2, high.registered.Load())
+
+	bid, err := client.GetHeader(ctx, 1, parentHash, libcommon.Bytes48{})
+	require.NoError(t, err)
+	require.NotNil(t, bid)
+	require.Equal(t, highHash, bid.Header.BlockHash)
+	require.Equal(t, big.NewInt(2000), bid.Value)
+
+	// only the relay which offered the payload reveals it
+	resp, err := client.SubmitBlindedBlocks(ctx, blindedBlock(highHash))
+	require.NoError(t, err)
+	require.Equal(t, highHash, resp.ExecutionPayload.BlockHash)
+	require.NotNil(t, resp.BlobsBundle)
+	require.EqualValues(t, 1, high.submitted.Load())
+	require.EqualValues(t, 0, low.submitted.Load())
+
+	// a relay revealing another payload is not trusted
+	_, err = client.SubmitBlindedBlocks(ctx, blindedBlock(libcommon.HexToHash("0x04")))
+	require.Error(t, err)
+}
+
+func TestRelaysMinBid(t *testing.T) {
+	ctx := context.Background()
+	parentHash := libcommon.HexToHash("0x01")
+	relay := newTestRelay(t, parentHash, libcommon.HexToHash("0x02"), "1000")
+
+	client, err := NewBlockBuilderClient([]string{relay.URL}, big.NewInt(1001), &clparams.MainnetBeaconConfig, log.New())
+	require.NoError(t, err)
+	bid, err := client.GetHeader(ctx, 1, parentHash, libcommon.Bytes48{})
+	require.NoError(t, err)
+	require.Nil(t, bid)
+
+	// bids on top of another parent are ignored
+	client, err = NewBlockBuilderClient([]string{relay.URL}, nil, &clparams.MainnetBeaconConfig, log.New())
+	require.NoError(t, err)
+	bid, err = client.GetHeader(ctx, 1, libcommon.HexToHash("0x05"), libcommon.Bytes48{})
+	require.NoError(t, err)
+	require.Nil(t, bid)
+}
+
+func TestRelaysBidSignature(t *testing.T) {
+	ctx := context.Background()
+	parentHash := libcommon.HexToHash("0x01")
+	honest := newTestRelay(t, parentHash, libcommon.HexToHash("0x02"), "1000")
+	forged := newTestRelay(t, parentHash, libcommon.HexToHash("0x03"), "2000")
+	forged.badSignature.Store(true)
+
+	client, err := NewBlockBuilderClient([]string{honest.URL, forged.URL}, nil, &clparams.MainnetBeaconConfig, log.New())
+	require.NoError(t, err)
+	bid, err := client.GetHeader(ctx, 1, parentHash, libcommon.Bytes48{})
+	require.NoError(t, err)
+	require.Equal(t, libcommon.HexToHash("0x02"), bid.Header.BlockHash)
+
+	// the bid must be signed by the builder key in the relay url
+	forged.badSignature.Store(false)
+	c, err := newRelayClient(strings.Replace(forged.URL, "://", "://"+honest.pubKey.Hex()+"@", 1), &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	_, err = c.GetHeader(ctx, 1, parentHash, libcommon.Bytes48{})
+	require.Error(t, err)
+	c, err = newRelayClient(strings.Replace(forged.URL, "://", "://"+forged.pubKey.Hex()+"@", 1), &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	bid, err = c.GetHeader(ctx, 1, parentHash, libcommon.Bytes48{})
+	require.NoError(t, err)
+	require.Equal(t, forged.pubKey, bid.PubKey)
+}
+
+func TestRelayUrl(t *testing.T) {
+	c, err := newRelayClient("https://0xac6e77dfe25ecd6110b8e780608cce0dab71fdd5ebea22a16c0205200f2f8e2e3ad3b71d3499c54ad14d6c21b41a37ae@relay.example.org/", &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	require.Equal(t, "https://relay.example.org", c.url.String())
+	_, err = newRelayClient("relay.example.org", &clparams.MainnetBeaconConfig)
+	require.Error(t, err)
+}
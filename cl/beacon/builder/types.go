@@ -0,0 +1,119 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+)
+
+// ExecutionHeader is a signed builder bid: the header of a payload offered by a builder and its value for the proposer.
+type ExecutionHeader struct {
+	Version clparams.StateVersion
+	Header  *cltypes.Eth1Header
+	// BlobKzgCommitments - commitments of the blobs of the payload, since deneb
+	BlobKzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment]
+	Value              *big.Int // wei
+	PubKey             libcommon.Bytes48
+	Signature          libcommon.Bytes96
+}
+
+func (h *ExecutionHeader) UnmarshalJSON(buf []byte) error {
+	var versioned struct {
+		Version string          `json:"version"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(buf, &versioned); err != nil {
+		return err
+	}
+	version, err := clparams.StringToClVersion(versioned.Version)
+	if err != nil {
+		return err
+	}
+	var bid struct {
+		Message struct {
+			Header             *cltypes.Eth1Header                    `json:"header"`
+			BlobKzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment] `json:"blob_kzg_commitments"`
+			Value              string                                 `json:"value"`
+			PubKey             libcommon.Bytes48                      `json:"pubkey"`
+		} `json:"message"`
+		Signature libcommon.Bytes96 `json:"signature"`
+	}
+	bid.Message.Header = cltypes.NewEth1Header(version)
+	bid.Message.BlobKzgCommitments = solid.NewStaticListSSZ[*cltypes.KZGCommitment](cltypes.MaxBlobsCommittmentsPerBlock, 48)
+	if err := json.Unmarshal(versioned.Data, &bid); err != nil {
+		return err
+	}
+	value, ok := new(big.Int).SetString(bid.Message.Value, 10)
+	if !ok {
+		return fmt.Errorf("invalid bid value: %q", bid.Message.Value)
+	}
+	h.Version = version
+	h.Header = bid.Message.Header
+	h.BlobKzgCommitments = bid.Message.BlobKzgCommitments
+	h.Value = value
+	h.PubKey = bid.Message.PubKey
+	h.Signature = bid.Signature
+	return nil
+}
+
+// HashSSZ returns the hash tree root of the BuilderBid message, the object signed by the builder.
+func (h *ExecutionHeader) HashSSZ() ([32]byte, error) {
+	if h.Value.Sign() < 0 || h.Value.BitLen() > 256 {
+		return [32]byte{}, fmt.Errorf("invalid bid value: %s", h.Value)
+	}
+	// value is an ssz uint256: little-endian
+	value := utils.ReverseOfByteSlice(h.Value.FillBytes(make([]byte, 32)))
+	schema := []interface{}{h.Header}
+	if h.Version >= clparams.DenebVersion {
+		schema = append(schema, h.BlobKzgCommitments)
+	}
+	schema = append(schema, value, h.PubKey[:])
+	return merkle_tree.HashTreeRoot(schema...)
+}
+
+// BlindedBlockResponse is the payload revealed by a relay for a signed blinded block.
+type BlindedBlockResponse struct {
+	ExecutionPayload *cltypes.Eth1Block
+	// BlobsBundle - blobs of the payload, nil before deneb
+	BlobsBundle *engine_types.BlobsBundleV1
+}
+
+func decodeBlindedBlockResponse(buf []byte, beaconCfg *clparams.BeaconChainConfig) (*BlindedBlockResponse, error) {
+	var versioned struct {
+		Version string          `json:"version"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(buf, &versioned); err != nil {
+		return nil, err
+	}
+	version, err := clparams.StringToClVersion(versioned.Version)
+	if err != nil {
+		return nil, err
+	}
+	payload := cltypes.NewEth1Block(version, beaconCfg)
+	if version < clparams.DenebVersion {
+		if err := json.Unmarshal(versioned.Data, payload); err != nil {
+			return nil, err
+		}
+		return &BlindedBlockResponse{ExecutionPayload: payload}, nil
+	}
+	resp := struct {
+		ExecutionPayload *cltypes.Eth1Block          `json:"execution_payload"`
+		BlobsBundle      *engine_types.BlobsBundleV1 `json:"blobs_bundle"`
+	}{ExecutionPayload: payload}
+	if err := json.Unmarshal(versioned.Data, &resp); err != nil {
+		return nil, err
+	}
+	if resp.BlobsBundle == nil {
+		resp.BlobsBundle = &engine_types.BlobsBundleV1{}
+	}
+	return &BlindedBlockResponse{ExecutionPayload: resp.ExecutionPayload, BlobsBundle: resp.BlobsBundle}, nil
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/phase1/network/services"
+)
+
+type attestationSeenCacheResponse struct {
+	MaxEpochs    uint64                          `json:"max_epochs,string"`
+	MaxEpochSize int                             `json:"max_epoch_size,string"`
+	Epochs       []services.SeenAttestationEpoch `json:"epochs"`
+}
+
+// GetEthV1DebugCaplinAttestationSeenCache - occupancy per epoch of the cache of attestations seen in gossip
+func (a *ApiHandler) GetEthV1DebugCaplinAttestationSeenCache(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	if a.seenAttestations == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("attestation seen cache is not available"))
+	}
+	maxEpochs, maxEpochSize := a.seenAttestations.Limits()
+	return newBeaconResponse(attestationSeenCacheResponse{
+		MaxEpochs:    maxEpochs,
+		MaxEpochSize: maxEpochSize,
+		Epochs:       a.seenAttestations.Occupancy(),
+	}), nil
+}
@@ -4,11 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
+	"math"
 	"net/http"
-	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -107,6 +105,13 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 	if !r.URL.Query().Has("graffiti") {
 		graffiti = libcommon.HexToHash(hex.EncodeToString([]byte(defaultGraffitiString)))
 	}
+	builderBoostFactor, err := beaconhttp.Uint64FromQueryParams(r, "builder_boost_factor")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if builderBoostFactor == nil {
+		builderBoostFactor = &defaultBuilderBoostFactor
+	}
 
 	tx, err := a.indiciesDB.BeginRo(ctx)
 	if err != nil {
@@ -163,6 +168,13 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 		return nil, err
 	}
 
+	proposerIndex, err := baseState.GetBeaconProposerIndex()
+	if err != nil {
+		return nil, err
+	}
+	// ask the relays for a bid while the local payload is being built
+	builderBidCh := a.requestBuilderBid(ctx, baseState, targetSlot, proposerIndex, *builderBoostFactor)
+
 	beaconBody, executionValue, err := a.produceBeaconBody(
 		ctx,
 		3,
@@ -175,10 +187,12 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 	if err != nil {
 		return nil, err
 	}
-
-	proposerIndex, err := baseState.GetBeaconProposerIndex()
-	if err != nil {
-		return nil, err
+	builderBid := <-builderBidCh
+	if !useBuilderBid(builderBid, executionValue, *builderBoostFactor) {
+		builderBid = nil
+	} else if err := a.setBuilderPayload(baseState, beaconBody, builderBid); err != nil {
+		log.Warn("BlockProduction: Invalid builder bid, using local payload", "slot", targetSlot, "err", err)
+		builderBid = nil
 	}
 
 	rewardsCollector := &eth2.BlockRewardsCollector{}
@@ -202,11 +216,32 @@ func (a *ApiHandler) GetEthV3ValidatorBlock(
 	if err := machine.ProcessBlock(transition.DefaultMachine, baseState, &cltypes.SignedBeaconBlock{Block: block}); err != nil {
 		return nil, err
 	}
+	if builderBid != nil {
+		// the block was processed with a stand-in of the builder's payload, the state commits to its header
+		baseState.SetLatestExecutionPayloadHeader(builderBid.Header)
+	}
 	block.StateRoot, err = baseState.HashSSZ()
 	if err != nil {
 		return nil, err
 	}
 	consensusValue := rewardsCollector.Attestations + rewardsCollector.ProposerSlashings + rewardsCollector.AttesterSlashings + rewardsCollector.SyncAggregate
+	if builderBid != nil {
+		blindedBlock, err := block.Blinded()
+		if err != nil {
+			return nil, err
+		}
+		blindedBlock.Body.ExecutionPayload = builderBid.Header
+		executionValue = math.MaxUint64
+		if builderBid.Value.IsUint64() {
+			executionValue = builderBid.Value.Uint64()
+		}
+		log.Info("BlockProduction: Using builder payload", "slot", targetSlot, "value", builderBid.Value, "blockHash", builderBid.Header.BlockHash)
+		a.setupHeaderReponseForBlockProduction(w, block.Version(), true, executionValue, consensusValue)
+		return newBeaconResponse(blindedBlock).
+			With("execution_payload_blinded", true).
+			With("execution_payload_value", builderBid.Value.String()).
+			With("consensus_block_value", strconv.FormatUint(consensusValue, 10)), nil
+	}
 	isSSZBlinded := false
 	a.setupHeaderReponseForBlockProduction(
 		w,
@@ -276,7 +311,11 @@ func (a *ApiHandler) produceBeaconBody(
 		timeoutForBlockBuilding := 2 * time.Second // keep asking for 2 seconds for block
 		retryTime := 10 * time.Millisecond
 		secsDiff := (targetSlot - baseBlock.Slot) * a.beaconChainCfg.SecondsPerSlot
-		feeRecipient, _ := a.validatorParams.GetFeeRecipient(proposerIndex)
+		proposerPubKey, err := baseState.ValidatorPublicKey(int(proposerIndex))
+		if err != nil {
+			log.Warn("BlockProduction: Failed to get proposer public key", "err", err)
+		}
+		feeRecipient, _ := a.validatorParams.GetProposerFeeRecipient(proposerIndex, proposerPubKey)
 		var withdrawals []*types.Withdrawal
 		clWithdrawals := state.ExpectedWithdrawals(
 			baseState,
@@ -291,12 +330,31 @@ func (a *ApiHandler) produceBeaconBody(
 			})
 		}
 
+		timestamp := latestExecutionPayload.Time + secsDiff
+		a.emitters.Publish("payload_attributes", map[string]any{
+			"version": clparams.ClVersionToString(stateVersion),
+			"data": map[string]any{
+				"proposer_index":      strconv.FormatUint(proposerIndex, 10),
+				"proposal_slot":       strconv.FormatUint(targetSlot, 10),
+				"parent_block_number": strconv.FormatUint(latestExecutionPayload.BlockNumber, 10),
+				"parent_block_root":   libcommon.Hash(blockRoot),
+				"parent_block_hash":   head,
+				"payload_attributes": map[string]any{
+					"timestamp":                strconv.FormatUint(timestamp, 10),
+					"prev_randao":              libcommon.Hash(random),
+					"suggested_fee_recipient":  feeRecipient,
+					"withdrawals":              clWithdrawals,
+					"parent_beacon_block_root": libcommon.Hash(blockRoot),
+				},
+			},
+		})
+
 		idBytes, err := a.engine.ForkChoiceUpdate(
 			ctx,
 			finalizedHash,
 			head,
 			&engine_types.PayloadAttributes{
-				Timestamp:             hexutil.Uint64(latestExecutionPayload.Time + secsDiff),
+				Timestamp:             hexutil.Uint64(timestamp),
 				PrevRandao:            random,
 				SuggestedFeeRecipient: feeRecipient,
 				Withdrawals:           withdrawals,
@@ -602,15 +660,7 @@ func (a *ApiHandler) parseRequestBeaconBlock(
 ) (*cltypes.SignedBeaconBlock, error) {
 	block := cltypes.NewSignedBeaconBlock(a.beaconChainCfg)
 	block.Block.Body.Version = version
-	// check content type
-	if r.Header.Get("Content-Type") == "application/json" {
-		return block, json.NewDecoder(r.Body).Decode(block)
-	}
-	octect, err := io.ReadAll(r.Body)
-	if err != nil {
-		return nil, err
-	}
-	if err := block.DecodeSSZ(octect, int(version)); err != nil {
+	if err := beaconhttp.DecodeRequestBody(r, block, int(version)); err != nil {
 		return nil, err
 	}
 	return block, nil
@@ -718,19 +768,14 @@ func (a *ApiHandler) storeBlockAndBlobs(
 	return a.forkchoiceStore.OnBlock(ctx, block, true, false, false)
 }
 
-type attestationCandidate struct {
-	attestation *solid.Attestation
-	reward      uint64
-}
-
+// findBestAttestationsForBlockProduction takes the aggregates in the packing order of the attestations pool
+// and keeps the ones which are valid and still rewarded against the block's pre-state.
 func (a *ApiHandler) findBestAttestationsForBlockProduction(
 	s abstract.BeaconState,
 ) *solid.ListSSZ[*solid.Attestation] {
 
 	ret := solid.NewDynamicListSSZ[*solid.Attestation](int(a.beaconChainCfg.MaxAttestations))
-	attestationCandidates := []attestationCandidate{}
-
-	for _, attestation := range a.operationsPool.AttestationsPool.Raw() {
+	for _, attestation := range a.operationsPool.BlockAttestationsPool.GetAttestationsForBlock(s.Slot()) {
 		if err := eth2.IsAttestationApplicable(s, attestation); err != nil {
 			continue // attestation not applicable skip
 		}
@@ -744,43 +789,9 @@ func (a *ApiHandler) findBestAttestationsForBlockProduction(
 			continue
 		}
 		if expectedReward == 0 {
-			continue
-		}
-		attestationCandidates = append(attestationCandidates, attestationCandidate{
-			attestation: attestation,
-			reward:      expectedReward,
-		})
-	}
-	// Rank by reward in descending order.
-	sort.Slice(attestationCandidates, func(i, j int) bool {
-		return attestationCandidates[i].reward > attestationCandidates[j].reward
-	})
-	// Some aggregates can be supersets of existing ones so let's filter out the supersets
-	// this MAP is HashTreeRoot(AttestationData) => AggregationBits
-	aggregationBitsByAttestationData := make(map[libcommon.Hash][]byte)
-	for _, candidate := range attestationCandidates {
-		// Check if it is a superset of a pre-included attestation with higher reward
-		attestationDataRoot, err := candidate.attestation.AttestantionData().HashSSZ()
-		if err != nil {
-			log.Warn("[Block Production] Cannot compute attestation data root", "err", err)
-			continue
+			continue // all votes are already included
 		}
-		currAggregationBits, exists := aggregationBitsByAttestationData[attestationDataRoot]
-		if exists {
-			if utils.IsNonStrictSupersetBitlist(
-				currAggregationBits,
-				candidate.attestation.AggregationBits(),
-			) {
-				continue
-			}
-			utils.MergeBitlists(currAggregationBits, candidate.attestation.AggregationBits())
-		} else {
-			currAggregationBits = candidate.attestation.AggregationBits()
-		}
-		// Update the currently built superset
-		aggregationBitsByAttestationData[attestationDataRoot] = currAggregationBits
-
-		ret.Append(candidate.attestation)
+		ret.Append(attestation)
 		if ret.Len() >= int(a.beaconChainCfg.MaxAttestations) {
 			break
 		}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/beacon/builder"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// defaultBuilderBoostFactor - builder bids are compared with the local payload at their face value (percent)
+var defaultBuilderBoostFactor uint64 = 100
+
+// requestBuilderBid asks the relays for a bid for the proposal of targetSlot. The returned channel yields nil if
+// builders are not used for the proposal, or there is no bid.
+func (a *ApiHandler) requestBuilderBid(
+	ctx context.Context,
+	s *state.CachingBeaconState,
+	targetSlot uint64,
+	proposerIndex uint64,
+	builderBoostFactor uint64,
+) <-chan *builder.ExecutionHeader {
+	bidCh := make(chan *builder.ExecutionHeader, 1)
+	if a.builderClient == nil || builderBoostFactor == 0 || s.Version() < clparams.BellatrixVersion {
+		close(bidCh)
+		return bidCh
+	}
+	pubKey, err := s.ValidatorPublicKey(int(proposerIndex))
+	if err != nil {
+		close(bidCh)
+		return bidCh
+	}
+	parentHash := s.LatestExecutionPayloadHeader().BlockHash
+	go func() {
+		defer close(bidCh)
+		bid, err := a.builderClient.GetHeader(ctx, targetSlot, parentHash, pubKey)
+		if err != nil {
+			log.Warn("BlockProduction: Failed to get builder bid", "slot", targetSlot, "err", err)
+			return
+		}
+		bidCh <- bid
+	}()
+	return bidCh
+}
+
+// useBuilderBid - the bid wins if, scaled by builderBoostFactor percent, it is worth more than the local payload.
+func useBuilderBid(bid *builder.ExecutionHeader, localValue uint64, builderBoostFactor uint64) bool {
+	if bid == nil {
+		return false
+	}
+	boosted := new(big.Int).Mul(bid.Value, new(big.Int).SetUint64(builderBoostFactor))
+	boosted.Div(boosted, big.NewInt(100))
+	return boosted.Cmp(new(big.Int).SetUint64(localValue)) > 0
+}
+
+// setBuilderPayload replaces the local payload of the body with a stand-in of the builder's one: it carries the
+// bid's header fields and the expected withdrawals, so that the block can be processed to compute the state root.
+func (a *ApiHandler) setBuilderPayload(s *state.CachingBeaconState, body *cltypes.BeaconBody, bid *builder.ExecutionHeader) error {
+	header := bid.Header
+	if bid.Version != body.Version {
+		return fmt.Errorf("bid version %s, expected %s", clparams.ClVersionToString(bid.Version), clparams.ClVersionToString(body.Version))
+	}
+	if header.ParentHash != s.LatestExecutionPayloadHeader().BlockHash {
+		return fmt.Errorf("bid parent hash %x, expected %x", header.ParentHash, s.LatestExecutionPayloadHeader().BlockHash)
+	}
+	if header.PrevRandao != s.GetRandaoMixes(state.Epoch(s)) {
+		return fmt.Errorf("bid prev randao %x, expected %x", header.PrevRandao, s.GetRandaoMixes(state.Epoch(s)))
+	}
+	if header.Time != state.ComputeTimestampAtSlot(s, s.Slot()) {
+		return fmt.Errorf("bid timestamp %d, expected %d", header.Time, state.ComputeTimestampAtSlot(s, s.Slot()))
+	}
+	withdrawals := solid.NewStaticListSSZFromList(
+		state.ExpectedWithdrawals(s, state.Epoch(s)),
+		int(a.beaconChainCfg.MaxWithdrawalsPerPayload),
+		44,
+	)
+	if body.Version >= clparams.CapellaVersion {
+		withdrawalsRoot, err := withdrawals.HashSSZ()
+		if err != nil {
+			return err
+		}
+		if header.WithdrawalsRoot != withdrawalsRoot {
+			return fmt.Errorf("bid withdrawals root %x, expected %x", header.WithdrawalsRoot, libcommon.Hash(withdrawalsRoot))
+		}
+	}
+	if body.Version >= clparams.DenebVersion && bid.BlobKzgCommitments.Len() > int(a.beaconChainCfg.MaxBlobsPerBlock) {
+		return fmt.Errorf("bid has %d blobs, max %d", bid.BlobKzgCommitments.Len(), a.beaconChainCfg.MaxBlobsPerBlock)
+	}
+
+	payload := cltypes.NewEth1Block(body.Version, a.beaconChainCfg)
+	payload.ParentHash = header.ParentHash
+	payload.FeeRecipient = header.FeeRecipient
+	payload.StateRoot = header.StateRoot
+	payload.ReceiptsRoot = header.ReceiptsRoot
+	payload.LogsBloom = header.LogsBloom
+	payload.PrevRandao = header.PrevRandao
+	payload.BlockNumber = header.BlockNumber
+	payload.GasLimit = header.GasLimit
+	payload.GasUsed = header.GasUsed
+	payload.Time = header.Time
+	payload.Extra = header.Extra
+	payload.BaseFeePerGas = header.BaseFeePerGas
+	payload.BlockHash = header.BlockHash
+	payload.BlobGasUsed = header.BlobGasUsed
+	payload.ExcessBlobGas = header.ExcessBlobGas
+	payload.Transactions = solid.NewTransactionsSSZFromTransactions(nil)
+	payload.Withdrawals = withdrawals
+	body.ExecutionPayload = payload
+	if body.Version >= clparams.DenebVersion {
+		body.BlobKzgCommitments = bid.BlobKzgCommitments
+	}
+	return nil
+}
+
+func (a *ApiHandler) PostEthV1BlindedBlocks(w http.ResponseWriter, r *http.Request) {
+	a.postBlindedBlocks(w, r, 1)
+}
+
+func (a *ApiHandler) PostEthV2BlindedBlocks(w http.ResponseWriter, r *http.Request) {
+	a.postBlindedBlocks(w, r, 2)
+}
+
+func (a *ApiHandler) postBlindedBlocks(w http.ResponseWriter, r *http.Request, apiVersion int) {
+	ctx := r.Context()
+	version, err := a.parseEthConsensusVersion(r.Header.Get("Eth-Consensus-Version"), apiVersion)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	blindedBlock := cltypes.NewSignedBlindedBeaconBlock(a.beaconChainCfg)
+	blindedBlock.Block.Body.Version = version
+	if err := beaconhttp.DecodeRequestBody(r, blindedBlock, int(version)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if a.builderClient == nil {
+		http.Error(w, "no relays configured to reveal the payload of blinded blocks", http.StatusBadRequest)
+		return
+	}
+	block, err := a.unblindBlock(ctx, blindedBlock)
+	if err != nil {
+		log.Error("BlockPublishing: Failed to reveal builder payload", "slot", blindedBlock.Block.Slot, "err", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if err := a.broadcastBlock(ctx, block); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// unblindBlock gets the payload of a signed blinded block from the relays and checks it matches the committed header.
+// Blobs of the payload are kept for the blob sidecars to be published with the block.
+func (a *ApiHandler) unblindBlock(ctx context.Context, blindedBlock *cltypes.SignedBlindedBeaconBlock) (*cltypes.SignedBeaconBlock, error) {
+	resp, err := a.builderClient.SubmitBlindedBlocks(ctx, blindedBlock)
+	if err != nil {
+		return nil, err
+	}
+	payloadHeader, err := resp.ExecutionPayload.PayloadHeader()
+	if err != nil {
+		return nil, err
+	}
+	payloadRoot, err := payloadHeader.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	expectedRoot, err := blindedBlock.Block.Body.ExecutionPayload.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	if payloadRoot != expectedRoot {
+		return nil, fmt.Errorf("revealed payload %x does not match the block's header", resp.ExecutionPayload.BlockHash)
+	}
+
+	if blindedBlock.Version() >= clparams.DenebVersion {
+		bundle := resp.BlobsBundle
+		commitments := blindedBlock.Block.Body.BlobKzgCommitments
+		if len(bundle.Commitments) != commitments.Len() || len(bundle.Proofs) != commitments.Len() || len(bundle.Blobs) != commitments.Len() {
+			return nil, fmt.Errorf("revealed blobs bundle does not match %d commitments of the block", commitments.Len())
+		}
+		for i := range bundle.Blobs {
+			if len(bundle.Commitments[i]) != length.Bytes48 || len(bundle.Proofs[i]) != length.Bytes48 || len(bundle.Blobs[i]) != int(cltypes.BYTES_PER_BLOB) {
+				return nil, fmt.Errorf("invalid blob %d in revealed blobs bundle", i)
+			}
+			if libcommon.Bytes48(bundle.Commitments[i]) != libcommon.Bytes48(*commitments.Get(i)) {
+				return nil, fmt.Errorf("revealed blob %d does not match the block's commitment", i)
+			}
+			a.blobBundles.Add(libcommon.Bytes48(bundle.Commitments[i]), BlobBundle{
+				Blob:       (*cltypes.Blob)(bundle.Blobs[i]),
+				KzgProof:   libcommon.Bytes48(bundle.Proofs[i]),
+				Commitment: libcommon.Bytes48(bundle.Commitments[i]),
+			})
+		}
+	}
+	return blindedBlock.Full(resp.ExecutionPayload.Transactions, resp.ExecutionPayload.Withdrawals), nil
+}
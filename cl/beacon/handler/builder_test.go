@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+func TestGetExpectedWithdrawalsSSZ(t *testing.T) {
+	_, blocks, _, _, postState, handler, _, sm, fcu, _ := setupTestingHandler(t, clparams.CapellaVersion, log.Root())
+
+	var err error
+	fcu.HeadVal, err = blocks[len(blocks)-1].Block.HashSSZ()
+	require.NoError(t, err)
+	fcu.HeadSlotVal = blocks[len(blocks)-1].Block.Slot
+	require.NoError(t, sm.OnHeadState(postState))
+
+	maxWithdrawals := int(handler.beaconChainCfg.MaxWithdrawalsPerPayload)
+	headWithdrawals := solid.NewStaticListSSZFromList(state.ExpectedWithdrawals(postState, state.Epoch(postState)), maxWithdrawals, 44)
+	require.NotZero(t, headWithdrawals.Len())
+
+	server := httptest.NewServer(handler.mux)
+	defer server.Close()
+	get := func(t *testing.T, stateID, accept string) *http.Response {
+		req, err := http.NewRequest("GET", server.URL+"/eth/v1/builder/states/"+stateID+"/expected_withdrawals", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", accept)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp
+	}
+
+	for _, stateID := range []string{"head", "8320"} {
+		t.Run(stateID, func(t *testing.T) {
+			resp := get(t, stateID, "application/octet-stream")
+			defer resp.Body.Close()
+			require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+			require.Equal(t, "capella", resp.Header.Get("Eth-Consensus-Version"))
+			out, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			withdrawals := solid.NewStaticListSSZ[*cltypes.Withdrawal](maxWithdrawals, 44)
+			require.NoError(t, withdrawals.DecodeSSZ(out, int(clparams.CapellaVersion)))
+			encoded, err := withdrawals.EncodeSSZ(nil)
+			require.NoError(t, err)
+			require.Equal(t, out, encoded)
+
+			// same withdrawals as the json response
+			jsonResp := get(t, stateID, "application/json")
+			defer jsonResp.Body.Close()
+			require.Equal(t, "capella", jsonResp.Header.Get("Eth-Consensus-Version"))
+			jsonWithdrawals := struct {
+				Data *solid.ListSSZ[*cltypes.Withdrawal] `json:"data"`
+			}{Data: solid.NewStaticListSSZ[*cltypes.Withdrawal](maxWithdrawals, 44)}
+			require.NoError(t, json.NewDecoder(jsonResp.Body).Decode(&jsonWithdrawals))
+			expectedRoot, err := jsonWithdrawals.Data.HashSSZ()
+			require.NoError(t, err)
+			root, err := withdrawals.HashSSZ()
+			require.NoError(t, err)
+			require.Equal(t, expectedRoot, root)
+
+			if stateID == "head" {
+				expectedRoot, err = headWithdrawals.HashSSZ()
+				require.NoError(t, err)
+				require.Equal(t, expectedRoot, root)
+			}
+		})
+	}
+}
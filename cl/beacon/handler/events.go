@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,44 +24,131 @@ var validTopics = map[string]struct{}{
 	"light_client_finality_update":   {},
 	"light_client_optimistic_update": {},
 	"payload_attributes":             {},
+	"blob_sidecar":                   {},
 	"*":                              {},
 }
 
-func (a *ApiHandler) EventSourceGetV1Events(w http.ResponseWriter, r *http.Request) {
-	sink, err := sse.DefaultUpgrader.Upgrade(w, r)
-	if err != nil {
-		http.Error(w, "failed to upgrade", http.StatusInternalServerError)
+const (
+	// eventsTopicBufferSize is how many events of a single topic are buffered for a slow client,
+	// on overflow the oldest event of that topic is dropped.
+	eventsTopicBufferSize = 256
+	// eventsMaxDropped is how many events a client may miss before it gets disconnected.
+	eventsMaxDropped = 4 * eventsTopicBufferSize
+)
+
+type queuedEvent struct {
+	seq   uint64
+	event *sse.Event
+}
+
+// eventsQueue buffers events of a single client per topic, so that a burst of one topic (e.g. attestations)
+// does not evict events of the other ones. Events are delivered in publish order.
+type eventsQueue struct {
+	mu      sync.Mutex
+	seq     uint64
+	topics  map[string][]queuedEvent
+	dropped int
+	notify  chan struct{}
+}
+
+func newEventsQueue() *eventsQueue {
+	return &eventsQueue{
+		topics: map[string][]queuedEvent{},
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// push enqueues an event, it returns false once the client lags too much and should be disconnected.
+func (q *eventsQueue) push(topic string, event *sse.Event) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue := q.topics[topic]
+	if len(queue) >= eventsTopicBufferSize {
+		queue = queue[1:]
+		q.dropped++
+	}
+	q.seq++
+	q.topics[topic] = append(queue, queuedEvent{seq: q.seq, event: event})
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return q.dropped <= eventsMaxDropped
+}
+
+// pop dequeues the oldest event among all topics.
+func (q *eventsQueue) pop() (*sse.Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var oldest string
+	found := false
+	for topic, queue := range q.topics {
+		if len(queue) == 0 {
+			continue
+		}
+		if !found || queue[0].seq < q.topics[oldest][0].seq {
+			oldest, found = topic, true
+		}
+	}
+	if !found {
+		return nil, false
 	}
+	event := q.topics[oldest][0].event
+	q.topics[oldest][0] = queuedEvent{}
+	q.topics[oldest] = q.topics[oldest][1:]
+	return event, true
+}
+
+func (a *ApiHandler) EventSourceGetV1Events(w http.ResponseWriter, r *http.Request) {
 	topics := r.URL.Query()["topics"]
+	if len(topics) == 0 {
+		http.Error(w, "no topics specified", http.StatusBadRequest)
+		return
+	}
 	for _, v := range topics {
 		if _, ok := validTopics[v]; !ok {
 			http.Error(w, fmt.Sprintf("invalid Topic: %s", v), http.StatusBadRequest)
+			return
 		}
 	}
-	var mu sync.Mutex
+	sink, err := sse.DefaultUpgrader.Upgrade(w, r)
+	if err != nil {
+		http.Error(w, "failed to upgrade", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	queue := newEventsQueue()
+	// the callback must not block: publishers are gossip and forkchoice goroutines, so slow clients are handled by the queue
 	closer, err := a.emitters.Subscribe(topics, func(topic string, item any) {
 		buf := &bytes.Buffer{}
-		err := json.NewEncoder(buf).Encode(item)
-		if err != nil {
-			// return early
+		if err := json.NewEncoder(buf).Encode(item); err != nil {
+			log.Debug("failed to encode event", "topic", topic, "err", err)
 			return
 		}
-		mu.Lock()
-		err = sink.Encode(&sse.Event{
-			Event: []byte(topic),
-			Data:  buf,
-		})
-		mu.Unlock()
-		if err != nil {
-			log.Error("failed to encode data", "topic", topic, "err", err)
+		if !queue.push(topic, &sse.Event{Event: []byte(topic), Data: buf}) {
+			log.Debug("events client is too slow, disconnecting", "remote", r.RemoteAddr)
+			cancel()
 		}
-		// OK to ignore this error. maybe should log it later?
 	})
 	if err != nil {
 		http.Error(w, "failed to subscribe", http.StatusInternalServerError)
 		return
 	}
 	defer closer()
-	<-r.Context().Done()
 
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-queue.notify:
+		}
+		for event, ok := queue.pop(); ok; event, ok = queue.pop() {
+			if err := sink.Encode(event); err != nil {
+				log.Debug("failed to write event", "topic", string(event.Event), "err", err)
+				return
+			}
+		}
+	}
 }
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/gfx-labs/sse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsQueue(t *testing.T) {
+	q := newEventsQueue()
+	require.True(t, q.push("head", &sse.Event{Event: []byte("head")}))
+	require.True(t, q.push("attestation", &sse.Event{Event: []byte("attestation")}))
+	require.True(t, q.push("block", &sse.Event{Event: []byte("block")}))
+
+	// delivered in publish order across topics
+	for _, expected := range []string{"head", "attestation", "block"} {
+		event, ok := q.pop()
+		require.True(t, ok)
+		require.Equal(t, expected, string(event.Event))
+	}
+	_, ok := q.pop()
+	require.False(t, ok)
+
+	// a burst of one topic evicts only its own oldest events
+	require.True(t, q.push("head", &sse.Event{Event: []byte("head")}))
+	for i := 0; i < eventsTopicBufferSize+10; i++ {
+		require.True(t, q.push("attestation", &sse.Event{Event: []byte("attestation"), ID: &[]byte{byte(i)}}))
+	}
+	event, ok := q.pop()
+	require.True(t, ok)
+	require.Equal(t, "head", string(event.Event))
+	event, ok = q.pop()
+	require.True(t, ok)
+	require.Equal(t, byte(10), (*event.ID)[0])
+
+	// client which keeps lagging is disconnected
+	lagging := true
+	for i := 0; i < eventsTopicBufferSize+eventsMaxDropped+1; i++ {
+		lagging = q.push("attestation", &sse.Event{Event: []byte("attestation")})
+	}
+	require.False(t, lagging)
+}
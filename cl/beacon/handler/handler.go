@@ -12,10 +12,12 @@ import (
 	"github.com/ledgerwatch/erigon/cl/beacon/beacon_router_configuration"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/beacon/builder"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/monitor"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/persistence/state/historical_states_reader"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
@@ -77,6 +79,7 @@ type ApiHandler struct {
 	committeeSub        *committee_subscription.CommitteeSubscribeMgmt
 	attestationProducer attestation_producer.AttestationDataProducer
 	aggregatePool       aggregation.AggregationPool
+	builderClient       builder.BuilderClient // nil if no relays are configured
 
 	// services
 	syncCommitteeMessagesService     services.SyncCommitteeMessagesService
@@ -86,6 +89,9 @@ type ApiHandler struct {
 	voluntaryExitService             services.VoluntaryExitService
 	blsToExecutionChangeService      services.BLSToExecutionChangeService
 	proposerSlashingService          services.ProposerSlashingService
+
+	validatorMonitor monitor.ValidatorMonitor
+	seenAttestations *services.SeenAttestationCache
 }
 
 func NewApiHandler(
@@ -118,6 +124,9 @@ func NewApiHandler(
 	voluntaryExitService services.VoluntaryExitService,
 	blsToExecutionChangeService services.BLSToExecutionChangeService,
 	proposerSlashingService services.ProposerSlashingService,
+	validatorMonitor monitor.ValidatorMonitor,
+	builderClient builder.BuilderClient,
+	seenAttestations *services.SeenAttestationCache,
 ) *ApiHandler {
 	blobBundles, err := lru.New[common.Bytes48, BlobBundle]("blobs", maxBlobBundleCacheSize)
 	if err != nil {
@@ -158,6 +167,9 @@ func NewApiHandler(
 		voluntaryExitService:             voluntaryExitService,
 		blsToExecutionChangeService:      blsToExecutionChangeService,
 		proposerSlashingService:          proposerSlashingService,
+		validatorMonitor:                 validatorMonitor,
+		builderClient:                    builderClient,
+		seenAttestations:                 seenAttestations,
 	}
 }
 
@@ -176,6 +188,7 @@ func (a *ApiHandler) init() {
 		r.Route("/lighthouse", func(r chi.Router) {
 			r.Get("/validator_inclusion/{epoch}/global", beaconhttp.HandleEndpointFunc(a.GetLighthouseValidatorInclusionGlobal))
 			r.Get("/validator_inclusion/{epoch}/{validator_id}", beaconhttp.HandleEndpointFunc(a.GetLighthouseValidatorInclusion))
+			r.Post("/ui/validator_metrics", beaconhttp.HandleEndpointFunc(a.PostLighthouseUiValidatorMetrics))
 		})
 	}
 	r.Route("/eth", func(r chi.Router) {
@@ -200,6 +213,7 @@ func (a *ApiHandler) init() {
 
 			if a.routerCfg.Debug {
 				r.Get("/debug/fork_choice", a.GetEthV1DebugBeaconForkChoice)
+				r.Get("/debug/caplin/attestation_seen_cache", beaconhttp.HandleEndpointFunc(a.GetEthV1DebugCaplinAttestationSeenCache))
 			}
 			if a.routerCfg.Config {
 				r.Route("/config", func(r chi.Router) {
@@ -227,6 +241,7 @@ func (a *ApiHandler) init() {
 					})
 					r.Get("/genesis", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconGenesis))
 					r.Get("/blinded_blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BlindedBlock))
+					r.Post("/blinded_blocks", a.PostEthV1BlindedBlocks)
 					r.Route("/pool", func(r chi.Router) {
 						r.Get("/voluntary_exits", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconPoolVoluntaryExits))
 						r.Post("/voluntary_exits", a.PostEthV1BeaconPoolVoluntaryExits)
@@ -278,6 +293,7 @@ func (a *ApiHandler) init() {
 					r.Get("/sync_committee_contribution", beaconhttp.HandleEndpointFunc(a.GetEthV1ValidatorSyncCommitteeContribution))
 					r.Post("/contribution_and_proofs", a.PostEthV1ValidatorContributionsAndProofs)
 					r.Post("/prepare_beacon_proposer", a.PostEthV1ValidatorPrepareBeaconProposal)
+					r.Post("/register_validator", a.PostEthV1ValidatorRegisterValidator)
 					r.Post("/liveness/{epoch}", beaconhttp.HandleEndpointFunc(a.liveness))
 				})
 			}
@@ -296,6 +312,7 @@ func (a *ApiHandler) init() {
 				r.Route("/beacon", func(r chi.Router) {
 					r.Get("/blocks/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconBlock))
 					r.Post("/blocks", a.PostEthV2BeaconBlocks)
+					r.Post("/blinded_blocks", a.PostEthV2BlindedBlocks)
 				})
 			}
 			if a.routerCfg.Validator {
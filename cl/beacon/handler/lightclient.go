@@ -8,6 +8,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
 )
 
 func (a *ApiHandler) GetEthV1BeaconLightClientBootstrap(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
@@ -83,29 +84,40 @@ func (a *ApiHandler) GetEthV1BeaconLightClientUpdates(w http.ResponseWriter, r *
 		return
 	}
 
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
 	resp := []interface{}{}
 	endPeriod := *startPeriod + *count
-	currentSlot := a.ethClock.GetCurrentSlot()
-	if endPeriod > a.beaconChainCfg.SyncCommitteePeriod(currentSlot) {
-		endPeriod = a.beaconChainCfg.SyncCommitteePeriod(currentSlot)
+	currentPeriod := a.beaconChainCfg.SyncCommitteePeriod(a.ethClock.GetCurrentSlot())
+	if endPeriod > currentPeriod+1 {
+		endPeriod = currentPeriod + 1
 	}
 
-	notFoundPrev := false
-	// Fetch from [start_period, start_period + count]
-	for i := *startPeriod; i <= endPeriod; i++ {
-		respUpdate := map[string]interface{}{}
+	// Fetch from [start_period, start_period + count), returned updates must be consecutive so stop at the first gap
+	for i := *startPeriod; i < endPeriod; i++ {
 		update, has := a.forkchoiceStore.GetLightClientUpdate(i)
 		if !has {
-			notFoundPrev = true
-			continue
+			// older periods are pruned from the fork graph, but are kept in the db
+			if update, err = beacon_indicies.ReadLightClientUpdate(tx, i); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		}
-		if notFoundPrev {
-			resp = []interface{}{}
-			notFoundPrev = false
+		if update == nil {
+			if len(resp) > 0 {
+				break
+			}
+			continue
 		}
-		respUpdate["data"] = update
-		respUpdate["version"] = clparams.ClVersionToString(update.AttestedHeader.Version())
-		resp = append(resp, respUpdate)
+		resp = append(resp, map[string]interface{}{
+			"data":    update,
+			"version": clparams.ClVersionToString(update.AttestedHeader.Version()),
+		})
 	}
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
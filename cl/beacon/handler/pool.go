@@ -16,20 +16,37 @@ import (
 	"github.com/ledgerwatch/log/v3"
 )
 
+// poolListLimit - limit of lists of objects submitted to and returned from the pools
+const poolListLimit = 1 << 16
+
+// ssz sizes of the pooled objects of static size
+var (
+	voluntaryExitSSZLength        = (&cltypes.SignedVoluntaryExit{}).EncodingSizeSSZ()
+	proposerSlashingSSZLength     = (&cltypes.ProposerSlashing{Header1: &cltypes.SignedBeaconBlockHeader{}}).EncodingSizeSSZ()
+	blsToExecutionChangeSSZLength = (&cltypes.SignedBLSToExecutionChange{}).EncodingSizeSSZ()
+	syncCommitteeMessageSSZLength = (*cltypes.SyncCommitteeMessage)(nil).EncodingSizeSSZ()
+	contributionAndProofSSZLength = (&cltypes.SignedContributionAndProof{Message: &cltypes.ContributionAndProof{Contribution: &cltypes.Contribution{AggregationBits: make([]byte, cltypes.SyncCommitteeAggregationBitsSize)}}}).EncodingSizeSSZ()
+)
+
+// currentVersion - version of the objects submitted to the pools
+func (a *ApiHandler) currentVersion() int {
+	return int(a.beaconChainCfg.GetCurrentStateVersion(a.ethClock.GetCurrentEpoch()))
+}
+
 func (a *ApiHandler) GetEthV1BeaconPoolVoluntaryExits(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
-	return newBeaconResponse(a.operationsPool.VoluntaryExitsPool.Raw()), nil
+	return newBeaconResponse(solid.NewStaticListSSZFromList(a.operationsPool.VoluntaryExitsPool.Raw(), poolListLimit, voluntaryExitSSZLength)), nil
 }
 
 func (a *ApiHandler) GetEthV1BeaconPoolAttesterSlashings(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
-	return newBeaconResponse(a.operationsPool.AttesterSlashingsPool.Raw()), nil
+	return newBeaconResponse(solid.NewDynamicListSSZFromList(a.operationsPool.AttesterSlashingsPool.Raw(), poolListLimit)), nil
 }
 
 func (a *ApiHandler) GetEthV1BeaconPoolProposerSlashings(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
-	return newBeaconResponse(a.operationsPool.ProposerSlashingsPool.Raw()), nil
+	return newBeaconResponse(solid.NewStaticListSSZFromList(a.operationsPool.ProposerSlashingsPool.Raw(), poolListLimit, proposerSlashingSSZLength)), nil
 }
 
 func (a *ApiHandler) GetEthV1BeaconPoolBLSExecutionChanges(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
-	return newBeaconResponse(a.operationsPool.BLSToExecutionChangesPool.Raw()), nil
+	return newBeaconResponse(solid.NewStaticListSSZFromList(a.operationsPool.BLSToExecutionChangesPool.Raw(), poolListLimit, blsToExecutionChangeSSZLength)), nil
 }
 
 func (a *ApiHandler) GetEthV1BeaconPoolAttestations(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
@@ -43,9 +60,9 @@ func (a *ApiHandler) GetEthV1BeaconPoolAttestations(w http.ResponseWriter, r *ht
 	}
 	atts := a.operationsPool.AttestationsPool.Raw()
 	if slot == nil && committeeIndex == nil {
-		return newBeaconResponse(atts), nil
+		return newBeaconResponse(solid.NewDynamicListSSZFromList(atts, poolListLimit)), nil
 	}
-	ret := make([]any, 0, len(atts))
+	ret := make([]*solid.Attestation, 0, len(atts))
 	for i := range atts {
 		if slot != nil && atts[i].AttestantionData().Slot() != *slot {
 			continue
@@ -56,12 +73,12 @@ func (a *ApiHandler) GetEthV1BeaconPoolAttestations(w http.ResponseWriter, r *ht
 		ret = append(ret, atts[i])
 	}
 
-	return newBeaconResponse(ret), nil
+	return newBeaconResponse(solid.NewDynamicListSSZFromList(ret, poolListLimit)), nil
 }
 
 func (a *ApiHandler) PostEthV1BeaconPoolAttestations(w http.ResponseWriter, r *http.Request) {
-	req := []*solid.Attestation{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := beaconhttp.DecodeRequestList[*solid.Attestation](r, poolListLimit, 0, a.currentVersion())
+	if err != nil {
 		beaconhttp.NewEndpointError(http.StatusBadRequest, err).WriteTo(w)
 		return
 	}
@@ -121,7 +138,7 @@ func (a *ApiHandler) PostEthV1BeaconPoolAttestations(w http.ResponseWriter, r *h
 
 func (a *ApiHandler) PostEthV1BeaconPoolVoluntaryExits(w http.ResponseWriter, r *http.Request) {
 	req := cltypes.SignedVoluntaryExit{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := beaconhttp.DecodeRequestBody(r, &req, a.currentVersion()); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -152,7 +169,7 @@ func (a *ApiHandler) PostEthV1BeaconPoolVoluntaryExits(w http.ResponseWriter, r
 
 func (a *ApiHandler) PostEthV1BeaconPoolAttesterSlashings(w http.ResponseWriter, r *http.Request) {
 	req := cltypes.NewAttesterSlashing()
-	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+	if err := beaconhttp.DecodeRequestBody(r, req, a.currentVersion()); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -181,7 +198,7 @@ func (a *ApiHandler) PostEthV1BeaconPoolAttesterSlashings(w http.ResponseWriter,
 
 func (a *ApiHandler) PostEthV1BeaconPoolProposerSlashings(w http.ResponseWriter, r *http.Request) {
 	req := cltypes.ProposerSlashing{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := beaconhttp.DecodeRequestBody(r, &req, a.currentVersion()); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -220,8 +237,8 @@ type poolingError struct {
 }
 
 func (a *ApiHandler) PostEthV1BeaconPoolBlsToExecutionChanges(w http.ResponseWriter, r *http.Request) {
-	req := []*cltypes.SignedBLSToExecutionChange{}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := beaconhttp.DecodeRequestList[*cltypes.SignedBLSToExecutionChange](r, poolListLimit, blsToExecutionChangeSSZLength, a.currentVersion())
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -258,9 +275,8 @@ func (a *ApiHandler) PostEthV1BeaconPoolBlsToExecutionChanges(w http.ResponseWri
 }
 
 func (a *ApiHandler) PostEthV1ValidatorAggregatesAndProof(w http.ResponseWriter, r *http.Request) {
-	req := []*cltypes.SignedAggregateAndProof{}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := beaconhttp.DecodeRequestList[*cltypes.SignedAggregateAndProof](r, poolListLimit, 0, a.currentVersion())
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -295,8 +311,8 @@ func (a *ApiHandler) PostEthV1ValidatorAggregatesAndProof(w http.ResponseWriter,
 // PostEthV1BeaconPoolSyncCommittees is a handler for POST /eth/v1/beacon/pool/sync_committees.
 // it receives a list of sync committee messages and adds them to the sync committee pool.
 func (a *ApiHandler) PostEthV1BeaconPoolSyncCommittees(w http.ResponseWriter, r *http.Request) {
-	msgs := []*cltypes.SyncCommitteeMessage{}
-	if err := json.NewDecoder(r.Body).Decode(&msgs); err != nil {
+	msgs, err := beaconhttp.DecodeRequestList[*cltypes.SyncCommitteeMessage](r, poolListLimit, syncCommitteeMessageSSZLength, a.currentVersion())
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -349,8 +365,8 @@ func (a *ApiHandler) PostEthV1BeaconPoolSyncCommittees(w http.ResponseWriter, r
 // PostEthV1ValidatorContributionsAndProofs is a handler for POST /eth/v1/validator/contributions_and_proofs.
 // it receives a list of signed contributions and proofs and adds them to the sync committee pool.
 func (a *ApiHandler) PostEthV1ValidatorContributionsAndProofs(w http.ResponseWriter, r *http.Request) {
-	msgs := []*cltypes.SignedContributionAndProof{}
-	if err := json.NewDecoder(r.Body).Decode(&msgs); err != nil {
+	msgs, err := beaconhttp.DecodeRequestList[*cltypes.SignedContributionAndProof](r, poolListLimit, contributionAndProofSSZLength, a.currentVersion())
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -360,7 +376,6 @@ func (a *ApiHandler) PostEthV1ValidatorContributionsAndProofs(w http.ResponseWri
 		return
 	}
 	failures := []poolingFailure{}
-	var err error
 	for idx, v := range msgs {
 		if bytes.Equal(v.Message.Contribution.AggregationBits, make([]byte, len(v.Message.Contribution.AggregationBits))) {
 			continue // skip empty contributions
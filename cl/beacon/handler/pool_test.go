@@ -3,6 +3,8 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"io"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -109,6 +111,58 @@ func TestPoolProposerSlashings(t *testing.T) {
 	require.Equal(t, proposerSlashing, out.Data[0])
 }
 
+func TestPoolProposerSlashingsSSZ(t *testing.T) {
+	proposerSlashing := &cltypes.ProposerSlashing{
+		Header1: &cltypes.SignedBeaconBlockHeader{
+			Header: &cltypes.BeaconBlockHeader{
+				Slot:          1,
+				ProposerIndex: 3,
+			},
+		},
+		Header2: &cltypes.SignedBeaconBlockHeader{
+			Header: &cltypes.BeaconBlockHeader{
+				Slot:          2,
+				ProposerIndex: 4,
+			},
+		},
+	}
+	// find server
+	_, _, _, _, _, handler, _, _, _, _ := setupTestingHandler(t, clparams.Phase0Version, log.Root())
+
+	server := httptest.NewServer(handler.mux)
+	defer server.Close()
+	// ssz
+	req, err := proposerSlashing.EncodeSSZ(nil)
+	require.NoError(t, err)
+
+	// post proposer slashing
+	resp, err := server.Client().Post(server.URL+"/eth/v1/beacon/pool/proposer_slashings", "application/octet-stream", bytes.NewBuffer(req))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, 200, resp.StatusCode)
+	// get proposer slashings as ssz
+	getReq, err := http.NewRequest("GET", server.URL+"/eth/v1/beacon/pool/proposer_slashings", nil)
+	require.NoError(t, err)
+	getReq.Header.Set("Accept", "application/json;q=0.5, application/octet-stream")
+	resp, err = server.Client().Do(getReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+	out, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, req, out)
+
+	// not acceptable content type
+	getReq.Header.Set("Accept", "application/xml")
+	resp, err = server.Client().Do(getReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
 func TestPoolVoluntaryExits(t *testing.T) {
 	voluntaryExit := &cltypes.SignedVoluntaryExit{
 		VoluntaryExit: &cltypes.VoluntaryExit{
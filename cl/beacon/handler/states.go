@@ -168,7 +168,11 @@ func (a *ApiHandler) getFullState(w http.ResponseWriter, r *http.Request) (*beac
 
 	blockRoot, httpStatus, err := a.blockRootFromStateId(ctx, tx, blockId)
 	if err != nil {
-		return nil, beaconhttp.NewEndpointError(httpStatus, err)
+		// a skipped slot has no block root, but its state can still be reconstructed.
+		if blockId.GetSlot() == nil || httpStatus != http.StatusNotFound {
+			return nil, beaconhttp.NewEndpointError(httpStatus, err)
+		}
+		return a.reconstructHistoricalState(ctx, tx, *blockId.GetSlot())
 	}
 
 	state, err := a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
@@ -191,19 +195,32 @@ func (a *ApiHandler) getFullState(w http.ResponseWriter, r *http.Request) (*beac
 		if canonicalRoot != blockRoot {
 			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state: %x", blockRoot))
 		}
-		state, err := a.stateReader.ReadHistoricalState(ctx, tx, *slot)
-		if err != nil {
-			return nil, err
-		}
-		if state == nil {
-			return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state: %x", blockRoot))
-		}
-		return newBeaconResponse(state).WithFinalized(true).WithVersion(state.Version()), nil
+		return a.reconstructHistoricalState(ctx, tx, *slot)
 	}
 
 	return newBeaconResponse(state).WithFinalized(false).WithVersion(state.Version()), nil
 }
 
+// reconstructHistoricalState reconstructs the state at the given slot from the archive, replaying blocks from the nearest stored state if needed.
+// Slots after the head didn't happen yet, they are not found.
+func (a *ApiHandler) reconstructHistoricalState(ctx context.Context, tx kv.Tx, slot uint64) (*beaconhttp.BeaconResponse, error) {
+	_, headSlot, err := a.forkchoiceStore.GetHead()
+	if err != nil {
+		return nil, err
+	}
+	if slot > headSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("slot %d is after the head slot %d", slot, headSlot))
+	}
+	state, err := a.stateReader.ReconstructState(ctx, tx, slot, headSlot)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not reconstruct state at slot: %d", slot))
+	}
+	return newBeaconResponse(state).WithFinalized(slot <= a.forkchoiceStore.FinalizedSlot()).WithVersion(state.Version()), nil
+}
+
 type finalityCheckpointsResponse struct {
 	FinalizedCheckpoint         solid.Checkpoint `json:"finalized_checkpoint"`
 	CurrentJustifiedCheckpoint  solid.Checkpoint `json:"current_justified_checkpoint"`
@@ -167,6 +167,11 @@ func TestGetStateFullHistorical(t *testing.T) {
 			blockID: strconv.FormatInt(int64(postState.Slot()), 10),
 			code:    http.StatusOK,
 		},
+		{
+			// after the head, never reconstructed
+			blockID: strconv.FormatInt(int64(postState.Slot()+1), 10),
+			code:    http.StatusNotFound,
+		},
 	}
 
 	for _, c := range cases {
@@ -186,6 +191,8 @@ func TestGetStateFullHistorical(t *testing.T) {
 			if resp.StatusCode != http.StatusOK {
 				return
 			}
+			require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+			require.Equal(t, "phase0", resp.Header.Get("Eth-Consensus-Version"))
 			// read the all of the octect
 			out, err := io.ReadAll(resp.Body)
 			require.NoError(t, err)
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconhttp"
+	"github.com/ledgerwatch/erigon/cl/monitor"
+)
+
+type validatorMetricsRequest struct {
+	Indices []uint64 `json:"indices"`
+}
+
+// validatorMetrics - totals over the epochs kept by the validator monitor, as in lighthouse's validator_metrics
+type validatorMetrics struct {
+	AttestationHits                    uint64                 `json:"attestation_hits"`
+	AttestationMisses                  uint64                 `json:"attestation_misses"`
+	AttestationHitPercentage           float64                `json:"attestation_hit_percentage"`
+	LatestAttestationInclusionDistance uint64                 `json:"latest_attestation_inclusion_distance"`
+	BlocksProposed                     uint64                 `json:"blocks_proposed"`
+	SyncCommitteeHits                  uint64                 `json:"sync_committee_hits"`
+	SyncCommitteeMisses                uint64                 `json:"sync_committee_misses"`
+	Epochs                             []monitor.EpochSummary `json:"epochs"`
+}
+
+type validatorMetricsResponse struct {
+	Validators map[string]*validatorMetrics `json:"validators"`
+}
+
+func (a *ApiHandler) PostLighthouseUiValidatorMetrics(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req validatorMetricsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("could not decode request body: %w. request body is required.", err))
+	}
+
+	resp := validatorMetricsResponse{Validators: map[string]*validatorMetrics{}}
+	for _, idx := range req.Indices {
+		summaries, ok := a.validatorMonitor.ValidatorSummaries(idx)
+		if !ok {
+			// like lighthouse, validators which are not monitored are omitted
+			continue
+		}
+		metrics := &validatorMetrics{Epochs: summaries}
+		for _, summary := range summaries {
+			if summary.AttestationIncluded {
+				metrics.AttestationHits++
+				metrics.LatestAttestationInclusionDistance = summary.InclusionDistance
+			}
+			if summary.AttestationMissed {
+				metrics.AttestationMisses++
+			}
+			metrics.BlocksProposed += uint64(len(summary.ProposedSlots))
+			metrics.SyncCommitteeHits += summary.SyncCommitteeHits
+			metrics.SyncCommitteeMisses += summary.SyncCommitteeMisses
+		}
+		if total := metrics.AttestationHits + metrics.AttestationMisses; total > 0 {
+			metrics.AttestationHitPercentage = 100 * float64(metrics.AttestationHits) / float64(total)
+		}
+		resp.Validators[fmt.Sprint(idx)] = metrics
+	}
+	return newBeaconResponse(resp), nil
+}
@@ -34,6 +34,24 @@ type CaplinConfig struct {
 	BlobBackfilling     bool
 	BlobPruningDisabled bool
 	Archive             bool
+	// MonitoredValidators - indices of validators whose duties are tracked by the validator monitor
+	MonitoredValidators []uint64
+	// Slasher - look for slashable attestations and proposals in gossip, and submit their slashings
+	Slasher bool
+	// MevRelayUrls - relays of external builders, proposals use their payloads when worth more than the local one
+	MevRelayUrls []string
+	// MevMinBid - builders' bids below this value (gwei) are ignored
+	MevMinBid uint64
+	// ProposerConfigFile - fee recipients by validator public key, for proposers not prepared by validator clients
+	ProposerConfigFile string
+	// CheckpointSyncUrls - providers of the checkpoint state, the fastest healthy one is used. Network's defaults if empty
+	CheckpointSyncUrls []string
+	// AttestationSeenCacheEpochs, AttestationSeenCacheEpochSize - target epochs kept by the cache of seen attestations,
+	// and the max number of attestations per epoch. Defaults if zero
+	AttestationSeenCacheEpochs    uint64
+	AttestationSeenCacheEpochSize int
+	// PeerDAS - experimental: custody, gossip and serve data column sidecars (EIP-7594) when the fork is scheduled
+	PeerDAS bool
 }
 
 type NetworkType int
@@ -125,6 +143,9 @@ type NetworkConfig struct {
 	MaxRequestBlocks                uint64        `json:"max_request_blocks"`                 // Maximum number of blocks in a single request
 	MaxChunkSize                    uint64        `json:"max_chunk_size"`                     // The maximum allowed size of uncompressed req/resp chunked responses.
 	AttestationSubnetCount          uint64        `json:"attestation_subnet_count"`           // The number of attestation subnets used in the gossipsub protocol.
+	SubnetsPerNode                  uint64        `json:"subnets_per_node"`                   // The number of long-lived attestation subnets each node subscribes to.
+	EpochsPerSubnetSubscription     uint64        `json:"epochs_per_subnet_subscription"`     // The number of epochs a node keeps its long-lived attestation subnets.
+	AttestationSubnetPrefixBits     uint64        `json:"attestation_subnet_prefix_bits"`     // The number of node id bits mapped to long-lived subnets, ceillog2(ATTESTATION_SUBNET_COUNT) + ATTESTATION_SUBNET_EXTRA_BITS.
 	TtfbTimeout                     time.Duration `json:"ttfbt_timeout"`                      // The maximum time to wait for first byte of request response (time-to-first-byte).
 	RespTimeout                     time.Duration `json:"resp_timeout"`                       // The maximum time for complete response transfer.
 	AttestationPropagationSlotRange uint64        `json:"attestation_propagation_slot_range"` // The maximum number of slots during which an attestation can be propagated.
@@ -149,6 +170,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    MaxChunkSize,
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -169,6 +193,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    1 << 20, // 1 MiB
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -189,6 +216,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    1 << 20, // 1 MiB
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -209,6 +239,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    1 << 20, // 1 MiB
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -229,6 +262,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    1 << 20, // 1 MiB
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -249,6 +285,9 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		GossipMaxSizeBellatrix:          10485760,
 		MaxChunkSize:                    1 << 20, // 1 MiB
 		AttestationSubnetCount:          64,
+		SubnetsPerNode:                  2,
+		EpochsPerSubnetSubscription:     256,
+		AttestationSubnetPrefixBits:     6,
 		AttestationPropagationSlotRange: 32,
 		MaxRequestBlocks:                1 << 10, // 1024
 		TtfbTimeout:                     ReqTimeout,
@@ -496,6 +535,21 @@ type BeaconChainConfig struct {
 
 	MaxBlobGasPerBlock uint64 `yaml:"MAX_BLOB_GAS_PER_BLOCK" json:"MAX_BLOB_GAS_PER_BLOCK,string"` // MaxBlobGasPerBlock defines the maximum gas limit for blob sidecar per block.
 	MaxBlobsPerBlock   uint64 `yaml:"MAX_BLOBS_PER_BLOCK" json:"MAX_BLOBS_PER_BLOCK,string"`       // MaxBlobsPerBlock defines the maximum number of blobs per block.
+
+	// PeerDAS
+	EIP7594ForkEpoch                       uint64 `yaml:"EIP7594_FORK_EPOCH" spec:"true" json:"EIP7594_FORK_EPOCH,string"`                                                     // EIP7594ForkEpoch is the epoch from which blobs are distributed as data column sidecars.
+	NumberOfColumns                        uint64 `yaml:"NUMBER_OF_COLUMNS" spec:"true" json:"NUMBER_OF_COLUMNS,string"`                                                       // NumberOfColumns is the number of columns in the extended data matrix.
+	NumberOfCustodyGroups                  uint64 `yaml:"NUMBER_OF_CUSTODY_GROUPS" spec:"true" json:"NUMBER_OF_CUSTODY_GROUPS,string"`                                         // NumberOfCustodyGroups is the number of custody groups available for nodes to custody.
+	DataColumnSidecarSubnetCount           uint64 `yaml:"DATA_COLUMN_SIDECAR_SUBNET_COUNT" spec:"true" json:"DATA_COLUMN_SIDECAR_SUBNET_COUNT,string"`                         // DataColumnSidecarSubnetCount is the number of data column sidecar subnets used in the gossipsub protocol.
+	CustodyRequirement                     uint64 `yaml:"CUSTODY_REQUIREMENT" spec:"true" json:"CUSTODY_REQUIREMENT,string"`                                                   // CustodyRequirement is the minimum number of custody groups an honest node custodies and serves samples from.
+	SamplesPerSlot                         uint64 `yaml:"SAMPLES_PER_SLOT" spec:"true" json:"SAMPLES_PER_SLOT,string"`                                                         // SamplesPerSlot is the number of columns a node samples per slot.
+	MaxRequestDataColumnSidecars           uint64 `yaml:"MAX_REQUEST_DATA_COLUMN_SIDECARS" spec:"true" json:"MAX_REQUEST_DATA_COLUMN_SIDECARS,string"`                         // MaxRequestDataColumnSidecars is the maximum number of data column sidecars in a single request.
+	MinEpochsForDataColumnSidecarsRequests uint64 `yaml:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS" spec:"true" json:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS,string"` // MinEpochsForDataColumnSidecarsRequests is the minimum number of epochs for which data column sidecars are served.
+}
+
+// PeerDASActive returns whether blobs are distributed as data column sidecars at the given epoch.
+func (b *BeaconChainConfig) PeerDASActive(epoch uint64) bool {
+	return epoch >= b.EIP7594ForkEpoch
 }
 
 func (b *BeaconChainConfig) RoundSlotToEpoch(slot uint64) uint64 {
@@ -736,6 +790,16 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 
 	MaxBlobGasPerBlock: 786432,
 	MaxBlobsPerBlock:   6,
+
+	// PeerDAS
+	EIP7594ForkEpoch:                       math.MaxUint64,
+	NumberOfColumns:                        128,
+	NumberOfCustodyGroups:                  128,
+	DataColumnSidecarSubnetCount:           128,
+	CustodyRequirement:                     4,
+	SamplesPerSlot:                         8,
+	MaxRequestDataColumnSidecars:           16384,
+	MinEpochsForDataColumnSidecarsRequests: 4096,
 }
 
 func mainnetConfig() BeaconChainConfig {
@@ -1035,6 +1099,11 @@ func GetConfigsByNetworkName(net string) (*NetworkConfig, *BeaconChainConfig, Ne
 	}
 }
 
+// GetCheckpointSyncEndpoints returns all the trusted checkpoint sync endpoints of the network
+func GetCheckpointSyncEndpoints(net NetworkType) []string {
+	return append([]string(nil), CheckpointSyncEndpoints[net]...)
+}
+
 func GetCheckpointSyncEndpoint(net NetworkType) string {
 	checkpoints, ok := CheckpointSyncEndpoints[net]
 	if !ok {
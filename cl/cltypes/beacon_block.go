@@ -232,6 +232,7 @@ func (b *BeaconBody) Blinded() (*BlindedBeaconBody, error) {
 		ExecutionChanges:   b.ExecutionChanges,
 		BlobKzgCommitments: b.BlobKzgCommitments,
 		Version:            b.Version,
+		beaconCfg:          b.beaconCfg,
 	}, nil
 }
 
@@ -318,6 +319,11 @@ func (b *BeaconBody) KzgCommitmentMerkleProof(index int) ([][32]byte, error) {
 	return append(branch, kzgCommitmentsProof...), nil
 }
 
+// KzgCommitmentsMerkleProof proves the whole blob_kzg_commitments list against the body root, used by data column sidecars.
+func (b *BeaconBody) KzgCommitmentsMerkleProof() ([][32]byte, error) {
+	return merkle_tree.MerkleProof(4, 11, b.getSchema(false)...)
+}
+
 func (b *BeaconBody) UnmarshalJSON(buf []byte) error {
 	var tmp struct {
 		RandaoReveal       libcommon.Bytes96                           `json:"randao_reveal"`
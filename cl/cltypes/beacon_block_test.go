@@ -120,3 +120,29 @@ func TestBeaconBlockJson(t *testing.T) {
 	assert.Equal(t, map1, map2)
 	assert.Equal(t, libcommon.Hash(r), libcommon.HexToHash("0x1a9b89eb12282543a5fa0b0f251d8ec0c5c432121d7cb2a8d78461ea9d10c294"))
 }
+
+func TestBlindedBeaconBlockJson(t *testing.T) {
+	_, bc := clparams.GetConfigsByNetwork(clparams.GnosisNetwork)
+	block := NewSignedBeaconBlock(bc)
+	block.Block.Body.Version = clparams.DenebVersion
+	require.NoError(t, json.Unmarshal(beaconBodyJSON, block))
+	blinded, err := block.Blinded()
+	require.NoError(t, err)
+
+	out, err := json.Marshal(blinded)
+	require.NoError(t, err)
+	blinded2 := NewSignedBlindedBeaconBlock(bc)
+	blinded2.Block.Body.Version = clparams.DenebVersion
+	require.NoError(t, json.Unmarshal(out, blinded2))
+
+	r, err := block.Block.HashSSZ()
+	require.NoError(t, err)
+	r2, err := blinded2.Block.HashSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, r, r2)
+
+	full := blinded2.Full(block.Block.Body.ExecutionPayload.Transactions, block.Block.Body.ExecutionPayload.Withdrawals)
+	r3, err := full.Block.HashSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, r, r3)
+}
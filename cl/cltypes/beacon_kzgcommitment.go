@@ -55,6 +55,30 @@ func (b *KZGCommitment) HashSSZ() ([32]byte, error) {
 	return merkle_tree.BytesRoot(b[:])
 }
 
+func (b KZGProof) MarshalJSON() ([]byte, error) {
+	return json.Marshal(libcommon.Bytes48(b))
+}
+
+func (b *KZGProof) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*libcommon.Bytes48)(b))
+}
+
+func (b *KZGProof) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, b[:]...), nil
+}
+
+func (b *KZGProof) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, b[:])
+}
+
+func (b *KZGProof) EncodingSizeSSZ() int {
+	return 48
+}
+
+func (b *KZGProof) HashSSZ() ([32]byte, error) {
+	return merkle_tree.BytesRoot(b[:])
+}
+
 func (b *Blob) MarshalJSON() ([]byte, error) {
 	return json.Marshal(hexutility.Bytes(b[:]))
 }
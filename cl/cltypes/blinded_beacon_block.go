@@ -1,6 +1,7 @@
 package cltypes
 
 import (
+	"encoding/json"
 	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -48,7 +49,7 @@ type BlindedBeaconBody struct {
 	// Data related to crosslink records and executing operations on the Ethereum 2.0 chain
 	ExecutionPayload *Eth1Header `json:"execution_payload_header,omitempty"`
 	// Withdrawals Diffs for Execution Layer
-	ExecutionChanges *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"execution_changes,omitempty"`
+	ExecutionChanges *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"bls_to_execution_changes,omitempty"`
 	// The commitments for beacon chain blobs
 	// With a max of 4 per block
 	BlobKzgCommitments *solid.ListSSZ[*KZGCommitment] `json:"blob_kzg_commitments,omitempty"`
@@ -169,7 +170,7 @@ func (b *BlindedBeaconBody) EncodingSizeSSZ() (size int) {
 		size += b.ExecutionChanges.EncodingSizeSSZ()
 	}
 	if b.Version >= clparams.DenebVersion {
-		size += b.ExecutionChanges.EncodingSizeSSZ()
+		size += b.BlobKzgCommitments.EncodingSizeSSZ()
 	}
 
 	return
@@ -188,6 +189,48 @@ func (b *BlindedBeaconBody) DecodeSSZ(buf []byte, version int) error {
 	return err
 }
 
+func (b *BlindedBeaconBody) UnmarshalJSON(buf []byte) error {
+	var tmp struct {
+		RandaoReveal       libcommon.Bytes96                           `json:"randao_reveal"`
+		Eth1Data           *Eth1Data                                   `json:"eth1_data"`
+		Graffiti           libcommon.Hash                              `json:"graffiti"`
+		ProposerSlashings  *solid.ListSSZ[*ProposerSlashing]           `json:"proposer_slashings"`
+		AttesterSlashings  *solid.ListSSZ[*AttesterSlashing]           `json:"attester_slashings"`
+		Attestations       *solid.ListSSZ[*solid.Attestation]          `json:"attestations"`
+		Deposits           *solid.ListSSZ[*Deposit]                    `json:"deposits"`
+		VoluntaryExits     *solid.ListSSZ[*SignedVoluntaryExit]        `json:"voluntary_exits"`
+		SyncAggregate      *SyncAggregate                              `json:"sync_aggregate,omitempty"`
+		ExecutionPayload   *Eth1Header                                 `json:"execution_payload_header,omitempty"`
+		ExecutionChanges   *solid.ListSSZ[*SignedBLSToExecutionChange] `json:"bls_to_execution_changes,omitempty"`
+		BlobKzgCommitments *solid.ListSSZ[*KZGCommitment]              `json:"blob_kzg_commitments,omitempty"`
+	}
+	tmp.ProposerSlashings = solid.NewStaticListSSZ[*ProposerSlashing](MaxProposerSlashings, 416)
+	tmp.AttesterSlashings = solid.NewDynamicListSSZ[*AttesterSlashing](MaxAttesterSlashings)
+	tmp.Attestations = solid.NewDynamicListSSZ[*solid.Attestation](MaxAttestations)
+	tmp.Deposits = solid.NewStaticListSSZ[*Deposit](MaxDeposits, 1240)
+	tmp.VoluntaryExits = solid.NewStaticListSSZ[*SignedVoluntaryExit](MaxVoluntaryExits, 112)
+	tmp.ExecutionChanges = solid.NewStaticListSSZ[*SignedBLSToExecutionChange](MaxExecutionChanges, 172)
+	tmp.BlobKzgCommitments = solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, 48)
+	tmp.ExecutionPayload = NewEth1Header(b.Version)
+
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+	b.RandaoReveal = tmp.RandaoReveal
+	b.Eth1Data = tmp.Eth1Data
+	b.Graffiti = tmp.Graffiti
+	b.ProposerSlashings = tmp.ProposerSlashings
+	b.AttesterSlashings = tmp.AttesterSlashings
+	b.Attestations = tmp.Attestations
+	b.Deposits = tmp.Deposits
+	b.VoluntaryExits = tmp.VoluntaryExits
+	b.SyncAggregate = tmp.SyncAggregate
+	b.ExecutionPayload = tmp.ExecutionPayload
+	b.ExecutionChanges = tmp.ExecutionChanges
+	b.BlobKzgCommitments = tmp.BlobKzgCommitments
+	return nil
+}
+
 func (b *BlindedBeaconBody) HashSSZ() ([32]byte, error) {
 	return merkle_tree.HashTreeRoot(b.getSchema(false)...)
 }
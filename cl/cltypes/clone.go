@@ -106,6 +106,10 @@ func (*KZGCommitment) Clone() clonable.Clonable {
 	return &KZGCommitment{}
 }
 
+func (*KZGProof) Clone() clonable.Clonable {
+	return &KZGProof{}
+}
+
 func (*Eth1Header) Clone() clonable.Clonable {
 	return &Eth1Header{}
 }
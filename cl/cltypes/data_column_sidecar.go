@@ -0,0 +1,224 @@
+package cltypes
+
+import (
+	"encoding/json"
+	"reflect"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/das-core.md#preset
+const (
+	FieldElementsPerCell              = 64
+	BytesPerCell                      = FieldElementsPerCell * BYTES_PER_FIELD_ELEMENT
+	NumberOfColumns                   = 128
+	KzgCommitmentsInclusionProofDepth = 4
+)
+
+var cellT = reflect.TypeOf(Cell{})
+
+// Cell is a single column entry of an extended blob
+type Cell [BytesPerCell]byte
+
+func (c *Cell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutility.Bytes(c[:]))
+}
+
+func (c *Cell) UnmarshalJSON(in []byte) error {
+	return hexutility.UnmarshalFixedJSON(cellT, in, c[:])
+}
+
+func (*Cell) Clone() clonable.Clonable {
+	return &Cell{}
+}
+
+func (c *Cell) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c[:])
+}
+
+func (c *Cell) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, c[:]...), nil
+}
+
+func (c *Cell) EncodingSizeSSZ() int {
+	return BytesPerCell
+}
+
+func (c *Cell) HashSSZ() ([32]byte, error) {
+	return merkle_tree.BytesRoot(c[:])
+}
+
+// DataColumnSidecar carries one column of the extended blob matrix of a block together with all of its commitments.
+type DataColumnSidecar struct {
+	Index                        uint64                         `json:"index,string"`
+	Column                       *solid.ListSSZ[*Cell]          `json:"column"`
+	KzgCommitments               *solid.ListSSZ[*KZGCommitment] `json:"kzg_commitments"`
+	KzgProofs                    *solid.ListSSZ[*KZGProof]      `json:"kzg_proofs"`
+	SignedBlockHeader            *SignedBeaconBlockHeader       `json:"signed_block_header"`
+	KzgCommitmentsInclusionProof solid.HashVectorSSZ            `json:"kzg_commitments_inclusion_proof"`
+}
+
+func NewDataColumnSidecar() *DataColumnSidecar {
+	return &DataColumnSidecar{
+		Column:                       solid.NewStaticListSSZ[*Cell](MaxBlobsCommittmentsPerBlock, BytesPerCell),
+		KzgCommitments:               solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		KzgProofs:                    solid.NewStaticListSSZ[*KZGProof](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		SignedBlockHeader:            &SignedBeaconBlockHeader{Header: &BeaconBlockHeader{}},
+		KzgCommitmentsInclusionProof: solid.NewHashVector(KzgCommitmentsInclusionProofDepth),
+	}
+}
+
+func (d *DataColumnSidecar) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) DecodeSSZ(buf []byte, version int) error {
+	*d = *NewDataColumnSidecar()
+	return ssz2.UnmarshalSSZ(buf, version, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) EncodingSizeSSZ() int {
+	return length.BlockNum + 3*4 + d.Column.EncodingSizeSSZ() + d.KzgCommitments.EncodingSizeSSZ() + d.KzgProofs.EncodingSizeSSZ() +
+		d.SignedBlockHeader.EncodingSizeSSZ() + KzgCommitmentsInclusionProofDepth*length.Hash
+}
+
+func (*DataColumnSidecar) Static() bool {
+	return false
+}
+
+func (d *DataColumnSidecar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.getSchema()...)
+}
+
+func (*DataColumnSidecar) Clone() clonable.Clonable {
+	return NewDataColumnSidecar()
+}
+
+func (d *DataColumnSidecar) UnmarshalJSON(buf []byte) error {
+	var tmp struct {
+		Index                        uint64                         `json:"index,string"`
+		Column                       *solid.ListSSZ[*Cell]          `json:"column"`
+		KzgCommitments               *solid.ListSSZ[*KZGCommitment] `json:"kzg_commitments"`
+		KzgProofs                    *solid.ListSSZ[*KZGProof]      `json:"kzg_proofs"`
+		SignedBlockHeader            *SignedBeaconBlockHeader       `json:"signed_block_header"`
+		KzgCommitmentsInclusionProof solid.HashVectorSSZ            `json:"kzg_commitments_inclusion_proof"`
+	}
+	empty := NewDataColumnSidecar()
+	tmp.Column = empty.Column
+	tmp.KzgCommitments = empty.KzgCommitments
+	tmp.KzgProofs = empty.KzgProofs
+	tmp.KzgCommitmentsInclusionProof = empty.KzgCommitmentsInclusionProof
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+	d.Index = tmp.Index
+	d.Column = tmp.Column
+	d.KzgCommitments = tmp.KzgCommitments
+	d.KzgProofs = tmp.KzgProofs
+	d.SignedBlockHeader = tmp.SignedBlockHeader
+	d.KzgCommitmentsInclusionProof = tmp.KzgCommitmentsInclusionProof
+	return nil
+}
+
+func (d *DataColumnSidecar) getSchema() []interface{} {
+	return []interface{}{&d.Index, d.Column, d.KzgCommitments, d.KzgProofs, d.SignedBlockHeader, d.KzgCommitmentsInclusionProof}
+}
+
+type DataColumnIdentifier struct {
+	BlockRoot libcommon.Hash `json:"block_root"`
+	Index     uint64         `json:"index,string"`
+}
+
+func NewDataColumnIdentifier(blockRoot libcommon.Hash, index uint64) *DataColumnIdentifier {
+	return &DataColumnIdentifier{
+		BlockRoot: blockRoot,
+		Index:     index,
+	}
+}
+
+func (d *DataColumnIdentifier) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.getSchema()...)
+}
+
+func (d *DataColumnIdentifier) EncodingSizeSSZ() int {
+	return length.Hash + length.BlockNum
+}
+
+func (d *DataColumnIdentifier) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, d.getSchema()...)
+}
+
+func (d *DataColumnIdentifier) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.getSchema()...)
+}
+
+func (*DataColumnIdentifier) Clone() clonable.Clonable {
+	return &DataColumnIdentifier{}
+}
+
+func (d *DataColumnIdentifier) getSchema() []interface{} {
+	return []interface{}{
+		d.BlockRoot[:],
+		&d.Index,
+	}
+}
+
+type DataColumnSidecarsByRangeRequest struct {
+	StartSlot uint64
+	Count     uint64
+	Columns   solid.Uint64ListSSZ
+}
+
+func NewDataColumnSidecarsByRangeRequest() *DataColumnSidecarsByRangeRequest {
+	return &DataColumnSidecarsByRangeRequest{Columns: solid.NewUint64ListSSZ(NumberOfColumns)}
+}
+
+func (d *DataColumnSidecarsByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, &d.StartSlot, &d.Count, d.Columns)
+}
+
+func (d *DataColumnSidecarsByRangeRequest) DecodeSSZ(buf []byte, version int) error {
+	d.Columns = solid.NewUint64ListSSZ(NumberOfColumns)
+	return ssz2.UnmarshalSSZ(buf, version, &d.StartSlot, &d.Count, d.Columns)
+}
+
+func (d *DataColumnSidecarsByRangeRequest) EncodingSizeSSZ() int {
+	return 2*length.BlockNum + 4 + d.Columns.EncodingSizeSSZ()
+}
+
+func (*DataColumnSidecarsByRangeRequest) Clone() clonable.Clonable {
+	return NewDataColumnSidecarsByRangeRequest()
+}
+
+// VerifyDataColumnSidecar performs the structural checks of verify_data_column_sidecar.
+func VerifyDataColumnSidecar(sidecar *DataColumnSidecar, numberOfColumns uint64) bool {
+	if sidecar.Index >= numberOfColumns {
+		return false
+	}
+	// A sidecar for zero blobs is never sent
+	if sidecar.KzgCommitments.Len() == 0 {
+		return false
+	}
+	return sidecar.Column.Len() == sidecar.KzgCommitments.Len() && sidecar.KzgCommitments.Len() == sidecar.KzgProofs.Len()
+}
+
+// VerifyDataColumnSidecarInclusionProof checks that the commitments list is included in the block body of the sidecar's header.
+func VerifyDataColumnSidecarInclusionProof(sidecar *DataColumnSidecar) bool {
+	leaf, err := sidecar.KzgCommitments.HashSSZ()
+	if err != nil {
+		return false
+	}
+	branch := make([]libcommon.Hash, KzgCommitmentsInclusionProofDepth)
+	for i := range branch {
+		branch[i] = sidecar.KzgCommitmentsInclusionProof.Get(i)
+	}
+	// 11 is the subtree index of blob_kzg_commitments in the block body
+	return utils.IsValidMerkleBranch(leaf, branch, KzgCommitmentsInclusionProofDepth, 11, sidecar.SignedBlockHeader.Header.BodyRoot)
+}
@@ -0,0 +1,88 @@
+package cltypes
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/stretchr/testify/require"
+)
+
+func testDataColumnSidecar(t *testing.T) *DataColumnSidecar {
+	_, bc := clparams.GetConfigsByNetwork(clparams.GnosisNetwork)
+	block := NewSignedBeaconBlock(bc)
+	require.NoError(t, block.DecodeSSZ(beaconBodySSZ, int(clparams.DenebVersion)))
+	body := block.Block.Body
+	body.BlobKzgCommitments.Clear()
+	body.BlobKzgCommitments.Append(&KZGCommitment{1})
+	body.BlobKzgCommitments.Append(&KZGCommitment{2})
+
+	bodyRoot, err := body.HashSSZ()
+	require.NoError(t, err)
+	proof, err := body.KzgCommitmentsMerkleProof()
+	require.NoError(t, err)
+
+	sidecar := NewDataColumnSidecar()
+	sidecar.Index = 5
+	sidecar.SignedBlockHeader.Header.Slot = block.Block.Slot
+	sidecar.SignedBlockHeader.Header.BodyRoot = bodyRoot
+	for i := 0; i < body.BlobKzgCommitments.Len(); i++ {
+		sidecar.Column.Append(&Cell{byte(i)})
+		sidecar.KzgCommitments.Append(body.BlobKzgCommitments.Get(i))
+		sidecar.KzgProofs.Append(&KZGProof{byte(i)})
+	}
+	for i, p := range proof {
+		sidecar.KzgCommitmentsInclusionProof.Set(i, p)
+	}
+	return sidecar
+}
+
+func TestDataColumnSidecarSSZ(t *testing.T) {
+	sidecar := testDataColumnSidecar(t)
+	enc, err := sidecar.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Equal(t, sidecar.EncodingSizeSSZ(), len(enc))
+
+	decoded := NewDataColumnSidecar()
+	require.NoError(t, decoded.DecodeSSZ(enc, int(clparams.DenebVersion)))
+	require.Equal(t, sidecar.Index, decoded.Index)
+	require.Equal(t, 2, decoded.Column.Len())
+
+	root1, err := sidecar.HashSSZ()
+	require.NoError(t, err)
+	root2, err := decoded.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, root1, root2)
+}
+
+func TestDataColumnSidecarVerify(t *testing.T) {
+	sidecar := testDataColumnSidecar(t)
+	require.True(t, VerifyDataColumnSidecar(sidecar, NumberOfColumns))
+	require.True(t, VerifyDataColumnSidecarInclusionProof(sidecar))
+
+	sidecar.KzgProofs.Append(&KZGProof{})
+	require.False(t, VerifyDataColumnSidecar(sidecar, NumberOfColumns))
+
+	sidecar.Index = NumberOfColumns
+	require.False(t, VerifyDataColumnSidecar(sidecar, NumberOfColumns))
+
+	sidecar.KzgCommitments.Append(&KZGCommitment{3})
+	require.False(t, VerifyDataColumnSidecarInclusionProof(sidecar))
+}
+
+func TestDataColumnSidecarsByRangeRequestSSZ(t *testing.T) {
+	req := NewDataColumnSidecarsByRangeRequest()
+	req.StartSlot = 100
+	req.Count = 64
+	req.Columns.Append(3)
+	req.Columns.Append(77)
+	enc, err := req.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Equal(t, req.EncodingSizeSSZ(), len(enc))
+
+	decoded := NewDataColumnSidecarsByRangeRequest()
+	require.NoError(t, decoded.DecodeSSZ(enc, 0))
+	require.Equal(t, uint64(100), decoded.StartSlot)
+	require.Equal(t, uint64(64), decoded.Count)
+	require.Equal(t, 2, decoded.Columns.Length())
+	require.Equal(t, uint64(77), decoded.Columns.Get(1))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEth1Header(t *testing.T) {
@@ -77,3 +78,17 @@ func TestEth1Header(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, libcommon.HexToHash("0x9170a25a0980f07bcb9af2a52ff915262763e0e6a2df26aa205b967bd462a6d3"), libcommon.Hash(root))
 }
+
+func TestEth1HeaderJSONBaseFee(t *testing.T) {
+	// base_fee_per_gas is a little-endian uint256 in SSZ, as in Eth1Block
+	header := NewEth1Header(clparams.DenebVersion)
+	header.BaseFeePerGas[0], header.BaseFeePerGas[1] = 0x07, 0x01
+
+	headerJson, err := header.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(headerJson), `"base_fee_per_gas":"263"`)
+
+	decoded := NewEth1Header(clparams.DenebVersion)
+	require.NoError(t, decoded.UnmarshalJSON(headerJson))
+	require.Equal(t, header.BaseFeePerGas, decoded.BaseFeePerGas)
+}
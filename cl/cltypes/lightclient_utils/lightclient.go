@@ -183,3 +183,84 @@ func CreateLightClientBootstrap(state *state.CachingBeaconState, block *cltypes.
 		CurrentSyncCommitteeBranch: hashVector,
 	}, nil
 }
+
+// def is_better_update(new_update: LightClientUpdate, old_update: LightClientUpdate) -> bool:
+//
+//	# Compare supermajority (> 2/3) sync committee participation
+//	# Compare presence of relevant sync committee
+//	# Compare indication of any finality
+//	# Compare sync committee finality
+//	# Tiebreaker 1: Sync committee participation beyond supermajority
+//	# Tiebreaker 2: Prefer older data (fewer changes to best)
+//
+// IsBetterUpdate implements the specs to select the best light client update of a sync committee period
+func IsBetterUpdate(cfg *clparams.BeaconChainConfig, newUpdate, oldUpdate *cltypes.LightClientUpdate) bool {
+	maxActiveParticipants := int(cfg.SyncCommitteeSize)
+	newNumActiveParticipants := newUpdate.SyncAggregate.Sum()
+	oldNumActiveParticipants := oldUpdate.SyncAggregate.Sum()
+	newHasSupermajority := newNumActiveParticipants*3 >= maxActiveParticipants*2
+	oldHasSupermajority := oldNumActiveParticipants*3 >= maxActiveParticipants*2
+	if newHasSupermajority != oldHasSupermajority {
+		return newHasSupermajority
+	}
+	if !newHasSupermajority && newNumActiveParticipants != oldNumActiveParticipants {
+		return newNumActiveParticipants > oldNumActiveParticipants
+	}
+
+	newHasRelevantSyncCommittee := isSyncCommitteeUpdate(newUpdate) &&
+		cfg.SyncCommitteePeriod(newUpdate.AttestedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(newUpdate.SignatureSlot)
+	oldHasRelevantSyncCommittee := isSyncCommitteeUpdate(oldUpdate) &&
+		cfg.SyncCommitteePeriod(oldUpdate.AttestedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(oldUpdate.SignatureSlot)
+	if newHasRelevantSyncCommittee != oldHasRelevantSyncCommittee {
+		return newHasRelevantSyncCommittee
+	}
+
+	newHasFinality := isFinalityUpdate(newUpdate)
+	oldHasFinality := isFinalityUpdate(oldUpdate)
+	if newHasFinality != oldHasFinality {
+		return newHasFinality
+	}
+
+	if newHasFinality {
+		newHasSyncCommitteeFinality := cfg.SyncCommitteePeriod(newUpdate.FinalizedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(newUpdate.AttestedHeader.Beacon.Slot)
+		oldHasSyncCommitteeFinality := cfg.SyncCommitteePeriod(oldUpdate.FinalizedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(oldUpdate.AttestedHeader.Beacon.Slot)
+		if newHasSyncCommitteeFinality != oldHasSyncCommitteeFinality {
+			return newHasSyncCommitteeFinality
+		}
+	}
+
+	if newNumActiveParticipants != oldNumActiveParticipants {
+		return newNumActiveParticipants > oldNumActiveParticipants
+	}
+
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// def is_sync_committee_update(update: LightClientUpdate) -> bool:
+//
+//	return update.next_sync_committee_branch != NextSyncCommitteeBranch()
+func isSyncCommitteeUpdate(update *cltypes.LightClientUpdate) bool {
+	return !isEmptyBranch(update.NextSyncCommitteeBranch)
+}
+
+// def is_finality_update(update: LightClientUpdate) -> bool:
+//
+//	return update.finality_branch != FinalityBranch()
+func isFinalityUpdate(update *cltypes.LightClientUpdate) bool {
+	return !isEmptyBranch(update.FinalityBranch)
+}
+
+func isEmptyBranch(branch solid.HashVectorSSZ) bool {
+	if branch == nil {
+		return true
+	}
+	for i := 0; i < branch.Length(); i++ {
+		if branch.Get(i) != (libcommon.Hash{}) {
+			return false
+		}
+	}
+	return true
+}
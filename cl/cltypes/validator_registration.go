@@ -0,0 +1,19 @@
+package cltypes
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// ValidatorRegistration is the signed preference of a validator for blocks built by external builders,
+// forwarded as is to the relays (builder-specs: SignedValidatorRegistrationV1).
+type ValidatorRegistration struct {
+	Message   ValidatorRegistrationMessage `json:"message"`
+	Signature libcommon.Bytes96            `json:"signature"`
+}
+
+type ValidatorRegistrationMessage struct {
+	FeeRecipient libcommon.Address `json:"fee_recipient"`
+	GasLimit     uint64            `json:"gas_limit,string"`
+	Timestamp    uint64            `json:"timestamp,string"`
+	PubKey       libcommon.Bytes48 `json:"pubkey"`
+}
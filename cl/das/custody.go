@@ -0,0 +1,82 @@
+package das
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+)
+
+// CustodyGroups implements get_custody_groups: the sorted custody groups a node with the given id is responsible for.
+func CustodyGroups(cfg *clparams.BeaconChainConfig, nodeID enode.ID, custodyGroupCount uint64) ([]uint64, error) {
+	if custodyGroupCount > cfg.NumberOfCustodyGroups {
+		return nil, fmt.Errorf("custody group count %d exceeds number of custody groups %d", custodyGroupCount, cfg.NumberOfCustodyGroups)
+	}
+	currentID := new(uint256.Int).SetBytes32(nodeID[:])
+	one := uint256.NewInt(1)
+
+	groups := make([]uint64, 0, custodyGroupCount)
+	seen := make(map[uint64]struct{}, custodyGroupCount)
+	for uint64(len(groups)) < custodyGroupCount {
+		// node id is hashed as little-endian uint256
+		idBytes := currentID.Bytes32()
+		slices.Reverse(idBytes[:])
+		h := utils.Sha256(idBytes[:])
+		group := binary.LittleEndian.Uint64(h[:8]) % cfg.NumberOfCustodyGroups
+		if _, ok := seen[group]; !ok {
+			seen[group] = struct{}{}
+			groups = append(groups, group)
+		}
+		// wraps around to 0 after UINT256_MAX
+		currentID.Add(currentID, one)
+	}
+	slices.Sort(groups)
+	return groups, nil
+}
+
+// ColumnsForCustodyGroup implements compute_columns_for_custody_group.
+func ColumnsForCustodyGroup(cfg *clparams.BeaconChainConfig, custodyGroup uint64) []uint64 {
+	columnsPerGroup := cfg.NumberOfColumns / cfg.NumberOfCustodyGroups
+	columns := make([]uint64, columnsPerGroup)
+	for i := range columns {
+		columns[i] = cfg.NumberOfCustodyGroups*uint64(i) + custodyGroup
+	}
+	return columns
+}
+
+// CustodyColumns returns the sorted data columns a node with the given id has to custody.
+func CustodyColumns(cfg *clparams.BeaconChainConfig, nodeID enode.ID, custodyGroupCount uint64) ([]uint64, error) {
+	groups, err := CustodyGroups(cfg, nodeID, custodyGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]uint64, 0, uint64(len(groups))*(cfg.NumberOfColumns/cfg.NumberOfCustodyGroups))
+	for _, group := range groups {
+		columns = append(columns, ColumnsForCustodyGroup(cfg, group)...)
+	}
+	slices.Sort(columns)
+	return columns, nil
+}
+
+// CustodySubnets returns the sorted data column sidecar subnets carrying the custody columns of a node.
+func CustodySubnets(cfg *clparams.BeaconChainConfig, nodeID enode.ID, custodyGroupCount uint64) ([]uint64, error) {
+	columns, err := CustodyColumns(cfg, nodeID, custodyGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	subnets := make([]uint64, 0, len(columns))
+	for _, column := range columns {
+		subnets = append(subnets, ComputeSubnetForDataColumnSidecar(cfg, column))
+	}
+	slices.Sort(subnets)
+	return slices.Compact(subnets), nil
+}
+
+// ComputeSubnetForDataColumnSidecar implements compute_subnet_for_data_column_sidecar.
+func ComputeSubnetForDataColumnSidecar(cfg *clparams.BeaconChainConfig, columnIndex uint64) uint64 {
+	return columnIndex % cfg.DataColumnSidecarSubnetCount
+}
@@ -0,0 +1,71 @@
+package das
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustodyGroups(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	nodeID := enode.HexID("0x6a1e1b2bf7c7b5e4b6a3c9f1f5d3a4b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6")
+
+	groups, err := CustodyGroups(cfg, nodeID, cfg.CustodyRequirement)
+	require.NoError(t, err)
+	require.Len(t, groups, int(cfg.CustodyRequirement))
+	for i := range groups {
+		require.Less(t, groups[i], cfg.NumberOfCustodyGroups)
+		if i > 0 {
+			require.Less(t, groups[i-1], groups[i])
+		}
+	}
+
+	again, err := CustodyGroups(cfg, nodeID, cfg.CustodyRequirement)
+	require.NoError(t, err)
+	require.Equal(t, groups, again)
+
+	// custodying more groups extends the smaller set
+	more, err := CustodyGroups(cfg, nodeID, cfg.CustodyRequirement*2)
+	require.NoError(t, err)
+	require.Subset(t, more, groups)
+
+	all, err := CustodyGroups(cfg, nodeID, cfg.NumberOfCustodyGroups)
+	require.NoError(t, err)
+	require.Len(t, all, int(cfg.NumberOfCustodyGroups))
+
+	_, err = CustodyGroups(cfg, nodeID, cfg.NumberOfCustodyGroups+1)
+	require.Error(t, err)
+}
+
+func TestCustodyGroupsWrapsAround(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	var maxID enode.ID
+	for i := range maxID {
+		maxID[i] = 0xff
+	}
+	groups, err := CustodyGroups(cfg, maxID, cfg.CustodyRequirement)
+	require.NoError(t, err)
+	require.Len(t, groups, int(cfg.CustodyRequirement))
+}
+
+func TestCustodyColumnsAndSubnets(t *testing.T) {
+	cfg := clparams.MainnetBeaconConfig
+	cfg.NumberOfCustodyGroups = 64
+	cfg.DataColumnSidecarSubnetCount = 32
+	nodeID := enode.HexID("0x0000000000000000000000000000000000000000000000000000000000000001")
+
+	require.Equal(t, []uint64{3, 67}, ColumnsForCustodyGroup(&cfg, 3))
+
+	columns, err := CustodyColumns(&cfg, nodeID, 4)
+	require.NoError(t, err)
+	require.Len(t, columns, 8)
+
+	subnets, err := CustodySubnets(&cfg, nodeID, 4)
+	require.NoError(t, err)
+	for _, column := range columns {
+		require.Contains(t, subnets, ComputeSubnetForDataColumnSidecar(&cfg, column))
+	}
+	require.LessOrEqual(t, len(subnets), len(columns))
+}
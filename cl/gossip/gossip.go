@@ -20,6 +20,7 @@ const (
 	TopicNamePrefixBlobSidecar       = "blob_sidecar_%d"
 	TopicNamePrefixBeaconAttestation = "beacon_attestation_%d"
 	TopicNamePrefixSyncCommittee     = "sync_committee_%d"
+	TopicNamePrefixDataColumnSidecar = "data_column_sidecar_%d"
 )
 
 func TopicNameBlobSidecar(d uint64) string {
@@ -34,6 +35,10 @@ func TopicNameSyncCommittee(d int) string {
 	return fmt.Sprintf(TopicNamePrefixSyncCommittee, d)
 }
 
+func TopicNameDataColumnSidecar(d uint64) string {
+	return fmt.Sprintf(TopicNamePrefixDataColumnSidecar, d)
+}
+
 func IsTopicBlobSidecar(d string) bool {
 	return strings.Contains(d, "blob_sidecar_")
 }
@@ -53,3 +58,16 @@ func SubnetIdFromTopicBeaconAttestation(d string) (uint64, error) {
 	_, err := fmt.Sscanf(d, TopicNamePrefixBeaconAttestation, &id)
 	return id, err
 }
+
+func IsTopicDataColumnSidecar(d string) bool {
+	return strings.Contains(d, "data_column_sidecar_")
+}
+
+func SubnetIdFromTopicDataColumnSidecar(d string) (uint64, error) {
+	if !IsTopicDataColumnSidecar(d) {
+		return 0, fmt.Errorf("not a data column sidecar topic")
+	}
+	var id uint64
+	_, err := fmt.Sscanf(d, TopicNamePrefixDataColumnSidecar, &id)
+	return id, err
+}
@@ -0,0 +1,224 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// keptEpochs - amount of epochs summaries are kept for, per validator
+const keptEpochs = 64
+
+// ValidatorMonitor tracks the duties of a set of validators as they appear in the blocks processed by forkchoice:
+// attestation inclusion, proposals and sync committee participation, per epoch. Results are exported as
+// Prometheus metrics labeled by validator index.
+type ValidatorMonitor interface {
+	// OnNewBlock must be called with the post-state of every block added to forkchoice.
+	OnNewBlock(state *state.CachingBeaconState, block *cltypes.BeaconBlock) error
+	// ValidatorSummaries returns the kept epoch summaries of a monitored validator, oldest first.
+	ValidatorSummaries(validatorIndex uint64) ([]EpochSummary, bool)
+}
+
+// EpochSummary - what a monitored validator did in an epoch.
+type EpochSummary struct {
+	Epoch  uint64 `json:"epoch,string"`
+	Active bool   `json:"active"`
+	// AttestationIncluded is set when an attestation of the validator for this epoch was included in a block,
+	// InclusionDistance is then the minimal distance between the attestation slot and the including block slot.
+	AttestationIncluded bool   `json:"attestation_included"`
+	InclusionDistance   uint64 `json:"inclusion_distance,string"`
+	// AttestationMissed is set for active validators once the inclusion window of the epoch is over.
+	AttestationMissed   bool     `json:"attestation_missed"`
+	ProposedSlots       []uint64 `json:"proposed_slots"`
+	SyncCommitteeHits   uint64   `json:"sync_committee_hits,string"`
+	SyncCommitteeMisses uint64   `json:"sync_committee_misses,string"`
+
+	closed    bool
+	syncSlots []uint64 // slots whose sync aggregate was accounted, blocks of forks may repeat them
+}
+
+type validatorMonitor struct {
+	beaconCfg *clparams.BeaconChainConfig
+
+	mu         sync.RWMutex
+	validators map[uint64]map[uint64]*EpochSummary // validator index -> epoch -> summary
+	// nextEpochToClose - epochs below it have their inclusion window over, 0 until the first block is seen
+	nextEpochToClose uint64
+}
+
+// NewValidatorMonitor returns a no-op monitor if validators is empty.
+func NewValidatorMonitor(validators []uint64, beaconCfg *clparams.BeaconChainConfig) ValidatorMonitor {
+	if len(validators) == 0 {
+		return NewDummyValidatorMonitor()
+	}
+	m := &validatorMonitor{
+		beaconCfg:  beaconCfg,
+		validators: make(map[uint64]map[uint64]*EpochSummary, len(validators)),
+	}
+	for _, idx := range validators {
+		m.validators[idx] = map[uint64]*EpochSummary{}
+	}
+	return m
+}
+
+func (m *validatorMonitor) OnNewBlock(s *state.CachingBeaconState, block *cltypes.BeaconBlock) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	slot := block.Slot
+	epoch := slot / m.beaconCfg.SlotsPerEpoch
+	if m.nextEpochToClose == 0 {
+		// blocks of the current epoch before the first one seen are unknown, so don't judge it
+		m.nextEpochToClose = epoch + 1
+	}
+
+	if _, ok := m.validators[block.ProposerIndex]; ok {
+		summary := m.summary(s, block.ProposerIndex, epoch)
+		if !slices.Contains(summary.ProposedSlots, slot) {
+			summary.ProposedSlots = append(summary.ProposedSlots, slot)
+			metrics.GetOrCreateCounter(fmt.Sprintf(`validator_monitor_blocks_proposed{validator="%d"}`, block.ProposerIndex)).Inc()
+		}
+	}
+
+	var err error
+	block.Body.Attestations.Range(func(_ int, att *solid.Attestation, _ int) bool {
+		data := att.AttestantionData()
+		var attesters []uint64
+		attesters, err = s.GetAttestingIndicies(data, att.AggregationBits(), true)
+		if err != nil {
+			return false
+		}
+		attSlot := data.Slot()
+		for _, idx := range attesters {
+			if _, ok := m.validators[idx]; !ok {
+				continue
+			}
+			summary := m.summary(s, idx, attSlot/m.beaconCfg.SlotsPerEpoch)
+			distance := slot - attSlot
+			if !summary.AttestationIncluded || distance < summary.InclusionDistance {
+				summary.InclusionDistance = distance
+			}
+			summary.AttestationIncluded = true
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if block.Version() >= clparams.AltairVersion && block.Body.SyncAggregate != nil {
+		// a validator may have several positions in the committee, any signature counts as participation
+		participation := map[uint64]bool{}
+		for i, pk := range s.CurrentSyncCommittee().GetCommittee() {
+			idx, ok := s.ValidatorIndexByPubkey(pk)
+			if !ok {
+				return fmt.Errorf("validator monitor: sync committee member %x not found", pk)
+			}
+			if _, ok := m.validators[idx]; ok {
+				participation[idx] = participation[idx] || block.Body.SyncAggregate.IsSet(uint64(i))
+			}
+		}
+		for idx, participated := range participation {
+			summary := m.summary(s, idx, epoch)
+			if slices.Contains(summary.syncSlots, slot) {
+				continue
+			}
+			summary.syncSlots = append(summary.syncSlots, slot)
+			if participated {
+				summary.SyncCommitteeHits++
+				metrics.GetOrCreateCounter(fmt.Sprintf(`validator_monitor_sync_committee_hits{validator="%d"}`, idx)).Inc()
+			} else {
+				summary.SyncCommitteeMisses++
+				metrics.GetOrCreateCounter(fmt.Sprintf(`validator_monitor_sync_committee_misses{validator="%d"}`, idx)).Inc()
+			}
+		}
+	}
+
+	// attestations can be included until the end of the next epoch
+	for ; m.nextEpochToClose+2 <= epoch; m.nextEpochToClose++ {
+		m.closeEpoch(s, m.nextEpochToClose)
+	}
+	return nil
+}
+
+// summary returns the summary of validator idx at epoch, creating it if needed. Must be called under lock.
+func (m *validatorMonitor) summary(s *state.CachingBeaconState, idx, epoch uint64) *EpochSummary {
+	summaries := m.validators[idx]
+	summary, ok := summaries[epoch]
+	if !ok {
+		summary = &EpochSummary{Epoch: epoch}
+		if v, err := s.ValidatorForValidatorIndex(int(idx)); err == nil {
+			summary.Active = v.Active(epoch)
+		}
+		summaries[epoch] = summary
+	}
+	return summary
+}
+
+// closeEpoch accounts attestation hits and misses of an epoch whose inclusion window is over, and prunes old
+// summaries. Must be called under lock.
+func (m *validatorMonitor) closeEpoch(s *state.CachingBeaconState, epoch uint64) {
+	for idx, summaries := range m.validators {
+		summary := m.summary(s, idx, epoch)
+		if summary.closed {
+			continue
+		}
+		summary.closed = true
+		switch {
+		case summary.AttestationIncluded:
+			metrics.GetOrCreateCounter(fmt.Sprintf(`validator_monitor_attestation_hits{validator="%d"}`, idx)).Inc()
+			metrics.GetOrCreateGauge(fmt.Sprintf(`validator_monitor_attestation_inclusion_distance{validator="%d"}`, idx)).SetUint64(summary.InclusionDistance)
+		case summary.Active:
+			summary.AttestationMissed = true
+			metrics.GetOrCreateCounter(fmt.Sprintf(`validator_monitor_attestation_misses{validator="%d"}`, idx)).Inc()
+		}
+		if epoch >= keptEpochs {
+			for e := range summaries {
+				if e <= epoch-keptEpochs {
+					delete(summaries, e)
+				}
+			}
+		}
+	}
+}
+
+func (m *validatorMonitor) ValidatorSummaries(validatorIndex uint64) ([]EpochSummary, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	summaries, ok := m.validators[validatorIndex]
+	if !ok {
+		return nil, false
+	}
+	ret := make([]EpochSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		cpy := *summary
+		cpy.ProposedSlots = slices.Clone(summary.ProposedSlots)
+		cpy.syncSlots = nil
+		ret = append(ret, cpy)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Epoch < ret[j].Epoch })
+	return ret, true
+}
+
+type dummyValidatorMonitor struct{}
+
+func NewDummyValidatorMonitor() ValidatorMonitor {
+	return &dummyValidatorMonitor{}
+}
+
+func (d *dummyValidatorMonitor) OnNewBlock(*state.CachingBeaconState, *cltypes.BeaconBlock) error {
+	return nil
+}
+
+func (d *dummyValidatorMonitor) ValidatorSummaries(uint64) ([]EpochSummary, bool) {
+	return nil, false
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/antiquary/tests"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/transition"
+)
+
+func TestValidatorMonitor(t *testing.T) {
+	blocks, preState, _ := tests.GetBellatrixRandom()
+	cfg := &clparams.MainnetBeaconConfig
+	s, err := preState.Copy()
+	require.NoError(t, err)
+
+	// the proposer of the first block, an attester included in it and a sync committee member
+	proposer := blocks[0].Block.ProposerIndex
+	att := blocks[0].Block.Body.Attestations.Get(0)
+	attesters, err := s.GetAttestingIndicies(att.AttestantionData(), att.AggregationBits(), true)
+	require.NoError(t, err)
+	attester := attesters[0]
+	syncMember, ok := s.ValidatorIndexByPubkey(s.CurrentSyncCommittee().GetCommittee()[0])
+	require.True(t, ok)
+
+	m := NewValidatorMonitor([]uint64{proposer, attester, syncMember}, cfg)
+	_, ok = m.ValidatorSummaries(proposer + attester + syncMember + 1)
+	require.False(t, ok)
+
+	for _, block := range blocks {
+		require.NoError(t, transition.TransitionState(s, block, nil, false))
+		require.NoError(t, m.OnNewBlock(s, block.Block))
+	}
+	// blocks of forks may repeat already processed ones
+	require.NoError(t, m.OnNewBlock(s, blocks[len(blocks)-1].Block))
+
+	summaryAt := func(idx, epoch uint64) EpochSummary {
+		summaries, ok := m.ValidatorSummaries(idx)
+		require.True(t, ok)
+		for _, summary := range summaries {
+			if summary.Epoch == epoch {
+				return summary
+			}
+		}
+		require.FailNow(t, "no summary", "validator %d epoch %d", idx, epoch)
+		return EpochSummary{}
+	}
+
+	firstEpoch := blocks[0].Block.Slot / cfg.SlotsPerEpoch
+	require.Contains(t, summaryAt(proposer, firstEpoch).ProposedSlots, blocks[0].Block.Slot)
+
+	attSummary := summaryAt(attester, att.AttestantionData().Slot()/cfg.SlotsPerEpoch)
+	require.True(t, attSummary.AttestationIncluded)
+	require.LessOrEqual(t, attSummary.InclusionDistance, blocks[0].Block.Slot-att.AttestantionData().Slot())
+
+	summaries, _ := m.ValidatorSummaries(syncMember)
+	var syncDuties uint64
+	for _, summary := range summaries {
+		syncDuties += summary.SyncCommitteeHits + summary.SyncCommitteeMisses
+	}
+	require.Equal(t, uint64(len(blocks)), syncDuties)
+
+	// a block far enough in the future closes the inclusion window of all seen epochs
+	late := cltypes.NewBeaconBlock(cfg)
+	late.Slot = blocks[len(blocks)-1].Block.Slot + 3*cfg.SlotsPerEpoch
+	late.ProposerIndex = proposer + attester + syncMember + 1
+	require.NoError(t, m.OnNewBlock(s, late))
+	for _, idx := range []uint64{proposer, attester, syncMember} {
+		summaries, _ = m.ValidatorSummaries(idx)
+		for _, summary := range summaries {
+			if summary.Epoch <= firstEpoch || summary.Epoch+2 > late.Slot/cfg.SlotsPerEpoch {
+				continue
+			}
+			require.Equal(t, summary.Active && !summary.AttestationIncluded, summary.AttestationMissed, "validator %d epoch %d", idx, summary.Epoch)
+		}
+	}
+}
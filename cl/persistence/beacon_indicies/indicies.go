@@ -389,3 +389,28 @@ func ReadSignedHeaderByBlockRoot(ctx context.Context, tx kv.Tx, blockRoot libcom
 	}
 	return h, canonical == blockRoot, nil
 }
+
+// WriteLightClientUpdate stores the best light client update of a sync committee period, prefixed by its version.
+func WriteLightClientUpdate(tx kv.RwTx, period uint64, update *cltypes.LightClientUpdate) error {
+	encoded, err := update.EncodeSSZ([]byte{byte(update.AttestedHeader.Version())})
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period), encoded)
+}
+
+func ReadLightClientUpdate(tx kv.Tx, period uint64) (*cltypes.LightClientUpdate, error) {
+	encoded, err := tx.GetOne(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period))
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	version := clparams.StateVersion(encoded[0])
+	update := cltypes.NewLightClientUpdate(version)
+	if err := update.DecodeSSZ(encoded[1:], int(version)); err != nil {
+		return nil, fmt.Errorf("failed to decode LightClientUpdate: %v", err)
+	}
+	return update, nil
+}
@@ -172,3 +172,26 @@ func TestWriteExecutionBlockHash(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, tHash2, tHash3)
 }
+
+func TestWriteLightClientUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	tx, _ := db.BeginRw(context.Background())
+	defer tx.Rollback()
+
+	update := cltypes.NewLightClientUpdate(clparams.CapellaVersion)
+	update.AttestedHeader.Beacon.Slot = 8192
+	update.SignatureSlot = 8193
+
+	require.NoError(t, WriteLightClientUpdate(tx, 1, update))
+
+	retrieved, err := ReadLightClientUpdate(tx, 1)
+	require.NoError(t, err)
+	require.Equal(t, clparams.CapellaVersion, retrieved.AttestedHeader.Version())
+	require.Equal(t, uint64(8192), retrieved.AttestedHeader.Beacon.Slot)
+	require.Equal(t, uint64(8193), retrieved.SignatureSlot)
+
+	retrieved, err = ReadLightClientUpdate(tx, 2)
+	require.NoError(t, err)
+	require.Nil(t, retrieved)
+}
@@ -18,8 +18,10 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/persistence/scoreboard"
 	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/log/v3"
 	"github.com/spf13/afero"
 )
 
@@ -32,6 +34,9 @@ type BlobStorage interface {
 	WriteStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, idx uint64) error // Used for P2P networking
 	KzgCommitmentsCount(ctx context.Context, blockRoot libcommon.Hash) (uint32, error)
 	Prune() error
+	// Scoreboard - slots of which blobs and data columns are stored, persisted on Close of the scoreboard
+	Scoreboard() *scoreboard.Scoreboard
+	DataColumnStorage
 }
 
 type BlobStore struct {
@@ -40,10 +45,31 @@ type BlobStore struct {
 	beaconChainConfig *clparams.BeaconChainConfig
 	ethClock          eth_clock.EthereumClock
 	slotsKept         uint64
+	scoreboard        *scoreboard.Scoreboard
 }
 
 func NewBlobStore(db kv.RwDB, fs afero.Fs, slotsKept uint64, beaconChainConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock) BlobStorage {
-	return &BlobStore{fs: fs, db: db, slotsKept: slotsKept, beaconChainConfig: beaconChainConfig, ethClock: ethClock}
+	sb, err := scoreboard.Open(fs)
+	if err != nil {
+		log.Warn("[BlobStore] could not open the scoreboard, starting from scratch", "err", err)
+		sb = scoreboard.New()
+	}
+	for _, kind := range []scoreboard.Kind{scoreboard.Blobs, scoreboard.DataColumns} {
+		if from, to := sb.Known(kind); from <= to {
+			continue
+		}
+		// what was stored before now is unknown, from now on every write is marked
+		var currentSlot uint64
+		if ethClock != nil {
+			currentSlot = ethClock.GetCurrentSlot()
+		}
+		sb.SetKnown(kind, currentSlot, math.MaxUint64)
+	}
+	return &BlobStore{fs: fs, db: db, slotsKept: slotsKept, beaconChainConfig: beaconChainConfig, ethClock: ethClock, scoreboard: sb}
+}
+
+func (bs *BlobStore) Scoreboard() *scoreboard.Scoreboard {
+	return bs.scoreboard
 }
 
 func blobSidecarFilePath(slot, index uint64, blockRoot libcommon.Hash) (folderpath, filepath string) {
@@ -93,7 +119,13 @@ func (bs *BlobStore) WriteBlobSidecars(ctx context.Context, blockRoot libcommon.
 	if err := tx.Put(kv.BlockRootToKzgCommitments, blockRoot[:], val); err != nil {
 		return err
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, blobSidecar := range blobSidecars {
+		bs.scoreboard.Mark(scoreboard.Blobs, blobSidecar.SignedBlockHeader.Header.Slot)
+	}
+	return nil
 }
 
 // ReadBlobSidecars reads the sidecars from the database. it assumes that all blobSidecars are for the same blockRoot and we have all of them.
@@ -151,6 +183,8 @@ func (bs *BlobStore) Prune() error {
 	for i := startPrune; i < currentSlot; i += subdivisionSlot {
 		bs.fs.RemoveAll(strconv.FormatUint(i, 10))
 	}
+	bs.scoreboard.Prune(scoreboard.Blobs, currentSlot)
+	bs.scoreboard.Prune(scoreboard.DataColumns, currentSlot)
 	return nil
 }
 
@@ -50,3 +50,40 @@ func TestBlobDB(t *testing.T) {
 	require.Equal(t, s1.SignedBlockHeader, sidecars[0].SignedBlockHeader)
 	require.Equal(t, s2.SignedBlockHeader, sidecars[1].SignedBlockHeader)
 }
+
+func TestDataColumnDB(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = 7
+	sidecar.SignedBlockHeader.Header.Slot = 1
+	sidecar.Column.Append(&cltypes.Cell{1})
+	sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{2})
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{3})
+
+	bs := NewBlobStore(db, afero.NewMemMapFs(), 12, &clparams.MainnetBeaconConfig, nil)
+	blockRoot := libcommon.Hash{1}
+	require.NoError(t, bs.WriteDataColumnSidecar(context.Background(), blockRoot, sidecar))
+
+	has, err := bs.HasDataColumnSidecar(1, blockRoot, 7)
+	require.NoError(t, err)
+	require.True(t, has)
+	has, err = bs.HasDataColumnSidecar(1, blockRoot, 8)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	read, found, err := bs.ReadDataColumnSidecar(context.Background(), 1, blockRoot, 7)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, sidecar.Index, read.Index)
+	require.Equal(t, sidecar.Column.Get(0), read.Column.Get(0))
+	require.Equal(t, sidecar.KzgCommitments.Get(0), read.KzgCommitments.Get(0))
+	require.Equal(t, sidecar.KzgProofs.Get(0), read.KzgProofs.Get(0))
+	require.Equal(t, sidecar.SignedBlockHeader, read.SignedBlockHeader)
+
+	require.NoError(t, bs.RemoveDataColumnSidecars(context.Background(), 1, blockRoot))
+	_, found, err = bs.ReadDataColumnSidecar(context.Background(), 1, blockRoot, 7)
+	require.NoError(t, err)
+	require.False(t, found)
+}
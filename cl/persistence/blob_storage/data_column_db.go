@@ -0,0 +1,102 @@
+package blob_storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/scoreboard"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/spf13/afero"
+)
+
+// DataColumnStorage keeps the PeerDAS data column sidecars of the columns we custody.
+// Columns live next to the blob sidecars, so they are pruned together with them.
+type DataColumnStorage interface {
+	WriteDataColumnSidecar(ctx context.Context, blockRoot libcommon.Hash, sidecar *cltypes.DataColumnSidecar) error
+	ReadDataColumnSidecar(ctx context.Context, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (out *cltypes.DataColumnSidecar, found bool, err error)
+	HasDataColumnSidecar(slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (bool, error)
+	RemoveDataColumnSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) error
+	WriteDataColumnStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) error // Used for P2P networking
+}
+
+/*
+file system layout: <slot/subdivisionSlot>/<blockRoot>_column_<index>
+*/
+func dataColumnSidecarFilePath(slot, columnIndex uint64, blockRoot libcommon.Hash) (folderpath, filepath string) {
+	folderpath, _ = blobSidecarFilePath(slot, 0, blockRoot)
+	filepath = fmt.Sprintf("%s/%s_column_%d", folderpath, blockRoot.String(), columnIndex)
+	return
+}
+
+func (bs *BlobStore) WriteDataColumnSidecar(ctx context.Context, blockRoot libcommon.Hash, sidecar *cltypes.DataColumnSidecar) error {
+	folderPath, filePath := dataColumnSidecarFilePath(sidecar.SignedBlockHeader.Header.Slot, sidecar.Index, blockRoot)
+	// mkdir the whole folder and subfolders
+	bs.fs.MkdirAll(folderPath, 0755)
+	// write to a temporary file first, so that readers never see a partially written column
+	file, err := bs.fs.Create(filePath + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := ssz_snappy.EncodeAndWrite(file, sidecar); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := bs.fs.Rename(filePath+".tmp", filePath); err != nil {
+		return err
+	}
+	bs.scoreboard.Mark(scoreboard.DataColumns, sidecar.SignedBlockHeader.Header.Slot)
+	return nil
+}
+
+func (bs *BlobStore) ReadDataColumnSidecar(ctx context.Context, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (*cltypes.DataColumnSidecar, bool, error) {
+	_, filePath := dataColumnSidecarFilePath(slot, columnIndex, blockRoot)
+	file, err := bs.fs.Open(filePath)
+	if err != nil {
+		if errors.Is(err, afero.ErrFileNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer file.Close()
+
+	sidecar := cltypes.NewDataColumnSidecar()
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(file, sidecar, clparams.DenebVersion); err != nil {
+		return nil, false, err
+	}
+	return sidecar, true, nil
+}
+
+func (bs *BlobStore) HasDataColumnSidecar(slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (bool, error) {
+	_, filePath := dataColumnSidecarFilePath(slot, columnIndex, blockRoot)
+	return afero.Exists(bs.fs, filePath)
+}
+
+func (bs *BlobStore) RemoveDataColumnSidecars(ctx context.Context, slot uint64, blockRoot libcommon.Hash) error {
+	for i := uint64(0); i < bs.beaconChainConfig.NumberOfColumns; i++ {
+		_, filePath := dataColumnSidecarFilePath(slot, i, blockRoot)
+		if err := bs.fs.Remove(filePath); err != nil && !errors.Is(err, afero.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *BlobStore) WriteDataColumnStream(w io.Writer, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) error {
+	_, filePath := dataColumnSidecarFilePath(slot, columnIndex, blockRoot)
+	file, err := bs.fs.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
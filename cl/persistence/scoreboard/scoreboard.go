@@ -0,0 +1,229 @@
+package scoreboard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/spf13/afero"
+)
+
+// Kind - type of data served over req/resp
+type Kind uint8
+
+const (
+	Blocks Kind = iota
+	Blobs
+	DataColumns
+	kindsCount
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Blocks:
+		return "blocks"
+	case Blobs:
+		return "blobs"
+	case DataColumns:
+		return "data_columns"
+	default:
+		return fmt.Sprintf("kind(%d)", uint8(k))
+	}
+}
+
+const (
+	fileName    = "scoreboard"
+	fileVersion = 1
+)
+
+// Scoreboard - which slots the node can serve over req/resp: one bitmap of slots per Kind. ByRange handlers
+// consult it to skip missing slots without reading the database.
+//
+// A bitmap is authoritative only inside of its known window [from, to]: slots outside of it may be available
+// without being marked, so they are reported as available and the caller falls back to the database.
+// False positives are fine - the database read just finds nothing, false negatives are not.
+//
+// The scoreboard is persisted only by Close and the file is removed by Open: after a crash it starts
+// from scratch instead of trusting bitmaps which missed the last writes.
+type Scoreboard struct {
+	mu    sync.RWMutex
+	kinds [kindsCount]bitmap
+	fs    afero.Fs // nil - in-memory only
+}
+
+type bitmap struct {
+	slots    *roaring64.Bitmap
+	from, to uint64 // known window, from > to - nothing is known
+}
+
+// New - empty in-memory scoreboard
+func New() *Scoreboard {
+	s := &Scoreboard{}
+	for i := range s.kinds {
+		s.kinds[i] = bitmap{slots: roaring64.New(), from: math.MaxUint64}
+	}
+	return s
+}
+
+// Open - loads the scoreboard persisted in `fs` by Close, or returns an empty one if there is none
+func Open(fs afero.Fs) (*Scoreboard, error) {
+	s := New()
+	s.fs = fs
+	f, err := fs.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("scoreboard: %w", err)
+	}
+	// from now on the file is stale, it is written again on Close
+	if err := fs.Remove(fileName); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close - persists the scoreboard, the file is replaced atomically
+func (s *Scoreboard) Close() error {
+	if s.fs == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, err := s.fs.Create(fileName + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.encode(f); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return s.fs.Rename(fileName+".tmp", fileName)
+}
+
+// Mark - slot of kind is available
+func (s *Scoreboard) Mark(kind Kind, slot uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kinds[kind].slots.Add(slot)
+}
+
+// Prune - slots of kind below `slot` are not available anymore, the known window is kept: they are reported
+// as missing from now on
+func (s *Scoreboard) Prune(kind Kind, slot uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kinds[kind].slots.RemoveRange(0, slot)
+}
+
+// SetKnown - sets the window of slots where the bitmap of kind is authoritative
+func (s *Scoreboard) SetKnown(kind Kind, from, to uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kinds[kind].from, s.kinds[kind].to = from, to
+}
+
+// Known - window of slots where the bitmap of kind is authoritative, from > to - nothing is known
+func (s *Scoreboard) Known(kind Kind) (from, to uint64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kinds[kind].from, s.kinds[kind].to
+}
+
+// Available - whether slot of kind may be available: marked or outside of the known window
+func (s *Scoreboard) Available(kind Kind, slot uint64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := &s.kinds[kind]
+	return slot < b.from || slot > b.to || b.slots.Contains(slot)
+}
+
+// CountAvailable - how many slots of kind in [from, from+count) may be available
+func (s *Scoreboard) CountAvailable(kind Kind, from, count uint64) uint64 {
+	if count == 0 {
+		return 0
+	}
+	to := from + count - 1
+	if to < from { // overflow
+		to = math.MaxUint64
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := &s.kinds[kind]
+	if b.from > b.to || to < b.from || from > b.to {
+		return to - from + 1
+	}
+	// unknown slots below and above the known window count as available
+	unknown := uint64(0)
+	if from < b.from {
+		unknown += b.from - from
+		from = b.from
+	}
+	if to > b.to {
+		unknown += to - b.to
+		to = b.to
+	}
+	marked := b.slots.Rank(to)
+	if from > 0 {
+		marked -= b.slots.Rank(from - 1)
+	}
+	return unknown + marked
+}
+
+func (s *Scoreboard) encode(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte(fileVersion)
+	for i := range s.kinds {
+		b := &s.kinds[i]
+		b.slots.RunOptimize()
+		buf.Write(binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, b.from), b.to))
+		buf.Write(binary.BigEndian.AppendUint64(nil, b.slots.GetSerializedSizeInBytes()))
+		if _, err := b.slots.WriteTo(&buf); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (s *Scoreboard) decode(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || data[0] != fileVersion {
+		return fmt.Errorf("unsupported file version")
+	}
+	data = data[1:]
+	for i := range s.kinds {
+		if len(data) < 24 {
+			return fmt.Errorf("%s: unexpected end of file", Kind(i))
+		}
+		b := &s.kinds[i]
+		b.from, b.to = binary.BigEndian.Uint64(data), binary.BigEndian.Uint64(data[8:])
+		size := binary.BigEndian.Uint64(data[16:])
+		data = data[24:]
+		if uint64(len(data)) < size {
+			return fmt.Errorf("%s: unexpected end of file", Kind(i))
+		}
+		if err := b.slots.UnmarshalBinary(data[:size]); err != nil {
+			return fmt.Errorf("%s: %w", Kind(i), err)
+		}
+		data = data[size:]
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package scoreboard
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+)
+
+func TestAvailable(t *testing.T) {
+	s := New()
+	// nothing is known: everything may be available
+	require.True(t, s.Available(Blobs, 10))
+	require.Equal(t, uint64(5), s.CountAvailable(Blobs, 10, 5))
+
+	s.SetKnown(Blobs, 10, math.MaxUint64)
+	s.Mark(Blobs, 12)
+	s.Mark(Blobs, 20)
+	require.True(t, s.Available(Blobs, 9))
+	require.False(t, s.Available(Blobs, 10))
+	require.True(t, s.Available(Blobs, 12))
+	// 5..9 are unknown, 12 is marked
+	require.Equal(t, uint64(6), s.CountAvailable(Blobs, 5, 10))
+	require.Equal(t, uint64(0), s.CountAvailable(Blobs, 13, 5))
+	require.Equal(t, uint64(1), s.CountAvailable(Blobs, 13, math.MaxUint64))
+	require.Equal(t, uint64(0), s.CountAvailable(Blobs, 12, 0))
+
+	s.Prune(Blobs, 15)
+	require.False(t, s.Available(Blobs, 12))
+	require.True(t, s.Available(Blobs, 20))
+	// other kinds are untouched
+	require.True(t, s.Available(DataColumns, 11))
+}
+
+func TestPersistence(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s, err := Open(fs)
+	require.NoError(t, err)
+	s.SetKnown(DataColumns, 100, math.MaxUint64)
+	s.Mark(DataColumns, 101)
+	s.Mark(Blocks, 7)
+	require.NoError(t, s.Close())
+
+	s, err = Open(fs)
+	require.NoError(t, err)
+	from, to := s.Known(DataColumns)
+	require.Equal(t, uint64(100), from)
+	require.Equal(t, uint64(math.MaxUint64), to)
+	require.True(t, s.Available(DataColumns, 101))
+	require.False(t, s.Available(DataColumns, 102))
+
+	// not closed: the next open starts from scratch
+	s, err = Open(fs)
+	require.NoError(t, err)
+	from, to = s.Known(DataColumns)
+	require.Greater(t, from, to)
+
+	require.NoError(t, afero.WriteFile(fs, fileName, []byte{0xff}, 0644))
+	_, err = Open(fs)
+	require.Error(t, err)
+}
+
+func TestSyncBlocks(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.NewTestDB(t)
+	put := func(slots ...uint64) {
+		tx, err := db.BeginRw(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		for _, slot := range slots {
+			require.NoError(t, beacon_indicies.MarkRootCanonical(ctx, tx, slot, libcommon.Hash{byte(slot)}))
+		}
+		require.NoError(t, tx.Commit())
+	}
+	sync := func(s *Scoreboard, frozen, finalized uint64) {
+		require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+			return s.SyncBlocks(ctx, tx, frozen, finalized)
+		}))
+	}
+
+	s := New()
+	put(105, 106, 108, 110)
+	sync(s, 100, 106)
+	from, to := s.Known(Blocks)
+	require.Equal(t, uint64(105), from)
+	require.Equal(t, uint64(110), to)
+	require.True(t, s.Available(Blocks, 50)) // frozen
+	require.True(t, s.Available(Blocks, 104))
+	require.False(t, s.Available(Blocks, 107))
+	require.True(t, s.Available(Blocks, 111))
+
+	// reorg of the unfinalized tail, new head and backfilling
+	tx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(kv.CanonicalBlockRoots, []byte{0, 0, 0, 108}))
+	require.NoError(t, tx.Commit())
+	put(102, 103, 109, 112)
+	sync(s, 100, 106)
+	from, to = s.Known(Blocks)
+	require.Equal(t, uint64(102), from)
+	require.Equal(t, uint64(112), to)
+	require.False(t, s.Available(Blocks, 108))
+	require.True(t, s.Available(Blocks, 109))
+	require.False(t, s.Available(Blocks, 111))
+	require.Equal(t, uint64(7), s.CountAvailable(Blocks, 102, 11))
+
+	// snapshots advanced
+	sync(s, 105, 110)
+	require.True(t, s.Available(Blocks, 104))
+	require.Equal(t, uint64(4), s.CountAvailable(Blocks, 106, 7))
+}
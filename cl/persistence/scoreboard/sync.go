@@ -0,0 +1,69 @@
+package scoreboard
+
+import (
+	"context"
+	"math"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+)
+
+// SyncBlocks - brings the blocks bitmap up to date with the canonical chain in the database, slots up to
+// frozenSlots are served from snapshots and are left out of the known window. Only the unfinalized tail
+// (which can be reorged) and the slots below the known window (filled by backfilling) are rescanned.
+func (s *Scoreboard) SyncBlocks(ctx context.Context, tx kv.Tx, frozenSlots, finalizedSlot uint64) error {
+	start := uint64(0)
+	if frozenSlots > 0 {
+		start = frozenSlots + 1
+	}
+	from, to := s.Known(Blocks)
+	if from <= to && from < start { // snapshots advanced, these slots were scanned already
+		from = min(start, to+1)
+	}
+	tailFrom := start
+	if from <= to {
+		tailFrom = min(to, finalizedSlot) + 1
+	}
+
+	head, tail := roaring64.New(), roaring64.New()
+	scan := func(bm *roaring64.Bitmap, fromSlot, toSlot uint64) error {
+		return beacon_indicies.RangeBlockRoots(ctx, tx, fromSlot, toSlot, func(slot uint64, root libcommon.Hash) bool {
+			if root != (libcommon.Hash{}) {
+				bm.Add(slot)
+			}
+			return true
+		})
+	}
+	if from <= to && start < from {
+		if err := scan(head, start, from-1); err != nil {
+			return err
+		}
+	}
+	if err := scan(tail, tailFrom, math.MaxUint32); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := &s.kinds[Blocks]
+	if from > to {
+		b.slots.Clear()
+	}
+	b.slots.RemoveRange(0, start)
+	b.slots.RemoveRange(tailFrom, math.MaxUint64)
+	b.slots.Or(head)
+	b.slots.Or(tail)
+	if b.slots.IsEmpty() {
+		b.from, b.to = math.MaxUint64, 0
+		return nil
+	}
+	// slots between the frozen ones and the first canonical root are not known yet: backfilling writes them
+	b.from, b.to = b.slots.Minimum(), b.slots.Maximum()
+	if tailFrom > 0 {
+		b.to = max(b.to, tailFrom-1)
+	}
+	return nil
+}
@@ -42,6 +42,19 @@ func runTest(t *testing.T, blocks []*cltypes.SignedBeaconBlock, preState, postSt
 	_, err = postState.HashSSZ()
 	require.NoError(t, err)
 	require.Equal(t, libcommon.Hash(postHash), blocks[len(blocks)-1].Block.StateRoot)
+
+	// Replaying from the nearest stored state must lead to the same state
+	lastSlot := blocks[len(blocks)-1].Block.Slot
+	s, err = hr.ReconstructState(ctx, tx, lastSlot, lastSlot)
+	require.NoError(t, err)
+	replayedHash, err := s.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, postHash, replayedHash)
+
+	// Slots after the head didn't happen, there is nothing to reconstruct
+	s, err = hr.ReconstructState(ctx, tx, lastSlot+1, lastSlot)
+	require.NoError(t, err)
+	require.Nil(t, s)
 }
 
 func TestStateAntiquaryCapella(t *testing.T) {
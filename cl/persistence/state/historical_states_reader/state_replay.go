@@ -0,0 +1,74 @@
+package historical_states_reader
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	state_accessors "github.com/ledgerwatch/erigon/cl/persistence/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/transition"
+)
+
+// MaxReplaySlots is the maximum distance between the requested slot and the nearest state which can be read from the archive.
+const MaxReplaySlots = 1024
+
+// ReconstructState reconstructs the full beacon state at any past slot. Unlike ReadHistoricalState, it also works for slots
+// without a block (skipped slots) and for slots which are not yet processed by the antiquary: it reads the nearest stored state at or
+// before the slot and replays the canonical blocks (and empty slots) on top of it. Slots after headSlot didn't happen yet,
+// nil is returned for them.
+func (r *HistoricalStatesReader) ReconstructState(ctx context.Context, tx kv.Tx, slot, headSlot uint64) (*state.CachingBeaconState, error) {
+	if slot > headSlot {
+		return nil, nil
+	}
+	latestProcessedState, err := state_accessors.GetStateProcessingProgress(tx)
+	if err != nil {
+		return nil, err
+	}
+	baseSlot := min(slot, latestProcessedState, r.validatorTable.Slot())
+	if slot-baseSlot > MaxReplaySlots {
+		return nil, nil
+	}
+
+	// Find the nearest stored state.
+	var baseState *state.CachingBeaconState
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		baseState, err = r.ReadHistoricalState(ctx, tx, baseSlot)
+		if err != nil {
+			return nil, err
+		}
+		if baseState != nil {
+			break
+		}
+		if baseSlot == 0 || slot-baseSlot >= MaxReplaySlots {
+			return nil, nil
+		}
+		baseSlot--
+	}
+
+	// Replay the canonical chain up to the requested slot.
+	for currentSlot := baseSlot + 1; currentSlot <= slot; currentSlot++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := r.blockReader.ReadBlockBySlot(ctx, tx, currentSlot)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+		if err := transition.TransitionState(baseState, block, nil, false); err != nil {
+			return nil, fmt.Errorf("failed to replay block at slot %d: %w", currentSlot, err)
+		}
+	}
+	if baseState.Slot() < slot {
+		if err := transition.DefaultMachine.ProcessSlots(baseState, slot); err != nil {
+			return nil, fmt.Errorf("failed to process empty slots up to %d: %w", slot, err)
+		}
+	}
+	return baseState, nil
+}
@@ -3,9 +3,15 @@ package core
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
@@ -61,6 +67,90 @@ func RetrieveBeaconState(ctx context.Context, beaconConfig *clparams.BeaconChain
 	return beaconState, nil
 }
 
+// checkpointProbeTimeout - providers not answering the health probe in time are tried last
+const checkpointProbeTimeout = 5 * time.Second
+
+// RetrieveBeaconStateFromProviders retrieves the checkpoint state from the fastest healthy provider, falling back
+// to the next ones on failure.
+func RetrieveBeaconStateFromProviders(ctx context.Context, beaconConfig *clparams.BeaconChainConfig, uris []string) (*state.CachingBeaconState, error) {
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("checkpoint sync failed, no providers")
+	}
+	ranked := uris
+	if len(uris) > 1 {
+		ranked = RankCheckpointSyncProviders(ctx, uris)
+	}
+	var errs []error
+	for _, uri := range ranked {
+		beaconState, err := RetrieveBeaconState(ctx, beaconConfig, uri)
+		if err == nil {
+			log.Info("[Checkpoint Sync] Retrieved beacon state", "uri", uri, "slot", beaconState.Slot())
+			return beaconState, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn("[Checkpoint Sync] Provider failed, trying the next one", "uri", uri, "err", err)
+		errs = append(errs, fmt.Errorf("%s: %w", uri, err))
+	}
+	return nil, fmt.Errorf("checkpoint sync failed with all providers: %w", errors.Join(errs...))
+}
+
+// RankCheckpointSyncProviders orders the providers by the latency of their health probe, the fastest first.
+// Providers failing the probe are kept at the end of the list, in their original order, as the last resort.
+func RankCheckpointSyncProviders(ctx context.Context, uris []string) []string {
+	latencies := make([]time.Duration, len(uris))
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		wg.Add(1)
+		go func(i int, uri string) {
+			defer wg.Done()
+			latencies[i] = probeCheckpointSyncProvider(ctx, uri)
+		}(i, uri)
+	}
+	wg.Wait()
+
+	order := make([]int, len(uris))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return latencies[order[i]] < latencies[order[j]] })
+	ranked := make([]string, 0, len(uris))
+	for _, i := range order {
+		if latencies[i] == math.MaxInt64 {
+			log.Debug("[Checkpoint Sync] Provider is not healthy", "uri", uris[i])
+		}
+		ranked = append(ranked, uris[i])
+	}
+	return ranked
+}
+
+// probeCheckpointSyncProvider returns the latency of the provider's beacon API, math.MaxInt64 if it's not healthy.
+// The state itself is too big to probe, so beacon API providers are asked for the genesis, others for the headers only.
+func probeCheckpointSyncProvider(ctx context.Context, uri string) time.Duration {
+	ctx, cancel := context.WithTimeout(ctx, checkpointProbeTimeout)
+	defer cancel()
+
+	method, probeUri := http.MethodHead, uri
+	if i := strings.Index(uri, "/eth/"); i >= 0 {
+		method, probeUri = http.MethodGet, uri[:i]+"/eth/v1/beacon/genesis"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, probeUri, nil)
+	if err != nil {
+		return math.MaxInt64
+	}
+	start := time.Now()
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return math.MaxInt64
+	}
+	defer r.Body.Close()
+	if _, err := io.Copy(io.Discard, r.Body); err != nil || r.StatusCode != http.StatusOK {
+		return math.MaxInt64
+	}
+	return time.Since(start)
+}
+
 func RetrieveBlock(ctx context.Context, beaconConfig *clparams.BeaconChainConfig, uri string, expectedBlockRoot *libcommon.Hash) (*cltypes.SignedBeaconBlock, error) {
 	log.Debug("[Checkpoint Sync] Requesting beacon block", "uri", uri)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankCheckpointSyncProviders(t *testing.T) {
+	provider := func(delay time.Duration, status int) *httptest.Server {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/eth/v1/beacon/genesis", r.URL.Path)
+			time.Sleep(delay)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(s.Close)
+		return s
+	}
+	const statePath = "/eth/v2/debug/beacon/states/finalized"
+	slow := provider(200*time.Millisecond, http.StatusOK).URL + statePath
+	fast := provider(0, http.StatusOK).URL + statePath
+	broken := provider(0, http.StatusServiceUnavailable).URL + statePath
+	down := "http://127.0.0.1:1" + statePath
+
+	ranked := RankCheckpointSyncProviders(context.Background(), []string{down, broken, slow, fast})
+	require.Equal(t, []string{fast, slow, down, broken}, ranked)
+}
@@ -2,6 +2,7 @@ package raw
 
 import (
 	"fmt"
+	"io"
 
 	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
 
@@ -49,6 +50,11 @@ func (b *BeaconState) EncodeSSZ(buf []byte) ([]byte, error) {
 	return ssz2.MarshalSSZ(buf, b.getSchema()...)
 }
 
+// WriteSSZ writes the ssz encoding of the state to w component by component, without encoding the whole state in memory.
+func (b *BeaconState) WriteSSZ(w io.Writer) error {
+	return ssz2.MarshalSSZTo(w, b.getSchema()...)
+}
+
 // getSchema gives the schema for the current beacon state version according to ETH 2.0 specs.
 func (b *BeaconState) getSchema() []interface{} {
 	s := []interface{}{&b.genesisTime, b.genesisValidatorsRoot[:], &b.slot, b.fork, b.latestBlockHeader, b.blockRoots, b.stateRoots, b.historicalRoots,
@@ -111,7 +111,7 @@ type forkGraphDisk struct {
 
 	newestLightClientUpdate atomic.Value
 	// the lightclientUpdates leaks memory, but it's not a big deal since new data is added every 27 hours.
-	lightClientUpdates sync.Map // period -> lightclientupdate
+	lightClientUpdates sync.Map // period -> best lightclientupdate of the period
 
 	// reusable buffers
 	sszBuffer       bytes.Buffer
@@ -213,10 +213,12 @@ func (f *forkGraphDisk) AddChainSegment(signedBlock *cltypes.SignedBeaconBlock,
 		} else {
 			f.newestLightClientUpdate.Store(lightclientUpdate)
 			period := f.beaconCfg.SyncCommitteePeriod(newState.Slot())
-			_, hasPeriod := f.lightClientUpdates.Load(period)
+			prevUpdate, hasPeriod := f.lightClientUpdates.Load(period)
 			if !hasPeriod {
 				log.Info("Adding light client update", "period", period)
 				f.lightClientUpdates.Store(period, lightclientUpdate)
+			} else if lightclient_utils.IsBetterUpdate(f.beaconCfg, lightclientUpdate, prevUpdate.(*cltypes.LightClientUpdate)) {
+				f.lightClientUpdates.Store(period, lightclientUpdate)
 			}
 		}
 	}
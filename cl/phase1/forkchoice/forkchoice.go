@@ -11,6 +11,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/monitor"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
@@ -86,6 +87,7 @@ type ForkChoiceStore struct {
 	equivocatingIndicies []byte
 	forkGraph            fork_graph.ForkGraph
 	blobStorage          blob_storage.BlobStorage
+	validatorMonitor     monitor.ValidatorMonitor
 	// I use the cache due to the convenient auto-cleanup feauture.
 	checkpointStates sync.Map // We keep ssz snappy of it as the full beacon state is full of rendundant data.
 
@@ -140,6 +142,7 @@ func NewForkChoiceStore(
 	emitters *beaconevents.Emitters,
 	syncedDataManager *synced_data.SyncedDataManager,
 	blobStorage blob_storage.BlobStorage,
+	validatorMonitor monitor.ValidatorMonitor,
 ) (*ForkChoiceStore, error) {
 	anchorRoot, err := anchorState.BlockRoot()
 	if err != nil {
@@ -236,6 +239,7 @@ func NewForkChoiceStore(
 		genesisValidatorsRoot: anchorState.GenesisValidatorsRoot(),
 		hotSidecars:           make(map[libcommon.Hash][]*cltypes.BlobSidecar),
 		blobStorage:           blobStorage,
+		validatorMonitor:      validatorMonitor,
 		ethClock:              ethClock,
 	}
 	f.justifiedCheckpoint.Store(anchorCheckpoint.Copy())
@@ -128,6 +128,9 @@ func (f *ForkChoiceStore) OnBlock(ctx context.Context, block *cltypes.SignedBeac
 	if block.Block.Body.ExecutionPayload != nil {
 		f.eth2Roots.Add(blockRoot, block.Block.Body.ExecutionPayload.BlockHash)
 	}
+	if err := f.validatorMonitor.OnNewBlock(lastProcessedState, block.Block); err != nil {
+		log.Warn("OnBlock: validator monitor failed", "slot", block.Block.Slot, "err", err)
+	}
 
 	if block.Block.Slot > f.highestSeen.Load() {
 		f.highestSeen.Store(block.Block.Slot)
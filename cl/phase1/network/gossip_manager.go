@@ -10,7 +10,6 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
 	sentinel "github.com/ledgerwatch/erigon-lib/gointerfaces/sentinelproto"
-	"github.com/ledgerwatch/erigon-lib/types/ssz"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
@@ -39,6 +38,7 @@ type GossipManager struct {
 	// Services for processing messages from the network
 	blockService                 services.BlockService
 	blobService                  services.BlobSidecarsService
+	dataColumnService            services.DataColumnSidecarService
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService
 	syncContributionService      services.SyncContributionService
 	aggregateAndProofService     services.AggregateAndProofService
@@ -46,6 +46,7 @@ type GossipManager struct {
 	voluntaryExitService         services.VoluntaryExitService
 	blsToExecutionChangeService  services.BLSToExecutionChangeService
 	proposerSlashingService      services.ProposerSlashingService
+	attesterSlashingService      services.AttesterSlashingService
 }
 
 func NewGossipReceiver(
@@ -57,6 +58,7 @@ func NewGossipReceiver(
 	comitteeSub *committee_subscription.CommitteeSubscribeMgmt,
 	blockService services.BlockService,
 	blobService services.BlobSidecarsService,
+	dataColumnService services.DataColumnSidecarService,
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService,
 	syncContributionService services.SyncContributionService,
 	aggregateAndProofService services.AggregateAndProofService,
@@ -64,6 +66,7 @@ func NewGossipReceiver(
 	voluntaryExitService services.VoluntaryExitService,
 	blsToExecutionChangeService services.BLSToExecutionChangeService,
 	proposerSlashingService services.ProposerSlashingService,
+	attesterSlashingService services.AttesterSlashingService,
 ) *GossipManager {
 	return &GossipManager{
 		sentinel:                     s,
@@ -74,6 +77,7 @@ func NewGossipReceiver(
 		committeeSub:                 comitteeSub,
 		blockService:                 blockService,
 		blobService:                  blobService,
+		dataColumnService:            dataColumnService,
 		syncCommitteeMessagesService: syncCommitteeMessagesService,
 		syncContributionService:      syncContributionService,
 		aggregateAndProofService:     aggregateAndProofService,
@@ -81,25 +85,10 @@ func NewGossipReceiver(
 		voluntaryExitService:         voluntaryExitService,
 		blsToExecutionChangeService:  blsToExecutionChangeService,
 		proposerSlashingService:      proposerSlashingService,
+		attesterSlashingService:      attesterSlashingService,
 	}
 }
 
-func operationsContract[T ssz.EncodableSSZ](ctx context.Context, g *GossipManager, data *sentinel.GossipData, version int, name string, fn func(T, bool) error) error {
-	var t T
-	object := t.Clone().(T)
-	if err := object.DecodeSSZ(common.CopyBytes(data.Data), version); err != nil {
-		g.sentinel.BanPeer(ctx, data.Peer)
-		return err
-	}
-	if err := fn(object /*test=*/, false); err != nil {
-		return err
-	}
-	if _, err := g.sentinel.PublishGossip(ctx, data); err != nil {
-		log.Debug("failed publish gossip", "err", err)
-	}
-	return nil
-}
-
 func (g *GossipManager) onRecv(ctx context.Context, data *sentinel.GossipData, l log.Ctx) (err error) {
 	// defer func() {
 	// 	r := recover()
@@ -169,7 +158,11 @@ func (g *GossipManager) routeAndProcess(ctx context.Context, data *sentinel.Goss
 		}
 		return g.proposerSlashingService.ProcessMessage(ctx, data.SubnetId, obj)
 	case gossip.TopicNameAttesterSlashing:
-		return operationsContract[*cltypes.AttesterSlashing](ctx, g, data, int(version), "attester slashing", g.forkChoice.OnAttesterSlashing)
+		obj := cltypes.NewAttesterSlashing()
+		if err := obj.DecodeSSZ(data.Data, int(version)); err != nil {
+			return err
+		}
+		return g.attesterSlashingService.ProcessMessage(ctx, data.SubnetId, obj)
 	case gossip.TopicNameBlsToExecutionChange:
 		obj := &cltypes.SignedBLSToExecutionChange{}
 		if err := obj.DecodeSSZ(data.Data, int(version)); err != nil {
@@ -193,6 +186,12 @@ func (g *GossipManager) routeAndProcess(ctx context.Context, data *sentinel.Goss
 			defer log.Debug("Received blob sidecar via gossip", "index", *data.SubnetId, "size", datasize.ByteSize(len(blobSideCar.Blob)))
 			// The background checks above are enough for now.
 			return g.blobService.ProcessMessage(ctx, data.SubnetId, blobSideCar)
+		case gossip.IsTopicDataColumnSidecar(data.Name):
+			sidecar := cltypes.NewDataColumnSidecar()
+			if err := sidecar.DecodeSSZ(data.Data, int(version)); err != nil {
+				return err
+			}
+			return g.dataColumnService.ProcessMessage(ctx, data.SubnetId, sidecar)
 		case gossip.IsTopicSyncCommittee(data.Name):
 			msg := &cltypes.SyncCommitteeMessage{}
 			if err := msg.DecodeSSZ(common.CopyBytes(data.Data), int(version)); err != nil {
@@ -268,7 +267,7 @@ Reconnect:
 				continue Reconnect
 			}
 
-			if data.Name == gossip.TopicNameBeaconBlock || gossip.IsTopicBlobSidecar(data.Name) {
+			if data.Name == gossip.TopicNameBeaconBlock || gossip.IsTopicBlobSidecar(data.Name) || gossip.IsTopicDataColumnSidecar(data.Name) {
 				blocksCh <- data
 			} else if gossip.IsTopicSyncCommittee(data.Name) || data.Name == gossip.TopicNameSyncCommitteeContributionAndProof {
 				syncCommitteesCh <- data
@@ -13,25 +13,34 @@ import (
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
-	"github.com/ledgerwatch/erigon/cl/fork"
 	"github.com/ledgerwatch/erigon/cl/merkle_tree"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
+var (
+	isAggregator                = state.IsAggregator
+	blsVerifyMultipleSignatures = bls.VerifyMultipleSignatures
+	blsAggregatePublicKeys      = bls.AggregatePublickKeys
+)
+
 type aggregateJob struct {
 	aggregate    *cltypes.SignedAggregateAndProof
 	creationTime time.Time
 }
 
 type aggregateAndProofServiceImpl struct {
-	syncedDataManager *synced_data.SyncedDataManager
+	syncedDataManager synced_data.SyncedData
 	forkchoiceStore   forkchoice.ForkChoiceStorage
 	beaconCfg         *clparams.BeaconChainConfig
 	opPool            pool.OperationsPool
-	test              bool
+	slasher           slasher.Slasher
+	// aggregatorSeen is used to ignore aggregates from an aggregator which already had a valid aggregate in the same target epoch.
+	aggregatorSeen *lru.CacheWithTTL[uint64, uint64] // aggregator index -> target epoch
 
 	// set of aggregates that are scheduled for later processing
 	aggregatesScheduledForLaterExecution sync.Map
@@ -39,18 +48,20 @@ type aggregateAndProofServiceImpl struct {
 
 func NewAggregateAndProofService(
 	ctx context.Context,
-	syncedDataManager *synced_data.SyncedDataManager,
+	syncedDataManager synced_data.SyncedData,
 	forkchoiceStore forkchoice.ForkChoiceStorage,
 	beaconCfg *clparams.BeaconChainConfig,
 	opPool pool.OperationsPool,
-	test bool,
+	slasher slasher.Slasher,
 ) AggregateAndProofService {
+	epochDuration := time.Duration(beaconCfg.SlotsPerEpoch*beaconCfg.SecondsPerSlot) * time.Second
 	a := &aggregateAndProofServiceImpl{
 		syncedDataManager: syncedDataManager,
 		forkchoiceStore:   forkchoiceStore,
 		beaconCfg:         beaconCfg,
 		opPool:            opPool,
-		test:              test,
+		slasher:           slasher,
+		aggregatorSeen:    lru.NewWithTTL[uint64, uint64]("aggregator_seen", validatorAttestationCacheSize, epochDuration),
 	}
 	go a.loop(ctx)
 	return a
@@ -66,6 +77,7 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 		return ErrIgnore
 	}
 	selectionProof := aggregateAndProof.Message.SelectionProof
+	aggregatorIndex := aggregateAndProof.Message.AggregatorIndex
 	aggregateData := aggregateAndProof.Message.Aggregate.AttestantionData()
 	target := aggregateAndProof.Message.Aggregate.AttestantionData().Target()
 	slot := aggregateAndProof.Message.Aggregate.AttestantionData().Slot()
@@ -104,13 +116,17 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 	if aggregateData.Target().Epoch() != epoch {
 		return fmt.Errorf("invalid target epoch in aggregate and proof")
 	}
+	// [IGNORE] The aggregate is the first valid aggregate received for the aggregator with index aggregate_and_proof.aggregator_index for the epoch aggregate.data.target.epoch.
+	if seenEpoch, ok := a.aggregatorSeen.Get(aggregatorIndex); ok && seenEpoch == target.Epoch() {
+		return fmt.Errorf("aggregator already seen in target epoch %w", ErrIgnore)
+	}
 	committee, err := headState.GetBeaconCommitee(slot, committeeIndex)
 	if err != nil {
 		return err
 	}
 
 	// [REJECT] The aggregator's validator index is within the committee -- i.e. aggregate_and_proof.aggregator_index in get_beacon_committee(state, aggregate.data.slot, index).
-	if !slices.Contains(committee, aggregateAndProof.Message.AggregatorIndex) {
+	if !slices.Contains(committee, aggregatorIndex) {
 		return fmt.Errorf("committee index not in committee")
 	}
 	// [REJECT] The aggregate attestation's target block is an ancestor of the block named in the LMD vote -- i.e. get_checkpoint_block(store, aggregate.data.beacon_block_root, aggregate.data.target.epoch) == aggregate.data.target.root
@@ -120,12 +136,9 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 	) != target.BlockRoot() {
 		return fmt.Errorf("invalid target block")
 	}
-	if a.test {
-		return nil
-	}
 
 	// [REJECT] aggregate_and_proof.selection_proof selects the validator as an aggregator for the slot -- i.e. is_aggregator(state, aggregate.data.slot, index, aggregate_and_proof.selection_proof) returns True.
-	if !state.IsAggregator(a.beaconCfg, uint64(len(committee)), committeeIndex, selectionProof) {
+	if !isAggregator(a.beaconCfg, uint64(len(committee)), committeeIndex, selectionProof) {
 		log.Warn("receveived aggregate and proof from invalid aggregator")
 		return fmt.Errorf("invalid aggregate and proof")
 	}
@@ -137,13 +150,20 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 	if err != nil {
 		return err
 	}
-	if err := verifySignaturesOnAggregate(headState, aggregateAndProof); err != nil {
+	if err := verifySignaturesOnAggregate(headState, aggregateAndProof, attestingIndicies); err != nil {
 		return err
-	} // Add to aggregation pool
+	}
+	a.slasher.OnAttestation(state.GetIndexedAttestation(aggregateAndProof.Message.Aggregate, attestingIndicies))
+	a.aggregatorSeen.Add(aggregatorIndex, target.Epoch())
+
+	// Add to aggregation pool
 	a.opPool.AttestationsPool.Insert(
 		aggregateAndProof.Message.Aggregate.Signature(),
 		aggregateAndProof.Message.Aggregate,
 	)
+	if err := a.opPool.BlockAttestationsPool.Add(aggregateAndProof.Message.Aggregate); err != nil {
+		log.Trace("Could not add aggregate to block attestations pool", "err", err)
+	}
 	a.forkchoiceStore.ProcessAttestingIndicies(
 		aggregateAndProof.Message.Aggregate,
 		attestingIndicies,
@@ -151,54 +171,43 @@ func (a *aggregateAndProofServiceImpl) ProcessMessage(
 	return nil
 }
 
+// verifySignaturesOnAggregate batch-verifies the selection proof, the aggregator signature and the aggregate signature.
 func verifySignaturesOnAggregate(
 	s *state.CachingBeaconState,
 	aggregateAndProof *cltypes.SignedAggregateAndProof,
+	attestingIndicies []uint64,
 ) error {
-	aggregationBits := aggregateAndProof.Message.Aggregate.AggregationBits()
 	// [REJECT] The aggregate attestation has participants -- that is, len(get_attesting_indices(state, aggregate)) >= 1.
-	attestingIndicies, err := s.GetAttestingIndicies(
-		aggregateAndProof.Message.Aggregate.AttestantionData(),
-		aggregationBits,
-		true,
-	)
-	if err != nil {
-		return err
-	}
 	if len(attestingIndicies) == 0 {
 		return fmt.Errorf("no attesting indicies")
 	}
+	var (
+		signatures = make([][]byte, 0, 3)
+		messages   = make([][]byte, 0, 3)
+		publicKeys = make([][]byte, 0, 3)
+	)
 	// [REJECT] The aggregate_and_proof.selection_proof is a valid signature of the aggregate.data.slot by the validator with index aggregate_and_proof.aggregator_index.
-	if err := verifyAggregateAndProofSignature(s, aggregateAndProof.Message); err != nil {
+	signature, signingRoot, publicKey, err := aggregateAndProofSignatureSet(s, aggregateAndProof.Message)
+	if err != nil {
 		return err
 	}
+	signatures, messages, publicKeys = append(signatures, signature), append(messages, signingRoot), append(publicKeys, publicKey)
 
 	// [REJECT] The aggregator signature, signed_aggregate_and_proof.signature, is valid.
-	if err := verifyAggregatorSignature(s, aggregateAndProof); err != nil {
-		return err
-	}
-
-	return verifyAggregateMessageSignature(s, aggregateAndProof, attestingIndicies)
-}
-
-func verifyAggregateAndProofSignature(
-	state *state.CachingBeaconState,
-	aggregate *cltypes.AggregateAndProof,
-) error {
-	slot := aggregate.Aggregate.AttestantionData().Slot()
-	publicKey, err := state.ValidatorPublicKey(int(aggregate.AggregatorIndex))
+	signature, signingRoot, publicKey, err = aggregatorSignatureSet(s, aggregateAndProof)
 	if err != nil {
 		return err
 	}
-	domain, err := state.GetDomain(
-		state.BeaconConfig().DomainSelectionProof,
-		slot*state.BeaconConfig().SlotsPerEpoch,
-	)
+	signatures, messages, publicKeys = append(signatures, signature), append(messages, signingRoot), append(publicKeys, publicKey)
+
+	// [REJECT] The signature of aggregate is valid.
+	signature, signingRoot, publicKey, err = aggregateMessageSignatureSet(s, aggregateAndProof, attestingIndicies)
 	if err != nil {
 		return err
 	}
-	signingRoot := utils.Sha256(merkle_tree.Uint64Root(slot).Bytes(), domain)
-	valid, err := bls.Verify(aggregate.SelectionProof[:], signingRoot[:], publicKey[:])
+	signatures, messages, publicKeys = append(signatures, signature), append(messages, signingRoot), append(publicKeys, publicKey)
+
+	valid, err := blsVerifyMultipleSignatures(signatures, messages, publicKeys)
 	if err != nil {
 		return err
 	}
@@ -208,50 +217,76 @@ func verifyAggregateAndProofSignature(
 	return nil
 }
 
-func verifyAggregatorSignature(
-	state *state.CachingBeaconState,
-	aggregate *cltypes.SignedAggregateAndProof,
-) error {
-	publicKey, err := state.ValidatorPublicKey(int(aggregate.Message.AggregatorIndex))
+func aggregateAndProofSignatureSet(
+	s *state.CachingBeaconState,
+	aggregate *cltypes.AggregateAndProof,
+) (signature, signingRoot, publicKey []byte, err error) {
+	slot := aggregate.Aggregate.AttestantionData().Slot()
+	aggregatorPublicKey, err := s.ValidatorPublicKey(int(aggregate.AggregatorIndex))
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
-	domain, err := state.GetDomain(state.BeaconConfig().DomainAggregateAndProof, state.Slot())
+	domain, err := s.GetDomain(
+		s.BeaconConfig().DomainSelectionProof,
+		slot/s.BeaconConfig().SlotsPerEpoch,
+	)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
-	signingRoot, err := fork.ComputeSigningRoot(aggregate.Message, domain)
+	root := utils.Sha256(merkle_tree.Uint64Root(slot).Bytes(), domain)
+	return aggregate.SelectionProof[:], root[:], aggregatorPublicKey[:], nil
+}
+
+func aggregatorSignatureSet(
+	s *state.CachingBeaconState,
+	aggregate *cltypes.SignedAggregateAndProof,
+) (signature, signingRoot, publicKey []byte, err error) {
+	aggregatorPublicKey, err := s.ValidatorPublicKey(int(aggregate.Message.AggregatorIndex))
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
-	valid, err := bls.Verify(aggregate.Signature[:], signingRoot[:], publicKey[:])
+	domain, err := s.GetDomain(
+		s.BeaconConfig().DomainAggregateAndProof,
+		aggregate.Message.Aggregate.AttestantionData().Slot()/s.BeaconConfig().SlotsPerEpoch,
+	)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
-	if !valid {
-		return fmt.Errorf("invalid bls signature on aggregate and proof")
+	root, err := computeSigningRoot(aggregate.Message, domain)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-	return nil
+	return aggregate.Signature[:], root[:], aggregatorPublicKey[:], nil
 }
 
-func verifyAggregateMessageSignature(
+func aggregateMessageSignatureSet(
 	s *state.CachingBeaconState,
 	aggregateAndProof *cltypes.SignedAggregateAndProof,
 	attestingIndicies []uint64,
-) error {
-	indexedAttestation := state.GetIndexedAttestation(
-		aggregateAndProof.Message.Aggregate,
-		attestingIndicies,
-	)
-
-	valid, err := state.IsValidIndexedAttestation(s, indexedAttestation)
+) (signature, signingRoot, publicKey []byte, err error) {
+	aggregate := aggregateAndProof.Message.Aggregate
+	publicKeys := make([][]byte, 0, len(attestingIndicies))
+	for _, index := range attestingIndicies {
+		pk, err := s.ValidatorPublicKey(int(index))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		publicKeys = append(publicKeys, pk[:])
+	}
+	aggregatePublicKey, err := blsAggregatePublicKeys(publicKeys)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
 	}
-	if !valid {
-		return fmt.Errorf("invalid aggregate signature")
+	domain, err := s.GetDomain(s.BeaconConfig().DomainBeaconAttester, aggregate.AttestantionData().Target().Epoch())
+	if err != nil {
+		return nil, nil, nil, err
 	}
-	return nil
+	root, err := computeSigningRoot(aggregate.AttestantionData(), domain)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	aggregateSignature := aggregate.Signature()
+	return aggregateSignature[:], root[:], aggregatePublicKey, nil
 }
 
 func (a *aggregateAndProofServiceImpl) scheduleAggregateForLaterProcessing(
@@ -294,7 +329,7 @@ func (a *aggregateAndProofServiceImpl) loop(ctx context.Context) {
 			}
 
 			if err := a.ProcessMessage(ctx, nil, job.aggregate); err != nil {
-				log.Trace("blob sidecar verification failed", "err", err)
+				log.Trace("aggregate and proof verification failed", "err", err)
 				return true
 			}
 			a.aggregatesScheduledForLaterExecution.Delete(key.([32]byte))
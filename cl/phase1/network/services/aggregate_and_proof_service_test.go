@@ -9,6 +9,7 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
 	"github.com/ledgerwatch/erigon/cl/antiquary/tests"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -17,6 +18,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/mock_services"
 	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 )
 
 func getAggregateAndProofAndState(t *testing.T) (*cltypes.SignedAggregateAndProof, *state.CachingBeaconState) {
@@ -26,7 +28,7 @@ func getAggregateAndProofAndState(t *testing.T) (*cltypes.SignedAggregateAndProo
 	a := &cltypes.SignedAggregateAndProof{
 		Message: &cltypes.AggregateAndProof{
 			AggregatorIndex: 141,
-			Aggregate: solid.NewAttestionFromParameters([]byte{1, 2}, solid.NewAttestionDataFromParameters(
+			Aggregate: solid.NewAttestionFromParameters([]byte{1, 1}, solid.NewAttestionDataFromParameters(
 				s.Slot(),
 				0,
 				br,
@@ -49,7 +51,12 @@ func setupAggregateAndProofTest(t *testing.T) (AggregateAndProofService, *synced
 	forkchoiceMock := mock_services.NewForkChoiceStorageMock(t)
 	p := pool.OperationsPool{}
 	p.AttestationsPool = pool.NewOperationPool[libcommon.Bytes96, *solid.Attestation](100, "test")
-	blockService := NewAggregateAndProofService(ctx, syncedDataManager, forkchoiceMock, cfg, p, true)
+	p.BlockAttestationsPool = pool.NewAttestationPool(cfg)
+	computeSigningRoot = func(obj ssz.HashableSSZ, domain []byte) ([32]byte, error) { return [32]byte{}, nil }
+	isAggregator = func(*clparams.BeaconChainConfig, uint64, uint64, libcommon.Bytes96) bool { return true }
+	blsAggregatePublicKeys = func(pubKeys [][]byte) ([]byte, error) { return make([]byte, 48), nil }
+	blsVerifyMultipleSignatures = func(sigs, msgs, pubKeys [][]byte) (bool, error) { return true, nil }
+	blockService := NewAggregateAndProofService(ctx, syncedDataManager, forkchoiceMock, cfg, p, slasher.NewDummySlasher())
 	return blockService, syncedDataManager, forkchoiceMock
 }
 
@@ -170,3 +177,38 @@ func TestAggregateAndProofSuccess(t *testing.T) {
 	fcu.Headers[agg.Message.Aggregate.AttestantionData().BeaconBlockRoot()] = &cltypes.BeaconBlockHeader{}
 	require.NoError(t, aggService.ProcessMessage(context.Background(), nil, agg))
 }
+
+func TestAggregateAndProofAggregatorAlreadySeen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, s := getAggregateAndProofAndState(t)
+
+	aggService, sd, fcu := setupAggregateAndProofTest(t)
+	sd.OnHeadState(s)
+	fcu.FinalizedCheckpointVal = s.FinalizedCheckpoint()
+	fcu.Ancestors[s.FinalizedCheckpoint().Epoch()*32] = s.FinalizedCheckpoint().BlockRoot()
+	fcu.Ancestors[agg.Message.Aggregate.AttestantionData().Slot()] = agg.Message.Aggregate.AttestantionData().Target().BlockRoot()
+	fcu.Headers[agg.Message.Aggregate.AttestantionData().BeaconBlockRoot()] = &cltypes.BeaconBlockHeader{}
+	require.NoError(t, aggService.ProcessMessage(context.Background(), nil, agg))
+	require.ErrorIs(t, aggService.ProcessMessage(context.Background(), nil, agg), ErrIgnore)
+}
+
+func TestAggregateAndProofInvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg, s := getAggregateAndProofAndState(t)
+
+	aggService, sd, fcu := setupAggregateAndProofTest(t)
+	blsVerifyMultipleSignatures = func(sigs, msgs, pubKeys [][]byte) (bool, error) {
+		require.Len(t, sigs, 3)
+		return false, nil
+	}
+	sd.OnHeadState(s)
+	fcu.FinalizedCheckpointVal = s.FinalizedCheckpoint()
+	fcu.Ancestors[s.FinalizedCheckpoint().Epoch()*32] = s.FinalizedCheckpoint().BlockRoot()
+	fcu.Ancestors[agg.Message.Aggregate.AttestantionData().Slot()] = agg.Message.Aggregate.AttestantionData().Target().BlockRoot()
+	fcu.Headers[agg.Message.Aggregate.AttestantionData().BeaconBlockRoot()] = &cltypes.BeaconBlockHeader{}
+	require.Error(t, aggService.ProcessMessage(context.Background(), nil, agg))
+}
@@ -8,17 +8,22 @@ import (
 	"time"
 
 	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/aggregation"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/fork"
-	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/phase1/network/subnets"
+	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/ledgerwatch/erigon/cl/validator/committee_subscription"
+	"github.com/ledgerwatch/log/v3"
 )
 
 var (
@@ -35,9 +40,16 @@ type attestationService struct {
 	syncedDataManager  synced_data.SyncedData
 	beaconCfg          *clparams.BeaconChainConfig
 	netCfg             *clparams.NetworkConfig
-	// validatorAttestationSeen maps from epoch to validator index. This is used to ignore duplicate validator attestations in the same epoch.
-	validatorAttestationSeen       *lru.CacheWithTTL[uint64, uint64] // validator index -> epoch
-	attestationsToBeLaterProcessed sync.Map
+	emitters           *beaconevents.Emitters
+	opPool             pool.OperationsPool
+	slasher            slasher.Slasher
+	// seenAttestations is used to ignore duplicate validator attestations in the same target epoch.
+	seenAttestations *SeenAttestationCache
+
+	// pendingAttestations holds attestations whose beacon block root has not been imported yet, keyed by that root.
+	pendingAttestationsMutex sync.Mutex
+	pendingAttestations      map[libcommon.Hash][]*attestationJob
+	pendingAttestationsCount int
 }
 
 func NewAttestationService(
@@ -48,22 +60,35 @@ func NewAttestationService(
 	syncedDataManager synced_data.SyncedData,
 	beaconCfg *clparams.BeaconChainConfig,
 	netCfg *clparams.NetworkConfig,
+	emitters *beaconevents.Emitters,
+	opPool pool.OperationsPool,
+	slasher slasher.Slasher,
+	seenAttestations *SeenAttestationCache,
 ) AttestationService {
-	epochDuration := time.Duration(beaconCfg.SlotsPerEpoch*beaconCfg.SecondsPerSlot) * time.Second
 	a := &attestationService{
-		forkchoiceStore:          forkchoiceStore,
-		committeeSubscribe:       committeeSubscribe,
-		ethClock:                 ethClock,
-		syncedDataManager:        syncedDataManager,
-		beaconCfg:                beaconCfg,
-		netCfg:                   netCfg,
-		validatorAttestationSeen: lru.NewWithTTL[uint64, uint64]("validator_attestation_seen", validatorAttestationCacheSize, epochDuration),
+		forkchoiceStore:     forkchoiceStore,
+		committeeSubscribe:  committeeSubscribe,
+		ethClock:            ethClock,
+		syncedDataManager:   syncedDataManager,
+		beaconCfg:           beaconCfg,
+		netCfg:              netCfg,
+		emitters:            emitters,
+		opPool:              opPool,
+		slasher:             slasher,
+		seenAttestations:    seenAttestations,
+		pendingAttestations: make(map[libcommon.Hash][]*attestationJob),
 	}
 	go a.loop(ctx)
 	return a
 }
 
 func (s *attestationService) ProcessMessage(ctx context.Context, subnet *uint64, att *solid.Attestation) error {
+	return s.processAttestation(ctx, subnet, att, false)
+}
+
+// processAttestation - `verified` attestations are released from the pending queue: their signature was checked and
+// they were reported to the slasher before they were queued, so both are skipped.
+func (s *attestationService) processAttestation(ctx context.Context, subnet *uint64, att *solid.Attestation, verified bool) error {
 	var (
 		root           = att.AttestantionData().BeaconBlockRoot()
 		slot           = att.AttestantionData().Slot()
@@ -137,38 +162,46 @@ func (s *attestationService) ProcessMessage(ctx context.Context, subnet *uint64,
 	}
 	// mark the validator as seen
 	vIndex := beaconCommittee[onBitIndex]
-	epochLastTime, ok := s.validatorAttestationSeen.Get(vIndex)
-	if ok && epochLastTime == targetEpoch {
+	if s.seenAttestations.Seen(targetEpoch, vIndex, slot, committeeIndex) {
 		return fmt.Errorf("validator already seen in target epoch %w", ErrIgnore)
 	}
-	s.validatorAttestationSeen.Add(vIndex, targetEpoch)
 
-	// [REJECT] The signature of attestation is valid.
-	signature := att.Signature()
-	pubKey, err := headState.ValidatorPublicKey(int(beaconCommittee[onBitIndex]))
-	if err != nil {
-		return fmt.Errorf("unable to get public key: %v", err)
-	}
-	domain, err := headState.GetDomain(s.beaconCfg.DomainBeaconAttester, targetEpoch)
-	if err != nil {
-		return fmt.Errorf("unable to get the domain: %v", err)
-	}
-	signingRoot, err := computeSigningRoot(att.AttestantionData(), domain)
-	if err != nil {
-		return fmt.Errorf("unable to get signing root: %v", err)
-	}
-	if valid, err := blsVerify(signature[:], signingRoot[:], pubKey[:]); err != nil {
-		return err
-	} else if !valid {
-		return fmt.Errorf("invalid signature")
+	if !verified {
+		// [REJECT] The signature of attestation is valid.
+		signature := att.Signature()
+		pubKey, err := headState.ValidatorPublicKey(int(beaconCommittee[onBitIndex]))
+		if err != nil {
+			return fmt.Errorf("unable to get public key: %v", err)
+		}
+		domain, err := headState.GetDomain(s.beaconCfg.DomainBeaconAttester, targetEpoch)
+		if err != nil {
+			return fmt.Errorf("unable to get the domain: %v", err)
+		}
+		signingRoot, err := computeSigningRoot(att.AttestantionData(), domain)
+		if err != nil {
+			return fmt.Errorf("unable to get signing root: %v", err)
+		}
+		if valid, err := blsVerify(signature[:], signingRoot[:], pubKey[:]); err != nil {
+			return err
+		} else if !valid {
+			return fmt.Errorf("invalid signature")
+		}
+		// the slasher looks at every signed vote, including the ones ignored below
+		s.slasher.OnAttestation(&cltypes.IndexedAttestation{
+			AttestingIndices: solid.NewRawUint64List(2048, []uint64{vIndex}),
+			Data:             att.AttestantionData(),
+			Signature:        signature,
+		})
 	}
 
 	// [IGNORE] The block being voted for (attestation.data.beacon_block_root) has been seen (via both gossip and non-gossip sources)
 	// (a client MAY queue attestations for processing once block is retrieved).
 	if _, ok := s.forkchoiceStore.GetHeader(root); !ok {
-		s.scheduleAttestationForLaterProcessing(att)
+		s.scheduleAttestationForLaterProcessing(att, *subnet)
 		return ErrIgnore
 	}
+	// mark the validator as seen only once the attestation is no longer pending, so that queued attestations can be re-processed.
+	s.seenAttestations.Add(targetEpoch, vIndex, slot, committeeIndex)
 
 	// [REJECT] The attestation's target block is an ancestor of the block named in the LMD vote -- i.e.
 	// get_checkpoint_block(store, attestation.data.beacon_block_root, attestation.data.target.epoch) == attestation.data.target.root
@@ -183,31 +216,88 @@ func (s *attestationService) ProcessMessage(ctx context.Context, subnet *uint64,
 		return fmt.Errorf("invalid finalized checkpoint %w", ErrIgnore)
 	}
 
+	if err := s.opPool.BlockAttestationsPool.Add(att); err != nil {
+		log.Trace("Could not add attestation to block attestations pool", "err", err)
+	}
 	err = s.committeeSubscribe.CheckAggregateAttestation(att)
 	if errors.Is(err, aggregation.ErrIsSuperset) {
 		return ErrIgnore
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	s.emitters.Publish("attestation", att)
+	return nil
 }
 
 type attestationJob struct {
-	att          *solid.Attestation
-	creationTime time.Time
-	subnet       uint64
+	att      *solid.Attestation
+	hash     libcommon.Hash
+	subnet   uint64
+	verified bool // signature checked and reported to the slasher before queueing
 }
 
-func (a *attestationService) scheduleAttestationForLaterProcessing(att *solid.Attestation) {
-	key, err := att.HashSSZ()
+// scheduleAttestationForLaterProcessing queues an attestation until its beacon block root is imported.
+// The queue is bounded by maxPendingAttestations, attestations received once it is full are dropped,
+// as well as attestations which are already queued (e.g. re-gossiped on another subnet).
+func (s *attestationService) scheduleAttestationForLaterProcessing(att *solid.Attestation, subnet uint64) {
+	hash, err := att.HashSSZ()
 	if err != nil {
+		log.Trace("Could not hash pending attestation", "err", err)
+		return
+	}
+	s.pendingAttestationsMutex.Lock()
+	defer s.pendingAttestationsMutex.Unlock()
+	if s.pendingAttestationsCount >= maxPendingAttestations {
+		log.Trace("Pending attestations queue is full, dropping attestation", "slot", att.AttestantionData().Slot())
 		return
 	}
-	a.attestationsToBeLaterProcessed.Store(key, &attestationJob{
-		att:          att,
-		creationTime: time.Now(),
+	root := att.AttestantionData().BeaconBlockRoot()
+	for _, job := range s.pendingAttestations[root] {
+		if job.hash == hash {
+			return
+		}
+	}
+	s.pendingAttestations[root] = append(s.pendingAttestations[root], &attestationJob{
+		att:      att,
+		hash:     hash,
+		subnet:   subnet,
+		verified: true,
 	})
+	s.pendingAttestationsCount++
+}
+
+// popReadyAttestations removes and returns the pending attestations whose block has been imported
+// and drops the ones which are older than pendingAttestationExpirySlots.
+func (s *attestationService) popReadyAttestations(currentSlot uint64) []*attestationJob {
+	s.pendingAttestationsMutex.Lock()
+	defer s.pendingAttestationsMutex.Unlock()
+	var ready []*attestationJob
+	for root, jobs := range s.pendingAttestations {
+		if _, ok := s.forkchoiceStore.GetHeader(root); ok {
+			ready = append(ready, jobs...)
+			s.pendingAttestationsCount -= len(jobs)
+			delete(s.pendingAttestations, root)
+			continue
+		}
+		// arrival order is not slot order: each attestation expires by its own slot.
+		kept := jobs[:0]
+		for _, job := range jobs {
+			if job.att.AttestantionData().Slot()+pendingAttestationExpirySlots >= currentSlot {
+				kept = append(kept, job)
+			}
+		}
+		s.pendingAttestationsCount -= len(jobs) - len(kept)
+		if len(kept) == 0 {
+			delete(s.pendingAttestations, root)
+			continue
+		}
+		s.pendingAttestations[root] = kept
+	}
+	return ready
 }
 
-func (a *attestationService) loop(ctx context.Context) {
+func (s *attestationService) loop(ctx context.Context) {
 	ticker := time.NewTicker(singleAttestationIntervalTick)
 	defer ticker.Stop()
 
@@ -217,20 +307,16 @@ func (a *attestationService) loop(ctx context.Context) {
 			return
 		case <-ticker.C:
 		}
-		a.attestationsToBeLaterProcessed.Range(func(key, value any) bool {
-			k := key.([32]byte)
-			v := value.(*attestationJob)
-			if time.Now().After(v.creationTime.Add(singleAttestationJobExpiry)) {
-				a.attestationsToBeLaterProcessed.Delete(k)
-				return true
-			}
-
-			root := v.att.AttestantionData().BeaconBlockRoot()
-			if _, ok := a.forkchoiceStore.GetHeader(root); !ok {
-				return true
+		s.pendingAttestationsMutex.Lock()
+		empty := s.pendingAttestationsCount == 0
+		s.pendingAttestationsMutex.Unlock()
+		if empty {
+			continue
+		}
+		for _, job := range s.popReadyAttestations(s.ethClock.GetCurrentSlot()) {
+			if err := s.processAttestation(ctx, &job.subnet, job.att, job.verified); err != nil && !errors.Is(err, ErrIgnore) {
+				log.Trace("Failed to process pending attestation", "slot", job.att.AttestantionData().Slot(), "err", err)
 			}
-			a.ProcessMessage(ctx, &v.subnet, v.att)
-			return true
-		})
+		}
 	}
 }
@@ -12,11 +12,14 @@ import (
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
 	"github.com/ledgerwatch/erigon/cl/abstract"
 	mockState "github.com/ledgerwatch/erigon/cl/abstract/mock_services"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	mockSync "github.com/ledgerwatch/erigon/cl/beacon/synced_data/mock_services"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	mockCommittee "github.com/ledgerwatch/erigon/cl/validator/committee_subscription/mock_services"
 )
@@ -61,7 +64,7 @@ func (t *attestationTestSuite) SetupTest() {
 	blsVerify = func(sig []byte, msg []byte, pubKeys []byte) (bool, error) { return true, nil }
 	ctx, cn := context.WithCancel(context.Background())
 	cn()
-	t.attService = NewAttestationService(ctx, t.mockForkChoice, t.committeeSubscibe, t.ethClock, t.syncedData, t.beaconConfig, netConfig)
+	t.attService = NewAttestationService(ctx, t.mockForkChoice, t.committeeSubscibe, t.ethClock, t.syncedData, t.beaconConfig, netConfig, beaconevents.NewEmitters(), pool.NewOperationsPool(t.beaconConfig), slasher.NewDummySlasher(), NewSeenAttestationCache(0, 0))
 }
 
 func (t *attestationTestSuite) TearDownTest() {
@@ -356,6 +359,101 @@ func (t *attestationTestSuite) TestAttestationProcessMessage() {
 	}
 }
 
+func (t *attestationTestSuite) TestPendingAttestations() {
+	s := t.attService.(*attestationService)
+	root := att.AttestantionData().BeaconBlockRoot()
+
+	other := att.Copy()
+	other.SetSignature([96]byte{1})
+	s.scheduleAttestationForLaterProcessing(att, 1)
+	s.scheduleAttestationForLaterProcessing(other, 2)
+	// duplicates are queued once
+	s.scheduleAttestationForLaterProcessing(att, 3)
+	s.scheduleAttestationForLaterProcessing(other.Copy(), 2)
+	t.Equal(2, s.pendingAttestationsCount)
+
+	// block not imported yet and attestations not expired
+	t.Empty(s.popReadyAttestations(mockSlot + pendingAttestationExpirySlots))
+	t.Equal(2, s.pendingAttestationsCount)
+
+	// block imported
+	t.mockForkChoice.Headers = map[common.Hash]*cltypes.BeaconBlockHeader{root: {}}
+	ready := s.popReadyAttestations(mockSlot)
+	t.Len(ready, 2)
+	t.Equal(uint64(1), ready[0].subnet)
+	t.Equal(uint64(2), ready[1].subnet)
+	t.True(ready[0].verified)
+	t.Equal(0, s.pendingAttestationsCount)
+	t.Empty(s.pendingAttestations)
+
+	// expired attestations are dropped
+	t.mockForkChoice.Headers = nil
+	s.scheduleAttestationForLaterProcessing(att, 1)
+	t.Empty(s.popReadyAttestations(mockSlot + pendingAttestationExpirySlots + 1))
+	t.Equal(0, s.pendingAttestationsCount)
+	t.Empty(s.pendingAttestations)
+
+	// arrival order is not slot order: each attestation expires by its own slot
+	later := solid.NewAttestionFromParameters(
+		[]byte{0b00000001, 1},
+		solid.NewAttestionDataFromParameters(mockSlot+pendingAttestationExpirySlots, 2, root, attData.Source(), attData.Target()),
+		[96]byte{2},
+	)
+	s.scheduleAttestationForLaterProcessing(later, 1)
+	s.scheduleAttestationForLaterProcessing(att, 1)
+	t.Empty(s.popReadyAttestations(mockSlot + pendingAttestationExpirySlots + 1))
+	t.Equal(1, s.pendingAttestationsCount)
+	t.Len(s.pendingAttestations[root], 1)
+	t.Equal(later, s.pendingAttestations[root][0].att)
+	s.pendingAttestations, s.pendingAttestationsCount = map[common.Hash][]*attestationJob{}, 0
+
+	// queue is bounded
+	s.pendingAttestationsCount = maxPendingAttestations
+	s.scheduleAttestationForLaterProcessing(att, 1)
+	t.Empty(s.pendingAttestations)
+}
+
+// countingSlasher - counts the attestations it's given
+type countingSlasher struct {
+	attestations int
+}
+
+func (c *countingSlasher) OnAttestation(*cltypes.IndexedAttestation)      { c.attestations++ }
+func (c *countingSlasher) OnBlockHeader(*cltypes.SignedBeaconBlockHeader) {}
+
+func (t *attestationTestSuite) TestPendingAttestationReplay() {
+	s := t.attService.(*attestationService)
+	slasher := &countingSlasher{}
+	s.slasher = slasher
+
+	// no public key or domain lookups: signature isn't verified again
+	t.syncedData.EXPECT().HeadStateReader().Return(t.beaconStateReader).Times(1)
+	computeCommitteeCountPerSlot = func(_ abstract.BeaconStateReader, _, _ uint64) uint64 {
+		return 8
+	}
+	computeSubnetForAttestation = func(_, _, _, _, _ uint64) uint64 {
+		return 1
+	}
+	t.ethClock.EXPECT().GetCurrentSlot().Return(mockSlot).Times(1)
+	blsVerify = func(sig []byte, msg []byte, pubKeys []byte) (bool, error) {
+		return false, nil
+	}
+	t.mockForkChoice.Headers = map[common.Hash]*cltypes.BeaconBlockHeader{
+		att.AttestantionData().BeaconBlockRoot(): {},
+	}
+	mockFinalizedCheckPoint := solid.NewCheckpointFromParameters([32]byte{1, 0}, 1)
+	t.mockForkChoice.Ancestors = map[uint64]common.Hash{
+		mockEpoch * mockSlotsPerEpoch:                       att.AttestantionData().Target().BlockRoot(),
+		mockFinalizedCheckPoint.Epoch() * mockSlotsPerEpoch: mockFinalizedCheckPoint.BlockRoot(),
+	}
+	t.mockForkChoice.FinalizedCheckpointVal = mockFinalizedCheckPoint
+	t.committeeSubscibe.EXPECT().CheckAggregateAttestation(att).Return(nil).Times(1)
+
+	t.Require().NoError(s.processAttestation(context.Background(), uint64Ptr(1), att, true))
+	t.Zero(slasher.attestations)
+	t.True(t.gomockCtrl.Satisfied())
+}
+
 func TestAttestation(t *testing.T) {
 	suite.Run(t, &attestationTestSuite{})
 }
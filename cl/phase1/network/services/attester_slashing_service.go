@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/cl/abstract"
+	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+)
+
+type attesterSlashingService struct {
+	operationsPool    pool.OperationsPool
+	syncedDataManager synced_data.SyncedData
+	forkchoiceStore   forkchoice.ForkChoiceStorage
+	beaconCfg         *clparams.BeaconChainConfig
+	ethClock          eth_clock.EthereumClock
+	cache             *lru.Cache[uint64, struct{}] // validator indices already included in a valid attester slashing
+}
+
+func NewAttesterSlashingService(
+	operationsPool pool.OperationsPool,
+	syncedDataManager synced_data.SyncedData,
+	forkchoiceStore forkchoice.ForkChoiceStorage,
+	beaconCfg *clparams.BeaconChainConfig,
+	ethClock eth_clock.EthereumClock,
+) AttesterSlashingService {
+	cache, err := lru.New[uint64, struct{}]("attester_slashing", attesterSlashingCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &attesterSlashingService{
+		operationsPool:    operationsPool,
+		syncedDataManager: syncedDataManager,
+		forkchoiceStore:   forkchoiceStore,
+		beaconCfg:         beaconCfg,
+		ethClock:          ethClock,
+		cache:             cache,
+	}
+}
+
+func (s *attesterSlashingService) ProcessMessage(ctx context.Context, subnet *uint64, msg *cltypes.AttesterSlashing) error {
+	// https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/p2p-interface.md#attester_slashing
+	if s.operationsPool.AttesterSlashingsPool.Has(pool.ComputeKeyForAttesterSlashing(msg)) {
+		return ErrIgnore
+	}
+	attestation1 := msg.Attestation_1
+	attestation2 := msg.Attestation_2
+
+	// [REJECT] All of the conditions within process_attester_slashing pass validation.
+	if !cltypes.IsSlashableAttestationData(attestation1.Data, attestation2.Data) {
+		return fmt.Errorf("attestation data is not slashable")
+	}
+	for _, att := range []*cltypes.IndexedAttestation{attestation1, attestation2} {
+		if att.AttestingIndices.Length() == 0 || !solid.IsUint64SortedSet(att.AttestingIndices) {
+			return fmt.Errorf("attesting indices are not sorted or are null")
+		}
+	}
+
+	// [IGNORE] At least one index in the intersection of the attesting indices of each attestation has not yet been seen in any prior attester_slashing.
+	intersection := solid.IntersectionOfSortedSets(attestation1.AttestingIndices, attestation2.AttestingIndices)
+	allSeen := true
+	for _, index := range intersection {
+		if _, ok := s.cache.Get(index); !ok {
+			allSeen = false
+			break
+		}
+	}
+	if allSeen {
+		return ErrIgnore
+	}
+
+	state := s.syncedDataManager.HeadStateReader()
+	if state == nil {
+		return ErrIgnore
+	}
+
+	// Verify that at least one of the validators in the intersection is slashable
+	currentEpoch := s.ethClock.GetCurrentEpoch()
+	anySlashable := false
+	for _, index := range intersection {
+		v, err := state.ValidatorForValidatorIndex(int(index))
+		if err != nil {
+			return fmt.Errorf("unable to retrieve state: %v", err)
+		}
+		if v.IsSlashable(currentEpoch) {
+			anySlashable = true
+			break
+		}
+	}
+	if !anySlashable {
+		return fmt.Errorf("no slashable validators in attester slashing")
+	}
+
+	// Verify signatures of both indexed attestations
+	for _, att := range []*cltypes.IndexedAttestation{attestation1, attestation2} {
+		if err := s.verifyIndexedAttestationSignature(state, att); err != nil {
+			return err
+		}
+	}
+
+	// signatures were verified above, so fork choice may skip them.
+	if err := s.forkchoiceStore.OnAttesterSlashing(msg, true); err != nil {
+		return err
+	}
+	for _, index := range intersection {
+		s.cache.Add(index, struct{}{})
+	}
+	return nil
+}
+
+func (s *attesterSlashingService) verifyIndexedAttestationSignature(state abstract.BeaconStateReader, att *cltypes.IndexedAttestation) error {
+	pks := make([][]byte, 0, att.AttestingIndices.Length())
+	if err := solid.RangeErr[uint64](att.AttestingIndices, func(_ int, index uint64, _ int) error {
+		pk, err := state.ValidatorPublicKey(int(index))
+		if err != nil {
+			return err
+		}
+		pks = append(pks, pk[:])
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to retrieve public keys: %v", err)
+	}
+	domain, err := state.GetDomain(s.beaconCfg.DomainBeaconAttester, att.Data.Target().Epoch())
+	if err != nil {
+		return fmt.Errorf("unable to get domain: %v", err)
+	}
+	signingRoot, err := computeSigningRoot(att.Data, domain)
+	if err != nil {
+		return fmt.Errorf("unable to compute signing root: %v", err)
+	}
+	aggregatePk, err := blsAggregatePublicKeys(pks)
+	if err != nil {
+		return fmt.Errorf("unable to aggregate public keys: %v", err)
+	}
+	valid, err := blsVerify(att.Signature[:], signingRoot[:], aggregatePk)
+	if err != nil {
+		return fmt.Errorf("unable to verify signature: %v", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid aggregate signature")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	mockState "github.com/ledgerwatch/erigon/cl/abstract/mock_services"
+	mockSync "github.com/ledgerwatch/erigon/cl/beacon/synced_data/mock_services"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/ledgerwatch/erigon/cl/pool"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type attesterSlashingTestSuite struct {
+	suite.Suite
+	gomockCtrl              *gomock.Controller
+	operationsPool          *pool.OperationsPool
+	forkchoiceMock          *mock_services.ForkChoiceStorageMock
+	syncedData              *mockSync.MockSyncedData
+	beaconCfg               *clparams.BeaconChainConfig
+	ethClock                *eth_clock.MockEthereumClock
+	attesterSlashingService *attesterSlashingService
+	mockFuncs               *mockFuncs
+}
+
+func (t *attesterSlashingTestSuite) SetupTest() {
+	t.gomockCtrl = gomock.NewController(t.T())
+	t.operationsPool = &pool.OperationsPool{
+		AttesterSlashingsPool: pool.NewOperationPool[common.Bytes96, *cltypes.AttesterSlashing](10, "attesterSlashingsPool"),
+	}
+	t.forkchoiceMock = mock_services.NewForkChoiceStorageMock(t.T())
+	t.forkchoiceMock.Pool = *t.operationsPool
+	t.syncedData = mockSync.NewMockSyncedData(t.gomockCtrl)
+	t.ethClock = eth_clock.NewMockEthereumClock(t.gomockCtrl)
+	t.beaconCfg = &clparams.BeaconChainConfig{
+		SlotsPerEpoch: 2,
+	}
+	t.attesterSlashingService = NewAttesterSlashingService(*t.operationsPool, t.syncedData, t.forkchoiceMock, t.beaconCfg, t.ethClock).(*attesterSlashingService)
+	// mock global functions
+	t.mockFuncs = &mockFuncs{ctrl: t.gomockCtrl}
+	computeSigningRoot = t.mockFuncs.ComputeSigningRoot
+	blsVerify = t.mockFuncs.BlsVerify
+	blsAggregatePublicKeys = func(pubKeys [][]byte) ([]byte, error) { return make([]byte, 48), nil }
+}
+
+func (t *attesterSlashingTestSuite) TearDownTest() {
+	t.gomockCtrl.Finish()
+}
+
+func (t *attesterSlashingTestSuite) TestProcessMessage() {
+	newIndexedAttestation := func(indices []uint64, blockRoot common.Hash) *cltypes.IndexedAttestation {
+		checkpoint := solid.NewCheckpointFromParameters(common.Hash{}, 1)
+		return &cltypes.IndexedAttestation{
+			AttestingIndices: solid.NewRawUint64List(2048, indices),
+			Data:             solid.NewAttestionDataFromParameters(2, 0, blockRoot, checkpoint, checkpoint),
+			Signature:        common.Bytes96{1, 2, 3},
+		}
+	}
+	// double vote of validators 2 and 3
+	mockMsg := &cltypes.AttesterSlashing{
+		Attestation_1: newIndexedAttestation([]uint64{1, 2, 3}, common.Hash{1}),
+		Attestation_2: newIndexedAttestation([]uint64{2, 3, 4}, common.Hash{2}),
+	}
+	slashableValidator := solid.NewValidatorFromParameters([48]byte{}, [32]byte{}, 0, false, 0, 0, 2, 2)
+	tests := []struct {
+		name    string
+		mock    func()
+		msg     *cltypes.AttesterSlashing
+		wantErr bool
+		err     error
+	}{
+		{
+			name: "ignore attester slashing in pool",
+			mock: func() {
+				t.operationsPool.AttesterSlashingsPool.Insert(pool.ComputeKeyForAttesterSlashing(mockMsg), mockMsg)
+			},
+			msg:     mockMsg,
+			wantErr: true,
+			err:     ErrIgnore,
+		},
+		{
+			name: "attestation data is not slashable",
+			mock: func() {},
+			msg: &cltypes.AttesterSlashing{
+				Attestation_1: newIndexedAttestation([]uint64{1, 2, 3}, common.Hash{1}),
+				Attestation_2: newIndexedAttestation([]uint64{2, 3, 4}, common.Hash{1}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsorted attesting indices",
+			mock: func() {},
+			msg: &cltypes.AttesterSlashing{
+				Attestation_1: newIndexedAttestation([]uint64{3, 2, 1}, common.Hash{1}),
+				Attestation_2: newIndexedAttestation([]uint64{2, 3, 4}, common.Hash{2}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "all validators already slashed via gossip",
+			mock: func() {
+				t.attesterSlashingService.cache.Add(2, struct{}{})
+				t.attesterSlashingService.cache.Add(3, struct{}{})
+			},
+			msg:     mockMsg,
+			wantErr: true,
+			err:     ErrIgnore,
+		},
+		{
+			name: "empty head state",
+			mock: func() {
+				t.syncedData.EXPECT().HeadStateReader().Return(nil).Times(1)
+			},
+			msg:     mockMsg,
+			wantErr: true,
+			err:     ErrIgnore,
+		},
+		{
+			name: "validator not found",
+			mock: func() {
+				mockState := mockState.NewMockBeaconStateReader(t.gomockCtrl)
+				mockState.EXPECT().ValidatorForValidatorIndex(2).Return(nil, errors.New("not found")).Times(1)
+				t.syncedData.EXPECT().HeadStateReader().Return(mockState).Times(1)
+				t.ethClock.EXPECT().GetCurrentEpoch().Return(uint64(1)).Times(1)
+			},
+			msg:     mockMsg,
+			wantErr: true,
+		},
+		{
+			name: "no slashable validators",
+			mock: func() {
+				mockState := mockState.NewMockBeaconStateReader(t.gomockCtrl)
+				mockValidator := solid.NewValidatorFromParameters([48]byte{}, [32]byte{}, 0, true, 0, 0, 2, 2)
+				mockState.EXPECT().ValidatorForValidatorIndex(gomock.Any()).Return(mockValidator, nil).Times(2)
+				t.syncedData.EXPECT().HeadStateReader().Return(mockState).Times(1)
+				t.ethClock.EXPECT().GetCurrentEpoch().Return(uint64(1)).Times(1)
+			},
+			msg:     mockMsg,
+			wantErr: true,
+		},
+		{
+			name: "invalid signature",
+			mock: func() {
+				mockState := mockState.NewMockBeaconStateReader(t.gomockCtrl)
+				mockState.EXPECT().ValidatorForValidatorIndex(2).Return(slashableValidator, nil).Times(1)
+				mockState.EXPECT().ValidatorPublicKey(gomock.Any()).Return(common.Bytes48{}, nil).Times(3)
+				mockState.EXPECT().GetDomain(t.beaconCfg.DomainBeaconAttester, uint64(1)).Return([]byte{}, nil).Times(1)
+				t.syncedData.EXPECT().HeadStateReader().Return(mockState).Times(1)
+				t.ethClock.EXPECT().GetCurrentEpoch().Return(uint64(1)).Times(1)
+				t.mockFuncs.ctrl.RecordCall(t.mockFuncs, "ComputeSigningRoot", mockMsg.Attestation_1.Data, []byte{}).Return([32]byte{}, nil).Times(1)
+				t.mockFuncs.ctrl.RecordCall(t.mockFuncs, "BlsVerify", gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+			},
+			msg:     mockMsg,
+			wantErr: true,
+		},
+		{
+			name: "pass",
+			mock: func() {
+				mockState := mockState.NewMockBeaconStateReader(t.gomockCtrl)
+				mockState.EXPECT().ValidatorForValidatorIndex(2).Return(slashableValidator, nil).Times(1)
+				mockState.EXPECT().ValidatorPublicKey(gomock.Any()).Return(common.Bytes48{}, nil).Times(6)
+				mockState.EXPECT().GetDomain(t.beaconCfg.DomainBeaconAttester, uint64(1)).Return([]byte{}, nil).Times(2)
+				t.syncedData.EXPECT().HeadStateReader().Return(mockState).Times(1)
+				t.ethClock.EXPECT().GetCurrentEpoch().Return(uint64(1)).Times(1)
+				t.mockFuncs.ctrl.RecordCall(t.mockFuncs, "ComputeSigningRoot", mockMsg.Attestation_1.Data, []byte{}).Return([32]byte{}, nil).Times(1)
+				t.mockFuncs.ctrl.RecordCall(t.mockFuncs, "ComputeSigningRoot", mockMsg.Attestation_2.Data, []byte{}).Return([32]byte{}, nil).Times(1)
+				t.mockFuncs.ctrl.RecordCall(t.mockFuncs, "BlsVerify", gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+			},
+			msg:     mockMsg,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		log.Printf("Running test case: %s", tt.name)
+		t.SetupTest()
+		tt.mock()
+		err := t.attesterSlashingService.ProcessMessage(context.Background(), nil, tt.msg)
+		if tt.wantErr {
+			t.Assert().Error(err)
+			if tt.err != nil {
+				t.Assert().Equal(tt.err, err)
+			}
+		} else {
+			t.Assert().NoError(err)
+			t.Assert().True(t.operationsPool.AttesterSlashingsPool.Has(pool.ComputeKeyForAttesterSlashing(tt.msg)))
+		}
+		t.gomockCtrl.Satisfied()
+	}
+}
+
+func TestAttesterSlashing(t *testing.T) {
+	suite.Run(t, new(attesterSlashingTestSuite))
+}
@@ -3,12 +3,14 @@ package services
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/Giulio2002/bls"
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
 	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
@@ -24,6 +26,7 @@ type blobSidecarService struct {
 	forkchoiceStore   forkchoice.ForkChoiceStorage
 	beaconCfg         *clparams.BeaconChainConfig
 	syncedDataManager *synced_data.SyncedDataManager
+	emitters          *beaconevents.Emitters
 
 	blobSidecarsScheduledForLaterExecution sync.Map
 	ethClock                               eth_clock.EthereumClock
@@ -42,6 +45,7 @@ func NewBlobSidecarService(
 	forkchoiceStore forkchoice.ForkChoiceStorage,
 	syncedDataManager *synced_data.SyncedDataManager,
 	ethClock eth_clock.EthereumClock,
+	emitters *beaconevents.Emitters,
 	test bool,
 ) BlobSidecarsService {
 	b := &blobSidecarService{
@@ -50,6 +54,7 @@ func NewBlobSidecarService(
 		syncedDataManager: syncedDataManager,
 		test:              test,
 		ethClock:          ethClock,
+		emitters:          emitters,
 	}
 	go b.loop(ctx)
 	return b
@@ -121,7 +126,25 @@ func (b *blobSidecarService) verifyAndStoreBlobSidecar(headState *state.CachingB
 		}
 	}
 	// operation is not thread safe from here.
-	return b.forkchoiceStore.AddPreverifiedBlobSidecar(msg)
+	if err := b.forkchoiceStore.AddPreverifiedBlobSidecar(msg); err != nil {
+		return err
+	}
+	blockRoot, err := msg.SignedBlockHeader.Header.HashSSZ()
+	if err != nil {
+		return err
+	}
+	versionedHash, err := utils.KzgCommitmentToVersionedHash(msg.KzgCommitment)
+	if err != nil {
+		return err
+	}
+	b.emitters.Publish("blob_sidecar", map[string]any{
+		"block_root":     blockRoot,
+		"index":          strconv.FormatUint(msg.Index, 10),
+		"slot":           strconv.FormatUint(msg.SignedBlockHeader.Header.Slot, 10),
+		"kzg_commitment": msg.KzgCommitment,
+		"versioned_hash": versionedHash,
+	})
+	return nil
 }
 
 func (b *blobSidecarService) verifySidecarsSignature(headState *state.CachingBeaconState, header *cltypes.SignedBeaconBlockHeader) error {
@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/ledgerwatch/log/v3"
 
@@ -44,7 +45,8 @@ type blockService struct {
 	emitter                          *beaconevents.Emitters
 	blocksScheduledForLaterExecution sync.Map
 	// store the block in db
-	db kv.RwDB
+	db      kv.RwDB
+	slasher slasher.Slasher
 }
 
 // NewBlockService creates a new block service
@@ -56,6 +58,7 @@ func NewBlockService(
 	ethClock eth_clock.EthereumClock,
 	beaconCfg *clparams.BeaconChainConfig,
 	emitter *beaconevents.Emitters,
+	slasher slasher.Slasher,
 ) Service[*cltypes.SignedBeaconBlock] {
 	seenBlocksCache, err := lru.New[proposerIndexAndSlot, struct{}]("seenblocks", seenBlockCacheSize)
 	if err != nil {
@@ -69,6 +72,7 @@ func NewBlockService(
 		seenBlocksCache: seenBlocksCache,
 		emitter:         emitter,
 		db:              db,
+		slasher:         slasher,
 	}
 	go b.loop(ctx)
 	return b
@@ -176,6 +180,8 @@ func (b *blockService) processAndStoreBlock(ctx context.Context, block *cltypes.
 	if err := b.forkchoiceStore.OnBlock(ctx, block, true, true, true); err != nil {
 		return err
 	}
+	// forkchoice verified the proposer signature
+	b.slasher.OnBlockHeader(block.SignedBeaconBlockHeader())
 	go b.importBlockOperations(block)
 	return b.db.Update(ctx, func(tx kv.RwTx) error {
 		return beacon_indicies.WriteHighestFinalized(tx, b.forkchoiceStore.FinalizedSlot())
@@ -8,7 +8,11 @@ import (
 const (
 	validatorAttestationCacheSize = 100_000
 	proposerSlashingCacheSize     = 100
-	seenBlockCacheSize            = 1000 // SeenBlockCacheSize is the size of the cache for seen blocks.
+	attesterSlashingCacheSize     = 1000
+	maxPendingAttestations        = 16_384 // maximum number of attestations waiting for their beacon block to be imported.
+	pendingAttestationExpirySlots = 2      // number of slots after which a pending attestation is dropped.
+	seenBlockCacheSize            = 1000   // SeenBlockCacheSize is the size of the cache for seen blocks.
+	seenDataColumnCacheSize       = 16_384 // seenDataColumnCacheSize is the size of the cache for seen data column sidecars.
 	blockJobsIntervalTick         = 50 * time.Millisecond
 	blobJobsIntervalTick          = 5 * time.Millisecond
 	singleAttestationIntervalTick = 10 * time.Millisecond
@@ -16,7 +20,6 @@ const (
 	blockJobExpiry                = 7 * time.Minute
 	blobJobExpiry                 = 7 * time.Minute
 	attestationJobExpiry          = 30 * time.Minute
-	singleAttestationJobExpiry    = 6 * time.Second
 )
 
 var (
@@ -26,4 +29,9 @@ var (
 	ErrCommitmentsInclusionProofFailed = errors.New("commitments inclusion proof failed")
 	ErrInvalidSidecarSlot              = errors.New("invalid sidecar slot")
 	ErrBlobIndexOutOfRange             = errors.New("blob index out of range")
+	ErrInvalidDataColumnSidecar        = errors.New("invalid data column sidecar")
+	ErrDataColumnSubnetMismatch        = errors.New("data column sidecar on wrong subnet")
+	ErrInvalidDataColumnProposer       = errors.New("data column sidecar from unexpected proposer")
+
+	errCellKzgProofsUnsupported = errors.New("cell kzg proofs verification is not supported")
 )
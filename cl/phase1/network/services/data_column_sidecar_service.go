@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Giulio2002/bls"
+	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/das"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/lru"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+	"github.com/ledgerwatch/log/v3"
+)
+
+type seenDataColumnKey struct {
+	slot          uint64
+	proposerIndex uint64
+	index         uint64
+}
+
+type dataColumnSidecarService struct {
+	forkchoiceStore   forkchoice.ForkChoiceStorage
+	beaconCfg         *clparams.BeaconChainConfig
+	syncedDataManager *synced_data.SyncedDataManager
+	ethClock          eth_clock.EthereumClock
+	storage           blob_storage.DataColumnStorage
+
+	seenSidecars *lru.Cache[seenDataColumnKey, struct{}]
+}
+
+// NewDataColumnSidecarService creates a new PeerDAS data column sidecar service
+func NewDataColumnSidecarService(
+	beaconCfg *clparams.BeaconChainConfig,
+	forkchoiceStore forkchoice.ForkChoiceStorage,
+	syncedDataManager *synced_data.SyncedDataManager,
+	ethClock eth_clock.EthereumClock,
+	storage blob_storage.DataColumnStorage,
+) DataColumnSidecarService {
+	seenSidecars, err := lru.New[seenDataColumnKey, struct{}]("seendatacolumns", seenDataColumnCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &dataColumnSidecarService{
+		beaconCfg:         beaconCfg,
+		forkchoiceStore:   forkchoiceStore,
+		syncedDataManager: syncedDataManager,
+		ethClock:          ethClock,
+		storage:           storage,
+		seenSidecars:      seenSidecars,
+	}
+}
+
+// ProcessMessage processes a data column sidecar message according to https://github.com/ethereum/consensus-specs/blob/dev/specs/_features/eip7594/p2p-interface.md#data_column_sidecar_subnet_id
+// Note: cell KZG proofs (verify_data_column_sidecar_kzg_proofs) can not be checked yet, as our KZG library does not support cells,
+// so sidecars are ignored before being stored (and therefore served).
+func (d *dataColumnSidecarService) ProcessMessage(ctx context.Context, subnetId *uint64, msg *cltypes.DataColumnSidecar) error {
+	// [REJECT] The sidecar is valid as verified by verify_data_column_sidecar(sidecar).
+	if !cltypes.VerifyDataColumnSidecar(msg, d.beaconCfg.NumberOfColumns) {
+		return ErrInvalidDataColumnSidecar
+	}
+	// [REJECT] The sidecar is for the correct subnet -- i.e. compute_subnet_for_data_column_sidecar(sidecar.index) == subnet_id.
+	if subnetId == nil || das.ComputeSubnetForDataColumnSidecar(d.beaconCfg, msg.Index) != *subnetId {
+		return ErrDataColumnSubnetMismatch
+	}
+
+	headState := d.syncedDataManager.HeadState()
+	if headState == nil {
+		return ErrIgnore
+	}
+	header := msg.SignedBlockHeader.Header
+	// [IGNORE] The sidecar is not from a future slot (with a MAXIMUM_GOSSIP_CLOCK_DISPARITY allowance).
+	if d.ethClock.GetCurrentSlot() < header.Slot && !d.ethClock.IsSlotCurrentSlotWithMaximumClockDisparity(header.Slot) {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar is from a slot greater than the latest finalized slot.
+	if header.Slot <= d.forkchoiceStore.FinalizedSlot() {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar is the first sidecar for the tuple (block_header.slot, block_header.proposer_index, sidecar.index) with valid header signature, sidecar inclusion proof, and kzg proof.
+	key := seenDataColumnKey{slot: header.Slot, proposerIndex: header.ProposerIndex, index: msg.Index}
+	if d.seenSidecars.Contains(key) {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar's block's parent has been seen.
+	parentHeader, has := d.forkchoiceStore.GetHeader(header.ParentRoot)
+	if !has {
+		return ErrIgnore
+	}
+	// [REJECT] The sidecar is from a higher slot than the sidecar's block's parent.
+	if header.Slot <= parentHeader.Slot {
+		return ErrInvalidSidecarSlot
+	}
+	// [REJECT] The sidecar is proposed by the expected proposer_index for the block's slot in the context of the current shuffling (defined by block_header.parent_root/block_header.slot).
+	// The shuffling is only known for sidecars whose parent is our head and whose slot is in the epoch of our head state, the rest are ignored.
+	headRoot, _, err := d.forkchoiceStore.GetHead()
+	if err != nil {
+		return err
+	}
+	if header.ParentRoot != headRoot || header.Slot/d.beaconCfg.SlotsPerEpoch != headState.Slot()/d.beaconCfg.SlotsPerEpoch {
+		return ErrIgnore
+	}
+	proposerIndex, err := headState.GetBeaconProposerIndexForSlot(header.Slot)
+	if err != nil {
+		return err
+	}
+	if proposerIndex != header.ProposerIndex {
+		return ErrInvalidDataColumnProposer
+	}
+	// [REJECT] The sidecar's kzg_commitments field inclusion proof is valid as verified by verify_data_column_sidecar_inclusion_proof(sidecar).
+	if !cltypes.VerifyDataColumnSidecarInclusionProof(msg) {
+		return ErrCommitmentsInclusionProofFailed
+	}
+	// [REJECT] The proposer signature of sidecar.signed_block_header, is valid with respect to the block_header.proposer_index pubkey.
+	if err := d.verifySidecarSignature(headState, parentHeader.Slot, msg.SignedBlockHeader); err != nil {
+		return err
+	}
+	// [REJECT] The sidecar's column data is valid as verified by verify_data_column_sidecar_kzg_proofs(sidecar).
+	// Until cell proofs can be verified every column is ignored rather than rejected: the peer is not at fault.
+	if err := verifyDataColumnSidecarKzgProofs(msg); err != nil {
+		log.Trace("Data column sidecar ignored", "slot", header.Slot, "index", msg.Index, "err", err)
+		return ErrIgnore
+	}
+
+	blockRoot, err := header.HashSSZ()
+	if err != nil {
+		return err
+	}
+	if err := d.storage.WriteDataColumnSidecar(ctx, blockRoot, msg); err != nil {
+		return err
+	}
+	d.seenSidecars.Add(key, struct{}{})
+	return nil
+}
+
+func (d *dataColumnSidecarService) verifySidecarSignature(headState *state.CachingBeaconState, parentSlot uint64, header *cltypes.SignedBeaconBlockHeader) error {
+	currentVersion := d.beaconCfg.GetCurrentStateVersion(parentSlot / d.beaconCfg.SlotsPerEpoch)
+	forkVersion := d.beaconCfg.GetForkVersionByVersion(currentVersion)
+	domain, err := fork.ComputeDomain(d.beaconCfg.DomainBeaconProposer[:], utils.Uint32ToBytes4(forkVersion), headState.GenesisValidatorsRoot())
+	if err != nil {
+		return err
+	}
+	sigRoot, err := fork.ComputeSigningRoot(header.Header, domain)
+	if err != nil {
+		return err
+	}
+	pk, err := headState.ValidatorPublicKey(int(header.Header.ProposerIndex))
+	if err != nil {
+		return err
+	}
+	ok, err := bls.Verify(header.Signature[:], sigRoot[:], pk[:])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("data column sidecar signature validation: signature not valid")
+	}
+	return nil
+}
+
+// verifyDataColumnSidecarKzgProofs - verify_data_column_sidecar_kzg_proofs, the batch verification of the cell proofs of the column.
+func verifyDataColumnSidecarKzgProofs(sidecar *cltypes.DataColumnSidecar) error {
+	return errCellKzgProofsUnsupported
+}
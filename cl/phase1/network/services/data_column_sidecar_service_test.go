@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/das"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
+)
+
+// setupDataColumnSidecarService - the storage is nil: sidecars reaching it make the test panic
+func setupDataColumnSidecarService(t *testing.T, ctrl *gomock.Controller) (DataColumnSidecarService, *state.CachingBeaconState, *cltypes.DataColumnSidecar, *mock_services.ForkChoiceStorageMock) {
+	cfg := &clparams.MainnetBeaconConfig
+	syncedDataManager := synced_data.NewSyncedDataManager(true, cfg)
+	ethClock := eth_clock.NewMockEthereumClock(ctrl)
+	forkchoiceMock := mock_services.NewForkChoiceStorageMock(t)
+
+	stateObj, block, _ := getObjectsForBlobSidecarServiceTests(t)
+	header := block.SignedBeaconBlockHeader()
+	stateObj.SetSlot(header.Header.Slot)
+	syncedDataManager.OnHeadState(stateObj)
+
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = 5
+	sidecar.SignedBlockHeader = header
+	sidecar.Column.Append(&cltypes.Cell{1})
+	sidecar.KzgCommitments.Append(block.Block.Body.BlobKzgCommitments.Get(0))
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{1})
+
+	parentHeader := header.Header.Copy()
+	parentHeader.Slot--
+	forkchoiceMock.Headers[header.Header.ParentRoot] = parentHeader
+	forkchoiceMock.HeadVal = header.Header.ParentRoot
+
+	ethClock.EXPECT().GetCurrentSlot().Return(header.Header.Slot).AnyTimes()
+	ethClock.EXPECT().IsSlotCurrentSlotWithMaximumClockDisparity(gomock.Any()).Return(true).AnyTimes()
+	return NewDataColumnSidecarService(cfg, forkchoiceMock, syncedDataManager, ethClock, nil), stateObj, sidecar, forkchoiceMock
+}
+
+func TestDataColumnServiceUnexpectedProposer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataColumnService, stateObj, sidecar, _ := setupDataColumnSidecarService(t, ctrl)
+	header := sidecar.SignedBlockHeader.Header
+	proposerIndex, err := stateObj.GetBeaconProposerIndexForSlot(header.Slot)
+	require.NoError(t, err)
+	header.ProposerIndex = proposerIndex + 1
+	sn := das.ComputeSubnetForDataColumnSidecar(&clparams.MainnetBeaconConfig, sidecar.Index)
+
+	require.ErrorIs(t, dataColumnService.ProcessMessage(context.Background(), &sn, sidecar), ErrInvalidDataColumnProposer)
+}
+
+func TestDataColumnServiceParentNotHead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataColumnService, _, sidecar, fcu := setupDataColumnSidecarService(t, ctrl)
+	// the shuffling of another branch is unknown, the proposer can't be checked
+	fcu.HeadVal = libcommon.Hash{1}
+	sidecar.SignedBlockHeader.Header.ProposerIndex++
+	sn := das.ComputeSubnetForDataColumnSidecar(&clparams.MainnetBeaconConfig, sidecar.Index)
+
+	require.ErrorIs(t, dataColumnService.ProcessMessage(context.Background(), &sn, sidecar), ErrIgnore)
+}
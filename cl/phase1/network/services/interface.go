@@ -19,6 +19,9 @@ type BlockService Service[*cltypes.SignedBeaconBlock]
 //go:generate mockgen -typed=true -destination=./mock_services/blob_sidecars_service_mock.go -package=mock_services . BlobSidecarsService
 type BlobSidecarsService Service[*cltypes.BlobSidecar]
 
+//go:generate mockgen -typed=true -destination=./mock_services/data_column_sidecar_service_mock.go -package=mock_services . DataColumnSidecarService
+type DataColumnSidecarService Service[*cltypes.DataColumnSidecar]
+
 //go:generate mockgen -typed=true -destination=./mock_services/sync_committee_messages_service_mock.go -package=mock_services . SyncCommitteeMessagesService
 type SyncCommitteeMessagesService Service[*cltypes.SyncCommitteeMessage]
 
@@ -39,3 +42,6 @@ type BLSToExecutionChangeService Service[*cltypes.SignedBLSToExecutionChange]
 
 //go:generate mockgen -typed=true -destination=./mock_services/proposer_slashing_service_mock.go -package=mock_services . ProposerSlashingService
 type ProposerSlashingService Service[*cltypes.ProposerSlashing]
+
+//go:generate mockgen -typed=true -destination=./mock_services/attester_slashing_service_mock.go -package=mock_services . AttesterSlashingService
+type AttesterSlashingService Service[*cltypes.AttesterSlashing]
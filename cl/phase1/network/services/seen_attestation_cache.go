@@ -0,0 +1,131 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+const (
+	DefaultSeenAttestationCacheEpochs    = 3       // the target epochs of attestations in the propagation range, and the next one
+	DefaultSeenAttestationCacheEpochSize = 100_000 // validators attesting on the subscribed subnets in an epoch
+	seenAttestationCacheMetricsPrefix    = "caplin_attestation_seen_cache"
+)
+
+var (
+	seenAttestationCacheHits    = metrics.GetOrCreateCounter(seenAttestationCacheMetricsPrefix + "_hits")
+	seenAttestationCacheMisses  = metrics.GetOrCreateCounter(seenAttestationCacheMetricsPrefix + "_misses")
+	seenAttestationCacheDropped = metrics.GetOrCreateCounter(seenAttestationCacheMetricsPrefix + "_dropped")
+	seenAttestationCacheEntries = metrics.GetOrCreateGauge(seenAttestationCacheMetricsPrefix + "_entries")
+)
+
+type seenAttestation struct {
+	validatorIndex uint64
+	slot           uint64
+	committeeIndex uint64
+}
+
+// SeenAttestationEpoch - occupancy of an epoch of the seen attestation cache
+type SeenAttestationEpoch struct {
+	Epoch   uint64 `json:"epoch,string"`
+	Entries int    `json:"entries,string"`
+}
+
+// SeenAttestationCache - (validator, slot, committee index) tuples of the attestations seen on the attestation subnets,
+// partitioned by target epoch. Only the latest maxEpochs epochs are kept, older partitions are dropped as a whole, and
+// an epoch holds at most maxEpochSize tuples, so the memory is bounded whatever is gossiped.
+type SeenAttestationCache struct {
+	mu           sync.Mutex
+	epochs       map[uint64]map[seenAttestation]struct{}
+	latestEpoch  uint64
+	entries      int
+	maxEpochs    uint64
+	maxEpochSize int
+}
+
+// NewSeenAttestationCache - zero values mean the defaults
+func NewSeenAttestationCache(maxEpochs uint64, maxEpochSize int) *SeenAttestationCache {
+	if maxEpochs == 0 {
+		maxEpochs = DefaultSeenAttestationCacheEpochs
+	}
+	if maxEpochSize <= 0 {
+		maxEpochSize = DefaultSeenAttestationCacheEpochSize
+	}
+	return &SeenAttestationCache{
+		epochs:       make(map[uint64]map[seenAttestation]struct{}),
+		maxEpochs:    maxEpochs,
+		maxEpochSize: maxEpochSize,
+	}
+}
+
+// Seen - the attestation of the validator has been seen in the target epoch
+func (c *SeenAttestationCache) Seen(epoch, validatorIndex, slot, committeeIndex uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, seen := c.epochs[epoch][seenAttestation{validatorIndex, slot, committeeIndex}]
+	if seen {
+		seenAttestationCacheHits.Inc()
+	} else {
+		seenAttestationCacheMisses.Inc()
+	}
+	return seen
+}
+
+// Add marks the attestation as seen. Attestations older than the kept epochs are not recorded, and neither are the
+// ones of full epochs.
+func (c *SeenAttestationCache) Add(epoch, validatorIndex, slot, committeeIndex uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch > c.latestEpoch {
+		c.latestEpoch = epoch
+		c.evict()
+	}
+	if epoch+c.maxEpochs <= c.latestEpoch {
+		return
+	}
+	seen, ok := c.epochs[epoch]
+	if !ok {
+		seen = make(map[seenAttestation]struct{})
+		c.epochs[epoch] = seen
+	}
+	key := seenAttestation{validatorIndex, slot, committeeIndex}
+	if _, ok := seen[key]; ok {
+		return
+	}
+	if len(seen) >= c.maxEpochSize {
+		seenAttestationCacheDropped.Inc()
+		return
+	}
+	seen[key] = struct{}{}
+	c.entries++
+	seenAttestationCacheEntries.SetInt(c.entries)
+}
+
+// evict drops the epochs which are out of the window of the latest epoch
+func (c *SeenAttestationCache) evict() {
+	for epoch, seen := range c.epochs {
+		if epoch+c.maxEpochs <= c.latestEpoch {
+			c.entries -= len(seen)
+			delete(c.epochs, epoch)
+		}
+	}
+	seenAttestationCacheEntries.SetInt(c.entries)
+}
+
+// Occupancy returns the number of tuples per kept epoch, in ascending order of epochs
+func (c *SeenAttestationCache) Occupancy() []SeenAttestationEpoch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	occupancy := make([]SeenAttestationEpoch, 0, len(c.epochs))
+	for epoch, seen := range c.epochs {
+		occupancy = append(occupancy, SeenAttestationEpoch{Epoch: epoch, Entries: len(seen)})
+	}
+	sort.Slice(occupancy, func(i, j int) bool { return occupancy[i].Epoch < occupancy[j].Epoch })
+	return occupancy
+}
+
+// Limits returns the number of kept epochs and the max number of tuples per epoch
+func (c *SeenAttestationCache) Limits() (maxEpochs uint64, maxEpochSize int) {
+	return c.maxEpochs, c.maxEpochSize
+}
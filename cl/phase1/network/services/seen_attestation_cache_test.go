@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenAttestationCache(t *testing.T) {
+	c := NewSeenAttestationCache(2, 3)
+
+	require.False(t, c.Seen(10, 1, 320, 0))
+	c.Add(10, 1, 320, 0)
+	require.True(t, c.Seen(10, 1, 320, 0))
+	require.False(t, c.Seen(10, 1, 321, 0))
+	require.False(t, c.Seen(11, 1, 320, 0))
+
+	// the epoch is full, the attestation is not recorded
+	c.Add(10, 2, 320, 0)
+	c.Add(10, 3, 320, 1)
+	c.Add(10, 4, 321, 0)
+	require.False(t, c.Seen(10, 4, 321, 0))
+	require.Equal(t, []SeenAttestationEpoch{{Epoch: 10, Entries: 3}}, c.Occupancy())
+
+	c.Add(11, 1, 352, 0)
+	require.Equal(t, []SeenAttestationEpoch{{Epoch: 10, Entries: 3}, {Epoch: 11, Entries: 1}}, c.Occupancy())
+
+	// the epoch 10 is out of the window
+	c.Add(12, 1, 384, 0)
+	require.Equal(t, []SeenAttestationEpoch{{Epoch: 11, Entries: 1}, {Epoch: 12, Entries: 1}}, c.Occupancy())
+	require.False(t, c.Seen(10, 1, 320, 0))
+
+	// old attestations are not recorded
+	c.Add(9, 1, 288, 0)
+	require.False(t, c.Seen(9, 1, 288, 0))
+	require.Equal(t, 2, c.entries)
+}
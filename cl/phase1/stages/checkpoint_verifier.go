@@ -0,0 +1,79 @@
+package stages
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/log/v3"
+)
+
+var checkpointInconsistent = metrics.GetOrCreateGauge("caplin_checkpoint_state_inconsistent")
+
+// checkpointVerifier checks the blocks backfilled from the network against the history the checkpoint state
+// commits to: the roots of the blocks and of the post-states of its last SlotsPerHistoricalRoot slots.
+// The checkpoint state is trusted to start syncing, a mismatch means that the checkpoint sync provider
+// served a state which is not on the chain.
+type checkpointVerifier struct {
+	slot       uint64 // slot of the checkpoint state
+	blockRoots []libcommon.Hash
+	stateRoots []libcommon.Hash
+
+	verified     uint64
+	done         bool
+	inconsistent bool
+	logger       log.Logger
+}
+
+func newCheckpointVerifier(checkpoint *state.CachingBeaconState, logger log.Logger) *checkpointVerifier {
+	// copy the roots, not to hold on to the whole state during the backfilling
+	blockRoots, stateRoots := checkpoint.BlockRoots(), checkpoint.StateRoots()
+	v := &checkpointVerifier{
+		slot:       checkpoint.Slot(),
+		blockRoots: make([]libcommon.Hash, blockRoots.Length()),
+		stateRoots: make([]libcommon.Hash, stateRoots.Length()),
+		logger:     logger,
+	}
+	for i := range v.blockRoots {
+		v.blockRoots[i] = blockRoots.Get(i)
+		v.stateRoots[i] = stateRoots.Get(i)
+	}
+	return v
+}
+
+// verify checks a block downloaded backwards from the checkpoint, a mismatch is alerted only once
+func (v *checkpointVerifier) verify(blk *cltypes.SignedBeaconBlock) error {
+	historyLength := uint64(len(v.blockRoots))
+	slot := blk.Block.Slot
+	if v.done || historyLength == 0 || slot >= v.slot {
+		return nil
+	}
+	if slot+historyLength < v.slot {
+		// the blocks are downloaded backwards, the whole history of the checkpoint state has been seen
+		v.done = true
+		if !v.inconsistent {
+			v.logger.Info("[Checkpoint Sync] Checkpoint state verified against the backfilled blocks", "slot", v.slot, "blocks", v.verified)
+		}
+		return nil
+	}
+
+	blockRoot, err := blk.Block.HashSSZ()
+	if err != nil {
+		return err
+	}
+	expectedBlockRoot, expectedStateRoot := v.blockRoots[slot%historyLength], v.stateRoots[slot%historyLength]
+	if blockRoot == expectedBlockRoot && blk.Block.StateRoot == expectedStateRoot {
+		v.verified++
+		return nil
+	}
+
+	if !v.inconsistent {
+		v.inconsistent = true
+		checkpointInconsistent.SetUint64(1)
+		v.logger.Error("[Checkpoint Sync] The checkpoint sync provider served a state inconsistent with the chain, resync with a trusted provider",
+			"checkpointSlot", v.slot, "slot", slot,
+			"blockRoot", blockRoot, "expectedBlockRoot", expectedBlockRoot,
+			"stateRoot", blk.Block.StateRoot, "expectedStateRoot", expectedStateRoot)
+	}
+	return nil
+}
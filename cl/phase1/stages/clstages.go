@@ -213,6 +213,11 @@ func ConsensusClStages(ctx context.Context,
 	// Probably the correct long term solution is to create a third generic parameter that defines shared state
 	// but for now, all it would have are the two gossip sources and the forkChoicesSinceReorg, so i don't think its worth it (yet).
 	shouldForkChoiceSinceReorg := false
+	// last head announced via the "head" event, used to detect reorgs
+	var prevHeadRoot common.Hash
+	var prevHeadSlot uint64
+	// last light client update written to the indicies db
+	var prevLightClientUpdate *cltypes.LightClientUpdate
 
 	// clstages run in a single thread - so we don't need to worry about any synchronization.
 	return &clstages.StageGraph[*Cfg, Args]{
@@ -251,7 +256,7 @@ func ConsensusClStages(ctx context.Context,
 					startingSlot := cfg.state.LatestBlockHeader().Slot
 					downloader := network2.NewBackwardBeaconDownloader(context.Background(), cfg.rpc, cfg.executionClient, cfg.indiciesDB)
 
-					if err := SpawnStageHistoryDownload(StageHistoryReconstruction(downloader, cfg.antiquary, cfg.sn, cfg.indiciesDB, cfg.executionClient, cfg.beaconCfg, cfg.backfilling, cfg.blobBackfilling, false, startingRoot, startingSlot, cfg.tmpdir, 600*time.Millisecond, cfg.blockCollector, cfg.blockReader, cfg.blobStore, logger), context.Background(), logger); err != nil {
+					if err := SpawnStageHistoryDownload(StageHistoryReconstruction(downloader, cfg.antiquary, cfg.sn, cfg.indiciesDB, cfg.executionClient, cfg.beaconCfg, cfg.backfilling, cfg.blobBackfilling, false, startingRoot, startingSlot, cfg.tmpdir, 600*time.Millisecond, cfg.blockCollector, cfg.blockReader, cfg.blobStore, newCheckpointVerifier(cfg.state, logger), logger), context.Background(), logger); err != nil {
 						cfg.hasDownloaded = false
 						return err
 					}
@@ -686,6 +691,36 @@ func ConsensusClStages(ctx context.Context,
 						"current_duty_dependent_root":  current_duty_dependent_root,
 						"execution_optimistic":         false,
 					})
+					if prevHeadRoot != (common.Hash{}) && prevHeadRoot != headRoot && cfg.forkChoice.Ancestor(headRoot, prevHeadSlot) != prevHeadRoot {
+						if depth, ok := chainReorgDepth(cfg.forkChoice, prevHeadRoot, headRoot); ok {
+							var oldHeadState common.Hash
+							if oldHeader, has := cfg.forkChoice.GetHeader(prevHeadRoot); has {
+								oldHeadState = oldHeader.Root
+							}
+							cfg.emitter.Publish("chain_reorg", map[string]any{
+								"slot":                 strconv.FormatUint(headSlot, 10),
+								"depth":                strconv.FormatUint(depth, 10),
+								"old_head_block":       prevHeadRoot,
+								"new_head_block":       headRoot,
+								"old_head_state":       oldHeadState,
+								"new_head_state":       common.Hash(stateRoot),
+								"epoch":                strconv.FormatUint(headEpoch, 10),
+								"execution_optimistic": false,
+							})
+						}
+					}
+					prevHeadRoot, prevHeadSlot = headRoot, headSlot
+
+					// persist the best light client update of the current period, so it survives the pruning of the fork graph
+					if newestUpdate := cfg.forkChoice.NewestLightClientUpdate(); newestUpdate != nil {
+						period := cfg.beaconCfg.SyncCommitteePeriod(newestUpdate.AttestedHeader.Beacon.Slot)
+						if bestUpdate, ok := cfg.forkChoice.GetLightClientUpdate(period); ok && bestUpdate != prevLightClientUpdate {
+							if err := beacon_indicies.WriteLightClientUpdate(tx, period, bestUpdate); err != nil {
+								return fmt.Errorf("failed to write light client update: %w", err)
+							}
+							prevLightClientUpdate = bestUpdate
+						}
+					}
 
 					var m runtime.MemStats
 					dbg.ReadMemStats(&m)
@@ -766,3 +801,20 @@ func ConsensusClStages(ctx context.Context,
 		},
 	}
 }
+
+// chainReorgDepth returns the distance in slots between the old head and its common ancestor with the new head.
+func chainReorgDepth(fc *forkchoice.ForkChoiceStore, oldHeadRoot, newHeadRoot common.Hash) (uint64, bool) {
+	oldHead, has := fc.GetHeader(oldHeadRoot)
+	if !has {
+		return 0, false
+	}
+	for root, header := oldHeadRoot, oldHead; ; {
+		if fc.Ancestor(newHeadRoot, header.Slot) == root {
+			return oldHead.Slot - header.Slot, true
+		}
+		root = header.ParentRoot
+		if header, has = fc.GetHeader(root); !has {
+			return 0, false
+		}
+	}
+}
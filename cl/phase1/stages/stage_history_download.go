@@ -38,6 +38,7 @@ type StageHistoryReconstructionCfg struct {
 	antiquary                *antiquary.Antiquary
 	logger                   log.Logger
 	executionBlocksCollector block_collector.BlockCollector
+	checkpointVerifier       *checkpointVerifier
 	backfillingThrottling    time.Duration
 	blockReader              freezeblocks.BeaconSnapshotReader
 	blobStorage              blob_storage.BlobStorage
@@ -45,7 +46,7 @@ type StageHistoryReconstructionCfg struct {
 
 const logIntervalTime = 30 * time.Second
 
-func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, antiquary *antiquary.Antiquary, sn *freezeblocks.CaplinSnapshots, indiciesDB kv.RwDB, engine execution_client.ExecutionEngine, beaconCfg *clparams.BeaconChainConfig, backfilling, blobsBackfilling, waitForAllRoutines bool, startingRoot libcommon.Hash, startinSlot uint64, tmpdir string, backfillingThrottling time.Duration, executionBlocksCollector block_collector.BlockCollector, blockReader freezeblocks.BeaconSnapshotReader, blobStorage blob_storage.BlobStorage, logger log.Logger) StageHistoryReconstructionCfg {
+func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, antiquary *antiquary.Antiquary, sn *freezeblocks.CaplinSnapshots, indiciesDB kv.RwDB, engine execution_client.ExecutionEngine, beaconCfg *clparams.BeaconChainConfig, backfilling, blobsBackfilling, waitForAllRoutines bool, startingRoot libcommon.Hash, startinSlot uint64, tmpdir string, backfillingThrottling time.Duration, executionBlocksCollector block_collector.BlockCollector, blockReader freezeblocks.BeaconSnapshotReader, blobStorage blob_storage.BlobStorage, checkpointVerifier *checkpointVerifier, logger log.Logger) StageHistoryReconstructionCfg {
 	return StageHistoryReconstructionCfg{
 		beaconCfg:                beaconCfg,
 		downloader:               downloader,
@@ -61,6 +62,7 @@ func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, an
 		sn:                       sn,
 		backfillingThrottling:    backfillingThrottling,
 		executionBlocksCollector: executionBlocksCollector,
+		checkpointVerifier:       checkpointVerifier,
 		blockReader:              blockReader,
 		blobsBackfilling:         blobsBackfilling,
 		blobStorage:              blobStorage,
@@ -103,6 +105,12 @@ func SpawnStageHistoryDownload(cfg StageHistoryReconstructionCfg, ctx context.Co
 		destinationSlotForCL := cfg.sn.SegmentsMax()
 
 		slot := blk.Block.Slot
+		// trust, but verify: the checkpoint state must be consistent with the blocks served by the network
+		if cfg.checkpointVerifier != nil {
+			if err := cfg.checkpointVerifier.verify(blk); err != nil {
+				return false, err
+			}
+		}
 		if destinationSlotForCL <= blk.Block.Slot {
 			if err := beacon_indicies.WriteBeaconBlockAndIndicies(ctx, tx, blk, true); err != nil {
 				return false, err
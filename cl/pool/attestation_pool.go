@@ -0,0 +1,261 @@
+package pool
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/Giulio2002/bls"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// maxAggregatesPerData caps how many non-mergeable aggregates are kept for the same attestation data.
+const maxAggregatesPerData = 16
+
+var blsAggregate = bls.AggregateSignatures
+
+// AttestationPool keeps validated attestations (single and aggregated) for block production.
+// Attestations with the same data (so same slot and committee) are aggregated on insertion whenever their
+// aggregation bits don't overlap, so the pool holds few large aggregates instead of many small ones.
+type AttestationPool struct {
+	beaconCfg *clparams.BeaconChainConfig
+
+	mu      sync.Mutex
+	groups  map[libcommon.Hash]*attestationGroup // attestation data root -> aggregates
+	maxSlot uint64                               // highest attestation slot seen
+}
+
+type attestationGroup struct {
+	data       solid.AttestationData
+	aggregates []*solid.Attestation
+}
+
+func NewAttestationPool(beaconCfg *clparams.BeaconChainConfig) *AttestationPool {
+	return &AttestationPool{
+		beaconCfg: beaconCfg,
+		groups:    make(map[libcommon.Hash]*attestationGroup),
+	}
+}
+
+// Add inserts an attestation, which must already be validated (signature included).
+func (p *AttestationPool) Add(att *solid.Attestation) error {
+	data := att.AttestantionData()
+	root, err := data.HashSSZ()
+	if err != nil {
+		return err
+	}
+	attBits := att.AggregationBits()
+	if bitlistMarker(attBits) == 0 {
+		return errors.New("attestation pool: empty aggregation bits")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if data.Slot() > p.maxSlot {
+		p.maxSlot = data.Slot()
+	}
+	if p.isStale(data, p.maxSlot) {
+		return nil
+	}
+	group, ok := p.groups[root]
+	if !ok {
+		group = &attestationGroup{data: att.Copy().AttestantionData()}
+		p.groups[root] = group
+	}
+
+	// drop what the new attestation covers, and skip it if it is covered itself
+	kept := group.aggregates[:0]
+	for _, agg := range group.aggregates {
+		if utils.IsNonStrictSupersetBitlist(agg.AggregationBits(), attBits) {
+			return nil
+		}
+		if !utils.IsNonStrictSupersetBitlist(attBits, agg.AggregationBits()) {
+			kept = append(kept, agg)
+		}
+	}
+	group.aggregates = kept
+
+	for i, agg := range group.aggregates {
+		if bitlistsOverlap(agg.AggregationBits(), attBits) {
+			continue
+		}
+		aggSig, attSig := agg.Signature(), att.Signature()
+		merged, err := blsAggregate([][]byte{aggSig[:], attSig[:]})
+		if err != nil {
+			return err
+		}
+		var mergedSig [96]byte
+		copy(mergedSig[:], merged)
+		mergedBits := libcommon.Copy(agg.AggregationBits())
+		utils.MergeBitlists(mergedBits, attBits)
+		group.aggregates[i] = solid.NewAttestionFromParameters(mergedBits, group.data, mergedSig)
+		return nil
+	}
+
+	group.aggregates = append(group.aggregates, att.Copy())
+	if len(group.aggregates) > maxAggregatesPerData {
+		sort.Slice(group.aggregates, func(i, j int) bool {
+			return bitlistCount(group.aggregates[i].AggregationBits(), nil) > bitlistCount(group.aggregates[j].AggregationBits(), nil)
+		})
+		group.aggregates = group.aggregates[:maxAggregatesPerData]
+	}
+	return nil
+}
+
+// NotifyIncluded drops aggregates whose votes are all included in a block at slot, and attestations too old to be included after it.
+func (p *AttestationPool) NotifyIncluded(slot uint64, included []*solid.Attestation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, att := range included {
+		root, err := att.AttestantionData().HashSSZ()
+		if err != nil {
+			continue
+		}
+		group, ok := p.groups[root]
+		if !ok {
+			continue
+		}
+		kept := group.aggregates[:0]
+		for _, agg := range group.aggregates {
+			if !utils.IsNonStrictSupersetBitlist(att.AggregationBits(), agg.AggregationBits()) {
+				kept = append(kept, agg)
+			}
+		}
+		group.aggregates = kept
+	}
+	for root, group := range p.groups {
+		if len(group.aggregates) == 0 || p.isStale(group.data, slot) {
+			delete(p.groups, root)
+		}
+	}
+}
+
+// GetAttestationsForBlock returns aggregates includable in a block at slot, best first.
+// Packing is greedy weighted max-coverage: each next aggregate is the one adding the most not yet covered votes
+// for its data, weighted by the participation flags (source, target, head) its inclusion delay still earns.
+// Aggregates adding nothing are left out. Caller takes the first MaxAttestations which are valid against its state.
+func (p *AttestationPool) GetAttestationsForBlock(slot uint64) []*solid.Attestation {
+	type candidate struct {
+		att    *solid.Attestation
+		root   libcommon.Hash
+		weight uint64
+	}
+	p.mu.Lock()
+	var candidates []candidate
+	for root, group := range p.groups {
+		weight := p.inclusionWeight(group.data, slot)
+		if weight == 0 {
+			continue
+		}
+		for _, agg := range group.aggregates {
+			candidates = append(candidates, candidate{att: agg.Copy(), root: root, weight: weight})
+		}
+	}
+	p.mu.Unlock()
+
+	covered := make(map[libcommon.Hash][]byte)
+	var ret []*solid.Attestation
+	for len(candidates) > 0 {
+		best, bestScore := -1, uint64(0)
+		for i, c := range candidates {
+			score := uint64(bitlistCount(c.att.AggregationBits(), covered[c.root])) * c.weight
+			// ties: prefer older attestations, they are closer to expire
+			if score > bestScore || (score == bestScore && score > 0 && c.att.AttestantionData().Slot() < candidates[best].att.AttestantionData().Slot()) {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		c := candidates[best]
+		ret = append(ret, c.att)
+		if cov, ok := covered[c.root]; ok {
+			utils.MergeBitlists(cov, c.att.AggregationBits())
+		} else {
+			covered[c.root] = libcommon.Copy(c.att.AggregationBits())
+		}
+		candidates[best] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+	}
+	return ret
+}
+
+// inclusionWeight - sum of weights of participation flags an attestation with data still earns in a block at slot, 0 if not includable
+func (p *AttestationPool) inclusionWeight(data solid.AttestationData, slot uint64) uint64 {
+	if data.Slot()+p.beaconCfg.MinAttestationInclusionDelay > slot || p.isStale(data, slot) {
+		return 0
+	}
+	delay := slot - data.Slot()
+	weight := p.beaconCfg.TimelyTargetWeight // includable - target is still timely
+	if delay <= utils.IntegerSquareRoot(p.beaconCfg.SlotsPerEpoch) {
+		weight += p.beaconCfg.TimelySourceWeight
+	}
+	if delay == p.beaconCfg.MinAttestationInclusionDelay {
+		weight += p.beaconCfg.TimelyHeadWeight
+	}
+	return weight
+}
+
+// isStale - attestation with data can't be included in a block at slot or later
+func (p *AttestationPool) isStale(data solid.AttestationData, slot uint64) bool {
+	if data.Slot() >= slot {
+		return false
+	}
+	epoch := slot / p.beaconCfg.SlotsPerEpoch
+	if p.beaconCfg.GetCurrentStateVersion(epoch) >= clparams.DenebVersion {
+		// EIP-7045: attestations from the current and previous epoch are includable
+		return data.Target().Epoch()+1 < epoch
+	}
+	return data.Slot()+p.beaconCfg.SlotsPerEpoch < slot
+}
+
+// bitlistMarker - length marker bit (most significant set bit of the last byte), 0 for empty bitlist
+func bitlistMarker(b []byte) byte {
+	if len(b) == 0 {
+		return 0
+	}
+	l := bits.Len8(b[len(b)-1])
+	if l == 0 {
+		return 0
+	}
+	return 1 << (l - 1)
+}
+
+// bitlistsOverlap - true if bitlists share a set bit, or have different length and can't be merged
+func bitlistsOverlap(a, b []byte) bool {
+	if len(a) != len(b) || bitlistMarker(a) != bitlistMarker(b) {
+		return true
+	}
+	last := len(a) - 1
+	for i := range a {
+		x := a[i] & b[i]
+		if i == last {
+			x &^= bitlistMarker(a)
+		}
+		if x != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bitlistCount - number of bits set in b and not in covered, length marker excluded
+func bitlistCount(b, covered []byte) int {
+	var n int
+	last := len(b) - 1
+	for i := range b {
+		x := b[i]
+		if i < len(covered) {
+			x &^= covered[i]
+		}
+		if i == last {
+			x &^= bitlistMarker(b)
+		}
+		n += bits.OnesCount8(x)
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package pool
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/stretchr/testify/require"
+)
+
+func testAttestation(slot, committee uint64, bits byte) *solid.Attestation {
+	data := solid.NewAttestionDataFromParameters(slot, committee, libcommon.Hash{1},
+		solid.NewCheckpointFromParameters(libcommon.Hash{}, 0),
+		solid.NewCheckpointFromParameters(libcommon.Hash{2}, slot/32))
+	// committee of 7 validators: bit 7 is the length marker
+	return solid.NewAttestionFromParameters([]byte{bits | 0x80}, data, [96]byte{bits})
+}
+
+func TestAttestationPoolAggregation(t *testing.T) {
+	blsAggregate = func(sigs [][]byte) ([]byte, error) { return make([]byte, 96), nil }
+	p := NewAttestationPool(&clparams.MainnetBeaconConfig)
+
+	require.NoError(t, p.Add(testAttestation(10, 0, 0b0001)))
+	require.NoError(t, p.Add(testAttestation(10, 0, 0b0010)))
+	require.NoError(t, p.Add(testAttestation(10, 0, 0b0010))) // duplicate
+	require.NoError(t, p.Add(testAttestation(10, 0, 0b0110))) // overlaps with the aggregate
+	require.NoError(t, p.Add(testAttestation(10, 1, 0b0001))) // other committee
+
+	atts := p.GetAttestationsForBlock(11)
+	require.Len(t, atts, 3)
+	// 0b0011 and 0b0110 add 2 votes each, committee 1 adds 1, so one of the 2-vote aggregates goes first
+	require.Equal(t, 2, bitlistCount(atts[0].AggregationBits(), nil))
+	require.Equal(t, uint64(0), atts[0].AttestantionData().CommitteeIndex())
+
+	// not includable in the same slot
+	require.Empty(t, p.GetAttestationsForBlock(10))
+
+	// superset replaces the subsets
+	require.NoError(t, p.Add(testAttestation(10, 0, 0b0111)))
+	atts = p.GetAttestationsForBlock(11)
+	require.Len(t, atts, 2)
+	require.Equal(t, []byte{0x87}, atts[0].AggregationBits())
+
+	// included in a block - removed
+	p.NotifyIncluded(11, []*solid.Attestation{atts[0]})
+	atts = p.GetAttestationsForBlock(12)
+	require.Len(t, atts, 1)
+	require.Equal(t, uint64(1), atts[0].AttestantionData().CommitteeIndex())
+}
+
+func TestAttestationPoolPacking(t *testing.T) {
+	blsAggregate = func(sigs [][]byte) ([]byte, error) { return make([]byte, 96), nil }
+	cfg := clparams.MainnetBeaconConfig
+	p := NewAttestationPool(&cfg)
+
+	require.NoError(t, p.Add(testAttestation(96, 0, 0b0111))) // 3 votes, source+target
+	require.NoError(t, p.Add(testAttestation(99, 0, 0b0011))) // 2 votes, source+target+head
+	require.NoError(t, p.Add(testAttestation(70, 0, 0b1111))) // 4 votes, target only
+
+	atts := p.GetAttestationsForBlock(100)
+	require.Len(t, atts, 3)
+	require.Equal(t, uint64(96), atts[0].AttestantionData().Slot()) // 3 * 40
+	require.Equal(t, uint64(99), atts[1].AttestantionData().Slot()) // 2 * 54
+	require.Equal(t, uint64(70), atts[2].AttestantionData().Slot()) // 4 * 26
+	require.NoError(t, p.Add(testAttestation(40, 0, 0b1111)))       // epoch 1, can't be included in epoch 3
+	require.Len(t, p.GetAttestationsForBlock(100), 3)
+
+	// attestations expire after the next epoch
+	p.NotifyIncluded(128, nil)
+	require.Len(t, p.GetAttestationsForBlock(128), 2)
+	p.NotifyIncluded(160, nil)
+	require.Empty(t, p.GetAttestationsForBlock(160))
+}
+
+func TestBitlistHelpers(t *testing.T) {
+	require.False(t, bitlistsOverlap([]byte{0x81}, []byte{0x82}))
+	require.True(t, bitlistsOverlap([]byte{0x81}, []byte{0x83}))
+	require.True(t, bitlistsOverlap([]byte{0x81}, []byte{0x41}))
+	require.True(t, bitlistsOverlap([]byte{0x01, 0x01}, []byte{0x81}))
+	require.Equal(t, 2, bitlistCount([]byte{0xff, 0x03}, []byte{0xfe}))
+	require.Equal(t, byte(0), bitlistMarker(nil))
+}
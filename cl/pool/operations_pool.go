@@ -32,6 +32,8 @@ type OperationsPool struct {
 	ProposerSlashingsPool     *OperationPool[libcommon.Bytes96, *cltypes.ProposerSlashing]
 	BLSToExecutionChangesPool *OperationPool[libcommon.Bytes96, *cltypes.SignedBLSToExecutionChange]
 	VoluntaryExitsPool        *OperationPool[uint64, *cltypes.SignedVoluntaryExit]
+	// BlockAttestationsPool aggregates validated attestations for block production
+	BlockAttestationsPool *AttestationPool
 }
 
 func NewOperationsPool(beaconCfg *clparams.BeaconChainConfig) OperationsPool {
@@ -41,6 +43,7 @@ func NewOperationsPool(beaconCfg *clparams.BeaconChainConfig) OperationsPool {
 		ProposerSlashingsPool:     NewOperationPool[libcommon.Bytes96, *cltypes.ProposerSlashing](operationsPerPool, "proposerSlashingsPool"),
 		BLSToExecutionChangesPool: NewOperationPool[libcommon.Bytes96, *cltypes.SignedBLSToExecutionChange](operationsPerPool, "blsExecutionChangesPool"),
 		VoluntaryExitsPool:        NewOperationPool[uint64, *cltypes.SignedVoluntaryExit](operationsPerPool, "voluntaryExitsPool"),
+		BlockAttestationsPool:     NewAttestationPool(beaconCfg),
 	}
 }
 
@@ -62,4 +65,12 @@ func (o *OperationsPool) NotifyBlock(blk *cltypes.BeaconBlock) {
 		return true
 	})
 	o.BLSToExecutionChangesPool.pool.Purge()
+	if o.BlockAttestationsPool != nil {
+		included := make([]*solid.Attestation, 0, blk.Body.Attestations.Len())
+		blk.Body.Attestations.Range(func(_ int, att *solid.Attestation, _ int) bool {
+			included = append(included, att)
+			return true
+		})
+		o.BlockAttestationsPool.NotifyIncluded(blk.Slot, included)
+	}
 }
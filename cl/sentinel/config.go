@@ -16,6 +16,7 @@ package sentinel
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"math"
 	"net"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -46,6 +47,13 @@ type SentinelConfig struct {
 
 	EnableBlocks   bool
 	ActiveIndicies uint64
+	// EnablePeerDAS - experimental: custody, gossip and serve data column sidecars once EIP-7594 is scheduled
+	EnablePeerDAS bool
+}
+
+// peerDASEnabled - whether data column sidecars are advertised (ENR), gossiped and served
+func (c *SentinelConfig) peerDASEnabled() bool {
+	return c.EnablePeerDAS && c.BeaconConfig.EIP7594ForkEpoch != math.MaxUint64
 }
 
 func convertToCryptoPrivkey(privkey *ecdsa.PrivateKey) (crypto.PrivKey, error) {
@@ -89,6 +89,12 @@ func (s *Sentinel) listenForPeers() {
 			continue
 		}
 		node := iterator.Node()
+		// close to the peer limit, leave the remaining slots to the nodes serving subnets we lack peers in
+		if s.isNearPeerLimit() {
+			if missing := s.subnetsWithoutPeers(s.advertisedAttSubnets()); len(missing) > 0 && !s.nodeHasAnySubnet(node, missing) {
+				continue
+			}
+		}
 		peerInfo, _, err := convertToAddrInfo(node)
 		if err != nil {
 			log.Error("[Sentinel] Could not convert to peer info", "err", err)
@@ -123,6 +129,9 @@ func (s *Sentinel) connectToBootnodes() error {
 	return nil
 }
 
+// custodyGroupCountKey is the ENR key advertising how many PeerDAS custody groups we serve.
+const custodyGroupCountKey = "cgc"
+
 func (s *Sentinel) setupENR(
 	node *enode.LocalNode,
 ) (*enode.LocalNode, error) {
@@ -133,6 +142,9 @@ func (s *Sentinel) setupENR(
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.Eth2key, forkId))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, bitfield.NewBitvector64().Bytes()))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.SyncCommsSubnetKey, bitfield.Bitvector4{byte(0x00)}.Bytes()))
+	if s.cfg.peerDASEnabled() {
+		node.Set(enr.WithEntry(custodyGroupCountKey, s.cfg.BeaconConfig.CustodyRequirement))
+	}
 	return node, nil
 }
 
@@ -15,6 +15,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/gossip"
+	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/ledgerwatch/log/v3"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -120,6 +122,9 @@ var LightClientOptimisticUpdateSsz = GossipTopic{
 type GossipManager struct {
 	ch            chan *GossipMessage
 	subscriptions sync.Map // map from topic string to *GossipSubscription
+	ethClock      eth_clock.EthereumClock
+
+	subscribeLock sync.Mutex // topic can be joined only once
 }
 
 const maxIncomingGossipMessages = 1 << 16
@@ -127,10 +132,12 @@ const maxIncomingGossipMessages = 1 << 16
 // construct a new gossip manager that will handle packets with the given handlerfunc
 func NewGossipManager(
 	ctx context.Context,
+	ethClock eth_clock.EthereumClock,
 ) *GossipManager {
 	g := &GossipManager{
 		ch:            make(chan *GossipMessage, maxIncomingGossipMessages),
 		subscriptions: sync.Map{},
+		ethClock:      ethClock,
 	}
 	return g
 }
@@ -149,8 +156,37 @@ func (s *GossipManager) Recv() <-chan *GossipMessage {
 	return s.ch
 }
 
+// GetMatchingSubscription returns the subscription of the current fork digest, around forks the topics of
+// the next/previous fork are subscribed too, see forkWatcher
 func (s *GossipManager) GetMatchingSubscription(match string) *GossipSubscription {
+	digest, err := s.ethClock.CurrentForkDigest()
+	if err != nil {
+		log.Error("[Gossip] Failed to calculate fork choice", "err", err)
+		return nil
+	}
 	var sub *GossipSubscription
+	s.rangeMatchingSubscriptions(match, func(candidate *GossipSubscription) bool {
+		if candidate.digest != digest {
+			return true
+		}
+		sub = candidate
+		return false
+	})
+	return sub
+}
+
+// OverwriteSubscriptionExpiry extends the expiry of the topic subscriptions of all fork digests,
+// returns false if there is no such topic.
+func (s *GossipManager) OverwriteSubscriptionExpiry(match string, expiry time.Time) (found bool) {
+	s.rangeMatchingSubscriptions(match, func(sub *GossipSubscription) bool {
+		sub.OverwriteSubscriptionExpiry(expiry)
+		found = true
+		return true
+	})
+	return found
+}
+
+func (s *GossipManager) rangeMatchingSubscriptions(match string, f func(sub *GossipSubscription) bool) {
 	s.subscriptions.Range(func(topic, value interface{}) bool {
 		topicStr := topic.(string)
 		// take out third part of the topic by splitting on "/"
@@ -160,12 +196,10 @@ func (s *GossipManager) GetMatchingSubscription(match string) *GossipSubscriptio
 			return true
 		}
 		if parts[3] == match {
-			sub = value.(*GossipSubscription)
-			return false
+			return f(value.(*GossipSubscription))
 		}
 		return true
 	})
-	return sub
 }
 
 func (s *GossipManager) AddSubscription(topic string, sub *GossipSubscription) {
@@ -180,57 +214,113 @@ func (s *GossipManager) unsubscribe(topic string) {
 	sub.(*GossipSubscription).Close()
 }
 
+// forkWatcher keeps the subscriptions on the topics of the fork digests of gossipForkVersions: topics of the next
+// fork are joined in advance and topics of the previous fork are kept for a while after it.
 func (s *Sentinel) forkWatcher() {
-	prevDigest, err := s.ethClock.CurrentForkDigest()
-	if err != nil {
-		log.Error("[Gossip] Failed to calculate fork choice", "err", err)
-		return
-	}
+	var prevVersions []common.Bytes4
 	iterationInterval := time.NewTicker(30 * time.Millisecond)
+	defer iterationInterval.Stop()
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 		case <-iterationInterval.C:
-			digest, err := s.ethClock.CurrentForkDigest()
+			versions := gossipForkVersions(s.cfg.BeaconConfig.ForkVersionSchedule, s.ethClock.GetCurrentEpoch(), forkTransitionEpochs)
+			if slices.Equal(versions, prevVersions) {
+				continue
+			}
+			digests, err := s.forkDigests(versions)
 			if err != nil {
 				log.Error("[Gossip] Failed to calculate fork choice", "err", err)
 				return
 			}
-			if prevDigest != digest {
-				// unsubscribe and resubscribe to all topics
-				s.subManager.subscriptions.Range(func(key, value interface{}) bool {
-					sub := value.(*GossipSubscription)
-					s.subManager.unsubscribe(key.(string))
-					newSub, err := s.SubscribeGossip(sub.gossip_topic, sub.expiration.Load().(time.Time))
-					if err != nil {
-						log.Warn("[Gossip] Failed to resubscribe to topic", "err", err)
-					}
-					newSub.Listen()
-					return true
-				})
-				prevDigest = digest
+			s.resubscribeOnDigests(digests)
+			prevVersions = versions
+		}
+	}
+}
+
+// gossipDigests - fork digests of the topics to be subscribed at the current epoch
+func (s *Sentinel) gossipDigests() ([]common.Bytes4, error) {
+	return s.forkDigests(gossipForkVersions(s.cfg.BeaconConfig.ForkVersionSchedule, s.ethClock.GetCurrentEpoch(), forkTransitionEpochs))
+}
+
+func (s *Sentinel) forkDigests(versions []common.Bytes4) ([]common.Bytes4, error) {
+	digests := make([]common.Bytes4, 0, len(versions))
+	for _, version := range versions {
+		digest, err := s.ethClock.ComputeForkDigestForVersion(version)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, digest)
+	}
+	return digests, nil
+}
+
+// resubscribeOnDigests subscribes every known topic on all the digests and drops the subscriptions of other digests
+func (s *Sentinel) resubscribeOnDigests(digests []common.Bytes4) {
+	topics := map[GossipTopic]*GossipSubscription{}
+	s.subManager.subscriptions.Range(func(key, value interface{}) bool {
+		sub := value.(*GossipSubscription)
+		if _, ok := topics[sub.gossip_topic]; !ok || slices.Contains(digests, sub.digest) {
+			topics[sub.gossip_topic] = sub
+		}
+		if !slices.Contains(digests, sub.digest) {
+			s.subManager.unsubscribe(key.(string))
+			log.Debug("[Gossip] Left topic of previous fork", "topic", key)
+		}
+		return true
+	})
+	for topic, sub := range topics {
+		for _, digest := range digests {
+			if _, err := s.ensureSubscription(topic, digest, sub.expiration.Load().(time.Time), sub.suspended.Load()); err != nil {
+				log.Warn("[Gossip] Failed to resubscribe to topic", "err", err)
 			}
 		}
 	}
 }
 
+// ensureSubscription joins the topic of the fork digest and starts listening to it, if it's not joined yet
+func (s *Sentinel) ensureSubscription(topic GossipTopic, digest common.Bytes4, expiration time.Time, suspended bool) (*GossipSubscription, error) {
+	s.subManager.subscribeLock.Lock()
+	defer s.subManager.subscribeLock.Unlock()
+	if sub, ok := s.subManager.subscriptions.Load(gossipTopicPath(digest, topic)); ok {
+		return sub.(*GossipSubscription), nil
+	}
+	sub, err := s.subscribeGossip(topic, digest, expiration)
+	if err != nil {
+		return nil, err
+	}
+	sub.suspended.Store(suspended)
+	sub.Listen()
+	return sub, nil
+}
+
+func gossipTopicPath(digest common.Bytes4, topic GossipTopic) string {
+	return fmt.Sprintf("/eth2/%x/%s/%s", digest, topic.Name, topic.CodecStr)
+}
+
 func (s *Sentinel) SubscribeGossip(topic GossipTopic, expiration time.Time, opts ...pubsub.TopicOpt) (sub *GossipSubscription, err error) {
 	digest, err := s.ethClock.CurrentForkDigest()
 	if err != nil {
 		log.Error("[Gossip] Failed to calculate fork choice", "err", err)
 	}
+	return s.subscribeGossip(topic, digest, expiration, opts...)
+}
+
+func (s *Sentinel) subscribeGossip(topic GossipTopic, digest common.Bytes4, expiration time.Time, opts ...pubsub.TopicOpt) (sub *GossipSubscription, err error) {
 	var exp atomic.Value
 	exp.Store(expiration)
 	sub = &GossipSubscription{
 		gossip_topic: topic,
+		digest:       digest,
 		ch:           s.subManager.ch,
 		host:         s.host.ID(),
 		ctx:          s.ctx,
 		expiration:   exp,
 		s:            s,
 	}
-	path := fmt.Sprintf("/eth2/%x/%s/%s", digest, topic.Name, topic.CodecStr)
+	path := gossipTopicPath(digest, topic)
 	sub.topic, err = s.pubsub.Join(path, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to join topic %s, err=%w", path, err)
@@ -249,14 +339,14 @@ func (s *Sentinel) Unsubscribe(topic GossipTopic, opts ...pubsub.TopicOpt) (err
 	if err != nil {
 		log.Error("[Gossip] Failed to calculate fork choice", "err", err)
 	}
-	s.subManager.unsubscribe(fmt.Sprintf("/eth2/%x/%s/%s", digest, topic.Name, topic.CodecStr))
+	s.subManager.unsubscribe(gossipTopicPath(digest, topic))
 
 	return nil
 }
 
 func (s *Sentinel) topicScoreParams(topic string) *pubsub.TopicScoreParams {
 	switch {
-	case strings.Contains(topic, gossip.TopicNameBeaconBlock) || gossip.IsTopicBlobSidecar(topic):
+	case strings.Contains(topic, gossip.TopicNameBeaconBlock) || gossip.IsTopicBlobSidecar(topic) || gossip.IsTopicDataColumnSidecar(topic):
 		return s.defaultBlockTopicParams()
 	case strings.Contains(topic, gossip.TopicNameVoluntaryExit):
 		return s.defaultVoluntaryExitTopicParams()
@@ -492,11 +582,13 @@ func (g *GossipManager) Close() {
 // GossipSubscription abstracts a gossip subscription to write decoded structs.
 type GossipSubscription struct {
 	gossip_topic GossipTopic
+	digest       common.Bytes4 // fork digest of the topic
 	host         peer.ID
 	ch           chan *GossipMessage
 	ctx          context.Context
 	expiration   atomic.Value // Unix nano for how much we should listen to this topic
 	subscribed   atomic.Bool
+	suspended    atomic.Bool // don't listen regardless of expiration, see TopicManager
 
 	topic *pubsub.Topic
 	sub   *pubsub.Subscription
@@ -506,48 +598,66 @@ type GossipSubscription struct {
 
 	s *Sentinel
 
-	stopCh    chan struct{}
+	lock      sync.Mutex // between Listen and Close
+	closed    bool
 	closeOnce sync.Once
 }
 
 func (sub *GossipSubscription) Listen() {
 	go func() {
-		var err error
 		checkingInterval := time.NewTicker(100 * time.Millisecond)
+		defer checkingInterval.Stop()
 		for {
 			select {
 			case <-sub.ctx.Done():
 				return
 			case <-checkingInterval.C:
-				expirationTime := sub.expiration.Load().(time.Time)
-				if sub.subscribed.Load() && time.Now().After(expirationTime) {
-					sub.stopCh <- struct{}{}
-					sub.topic.Close()
-					sub.subscribed.Store(false)
-					log.Info("[Gossip] Unsubscribed from topic", "topic", sub.sub.Topic())
-					sub.s.updateENROnSubscription(sub.sub.Topic(), false)
-					continue
+				closed, err := sub.updateSubscription()
+				if closed {
+					return
 				}
-				if !sub.subscribed.Load() && time.Now().Before(expirationTime) {
-					sub.stopCh = make(chan struct{}, 3)
-					sub.sub, err = sub.topic.Subscribe()
-					if err != nil {
-						log.Warn("[Gossip] failed to begin topic subscription", "err", err)
-						time.Sleep(30 * time.Second)
-						continue
-					}
-					var sctx context.Context
-					sctx, sub.cf = context.WithCancel(sub.ctx)
-					go sub.run(sctx, sub.sub, sub.sub.Topic())
-					sub.subscribed.Store(true)
-					sub.s.updateENROnSubscription(sub.sub.Topic(), true)
-					log.Info("[Gossip] Subscribed to topic", "topic", sub.sub.Topic())
+				if err != nil {
+					log.Warn("[Gossip] failed to begin topic subscription", "err", err)
+					time.Sleep(30 * time.Second)
 				}
 			}
 		}
 	}()
 }
 
+// updateSubscription subscribes to the topic or unsubscribes from it according to expiration and suspension.
+// The topic stays joined: it's still possible to publish to it.
+func (sub *GossipSubscription) updateSubscription() (closed bool, err error) {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+	if sub.closed {
+		return true, nil
+	}
+	expirationTime := sub.expiration.Load().(time.Time)
+	listen := time.Now().Before(expirationTime) && !sub.suspended.Load()
+	if sub.subscribed.Load() && !listen {
+		sub.cf()
+		sub.sub.Cancel()
+		sub.subscribed.Store(false)
+		log.Info("[Gossip] Unsubscribed from topic", "topic", sub.sub.Topic())
+		sub.s.updateENROnSubscription(sub.sub.Topic(), false)
+		return false, nil
+	}
+	if !sub.subscribed.Load() && listen {
+		sub.sub, err = sub.topic.Subscribe()
+		if err != nil {
+			return false, err
+		}
+		var sctx context.Context
+		sctx, sub.cf = context.WithCancel(sub.ctx)
+		go sub.run(sctx, sub.sub, sub.sub.Topic())
+		sub.subscribed.Store(true)
+		sub.s.updateENROnSubscription(sub.sub.Topic(), true)
+		log.Info("[Gossip] Subscribed to topic", "topic", sub.sub.Topic())
+	}
+	return false, nil
+}
+
 func (sub *GossipSubscription) OverwriteSubscriptionExpiry(expiry time.Time) {
 	if expiry.After(sub.expiration.Load().(time.Time)) {
 		sub.expiration.Store(expiry)
@@ -557,7 +667,9 @@ func (sub *GossipSubscription) OverwriteSubscriptionExpiry(expiry time.Time) {
 // calls the cancel func for the subscriber and closes the topic and sub
 func (s *GossipSubscription) Close() {
 	s.closeOnce.Do(func() {
-		close(s.stopCh)
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.closed = true
 		if s.cf != nil {
 			s.cf()
 		}
@@ -593,8 +705,6 @@ func (s *GossipSubscription) run(ctx context.Context, sub *pubsub.Subscription,
 		select {
 		case <-ctx.Done():
 			return
-		case <-s.stopCh:
-			return
 		default:
 			msg, err := sub.Next(ctx)
 			if err != nil {
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"math"
+
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/persistence/scoreboard"
 	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/libp2p/go-libp2p/core/network"
@@ -20,7 +23,11 @@ func (c *ConsensusHandlers) blobsSidecarsByRangeHandler(s network.Stream) error
 	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.DenebVersion); err != nil {
 		return err
 	}
-	if err := c.checkRateLimit(peerId, "blobSidecar", rateLimits.blobSidecarsLimit, int(req.Count)); err != nil {
+	available := c.scoreboard.CountAvailable(scoreboard.Blobs, req.StartSlot, req.Count)
+	if available == 0 {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavailablePrefix)
+	}
+	if err := c.checkRateLimit(peerId, "blobSidecar", rateLimits.blobSidecarsLimit, int(min(available, math.MaxInt32))); err != nil {
 		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
 		return err
 	}
@@ -33,6 +40,9 @@ func (c *ConsensusHandlers) blobsSidecarsByRangeHandler(s network.Stream) error
 
 	written := 0
 	for slot := req.StartSlot; slot < req.StartSlot+req.Count; slot++ {
+		if !c.scoreboard.Available(scoreboard.Blobs, slot) {
+			continue
+		}
 		blockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, slot)
 		if err != nil {
 			return err
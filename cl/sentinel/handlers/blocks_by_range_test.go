@@ -67,7 +67,7 @@ func TestBlocksByRootHandler(t *testing.T) {
 		nil,
 		beaconCfg,
 		ethClock,
-		nil, &mock_services.ForkChoiceStorageMock{}, nil, true,
+		nil, &mock_services.ForkChoiceStorageMock{}, nil, true, false,
 	)
 	c.Start()
 	req := &cltypes.BeaconBlocksByRangeRequest{
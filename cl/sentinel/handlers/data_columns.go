@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"io"
+	"math"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/cl/persistence/scoreboard"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+const maxDataColumnsThroughoutputPerRequest = 512
+
+// writeDataColumnSidecar writes a single response chunk, missing columns are skipped as allowed by the spec.
+func (c *ConsensusHandlers) writeDataColumnSidecar(w io.Writer, slot uint64, blockRoot libcommon.Hash, columnIndex uint64) (bool, error) {
+	has, err := c.blobsStorage.HasDataColumnSidecar(slot, blockRoot, columnIndex)
+	if err != nil || !has {
+		return false, err
+	}
+	version := c.beaconConfig.GetCurrentStateVersion(slot / c.beaconConfig.SlotsPerEpoch)
+	// Read the fork digest
+	forkDigest, err := c.ethClock.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(version)))
+	if err != nil {
+		return false, err
+	}
+	if _, err := w.Write([]byte{0}); err != nil {
+		return false, err
+	}
+	if _, err := w.Write(forkDigest[:]); err != nil {
+		return false, err
+	}
+	if err := c.blobsStorage.WriteDataColumnStream(w, slot, blockRoot, columnIndex); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRangeHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+
+	req := cltypes.NewDataColumnSidecarsByRangeRequest()
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+	available := c.scoreboard.CountAvailable(scoreboard.DataColumns, req.StartSlot, req.Count)
+	if available == 0 {
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavailablePrefix)
+	}
+	if err := c.checkRateLimit(peerId, "dataColumnSidecar", rateLimits.dataColumnSidecarsLimit, int(min(available, math.MaxInt32))); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := 0
+	for slot := req.StartSlot; slot < req.StartSlot+req.Count && written < maxDataColumnsThroughoutputPerRequest; slot++ {
+		if !c.scoreboard.Available(scoreboard.DataColumns, slot) {
+			continue
+		}
+		blockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, slot)
+		if err != nil {
+			return err
+		}
+		if blockRoot == (libcommon.Hash{}) {
+			continue
+		}
+		for i := 0; i < req.Columns.Length() && written < maxDataColumnsThroughoutputPerRequest; i++ {
+			ok, err := c.writeDataColumnSidecar(s, slot, blockRoot, req.Columns.Get(i))
+			if err != nil {
+				return err
+			}
+			if ok {
+				written++
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRootHandler(s network.Stream) error {
+	peerId := s.Conn().RemotePeer().String()
+
+	req := solid.NewStaticListSSZ[*cltypes.DataColumnIdentifier](int(c.beaconConfig.MaxRequestDataColumnSidecars), 40)
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+	if err := c.checkRateLimit(peerId, "dataColumnSidecar", rateLimits.dataColumnSidecarsLimit, req.Len()); err != nil {
+		ssz_snappy.EncodeAndWrite(s, &emptyString{}, RateLimitedPrefix)
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := 0
+	for i := 0; i < req.Len() && written < maxDataColumnsThroughoutputPerRequest; i++ {
+		id := req.Get(i)
+		slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, id.BlockRoot)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			continue
+		}
+		ok, err := c.writeDataColumnSidecar(s, *slot, id.BlockRoot, id.Index)
+		if err != nil {
+			return err
+		}
+		if ok {
+			written++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/cl/antiquary/tests"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication"
+	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/erigon/cl/sentinel/peers"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+func getTestDataColumnSidecar(blockHeader *cltypes.SignedBeaconBlockHeader, index uint64) *cltypes.DataColumnSidecar {
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = index
+	sidecar.SignedBlockHeader = blockHeader
+	sidecar.Column.Append(&cltypes.Cell{byte(index)})
+	sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{byte(index)})
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{byte(index)})
+	return sidecar
+}
+
+func TestDataColumnSidecarsByRangeHandler(t *testing.T) {
+	ctx := context.Background()
+
+	listenAddrHost := "/ip4/127.0.0.1/tcp/6127"
+	host, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost))
+	require.NoError(t, err)
+
+	listenAddrHost1 := "/ip4/127.0.0.1/tcp/6352"
+	host1, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost1))
+	require.NoError(t, err)
+
+	err = host.Connect(ctx, peer.AddrInfo{
+		ID:    host1.ID(),
+		Addrs: host1.Addrs(),
+	})
+	require.NoError(t, err)
+
+	peersPool := peers.NewPool()
+	blobDb := memdb.NewTestDB(t)
+	_, indiciesDB := setupStore(t)
+	store := tests.NewMockBlockReader()
+
+	tx, _ := indiciesDB.BeginRw(ctx)
+
+	startSlot := uint64(100)
+	count := uint64(10)
+
+	ethClock := getEthClock(t)
+	expBlocks := populateDatabaseWithBlocks(t, store, tx, startSlot, count)
+	h := expBlocks[0].SignedBeaconBlockHeader()
+	_, beaconCfg := clparams.GetConfigsByNetwork(1)
+	blobStorage := blob_storage.NewBlobStore(blobDb, afero.NewMemMapFs(), math.MaxUint64, beaconCfg, ethClock)
+	r, _ := h.Header.HashSSZ()
+	// we only custody columns 3 and 9, column 5 is requested but missing
+	sidecars := []*cltypes.DataColumnSidecar{getTestDataColumnSidecar(h, 3), getTestDataColumnSidecar(h, 9)}
+	for _, sidecar := range sidecars {
+		require.NoError(t, blobStorage.WriteDataColumnSidecar(ctx, r, sidecar))
+	}
+
+	tx.Commit()
+
+	c := NewConsensusHandlers(
+		ctx,
+		store,
+		indiciesDB,
+		host,
+		peersPool,
+		&clparams.NetworkConfig{},
+		nil,
+		beaconCfg,
+		ethClock,
+		nil, &mock_services.ForkChoiceStorageMock{}, blobStorage, true, true,
+	)
+	c.Start()
+	req := cltypes.NewDataColumnSidecarsByRangeRequest()
+	req.StartSlot = h.Header.Slot
+	req.Count = 1
+	req.Columns.Append(3)
+	req.Columns.Append(5)
+	req.Columns.Append(9)
+
+	var reqBuf bytes.Buffer
+	require.NoError(t, ssz_snappy.EncodeAndWrite(&reqBuf, req))
+
+	reqData := libcommon.CopyBytes(reqBuf.Bytes())
+	stream, err := host1.NewStream(ctx, host.ID(), protocol.ID(communication.DataColumnSidecarsByRangeProtocolV1))
+	require.NoError(t, err)
+
+	_, err = stream.Write(reqData)
+	require.NoError(t, err)
+
+	firstByte := make([]byte, 1)
+	_, err = stream.Read(firstByte)
+	require.NoError(t, err)
+	require.Equal(t, firstByte[0], byte(0))
+
+	for i := 0; i < len(sidecars); i++ {
+		forkDigest := make([]byte, 4)
+		_, err := stream.Read(forkDigest)
+		if err != nil && err != io.EOF {
+			require.NoError(t, err)
+		}
+
+		encodedLn, _, err := ssz_snappy.ReadUvarint(stream)
+		require.NoError(t, err)
+
+		raw := make([]byte, encodedLn)
+		sr := snappy.NewReader(stream)
+		bytesRead := 0
+		for bytesRead < int(encodedLn) {
+			n, err := sr.Read(raw[bytesRead:])
+			require.NoError(t, err)
+			bytesRead += n
+		}
+		version, err := ethClock.StateVersionByForkDigest(utils.Uint32ToBytes4(binary.BigEndian.Uint32(forkDigest)))
+		require.NoError(t, err)
+
+		sidecar := cltypes.NewDataColumnSidecar()
+		require.NoError(t, sidecar.DecodeSSZ(raw, int(version)))
+		require.Equal(t, sidecars[i].Index, sidecar.Index)
+		require.Equal(t, sidecars[i].Column.Get(0), sidecar.Column.Get(0))
+		stream.Read(make([]byte, 1))
+	}
+
+	_, err = stream.Read(make([]byte, 1))
+	if err != io.EOF {
+		t.Fatal("Stream is not empty")
+	}
+
+	defer indiciesDB.Close()
+	defer tx.Rollback()
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
+	"github.com/ledgerwatch/erigon/cl/persistence/scoreboard"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/sentinel/communication"
 	"github.com/ledgerwatch/erigon/cl/sentinel/handshake"
@@ -50,6 +51,7 @@ type RateLimits struct {
 	beaconBlocksByRootLimit  int
 	lightClientLimit         int
 	blobSidecarsLimit        int
+	dataColumnSidecarsLimit  int
 }
 
 const (
@@ -58,6 +60,8 @@ const (
 	blockHandlerRateLimit = 200
 	lightClientRateLimit  = 500
 	blobHandlerRateLimit  = 50 // very generous here.
+	// data column requests are counted per slot or identifier, a single block alone may ask for all 128 columns.
+	dataColumnHandlerRateLimit = 1024
 )
 
 var rateLimits = RateLimits{
@@ -70,6 +74,7 @@ var rateLimits = RateLimits{
 	beaconBlocksByRootLimit:  blockHandlerRateLimit,
 	lightClientLimit:         lightClientRateLimit,
 	blobSidecarsLimit:        blobHandlerRateLimit,
+	dataColumnSidecarsLimit:  dataColumnHandlerRateLimit,
 }
 
 type ConsensusHandlers struct {
@@ -88,18 +93,21 @@ type ConsensusHandlers struct {
 	me                 *enode.LocalNode
 	netCfg             *clparams.NetworkConfig
 	blobsStorage       blob_storage.BlobStorage
+	scoreboard         *scoreboard.Scoreboard
 
-	enableBlocks bool
+	enableBlocks  bool
+	enablePeerDAS bool
 }
 
 const (
-	SuccessfulResponsePrefix = 0x00
-	RateLimitedPrefix        = 0x01
-	ResourceUnavaiablePrefix = 0x02
+	SuccessfulResponsePrefix  = 0x00
+	RateLimitedPrefix         = 0x01
+	ServerErrorPrefix         = 0x02
+	ResourceUnavailablePrefix = 0x03
 )
 
 func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotReader, indiciesDB kv.RoDB, host host.Host,
-	peers *peers.Pool, netCfg *clparams.NetworkConfig, me *enode.LocalNode, beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, hs *handshake.HandShaker, forkChoiceReader forkchoice.ForkChoiceStorageReader, blobsStorage blob_storage.BlobStorage, enabledBlocks bool) *ConsensusHandlers {
+	peers *peers.Pool, netCfg *clparams.NetworkConfig, me *enode.LocalNode, beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, hs *handshake.HandShaker, forkChoiceReader forkchoice.ForkChoiceStorageReader, blobsStorage blob_storage.BlobStorage, enabledBlocks, enabledPeerDAS bool) *ConsensusHandlers {
 	c := &ConsensusHandlers{
 		host:               host,
 		hs:                 hs,
@@ -111,11 +119,17 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		peerRateLimits:     sync.Map{},
 		punishmentEndTimes: sync.Map{},
 		enableBlocks:       enabledBlocks,
+		enablePeerDAS:      enabledPeerDAS,
 		forkChoiceReader:   forkChoiceReader,
 		me:                 me,
 		netCfg:             netCfg,
 		blobsStorage:       blobsStorage,
 	}
+	if blobsStorage != nil {
+		c.scoreboard = blobsStorage.Scoreboard()
+	} else {
+		c.scoreboard = scoreboard.New()
+	}
 
 	hm := map[string]func(s network.Stream) error{
 		communication.PingProtocolV1:                        c.pingHandler,
@@ -135,6 +149,10 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		hm[communication.BlobSidecarByRangeProtocolV1] = c.blobsSidecarsByRangeHandler
 		hm[communication.BlobSidecarByRootProtocolV1] = c.blobsSidecarsByIdsHandler
 	}
+	if c.enableBlocks && c.enablePeerDAS {
+		hm[communication.DataColumnSidecarsByRangeProtocolV1] = c.dataColumnSidecarsByRangeHandler
+		hm[communication.DataColumnSidecarsByRootProtocolV1] = c.dataColumnSidecarsByRootHandler
+	}
 
 	c.handlers = map[protocol.ID]network.StreamHandler{}
 	for k, v := range hm {
@@ -174,6 +192,9 @@ func (c *ConsensusHandlers) Start() {
 	for id, handler := range c.handlers {
 		c.host.SetStreamHandler(id, handler)
 	}
+	if c.enableBlocks {
+		go c.syncScoreboard()
+	}
 }
 
 func (c *ConsensusHandlers) wrapStreamHandler(name string, fn func(s network.Stream) error) func(s network.Stream) {
@@ -83,7 +83,7 @@ func TestPing(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -137,7 +137,7 @@ func TestGoodbye(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -198,7 +198,7 @@ func TestMetadataV2(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -257,7 +257,7 @@ func TestMetadataV1(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -322,7 +322,7 @@ func TestStatus(t *testing.T) {
 		testLocalNode(),
 		beaconCfg,
 		getEthClock(t),
-		hs, f, nil, true,
+		hs, f, nil, true, false,
 	)
 	c.Start()
 
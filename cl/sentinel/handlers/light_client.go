@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
 	"github.com/ledgerwatch/erigon/cl/sentinel/communication/ssz_snappy"
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/libp2p/go-libp2p/core/network"
@@ -18,7 +19,7 @@ func (c *ConsensusHandlers) optimisticLightClientUpdateHandler(s network.Stream)
 	}
 	lc := c.forkChoiceReader.NewestLightClientUpdate()
 	if lc == nil {
-		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavailablePrefix)
 	}
 	version := lc.AttestedHeader.Version()
 	// Read the fork digest
@@ -43,7 +44,7 @@ func (c *ConsensusHandlers) finalityLightClientUpdateHandler(s network.Stream) e
 	}
 	lc := c.forkChoiceReader.NewestLightClientUpdate()
 	if lc == nil {
-		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavailablePrefix)
 	}
 
 	forkDigest, err := c.ethClock.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(lc.AttestedHeader.Version())))
@@ -74,7 +75,7 @@ func (c *ConsensusHandlers) lightClientBootstrapHandler(s network.Stream) error
 
 	lc, has := c.forkChoiceReader.GetLightClientBootstrap(root.Root)
 	if !has {
-		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavaiablePrefix)
+		return ssz_snappy.EncodeAndWrite(s, &emptyString{}, ResourceUnavailablePrefix)
 	}
 
 	forkDigest, err := c.ethClock.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(lc.Header.Version())))
@@ -98,25 +99,34 @@ func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(s network.Stream) e
 		return err
 	}
 
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	lightClientUpdates := make([]*cltypes.LightClientUpdate, 0, maxLightClientsPerRequest)
 
 	endPeriod := req.StartPeriod + req.Count
 	currentSlot := c.ethClock.GetCurrentSlot()
-	if endPeriod > c.beaconConfig.SyncCommitteePeriod(currentSlot) {
+	if endPeriod > c.beaconConfig.SyncCommitteePeriod(currentSlot)+1 {
 		endPeriod = c.beaconConfig.SyncCommitteePeriod(currentSlot) + 1
 	}
 
-	notFoundPrev := false
-	// Fetch from [start_period, start_period + count]
+	// Fetch from [start_period, start_period + count), returned updates must be consecutive so stop at the first gap
 	for i := req.StartPeriod; i < endPeriod; i++ {
 		update, has := c.forkChoiceReader.GetLightClientUpdate(i)
 		if !has {
-			notFoundPrev = true
-			continue
+			// older periods are pruned from the fork graph, but are kept in the db
+			if update, err = beacon_indicies.ReadLightClientUpdate(tx, i); err != nil {
+				return err
+			}
 		}
-		if notFoundPrev {
-			lightClientUpdates = lightClientUpdates[0:]
-			notFoundPrev = false
+		if update == nil {
+			if len(lightClientUpdates) > 0 {
+				break
+			}
+			continue
 		}
 
 		lightClientUpdates = append(lightClientUpdates, update)
@@ -71,7 +71,7 @@ func TestLightClientOptimistic(t *testing.T) {
 		nil,
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -141,7 +141,7 @@ func TestLightClientFinality(t *testing.T) {
 		nil,
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -218,7 +218,7 @@ func TestLightClientBootstrap(t *testing.T) {
 		nil,
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
@@ -301,7 +301,7 @@ func TestLightClientUpdates(t *testing.T) {
 		nil,
 		beaconCfg,
 		ethClock,
-		nil, f, nil, true,
+		nil, f, nil, true, false,
 	)
 	c.Start()
 
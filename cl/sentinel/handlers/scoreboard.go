@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+// syncScoreboard - keeps the blocks of the scoreboard in sync with the canonical chain, once per slot.
+// Blobs and data columns are marked by the blob storage as they are written.
+func (c *ConsensusHandlers) syncScoreboard() {
+	ticker := time.NewTicker(time.Duration(c.beaconConfig.SecondsPerSlot) * time.Second)
+	defer ticker.Stop()
+	for {
+		if err := c.syncScoreboardBlocks(); err != nil {
+			log.Debug("[Sentinel] could not sync the scoreboard", "err", err)
+		}
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ConsensusHandlers) syncScoreboardBlocks() error {
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return c.scoreboard.SyncBlocks(c.ctx, tx, c.beaconDB.FrozenSlots(), c.forkChoiceReader.FinalizedSlot())
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cl/das"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/sentinel/handlers"
@@ -123,6 +124,11 @@ func (s *Sentinel) createLocalNode(
 	return localNode, nil
 }
 
+// CustodySubnets returns the data column sidecar subnets of the columns we custody, derived from our node id.
+func (s *Sentinel) CustodySubnets() ([]uint64, error) {
+	return das.CustodySubnets(s.cfg.BeaconConfig, s.listener.LocalNode().ID(), s.cfg.BeaconConfig.CustodyRequirement)
+}
+
 func (s *Sentinel) SetStatus(status *cltypes.Status) {
 	s.handshaker.SetStatus(status)
 }
@@ -176,7 +182,7 @@ func (s *Sentinel) createListener() (*discover.UDPv5, error) {
 	if err != nil {
 		return nil, err
 	}
-	handlers.NewConsensusHandlers(s.ctx, s.blockReader, s.indiciesDB, s.host, s.peers, s.cfg.NetworkConfig, localNode, s.cfg.BeaconConfig, s.ethClock, s.handshaker, s.forkChoiceReader, s.blobStorage, s.cfg.EnableBlocks).Start()
+	handlers.NewConsensusHandlers(s.ctx, s.blockReader, s.indiciesDB, s.host, s.peers, s.cfg.NetworkConfig, localNode, s.cfg.BeaconConfig, s.ethClock, s.handshaker, s.forkChoiceReader, s.blobStorage, s.cfg.EnableBlocks, s.cfg.peerDASEnabled()).Start()
 
 	return net, err
 }
@@ -297,10 +303,11 @@ func (s *Sentinel) Start() error {
 			s.peers.RemovePeer(peerId)
 		},
 	})
-	s.subManager = NewGossipManager(s.ctx)
+	s.subManager = NewGossipManager(s.ctx, s.ethClock)
 
 	go s.listenForPeers()
 	go s.forkWatcher()
+	go s.backboneSubnets()
 
 	return nil
 }
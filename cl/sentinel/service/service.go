@@ -106,6 +106,11 @@ func (s *SentinelServer) PublishGossip(_ context.Context, msg *sentinelrpc.Gossi
 				return nil, fmt.Errorf("subnetId is required for blob sidecar")
 			}
 			subscription = manager.GetMatchingSubscription(gossip.TopicNameBlobSidecar(*msg.SubnetId))
+		case gossip.IsTopicDataColumnSidecar(msg.Name):
+			if msg.SubnetId == nil {
+				return nil, fmt.Errorf("subnetId is required for data column sidecar")
+			}
+			subscription = manager.GetMatchingSubscription(gossip.TopicNameDataColumnSidecar(*msg.SubnetId))
 		case gossip.IsTopicSyncCommittee(msg.Name):
 			if msg.SubnetId == nil {
 				return nil, fmt.Errorf("subnetId is required for sync_committee")
@@ -348,11 +353,9 @@ func (s *SentinelServer) SetSubscribeExpiry(ctx context.Context, expiryReq *sent
 		topic      = expiryReq.GetTopic()
 		expiryTime = time.Unix(int64(expiryReq.GetExpiryUnixSecs()), 0)
 	)
-	subs := s.sentinel.GossipManager().GetMatchingSubscription(topic)
-	if subs == nil {
+	if !s.sentinel.GossipManager().OverwriteSubscriptionExpiry(topic, expiryTime) {
 		return nil, fmt.Errorf("no such subscription")
 	}
-	subs.OverwriteSubscriptionExpiry(expiryTime)
 	return &sentinelrpc.EmptyMessage{}, nil
 }
 
@@ -396,6 +399,7 @@ func (s *SentinelServer) handleGossipPacket(pkt *sentinel.GossipMessage) error {
 		// TopicNamePrefixBlobSidecar
 		// TopicNamePrefixBeaconAttestation
 		// TopicNamePrefixSyncCommittee
+		// TopicNamePrefixDataColumnSidecar
 		subnet := extractSubnetIndexByGossipTopic(gossipTopic)
 		if subnet < 0 {
 			break
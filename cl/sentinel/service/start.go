@@ -2,17 +2,12 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"net"
-	"strings"
-	"time"
 
-	"github.com/ledgerwatch/erigon/cl/gossip"
 	"github.com/ledgerwatch/erigon/cl/persistence/blob_storage"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 	"github.com/ledgerwatch/erigon/cl/sentinel"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
-	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
 
 	"github.com/ledgerwatch/erigon-lib/direct"
@@ -32,26 +27,6 @@ type ServerConfig struct {
 	InitialStatus *cltypes.Status
 }
 
-func generateSubnetsTopics(template string, maxIds int) []sentinel.GossipTopic {
-	topics := make([]sentinel.GossipTopic, 0, maxIds)
-	for i := 0; i < maxIds; i++ {
-		topics = append(topics, sentinel.GossipTopic{
-			Name:     fmt.Sprintf(template, i),
-			CodecStr: sentinel.SSZSnappyCodec,
-		})
-	}
-	return topics
-}
-
-func getExpirationForTopic(topic string) time.Time {
-	if strings.Contains(topic, "beacon_attestation") ||
-		(strings.Contains(topic, "sync_committee_") && !strings.Contains(topic, gossip.TopicNameSyncCommitteeContributionAndProof)) {
-		return time.Unix(0, 0)
-	}
-
-	return time.Unix(0, math.MaxInt64)
-}
-
 func createSentinel(
 	cfg *sentinel.SentinelConfig,
 	blockReader freezeblocks.BeaconSnapshotReader,
@@ -77,49 +52,8 @@ func createSentinel(
 	if err := sent.Start(); err != nil {
 		return nil, err
 	}
-	gossipTopics := []sentinel.GossipTopic{
-		sentinel.BeaconBlockSsz,
-		//sentinel.VoluntaryExitSsz,
-		sentinel.ProposerSlashingSsz,
-		sentinel.AttesterSlashingSsz,
-		sentinel.BlsToExecutionChangeSsz,
-		////sentinel.LightClientFinalityUpdateSsz,
-		////sentinel.LightClientOptimisticUpdateSsz,
-		sentinel.SyncCommitteeContributionAndProofSsz,
-		sentinel.BeaconAggregateAndProofSsz,
-	}
-	gossipTopics = append(
-		gossipTopics,
-		generateSubnetsTopics(
-			gossip.TopicNamePrefixBlobSidecar,
-			int(cfg.BeaconConfig.MaxBlobsPerBlock),
-		)...)
-	gossipTopics = append(
-		gossipTopics,
-		generateSubnetsTopics(
-			gossip.TopicNamePrefixBeaconAttestation,
-			int(cfg.NetworkConfig.AttestationSubnetCount),
-		)...)
-	gossipTopics = append(
-		gossipTopics,
-		generateSubnetsTopics(
-			gossip.TopicNamePrefixSyncCommittee,
-			int(cfg.BeaconConfig.SyncCommitteeSubnetCount),
-		)...)
-
-	for _, v := range gossipTopics {
-		if err := sent.Unsubscribe(v); err != nil {
-			logger.Error("[Sentinel] failed to start sentinel", "err", err)
-			continue
-		}
-
-		// now lets separately connect to the gossip topics. this joins the room
-		subscriber, err := sent.SubscribeGossip(v, getExpirationForTopic(v.Name)) // Listen forever.
-		if err != nil {
-			logger.Error("[Sentinel] failed to start sentinel", "err", err)
-		}
-		// actually start the subscription, aka listening and sending packets to the sentinel recv channel
-		subscriber.Listen()
+	if err := sent.ManageGossipTopics(validatorTopics); err != nil {
+		return nil, err
 	}
 	return sent, nil
 }
@@ -0,0 +1,205 @@
+package sentinel
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/gossip"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/shuffling"
+	"github.com/ledgerwatch/erigon/cl/sentinel/peers"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/enr"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// ComputeSubscribedSubnets implements compute_subscribed_subnets: the long-lived (backbone) attestation subnets
+// every node subscribes to, regardless of attached validators. They are derived from the node id and rotate
+// every EPOCHS_PER_SUBNET_SUBSCRIPTION epochs.
+func ComputeSubscribedSubnets(netCfg *clparams.NetworkConfig, beaconCfg *clparams.BeaconChainConfig, nodeID enode.ID, epoch uint64) ([]uint64, error) {
+	if netCfg.EpochsPerSubnetSubscription == 0 || netCfg.AttestationSubnetCount == 0 {
+		return nil, nil
+	}
+	subnets := make([]uint64, 0, netCfg.SubnetsPerNode)
+	for i := uint64(0); i < netCfg.SubnetsPerNode; i++ {
+		subnet, err := computeSubscribedSubnet(netCfg, beaconCfg, nodeID, epoch, i)
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, subnet)
+	}
+	slices.Sort(subnets)
+	return slices.Compact(subnets), nil
+}
+
+// def compute_subscribed_subnet(node_id: NodeID, epoch: Epoch, index: int) -> SubnetID:
+//
+//	node_id_prefix = node_id >> (NODE_ID_BITS - ATTESTATION_SUBNET_PREFIX_BITS)
+//	node_offset = node_id % EPOCHS_PER_SUBNET_SUBSCRIPTION
+//	permutation_seed = hash(uint_to_bytes(uint64((epoch + node_offset) // EPOCHS_PER_SUBNET_SUBSCRIPTION)))
+//	permutated_prefix = compute_shuffled_index(node_id_prefix, 1 << ATTESTATION_SUBNET_PREFIX_BITS, permutation_seed)
+//	return SubnetID((permutated_prefix + index) % ATTESTATION_SUBNET_COUNT)
+func computeSubscribedSubnet(netCfg *clparams.NetworkConfig, beaconCfg *clparams.BeaconChainConfig, nodeID enode.ID, epoch, index uint64) (uint64, error) {
+	// node id is a big-endian uint256: the prefix bits fit into its most significant 8 bytes
+	nodeIDPrefix := binary.BigEndian.Uint64(nodeID[:8]) >> (64 - netCfg.AttestationSubnetPrefixBits)
+	nodeOffset := nodeIDModulo(nodeID, netCfg.EpochsPerSubnetSubscription)
+
+	var epochBytes [8]byte
+	binary.LittleEndian.PutUint64(epochBytes[:], (epoch+nodeOffset)/netCfg.EpochsPerSubnetSubscription)
+	permutationSeed := utils.Sha256(epochBytes[:])
+
+	permutatedPrefix, err := shuffling.ComputeShuffledIndex(beaconCfg, nodeIDPrefix, 1<<netCfg.AttestationSubnetPrefixBits, permutationSeed, nil, utils.Sha256)
+	if err != nil {
+		return 0, err
+	}
+	return (permutatedPrefix + index) % netCfg.AttestationSubnetCount, nil
+}
+
+// nodeIDModulo - node id (big-endian uint256) modulo m
+func nodeIDModulo(nodeID enode.ID, m uint64) uint64 {
+	var rem uint64
+	for _, b := range nodeID {
+		rem = (rem<<8 | uint64(b)) % m
+	}
+	return rem
+}
+
+// backboneSubnets keeps us subscribed to the long-lived attestation subnets and advertises them in the ENR
+// (and so in the metadata), short-lived subscriptions of validator duties are not advertised.
+func (s *Sentinel) backboneSubnets() {
+	ticker := time.NewTicker(s.oneSlotDuration())
+	defer ticker.Stop()
+
+	var advertised []uint64
+	for {
+		epoch := s.ethClock.GetCurrentEpoch()
+		subnets, err := ComputeSubscribedSubnets(s.cfg.NetworkConfig, s.cfg.BeaconConfig, s.listener.LocalNode().ID(), epoch)
+		if err != nil {
+			log.Warn("[Sentinel] Could not compute backbone subnets", "err", err)
+			return
+		}
+		// attestation subnets are not listened while syncing, don't advertise them
+		if s.isFarBehindHead() {
+			subnets = nil
+		}
+		// subscriptions are renewed every slot, the ones we rotated out of expire on their own
+		expiry := time.Now().Add(2 * s.oneEpochDuration())
+		for _, subnet := range subnets {
+			s.subManager.OverwriteSubscriptionExpiry(gossip.TopicNameBeaconAttestation(subnet), expiry)
+		}
+		if !slices.Equal(subnets, advertised) {
+			log.Info("[Sentinel] Backbone attestation subnets", "epoch", epoch, "subnets", subnets)
+			s.setENRAttSubnets(subnets)
+			advertised = subnets
+		}
+
+		if missing := s.subnetsWithoutPeers(subnets); len(missing) > 0 {
+			s.findPeersWithSubnets(missing)
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setENRAttSubnets advertises the attestation subnets in the ENR.
+func (s *Sentinel) setENRAttSubnets(subnets []uint64) {
+	s.metadataLock.Lock()
+	defer s.metadataLock.Unlock()
+	subnetField := bitfield.NewBitvector64()
+	for _, subnet := range subnets {
+		subnetField.SetBitAt(subnet, true)
+	}
+	s.listener.LocalNode().Set(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, &subnetField))
+}
+
+// advertisedAttSubnets returns the attestation subnets advertised in our ENR.
+func (s *Sentinel) advertisedAttSubnets() (subnets []uint64) {
+	s.metadataLock.Lock()
+	defer s.metadataLock.Unlock()
+	subnetField := bitfield.NewBitvector64()
+	if err := s.listener.LocalNode().Node().Load(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, &subnetField)); err != nil {
+		return nil
+	}
+	for _, subnet := range subnetField.BitIndices() {
+		subnets = append(subnets, uint64(subnet))
+	}
+	return subnets
+}
+
+// isNearPeerLimit - whether the remaining peer slots should go to the nodes serving subnets we lack peers in.
+func (s *Sentinel) isNearPeerLimit() bool {
+	active, _, _ := s.GetPeersCount()
+	return active >= peers.DefaultMaxPeers*9/10
+}
+
+// subnetsWithoutPeers returns the attestation subnets which have less peers than the low watermark of the mesh.
+func (s *Sentinel) subnetsWithoutPeers(subnets []uint64) (missing []uint64) {
+	for _, subnet := range subnets {
+		sub := s.subManager.GetMatchingSubscription(gossip.TopicNameBeaconAttestation(subnet))
+		if sub == nil || sub.topic == nil {
+			continue
+		}
+		if len(sub.topic.ListPeers()) < gossipSubDlo {
+			missing = append(missing, subnet)
+		}
+	}
+	return missing
+}
+
+// nodeHasAnySubnet checks if the node advertises any of the attestation subnets in its ENR.
+func (s *Sentinel) nodeHasAnySubnet(node *enode.Node, subnets []uint64) bool {
+	subnetField := bitfield.NewBitvector64()
+	if err := node.Load(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, &subnetField)); err != nil {
+		return false
+	}
+	for _, subnet := range subnets {
+		if subnet < subnetField.Len() && subnetField.BitAt(subnet) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPeersWithSubnets runs a discovery walk for at most one slot, connecting to the nodes which advertise
+// any of the subnets, up to MinimumPeersInSubnetSearch of them.
+func (s *Sentinel) findPeersWithSubnets(subnets []uint64) {
+	if s.cfg.NoDiscovery || s.HasTooManyPeers() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.oneSlotDuration())
+	defer cancel()
+
+	iterator := enode.Filter(s.listener.RandomNodes(), func(node *enode.Node) bool {
+		return s.nodeHasAnySubnet(node, subnets)
+	})
+	// Next blocks until a node is found, closing the iterator unblocks it
+	go func() {
+		<-ctx.Done()
+		iterator.Close()
+	}()
+
+	for found := uint64(0); found < s.cfg.NetworkConfig.MinimumPeersInSubnetSearch && ctx.Err() == nil && iterator.Next(); found++ {
+		node := iterator.Node()
+		if node.IP().IsPrivate() {
+			continue
+		}
+		peerInfo, _, err := convertToAddrInfo(node)
+		if err != nil {
+			continue
+		}
+		s.pidToEnr.Store(peerInfo.ID, node.String())
+		go func() {
+			if err := s.ConnectWithPeer(s.ctx, *peerInfo); err != nil {
+				log.Trace("[Sentinel] Could not connect with subnet peer", "err", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,87 @@
+package sentinel
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state/shuffling"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+)
+
+// computeSubscribedSubnetSpec follows the spec literally, with uint256 arithmetic
+func computeSubscribedSubnetSpec(t *testing.T, netCfg *clparams.NetworkConfig, beaconCfg *clparams.BeaconChainConfig, nodeID enode.ID, epoch, index uint64) uint64 {
+	id := new(big.Int).SetBytes(nodeID[:])
+	prefix := new(big.Int).Rsh(id, uint(256-netCfg.AttestationSubnetPrefixBits)).Uint64()
+	offset := new(big.Int).Mod(id, new(big.Int).SetUint64(netCfg.EpochsPerSubnetSubscription)).Uint64()
+
+	seed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seed, (epoch+offset)/netCfg.EpochsPerSubnetSubscription)
+	permutated, err := shuffling.ComputeShuffledIndex(beaconCfg, prefix, 1<<netCfg.AttestationSubnetPrefixBits, utils.Sha256(seed), nil, utils.Sha256)
+	require.NoError(t, err)
+	return (permutated + index) % netCfg.AttestationSubnetCount
+}
+
+func TestComputeSubscribedSubnets(t *testing.T) {
+	netCfg, beaconCfg := clparams.GetConfigsByNetwork(clparams.MainnetNetwork)
+
+	for i := 0; i < 64; i++ {
+		var nodeID enode.ID
+		h := utils.Sha256([]byte{byte(i)})
+		copy(nodeID[:], h[:])
+
+		for _, epoch := range []uint64{0, 1, 255, 256, 1_000_000} {
+			subnets, err := ComputeSubscribedSubnets(netCfg, beaconCfg, nodeID, epoch)
+			require.NoError(t, err)
+			require.Len(t, subnets, int(netCfg.SubnetsPerNode))
+
+			expected := map[uint64]struct{}{}
+			for index := uint64(0); index < netCfg.SubnetsPerNode; index++ {
+				expected[computeSubscribedSubnetSpec(t, netCfg, beaconCfg, nodeID, epoch, index)] = struct{}{}
+			}
+			for _, subnet := range subnets {
+				require.Contains(t, expected, subnet)
+				require.Less(t, subnet, netCfg.AttestationSubnetCount)
+			}
+		}
+	}
+
+	// subnets are kept for EPOCHS_PER_SUBNET_SUBSCRIPTION epochs, the rotation is staggered by node id
+	var nodeID enode.ID
+	nodeID[31] = 10
+	first, err := ComputeSubscribedSubnets(netCfg, beaconCfg, nodeID, 0)
+	require.NoError(t, err)
+	last, err := ComputeSubscribedSubnets(netCfg, beaconCfg, nodeID, netCfg.EpochsPerSubnetSubscription-11)
+	require.NoError(t, err)
+	require.Equal(t, first, last)
+
+	// the subnets of a node are consecutive
+	subnet0, err := computeSubscribedSubnet(netCfg, beaconCfg, nodeID, 0, 0)
+	require.NoError(t, err)
+	subnet1, err := computeSubscribedSubnet(netCfg, beaconCfg, nodeID, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, (subnet0+1)%netCfg.AttestationSubnetCount, subnet1)
+	require.ElementsMatch(t, []uint64{subnet0, subnet1}, first)
+
+	noSubnets := *netCfg
+	noSubnets.EpochsPerSubnetSubscription = 0
+	subnets, err := ComputeSubscribedSubnets(&noSubnets, beaconCfg, nodeID, 0)
+	require.NoError(t, err)
+	require.Empty(t, subnets)
+}
+
+func TestNodeIDModulo(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		var nodeID enode.ID
+		h := utils.Sha256([]byte{byte(i), 1})
+		copy(nodeID[:], h[:])
+		for _, m := range []uint64{1, 3, 256, 1_000_003} {
+			expected := new(big.Int).Mod(new(big.Int).SetBytes(nodeID[:]), new(big.Int).SetUint64(m)).Uint64()
+			require.Equal(t, expected, nodeIDModulo(nodeID, m))
+		}
+	}
+}
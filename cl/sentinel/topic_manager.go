@@ -0,0 +1,192 @@
+package sentinel
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/gossip"
+	"github.com/ledgerwatch/log/v3"
+)
+
+const (
+	// forkTransitionEpochs - topics of the next fork are subscribed this many epochs before its activation
+	// and topics of the previous fork are kept this many epochs after it
+	forkTransitionEpochs = 2
+	// syncDistanceEpochs - attestations can't be validated while the head is behind the clock for more epochs,
+	// attestation subnets are not listened till then
+	syncDistanceEpochs = 2
+)
+
+// TopicManager decides which gossip topics the node listens to:
+//   - node role: sync committee subnets are joined only by nodes serving validators
+//   - fork schedule: topics are joined on the fork digests of gossipForkVersions, see forkWatcher
+//   - sync status: attestation subnets are suspended while the node is far behind the head
+//   - validator duties and backbone subnets: extend expiry of the subscriptions, see GossipManager.OverwriteSubscriptionExpiry
+type TopicManager struct {
+	s         *Sentinel
+	validator bool
+	syncing   atomic.Bool
+}
+
+// ManageGossipTopics joins the gossip topics of the node role and keeps the subscriptions up to date with the sync status.
+func (s *Sentinel) ManageGossipTopics(validator bool) error {
+	m := &TopicManager{s: s, validator: validator}
+	topics, err := m.topics()
+	if err != nil {
+		return err
+	}
+	digests, err := s.gossipDigests()
+	if err != nil {
+		return err
+	}
+	syncing := s.isFarBehindHead()
+	m.syncing.Store(syncing)
+	for _, topic := range topics {
+		for _, digest := range digests {
+			if _, err := s.ensureSubscription(topic, digest, defaultTopicExpiry(topic.Name), syncing && gossip.IsTopicBeaconAttestation(topic.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	go m.loop()
+	return nil
+}
+
+// topics - topics joined at every fork digest, short-lived subscriptions of validator duties are joined but not listened
+func (m *TopicManager) topics() ([]GossipTopic, error) {
+	cfg := m.s.cfg
+	topics := []GossipTopic{
+		BeaconBlockSsz,
+		//VoluntaryExitSsz,
+		ProposerSlashingSsz,
+		AttesterSlashingSsz,
+		BlsToExecutionChangeSsz,
+		////LightClientFinalityUpdateSsz,
+		////LightClientOptimisticUpdateSsz,
+		SyncCommitteeContributionAndProofSsz,
+		BeaconAggregateAndProofSsz,
+	}
+	topics = append(topics, GossipSidecarTopics(cfg.BeaconConfig.MaxBlobsPerBlock)...)
+	for subnet := uint64(0); subnet < cfg.NetworkConfig.AttestationSubnetCount; subnet++ {
+		topics = append(topics, GossipTopic{Name: gossip.TopicNameBeaconAttestation(subnet), CodecStr: SSZSnappyCodec})
+	}
+	if m.validator {
+		for subnet := 0; subnet < int(cfg.BeaconConfig.SyncCommitteeSubnetCount); subnet++ {
+			topics = append(topics, GossipTopic{Name: gossip.TopicNameSyncCommittee(subnet), CodecStr: SSZSnappyCodec})
+		}
+	}
+	// PeerDAS: only the subnets of the columns we custody
+	if cfg.peerDASEnabled() {
+		custodySubnets, err := m.s.CustodySubnets()
+		if err != nil {
+			return nil, err
+		}
+		for _, subnet := range custodySubnets {
+			topics = append(topics, GossipTopic{Name: gossip.TopicNameDataColumnSidecar(subnet), CodecStr: SSZSnappyCodec})
+		}
+	}
+	return topics, nil
+}
+
+// defaultTopicExpiry - subnets of validator duties are not listened until subscribed by duties, the rest forever
+func defaultTopicExpiry(topic string) time.Time {
+	if gossip.IsTopicBeaconAttestation(topic) ||
+		(strings.Contains(topic, "sync_committee_") && !strings.Contains(topic, gossip.TopicNameSyncCommitteeContributionAndProof)) {
+		return time.Unix(0, 0)
+	}
+
+	return time.Unix(0, math.MaxInt64)
+}
+
+func (m *TopicManager) loop() {
+	ticker := time.NewTicker(m.s.oneSlotDuration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.s.ctx.Done():
+			return
+		case <-ticker.C:
+			m.updateSyncStatus()
+		}
+	}
+}
+
+// updateSyncStatus suspends attestation subnets while the node is far behind the head
+func (m *TopicManager) updateSyncStatus() {
+	syncing := m.s.isFarBehindHead()
+	if m.syncing.Swap(syncing) != syncing {
+		log.Info("[Sentinel] Attestation subnets", "suspended", syncing)
+	}
+	m.s.subManager.subscriptions.Range(func(_, value interface{}) bool {
+		sub := value.(*GossipSubscription)
+		if gossip.IsTopicBeaconAttestation(sub.gossip_topic.Name) {
+			sub.suspended.Store(syncing)
+		}
+		return true
+	})
+}
+
+// isFarBehindHead - whether the head is behind the clock for more than syncDistanceEpochs
+func (s *Sentinel) isFarBehindHead() bool {
+	if s.forkChoiceReader == nil {
+		return false
+	}
+	_, headSlot, err := s.forkChoiceReader.GetHead()
+	if err != nil {
+		return false
+	}
+	return s.ethClock.GetCurrentSlot() > headSlot+syncDistanceEpochs*s.cfg.BeaconConfig.SlotsPerEpoch
+}
+
+// gossipForkVersions returns the versions of the forks whose topics are subscribed at the epoch: the current fork,
+// the next one if it's activated within transitionEpochs and the previous one during transitionEpochs after the current one.
+func gossipForkVersions(schedule map[common.Bytes4]uint64, epoch, transitionEpochs uint64) []common.Bytes4 {
+	type fork struct {
+		version common.Bytes4
+		epoch   uint64
+	}
+	forks := make([]fork, 0, len(schedule))
+	for version, forkEpoch := range schedule {
+		forks = append(forks, fork{version: version, epoch: forkEpoch})
+	}
+	sort.Slice(forks, func(i, j int) bool {
+		if forks[i].epoch != forks[j].epoch {
+			return forks[i].epoch < forks[j].epoch
+		}
+		return bytes.Compare(forks[i].version[:], forks[j].version[:]) < 0
+	})
+
+	current := -1
+	for i, f := range forks {
+		if f.epoch > epoch {
+			break
+		}
+		current = i
+	}
+	if current < 0 {
+		return nil
+	}
+	versions := []common.Bytes4{forks[current].version}
+	if epoch-forks[current].epoch < transitionEpochs {
+		// forks activated at the same epoch as the current one never were current
+		for previous := current - 1; previous >= 0; previous-- {
+			if forks[previous].epoch < forks[current].epoch {
+				versions = append(versions, forks[previous].version)
+				break
+			}
+		}
+	}
+	if next := current + 1; next < len(forks) && forks[next].epoch-epoch <= transitionEpochs {
+		// the last of the forks activated at the same epoch
+		for next+1 < len(forks) && forks[next+1].epoch == forks[next].epoch {
+			next++
+		}
+		versions = append(versions, forks[next].version)
+	}
+	return versions
+}
@@ -0,0 +1,61 @@
+package sentinel
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/gossip"
+)
+
+func TestGossipForkVersions(t *testing.T) {
+	genesis, altair, bellatrix, capella := common.Bytes4{0}, common.Bytes4{1}, common.Bytes4{2}, common.Bytes4{3}
+	schedule := map[common.Bytes4]uint64{
+		genesis:   0,
+		altair:    10,
+		bellatrix: 20,
+		capella:   math.MaxUint64,
+	}
+
+	tests := []struct {
+		epoch    uint64
+		expected []common.Bytes4
+	}{
+		{0, []common.Bytes4{genesis}},
+		{7, []common.Bytes4{genesis}},
+		{8, []common.Bytes4{genesis, altair}}, // next fork topics are subscribed in advance
+		{10, []common.Bytes4{altair, genesis}},
+		{11, []common.Bytes4{altair, genesis}}, // previous fork topics are kept for a while
+		{12, []common.Bytes4{altair}},
+		{18, []common.Bytes4{altair, bellatrix}},
+		{1_000_000, []common.Bytes4{bellatrix}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, gossipForkVersions(schedule, tt.epoch, 2), "epoch %d", tt.epoch)
+	}
+
+	// forks activated at the same epoch: only the last of them is ever current
+	schedule = map[common.Bytes4]uint64{
+		genesis:   0,
+		altair:    0,
+		bellatrix: 5,
+		capella:   5,
+	}
+	require.Equal(t, []common.Bytes4{altair}, gossipForkVersions(schedule, 0, 2))
+	require.Equal(t, []common.Bytes4{altair, capella}, gossipForkVersions(schedule, 3, 2))
+	require.Equal(t, []common.Bytes4{capella, altair}, gossipForkVersions(schedule, 5, 2))
+
+	require.Empty(t, gossipForkVersions(map[common.Bytes4]uint64{genesis: 10}, 0, 2))
+}
+
+func TestDefaultTopicExpiry(t *testing.T) {
+	forever := time.Unix(0, math.MaxInt64)
+	require.Equal(t, forever, defaultTopicExpiry(gossip.TopicNameBeaconBlock))
+	require.Equal(t, forever, defaultTopicExpiry(gossip.TopicNameSyncCommitteeContributionAndProof))
+	require.Equal(t, forever, defaultTopicExpiry(gossip.TopicNameBlobSidecar(1)))
+	require.True(t, defaultTopicExpiry(gossip.TopicNameBeaconAttestation(3)).Before(time.Now()))
+	require.True(t, defaultTopicExpiry(gossip.TopicNameSyncCommittee(1)).Before(time.Now()))
+}
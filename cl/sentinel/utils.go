@@ -120,26 +120,6 @@ func (s *Sentinel) inMeshCap() float64 {
 	return float64((3600 * time.Second) / s.oneSlotDuration())
 }
 
-// updateENRAttSubnets calls the ENR to notify other peers their attnets preferences.
-func (s *Sentinel) updateENRAttSubnets(subnetIndex int, on bool) {
-	subnetField := bitfield.NewBitvector64()
-	if err := s.listener.LocalNode().Node().Load(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, &subnetField)); err != nil {
-		log.Error("[Sentinel] Could not load attSubnetKey", "err", err)
-		return
-	}
-	subnetField = common.Copy(subnetField)
-	if len(subnetField) <= subnetIndex/8 {
-		log.Error("[Sentinel] Subnet index out of range", "subnetIndex", subnetIndex, "len", len(subnetField))
-		return
-	}
-	if on {
-		subnetField[subnetIndex/8] |= 1 << (subnetIndex % 8)
-	} else {
-		subnetField[subnetIndex/8] &^= 1 << (subnetIndex % 8)
-	}
-	s.listener.LocalNode().Set(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, &subnetField))
-}
-
 // updateENRSyncNets calls the ENR to notify other peers their attnets preferences.
 func (s *Sentinel) updateENRSyncNets(subnetIndex int, on bool) {
 	subnetField := bitfield.NewBitvector4()
@@ -171,13 +151,7 @@ func (s *Sentinel) updateENROnSubscription(topicName string, subscribe bool) {
 		return
 	}
 	part := parts[3]
-	for i := 0; i < int(s.cfg.NetworkConfig.AttestationSubnetCount); i++ {
-		if part == gossip.TopicNameBeaconAttestation(uint64(i)) {
-			log.Info("[Sentinel] Update ENR on subscription", "subnet", i, "subscribe", subscribe, "type", "attestation")
-			s.updateENRAttSubnets(i, subscribe)
-			return
-		}
-	}
+	// only the long-lived attestation subnets are advertised, see backboneSubnets
 	for i := 0; i < int(s.cfg.BeaconConfig.SyncCommitteeSubnetCount); i++ {
 		if part == gossip.TopicNameSyncCommittee(i) {
 			log.Info("[Sentinel] Update ENR on subscription", "subnet", i, "subscribe", subscribe, "type", "syncnets")
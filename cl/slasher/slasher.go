@@ -0,0 +1,184 @@
+package slasher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+const (
+	// HistoryEpochs - how far back offences are looked for, older attestations and proposals are pruned
+	HistoryEpochs = 4096
+	// maxQueued - attestations and headers received while the queue is full are dropped
+	maxQueued = 1 << 16
+	// processInterval - queued attestations and headers are processed in batches, one transaction per batch
+	processInterval = time.Second
+)
+
+// Slasher records the attestations and block headers seen by the node and looks for slashable offences among them:
+// double and surround votes of attesters, double proposals of proposers. Found offences are turned into slashings
+// and handed to the Submitter. Only attestations and headers with verified signatures must be passed in, so that
+// the proofs are valid.
+type Slasher interface {
+	OnAttestation(att *cltypes.IndexedAttestation)
+	OnBlockHeader(header *cltypes.SignedBeaconBlockHeader)
+}
+
+// Submitter receives the slashings found, e.g. to put them into the operations pool and publish them to gossip.
+type Submitter interface {
+	SubmitAttesterSlashing(ctx context.Context, slashing *cltypes.AttesterSlashing) error
+	SubmitProposerSlashing(ctx context.Context, slashing *cltypes.ProposerSlashing) error
+}
+
+type slasher struct {
+	db        kv.RwDB
+	beaconCfg *clparams.BeaconChainConfig
+	submitter Submitter
+	logger    log.Logger
+
+	mu           sync.Mutex
+	attestations []*cltypes.IndexedAttestation
+	headers      []*cltypes.SignedBeaconBlockHeader
+
+	// latestEpoch - highest target epoch seen, the history window ends at it
+	latestEpoch uint64
+}
+
+// NewSlasher returns a no-op slasher if enabled is false. The slasher state is kept in db, next to the other Caplin
+// indices, and processed in the background until ctx is done.
+func NewSlasher(ctx context.Context, enabled bool, db kv.RwDB, beaconCfg *clparams.BeaconChainConfig, submitter Submitter, logger log.Logger) Slasher {
+	if !enabled {
+		return NewDummySlasher()
+	}
+	s := &slasher{
+		db:        db,
+		beaconCfg: beaconCfg,
+		submitter: submitter,
+		logger:    logger,
+	}
+	go s.loop(ctx)
+	return s
+}
+
+func (s *slasher) OnAttestation(att *cltypes.IndexedAttestation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.attestations) >= maxQueued {
+		return
+	}
+	s.attestations = append(s.attestations, att)
+}
+
+func (s *slasher) OnBlockHeader(header *cltypes.SignedBeaconBlockHeader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.headers) >= maxQueued {
+		return
+	}
+	s.headers = append(s.headers, header)
+}
+
+func (s *slasher) loop(ctx context.Context) {
+	ticker := time.NewTicker(processInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.mu.Lock()
+		attestations, headers := s.attestations, s.headers
+		s.attestations, s.headers = nil, nil
+		s.mu.Unlock()
+		if len(attestations) == 0 && len(headers) == 0 {
+			continue
+		}
+		if err := s.process(ctx, attestations, headers); err != nil {
+			s.logger.Warn("[Slasher] failed to process batch", "attestations", len(attestations), "headers", len(headers), "err", err)
+		}
+	}
+}
+
+// process records a batch of attestations and headers and submits the slashings found among them.
+func (s *slasher) process(ctx context.Context, attestations []*cltypes.IndexedAttestation, headers []*cltypes.SignedBeaconBlockHeader) error {
+	var (
+		attesterSlashings []*cltypes.AttesterSlashing
+		proposerSlashings []*cltypes.ProposerSlashing
+	)
+	if err := s.db.Update(ctx, func(tx kv.RwTx) error {
+		db := newSlasherDB(tx)
+		for _, att := range attestations {
+			if target := att.Data.Target().Epoch(); target > s.latestEpoch {
+				s.latestEpoch = target
+			}
+		}
+		minEpoch := s.minEpoch()
+		for _, att := range attestations {
+			found, err := db.processAttestation(att, minEpoch)
+			if err != nil {
+				return err
+			}
+			attesterSlashings = append(attesterSlashings, found...)
+		}
+		for _, header := range headers {
+			if header.Header.Slot < minEpoch*s.beaconCfg.SlotsPerEpoch {
+				continue
+			}
+			found, err := db.processHeader(header)
+			if err != nil {
+				return err
+			}
+			if found != nil {
+				proposerSlashings = append(proposerSlashings, found)
+			}
+		}
+		if err := db.flush(); err != nil {
+			return err
+		}
+		return db.prune(minEpoch, s.beaconCfg.SlotsPerEpoch)
+	}); err != nil {
+		return err
+	}
+
+	for _, slashing := range attesterSlashings {
+		s.logger.Info("[Slasher] attester slashing found",
+			"source1", slashing.Attestation_1.Data.Source().Epoch(), "target1", slashing.Attestation_1.Data.Target().Epoch(),
+			"source2", slashing.Attestation_2.Data.Source().Epoch(), "target2", slashing.Attestation_2.Data.Target().Epoch())
+		if err := s.submitter.SubmitAttesterSlashing(ctx, slashing); err != nil {
+			s.logger.Debug("[Slasher] failed to submit attester slashing", "err", err)
+		}
+	}
+	for _, slashing := range proposerSlashings {
+		s.logger.Info("[Slasher] proposer slashing found", "slot", slashing.Header1.Header.Slot, "proposer", slashing.Header1.Header.ProposerIndex)
+		if err := s.submitter.SubmitProposerSlashing(ctx, slashing); err != nil {
+			s.logger.Debug("[Slasher] failed to submit proposer slashing", "err", err)
+		}
+	}
+	return nil
+}
+
+// minEpoch - first epoch of the history window
+func (s *slasher) minEpoch() uint64 {
+	if s.latestEpoch < HistoryEpochs {
+		return 0
+	}
+	return s.latestEpoch - HistoryEpochs
+}
+
+type dummySlasher struct{}
+
+func NewDummySlasher() Slasher {
+	return &dummySlasher{}
+}
+
+func (d *dummySlasher) OnAttestation(*cltypes.IndexedAttestation) {}
+
+func (d *dummySlasher) OnBlockHeader(*cltypes.SignedBeaconBlockHeader) {}
@@ -0,0 +1,349 @@
+package slasher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// chunkSize - epochs per min/max span record of a validator
+const chunkSize = 16
+
+// The min-max span surround detection, per validator:
+//
+//	minSpan[e] = min(t - e) over the recorded attestations (s, t) with s > e
+//	maxSpan[e] = max(t - e) over the recorded attestations (s, t) with s < e
+//
+// A new attestation (s, t) surrounds a recorded one iff t - s > minSpan[s], and is surrounded by a recorded one iff
+// t - s < maxSpan[s]; the target of the recorded attestation is then s + minSpan[s] or s + maxSpan[s]. Distances are
+// stored as uint16, 0 stands for no attestation, the history window keeps them below HistoryEpochs.
+
+type chunkKey struct {
+	table string
+	key   [16]byte
+}
+
+type attestationRecord struct {
+	source          uint64
+	dataRoot        libcommon.Hash
+	attestationRoot libcommon.Hash
+}
+
+// slasherDB - slasher state within a transaction, span chunks are cached until flush.
+type slasherDB struct {
+	tx     kv.RwTx
+	chunks map[chunkKey][]byte
+}
+
+func newSlasherDB(tx kv.RwTx) *slasherDB {
+	return &slasherDB{tx: tx, chunks: map[chunkKey][]byte{}}
+}
+
+func epochKey(epoch, index uint64) []byte {
+	k := make([]byte, 16)
+	binary.BigEndian.PutUint64(k, epoch)
+	binary.BigEndian.PutUint64(k[8:], index)
+	return k
+}
+
+func (db *slasherDB) chunk(table string, validator, epoch uint64) ([]byte, error) {
+	var ck chunkKey
+	ck.table = table
+	copy(ck.key[:], epochKey(epoch/chunkSize, validator))
+	if c, ok := db.chunks[ck]; ok {
+		return c, nil
+	}
+	v, err := db.tx.GetOne(table, ck.key[:])
+	if err != nil {
+		return nil, err
+	}
+	c := make([]byte, chunkSize*2)
+	copy(c, v)
+	db.chunks[ck] = c
+	return c, nil
+}
+
+func (db *slasherDB) span(table string, validator, epoch uint64) (uint16, error) {
+	c, err := db.chunk(table, validator, epoch)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(c[epoch%chunkSize*2:]), nil
+}
+
+func (db *slasherDB) setSpan(table string, validator, epoch uint64, distance uint16) error {
+	c, err := db.chunk(table, validator, epoch)
+	if err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint16(c[epoch%chunkSize*2:], distance)
+	return nil
+}
+
+// updateSpans accounts the attestation (source, target) of validator in the spans of the epochs >= minEpoch.
+func (db *slasherDB) updateSpans(validator, source, target, minEpoch uint64) error {
+	// the min spans of epochs below source only shrink until a closer attestation was already recorded
+	for e := source; e > minEpoch; {
+		e--
+		distance := uint16(target - e)
+		current, err := db.span(kv.SlasherMinSpans, validator, e)
+		if err != nil {
+			return err
+		}
+		if current != 0 && current <= distance {
+			break
+		}
+		if err := db.setSpan(kv.SlasherMinSpans, validator, e, distance); err != nil {
+			return err
+		}
+	}
+	// the max spans of epochs within (source, target) only grow until a farther attestation was already recorded
+	for e := max(source+1, minEpoch); e < target; e++ {
+		distance := uint16(target - e)
+		current, err := db.span(kv.SlasherMaxSpans, validator, e)
+		if err != nil {
+			return err
+		}
+		if current >= distance {
+			break
+		}
+		if err := db.setSpan(kv.SlasherMaxSpans, validator, e, distance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *slasherDB) attestationRecord(target, validator uint64) (*attestationRecord, error) {
+	v, err := db.tx.GetOne(kv.SlasherAttestations, epochKey(target, validator))
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if len(v) != 8+2*length.Hash {
+		return nil, fmt.Errorf("slasher: invalid attestation record of validator %d at target %d", validator, target)
+	}
+	r := &attestationRecord{source: binary.BigEndian.Uint64(v)}
+	copy(r.dataRoot[:], v[8:])
+	copy(r.attestationRoot[:], v[8+length.Hash:])
+	return r, nil
+}
+
+func (db *slasherDB) putAttestationRecord(target, validator uint64, r *attestationRecord) error {
+	v := make([]byte, 8, 8+2*length.Hash)
+	binary.BigEndian.PutUint64(v, r.source)
+	v = append(v, r.dataRoot[:]...)
+	v = append(v, r.attestationRoot[:]...)
+	return db.tx.Put(kv.SlasherAttestations, epochKey(target, validator), v)
+}
+
+func (db *slasherDB) indexedAttestation(target uint64, root libcommon.Hash) (*cltypes.IndexedAttestation, error) {
+	k := make([]byte, 8, 8+length.Hash)
+	binary.BigEndian.PutUint64(k, target)
+	v, err := db.tx.GetOne(kv.SlasherIndexedAttestations, append(k, root[:]...))
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("slasher: missing indexed attestation %x at target %d", root, target)
+	}
+	att := cltypes.NewIndexedAttestation()
+	if err := att.DecodeSSZ(v, int(clparams.Phase0Version)); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+func (db *slasherDB) putIndexedAttestation(target uint64, root libcommon.Hash, att *cltypes.IndexedAttestation) error {
+	encoded, err := att.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	k := make([]byte, 8, 8+length.Hash)
+	binary.BigEndian.PutUint64(k, target)
+	return db.tx.Put(kv.SlasherIndexedAttestations, append(k, root[:]...), encoded)
+}
+
+// processAttestation records att for each of its attesters and returns the slashings of the attesters who double
+// voted, surrounded or were surrounded. The first attestation of a validator for a target epoch is the one recorded.
+func (db *slasherDB) processAttestation(att *cltypes.IndexedAttestation, minEpoch uint64) ([]*cltypes.AttesterSlashing, error) {
+	source, target := att.Data.Source().Epoch(), att.Data.Target().Epoch()
+	if target < minEpoch || source > target {
+		return nil, nil
+	}
+	dataRoot, err := att.Data.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	attestationRoot, err := att.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		slashings []*cltypes.AttesterSlashing
+		stored    bool
+		// conflicting attestations already turned into a slashing, one proof covers all the attesters of both
+		reported = map[libcommon.Hash]struct{}{}
+	)
+	err = solid.RangeErr[uint64](att.AttestingIndices, func(_ int, validator uint64, _ int) error {
+		var (
+			conflictTarget uint64
+			conflictRoot   libcommon.Hash
+			surrounding    bool // att surrounds the conflicting attestation
+			found          bool
+		)
+		record, err := db.attestationRecord(target, validator)
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			if record.dataRoot == dataRoot {
+				return nil
+			}
+			conflictTarget, conflictRoot, found = target, record.attestationRoot, true
+		} else {
+			minSpan, err := db.span(kv.SlasherMinSpans, validator, source)
+			if err != nil {
+				return err
+			}
+			maxSpan, err := db.span(kv.SlasherMaxSpans, validator, source)
+			if err != nil {
+				return err
+			}
+			switch {
+			case minSpan != 0 && target-source > uint64(minSpan):
+				conflictTarget, surrounding, found = source+uint64(minSpan), true, true
+			case maxSpan != 0 && target-source < uint64(maxSpan):
+				conflictTarget, found = source+uint64(maxSpan), true
+			}
+			if found {
+				conflicting, err := db.attestationRecord(conflictTarget, validator)
+				if err != nil {
+					return err
+				}
+				if conflicting == nil {
+					// the conflicting attestation was pruned
+					found = false
+				} else {
+					conflictRoot = conflicting.attestationRoot
+				}
+			}
+
+			if !stored {
+				if err := db.putIndexedAttestation(target, attestationRoot, att); err != nil {
+					return err
+				}
+				stored = true
+			}
+			if err := db.putAttestationRecord(target, validator, &attestationRecord{source: source, dataRoot: dataRoot, attestationRoot: attestationRoot}); err != nil {
+				return err
+			}
+			if err := db.updateSpans(validator, source, target, minEpoch); err != nil {
+				return err
+			}
+		}
+		if !found {
+			return nil
+		}
+		if _, ok := reported[conflictRoot]; ok {
+			return nil
+		}
+		conflicting, err := db.indexedAttestation(conflictTarget, conflictRoot)
+		if err != nil {
+			return err
+		}
+		slashing := &cltypes.AttesterSlashing{Attestation_1: conflicting, Attestation_2: att}
+		if surrounding {
+			slashing.Attestation_1, slashing.Attestation_2 = att, conflicting
+		}
+		if !cltypes.IsSlashableAttestationData(slashing.Attestation_1.Data, slashing.Attestation_2.Data) {
+			return nil
+		}
+		reported[conflictRoot] = struct{}{}
+		slashings = append(slashings, slashing)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slashings, nil
+}
+
+// processHeader records the header of its proposer and slot, and returns a slashing if a different one was recorded.
+func (db *slasherDB) processHeader(header *cltypes.SignedBeaconBlockHeader) (*cltypes.ProposerSlashing, error) {
+	k := epochKey(header.Header.Slot, header.Header.ProposerIndex)
+	v, err := db.tx.GetOne(kv.SlasherProposals, k)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := header.EncodeSSZ(nil)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, db.tx.Put(kv.SlasherProposals, k, encoded)
+	}
+	if bytes.Equal(v, encoded) {
+		return nil, nil
+	}
+	recorded := &cltypes.SignedBeaconBlockHeader{Header: &cltypes.BeaconBlockHeader{}}
+	if err := recorded.DecodeSSZ(v, int(clparams.Phase0Version)); err != nil {
+		return nil, err
+	}
+	if *recorded.Header == *header.Header {
+		// same block, signatures differ only if the proposer signed it twice, which is not slashable
+		return nil, nil
+	}
+	return &cltypes.ProposerSlashing{Header1: recorded, Header2: header}, nil
+}
+
+// flush writes the cached span chunks.
+func (db *slasherDB) flush() error {
+	for ck, c := range db.chunks {
+		if err := db.tx.Put(ck.table, ck.key[:], c); err != nil {
+			return err
+		}
+	}
+	clear(db.chunks)
+	return nil
+}
+
+// prune deletes the records older than minEpoch.
+func (db *slasherDB) prune(minEpoch, slotsPerEpoch uint64) error {
+	for table, limit := range map[string]uint64{
+		kv.SlasherAttestations:        minEpoch,
+		kv.SlasherIndexedAttestations: minEpoch,
+		kv.SlasherMinSpans:            minEpoch / chunkSize,
+		kv.SlasherMaxSpans:            minEpoch / chunkSize,
+		kv.SlasherProposals:           minEpoch * slotsPerEpoch,
+	} {
+		if err := pruneBelow(db.tx, table, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneBelow deletes the keys of table starting with a big endian number below limit.
+func pruneBelow(tx kv.RwTx, table string, limit uint64) error {
+	c, err := tx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	k, _, err := c.First()
+	for ; err == nil && k != nil && binary.BigEndian.Uint64(k) < limit; k, _, err = c.Next() {
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package slasher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+func testAttestation(source, target uint64, blockRoot libcommon.Hash, validators ...uint64) *cltypes.IndexedAttestation {
+	data := solid.NewAttestationData()
+	data.SetSlot(target * 32)
+	data.SetBeaconBlockRoot(blockRoot)
+	data.SetSource(solid.NewCheckpointFromParameters(libcommon.Hash{}, source))
+	data.SetTarget(solid.NewCheckpointFromParameters(blockRoot, target))
+	return &cltypes.IndexedAttestation{
+		AttestingIndices: solid.NewRawUint64List(2048, validators),
+		Data:             data,
+	}
+}
+
+// requireSameAttestation compares roots, decoded attestations differ from the encoded ones in their hash caches
+func requireSameAttestation(t *testing.T, expected, actual *cltypes.IndexedAttestation) {
+	expectedRoot, err := expected.HashSSZ()
+	require.NoError(t, err)
+	actualRoot, err := actual.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, actualRoot)
+}
+
+type testSubmitter struct {
+	attester []*cltypes.AttesterSlashing
+	proposer []*cltypes.ProposerSlashing
+}
+
+func (s *testSubmitter) SubmitAttesterSlashing(_ context.Context, slashing *cltypes.AttesterSlashing) error {
+	s.attester = append(s.attester, slashing)
+	return nil
+}
+
+func (s *testSubmitter) SubmitProposerSlashing(_ context.Context, slashing *cltypes.ProposerSlashing) error {
+	s.proposer = append(s.proposer, slashing)
+	return nil
+}
+
+func TestSlasherAttestations(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	submitter := &testSubmitter{}
+	s := &slasher{db: db, beaconCfg: &clparams.MainnetBeaconConfig, submitter: submitter, logger: log.New()}
+	ctx := context.Background()
+
+	// honest votes of validators 1 and 2, repeated votes are not offences
+	honest := []*cltypes.IndexedAttestation{
+		testAttestation(1, 2, libcommon.Hash{2}, 1, 2),
+		testAttestation(2, 3, libcommon.Hash{3}, 1, 2),
+		testAttestation(3, 4, libcommon.Hash{4}, 1),
+		testAttestation(3, 4, libcommon.Hash{4}, 1),
+	}
+	require.NoError(t, s.process(ctx, honest, nil))
+	require.Empty(t, submitter.attester)
+
+	// double vote
+	double := testAttestation(3, 4, libcommon.Hash{5}, 1)
+	require.NoError(t, s.process(ctx, []*cltypes.IndexedAttestation{double}, nil))
+	require.Len(t, submitter.attester, 1)
+	requireSameAttestation(t, honest[2], submitter.attester[0].Attestation_1)
+	requireSameAttestation(t, double, submitter.attester[0].Attestation_2)
+
+	// surrounding vote of validator 2: (1, 5) surrounds (2, 3)
+	surrounding := testAttestation(1, 5, libcommon.Hash{6}, 2)
+	require.NoError(t, s.process(ctx, []*cltypes.IndexedAttestation{surrounding}, nil))
+	require.Len(t, submitter.attester, 2)
+	requireSameAttestation(t, surrounding, submitter.attester[1].Attestation_1)
+	require.Equal(t, uint64(3), submitter.attester[1].Attestation_2.Data.Target().Epoch())
+
+	// surrounded vote of validator 2: (2, 4) is within (1, 5)
+	surrounded := testAttestation(2, 4, libcommon.Hash{7}, 2)
+	require.NoError(t, s.process(ctx, []*cltypes.IndexedAttestation{surrounded}, nil))
+	require.Len(t, submitter.attester, 3)
+	require.Equal(t, uint64(5), submitter.attester[2].Attestation_1.Data.Target().Epoch())
+	requireSameAttestation(t, surrounded, submitter.attester[2].Attestation_2)
+
+	for _, slashing := range submitter.attester {
+		require.True(t, cltypes.IsSlashableAttestationData(slashing.Attestation_1.Data, slashing.Attestation_2.Data))
+	}
+
+	// attestations out of the history window are pruned
+	require.NoError(t, s.process(ctx, []*cltypes.IndexedAttestation{testAttestation(HistoryEpochs+9, HistoryEpochs+10, libcommon.Hash{8}, 3)}, nil))
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		c, err := tx.Cursor(kv.SlasherAttestations)
+		require.NoError(t, err)
+		defer c.Close()
+		count, err := c.Count()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), count)
+		return nil
+	}))
+}
+
+func TestSlasherProposals(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	submitter := &testSubmitter{}
+	s := &slasher{db: db, beaconCfg: &clparams.MainnetBeaconConfig, submitter: submitter, logger: log.New()}
+	ctx := context.Background()
+
+	header := func(slot, proposer uint64, bodyRoot libcommon.Hash) *cltypes.SignedBeaconBlockHeader {
+		return &cltypes.SignedBeaconBlockHeader{Header: &cltypes.BeaconBlockHeader{Slot: slot, ProposerIndex: proposer, BodyRoot: bodyRoot}}
+	}
+
+	first := header(10, 1, libcommon.Hash{1})
+	require.NoError(t, s.process(ctx, nil, []*cltypes.SignedBeaconBlockHeader{first, header(10, 2, libcommon.Hash{2}), header(11, 1, libcommon.Hash{3}), first}))
+	require.Empty(t, submitter.proposer)
+
+	second := header(10, 1, libcommon.Hash{4})
+	require.NoError(t, s.process(ctx, nil, []*cltypes.SignedBeaconBlockHeader{second}))
+	require.Len(t, submitter.proposer, 1)
+	require.Equal(t, first, submitter.proposer[0].Header1)
+	require.Equal(t, second, submitter.proposer[0].Header2)
+}
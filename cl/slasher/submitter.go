@@ -0,0 +1,58 @@
+package slasher
+
+import (
+	"context"
+
+	sentinel "github.com/ledgerwatch/erigon-lib/gointerfaces/sentinelproto"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/gossip"
+)
+
+// messageProcessor - the gossip service of a message type, validating it and importing it into the operations pool
+type messageProcessor[T any] interface {
+	ProcessMessage(ctx context.Context, subnet *uint64, msg T) error
+}
+
+type gossipSubmitter struct {
+	sentinel         sentinel.SentinelClient
+	attesterSlashing messageProcessor[*cltypes.AttesterSlashing]
+	proposerSlashing messageProcessor[*cltypes.ProposerSlashing]
+}
+
+// NewGossipSubmitter returns a Submitter passing the slashings through the gossip services, the same way as
+// the ones received from peers, and publishing the valid ones to the network.
+func NewGossipSubmitter(
+	sentinel sentinel.SentinelClient,
+	attesterSlashing messageProcessor[*cltypes.AttesterSlashing],
+	proposerSlashing messageProcessor[*cltypes.ProposerSlashing],
+) Submitter {
+	return &gossipSubmitter{
+		sentinel:         sentinel,
+		attesterSlashing: attesterSlashing,
+		proposerSlashing: proposerSlashing,
+	}
+}
+
+func (g *gossipSubmitter) SubmitAttesterSlashing(ctx context.Context, slashing *cltypes.AttesterSlashing) error {
+	if err := g.attesterSlashing.ProcessMessage(ctx, nil, slashing); err != nil {
+		return err
+	}
+	return g.publish(ctx, slashing, gossip.TopicNameAttesterSlashing)
+}
+
+func (g *gossipSubmitter) SubmitProposerSlashing(ctx context.Context, slashing *cltypes.ProposerSlashing) error {
+	if err := g.proposerSlashing.ProcessMessage(ctx, nil, slashing); err != nil {
+		return err
+	}
+	return g.publish(ctx, slashing, gossip.TopicNameProposerSlashing)
+}
+
+func (g *gossipSubmitter) publish(ctx context.Context, msg interface{ EncodeSSZ([]byte) ([]byte, error) }, topic string) error {
+	encoded, err := msg.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.sentinel.PublishGossip(ctx, &sentinel.GossipData{Data: encoded, Name: topic})
+	return err
+}
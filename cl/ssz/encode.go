@@ -3,6 +3,7 @@ package ssz2
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
 )
@@ -112,3 +113,77 @@ func MarshalSSZ(buf []byte, schema ...any) (dst []byte, err error) {
 
 	return dst, nil
 }
+
+// MarshalSSZTo writes the same encoding as MarshalSSZ to w, without materializing the whole object in memory:
+// offsets of dynamic components are computed from their EncodingSizeSSZ and components are encoded one by one,
+// so at most one component is buffered at a time. Used to serve large objects, such as beacon states.
+func MarshalSSZTo(w io.Writer, schema ...any) (err error) {
+	defer func() {
+		if err2 := recover(); err2 != nil {
+			err = fmt.Errorf("panic while encoding: %v", err2)
+		}
+	}()
+
+	fixedSize := 0
+	for i, element := range schema {
+		switch obj := element.(type) {
+		case uint64, *uint64:
+			fixedSize += 8
+		case []byte:
+			fixedSize += len(obj)
+		case SizedObjectSSZ:
+			if obj.Static() {
+				fixedSize += obj.EncodingSizeSSZ()
+			} else {
+				fixedSize += 4
+			}
+		default:
+			panic(fmt.Sprintf("bad schema component %d", i))
+		}
+	}
+
+	var buf []byte
+	dynamicComponents := []SizedObjectSSZ{}
+	currentOffset := fixedSize
+	for _, element := range schema {
+		buf = buf[:0]
+		switch obj := element.(type) {
+		case uint64:
+			buf = append(buf, ssz.Uint64SSZ(obj)...)
+		case *uint64:
+			buf = append(buf, ssz.Uint64SSZ(*obj)...)
+		case []byte:
+			buf = obj
+		case SizedObjectSSZ:
+			if obj.Static() {
+				if buf, err = obj.EncodeSSZ(buf); err != nil {
+					return err
+				}
+			} else {
+				buf = binary.LittleEndian.AppendUint32(buf, uint32(currentOffset))
+				currentOffset += obj.EncodingSizeSSZ()
+				dynamicComponents = append(dynamicComponents, obj)
+			}
+		}
+		if _, err = w.Write(buf); err != nil {
+			return err
+		}
+		if _, ok := element.([]byte); ok {
+			buf = nil // don't overwrite the caller's slice
+		}
+	}
+
+	for i, dynamicComponent := range dynamicComponents {
+		if buf, err = dynamicComponent.EncodeSSZ(buf[:0]); err != nil {
+			return err
+		}
+		// offsets are already written, they are valid only if the sizes match
+		if len(buf) != dynamicComponent.EncodingSizeSSZ() {
+			return fmt.Errorf("dynamic component %d: encoded %d bytes, expected %d", i, len(buf), dynamicComponent.EncodingSizeSSZ())
+		}
+		if _, err = w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package ssz2_test
 
 import (
+	"bytes"
 	_ "embed"
 	"testing"
 
@@ -25,3 +26,13 @@ func TestEncodeDecode(t *testing.T) {
 	dec, _ := utils.DecompressSnappy(beaconState)
 	require.Equal(t, dec, d)
 }
+
+func TestWriteSSZ(t *testing.T) {
+	bs := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(bs, beaconState, int(clparams.CapellaVersion)))
+
+	var buf bytes.Buffer
+	require.NoError(t, bs.WriteSSZ(&buf))
+	dec, _ := utils.DecompressSnappy(beaconState)
+	require.Equal(t, dec, buf.Bytes())
+}
@@ -97,28 +97,40 @@ func ProcessJustificationBitsAndFinality(s abstract.BeaconState, unslashedPartic
 			return err
 		}
 	} else {
-		// Use bitlists to determine finality.
+		// Use bitlists to determine finality. Target balances are summed per worker and then added up.
 		currentParticipation, previousParticipation := s.EpochParticipation(true), s.EpochParticipation(false)
-		s.ForEachValidator(func(validator solid.Validator, i, total int) bool {
-			if validator.Slashed() {
-				return true
-			}
-			effectiveBalance := validator.EffectiveBalance()
-			if unslashedParticipatingIndicies != nil {
-				if unslashedParticipatingIndicies[beaconConfig.TimelyTargetFlagIndex][i] {
-					previousTargetBalance += effectiveBalance
+		validatorSet := s.ValidatorSet()
+		workers := chunksCount(validatorSet.Length())
+		previousTargetBalances, currentTargetBalances := make([]uint64, workers), make([]uint64, workers)
+		_ = parallelRange(validatorSet.Length(), func(worker, from, to int) error {
+			var previous, current uint64
+			for i := from; i < to; i++ {
+				validator := validatorSet.Get(i)
+				if validator.Slashed() {
+					continue
+				}
+				effectiveBalance := validator.EffectiveBalance()
+				if unslashedParticipatingIndicies != nil {
+					if unslashedParticipatingIndicies[beaconConfig.TimelyTargetFlagIndex][i] {
+						previous += effectiveBalance
+					}
+				} else if validator.Active(previousEpoch) &&
+					cltypes.ParticipationFlags(previousParticipation.Get(i)).HasFlag(int(beaconConfig.TimelyTargetFlagIndex)) {
+					previous += effectiveBalance
 				}
-			} else if validator.Active(previousEpoch) &&
-				cltypes.ParticipationFlags(previousParticipation.Get(i)).HasFlag(int(beaconConfig.TimelyTargetFlagIndex)) {
-				previousTargetBalance += effectiveBalance
-			}
 
-			if validator.Active(currentEpoch) &&
-				cltypes.ParticipationFlags(currentParticipation.Get(i)).HasFlag(int(beaconConfig.TimelyTargetFlagIndex)) {
-				currentTargetBalance += effectiveBalance
+				if validator.Active(currentEpoch) &&
+					cltypes.ParticipationFlags(currentParticipation.Get(i)).HasFlag(int(beaconConfig.TimelyTargetFlagIndex)) {
+					current += effectiveBalance
+				}
 			}
-			return true
+			previousTargetBalances[worker], currentTargetBalances[worker] = previous, current
+			return nil
 		})
+		for worker := 0; worker < workers; worker++ {
+			previousTargetBalance += previousTargetBalances[worker]
+			currentTargetBalance += currentTargetBalances[worker]
+		}
 	}
 
 	return weighJustificationAndFinalization(s, previousTargetBalance, currentTargetBalance)
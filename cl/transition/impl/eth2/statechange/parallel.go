@@ -0,0 +1,42 @@
+package statechange
+
+import (
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// parallelThreshold - below this amount of validators the epoch is processed in the calling goroutine, splitting it
+// across workers costs more than it saves. It is a variable so that tests can force the parallel path.
+var parallelThreshold = 4096
+
+// epochWorkers - amount of workers per-validator computations of the epoch transition are split across.
+var epochWorkers = runtime.NumCPU()
+
+// parallelRange splits [0, n) in contiguous chunks, one per worker, and calls fn for each of them concurrently.
+// fn must only read the state and write results into its own range of flat slices: state setters aren't thread-safe,
+// so results are applied by the caller once all workers are done. worker is the index of the chunk, in [0, workers).
+func parallelRange(n int, fn func(worker, from, to int) error) error {
+	workers := chunksCount(n)
+	if workers == 1 {
+		return fn(0, 0, n)
+	}
+	chunk := (n + workers - 1) / workers
+	var g errgroup.Group
+	for w := 0; w < workers; w++ {
+		w, from, to := w, w*chunk, min((w+1)*chunk, n)
+		g.Go(func() error { return fn(w, from, to) })
+	}
+	return g.Wait()
+}
+
+// chunksCount - amount of chunks parallelRange splits n items into, used to size per-worker partial sums.
+func chunksCount(n int) int {
+	if n < parallelThreshold || epochWorkers <= 1 {
+		return 1
+	}
+	workers := min(epochWorkers, n)
+	// last chunks would be empty if n isn't divisible enough
+	chunk := (n + workers - 1) / workers
+	return (n + chunk - 1) / chunk
+}
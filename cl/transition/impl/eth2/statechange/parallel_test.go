@@ -0,0 +1,43 @@
+package statechange
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// forceParallel makes epoch processing split any validator set across workers
+func forceParallel(t *testing.T, workers int) {
+	threshold, prevWorkers := parallelThreshold, epochWorkers
+	parallelThreshold, epochWorkers = 1, workers
+	t.Cleanup(func() { parallelThreshold, epochWorkers = threshold, prevWorkers })
+}
+
+func TestParallelRange(t *testing.T) {
+	forceParallel(t, 4)
+	for _, n := range []int{0, 1, 3, 4, 5, 9, 1000} {
+		seen := make([]int32, n)
+		var calls atomic.Int32
+		require.NoError(t, parallelRange(n, func(worker, from, to int) error {
+			require.Less(t, worker, chunksCount(n))
+			calls.Add(1)
+			for i := from; i < to; i++ {
+				atomic.AddInt32(&seen[i], 1)
+			}
+			return nil
+		}))
+		require.Equal(t, int32(chunksCount(n)), calls.Load())
+		for i := range seen {
+			require.Equal(t, int32(1), seen[i], "n=%d index=%d", n, i)
+		}
+	}
+}
+
+func TestParallelEpochProcessing(t *testing.T) {
+	forceParallel(t, 7)
+	t.Run("RewardsAndPenalties", TestProcessRewardsAndPenalties)
+	t.Run("EffectiveBalances", TestProcessEffectiveBalances)
+	t.Run("JustificationAndFinality", TestProcessJustificationAndFinality)
+	t.Run("InactivityScores", TestInactivityScores)
+}
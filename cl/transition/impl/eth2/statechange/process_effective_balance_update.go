@@ -1,8 +1,9 @@
 package statechange
 
 import (
+	"math"
+
 	"github.com/ledgerwatch/erigon/cl/abstract"
-	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/utils"
 )
 
@@ -13,24 +14,31 @@ func ProcessEffectiveBalanceUpdates(state abstract.BeaconState) error {
 	histeresisIncrement := beaconConfig.EffectiveBalanceIncrement / beaconConfig.HysteresisQuotient
 	downwardThreshold := histeresisIncrement * beaconConfig.HysteresisDownwardMultiplier
 	upwardThreshold := histeresisIncrement * beaconConfig.HysteresisUpwardMultiplier
-	// Iterate over validator set and compute the diff of each validator.
-	var err error
-	var balance uint64
-	state.ForEachValidator(func(validator solid.Validator, index, total int) bool {
-		balance, err = state.ValidatorBalance(index)
-		if err != nil {
-			return false
+	// Compute new effective balances across workers, noChange where hysteresis isn't crossed, then apply them.
+	const noChange = math.MaxUint64
+	validatorSet := state.ValidatorSet()
+	effectiveBalances := make([]uint64, validatorSet.Length())
+	if err := parallelRange(validatorSet.Length(), func(_, from, to int) error {
+		for index := from; index < to; index++ {
+			balance, err := state.ValidatorBalance(index)
+			if err != nil {
+				return err
+			}
+			effectiveBalances[index] = noChange
+			eb := validatorSet.Get(index).EffectiveBalance()
+			if balance+downwardThreshold < eb || eb+upwardThreshold < balance {
+				// Set new effective balance
+				effectiveBalances[index] = utils.Min64(balance-(balance%beaconConfig.EffectiveBalanceIncrement), beaconConfig.MaxEffectiveBalance)
+			}
 		}
-		eb := validator.EffectiveBalance()
-		if balance+downwardThreshold < eb || eb+upwardThreshold < balance {
-			// Set new effective balance
-			effectiveBalance := utils.Min64(balance-(balance%beaconConfig.EffectiveBalanceIncrement), beaconConfig.MaxEffectiveBalance)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for index, effectiveBalance := range effectiveBalances {
+		if effectiveBalance != noChange {
 			state.SetEffectiveBalanceForValidatorAtIndex(index, effectiveBalance)
 		}
-		return true
-	})
-	if err != nil {
-		return err
 	}
 	return nil
 }
@@ -14,11 +14,14 @@ func GetUnslashedIndiciesSet(cfg *clparams.BeaconChainConfig, previousEpoch uint
 		flagsUnslashedIndiciesSet[i] = make([]bool, validatorSet.Length())
 	}
 
-	validatorSet.Range(func(validatorIndex int, validator solid.Validator, total int) bool {
-		for i := range weights {
-			flagsUnslashedIndiciesSet[i][validatorIndex] = state.IsUnslashedParticipatingIndex(validatorSet, previousEpochPartecipation, previousEpoch, uint64(validatorIndex), i)
+	// workers fill disjoint ranges of the flat sets, reads of the validator set are thread-safe
+	_ = parallelRange(validatorSet.Length(), func(_, from, to int) error {
+		for validatorIndex := from; validatorIndex < to; validatorIndex++ {
+			for i := range weights {
+				flagsUnslashedIndiciesSet[i][validatorIndex] = state.IsUnslashedParticipatingIndex(validatorSet, previousEpochPartecipation, previousEpoch, uint64(validatorIndex), i)
+			}
 		}
-		return true
+		return nil
 	})
 	return flagsUnslashedIndiciesSet
 }
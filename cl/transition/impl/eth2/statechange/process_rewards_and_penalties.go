@@ -10,62 +10,79 @@ import (
 func processRewardsAndPenaltiesPostAltair(s abstract.BeaconState, eligibleValidators []uint64, flagsUnslashedIndiciesSet [][]bool) (err error) {
 	beaconConfig := s.BeaconConfig()
 	weights := beaconConfig.ParticipationWeights()
+	validatorSet := s.ValidatorSet()
 
 	// Initialize variables
 	totalActiveBalance := s.GetTotalActiveBalance()
 	// Inactivity penalties denominator.
 	inactivityPenaltyDenominator := beaconConfig.InactivityScoreBias * beaconConfig.GetPenaltyQuotient(s.Version())
-	// Make buffer for flag indexes total balances.
-	flagsTotalBalances := make([]uint64, len(weights))
-	s.ForEachValidator(func(validator solid.Validator, validatorIndex, total int) bool {
-		for i := range weights {
-			if flagsUnslashedIndiciesSet[i][validatorIndex] {
-				flagsTotalBalances[i] += validator.EffectiveBalance()
+	// Make buffer for flag indexes total balances, flat: partial sums of each worker one after another.
+	workers := chunksCount(validatorSet.Length())
+	partialFlagsTotalBalances := make([]uint64, workers*len(weights))
+	_ = parallelRange(validatorSet.Length(), func(worker, from, to int) error {
+		partial := partialFlagsTotalBalances[worker*len(weights) : (worker+1)*len(weights)]
+		for validatorIndex := from; validatorIndex < to; validatorIndex++ {
+			for i := range weights {
+				if flagsUnslashedIndiciesSet[i][validatorIndex] {
+					partial[i] += validatorSet.Get(validatorIndex).EffectiveBalance()
+				}
 			}
 		}
-		return true
+		return nil
 	})
+	flagsTotalBalances := make([]uint64, len(weights))
+	for worker := 0; worker < workers; worker++ {
+		for i := range weights {
+			flagsTotalBalances[i] += partialFlagsTotalBalances[worker*len(weights)+i]
+		}
+	}
 	// precomputed multiplier for reward.
 	rewardMultipliers := make([]uint64, len(weights))
 	for i := range weights {
 		rewardMultipliers[i] = weights[i] * (flagsTotalBalances[i] / beaconConfig.EffectiveBalanceIncrement)
 	}
 	rewardDenominator := (totalActiveBalance / beaconConfig.EffectiveBalanceIncrement) * beaconConfig.WeightDenominator
-	var baseReward uint64
+	// base rewards are computed from it directly: the state caches it lazily, so it must not be touched by workers.
+	baseRewardPerIncrement := s.BaseRewardPerIncrement()
 	inactivityLeaking := state.InactivityLeaking(s)
-	// Now process deltas and whats nots.
-	for _, index := range eligibleValidators {
-		baseReward, err = s.BaseReward(index)
-		if err != nil {
-			return
-		}
-		delta := int64(0)
-		for flagIdx := range weights {
-			if flagsUnslashedIndiciesSet[flagIdx][index] {
-				if !inactivityLeaking {
-					delta += int64((baseReward * rewardMultipliers[flagIdx]) / rewardDenominator)
+	// Now process deltas and whats nots, each worker computes deltas of its range of eligible validators.
+	deltas := make([]int64, len(eligibleValidators))
+	if err := parallelRange(len(eligibleValidators), func(_, from, to int) error {
+		for i := from; i < to; i++ {
+			index := eligibleValidators[i]
+			effectiveBalance := validatorSet.Get(int(index)).EffectiveBalance()
+			baseReward := (effectiveBalance / beaconConfig.EffectiveBalanceIncrement) * baseRewardPerIncrement
+			delta := int64(0)
+			for flagIdx := range weights {
+				if flagsUnslashedIndiciesSet[flagIdx][index] {
+					if !inactivityLeaking {
+						delta += int64((baseReward * rewardMultipliers[flagIdx]) / rewardDenominator)
+					}
+				} else if flagIdx != int(beaconConfig.TimelyHeadFlagIndex) {
+					delta -= int64(baseReward * weights[flagIdx] / beaconConfig.WeightDenominator)
 				}
-			} else if flagIdx != int(beaconConfig.TimelyHeadFlagIndex) {
-				delta -= int64(baseReward * weights[flagIdx] / beaconConfig.WeightDenominator)
-			}
-		}
-		if !flagsUnslashedIndiciesSet[beaconConfig.TimelyTargetFlagIndex][index] {
-			inactivityScore, err := s.ValidatorInactivityScore(int(index))
-			if err != nil {
-				return err
 			}
-			// Process inactivity penalties.
-			effectiveBalance, err := s.ValidatorEffectiveBalance(int(index))
-			if err != nil {
-				return err
+			if !flagsUnslashedIndiciesSet[beaconConfig.TimelyTargetFlagIndex][index] {
+				inactivityScore, err := s.ValidatorInactivityScore(int(index))
+				if err != nil {
+					return err
+				}
+				// Process inactivity penalties.
+				delta -= int64((effectiveBalance * inactivityScore) / inactivityPenaltyDenominator)
 			}
-			delta -= int64((effectiveBalance * inactivityScore) / inactivityPenaltyDenominator)
+			deltas[i] = delta
 		}
-		if delta > 0 {
-			if err := state.IncreaseBalance(s, index, uint64(delta)); err != nil {
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Apply deltas, setters aren't thread-safe.
+	for i, index := range eligibleValidators {
+		if deltas[i] > 0 {
+			if err := state.IncreaseBalance(s, index, uint64(deltas[i])); err != nil {
 				return err
 			}
-		} else if err := state.DecreaseBalance(s, index, uint64(-delta)); err != nil {
+		} else if err := state.DecreaseBalance(s, index, uint64(-deltas[i])); err != nil {
 			return err
 		}
 	}
@@ -1,6 +1,7 @@
 package eth_clock
 
 import (
+	"bytes"
 	"encoding/binary"
 	"sort"
 	"time"
@@ -40,7 +41,11 @@ func forkList(schedule map[common.Bytes4]uint64) (f []forkNode) {
 		f = append(f, forkNode{epoch: epoch, version: version})
 	}
 	sort.Slice(f, func(i, j int) bool {
-		return f[i].epoch < f[j].epoch
+		if f[i].epoch != f[j].epoch {
+			return f[i].epoch < f[j].epoch
+		}
+		// forks activated at the same epoch: the order must be deterministic
+		return bytes.Compare(f[i].version[:], f[j].version[:]) < 0
 	})
 	return
 }
@@ -1,19 +1,53 @@
 package validator_params
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
 	"sync"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 )
 
+// ProposerConfig - fee recipients of validators by public key, in the format of the proposer config files of
+// validator clients: {"proposer_config": {"<pubkey>": {"fee_recipient": "<address>"}}, "default_config": {"fee_recipient": "<address>"}}
+type ProposerConfig struct {
+	ProposerConfig map[libcommon.Bytes48]ProposerOptions `json:"proposer_config"`
+	DefaultConfig  *ProposerOptions                      `json:"default_config"`
+}
+
+type ProposerOptions struct {
+	FeeRecipient libcommon.Address `json:"fee_recipient"`
+}
+
+func ReadProposerConfig(path string) (*ProposerConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &ProposerConfig{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("invalid proposer config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
 type ValidatorParams struct {
-	feeRecipients sync.Map
+	feeRecipients  sync.Map
+	proposerConfig *ProposerConfig
 }
 
 func NewValidatorParams() *ValidatorParams {
 	return &ValidatorParams{}
 }
 
+// WithProposerConfig - fee recipients of the config are used for validators which are not prepared by
+// validator clients (see SetFeeRecipient).
+func (vp *ValidatorParams) WithProposerConfig(cfg *ProposerConfig) *ValidatorParams {
+	vp.proposerConfig = cfg
+	return vp
+}
+
 func (vp *ValidatorParams) SetFeeRecipient(validatorIndex uint64, feeRecipient libcommon.Address) {
 	vp.feeRecipients.Store(validatorIndex, feeRecipient)
 }
@@ -25,3 +59,20 @@ func (vp *ValidatorParams) GetFeeRecipient(validatorIndex uint64) (libcommon.Add
 	}
 	return val.(libcommon.Address), true
 }
+
+// GetProposerFeeRecipient - fee recipient prepared by validator client, or the one of the proposer config.
+func (vp *ValidatorParams) GetProposerFeeRecipient(validatorIndex uint64, pubKey libcommon.Bytes48) (libcommon.Address, bool) {
+	if feeRecipient, ok := vp.GetFeeRecipient(validatorIndex); ok {
+		return feeRecipient, true
+	}
+	if vp.proposerConfig == nil {
+		return libcommon.Address{}, false
+	}
+	if opts, ok := vp.proposerConfig.ProposerConfig[pubKey]; ok {
+		return opts.FeeRecipient, true
+	}
+	if vp.proposerConfig.DefaultConfig != nil {
+		return vp.proposerConfig.DefaultConfig.FeeRecipient, true
+	}
+	return libcommon.Address{}, false
+}
@@ -0,0 +1,49 @@
+package validator_params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposerFeeRecipient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proposer_config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "proposer_config": {
+    "0xa99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c": {
+      "fee_recipient": "0x50155530FCE8a85ec7055A5F8b2bE214B3DaeFd3"
+    }
+  },
+  "default_config": {
+    "fee_recipient": "0x6e35733c5af9B61374A128e6F85f553aF09ff89A"
+  }
+}`), 0o644))
+	cfg, err := ReadProposerConfig(path)
+	require.NoError(t, err)
+
+	configured := libcommon.Bytes48(libcommon.FromHex("0xa99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c"))
+	vp := NewValidatorParams()
+	_, ok := vp.GetProposerFeeRecipient(1, configured)
+	require.False(t, ok)
+
+	vp.WithProposerConfig(cfg)
+	feeRecipient, ok := vp.GetProposerFeeRecipient(1, configured)
+	require.True(t, ok)
+	require.Equal(t, libcommon.HexToAddress("0x50155530FCE8a85ec7055A5F8b2bE214B3DaeFd3"), feeRecipient)
+	feeRecipient, ok = vp.GetProposerFeeRecipient(2, libcommon.Bytes48{2})
+	require.True(t, ok)
+	require.Equal(t, libcommon.HexToAddress("0x6e35733c5af9B61374A128e6F85f553aF09ff89A"), feeRecipient)
+
+	// prepared by validator client
+	vp.SetFeeRecipient(1, libcommon.Address{1})
+	feeRecipient, ok = vp.GetProposerFeeRecipient(1, configured)
+	require.True(t, ok)
+	require.Equal(t, libcommon.Address{1}, feeRecipient)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"proposer_config": {"0x01": {}}}`), 0o644))
+	_, err = ReadProposerConfig(path)
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package caplin1
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"os"
 	"path"
 	"time"
@@ -17,14 +18,17 @@ import (
 	"github.com/ledgerwatch/erigon/cl/antiquary"
 	"github.com/ledgerwatch/erigon/cl/beacon"
 	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/beacon/builder"
 	"github.com/ledgerwatch/erigon/cl/beacon/handler"
 	"github.com/ledgerwatch/erigon/cl/beacon/synced_data"
 	"github.com/ledgerwatch/erigon/cl/clparams/initial_state"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/monitor"
 	"github.com/ledgerwatch/erigon/cl/rpc"
 	"github.com/ledgerwatch/erigon/cl/sentinel"
 	"github.com/ledgerwatch/erigon/cl/sentinel/service"
+	"github.com/ledgerwatch/erigon/cl/slasher"
 	"github.com/ledgerwatch/erigon/cl/utils/eth_clock"
 	"github.com/ledgerwatch/erigon/cl/validator/attestation_producer"
 	"github.com/ledgerwatch/erigon/cl/validator/committee_subscription"
@@ -90,14 +94,18 @@ func OpenCaplinDatabase(ctx context.Context,
 	if err := tx.Commit(); err != nil {
 		return nil, nil, err
 	}
+	blobStorage := blob_storage.NewBlobStore(blobDB, afero.NewBasePathFs(afero.NewOsFs(), blobDir), blobPruneDistance, beaconConfig, ethClock)
 	{ // start ticking forkChoice
 		go func() {
 			<-ctx.Done()
 			db.Close()     // close sql database here
 			blobDB.Close() // close blob database here
+			if err := blobStorage.Scoreboard().Close(); err != nil {
+				log.Warn("[Caplin] could not persist the scoreboard", "err", err)
+			}
 		}()
 	}
-	return db, blob_storage.NewBlobStore(blobDB, afero.NewBasePathFs(afero.NewOsFs(), blobDir), blobPruneDistance, beaconConfig, ethClock), nil
+	return db, blobStorage, nil
 }
 
 func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngine, config *ethconfig.Config, networkConfig *clparams.NetworkConfig,
@@ -126,7 +134,8 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 	syncContributionPool := sync_contribution_pool.NewSyncContributionPool(beaconConfig)
 	emitters := beaconevents.NewEmitters()
 	aggregationPool := aggregation.NewAggregationPool(ctx, beaconConfig, networkConfig, ethClock)
-	forkChoice, err := forkchoice.NewForkChoiceStore(ethClock, state, engine, pool, fork_graph.NewForkGraphDisk(state, fcuFs, config.BeaconRouter), emitters, syncedDataManager, blobStorage)
+	validatorMonitor := monitor.NewValidatorMonitor(config.CaplinConfig.MonitoredValidators, beaconConfig)
+	forkChoice, err := forkchoice.NewForkChoiceStore(ethClock, state, engine, pool, fork_graph.NewForkGraphDisk(state, fcuFs, config.BeaconRouter), emitters, syncedDataManager, blobStorage, validatorMonitor)
 	if err != nil {
 		logger.Error("Could not create forkchoice", "err", err)
 		return err
@@ -155,6 +164,7 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 		TmpDir:         dirs.Tmp,
 		EnableBlocks:   true,
 		ActiveIndicies: uint64(len(activeIndicies)),
+		EnablePeerDAS:  config.CaplinConfig.PeerDAS,
 	}, rcsn, blobStorage, indexDB, &service.ServerConfig{
 		Network:   "tcp",
 		Addr:      fmt.Sprintf("%s:%d", config.SentinelAddr, config.SentinelPort),
@@ -174,19 +184,24 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 	beaconRpc := rpc.NewBeaconRpcP2P(ctx, sentinel, beaconConfig, ethClock)
 	committeeSub := committee_subscription.NewCommitteeSubscribeManagement(ctx, indexDB, beaconConfig, networkConfig, ethClock, sentinel, state, aggregationPool, syncedDataManager)
 	// Define gossip services
-	blockService := services.NewBlockService(ctx, indexDB, forkChoice, syncedDataManager, ethClock, beaconConfig, emitters)
-	blobService := services.NewBlobSidecarService(ctx, beaconConfig, forkChoice, syncedDataManager, ethClock, false)
+	proposerSlashingService := services.NewProposerSlashingService(pool, syncedDataManager, beaconConfig, ethClock)
+	attesterSlashingService := services.NewAttesterSlashingService(pool, syncedDataManager, forkChoice, beaconConfig, ethClock)
+	slasherService := slasher.NewSlasher(ctx, config.CaplinConfig.Slasher, indexDB, beaconConfig,
+		slasher.NewGossipSubmitter(sentinel, attesterSlashingService, proposerSlashingService), logger)
+	blockService := services.NewBlockService(ctx, indexDB, forkChoice, syncedDataManager, ethClock, beaconConfig, emitters, slasherService)
+	blobService := services.NewBlobSidecarService(ctx, beaconConfig, forkChoice, syncedDataManager, ethClock, emitters, false)
+	dataColumnService := services.NewDataColumnSidecarService(beaconConfig, forkChoice, syncedDataManager, ethClock, blobStorage)
 	syncCommitteeMessagesService := services.NewSyncCommitteeMessagesService(beaconConfig, ethClock, syncedDataManager, syncContributionPool, false)
-	attestationService := services.NewAttestationService(ctx, forkChoice, committeeSub, ethClock, syncedDataManager, beaconConfig, networkConfig)
+	seenAttestations := services.NewSeenAttestationCache(config.CaplinConfig.AttestationSeenCacheEpochs, config.CaplinConfig.AttestationSeenCacheEpochSize)
+	attestationService := services.NewAttestationService(ctx, forkChoice, committeeSub, ethClock, syncedDataManager, beaconConfig, networkConfig, emitters, pool, slasherService, seenAttestations)
 	syncContributionService := services.NewSyncContributionService(syncedDataManager, beaconConfig, syncContributionPool, ethClock, emitters, false)
-	aggregateAndProofService := services.NewAggregateAndProofService(ctx, syncedDataManager, forkChoice, beaconConfig, pool, false)
+	aggregateAndProofService := services.NewAggregateAndProofService(ctx, syncedDataManager, forkChoice, beaconConfig, pool, slasherService)
 	voluntaryExitService := services.NewVoluntaryExitService(pool, emitters, syncedDataManager, beaconConfig, ethClock)
 	blsToExecutionChangeService := services.NewBLSToExecutionChangeService(pool, emitters, syncedDataManager, beaconConfig)
-	proposerSlashingService := services.NewProposerSlashingService(pool, syncedDataManager, beaconConfig, ethClock)
 	// Create the gossip manager
 	gossipManager := network.NewGossipReceiver(sentinel, forkChoice, beaconConfig, ethClock, emitters, committeeSub,
-		blockService, blobService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
-		attestationService, voluntaryExitService, blsToExecutionChangeService, proposerSlashingService)
+		blockService, blobService, dataColumnService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
+		attestationService, voluntaryExitService, blsToExecutionChangeService, proposerSlashingService, attesterSlashingService)
 	{ // start ticking forkChoice
 		go func() {
 			tickInterval := time.NewTicker(2 * time.Millisecond)
@@ -261,6 +276,24 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 
 	statesReader := historical_states_reader.NewHistoricalStatesReader(beaconConfig, rcsn, vTables, genesisState)
 	validatorParameters := validator_params.NewValidatorParams()
+	if config.CaplinConfig.ProposerConfigFile != "" {
+		proposerConfig, err := validator_params.ReadProposerConfig(config.CaplinConfig.ProposerConfigFile)
+		if err != nil {
+			return err
+		}
+		validatorParameters.WithProposerConfig(proposerConfig)
+	}
+	var builderClient builder.BuilderClient
+	if len(config.CaplinConfig.MevRelayUrls) > 0 {
+		minBid := new(big.Int).Mul(new(big.Int).SetUint64(config.CaplinConfig.MevMinBid), big.NewInt(params.GWei))
+		builderClient, err = builder.NewBlockBuilderClient(config.CaplinConfig.MevRelayUrls, minBid, beaconConfig, logger)
+		if err != nil {
+			return err
+		}
+		if err := builderClient.GetStatus(ctx); err != nil {
+			logger.Warn("[Caplin] Builder relays are not available", "err", err)
+		}
+	}
 	if config.BeaconRouter.Active {
 		apiHandler := handler.NewApiHandler(
 			logger,
@@ -292,6 +325,9 @@ func RunCaplinPhase1(ctx context.Context, engine execution_client.ExecutionEngin
 			voluntaryExitService,
 			blsToExecutionChangeService,
 			proposerSlashingService,
+			validatorMonitor,
+			builderClient,
+			seenAttestations,
 		)
 		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
 			ArchiveApi: apiHandler,
@@ -21,7 +21,7 @@ import (
 type CaplinCliCfg struct {
 	*sentinelcli.SentinelCliCfg
 
-	CheckpointUri         string        `json:"checkpoint_uri"`
+	CheckpointUris        []string      `json:"checkpoint_uris"`
 	Chaindata             string        `json:"chaindata"`
 	ErigonPrivateApi      string        `json:"erigon_private_api"`
 	TransitionChain       bool          `json:"transition_chain"`
@@ -99,10 +99,10 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 		}
 	}
 
-	if ctx.String(caplinflags.CheckpointSyncUrlFlag.Name) != "" {
-		cfg.CheckpointUri = ctx.String(caplinflags.CheckpointSyncUrlFlag.Name)
+	if urls := ctx.StringSlice(caplinflags.CheckpointSyncUrlFlag.Name); len(urls) > 0 {
+		cfg.CheckpointUris = urls
 	} else {
-		cfg.CheckpointUri = clparams.GetCheckpointSyncEndpoint(cfg.NetworkType)
+		cfg.CheckpointUris = clparams.GetCheckpointSyncEndpoints(cfg.NetworkType)
 	}
 
 	cfg.Chaindata = ctx.String(caplinflags.ChaindataFlag.Name)
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/core"
@@ -40,6 +41,26 @@ func NewAccount(name string) *Account {
 
 	sigKey, _ := crypto.GenerateKey()
 
+	return register(name, sigKey)
+}
+
+// NewDeterministicAccount - like NewAccount, but the key is derived from the name,
+// so the account has the same address on every devnet run
+func NewDeterministicAccount(name string) *Account {
+	if account, ok := accountsByName[name]; ok {
+		return account
+	}
+
+	sigKey, err := crypto.ToECDSA(crypto.Keccak256([]byte("devnet account: " + name)))
+
+	if err != nil {
+		panic(fmt.Sprintf("can't derive key of %q: %s", name, err))
+	}
+
+	return register(name, sigKey)
+}
+
+func register(name string, sigKey *ecdsa.PrivateKey) *Account {
 	account := &Account{
 		Name:    name,
 		Address: crypto.PubkeyToAddress(sigKey.PublicKey),
@@ -8,6 +8,7 @@ import (
 	"net"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/ledgerwatch/erigon-lib/chain/networkname"
 	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
@@ -52,9 +53,16 @@ type NodeArgs struct {
 	HeimdallURL               string `arg:"--bor.heimdall" json:"bor.heimdall,omitempty"`
 	WithHeimdallMilestones    bool   `arg:"--bor.milestone" json:"bor.milestone"`
 	VMDebug                   bool   `arg:"--vmdebug" flag:"" default:"false" json:"dmdebug"`
+	ExternalCL                bool   `arg:"--externalcl" flag:"" default:"false" json:"externalcl,omitempty"`
 
 	NodeKey    *ecdsa.PrivateKey `arg:"-"`
 	NodeKeyHex string            `arg:"--nodekeyhex" json:"nodekeyhex,omitempty"`
+
+	// Binary - erigon binary to run the node as a separate process. By default nodes run in-process, i.e. the code
+	// of current build. Allows networks of mixed versions, e.g. current branch + last release
+	Binary string `arg:"-" json:"-"`
+	// ExpectedVersion - web3_clientVersion of the node must contain it, checked once the node is started
+	ExpectedVersion string `arg:"-" json:"-"`
 }
 
 func (node *NodeArgs) Configure(base NodeArgs, nodeNumber int) error {
@@ -78,9 +86,12 @@ func (node *NodeArgs) Configure(base NodeArgs, nodeNumber int) error {
 	}
 	node.NodeKeyHex = hex.EncodeToString(crypto.FromECDSA(node.NodeKey))
 
-	node.Metrics = base.Metrics
-	node.MetricsPort = base.MetricsPort
-	node.MetricsAddr = base.MetricsAddr
+	// metrics may be enabled per node by EnableMetrics
+	if base.Metrics {
+		node.Metrics = base.Metrics
+		node.MetricsPort = base.MetricsPort
+		node.MetricsAddr = base.MetricsAddr
+	}
 
 	node.Snapshots = base.Snapshots
 
@@ -117,15 +128,36 @@ func (node *NodeArgs) ChainID() *big.Int {
 	return config.ChainID
 }
 
+func (node *NodeArgs) GetBinary() string {
+	return node.Binary
+}
+
+func (node *NodeArgs) GetExpectedVersion() string {
+	return node.ExpectedVersion
+}
+
 func (node *NodeArgs) GetHttpPort() int {
 	return node.HttpPort
 }
 
+func (node *NodeArgs) GetAuthRpcPort() int {
+	return node.AuthRpcPort
+}
+
+func (node *NodeArgs) GetDataDir() string {
+	return node.DataDir
+}
+
 func (node *NodeArgs) GetEnodeURL() string {
 	port := node.Port
 	return enode.NewV4(&node.NodeKey.PublicKey, net.ParseIP("127.0.0.1"), port, port).URLv4()
 }
 
+// SetStaticPeers replaces the static peers of the node, it is applied once the node is (re)started
+func (node *NodeArgs) SetStaticPeers(peers []string) {
+	node.StaticPeers = strings.Join(peers, ",")
+}
+
 func (node *NodeArgs) EnableMetrics(port int) {
 	node.Metrics = true
 	node.MetricsPort = port
@@ -133,7 +165,7 @@ func (node *NodeArgs) EnableMetrics(port int) {
 
 type BlockProducer struct {
 	NodeArgs
-	Mine            bool   `arg:"--mine" flag:"true"`
+	Mine            string `arg:"--mine" flag:"" default:"true"`
 	Etherbase       string `arg:"--miner.etherbase"`
 	GasLimit        int    `arg:"--miner.gaslimit"`
 	DevPeriod       int    `arg:"--dev.period"`
@@ -152,7 +184,10 @@ func (m *BlockProducer) Configure(baseNode NodeArgs, nodeNumber int) error {
 
 	switch m.Chain {
 	case networkname.DevChainName:
-		if m.DevPeriod == 0 {
+		if m.ExternalCL {
+			// blocks are proposed via the engine api of the local consensus layer
+			m.Mine = "false"
+		} else if m.DevPeriod == 0 {
 			m.DevPeriod = 30
 		}
 		m.account = accounts.NewAccount(m.GetName() + "-etherbase")
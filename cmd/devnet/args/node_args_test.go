@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ledgerwatch/erigon/cmd/devnet/args"
@@ -61,6 +62,60 @@ func TestNodeArgs(t *testing.T) {
 	}
 }
 
+func TestNodeArgsBinary(t *testing.T) {
+	nodeArgs, err := args.AsArgs(args.BlockConsumer{
+		NodeArgs: args.NodeArgs{
+			DataDir:         filepath.Join("data", fmt.Sprintf("%d", 2)),
+			StaticPeers:     "enode",
+			PrivateApiAddr:  "localhost:9091",
+			Binary:          "/opt/erigon/erigon",
+			ExpectedVersion: "2.60.0",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// binary and expected version are used by devnet itself and must not be passed to the node
+	expected := nonProducingNodeArgs("data", 2, "enode")
+	if len(nodeArgs) != len(expected) {
+		t.Fatal(nodeArgs, "expected", expected)
+	}
+
+	for _, arg := range nodeArgs {
+		if strings.Contains(arg, "/opt/erigon") || strings.Contains(arg, "2.60.0") {
+			t.Fatal(arg, "unexpected")
+		}
+	}
+}
+
+func TestNodeArgsStaticPeers(t *testing.T) {
+	node := args.BlockConsumer{
+		NodeArgs: args.NodeArgs{
+			DataDir:        filepath.Join("data", fmt.Sprintf("%d", 2)),
+			StaticPeers:    "enode",
+			PrivateApiAddr: "localhost:9091",
+		},
+	}
+
+	node.SetStaticPeers([]string{"enode1", "enode2"})
+	nodeArgs, err := args.AsArgs(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asMap := map[string]struct{}{}
+	for _, arg := range nodeArgs {
+		asMap[arg] = struct{}{}
+	}
+
+	for _, arg := range nonProducingNodeArgs("data", 2, "enode1,enode2") {
+		if _, ok := asMap[arg]; !ok {
+			t.Fatal(arg, "missing")
+		}
+	}
+}
+
 func TestParameterFromArgument(t *testing.T) {
 	enode := fmt.Sprintf("%q", "1234567")
 	testCases := []struct {
@@ -0,0 +1,219 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// Fault injection steps, nodes are selected by their index in the current network.
+// Faults are expected to be followed by AwaitRecovery which asserts that the cluster re-syncs
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(KillNode),
+		scenarios.StepHandler(StartNode),
+		scenarios.StepHandler(RestartNode),
+		scenarios.StepHandler(PartitionNetwork),
+		scenarios.StepHandler(HealNetwork),
+		scenarios.StepHandler(PauseBlockProduction),
+		scenarios.StepHandler(AwaitRecovery),
+	)
+}
+
+func currentNetwork(ctx context.Context) (*devnet.Network, error) {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		return network, nil
+	}
+
+	return nil, fmt.Errorf("no current network")
+}
+
+func selectNode(ctx context.Context, network *devnet.Network, node int) (devnet.Node, error) {
+	if selected := network.SelectNode(ctx, node); selected != nil {
+		return selected, nil
+	}
+
+	return nil, fmt.Errorf("unknown node: %d", node)
+}
+
+// KillNode stops the node, it keeps its datadir and can be started again by StartNode
+func KillNode(ctx context.Context, node int) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectNode(ctx, network, node)
+	if err != nil {
+		return err
+	}
+
+	return network.StopNode(ctx, selected)
+}
+
+func StartNode(ctx context.Context, node int) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectNode(ctx, network, node)
+	if err != nil {
+		return err
+	}
+
+	return network.StartNode(ctx, selected)
+}
+
+func RestartNode(ctx context.Context, node int, downtime time.Duration) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectNode(ctx, network, node)
+	if err != nil {
+		return err
+	}
+
+	return network.RestartNode(ctx, selected, downtime)
+}
+
+// PartitionNetwork splits the network into groups of nodes which can't reach each other, nodes which are not
+// in any group are isolated. The partition is healed after the given duration, zero keeps it until HealNetwork
+func PartitionNetwork(ctx context.Context, groups [][]int, duration time.Duration) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	partition := make([][]devnet.Node, len(groups))
+
+	for i, group := range groups {
+		for _, node := range group {
+			selected, err := selectNode(ctx, network, node)
+			if err != nil {
+				return err
+			}
+
+			partition[i] = append(partition[i], selected)
+		}
+	}
+
+	if err := network.Partition(ctx, partition...); err != nil {
+		return err
+	}
+
+	if duration == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+	}
+
+	return network.Heal(ctx)
+}
+
+func HealNetwork(ctx context.Context) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	return network.Heal(ctx)
+}
+
+func PauseBlockProduction(ctx context.Context, window time.Duration) error {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return err
+	}
+
+	return network.PauseBlockProduction(ctx, window)
+}
+
+// AwaitRecovery waits until every node of the network progressed past the highest block seen when the step
+// started and all nodes agree on that block, i.e. the cluster recovered from the injected faults and re-synced
+func AwaitRecovery(ctx context.Context, timeout time.Duration) (uint64, error) {
+	network, err := currentNetwork(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	logger := devnet.Logger(ctx)
+	deadline := time.Now().Add(timeout)
+
+	var target uint64
+
+	for _, node := range network.Nodes {
+		// a node which is still starting up is checked in the loop below
+		if blockNum, err := node.BlockNumber(); err == nil && blockNum > target {
+			target = blockNum
+		}
+	}
+
+	target++
+
+	for {
+		heights, err := checkRecovery(ctx, network, target)
+
+		if err == nil {
+			logger.Info("Network recovered", "block", target, "heights", heights)
+			return target, nil
+		}
+
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("network did not recover in %s, heights: %v: %w", timeout, heights, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// checkRecovery checks that all nodes have the same block at the target height
+func checkRecovery(ctx context.Context, network *devnet.Network, target uint64) (map[string]uint64, error) {
+	heights := map[string]uint64{}
+
+	var hash libcommon.Hash
+
+	for _, node := range network.Nodes {
+		blockNum, err := node.BlockNumber()
+		if err != nil {
+			return heights, fmt.Errorf("node %s: %w", node.GetName(), err)
+		}
+
+		heights[node.GetName()] = blockNum
+
+		if blockNum < target {
+			return heights, fmt.Errorf("node %s is at block %d", node.GetName(), blockNum)
+		}
+	}
+
+	for _, node := range network.Nodes {
+		block, err := node.GetBlockByNumber(ctx, rpc.BlockNumber(target), false)
+		if err != nil {
+			return heights, fmt.Errorf("node %s: %w", node.GetName(), err)
+		}
+
+		if hash == (libcommon.Hash{}) {
+			hash = block.Hash
+		} else if block.Hash != hash {
+			return heights, fmt.Errorf("node %s has block %x at %d, expected %x", node.GetName(), block.Hash, target, hash)
+		}
+	}
+
+	return heights, nil
+}
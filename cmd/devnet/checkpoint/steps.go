@@ -0,0 +1,36 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+)
+
+// Steps saving the state of all nodes of the devnet after an expensive setup and rolling back to it, so that
+// the following steps start from the same chain state
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(SaveCheckpoint),
+		scenarios.StepHandler(RestoreCheckpoint),
+	)
+}
+
+func SaveCheckpoint(ctx context.Context, name string) error {
+	networks := devnet.Networks(ctx)
+	if len(networks) == 0 {
+		return fmt.Errorf("no devnet")
+	}
+
+	return devnet.Devnet(networks).Checkpoint(ctx, name)
+}
+
+func RestoreCheckpoint(ctx context.Context, name string) error {
+	networks := devnet.Networks(ctx)
+	if len(networks) == 0 {
+		return fmt.Errorf("no devnet")
+	}
+
+	return devnet.Devnet(networks).Restore(ctx, name)
+}
@@ -0,0 +1,152 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnetutils"
+)
+
+// CheckpointDir is the folder keeping the named checkpoint of a devnet running in dataDir
+func CheckpointDir(dataDir, name string) string {
+	return filepath.Join(dataDir, devnetutils.CheckpointsDir, name)
+}
+
+// Checkpoint stops all nodes of the devnet, snapshots their data folders into <datadir>/checkpoints/<name> and
+// starts them again. Nodes are stopped together rather than one by one, so that databases are closed and the
+// snapshot is consistent across the nodes. An existing checkpoint with the same name is replaced
+func (d Devnet) Checkpoint(ctx context.Context, name string) error {
+	if len(d) == 0 {
+		return fmt.Errorf("no networks to checkpoint")
+	}
+
+	logger := d[0].Logger
+	checkpointDir := CheckpointDir(d[0].DataDir, name)
+	tmpDir := checkpointDir + ".tmp"
+
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+
+	return d.paused(ctx, func(nodes []Node) error {
+		start := time.Now()
+		var cloned int
+
+		for _, node := range nodes {
+			n, err := devnetutils.CopyDir(node.GetDataDir(), filepath.Join(tmpDir, node.GetName()))
+			if err != nil {
+				return fmt.Errorf("checkpoint of node %s: %w", node.GetName(), err)
+			}
+
+			cloned += n
+		}
+
+		if err := os.RemoveAll(checkpointDir); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmpDir, checkpointDir); err != nil {
+			return err
+		}
+
+		logger.Info("Devnet checkpoint saved", "name", name, "nodes", len(nodes), "clonedFiles", cloned, "took", time.Since(start))
+		return nil
+	})
+}
+
+// Restore stops all nodes of the devnet, replaces their data folders by the ones of the named checkpoint and
+// starts them again
+func (d Devnet) Restore(ctx context.Context, name string) error {
+	if len(d) == 0 {
+		return fmt.Errorf("no networks to restore")
+	}
+
+	return d.paused(ctx, func(nodes []Node) error {
+		for _, node := range nodes {
+			if err := os.RemoveAll(node.GetDataDir()); err != nil {
+				return err
+			}
+		}
+
+		return RestoreCheckpoint(d[0].DataDir, name, d[0].Logger)
+	})
+}
+
+// RestoreCheckpoint copies the node data folders of the named checkpoint into dataDir, the nodes must not
+// be running and their data folders must have been cleared, see devnetutils.ClearDevDB
+func RestoreCheckpoint(dataDir, name string, logger log.Logger) error {
+	checkpointDir := CheckpointDir(dataDir, name)
+
+	if !dir.Exist(checkpointDir) {
+		return fmt.Errorf("checkpoint %q not found in %s", name, checkpointDir)
+	}
+
+	nodeDirs, err := os.ReadDir(checkpointDir)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var cloned int
+
+	for _, nodeDir := range nodeDirs {
+		if !nodeDir.IsDir() {
+			continue
+		}
+
+		n, err := devnetutils.CopyDir(filepath.Join(checkpointDir, nodeDir.Name()), filepath.Join(dataDir, nodeDir.Name()))
+		if err != nil {
+			return fmt.Errorf("restore of node %s: %w", nodeDir.Name(), err)
+		}
+
+		cloned += n
+	}
+
+	logger.Info("Devnet checkpoint restored", "name", name, "nodes", len(nodeDirs), "clonedFiles", cloned, "took", time.Since(start))
+	return nil
+}
+
+// paused runs fn on all nodes of the devnet while they are stopped, the nodes which were running are started
+// again afterwards even if fn failed
+func (d Devnet) paused(ctx context.Context, fn func(nodes []Node) error) (err error) {
+	type stopped struct {
+		network *Network
+		node    Node
+	}
+
+	var nodes []Node
+	var toStart []stopped
+
+	defer func() {
+		for _, s := range toStart {
+			if startErr := s.network.StartNode(ctx, s.node); startErr != nil && err == nil {
+				err = startErr
+			}
+		}
+	}()
+
+	for _, network := range d {
+		for _, node := range network.Nodes {
+			nodes = append(nodes, node)
+
+			// nodes stopped by a previous step are kept stopped
+			if n, ok := node.(*devnetNode); ok && !n.running() {
+				continue
+			}
+
+			if err := network.StopNode(ctx, node); err != nil {
+				return err
+			}
+
+			toStart = append(toStart, stopped{network, node})
+		}
+	}
+
+	return fn(nodes)
+}
@@ -108,7 +108,7 @@ func (nw *Network) Start(ctx context.Context) error {
 	}
 
 	for _, node := range nw.Nodes {
-		err := nw.startNode(node)
+		err := nw.startNode(ctx, node)
 		if err != nil {
 			nw.Stop()
 			return err
@@ -137,6 +137,8 @@ func (nw *Network) createNode(nodeArgs Node) (Node, error) {
 		nil,
 		nil,
 		nil,
+		nil,
+		make(chan struct{}),
 	}
 
 	if n.IsBlockProducer() {
@@ -176,7 +178,7 @@ func copyFlags(flags []cli.Flag) []cli.Flag {
 }
 
 // startNode starts an erigon node on the dev chain
-func (nw *Network) startNode(n Node) error {
+func (nw *Network) startNode(ctx context.Context, n Node) error {
 	nw.wg.Add(1)
 
 	node := n.(*devnetNode)
@@ -186,7 +188,22 @@ func (nw *Network) startNode(n Node) error {
 		return err
 	}
 
+	if binary := node.GetBinary(); binary != "" {
+		nw.Logger.Info("Running node", "name", node.GetName(), "binary", binary, "args", args)
+
+		// args[0] is program name
+		if err := node.runBinary(binary, args[1:], nw.Logger); err != nil {
+			return err
+		}
+
+		return nw.versionHandshake(ctx, node)
+	}
+
+	exited := node.exitedCh()
+
 	go func() {
+		defer close(exited)
+
 		nw.Logger.Info("Running node", "name", node.GetName(), "args", args)
 
 		// catch any errors and avoid panics if an error occurs
@@ -214,6 +231,191 @@ func (nw *Network) startNode(n Node) error {
 		return err
 	}
 
+	if node.GetExpectedVersion() != "" {
+		return nw.versionHandshake(ctx, node)
+	}
+
+	return nil
+}
+
+const versionHandshakeTimeout = 2 * time.Minute
+
+// versionHandshake waits for the node's rpc to become available and checks that the node runs expected version
+func (nw *Network) versionHandshake(ctx context.Context, node Node) error {
+	deadline := time.Now().Add(versionHandshakeTimeout)
+
+	for {
+		version, err := node.Web3ClientVersion()
+
+		if err == nil {
+			nw.Logger.Info("Node version", "name", node.GetName(), "version", version)
+
+			if expected := node.GetExpectedVersion(); expected != "" && !strings.Contains(version, expected) {
+				return fmt.Errorf("node %s runs %q, expected version %q", node.GetName(), version, expected)
+			}
+
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %s version handshake: %w", node.GetName(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// StopNode stops a running node and waits for it to exit. The datadir is kept, so the node can be started again
+func (nw *Network) StopNode(ctx context.Context, n Node) error {
+	node, ok := n.(*devnetNode)
+	if !ok {
+		return fmt.Errorf("can't stop node %s: unexpected node type %T", n.GetName(), n)
+	}
+
+	if !node.running() {
+		return nil
+	}
+
+	nw.Logger.Info("Stopping", "node", node.GetName())
+
+	exited := node.exitedCh()
+	go node.Stop()
+
+	select {
+	case <-exited:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StartNode starts a node which was stopped by StopNode
+func (nw *Network) StartNode(ctx context.Context, n Node) error {
+	node, ok := n.(*devnetNode)
+	if !ok {
+		return fmt.Errorf("can't start node %s: unexpected node type %T", n.GetName(), n)
+	}
+
+	if node.running() {
+		return fmt.Errorf("node %s is already running", node.GetName())
+	}
+
+	node.reset(&nw.wg)
+
+	if err := nw.startNode(ctx, node); err != nil {
+		return err
+	}
+
+	for _, service := range nw.Services {
+		service.NodeStarted(ctx, node)
+	}
+
+	return nil
+}
+
+// RestartNode stops the node and starts it again after the given downtime
+func (nw *Network) RestartNode(ctx context.Context, n Node, downtime time.Duration) error {
+	if err := nw.StopNode(ctx, n); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(downtime):
+	}
+
+	return nw.StartNode(ctx, n)
+}
+
+// Partition rewires the static peers of the nodes, so that each node is connected to the nodes of its own
+// group only, and restarts the network. Nodes which are not in any of the groups are isolated
+func (nw *Network) Partition(ctx context.Context, groups ...[]Node) error {
+	peers := map[Node][]string{}
+
+	for _, group := range groups {
+		for _, node := range group {
+			for _, peer := range group {
+				if peer != node {
+					peers[node] = append(peers[node], peer.GetEnodeURL())
+				}
+			}
+		}
+	}
+
+	nw.Logger.Info("Partitioning network", "groups", len(groups))
+
+	return nw.rewirePeers(ctx, func(node Node) []string { return peers[node] })
+}
+
+// Heal connects all the nodes of the network with each other and restarts the network
+func (nw *Network) Heal(ctx context.Context) error {
+	nw.Logger.Info("Healing network partition")
+
+	return nw.rewirePeers(ctx, func(node Node) []string {
+		var peers []string
+
+		for _, peer := range nw.Nodes {
+			if peer != node {
+				peers = append(peers, peer.GetEnodeURL())
+			}
+		}
+
+		return peers
+	})
+}
+
+func (nw *Network) rewirePeers(ctx context.Context, peersOf func(node Node) []string) error {
+	// all nodes are stopped first, so that no node keeps a connection across the partition
+	for _, node := range nw.Nodes {
+		if err := nw.StopNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range nw.Nodes {
+		if node, ok := node.(*devnetNode); ok {
+			if nodeArgs, ok := node.nodeArgs.(interface{ SetStaticPeers(peers []string) }); ok {
+				nodeArgs.SetStaticPeers(peersOf(node))
+			}
+		}
+
+		if err := nw.StartNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PauseBlockProduction stops the block producers for the given window, the rest of the network keeps running
+func (nw *Network) PauseBlockProduction(ctx context.Context, window time.Duration) error {
+	producers := nw.BlockProducers()
+
+	nw.Logger.Info("Pausing block production", "producers", len(producers), "window", window)
+
+	for _, node := range producers {
+		if err := nw.StopNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(window):
+	}
+
+	for _, node := range producers {
+		if err := nw.StartNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
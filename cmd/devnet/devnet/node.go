@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"os"
+	"os/exec"
 	"sync"
 
 	"github.com/c2h5oh/datasize"
@@ -27,7 +29,11 @@ type Node interface {
 	GetName() string
 	ChainID() *big.Int
 	GetHttpPort() int
+	GetAuthRpcPort() int
+	GetDataDir() string
 	GetEnodeURL() string
+	GetBinary() string
+	GetExpectedVersion() string
 	Account() *accounts.Account
 	IsBlockProducer() bool
 	Configure(baseNode args.NodeArgs, nodeNumber int) error
@@ -68,12 +74,21 @@ type devnetNode struct {
 	nodeCfg  *nodecfg.Config
 	ethCfg   *ethconfig.Config
 	ethNode  *enode.ErigonNode
+	proc     *exec.Cmd     // set if node runs as a separate process of args.NodeArgs.Binary
+	exited   chan struct{} // closed once the node's app or process returned
 }
 
 func (n *devnetNode) Stop() {
 	var toClose *enode.ErigonNode
 
 	n.Lock()
+	if n.proc != nil {
+		// process exit is handled by runBinary
+		proc := n.proc
+		n.Unlock()
+		_ = proc.Process.Signal(os.Interrupt)
+		return
+	}
 	if n.ethNode != nil {
 		toClose = n.ethNode
 		n.ethNode = nil
@@ -87,10 +102,25 @@ func (n *devnetNode) Stop() {
 	n.done()
 }
 
+// reset prepares a stopped node to be started again
+func (n *devnetNode) reset(wg *sync.WaitGroup) {
+	n.Lock()
+	defer n.Unlock()
+	n.wg = wg
+	n.startErr = make(chan error)
+	n.exited = make(chan struct{})
+}
+
+func (n *devnetNode) exitedCh() chan struct{} {
+	n.Lock()
+	defer n.Unlock()
+	return n.exited
+}
+
 func (n *devnetNode) running() bool {
 	n.Lock()
 	defer n.Unlock()
-	return n.startErr == nil && n.ethNode != nil
+	return n.startErr == nil && (n.ethNode != nil || n.proc != nil)
 }
 
 func (n *devnetNode) done() {
@@ -127,10 +157,26 @@ func (n *devnetNode) GetHttpPort() int {
 	return n.nodeArgs.GetHttpPort()
 }
 
+func (n *devnetNode) GetAuthRpcPort() int {
+	return n.nodeArgs.GetAuthRpcPort()
+}
+
+func (n *devnetNode) GetDataDir() string {
+	return n.nodeArgs.GetDataDir()
+}
+
 func (n *devnetNode) GetEnodeURL() string {
 	return n.nodeArgs.GetEnodeURL()
 }
 
+func (n *devnetNode) GetBinary() string {
+	return n.nodeArgs.GetBinary()
+}
+
+func (n *devnetNode) GetExpectedVersion() string {
+	return n.nodeArgs.GetExpectedVersion()
+}
+
 func (n *devnetNode) EnableMetrics(int) {
 	panic("not implemented")
 }
@@ -176,6 +222,15 @@ func (n *devnetNode) run(ctx *cli.Context) error {
 		if n.network.Genesis.GasLimit != 0 {
 			n.ethCfg.Genesis.GasLimit = n.network.Genesis.GasLimit
 		}
+
+		// e.g. proof-of-stake dev chain driven by the local consensus layer
+		if n.network.Genesis.Config != nil {
+			n.ethCfg.Genesis.Config = n.network.Genesis.Config
+		}
+
+		if n.network.Genesis.Difficulty != nil {
+			n.ethCfg.Genesis.Difficulty = n.network.Genesis.Difficulty
+		}
 	}
 
 	if n.network.BorStateSyncDelay > 0 {
@@ -208,3 +263,67 @@ func (n *devnetNode) run(ctx *cli.Context) error {
 
 	return err
 }
+
+// runBinary starts the node as a separate process of given erigon binary, args don't include program name
+func (n *devnetNode) runBinary(binary string, args []string, logger log.Logger) error {
+	cmd := exec.Command(binary, args...)
+	// node logs go to --log.dir.path, keep the tail of stderr to report startup failures e.g. unknown flags
+	stderr := &tailBuffer{limit: 4096}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		n.Lock()
+		close(n.exited)
+		n.Unlock()
+		n.done()
+		return fmt.Errorf("can't start %s: %w", binary, err)
+	}
+
+	n.Lock()
+	n.proc = cmd
+	exited := n.exited
+	if n.startErr != nil {
+		close(n.startErr)
+		n.startErr = nil
+	}
+	n.Unlock()
+
+	go func() {
+		defer close(exited)
+		defer n.done()
+		err := cmd.Wait()
+
+		n.Lock()
+		n.proc = nil
+		n.Unlock()
+
+		if err != nil {
+			logger.Warn("Node process exited", "node", n.GetName(), "binary", binary, "err", err, "stderr", stderr.String())
+		}
+	}()
+
+	return nil
+}
+
+// tailBuffer - keeps last `limit` bytes written to it
+type tailBuffer struct {
+	sync.Mutex
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,89 @@
+package devnetutils
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyDir copies the src directory tree into dst, which must not exist. Files are cloned (copy-on-write) where
+// the filesystem supports it and copied otherwise. Returns the number of files which were cloned
+func CopyDir(src, dst string) (cloned int, err error) {
+	if _, err := os.Stat(dst); err == nil {
+		return 0, fmt.Errorf("%s already exists", dst)
+	}
+
+	cloneSupported := true
+
+	err = filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !entry.Type().IsRegular():
+			// sockets, pipes and the like are re-created by the node
+			return nil
+		}
+
+		if cloneSupported {
+			switch err := cloneFile(path, target, info.Mode().Perm()); {
+			case err == nil:
+				cloned++
+				return nil
+			case isCloneUnsupported(err):
+				// a filesystem either supports cloning or not, don't retry for every file
+				cloneSupported = false
+				_ = os.Remove(target)
+			default:
+				return err
+			}
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+
+	return cloned, err
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
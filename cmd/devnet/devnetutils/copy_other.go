@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package devnetutils
+
+import (
+	"errors"
+	"io/fs"
+)
+
+var errCloneUnsupported = errors.New("copy-on-write clone is not supported")
+
+func cloneFile(string, string, fs.FileMode) error {
+	return errCloneUnsupported
+}
+
+func isCloneUnsupported(err error) bool {
+	return errors.Is(err, errCloneUnsupported)
+}
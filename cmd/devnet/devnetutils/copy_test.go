@@ -0,0 +1,37 @@
+package devnetutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDir(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "node-0")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "chaindata"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "chaindata", "mdbx.dat"), []byte("data"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "nodekey"), []byte("key"), 0600))
+
+	dst := filepath.Join(t.TempDir(), "checkpoint", "node-0")
+	_, err := CopyDir(src, dst)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dst, "chaindata", "mdbx.dat"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+
+	info, err := os.Stat(filepath.Join(dst, "nodekey"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the copy is independent of the source
+	require.NoError(t, os.WriteFile(filepath.Join(src, "chaindata", "mdbx.dat"), []byte("changed"), 0644))
+	data, err = os.ReadFile(filepath.Join(dst, "chaindata", "mdbx.dat"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+
+	_, err = CopyDir(src, dst)
+	require.Error(t, err)
+}
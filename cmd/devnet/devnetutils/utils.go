@@ -20,7 +20,10 @@ import (
 
 var ErrInvalidEnodeString = errors.New("invalid enode string")
 
-// ClearDevDB cleans up the dev folder used for the operations
+// CheckpointsDir is the folder of the devnet datadir which keeps checkpoints of the nodes' data folders
+const CheckpointsDir = "checkpoints"
+
+// ClearDevDB cleans up the dev folder used for the operations, checkpoints are kept
 func ClearDevDB(dataDir string, logger log.Logger) error {
 	logger.Info("Deleting nodes' data folders")
 
@@ -31,7 +34,7 @@ func ClearDevDB(dataDir string, logger log.Logger) error {
 	}
 
 	for _, file := range files {
-		if !file.IsDir() || file.Name() == "logs" {
+		if !file.IsDir() || file.Name() == "logs" || file.Name() == CheckpointsDir {
 			continue
 		}
 
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -19,6 +21,8 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
 	_ "github.com/ledgerwatch/erigon/cmd/devnet/accounts/steps"
 	_ "github.com/ledgerwatch/erigon/cmd/devnet/admin"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/chaos"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/checkpoint"
 	_ "github.com/ledgerwatch/erigon/cmd/devnet/contracts/steps"
 	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
 	"github.com/ledgerwatch/erigon/cmd/devnet/devnetutils"
@@ -26,8 +30,21 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/devnet/requests"
 	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
 	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/consistency"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/services/consistency/steps"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/externalcl"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/services/externalcl/steps"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/fixtures"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/services/fixtures/steps"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/loadgen"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/services/loadgen/steps"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/localcl"
+	_ "github.com/ledgerwatch/erigon/cmd/devnet/services/localcl/steps"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/mockheimdall"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/monitoring"
 	"github.com/ledgerwatch/erigon/cmd/devnet/services/polygon"
 	"github.com/ledgerwatch/erigon/cmd/utils/flags"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/params"
 	erigon_app "github.com/ledgerwatch/erigon/turbo/app"
 	"github.com/ledgerwatch/erigon/turbo/debug"
@@ -76,6 +93,40 @@ var (
 		Usage: "Run with a devnet local Heimdall service",
 	}
 
+	MockHeimdallFlag = cli.BoolFlag{
+		Name:  "bor.mockheimdall",
+		Usage: "Run with a mock Heimdall service serving spans, checkpoints, milestones and state sync events without a root chain, listening at --bor.heimdall",
+	}
+
+	MockHeimdallFaultsFlag = cli.StringFlag{
+		Name:  "bor.mockheimdall.faults",
+		Usage: "Faults injected by the mock Heimdall, comma separated: latency=<duration>,errors=<rate>,badroots=<rate>,eventgaps=<rate>",
+	}
+
+	MockHeimdallCheckpointIntervalFlag = cli.DurationFlag{
+		Name:  "bor.mockheimdall.checkpoint",
+		Usage: "Interval between checkpoints of the mock Heimdall",
+		Value: mockheimdall.DefaultConfig.CheckpointInterval,
+	}
+
+	MockHeimdallMilestoneIntervalFlag = cli.DurationFlag{
+		Name:  "bor.mockheimdall.milestone",
+		Usage: "Interval between milestones of the mock Heimdall, 0 disables milestones",
+		Value: mockheimdall.DefaultConfig.MilestoneInterval,
+	}
+
+	MockHeimdallStateSyncIntervalFlag = cli.DurationFlag{
+		Name:  "bor.mockheimdall.statesync",
+		Usage: "Interval between state sync events of the mock Heimdall, 0 disables the events",
+		Value: mockheimdall.DefaultConfig.StateSyncInterval,
+	}
+
+	MockHeimdallSeedFlag = cli.Int64Flag{
+		Name:  "bor.mockheimdall.seed",
+		Usage: "Seed of the mock Heimdall faults and events, runs with the same seed are reproducible",
+		Value: mockheimdall.DefaultConfig.Seed,
+	}
+
 	HeimdallURLFlag = cli.StringFlag{
 		Name:  "bor.heimdall",
 		Usage: "URL of Heimdall service",
@@ -104,6 +155,29 @@ var (
 		Value: metrics.DefaultConfig.Port,
 	}
 
+	MetricsStackFlag = cli.StringFlag{
+		Name:  "metrics.stack",
+		Usage: "Enable metrics on all nodes, scrape them with prometheus and provision grafana with the erigon dashboards, one of: auto, docker, binary, none (only write the configs). Requires --metrics",
+	}
+
+	MetricsDashboardsFlag = cli.StringFlag{
+		Name:  "metrics.dashboards",
+		Usage: "Grafana dashboards provisioned by --metrics.stack",
+		Value: monitoring.DefaultConfig.DashboardsDir,
+	}
+
+	PrometheusPortFlag = cli.IntFlag{
+		Name:  "metrics.prometheus.port",
+		Usage: "Prometheus HTTP server listening port, used by --metrics.stack",
+		Value: monitoring.DefaultConfig.PrometheusPort,
+	}
+
+	GrafanaPortFlag = cli.IntFlag{
+		Name:  "metrics.grafana.port",
+		Usage: "Grafana HTTP server listening port, used by --metrics.stack",
+		Value: monitoring.DefaultConfig.GrafanaPort,
+	}
+
 	DiagnosticsURLFlag = cli.StringFlag{
 		Name:  "diagnostics.addr",
 		Usage: "Address of the diagnostics system provided by the support team, include unique session PIN",
@@ -141,6 +215,118 @@ var (
 		Name:  "wait",
 		Usage: "Wait until interrupted after all scenarios have run",
 	}
+
+	LoadGenTPSFlag = cli.UintFlag{
+		Name:  "loadgen.tps",
+		Usage: "Target transactions per second sent by the load-generator scenario",
+		Value: loadgen.DefaultConfig.TPS,
+	}
+
+	LoadGenDurationFlag = cli.DurationFlag{
+		Name:  "loadgen.duration",
+		Usage: "How long the load-generator scenario sends transactions",
+		Value: loadgen.DefaultConfig.Duration,
+	}
+
+	LoadGenMixFlag = cli.StringFlag{
+		Name:  "loadgen.mix",
+		Usage: "Traffic mix of the load-generator scenario: comma separated kind=weight pairs, kinds: legacy,dynamic,blob,deploy,nested",
+		Value: loadgen.DefaultConfig.Mix.String(),
+	}
+
+	LoadGenSendersFlag = cli.UintFlag{
+		Name:  "loadgen.senders",
+		Usage: "Number of accounts the load-generator scenario sends transactions from",
+		Value: loadgen.DefaultConfig.Senders,
+	}
+
+	FixturesFlag = cli.BoolFlag{
+		Name:  "fixtures",
+		Usage: "Fund fixture accounts in genesis and deploy ERC-20, ERC-721, multicall and create2 deployer contracts at deterministic addresses on startup",
+	}
+
+	FixturesAccountsFlag = cli.UintFlag{
+		Name:  "fixtures.accounts",
+		Usage: "Number of fixture accounts funded in genesis",
+		Value: fixtures.DefaultConfig.Accounts,
+	}
+
+	FixturesBalanceFlag = cli.Float64Flag{
+		Name:  "fixtures.balance",
+		Usage: "Ether allocated in genesis to every fixture account",
+		Value: fixtures.DefaultConfig.Balance,
+	}
+
+	LocalCLFlag = cli.BoolFlag{
+		Name:  "localcl",
+		Usage: "Run the dev chain as proof-of-stake with Cancun from genesis, blocks are proposed via the engine api by a local consensus layer",
+	}
+
+	LocalCLSlotFlag = cli.DurationFlag{
+		Name:  "localcl.slot",
+		Usage: "Interval between blocks proposed by the local consensus layer",
+		Value: localcl.DefaultConfig.SlotTime,
+	}
+
+	LocalCLAPIPortFlag = cli.IntFlag{
+		Name:  "localcl.api.port",
+		Usage: "Port of the beacon api (blob sidecars) served by the local consensus layer",
+		Value: localcl.DefaultConfig.APIPort,
+	}
+
+	LocalCLPragueFlag = cli.BoolFlag{
+		Name:  "localcl.prague",
+		Usage: "Activate Prague from genesis on the local consensus layer dev chain",
+	}
+
+	ExternalCLFlag = cli.StringFlag{
+		Name:  "externalcl",
+		Usage: "Run the dev chain as proof-of-stake with Cancun from genesis, driven by beacon nodes of an external consensus client: lighthouse or teku",
+	}
+
+	ExternalCLBinaryFlag = cli.StringFlag{
+		Name:  "externalcl.binary",
+		Usage: "Path of the external consensus client binary, the client name is looked up in PATH by default",
+	}
+
+	ExternalCLValidatorsFlag = cli.IntFlag{
+		Name:  "externalcl.validators",
+		Usage: "Number of genesis validators of the external consensus layer, split between the block producers",
+		Value: externalcl.DefaultConfig.Validators,
+	}
+
+	ExternalCLSlotFlag = cli.DurationFlag{
+		Name:  "externalcl.slot",
+		Usage: "Slot time of the external consensus layer, whole seconds",
+		Value: externalcl.DefaultConfig.SlotTime,
+	}
+
+	CheckpointSaveFlag = cli.StringFlag{
+		Name:  "checkpoint.save",
+		Usage: "Save the data folders of all nodes as the named checkpoint once the scenarios have run",
+	}
+
+	CheckpointRestoreFlag = cli.StringFlag{
+		Name:  "checkpoint.restore",
+		Usage: "Start the nodes from the named checkpoint instead of an empty chain, see --checkpoint.save",
+	}
+
+	ConsistencyFlag = cli.BoolFlag{
+		Name:  "consistency",
+		Usage: "Periodically compare the canonical chains of all nodes of every network, steps of the running scenario fail once the nodes diverge",
+	}
+
+	ConsistencyIntervalFlag = cli.DurationFlag{
+		Name:  "consistency.interval",
+		Usage: "Interval between checks of the consistency checker",
+		Value: consistency.DefaultConfig.Interval,
+	}
+
+	ConsistencyDepthFlag = cli.Uint64Flag{
+		Name:  "consistency.depth",
+		Usage: "State and receipts roots are compared this many blocks below the lowest head of the nodes",
+		Value: consistency.DefaultConfig.Depth,
+	}
 )
 
 type PanicHandler struct {
@@ -165,11 +351,21 @@ func main() {
 		&BaseRpcPortFlag,
 		&WithoutHeimdallFlag,
 		&LocalHeimdallFlag,
+		&MockHeimdallFlag,
+		&MockHeimdallFaultsFlag,
+		&MockHeimdallCheckpointIntervalFlag,
+		&MockHeimdallMilestoneIntervalFlag,
+		&MockHeimdallStateSyncIntervalFlag,
+		&MockHeimdallSeedFlag,
 		&HeimdallURLFlag,
 		&BorSprintSizeFlag,
 		&MetricsEnabledFlag,
 		&MetricsNodeFlag,
 		&MetricsPortFlag,
+		&MetricsStackFlag,
+		&MetricsDashboardsFlag,
+		&PrometheusPortFlag,
+		&GrafanaPortFlag,
 		&DiagnosticsURLFlag,
 		&insecureFlag,
 		&metricsURLsFlag,
@@ -180,6 +376,26 @@ func main() {
 		&logging.LogConsoleVerbosityFlag,
 		&logging.LogDirVerbosityFlag,
 		&GasLimitFlag,
+		&LoadGenTPSFlag,
+		&LoadGenDurationFlag,
+		&LoadGenMixFlag,
+		&LoadGenSendersFlag,
+		&FixturesFlag,
+		&FixturesAccountsFlag,
+		&FixturesBalanceFlag,
+		&LocalCLFlag,
+		&LocalCLSlotFlag,
+		&LocalCLAPIPortFlag,
+		&LocalCLPragueFlag,
+		&ExternalCLFlag,
+		&ExternalCLBinaryFlag,
+		&ExternalCLValidatorsFlag,
+		&ExternalCLSlotFlag,
+		&CheckpointSaveFlag,
+		&CheckpointRestoreFlag,
+		&ConsistencyFlag,
+		&ConsistencyIntervalFlag,
+		&ConsistencyDepthFlag,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -243,22 +459,51 @@ func mainContext(ctx *cli.Context) error {
 		return err
 	}
 
+	if checkpoint := ctx.String(CheckpointRestoreFlag.Name); checkpoint != "" {
+		if err := devnet.RestoreCheckpoint(dataDir, checkpoint, logger); err != nil {
+			return err
+		}
+	}
+
 	network, err := initDevnet(ctx, logger)
 	if err != nil {
 		return err
 	}
 
-	if err = initDevnetMetrics(ctx, network); err != nil {
+	metricsTargets, err := initDevnetMetrics(ctx, network)
+	if err != nil {
 		return err
 	}
 
+	if err = initLoadGenerator(ctx, network); err != nil {
+		return err
+	}
+
+	initFixtures(ctx, network)
+	initConsistencyChecker(ctx, network, logger)
+
 	logger.Info("Starting Devnet")
 	runCtx, err := network.Start(logger)
 	if err != nil {
 		return fmt.Errorf("devnet start failed: %w", err)
 	}
 
-	go handleTerminationSignals(network.Stop, logger)
+	stop := network.Stop
+
+	if len(metricsTargets) > 0 {
+		stack, err := startMetricsStack(ctx, network, metricsTargets, logger)
+		if err != nil {
+			network.Stop()
+			return err
+		}
+
+		stop = func() {
+			network.Stop()
+			stack.Stop()
+		}
+	}
+
+	go handleTerminationSignals(stop, logger)
 	go connectDiagnosticsIfEnabled(ctx, logger)
 
 	enabledScenarios := strings.Split(ctx.String(ScenariosFlag.Name), ",")
@@ -267,12 +512,18 @@ func mainContext(ctx *cli.Context) error {
 		return err
 	}
 
+	if checkpoint := ctx.String(CheckpointSaveFlag.Name); checkpoint != "" {
+		if err = network.Checkpoint(runCtx, checkpoint); err != nil {
+			return err
+		}
+	}
+
 	if ctx.Bool(WaitFlag.Name) {
 		logger.Info("Waiting")
 		network.Wait()
 	} else {
 		logger.Info("Stopping Networks")
-		network.Stop()
+		stop()
 	}
 
 	return nil
@@ -285,6 +536,12 @@ func allScenarios(cliCtx *cli.Context, runCtx devnet.Context) scenarios.Scenario
 	const recipientAddress = "0x71562b71999873DB5b286dF957af199Ec94617F7"
 	const sendValue uint64 = 10000
 
+	consumer := int(cliCtx.Uint(BlockProducersFlag.Name))
+	producers := make([]int, consumer)
+	for i := range producers {
+		producers[i] = i
+	}
+
 	return scenarios.Scenarios{
 		"dynamic-tx-node-0": {
 			Context: runCtx.WithCurrentNetwork(0).WithCurrentNode(0),
@@ -340,6 +597,50 @@ func allScenarios(cliCtx *cli.Context, runCtx devnet.Context) scenarios.Scenario
 				{Text: "SendTxLoad", Args: []any{recipientAddress, accounts.DevAddress, sendValue, cliCtx.Uint(txCountFlag.Name)}},
 			},
 		},
+		"chaos": {
+			// the dev network runs the block producers followed by a single block consumer
+			Context: runCtx.WithCurrentNetwork(0).WithCurrentNode(0),
+			Steps: []*scenarios.Step{
+				{Text: "PingErigonRpc"},
+				{Text: "AwaitRecovery", Args: []any{2 * time.Minute}},
+				{Text: "RestartNode", Args: []any{consumer, 10 * time.Second}},
+				{Text: "AwaitRecovery", Args: []any{2 * time.Minute}},
+				{Text: "PartitionNetwork", Args: []any{[][]int{producers}, time.Minute}},
+				{Text: "AwaitRecovery", Args: []any{2 * time.Minute}},
+				{Text: "PauseBlockProduction", Args: []any{30 * time.Second}},
+				{Text: "AwaitRecovery", Args: []any{2 * time.Minute}},
+			},
+		},
+		"load-generator": {
+			Context: runCtx.WithCurrentNetwork(0),
+			Steps: []*scenarios.Step{
+				{Text: "PingErigonRpc"},
+				{Text: "GenerateLoad"},
+			},
+		},
+		"fixtures": {
+			Context: runCtx.WithCurrentNetwork(0),
+			Steps: []*scenarios.Step{
+				{Text: "PingErigonRpc"},
+				{Text: "CheckFixtures"},
+			},
+		},
+		"blob-tx": {
+			Context: runCtx.WithCurrentNetwork(0),
+			Steps: []*scenarios.Step{
+				{Text: "InitSubscriptions", Args: []any{[]requests.SubMethod{requests.Methods.ETHNewHeads}}},
+				{Text: "PingErigonRpc"},
+				{Text: "SendBlobTransactions", Args: []any{accounts.DevAddress, 4, 2}},
+				{Text: "CheckBlobAvailability"},
+			},
+		},
+		"external-cl": {
+			Context: runCtx.WithCurrentNetwork(0),
+			Steps: []*scenarios.Step{
+				{Text: "PingErigonRpc"},
+				{Text: "CheckBeaconHeads", Args: []any{uint64(8)}},
+			},
+		},
 	}
 }
 
@@ -411,11 +712,46 @@ func initDevnet(ctx *cli.Context, logger log.Logger) (devnet.Devnet, error) {
 			heimdallURL := ctx.String(HeimdallURLFlag.Name)
 			sprintSize := uint64(ctx.Int(BorSprintSizeFlag.Name))
 			return networks.NewBorDevnetWithLocalHeimdall(dataDir, baseRpcHost, baseRpcPort, heimdallURL, sprintSize, producerCount, gasLimit, logger, consoleLogLevel, dirLogLevel), nil
+		} else if ctx.Bool(MockHeimdallFlag.Name) {
+			heimdallConfig, err := mockHeimdallConfig(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			sprintSize := uint64(ctx.Int(BorSprintSizeFlag.Name))
+			return networks.NewBorDevnetWithMockHeimdall(dataDir, baseRpcHost, baseRpcPort, heimdallConfig, sprintSize, producerCount, gasLimit, logger, consoleLogLevel, dirLogLevel), nil
 		} else {
 			return networks.NewBorDevnetWithRemoteHeimdall(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, logger, consoleLogLevel, dirLogLevel), nil
 		}
 
 	case networkname.DevChainName:
+		if ctx.IsSet(ExternalCLFlag.Name) {
+			if ctx.Bool(LocalCLFlag.Name) {
+				return nil, fmt.Errorf("%s and %s are mutually exclusive", LocalCLFlag.Name, ExternalCLFlag.Name)
+			}
+
+			client, err := externalcl.ParseClient(ctx.String(ExternalCLFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+
+			clConfig := externalcl.DefaultConfig
+			clConfig.Client = client
+			clConfig.Binary = ctx.String(ExternalCLBinaryFlag.Name)
+			clConfig.Dir = filepath.Join(dataDir, "externalcl")
+			clConfig.Validators = ctx.Int(ExternalCLValidatorsFlag.Name)
+			clConfig.SlotTime = ctx.Duration(ExternalCLSlotFlag.Name)
+			return networks.NewDevDevnetWithExternalCL(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, clConfig, logger, consoleLogLevel, dirLogLevel), nil
+		}
+
+		if ctx.Bool(LocalCLFlag.Name) {
+			clConfig := localcl.DefaultConfig
+			clConfig.SlotTime = ctx.Duration(LocalCLSlotFlag.Name)
+			clConfig.APIPort = ctx.Int(LocalCLAPIPortFlag.Name)
+			clConfig.Prague = ctx.Bool(LocalCLPragueFlag.Name)
+			return networks.NewDevDevnetWithLocalCL(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, clConfig, logger, consoleLogLevel, dirLogLevel), nil
+		}
+
 		return networks.NewDevDevnet(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, logger, consoleLogLevel, dirLogLevel), nil
 
 	default:
@@ -423,23 +759,166 @@ func initDevnet(ctx *cli.Context, logger log.Logger) (devnet.Devnet, error) {
 	}
 }
 
-func initDevnetMetrics(ctx *cli.Context, network devnet.Devnet) error {
+func mockHeimdallConfig(ctx *cli.Context) (mockheimdall.Config, error) {
+	cfg := mockheimdall.DefaultConfig
+
+	heimdallURL, err := url.Parse(ctx.String(HeimdallURLFlag.Name))
+	if err != nil || len(heimdallURL.Host) == 0 {
+		return cfg, fmt.Errorf("invalid %s: %q", HeimdallURLFlag.Name, ctx.String(HeimdallURLFlag.Name))
+	}
+
+	faults, err := mockheimdall.ParseFaults(ctx.String(MockHeimdallFaultsFlag.Name))
+	if err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", MockHeimdallFaultsFlag.Name, err)
+	}
+
+	cfg.ListenAddr = heimdallURL.Host
+	cfg.Faults = faults
+	cfg.CheckpointInterval = ctx.Duration(MockHeimdallCheckpointIntervalFlag.Name)
+	cfg.MilestoneInterval = ctx.Duration(MockHeimdallMilestoneIntervalFlag.Name)
+	cfg.StateSyncInterval = ctx.Duration(MockHeimdallStateSyncIntervalFlag.Name)
+	cfg.Seed = ctx.Int64(MockHeimdallSeedFlag.Name)
+
+	return cfg, nil
+}
+
+// metricsTarget - node with metrics enabled for --metrics.stack, node names are known once the network is started
+type metricsTarget struct {
+	network int
+	node    int
+	port    int
+}
+
+func initDevnetMetrics(ctx *cli.Context, network devnet.Devnet) ([]metricsTarget, error) {
 	metricsEnabled := ctx.Bool(MetricsEnabledFlag.Name)
 	metricsNode := ctx.Int(MetricsNodeFlag.Name)
 	metricsPort := ctx.Int(MetricsPortFlag.Name)
 
 	if !metricsEnabled {
-		return nil
+		if ctx.IsSet(MetricsStackFlag.Name) {
+			return nil, fmt.Errorf("initDevnetMetrics: %s requires %s", MetricsStackFlag.Name, MetricsEnabledFlag.Name)
+		}
+
+		return nil, nil
+	}
+
+	if ctx.IsSet(MetricsStackFlag.Name) {
+		if _, err := monitoring.ParseMode(ctx.String(MetricsStackFlag.Name)); err != nil {
+			return nil, fmt.Errorf("initDevnetMetrics: %w", err)
+		}
+
+		// every node of every network gets its own metrics port
+		var targets []metricsTarget
+
+		for n, nw := range network {
+			for i, nodeArgs := range nw.Nodes {
+				port := metricsPort + len(targets)
+				nodeArgs.EnableMetrics(port)
+				targets = append(targets, metricsTarget{network: n, node: i, port: port})
+			}
+		}
+
+		return targets, nil
 	}
 
 	for _, nw := range network {
 		for i, nodeArgs := range nw.Nodes {
 			if metricsEnabled && (metricsNode == i) {
 				nodeArgs.EnableMetrics(metricsPort)
-				return nil
+				return nil, nil
 			}
 		}
 	}
 
-	return fmt.Errorf("initDevnetMetrics: not found %s=%d", MetricsNodeFlag.Name, metricsNode)
+	return nil, fmt.Errorf("initDevnetMetrics: not found %s=%d", MetricsNodeFlag.Name, metricsNode)
+}
+
+func startMetricsStack(ctx *cli.Context, network devnet.Devnet, metricsTargets []metricsTarget, logger log.Logger) (*monitoring.Stack, error) {
+	mode, err := monitoring.ParseMode(ctx.String(MetricsStackFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := monitoring.DefaultConfig
+	cfg.Dir = filepath.Join(ctx.String(DataDirFlag.Name), "monitoring")
+	cfg.Mode = mode
+	cfg.DashboardsDir = ctx.String(MetricsDashboardsFlag.Name)
+	cfg.PrometheusPort = ctx.Int(PrometheusPortFlag.Name)
+	cfg.GrafanaPort = ctx.Int(GrafanaPortFlag.Name)
+
+	targets := make([]monitoring.Target, 0, len(metricsTargets))
+
+	for _, target := range metricsTargets {
+		targets = append(targets, monitoring.Target{
+			Node: network[target.network].Nodes[target.node].GetName(),
+			Addr: fmt.Sprintf("localhost:%d", target.port),
+		})
+	}
+
+	stack := monitoring.NewStack(cfg, targets, logger)
+
+	if err := stack.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return stack, nil
+}
+
+func initFixtures(ctx *cli.Context, network devnet.Devnet) {
+	if !ctx.Bool(FixturesFlag.Name) {
+		return
+	}
+
+	cfg := fixtures.DefaultConfig
+	cfg.Accounts = ctx.Uint(FixturesAccountsFlag.Name)
+	cfg.Balance = ctx.Float64(FixturesBalanceFlag.Name)
+
+	// fixtures are deployed to the first network, the accounts are funded in its genesis
+	nw := network[0]
+	f := fixtures.NewFixtures(nw.Chain, cfg)
+
+	if nw.Genesis == nil {
+		nw.Genesis = &types.Genesis{}
+	}
+
+	if nw.Genesis.Alloc == nil {
+		nw.Genesis.Alloc = types.GenesisAlloc{}
+	}
+
+	f.Alloc(nw.Genesis.Alloc)
+	nw.Services = append(nw.Services, f)
+}
+
+func initLoadGenerator(ctx *cli.Context, network devnet.Devnet) error {
+	mix, err := loadgen.ParseMix(ctx.String(LoadGenMixFlag.Name))
+
+	if err != nil {
+		return fmt.Errorf("initLoadGenerator: %w", err)
+	}
+
+	cfg := loadgen.DefaultConfig
+	cfg.TPS = ctx.Uint(LoadGenTPSFlag.Name)
+	cfg.Duration = ctx.Duration(LoadGenDurationFlag.Name)
+	cfg.Mix = mix
+	cfg.Senders = ctx.Uint(LoadGenSendersFlag.Name)
+
+	// load goes to the first network, block producers and consumers are targeted round-robin
+	network[0].Services = append(network[0].Services, loadgen.NewLoadGenerator(cfg))
+
+	return nil
+}
+
+func initConsistencyChecker(ctx *cli.Context, network devnet.Devnet, logger log.Logger) {
+	if !ctx.Bool(ConsistencyFlag.Name) {
+		return
+	}
+
+	cfg := consistency.DefaultConfig
+	cfg.Interval = ctx.Duration(ConsistencyIntervalFlag.Name)
+	cfg.Depth = ctx.Uint64(ConsistencyDepthFlag.Name)
+
+	// nodes are compared within their network
+	for _, nw := range network {
+		nw.Services = append(nw.Services, consistency.NewChecker(cfg, logger))
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/devnet/args"
 	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
 	account_services "github.com/ledgerwatch/erigon/cmd/devnet/services/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/mockheimdall"
 	"github.com/ledgerwatch/erigon/cmd/devnet/services/polygon"
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/core/types"
@@ -73,7 +74,7 @@ func NewBorDevnetWithHeimdall(
 	dataDir string,
 	baseRpcHost string,
 	baseRpcPort int,
-	heimdall *polygon.Heimdall,
+	heimdall devnet.Service,
 	heimdallURL string,
 	checkpointOwner *accounts.Account,
 	producerCount int,
@@ -245,3 +246,38 @@ func NewBorDevnetWithLocalHeimdall(
 		false,
 		logger, consoleLogLevel, dirLogLevel)
 }
+
+// NewBorDevnetWithMockHeimdall - bor devnet with the mock heimdall, which doesn't need the root chain contracts
+// and can inject faults, see mockheimdall.Faults
+func NewBorDevnetWithMockHeimdall(
+	dataDir string,
+	baseRpcHost string,
+	baseRpcPort int,
+	heimdallConfig mockheimdall.Config,
+	sprintSize uint64,
+	producerCount int,
+	gasLimit uint64,
+	logger log.Logger,
+	consoleLogLevel log.Lvl,
+	dirLogLevel log.Lvl,
+) devnet.Devnet {
+	config := *params.BorDevnetChainConfig
+	borConfig := config.Bor.(*borcfg.BorConfig)
+	if sprintSize > 0 {
+		borConfig.Sprint = map[string]uint64{"0": sprintSize}
+	}
+
+	heimdall := mockheimdall.NewMockHeimdall(heimdallConfig, &config, logger)
+
+	return NewBorDevnetWithHeimdall(
+		dataDir,
+		baseRpcHost,
+		baseRpcPort,
+		heimdall,
+		heimdall.URL(),
+		accounts.NewAccount("checkpoint-owner"),
+		producerCount,
+		gasLimit,
+		heimdallConfig.MilestoneInterval > 0,
+		logger, consoleLogLevel, dirLogLevel)
+}
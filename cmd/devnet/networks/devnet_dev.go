@@ -1,6 +1,7 @@
 package networks
 
 import (
+	"math/big"
 	"strconv"
 
 	"github.com/ledgerwatch/erigon-lib/chain/networkname"
@@ -8,7 +9,10 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/devnet/args"
 	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
 	account_services "github.com/ledgerwatch/erigon/cmd/devnet/services/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/externalcl"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/localcl"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -22,6 +26,72 @@ func NewDevDevnet(
 	consoleLogLevel log.Lvl,
 	dirLogLevel log.Lvl,
 ) devnet.Devnet {
+	return devnet.Devnet{newDevNetwork(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, false, logger, consoleLogLevel, dirLogLevel)}
+}
+
+// NewDevDevnetWithLocalCL - proof-of-stake dev network with Cancun (and optionally Prague) active from genesis,
+// blocks are proposed via the engine api by the local consensus layer service
+func NewDevDevnetWithLocalCL(
+	dataDir string,
+	baseRpcHost string,
+	baseRpcPort int,
+	producerCount int,
+	gasLimit uint64,
+	clConfig localcl.Config,
+	logger log.Logger,
+	consoleLogLevel log.Lvl,
+	dirLogLevel log.Lvl,
+) devnet.Devnet {
+	network := newDevNetwork(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, true, logger, consoleLogLevel, dirLogLevel)
+
+	chainConfig := *params.AllProtocolChanges
+
+	if clConfig.Prague {
+		chainConfig.PragueTime = big.NewInt(0)
+	}
+
+	network.Genesis.Config = &chainConfig
+	network.Genesis.Difficulty = big.NewInt(0)
+	network.Services = append(network.Services, localcl.NewLocalCL(clConfig, logger))
+
+	return devnet.Devnet{network}
+}
+
+// NewDevDevnetWithExternalCL - proof-of-stake dev network with Cancun active from genesis, driven by beacon nodes
+// of an external consensus client, see externalcl.ExternalCL
+func NewDevDevnetWithExternalCL(
+	dataDir string,
+	baseRpcHost string,
+	baseRpcPort int,
+	producerCount int,
+	gasLimit uint64,
+	clConfig externalcl.Config,
+	logger log.Logger,
+	consoleLogLevel log.Lvl,
+	dirLogLevel log.Lvl,
+) devnet.Devnet {
+	network := newDevNetwork(dataDir, baseRpcHost, baseRpcPort, producerCount, gasLimit, true, logger, consoleLogLevel, dirLogLevel)
+
+	chainConfig := *params.AllProtocolChanges
+
+	network.Genesis.Config = &chainConfig
+	network.Genesis.Difficulty = big.NewInt(0)
+	network.Services = append(network.Services, externalcl.NewExternalCL(clConfig, logger))
+
+	return devnet.Devnet{network}
+}
+
+func newDevNetwork(
+	dataDir string,
+	baseRpcHost string,
+	baseRpcPort int,
+	producerCount int,
+	gasLimit uint64,
+	externalCL bool,
+	logger log.Logger,
+	consoleLogLevel log.Lvl,
+	dirLogLevel log.Lvl,
+) *devnet.Network {
 	faucetSource := accounts.NewAccount("faucet-source")
 
 	var nodes []devnet.Node
@@ -35,12 +105,13 @@ func NewDevDevnet(
 			NodeArgs: args.NodeArgs{
 				ConsoleVerbosity: strconv.Itoa(int(consoleLogLevel)),
 				DirVerbosity:     strconv.Itoa(int(dirLogLevel)),
+				ExternalCL:       externalCL,
 			},
 			AccountSlots: 200,
 		})
 	}
 
-	network := devnet.Network{
+	return &devnet.Network{
 		DataDir:            dataDir,
 		Chain:              networkname.DevChainName,
 		Logger:             logger,
@@ -62,9 +133,8 @@ func NewDevDevnet(
 				NodeArgs: args.NodeArgs{
 					ConsoleVerbosity: "0",
 					DirVerbosity:     "5",
+					ExternalCL:       externalCL,
 				},
 			}),
 	}
-
-	return devnet.Devnet{&network}
 }
@@ -15,3 +15,13 @@ func (reqGen *requestGenerator) AdminNodeInfo() (p2p.NodeInfo, error) {
 
 	return result, nil
 }
+
+func (reqGen *requestGenerator) Web3ClientVersion() (string, error) {
+	var result string
+
+	if err := reqGen.rpcCall(context.Background(), &result, Methods.Web3ClientVersion); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
@@ -32,6 +32,10 @@ func (n NopRequestGenerator) AdminNodeInfo() (p2p.NodeInfo, error) {
 	return p2p.NodeInfo{}, ErrNotImplemented
 }
 
+func (n NopRequestGenerator) Web3ClientVersion() (string, error) {
+	return "", ErrNotImplemented
+}
+
 func (n NopRequestGenerator) GetBlockByNumber(ctx context.Context, blockNum rpc.BlockNumber, withTxs bool) (*Block, error) {
 	return nil, ErrNotImplemented
 }
@@ -56,6 +56,7 @@ type RequestGenerator interface {
 	PingErigonRpc() PingResult
 	GetBalance(address libcommon.Address, blockRef rpc.BlockReference) (*big.Int, error)
 	AdminNodeInfo() (p2p.NodeInfo, error)
+	Web3ClientVersion() (string, error)
 	GetBlockByNumber(ctx context.Context, blockNum rpc.BlockNumber, withTxs bool) (*Block, error)
 	GetTransactionByHash(hash libcommon.Hash) (*jsonrpc.RPCTransaction, error)
 	GetTransactionReceipt(ctx context.Context, hash libcommon.Hash) (*types.Receipt, error)
@@ -111,6 +112,8 @@ var Methods = struct {
 	ETHBlockNumber RPCMethod
 	// AdminNodeInfo represents the admin_nodeInfo method
 	AdminNodeInfo RPCMethod
+	// Web3ClientVersion represents the web3_clientVersion method
+	Web3ClientVersion RPCMethod
 	// TxpoolContent represents the txpool_content method
 	TxpoolContent RPCMethod
 	// OTSGetBlockDetails represents the ots_getBlockDetails method
@@ -137,6 +140,7 @@ var Methods = struct {
 	ETHGetLogs:               "eth_getLogs",
 	ETHBlockNumber:           "eth_blockNumber",
 	AdminNodeInfo:            "admin_nodeInfo",
+	Web3ClientVersion:        "web3_clientVersion",
 	TxpoolContent:            "txpool_content",
 	OTSGetBlockDetails:       "ots_getBlockDetails",
 	ETHNewHeads:              "eth_newHeads",
@@ -44,9 +44,10 @@ func (r *runner) concurrent(ctx context.Context, rate int) (err error) {
 
 	simulationContext := SimulationContext{
 		suite: &suite{
-			randomize:      r.randomize,
-			defaultContext: ctx,
-			stepRunners:    stepRunners(ctx),
+			randomize:         r.randomize,
+			defaultContext:    ctx,
+			stepRunners:       stepRunners(ctx),
+			afterStepHandlers: afterStepHookRegistry,
 		},
 	}
 
@@ -72,6 +72,13 @@ func MustRegisterStepHandlers(handlers ...stepHandler) {
 	}
 }
 
+var afterStepHookRegistry []AfterStepHook
+
+// RegisterAfterStepHooks - hooks run after every step of every scenario, an error returned by a hook fails the step
+func RegisterAfterStepHooks(hooks ...AfterStepHook) {
+	afterStepHookRegistry = append(afterStepHookRegistry, hooks...)
+}
+
 func StepHandler(handler interface{}, matchExpressions ...string) stepHandler {
 	return stepHandler{reflect.ValueOf(handler), matchExpressions}
 }
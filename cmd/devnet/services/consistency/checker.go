@@ -0,0 +1,303 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+type Config struct {
+	Interval      time.Duration // between checks
+	Depth         uint64        // state and receipts are compared this many blocks below the lowest head of the nodes
+	Confirmations int           // checks in a row a difference has to be seen by, short-lived forks near the head are tolerated
+}
+
+var DefaultConfig = Config{
+	Interval:      10 * time.Second,
+	Depth:         4,
+	Confirmations: 3,
+}
+
+var _ devnet.Service = (*Checker)(nil)
+
+// Checker - devnet service which periodically compares canonical chains of all nodes of the network: the hash of
+// the block at the lowest head of the nodes, and the hash, state root and receipts root of the block `Depth` blocks
+// below it. Once a difference persists for `Confirmations` checks, the network is considered diverged: Err returns
+// the diff report and the scenario steps fail (see consistency/steps). Nodes which don't respond, for example
+// stopped by chaos steps, are left out of the check.
+type Checker struct {
+	sync.Mutex
+	cfg    Config
+	logger log.Logger
+	nodes  []devnet.Node
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	differences int         // checks in a row which found a difference
+	diverged    *Divergence // sticky, the first confirmed divergence
+}
+
+func NewChecker(cfg Config, logger log.Logger) *Checker {
+	return &Checker{cfg: cfg, logger: logger}
+}
+
+func (c *Checker) Start(ctx context.Context) error {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go c.run(ctx)
+
+	return nil
+}
+
+func (c *Checker) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+}
+
+func (c *Checker) NodeCreated(_ context.Context, node devnet.Node) {
+	c.Lock()
+	defer c.Unlock()
+	c.nodes = append(c.nodes, node)
+}
+
+func (c *Checker) NodeStarted(_ context.Context, _ devnet.Node) {
+}
+
+// Err returns the divergence found by the checker, nil while the nodes agree
+func (c *Checker) Err() error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.diverged == nil {
+		return nil
+	}
+
+	return c.diverged
+}
+
+func (c *Checker) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := c.Check(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Debug("[consistency] check skipped", "err", err)
+		}
+	}
+}
+
+// Check compares the chains of the nodes once. Returns the found difference (confirmed or not), nil if the nodes agree.
+// Error is returned if the check couldn't be made, for example while fewer than two nodes respond.
+func (c *Checker) Check(ctx context.Context) (*Divergence, error) {
+	c.Lock()
+	nodes := append([]devnet.Node(nil), c.nodes...)
+	c.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Interval)
+	defer cancel()
+
+	heads := make([]uint64, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+
+	for i, node := range nodes {
+		wg.Add(1)
+
+		go func(i int, node devnet.Node) {
+			defer wg.Done()
+			heads[i], errs[i] = nodeHead(ctx, node)
+		}(i, node)
+	}
+
+	wg.Wait()
+
+	var head uint64
+	var responding []devnet.Node
+
+	for i, node := range nodes {
+		if errs[i] != nil {
+			continue
+		}
+
+		if len(responding) == 0 || heads[i] < head {
+			head = heads[i]
+		}
+
+		responding = append(responding, node)
+	}
+
+	if len(responding) < 2 {
+		return nil, fmt.Errorf("%d of %d nodes respond", len(responding), len(nodes))
+	}
+
+	if head < c.cfg.Depth {
+		return nil, fmt.Errorf("head %d is below depth %d", head, c.cfg.Depth)
+	}
+
+	compared := make([]NodeView, len(responding))
+
+	for i, node := range responding {
+		wg.Add(1)
+
+		go func(i int, node devnet.Node) {
+			defer wg.Done()
+			compared[i] = nodeView(ctx, node, head, head-c.cfg.Depth)
+		}(i, node)
+	}
+
+	wg.Wait()
+
+	for _, view := range compared {
+		if view.Err != nil {
+			return nil, fmt.Errorf("%s: %w", view.Node, view.Err)
+		}
+	}
+
+	divergence := compare(compared)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if divergence == nil {
+		if c.differences > 0 {
+			c.logger.Info("[consistency] nodes converged", "head", head, "after", c.differences)
+		}
+
+		c.differences = 0
+		return nil, nil
+	}
+
+	c.differences++
+
+	if c.diverged == nil {
+		if c.differences >= c.cfg.Confirmations {
+			c.diverged = divergence
+			c.logger.Error("[consistency] nodes diverged", "report", divergence.Error())
+		} else {
+			c.logger.Warn("[consistency] nodes differ", "fields", strings.Join(divergence.Fields, ","),
+				"head", divergence.Head, "check", c.differences, "of", c.cfg.Confirmations)
+		}
+	}
+
+	return divergence, nil
+}
+
+// NodeView - canonical chain of a node at the compared heights
+type NodeView struct {
+	Node         string
+	Head         uint64 // lowest head of the nodes
+	HeadHash     libcommon.Hash
+	Number       uint64 // Depth blocks below the lowest head
+	Hash         libcommon.Hash
+	StateRoot    libcommon.Hash
+	ReceiptsRoot libcommon.Hash
+	Err          error
+}
+
+func nodeView(ctx context.Context, node devnet.Node, head, number uint64) NodeView {
+	view := NodeView{Node: node.GetName(), Head: head, Number: number}
+
+	headBlock, err := node.GetBlockByNumber(ctx, rpc.BlockNumber(head), false)
+	if err != nil {
+		view.Err = err
+		return view
+	}
+
+	block, err := node.GetBlockByNumber(ctx, rpc.BlockNumber(number), false)
+	if err != nil {
+		view.Err = err
+		return view
+	}
+
+	view.HeadHash = headBlock.Hash
+	view.Hash = block.Hash
+	view.StateRoot = block.Root
+	view.ReceiptsRoot = block.ReceiptHash
+
+	return view
+}
+
+func nodeHead(ctx context.Context, node devnet.Node) (uint64, error) {
+	block, err := node.GetBlockByNumber(ctx, rpc.LatestBlockNumber, false)
+	if err != nil {
+		return 0, err
+	}
+
+	if block.Header == nil || block.Number == nil {
+		return 0, errors.New("no head block")
+	}
+
+	return block.Number.Uint64(), nil
+}
+
+// Divergence - nodes which see different canonical chains
+type Divergence struct {
+	Head   uint64
+	Number uint64
+	Fields []string // which differ: headHash, hash, stateRoot, receiptsRoot
+	Views  []NodeView
+}
+
+func compare(views []NodeView) *Divergence {
+	if len(views) == 0 {
+		return nil
+	}
+
+	var fields []string
+	ref := views[0]
+
+	for _, field := range []struct {
+		name  string
+		value func(v NodeView) libcommon.Hash
+	}{
+		{"headHash", func(v NodeView) libcommon.Hash { return v.HeadHash }},
+		{"hash", func(v NodeView) libcommon.Hash { return v.Hash }},
+		{"stateRoot", func(v NodeView) libcommon.Hash { return v.StateRoot }},
+		{"receiptsRoot", func(v NodeView) libcommon.Hash { return v.ReceiptsRoot }},
+	} {
+		for _, view := range views[1:] {
+			if field.value(view) != field.value(ref) {
+				fields = append(fields, field.name)
+				break
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &Divergence{Head: ref.Head, Number: ref.Number, Fields: fields, Views: views}
+}
+
+func (d *Divergence) Error() string {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "nodes diverged: %s differ (head %d, compared block %d)", strings.Join(d.Fields, ", "), d.Head, d.Number)
+
+	for _, view := range d.Views {
+		fmt.Fprintf(&report, "\n  %s: headHash=%x hash=%x stateRoot=%x receiptsRoot=%x",
+			view.Node, view.HeadHash, view.Hash, view.StateRoot, view.ReceiptsRoot)
+	}
+
+	return report.String()
+}
@@ -0,0 +1,111 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/requests"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// chainNode - node with a canonical chain of blocks 0..head, stateRoots overrides state roots of some blocks
+type chainNode struct {
+	devnet.Node
+	name       string
+	head       uint64
+	stateRoots map[uint64]libcommon.Hash
+	down       bool
+}
+
+func (n *chainNode) GetName() string { return n.name }
+
+func (n *chainNode) GetBlockByNumber(_ context.Context, number rpc.BlockNumber, _ bool) (*requests.Block, error) {
+	if n.down {
+		return nil, errors.New("connection refused")
+	}
+
+	num := uint64(number)
+	if number == rpc.LatestBlockNumber {
+		num = n.head
+	}
+
+	header := &types.Header{Number: new(big.Int).SetUint64(num), Root: libcommon.Hash{byte(num)}}
+	if root, ok := n.stateRoots[num]; ok {
+		header.Root = root
+	}
+
+	block := &requests.Block{}
+	block.Header = header
+	block.Hash = header.Hash()
+	return block, nil
+}
+
+func newTestChecker(nodes ...*chainNode) *Checker {
+	c := NewChecker(Config{Interval: time.Second, Depth: 2, Confirmations: 2}, log.New())
+	for _, node := range nodes {
+		c.NodeCreated(context.Background(), node)
+	}
+	return c
+}
+
+func TestCheckerAgree(t *testing.T) {
+	ctx := context.Background()
+	c := newTestChecker(&chainNode{name: "a", head: 10}, &chainNode{name: "b", head: 12}, &chainNode{name: "c", down: true})
+
+	divergence, err := c.Check(ctx)
+	require.NoError(t, err)
+	require.Nil(t, divergence)
+	require.NoError(t, c.Err())
+
+	c = newTestChecker(&chainNode{name: "a", head: 10}, &chainNode{name: "b", down: true})
+	_, err = c.Check(ctx)
+	require.Error(t, err)
+
+	c = newTestChecker(&chainNode{name: "a", head: 1}, &chainNode{name: "b", head: 1})
+	_, err = c.Check(ctx)
+	require.Error(t, err)
+}
+
+func TestCheckerDiverged(t *testing.T) {
+	ctx := context.Background()
+	a := &chainNode{name: "a", head: 10}
+	b := &chainNode{name: "b", head: 11, stateRoots: map[uint64]libcommon.Hash{8: {0xff}}}
+	c := newTestChecker(a, b)
+
+	// first difference is not confirmed yet
+	divergence, err := c.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"hash", "stateRoot"}, divergence.Fields)
+	require.Equal(t, uint64(8), divergence.Number)
+	require.NoError(t, c.Err())
+
+	// converged, confirmations start over
+	b.stateRoots = nil
+	divergence, err = c.Check(ctx)
+	require.NoError(t, err)
+	require.Nil(t, divergence)
+
+	b.stateRoots = map[uint64]libcommon.Hash{8: {0xff}}
+	_, err = c.Check(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c.Err())
+	_, err = c.Check(ctx)
+	require.NoError(t, err)
+	require.ErrorContains(t, c.Err(), "stateRoot")
+	require.ErrorContains(t, c.Err(), "compared block 8")
+
+	// divergence is sticky
+	b.stateRoots = nil
+	_, err = c.Check(ctx)
+	require.NoError(t, err)
+	require.Error(t, c.Err())
+}
@@ -0,0 +1,48 @@
+package consistency_steps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+)
+
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(CheckConsistency),
+	)
+
+	scenarios.RegisterAfterStepHooks(failOnDivergence)
+}
+
+// CheckConsistency compares the chains of the nodes of the current network right away,
+// fails if they have diverged - now or at any earlier check of the consistency checker service
+func CheckConsistency(ctx context.Context) error {
+	checker := services.ConsistencyChecker(ctx)
+
+	if checker == nil {
+		return fmt.Errorf("consistency checker service is not configured for the current network")
+	}
+
+	if _, err := checker.Check(ctx); err != nil {
+		devnet.Logger(ctx).Warn("[consistency] check skipped", "err", err)
+	}
+
+	return checker.Err()
+}
+
+// failOnDivergence fails the running step once the chains of nodes of any network have diverged,
+// the checker runs in the background so the step itself may be unrelated to the divergence
+func failOnDivergence(ctx context.Context, _ *scenarios.Step, _ scenarios.StepStatus, _ error) (context.Context, error) {
+	for _, network := range devnet.Networks(ctx) {
+		if checker := services.NetworkConsistencyChecker(network); checker != nil {
+			if err := checker.Err(); err != nil {
+				return ctx, fmt.Errorf("network %s: %w", network.Chain, err)
+			}
+		}
+	}
+
+	return ctx, nil
+}
@@ -5,6 +5,11 @@ import (
 
 	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
 	"github.com/ledgerwatch/erigon/cmd/devnet/services/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/consistency"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/externalcl"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/fixtures"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/loadgen"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/localcl"
 	"github.com/ledgerwatch/erigon/cmd/devnet/services/polygon"
 )
 
@@ -49,3 +54,69 @@ func ProofGenerator(ctx context.Context) *polygon.ProofGenerator {
 
 	return nil
 }
+
+func LoadGenerator(ctx context.Context) *loadgen.LoadGenerator {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		for _, service := range network.Services {
+			if loadGenerator, ok := service.(*loadgen.LoadGenerator); ok {
+				return loadGenerator
+			}
+		}
+	}
+
+	return nil
+}
+
+func Fixtures(ctx context.Context) *fixtures.Fixtures {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		for _, service := range network.Services {
+			if f, ok := service.(*fixtures.Fixtures); ok {
+				return f
+			}
+		}
+	}
+
+	return nil
+}
+
+func LocalCL(ctx context.Context) *localcl.LocalCL {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		for _, service := range network.Services {
+			if cl, ok := service.(*localcl.LocalCL); ok {
+				return cl
+			}
+		}
+	}
+
+	return nil
+}
+
+func ExternalCL(ctx context.Context) *externalcl.ExternalCL {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		for _, service := range network.Services {
+			if cl, ok := service.(*externalcl.ExternalCL); ok {
+				return cl
+			}
+		}
+	}
+
+	return nil
+}
+
+func ConsistencyChecker(ctx context.Context) *consistency.Checker {
+	if network := devnet.CurrentNetwork(ctx); network != nil {
+		return NetworkConsistencyChecker(network)
+	}
+
+	return nil
+}
+
+func NetworkConsistencyChecker(network *devnet.Network) *consistency.Checker {
+	for _, service := range network.Services {
+		if checker, ok := service.(*consistency.Checker); ok {
+			return checker
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package externalcl
+
+import (
+	"fmt"
+	"time"
+)
+
+// Client - consensus client implementation launched against the devnet nodes
+type Client string
+
+const (
+	Lighthouse Client = "lighthouse"
+	Teku       Client = "teku"
+)
+
+func ParseClient(s string) (Client, error) {
+	switch client := Client(s); client {
+	case Lighthouse, Teku:
+		return client, nil
+	default:
+		return "", fmt.Errorf("unknown consensus client: %q, expected one of: lighthouse, teku", s)
+	}
+}
+
+type Config struct {
+	Client Client
+	// Binary - path of the client binary, the client name is looked up in PATH if empty
+	Binary string
+	// Dir - where the testnet config, genesis state, keystores and data of the beacon nodes are written
+	Dir string
+	// Validators - number of genesis validators, split evenly between the block producers
+	Validators int
+	SlotTime   time.Duration
+	// GenesisDelay - time given to the beacon nodes to start before the first slot
+	GenesisDelay time.Duration
+	// BeaconAPIPort, P2PPort - ports of the first beacon node, following nodes use the next ports
+	BeaconAPIPort int
+	P2PPort       int
+	// Mnemonic - source of the validator keys, both for the genesis state and the keystores
+	Mnemonic string
+	// tools generating the genesis state and the keystores, see https://github.com/protolambda/eth2-testnet-genesis
+	// and https://github.com/protolambda/eth2-val-tools
+	GenesisTool   string
+	KeystoresTool string
+}
+
+var DefaultConfig = Config{
+	Client:        Lighthouse,
+	Validators:    64,
+	SlotTime:      4 * time.Second,
+	GenesisDelay:  20 * time.Second,
+	BeaconAPIPort: 5052,
+	P2PPort:       9000,
+	Mnemonic:      "giant issue aisle success illegal bike spike question tent bar rely arctic volcano long crawl hungry vocal artwork sniff fantasy very lucky have athlete",
+	GenesisTool:   "eth2-testnet-genesis",
+	KeystoresTool: "eth2-val-tools",
+}
+
+func (cfg Config) binary() string {
+	if cfg.Binary != "" {
+		return cfg.Binary
+	}
+
+	return string(cfg.Client)
+}
@@ -0,0 +1,441 @@
+package externalcl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+)
+
+// ExternalCL - consensus layer of the proof-of-stake dev network run by an external client (lighthouse or teku).
+// Every node of the network gets its own beacon node connected to its engine api, validators of the genesis state are
+// split between the block producers. The testnet config, the beacon genesis state on top of the execution genesis
+// block, the keystores and the jwt secret shared with the nodes are generated into Config.Dir once all nodes are started.
+type ExternalCL struct {
+	sync.Mutex
+	cfg     Config
+	logger  log.Logger
+	nodes   []devnet.Node
+	started int
+	procs   []*process
+	beacons []string // beacon api urls, in order of nodes
+}
+
+type process struct {
+	name   string
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+func NewExternalCL(cfg Config, logger log.Logger) *ExternalCL {
+	return &ExternalCL{cfg: cfg, logger: logger}
+}
+
+func (cl *ExternalCL) Start(_ context.Context) error {
+	if _, err := exec.LookPath(cl.cfg.binary()); err != nil {
+		return fmt.Errorf("externalcl: %w", err)
+	}
+
+	for _, tool := range []string{cl.cfg.GenesisTool, cl.cfg.KeystoresTool} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("externalcl: %w", err)
+		}
+	}
+
+	var err error
+	if cl.cfg.Dir, err = filepath.Abs(cl.cfg.Dir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cl.cfg.Dir, 0755); err != nil {
+		return err
+	}
+
+	_, err = writeJWTSecret(cl.jwtSecretFile())
+	return err
+}
+
+func (cl *ExternalCL) Stop() {
+	cl.Lock()
+	procs := cl.procs
+	cl.procs = nil
+	cl.Unlock()
+
+	// reverse order of start: validator clients are stopped before their beacon nodes
+	for i := len(procs) - 1; i >= 0; i-- {
+		_ = procs[i].cmd.Process.Signal(os.Interrupt)
+
+		select {
+		case <-procs[i].exited:
+		case <-time.After(10 * time.Second):
+			_ = procs[i].cmd.Process.Kill()
+			<-procs[i].exited
+		}
+	}
+}
+
+// NodeCreated gives the node the jwt secret of the beacon nodes, erigon uses an existing <datadir>/jwt.hex
+func (cl *ExternalCL) NodeCreated(_ context.Context, node devnet.Node) {
+	cl.Lock()
+	cl.nodes = append(cl.nodes, node)
+	cl.Unlock()
+
+	secret, err := os.ReadFile(cl.jwtSecretFile())
+	if err == nil {
+		if err = os.MkdirAll(node.GetDataDir(), 0755); err == nil {
+			err = os.WriteFile(filepath.Join(node.GetDataDir(), jwtFile), secret, 0600)
+		}
+	}
+
+	if err != nil {
+		cl.logger.Error("[externalcl] can't write jwt secret", "node", node.GetName(), "err", err)
+	}
+}
+
+// NodeStarted launches the consensus layer once the last node of the network is started
+func (cl *ExternalCL) NodeStarted(ctx context.Context, _ devnet.Node) {
+	cl.Lock()
+	cl.started++
+	last := cl.started == len(cl.nodes)
+	cl.Unlock()
+
+	if !last {
+		return
+	}
+
+	if err := cl.launch(ctx); err != nil {
+		cl.logger.Error("[externalcl] start failed", "client", cl.cfg.Client, "dir", cl.cfg.Dir, "err", err)
+		cl.Stop()
+	}
+}
+
+// BeaconAPIURLs returns base urls of the beacon api of the beacon nodes, in order of the nodes they drive
+func (cl *ExternalCL) BeaconAPIURLs() []string {
+	cl.Lock()
+	defer cl.Unlock()
+	return append([]string(nil), cl.beacons...)
+}
+
+func (cl *ExternalCL) jwtSecretFile() string {
+	return filepath.Join(cl.cfg.Dir, jwtFile)
+}
+
+func (cl *ExternalCL) testnetDir() string {
+	return filepath.Join(cl.cfg.Dir, "testnet")
+}
+
+func (cl *ExternalCL) launch(ctx context.Context) error {
+	cl.Lock()
+	nodes := append([]devnet.Node(nil), cl.nodes...)
+	cl.Unlock()
+
+	var producers []int
+	for i, node := range nodes {
+		if node.IsBlockProducer() {
+			producers = append(producers, i)
+		}
+	}
+
+	if len(producers) == 0 {
+		return errors.New("no block producers")
+	}
+
+	if err := os.MkdirAll(cl.testnetDir(), 0755); err != nil {
+		return err
+	}
+
+	if err := writeTestnetConfig(cl.testnetDir(), cl.cfg, nodes[0].ChainID().Uint64()); err != nil {
+		return err
+	}
+
+	elRPC := fmt.Sprintf("http://localhost:%d", nodes[0].GetHttpPort())
+	if err := generateGenesis(ctx, cl.testnetDir(), cl.cfg, elRPC); err != nil {
+		return err
+	}
+
+	keys := map[int]string{}
+	for i, r := range validatorRanges(cl.cfg.Validators, len(producers)) {
+		node := nodes[producers[i]]
+		keys[producers[i]] = filepath.Join(cl.cfg.Dir, node.GetName(), "validators")
+
+		if err := generateKeystores(ctx, keys[producers[i]], cl.cfg, r[0], r[1]); err != nil {
+			return err
+		}
+	}
+
+	cl.logger.Info("[externalcl] testnet generated", "dir", cl.testnetDir(), "validators", cl.cfg.Validators,
+		"producers", len(producers), "genesis_delay", cl.cfg.GenesisDelay)
+
+	var bootnode string
+	for i, node := range nodes {
+		bn := beaconNode{
+			name:          node.GetName(),
+			dataDir:       filepath.Join(cl.cfg.Dir, node.GetName()),
+			testnetDir:    cl.testnetDir(),
+			jwtSecretFile: cl.jwtSecretFile(),
+			engineURL:     fmt.Sprintf("http://localhost:%d", node.GetAuthRpcPort()),
+			apiPort:       cl.cfg.BeaconAPIPort + i,
+			p2pPort:       cl.cfg.P2PPort + i,
+			bootnode:      bootnode,
+			keysDir:       keys[i],
+		}
+
+		if account := node.Account(); account != nil {
+			bn.feeRecipient = account.Address
+		}
+
+		if err := cl.startProcess(ctx, bn.name+"-beacon", bn.args(cl.cfg.Client), bn.dataDir); err != nil {
+			return err
+		}
+
+		cl.Lock()
+		cl.beacons = append(cl.beacons, bn.apiURL())
+		cl.Unlock()
+
+		// following beacon nodes discover each other via the first one
+		if i == 0 {
+			enr, err := waitForENR(ctx, bn.apiURL())
+			if err != nil {
+				return fmt.Errorf("%s: %w", bn.name, err)
+			}
+			bootnode = enr
+		}
+
+		// teku runs the validators in the beacon node
+		if bn.keysDir != "" && cl.cfg.Client == Lighthouse {
+			if err := cl.startProcess(ctx, bn.name+"-validator", bn.validatorArgs(), bn.dataDir); err != nil {
+				return err
+			}
+		}
+
+		cl.logger.Info("[externalcl] beacon node started", "node", node.GetName(), "client", cl.cfg.Client,
+			"api", bn.apiURL(), "engine", bn.engineURL, "validators", bn.keysDir != "")
+	}
+
+	return nil
+}
+
+func (cl *ExternalCL) startProcess(ctx context.Context, name string, args []string, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, name+".log"))
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(cl.cfg.binary(), args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+
+	proc := &process{name: name, cmd: cmd, exited: make(chan struct{})}
+
+	cl.Lock()
+	cl.procs = append(cl.procs, proc)
+	cl.Unlock()
+
+	go func() {
+		defer close(proc.exited)
+		defer logFile.Close()
+
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			cl.logger.Warn("[externalcl] exited", "process", name, "err", err, "log", logFile.Name())
+		}
+	}()
+
+	return nil
+}
+
+// beaconNode - beacon node (and, for block producers, validator client) driving one devnet node
+type beaconNode struct {
+	name          string
+	dataDir       string
+	testnetDir    string
+	jwtSecretFile string
+	engineURL     string
+	apiPort       int
+	p2pPort       int
+	bootnode      string // enr, empty for the first node
+	keysDir       string // output of generateKeystores, empty if the node isn't a block producer
+	feeRecipient  libcommon.Address
+}
+
+func (bn *beaconNode) apiURL() string {
+	return fmt.Sprintf("http://localhost:%d", bn.apiPort)
+}
+
+func (bn *beaconNode) args(client Client) []string {
+	p2pPort := strconv.Itoa(bn.p2pPort)
+
+	if client == Teku {
+		args := []string{
+			"--network=" + filepath.Join(bn.testnetDir, configFile),
+			"--initial-state=" + filepath.Join(bn.testnetDir, genesisFile),
+			"--data-path=" + filepath.Join(bn.dataDir, "beacon"),
+			"--ee-endpoint=" + bn.engineURL,
+			"--ee-jwt-secret-file=" + bn.jwtSecretFile,
+			"--rest-api-enabled=true",
+			"--rest-api-port=" + strconv.Itoa(bn.apiPort),
+			"--p2p-port=" + p2pPort,
+			"--p2p-advertised-ip=127.0.0.1",
+			"--p2p-peer-lower-bound=0",
+			"--Xstartup-target-peer-count=0",
+			"--validators-proposer-default-fee-recipient=" + bn.feeRecipient.Hex(),
+		}
+
+		if bn.bootnode != "" {
+			args = append(args, "--p2p-discovery-bootnodes="+bn.bootnode)
+		}
+
+		if bn.keysDir != "" {
+			args = append(args,
+				"--validator-keys="+filepath.Join(bn.keysDir, "teku-keys")+":"+filepath.Join(bn.keysDir, "teku-secrets"),
+				"--validators-keystore-locking-enabled=false")
+		}
+
+		return args
+	}
+
+	args := []string{"beacon_node",
+		"--testnet-dir", bn.testnetDir,
+		"--datadir", filepath.Join(bn.dataDir, "beacon"),
+		"--execution-endpoint", bn.engineURL,
+		"--execution-jwt", bn.jwtSecretFile,
+		"--http",
+		"--http-port", strconv.Itoa(bn.apiPort),
+		"--port", p2pPort,
+		"--enr-address", "127.0.0.1",
+		"--enr-udp-port", p2pPort,
+		"--enr-tcp-port", p2pPort,
+		"--disable-enr-auto-update",
+		"--disable-packet-filter",
+		"--enable-private-discovery",
+		"--disable-upnp",
+		"--subscribe-all-subnets",
+		"--suggested-fee-recipient", bn.feeRecipient.Hex(),
+	}
+
+	if bn.bootnode != "" {
+		args = append(args, "--boot-nodes", bn.bootnode)
+	}
+
+	return args
+}
+
+// validatorArgs - lighthouse validator client of the beacon node
+func (bn *beaconNode) validatorArgs() []string {
+	return []string{"validator_client",
+		"--testnet-dir", bn.testnetDir,
+		"--datadir", filepath.Join(bn.dataDir, "validator"),
+		"--validators-dir", filepath.Join(bn.keysDir, "keys"),
+		"--secrets-dir", filepath.Join(bn.keysDir, "secrets"),
+		"--beacon-nodes", bn.apiURL(),
+		"--init-slashing-protection",
+		"--suggested-fee-recipient", bn.feeRecipient.Hex(),
+	}
+}
+
+// waitForENR waits for the beacon api to come up and returns the enr of the node
+func waitForENR(ctx context.Context, apiURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	var identity struct {
+		Data struct {
+			ENR string `json:"enr"`
+		} `json:"data"`
+	}
+
+	for {
+		err := getJSON(ctx, apiURL+"/eth/v1/node/identity", &identity)
+		if err == nil && identity.Data.ENR != "" {
+			return identity.Data.ENR, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("beacon api not available: %w", errors.Join(ctx.Err(), err))
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// HeadPayload - execution payload of the head block of the beacon node
+type HeadPayload struct {
+	Slot        uint64
+	BlockNumber uint64
+	BlockHash   libcommon.Hash
+}
+
+// FetchHeadPayload returns the execution payload of the head of the beacon node with the given api url
+func FetchHeadPayload(ctx context.Context, apiURL string) (*HeadPayload, error) {
+	var block struct {
+		Data struct {
+			Message struct {
+				Slot string `json:"slot"`
+				Body struct {
+					ExecutionPayload struct {
+						BlockNumber string         `json:"block_number"`
+						BlockHash   libcommon.Hash `json:"block_hash"`
+					} `json:"execution_payload"`
+				} `json:"body"`
+			} `json:"message"`
+		} `json:"data"`
+	}
+
+	if err := getJSON(ctx, apiURL+"/eth/v2/beacon/blocks/head", &block); err != nil {
+		return nil, err
+	}
+
+	slot, err := strconv.ParseUint(block.Data.Message.Slot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot: %w", err)
+	}
+
+	payload := block.Data.Message.Body.ExecutionPayload
+	number, err := strconv.ParseUint(payload.BlockNumber, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number: %w", err)
+	}
+
+	return &HeadPayload{Slot: slot, BlockNumber: number, BlockHash: payload.BlockHash}, nil
+}
+
+func getJSON(ctx context.Context, url string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
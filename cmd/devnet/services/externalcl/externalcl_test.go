@@ -0,0 +1,71 @@
+package externalcl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTestnetConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, writeTestnetConfig(dir, DefaultConfig, 1337))
+
+	spec, err := os.ReadFile(filepath.Join(dir, configFile))
+	require.NoError(t, err)
+	require.Contains(t, string(spec), "\nDEPOSIT_CHAIN_ID: 1337\n")
+	require.Contains(t, string(spec), "\nSECONDS_PER_SLOT: 4\n")
+	require.Contains(t, string(spec), "\nGENESIS_DELAY: 20\n")
+	require.Contains(t, string(spec), "\nMIN_GENESIS_ACTIVE_VALIDATOR_COUNT: 64\n")
+	require.Contains(t, string(spec), "\nELECTRA_FORK_EPOCH: 18446744073709551615\n")
+
+	mnemonics, err := os.ReadFile(filepath.Join(dir, mnemonicsFile))
+	require.NoError(t, err)
+	require.Contains(t, string(mnemonics), DefaultConfig.Mnemonic)
+	require.Contains(t, string(mnemonics), "count: 64")
+
+	cfg := DefaultConfig
+	cfg.SlotTime = 500 * time.Millisecond
+	require.Error(t, writeTestnetConfig(dir, cfg, 1337))
+}
+
+func TestValidatorRanges(t *testing.T) {
+	require.Equal(t, [][2]int{{0, 64}}, validatorRanges(64, 1))
+	require.Equal(t, [][2]int{{0, 21}, {21, 42}, {42, 64}}, validatorRanges(64, 3))
+}
+
+func TestBeaconNodeArgs(t *testing.T) {
+	bn := beaconNode{
+		name:          "dev-1",
+		dataDir:       "/devnet/externalcl/dev-1",
+		testnetDir:    "/devnet/externalcl/testnet",
+		jwtSecretFile: "/devnet/externalcl/jwt.hex",
+		engineURL:     "http://localhost:8555",
+		apiPort:       5053,
+		p2pPort:       9001,
+		bootnode:      "enr:-abc",
+		keysDir:       "/devnet/externalcl/dev-1/validators",
+	}
+
+	lighthouse := strings.Join(bn.args(Lighthouse), " ")
+	require.True(t, strings.HasPrefix(lighthouse, "beacon_node "))
+	require.Contains(t, lighthouse, "--execution-endpoint http://localhost:8555")
+	require.Contains(t, lighthouse, "--execution-jwt /devnet/externalcl/jwt.hex")
+	require.Contains(t, lighthouse, "--http-port 5053")
+	require.Contains(t, lighthouse, "--boot-nodes enr:-abc")
+	require.Contains(t, strings.Join(bn.validatorArgs(), " "), "--beacon-nodes http://localhost:5053")
+
+	teku := strings.Join(bn.args(Teku), " ")
+	require.Contains(t, teku, "--ee-endpoint=http://localhost:8555")
+	require.Contains(t, teku, "--initial-state=/devnet/externalcl/testnet/genesis.ssz")
+	require.Contains(t, teku, "--p2p-discovery-bootnodes=enr:-abc")
+	require.Contains(t, teku, "--validator-keys=/devnet/externalcl/dev-1/validators/teku-keys:/devnet/externalcl/dev-1/validators/teku-secrets")
+
+	bn.bootnode, bn.keysDir = "", ""
+	require.NotContains(t, strings.Join(bn.args(Teku), " "), "--validator-keys")
+	require.NotContains(t, strings.Join(bn.args(Lighthouse), " "), "--boot-nodes")
+}
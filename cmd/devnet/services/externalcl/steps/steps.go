@@ -0,0 +1,78 @@
+package externalcl_steps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/externalcl"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(CheckBeaconHeads),
+	)
+}
+
+// CheckBeaconHeads waits until every beacon node of the external consensus layer has a head with an execution payload
+// at or above minBlock, and checks that the payload is the block of the same number on all nodes of the network -
+// i.e. blocks built by the nodes via the engine api are accepted by the consensus client and imported by all nodes.
+func CheckBeaconHeads(ctx context.Context, minBlock uint64) error {
+	logger := devnet.Logger(ctx)
+	network := devnet.CurrentNetwork(ctx)
+
+	cl := services.ExternalCL(ctx)
+
+	if cl == nil {
+		return fmt.Errorf("external consensus layer is not configured for the current network")
+	}
+
+	urls := cl.BeaconAPIURLs()
+
+	if len(urls) == 0 {
+		return fmt.Errorf("external consensus layer is not started")
+	}
+
+	for i, url := range urls {
+		var head *externalcl.HeadPayload
+
+		for {
+			var err error
+			head, err = externalcl.FetchHeadPayload(ctx, url)
+
+			if err == nil && head.BlockNumber >= minBlock {
+				break
+			}
+
+			if err != nil {
+				logger.Debug("[externalcl] beacon head not available", "api", url, "err", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("beacon node %s didn't reach block %d: %w", url, minBlock, ctx.Err())
+			case <-time.After(time.Second):
+			}
+		}
+
+		for _, node := range network.Nodes {
+			block, err := node.GetBlockByNumber(ctx, rpc.BlockNumber(head.BlockNumber), false)
+
+			if err != nil {
+				return fmt.Errorf("%s: failed to get block %d: %w", node.GetName(), head.BlockNumber, err)
+			}
+
+			if block.Hash != head.BlockHash {
+				return fmt.Errorf("%s: block %d is %x, head of beacon node %d is %x", node.GetName(), head.BlockNumber, block.Hash, i, head.BlockHash)
+			}
+		}
+
+		logger.Info("Beacon head checked", "api", url, "slot", head.Slot, "block", head.BlockNumber, "hash", head.BlockHash)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package externalcl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+)
+
+// files of the testnet dir, which is the format both lighthouse (--testnet-dir) and teku (--network, --initial-state) read
+const (
+	configFile    = "config.yaml"
+	genesisFile   = "genesis.ssz"
+	mnemonicsFile = "mnemonics.yaml"
+	jwtFile       = "jwt.hex"
+)
+
+// chainSpec - beacon chain config of the devnet: mainnet preset, all forks up to Deneb active from genesis, so the
+// Cancun execution genesis is the merge block.
+var chainSpec = template.Must(template.New(configFile).Parse(`PRESET_BASE: mainnet
+CONFIG_NAME: erigon-devnet
+
+MIN_GENESIS_ACTIVE_VALIDATOR_COUNT: {{.Validators}}
+MIN_GENESIS_TIME: 0
+GENESIS_FORK_VERSION: 0x10000000
+GENESIS_DELAY: {{.GenesisDelay}}
+
+ALTAIR_FORK_VERSION: 0x20000000
+ALTAIR_FORK_EPOCH: 0
+BELLATRIX_FORK_VERSION: 0x30000000
+BELLATRIX_FORK_EPOCH: 0
+CAPELLA_FORK_VERSION: 0x40000000
+CAPELLA_FORK_EPOCH: 0
+DENEB_FORK_VERSION: 0x50000000
+DENEB_FORK_EPOCH: 0
+ELECTRA_FORK_VERSION: 0x60000000
+ELECTRA_FORK_EPOCH: {{.FarFutureEpoch}}
+
+TERMINAL_TOTAL_DIFFICULTY: 0
+TERMINAL_BLOCK_HASH: 0x0000000000000000000000000000000000000000000000000000000000000000
+TERMINAL_BLOCK_HASH_ACTIVATION_EPOCH: {{.FarFutureEpoch}}
+
+SECONDS_PER_SLOT: {{.SecondsPerSlot}}
+SECONDS_PER_ETH1_BLOCK: {{.SecondsPerSlot}}
+MIN_VALIDATOR_WITHDRAWABILITY_DELAY: 256
+SHARD_COMMITTEE_PERIOD: 256
+ETH1_FOLLOW_DISTANCE: 12
+
+INACTIVITY_SCORE_BIAS: 4
+INACTIVITY_SCORE_RECOVERY_RATE: 16
+EJECTION_BALANCE: 16000000000
+MIN_PER_EPOCH_CHURN_LIMIT: 4
+CHURN_LIMIT_QUOTIENT: 65536
+MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT: 8
+PROPOSER_SCORE_BOOST: 40
+
+DEPOSIT_CHAIN_ID: {{.ChainID}}
+DEPOSIT_NETWORK_ID: {{.ChainID}}
+DEPOSIT_CONTRACT_ADDRESS: 0x4242424242424242424242424242424242424242
+
+GOSSIP_MAX_SIZE: 10485760
+MAX_REQUEST_BLOCKS: 1024
+EPOCHS_PER_SUBNET_SUBSCRIPTION: 256
+MIN_EPOCHS_FOR_BLOCK_REQUESTS: 33024
+MAX_CHUNK_SIZE: 10485760
+TTFB_TIMEOUT: 5
+RESP_TIMEOUT: 10
+ATTESTATION_PROPAGATION_SLOT_RANGE: 32
+MAXIMUM_GOSSIP_CLOCK_DISPARITY: 500
+MESSAGE_DOMAIN_INVALID_SNAPPY: 0x00000000
+MESSAGE_DOMAIN_VALID_SNAPPY: 0x01000000
+SUBNETS_PER_NODE: 2
+ATTESTATION_SUBNET_COUNT: 64
+ATTESTATION_SUBNET_EXTRA_BITS: 0
+ATTESTATION_SUBNET_PREFIX_BITS: 6
+MAX_REQUEST_BLOCKS_DENEB: 128
+MAX_REQUEST_BLOB_SIDECARS: 768
+MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS: 4096
+BLOB_SIDECAR_SUBNET_COUNT: 6
+`))
+
+var mnemonics = template.Must(template.New(mnemonicsFile).Parse(`- mnemonic: "{{.Mnemonic}}"
+  count: {{.Validators}}
+`))
+
+// writeTestnetConfig writes the chain spec and the source of the genesis validators into dir
+func writeTestnetConfig(dir string, cfg Config, chainID uint64) error {
+	params := struct {
+		Config
+		ChainID        uint64
+		SecondsPerSlot int64
+		GenesisDelay   int64
+		FarFutureEpoch uint64
+	}{cfg, chainID, int64(cfg.SlotTime / time.Second), int64(cfg.GenesisDelay / time.Second), math.MaxUint64}
+
+	if params.SecondsPerSlot < 1 {
+		return fmt.Errorf("slot time must be at least 1s, got: %s", cfg.SlotTime)
+	}
+
+	for name, tmpl := range map[string]*template.Template{configFile: chainSpec, mnemonicsFile: mnemonics} {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, params); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+
+	// lighthouse reads the deposit contract deployment block from the testnet dir, there is no contract in the devnet
+	for _, name := range []string{"deploy_block.txt", "deposit_contract_block.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("0"), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJWTSecret writes a new secret shared by the execution and the beacon nodes, in the format of --authrpc.jwtsecret
+func writeJWTSecret(path string) ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, []byte(hexutility.Encode(secret)), 0600); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// generateGenesis builds genesis.ssz of the beacon chain on top of the execution genesis block served by elRPC.
+// The beacon genesis starts GENESIS_DELAY after now.
+func generateGenesis(ctx context.Context, dir string, cfg Config, elRPC string) error {
+	cmd := exec.CommandContext(ctx, cfg.GenesisTool, "deneb",
+		"--config", filepath.Join(dir, configFile),
+		"--mnemonics", filepath.Join(dir, mnemonicsFile),
+		"--shadow-fork-eth1-rpc", elRPC,
+		"--timestamp", strconv.FormatInt(time.Now().Unix(), 10),
+		"--state-output", filepath.Join(dir, genesisFile))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cfg.GenesisTool, err, out)
+	}
+
+	return nil
+}
+
+// validatorRanges splits [0, validators) into count consecutive ranges of key indices
+func validatorRanges(validators, count int) [][2]int {
+	ranges := make([][2]int, count)
+
+	for i := range ranges {
+		ranges[i] = [2]int{validators * i / count, validators * (i + 1) / count}
+	}
+
+	return ranges
+}
+
+// generateKeystores writes keystores of the validators [from, to) into dir, in the layouts of all clients:
+// keys+secrets for lighthouse, teku-keys+teku-secrets for teku
+func generateKeystores(ctx context.Context, dir string, cfg Config, from, to int) error {
+	// the tool refuses to write into an existing dir, keystores of a previous run are replaced
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, cfg.KeystoresTool, "keystores",
+		"--insecure",
+		"--prysm-pass", "devnet",
+		"--out-loc", dir,
+		"--source-mnemonic", cfg.Mnemonic,
+		"--source-min", strconv.Itoa(from),
+		"--source-max", strconv.Itoa(to))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cfg.KeystoresTool, err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package fixtures
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// program - minimal EVM assembler used to build the fixture contracts without a solidity compiler.
+// Jump targets are referenced by label and always pushed with PUSH2, so the code size
+// doesn't depend on label positions.
+type program struct {
+	code   []byte
+	labels map[string]int
+	refs   map[int]string // position of PUSH2 argument => label
+}
+
+func newProgram() *program {
+	return &program{labels: map[string]int{}, refs: map[int]string{}}
+}
+
+func (p *program) op(ops ...vm.OpCode) *program {
+	for _, op := range ops {
+		p.code = append(p.code, byte(op))
+	}
+	return p
+}
+
+// push - pushes v with the shortest PUSHn (PUSH0 is avoided to not depend on Shanghai)
+func (p *program) push(v *uint256.Int) *program {
+	n := v.ByteLen()
+	if n == 0 {
+		n = 1
+	}
+	b := v.Bytes32()
+	p.code = append(p.code, byte(vm.PUSH1)+byte(n-1))
+	p.code = append(p.code, b[32-n:]...)
+	return p
+}
+
+func (p *program) pushN(v uint64) *program {
+	return p.push(uint256.NewInt(v))
+}
+
+func (p *program) pushBytes(b []byte) *program {
+	return p.push(new(uint256.Int).SetBytes(b))
+}
+
+// push2 - pushes v with PUSH2 regardless of its value
+func (p *program) push2(v uint64) *program {
+	p.code = append(p.code, byte(vm.PUSH2), byte(v>>8), byte(v))
+	return p
+}
+
+// pushLabel - pushes position of the label
+func (p *program) pushLabel(label string) *program {
+	p.refs[len(p.code)+1] = label
+	return p.push2(0)
+}
+
+// jump, jumpi - jump to the label, jumpi takes the condition from the stack
+func (p *program) jump(label string) *program  { return p.pushLabel(label).op(vm.JUMP) }
+func (p *program) jumpi(label string) *program { return p.pushLabel(label).op(vm.JUMPI) }
+
+// label - marks the current position with a JUMPDEST
+func (p *program) label(label string) *program {
+	if _, ok := p.labels[label]; ok {
+		panic(fmt.Sprintf("duplicate label: %s", label))
+	}
+	p.labels[label] = len(p.code)
+	return p.op(vm.JUMPDEST)
+}
+
+func (p *program) append(code ...func(p *program)) *program {
+	for _, c := range code {
+		c(p)
+	}
+	return p
+}
+
+func (p *program) bytes() []byte {
+	code := append([]byte{}, p.code...)
+	for pos, label := range p.refs {
+		target, ok := p.labels[label]
+		if !ok {
+			panic(fmt.Sprintf("undefined label: %s", label))
+		}
+		code[pos], code[pos+1] = byte(target>>8), byte(target)
+	}
+	return code
+}
+
+// initCode - wraps runtime into code returning it from the constructor, `constructor` runs first
+// and may read constructor arguments appended to the init code with codeArg
+func initCode(constructor func(p *program), runtime []byte) []byte {
+	build := func(offset uint64) []byte {
+		p := newProgram()
+		if constructor != nil {
+			constructor(p)
+		}
+		// codecopy(0, offset, len(runtime)) return(0, len(runtime))
+		return p.push2(uint64(len(runtime))).op(vm.DUP1).push2(offset).pushN(0).op(vm.CODECOPY).pushN(0).op(vm.RETURN).bytes()
+	}
+	ctor := build(0)
+	return append(build(uint64(len(ctor))), runtime...)
+}
+
+// Common code fragments. Stack comments list the top of the stack last.
+
+// arg - [] => [calldata argument i]
+func arg(i uint64) func(p *program) {
+	return func(p *program) {
+		p.pushN(4 + 32*i).op(vm.CALLDATALOAD)
+	}
+}
+
+// addressArg - [] => [calldata argument i masked to 20 bytes]
+func addressArg(i uint64) func(p *program) {
+	return func(p *program) {
+		p.append(arg(i)).push(addressMask).op(vm.AND)
+	}
+}
+
+// codeArg - [] => [constructor argument i], arguments are the 32-byte words appended to the init code
+func codeArg(i, count uint64) func(p *program) {
+	return func(p *program) {
+		// codecopy(0, codesize-32*(count-i), 32)
+		p.pushN(32).pushN(32*(count-i)).op(vm.CODESIZE, vm.SUB).pushN(0).op(vm.CODECOPY).pushN(0).op(vm.MLOAD)
+	}
+}
+
+// hashPair - [b, a] => [keccak256(a . b)]: slot of mapping `b` at key `a` in solidity storage layout
+func hashPair(p *program) {
+	p.pushN(0).op(vm.MSTORE).pushN(32).op(vm.MSTORE).pushN(64).pushN(0).op(vm.KECCAK256)
+}
+
+// mapping - [] => [slot of mapping `slot` at the key pushed by `key`]
+func mapping(slot uint64, key func(p *program)) func(p *program) {
+	return func(p *program) {
+		p.pushN(slot).append(key, hashPair)
+	}
+}
+
+// returnWord - [v] => return v
+func returnWord(p *program) {
+	p.pushN(0).op(vm.MSTORE).pushN(32).pushN(0).op(vm.RETURN)
+}
+
+// returnTrue - return true
+func returnTrue(p *program) {
+	p.pushN(1).append(returnWord)
+}
+
+// returnString - return abi encoded string s, len(s) <= 32
+func returnString(s string) func(p *program) {
+	return func(p *program) {
+		word := make([]byte, 32)
+		copy(word, s)
+		p.pushN(32).pushN(0).op(vm.MSTORE).
+			pushN(uint64(len(s))).pushN(32).op(vm.MSTORE).
+			pushBytes(word).pushN(64).op(vm.MSTORE).
+			pushN(96).pushN(0).op(vm.RETURN)
+	}
+}
+
+// dispatch - jumps to the label of the function whose selector matches the calldata, reverts otherwise
+func dispatch(functions ...string) func(p *program) {
+	return func(p *program) {
+		p.pushN(4).op(vm.CALLDATASIZE, vm.LT).jumpi("revert")
+		p.pushN(0).op(vm.CALLDATALOAD).pushN(224).op(vm.SHR)
+		for _, f := range functions {
+			p.op(vm.DUP1).pushBytes(selector(f)).op(vm.EQ).jumpi(f)
+		}
+		p.jump("revert")
+	}
+}
+
+// revert - the "revert" label all failed checks jump to
+func revert(p *program) {
+	p.label("revert").pushN(0).op(vm.DUP1, vm.REVERT)
+}
+
+var addressMask = new(uint256.Int).SetBytes(bytesOf(20, 0xff))
+
+func bytesOf(n int, b byte) []byte {
+	bs := make([]byte, n)
+	for i := range bs {
+		bs[i] = b
+	}
+	return bs
+}
@@ -0,0 +1,350 @@
+package fixtures
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+// The fixture contracts are assembled by hand (see asm.go) - devnet must not depend on a solidity compiler.
+// ERC-20 and ERC-721 use solidity storage layout, so their state may be inspected with the usual tools.
+
+const (
+	TokenName     = "Devnet Token"
+	TokenSymbol   = "DVT"
+	TokenDecimals = 18
+
+	NFTName   = "Devnet NFT"
+	NFTSymbol = "DNFT"
+)
+
+// TokenSupply - amount of ERC-20 tokens minted to the fixtures deployer: 1 billion with 18 decimals
+var TokenSupply = new(uint256.Int).Mul(uint256.NewInt(1_000_000_000), uint256.NewInt(1_000_000_000_000_000_000))
+
+func selector(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))[:4]
+}
+
+func topic(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))
+}
+
+var (
+	transferTopic       = topic("Transfer(address,address,uint256)")
+	approvalTopic       = topic("Approval(address,address,uint256)")
+	approvalForAllTopic = topic("ApprovalForAll(address,address,bool)")
+)
+
+func caller(p *program) { p.op(vm.CALLER) }
+
+// create2DeployerRuntime - same interface as the widely used deterministic deployment proxy:
+// calldata is a 32-byte salt followed by the init code, the value is passed to the created contract,
+// returns 20 bytes of the created address and reverts if creation failed
+func create2DeployerRuntime() []byte {
+	p := newProgram()
+	// calldatacopy(0, 32, calldatasize-32)
+	p.pushN(32).op(vm.CALLDATASIZE, vm.SUB).op(vm.DUP1).pushN(32).pushN(0).op(vm.CALLDATACOPY)
+	// create2(callvalue, 0, calldatasize-32, salt)
+	p.pushN(0).op(vm.CALLDATALOAD, vm.SWAP1).pushN(0).op(vm.CALLVALUE, vm.CREATE2)
+	p.op(vm.DUP1, vm.ISZERO).jumpi("revert")
+	// return(12, 20)
+	p.pushN(0).op(vm.MSTORE).pushN(20).pushN(12).op(vm.RETURN)
+	return p.append(revert).bytes()
+}
+
+// erc20Transfer - moves amount from `from` to `to` and emits Transfer, reverts if balance is insufficient
+func erc20Transfer(from, to, amount func(p *program)) func(p *program) {
+	return func(p *program) {
+		// balances[from] -= amount
+		p.append(mapping(1, from)).op(vm.DUP1, vm.SLOAD).append(amount).op(vm.DUP1, vm.DUP3, vm.LT).jumpi("revert")
+		p.op(vm.SWAP1, vm.SUB, vm.SWAP1, vm.SSTORE)
+		// balances[to] += amount
+		p.append(mapping(1, to)).op(vm.DUP1, vm.SLOAD).append(amount).op(vm.ADD, vm.SWAP1, vm.SSTORE)
+		// emit Transfer(from, to, amount)
+		p.append(amount).pushN(0).op(vm.MSTORE)
+		p.append(to, from).pushBytes(transferTopic).pushN(32).pushN(0).op(vm.LOG3)
+	}
+}
+
+// erc20 storage: 0 - totalSupply, 1 - balances, 2 - allowances
+func erc20Runtime() []byte {
+	const (
+		name         = "name()"
+		symbol       = "symbol()"
+		decimals     = "decimals()"
+		totalSupply  = "totalSupply()"
+		balanceOf    = "balanceOf(address)"
+		allowance    = "allowance(address,address)"
+		transfer     = "transfer(address,uint256)"
+		approve      = "approve(address,uint256)"
+		transferFrom = "transferFrom(address,address,uint256)"
+	)
+
+	p := newProgram()
+	p.append(dispatch(name, symbol, decimals, totalSupply, balanceOf, allowance, transfer, approve, transferFrom))
+
+	p.label(name).append(returnString(TokenName))
+	p.label(symbol).append(returnString(TokenSymbol))
+	p.label(decimals).pushN(TokenDecimals).append(returnWord)
+	p.label(totalSupply).pushN(0).op(vm.SLOAD).append(returnWord)
+	p.label(balanceOf).append(mapping(1, addressArg(0))).op(vm.SLOAD).append(returnWord)
+	p.label(allowance).append(mapping(2, addressArg(0)), addressArg(1), hashPair).op(vm.SLOAD).append(returnWord)
+
+	p.label(transfer).append(erc20Transfer(caller, addressArg(0), arg(1)), returnTrue)
+
+	// allowances[caller][spender] = amount
+	p.label(approve).append(arg(1), mapping(2, caller), addressArg(0), hashPair).op(vm.SSTORE)
+	// emit Approval(caller, spender, amount)
+	p.append(arg(1)).pushN(0).op(vm.MSTORE)
+	p.append(addressArg(0), caller).pushBytes(approvalTopic).pushN(32).pushN(0).op(vm.LOG3).append(returnTrue)
+
+	// allowances[from][caller] -= amount, unless the allowance is infinite
+	p.label(transferFrom).append(mapping(2, addressArg(0)), caller, hashPair)
+	p.op(vm.DUP1, vm.SLOAD).append(arg(2)).op(vm.DUP1, vm.DUP3, vm.LT).jumpi("revert")
+	p.op(vm.DUP2, vm.NOT, vm.ISZERO).jumpi("transferFrom.infinite")
+	p.op(vm.SWAP1, vm.SUB, vm.SWAP1, vm.SSTORE).jump("transferFrom.transfer")
+	p.label("transferFrom.infinite").op(vm.POP, vm.POP, vm.POP)
+	p.label("transferFrom.transfer").append(erc20Transfer(addressArg(0), addressArg(1), arg(2)), returnTrue)
+
+	return p.append(revert).bytes()
+}
+
+// erc20Constructor - mints TokenSupply to the holder passed as the constructor argument
+func erc20Constructor(p *program) {
+	// totalSupply = supply
+	p.push(TokenSupply).pushN(0).op(vm.SSTORE)
+	// balances[holder] = supply
+	p.append(codeArg(0, 1)).push(TokenSupply).pushN(1).op(vm.DUP3).append(hashPair).op(vm.SSTORE)
+	// emit Transfer(0, holder, supply)
+	p.push(TokenSupply).pushN(0).op(vm.MSTORE)
+	p.pushN(0).pushBytes(transferTopic).pushN(32).pushN(0).op(vm.LOG3)
+}
+
+// erc721Transfer - transferFrom(from, to, id) of the calldata: checks ownership and authorization of the caller,
+// moves the token and emits Transfer
+func erc721Transfer(p *program) {
+	// owner := owners[id], owner == from && owner != 0
+	p.append(mapping(0, arg(2))).op(vm.SLOAD)
+	p.op(vm.DUP1).append(addressArg(0)).op(vm.EQ, vm.ISZERO, vm.DUP2, vm.ISZERO, vm.OR).jumpi("revert")
+	// to != 0
+	p.append(addressArg(1)).op(vm.ISZERO).jumpi("revert")
+	// caller == owner || approvals[id] == caller || operators[owner][caller]
+	p.op(vm.DUP1, vm.CALLER, vm.EQ)
+	p.append(mapping(2, arg(2))).op(vm.SLOAD, vm.CALLER, vm.EQ, vm.OR)
+	p.pushN(3).op(vm.DUP3).append(hashPair).op(vm.CALLER).append(hashPair).op(vm.SLOAD, vm.OR)
+	p.op(vm.ISZERO).jumpi("revert").op(vm.POP)
+	// delete approvals[id]
+	p.pushN(0).append(mapping(2, arg(2))).op(vm.SSTORE)
+	// balances[from]--, balances[to]++
+	p.append(mapping(1, addressArg(0))).op(vm.DUP1, vm.SLOAD).pushN(1).op(vm.SWAP1, vm.SUB, vm.SWAP1, vm.SSTORE)
+	p.append(mapping(1, addressArg(1))).op(vm.DUP1, vm.SLOAD).pushN(1).op(vm.ADD, vm.SWAP1, vm.SSTORE)
+	// owners[id] = to
+	p.append(addressArg(1), mapping(0, arg(2))).op(vm.SSTORE)
+	// emit Transfer(from, to, id)
+	p.append(arg(2), addressArg(1), addressArg(0)).pushBytes(transferTopic).pushN(0).pushN(0).op(vm.LOG4)
+}
+
+// erc721CheckReceiver - if `to` is a contract, calls its onERC721Received(caller, from, id, data)
+// and reverts unless it returns its selector. withData - the data is the 4th calldata argument
+func erc721CheckReceiver(withData bool, done string) func(p *program) {
+	onReceived := selector("onERC721Received(address,address,uint256,bytes)")
+	onReceivedWord := make([]byte, 32)
+	copy(onReceivedWord, onReceived)
+
+	return func(p *program) {
+		p.append(addressArg(1)).op(vm.EXTCODESIZE, vm.ISZERO).jumpi(done)
+		// mem[0x80:] = selector . caller . from . id . 0x80 . data
+		p.pushBytes(onReceivedWord).pushN(0x80).op(vm.MSTORE)
+		p.op(vm.CALLER).pushN(0x84).op(vm.MSTORE)
+		p.append(addressArg(0)).pushN(0xa4).op(vm.MSTORE)
+		p.append(arg(2)).pushN(0xc4).op(vm.MSTORE)
+		p.pushN(0x80).pushN(0xe4).op(vm.MSTORE)
+
+		if withData {
+			// size := 32 + roundup32(len(data)), calldatacopy(0x104, data, size)
+			p.append(arg(3)).pushN(4).op(vm.ADD)
+			p.op(vm.DUP1, vm.CALLDATALOAD).pushN(31).op(vm.ADD).pushN(5).op(vm.SHR).pushN(5).op(vm.SHL).pushN(32).op(vm.ADD)
+			p.op(vm.DUP1).pushN(0x84).op(vm.ADD, vm.SWAP2).push2(0x104).op(vm.CALLDATACOPY)
+			// [args size] => [32, 0, args size]
+			p.pushN(32).op(vm.SWAP1).pushN(0).op(vm.SWAP1)
+		} else {
+			p.pushN(0).push2(0x104).op(vm.MSTORE)
+			p.pushN(32).pushN(0).pushN(0xa4)
+		}
+
+		// call(gas, to, 0, 0x80, args size, 0, 32)
+		p.pushN(0x80).pushN(0).append(addressArg(1)).op(vm.GAS, vm.CALL, vm.ISZERO).jumpi("revert")
+		p.pushN(32).op(vm.RETURNDATASIZE, vm.LT).jumpi("revert")
+		p.pushN(0).op(vm.MLOAD).pushN(224).op(vm.SHR).pushBytes(onReceived).op(vm.EQ, vm.ISZERO).jumpi("revert")
+		p.label(done)
+	}
+}
+
+// erc721 storage: 0 - owners, 1 - balances, 2 - token approvals, 3 - operator approvals, 4 - minter
+func erc721Runtime() []byte {
+	const (
+		name                  = "name()"
+		symbol                = "symbol()"
+		minter                = "minter()"
+		balanceOf             = "balanceOf(address)"
+		ownerOf               = "ownerOf(uint256)"
+		getApproved           = "getApproved(uint256)"
+		isApprovedForAll      = "isApprovedForAll(address,address)"
+		supportsInterface     = "supportsInterface(bytes4)"
+		approve               = "approve(address,uint256)"
+		setApprovalForAll     = "setApprovalForAll(address,bool)"
+		transferFrom          = "transferFrom(address,address,uint256)"
+		safeTransferFrom      = "safeTransferFrom(address,address,uint256)"
+		safeTransferFromBytes = "safeTransferFrom(address,address,uint256,bytes)"
+		mint                  = "mint(address,uint256)"
+	)
+
+	p := newProgram()
+	p.append(dispatch(name, symbol, minter, balanceOf, ownerOf, getApproved, isApprovedForAll, supportsInterface,
+		approve, setApprovalForAll, transferFrom, safeTransferFrom, safeTransferFromBytes, mint))
+
+	p.label(name).append(returnString(NFTName))
+	p.label(symbol).append(returnString(NFTSymbol))
+	p.label(minter).pushN(4).op(vm.SLOAD).append(returnWord)
+
+	p.label(balanceOf).append(addressArg(0)).op(vm.DUP1, vm.ISZERO).jumpi("revert")
+	p.pushN(1).op(vm.SWAP1).append(hashPair).op(vm.SLOAD).append(returnWord)
+
+	p.label(ownerOf).append(mapping(0, arg(0))).op(vm.SLOAD, vm.DUP1, vm.ISZERO).jumpi("revert").append(returnWord)
+
+	p.label(getApproved).append(mapping(0, arg(0))).op(vm.SLOAD, vm.ISZERO).jumpi("revert")
+	p.append(mapping(2, arg(0))).op(vm.SLOAD).append(returnWord)
+
+	p.label(isApprovedForAll).append(mapping(3, addressArg(0)), addressArg(1), hashPair).op(vm.SLOAD).append(returnWord)
+
+	// ERC-165 and ERC-721
+	p.label(supportsInterface).append(arg(0)).pushN(224).op(vm.SHR)
+	p.op(vm.DUP1).pushBytes([]byte{0x01, 0xff, 0xc9, 0xa7}).op(vm.EQ)
+	p.op(vm.DUP2).pushBytes([]byte{0x80, 0xac, 0x58, 0xcd}).op(vm.EQ, vm.OR).append(returnWord)
+
+	// owner := owners[id] != 0, caller == owner || operators[owner][caller]
+	p.label(approve).append(mapping(0, arg(1))).op(vm.SLOAD, vm.DUP1, vm.ISZERO).jumpi("revert")
+	p.op(vm.DUP1, vm.CALLER, vm.EQ).pushN(3).op(vm.DUP3).append(hashPair).op(vm.CALLER).append(hashPair).op(vm.SLOAD, vm.OR)
+	p.op(vm.ISZERO).jumpi("revert")
+	// approvals[id] = to, emit Approval(owner, to, id)
+	p.append(addressArg(0), mapping(2, arg(1))).op(vm.SSTORE)
+	p.append(arg(1), addressArg(0)).op(vm.DUP3).pushBytes(approvalTopic).pushN(0).pushN(0).op(vm.LOG4, vm.STOP)
+
+	// operators[caller][operator] = approved, emit ApprovalForAll(caller, operator, approved)
+	p.label(setApprovalForAll).append(arg(1)).op(vm.ISZERO, vm.ISZERO)
+	p.op(vm.DUP1).append(mapping(3, caller), addressArg(0), hashPair).op(vm.SSTORE)
+	p.pushN(0).op(vm.MSTORE)
+	p.append(addressArg(0), caller).pushBytes(approvalForAllTopic).pushN(32).pushN(0).op(vm.LOG3, vm.STOP)
+
+	p.label(transferFrom).append(erc721Transfer).op(vm.STOP)
+	p.label(safeTransferFrom).append(erc721Transfer, erc721CheckReceiver(false, "safeTransferFrom.done")).op(vm.STOP)
+	p.label(safeTransferFromBytes).append(erc721Transfer, erc721CheckReceiver(true, "safeTransferFromBytes.done")).op(vm.STOP)
+
+	// caller == minter, to != 0, owners[id] == 0
+	p.label(mint).pushN(4).op(vm.SLOAD, vm.CALLER, vm.EQ, vm.ISZERO).jumpi("revert")
+	p.append(addressArg(0)).op(vm.ISZERO).jumpi("revert")
+	p.append(mapping(0, arg(1))).op(vm.SLOAD).jumpi("revert")
+	// balances[to]++, owners[id] = to, emit Transfer(0, to, id)
+	p.append(mapping(1, addressArg(0))).op(vm.DUP1, vm.SLOAD).pushN(1).op(vm.ADD, vm.SWAP1, vm.SSTORE)
+	p.append(addressArg(0), mapping(0, arg(1))).op(vm.SSTORE)
+	p.append(arg(1), addressArg(0)).pushN(0).pushBytes(transferTopic).pushN(0).pushN(0).op(vm.LOG4, vm.STOP)
+
+	return p.append(revert).bytes()
+}
+
+// erc721Constructor - the minter is passed as the constructor argument
+func erc721Constructor(p *program) {
+	p.append(codeArg(0, 1)).pushN(4).op(vm.SSTORE)
+}
+
+// multicallRuntime - subset of Multicall3: aggregate, which reverts if any of the calls fails, and block helpers
+func multicallRuntime() []byte {
+	const (
+		aggregate                = "aggregate((address,bytes)[])"
+		getBlockNumber           = "getBlockNumber()"
+		getCurrentBlockTimestamp = "getCurrentBlockTimestamp()"
+		getEthBalance            = "getEthBalance(address)"
+	)
+
+	p := newProgram()
+	p.append(dispatch(aggregate, getBlockNumber, getCurrentBlockTimestamp, getEthBalance))
+
+	p.label(getBlockNumber).op(vm.NUMBER).append(returnWord)
+	p.label(getCurrentBlockTimestamp).op(vm.TIMESTAMP).append(returnWord)
+	p.label(getEthBalance).append(addressArg(0)).op(vm.BALANCE).append(returnWord)
+
+	// returns (uint256 blockNumber, bytes[] returnData), encoded in memory as:
+	//   0x00: blockNumber, 0x20: 0x40, 0x40: n, 0x60: offsets of n results, tail: results
+	// stack: [base, n, i, tail], base - calldata position of n, tail - memory position of the next result
+	p.label(aggregate).append(arg(0)).pushN(4).op(vm.ADD, vm.DUP1, vm.CALLDATALOAD).pushN(0)
+	p.op(vm.DUP2).pushN(5).op(vm.SHL).pushN(0x60).op(vm.ADD)
+
+	p.label("aggregate.loop").op(vm.DUP3, vm.DUP3, vm.LT, vm.ISZERO).jumpi("aggregate.done")
+	// tuple := base + 32 + calldata[base + 32 + 32*i], data := tuple + calldata[tuple + 32]
+	p.op(vm.DUP2).pushN(5).op(vm.SHL, vm.DUP5, vm.ADD).pushN(32).op(vm.ADD, vm.CALLDATALOAD, vm.DUP5, vm.ADD).pushN(32).op(vm.ADD)
+	p.op(vm.DUP1).pushN(32).op(vm.ADD, vm.CALLDATALOAD, vm.DUP2, vm.ADD)
+	// [.., tuple, data, len], calldatacopy(tail+32, data+32, len)
+	p.op(vm.DUP1, vm.CALLDATALOAD)
+	p.op(vm.DUP1, vm.DUP3).pushN(32).op(vm.ADD, vm.DUP6).pushN(32).op(vm.ADD, vm.CALLDATACOPY)
+	// call(gas, target, 0, tail+32, len, 0, 0)
+	p.pushN(0).pushN(0).op(vm.DUP3, vm.DUP7).pushN(32).op(vm.ADD).pushN(0).op(vm.DUP8, vm.CALLDATALOAD).push(addressMask).op(vm.AND)
+	p.op(vm.GAS, vm.CALL, vm.ISZERO).jumpi("revert").op(vm.POP, vm.POP, vm.POP)
+	// offsets[i] = tail - 0x60
+	p.pushN(0x60).op(vm.DUP2, vm.SUB, vm.DUP3).pushN(5).op(vm.SHL).pushN(0x60).op(vm.ADD, vm.MSTORE)
+	// mem[tail] = returndatasize, mem[tail+32:] = returndata, padding is zeroed
+	p.op(vm.RETURNDATASIZE, vm.DUP2, vm.MSTORE)
+	p.op(vm.RETURNDATASIZE).pushN(0).op(vm.DUP3).pushN(32).op(vm.ADD, vm.RETURNDATACOPY)
+	p.pushN(0).op(vm.RETURNDATASIZE, vm.DUP3, vm.ADD).pushN(32).op(vm.ADD, vm.MSTORE)
+	// tail += 32 + roundup32(returndatasize), i++
+	p.op(vm.RETURNDATASIZE).pushN(31).op(vm.ADD).pushN(5).op(vm.SHR).pushN(5).op(vm.SHL, vm.ADD).pushN(32).op(vm.ADD)
+	p.op(vm.SWAP1).pushN(1).op(vm.ADD, vm.SWAP1).jump("aggregate.loop")
+
+	p.label("aggregate.done").op(vm.NUMBER).pushN(0).op(vm.MSTORE).pushN(0x40).pushN(0x20).op(vm.MSTORE)
+	p.op(vm.DUP3).pushN(0x40).op(vm.MSTORE).pushN(0).op(vm.RETURN)
+
+	return p.append(revert).bytes()
+}
+
+const create2DeployerABI = `[]`
+
+const erc20ABI = `[
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"transferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+{"type":"event","name":"Approval","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+const erc721ABI = `[
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"minter","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"getApproved","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"isApprovedForAll","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceId","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"setApprovalForAll","stateMutability":"nonpayable","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+{"type":"function","name":"transferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"mint","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+{"type":"event","name":"Approval","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"approved","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+{"type":"event","name":"ApprovalForAll","anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]}
+]`
+
+const multicallABI = `[
+{"type":"function","name":"aggregate","stateMutability":"payable","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],"outputs":[{"name":"blockNumber","type":"uint256"},{"name":"returnData","type":"bytes[]"}]},
+{"type":"function","name":"getBlockNumber","stateMutability":"view","inputs":[],"outputs":[{"name":"blockNumber","type":"uint256"}]},
+{"type":"function","name":"getCurrentBlockTimestamp","stateMutability":"view","inputs":[],"outputs":[{"name":"timestamp","type":"uint256"}]},
+{"type":"function","name":"getEthBalance","stateMutability":"view","inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]}
+]`
@@ -0,0 +1,199 @@
+package fixtures
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/runtime"
+)
+
+type testChain struct {
+	t   *testing.T
+	cfg *runtime.Config
+}
+
+func (c *testChain) call(from libcommon.Address, contract *Contract, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := contract.ABI.Pack(method, args...)
+	require.NoError(c.t, err)
+
+	c.cfg.Origin = from
+	ret, _, err := runtime.Call(contract.Address, input, c.cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return contract.ABI.Unpack(method, ret)
+}
+
+func (c *testChain) mustCall(from libcommon.Address, contract *Contract, method string, args ...interface{}) []interface{} {
+	out, err := c.call(from, contract, method, args...)
+	require.NoError(c.t, err, method)
+	return out
+}
+
+func TestContracts(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	deployer := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	alice := libcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	bob := libcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	c := &testChain{t: t, cfg: &runtime.Config{State: state.New(state.NewDbStateReader(tx)), GasLimit: 10_000_000, Origin: deployer}}
+
+	fixtures := Contracts(deployer)
+	factory, erc20, erc721, multicall := fixtures[0], fixtures[1], fixtures[2], fixtures[3]
+
+	// the create2 deployer is created by the deployer with nonce 0, the rest are created by it
+	code, address, _, err := runtime.Create(factory.initCode, c.cfg, 0)
+	require.NoError(t, err)
+	require.Equal(t, factory.Address, address)
+	require.Equal(t, factory.Code, code)
+
+	for _, contract := range fixtures[1:] {
+		ret, _, err := runtime.Call(factory.Address, append(Salt[:], contract.initCode...), c.cfg)
+		require.NoError(t, err, contract.Name)
+		require.Equal(t, contract.Address.Bytes(), ret, contract.Name)
+		require.Equal(t, contract.Code, c.cfg.State.GetCode(contract.Address), contract.Name)
+	}
+
+	// the same salt and init code can't be deployed twice
+	_, _, err = runtime.Call(factory.Address, append(Salt[:], multicall.initCode...), c.cfg)
+	require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+	t.Run("erc20", func(t *testing.T) {
+		supply := TokenSupply.ToBig()
+
+		require.Equal(t, []interface{}{TokenName}, c.mustCall(alice, erc20, "name"))
+		require.Equal(t, []interface{}{TokenSymbol}, c.mustCall(alice, erc20, "symbol"))
+		require.Equal(t, []interface{}{uint8(TokenDecimals)}, c.mustCall(alice, erc20, "decimals"))
+		require.Equal(t, []interface{}{supply}, c.mustCall(alice, erc20, "totalSupply"))
+		require.Equal(t, []interface{}{supply}, c.mustCall(alice, erc20, "balanceOf", deployer))
+
+		require.Equal(t, []interface{}{true}, c.mustCall(deployer, erc20, "transfer", alice, big.NewInt(1000)))
+		require.Equal(t, []interface{}{big.NewInt(1000)}, c.mustCall(bob, erc20, "balanceOf", alice))
+		require.Equal(t, []interface{}{new(big.Int).Sub(supply, big.NewInt(1000))}, c.mustCall(bob, erc20, "balanceOf", deployer))
+
+		_, err := c.call(alice, erc20, "transfer", bob, big.NewInt(1001))
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		// bob spends the allowance given by alice
+		_, err = c.call(bob, erc20, "transferFrom", alice, bob, big.NewInt(1))
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		c.mustCall(alice, erc20, "approve", bob, big.NewInt(300))
+		require.Equal(t, []interface{}{big.NewInt(300)}, c.mustCall(bob, erc20, "allowance", alice, bob))
+
+		c.mustCall(bob, erc20, "transferFrom", alice, bob, big.NewInt(200))
+		require.Equal(t, []interface{}{big.NewInt(100)}, c.mustCall(bob, erc20, "allowance", alice, bob))
+		require.Equal(t, []interface{}{big.NewInt(800)}, c.mustCall(bob, erc20, "balanceOf", alice))
+		require.Equal(t, []interface{}{big.NewInt(200)}, c.mustCall(bob, erc20, "balanceOf", bob))
+
+		_, err = c.call(bob, erc20, "transferFrom", alice, bob, big.NewInt(101))
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		// infinite allowance is not decreased
+		infinite := new(uint256.Int).SetAllOne().ToBig()
+		c.mustCall(alice, erc20, "approve", bob, infinite)
+		c.mustCall(bob, erc20, "transferFrom", alice, bob, big.NewInt(500))
+		require.Equal(t, []interface{}{infinite}, c.mustCall(bob, erc20, "allowance", alice, bob))
+		require.Equal(t, []interface{}{big.NewInt(300)}, c.mustCall(bob, erc20, "balanceOf", alice))
+	})
+
+	t.Run("erc721", func(t *testing.T) {
+		id := big.NewInt(7)
+
+		require.Equal(t, []interface{}{NFTName}, c.mustCall(alice, erc721, "name"))
+		require.Equal(t, []interface{}{deployer}, c.mustCall(alice, erc721, "minter"))
+		require.Equal(t, []interface{}{true}, c.mustCall(alice, erc721, "supportsInterface", [4]byte{0x80, 0xac, 0x58, 0xcd}))
+		require.Equal(t, []interface{}{false}, c.mustCall(alice, erc721, "supportsInterface", [4]byte{0xff, 0xff, 0xff, 0xff}))
+
+		// only the minter mints, every token once
+		_, err := c.call(alice, erc721, "mint", alice, id)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+		_, err = c.call(alice, erc721, "ownerOf", id)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		c.mustCall(deployer, erc721, "mint", alice, id)
+		require.Equal(t, []interface{}{alice}, c.mustCall(bob, erc721, "ownerOf", id))
+		require.Equal(t, []interface{}{big.NewInt(1)}, c.mustCall(bob, erc721, "balanceOf", alice))
+
+		_, err = c.call(deployer, erc721, "mint", bob, id)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		// bob is neither the owner nor approved
+		_, err = c.call(bob, erc721, "transferFrom", alice, bob, id)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		c.mustCall(alice, erc721, "approve", bob, id)
+		require.Equal(t, []interface{}{bob}, c.mustCall(bob, erc721, "getApproved", id))
+
+		c.mustCall(bob, erc721, "transferFrom", alice, bob, id)
+		require.Equal(t, []interface{}{bob}, c.mustCall(bob, erc721, "ownerOf", id))
+		require.Zero(t, c.mustCall(bob, erc721, "balanceOf", alice)[0].(*big.Int).Sign())
+		require.Equal(t, []interface{}{big.NewInt(1)}, c.mustCall(bob, erc721, "balanceOf", bob))
+		require.Equal(t, []interface{}{libcommon.Address{}}, c.mustCall(bob, erc721, "getApproved", id))
+
+		// alice is an operator of bob
+		c.mustCall(bob, erc721, "setApprovalForAll", alice, true)
+		require.Equal(t, []interface{}{true}, c.mustCall(bob, erc721, "isApprovedForAll", bob, alice))
+		c.mustCall(alice, erc721, "transferFrom", bob, alice, id)
+		require.Equal(t, []interface{}{alice}, c.mustCall(bob, erc721, "ownerOf", id))
+
+		// safe transfers to contracts check onERC721Received
+		receiver := libcommon.HexToAddress("0x4000000000000000000000000000000000000004")
+		c.cfg.State.SetCode(receiver, newProgram().
+			pushBytes(selector("onERC721Received(address,address,uint256,bytes)")).pushN(224).op(vm.SHL).
+			pushN(0).op(vm.MSTORE).pushN(32).pushN(0).op(vm.RETURN).bytes())
+
+		_, err = c.call(alice, erc721, "safeTransferFrom", alice, multicall.Address, id)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+		_, err = c.call(alice, erc721, "safeTransferFrom0", alice, multicall.Address, id, []byte{1, 2, 3})
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		c.mustCall(alice, erc721, "safeTransferFrom", alice, bob, id)
+		c.mustCall(bob, erc721, "safeTransferFrom0", bob, receiver, id, make([]byte, 33))
+		require.Equal(t, []interface{}{receiver}, c.mustCall(bob, erc721, "ownerOf", id))
+	})
+
+	t.Run("multicall", func(t *testing.T) {
+		balanceOf, err := erc20.ABI.Pack("balanceOf", alice)
+		require.NoError(t, err)
+		ownerOf, err := erc721.ABI.Pack("ownerOf", big.NewInt(7))
+		require.NoError(t, err)
+
+		type call struct {
+			Target   libcommon.Address
+			CallData []byte
+		}
+
+		out := c.mustCall(alice, multicall, "aggregate", []call{{erc20.Address, balanceOf}, {erc721.Address, ownerOf}, {erc20.Address, balanceOf}})
+		require.Len(t, out, 2)
+
+		results := out[1].([][]byte)
+		require.Len(t, results, 3)
+
+		balance, err := erc20.ABI.Unpack("balanceOf", results[0])
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{big.NewInt(300)}, balance)
+
+		owner, err := erc721.ABI.Unpack("ownerOf", results[1])
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{libcommon.HexToAddress("0x4000000000000000000000000000000000000004")}, owner)
+
+		require.Equal(t, results[0], results[2])
+
+		// any failed call reverts the aggregate
+		_, err = c.call(alice, multicall, "aggregate", []call{{erc20.Address, balanceOf}, {erc721.Address, balanceOf[:4]}})
+		require.ErrorIs(t, err, vm.ErrExecutionReverted)
+
+		require.Zero(t, c.mustCall(alice, multicall, "getEthBalance", alice)[0].(*big.Int).Sign())
+	})
+}
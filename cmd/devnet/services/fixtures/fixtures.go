@@ -0,0 +1,332 @@
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/accounts/abi/bind"
+	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/contracts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// Names of the fixture contracts
+const (
+	Create2Deployer = "create2-deployer"
+	ERC20           = "erc20"
+	ERC721          = "erc721"
+	Multicall       = "multicall"
+)
+
+const (
+	DeployerName = "fixtures-deployer"
+
+	deployGas       = 3_000_000
+	deployTimeout   = 2 * time.Minute
+	receiptInterval = time.Second
+)
+
+// Salt of the fixture contracts deployed by the create2 deployer
+var Salt = [32]byte(crypto.Keccak256([]byte("erigon devnet fixtures")))
+
+type Config struct {
+	Accounts uint    // amount of funded accounts
+	Balance  float64 // ether allocated in genesis to every funded account and to the deployer
+}
+
+var DefaultConfig = Config{
+	Accounts: 10,
+	Balance:  1000,
+}
+
+// AccountName - name of i-th funded account, see accounts.GetAccount
+func AccountName(i uint) string {
+	return fmt.Sprintf("fixtures-account-%d", i)
+}
+
+// Contract - fixture contract, its address is known before the deployment
+type Contract struct {
+	Name     string
+	Address  libcommon.Address
+	ABI      abi.ABI
+	Code     []byte // runtime code
+	initCode []byte
+}
+
+// Bind - contract bound to the node, to call and transact with the fixture's ABI
+func (c *Contract) Bind(node devnet.Node) *bind.BoundContract {
+	backend := contracts.NewBackend(node)
+	return bind.NewBoundContract(c.Address, c.ABI, backend, backend, backend)
+}
+
+var _ devnet.Service = (*Fixtures)(nil)
+
+// Fixtures - devnet service which deploys a standard set of contracts (see Contracts) and funds accounts
+// at deterministic addresses: the keys of the deployer and of the funded accounts are derived from their names,
+// the create2 deployer is the first contract created by the deployer and the others are created by it with Salt.
+// So scenarios, external tools and checkpoints of previous runs may rely on the addresses.
+//
+// The accounts are funded in genesis (see Alloc), the contracts are deployed when the first block producer
+// of the chain starts, unless they are already deployed (e.g. the devnet was restored from a checkpoint).
+type Fixtures struct {
+	sync.Mutex
+	cfg       Config
+	chainName string
+	deployer  *accounts.Account
+	accounts  []*accounts.Account
+	contracts []*Contract
+	started   bool
+	ready     chan struct{}
+	err       error
+}
+
+func NewFixtures(chainName string, cfg Config) *Fixtures {
+	f := &Fixtures{
+		cfg:       cfg,
+		chainName: chainName,
+		deployer:  accounts.NewDeterministicAccount(DeployerName),
+		ready:     make(chan struct{}),
+	}
+
+	for i := uint(0); i < cfg.Accounts; i++ {
+		f.accounts = append(f.accounts, accounts.NewDeterministicAccount(AccountName(i)))
+	}
+
+	f.contracts = Contracts(f.deployer.Address)
+
+	return f
+}
+
+// Contracts - fixture contracts deployed by the deployer: ERC-20 tokens are minted to the deployer
+// and the deployer is the minter of ERC-721
+func Contracts(deployer libcommon.Address) []*Contract {
+	factory := &Contract{
+		Name:     Create2Deployer,
+		Address:  crypto.CreateAddress(deployer, 0),
+		ABI:      mustParseABI(create2DeployerABI),
+		Code:     create2DeployerRuntime(),
+		initCode: initCode(nil, create2DeployerRuntime()),
+	}
+
+	deployerArg := libcommon.BytesToHash(deployer.Bytes()).Bytes()
+
+	create2 := func(name, abiJSON string, runtime, initCode []byte) *Contract {
+		return &Contract{
+			Name:     name,
+			Address:  crypto.CreateAddress2(factory.Address, Salt, crypto.Keccak256(initCode)),
+			ABI:      mustParseABI(abiJSON),
+			Code:     runtime,
+			initCode: initCode,
+		}
+	}
+
+	return []*Contract{
+		factory,
+		create2(ERC20, erc20ABI, erc20Runtime(), append(initCode(erc20Constructor, erc20Runtime()), deployerArg...)),
+		create2(ERC721, erc721ABI, erc721Runtime(), append(initCode(erc721Constructor, erc721Runtime()), deployerArg...)),
+		create2(Multicall, multicallABI, multicallRuntime(), initCode(nil, multicallRuntime())),
+	}
+}
+
+func mustParseABI(abiJSON string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// Alloc - funds the deployer and the accounts in genesis
+func (f *Fixtures) Alloc(alloc types.GenesisAlloc) {
+	balance := accounts.EtherAmount(f.cfg.Balance)
+
+	alloc[f.deployer.Address] = types.GenesisAccount{Balance: balance}
+
+	for _, account := range f.accounts {
+		alloc[account.Address] = types.GenesisAccount{Balance: balance}
+	}
+}
+
+func (f *Fixtures) Config() Config {
+	return f.cfg
+}
+
+func (f *Fixtures) Deployer() *accounts.Account {
+	return f.deployer
+}
+
+// Accounts - funded accounts
+func (f *Fixtures) Accounts() []*accounts.Account {
+	return f.accounts
+}
+
+func (f *Fixtures) Contracts() []*Contract {
+	return f.contracts
+}
+
+// Contract - fixture contract by name, nil if there is no such fixture
+func (f *Fixtures) Contract(name string) *Contract {
+	for _, contract := range f.contracts {
+		if contract.Name == name {
+			return contract
+		}
+	}
+
+	return nil
+}
+
+// Wait - waits until the contracts are deployed, returns the deployment error if any
+func (f *Fixtures) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-f.ready:
+		return f.err
+	}
+}
+
+func (f *Fixtures) Start(_ context.Context) error {
+	return nil
+}
+
+func (f *Fixtures) Stop() {}
+
+func (f *Fixtures) NodeCreated(_ context.Context, _ devnet.Node) {}
+
+func (f *Fixtures) NodeStarted(ctx context.Context, node devnet.Node) {
+	if !strings.HasPrefix(node.GetName(), f.chainName) || !node.IsBlockProducer() {
+		return
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if f.started {
+		return
+	}
+
+	f.started = true
+
+	go func() {
+		logger := devnet.Logger(ctx)
+
+		f.err = f.deploy(ctx, node)
+
+		if f.err != nil {
+			logger.Error("[fixtures] deployment failed", "chain", f.chainName, "err", f.err)
+		} else {
+			logger.Info("[fixtures] deployed", "chain", f.chainName, "accounts", len(f.accounts))
+		}
+
+		close(f.ready)
+	}()
+}
+
+func (f *Fixtures) deploy(ctx context.Context, node devnet.Node) error {
+	logger := devnet.Logger(ctx)
+	signer := types.LatestSignerForChainID(node.ChainID())
+
+	gasPrice, err := node.GasPrice()
+
+	if err != nil {
+		return err
+	}
+
+	// 2x - to stay includable if the base fee grows
+	price := uint256.MustFromBig(new(big.Int).Lsh(gasPrice, 1))
+
+	count, err := node.GetTransactionCount(f.deployer.Address, rpc.PendingBlock)
+
+	if err != nil {
+		return err
+	}
+
+	nonce := count.Uint64()
+
+	for i, contract := range f.contracts {
+		code, err := node.GetCode(contract.Address, rpc.LatestBlock)
+
+		if err != nil {
+			return err
+		}
+
+		if len(code) > 0 {
+			logger.Info("[fixtures] already deployed", "contract", contract.Name, "address", contract.Address)
+			continue
+		}
+
+		var txn types.Transaction
+
+		if i == 0 {
+			// the address of the create2 deployer depends on the nonce
+			if nonce != 0 {
+				return fmt.Errorf("%s is not deployed, but nonce of the deployer %s is %d", contract.Name, f.deployer.Address, nonce)
+			}
+
+			txn = types.NewContractCreation(nonce, uint256.NewInt(0), deployGas, price, contract.initCode)
+		} else {
+			data := append(append([]byte{}, Salt[:]...), contract.initCode...)
+			txn = types.NewTransaction(nonce, f.contracts[0].Address, uint256.NewInt(0), deployGas, price, data)
+		}
+
+		signed, err := types.SignTx(txn, *signer, f.deployer.SigKey())
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := node.SendTransaction(signed); err != nil {
+			return fmt.Errorf("deployment of %s: %w", contract.Name, err)
+		}
+
+		logger.Info("[fixtures] deploying", "contract", contract.Name, "address", contract.Address, "nonce", nonce)
+
+		nonce++
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deployTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptInterval)
+	defer ticker.Stop()
+
+	for _, contract := range f.contracts {
+		for {
+			code, err := node.GetCode(contract.Address, rpc.LatestBlock)
+
+			if err != nil {
+				return err
+			}
+
+			if len(code) > 0 {
+				if !bytes.Equal(code, contract.Code) {
+					return fmt.Errorf("unexpected code of %s at %s", contract.Name, contract.Address)
+				}
+
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("deployment of %s: %w", contract.Name, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+
+	return nil
+}
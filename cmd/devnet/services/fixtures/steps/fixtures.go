@@ -0,0 +1,79 @@
+package fixtures_steps
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/erigon/accounts/abi/bind"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/fixtures"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(CheckFixtures),
+	)
+}
+
+// CheckFixtures waits for the deployment of the fixtures of the current network,
+// then checks that the funded accounts have funds and the contracts respond at their addresses
+func CheckFixtures(ctx context.Context) error {
+	fixturesService := services.Fixtures(ctx)
+
+	if fixturesService == nil {
+		return fmt.Errorf("fixtures service is not configured for the current network")
+	}
+
+	if err := fixturesService.Wait(ctx); err != nil {
+		return err
+	}
+
+	logger := devnet.Logger(ctx)
+	node := devnet.SelectNode(ctx)
+
+	for _, account := range fixturesService.Accounts() {
+		balance, err := node.GetBalance(account.Address, rpc.LatestBlock)
+
+		if err != nil {
+			return err
+		}
+
+		if balance.Sign() == 0 {
+			return fmt.Errorf("fixture account %s (%s) is not funded", account.Name, account.Address)
+		}
+	}
+
+	for _, contract := range fixturesService.Contracts() {
+		logger.Info("[fixtures] contract", "name", contract.Name, "address", contract.Address)
+	}
+
+	var supply []interface{}
+
+	erc20 := fixturesService.Contract(fixtures.ERC20)
+
+	if err := erc20.Bind(node).Call(&bind.CallOpts{Context: ctx}, &supply, "totalSupply"); err != nil {
+		return fmt.Errorf("%s totalSupply: %w", erc20.Name, err)
+	}
+
+	if supply[0].(*big.Int).Cmp(fixtures.TokenSupply.ToBig()) != 0 {
+		return fmt.Errorf("%s totalSupply: expected %s, got %s", erc20.Name, fixtures.TokenSupply, supply[0])
+	}
+
+	var minter []interface{}
+
+	erc721 := fixturesService.Contract(fixtures.ERC721)
+
+	if err := erc721.Bind(node).Call(&bind.CallOpts{Context: ctx}, &minter, "minter"); err != nil {
+		return fmt.Errorf("%s minter: %w", erc721.Name, err)
+	}
+
+	if minter[0] != fixturesService.Deployer().Address {
+		return fmt.Errorf("%s minter: expected %s, got %s", erc721.Name, fixturesService.Deployer().Address, minter[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
+)
+
+// Kind of transactions produced by the load generator
+type Kind int
+
+const (
+	Legacy         Kind = iota // legacy value transfer
+	DynamicFee                 // EIP-1559 value transfer
+	Blob                       // EIP-4844 transaction with 1 blob (requires Cancun)
+	ContractDeploy             // deployment of the nested-calls contract
+	NestedCall                 // call of the nested-calls contract which calls itself `NestedDepth` times
+)
+
+var kindNames = map[Kind]string{
+	Legacy:         "legacy",
+	DynamicFee:     "dynamic",
+	Blob:           "blob",
+	ContractDeploy: "deploy",
+	NestedCall:     "nested",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+func ParseKind(name string) (Kind, error) {
+	for kind, kindName := range kindNames {
+		if kindName == name {
+			return kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown transaction kind: %q", name)
+}
+
+// Mix - relative weights of transaction kinds in generated traffic
+type Mix map[Kind]uint
+
+// ParseMix parses comma separated list of kind=weight pairs, for example: "legacy=1,dynamic=3,nested=1"
+func ParseMix(s string) (Mix, error) {
+	mix := Mix{}
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+
+		if len(item) == 0 {
+			continue
+		}
+
+		name, weightStr, found := strings.Cut(item, "=")
+
+		weight := uint64(1)
+
+		if found {
+			var err error
+
+			if weight, err = strconv.ParseUint(strings.TrimSpace(weightStr), 10, 32); err != nil {
+				return nil, fmt.Errorf("invalid weight of %q: %w", name, err)
+			}
+		}
+
+		kind, err := ParseKind(strings.TrimSpace(name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		mix[kind] += uint(weight)
+	}
+
+	if mix.total() == 0 {
+		return nil, fmt.Errorf("empty transaction mix: %q", s)
+	}
+
+	return mix, nil
+}
+
+func (m Mix) String() string {
+	items := make([]string, 0, len(m))
+
+	for _, kind := range m.kinds() {
+		items = append(items, fmt.Sprintf("%s=%d", kind, m[kind]))
+	}
+
+	return strings.Join(items, ",")
+}
+
+func (m Mix) total() uint {
+	var total uint
+
+	for _, weight := range m {
+		total += weight
+	}
+
+	return total
+}
+
+// kinds returns kinds with non-zero weight in stable order
+func (m Mix) kinds() []Kind {
+	kinds := make([]Kind, 0, len(m))
+
+	for kind, weight := range m {
+		if weight > 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+
+	return kinds
+}
+
+// pick selects kind of the next transaction proportionally to its weight
+func (m Mix) pick(rnd *rand.Rand) Kind {
+	n := uint(rnd.Int63n(int64(m.total())))
+
+	for _, kind := range m.kinds() {
+		if n < m[kind] {
+			return kind
+		}
+
+		n -= m[kind]
+	}
+
+	panic("unreachable")
+}
+
+type Config struct {
+	TPS              uint          // target amount of transactions sent per second (to all nodes)
+	Duration         time.Duration // how long to send transactions
+	Mix              Mix           // traffic mix
+	From             string        // account which funds senders and deploys contracts
+	Senders          uint          // amount of sender accounts - spreads txs between accounts to not hit txpool per-account limits
+	SenderFunds      float64       // amount of ether sent to every sender
+	NestedDepth      uint          // depth of nested calls made by NestedCall transactions
+	InclusionTimeout time.Duration // how long to wait for inclusion of sent transactions after sending is finished
+}
+
+var DefaultConfig = Config{
+	TPS:      10,
+	Duration: time.Minute,
+	Mix: Mix{
+		Legacy:         1,
+		DynamicFee:     1,
+		ContractDeploy: 1,
+		NestedCall:     1,
+	},
+	From:             accounts.DevAddress,
+	Senders:          4,
+	SenderFunds:      100,
+	NestedDepth:      8,
+	InclusionTimeout: time.Minute,
+}
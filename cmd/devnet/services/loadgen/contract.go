@@ -0,0 +1,24 @@
+package loadgen
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+)
+
+// nestedCallsInitCode deploys a contract which, being called with 32-byte `depth` argument,
+// calls itself with `depth-1` until depth is 0:
+//
+//	runtime: PUSH1 0 CALLDATALOAD DUP1 ISZERO PUSH1 end JUMPI          // depth == 0 => stop
+//	         PUSH1 1 SWAP1 SUB PUSH1 0 MSTORE                          // mem[0:32] = depth-1
+//	         PUSH1 0 PUSH1 0 PUSH1 32 PUSH1 0 PUSH1 0 ADDRESS GAS CALL // call(gas, self, 0, mem[0:32], nil)
+//	         POP end: JUMPDEST STOP
+//	init:    PUSH1 len(runtime) DUP1 PUSH1 len(init) PUSH1 0 CODECOPY PUSH1 0 RETURN
+var nestedCallsInitCode = hexutility.MustDecodeHex(
+	"0x601f80600b6000396000f3" +
+		"6000358015601d576001900360005260006000602060006000305af1505b00")
+
+func nestedCallsInput(depth uint) []byte {
+	input := uint256.NewInt(uint64(depth)).Bytes32()
+	return input[:]
+}
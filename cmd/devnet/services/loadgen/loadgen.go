@@ -0,0 +1,495 @@
+package loadgen
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/blocks"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+const (
+	sendInterval    = 100 * time.Millisecond
+	feesInterval    = time.Second
+	receiptInterval = 500 * time.Millisecond
+
+	nestedCallGasPerLevel = 50_000
+	deployGas             = 200_000
+)
+
+var ErrAlreadyRunning = errors.New("load generator is already running")
+
+var _ devnet.Service = (*LoadGenerator)(nil)
+
+// LoadGenerator - devnet service which sends configurable mix of transactions with target rate
+// to the nodes of the current network (round-robin) and collects inclusion statistics.
+// It does nothing until Run is called - usually by the GenerateLoad scenario step.
+type LoadGenerator struct {
+	sync.Mutex
+	cfg    Config
+	cancel context.CancelFunc
+}
+
+func NewLoadGenerator(cfg Config) *LoadGenerator {
+	return &LoadGenerator{cfg: cfg}
+}
+
+func (g *LoadGenerator) Config() Config {
+	return g.cfg
+}
+
+func (g *LoadGenerator) Start(_ context.Context) error {
+	return nil
+}
+
+func (g *LoadGenerator) Stop() {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *LoadGenerator) NodeCreated(_ context.Context, _ devnet.Node) {}
+
+func (g *LoadGenerator) NodeStarted(_ context.Context, _ devnet.Node) {}
+
+// Run sends transactions for the configured duration, then waits for their inclusion.
+// Returned summary is valid even if an error happened in the middle of the run.
+func (g *LoadGenerator) Run(ctx context.Context) (Summary, error) {
+	stats := NewStats()
+
+	network := devnet.CurrentNetwork(ctx)
+
+	if network == nil || len(network.Nodes) == 0 {
+		return stats.Summary(), fmt.Errorf("load generator: no network nodes")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	g.Lock()
+	if g.cancel != nil {
+		g.Unlock()
+		return stats.Summary(), ErrAlreadyRunning
+	}
+	g.cancel = cancel
+	g.Unlock()
+
+	defer func() {
+		g.Lock()
+		defer g.Unlock()
+		g.cancel = nil
+	}()
+
+	r := &run{
+		cfg:     g.cfg,
+		nodes:   network.Nodes,
+		stats:   stats,
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		pending: map[libcommon.Hash]sentTx{},
+	}
+
+	err := r.run(ctx)
+
+	return stats.Summary(), err
+}
+
+type sender struct {
+	account *accounts.Account
+	nonce   uint64
+}
+
+type sentTx struct {
+	kind   Kind
+	sentAt time.Time
+}
+
+type run struct {
+	cfg   Config
+	nodes []devnet.Node
+	stats *Stats
+	rnd   *rand.Rand
+
+	chainID *uint256.Int
+	signer  *types.Signer
+	senders []*sender
+	tip     *uint256.Int
+	feeCap  *uint256.Int
+
+	nestedCalls libcommon.Address // address of deployed nested-calls contract
+	blobs       *types.BlobTxWrapper
+
+	pendingLock sync.Mutex
+	pending     map[libcommon.Hash]sentTx
+}
+
+func (r *run) run(ctx context.Context) error {
+	logger := devnet.Logger(ctx)
+	node := devnet.SelectNode(ctx)
+
+	r.chainID = uint256.MustFromBig(node.ChainID())
+	r.signer = types.LatestSignerForChainID(node.ChainID())
+	r.tip = uint256.NewInt(libcommon.GWei)
+
+	if err := r.updateFees(ctx); err != nil {
+		return err
+	}
+
+	logger.Info("[loadgen] preparing", "mix", r.cfg.Mix, "tps", r.cfg.TPS, "duration", r.cfg.Duration, "senders", r.cfg.Senders)
+
+	if err := r.prepare(ctx, node); err != nil {
+		return fmt.Errorf("load generator: %w", err)
+	}
+
+	trackerCtx, stopTracker := context.WithCancel(ctx)
+	trackerDone := make(chan struct{})
+
+	go func() {
+		defer close(trackerDone)
+		r.trackInclusion(trackerCtx, node)
+	}()
+
+	logger.Info("[loadgen] sending")
+
+	err := r.send(ctx)
+
+	logger.Info("[loadgen] awaiting inclusion", "pending", r.pendingCount(), "timeout", r.cfg.InclusionTimeout)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, r.cfg.InclusionTimeout)
+	defer waitCancel()
+
+	ticker := time.NewTicker(receiptInterval)
+	defer ticker.Stop()
+
+await:
+	for r.pendingCount() > 0 {
+		select {
+		case <-waitCtx.Done():
+			break await
+		case <-ticker.C:
+		}
+	}
+
+	stopTracker()
+	<-trackerDone
+
+	r.stats.Summary().Log(logger)
+
+	return err
+}
+
+// prepare funds senders and deploys contracts required by the mix
+func (r *run) prepare(ctx context.Context, node devnet.Node) error {
+	from := accounts.GetAccount(r.cfg.From)
+
+	if from == nil {
+		return fmt.Errorf("unknown from account: %s", r.cfg.From)
+	}
+
+	nonce, err := r.pendingNonce(node, from.Address)
+
+	if err != nil {
+		return err
+	}
+
+	var hashes []libcommon.Hash
+
+	if r.cfg.Senders == 0 {
+		r.senders = append(r.senders, &sender{account: from})
+	}
+
+	for i := uint(0); i < r.cfg.Senders; i++ {
+		account := accounts.NewAccount(fmt.Sprintf("loadgen-sender-%d", i))
+		amount, _ := uint256.FromBig(accounts.EtherAmount(r.cfg.SenderFunds))
+
+		hash, err := r.signAndSend(node, types.NewTransaction(nonce, account.Address, amount, params.TxGas, r.feeCap, nil), from.SigKey())
+
+		if err != nil {
+			return fmt.Errorf("can't fund %s: %w", account.Name, err)
+		}
+
+		hashes = append(hashes, hash)
+		nonce++
+
+		r.senders = append(r.senders, &sender{account: account})
+	}
+
+	if r.cfg.Mix[NestedCall] > 0 {
+		hash, err := r.signAndSend(node, types.NewContractCreation(nonce, uint256.NewInt(0), deployGas, r.feeCap, nestedCallsInitCode), from.SigKey())
+
+		if err != nil {
+			return fmt.Errorf("can't deploy nested calls contract: %w", err)
+		}
+
+		r.nestedCalls = crypto.CreateAddress(from.Address, nonce)
+		hashes = append(hashes, hash)
+		nonce++
+	}
+
+	if r.cfg.Mix[Blob] > 0 {
+		blobs := types.Blobs{types.Blob{}}
+		commitments, versionedHashes, proofs, err := blobs.ComputeCommitmentsAndProofs()
+
+		if err != nil {
+			return err
+		}
+
+		r.blobs = &types.BlobTxWrapper{
+			Tx:          types.BlobTx{BlobVersionedHashes: versionedHashes},
+			Commitments: commitments,
+			Blobs:       blobs,
+			Proofs:      proofs,
+		}
+	}
+
+	if err := awaitReceipts(ctx, node, r.cfg.InclusionTimeout, hashes...); err != nil {
+		return err
+	}
+
+	// senders nonces: from account may be used by other scenarios - so read it once all preparations done
+	for _, s := range r.senders {
+		if s.nonce, err = r.pendingNonce(node, s.account.Address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send sends transactions until configured duration passes, with `TPS` rate
+func (r *run) send(ctx context.Context) error {
+	logger := devnet.Logger(ctx)
+
+	ticker := time.NewTicker(sendInterval)
+	defer ticker.Stop()
+
+	started := time.Now()
+	r.stats.start(started)
+	defer func() { r.stats.finish(time.Now()) }()
+
+	lastFeesUpdate := started
+	var count uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			elapsed := now.Sub(started)
+
+			if elapsed > r.cfg.Duration {
+				return nil
+			}
+
+			if now.Sub(lastFeesUpdate) > feesInterval {
+				if err := r.updateFees(ctx); err != nil {
+					logger.Warn("[loadgen] can't update fees", "err", err)
+				}
+				lastFeesUpdate = now
+			}
+
+			for due := uint64(elapsed.Seconds() * float64(r.cfg.TPS)); count < due; count++ {
+				r.sendOne(ctx, r.senders[count%uint64(len(r.senders))], r.nodes[count%uint64(len(r.nodes))])
+			}
+		}
+	}
+}
+
+func (r *run) sendOne(ctx context.Context, s *sender, node devnet.Node) {
+	kind := r.cfg.Mix.pick(r.rnd)
+
+	hash, err := r.signAndSend(node, r.newTransaction(kind, s), s.account.SigKey())
+
+	if err != nil {
+		r.stats.sendFailed(kind)
+		devnet.Logger(ctx).Debug("[loadgen] send failed", "kind", kind, "node", node.GetName(), "err", err)
+
+		// nonce may be out of sync after failure - re-read it
+		if nonce, err := r.pendingNonce(node, s.account.Address); err == nil {
+			s.nonce = nonce
+		}
+
+		return
+	}
+
+	s.nonce++
+	r.stats.sent(kind)
+
+	r.pendingLock.Lock()
+	r.pending[hash] = sentTx{kind: kind, sentAt: time.Now()}
+	r.pendingLock.Unlock()
+}
+
+func (r *run) newTransaction(kind Kind, s *sender) types.Transaction {
+	to := r.senders[r.rnd.Intn(len(r.senders))].account.Address
+	value := uint256.NewInt(1)
+
+	switch kind {
+	case Legacy:
+		return types.NewTransaction(s.nonce, to, value, params.TxGas, r.feeCap, nil)
+	case DynamicFee:
+		return types.NewEIP1559Transaction(*r.chainID, s.nonce, to, value, params.TxGas, r.feeCap, r.tip, r.feeCap, nil)
+	case Blob:
+		return &types.BlobTx{
+			DynamicFeeTransaction: *types.NewEIP1559Transaction(*r.chainID, s.nonce, to, value, params.TxGas, r.feeCap, r.tip, r.feeCap, nil),
+			MaxFeePerBlobGas:      r.feeCap,
+			BlobVersionedHashes:   r.blobs.Tx.BlobVersionedHashes,
+		}
+	case ContractDeploy:
+		return types.NewContractCreation(s.nonce, uint256.NewInt(0), deployGas, r.feeCap, nestedCallsInitCode)
+	case NestedCall:
+		gas := params.TxGas + uint64(r.cfg.NestedDepth+1)*nestedCallGasPerLevel
+		return types.NewEIP1559Transaction(*r.chainID, s.nonce, r.nestedCalls, uint256.NewInt(0), gas, r.feeCap, r.tip, r.feeCap, nestedCallsInput(r.cfg.NestedDepth))
+	default:
+		panic(fmt.Sprintf("unknown transaction kind: %s", kind))
+	}
+}
+
+// signAndSend signs and sends transaction, blob transactions are sent together with blobs
+func (r *run) signAndSend(node devnet.Node, txn types.Transaction, key *ecdsa.PrivateKey) (libcommon.Hash, error) {
+	if key == nil {
+		return libcommon.Hash{}, fmt.Errorf("private key not found")
+	}
+
+	signed, err := types.SignTx(txn, *r.signer, key)
+
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	if blobTx, ok := signed.(*types.BlobTx); ok {
+		wrapper := *r.blobs
+		wrapper.Tx = *blobTx
+		signed = &wrapper
+	}
+
+	return node.SendTransaction(signed)
+}
+
+func (r *run) updateFees(ctx context.Context) error {
+	baseFee, err := blocks.BaseFeeFromBlock(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// 2x of base fee - to stay includable while base fee grows under load
+	r.feeCap = new(uint256.Int).Add(uint256.NewInt(2*baseFee), r.tip)
+
+	return nil
+}
+
+func (r *run) pendingNonce(node devnet.Node, address libcommon.Address) (uint64, error) {
+	count, err := node.GetTransactionCount(address, rpc.PendingBlock)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transaction count for address 0x%x: %w", address, err)
+	}
+
+	return count.Uint64(), nil
+}
+
+func (r *run) pendingCount() int {
+	r.pendingLock.Lock()
+	defer r.pendingLock.Unlock()
+	return len(r.pending)
+}
+
+// trackInclusion polls receipts of pending transactions and records inclusion latencies
+func (r *run) trackInclusion(ctx context.Context, node devnet.Node) {
+	ticker := time.NewTicker(receiptInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.pendingLock.Lock()
+		hashes := make([]libcommon.Hash, 0, len(r.pending))
+		for hash := range r.pending {
+			hashes = append(hashes, hash)
+		}
+		r.pendingLock.Unlock()
+
+		for _, hash := range hashes {
+			receipt, ok := getReceipt(ctx, node, hash)
+
+			if !ok {
+				continue
+			}
+
+			now := time.Now()
+
+			r.pendingLock.Lock()
+			sent := r.pending[hash]
+			delete(r.pending, hash)
+			r.pendingLock.Unlock()
+
+			r.stats.included(sent.kind, now.Sub(sent.sentAt), receipt.Status == types.ReceiptStatusSuccessful)
+		}
+	}
+}
+
+// getReceipt returns false if transaction is not included yet
+func getReceipt(ctx context.Context, node devnet.Node, hash libcommon.Hash) (*types.Receipt, bool) {
+	receipt, err := node.GetTransactionReceipt(ctx, hash)
+
+	if err != nil || receipt == nil || receipt.BlockNumber == nil {
+		return nil, false
+	}
+
+	return receipt, true
+}
+
+func awaitReceipts(ctx context.Context, node devnet.Node, timeout time.Duration, hashes ...libcommon.Hash) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptInterval)
+	defer ticker.Stop()
+
+	for len(hashes) > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d transactions not included: %w", len(hashes), ctx.Err())
+		case <-ticker.C:
+		}
+
+		var notIncluded []libcommon.Hash
+
+		for _, hash := range hashes {
+			receipt, ok := getReceipt(ctx, node, hash)
+
+			if !ok {
+				notIncluded = append(notIncluded, hash)
+				continue
+			}
+
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("transaction %x failed", hash)
+			}
+		}
+
+		hashes = notIncluded
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package loadgen
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/vm/runtime"
+)
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix("legacy=1, dynamic=3,nested,legacy=2")
+	require.NoError(t, err)
+	require.Equal(t, Mix{Legacy: 3, DynamicFee: 3, NestedCall: 1}, mix)
+	require.Equal(t, "legacy=3,dynamic=3,nested=1", mix.String())
+
+	parsed, err := ParseMix(DefaultConfig.Mix.String())
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfig.Mix, parsed)
+
+	_, err = ParseMix("legacy=1,unknown=1")
+	require.Error(t, err)
+	_, err = ParseMix("legacy=x")
+	require.Error(t, err)
+	_, err = ParseMix("legacy=0")
+	require.Error(t, err)
+	_, err = ParseMix("")
+	require.Error(t, err)
+}
+
+func TestMixPick(t *testing.T) {
+	mix := Mix{Legacy: 1, Blob: 3, ContractDeploy: 0}
+	rnd := rand.New(rand.NewSource(1))
+
+	picked := map[Kind]int{}
+	for i := 0; i < 4_000; i++ {
+		picked[mix.pick(rnd)]++
+	}
+
+	require.Zero(t, picked[ContractDeploy])
+	require.InDelta(t, 1_000, picked[Legacy], 150)
+	require.InDelta(t, 3_000, picked[Blob], 150)
+}
+
+func TestStatsSummary(t *testing.T) {
+	stats := NewStats()
+	started := time.Now()
+	stats.start(started)
+
+	for i := 1; i <= 100; i++ {
+		stats.sent(Legacy)
+		stats.included(Legacy, time.Duration(i)*time.Millisecond, true)
+	}
+	stats.sent(NestedCall)
+	stats.sent(NestedCall)
+	stats.included(NestedCall, time.Second, false)
+	stats.sendFailed(NestedCall)
+
+	stats.finish(started.Add(10 * time.Second))
+
+	summary := stats.Summary()
+	require.Equal(t, 10*time.Second, summary.Elapsed)
+	require.InDelta(t, 10.2, summary.TPS, 0.001)
+	require.Len(t, summary.Kinds, 2)
+
+	legacy := summary.Kinds[0]
+	require.Equal(t, Legacy, legacy.Kind)
+	require.Equal(t, uint64(100), legacy.Included)
+	require.Equal(t, 50*time.Millisecond, legacy.LatencyP50)
+	require.Equal(t, 90*time.Millisecond, legacy.LatencyP90)
+	require.Equal(t, 99*time.Millisecond, legacy.LatencyP99)
+	require.Equal(t, 100*time.Millisecond, legacy.LatencyMax)
+
+	nested := summary.Kinds[1]
+	require.Equal(t, NestedCall, nested.Kind)
+	require.Equal(t, uint64(2), nested.Sent)
+	require.Equal(t, uint64(1), nested.SendFailed)
+	require.Equal(t, uint64(1), nested.Reverted)
+	require.Equal(t, uint64(1), nested.NotIncluded)
+
+	require.Equal(t, uint64(102), summary.Total.Sent)
+	require.Equal(t, uint64(101), summary.Total.Included)
+	require.Equal(t, time.Second, summary.Total.LatencyMax)
+}
+
+func TestNestedCallsContract(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	cfg := &runtime.Config{State: state.New(state.NewDbStateReader(tx)), GasLimit: 10_000_000}
+
+	code, address, _, err := runtime.Create(nestedCallsInitCode, cfg, 0)
+	require.NoError(t, err)
+	require.Equal(t, nestedCallsInitCode[11:], code)
+
+	gasUsed := func(depth uint) uint64 {
+		_, leftOverGas, err := runtime.Call(address, nestedCallsInput(depth), cfg)
+		require.NoError(t, err)
+		return cfg.GasLimit - leftOverGas
+	}
+
+	// every nested call costs at least 100 gas (warm CALL)
+	shallow, deep := gasUsed(0), gasUsed(8)
+	require.Greater(t, deep, shallow+8*100)
+}
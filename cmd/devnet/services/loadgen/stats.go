@@ -0,0 +1,193 @@
+package loadgen
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+type kindStats struct {
+	sent       uint64
+	sendFailed uint64
+	included   uint64
+	reverted   uint64
+	latencies  []time.Duration // send => receipt available
+}
+
+// Stats - counters and inclusion latencies of generated transactions, safe for concurrent use
+type Stats struct {
+	sync.Mutex
+	started  time.Time
+	finished time.Time
+	kinds    map[Kind]*kindStats
+}
+
+func NewStats() *Stats {
+	return &Stats{kinds: map[Kind]*kindStats{}}
+}
+
+func (s *Stats) kind(kind Kind) *kindStats {
+	ks, ok := s.kinds[kind]
+
+	if !ok {
+		ks = &kindStats{}
+		s.kinds[kind] = ks
+	}
+
+	return ks
+}
+
+func (s *Stats) start(now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.started = now
+}
+
+func (s *Stats) finish(now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.finished = now
+}
+
+func (s *Stats) sent(kind Kind) {
+	s.Lock()
+	defer s.Unlock()
+	s.kind(kind).sent++
+}
+
+func (s *Stats) sendFailed(kind Kind) {
+	s.Lock()
+	defer s.Unlock()
+	s.kind(kind).sendFailed++
+}
+
+func (s *Stats) included(kind Kind, latency time.Duration, success bool) {
+	s.Lock()
+	defer s.Unlock()
+	ks := s.kind(kind)
+	ks.included++
+	if !success {
+		ks.reverted++
+	}
+	ks.latencies = append(ks.latencies, latency)
+}
+
+type KindSummary struct {
+	Kind        Kind
+	Sent        uint64
+	SendFailed  uint64
+	Included    uint64
+	Reverted    uint64
+	NotIncluded uint64
+	LatencyP50  time.Duration
+	LatencyP90  time.Duration
+	LatencyP99  time.Duration
+	LatencyMax  time.Duration
+}
+
+type Summary struct {
+	Elapsed time.Duration
+	TPS     float64 // achieved rate of successfully sent transactions
+	Kinds   []KindSummary
+	Total   KindSummary
+}
+
+func (s *Stats) Summary() Summary {
+	s.Lock()
+	defer s.Unlock()
+
+	var summary Summary
+	var total kindStats
+
+	kinds := make([]Kind, 0, len(s.kinds))
+	for kind := range s.kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+
+	for _, kind := range kinds {
+		ks := s.kinds[kind]
+		summary.Kinds = append(summary.Kinds, ks.summary(kind))
+
+		total.sent += ks.sent
+		total.sendFailed += ks.sendFailed
+		total.included += ks.included
+		total.reverted += ks.reverted
+		total.latencies = append(total.latencies, ks.latencies...)
+	}
+
+	summary.Total = total.summary(-1)
+
+	if !s.started.IsZero() {
+		finished := s.finished
+		if finished.IsZero() {
+			finished = time.Now()
+		}
+		summary.Elapsed = finished.Sub(s.started)
+	}
+
+	if summary.Elapsed > 0 {
+		summary.TPS = float64(summary.Total.Sent) / summary.Elapsed.Seconds()
+	}
+
+	return summary
+}
+
+func (ks *kindStats) summary(kind Kind) KindSummary {
+	sorted := make([]time.Duration, len(ks.latencies))
+	copy(sorted, ks.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return KindSummary{
+		Kind:        kind,
+		Sent:        ks.sent,
+		SendFailed:  ks.sendFailed,
+		Included:    ks.included,
+		Reverted:    ks.reverted,
+		NotIncluded: ks.sent - ks.included,
+		LatencyP50:  percentile(sorted, 50),
+		LatencyP90:  percentile(sorted, 90),
+		LatencyP99:  percentile(sorted, 99),
+		LatencyMax:  percentile(sorted, 100),
+	}
+}
+
+// percentile - nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func (s Summary) Log(logger log.Logger) {
+	for _, ks := range s.Kinds {
+		logger.Info("[loadgen] stats", ks.logCtx()...)
+	}
+
+	logger.Info("[loadgen] total", append([]interface{}{"elapsed", s.Elapsed.Round(time.Millisecond), "tps", s.TPS}, s.Total.logCtx()[2:]...)...)
+}
+
+func (ks KindSummary) logCtx() []interface{} {
+	return []interface{}{
+		"kind", ks.Kind,
+		"sent", ks.Sent,
+		"sendFailed", ks.SendFailed,
+		"included", ks.Included,
+		"reverted", ks.Reverted,
+		"notIncluded", ks.NotIncluded,
+		"p50", ks.LatencyP50.Round(time.Millisecond),
+		"p90", ks.LatencyP90.Round(time.Millisecond),
+		"p99", ks.LatencyP99.Round(time.Millisecond),
+		"max", ks.LatencyMax.Round(time.Millisecond),
+	}
+}
@@ -0,0 +1,38 @@
+package loadgen_steps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/loadgen"
+)
+
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(GenerateLoad),
+	)
+}
+
+// GenerateLoad runs load generator service of the current network with its configuration,
+// fails if none of the sent transactions was included
+func GenerateLoad(ctx context.Context) (loadgen.Summary, error) {
+	loadGenerator := services.LoadGenerator(ctx)
+
+	if loadGenerator == nil {
+		return loadgen.Summary{}, fmt.Errorf("load generator service is not configured for the current network")
+	}
+
+	summary, err := loadGenerator.Run(ctx)
+
+	if err != nil {
+		return summary, err
+	}
+
+	if summary.Total.Sent > 0 && summary.Total.Included == 0 {
+		return summary, fmt.Errorf("none of %d sent transactions was included", summary.Total.Sent)
+	}
+
+	return summary, nil
+}
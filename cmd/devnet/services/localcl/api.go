@@ -0,0 +1,183 @@
+package localcl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+)
+
+// BlobSidecar - blob of a block with its kzg commitment and proof, as served by the beacon api
+type BlobSidecar struct {
+	Index         uint64           `json:"index,string"`
+	Blob          hexutility.Bytes `json:"blob"`
+	KZGCommitment hexutility.Bytes `json:"kzg_commitment"`
+	KZGProof      hexutility.Bytes `json:"kzg_proof"`
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiHandler serves the subset of the beacon api needed by devnet scenarios:
+//
+//	GET /eth/v1/beacon/blob_sidecars/{block_id}?indices=0,1
+//
+// block_id is `head`, a slot (execution block number) or an execution block hash
+func (cl *LocalCL) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/beacon/blob_sidecars/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		blockID := strings.TrimPrefix(r.URL.Path, "/eth/v1/beacon/blob_sidecars/")
+		slot, err := cl.resolveBlockID(blockID)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		sidecars, ok := cl.Sidecars(slot)
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, fmt.Sprintf("block not found: %s", blockID))
+			return
+		}
+
+		if indices := r.URL.Query().Get("indices"); indices != "" {
+			if sidecars, err = filterSidecars(sidecars, indices); err != nil {
+				writeAPIError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		if sidecars == nil {
+			sidecars = []BlobSidecar{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Data []BlobSidecar `json:"data"`
+		}{sidecars})
+	})
+
+	return mux
+}
+
+func (cl *LocalCL) resolveBlockID(blockID string) (uint64, error) {
+	cl.Lock()
+	defer cl.Unlock()
+
+	switch {
+	case blockID == "head":
+		return cl.slot, nil
+	case strings.HasPrefix(blockID, "0x"):
+		hash := libcommon.HexToHash(blockID)
+		if slot, ok := cl.slotsByEL[hash]; ok {
+			return slot, nil
+		}
+		return 0, fmt.Errorf("unknown block: %s", blockID)
+	default:
+		slot, err := strconv.ParseUint(blockID, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid block id: %s", blockID)
+		}
+		return slot, nil
+	}
+}
+
+func filterSidecars(sidecars []BlobSidecar, indices string) ([]BlobSidecar, error) {
+	var res []BlobSidecar
+
+	for _, s := range strings.Split(indices, ",") {
+		index, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index: %s", s)
+		}
+
+		if index < uint64(len(sidecars)) {
+			res = append(res, sidecars[index])
+		}
+	}
+
+	return res, nil
+}
+
+func writeAPIError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(apiError{Code: code, Message: message})
+}
+
+// FetchSidecars requests blob sidecars of the block from the beacon api at apiURL
+func FetchSidecars(ctx context.Context, apiURL string, blockID string) ([]BlobSidecar, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/eth/v1/beacon/blob_sidecars/"+blockID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr apiError
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, fmt.Errorf("blob sidecars of %s: %d %s", blockID, resp.StatusCode, apiErr.Message)
+	}
+
+	var res struct {
+		Data []BlobSidecar `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return res.Data, nil
+}
+
+// VerifySidecars checks that sidecars hold the blobs of the versioned hashes (in order of transactions of the block)
+// and that their kzg proofs are valid
+func VerifySidecars(sidecars []BlobSidecar, versionedHashes []libcommon.Hash) error {
+	if len(sidecars) != len(versionedHashes) {
+		return fmt.Errorf("expected %d blob sidecars, got %d", len(versionedHashes), len(sidecars))
+	}
+
+	kzgCtx := kzg.Ctx()
+
+	for i, sidecar := range sidecars {
+		var blob gokzg4844.Blob
+		var commitment gokzg4844.KZGCommitment
+		var proof gokzg4844.KZGProof
+
+		if sidecar.Index != uint64(i) || len(sidecar.Blob) != len(blob) || len(sidecar.KZGCommitment) != len(commitment) || len(sidecar.KZGProof) != len(proof) {
+			return fmt.Errorf("malformed blob sidecar %d", i)
+		}
+
+		copy(blob[:], sidecar.Blob)
+		copy(commitment[:], sidecar.KZGCommitment)
+		copy(proof[:], sidecar.KZGProof)
+
+		if hash := libcommon.Hash(kzg.KZGToVersionedHash(commitment)); hash != versionedHashes[i] {
+			return fmt.Errorf("blob sidecar %d: commitment of versioned hash %x, expected %x", i, hash, versionedHashes[i])
+		}
+
+		if err := kzgCtx.VerifyBlobKZGProof(blob, commitment, proof); err != nil {
+			return fmt.Errorf("blob sidecar %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package localcl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/crypto/kzg"
+	"github.com/ledgerwatch/erigon/cl/phase1/execution_client/rpc_helper"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+)
+
+type Config struct {
+	SlotTime  time.Duration // interval between blocks
+	BuildTime time.Duration // time given to the producer to fill the payload with transactions
+	APIPort   int           // port of the beacon api serving blob sidecars
+	Prague    bool          // chain has Prague activated, engine api v4 methods are used
+}
+
+var DefaultConfig = Config{
+	SlotTime:  4 * time.Second,
+	BuildTime: time.Second,
+	APIPort:   5555,
+}
+
+// sidecarsRetention - number of latest blocks which blob sidecars are served for
+const sidecarsRetention = 4096
+
+// LocalCL - consensus layer of the proof-of-stake dev network. It drives the nodes via the engine api: block
+// producers take turns building payloads, which are then imported by all nodes. There is no beacon chain: a slot
+// is the number of the execution block, parent beacon block roots are derived from it. Blobs of the built payloads
+// are kept and served by a subset of the beacon api, so blob pipelines can be checked end-to-end.
+type LocalCL struct {
+	sync.Mutex
+	cfg    Config
+	logger log.Logger
+	nodes  []*engineNode
+	server *http.Server
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	slot      uint64
+	head      libcommon.Hash
+	headTime  uint64
+	produced  int // blocks produced, selects the next producer
+	sidecars  map[uint64][]BlobSidecar
+	slotsByEL map[libcommon.Hash]uint64
+}
+
+type engineNode struct {
+	node   devnet.Node
+	client *rpc.Client
+}
+
+func NewLocalCL(cfg Config, logger log.Logger) *LocalCL {
+	return &LocalCL{
+		cfg:       cfg,
+		logger:    logger,
+		sidecars:  map[uint64][]BlobSidecar{},
+		slotsByEL: map[libcommon.Hash]uint64{},
+	}
+}
+
+func (cl *LocalCL) Start(ctx context.Context) error {
+	cl.server = &http.Server{
+		Addr:              fmt.Sprintf("localhost:%d", cl.cfg.APIPort),
+		Handler:           cl.apiHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := cl.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			cl.logger.Error("[localcl] beacon api stopped", "err", err)
+		}
+	}()
+
+	ctx, cl.cancel = context.WithCancel(ctx)
+	cl.done = make(chan struct{})
+	go cl.run(ctx)
+
+	return nil
+}
+
+func (cl *LocalCL) Stop() {
+	if cl.cancel != nil {
+		cl.cancel()
+		<-cl.done
+	}
+
+	if cl.server != nil {
+		_ = cl.server.Close()
+	}
+
+	for _, n := range cl.engineNodes() {
+		if n.client != nil {
+			n.client.Close()
+		}
+	}
+}
+
+func (cl *LocalCL) NodeCreated(_ context.Context, node devnet.Node) {
+	cl.Lock()
+	defer cl.Unlock()
+	cl.nodes = append(cl.nodes, &engineNode{node: node})
+}
+
+func (cl *LocalCL) NodeStarted(_ context.Context, _ devnet.Node) {
+}
+
+// APIURL returns the base url of the beacon api of the local consensus layer
+func (cl *LocalCL) APIURL() string {
+	return fmt.Sprintf("http://localhost:%d", cl.cfg.APIPort)
+}
+
+// Sidecars returns blob sidecars of the block at the slot (execution block number), false if unknown
+func (cl *LocalCL) Sidecars(slot uint64) ([]BlobSidecar, bool) {
+	cl.Lock()
+	defer cl.Unlock()
+	sidecars, ok := cl.sidecars[slot]
+	return sidecars, ok
+}
+
+func (cl *LocalCL) engineNodes() []*engineNode {
+	cl.Lock()
+	defer cl.Unlock()
+	return append([]*engineNode(nil), cl.nodes...)
+}
+
+func (cl *LocalCL) run(ctx context.Context) {
+	defer close(cl.done)
+
+	ticker := time.NewTicker(cl.cfg.SlotTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := cl.connect(ctx); err != nil {
+			// nodes are still starting
+			cl.logger.Debug("[localcl] waiting for engine api", "err", err)
+			continue
+		}
+
+		if err := cl.produceBlock(ctx); err != nil && ctx.Err() == nil {
+			cl.logger.Warn("[localcl] block production failed", "slot", cl.slot+1, "err", err)
+		}
+	}
+}
+
+// connect dials engine api of nodes which aren't connected yet and reads the head of the chain
+func (cl *LocalCL) connect(ctx context.Context) error {
+	nodes := cl.engineNodes()
+	if len(nodes) == 0 {
+		return errors.New("no nodes")
+	}
+
+	for _, n := range nodes {
+		if n.client != nil {
+			continue
+		}
+
+		jwtSecret, err := readJWTSecret(filepath.Join(n.node.GetDataDir(), "jwt.hex"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", n.node.GetName(), err)
+		}
+
+		client := &http.Client{Timeout: 30 * time.Second, Transport: rpc_helper.NewJWTRoundTripper(jwtSecret)}
+		n.client, err = rpc.DialHTTPWithClient(fmt.Sprintf("http://localhost:%d", n.node.GetAuthRpcPort()), client, cl.logger)
+		if err != nil {
+			return fmt.Errorf("%s: %w", n.node.GetName(), err)
+		}
+	}
+
+	if cl.head != (libcommon.Hash{}) {
+		return nil
+	}
+
+	head, err := nodes[0].node.GetBlockByNumber(ctx, rpc.LatestBlockNumber, false)
+	if err != nil {
+		return err
+	}
+
+	cl.Lock()
+	cl.slot, cl.head, cl.headTime = head.Number.Uint64(), head.Hash, head.Time
+	cl.Unlock()
+
+	return nil
+}
+
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := libcommon.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid jwt secret in %s", path)
+	}
+
+	return secret, nil
+}
+
+// produceBlock builds the payload of the next slot on one of the block producers and imports it on all nodes
+func (cl *LocalCL) produceBlock(ctx context.Context) error {
+	nodes := cl.engineNodes()
+
+	var producers []*engineNode
+	for _, n := range nodes {
+		if n.node.IsBlockProducer() {
+			producers = append(producers, n)
+		}
+	}
+
+	if len(producers) == 0 {
+		return errors.New("no block producers")
+	}
+
+	producer := producers[cl.produced%len(producers)]
+
+	slot := cl.slot + 1
+	timestamp := uint64(time.Now().Unix())
+	if timestamp <= cl.headTime {
+		timestamp = cl.headTime + 1
+	}
+
+	var prevRandao libcommon.Hash
+	if _, err := rand.Read(prevRandao[:]); err != nil {
+		return err
+	}
+
+	feeRecipient := libcommon.Address{}
+	if account := producer.node.Account(); account != nil {
+		feeRecipient = account.Address
+	}
+
+	beaconRoot := parentBeaconBlockRoot(slot - 1)
+
+	forkchoice := &engine_types.ForkChoiceState{HeadHash: cl.head, SafeBlockHash: cl.head, FinalizedBlockHash: cl.head}
+	var fcuResponse engine_types.ForkChoiceUpdatedResponse
+	if err := producer.client.CallContext(ctx, &fcuResponse, "engine_forkchoiceUpdatedV3", forkchoice, &engine_types.PayloadAttributes{
+		Timestamp:             hexutil.Uint64(timestamp),
+		PrevRandao:            prevRandao,
+		SuggestedFeeRecipient: feeRecipient,
+		Withdrawals:           []*types.Withdrawal{},
+		ParentBeaconBlockRoot: &beaconRoot,
+	}); err != nil {
+		return fmt.Errorf("%s: forkchoice update: %w", producer.node.GetName(), err)
+	}
+
+	if fcuResponse.PayloadId == nil {
+		status := engine_types.EngineStatus("")
+		if fcuResponse.PayloadStatus != nil {
+			status = fcuResponse.PayloadStatus.Status
+		}
+		return fmt.Errorf("%s: payload building not started, status: %s", producer.node.GetName(), status)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(cl.cfg.BuildTime):
+	}
+
+	getPayload, newPayload := "engine_getPayloadV3", "engine_newPayloadV3"
+	if cl.cfg.Prague {
+		getPayload, newPayload = "engine_getPayloadV4", "engine_newPayloadV4"
+	}
+
+	var built engine_types.GetPayloadResponse
+	if err := producer.client.CallContext(ctx, &built, getPayload, fcuResponse.PayloadId); err != nil {
+		return fmt.Errorf("%s: get payload: %w", producer.node.GetName(), err)
+	}
+
+	payload := built.ExecutionPayload
+	sidecars, versionedHashes, err := blobSidecars(built.BlobsBundle)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		var status engine_types.PayloadStatus
+		if err := n.client.CallContext(ctx, &status, newPayload, payload, versionedHashes, &beaconRoot); err != nil {
+			return fmt.Errorf("%s: new payload: %w", n.node.GetName(), err)
+		}
+
+		if status.Status == engine_types.InvalidStatus || status.Status == engine_types.InvalidBlockHashStatus {
+			var validationErr error
+			if status.ValidationError != nil {
+				validationErr = status.ValidationError.Error()
+			}
+			return fmt.Errorf("%s: payload %d %x is %s: %v", n.node.GetName(), payload.BlockNumber, payload.BlockHash, status.Status, validationErr)
+		}
+	}
+
+	forkchoice = &engine_types.ForkChoiceState{HeadHash: payload.BlockHash, SafeBlockHash: payload.BlockHash, FinalizedBlockHash: payload.BlockHash}
+	for _, n := range nodes {
+		var response engine_types.ForkChoiceUpdatedResponse
+		if err := n.client.CallContext(ctx, &response, "engine_forkchoiceUpdatedV3", forkchoice, nil); err != nil {
+			return fmt.Errorf("%s: forkchoice update: %w", n.node.GetName(), err)
+		}
+	}
+
+	cl.Lock()
+	defer cl.Unlock()
+
+	cl.slot, cl.head, cl.headTime = uint64(payload.BlockNumber), payload.BlockHash, uint64(payload.Timestamp)
+	cl.produced++
+	cl.sidecars[cl.slot] = sidecars
+	cl.slotsByEL[payload.BlockHash] = cl.slot
+	if cl.slot > sidecarsRetention {
+		delete(cl.sidecars, cl.slot-sidecarsRetention)
+	}
+
+	cl.logger.Debug("[localcl] block produced", "slot", cl.slot, "hash", payload.BlockHash, "producer", producer.node.GetName(),
+		"txs", len(payload.Transactions), "blobs", len(sidecars))
+
+	return nil
+}
+
+// parentBeaconBlockRoot - there is no beacon chain, roots of beacon blocks are derived from their slots
+func parentBeaconBlockRoot(slot uint64) libcommon.Hash {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], slot)
+	return crypto.Keccak256Hash([]byte("devnet-beacon-block"), b[:])
+}
+
+// blobSidecars converts blobs bundle of the payload to sidecars, returns versioned hashes of its commitments
+func blobSidecars(bundle *engine_types.BlobsBundleV1) ([]BlobSidecar, []libcommon.Hash, error) {
+	if bundle == nil {
+		return nil, []libcommon.Hash{}, nil
+	}
+
+	if len(bundle.Blobs) != len(bundle.Commitments) || len(bundle.Proofs) != len(bundle.Commitments) {
+		return nil, nil, fmt.Errorf("inconsistent blobs bundle: %d blobs, %d commitments, %d proofs",
+			len(bundle.Blobs), len(bundle.Commitments), len(bundle.Proofs))
+	}
+
+	sidecars := make([]BlobSidecar, len(bundle.Blobs))
+	versionedHashes := make([]libcommon.Hash, len(bundle.Blobs))
+
+	for i := range bundle.Blobs {
+		var commitment [48]byte
+		if len(bundle.Commitments[i]) != len(commitment) {
+			return nil, nil, fmt.Errorf("invalid commitment %d: %d bytes", i, len(bundle.Commitments[i]))
+		}
+		copy(commitment[:], bundle.Commitments[i])
+
+		versionedHashes[i] = libcommon.Hash(kzg.KZGToVersionedHash(commitment))
+		sidecars[i] = BlobSidecar{
+			Index:         uint64(i),
+			Blob:          hexutility.Bytes(bundle.Blobs[i]),
+			KZGCommitment: hexutility.Bytes(bundle.Commitments[i]),
+			KZGProof:      hexutility.Bytes(bundle.Proofs[i]),
+		}
+	}
+
+	return sidecars, versionedHashes, nil
+}
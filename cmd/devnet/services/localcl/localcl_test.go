@@ -0,0 +1,101 @@
+package localcl
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+	"github.com/ledgerwatch/log/v3"
+)
+
+func testBundle(t *testing.T, count int) (*engine_types.BlobsBundleV1, []libcommon.Hash) {
+	blobs := make(types.Blobs, count)
+	for i := range blobs {
+		blobs[i][1] = byte(i + 1)
+	}
+
+	commitments, versionedHashes, proofs, err := blobs.ComputeCommitmentsAndProofs()
+	require.NoError(t, err)
+
+	bundle := &engine_types.BlobsBundleV1{}
+	for i := range blobs {
+		bundle.Blobs = append(bundle.Blobs, hexutility.Bytes(blobs[i][:]))
+		bundle.Commitments = append(bundle.Commitments, hexutility.Bytes(commitments[i][:]))
+		bundle.Proofs = append(bundle.Proofs, hexutility.Bytes(proofs[i][:]))
+	}
+
+	return bundle, versionedHashes
+}
+
+func TestBlobSidecars(t *testing.T) {
+	bundle, versionedHashes := testBundle(t, 2)
+
+	sidecars, hashes, err := blobSidecars(bundle)
+	require.NoError(t, err)
+	require.Equal(t, versionedHashes, hashes)
+	require.Len(t, sidecars, 2)
+	require.Equal(t, uint64(1), sidecars[1].Index)
+	require.NoError(t, VerifySidecars(sidecars, versionedHashes))
+
+	require.Error(t, VerifySidecars(sidecars, []libcommon.Hash{versionedHashes[1], versionedHashes[0]}))
+	require.Error(t, VerifySidecars(sidecars[:1], versionedHashes))
+
+	sidecars[0].KZGProof, sidecars[1].KZGProof = sidecars[1].KZGProof, sidecars[0].KZGProof
+	require.Error(t, VerifySidecars(sidecars, versionedHashes))
+
+	sidecars, hashes, err = blobSidecars(nil)
+	require.NoError(t, err)
+	require.Empty(t, sidecars)
+	require.Empty(t, hashes)
+
+	bundle.Proofs = bundle.Proofs[:1]
+	_, _, err = blobSidecars(bundle)
+	require.Error(t, err)
+}
+
+func TestBlobSidecarsAPI(t *testing.T) {
+	bundle, versionedHashes := testBundle(t, 3)
+	sidecars, _, err := blobSidecars(bundle)
+	require.NoError(t, err)
+
+	elHash := libcommon.HexToHash("0x01")
+	cl := NewLocalCL(DefaultConfig, log.New())
+	cl.slot = 5
+	cl.sidecars[4] = []BlobSidecar{}
+	cl.sidecars[5] = sidecars
+	cl.slotsByEL[elHash] = 5
+
+	server := httptest.NewServer(cl.apiHandler())
+	defer server.Close()
+
+	ctx := context.Background()
+
+	for _, blockID := range []string{"head", "5", elHash.Hex()} {
+		fetched, err := FetchSidecars(ctx, server.URL, blockID)
+		require.NoError(t, err, blockID)
+		require.NoError(t, VerifySidecars(fetched, versionedHashes), blockID)
+	}
+
+	fetched, err := FetchSidecars(ctx, server.URL, "5?indices=2,0,7")
+	require.NoError(t, err)
+	require.Len(t, fetched, 2)
+	require.Equal(t, uint64(2), fetched[0].Index)
+	require.Equal(t, uint64(0), fetched[1].Index)
+
+	fetched, err = FetchSidecars(ctx, server.URL, "4")
+	require.NoError(t, err)
+	require.Empty(t, fetched)
+
+	_, err = FetchSidecars(ctx, server.URL, "6")
+	require.ErrorContains(t, err, "404")
+	_, err = FetchSidecars(ctx, server.URL, "0x02")
+	require.ErrorContains(t, err, "400")
+	_, err = FetchSidecars(ctx, server.URL, "latest")
+	require.ErrorContains(t, err, "400")
+}
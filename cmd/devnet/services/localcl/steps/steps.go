@@ -0,0 +1,179 @@
+package localcl_steps
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/accounts"
+	"github.com/ledgerwatch/erigon/cmd/devnet/blocks"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/cmd/devnet/scenarios"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services"
+	"github.com/ledgerwatch/erigon/cmd/devnet/services/localcl"
+	"github.com/ledgerwatch/erigon/cmd/devnet/transactions"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+func init() {
+	scenarios.MustRegisterStepHandlers(
+		scenarios.StepHandler(SendBlobTransactions),
+		scenarios.StepHandler(CheckBlobAvailability),
+	)
+}
+
+// SendBlobTransactions sends count blob transactions with blobsPerTx distinct blobs each from the account
+// and waits for their inclusion
+func SendBlobTransactions(ctx context.Context, from string, count int, blobsPerTx int) ([]libcommon.Hash, error) {
+	logger := devnet.Logger(ctx)
+	node := devnet.SelectNode(ctx)
+
+	sender := accounts.GetAccount(from)
+
+	if sender == nil {
+		return nil, fmt.Errorf("unknown from account: %s", from)
+	}
+
+	nonce, err := node.GetTransactionCount(sender.Address, rpc.PendingBlock)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction count for address 0x%x: %w", sender.Address, err)
+	}
+
+	baseFee, err := blocks.BaseFeeFromBlock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := uint256.MustFromBig(node.ChainID())
+	signer := types.LatestSignerForChainID(node.ChainID())
+	tip := uint256.NewInt(libcommon.GWei)
+	feeCap := new(uint256.Int).Add(uint256.NewInt(2*baseFee), tip)
+
+	hashes := make([]libcommon.Hash, 0, count)
+
+	for i := 0; i < count; i++ {
+		blobs := make(types.Blobs, blobsPerTx)
+
+		for j := range blobs {
+			blobs[j] = testBlob(i*blobsPerTx + j)
+		}
+
+		commitments, versionedHashes, proofs, err := blobs.ComputeCommitmentsAndProofs()
+
+		if err != nil {
+			return nil, err
+		}
+
+		txn := &types.BlobTx{
+			DynamicFeeTransaction: *types.NewEIP1559Transaction(*chainID, nonce.Uint64()+uint64(i), sender.Address, uint256.NewInt(0), params.TxGas, feeCap, tip, feeCap, nil),
+			MaxFeePerBlobGas:      feeCap,
+			BlobVersionedHashes:   versionedHashes,
+		}
+
+		signed, err := types.SignTx(txn, *signer, sender.SigKey())
+
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := node.SendTransaction(&types.BlobTxWrapper{
+			Tx:          *signed.(*types.BlobTx),
+			Commitments: commitments,
+			Blobs:       blobs,
+			Proofs:      proofs,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to send blob transaction %d: %w", i, err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	logger.Info("Sent blob transactions", "count", count, "blobsPerTx", blobsPerTx)
+
+	if _, err := transactions.AwaitTransactions(ctx, hashes...); err != nil {
+		return nil, fmt.Errorf("blob transactions were not included: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// testBlob returns a blob distinct for each seed, elements are kept below the field modulus
+func testBlob(seed int) types.Blob {
+	var blob types.Blob
+
+	for i := 0; i < len(blob); i += 32 {
+		blob[i+1] = byte(seed)
+		blob[i+2] = byte(seed >> 8)
+		blob[i+31] = byte(i / 32)
+	}
+
+	return blob
+}
+
+// CheckBlobAvailability checks that blobs of all blob transactions included in the chain of the current node
+// are served by the beacon api of the local consensus layer with valid kzg proofs
+func CheckBlobAvailability(ctx context.Context) error {
+	logger := devnet.Logger(ctx)
+	node := devnet.SelectNode(ctx)
+
+	cl := services.LocalCL(ctx)
+
+	if cl == nil {
+		return fmt.Errorf("local consensus layer is not configured for the current network")
+	}
+
+	head, err := node.BlockNumber()
+
+	if err != nil {
+		return err
+	}
+
+	var blobCount int
+
+	for n := uint64(1); n <= head; n++ {
+		block, err := node.GetBlockByNumber(ctx, rpc.BlockNumber(n), true)
+
+		if err != nil {
+			return fmt.Errorf("failed to get block %d: %w", n, err)
+		}
+
+		var versionedHashes []libcommon.Hash
+
+		for _, txn := range block.Transactions {
+			versionedHashes = append(versionedHashes, txn.BlobVersionedHashes...)
+		}
+
+		if len(versionedHashes) == 0 {
+			continue
+		}
+
+		sidecars, err := localcl.FetchSidecars(ctx, cl.APIURL(), strconv.FormatUint(n, 10))
+
+		if err != nil {
+			return err
+		}
+
+		if err := localcl.VerifySidecars(sidecars, versionedHashes); err != nil {
+			return fmt.Errorf("block %d: %w", n, err)
+		}
+
+		blobCount += len(sidecars)
+	}
+
+	if blobCount == 0 {
+		return fmt.Errorf("no blobs found in blocks 1..%d", head)
+	}
+
+	logger.Info("Blobs are available", "blobs", blobCount, "blocks", head)
+
+	return nil
+}
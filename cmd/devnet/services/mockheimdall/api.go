@@ -0,0 +1,232 @@
+package mockheimdall
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+// apiHandler serves the subset of the heimdall rest api used by bor and the polygon bridge
+func (h *MockHeimdall) apiHandler() http.Handler {
+	router := chi.NewRouter()
+	router.Use(h.faultsMiddleware)
+
+	router.Get("/clerk/event-record/list", func(w http.ResponseWriter, r *http.Request) {
+		fromId, err := strconv.ParseUint(r.URL.Query().Get("from-id"), 10, 64)
+		if err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		toTime, err := strconv.ParseInt(r.URL.Query().Get("to-time"), 10, 64)
+		if err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		limit := 50
+		if limitStr := r.URL.Query().Get("limit"); len(limitStr) > 0 {
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				http.Error(w, http.StatusText(400), 400)
+				return
+			}
+		}
+
+		writeResult(w, h.stateSyncEvents(fromId, time.Unix(toTime, 0), limit))
+	})
+
+	router.Get("/clerk/event-record/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		event := h.stateSyncEvent(id)
+		if event == nil {
+			// the client recognizes a missing event by the message of heimdall
+			http.Error(w, "could not get state record; No record found", 500)
+			return
+		}
+
+		writeResult(w, event)
+	})
+
+	router.Get("/bor/span/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		span, err := h.span(heimdall.SpanId(id))
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		writeResult(w, span)
+	})
+
+	router.Get("/bor/latest-span", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		id := h.latestSpanId()
+		h.Unlock()
+
+		span, err := h.span(id)
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+
+		writeResult(w, span)
+	})
+
+	router.Get("/checkpoints/count", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		count := len(h.checkpoints)
+		h.Unlock()
+
+		writeResult(w, heimdall.CheckpointCount{Result: int64(count)})
+	})
+
+	router.Get("/checkpoints/list", func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.ParseUint(r.URL.Query().Get("page"), 10, 64)
+		if err != nil || page == 0 {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		limit, err := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+		if err != nil || limit == 0 {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+
+		h.Lock()
+		defer h.Unlock()
+
+		checkpoints := heimdall.Checkpoints{}
+		for i := (page - 1) * limit; i < page*limit && i < uint64(len(h.checkpoints)); i++ {
+			checkpoints = append(checkpoints, h.checkpoints[i])
+		}
+
+		writeResult(w, checkpoints)
+	})
+
+	router.Get("/checkpoints/{number}", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		checkpoints := h.checkpoints
+		h.Unlock()
+
+		number, ok := waypointNumber(chi.URLParam(r, "number"), len(checkpoints))
+		if !ok {
+			http.Error(w, "checkpoint not found", 404)
+			return
+		}
+
+		writeResult(w, checkpoints[number-1])
+	})
+
+	router.Get("/milestone/count", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		count := len(h.milestones)
+		h.Unlock()
+
+		writeResult(w, heimdall.MilestoneCount{Count: int64(count)})
+	})
+
+	router.Get("/milestone/lastNoAck", func(w http.ResponseWriter, r *http.Request) {
+		// milestones are never rejected
+		writeResult(w, heimdall.MilestoneLastNoAck{})
+	})
+
+	router.Get("/milestone/noAck/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, heimdall.MilestoneNoAck{Result: false})
+	})
+
+	router.Get("/milestone/ID/{id}", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		count := len(h.milestones)
+		h.Unlock()
+
+		_, ok := waypointNumber(chi.URLParam(r, "id"), count)
+		writeResult(w, heimdall.MilestoneID{Result: ok})
+	})
+
+	router.Get("/milestone/{number}", func(w http.ResponseWriter, r *http.Request) {
+		h.Lock()
+		milestones := h.milestones
+		h.Unlock()
+
+		number, ok := waypointNumber(chi.URLParam(r, "number"), len(milestones))
+		if !ok {
+			// the client recognizes a missing milestone by the message of heimdall
+			http.Error(w, "Invalid milestone index", 500)
+			return
+		}
+
+		writeResult(w, milestones[number-1])
+	})
+
+	return router
+}
+
+// waypointNumber parses the number of a checkpoint or a milestone: "latest" or 1..count
+func waypointNumber(s string, count int) (int, bool) {
+	if s == "latest" {
+		return count, count > 0
+	}
+
+	number, err := strconv.Atoi(s)
+	if err != nil || number < 1 || number > count {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// faultsMiddleware delays the responses and fails the requests according to the configured faults
+func (h *MockHeimdall) faultsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if latency := h.cfg.Faults.Latency; latency > 0 {
+			h.Lock()
+			delay := time.Duration(h.rnd.Int63n(int64(latency) + 1))
+			h.Unlock()
+
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if h.chance(h.cfg.Faults.Errors) {
+			h.logger.Debug("[mock-heimdall] fault: request failed", "path", r.URL.Path)
+			http.Error(w, http.StatusText(500), 500)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeResult(w http.ResponseWriter, result any) {
+	response, err := json.Marshal(struct {
+		Height string `json:"height"`
+		Result any    `json:"result"`
+	}{"0", result})
+
+	if err != nil {
+		http.Error(w, http.StatusText(500), 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(response)
+}
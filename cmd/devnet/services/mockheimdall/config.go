@@ -0,0 +1,100 @@
+package mockheimdall
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// Faults - adversarial behaviour of the mock heimdall, rates are probabilities in [0, 1]
+type Faults struct {
+	Latency   time.Duration // max latency added to every response, the actual latency is random in [0, Latency]
+	Errors    float64       // share of requests failing with 500 (clients retry them)
+	BadRoots  float64       // share of checkpoints and milestones with a wrong root hash
+	EventGaps float64       // share of state sync event ids which are skipped
+}
+
+var faultNames = []string{"latency", "errors", "badroots", "eventgaps"}
+
+// ParseFaults parses comma separated list of fault=value pairs, for example: "latency=200ms,errors=0.1,badroots=0.05"
+func ParseFaults(s string) (Faults, error) {
+	var faults Faults
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+
+		if len(item) == 0 {
+			continue
+		}
+
+		name, value, found := strings.Cut(item, "=")
+
+		if !found {
+			return Faults{}, fmt.Errorf("missing value of fault %q", name)
+		}
+
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+
+		if name == "latency" {
+			latency, err := time.ParseDuration(value)
+
+			if err != nil {
+				return Faults{}, fmt.Errorf("invalid latency: %w", err)
+			}
+
+			faults.Latency = latency
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(value, 64)
+
+		if err != nil || rate < 0 || rate > 1 {
+			return Faults{}, fmt.Errorf("invalid rate of %q: %q, must be in [0, 1]", name, value)
+		}
+
+		switch name {
+		case "errors":
+			faults.Errors = rate
+		case "badroots":
+			faults.BadRoots = rate
+		case "eventgaps":
+			faults.EventGaps = rate
+		default:
+			return Faults{}, fmt.Errorf("unknown fault: %q, expected one of %s", name, strings.Join(faultNames, ", "))
+		}
+	}
+
+	return faults, nil
+}
+
+func (f Faults) String() string {
+	return fmt.Sprintf("latency=%s,errors=%g,badroots=%g,eventgaps=%g", f.Latency, f.Errors, f.BadRoots, f.EventGaps)
+}
+
+type Config struct {
+	ListenAddr         string            // host:port of the heimdall http api
+	CheckpointInterval time.Duration     // interval between checkpoints
+	CheckpointLength   uint64            // max amount of blocks in a checkpoint
+	MilestoneInterval  time.Duration     // interval between milestones, 0 - no milestones
+	MilestoneLength    uint64            // max amount of blocks in a milestone
+	Confirmations      uint64            // blocks behind the bor head which waypoints end at
+	StateSyncInterval  time.Duration     // interval between state sync events, 0 - no events
+	StateReceiver      libcommon.Address // contract receiving the state sync events on bor
+	Faults             Faults
+	Seed               int64 // seed of the faults and of the events data, runs with the same seed are reproducible
+}
+
+var DefaultConfig = Config{
+	ListenAddr:         "localhost:1317",
+	CheckpointInterval: 30 * time.Second,
+	CheckpointLength:   256,
+	MilestoneInterval:  5 * time.Second,
+	MilestoneLength:    16,
+	Confirmations:      4,
+	StateSyncInterval:  10 * time.Second,
+	StateReceiver:      libcommon.HexToAddress("0x0000000000000000000000000000000000000fee"),
+	Seed:               1,
+}
@@ -0,0 +1,480 @@
+package mockheimdall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cmd/devnet/devnet"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/polygon/bor"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// Chain - source of the bor blocks which checkpoints and milestones are built from
+type Chain interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number uint64) (*types.Header, error)
+}
+
+// nodeChain reads the bor blocks from a devnet node
+type nodeChain struct {
+	node devnet.Node
+}
+
+func (c nodeChain) BlockNumber(_ context.Context) (uint64, error) {
+	return c.node.BlockNumber()
+}
+
+func (c nodeChain) HeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	block, err := c.node.GetBlockByNumber(ctx, rpc.BlockNumber(number), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if block == nil || block.Header == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+
+	return block.Header, nil
+}
+
+// MockHeimdall - heimdall of the bor dev network which doesn't need a root chain: spans are built from the block
+// producers of the network, checkpoints and milestones from the blocks of the first started bor node, and state
+// sync events are generated. Everything is served over the heimdall http api with configurable timing and faults,
+// so bor nodes (and the polygon bridge) can be run fully offline and fed adversarial inputs.
+type MockHeimdall struct {
+	sync.Mutex
+	cfg         Config
+	chainConfig *chain.Config
+	logger      log.Logger
+	rnd         *rand.Rand
+	chain       Chain
+	server      *http.Server
+	cancel      context.CancelFunc
+	done        chan struct{}
+
+	head        uint64
+	validators  []*valset.Validator
+	spans       []*heimdall.Span
+	checkpoints []*heimdall.Checkpoint
+	milestones  []*heimdall.Milestone
+	events      []*heimdall.EventRecordWithTime
+	nextEventId uint64
+}
+
+func NewMockHeimdall(cfg Config, chainConfig *chain.Config, logger log.Logger) *MockHeimdall {
+	return &MockHeimdall{
+		cfg:         cfg,
+		chainConfig: chainConfig,
+		logger:      logger,
+		rnd:         rand.New(rand.NewSource(cfg.Seed)),
+		nextEventId: 1,
+	}
+}
+
+// URL returns the url of the heimdall api to configure bor nodes with
+func (h *MockHeimdall) URL() string {
+	return "http://" + h.cfg.ListenAddr
+}
+
+func (h *MockHeimdall) Start(ctx context.Context) error {
+	h.Lock()
+	defer h.Unlock()
+
+	// the service is shared by the networks of the devnet
+	if h.cancel != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", h.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("mock heimdall api: %w", err)
+	}
+
+	h.server = &http.Server{
+		Handler:           h.apiHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			h.logger.Error("[mock-heimdall] api stopped", "err", err)
+		}
+	}()
+
+	ctx, h.cancel = context.WithCancel(ctx)
+	h.done = make(chan struct{})
+	go h.run(ctx)
+
+	h.logger.Info("[mock-heimdall] started", "addr", h.cfg.ListenAddr, "faults", h.cfg.Faults)
+
+	return nil
+}
+
+func (h *MockHeimdall) Stop() {
+	h.Lock()
+	cancel, done, server := h.cancel, h.done, h.server
+	h.cancel, h.server = nil, nil
+	h.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	if server != nil {
+		_ = server.Close()
+	}
+}
+
+func (h *MockHeimdall) NodeCreated(_ context.Context, node devnet.Node) {
+	h.Lock()
+	defer h.Unlock()
+
+	if strings.HasPrefix(node.GetName(), "bor") && node.IsBlockProducer() && node.Account() != nil {
+		h.validators = append(h.validators, &valset.Validator{
+			ID:          uint64(len(h.validators) + 1),
+			Address:     node.Account().Address,
+			VotingPower: 1000,
+		})
+	}
+}
+
+func (h *MockHeimdall) NodeStarted(_ context.Context, node devnet.Node) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.chain == nil && strings.HasPrefix(node.GetName(), "bor") {
+		h.chain = nodeChain{node}
+	}
+}
+
+func (h *MockHeimdall) run(ctx context.Context) {
+	defer close(h.done)
+
+	tick := func(interval time.Duration) <-chan time.Time {
+		if interval <= 0 {
+			return nil
+		}
+
+		ticker := time.NewTicker(interval)
+		go func() {
+			<-ctx.Done()
+			ticker.Stop()
+		}()
+
+		return ticker.C
+	}
+
+	checkpoints, milestones, events := tick(h.cfg.CheckpointInterval), tick(h.cfg.MilestoneInterval), tick(h.cfg.StateSyncInterval)
+
+	for {
+		var err error
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-checkpoints:
+			err = h.produceCheckpoint(ctx)
+		case <-milestones:
+			err = h.produceMilestone(ctx)
+		case now := <-events:
+			h.produceEvent(now)
+		}
+
+		if err != nil && ctx.Err() == nil {
+			// nodes are still starting
+			h.logger.Debug("[mock-heimdall] waypoint not produced", "err", err)
+		}
+	}
+}
+
+// chance returns true with the probability p
+func (h *MockHeimdall) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	return h.rnd.Float64() < p
+}
+
+func (h *MockHeimdall) randomHash() (hash libcommon.Hash) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.rnd.Read(hash[:])
+
+	return hash
+}
+
+// waypointRange returns the range of the next waypoint after prevEnd, false if there are not enough confirmed blocks yet
+func (h *MockHeimdall) waypointRange(ctx context.Context, prevEnd *big.Int, maxLength uint64) (start, end uint64, ok bool, err error) {
+	h.Lock()
+	source := h.chain
+	h.Unlock()
+
+	if source == nil {
+		return 0, 0, false, errors.New("no bor nodes")
+	}
+
+	head, err := source.BlockNumber(ctx)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	h.Lock()
+	h.head = max(h.head, head)
+	h.Unlock()
+
+	if prevEnd != nil {
+		start = prevEnd.Uint64() + 1
+	}
+
+	if head < start+h.cfg.Confirmations {
+		return 0, 0, false, nil
+	}
+
+	return start, min(head-h.cfg.Confirmations, start+maxLength-1), true, nil
+}
+
+func (h *MockHeimdall) proposer() libcommon.Address {
+	if len(h.validators) == 0 {
+		return libcommon.Address{}
+	}
+
+	return h.validators[0].Address
+}
+
+func (h *MockHeimdall) produceCheckpoint(ctx context.Context) error {
+	var prevEnd *big.Int
+	if last := h.lastCheckpoint(); last != nil {
+		prevEnd = last.EndBlock()
+	}
+
+	start, end, ok, err := h.waypointRange(ctx, prevEnd, h.cfg.CheckpointLength)
+	if err != nil || !ok {
+		return err
+	}
+
+	headers := make([]*types.Header, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		header, err := h.chain.HeaderByNumber(ctx, n)
+		if err != nil {
+			return err
+		}
+
+		headers = append(headers, header)
+	}
+
+	rootHash, err := bor.ComputeHeadersRootHash(headers)
+	if err != nil {
+		return err
+	}
+
+	root := libcommon.BytesToHash(rootHash)
+	if h.chance(h.cfg.Faults.BadRoots) {
+		root = h.randomHash()
+		h.logger.Info("[mock-heimdall] fault: checkpoint with a bad root hash", "start", start, "end", end)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.checkpoints = append(h.checkpoints, &heimdall.Checkpoint{
+		Id:     heimdall.CheckpointId(len(h.checkpoints) + 1),
+		Fields: h.waypointFields(start, end, root),
+	})
+
+	h.logger.Debug("[mock-heimdall] checkpoint", "id", len(h.checkpoints), "start", start, "end", end)
+
+	return nil
+}
+
+func (h *MockHeimdall) produceMilestone(ctx context.Context) error {
+	var prevEnd *big.Int
+	if last := h.lastMilestone(); last != nil {
+		prevEnd = last.EndBlock()
+	}
+
+	start, end, ok, err := h.waypointRange(ctx, prevEnd, h.cfg.MilestoneLength)
+	if err != nil || !ok {
+		return err
+	}
+
+	header, err := h.chain.HeaderByNumber(ctx, end)
+	if err != nil {
+		return err
+	}
+
+	hash := header.Hash()
+	if h.chance(h.cfg.Faults.BadRoots) {
+		hash = h.randomHash()
+		h.logger.Info("[mock-heimdall] fault: milestone with a bad hash", "start", start, "end", end)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.milestones = append(h.milestones, &heimdall.Milestone{
+		Id:     heimdall.MilestoneId(len(h.milestones) + 1),
+		Fields: h.waypointFields(start, end, hash),
+	})
+
+	h.logger.Debug("[mock-heimdall] milestone", "id", len(h.milestones), "start", start, "end", end)
+
+	return nil
+}
+
+func (h *MockHeimdall) waypointFields(start, end uint64, root libcommon.Hash) heimdall.WaypointFields {
+	return heimdall.WaypointFields{
+		Proposer:   h.proposer(),
+		StartBlock: new(big.Int).SetUint64(start),
+		EndBlock:   new(big.Int).SetUint64(end),
+		RootHash:   root,
+		ChainID:    h.chainConfig.ChainID.String(),
+		Timestamp:  uint64(time.Now().Unix()),
+	}
+}
+
+func (h *MockHeimdall) lastCheckpoint() *heimdall.Checkpoint {
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.checkpoints) == 0 {
+		return nil
+	}
+
+	return h.checkpoints[len(h.checkpoints)-1]
+}
+
+func (h *MockHeimdall) lastMilestone() *heimdall.Milestone {
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.milestones) == 0 {
+		return nil
+	}
+
+	return h.milestones[len(h.milestones)-1]
+}
+
+func (h *MockHeimdall) produceEvent(now time.Time) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.cfg.Faults.EventGaps > 0 && h.rnd.Float64() < h.cfg.Faults.EventGaps {
+		h.logger.Info("[mock-heimdall] fault: state sync event id skipped", "id", h.nextEventId)
+		h.nextEventId++
+	}
+
+	event := &heimdall.EventRecordWithTime{
+		EventRecord: heimdall.EventRecord{
+			ID:       h.nextEventId,
+			Contract: h.cfg.StateReceiver,
+			Data:     make([]byte, 32),
+			ChainID:  h.chainConfig.ChainID.String(),
+		},
+		Time: now.UTC(),
+	}
+
+	h.rnd.Read(event.Data)
+	h.rnd.Read(event.TxHash[:])
+	h.events = append(h.events, event)
+	h.nextEventId++
+}
+
+// span returns the span, the spans before it are created if needed. Only the span of the current head and the next
+// one are available, as on the real heimdall.
+func (h *MockHeimdall) span(id heimdall.SpanId) (*heimdall.Span, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if id > h.latestSpanId() {
+		return nil, fmt.Errorf("span %d not found", id)
+	}
+
+	if len(h.validators) == 0 {
+		return nil, errors.New("no validators")
+	}
+
+	for heimdall.SpanId(len(h.spans)) <= id {
+		spanId := heimdall.SpanId(len(h.spans))
+		span := &heimdall.Span{
+			Id:                spanId,
+			EndBlock:          heimdall.SpanEndBlockNum(spanId),
+			ValidatorSet:      *valset.NewValidatorSet(h.validatorsCopy()),
+			SelectedProducers: make([]valset.Validator, 0, len(h.validators)),
+			ChainID:           h.chainConfig.ChainID.String(),
+		}
+
+		if spanId > 0 {
+			span.StartBlock = heimdall.SpanEndBlockNum(spanId-1) + 1
+		}
+
+		for _, v := range h.validators {
+			span.SelectedProducers = append(span.SelectedProducers, *v.Copy())
+		}
+
+		h.spans = append(h.spans, span)
+	}
+
+	return h.spans[id], nil
+}
+
+func (h *MockHeimdall) latestSpanId() heimdall.SpanId {
+	return heimdall.SpanIdAt(h.head) + 1
+}
+
+func (h *MockHeimdall) validatorsCopy() []*valset.Validator {
+	validators := make([]*valset.Validator, len(h.validators))
+	for i, v := range h.validators {
+		validators[i] = v.Copy()
+	}
+
+	return validators
+}
+
+// stateSyncEvents returns the events with ids in [fromId, fromId+limit) recorded before toTime
+func (h *MockHeimdall) stateSyncEvents(fromId uint64, toTime time.Time, limit int) []*heimdall.EventRecordWithTime {
+	h.Lock()
+	defer h.Unlock()
+
+	events := make([]*heimdall.EventRecordWithTime, 0, limit)
+	for _, event := range h.events {
+		if event.ID >= fromId && event.ID < fromId+uint64(limit) && event.Time.Before(toTime) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (h *MockHeimdall) stateSyncEvent(id uint64) *heimdall.EventRecordWithTime {
+	h.Lock()
+	defer h.Unlock()
+
+	for _, event := range h.events {
+		if event.ID == id {
+			return event
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package mockheimdall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/polygon/bor"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/log/v3"
+)
+
+type testChain struct {
+	headers []*types.Header
+}
+
+func newTestChain(length int) *testChain {
+	c := &testChain{}
+	for i := 0; i < length; i++ {
+		c.headers = append(c.headers, &types.Header{Number: big.NewInt(int64(i)), Time: uint64(i)})
+	}
+	return c
+}
+
+func (c *testChain) BlockNumber(_ context.Context) (uint64, error) {
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *testChain) HeaderByNumber(_ context.Context, number uint64) (*types.Header, error) {
+	if number >= uint64(len(c.headers)) {
+		return nil, errors.New("not found")
+	}
+	return c.headers[number], nil
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults("latency=200ms, errors=0.1,badroots=0.05,eventgaps=1")
+	require.NoError(t, err)
+	require.Equal(t, Faults{Latency: 200 * time.Millisecond, Errors: 0.1, BadRoots: 0.05, EventGaps: 1}, faults)
+
+	parsed, err := ParseFaults(faults.String())
+	require.NoError(t, err)
+	require.Equal(t, faults, parsed)
+
+	faults, err = ParseFaults("")
+	require.NoError(t, err)
+	require.Equal(t, Faults{}, faults)
+
+	_, err = ParseFaults("errors=2")
+	require.Error(t, err)
+	_, err = ParseFaults("latency=x")
+	require.Error(t, err)
+	_, err = ParseFaults("unknown=0.1")
+	require.Error(t, err)
+	_, err = ParseFaults("errors")
+	require.Error(t, err)
+}
+
+func newTestHeimdall(t *testing.T, cfg Config, chainLength int) (*MockHeimdall, heimdall.HeimdallClient) {
+	logger := log.New()
+	h := NewMockHeimdall(cfg, &chain.Config{ChainID: big.NewInt(1337)}, logger)
+	h.chain = newTestChain(chainLength)
+	h.validators = []*valset.Validator{
+		{ID: 1, Address: libcommon.HexToAddress("0x01"), VotingPower: 1000},
+		{ID: 2, Address: libcommon.HexToAddress("0x02"), VotingPower: 1000},
+	}
+
+	server := httptest.NewServer(h.apiHandler())
+	t.Cleanup(server.Close)
+
+	client := heimdall.NewHeimdallClient(server.URL, logger)
+	t.Cleanup(client.Close)
+
+	return h, client
+}
+
+func TestWaypoints(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.CheckpointLength = 8
+	cfg.MilestoneLength = 4
+	h, client := newTestHeimdall(t, cfg, 30)
+	blocks := h.chain.(*testChain)
+	ctx := context.Background()
+
+	count, err := client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, err = client.FetchMilestone(ctx, 1)
+	require.ErrorIs(t, err, heimdall.ErrNotInMilestoneList)
+
+	// 30 blocks with 4 confirmations: the waypoints end at the block 25
+	for i := 0; i < 4; i++ {
+		require.NoError(t, h.produceCheckpoint(ctx))
+		require.NoError(t, h.produceMilestone(ctx))
+	}
+
+	count, err = client.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), count)
+
+	latest, err := client.FetchCheckpoint(ctx, -1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(24), latest.StartBlock().Uint64())
+	require.Equal(t, uint64(25), latest.EndBlock().Uint64())
+
+	checkpoint, err := client.FetchCheckpoint(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), checkpoint.StartBlock().Uint64())
+	require.Equal(t, uint64(15), checkpoint.EndBlock().Uint64())
+	rootHash, err := bor.ComputeHeadersRootHash(blocks.headers[8:16])
+	require.NoError(t, err)
+	require.Equal(t, libcommon.BytesToHash(rootHash), checkpoint.RootHash())
+
+	checkpoints, err := client.FetchCheckpoints(ctx, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	require.Equal(t, latest.EndBlock(), checkpoints[0].EndBlock())
+
+	count, err = client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), count)
+
+	milestone, err := client.FetchMilestone(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), milestone.StartBlock().Uint64())
+	require.Equal(t, uint64(11), milestone.EndBlock().Uint64())
+	require.Equal(t, blocks.headers[11].Hash(), milestone.RootHash())
+
+	require.NoError(t, client.FetchMilestoneID(ctx, "3"))
+	require.ErrorIs(t, client.FetchMilestoneID(ctx, "5"), heimdall.ErrNotInMilestoneList)
+	require.ErrorIs(t, client.FetchNoAckMilestone(ctx, "3"), heimdall.ErrNotInRejectedList)
+}
+
+func TestBadRoots(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.CheckpointLength = 8
+	cfg.Faults.BadRoots = 1
+	h, client := newTestHeimdall(t, cfg, 30)
+	ctx := context.Background()
+
+	require.NoError(t, h.produceCheckpoint(ctx))
+
+	checkpoint, err := client.FetchCheckpoint(ctx, 1)
+	require.NoError(t, err)
+	rootHash, err := bor.ComputeHeadersRootHash(h.chain.(*testChain).headers[0:8])
+	require.NoError(t, err)
+	require.NotEqual(t, libcommon.BytesToHash(rootHash), checkpoint.RootHash())
+}
+
+func TestSpans(t *testing.T) {
+	h, client := newTestHeimdall(t, DefaultConfig, 300)
+	ctx := context.Background()
+
+	require.NoError(t, h.produceCheckpoint(ctx))
+
+	// the head 299 is in the span 1, the next one is already available
+	latest, err := client.FetchLatestSpan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, heimdall.SpanId(2), latest.Id)
+	require.Equal(t, heimdall.SpanEndBlockNum(1)+1, latest.StartBlock)
+	require.Equal(t, heimdall.SpanEndBlockNum(2), latest.EndBlock)
+	require.Len(t, latest.SelectedProducers, 2)
+
+	span, err := client.FetchSpan(ctx, 0)
+	require.NoError(t, err)
+	require.Zero(t, span.StartBlock)
+	require.Equal(t, "1337", span.ChainID)
+}
+
+func TestStateSyncEvents(t *testing.T) {
+	h, client := newTestHeimdall(t, DefaultConfig, 1)
+	ctx := context.Background()
+
+	start := time.Unix(1_000_000, 0)
+	for i := 0; i < 120; i++ {
+		h.produceEvent(start.Add(time.Duration(i) * time.Second))
+	}
+
+	events, err := client.FetchStateSyncEvents(ctx, 1, start.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 120)
+	require.Equal(t, uint64(120), events[119].ID)
+
+	events, err = client.FetchStateSyncEvents(ctx, 1, start.Add(10*time.Second), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 10)
+
+	event, err := client.FetchStateSyncEvent(ctx, 4)
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfig.StateReceiver, event.Contract)
+	require.Equal(t, events[3].Data, event.Data)
+
+	_, err = client.FetchStateSyncEvent(ctx, 121)
+	require.ErrorIs(t, err, heimdall.ErrEventRecordNotFound)
+}
+
+func TestEventGaps(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.Faults.EventGaps = 0.5
+	h, client := newTestHeimdall(t, cfg, 1)
+	ctx := context.Background()
+
+	start := time.Unix(1_000_000, 0)
+	for i := 0; i < 20; i++ {
+		h.produceEvent(start.Add(time.Duration(i) * time.Second))
+	}
+
+	events, err := client.FetchStateSyncEvents(ctx, 1, start.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 20)
+	require.Greater(t, events[19].ID, uint64(20))
+}
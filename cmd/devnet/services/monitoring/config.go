@@ -0,0 +1,57 @@
+package monitoring
+
+import (
+	"fmt"
+)
+
+// Mode - how prometheus and grafana are launched
+type Mode string
+
+const (
+	Auto   Mode = "auto"   // binaries if both are found in PATH, docker containers otherwise
+	Docker Mode = "docker" // prom/prometheus and grafana/grafana containers on the host network
+	Binary Mode = "binary" // prometheus and grafana binaries found in PATH
+	None   Mode = "none"   // only write the configs, prometheus and grafana are run by the user
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case Auto, Docker, Binary, None:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown monitoring mode: %q, expected one of: auto, docker, binary, none", s)
+	}
+}
+
+type Config struct {
+	// Dir - where the configs and the data of prometheus and grafana are written
+	Dir  string
+	Mode Mode
+	// DashboardsDir - grafana dashboards to provision, no dashboards are provisioned if it doesn't exist
+	DashboardsDir  string
+	PrometheusPort int
+	GrafanaPort    int
+	ScrapeInterval string
+	// images used in Docker mode, same as in docker-compose.yml
+	PrometheusImage string
+	GrafanaImage    string
+	// GrafanaHome - homepath of the grafana binary, used in Binary mode if it exists
+	GrafanaHome string
+}
+
+var DefaultConfig = Config{
+	Mode:            Auto,
+	DashboardsDir:   "./cmd/prometheus/dashboards",
+	PrometheusPort:  9090,
+	GrafanaPort:     3000,
+	ScrapeInterval:  "5s",
+	PrometheusImage: "prom/prometheus:v2.51.2",
+	GrafanaImage:    "grafana/grafana:10.4.2",
+	GrafanaHome:     "/usr/share/grafana",
+}
+
+// Target - metrics endpoint of a devnet node
+type Target struct {
+	Node string // node name, exported as the `instance` label
+	Addr string // host:port of the node's metrics server
+}
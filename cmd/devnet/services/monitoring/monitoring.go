@@ -0,0 +1,275 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+const (
+	prometheusContainer = "devnet-prometheus"
+	grafanaContainer    = "devnet-grafana"
+
+	// paths in the containers
+	containerPrometheusConfig = "/etc/prometheus/prometheus.yml"
+	containerPrometheusData   = "/prometheus"
+	containerProvisioning     = "/etc/grafana/provisioning"
+	containerDashboards       = "/etc/grafana/dashboards"
+	containerGrafanaData      = "/var/lib/grafana"
+)
+
+// Stack - prometheus scraping the devnet nodes and grafana with provisioned erigon dashboards
+type Stack struct {
+	cfg     Config
+	targets []Target
+	logger  log.Logger
+
+	mu    sync.Mutex
+	mode  Mode
+	procs []*process
+}
+
+type process struct {
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+func NewStack(cfg Config, targets []Target, logger log.Logger) *Stack {
+	return &Stack{cfg: cfg, targets: targets, logger: logger}
+}
+
+// Start writes the configs and launches prometheus and grafana, they are stopped by Stop
+func (s *Stack) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mode, err := s.resolveMode()
+	if err != nil {
+		return err
+	}
+	s.mode = mode
+
+	// grafana resolves relative paths against its homepath, docker needs absolute paths of volumes
+	if s.cfg.Dir, err = filepath.Abs(s.cfg.Dir); err != nil {
+		return err
+	}
+
+	grafanaDashboardsPath := s.cfg.grafanaDashboardsDir()
+	if mode == Docker {
+		grafanaDashboardsPath = containerDashboards
+	}
+
+	dashboards, err := Provision(s.cfg, s.targets, grafanaDashboardsPath)
+	if err != nil {
+		return fmt.Errorf("monitoring: provisioning failed: %w", err)
+	}
+
+	if dashboards == 0 {
+		s.logger.Warn("[monitoring] no dashboards found", "dir", s.cfg.DashboardsDir)
+	}
+
+	s.logger.Info("[monitoring] prometheus config written", "file", s.cfg.prometheusConfigFile(), "targets", targetNames(s.targets))
+
+	switch mode {
+	case Docker:
+		err = s.startContainers(ctx)
+	case Binary:
+		err = s.startBinaries(ctx)
+	case None:
+		return nil
+	}
+
+	if err != nil {
+		s.stop()
+		return fmt.Errorf("monitoring: %s start failed: %w", mode, err)
+	}
+
+	s.logger.Info("[monitoring] started", "mode", mode,
+		"prometheus", fmt.Sprintf("http://localhost:%d", s.cfg.PrometheusPort),
+		"grafana", fmt.Sprintf("http://localhost:%d", s.cfg.GrafanaPort), "dashboards", dashboards)
+
+	return nil
+}
+
+func (s *Stack) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop()
+}
+
+func (s *Stack) stop() {
+	switch s.mode {
+	case Docker:
+		// containers are run with --rm
+		for _, container := range []string{prometheusContainer, grafanaContainer} {
+			if out, err := exec.Command("docker", "stop", container).CombinedOutput(); err != nil {
+				s.logger.Debug("[monitoring] docker stop failed", "container", container, "err", err, "out", string(out))
+			}
+		}
+	case Binary:
+		for _, proc := range s.procs {
+			_ = proc.cmd.Process.Signal(os.Interrupt)
+		}
+
+		for _, proc := range s.procs {
+			select {
+			case <-proc.exited:
+			case <-time.After(10 * time.Second):
+				_ = proc.cmd.Process.Kill()
+				<-proc.exited
+			}
+		}
+
+		s.procs = nil
+	}
+}
+
+func (s *Stack) resolveMode() (Mode, error) {
+	switch s.cfg.Mode {
+	case Auto:
+		if _, _, err := s.binaries(); err == nil {
+			return Binary, nil
+		}
+
+		if _, err := exec.LookPath("docker"); err == nil {
+			return Docker, nil
+		}
+
+		return "", fmt.Errorf("monitoring: neither prometheus and grafana binaries nor docker found in PATH")
+	case Binary:
+		if _, _, err := s.binaries(); err != nil {
+			return "", err
+		}
+	case Docker:
+		if _, err := exec.LookPath("docker"); err != nil {
+			return "", fmt.Errorf("monitoring: %w", err)
+		}
+	}
+
+	return s.cfg.Mode, nil
+}
+
+// binaries - paths of prometheus and grafana, older grafana releases ship `grafana-server`
+func (s *Stack) binaries() (prometheus string, grafana []string, err error) {
+	if prometheus, err = exec.LookPath("prometheus"); err != nil {
+		return "", nil, fmt.Errorf("monitoring: %w", err)
+	}
+
+	if path, err := exec.LookPath("grafana"); err == nil {
+		return prometheus, []string{path, "server"}, nil
+	}
+
+	path, err := exec.LookPath("grafana-server")
+
+	if err != nil {
+		return "", nil, fmt.Errorf("monitoring: %w", err)
+	}
+
+	return prometheus, []string{path}, nil
+}
+
+func (s *Stack) startContainers(ctx context.Context) error {
+	// the host network makes the nodes' metrics servers, which listen on localhost, reachable from the containers
+	user := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+
+	prometheus := []string{"run", "--rm", "-d", "--name", prometheusContainer, "--network", "host", "--user", user,
+		"-v", s.cfg.prometheusConfigFile() + ":" + containerPrometheusConfig + ":ro",
+		"-v", s.cfg.prometheusDataDir() + ":" + containerPrometheusData,
+		s.cfg.PrometheusImage,
+		"--config.file=" + containerPrometheusConfig,
+		"--storage.tsdb.path=" + containerPrometheusData,
+		fmt.Sprintf("--web.listen-address=:%d", s.cfg.PrometheusPort),
+	}
+
+	grafana := []string{"run", "--rm", "-d", "--name", grafanaContainer, "--network", "host", "--user", user,
+		"-v", s.cfg.grafanaProvisioningDir() + ":" + containerProvisioning + ":ro",
+		"-v", s.cfg.grafanaDashboardsDir() + ":" + containerDashboards + ":ro",
+		"-v", s.cfg.grafanaDataDir() + ":" + containerGrafanaData,
+	}
+
+	for _, env := range s.grafanaEnv() {
+		grafana = append(grafana, "-e", env)
+	}
+
+	grafana = append(grafana, s.cfg.GrafanaImage)
+
+	for container, args := range map[string][]string{prometheusContainer: prometheus, grafanaContainer: grafana} {
+		// leftovers of a previous run, which wasn't stopped
+		_ = exec.CommandContext(ctx, "docker", "rm", "-f", container).Run()
+
+		if out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("docker run %s: %w: %s", container, err, out)
+		}
+	}
+
+	return nil
+}
+
+func (s *Stack) startBinaries(ctx context.Context) error {
+	prometheusPath, grafanaCmd, err := s.binaries()
+	if err != nil {
+		return err
+	}
+
+	prometheus := exec.Command(prometheusPath,
+		"--config.file="+s.cfg.prometheusConfigFile(),
+		"--storage.tsdb.path="+s.cfg.prometheusDataDir(),
+		fmt.Sprintf("--web.listen-address=:%d", s.cfg.PrometheusPort))
+
+	grafanaArgs := grafanaCmd[1:]
+	if _, err := os.Stat(s.cfg.GrafanaHome); err == nil {
+		grafanaArgs = append(grafanaArgs, "--homepath="+s.cfg.GrafanaHome)
+	}
+
+	grafana := exec.Command(grafanaCmd[0], grafanaArgs...)
+	grafana.Env = append(os.Environ(), s.grafanaEnv()...)
+	grafana.Env = append(grafana.Env,
+		"GF_PATHS_PROVISIONING="+s.cfg.grafanaProvisioningDir(),
+		"GF_PATHS_DATA="+s.cfg.grafanaDataDir(),
+		"GF_PATHS_LOGS="+filepath.Join(s.cfg.grafanaDataDir(), "log"))
+
+	for name, cmd := range map[string]*exec.Cmd{"prometheus": prometheus, "grafana": grafana} {
+		logFile, err := os.Create(filepath.Join(s.cfg.Dir, name+".log"))
+		if err != nil {
+			return err
+		}
+
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+
+		if err := cmd.Start(); err != nil {
+			logFile.Close()
+			return err
+		}
+
+		proc := &process{cmd: cmd, exited: make(chan struct{})}
+		s.procs = append(s.procs, proc)
+
+		go func(name string, proc *process, logFile *os.File) {
+			defer close(proc.exited)
+			defer logFile.Close()
+
+			if err := proc.cmd.Wait(); err != nil && ctx.Err() == nil {
+				s.logger.Warn("[monitoring] exited", "process", name, "err", err, "log", logFile.Name())
+			}
+		}(name, proc, logFile)
+	}
+
+	return nil
+}
+
+// grafanaEnv - anonymous admin access, so the dashboards are available without login
+func (s *Stack) grafanaEnv() []string {
+	return []string{
+		fmt.Sprintf("GF_SERVER_HTTP_PORT=%d", s.cfg.GrafanaPort),
+		"GF_AUTH_ANONYMOUS_ENABLED=true",
+		"GF_AUTH_ANONYMOUS_ORG_ROLE=Admin",
+		"GF_LOG_LEVEL=warn",
+	}
+}
@@ -0,0 +1,185 @@
+package monitoring
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	metricsPath = "/debug/metrics/prometheus"
+	// datasourceUID - uid of the provisioned prometheus datasource, dashboards exported for sharing
+	// externally refer to their datasource as ${DS_PROMETHEUS} which grafana doesn't resolve on provisioning
+	datasourceUID      = "devnet-prometheus"
+	datasourceVariable = "${DS_PROMETHEUS}"
+)
+
+// layout of Config.Dir
+
+func (cfg Config) prometheusConfigFile() string {
+	return filepath.Join(cfg.Dir, "prometheus", "prometheus.yml")
+}
+
+func (cfg Config) prometheusDataDir() string {
+	return filepath.Join(cfg.Dir, "prometheus", "data")
+}
+
+func (cfg Config) grafanaProvisioningDir() string {
+	return filepath.Join(cfg.Dir, "grafana", "provisioning")
+}
+
+func (cfg Config) grafanaDashboardsDir() string {
+	return filepath.Join(cfg.Dir, "grafana", "dashboards")
+}
+
+func (cfg Config) grafanaDataDir() string {
+	return filepath.Join(cfg.Dir, "grafana", "data")
+}
+
+type scrapeConfig struct {
+	JobName       string         `yaml:"job_name"`
+	MetricsPath   string         `yaml:"metrics_path"`
+	Scheme        string         `yaml:"scheme"`
+	StaticConfigs []staticConfig `yaml:"static_configs"`
+}
+
+type staticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// PrometheusConfig - scrape config with a static target per node, labelled with the node name
+func PrometheusConfig(scrapeInterval string, targets []Target) ([]byte, error) {
+	job := scrapeConfig{
+		JobName:     "devnet",
+		MetricsPath: metricsPath,
+		Scheme:      "http",
+	}
+
+	for _, target := range targets {
+		job.StaticConfigs = append(job.StaticConfigs, staticConfig{
+			Targets: []string{target.Addr},
+			Labels:  map[string]string{"instance": target.Node},
+		})
+	}
+
+	return yaml.Marshal(map[string]interface{}{
+		"global": map[string]string{
+			"scrape_interval":     scrapeInterval,
+			"evaluation_interval": scrapeInterval,
+		},
+		"scrape_configs": []scrapeConfig{job},
+	})
+}
+
+func datasourceConfig(prometheusPort int) ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"datasources": []map[string]interface{}{{
+			"name":      "Prometheus",
+			"uid":       datasourceUID,
+			"type":      "prometheus",
+			"access":    "proxy",
+			"orgId":     1,
+			"url":       fmt.Sprintf("http://localhost:%d", prometheusPort),
+			"isDefault": true,
+			"editable":  true,
+		}},
+	})
+}
+
+func dashboardProviderConfig(dashboardsPath string) ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"providers": []map[string]interface{}{{
+			"name":                  "erigon",
+			"orgId":                 1,
+			"type":                  "file",
+			"allowUiUpdates":        true,
+			"updateIntervalSeconds": 10,
+			"options":               map[string]string{"path": dashboardsPath},
+		}},
+	})
+}
+
+// Provision writes the prometheus scrape config and grafana provisioning into cfg.Dir.
+// grafanaDashboardsPath is the dashboards dir as seen by grafana, it differs from the host path in Docker mode.
+// Returns the number of provisioned dashboards.
+func Provision(cfg Config, targets []Target, grafanaDashboardsPath string) (int, error) {
+	for _, dir := range []string{
+		filepath.Dir(cfg.prometheusConfigFile()),
+		cfg.prometheusDataDir(),
+		filepath.Join(cfg.grafanaProvisioningDir(), "datasources"),
+		filepath.Join(cfg.grafanaProvisioningDir(), "dashboards"),
+		cfg.grafanaDashboardsDir(),
+		cfg.grafanaDataDir(),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, err
+		}
+	}
+
+	prometheusConfig, err := PrometheusConfig(cfg.ScrapeInterval, targets)
+	if err != nil {
+		return 0, err
+	}
+
+	datasources, err := datasourceConfig(cfg.PrometheusPort)
+	if err != nil {
+		return 0, err
+	}
+
+	providers, err := dashboardProviderConfig(grafanaDashboardsPath)
+	if err != nil {
+		return 0, err
+	}
+
+	for file, content := range map[string][]byte{
+		cfg.prometheusConfigFile(): prometheusConfig,
+		filepath.Join(cfg.grafanaProvisioningDir(), "datasources", "prometheus.yml"): datasources,
+		filepath.Join(cfg.grafanaProvisioningDir(), "dashboards", "dashboard.yml"):   providers,
+	} {
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return 0, err
+		}
+	}
+
+	return copyDashboards(cfg.DashboardsDir, cfg.grafanaDashboardsDir())
+}
+
+// copyDashboards copies *.json dashboards pointing them to the provisioned datasource
+func copyDashboards(from, to string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(from, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		dashboard, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+
+		dashboard = bytes.ReplaceAll(dashboard, []byte(datasourceVariable), []byte(datasourceUID))
+
+		if err := os.WriteFile(filepath.Join(to, filepath.Base(file)), dashboard, 0644); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(files), nil
+}
+
+func targetNames(targets []Target) string {
+	names := make([]string, 0, len(targets))
+
+	for _, target := range targets {
+		names = append(names, target.Node+"="+target.Addr)
+	}
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,70 @@
+package monitoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProvision(t *testing.T) {
+	dashboards := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dashboards, "erigon.json"),
+		[]byte(`{"panels": [{"datasource": {"type": "prometheus", "uid": "${DS_PROMETHEUS}"}}]}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dashboards, "README.md"), []byte("not a dashboard"), 0644))
+
+	cfg := DefaultConfig
+	cfg.Dir = t.TempDir()
+	cfg.DashboardsDir = dashboards
+	cfg.PrometheusPort = 9190
+
+	targets := []Target{{"dev-0", "localhost:6060"}, {"dev-1", "localhost:6061"}}
+
+	n, err := Provision(cfg, targets, "/etc/grafana/dashboards")
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	var prometheus struct {
+		ScrapeConfigs []scrapeConfig `yaml:"scrape_configs"`
+	}
+	data, err := os.ReadFile(filepath.Join(cfg.Dir, "prometheus", "prometheus.yml"))
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &prometheus))
+	require.Len(t, prometheus.ScrapeConfigs, 1)
+	require.Equal(t, "/debug/metrics/prometheus", prometheus.ScrapeConfigs[0].MetricsPath)
+	require.Equal(t, []staticConfig{
+		{Targets: []string{"localhost:6060"}, Labels: map[string]string{"instance": "dev-0"}},
+		{Targets: []string{"localhost:6061"}, Labels: map[string]string{"instance": "dev-1"}},
+	}, prometheus.ScrapeConfigs[0].StaticConfigs)
+
+	data, err = os.ReadFile(filepath.Join(cfg.Dir, "grafana", "provisioning", "datasources", "prometheus.yml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "url: http://localhost:9190")
+
+	data, err = os.ReadFile(filepath.Join(cfg.Dir, "grafana", "provisioning", "dashboards", "dashboard.yml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "path: /etc/grafana/dashboards")
+
+	data, err = os.ReadFile(filepath.Join(cfg.Dir, "grafana", "dashboards", "erigon.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{"panels": [{"datasource": {"type": "prometheus", "uid": "devnet-prometheus"}}]}`, string(data))
+
+	// missing dashboards aren't an error
+	cfg.DashboardsDir = filepath.Join(dashboards, "missing")
+	n, err = Provision(cfg, targets, "/etc/grafana/dashboards")
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{Auto, Docker, Binary, None} {
+		parsed, err := ParseMode(string(mode))
+		require.NoError(t, err)
+		require.Equal(t, mode, parsed)
+	}
+
+	_, err := ParseMode("kubernetes")
+	require.Error(t, err)
+}
@@ -83,6 +83,8 @@ var (
 	disableIPV6                    bool
 	disableIPV4                    bool
 	seedbox                        bool
+	verifyReportPath               string
+	verifyRestart, verifyRepair    bool
 )
 
 func init() {
@@ -129,6 +131,13 @@ func init() {
 	withChainFlag(manifestVerifyCmd)
 	rootCmd.AddCommand(manifestVerifyCmd)
 
+	withDataDir(verifyCmd)
+	verifyCmd.Flags().StringVar(&chain, utils.ChainFlag.Name, utils.ChainFlag.Value, "Chain, required by --verify.repair")
+	verifyCmd.Flags().StringVar(&verifyReportPath, "verify.report", "", "Where to write machine-readable report (default: <datadir>/downloader/"+downloader.VerifyReportFileName+")")
+	verifyCmd.Flags().BoolVar(&verifyRestart, "verify.restart", false, "Ignore progress of previous run and re-hash all pieces")
+	verifyCmd.Flags().BoolVar(&verifyRepair, "verify.repair", false, "Re-download only corrupted pieces and exit when done")
+	rootCmd.AddCommand(verifyCmd)
+
 	withDataDir(printTorrentHashes)
 	withChainFlag(printTorrentHashes)
 	printTorrentHashes.PersistentFlags().BoolVar(&forceRebuild, "rebuild", false, "Force re-create .torrent files")
@@ -187,53 +196,7 @@ var rootCmd = &cobra.Command{
 }
 
 func Downloader(ctx context.Context, logger log.Logger) error {
-	dirs := datadir.New(datadirCli)
-	if err := datadir.ApplyMigrations(dirs); err != nil {
-		return err
-	}
-	if err := checkChainName(ctx, dirs, chain); err != nil {
-		return err
-	}
-	torrentLogLevel, _, err := downloadercfg.Int2LogLevel(torrentVerbosity)
-	if err != nil {
-		return err
-	}
-
-	var downloadRate, uploadRate datasize.ByteSize
-	if err := downloadRate.UnmarshalText([]byte(downloadRateStr)); err != nil {
-		return err
-	}
-	if err := uploadRate.UnmarshalText([]byte(uploadRateStr)); err != nil {
-		return err
-	}
-
-	logger.Info("[snapshots] cli flags", "chain", chain, "addr", downloaderApiAddr, "datadir", dirs.DataDir, "ipv6-enabled", !disableIPV6, "ipv4-enabled", !disableIPV4, "download.rate", downloadRate.String(), "upload.rate", uploadRate.String(), "webseed", webseeds)
-	staticPeers := common.CliString2Array(staticPeersStr)
-
-	version := "erigon: " + params.VersionWithCommit(params.GitCommit)
-
-	webseedsList := common.CliString2Array(webseeds)
-	if known, ok := snapcfg.KnownWebseeds[chain]; ok {
-		webseedsList = append(webseedsList, known...)
-	}
-	cfg, err := downloadercfg.New(dirs, version, torrentLogLevel, downloadRate, uploadRate, torrentPort, torrentConnsPerFile, torrentDownloadSlots, staticPeers, webseedsList, chain, true)
-	if err != nil {
-		return err
-	}
-
-	cfg.ClientConfig.PieceHashersPerTorrent = dbg.EnvInt("DL_HASHERS", 32)
-	cfg.ClientConfig.DisableIPv6 = disableIPV6
-	cfg.ClientConfig.DisableIPv4 = disableIPV4
-
-	natif, err := nat.Parse(natSetting)
-	if err != nil {
-		return fmt.Errorf("invalid nat option %s: %w", natSetting, err)
-	}
-	downloadernat.DoNat(natif, cfg.ClientConfig, logger)
-
-	cfg.AddTorrentsFromDisk = true // always true unless using uploader - which wants control of torrent files
-
-	d, err := downloader.New(ctx, cfg, logger, log.LvlInfo, seedbox)
+	d, err := newDownloader(ctx, datadir.New(datadirCli), logger)
 	if err != nil {
 		return err
 	}
@@ -278,6 +241,55 @@ func Downloader(ctx context.Context, logger log.Logger) error {
 	return nil
 }
 
+func newDownloader(ctx context.Context, dirs datadir.Dirs, logger log.Logger) (*downloader.Downloader, error) {
+	if err := datadir.ApplyMigrations(dirs); err != nil {
+		return nil, err
+	}
+	if err := checkChainName(ctx, dirs, chain); err != nil {
+		return nil, err
+	}
+	torrentLogLevel, _, err := downloadercfg.Int2LogLevel(torrentVerbosity)
+	if err != nil {
+		return nil, err
+	}
+
+	var downloadRate, uploadRate datasize.ByteSize
+	if err := downloadRate.UnmarshalText([]byte(downloadRateStr)); err != nil {
+		return nil, err
+	}
+	if err := uploadRate.UnmarshalText([]byte(uploadRateStr)); err != nil {
+		return nil, err
+	}
+
+	logger.Info("[snapshots] cli flags", "chain", chain, "addr", downloaderApiAddr, "datadir", dirs.DataDir, "ipv6-enabled", !disableIPV6, "ipv4-enabled", !disableIPV4, "download.rate", downloadRate.String(), "upload.rate", uploadRate.String(), "webseed", webseeds)
+	staticPeers := common.CliString2Array(staticPeersStr)
+
+	version := "erigon: " + params.VersionWithCommit(params.GitCommit)
+
+	webseedsList := common.CliString2Array(webseeds)
+	if known, ok := snapcfg.KnownWebseeds[chain]; ok {
+		webseedsList = append(webseedsList, known...)
+	}
+	cfg, err := downloadercfg.New(dirs, version, torrentLogLevel, downloadRate, uploadRate, torrentPort, torrentConnsPerFile, torrentDownloadSlots, staticPeers, webseedsList, chain, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ClientConfig.PieceHashersPerTorrent = dbg.EnvInt("DL_HASHERS", 32)
+	cfg.ClientConfig.DisableIPv6 = disableIPV6
+	cfg.ClientConfig.DisableIPv4 = disableIPV4
+
+	natif, err := nat.Parse(natSetting)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nat option %s: %w", natSetting, err)
+	}
+	downloadernat.DoNat(natif, cfg.ClientConfig, logger)
+
+	cfg.AddTorrentsFromDisk = true // always true unless using uploader - which wants control of torrent files
+
+	return downloader.New(ctx, cfg, logger, log.LvlInfo, seedbox)
+}
+
 var createTorrent = &cobra.Command{
 	Use:     "torrent_create",
 	Example: "go run ./cmd/downloader torrent_create --datadir=<your_datadir> --file=<relative_file_path>",
@@ -329,6 +341,21 @@ var manifestVerifyCmd = &cobra.Command{
 	},
 }
 
+var verifyCmd = &cobra.Command{
+	Use:     "verify",
+	Short:   "Hash all local snapshot pieces against .torrent files, without deleting and re-downloading whole files",
+	Example: "go run ./cmd/downloader verify --datadir <your_datadir> [--verify.files=v1-1-2-transaction.seg] [--verify.repair --chain <chain>]",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := verifyPieces(cmd.Context(), logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			os.Exit(1) // to let scripts notice corruption
+		}
+		return nil
+	},
+}
+
 var torrentCat = &cobra.Command{
 	Use:     "torrent_cat",
 	Example: "go run ./cmd/downloader torrent_cat <path_to_torrent_file>",
@@ -406,6 +433,62 @@ var torrentMagnet = &cobra.Command{
 	},
 }
 
+func verifyPieces(ctx context.Context, logger log.Logger) error {
+	dirs := datadir.New(datadirCli)
+	if len(_verifyFiles) > 0 {
+		verifyFiles = strings.Split(_verifyFiles, ",")
+	}
+	reportPath := verifyReportPath
+	if reportPath == "" {
+		reportPath = filepath.Join(dirs.Downloader, downloader.VerifyReportFileName)
+	}
+	if verifyRestart {
+		if err := os.Remove(reportPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	report, err := downloader.VerifyPieces(ctx, dirs, reportPath, verifyFiles, logger)
+	if err != nil {
+		return err
+	}
+	corrupted := report.CorruptedFiles()
+	for _, f := range corrupted {
+		logger.Warn("[snapshots] corrupted file", "name", f.Name, "corrupted_pieces", len(f.Corrupted), "pieces", f.Pieces, "missing", f.Missing)
+	}
+	if len(corrupted) == 0 {
+		return nil
+	}
+	if !verifyRepair {
+		return fmt.Errorf("found %d corrupted files, report: %s", len(corrupted), reportPath)
+	}
+
+	if err := downloader.MarkCorruptedPieces(ctx, dirs, report, logger); err != nil {
+		return err
+	}
+	d, err := newDownloader(ctx, dirs, logger)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	d.MainLoopInBackground(false)
+
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-logEvery.C:
+			if stats := d.Stats(); stats.Completed {
+				logger.Info("[snapshots] corrupted pieces re-downloaded", "files", len(corrupted))
+				// next run must re-hash repaired files
+				return os.Remove(reportPath)
+			}
+		}
+	}
+}
+
 func manifestVerify(ctx context.Context, logger log.Logger) error {
 	webseedsList := common.CliString2Array(webseeds)
 	if len(webseedsList) == 0 { // fallback to default if exact list not passed
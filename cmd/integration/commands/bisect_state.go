@@ -0,0 +1,487 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	chain2 "github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+func init() {
+	withConfig(cmdBisectState)
+	withDataDir(cmdBisectState)
+	withChain(cmdBisectState)
+	withHeimdall(cmdBisectState)
+	withBlockRange(cmdBisectState)
+	rootCmd.AddCommand(cmdBisectState)
+}
+
+var cmdBisectState = &cobra.Command{
+	Use:     "bisect_state",
+	Short:   "Re-execute blocks [from, to] on historical state without writing anything, find first block and transaction whose resulting state diverges from canonical one and print differing keys",
+	Example: "go run ./cmd/integration bisect_state --datadir=... --from=19000000 --to=19001000",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := bisectState(db, cmd.Context(), fromBlock, toBlock, logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+func bisectState(db kv.RwDB, ctx context.Context, from, to uint64, logger log.Logger) error {
+	sn, borSn, agg := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		if to, err = stages.GetStageProgress(tx, stages.Execution); err != nil {
+			return err
+		}
+	}
+	if from > to {
+		return fmt.Errorf("empty range: from %d > to %d", from, to)
+	}
+
+	br, _ := blocksIO(db, logger)
+	chainConfig := fromdb.ChainConfig(db)
+	engine, _ := initConsensusEngine(ctx, chainConfig, datadirCli, db, br, logger)
+	b := &stateBisector{ctx: ctx, tx: tx, ttx: tx.(kv.TemporalTx), chainConfig: chainConfig, engine: engine, blockReader: br, logger: logger}
+
+	diverged, reason, err := b.check(from, to)
+	if err != nil {
+		return err
+	}
+	if !diverged {
+		fmt.Printf("no divergence in blocks %d-%d\n", from, to)
+		return nil
+	}
+
+	// range [from, lo) re-executes to canonical state, so next check can start from canonical state at lo
+	lo, hi := from, to
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		midDiverged, midReason, err := b.check(lo, mid)
+		if err != nil {
+			return err
+		}
+		if midDiverged {
+			hi, reason = mid, midReason
+		} else {
+			lo = mid + 1
+		}
+		logger.Info("[bisect_state] narrowed", "from", lo, "to", hi)
+	}
+	fmt.Printf("first divergent block %d: %s\n", lo, reason)
+	return b.explain(lo)
+}
+
+type stateBisector struct {
+	ctx         context.Context
+	tx          kv.Tx
+	ttx         kv.TemporalTx
+	chainConfig *chain2.Config
+	engine      consensus.Engine
+	blockReader services.FullBlockReader
+	logger      log.Logger
+}
+
+func (b *stateBisector) historyAt(txNum uint64) *state.HistoryReaderV3 {
+	r := state.NewHistoryReaderV3()
+	r.SetTx(b.tx)
+	r.SetTxNum(txNum)
+	return r
+}
+
+// check re-executes blocks [from, to] on top of canonical state before `from` and compares every key written by
+// re-execution or changed in canonical history of the range. Equal keys mean equal state roots.
+func (b *stateBisector) check(from, to uint64) (diverged bool, reason string, err error) {
+	fromTxNum, err := rawdbv3.TxNums.Min(b.tx, from)
+	if err != nil {
+		return false, "", err
+	}
+	toTxNum, err := rawdbv3.TxNums.Max(b.tx, to)
+	if err != nil {
+		return false, "", err
+	}
+
+	overlay := newStateOverlay(b.historyAt(fromTxNum))
+	for blockNum := from; blockNum <= to; blockNum++ {
+		if err := b.execBlock(blockNum, overlay, overlay, nil); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return false, "", err
+			}
+			return true, err.Error(), nil
+		}
+	}
+
+	diffs, err := b.compare(overlay, fromTxNum, toTxNum+1)
+	if err != nil {
+		return false, "", err
+	}
+	if len(diffs) > 0 {
+		return true, fmt.Sprintf("%d keys differ after block %d", len(diffs), to), nil
+	}
+	return false, "", nil
+}
+
+// explain re-executes the block on canonical state transaction by transaction, printing the first
+// transaction which state differs
+func (b *stateBisector) explain(blockNum uint64) error {
+	minTxNum, err := rawdbv3.TxNums.Min(b.tx, blockNum)
+	if err != nil {
+		return err
+	}
+	maxTxNum, err := rawdbv3.TxNums.Max(b.tx, blockNum)
+	if err != nil {
+		return err
+	}
+
+	var txDiffs []stateDiff
+	blockOverlay := newStateOverlay(b.historyAt(minTxNum))
+	execErr := b.execBlock(blockNum, blockOverlay, blockOverlay, func(txIndex int, txn types.Transaction, writes *stateOverlay) error {
+		if txDiffs != nil {
+			return nil
+		}
+		// system tx of block start precedes txs
+		txNum := minTxNum + 1 + uint64(txIndex)
+		diffs, err := b.compare(writes, txNum, txNum+1)
+		if err != nil {
+			return err
+		}
+		if len(diffs) > 0 {
+			fmt.Printf("first divergent tx %d [%x]\n", txIndex, txn.Hash())
+			txDiffs = diffs
+		}
+		return nil
+	})
+	if execErr != nil {
+		fmt.Printf("execution: %v\n", execErr)
+	}
+
+	diffs := txDiffs
+	if diffs == nil && execErr == nil {
+		// all txs match: rewards, withdrawals or other system calls of block end diverge
+		if diffs, err = b.compare(blockOverlay, minTxNum, maxTxNum+1); err != nil {
+			return err
+		}
+		if len(diffs) > 0 {
+			fmt.Printf("txs match, block finalization diverges\n")
+		}
+	}
+	for _, d := range diffs {
+		fmt.Printf("%s\n", d)
+	}
+	return nil
+}
+
+// execBlock executes block on top of reader, writing resulting state to blockWriter. If onTx is set, it's
+// called after each transaction with state written by the transaction (over canonical state before it).
+func (b *stateBisector) execBlock(blockNum uint64, reader state.StateReader, blockWriter state.StateWriter, onTx func(txIndex int, txn types.Transaction, writes *stateOverlay) error) error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	block, err := b.blockReader.BlockByNumber(b.ctx, b.tx, blockNum)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("block %d not found", blockNum)
+	}
+	header := block.Header()
+	chainReader := consensuschain.NewReader(b.chainConfig, b.tx, b.blockReader, b.logger)
+	getHashFn := core.GetHashFn(header, func(hash libcommon.Hash, number uint64) *types.Header {
+		h, _ := b.blockReader.Header(b.ctx, b.tx, hash, number)
+		return h
+	})
+
+	ibs := state.New(reader)
+	if err := core.InitializeBlockExecution(b.engine, chainReader, header, b.chainConfig, ibs, b.logger); err != nil {
+		return err
+	}
+
+	minTxNum, err := rawdbv3.TxNums.Min(b.tx, blockNum)
+	if err != nil {
+		return err
+	}
+	gp := new(core.GasPool).AddGas(block.GasLimit()).AddBlobGas(b.chainConfig.GetMaxBlobGasPerBlock())
+	usedGas, usedBlobGas := new(uint64), new(uint64)
+	receipts := make(types.Receipts, 0, block.Transactions().Len())
+	for i, txn := range block.Transactions() {
+		ibs.SetTxContext(txn.Hash(), block.Hash(), i)
+		var txWriter state.StateWriter = state.NewNoopWriter()
+		var writes *stateOverlay
+		if onTx != nil {
+			writes = newStateOverlay(b.historyAt(minTxNum + 1 + uint64(i)))
+			txWriter = writes
+		}
+		receipt, _, err := core.ApplyTransaction(b.chainConfig, getHashFn, b.engine, nil, gp, ibs, txWriter, header, txn, usedGas, usedBlobGas, vm.Config{})
+		if err != nil {
+			return fmt.Errorf("tx %d [%x]: %w", i, txn.Hash(), err)
+		}
+		receipts = append(receipts, receipt)
+		if onTx != nil {
+			if err := onTx(i, txn, writes); err != nil {
+				return err
+			}
+		}
+	}
+
+	if *usedGas != header.GasUsed {
+		return fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)
+	}
+	if b.chainConfig.IsByzantium(blockNum) {
+		if receiptHash := types.DeriveSha(receipts); receiptHash != header.ReceiptHash {
+			return fmt.Errorf("receipts root by execution: %x, in header: %x", receiptHash, header.ReceiptHash)
+		}
+	}
+
+	if _, _, _, err := core.FinalizeBlockExecution(b.engine, reader, header, block.Transactions(), block.Uncles(), blockWriter, b.chainConfig, ibs, receipts, block.Withdrawals(), block.Requests(), chainReader, false, b.logger); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compare returns keys which value in `got` differs from canonical state as of toTxNum: keys written to `got`
+// and keys changed by canonical chain in [fromTxNum, toTxNum)
+func (b *stateBisector) compare(got *stateOverlay, fromTxNum, toTxNum uint64) ([]stateDiff, error) {
+	want := b.historyAt(toTxNum)
+
+	addrs := map[libcommon.Address]struct{}{}
+	for addr := range got.accounts {
+		addrs[addr] = struct{}{}
+	}
+	slots := map[storageSlot]struct{}{}
+	for slot := range got.storage {
+		slots[slot] = struct{}{}
+	}
+
+	it, err := b.ttx.HistoryRange(kv.AccountsHistory, int(fromTxNum), int(toTxNum), order.Asc, -1)
+	if err != nil {
+		return nil, err
+	}
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		addrs[libcommon.BytesToAddress(k)] = struct{}{}
+	}
+	it, err = b.ttx.HistoryRange(kv.StorageHistory, int(fromTxNum), int(toTxNum), order.Asc, -1)
+	if err != nil {
+		return nil, err
+	}
+	for it.HasNext() {
+		k, _, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		slots[storageSlot{libcommon.BytesToAddress(k[:length.Addr]), libcommon.BytesToHash(k[length.Addr:])}] = struct{}{}
+	}
+
+	var diffs []stateDiff
+	for addr := range addrs {
+		gotAcc, err := got.ReadAccountData(addr)
+		if err != nil {
+			return nil, err
+		}
+		wantAcc, err := want.ReadAccountData(addr)
+		if err != nil {
+			return nil, err
+		}
+		if accountString(gotAcc) != accountString(wantAcc) {
+			diffs = append(diffs, stateDiff{addr: addr, got: accountString(gotAcc), want: accountString(wantAcc)})
+		}
+	}
+	for slot := range slots {
+		key := slot.key
+		gotV, err := got.ReadAccountStorage(slot.addr, 0, &key)
+		if err != nil {
+			return nil, err
+		}
+		wantV, err := want.ReadAccountStorage(slot.addr, 0, &key)
+		if err != nil {
+			return nil, err
+		}
+		if g, w := new(uint256.Int).SetBytes(gotV), new(uint256.Int).SetBytes(wantV); !g.Eq(w) {
+			diffs = append(diffs, stateDiff{addr: slot.addr, slot: &key, got: g.Hex(), want: w.Hex()})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if c := bytes.Compare(diffs[i].addr[:], diffs[j].addr[:]); c != 0 {
+			return c < 0
+		}
+		return diffs[i].slot != nil && (diffs[j].slot == nil || bytes.Compare(diffs[i].slot[:], diffs[j].slot[:]) < 0)
+	})
+	return diffs, nil
+}
+
+type stateDiff struct {
+	addr      libcommon.Address
+	slot      *libcommon.Hash // nil for account
+	got, want string
+}
+
+func (d stateDiff) String() string {
+	if d.slot == nil {
+		return fmt.Sprintf("account %x: got %s, canonical %s", d.addr, d.got, d.want)
+	}
+	return fmt.Sprintf("storage %x %x: got %s, canonical %s", d.addr, *d.slot, d.got, d.want)
+}
+
+func accountString(a *accounts.Account) string {
+	if a == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{nonce: %d, balance: %d, codeHash: %x}", a.Nonce, &a.Balance, a.CodeHash)
+}
+
+type storageSlot struct {
+	addr libcommon.Address
+	key  libcommon.Hash
+}
+
+// stateOverlay - in-memory state written by re-execution on top of read-only historical state
+type stateOverlay struct {
+	base     state.StateReader
+	accounts map[libcommon.Address]*accounts.Account // nil - deleted
+	storage  map[storageSlot]uint256.Int
+	code     map[libcommon.Address][]byte
+	wiped    map[libcommon.Address]struct{} // storage of base must not be read: account deleted or re-created
+}
+
+func newStateOverlay(base state.StateReader) *stateOverlay {
+	return &stateOverlay{
+		base:     base,
+		accounts: map[libcommon.Address]*accounts.Account{},
+		storage:  map[storageSlot]uint256.Int{},
+		code:     map[libcommon.Address][]byte{},
+		wiped:    map[libcommon.Address]struct{}{},
+	}
+}
+
+func (o *stateOverlay) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	if a, ok := o.accounts[address]; ok {
+		if a == nil {
+			return nil, nil
+		}
+		cpy := new(accounts.Account)
+		cpy.Copy(a)
+		return cpy, nil
+	}
+	return o.base.ReadAccountData(address)
+}
+
+func (o *stateOverlay) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	if v, ok := o.storage[storageSlot{address, *key}]; ok {
+		return v.Bytes(), nil
+	}
+	if _, ok := o.wiped[address]; ok {
+		return nil, nil
+	}
+	return o.base.ReadAccountStorage(address, incarnation, key)
+}
+
+func (o *stateOverlay) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	if code, ok := o.code[address]; ok {
+		return code, nil
+	}
+	return o.base.ReadAccountCode(address, incarnation, codeHash)
+}
+
+func (o *stateOverlay) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := o.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (o *stateOverlay) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	if a, ok := o.accounts[address]; ok {
+		if a == nil {
+			return 0, nil
+		}
+		return a.Incarnation, nil
+	}
+	return o.base.ReadAccountIncarnation(address)
+}
+
+func (o *stateOverlay) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	cpy := new(accounts.Account)
+	cpy.Copy(account)
+	o.accounts[address] = cpy
+	return nil
+}
+
+func (o *stateOverlay) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	o.code[address] = libcommon.Copy(code)
+	return nil
+}
+
+func (o *stateOverlay) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	o.accounts[address] = nil
+	o.code[address] = nil
+	o.wipeStorage(address)
+	return nil
+}
+
+func (o *stateOverlay) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	o.storage[storageSlot{address, *key}] = *value
+	return nil
+}
+
+func (o *stateOverlay) CreateContract(address libcommon.Address) error {
+	o.wipeStorage(address)
+	return nil
+}
+
+func (o *stateOverlay) wipeStorage(address libcommon.Address) {
+	for slot := range o.storage {
+		if slot.addr == address {
+			o.storage[slot] = uint256.Int{}
+		}
+	}
+	o.wiped[address] = struct{}{}
+}
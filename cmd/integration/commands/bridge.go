@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/polygon/bor"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+)
+
+var (
+	bridgeFromID, bridgeToID uint64
+	bridgeToTime             int64
+	bridgeDryRun             bool
+)
+
+var cmdBridge = &cobra.Command{
+	Use:   "bridge",
+	Short: "Polygon bridge (state sync events) maintenance commands",
+}
+
+var cmdBridgeBackfill = &cobra.Command{
+	Use:     "backfill",
+	Short:   "Re-fetch state sync events from Heimdall, write the ones missing in db and report the ones differing from Heimdall",
+	Example: "integration bridge backfill --datadir=<datadir> --bor.heimdall=<url> --from-id=1000 --to-id=2000",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := bridgeBackfill(cmd.Context(), db, logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+func init() {
+	withDataDir(cmdBridgeBackfill)
+	withHeimdall(cmdBridgeBackfill)
+	cmdBridgeBackfill.Flags().Uint64Var(&bridgeFromID, "from-id", 0, "first state sync event id to check, 0 - first event after the snapshots (frozen events are never checked)")
+	cmdBridgeBackfill.Flags().Uint64Var(&bridgeToID, "to-id", 0, "last state sync event id to check, 0 - last event id in db")
+	cmdBridgeBackfill.Flags().Int64Var(&bridgeToTime, "to-time", 0, "unix timestamp, events recorded at or after it are not checked, 0 - now")
+	cmdBridgeBackfill.Flags().BoolVar(&bridgeDryRun, "dry-run", false, "only report missing and mismatched events, don't write anything")
+
+	cmdBridge.AddCommand(cmdBridgeBackfill)
+	rootCmd.AddCommand(cmdBridge)
+}
+
+func bridgeBackfill(ctx context.Context, db kv.RwDB, logger log.Logger) error {
+	chainConfig := fromdb.ChainConfig(db)
+	if chainConfig.Bor == nil {
+		return errors.New("bridge backfill is only supported for bor chains")
+	}
+
+	blockReader, _ := blocksIO(db, logger)
+	cfg := stagedsync.StateSyncEventsBackfillCfg{
+		HeimdallClient:    heimdall.NewHeimdallClient(HeimdallURL, logger),
+		StateReceiverABI:  bor.GenesisContractStateReceiverABI(),
+		ChainID:           chainConfig.ChainID.String(),
+		FromID:            bridgeFromID,
+		LastFrozenEventID: blockReader.LastFrozenEventId(),
+		ToID:              bridgeToID,
+		DryRun:            bridgeDryRun,
+	}
+	if bridgeToTime > 0 {
+		cfg.ToTime = time.Unix(bridgeToTime, 0)
+	}
+
+	tx, err := db.BeginRw(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	report, err := stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	if !bridgeDryRun {
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	for _, mismatch := range report.Mismatched {
+		logger.Warn("[bridge backfill] Event differs from Heimdall", "eventId", mismatch.ID, "diskHash", mismatch.DiskHash, "heimdallHash", mismatch.HeimdallHash)
+	}
+	logger.Info("[bridge backfill] Done",
+		"from", report.FromID, "lastId", report.LastID, "checked", report.Checked,
+		"missing", len(report.Missing), "written", !bridgeDryRun, "mismatched", len(report.Mismatched))
+	return nil
+}
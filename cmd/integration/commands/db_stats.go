@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/spf13/cobra"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+var (
+	dbStatsPayload bool
+	dbStatsJson    bool
+)
+
+func init() {
+	withDataDir(cmdDbStats)
+	cmdDbStats.Flags().BoolVar(&dbStatsPayload, "payload", false, "scan tables to compute size of keys and values and fill factor of pages (slow on large dbs)")
+	cmdDbStats.Flags().BoolVar(&dbStatsJson, "json", false, "print stats as json")
+	rootCmd.AddCommand(cmdDbStats)
+}
+
+var cmdDbStats = &cobra.Command{
+	Use:     "db_stats",
+	Short:   "Print per-table page counts, sizes and fill factor of the db",
+	Example: "go run ./cmd/integration db_stats --datadir=... --payload",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := debug.SetupCobra(cmd, "integration")
+		ctx, _ := libcommon.RootContext()
+
+		db := dbCfg(kv.ChainDB, chaindata).Readonly().MustOpen()
+		defer db.Close()
+
+		var stats []kv2.TableStats
+		if err := db.View(ctx, func(tx kv.Tx) (err error) {
+			stats, err = tx.(*kv2.MdbxTx).TablesStats(ctx, dbStatsPayload)
+			return err
+		}); err != nil {
+			logger.Error("Reading db stats", "error", err)
+			return err
+		}
+
+		if dbStatsJson {
+			return json.NewEncoder(os.Stdout).Encode(stats)
+		}
+		printDbStats(stats, dbStatsPayload)
+		return nil
+	},
+}
+
+func printDbStats(stats []kv2.TableStats, withPayload bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	defer w.Flush()
+
+	fmt.Fprint(w, "table\tsize\tentries\tdepth\tbranch\tleaf\toverflow\t")
+	if withPayload {
+		fmt.Fprint(w, "payload\tfill\t")
+	}
+	fmt.Fprintln(w)
+
+	var total uint64
+	for _, st := range stats {
+		total += st.Size
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t", st.Name, datasize.ByteSize(st.Size).HR(), st.Entries, st.Depth, st.BranchPages, st.LeafPages, st.OverflowPages)
+		if withPayload {
+			fmt.Fprintf(w, "%s\t%.2f\t", datasize.ByteSize(st.PayloadSize).HR(), st.FillFactor)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "total\t%s\t\n", datasize.ByteSize(total).HR())
+}
@@ -14,6 +14,7 @@ var (
 	databaseVerbosity                        int
 	referenceChaindata                       string
 	block, pruneTo, unwind                   uint64
+	fromBlock, toBlock                       uint64
 	unwindEvery                              uint64
 	batchSizeStr                             string
 	reset, warmup, noCommit                  bool
@@ -43,6 +44,11 @@ var (
 
 	_forceSetHistoryV3    bool
 	workers, reconWorkers uint64
+
+	migratePageSize, migrateGrowthStep, migrateMapSize string
+	migrateTables, migrateExcludeTables                []string
+	migrateConvertTables                               []string
+	migrateVerify                                      bool
 )
 
 func must(err error) {
@@ -86,6 +92,11 @@ func withBlock(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&block, "block", 0, "block test at this block")
 }
 
+func withBlockRange(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&fromBlock, "from", 0, "first block of range")
+	cmd.Flags().Uint64Var(&toBlock, "to", 0, "last block of range, 0 means execution stage progress")
+}
+
 func withUnwind(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&unwind, "unwind", 0, "how much blocks unwind on each iteration")
 }
@@ -110,6 +121,19 @@ func withResetPruneAt(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&resetPruneAt, "resetPruneAt", false, "reset prune_at to 0 for a given stage")
 }
 
+func withMdbxGeometry(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&migratePageSize, "page.size", "", "page size of the new db, like 8kb. Default: same as the source db")
+	cmd.Flags().StringVar(&migrateGrowthStep, "growth.step", "", "growth step of the new db, like 2gb. Default: same as the source db")
+	cmd.Flags().StringVar(&migrateMapSize, "map.size", "", "max size of the new db, like 12tb. Default: same as the source db")
+}
+
+func withMigrateTables(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&migrateTables, "tables", nil, "copy only these tables. Default: all tables")
+	cmd.Flags().StringSliceVar(&migrateExcludeTables, "tables.exclude", nil, "tables to skip, they are left empty in the new db")
+	cmd.Flags().StringSliceVar(&migrateConvertTables, "tables.convert", nil, "tables to re-encode by the converters registered in the integration tool")
+	cmd.Flags().BoolVar(&migrateVerify, "verify", true, "compare every copied table to the source")
+}
+
 func withBucket(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&bucket, "bucket", "", "reset given stage")
 }
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracers/profiler"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+func init() {
+	withConfig(cmdProfileExec)
+	withDataDir(cmdProfileExec)
+	withChain(cmdProfileExec)
+	withHeimdall(cmdProfileExec)
+	withBlockRange(cmdProfileExec)
+	withNoCommit(cmdProfileExec)
+	rootCmd.AddCommand(cmdProfileExec)
+}
+
+var cmdProfileExec = &cobra.Command{
+	Use:     "profile_exec",
+	Short:   "Re-execute blocks [from, to] on historical state recording per-opcode gas/time histograms, per-transaction costs and hottest contracts. Profiles are stored into ExecutionProfile table (served by debug_executionProfile), unless --no-commit",
+	Example: "go run ./cmd/integration profile_exec --datadir=... --from=19000000 --to=19001000",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := profileExec(db, cmd.Context(), fromBlock, toBlock, logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+const profileExecCommitEvery = 1000 // blocks per db transaction
+const profileExecTop = 20
+
+func profileExec(db kv.RwDB, ctx context.Context, from, to uint64, logger log.Logger) error {
+	sn, borSn, agg := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
+
+	if from == 0 {
+		from = 1
+	}
+	if to == 0 {
+		if err := db.View(ctx, func(tx kv.Tx) (err error) {
+			to, err = stages.GetStageProgress(tx, stages.Execution)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if from > to {
+		return fmt.Errorf("empty range: from %d > to %d", from, to)
+	}
+
+	br, _ := blocksIO(db, logger)
+	chainConfig := fromdb.ChainConfig(db)
+	engine, _ := initConsensusEngine(ctx, chainConfig, datadirCli, db, br, logger)
+
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+
+	aggregator := profiler.NewAggregator(from, to, profileExecTop)
+	for batchFrom := from; batchFrom <= to; batchFrom += profileExecCommitEvery {
+		batchTo := min(batchFrom+profileExecCommitEvery-1, to)
+		tx, err := db.BeginRw(ctx)
+		if err != nil {
+			return err
+		}
+		chainReader := consensuschain.NewReader(chainConfig, tx, br, logger)
+		for blockNum := batchFrom; blockNum <= batchTo; blockNum++ {
+			block, err := br.BlockByNumber(ctx, tx, blockNum)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			if block == nil {
+				tx.Rollback()
+				return fmt.Errorf("block %d not found", blockNum)
+			}
+			profile, err := profiler.Generate(ctx, tx, block, chainConfig, engine, chainReader, logger)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			aggregator.Add(profile)
+			if !noCommit {
+				enc, err := profile.Encode()
+				if err != nil {
+					tx.Rollback()
+					return err
+				}
+				if err := rawdb.WriteExecutionProfile(tx, block.Hash(), blockNum, enc); err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+
+			select {
+			case <-logEvery.C:
+				logger.Info("[profile_exec] progress", "block", blockNum, "to", to)
+			default:
+			}
+		}
+		if noCommit {
+			tx.Rollback()
+			continue
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	printRangeProfile(aggregator.Result())
+	return nil
+}
+
+func printRangeProfile(p *profiler.RangeProfile) {
+	fmt.Printf("blocks %d-%d: gas %d, time %s\n", p.FromBlock, p.ToBlock, p.GasUsed, time.Duration(p.TimeNs))
+
+	names := make([]string, 0, len(p.Opcodes))
+	for name := range p.Opcodes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return p.Opcodes[names[i]].TimeNs > p.Opcodes[names[j]].TimeNs })
+	if len(names) > profileExecTop {
+		names = names[:profileExecTop]
+	}
+	fmt.Printf("\nopcodes by time:\n")
+	for _, name := range names {
+		s := p.Opcodes[name]
+		fmt.Printf("%-16s count %12d  gas %14d  time %s\n", name, s.Count, s.Gas, time.Duration(s.TimeNs))
+	}
+
+	fmt.Printf("\ncontracts by gas:\n")
+	for _, c := range p.Contracts {
+		fmt.Printf("%x ops %12d  gas %14d  time %s\n", c.Address, c.Ops, c.Gas, time.Duration(c.TimeNs))
+	}
+
+	fmt.Printf("\ntransactions by time:\n")
+	for _, t := range p.Txs {
+		fmt.Printf("%x block %d  gas %12d  time %s\n", t.Hash, t.BlockNumber, t.GasUsed, time.Duration(t.TimeNs))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/c2h5oh/datasize"
 	common2 "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/cmp"
 	"github.com/ledgerwatch/erigon-lib/kv"
@@ -126,6 +127,22 @@ var cmdMdbxToMdbx = &cobra.Command{
 	},
 }
 
+var cmdMdbxMigrate = &cobra.Command{
+	Use:   "mdbx_migrate",
+	Short: "copy '--chaindata' to the new db '--chaindata.to' with different geometry (page size, growth step, map size)",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, _ := common2.RootContext()
+		logger := debug.SetupCobra(cmd, "integration")
+		err := mdbxMigrate(ctx, chaindata, toChaindata, logger)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
 var cmdFToMdbx = &cobra.Command{
 	Use:   "f_to_mdbx",
 	Short: "copy data from '--chaindata' to '--chaindata.to'",
@@ -171,6 +188,13 @@ func init() {
 
 	rootCmd.AddCommand(cmdMdbxToMdbx)
 
+	withDataDir(cmdMdbxMigrate)
+	withToChaindata(cmdMdbxMigrate)
+	withMdbxGeometry(cmdMdbxMigrate)
+	withMigrateTables(cmdMdbxMigrate)
+
+	rootCmd.AddCommand(cmdMdbxMigrate)
+
 	withToChaindata(cmdFToMdbx)
 	withFile(cmdFToMdbx)
 	withBucket(cmdFToMdbx)
@@ -391,6 +415,55 @@ func compareBuckets(ctx context.Context, tx kv.Tx, b string, refTx kv.Tx, refB s
 	return nil
 }
 
+// mdbxMigrateConverters - table converters applied by `mdbx_migrate`, register here when the encoding of a table changes
+var mdbxMigrateConverters = map[string]backup.Converter{}
+
+func mdbxMigrate(ctx context.Context, from, to string, logger log.Logger) error {
+	if to == "" {
+		return fmt.Errorf("--chaindata.to is required")
+	}
+	var geometry backup.Geometry
+	for _, size := range []struct {
+		flag, value string
+		dst         *datasize.ByteSize
+	}{
+		{"page.size", migratePageSize, &geometry.PageSize},
+		{"growth.step", migrateGrowthStep, &geometry.GrowthStep},
+		{"map.size", migrateMapSize, &geometry.MapSize},
+	} {
+		if size.value == "" {
+			continue
+		}
+		if err := size.dst.UnmarshalText([]byte(size.value)); err != nil {
+			return fmt.Errorf("--%s: %w", size.flag, err)
+		}
+	}
+
+	convert := map[string]backup.Converter{}
+	for _, table := range migrateConvertTables {
+		converter, ok := mdbxMigrateConverters[table]
+		if !ok {
+			return fmt.Errorf("no converter registered for table %s", table)
+		}
+		convert[table] = converter
+	}
+
+	src, dst, err := backup.OpenMigrationPair(from, to, kv.ChainDB, geometry, logger)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	defer dst.Close()
+
+	return backup.Migrate(ctx, src, dst, backup.MigrateCfg{
+		Tables:           migrateTables,
+		Exclude:          migrateExcludeTables,
+		Convert:          convert,
+		Verify:           migrateVerify,
+		ReadAheadThreads: backup.ReadAheadThreads,
+	}, logger)
+}
+
 func fToMdbx(ctx context.Context, logger log.Logger, to string) error {
 	file, err := os.Open(file)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/kv"
 	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 
 	"github.com/ledgerwatch/erigon/cmd/utils"
 	"github.com/ledgerwatch/erigon/migrations"
@@ -91,6 +92,12 @@ func openDB(opts kv2.MdbxOpts, applyMigrations bool, logger log.Logger) (kv.RwDB
 	}
 
 	if opts.GetLabel() == kv.ChainDB {
+		if err := db.View(context.Background(), func(tx kv.Tx) error {
+			_, err := statecodec.LoadActive(tx)
+			return err
+		}); err != nil {
+			return nil, err
+		}
 		_, _, agg := allSnapshots(context.Background(), db, logger)
 		tdb, err := temporal.New(db, agg)
 		if err != nil {
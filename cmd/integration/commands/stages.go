@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -565,6 +566,27 @@ var cmdSetSnap = &cobra.Command{
 	},
 }
 
+var cmdRebuildHeadersByHash = &cobra.Command{
+	Use:   "rebuild_headers_by_hash",
+	Short: "Build global header_hash -> block_number index of frozen headers (it's also built by blocks retire). --reset to build from scratch",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := debug.SetupCobra(cmd, "integration")
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), false, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := rebuildHeadersByHash(db, cmd.Context(), logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
 func init() {
 	withConfig(cmdPrintStages)
 	withDataDir(cmdPrintStages)
@@ -743,6 +765,11 @@ func init() {
 	must(cmdSetSnap.MarkFlagRequired("snapshots"))
 	rootCmd.AddCommand(cmdSetSnap)
 
+	withConfig(cmdRebuildHeadersByHash)
+	withDataDir(cmdRebuildHeadersByHash)
+	withReset(cmdRebuildHeadersByHash)
+	rootCmd.AddCommand(cmdRebuildHeadersByHash)
+
 	withConfig(cmdSetPrune)
 	withDataDir(cmdSetPrune)
 	withChain(cmdSetPrune)
@@ -762,6 +789,33 @@ func init() {
 	rootCmd.AddCommand(cmdSetPrune)
 }
 
+func rebuildHeadersByHash(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	sn, borSn, agg := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer borSn.Close()
+	defer agg.Close()
+
+	dirs := datadir.New(datadirCli)
+	if reset {
+		if err := os.Remove(filepath.Join(dirs.Snap, freezeblocks.HeadersByHashFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := sn.ReopenFolder(); err != nil {
+			return err
+		}
+	}
+
+	chainConfig := fromdb.ChainConfig(db)
+	built, err := freezeblocks.BuildHeadersByHashIdx(ctx, sn, chainConfig.ChainName, dirs.Tmp, logger)
+	if err != nil {
+		return err
+	}
+	if !built {
+		logger.Info("Headers-by-hash index is up to date", "to", sn.HeadersByHashTo())
+	}
+	return nil
+}
+
 func stageSnapshots(db kv.RwDB, ctx context.Context, logger log.Logger) error {
 	sn, borSn, agg := allSnapshots(ctx, db, logger)
 	defer sn.Close()
@@ -1864,6 +1918,7 @@ func newSync(ctx context.Context, db kv.RwDB, miningConfig *params.MiningConfig,
 
 	statusDataProvider := sentry.NewStatusDataProvider(
 		db,
+		blockReader,
 		chainConfig,
 		genesisBlock,
 		chainConfig.ChainID.Uint64(),
@@ -1941,7 +1996,7 @@ func newSync(ctx context.Context, db kv.RwDB, miningConfig *params.MiningConfig,
 				nil,
 			),
 			stagedsync.StageSendersCfg(db, sentryControlServer.ChainConfig, cfg.Sync, false, dirs.Tmp, cfg.Prune, blockReader, sentryControlServer.Hd, nil),
-			stagedsync.StageMiningExecCfg(db, miner, events, *chainConfig, engine, &vm.Config{}, dirs.Tmp, nil, 0, nil, nil, blockReader),
+			stagedsync.StageMiningExecCfg(db, miner, events, *chainConfig, engine, &vm.Config{}, dirs.Tmp, nil, 0, nil, nil, nil, blockReader),
 			stagedsync.StageMiningFinishCfg(db, *chainConfig, engine, miner, miningCancel, blockReader, builder.NewLatestBlockBuiltStore()),
 		),
 		stagedsync.MiningUnwindOrder,
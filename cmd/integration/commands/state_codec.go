@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+var stateCodecName string
+
+func init() {
+	withDataDir(cmdStateCodec)
+	cmdStateCodec.Flags().StringVar(&stateCodecName, "codec", "", fmt.Sprintf("re-encode accounts/storage with this codec, one of: %v. Empty - print current codec", statecodec.Names()))
+	rootCmd.AddCommand(cmdStateCodec)
+}
+
+var cmdStateCodec = &cobra.Command{
+	Use:     "state_codec",
+	Short:   "Print or change (offline) encoding of accounts and storage in chaindata",
+	Example: "go run ./cmd/integration state_codec --datadir=... --codec=verkle",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := debug.SetupCobra(cmd, "integration")
+		ctx, _ := libcommon.RootContext()
+
+		var to statecodec.StateCodec
+		if stateCodecName != "" {
+			var err error
+			if to, err = statecodec.ByName(stateCodecName); err != nil {
+				return err
+			}
+			// files are encoded at build time and can't be re-encoded in place
+			if err := checkNoStateFiles(datadir.New(datadirCli)); err != nil {
+				return err
+			}
+		}
+
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), true, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return err
+		}
+		defer db.Close()
+
+		from := statecodec.Active()
+		if to == nil {
+			fmt.Printf("state codec: %s\n", from.Name())
+			return nil
+		}
+		if from.Name() == to.Name() {
+			logger.Info("State codec not changed", "codec", to.Name())
+			return nil
+		}
+		if err := db.Update(ctx, func(tx kv.RwTx) error {
+			return statecodec.Reencode(ctx, tx, from, to, logger)
+		}); err != nil {
+			return err
+		}
+		logger.Info("State re-encoded", "from", from.Name(), "to", to.Name())
+		return nil
+	},
+}
+
+func checkNoStateFiles(dirs datadir.Dirs) error {
+	for _, pattern := range []string{
+		filepath.Join(dirs.SnapDomain, "*-accounts.*.kv"),
+		filepath.Join(dirs.SnapDomain, "*-storage.*.kv"),
+		filepath.Join(dirs.SnapHistory, "*-accounts.*.v"),
+		filepath.Join(dirs.SnapHistory, "*-storage.*.v"),
+	} {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			return errors.New("state codec can't be changed: datadir has state files (snapshots/domain, snapshots/history) which can't be re-encoded")
+		}
+	}
+	return nil
+}
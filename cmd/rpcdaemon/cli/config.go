@@ -39,6 +39,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
 	"github.com/ledgerwatch/erigon-lib/kv/temporal"
 	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql"
@@ -58,6 +59,7 @@ import (
 	"github.com/ledgerwatch/erigon/node/nodecfg"
 	"github.com/ledgerwatch/erigon/polygon/bor"
 	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+	borfinality "github.com/ledgerwatch/erigon/polygon/bor/finality"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 	"github.com/ledgerwatch/erigon/turbo/debug"
@@ -78,7 +80,9 @@ var rootCmd = &cobra.Command{
 }
 
 var (
-	stateCacheStr string
+	stateCacheStr       string
+	responseCacheStr    string
+	traceBlockMemoryStr string
 )
 
 func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
@@ -90,8 +94,10 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().BoolVar(&cfg.GraphQLEnabled, "graphql", false, "enables graphql endpoint (disabled by default)")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.Gascap, "rpc.gascap", 50_000_000, "Sets a cap on gas that can be used in eth_call/estimateGas")
 	rootCmd.PersistentFlags().Uint64Var(&cfg.MaxTraces, "trace.maxtraces", 200, "Sets a limit on traces that can be returned in trace_filter")
+	rootCmd.PersistentFlags().Uint64Var(&cfg.TraceReplayRangeLimit, utils.TraceReplayRangeLimitFlag.Name, utils.TraceReplayRangeLimitFlag.Value, utils.TraceReplayRangeLimitFlag.Usage)
 
 	rootCmd.PersistentFlags().StringVar(&cfg.RpcAllowListFilePath, utils.RpcAccessListFlag.Name, "", "Specify granular (method-by-method) API allowlist")
+	rootCmd.PersistentFlags().StringVar(&cfg.RpcAuthKeysFilePath, utils.RpcAuthKeysFlag.Name, "", utils.RpcAuthKeysFlag.Usage)
 	rootCmd.PersistentFlags().UintVar(&cfg.RpcBatchConcurrency, utils.RpcBatchConcurrencyFlag.Name, 2, utils.RpcBatchConcurrencyFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.RpcStreamingDisable, utils.RpcStreamingDisableFlag.Name, false, utils.RpcStreamingDisableFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.DebugSingleRequest, utils.HTTPDebugSingleFlag.Name, false, utils.HTTPDebugSingleFlag.Usage)
@@ -100,6 +106,12 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().StringVar(&cfg.TxPoolApiAddr, "txpool.api.addr", "", "txpool api network address, for example: 127.0.0.1:9090 (default: use value of --private.api.addr)")
 
 	rootCmd.PersistentFlags().StringVar(&stateCacheStr, "state.cache", "0MB", "Amount of data to store in StateCache (enabled if no --datadir set). Set 0 to disable StateCache. Defaults to 0MB RAM")
+	rootCmd.PersistentFlags().StringVar(&responseCacheStr, utils.RpcResponseCacheFlag.Name, utils.RpcResponseCacheFlag.Value, utils.RpcResponseCacheFlag.Usage)
+	rootCmd.PersistentFlags().IntVar(&cfg.Overload.MaxInFlight, utils.RpcOverloadInFlightFlag.Name, utils.RpcOverloadInFlightFlag.Value, utils.RpcOverloadInFlightFlag.Usage)
+	rootCmd.PersistentFlags().IntVar(&cfg.Overload.MaxQueued, utils.RpcOverloadQueuedFlag.Name, utils.RpcOverloadQueuedFlag.Value, utils.RpcOverloadQueuedFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&cfg.Overload.MaxLatency, utils.RpcOverloadLatencyFlag.Name, utils.RpcOverloadLatencyFlag.Value, utils.RpcOverloadLatencyFlag.Usage)
+	rootCmd.PersistentFlags().StringSliceVar(&cfg.Overload.ShedMethods, utils.RpcOverloadShedFlag.Name, rpccfg.DefaultOverloadShedMethods, utils.RpcOverloadShedFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&cfg.Overload.RetryAfter, utils.RpcOverloadRetryAfterFlag.Name, utils.RpcOverloadRetryAfterFlag.Value, utils.RpcOverloadRetryAfterFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.GRPCServerEnabled, "grpc", false, "Enable GRPC server")
 	rootCmd.PersistentFlags().StringVar(&cfg.GRPCListenAddress, "grpc.addr", nodecfg.DefaultGRPCHost, "GRPC server listening interface")
 	rootCmd.PersistentFlags().IntVar(&cfg.GRPCPort, "grpc.port", nodecfg.DefaultGRPCPort, "GRPC server listening port")
@@ -142,9 +154,14 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().IntVar(&cfg.ReturnDataLimit, utils.RpcReturnDataLimit.Name, utils.RpcReturnDataLimit.Value, utils.RpcReturnDataLimit.Usage)
 	rootCmd.PersistentFlags().BoolVar(&cfg.AllowUnprotectedTxs, utils.AllowUnprotectedTxs.Name, utils.AllowUnprotectedTxs.Value, utils.AllowUnprotectedTxs.Usage)
 	rootCmd.PersistentFlags().IntVar(&cfg.MaxGetProofRewindBlockCount, utils.RpcMaxGetProofRewindBlockCount.Name, utils.RpcMaxGetProofRewindBlockCount.Value, utils.RpcMaxGetProofRewindBlockCount.Usage)
+	rootCmd.PersistentFlags().IntVar(&cfg.TraceBlockWorkers, utils.RpcTraceBlockWorkersFlag.Name, utils.RpcTraceBlockWorkersFlag.Value, utils.RpcTraceBlockWorkersFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&traceBlockMemoryStr, utils.RpcTraceBlockMemoryFlag.Name, utils.RpcTraceBlockMemoryFlag.Value, utils.RpcTraceBlockMemoryFlag.Usage)
+	rootCmd.PersistentFlags().IntVar(&cfg.LogsPageBudget, utils.RpcLogsPageBudgetFlag.Name, utils.RpcLogsPageBudgetFlag.Value, utils.RpcLogsPageBudgetFlag.Usage)
 	rootCmd.PersistentFlags().Uint64Var(&cfg.OtsMaxPageSize, utils.OtsSearchMaxCapFlag.Name, utils.OtsSearchMaxCapFlag.Value, utils.OtsSearchMaxCapFlag.Usage)
 	rootCmd.PersistentFlags().DurationVar(&cfg.RPCSlowLogThreshold, utils.RPCSlowFlag.Name, utils.RPCSlowFlag.Value, utils.RPCSlowFlag.Usage)
 	rootCmd.PersistentFlags().IntVar(&cfg.WebsocketSubscribeLogsChannelSize, utils.WSSubscribeLogsChannelSize.Name, utils.WSSubscribeLogsChannelSize.Value, utils.WSSubscribeLogsChannelSize.Usage)
+	rootCmd.PersistentFlags().StringVar(&cfg.FiltersPersistDir, "rpc.filters.persist.dir", "", "Directory to persist filters installed by eth_newFilter/eth_newBlockFilter, so they survive restarts and polling clients receive logs/blocks they missed. Disabled if empty")
+	rootCmd.PersistentFlags().DurationVar(&cfg.FiltersPersistExpiry, "rpc.filters.persist.expiry", time.Hour, "Uninstall persisted filters which were not polled for this long. 0 - never")
 
 	if err := rootCmd.MarkPersistentFlagFilename("rpc.accessList", "json"); err != nil {
 		panic(err)
@@ -165,6 +182,14 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 			return fmt.Errorf("state.cache value of %v is not valid", stateCacheStr)
 		}
 
+		if err := cfg.ResponseCacheSize.UnmarshalText([]byte(responseCacheStr)); err != nil {
+			return fmt.Errorf("%s value of %v is not valid", utils.RpcResponseCacheFlag.Name, responseCacheStr)
+		}
+
+		if err := cfg.TraceBlockMemoryBudget.UnmarshalText([]byte(traceBlockMemoryStr)); err != nil {
+			return fmt.Errorf("%s value of %v is not valid", utils.RpcTraceBlockMemoryFlag.Name, traceBlockMemoryStr)
+		}
+
 		cfg.WithDatadir = cfg.DataDir != ""
 		if cfg.WithDatadir {
 			if cfg.DataDir == "" {
@@ -361,6 +386,9 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 			if err != nil {
 				return err
 			}
+			if _, err = statecodec.LoadActive(tx); err != nil {
+				return err
+			}
 			return nil
 		}); err != nil {
 			return nil, nil, nil, nil, nil, nil, nil, ff, nil, err
@@ -446,6 +474,13 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 	}
 
 	if !cfg.WithDatadir {
+		// Erigon may be not started yet - then state is decoded with default codec
+		if err := remoteKv.View(ctx, func(tx kv.Tx) error {
+			_, err := statecodec.LoadActive(tx)
+			return err
+		}); err != nil {
+			logger.Warn("Can't read state codec from remote db", "err", err)
+		}
 		if cfg.StateCache.CacheSize > 0 {
 			stateCache = kvcache.New(cfg.StateCache)
 		} else {
@@ -495,6 +530,9 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 				}
 				// Skip the compatibility check, until we have a schema in erigon-lib
 
+				// finalized/safe block tags follow milestones which erigon persists in bor db
+				borfinality.RegisterDBTracker(borKv)
+
 				borConfig := cc.Bor.(*borcfg.BorConfig)
 
 				engine = bor.NewRo(cc, borKv, blockReader,
@@ -534,12 +572,26 @@ func RemoteServices(ctx context.Context, cfg *httpcfg.HttpCfg, logger log.Logger
 	}()
 
 	ff = rpchelper.New(ctx, eth, txPool, mining, onNewSnapshot, logger)
+	if cfg.FiltersPersistDir != "" {
+		filterStore, err := rpchelper.OpenFilterStore(ctx, cfg.FiltersPersistDir, logger)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, nil, nil, nil, fmt.Errorf("could not open filters store: %w", err)
+		}
+		go func() {
+			<-ctx.Done()
+			filterStore.Close()
+		}()
+		if err = ff.Restore(ctx, filterStore, cfg.FiltersPersistExpiry); err != nil {
+			return nil, nil, nil, nil, nil, nil, nil, nil, nil, fmt.Errorf("could not restore filters: %w", err)
+		}
+	}
 	return db, eth, txPool, mining, stateCache, blockReader, engine, ff, agg, err
 }
 
-func StartRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []rpc.API, logger log.Logger) error {
+// StartRpcServer serves rpcAPI, responseCache may be nil
+func StartRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []rpc.API, responseCache rpc.ResponseCache, logger log.Logger) error {
 	if cfg.Enabled {
-		return startRegularRpcServer(ctx, cfg, rpcAPI, logger)
+		return startRegularRpcServer(ctx, cfg, rpcAPI, responseCache, logger)
 	}
 
 	return nil
@@ -557,7 +609,7 @@ func StartRpcServerWithJwtAuthentication(ctx context.Context, cfg *httpcfg.HttpC
 	return nil
 }
 
-func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []rpc.API, logger log.Logger) error {
+func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []rpc.API, responseCache rpc.ResponseCache, logger log.Logger) error {
 	// register apis and create handler stack
 	srv := rpc.NewServer(cfg.RpcBatchConcurrency, cfg.TraceRequests, cfg.DebugSingleRequest, cfg.RpcStreamingDisable, logger, cfg.RPCSlowLogThreshold)
 
@@ -567,7 +619,28 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 	}
 	srv.SetAllowList(allowListForRPC)
 
+	authorizer, err := parseAuthKeysForRPC(cfg.RpcAuthKeysFilePath)
+	if err != nil {
+		return err
+	}
+	if authorizer != nil {
+		srv.SetAuthorizer(authorizer)
+		logger.Info("RPC API keys authorization enabled", "path", cfg.RpcAuthKeysFilePath)
+	}
+
 	srv.SetBatchLimit(cfg.BatchLimit)
+	registerReloadableRpcLimits(srv, authorizer != nil, cfg)
+
+	if responseCache != nil {
+		srv.SetResponseCache(responseCache)
+		logger.Info("RPC response cache enabled", "size", cfg.ResponseCacheSize)
+	}
+
+	if cfg.Overload.Enabled() {
+		srv.SetOverload(cfg.Overload)
+		logger.Info("RPC overload shedding enabled", "inFlight", cfg.Overload.MaxInFlight, "queued", cfg.Overload.MaxQueued,
+			"latency", cfg.Overload.MaxLatency, "shed", cfg.Overload.ShedMethods)
+	}
 
 	defer srv.Stop()
 
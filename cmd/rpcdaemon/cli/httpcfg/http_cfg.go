@@ -3,6 +3,8 @@ package httpcfg
 import (
 	"time"
 
+	"github.com/c2h5oh/datasize"
+
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
@@ -43,17 +45,20 @@ type HttpCfg struct {
 	API                               []string
 	Gascap                            uint64
 	MaxTraces                         uint64
+	TraceReplayRangeLimit             uint64 // Max blocks of one trace_replayBlockRange call, 0 - unlimited
 	WebsocketPort                     int
 	WebsocketEnabled                  bool
 	WebsocketCompression              bool
 	WebsocketSubscribeLogsChannelSize int
 	RpcAllowListFilePath              string
+	RpcAuthKeysFilePath               string
 	RpcBatchConcurrency               uint
 	RpcStreamingDisable               bool
 	DBReadConcurrency                 int
 	TraceCompatibility                bool // Bug for bug compatibility for trace_ routines with OpenEthereum
 	TxPoolApiAddr                     string
 	StateCache                        kvcache.CoherentConfig
+	ResponseCacheSize                 datasize.ByteSize // Responses of idempotent methods on finalized blocks, disabled if 0
 	Snap                              ethconfig.BlocksFreezing
 	Sync                              ethconfig.Sync
 
@@ -83,8 +88,16 @@ type HttpCfg struct {
 	ReturnDataLimit             int  // Maximum number of bytes returned from calls (like eth_call)
 	AllowUnprotectedTxs         bool // Whether to allow non EIP-155 protected transactions  txs over RPC
 	MaxGetProofRewindBlockCount int  //Max GetProof rewind block count
+
+	TraceBlockWorkers      int               // Goroutines tracing the transactions of one block in debug_traceBlock*, sequential if <= 1
+	TraceBlockMemoryBudget datasize.ByteSize // Max size of per-transaction traces of one debug_traceBlock* call waiting to be streamed
+	LogsPageBudget         int               // Max amount of transactions re-executed by one erigon_getLogsPaged call
 	// Ots API
 	OtsMaxPageSize uint64
 
 	RPCSlowLogThreshold time.Duration
+	Overload            rpccfg.OverloadConfig // Shedding of HTTP calls under overload, disabled if no threshold is set
+
+	FiltersPersistDir    string        // Where eth_newFilter/eth_newBlockFilter filters are kept across restarts, disabled if empty
+	FiltersPersistExpiry time.Duration // Persisted filters not polled for this long are uninstalled, 0 - never
 }
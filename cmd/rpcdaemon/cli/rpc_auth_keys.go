@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+// parseAuthKeysForRPC reads API keys file, for example:
+//
+//	{
+//	  "jwtSecret": "0x...",
+//	  "keys": [
+//	    {"name": "A", "key": "...", "allow": ["eth", "trace"], "rateLimit": 100},
+//	    {"name": "B", "key": "...", "allow": ["eth_call", "eth_blockNumber"], "rateLimit": 10, "burst": 20}
+//	  ]
+//	}
+func parseAuthKeysForRPC(path string) (*rpc.Authorizer, error) {
+	path = strings.TrimSpace(path)
+	if path == "" { // no file is provided
+		return nil, nil
+	}
+
+	fileContents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg rpc.AuthConfig
+	if err := json.Unmarshal(fileContents, &cfg); err != nil {
+		return nil, err
+	}
+
+	return rpc.NewAuthorizer(cfg)
+}
+
+// registerReloadableRpcLimits - API keys with their rate limits (the file is read again on every reload) and batch limit
+// can be changed at runtime. Authorization can't be enabled or disabled at runtime.
+func registerReloadableRpcLimits(srv *rpc.Server, authEnabled bool, cfg *httpcfg.HttpCfg) {
+	if authEnabled {
+		debug.RegisterReloadable(utils.RpcAuthKeysFlag.Name, cfg.RpcAuthKeysFilePath, func(path string) error {
+			authorizer, err := parseAuthKeysForRPC(path)
+			if err != nil {
+				return err
+			}
+			if authorizer == nil {
+				return errors.New("API keys authorization can't be disabled at runtime")
+			}
+			srv.SetAuthorizer(authorizer)
+			return nil
+		})
+	}
+	debug.RegisterReloadable(utils.RpcBatchLimit.Name, strconv.Itoa(cfg.BatchLimit), func(value string) error {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		srv.SetBatchLimit(limit)
+		return nil
+	})
+}
@@ -33,9 +33,10 @@ func main() {
 		defer db.Close()
 		defer engine.Close()
 
-		apiList := jsonrpc.APIList(db, backend, txPool, mining, ff, stateCache, blockReader, agg, cfg, engine, logger)
+		apiList := jsonrpc.APIList(db, backend, txPool, mining, ff, stateCache, blockReader, agg, cfg, engine, nil, logger)
 		rpc.PreAllocateRPCMetricLabels(apiList)
-		if err := cli.StartRpcServer(ctx, cfg, apiList, logger); err != nil {
+		responseCache := jsonrpc.NewResponseCache(db, blockReader, cfg.ResponseCacheSize)
+		if err := cli.StartRpcServer(ctx, cfg, apiList, responseCache, logger); err != nil {
 			logger.Error(err.Error())
 			return nil
 		}
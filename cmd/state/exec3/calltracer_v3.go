@@ -9,6 +9,15 @@ import (
 type CallTracer struct {
 	froms map[libcommon.Address]struct{}
 	tos   map[libcommon.Address]struct{}
+
+	// contract creations and self-destructs of tx (address -> deployer/beneficiary), reverted ones are dropped
+	creations     map[libcommon.Address]libcommon.Address
+	selfDestructs map[libcommon.Address]libcommon.Address
+	frames        []callFrame
+}
+
+type callFrame struct {
+	created, selfDestructed []libcommon.Address
 }
 
 func NewCallTracer() *CallTracer {
@@ -16,9 +25,59 @@ func NewCallTracer() *CallTracer {
 }
 func (ct *CallTracer) Reset() {
 	ct.froms, ct.tos = nil, nil
+	ct.creations, ct.selfDestructs, ct.frames = nil, nil, ct.frames[:0]
 }
 func (ct *CallTracer) Froms() map[libcommon.Address]struct{} { return ct.froms }
 func (ct *CallTracer) Tos() map[libcommon.Address]struct{}   { return ct.tos }
+func (ct *CallTracer) Creations() map[libcommon.Address]libcommon.Address {
+	return ct.creations
+}
+func (ct *CallTracer) SelfDestructs() map[libcommon.Address]libcommon.Address {
+	return ct.selfDestructs
+}
+
+func (ct *CallTracer) enter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, create bool) {
+	var frame callFrame
+	if create {
+		if ct.creations == nil {
+			ct.creations = map[libcommon.Address]libcommon.Address{}
+		}
+		ct.creations[to] = from
+		frame.created = append(frame.created, to)
+	}
+	if typ == vm.SELFDESTRUCT {
+		if ct.selfDestructs == nil {
+			ct.selfDestructs = map[libcommon.Address]libcommon.Address{}
+		}
+		ct.selfDestructs[from] = to
+		frame.selfDestructed = append(frame.selfDestructed, from)
+	}
+	ct.frames = append(ct.frames, frame)
+}
+
+// exit - pops the frame, creations and self-destructs of reverted frame (and its sub-calls) are dropped,
+// otherwise they are handed over to the parent frame
+func (ct *CallTracer) exit(err error) {
+	if len(ct.frames) == 0 {
+		return
+	}
+	frame := ct.frames[len(ct.frames)-1]
+	ct.frames = ct.frames[:len(ct.frames)-1]
+	if err != nil {
+		for _, addr := range frame.created {
+			delete(ct.creations, addr)
+		}
+		for _, addr := range frame.selfDestructed {
+			delete(ct.selfDestructs, addr)
+		}
+		return
+	}
+	if len(ct.frames) > 0 {
+		parent := &ct.frames[len(ct.frames)-1]
+		parent.created = append(parent.created, frame.created...)
+		parent.selfDestructed = append(parent.selfDestructed, frame.selfDestructed...)
+	}
+}
 
 func (ct *CallTracer) CaptureTxStart(gasLimit uint64) {}
 func (ct *CallTracer) CaptureTxEnd(restGas uint64)    {}
@@ -28,6 +87,7 @@ func (ct *CallTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libco
 		ct.tos = map[libcommon.Address]struct{}{}
 	}
 	ct.froms[from], ct.tos[to] = struct{}{}, struct{}{}
+	ct.enter(vm.CALL, from, to, create)
 }
 func (ct *CallTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
 	if ct.froms == nil {
@@ -35,12 +95,15 @@ func (ct *CallTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to lib
 		ct.tos = map[libcommon.Address]struct{}{}
 	}
 	ct.froms[from], ct.tos[to] = struct{}{}, struct{}{}
+	ct.enter(typ, from, to, create)
 }
 func (ct *CallTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
 }
 func (ct *CallTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
 }
 func (ct *CallTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	ct.exit(err)
 }
 func (ct *CallTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	ct.exit(err)
 }
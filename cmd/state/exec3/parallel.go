@@ -0,0 +1,123 @@
+package exec3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/dbg"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// ErrNeedsReExec - result of optimistic execution can't be applied as write-set, tx must be re-executed sequentially
+var ErrNeedsReExec = errors.New("tx needs sequential re-execution")
+
+// ParallelExecutor - optimistic (Block-STM like) execution of block's txs:
+//   - all txs of block are executed concurrently on top of state before block. Each worker has own RoTx,
+//     records read-set (values tx did read) and buffers write-set (values tx did write)
+//   - then caller applies results in txs order: if read-set is still valid (none of previous txs changed values
+//     which tx did read - see StateV3.ReadsValid) - write-set is applied as-is, otherwise tx is re-executed sequentially
+//
+// So result is deterministic and equal to sequential execution. Txs conflict only if one reads what previous one
+// did write (same sender, call of contract which previous tx did change, etc...). Fees paid by previous txs are not
+// such a dependency: fee credit doesn't read coinbase (unless tx reads it by itself) - it's applied by
+// StateV3.ApplyState4 as balance increase on top of coinbase balance at that time. Bor fee transfer log does need
+// coinbase balance before tx: it's filled by SettleDeferredFees right before apply (see vm.Config.DeferFeeCredit).
+// Workers read SharedDomains without locks: nobody must write to them while Execute is running.
+// Workers read values which are not in SharedDomains RAM by own RoTx: must be reset (by ResetState) after each commit.
+type ParallelExecutor struct {
+	workers []*Worker
+	logger  log.Logger
+}
+
+func NewParallelExecutor(ctx context.Context, workerCount int, chainDb kv.RoDB, rs *state.StateV3, blockReader services.FullBlockReader, chainConfig *chain.Config, genesis *types.Genesis, engine consensus.Engine, dirs datadir.Dirs, logger log.Logger) *ParallelExecutor {
+	pe := &ParallelExecutor{workers: make([]*Worker, workerCount), logger: logger}
+	for i := range pe.workers {
+		w := NewWorker(&sync.Mutex{}, logger, nil, ctx, true, chainDb, rs, nil, blockReader, chainConfig, genesis, nil, engine, dirs)
+		w.parallel = true
+		w.vmCfg.DeferFeeCredit = true
+		w.ResetState(rs, nil)
+		pe.workers[i] = w
+	}
+	return pe
+}
+
+// Execute - runs txTasks concurrently. Results are in txTasks: caller must validate them before apply
+func (pe *ParallelExecutor) Execute(ctx context.Context, txTasks []*state.TxTask) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for _, w := range pe.workers {
+		wg.Add(1)
+		go func(w *Worker) {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < len(txTasks); i = int(next.Add(1) - 1) {
+				if ctx.Err() != nil {
+					txTasks[i].Error = ctx.Err()
+					continue
+				}
+				pe.run(w, txTasks[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func (pe *ParallelExecutor) run(w *Worker, txTask *state.TxTask) {
+	defer func() {
+		// tx executed on top of inconsistent state may do unexpected things: it's not fatal - just re-execute it sequentially
+		if rec := recover(); rec != nil {
+			pe.logger.Debug("[exec3] optimistic execution panic", "txNum", txTask.TxNum, "err", rec, "stack", dbg.Stack())
+			txTask.Error = fmt.Errorf("%w: panic: %v", ErrNeedsReExec, rec)
+		}
+	}()
+	w.RunTxTaskNoLock(txTask)
+}
+
+// SettleDeferredFees - completes optimistic result of txTask with values which depend on coinbase balance before tx
+// (see vm.Config.DeferFeeCredit). Must be called in txs order: right before result of txTask is applied
+func SettleDeferredFees(rs *state.StateV3, txTask *state.TxTask) error {
+	if txTask.FeeTransferLog == nil {
+		return nil
+	}
+	coinbase := txTask.EvmBlockContext.Coinbase
+	enc, _, err := rs.Domains().DomainGet(kv.AccountsDomain, coinbase[:], nil)
+	if err != nil {
+		return err
+	}
+	var acc accounts.Account
+	if len(enc) > 0 {
+		if err := accounts.DeserialiseV3(&acc, enc); err != nil {
+			return err
+		}
+	}
+	core.SetFeeTransferLogRecipientBalance(txTask.FeeTransferLog, &acc.Balance)
+	txTask.FeeTransferLog = nil
+	return nil
+}
+
+// ResetState - must be called after SharedDomains re-created (for example after commit): it also closes RoTx of workers
+func (pe *ParallelExecutor) ResetState(rs *state.StateV3) {
+	for _, w := range pe.workers {
+		w.ResetTx(nil)
+		w.ResetState(rs, nil)
+	}
+}
+
+func (pe *ParallelExecutor) Close() {
+	for _, w := range pe.workers {
+		w.ResetTx(nil)
+	}
+}
@@ -0,0 +1,215 @@
+package exec3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal/temporaltest"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+type testTxResult struct {
+	usedGas uint64
+	failed  bool
+}
+
+type testBlockResult struct {
+	root     []byte
+	coinbase uint256.Int
+	txs      []testTxResult
+	reExecs  int
+}
+
+// execTestBlock - executes genesis and given block, txs of block are executed by ParallelExecutor if workers > 0
+func execTestBlock(t *testing.T, genesis *types.Genesis, header *types.Header, txs types.Transactions, workers int) testBlockResult {
+	t.Helper()
+	ctx, logger := context.Background(), log.New()
+	dirs := datadir.New(t.TempDir())
+	db, _ := temporaltest.NewTestDB(t, dirs)
+	engine := ethash.NewFaker()
+	chainConfig := genesis.Config
+
+	open := func() (kv.RwTx, *libstate.SharedDomains, *state.StateV3) {
+		tx, err := db.BeginRw(ctx)
+		require.NoError(t, err)
+		t.Cleanup(tx.Rollback)
+		domains, err := libstate.NewSharedDomains(tx, logger)
+		require.NoError(t, err)
+		t.Cleanup(domains.Close)
+		return tx, domains, state.NewStateV3(domains, logger)
+	}
+	apply := func(rs *state.StateV3, txTask *state.TxTask) {
+		require.NoError(t, txTask.Error)
+		require.NoError(t, rs.ApplyState4(ctx, txTask))
+	}
+
+	// genesis: committed, so parallel workers can see it by own RoTx
+	tx, domains, rs := open()
+	applyWorker := NewWorker(&sync.Mutex{}, logger, nil, ctx, false, db, rs, nil, nil, chainConfig, genesis, nil, engine, dirs)
+	applyWorker.ResetTx(tx)
+	genesisRules := chainConfig.Rules(0, 0)
+	for txIndex := -1; txIndex <= 0; txIndex++ {
+		txTask := &state.TxTask{TxNum: uint64(txIndex + 1), TxIndex: txIndex, Final: txIndex == 0, Rules: genesisRules}
+		domains.SetTxNum(txTask.TxNum)
+		applyWorker.RunTxTaskNoLock(txTask)
+		apply(rs, txTask)
+	}
+	_, err := domains.ComputeCommitment(ctx, true, 0, "")
+	require.NoError(t, err)
+	require.NoError(t, domains.Flush(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	tx, domains, rs = open()
+	applyWorker.ResetTx(tx)
+	applyWorker.ResetState(rs, nil)
+	domains.SetBlockNum(1)
+
+	rules := chainConfig.Rules(header.Number.Uint64(), header.Time)
+	signer := types.MakeSigner(chainConfig, header.Number.Uint64(), header.Time)
+	blockContext := core.NewEVMBlockContext(header, func(n uint64) libcommon.Hash { return libcommon.Hash{} }, engine, nil)
+	blockHash := header.Hash()
+	newTxTask := func(txIndex int) *state.TxTask {
+		txTask := &state.TxTask{
+			BlockNum:        1,
+			Header:          header,
+			Coinbase:        header.Coinbase,
+			Rules:           rules,
+			Txs:             txs,
+			TxNum:           uint64(txIndex + 3), // genesis block takes 2 txNums
+			TxIndex:         txIndex,
+			BlockHash:       blockHash,
+			Final:           txIndex == len(txs),
+			EvmBlockContext: blockContext,
+		}
+		if txIndex >= 0 && txIndex < len(txs) {
+			txTask.Tx = txs[txIndex]
+			txTask.TxAsMessage, err = txTask.Tx.AsMessage(*signer, header.BaseFee, rules)
+			require.NoError(t, err)
+			sender, err := signer.Sender(txTask.Tx)
+			require.NoError(t, err)
+			txTask.Sender = &sender
+		}
+		return txTask
+	}
+
+	var speculated []*state.TxTask
+	if workers > 0 {
+		pe := NewParallelExecutor(ctx, workers, db, rs, nil, chainConfig, genesis, engine, dirs, logger)
+		defer pe.Close()
+		speculated = make([]*state.TxTask, len(txs))
+		for i := range txs {
+			speculated[i] = newTxTask(i)
+		}
+		pe.Execute(ctx, speculated)
+	}
+
+	res := testBlockResult{}
+	for txIndex := -1; txIndex <= len(txs); txIndex++ {
+		var txTask *state.TxTask
+		optimistic := speculated != nil && txIndex >= 0 && txIndex < len(txs)
+		if optimistic {
+			txTask = speculated[txIndex]
+		} else {
+			txTask = newTxTask(txIndex)
+		}
+		domains.SetTxNum(txTask.TxNum)
+		if optimistic && (txTask.Error != nil || !rs.ReadsValid(txTask.ReadLists)) {
+			res.reExecs++
+			txTask.Reset()
+			txTask.Error = nil
+			optimistic = false
+		}
+		if optimistic {
+			require.NoError(t, SettleDeferredFees(rs, txTask))
+		} else {
+			applyWorker.RunTxTaskNoLock(txTask)
+		}
+		if txTask.Tx != nil {
+			res.txs = append(res.txs, testTxResult{usedGas: txTask.UsedGas, failed: txTask.Failed})
+		}
+		apply(rs, txTask)
+	}
+
+	res.root, err = domains.ComputeCommitment(ctx, true, 1, "")
+	require.NoError(t, err)
+	enc, _, err := domains.DomainGet(kv.AccountsDomain, header.Coinbase[:], nil)
+	require.NoError(t, err)
+	var acc accounts.Account
+	require.NoError(t, accounts.DeserialiseV3(&acc, enc))
+	res.coinbase = acc.Balance
+	return res
+}
+
+func TestParallelExecutorMatchesSequential(t *testing.T) {
+	t.Parallel()
+	cc := *params.TestChainConfig
+	cc.LondonBlock = big.NewInt(0)
+	chainConfig := &cc
+	signer := types.LatestSignerForChainID(chainConfig.ChainID)
+	coinbase := libcommon.HexToAddress("0xc0ffee")
+	gasPrice := uint256.NewInt(params.GWei)
+
+	keys := make([]*ecdsa.PrivateKey, 8)
+	alloc := types.GenesisAlloc{}
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = types.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	genesis := &types.Genesis{Config: chainConfig, Alloc: alloc, GasLimit: 30_000_000, Difficulty: big.NewInt(1)}
+
+	var txs types.Transactions
+	transfer := func(from int, nonce uint64, to libcommon.Address) {
+		txn, err := types.SignTx(types.NewTransaction(nonce, to, uint256.NewInt(1000), params.TxGas, gasPrice, nil), *signer, keys[from])
+		require.NoError(t, err)
+		txs = append(txs, txn)
+	}
+	// independent transfers: each one pays fee to coinbase, but they must not conflict because of it
+	for i := 0; i < 6; i++ {
+		transfer(i, 0, libcommon.BytesToAddress([]byte{0xaa, byte(i)}))
+	}
+	transfer(0, 1, libcommon.HexToAddress("0xbb")) // depends on sender's nonce: conflicts with tx 0
+	transfer(6, 0, coinbase)                       // reads coinbase: conflicts with fees of previous txs
+	transfer(7, 0, libcommon.HexToAddress("0xcc"))
+
+	header := &types.Header{
+		ParentHash: libcommon.Hash{1},
+		Coinbase:   coinbase,
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		GasLimit:   genesis.GasLimit,
+		Time:       10,
+		BaseFee:    big.NewInt(params.GWei / 2),
+	}
+
+	sequential := execTestBlock(t, genesis, header, txs, 0)
+	parallel := execTestBlock(t, genesis, header, txs, 4)
+
+	require.Equal(t, sequential.root, parallel.root)
+	require.Equal(t, sequential.txs, parallel.txs)
+	require.Equal(t, sequential.coinbase, parallel.coinbase)
+	require.Equal(t, 2, parallel.reExecs)
+
+	// block reward + fees of all txs + transfer to coinbase
+	expect := uint256.NewInt(2 * params.Ether)
+	expect.Add(expect, new(uint256.Int).Mul(uint256.NewInt(params.GWei/2), uint256.NewInt(params.TxGas*uint64(len(txs)))))
+	expect.Add(expect, uint256.NewInt(1000))
+	require.Equal(t, expect.String(), parallel.coinbase.String())
+}
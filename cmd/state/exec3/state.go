@@ -33,9 +33,10 @@ type Worker struct {
 	blockReader services.FullBlockReader
 	in          *state.QueueWithRetry
 	rs          *state.StateV3
-	stateWriter *state.StateWriterV3
+	stateWriter state.ResettableStateWriter
 	stateReader state.ResettableStateReader
 	historyMode bool // if true - stateReader is HistoryReaderV3, otherwise it's state reader
+	parallel    bool // if true - worker executes txs optimistically: reads by own RoTx, doesn't write to SharedDomains
 	chainConfig *chain.Config
 
 	ctx      context.Context
@@ -86,6 +87,11 @@ func NewWorker(lock sync.Locker, logger log.Logger, accumulator *shards.Accumula
 
 func (rw *Worker) ResetState(rs *state.StateV3, accumulator *shards.Accumulator) {
 	rw.rs = rs
+	if rw.parallel {
+		rw.SetReader(state.NewStateReaderParallelV3(rs.Domains()))
+		rw.stateWriter = state.NewStateWriterParallelV3()
+		return
+	}
 	rw.SetReader(state.NewStateReaderV3(rs.Domains()))
 	rw.stateWriter = state.NewStateWriterV3(rs, accumulator)
 }
@@ -147,7 +153,11 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask) {
 		// Needed to correctly evaluate spent gas and other things.
 		rw.SetReader(state.NewHistoryReaderV3())
 	} else if !txTask.HistoryExecution && rw.historyMode {
-		rw.SetReader(state.NewStateReaderV3(rw.rs.Domains()))
+		if rw.parallel {
+			rw.SetReader(state.NewStateReaderParallelV3(rw.rs.Domains()))
+		} else {
+			rw.SetReader(state.NewStateReaderV3(rw.rs.Domains()))
+		}
 	}
 	if rw.background && rw.chainTx == nil {
 		var err error
@@ -249,8 +259,13 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask) {
 			ibs.SoftFinalise()
 			//txTask.Error = ibs.FinalizeTx(rules, noop)
 			txTask.Logs = ibs.GetLogs(txHash)
+			if rw.vmCfg.DeferFeeCredit && rw.chainConfig.Bor != nil {
+				txTask.FeeTransferLog = core.FeeTransferLog(txTask.Logs)
+			}
 			txTask.TraceFroms = rw.callTracer.Froms()
 			txTask.TraceTos = rw.callTracer.Tos()
+			txTask.Creations = rw.callTracer.Creations()
+			txTask.SelfDestructs = rw.callTracer.SelfDestructs()
 		}
 
 	}
@@ -266,6 +281,9 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask) {
 		txTask.ReadLists = rw.stateReader.ReadSet()
 		txTask.WriteLists = rw.stateWriter.WriteSet()
 		txTask.AccountPrevs, txTask.AccountDels, txTask.StoragePrevs, txTask.CodePrevs = rw.stateWriter.PrevAndDels()
+		if w, ok := rw.stateWriter.(*state.StateWriterParallelV3); ok && w.NeedsReExec() {
+			txTask.Error = ErrNeedsReExec
+		}
 	}
 }
 
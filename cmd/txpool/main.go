@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
 	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentryproto"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon-lib/kv/remotedb"
 	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
@@ -22,6 +23,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpooluitl"
 	"github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	common2 "github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/consensus/misc"
@@ -57,6 +59,18 @@ var (
 	priceBump          uint64
 	blobPriceBump      uint64
 
+	senderRateLimit float64
+	globalRateLimit float64
+
+	localsLifetime    time.Duration
+	localsRebroadcast time.Duration
+
+	replacementPolicy string
+	maxReplacements   uint64
+
+	prioritySenders  []string
+	priorityGasLimit uint64
+
 	noTxGossip bool
 
 	commitEvery time.Duration
@@ -84,9 +98,17 @@ func init() {
 	rootCmd.PersistentFlags().Uint64Var(&totalBlobPoolLimit, "txpool.totalblobpoollimit", txpoolcfg.DefaultConfig.TotalBlobPoolLimit, "Total limit of number of all blobs in txs within the txpool")
 	rootCmd.PersistentFlags().Uint64Var(&priceBump, "txpool.pricebump", txpoolcfg.DefaultConfig.PriceBump, "Price bump percentage to replace an already existing transaction")
 	rootCmd.PersistentFlags().Uint64Var(&blobPriceBump, "txpool.blobpricebump", txpoolcfg.DefaultConfig.BlobPriceBump, "Price bump percentage to replace an existing blob (type-3) transaction")
+	rootCmd.PersistentFlags().Float64Var(&senderRateLimit, utils.TxPoolSenderRateLimitFlag.Name, utils.TxPoolSenderRateLimitFlag.Value, utils.TxPoolSenderRateLimitFlag.Usage)
+	rootCmd.PersistentFlags().Float64Var(&globalRateLimit, utils.TxPoolGlobalRateLimitFlag.Name, utils.TxPoolGlobalRateLimitFlag.Value, utils.TxPoolGlobalRateLimitFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&localsLifetime, utils.TxPoolLocalsLifetimeFlag.Name, utils.TxPoolLocalsLifetimeFlag.Value, utils.TxPoolLocalsLifetimeFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&localsRebroadcast, utils.TxPoolLocalsRebroadcastFlag.Name, utils.TxPoolLocalsRebroadcastFlag.Value, utils.TxPoolLocalsRebroadcastFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&replacementPolicy, utils.TxPoolReplacementPolicyFlag.Name, utils.TxPoolReplacementPolicyFlag.Value, utils.TxPoolReplacementPolicyFlag.Usage)
+	rootCmd.PersistentFlags().Uint64Var(&maxReplacements, utils.TxPoolMaxReplacementsFlag.Name, utils.TxPoolMaxReplacementsFlag.Value, utils.TxPoolMaxReplacementsFlag.Usage)
 	rootCmd.PersistentFlags().DurationVar(&commitEvery, utils.TxPoolCommitEveryFlag.Name, utils.TxPoolCommitEveryFlag.Value, utils.TxPoolCommitEveryFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
 	rootCmd.Flags().StringSliceVar(&traceSenders, utils.TxPoolTraceSendersFlag.Name, []string{}, utils.TxPoolTraceSendersFlag.Usage)
+	rootCmd.Flags().StringSliceVar(&prioritySenders, utils.TxPoolPrioritySendersFlag.Name, []string{}, utils.TxPoolPrioritySendersFlag.Usage)
+	rootCmd.PersistentFlags().Uint64Var(&priorityGasLimit, utils.TxPoolPriorityGasLimitFlag.Name, utils.TxPoolPriorityGasLimitFlag.Value, utils.TxPoolPriorityGasLimitFlag.Usage)
 }
 
 var rootCmd = &cobra.Command{
@@ -121,6 +143,12 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	if err != nil {
 		return fmt.Errorf("could not connect to remoteKv: %w", err)
 	}
+	if err := coreDB.View(ctx, func(tx kv.Tx) error {
+		_, err := statecodec.LoadActive(tx)
+		return err
+	}); err != nil {
+		logger.Warn("Can't read state codec from remote db", "err", err)
+	}
 
 	log.Info("TxPool started", "db", filepath.Join(datadirCli, "txpool"))
 
@@ -154,6 +182,12 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 	cfg.PriceBump = priceBump
 	cfg.BlobPriceBump = blobPriceBump
 	cfg.NoGossip = noTxGossip
+	cfg.SenderRateLimit = senderRateLimit
+	cfg.GlobalRateLimit = globalRateLimit
+	cfg.LocalsLifetime = localsLifetime
+	cfg.RebroadcastLocalsEvery = localsRebroadcast
+	cfg.ReplacementPolicy = replacementPolicy
+	cfg.MaxReplacements = maxReplacements
 
 	cacheConfig := kvcache.DefaultCoherentConfig
 	cacheConfig.MetricsLabel = "txpool"
@@ -163,6 +197,11 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 		sender := common.HexToAddress(senderHex)
 		cfg.TracedSenders[i] = string(sender[:])
 	}
+	cfg.PrioritySenders = make([]common.Address, len(prioritySenders))
+	for i, senderHex := range prioritySenders {
+		cfg.PrioritySenders[i] = common.HexToAddress(senderHex)
+	}
+	cfg.PriorityLaneGasLimit = priorityGasLimit
 
 	newTxs := make(chan types.Announcements, 1024)
 	defer close(newTxs)
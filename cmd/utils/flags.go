@@ -52,6 +52,7 @@ import (
 	"github.com/ledgerwatch/erigon/consensus/ethash/ethashcfg"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/aapool/aapoolcfg"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/eth/gasprice/gaspricecfg"
 	"github.com/ledgerwatch/erigon/node/nodecfg"
@@ -175,6 +176,35 @@ var (
 		Usage: "Price bump percentage to replace existing (type-3) blob transaction",
 		Value: txpoolcfg.DefaultConfig.BlobPriceBump,
 	}
+	TxPoolReplacementPolicyFlag = cli.StringFlag{
+		Name:  "txpool.replacement.policy",
+		Usage: "Rule to replace an already existing transaction with the same sender and nonce: 'pricebump' - bumped by --txpool.pricebump percents, 'bor' - same plus at most --txpool.replacement.max replacements per nonce",
+		Value: txpoolcfg.DefaultConfig.ReplacementPolicy,
+	}
+	TxPoolMaxReplacementsFlag = cli.Uint64Flag{
+		Name:  "txpool.replacement.max",
+		Usage: "Max amount of replacements of a transaction with the same sender and nonce, for 'bor' replacement policy (0 - unlimited)",
+		Value: txpoolcfg.DefaultConfig.MaxReplacements,
+	}
+	TxPoolAAFlag = cli.BoolFlag{
+		Name:  "txpool.aa",
+		Usage: "Experimental: enables account abstraction lane - user operations sent by eth_sendUserOperation are validated by EntryPoint.simulateValidation and bundled by the block producer into a handleOps transaction signed by the miner key",
+	}
+	TxPoolAAEntryPointFlag = cli.StringFlag{
+		Name:  "txpool.aa.entrypoint",
+		Usage: "Address of ERC-4337 v0.6 EntryPoint contract used by account abstraction lane",
+		Value: aapoolcfg.DefaultConfig.EntryPoint.Hex(),
+	}
+	TxPoolAAMaxOpsFlag = cli.IntFlag{
+		Name:  "txpool.aa.maxops",
+		Usage: "Max amount of user operations in account abstraction lane",
+		Value: aapoolcfg.DefaultConfig.MaxOps,
+	}
+	TxPoolAABundleSizeFlag = cli.IntFlag{
+		Name:  "txpool.aa.bundlesize",
+		Usage: "Max amount of user operations bundled into a block",
+		Value: aapoolcfg.DefaultConfig.BundleSize,
+	}
 	TxPoolAccountSlotsFlag = cli.Uint64Flag{
 		Name:  "txpool.accountslots",
 		Usage: "Minimum number of executable transaction slots guaranteed per account",
@@ -220,11 +250,41 @@ var (
 		Usage: "Comma separated list of addresses, whose transactions will traced in transaction pool with debug printing",
 		Value: "",
 	}
+	TxPoolPrioritySendersFlag = cli.StringFlag{
+		Name:  "txpool.priority.senders",
+		Usage: "Comma separated list of addresses, whose transactions bypass pool limits and are included into blocks first (e.g. validator operational transactions)",
+		Value: "",
+	}
+	TxPoolPriorityGasLimitFlag = cli.Uint64Flag{
+		Name:  "txpool.priority.gaslimit",
+		Usage: "Max gas of priority senders transactions included first into a block, by transactions gas limits (0 - unlimited)",
+		Value: txpoolcfg.DefaultConfig.PriorityLaneGasLimit,
+	}
 	TxPoolCommitEveryFlag = cli.DurationFlag{
 		Name:  "txpool.commit.every",
 		Usage: "How often transactions should be committed to the storage",
 		Value: txpoolcfg.DefaultConfig.CommitEvery,
 	}
+	TxPoolSenderRateLimitFlag = cli.Float64Flag{
+		Name:  "txpool.ratelimit.sender",
+		Usage: "Max amount of new remote transactions per second accepted from one sender (0 - unlimited)",
+		Value: txpoolcfg.DefaultConfig.SenderRateLimit,
+	}
+	TxPoolGlobalRateLimitFlag = cli.Float64Flag{
+		Name:  "txpool.ratelimit.global",
+		Usage: "Max amount of new remote transactions per second accepted from all senders (0 - unlimited)",
+		Value: txpoolcfg.DefaultConfig.GlobalRateLimit,
+	}
+	TxPoolLocalsLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.locals.lifetime",
+		Usage: "How long transactions submitted to this node are journaled, re-broadcast and exempt from eviction (0 - until inclusion)",
+		Value: txpoolcfg.DefaultConfig.LocalsLifetime,
+	}
+	TxPoolLocalsRebroadcastFlag = cli.DurationFlag{
+		Name:  "txpool.locals.rebroadcast",
+		Usage: "How often journaled local transactions are re-broadcast to peers (0 - disabled)",
+		Value: txpoolcfg.DefaultConfig.RebroadcastLocalsEvery,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -349,6 +409,11 @@ var (
 		Usage: "Path to the token that ensures safe connection between CL and EL",
 		Value: "",
 	}
+	AuthRpcJournalSizeFlag = cli.StringFlag{
+		Name:  "authrpc.journal.size",
+		Usage: "Max disk size of the journal of Engine API forkchoiceUpdated/newPayload/getPayload calls (in datadir/engine-journal), it's served by debug_engineJournal on the Engine API port. 0 disables the journal",
+		Value: (64 * datasize.MB).String(),
+	}
 
 	HttpCompressionFlag = cli.BoolFlag{
 		Name:  "http.compression",
@@ -414,6 +479,40 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	RpcAuthKeysFlag = cli.StringFlag{
+		Name:  "rpc.auth.keys",
+		Usage: "Path to JSON file with API keys (and optional jwtSecret), requires every HTTP/WS client to present a key and limits it to allowed namespaces/methods and rate",
+	}
+	RpcResponseCacheFlag = cli.StringFlag{
+		Name:  "rpc.responsecache",
+		Usage: "Amount of responses of eth_getBlockByNumber, eth_getTransactionReceipt and trace_block on finalized blocks to cache for HTTP clients. Set 0 to disable",
+		Value: "0MB",
+	}
+	RpcOverloadInFlightFlag = cli.IntFlag{
+		Name:  "rpc.overload.inflight",
+		Usage: "HTTP calls executed at once above which the --rpc.overload.shed methods are rejected with 429 (twice above - all but the cheapest methods). Set 0 to disable",
+		Value: 0,
+	}
+	RpcOverloadQueuedFlag = cli.IntFlag{
+		Name:  "rpc.overload.queued",
+		Usage: "HTTP calls waiting to be executed above which the --rpc.overload.shed methods are rejected with 429 (twice above - all but the cheapest methods). Set 0 to disable",
+		Value: 0,
+	}
+	RpcOverloadLatencyFlag = cli.DurationFlag{
+		Name:  "rpc.overload.latency",
+		Usage: "Average duration of the HTTP calls, except the --rpc.overload.shed ones, above which the --rpc.overload.shed methods are rejected with 429 (twice above - all but the cheapest methods). Set 0 to disable",
+		Value: 0,
+	}
+	RpcOverloadShedFlag = cli.StringFlag{
+		Name:  "rpc.overload.shed",
+		Usage: "Comma separated prefixes of the expensive methods rejected first when the server is overloaded",
+		Value: strings.Join(rpccfg.DefaultOverloadShedMethods, ","),
+	}
+	RpcOverloadRetryAfterFlag = cli.DurationFlag{
+		Name:  "rpc.overload.retryafter",
+		Usage: "Retry-After sent to the clients of which calls were rejected because the server is overloaded",
+		Value: rpccfg.DefaultOverloadRetryAfter,
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -435,6 +534,11 @@ var (
 		Usage: "Sets a limit on traces that can be returned in trace_filter",
 		Value: 200,
 	}
+	TraceReplayRangeLimitFlag = cli.Uint64Flag{
+		Name:  "trace.replayrange.limit",
+		Usage: "Max amount of blocks replayed by one trace_replayBlockRange call. Set 0 for unlimited",
+		Value: 1000,
+	}
 
 	HTTPPathPrefixFlag = cli.StringFlag{
 		Name:  "http.rpcprefix",
@@ -515,6 +619,21 @@ var (
 		Usage: "Max GetProof rewind block count",
 		Value: 100_000,
 	}
+	RpcTraceBlockWorkersFlag = cli.IntFlag{
+		Name:  "rpc.traceblock.workers",
+		Usage: "Amount of goroutines tracing the transactions of a block in parallel in debug_traceBlockByNumber/debug_traceBlockByHash, each opening its own db read transaction. Set 1 to trace sequentially",
+		Value: 4,
+	}
+	RpcTraceBlockMemoryFlag = cli.StringFlag{
+		Name:  "rpc.traceblock.memory",
+		Usage: "Max size of transaction traces of one debug_traceBlockByNumber/debug_traceBlockByHash call that are ready but not yet streamed to the client. Set 0 for unbounded",
+		Value: "256MB",
+	}
+	RpcLogsPageBudgetFlag = cli.IntFlag{
+		Name:  "rpc.logs.pagebudget",
+		Usage: "Max amount of transactions re-executed by one erigon_getLogsPaged call, the logs of the following ones are returned by the next calls with the continuation",
+		Value: 10_000,
+	}
 	StateCacheFlag = cli.StringFlag{
 		Name:  "state.cache",
 		Value: "0MB",
@@ -687,6 +806,21 @@ var (
 		Name:  ethconfig.FlagSnapStop,
 		Usage: "Workaround to stop producing new snapshots, if you meet some snapshots-related critical bug. It will stop move historical data from DB to new immutable snapshots. DB will grow and may slightly slow-down - and removing this flag in future will not fix this effect (db size will not greatly reduce).",
 	}
+	SnapIndexWorkersFlag = cli.IntFlag{
+		Name:  ethconfig.FlagSnapIndexWorkers,
+		Usage: "Amount of workers building missed snapshot indices in parallel. 0 - estimate by available RAM and CPUs",
+		Value: 0,
+	}
+	SnapMergeIOLimitFlag = cli.StringFlag{
+		Name:  ethconfig.FlagSnapMergeIOLimit,
+		Usage: "Bytes per second written by background merges of state files (e.g. 50mb), so they don't compete with block execution on slow disks. 0 - unlimited",
+		Value: "0",
+	}
+	SnapMergeIdleWaitFlag = cli.DurationFlag{
+		Name:  ethconfig.FlagSnapMergeIdle,
+		Usage: "Max time background merges of state files wait for the block execution to be done. 0 - merges don't wait",
+		Value: 0,
+	}
 	TorrentVerbosityFlag = cli.IntFlag{
 		Name:  "torrent.verbosity",
 		Value: 2,
@@ -774,6 +908,12 @@ var (
 		Value: "http://localhost:1317",
 	}
 
+	HeimdallgRPCAddressFlag = cli.StringFlag{
+		Name:  "bor.heimdallgRPC",
+		Usage: "Address of Heimdall gRPC service, state sync events and spans are fetched over it instead of the http api",
+		Value: "",
+	}
+
 	// WithoutHeimdallFlag no heimdall (for testing purpose)
 	WithoutHeimdallFlag = cli.BoolFlag{
 		Name:  "bor.withoutheimdall",
@@ -978,6 +1118,47 @@ var (
 		Usage: "enables archival node in caplin",
 		Value: false,
 	}
+	CaplinMonitorFlag = cli.Uint64SliceFlag{
+		Name:  "caplin.monitor",
+		Usage: "comma separated indices of validators to track attestations, proposals and sync committee participation of",
+	}
+	CaplinSlasherFlag = cli.BoolFlag{
+		Name:  "caplin.slasher",
+		Usage: "enables the slasher in caplin: detect double and surround votes and double proposals in gossip, and submit their slashings",
+		Value: false,
+	}
+	CaplinMevRelayUrlFlag = cli.StringSliceFlag{
+		Name:  "caplin.mev-relay-url",
+		Usage: "comma separated urls of builder API relays (e.g. mev-boost) to get execution payloads of proposals from",
+	}
+	CaplinMevMinBidFlag = cli.Uint64Flag{
+		Name:  "caplin.mev-min-bid",
+		Usage: "minimum value of a builder's bid in gwei, lower bids are ignored in favour of the local payload",
+		Value: 0,
+	}
+	CaplinProposerConfigFlag = cli.StringFlag{
+		Name:  "caplin.proposer-config",
+		Usage: "path to the proposer config file (json) with fee recipients by validator public key and the default one, used for validators not prepared by the validator client",
+	}
+	CaplinCheckpointSyncUrlFlag = cli.StringSliceFlag{
+		Name:  "caplin.checkpoint-sync-url",
+		Usage: "comma separated checkpoint sync endpoints, the fastest healthy one is used and the others are fallbacks (default: network's trusted endpoints)",
+	}
+	CaplinAttestationSeenCacheEpochsFlag = cli.Uint64Flag{
+		Name:  "caplin.attestation-seen-cache.epochs",
+		Usage: "number of target epochs kept by the cache of seen attestations, used to ignore duplicates in gossip",
+		Value: 3,
+	}
+	CaplinAttestationSeenCacheEpochSizeFlag = cli.IntFlag{
+		Name:  "caplin.attestation-seen-cache.epoch-size",
+		Usage: "max number of attestations per epoch in the cache of seen attestations",
+		Value: 100_000,
+	}
+	CaplinPeerDASFlag = cli.BoolFlag{
+		Name:  "caplin.peerdas",
+		Usage: "experimental: enables PeerDAS (EIP-7594) in caplin: custody, gossip and serve data column sidecars once the fork is scheduled",
+		Value: false,
+	}
 	BeaconApiAllowCredentialsFlag = cli.BoolFlag{
 		Name:  "beacon.api.cors.allow-credentials",
 		Usage: "set the cors' allow credentials",
@@ -1153,6 +1334,8 @@ func NewP2PConfig(
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth67")
 	case direct.ETH68:
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth68")
+	case direct.ETH69:
+		enodeDBPath = filepath.Join(dirs.Nodes, "eth69")
 	default:
 		return nil, fmt.Errorf("unknown protocol: %v", protocol)
 	}
@@ -1489,7 +1672,46 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 	if ctx.IsSet(TxPoolBlobPriceBumpFlag.Name) {
 		fullCfg.TxPool.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
+	if ctx.IsSet(TxPoolPrioritySendersFlag.Name) {
+		senderHexes := libcommon.CliString2Array(ctx.String(TxPoolPrioritySendersFlag.Name))
+		fullCfg.TxPool.PrioritySenders = make([]libcommon.Address, len(senderHexes))
+		for i, senderHex := range senderHexes {
+			fullCfg.TxPool.PrioritySenders[i] = libcommon.HexToAddress(senderHex)
+		}
+	}
+	if ctx.IsSet(TxPoolPriorityGasLimitFlag.Name) {
+		fullCfg.TxPool.PriorityLaneGasLimit = ctx.Uint64(TxPoolPriorityGasLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolSenderRateLimitFlag.Name) {
+		fullCfg.TxPool.SenderRateLimit = ctx.Float64(TxPoolSenderRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolGlobalRateLimitFlag.Name) {
+		fullCfg.TxPool.GlobalRateLimit = ctx.Float64(TxPoolGlobalRateLimitFlag.Name)
+	}
+	if ctx.IsSet(TxPoolLocalsLifetimeFlag.Name) {
+		fullCfg.TxPool.LocalsLifetime = ctx.Duration(TxPoolLocalsLifetimeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolLocalsRebroadcastFlag.Name) {
+		fullCfg.TxPool.RebroadcastLocalsEvery = ctx.Duration(TxPoolLocalsRebroadcastFlag.Name)
+	}
+	if ctx.IsSet(TxPoolReplacementPolicyFlag.Name) {
+		fullCfg.TxPool.ReplacementPolicy = ctx.String(TxPoolReplacementPolicyFlag.Name)
+	}
+	if ctx.IsSet(TxPoolMaxReplacementsFlag.Name) {
+		fullCfg.TxPool.MaxReplacements = ctx.Uint64(TxPoolMaxReplacementsFlag.Name)
+	}
 	cfg.CommitEvery = common2.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
+
+	fullCfg.AAPool.Enabled = ctx.Bool(TxPoolAAFlag.Name)
+	if ctx.IsSet(TxPoolAAEntryPointFlag.Name) {
+		fullCfg.AAPool.EntryPoint = libcommon.HexToAddress(ctx.String(TxPoolAAEntryPointFlag.Name))
+	}
+	if ctx.IsSet(TxPoolAAMaxOpsFlag.Name) {
+		fullCfg.AAPool.MaxOps = ctx.Int(TxPoolAAMaxOpsFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAABundleSizeFlag.Name) {
+		fullCfg.AAPool.BundleSize = ctx.Int(TxPoolAABundleSizeFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, datadir string, cfg *ethconfig.Config) {
@@ -1576,6 +1798,7 @@ func setClique(ctx *cli.Context, cfg *params.ConsensusSnapshotConfig, datadir st
 
 func setBorConfig(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.HeimdallURL = ctx.String(HeimdallURLFlag.Name)
+	cfg.HeimdallgRPCAddress = ctx.String(HeimdallgRPCAddressFlag.Name)
 	cfg.WithoutHeimdall = ctx.Bool(WithoutHeimdallFlag.Name)
 	cfg.WithHeimdallMilestones = ctx.Bool(WithHeimdallMilestones.Name)
 	cfg.WithHeimdallWaypointRecording = ctx.Bool(WithHeimdallWaypoints.Name)
@@ -1658,6 +1881,15 @@ func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.CaplinConfig.BlobBackfilling = ctx.Bool(CaplinBlobBackfillingFlag.Name)
 	cfg.CaplinConfig.BlobPruningDisabled = ctx.Bool(CaplinDisableBlobPruningFlag.Name)
 	cfg.CaplinConfig.Archive = ctx.Bool(CaplinArchiveFlag.Name)
+	cfg.CaplinConfig.MonitoredValidators = ctx.Uint64Slice(CaplinMonitorFlag.Name)
+	cfg.CaplinConfig.Slasher = ctx.Bool(CaplinSlasherFlag.Name)
+	cfg.CaplinConfig.MevRelayUrls = ctx.StringSlice(CaplinMevRelayUrlFlag.Name)
+	cfg.CaplinConfig.MevMinBid = ctx.Uint64(CaplinMevMinBidFlag.Name)
+	cfg.CaplinConfig.ProposerConfigFile = ctx.String(CaplinProposerConfigFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncUrls = ctx.StringSlice(CaplinCheckpointSyncUrlFlag.Name)
+	cfg.CaplinConfig.AttestationSeenCacheEpochs = ctx.Uint64(CaplinAttestationSeenCacheEpochsFlag.Name)
+	cfg.CaplinConfig.AttestationSeenCacheEpochSize = ctx.Int(CaplinAttestationSeenCacheEpochSizeFlag.Name)
+	cfg.CaplinConfig.PeerDAS = ctx.Bool(CaplinPeerDASFlag.Name)
 }
 
 func setSilkworm(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1735,8 +1967,16 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	}
 
 	cfg.Dirs = nodeConfig.Dirs
+	if err := cfg.EngineJournalSize.UnmarshalText([]byte(ctx.String(AuthRpcJournalSizeFlag.Name))); err != nil {
+		panic(fmt.Errorf("invalid --%s: %w", AuthRpcJournalSizeFlag.Name, err))
+	}
 	cfg.Snapshot.KeepBlocks = ctx.Bool(SnapKeepBlocksFlag.Name)
 	cfg.Snapshot.Produce = !ctx.Bool(SnapStopFlag.Name)
+	cfg.Snapshot.IndexWorkers = ctx.Int(SnapIndexWorkersFlag.Name)
+	if err := cfg.Snapshot.MergeIOLimit.UnmarshalText([]byte(ctx.String(SnapMergeIOLimitFlag.Name))); err != nil {
+		panic(fmt.Errorf("invalid --%s: %w", SnapMergeIOLimitFlag.Name, err))
+	}
+	cfg.Snapshot.MergeIdleWait = ctx.Duration(SnapMergeIdleWaitFlag.Name)
 	cfg.Snapshot.NoDownloader = ctx.Bool(NoDownloaderFlag.Name)
 	cfg.Snapshot.Verify = ctx.Bool(DownloaderVerifyFlag.Name)
 	cfg.Snapshot.DownloaderAddr = strings.TrimSpace(ctx.String(DownloaderAddrFlag.Name))
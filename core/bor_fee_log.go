@@ -70,6 +70,26 @@ func AddFeeTransferLog(
 	)
 }
 
+// FeeTransferLog returns fee transfer log of tx: it's the last one of tx logs
+func FeeTransferLog(logs types.Logs) *types.Log {
+	if len(logs) == 0 {
+		return nil
+	}
+	l := logs[len(logs)-1]
+	if l.Address != feeAddress || len(l.Topics) == 0 || l.Topics[0] != transferFeeLogSig {
+		return nil
+	}
+	return l
+}
+
+// SetFeeTransferLogRecipientBalance fills fee recipient's balance before the fee credit into fee transfer log,
+// which was added without it (see vm.Config.DeferFeeCredit)
+func SetFeeTransferLogRecipientBalance(l *types.Log, balance *uint256.Int) {
+	amount := new(uint256.Int).SetBytes(l.Data[:32])
+	balance.WriteToSlice(l.Data[64:])
+	new(uint256.Int).Add(balance, amount).WriteToSlice(l.Data[128:])
+}
+
 // addTransferLog adds transfer log into state
 func addTransferLog(
 	state evmtypes.IntraBlockState,
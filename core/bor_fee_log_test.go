@@ -0,0 +1,80 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+)
+
+type accountReadsRecorder struct {
+	state.StateReader
+	reads map[libcommon.Address]struct{}
+}
+
+func (r *accountReadsRecorder) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	r.reads[address] = struct{}{}
+	return r.StateReader.ReadAccountData(address)
+}
+
+func TestDeferredFeeTransferLog(t *testing.T) {
+	t.Parallel()
+	chainConfig := &chain.Config{
+		ChainID:               big.NewInt(137),
+		HomesteadBlock:        big.NewInt(0),
+		TangerineWhistleBlock: big.NewInt(0),
+		SpuriousDragonBlock:   big.NewInt(0),
+		ByzantiumBlock:        big.NewInt(0),
+		ConstantinopleBlock:   big.NewInt(0),
+		PetersburgBlock:       big.NewInt(0),
+		IstanbulBlock:         big.NewInt(0),
+		BerlinBlock:           big.NewInt(0),
+		Bor:                   &borcfg.BorConfig{},
+	}
+	sender, to, coinbase := libcommon.HexToAddress("0x01"), libcommon.HexToAddress("0x02"), libcommon.HexToAddress("0x03")
+	coinbaseBalance := uint256.NewInt(5)
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: params.TxGas}
+
+	// returns fee transfer log and if coinbase was read
+	run := func(deferFeeCredit bool) (*types.Log, bool) {
+		_, tx := memdb.NewTestTx(t)
+		r := &accountReadsRecorder{StateReader: state.NewPlainStateReader(tx), reads: map[libcommon.Address]struct{}{}}
+		ibs := state.New(r)
+		ibs.SetBalance(sender, uint256.NewInt(params.Ether))
+		if !deferFeeCredit {
+			ibs.SetBalance(coinbase, coinbaseBalance)
+		}
+		delete(r.reads, coinbase)
+
+		msg := types.NewMessage(sender, &to, 0, uint256.NewInt(0), params.TxGas, uint256.NewInt(params.GWei), nil, nil, nil, nil, true, false, nil)
+		evm := vm.NewEVM(NewEVMBlockContext(header, nil, nil, &coinbase), NewEVMTxContext(msg), ibs, chainConfig, vm.Config{DeferFeeCredit: deferFeeCredit})
+		_, err := ApplyMessage(evm, msg, new(GasPool).AddGas(params.TxGas), true, false)
+		require.NoError(t, err)
+
+		_, coinbaseRead := r.reads[coinbase]
+		return FeeTransferLog(ibs.Logs()), coinbaseRead
+	}
+
+	expect, _ := run(false)
+	require.NotNil(t, expect)
+
+	deferred, coinbaseRead := run(true)
+	require.False(t, coinbaseRead)
+	require.NotNil(t, deferred)
+	require.NotEqual(t, expect.Data, deferred.Data)
+
+	SetFeeTransferLogRecipientBalance(deferred, coinbaseBalance)
+	require.Equal(t, expect.Data, deferred.Data)
+}
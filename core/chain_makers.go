@@ -30,6 +30,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/config3"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	state2 "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/merge"
 	"github.com/ledgerwatch/erigon/consensus/misc"
@@ -488,10 +489,12 @@ func CalcHashRootForTests(tx kv.RwTx, header *types.Header, histV4, trace bool)
 				return hashRoot, fmt.Errorf("interate over plain state: %w", err)
 			}
 			if len(v) > 0 {
-				v, err = accounts.ConvertV3toV2(v)
-				if err != nil {
+				var a accounts.Account
+				if err := accounts.DeserialiseState(&a, v); err != nil {
 					return hashRoot, fmt.Errorf("interate over plain state: %w", err)
 				}
+				v = make([]byte, a.EncodingLengthForStorage())
+				a.EncodeForStorage(v)
 			}
 			newK, err := hashKeyAndAddIncarnation(k, h)
 			if err != nil {
@@ -516,7 +519,7 @@ func CalcHashRootForTests(tx kv.RwTx, header *types.Header, histV4, trace bool)
 				return hashRoot, fmt.Errorf("clear HashedStorage bucket: %w", err)
 			}
 			fmt.Printf("storage %x -> %x\n", k, newK)
-			if err := tx.Put(kv.HashedStorage, newK, v); err != nil {
+			if err := tx.Put(kv.HashedStorage, newK, statecodec.Active().DecodeStorage(v)); err != nil {
 				return hashRoot, fmt.Errorf("clear HashedStorage bucket: %w", err)
 			}
 
@@ -951,6 +951,50 @@ func TruncateReceipts(db kv.RwTx, number uint64) error {
 	return nil
 }
 
+// ReadExecutionWitness retrieves the rlp-encoded execution witness of the block, nil if it wasn't emitted
+func ReadExecutionWitness(db kv.Getter, hash common.Hash, number uint64) ([]byte, error) {
+	data, err := db.GetOne(kv.ExecutionWitness, dbutils.HeaderKey(number, hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed ReadExecutionWitness: %w", err)
+	}
+	return data, nil
+}
+
+// WriteExecutionWitness stores the rlp-encoded execution witness of the block
+func WriteExecutionWitness(db kv.Putter, hash common.Hash, number uint64, witness []byte) error {
+	if err := db.Put(kv.ExecutionWitness, dbutils.HeaderKey(number, hash), witness); err != nil {
+		return fmt.Errorf("failed to store execution witness: %w", err)
+	}
+	return nil
+}
+
+// TruncateExecutionWitnesses removes all execution witnesses from block number N - used for Unwind
+func TruncateExecutionWitnesses(tx kv.RwTx, blockFrom uint64) error {
+	if err := tx.ForEach(kv.ExecutionWitness, hexutility.EncodeTs(blockFrom), func(k, _ []byte) error {
+		return tx.Delete(kv.ExecutionWitness, k)
+	}); err != nil {
+		return fmt.Errorf("TruncateExecutionWitnesses: %w", err)
+	}
+	return nil
+}
+
+// ReadExecutionProfile retrieves the json-encoded execution profile of the block, nil if the block wasn't profiled
+func ReadExecutionProfile(db kv.Getter, hash common.Hash, number uint64) ([]byte, error) {
+	data, err := db.GetOne(kv.ExecutionProfile, dbutils.HeaderKey(number, hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed ReadExecutionProfile: %w", err)
+	}
+	return data, nil
+}
+
+// WriteExecutionProfile stores the json-encoded execution profile of the block
+func WriteExecutionProfile(db kv.Putter, hash common.Hash, number uint64, profile []byte) error {
+	if err := db.Put(kv.ExecutionProfile, dbutils.HeaderKey(number, hash), profile); err != nil {
+		return fmt.Errorf("failed to store execution profile: %w", err)
+	}
+	return nil
+}
+
 func ReceiptsAvailableFrom(tx kv.Tx) (uint64, error) {
 	c, err := tx.Cursor(kv.Receipts)
 	if err != nil {
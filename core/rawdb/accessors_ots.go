@@ -0,0 +1,159 @@
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// OtsAppearanceKind - kind of address appearance stored in kv.OtsAppearances
+type OtsAppearanceKind byte
+
+const (
+	OtsContractCreation OtsAppearanceKind = iota + 1 // address deployed counterpart contract
+	OtsSelfDestruct                                  // address self-destructed, counterpart is beneficiary
+	OtsERC20Transfer                                 // address sent or received ERC-20 token counterpart
+	OtsERC721Transfer                                // address sent or received ERC-721 token counterpart
+)
+
+// OtsAppearance - address appearance in block
+type OtsAppearance struct {
+	Kind        OtsAppearanceKind
+	Address     libcommon.Address
+	Counterpart libcommon.Address
+	BlockNum    uint64
+}
+
+// firstOnly - token transfers are stored only at first appearance of the token, this is what "tokens
+// touched by address" needs and keeps the index small
+func (a OtsAppearanceKind) firstOnly() bool {
+	return a == OtsERC20Transfer || a == OtsERC721Transfer
+}
+
+func otsAppearanceValue(kind OtsAppearanceKind, counterpart libcommon.Address, blockNum uint64) []byte {
+	v := make([]byte, 1+length.Addr+8)
+	v[0] = byte(kind)
+	copy(v[1:], counterpart[:])
+	binary.BigEndian.PutUint64(v[1+length.Addr:], blockNum)
+	return v
+}
+
+// WriteOtsAppearances stores appearances of block, must be called in order of blocks
+func WriteOtsAppearances(tx kv.RwTx, blockNum uint64, appearances []OtsAppearance) error {
+	if len(appearances) == 0 {
+		return nil
+	}
+	c, err := tx.RwCursorDupSort(kv.OtsAppearances)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	blockKey := hexutility.EncodeTs(blockNum)
+	for _, a := range appearances {
+		v := otsAppearanceValue(a.Kind, a.Counterpart, blockNum)
+		if a.Kind.firstOnly() {
+			found, err := c.SeekBothRange(a.Address[:], v[:1+length.Addr])
+			if err != nil {
+				return err
+			}
+			if found != nil && bytes.HasPrefix(found, v[:1+length.Addr]) {
+				continue
+			}
+		}
+		if err := c.Put(a.Address[:], v); err != nil {
+			return fmt.Errorf("failed to store ots appearance: %w", err)
+		}
+		byBlock := make([]byte, 0, length.Addr+1+length.Addr)
+		byBlock = append(append(append(byBlock, a.Address[:]...), byte(a.Kind)), a.Counterpart[:]...)
+		if err := tx.Put(kv.OtsAppearancesByBlock, blockKey, byBlock); err != nil {
+			return fmt.Errorf("failed to store ots appearance: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadOtsAppearances returns up to limit appearances of given kind of address, skipping first offset of them.
+// Appearances are ordered by counterpart address. The bool is true if there are more appearances after the page.
+func ReadOtsAppearances(tx kv.Tx, addr libcommon.Address, kind OtsAppearanceKind, offset, limit uint64) ([]OtsAppearance, bool, error) {
+	c, err := tx.CursorDupSort(kv.OtsAppearances)
+	if err != nil {
+		return nil, false, err
+	}
+	defer c.Close()
+
+	var res []OtsAppearance
+	var i uint64
+	v, err := c.SeekBothRange(addr[:], []byte{byte(kind)})
+	for ; v != nil; _, v, err = c.NextDup() {
+		if err != nil {
+			return nil, false, err
+		}
+		if v[0] != byte(kind) {
+			break
+		}
+		if i++; i <= offset {
+			continue
+		}
+		if uint64(len(res)) == limit {
+			return res, true, nil
+		}
+		res = append(res, OtsAppearance{
+			Kind:        kind,
+			Address:     addr,
+			Counterpart: libcommon.BytesToAddress(v[1 : 1+length.Addr]),
+			BlockNum:    binary.BigEndian.Uint64(v[1+length.Addr:]),
+		})
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return res, false, nil
+}
+
+// TruncateOtsAppearances removes all ots appearances from block number N - used for Unwind
+func TruncateOtsAppearances(tx kv.RwTx, blockFrom uint64) error {
+	c, err := tx.RwCursorDupSort(kv.OtsAppearances)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	bc, err := tx.RwCursorDupSort(kv.OtsAppearancesByBlock)
+	if err != nil {
+		return err
+	}
+	defer bc.Close()
+
+	from := hexutility.EncodeTs(blockFrom)
+	for k, v, err := bc.Seek(from); k != nil; k, v, err = bc.Seek(from) {
+		if err != nil {
+			return fmt.Errorf("TruncateOtsAppearances: %w", err)
+		}
+		blockNum := binary.BigEndian.Uint64(k)
+		for ; v != nil; _, v, err = bc.NextDup() {
+			if err != nil {
+				return fmt.Errorf("TruncateOtsAppearances: %w", err)
+			}
+			kind := OtsAppearanceKind(v[length.Addr])
+			counterpart := libcommon.BytesToAddress(v[length.Addr+1:])
+			if err := c.DeleteExact(v[:length.Addr], otsAppearanceValue(kind, counterpart, blockNum)); err != nil {
+				return fmt.Errorf("TruncateOtsAppearances: %w", err)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("TruncateOtsAppearances: %w", err)
+		}
+		if _, _, err := bc.SeekExact(hexutility.EncodeTs(blockNum)); err != nil {
+			return fmt.Errorf("TruncateOtsAppearances: %w", err)
+		}
+		if err := bc.DeleteCurrentDuplicates(); err != nil {
+			return fmt.Errorf("TruncateOtsAppearances: %w", err)
+		}
+	}
+	return nil
+}
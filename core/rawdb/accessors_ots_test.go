@@ -0,0 +1,61 @@
+package rawdb_test
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+)
+
+func TestOtsAppearances(t *testing.T) {
+	t.Parallel()
+	_, tx := memdb.NewTestTx(t)
+
+	holder, deployer := libcommon.HexToAddress("0x01"), libcommon.HexToAddress("0x02")
+	tokenA, tokenB, contract := libcommon.HexToAddress("0x0a"), libcommon.HexToAddress("0x0b"), libcommon.HexToAddress("0x0c")
+
+	require.NoError(t, rawdb.WriteOtsAppearances(tx, 10, []rawdb.OtsAppearance{
+		{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenB},
+		{Kind: rawdb.OtsContractCreation, Address: deployer, Counterpart: contract},
+	}))
+	// only first transfer of token is stored
+	require.NoError(t, rawdb.WriteOtsAppearances(tx, 11, []rawdb.OtsAppearance{
+		{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenB},
+		{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenA},
+		{Kind: rawdb.OtsERC721Transfer, Address: holder, Counterpart: tokenA},
+	}))
+
+	got, more, err := rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsERC20Transfer, 0, 10)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, []rawdb.OtsAppearance{
+		{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenA, BlockNum: 11},
+		{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenB, BlockNum: 10},
+	}, got)
+
+	got, more, err = rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsERC20Transfer, 1, 1)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, tokenB, got[0].Counterpart)
+	_, more, err = rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsERC20Transfer, 0, 1)
+	require.NoError(t, err)
+	require.True(t, more)
+
+	got, _, err = rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsContractCreation, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	got, _, err = rawdb.ReadOtsAppearances(tx, deployer, rawdb.OtsContractCreation, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []rawdb.OtsAppearance{{Kind: rawdb.OtsContractCreation, Address: deployer, Counterpart: contract, BlockNum: 10}}, got)
+
+	require.NoError(t, rawdb.TruncateOtsAppearances(tx, 11))
+	got, _, err = rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsERC20Transfer, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []rawdb.OtsAppearance{{Kind: rawdb.OtsERC20Transfer, Address: holder, Counterpart: tokenB, BlockNum: 10}}, got)
+	got, _, err = rawdb.ReadOtsAppearances(tx, holder, rawdb.OtsERC721Transfer, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
@@ -0,0 +1,51 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// BorEventRootTxEntry - state sync event emitted by a log of the root chain (L1) transaction
+type BorEventRootTxEntry struct {
+	LogIndex uint64 // index of the StateSynced log in the root chain block
+	EventID  uint64
+	BlockNum uint64 // Bor block which committed the event
+}
+
+func borEventRootTxKey(rootTxHash libcommon.Hash, logIndex uint64) []byte {
+	return binary.BigEndian.AppendUint64(rootTxHash.Bytes(), logIndex)
+}
+
+// WriteBorEventRootTx - indexes state sync event by the root chain transaction and log index which emitted it.
+// Unlike kv.BorEvents the index is not pruned when events are moved to snapshots.
+func WriteBorEventRootTx(tx kv.Putter, rootTxHash libcommon.Hash, logIndex, eventID, blockNum uint64) error {
+	v := binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64(nil, eventID), blockNum)
+	return tx.Put(kv.BorEventsByRootTx, borEventRootTxKey(rootTxHash, logIndex), v)
+}
+
+func DeleteBorEventRootTx(tx kv.Deleter, rootTxHash libcommon.Hash, logIndex uint64) error {
+	return tx.Delete(kv.BorEventsByRootTx, borEventRootTxKey(rootTxHash, logIndex))
+}
+
+// ReadBorEventsByRootTx - state sync events emitted by the root chain transaction, ordered by log index
+func ReadBorEventsByRootTx(tx kv.Tx, rootTxHash libcommon.Hash) ([]BorEventRootTxEntry, error) {
+	var entries []BorEventRootTxEntry
+	if err := tx.ForPrefix(kv.BorEventsByRootTx, rootTxHash.Bytes(), func(k, v []byte) error {
+		if len(k) != length.Hash+8 || len(v) != 16 {
+			return fmt.Errorf("invalid %s entry: key %x, value %x", kv.BorEventsByRootTx, k, v)
+		}
+		entries = append(entries, BorEventRootTxEntry{
+			LogIndex: binary.BigEndian.Uint64(k[length.Hash:]),
+			EventID:  binary.BigEndian.Uint64(v),
+			BlockNum: binary.BigEndian.Uint64(v[8:]),
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 )
@@ -32,7 +33,7 @@ func (r *CachedReader3) ReadAccountData(address common.Address) (*accounts.Accou
 		return nil, nil
 	}
 	a := accounts.Account{}
-	if err = accounts.DeserialiseV3(&a, enc); err != nil {
+	if err = accounts.DeserialiseState(&a, enc); err != nil {
 		return nil, err
 	}
 	return &a, nil
@@ -47,7 +48,7 @@ func (r *CachedReader3) ReadAccountStorage(address common.Address, incarnation u
 	if len(enc) == 0 {
 		return nil, nil
 	}
-	return enc, nil
+	return statecodec.Active().DecodeStorage(enc), nil
 }
 
 func (r *CachedReader3) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
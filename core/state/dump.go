@@ -25,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
@@ -174,7 +175,7 @@ func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bo
 			continue
 		}
 
-		if e := accounts.DeserialiseV3(&acc, v); e != nil {
+		if e := accounts.DeserialiseState(&acc, v); e != nil {
 			return nil, fmt.Errorf("decoding %x for %x: %w", v, k, e)
 		}
 		account := DumpAccount{
@@ -234,6 +235,7 @@ func (d *Dumper) DumpToCollector(c DumpCollector, excludeCode, excludeStorage bo
 				if len(vs) == 0 {
 					continue // Skip deleted entries
 				}
+				vs = statecodec.Active().DecodeStorage(vs)
 				loc := k[20:]
 				account.Storage[libcommon.BytesToHash(loc).String()] = common.Bytes2Hex(vs)
 				h, _ := libcommon.HashData(loc)
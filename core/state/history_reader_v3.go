@@ -6,6 +6,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 )
 
@@ -43,7 +44,7 @@ func (hr *HistoryReaderV3) ReadAccountData(address common.Address) (*accounts.Ac
 		return nil, err
 	}
 	var a accounts.Account
-	if err := accounts.DeserialiseV3(&a, enc); err != nil {
+	if err := accounts.DeserialiseState(&a, enc); err != nil {
 		return nil, fmt.Errorf("ReadAccountData(%x): %w", address, err)
 	}
 	if hr.trace {
@@ -58,7 +59,7 @@ func (hr *HistoryReaderV3) ReadAccountStorage(address common.Address, incarnatio
 	if hr.trace {
 		fmt.Printf("ReadAccountStorage [%x] [%x] => [%x]\n", address, *key, enc)
 	}
-	return enc, err
+	return statecodec.Active().DecodeStorage(enc), err
 }
 
 func (hr *HistoryReaderV3) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
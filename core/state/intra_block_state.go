@@ -234,6 +234,16 @@ func (sdb *IntraBlockState) TxIndex() int {
 	return sdb.txIndex
 }
 
+// TxHash returns the hash of the transaction set by SetTxContext.
+func (sdb *IntraBlockState) TxHash() libcommon.Hash {
+	return sdb.thash
+}
+
+// BlockHash returns the hash of the block set by SetTxContext.
+func (sdb *IntraBlockState) BlockHash() libcommon.Hash {
+	return sdb.bhash
+}
+
 // DESCRIBED: docs/programmers_guide/guide.md#address---identifier-of-an-account
 func (sdb *IntraBlockState) GetCode(addr libcommon.Address) []byte {
 	stateObject := sdb.getStateObject(addr)
@@ -18,6 +18,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/metrics"
 	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/turbo/shards"
 )
@@ -133,7 +134,7 @@ func (rs *StateV3) applyState(txTask *TxTask, domains *libstate.SharedDomains) e
 		}
 		acc.Reset()
 		if len(enc0) > 0 {
-			if err := accounts.DeserialiseV3(&acc, enc0); err != nil {
+			if err := accounts.DeserialiseState(&acc, enc0); err != nil {
 				return err
 			}
 		}
@@ -143,7 +144,10 @@ func (rs *StateV3) applyState(txTask *TxTask, domains *libstate.SharedDomains) e
 				return err
 			}
 		} else {
-			enc1 := accounts.SerialiseV3(&acc)
+			enc1, err := accounts.SerialiseState(&acc)
+			if err != nil {
+				return err
+			}
 			if err := domains.DomainPut(kv.AccountsDomain, addrBytes, nil, enc1, enc0, step0); err != nil {
 				return err
 			}
@@ -241,7 +245,7 @@ func (rs *StateV3) Unwind(ctx context.Context, tx kv.RwTx, blockUnwindTo, txUnwi
 		if len(k) == length.Addr {
 			if len(v) > 0 {
 				var acc accounts.Account
-				if err := accounts.DeserialiseV3(&acc, v); err != nil {
+				if err := accounts.DeserialiseState(&acc, v); err != nil {
 					return fmt.Errorf("%w, %x", err, v)
 				}
 				var address common.Address
@@ -267,7 +271,7 @@ func (rs *StateV3) Unwind(ctx context.Context, tx kv.RwTx, blockUnwindTo, txUnwi
 		copy(address[:], k[:length.Addr])
 		copy(location[:], k[length.Addr:])
 		if accumulator != nil {
-			accumulator.ChangeStorage(address, currentInc, location, common.Copy(v))
+			accumulator.ChangeStorage(address, currentInc, location, common.Copy(statecodec.Active().DecodeStorage(v)))
 		}
 		return nil
 	}
@@ -396,7 +400,10 @@ func (w *StateWriterBufferedV3) UpdateAccountData(address common.Address, origin
 			return err
 		}
 	}
-	value := accounts.SerialiseV3(account)
+	value, err := accounts.SerialiseState(account)
+	if err != nil {
+		return err
+	}
 	if w.accumulator != nil {
 		w.accumulator.ChangeAccount(address, account.Incarnation, value)
 	}
@@ -432,7 +439,7 @@ func (w *StateWriterBufferedV3) WriteAccountStorage(address common.Address, inca
 		return nil
 	}
 	compositeS := string(append(address.Bytes(), key.Bytes()...))
-	w.writeLists[kv.StorageDomain.String()].Push(compositeS, value.Bytes())
+	w.writeLists[kv.StorageDomain.String()].Push(compositeS, statecodec.Active().EncodeStorage(value.Bytes()))
 	if w.trace {
 		fmt.Printf("storage: %x,%x,%x\n", address, *key, value.Bytes())
 	}
@@ -505,7 +512,10 @@ func (w *StateWriterV3) UpdateAccountData(address common.Address, original, acco
 			return err
 		}
 	}
-	value := accounts.SerialiseV3(account)
+	value, err := accounts.SerialiseState(account)
+	if err != nil {
+		return err
+	}
 	if w.accumulator != nil {
 		w.accumulator.ChangeAccount(address, account.Incarnation, value)
 	}
@@ -559,7 +569,7 @@ func (w *StateWriterV3) WriteAccountStorage(address common.Address, incarnation
 		w.accumulator.ChangeStorage(address, incarnation, k, v)
 	}
 
-	return w.rs.domains.DomainPut(kv.StorageDomain, composite, nil, v, nil, 0)
+	return w.rs.domains.DomainPut(kv.StorageDomain, composite, nil, statecodec.Active().EncodeStorage(v), nil, 0)
 }
 
 func (w *StateWriterV3) CreateContract(address common.Address) error {
@@ -574,12 +584,94 @@ func (w *StateWriterV3) CreateContract(address common.Address) error {
 	return nil
 }
 
+// ResettableStateWriter - writer of exec3 workers
+type ResettableStateWriter interface {
+	StateWriter
+	SetTx(tx kv.Tx)
+	SetTxNum(ctx context.Context, txNum uint64)
+	ResetWriteSet()
+	WriteSet() map[string]*libstate.KvList
+	PrevAndDels() (map[string][]byte, map[string]*accounts.Account, map[string][]byte, map[string]uint64)
+}
+
+// StateWriterParallelV3 - used by parallel workers which execute txs optimistically: doesn't touch SharedDomains
+// (they are shared by all workers), but accumulates updates in write-set. Write-set is applied by StateV3.ApplyState4
+// only if tx's read-set is still valid (see StateV3.ReadsValid) - otherwise tx is re-executed.
+// Same semantic as StateWriterV3 (including deletes).
+type StateWriterParallelV3 struct {
+	writeLists map[string]*libstate.KvList
+
+	// write-set can't represent deletion of storage by prefix (re-creation of contract) - such tx must be re-executed
+	needsReExec bool
+}
+
+func NewStateWriterParallelV3() *StateWriterParallelV3 {
+	return &StateWriterParallelV3{writeLists: newWriteList()}
+}
+
+func (w *StateWriterParallelV3) SetTxNum(ctx context.Context, txNum uint64) {}
+func (w *StateWriterParallelV3) SetTx(tx kv.Tx)                             {}
+
+func (w *StateWriterParallelV3) ResetWriteSet() {
+	w.writeLists = newWriteList()
+	w.needsReExec = false
+}
+
+func (w *StateWriterParallelV3) WriteSet() map[string]*libstate.KvList { return w.writeLists }
+
+// NeedsReExec - tx did something what can't be represented by write-set
+func (w *StateWriterParallelV3) NeedsReExec() bool { return w.needsReExec }
+
+func (w *StateWriterParallelV3) PrevAndDels() (map[string][]byte, map[string]*accounts.Account, map[string][]byte, map[string]uint64) {
+	return nil, nil, nil, nil
+}
+
+func (w *StateWriterParallelV3) UpdateAccountData(address common.Address, original, account *accounts.Account) error {
+	if original.Incarnation > account.Incarnation {
+		w.needsReExec = true
+	}
+	value, err := accounts.SerialiseState(account)
+	if err != nil {
+		return err
+	}
+	w.writeLists[kv.AccountsDomain.String()].Push(string(address[:]), value)
+	return nil
+}
+
+func (w *StateWriterParallelV3) UpdateAccountCode(address common.Address, incarnation uint64, codeHash common.Hash, code []byte) error {
+	w.writeLists[kv.CodeDomain.String()].Push(string(address[:]), code)
+	return nil
+}
+
+func (w *StateWriterParallelV3) DeleteAccount(address common.Address, original *accounts.Account) error {
+	w.writeLists[kv.AccountsDomain.String()].Push(string(address[:]), nil)
+	return nil
+}
+
+func (w *StateWriterParallelV3) WriteAccountStorage(address common.Address, incarnation uint64, key *common.Hash, original, value *uint256.Int) error {
+	if *original == *value {
+		return nil
+	}
+	composite := string(append(address.Bytes(), key.Bytes()...))
+	if value.IsZero() {
+		w.writeLists[kv.StorageDomain.String()].Push(composite, nil)
+		return nil
+	}
+	w.writeLists[kv.StorageDomain.String()].Push(composite, statecodec.Active().EncodeStorage(value.Bytes()))
+	return nil
+}
+
+func (w *StateWriterParallelV3) CreateContract(address common.Address) error { return nil }
+
 type StateReaderV3 struct {
 	txNum     uint64
 	trace     bool
 	sd        *libstate.SharedDomains
 	composite []byte
 
+	ownTx bool // if true - values which are not in SharedDomains RAM are read by `tx`, instead of SharedDomains.roTx
+	tx    kv.Tx
+
 	discardReadList bool
 	readLists       map[string]*libstate.KvList
 }
@@ -593,15 +685,36 @@ func NewStateReaderV3(sd *libstate.SharedDomains) *StateReaderV3 {
 	}
 }
 
-func (r *StateReaderV3) DiscardReadList()                     { r.discardReadList = true }
-func (r *StateReaderV3) SetTxNum(txNum uint64)                { r.txNum = txNum }
-func (r *StateReaderV3) SetTx(tx kv.Tx)                       {}
+// NewStateReaderParallelV3 - reader of parallel workers: SharedDomains.roTx can't be shared between goroutines,
+// so values which are not in SharedDomains RAM are read by reader's own tx (see SetTx)
+func NewStateReaderParallelV3(sd *libstate.SharedDomains) *StateReaderV3 {
+	r := NewStateReaderV3(sd)
+	r.ownTx = true
+	return r
+}
+
+func (r *StateReaderV3) DiscardReadList()      { r.discardReadList = true }
+func (r *StateReaderV3) SetTxNum(txNum uint64) { r.txNum = txNum }
+func (r *StateReaderV3) SetTx(tx kv.Tx) {
+	if r.ownTx {
+		r.tx = tx
+	}
+}
 func (r *StateReaderV3) ReadSet() map[string]*libstate.KvList { return r.readLists }
 func (r *StateReaderV3) SetTrace(trace bool)                  { r.trace = trace }
 func (r *StateReaderV3) ResetReadSet()                        { r.readLists = newReadList() }
 
+func (r *StateReaderV3) domainGet(domain kv.Domain, k []byte) ([]byte, error) {
+	if r.ownTx {
+		v, _, err := r.sd.DomainGetWithTx(r.tx, domain, k)
+		return v, err
+	}
+	v, _, err := r.sd.DomainGet(domain, k, nil)
+	return v, err
+}
+
 func (r *StateReaderV3) ReadAccountData(address common.Address) (*accounts.Account, error) {
-	enc, _, err := r.sd.DomainGet(kv.AccountsDomain, address[:], nil)
+	enc, err := r.domainGet(kv.AccountsDomain, address[:])
 	if err != nil {
 		return nil, err
 	}
@@ -617,7 +730,7 @@ func (r *StateReaderV3) ReadAccountData(address common.Address) (*accounts.Accou
 	}
 
 	var acc accounts.Account
-	if err := accounts.DeserialiseV3(&acc, enc); err != nil {
+	if err := accounts.DeserialiseState(&acc, enc); err != nil {
 		return nil, err
 	}
 	if r.trace {
@@ -628,7 +741,7 @@ func (r *StateReaderV3) ReadAccountData(address common.Address) (*accounts.Accou
 
 func (r *StateReaderV3) ReadAccountStorage(address common.Address, incarnation uint64, key *common.Hash) ([]byte, error) {
 	r.composite = append(append(r.composite[:0], address[:]...), key.Bytes()...)
-	enc, _, err := r.sd.DomainGet(kv.StorageDomain, r.composite, nil)
+	enc, err := r.domainGet(kv.StorageDomain, r.composite)
 	if err != nil {
 		return nil, err
 	}
@@ -642,11 +755,11 @@ func (r *StateReaderV3) ReadAccountStorage(address common.Address, incarnation u
 			fmt.Printf("ReadAccountStorage [%x] => [%x], txNum: %d\n", r.composite, enc, r.txNum)
 		}
 	}
-	return enc, nil
+	return statecodec.Active().DecodeStorage(enc), nil
 }
 
 func (r *StateReaderV3) ReadAccountCode(address common.Address, incarnation uint64, codeHash common.Hash) ([]byte, error) {
-	enc, _, err := r.sd.DomainGet(kv.CodeDomain, address[:], nil)
+	enc, err := r.domainGet(kv.CodeDomain, address[:])
 	if err != nil {
 		return nil, err
 	}
@@ -661,7 +774,7 @@ func (r *StateReaderV3) ReadAccountCode(address common.Address, incarnation uint
 }
 
 func (r *StateReaderV3) ReadAccountCodeSize(address common.Address, incarnation uint64, codeHash common.Hash) (int, error) {
-	enc, _, err := r.sd.DomainGet(kv.CodeDomain, address[:], nil)
+	enc, err := r.domainGet(kv.CodeDomain, address[:])
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+func TestStateWriterParallelV3(t *testing.T) {
+	t.Parallel()
+	w := NewStateWriterParallelV3()
+
+	addr := libcommon.HexToAddress("0x01")
+	original, account := accounts.NewAccount(), accounts.NewAccount()
+	account.Nonce = 1
+	require.NoError(t, w.UpdateAccountData(addr, &original, &account))
+	require.NoError(t, w.UpdateAccountCode(addr, 1, libcommon.Hash{}, []byte{0x60}))
+
+	key1, key2, key3 := libcommon.HexToHash("0x01"), libcommon.HexToHash("0x02"), libcommon.HexToHash("0x03")
+	require.NoError(t, w.WriteAccountStorage(addr, 1, &key1, uint256.NewInt(1), uint256.NewInt(1))) // unchanged: skipped
+	require.NoError(t, w.WriteAccountStorage(addr, 1, &key2, uint256.NewInt(1), uint256.NewInt(0))) // delete
+	require.NoError(t, w.WriteAccountStorage(addr, 1, &key3, uint256.NewInt(0), uint256.NewInt(7)))
+
+	ws := w.WriteSet()
+	require.Equal(t, []string{string(addr[:])}, ws[kv.AccountsDomain.String()].Keys)
+	require.Equal(t, accounts.SerialiseV3(&account), ws[kv.AccountsDomain.String()].Vals[0])
+	require.Equal(t, []byte{0x60}, ws[kv.CodeDomain.String()].Vals[0])
+
+	storage := ws[kv.StorageDomain.String()]
+	require.Equal(t, []string{string(append(addr.Bytes(), key2.Bytes()...)), string(append(addr.Bytes(), key3.Bytes()...))}, storage.Keys)
+	require.Nil(t, storage.Vals[0])
+	require.Equal(t, []byte{7}, storage.Vals[1])
+	require.False(t, w.NeedsReExec())
+
+	// re-creation of contract drops storage by prefix: can't be represented by write-set
+	original.Incarnation, account.Incarnation = 2, 1
+	require.NoError(t, w.UpdateAccountData(addr, &original, &account))
+	require.True(t, w.NeedsReExec())
+
+	w.ResetWriteSet()
+	require.False(t, w.NeedsReExec())
+	require.Empty(t, w.WriteSet()[kv.AccountsDomain.String()].Keys)
+}
@@ -3,6 +3,7 @@ package state
 import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 )
 
@@ -25,7 +26,7 @@ func (r *ReaderV4) ReadAccountData(address libcommon.Address) (*accounts.Account
 		return nil, nil
 	}
 	var a accounts.Account
-	if err = accounts.DeserialiseV3(&a, enc); err != nil {
+	if err = accounts.DeserialiseState(&a, enc); err != nil {
 		return nil, err
 	}
 	return &a, nil
@@ -39,7 +40,7 @@ func (r *ReaderV4) ReadAccountStorage(address libcommon.Address, incarnation uin
 	if len(enc) == 0 {
 		return nil, nil
 	}
-	return enc, nil
+	return statecodec.Active().DecodeStorage(enc), nil
 }
 
 func (r *ReaderV4) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (code []byte, err error) {
@@ -93,7 +94,7 @@ func (r *SimReaderV4) ReadAccountData(address libcommon.Address) (*accounts.Acco
 		return nil, nil
 	}
 	var a accounts.Account
-	if err = accounts.DeserialiseV3(&a, enc); err != nil {
+	if err = accounts.DeserialiseState(&a, enc); err != nil {
 		return nil, err
 	}
 	return &a, nil
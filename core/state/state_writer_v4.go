@@ -7,6 +7,7 @@ import (
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 )
 
@@ -36,7 +37,10 @@ func (w *WriterV4) UpdateAccountData(address libcommon.Address, original, accoun
 			return err
 		}
 	}
-	value := accounts.SerialiseV3(account)
+	value, err := accounts.SerialiseState(account)
+	if err != nil {
+		return err
+	}
 	return w.tx.DomainPut(kv.AccountsDomain, address.Bytes(), nil, value, nil, 0)
 }
 
@@ -58,7 +62,7 @@ func (w *WriterV4) WriteAccountStorage(address libcommon.Address, incarnation ui
 	if w.trace {
 		fmt.Printf("storage: %x,%x,%x\n", address, *key, value.Bytes())
 	}
-	return w.tx.DomainPut(kv.StorageDomain, address.Bytes(), key.Bytes(), value.Bytes(), nil, 0)
+	return w.tx.DomainPut(kv.StorageDomain, address.Bytes(), key.Bytes(), statecodec.Active().EncodeStorage(value.Bytes()), nil, 0)
 }
 
 func (w *WriterV4) CreateContract(address libcommon.Address) (err error) {
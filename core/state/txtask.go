@@ -51,8 +51,11 @@ type TxTask struct {
 	CodePrevs          map[string]uint64
 	Error              error
 	Logs               []*types.Log
+	FeeTransferLog     *types.Log // bor fee transfer log without fee recipient's balance, see vm.Config.DeferFeeCredit
 	TraceFroms         map[libcommon.Address]struct{}
 	TraceTos           map[libcommon.Address]struct{}
+	Creations          map[libcommon.Address]libcommon.Address // created contract -> deployer
+	SelfDestructs      map[libcommon.Address]libcommon.Address // self-destructed contract -> beneficiary
 
 	UsedGas uint64
 
@@ -73,8 +76,11 @@ func (t *TxTask) Reset() {
 	returnWriteList(t.WriteLists)
 	t.WriteLists = nil
 	t.Logs = nil
+	t.FeeTransferLog = nil
 	t.TraceFroms = nil
 	t.TraceTos = nil
+	t.Creations = nil
+	t.SelfDestructs = nil
 }
 
 // TxTaskQueue non-thread-safe priority-queue
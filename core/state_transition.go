@@ -351,7 +351,13 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	var input2 *uint256.Int
 	if st.isBor {
 		input1 = st.state.GetBalance(st.msg.From()).Clone()
-		input2 = st.state.GetBalance(coinbase).Clone()
+		if st.evm.Config().DeferFeeCredit {
+			// coinbase balance is filled into fee transfer log later (see SetFeeTransferLogRecipientBalance): reading it
+			// would make every tx depend on fees paid by previous txs of block
+			input2 = new(uint256.Int)
+		} else {
+			input2 = st.state.GetBalance(coinbase).Clone()
+		}
 	}
 
 	// First check this message satisfies all consensus rules before
@@ -0,0 +1,131 @@
+package stateless
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/commitment"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// Generate re-executes the block on top of the historical state of its parent and builds the witness of the
+// block. Proofs are generated from the commitment trie restored as of the parent, so the parent must be within
+// the commitment history kept in the db. proofCache is optional.
+func Generate(
+	ctx context.Context, tx kv.Tx, block *types.Block,
+	chainConfig *chain.Config, engine consensus.Engine, chainReader consensus.ChainReader,
+	proofCache *commitment.ProofCache, logger log.Logger,
+) (*Witness, error) {
+	blockNum := block.NumberU64()
+	if blockNum == 0 {
+		return nil, fmt.Errorf("genesis block has no witness")
+	}
+	parent := chainReader.GetHeader(block.ParentHash(), blockNum-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent of block %d not found", blockNum)
+	}
+
+	minTxNum, err := rawdbv3.TxNums.Min(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	historyReader := state.NewHistoryReaderV3()
+	historyReader.SetTx(tx)
+	historyReader.SetTxNum(minTxNum)
+	reader := newRecordingReader(historyReader)
+
+	headers := map[string]struct{}{}
+	if err := addHeader(headers, parent); err != nil {
+		return nil, err
+	}
+	getHeader := func(hash libcommon.Hash, number uint64) *types.Header {
+		h := chainReader.GetHeader(hash, number)
+		if h != nil {
+			if err := addHeader(headers, h); err != nil {
+				logger.Warn("[witness] failed to encode header", "number", number, "err", err)
+			}
+		}
+		return h
+	}
+
+	vmConfig := vm.Config{}
+	if _, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, core.GetHashFn(block.Header(), getHeader), engine, block, reader, state.NewNoopWriter(), chainReader, nil, logger); err != nil {
+		return nil, fmt.Errorf("re-execution of block %d: %w", blockNum, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	parentTxNum, err := rawdbv3.TxNums.Max(tx, blockNum-1)
+	if err != nil {
+		return nil, err
+	}
+	aggTx, ok := tx.(libstate.HasAggTx)
+	if !ok {
+		return nil, fmt.Errorf("witness of block %d: tx %T has no aggregator", blockNum, tx)
+	}
+	trie, err := aggTx.AggTx().(*libstate.AggregatorRoTx).CommitmentAsOf(ctx, tx, parentTxNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore commitment at block %d: %w", blockNum-1, err)
+	}
+	root, err := trie.RootHash()
+	if err != nil {
+		return nil, err
+	}
+	if libcommon.BytesToHash(root) != parent.Root {
+		return nil, fmt.Errorf("mismatch in expected state root computed %x vs %v at block %d", root, parent.Root, blockNum-1)
+	}
+
+	nodes := map[string]struct{}{}
+	keys := map[string]struct{}{}
+	addresses, slots := reader.sortedAccounts()
+	for i, address := range addresses {
+		storageKeys := make([][]byte, len(slots[i]))
+		for j := range slots[i] {
+			storageKeys[j] = slots[i][j][:]
+			keys[string(address[:])+string(storageKeys[j])] = struct{}{}
+		}
+		keys[string(address[:])] = struct{}{}
+
+		proof, err := trie.GenerateProof(address[:], storageKeys, proofCache)
+		if err != nil {
+			return nil, fmt.Errorf("witness of block %d: %w", blockNum, err)
+		}
+		for _, node := range proof.Proof {
+			nodes[string(node)] = struct{}{}
+		}
+		for _, storageProof := range proof.StorageProofs {
+			for _, node := range storageProof {
+				nodes[string(node)] = struct{}{}
+			}
+		}
+	}
+
+	return &Witness{
+		Headers: sortedSet(headers),
+		Codes:   sortedSet(reader.codes),
+		State:   sortedSet(nodes),
+		Keys:    sortedSet(keys),
+	}, nil
+}
+
+func addHeader(headers map[string]struct{}, h *types.Header) error {
+	enc, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		return err
+	}
+	headers[string(enc)] = struct{}{}
+	return nil
+}
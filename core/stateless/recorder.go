@@ -0,0 +1,89 @@
+package stateless
+
+import (
+	"bytes"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+// recordingReader - state reader recording accessed accounts, storage slots and codes
+type recordingReader struct {
+	state.StateReader
+	accounts map[libcommon.Address]map[libcommon.Hash]struct{}
+	codes    map[string]struct{}
+}
+
+func newRecordingReader(r state.StateReader) *recordingReader {
+	return &recordingReader{
+		StateReader: r,
+		accounts:    map[libcommon.Address]map[libcommon.Hash]struct{}{},
+		codes:       map[string]struct{}{},
+	}
+}
+
+func (r *recordingReader) touch(address libcommon.Address) map[libcommon.Hash]struct{} {
+	slots, ok := r.accounts[address]
+	if !ok {
+		slots = map[libcommon.Hash]struct{}{}
+		r.accounts[address] = slots
+	}
+	return slots
+}
+
+func (r *recordingReader) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	r.touch(address)
+	return r.StateReader.ReadAccountData(address)
+}
+
+func (r *recordingReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	r.touch(address)[*key] = struct{}{}
+	return r.StateReader.ReadAccountStorage(address, incarnation, key)
+}
+
+func (r *recordingReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	r.touch(address)
+	code, err := r.StateReader.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		r.codes[string(code)] = struct{}{}
+	}
+	return code, nil
+}
+
+// ReadAccountCodeSize - stateless client needs the whole code to know its size, so it's recorded as well
+func (r *recordingReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := r.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return 0, err
+	}
+	return len(code), nil
+}
+
+func (r *recordingReader) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	r.touch(address)
+	return r.StateReader.ReadAccountIncarnation(address)
+}
+
+// sortedAccounts - accessed accounts with their accessed storage slots, in the order of addresses and slots
+func (r *recordingReader) sortedAccounts() ([]libcommon.Address, [][]libcommon.Hash) {
+	addresses := make([]libcommon.Address, 0, len(r.accounts))
+	for address := range r.accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+
+	slots := make([][]libcommon.Hash, len(addresses))
+	for i, address := range addresses {
+		for slot := range r.accounts[address] {
+			slots[i] = append(slots[i], slot)
+		}
+		sort.Slice(slots[i], func(a, b int) bool { return bytes.Compare(slots[i][a][:], slots[i][b][:]) < 0 })
+	}
+	return addresses, slots
+}
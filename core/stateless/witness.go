@@ -0,0 +1,90 @@
+package stateless
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// Witness - everything needed to re-execute a block without the state: headers of the parent and of blocks
+// accessed by BLOCKHASH, codes of called contracts, trie nodes of proofs of accessed accounts and storage
+// against the parent state root, and the accessed keys themselves. All fields are sorted and deduplicated,
+// so witnesses of the same block produced by different nodes can be compared byte by byte.
+type Witness struct {
+	Headers [][]byte // rlp-encoded headers
+	Codes   [][]byte
+	State   [][]byte // rlp-encoded trie nodes
+	Keys    [][]byte // 20-byte addresses of accounts and 52-byte address+location of storage slots
+}
+
+type witnessJSON struct {
+	Headers []hexutility.Bytes `json:"headers"`
+	Codes   []hexutility.Bytes `json:"codes"`
+	State   []hexutility.Bytes `json:"state"`
+	Keys    []hexutility.Bytes `json:"keys"`
+}
+
+// EncodeRLP - format of witnesses in the ExecutionWitness table
+func (w *Witness) EncodeRLP() ([]byte, error) {
+	return rlp.EncodeToBytes(w)
+}
+
+// DecodeWitness decodes witness encoded by EncodeRLP
+func DecodeWitness(data []byte) (*Witness, error) {
+	w := &Witness{}
+	if err := rlp.DecodeBytes(data, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Witness) MarshalJSON() ([]byte, error) {
+	return json.Marshal(witnessJSON{
+		Headers: toHexSlice(w.Headers),
+		Codes:   toHexSlice(w.Codes),
+		State:   toHexSlice(w.State),
+		Keys:    toHexSlice(w.Keys),
+	})
+}
+
+func (w *Witness) UnmarshalJSON(data []byte) error {
+	var enc witnessJSON
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	w.Headers = fromHexSlice(enc.Headers)
+	w.Codes = fromHexSlice(enc.Codes)
+	w.State = fromHexSlice(enc.State)
+	w.Keys = fromHexSlice(enc.Keys)
+	return nil
+}
+
+func toHexSlice(in [][]byte) []hexutility.Bytes {
+	out := make([]hexutility.Bytes, len(in))
+	for i := range in {
+		out[i] = in[i]
+	}
+	return out
+}
+
+func fromHexSlice(in []hexutility.Bytes) [][]byte {
+	out := make([][]byte, len(in))
+	for i := range in {
+		out[i] = in[i]
+	}
+	return out
+}
+
+// sortedSet - deduplicated and sorted values of the set
+func sortedSet(set map[string]struct{}) [][]byte {
+	res := make([][]byte, 0, len(set))
+	for v := range set {
+		res = append(res, []byte(v))
+	}
+	sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i], res[j]) < 0 })
+	return res
+}
@@ -0,0 +1,35 @@
+package stateless
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWitnessEncoding(t *testing.T) {
+	w := &Witness{
+		Headers: [][]byte{{0xc0}},
+		Codes:   [][]byte{{0x60, 0x00}},
+		State:   [][]byte{{0x01, 0x02}, {0x03}},
+		Keys:    [][]byte{make([]byte, 20), make([]byte, 52)},
+	}
+
+	enc, err := w.EncodeRLP()
+	require.NoError(t, err)
+	dec, err := DecodeWitness(enc)
+	require.NoError(t, err)
+	require.Equal(t, w, dec)
+
+	js, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"headers":["0xc0"],"codes":["0x6000"],"state":["0x0102","0x03"],"keys":["0x0000000000000000000000000000000000000000","0x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"]}`, string(js))
+	var fromJSON Witness
+	require.NoError(t, json.Unmarshal(js, &fromJSON))
+	require.Equal(t, w, &fromJSON)
+}
+
+func TestSortedSet(t *testing.T) {
+	set := map[string]struct{}{"b": {}, "a": {}, "ab": {}}
+	require.Equal(t, [][]byte{[]byte("a"), []byte("ab"), []byte("b")}, sortedSet(set))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/holiman/uint256"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	rlp2 "github.com/ledgerwatch/erigon-lib/rlp"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/rlp"
@@ -788,3 +789,28 @@ func SerialiseV3To(a *Account, value []byte) {
 		}
 	}
 }
+
+// SerialiseState - encodes account as value of AccountsDomain with the codec of opened chaindata (see statecodec.Active)
+func SerialiseState(a *Account) ([]byte, error) {
+	var codeHash []byte
+	if !a.IsEmptyCodeHash() {
+		codeHash = a.CodeHash[:]
+	}
+	return statecodec.Active().EncodeAccount(a.Nonce, &a.Balance, codeHash, a.Incarnation)
+}
+
+// DeserialiseState - decodes value of AccountsDomain (or its history) with the codec of opened chaindata
+func DeserialiseState(a *Account, enc []byte) error {
+	a.Reset()
+	nonce, balance, codeHash, incarnation, err := statecodec.Active().DecodeAccount(enc)
+	if err != nil {
+		return err
+	}
+	a.Nonce = nonce
+	a.Balance.Set(balance)
+	a.Incarnation = incarnation
+	if codeHash != nil {
+		copy(a.CodeHash[:], codeHash)
+	}
+	return nil
+}
@@ -342,6 +342,44 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 
 }
 
+// receipt69RLP is the eth/69 network encoding of a receipt.
+type receipt69RLP struct {
+	Type              uint8
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*Log
+}
+
+// Receipt69 is a wrapper around a Receipt with the eth/69 network serialization: the type is a list item
+// instead of the envelope prefix, and the Bloom is omitted - deserialization re-computes it.
+type Receipt69 Receipt
+
+// EncodeRLP implements rlp.Encoder
+func (r *Receipt69) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &receipt69RLP{
+		Type:              r.Type,
+		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              r.Logs,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder
+func (r *Receipt69) DecodeRLP(s *rlp.Stream) error {
+	var dec receipt69RLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if err := (*Receipt)(r).setStatus(dec.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.Type = dec.Type
+	r.CumulativeGasUsed = dec.CumulativeGasUsed
+	r.Logs = dec.Logs
+	r.Bloom = CreateBloom(Receipts{(*Receipt)(r)})
+	return nil
+}
+
 // Receipts implements DerivableList for receipts.
 type Receipts []*Receipt
 
@@ -365,6 +365,71 @@ func TestTypedReceiptEncodingDecoding(t *testing.T) {
 	}
 }
 
+func TestReceipt69EncodingDecoding(t *testing.T) {
+	t.Parallel()
+	receipts := Receipts{
+		{
+			Type:              LegacyTxType,
+			Status:            ReceiptStatusFailed,
+			CumulativeGasUsed: 1,
+			Logs: []*Log{
+				{Address: libcommon.BytesToAddress([]byte{0x11}), Topics: []libcommon.Hash{libcommon.HexToHash("dead"), libcommon.HexToHash("beef")}, Data: []byte{0x01, 0x00, 0xff}},
+			},
+		},
+		{
+			Type:              DynamicFeeTxType,
+			PostState:         libcommon.Hash{2}.Bytes(),
+			CumulativeGasUsed: 2,
+			Logs:              []*Log{},
+		},
+		{
+			Type:              BlobTxType,
+			Status:            ReceiptStatusSuccessful,
+			CumulativeGasUsed: 3,
+			Logs: []*Log{
+				{Address: libcommon.BytesToAddress([]byte{0x22}), Topics: []libcommon.Hash{libcommon.HexToHash("cafe")}, Data: []byte{}},
+			},
+		},
+	}
+	for _, r := range receipts {
+		r.Bloom = CreateBloom(Receipts{r})
+	}
+
+	encoded := make([]*Receipt69, len(receipts))
+	for i, r := range receipts {
+		encoded[i] = (*Receipt69)(r)
+	}
+	enc, err := rlp.EncodeToBytes(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Less(t, len(enc), len(full), "blooms must be omitted")
+
+	var decoded []*Receipt69
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(receipts), len(decoded))
+	for i, r := range decoded {
+		want := receipts[i]
+		assert.Equal(t, want.Type, r.Type)
+		assert.Equal(t, want.Status, r.Status)
+		assert.Equal(t, want.PostState, r.PostState)
+		assert.Equal(t, want.CumulativeGasUsed, r.CumulativeGasUsed)
+		assert.Equal(t, want.Bloom, r.Bloom)
+		assert.Equal(t, len(want.Logs), len(r.Logs))
+		for j, l := range r.Logs {
+			assert.Equal(t, want.Logs[j].Address, l.Address)
+			assert.Equal(t, want.Logs[j].Topics, l.Topics)
+			assert.Equal(t, want.Logs[j].Data, l.Data)
+		}
+	}
+}
+
 func clearComputedFieldsOnReceipts(t *testing.T, receipts Receipts) {
 	t.Helper()
 
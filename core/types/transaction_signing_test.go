@@ -47,6 +47,40 @@ func TestEIP1559Signing(t *testing.T) {
 	}
 }
 
+func TestBlobTxSigning(t *testing.T) {
+	t.Parallel()
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	chainId := uint256.NewInt(18)
+	signer := LatestSignerForChainID(chainId.ToBig())
+	unsigned := &BlobTx{
+		DynamicFeeTransaction: *NewEIP1559Transaction(*chainId, 0, addr, new(uint256.Int), 0, new(uint256.Int), new(uint256.Int), new(uint256.Int), nil),
+		MaxFeePerBlobGas:      uint256.NewInt(1),
+		BlobVersionedHashes:   []libcommon.Hash{{0x01}},
+	}
+	tx, err := SignTx(unsigned, *signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobTx, ok := tx.(*BlobTx)
+	if !ok {
+		t.Fatalf("expected signed blob tx, got %T", tx)
+	}
+	if len(blobTx.BlobVersionedHashes) != 1 || blobTx.MaxFeePerBlobGas.Uint64() != 1 {
+		t.Errorf("blob fields lost after signing")
+	}
+
+	from, err := tx.Sender(*signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, addr)
+	}
+}
+
 func TestEIP155Signing(t *testing.T) {
 	t.Parallel()
 	key, _ := crypto.GenerateKey()
@@ -31,6 +31,10 @@ func SetupDbAccess(ctx *cli.Context, metricsMux *http.ServeMux) {
 		w.Header().Set("Content-Type", "application/json")
 		writeDbList(w, dataDir)
 	})
+	metricsMux.HandleFunc("/dbstats/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		writeDbStats(w, r, dataDir, strings.TrimPrefix(r.URL.Path, "/dbstats/"))
+	})
 	metricsMux.HandleFunc("/dbs/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 
@@ -188,6 +192,34 @@ func writeDbTables(w http.ResponseWriter, r *http.Request, dataDir string, dbnam
 	json.NewEncoder(w).Encode(tables)
 }
 
+func writeDbStats(w http.ResponseWriter, r *http.Request, dataDir string, dbname string) {
+	m := mdbx.PathDbMap()
+	db, ok := m[filepath.Join(dataDir, dbname)]
+	if !ok {
+		http.Error(w, fmt.Sprintf(`"%s" is not in the list of allowed dbs`, dbname), http.StatusNotFound)
+		return
+	}
+
+	// payload requires full scan of tables
+	withPayload := r.URL.Query().Get("payload") == "true"
+
+	var stats []mdbx.TableStats
+	if err := db.View(r.Context(), func(tx kv.Tx) error {
+		mdbxTx, ok := tx.(*mdbx.MdbxTx)
+		if !ok {
+			return fmt.Errorf("unexpected tx type %T", tx)
+		}
+		var e error
+		stats, e = mdbxTx.TablesStats(r.Context(), withPayload)
+		return e
+	}); err != nil {
+		http.Error(w, fmt.Sprintf(`failed to read stats of "%s": %v`, dbname, err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
 func writeDbRead(w http.ResponseWriter, r *http.Request, dataDir string, dbname string, table string, key []byte, offset int64, limit int64) {
 	m := mdbx.PathDbMap()
 	db, ok := m[filepath.Join(dataDir, dbname)]
@@ -102,4 +102,6 @@ func SetupEndpoints(ctx *cli.Context, node *node.ErigonNode, diagMux *http.Serve
 	SetupMemAccess(diagMux)
 	SetupHeadersAccess(diagMux, diagnostic)
 	SetupBodiesAccess(diagMux, diagnostic)
+	SetupStateSyncAccess(diagMux)
+	SetupSyncProgressAccess(diagMux, diagnostic)
 }
@@ -0,0 +1,32 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+func SetupStateSyncAccess(metricsMux *http.ServeMux) {
+	if metricsMux == nil {
+		return
+	}
+
+	metricsMux.HandleFunc("/bor/state-sync", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		writeStateSyncStatus(w)
+	})
+}
+
+// writeStateSyncStatus responds with 503 while the last fetch of state sync events failed, so the endpoint may be used as a health check
+func writeStateSyncStatus(w http.ResponseWriter) {
+	status := heimdall.GetStateSyncStatus()
+	if !status.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,93 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	diaglib "github.com/ledgerwatch/erigon-lib/diagnostics"
+)
+
+const (
+	syncProgressInterval    = time.Second
+	syncProgressMinInterval = 100 * time.Millisecond
+	syncProgressKeepAlive   = 15 * time.Second
+)
+
+func SetupSyncProgressAccess(metricsMux *http.ServeMux, diag *diaglib.DiagnosticClient) {
+	if metricsMux == nil {
+		return
+	}
+
+	metricsMux.HandleFunc("/sync-progress", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(diag.SyncProgress())
+	})
+
+	// server-sent events stream of the sync progress: an event is sent each interval (?interval=500ms, 1s by default)
+	// if the progress has changed
+	metricsMux.HandleFunc("/sync-progress/stream", func(w http.ResponseWriter, r *http.Request) {
+		streamSyncProgress(w, r, diag)
+	})
+}
+
+func streamSyncProgress(w http.ResponseWriter, r *http.Request, diag *diaglib.DiagnosticClient) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	interval := syncProgressInterval
+	if s := r.URL.Query().Get("interval"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid interval: %s", err), http.StatusBadRequest)
+			return
+		}
+		interval = max(d, syncProgressMinInterval)
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev []byte
+	lastSent := time.Now()
+	for {
+		progress, err := json.Marshal(diag.SyncProgress())
+		if err != nil {
+			return
+		}
+		if !bytes.Equal(progress, prev) {
+			if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", progress); err != nil {
+				return
+			}
+			prev = progress
+			lastSent = time.Now()
+			flusher.Flush()
+		} else if time.Since(lastSent) >= syncProgressKeepAlive {
+			// comment line, keeps proxies from closing an idle stream
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			lastSent = time.Now()
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/ledgerwatch/erigon/params"
 )
 
-const Version = 3
+const Version = 4
 
 func SetupVersionAccess(metricsMux *http.ServeMux) {
 	if metricsMux == nil {
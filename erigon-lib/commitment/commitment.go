@@ -9,7 +9,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/cryptozerocopy"
 	"github.com/ledgerwatch/erigon-lib/metrics"
-	"github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/log/v3"
 	"golang.org/x/crypto/sha3"
 	"math/bits"
@@ -85,7 +85,9 @@ func InitializeTrieAndUpdateTree(tv TrieVariant, mode Mode, tmpdir string) (Trie
 		fallthrough
 	default:
 		trie := NewHexPatriciaHashed(length.Addr, nil)
-		tree := NewUpdateTree(mode, tmpdir, trie.hashAndNibblizeKey)
+		// tree has own keccak: it may hash keys in background while trie is busy
+		keccak := sha3.NewLegacyKeccak256().(keccakState)
+		tree := NewUpdateTree(mode, tmpdir, func(key []byte) []byte { return hashAndNibblizeKey(keccak, key) })
 		return trie, tree
 	}
 }
@@ -800,6 +802,9 @@ type UpdateTree struct {
 	tree   *btree.BTreeG[*KeyUpdate]
 	mode   Mode
 	tmpdir string
+
+	fresh       []string         // ModeDirect: keys touched since last Precompute call
+	precomputer *keysPrecomputer // nil until first Precompute call of the batch
 }
 
 type keyHasher func(key []byte) []byte
@@ -842,11 +847,91 @@ func (t *UpdateTree) TouchPlainKey(key, val []byte, fn func(c *KeyUpdate, val []
 			t.tree.ReplaceOrInsert(pivot)
 		}
 	case ModeDirect:
-		t.keys[string(key)] = struct{}{}
+		if _, ok := t.keys[string(key)]; ok {
+			return
+		}
+		k := string(key)
+		t.keys[k] = struct{}{}
+		if t.precomputer != nil {
+			t.fresh = append(t.fresh, k)
+		}
 	default:
 	}
 }
 
+// Precompute - hands keys touched since previous call to background worker, which hashes and sorts them
+// while caller keeps touching new keys (e.g. executes next block). Next HashSort picks up the worker's result
+// and processes only keys touched after last Precompute. Values are not read in background, so resulting
+// commitment is the same. Only ModeDirect benefits from it - ModeUpdate hashes keys on touch.
+func (t *UpdateTree) Precompute() {
+	if t.mode != ModeDirect {
+		return
+	}
+	if t.precomputer == nil {
+		if len(t.keys) == 0 {
+			return
+		}
+		// first call of the batch - all keys touched so far are fresh
+		t.fresh = make([]string, 0, len(t.keys))
+		for k := range t.keys {
+			t.fresh = append(t.fresh, k)
+		}
+		t.precomputer = newKeysPrecomputer(t.tmpdir, t.hasher)
+	}
+	if len(t.fresh) == 0 {
+		return
+	}
+	t.precomputer.keys <- t.fresh
+	t.fresh = nil
+}
+
+// keysPrecomputer - background worker of UpdateTree.Precompute
+type keysPrecomputer struct {
+	hasher    keyHasher
+	collector *etl.Collector
+	keys      chan []string
+	done      chan error
+}
+
+func newKeysPrecomputer(tmpdir string, hasher keyHasher) *keysPrecomputer {
+	collector := etl.NewCollector("commitment", tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize/4), log.Root().New("update-tree"))
+	collector.LogLvl(log.LvlDebug)
+	collector.SortAndFlushInBackground(true)
+
+	p := &keysPrecomputer{hasher: hasher, collector: collector, keys: make(chan []string, 16), done: make(chan error, 1)}
+	go func() {
+		var err error
+		for keys := range p.keys {
+			if err != nil {
+				continue // drain
+			}
+			for _, k := range keys {
+				if err = p.collector.Collect(p.hasher([]byte(k)), []byte(k)); err != nil {
+					break
+				}
+			}
+		}
+		p.done <- err
+	}()
+	return p
+}
+
+// finish - waits until worker hashed all handed keys, then collects remaining ones. Caller owns the collector after that.
+func (p *keysPrecomputer) finish(remaining []string) (*etl.Collector, error) {
+	close(p.keys)
+	if err := <-p.done; err != nil {
+		p.collector.Close()
+		return nil, err
+	}
+	for _, k := range remaining {
+		if err := p.collector.Collect(p.hasher([]byte(k)), []byte(k)); err != nil {
+			p.collector.Close()
+			return nil, err
+		}
+	}
+	return p.collector, nil
+}
+
 func (t *UpdateTree) Size() (updates uint64) {
 	switch t.mode {
 	case ModeDirect:
@@ -866,7 +951,10 @@ func (t *UpdateTree) TouchAccount(c *KeyUpdate, val []byte) {
 	if c.update.Flags&DeleteUpdate != 0 {
 		c.update.Flags ^= DeleteUpdate
 	}
-	nonce, balance, chash := types.DecodeAccountBytesV3(val)
+	nonce, balance, chash, _, err := statecodec.Active().DecodeAccount(val)
+	if err != nil {
+		panic(fmt.Errorf("TouchAccount: %w", err))
+	}
 	if c.update.Nonce != nonce {
 		c.update.Nonce = nonce
 		c.update.Flags |= NonceUpdate
@@ -888,6 +976,7 @@ func (t *UpdateTree) TouchAccount(c *KeyUpdate, val []byte) {
 }
 
 func (t *UpdateTree) TouchStorage(c *KeyUpdate, val []byte) {
+	val = statecodec.Active().DecodeStorage(val)
 	c.update.ValLength = len(val)
 	if len(val) == 0 {
 		c.update.Flags = DeleteUpdate
@@ -913,6 +1002,12 @@ func (t *UpdateTree) TouchCode(c *KeyUpdate, val []byte) {
 }
 
 func (t *UpdateTree) Close() {
+	if t.precomputer != nil {
+		if collector, err := t.precomputer.finish(nil); err == nil {
+			collector.Close()
+		}
+		t.precomputer, t.fresh = nil, nil
+	}
 	if t.keys != nil {
 		clear(t.keys)
 	}
@@ -925,6 +1020,20 @@ func (t *UpdateTree) Close() {
 func (t *UpdateTree) HashSort(ctx context.Context, fn func(hk, pk []byte) error) error {
 	switch t.mode {
 	case ModeDirect:
+		if t.precomputer != nil {
+			collector, err := t.precomputer.finish(t.fresh)
+			t.precomputer, t.fresh = nil, nil
+			if err != nil {
+				return err
+			}
+			defer collector.Close()
+			clear(t.keys)
+
+			return collector.Load(nil, "", func(k, v []byte, table etl.CurrentTableReader, next etl.LoadNextFunc) error {
+				return fn(k, v)
+			}, etl.TransformArgs{Quit: ctx.Done()})
+		}
+
 		collector := etl.NewCollector("commitment", t.tmpdir, etl.NewSortableBuffer(etl.BufferOptimalSize/4), log.Root().New("update-tree"))
 		defer collector.Close()
 		collector.LogLvl(log.LvlDebug)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/hex"
+	"fmt"
 	"math/rand"
 	"sort"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
 )
 
 func generateCellRow(tb testing.TB, size int) (row []*Cell, bitmap uint16) {
@@ -384,3 +386,43 @@ func TestUpdateTree_TouchPlainKey(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestUpdateTree_Precompute(t *testing.T) {
+	hasher := func() keyHasher {
+		keccak := sha3.NewLegacyKeccak256().(keccakState)
+		return func(key []byte) []byte { return hashAndNibblizeKey(keccak, key) }
+	}
+	utDirect := NewUpdateTree(ModeDirect, t.TempDir(), hasher())
+	utPrecomputed := NewUpdateTree(ModeDirect, t.TempDir(), hasher())
+	defer utPrecomputed.Close()
+
+	collect := func(ut *UpdateTree) (hashed, plain [][]byte) {
+		err := ut.HashSort(context.Background(), func(hk, pk []byte) error {
+			hashed = append(hashed, common.Copy(hk))
+			plain = append(plain, common.Copy(pk))
+			return nil
+		})
+		require.NoError(t, err)
+		return hashed, plain
+	}
+
+	for batch := 0; batch < 2; batch++ {
+		for block := 0; block < 5; block++ {
+			for i := 0; i < 100; i++ {
+				// half of the keys are touched in several blocks
+				key := []byte(fmt.Sprintf("%020d", batch*1000+block*50+i))
+				utDirect.TouchPlainKey(key, nil, utDirect.TouchAccount)
+				utPrecomputed.TouchPlainKey(key, nil, utPrecomputed.TouchAccount)
+			}
+			utPrecomputed.Precompute()
+		}
+		require.Equal(t, utDirect.Size(), utPrecomputed.Size())
+
+		expectedHashed, expectedPlain := collect(utDirect)
+		hashed, plain := collect(utPrecomputed)
+		require.Len(t, plain, 300)
+		require.Equal(t, expectedHashed, hashed)
+		require.Equal(t, expectedPlain, plain)
+		require.Zero(t, utPrecomputed.Size())
+	}
+}
@@ -2005,21 +2005,25 @@ func commonPrefixLen(b1, b2 []byte) int {
 // nolint
 // Hashes provided key and expands resulting hash into nibbles (each byte split into two nibbles by 4 bits)
 func (hph *HexPatriciaHashed) hashAndNibblizeKey(key []byte) []byte {
+	return hashAndNibblizeKey(hph.keccak, key)
+}
+
+func hashAndNibblizeKey(keccak keccakState, key []byte) []byte {
 	hashedKey := make([]byte, length.Hash)
 
-	hph.keccak.Reset()
+	keccak.Reset()
 	fp := length.Addr
 	if len(key) < length.Addr {
 		fp = len(key)
 	}
-	hph.keccak.Write(key[:fp])
-	hph.keccak.Read(hashedKey[:length.Hash])
+	keccak.Write(key[:fp])
+	keccak.Read(hashedKey[:length.Hash])
 
 	if len(key[fp:]) > 0 {
 		hashedKey = append(hashedKey, make([]byte, length.Hash)...)
-		hph.keccak.Reset()
-		hph.keccak.Write(key[fp:])
-		hph.keccak.Read(hashedKey[length.Hash:])
+		keccak.Reset()
+		keccak.Write(key[fp:])
+		keccak.Read(hashedKey[length.Hash:])
 	}
 
 	nibblized := make([]byte, len(hashedKey)*2)
@@ -0,0 +1,321 @@
+package commitment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/rlp"
+)
+
+// AccountProof - merkle proofs of an account and of its storage slots in the form of eth_getProof (EIP-1186):
+// rlp encoded trie nodes starting from the root, nodes embedded into their parents are not listed separately.
+// Proof of an absent key ends with the node which proves the absence. Values are the ones committed
+// by the leaves, they are zero for absent keys.
+type AccountProof struct {
+	Proof         [][]byte
+	Nonce         uint64
+	Balance       uint256.Int
+	CodeHash      common.Hash
+	StorageHash   common.Hash
+	StorageProofs [][][]byte // in the order of requested storage keys
+	StorageValues [][]byte
+}
+
+// proofBranch - branch node loaded from the commitment domain together with its rlp encoding
+type proofBranch struct {
+	bitmap uint16
+	cells  [16]Cell
+	node   []byte
+}
+
+// ProofCache - branches loaded and hashed during proof generation. Branches are keyed by their
+// prefix and hash, so entries remain valid between different states of the trie and the cache
+// may be shared across requests for different blocks.
+type ProofCache struct {
+	branches *lru.Cache[string, *proofBranch]
+}
+
+func NewProofCache(size int) (*ProofCache, error) {
+	branches, err := lru.New[string, *proofBranch](size)
+	if err != nil {
+		return nil, err
+	}
+	return &ProofCache{branches: branches}, nil
+}
+
+func (c *ProofCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	return c.branches.Len()
+}
+
+// GenerateProof builds proofs of the account plainKey and of its storage keys (without the account prefix)
+// for the current state of the trie, which is expected to be set by SetState. Trie is not modified,
+// branches are read from PatriciaContext. cache is optional.
+func (hph *HexPatriciaHashed) GenerateProof(plainKey []byte, storageKeys [][]byte, cache *ProofCache) (*AccountProof, error) {
+	if len(plainKey) != hph.accountKeyLen {
+		return nil, fmt.Errorf("GenerateProof: account key length %d, expected %d", len(plainKey), hph.accountKeyLen)
+	}
+	rootHash, err := hph.RootHash()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &AccountProof{
+		StorageProofs: make([][][]byte, len(storageKeys)),
+		StorageValues: make([][]byte, len(storageKeys)),
+	}
+	root := hph.root
+	accountHashedKey := hph.hashAndNibblizeKey(plainKey)
+	accountCell, err := hph.proveKey(&root, 0, accountHashedKey, plainKey, cache, &res.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("account %x: %w", plainKey, err)
+	}
+	if len(res.Proof) > 0 && !bytes.Equal(hph.keccakOf(res.Proof[0]), rootHash) {
+		return nil, fmt.Errorf("account %x: proof does not match root hash %x", plainKey, rootHash)
+	}
+	if accountCell == nil {
+		// absent account has no storage
+		return res, nil
+	}
+	res.Nonce = accountCell.Nonce
+	res.Balance.Set(&accountCell.Balance)
+	res.CodeHash = accountCell.CodeHash
+
+	// storage trie of the account starts at depth 64 and its root is represented by the account cell
+	storageRoot := *accountCell
+	storageRoot.apl = 0
+	storageRootHash, err := hph.storageRootHash(&storageRoot)
+	if err != nil {
+		return nil, err
+	}
+	res.StorageHash = storageRootHash
+
+	storagePlainKey := make([]byte, hph.accountKeyLen+length.Hash)
+	copy(storagePlainKey, plainKey)
+	for i, storageKey := range storageKeys {
+		storagePlainKey = append(storagePlainKey[:hph.accountKeyLen], storageKey...)
+		hashedKey := hph.hashAndNibblizeKey(storagePlainKey)
+		cell := storageRoot
+		storageCell, err := hph.proveKey(&cell, 64, hashedKey, storagePlainKey, cache, &res.StorageProofs[i])
+		if err != nil {
+			return nil, fmt.Errorf("storage %x: %w", storagePlainKey, err)
+		}
+		if storageCell != nil {
+			res.StorageValues[i] = common.Copy(storageCell.Storage[:storageCell.StorageLen])
+		}
+		if len(res.StorageProofs[i]) > 0 && !bytes.Equal(hph.keccakOf(res.StorageProofs[i][0]), storageRootHash[:]) {
+			return nil, fmt.Errorf("storage %x: proof does not match storage root %x", storagePlainKey, storageRootHash)
+		}
+	}
+	return res, nil
+}
+
+// storageRootHash - hash of the storage trie rooted at the cell at depth 64
+func (hph *HexPatriciaHashed) storageRootHash(cell *Cell) (common.Hash, error) {
+	switch {
+	case cell.spl > 0:
+		node, err := hph.leafNode(cell, 64)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return common.BytesToHash(hph.keccakOf(node)), nil
+	case cell.extLen > 0:
+		if cell.hl == 0 {
+			return common.Hash{}, fmt.Errorf("storage root extension without hash")
+		}
+		hash, err := hph.extensionHash(cell.extension[:cell.extLen], cell.h[:cell.hl])
+		return common.Hash(hash), err
+	case cell.hl > 0:
+		return common.BytesToHash(cell.h[:cell.hl]), nil
+	default:
+		return common.BytesToHash(EmptyRootHash), nil
+	}
+}
+
+// proveKey follows hashedKey from the cell at the given depth and appends the visited nodes to proof.
+// Returns the leaf cell if the plainKey is present in the trie.
+func (hph *HexPatriciaHashed) proveKey(cell *Cell, depth int, hashedKey, plainKey []byte, cache *ProofCache, proof *[][]byte) (*Cell, error) {
+	start := len(*proof)
+	appendNode := func(node []byte) {
+		// nodes shorter than a hash are embedded into the parent
+		if len(*proof) == start || len(node) >= length.Hash {
+			*proof = append(*proof, node)
+		}
+	}
+
+	for {
+		if cell.apl > 0 || (depth >= 64 && cell.spl > 0) {
+			node, err := hph.leafNode(cell, depth)
+			if err != nil {
+				return nil, err
+			}
+			appendNode(node)
+			if (cell.apl > 0 && bytes.Equal(cell.apk[:cell.apl], plainKey)) || (cell.apl == 0 && bytes.Equal(cell.spk[:cell.spl], plainKey)) {
+				return cell, nil
+			}
+			return nil, nil
+		}
+		if cell.hl == 0 {
+			// empty trie
+			return nil, nil
+		}
+		if cell.extLen > 0 {
+			appendNode(extensionNode(cell.extension[:cell.extLen], cell.h[:cell.hl]))
+			if !bytes.HasPrefix(hashedKey[depth:], cell.extension[:cell.extLen]) {
+				return nil, nil
+			}
+			depth += cell.extLen
+		}
+
+		branch, err := hph.proofBranch(hashedKey[:depth], cell.h[:cell.hl], cache)
+		if err != nil {
+			return nil, err
+		}
+		appendNode(branch.node)
+		nibble := hashedKey[depth]
+		if branch.bitmap&(uint16(1)<<nibble) == 0 {
+			return nil, nil
+		}
+		child := branch.cells[nibble]
+		cell = &child
+		depth++
+	}
+}
+
+// proofBranch loads the branch at prefix and checks that it hashes to the expected hash
+func (hph *HexPatriciaHashed) proofBranch(prefix []byte, hash []byte, cache *ProofCache) (*proofBranch, error) {
+	key := hexToCompact(prefix)
+	if len(key) == 0 {
+		key = temporalReplacementForEmpty
+	}
+	cacheKey := string(key) + string(hash)
+	if cache != nil {
+		if branch, ok := cache.branches.Get(cacheKey); ok {
+			return branch, nil
+		}
+	}
+
+	branchData, _, err := hph.ctx.GetBranch(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(branchData) < 4 {
+		return nil, fmt.Errorf("branch not found, prefix %x", key)
+	}
+	branchData = branchData[2:] // skip touch map
+
+	branch := &proofBranch{bitmap: binary.BigEndian.Uint16(branchData[0:])}
+	depth := len(prefix) + 1
+	pos := 2
+	for bitset := branch.bitmap; bitset != 0; {
+		bit := bitset & -bitset
+		nibble := bits.TrailingZeros16(bit)
+		cell := &branch.cells[nibble]
+		fieldBits := branchData[pos]
+		pos++
+		if pos, err = cell.fillFromFields(branchData, pos, PartFlags(fieldBits)); err != nil {
+			return nil, fmt.Errorf("prefix [%x], branchData[%x]: %w", key, branchData, err)
+		}
+		if depth <= 64 && cell.apl > 0 {
+			if err = hph.ctx.GetAccount(cell.apk[:cell.apl], cell); err != nil {
+				return nil, fmt.Errorf("proofBranch GetAccount: %w", err)
+			}
+		} else {
+			cell.apl = 0
+		}
+		if cell.spl > 0 {
+			if err = hph.ctx.GetStorage(cell.spk[:cell.spl], cell); err != nil {
+				return nil, fmt.Errorf("proofBranch GetStorage: %w", err)
+			}
+		}
+		bitset ^= bit
+	}
+
+	branch.node = make([]byte, 0, 17*(length.Hash+1)+3)
+	var payload []byte
+	for nibble := 0; nibble < 16; nibble++ {
+		if branch.bitmap&(uint16(1)<<nibble) == 0 {
+			payload = append(payload, 0x80)
+			continue
+		}
+		cell := branch.cells[nibble] // computeCellHash overwrites downHashedKey
+		if payload, err = hph.computeCellHash(&cell, depth, payload); err != nil {
+			return nil, err
+		}
+	}
+	payload = append(payload, 0x80) // branches have no values
+	branch.node = appendRlpList(branch.node, payload)
+
+	if !bytes.Equal(hph.keccakOf(branch.node), hash) {
+		return nil, fmt.Errorf("branch %x hash mismatch: expected %x, got %x", key, hash, hph.keccakOf(branch.node))
+	}
+	if cache != nil {
+		cache.branches.Add(cacheKey, branch)
+	}
+	return branch, nil
+}
+
+// leafNode - rlp encoded account leaf or, if the cell has no account key, storage leaf
+func (hph *HexPatriciaHashed) leafNode(cell *Cell, depth int) ([]byte, error) {
+	var key [129]byte
+	var value []byte
+	var keyLen int
+	if cell.apl > 0 {
+		if err := hashKey(hph.keccak, cell.apk[:cell.apl], key[:], depth); err != nil {
+			return nil, err
+		}
+		keyLen = 64 - depth
+
+		storageRoot := *cell
+		storageRoot.apl = 0
+		storageRootHash, err := hph.storageRootHash(&storageRoot)
+		if err != nil {
+			return nil, err
+		}
+		var valBuf [128]byte
+		value = appendRlpString(nil, valBuf[:cell.accountForHashing(valBuf[:], storageRootHash)])
+	} else {
+		if err := hashKey(hph.keccak, cell.spk[hph.accountKeyLen:cell.spl], key[:], depth-64); err != nil {
+			return nil, err
+		}
+		keyLen = 128 - depth
+		value = appendRlpString(nil, appendRlpString(nil, cell.Storage[:cell.StorageLen]))
+	}
+	key[keyLen] = 16 // terminator
+
+	payload := appendRlpString(nil, hexToCompact(key[:keyLen+1]))
+	payload = append(payload, value...)
+	return appendRlpList(nil, payload), nil
+}
+
+func extensionNode(key []byte, hash []byte) []byte {
+	payload := appendRlpString(nil, hexToCompact(key))
+	payload = appendRlpString(payload, hash)
+	return appendRlpList(nil, payload)
+}
+
+func appendRlpString(buf []byte, s []byte) []byte {
+	enc := make([]byte, rlp.StringLen(s)+8) // EncodeString of long strings uses 8 bytes for the length
+	return append(buf, enc[:rlp.EncodeString(s, enc)]...)
+}
+
+func appendRlpList(buf []byte, payload []byte) []byte {
+	var prefix [10]byte
+	buf = append(buf, prefix[:rlp.EncodeListPrefix(len(payload), prefix[:])]...)
+	return append(buf, payload...)
+}
+
+func (hph *HexPatriciaHashed) keccakOf(data []byte) []byte {
+	hph.keccak.Reset()
+	hph.keccak.Write(data)
+	return hph.keccak.Sum(nil)
+}
@@ -0,0 +1,205 @@
+package commitment
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/rlp"
+)
+
+func Test_HexPatriciaHashed_GenerateProof(t *testing.T) {
+	ctx := context.Background()
+	ms := NewMockState(t)
+	rnd := rand.New(rand.NewSource(42))
+
+	randomHex := func(n int) string {
+		b := make([]byte, n)
+		rnd.Read(b)
+		return hex.EncodeToString(b)
+	}
+
+	builder := NewUpdateBuilder()
+	accounts := make([]string, 0, 256)
+	for i := 0; i < 256; i++ {
+		addr := randomHex(length.Addr)
+		accounts = append(accounts, addr)
+		builder.Balance(addr, uint64(i+1)).Nonce(addr, uint64(i))
+	}
+	// account with many slots, account with a single slot and small values, which are embedded into branches
+	storages := map[string][]string{accounts[0]: nil, accounts[1]: nil}
+	for i := 0; i < 200; i++ {
+		loc := randomHex(length.Hash)
+		builder.Storage(accounts[0], loc, randomHex(1+i%32))
+		storages[accounts[0]] = append(storages[accounts[0]], loc)
+	}
+	loc := randomHex(length.Hash)
+	builder.Storage(accounts[1], loc, "01")
+	storages[accounts[1]] = append(storages[accounts[1]], loc)
+
+	plainKeys, updates := builder.Build()
+	require.NoError(t, ms.applyPlainUpdates(plainKeys, updates))
+
+	trie := NewHexPatriciaHashed(length.Addr, ms)
+	rootHash, err := trie.ProcessKeys(ctx, plainKeys, "")
+	require.NoError(t, err)
+
+	state, err := trie.EncodeCurrentState(nil)
+	require.NoError(t, err)
+
+	// proofs are generated from the restored state, as for historical states
+	restored := NewHexPatriciaHashed(length.Addr, ms)
+	require.NoError(t, restored.SetState(state))
+
+	cache, err := NewProofCache(1024)
+	require.NoError(t, err)
+
+	absent := randomHex(length.Addr)
+	for i, addr := range append(slices.Clone(accounts[:16]), absent) {
+		slots := append(slices.Clone(storages[addr]), randomHex(length.Hash))
+		storageKeys := make([][]byte, 0, len(slots))
+		for _, slot := range slots {
+			storageKeys = append(storageKeys, decodeHex(slot))
+		}
+
+		proof, err := restored.GenerateProof(decodeHex(addr), storageKeys, cache)
+		require.NoError(t, err, addr)
+
+		account := verifyTestProof(t, rootHash, decodeHex(addr), proof.Proof)
+		if addr == absent {
+			require.Nil(t, account)
+			require.Zero(t, proof.Nonce)
+			require.Equal(t, make([][]byte, len(slots)), proof.StorageValues)
+			continue
+		}
+		require.NotNil(t, account, addr)
+		require.True(t, bytes.Contains(account, proof.StorageHash[:]))
+		require.Equal(t, uint64(i), proof.Nonce)
+		require.Equal(t, uint64(i+1), proof.Balance.Uint64())
+
+		for i, slot := range slots {
+			value := verifyTestProof(t, proof.StorageHash[:], storageKeys[i], proof.StorageProofs[i])
+			if i == len(slots)-1 {
+				require.Nil(t, value, "absent slot %s", slot)
+				continue
+			}
+			var expected [length.Hash + 1]byte
+			n := rlp.EncodeString(ms.storageValue(t, addr, slot), expected[:])
+			require.Equal(t, expected[:n], value, "slot %s", slot)
+			require.Equal(t, ms.storageValue(t, addr, slot), proof.StorageValues[i])
+		}
+	}
+	require.NotZero(t, cache.Len())
+
+	// proofs from the cache are the same
+	proof, err := restored.GenerateProof(decodeHex(accounts[0]), nil, cache)
+	require.NoError(t, err)
+	uncached, err := restored.GenerateProof(decodeHex(accounts[0]), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, uncached, proof)
+}
+
+func (ms *MockState) storageValue(t *testing.T, addr, loc string) []byte {
+	t.Helper()
+	var cell Cell
+	require.NoError(t, ms.GetStorage(append(decodeHex(addr), decodeHex(loc)...), &cell))
+	return cell.Storage[:cell.StorageLen]
+}
+
+// verifyTestProof checks the proof of the key against the root and returns the value of the leaf,
+// nil if the proof proves absence of the key
+func verifyTestProof(t *testing.T, root []byte, key []byte, proof [][]byte) []byte {
+	t.Helper()
+	if bytes.Equal(root, EmptyRootHash) {
+		require.Empty(t, proof)
+		return nil
+	}
+	keccak := sha3.NewLegacyKeccak256()
+	keccak.Write(key)
+	hashedKey := keybytesToHexNibbles(keccak.Sum(nil))
+	hashedKey = hashedKey[:len(hashedKey)-1]
+
+	ref := root
+	var node []byte
+	for i := 0; ; {
+		if len(ref) == length.Hash {
+			require.Less(t, i, len(proof), "proof is too short")
+			keccak.Reset()
+			keccak.Write(proof[i])
+			require.Equal(t, ref, keccak.Sum(nil), "node %d", i)
+			node = proof[i]
+			i++
+		} else {
+			node = ref // embedded
+		}
+
+		items := splitTestList(t, node)
+		switch len(items) {
+		case 17:
+			child := items[hashedKey[0]]
+			hashedKey = hashedKey[1:]
+			if bytes.Equal(child, []byte{0x80}) {
+				require.Equal(t, len(proof), i, "unused proof nodes")
+				return nil
+			}
+			ref = testRef(t, child)
+		case 2:
+			compact := testString(t, items[0])
+			path, leaf := CompactedKeyToHex(compact), compact[0]&0x20 != 0
+			if leaf {
+				path = path[:len(path)-1] // terminator
+			}
+			if !bytes.HasPrefix(hashedKey, path) || leaf && len(path) != len(hashedKey) {
+				require.Equal(t, len(proof), i, "unused proof nodes")
+				return nil
+			}
+			hashedKey = hashedKey[len(path):]
+			if leaf {
+				require.Equal(t, len(proof), i, "unused proof nodes")
+				return testString(t, items[1])
+			}
+			ref = testRef(t, items[1])
+		default:
+			t.Fatalf("unexpected node with %d items: %x", len(items), node)
+		}
+	}
+}
+
+// splitTestList returns the encoded items of the rlp list
+func splitTestList(t *testing.T, node []byte) [][]byte {
+	t.Helper()
+	pos, l, err := rlp.List(node, 0)
+	require.NoError(t, err)
+	var items [][]byte
+	for end := pos + l; pos < end; {
+		dataPos, dataLen, _, err := rlp.Prefix(node, pos)
+		require.NoError(t, err)
+		items = append(items, node[pos:dataPos+dataLen])
+		pos = dataPos + dataLen
+	}
+	return items
+}
+
+func testString(t *testing.T, item []byte) []byte {
+	t.Helper()
+	pos, l, err := rlp.String(item, 0)
+	require.NoError(t, err, fmt.Sprintf("%x", item))
+	return item[pos : pos+l]
+}
+
+// testRef - child reference, either a hash or an embedded node
+func testRef(t *testing.T, item []byte) []byte {
+	t.Helper()
+	if item[0] >= 0xc0 {
+		return item
+	}
+	return testString(t, item)
+}
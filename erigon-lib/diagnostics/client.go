@@ -24,6 +24,12 @@ type DiagnosticClient struct {
 	resourcesUsageMutex sync.Mutex
 	networkSpeed        NetworkSpeedTestResult
 	networkSpeedMutex   sync.Mutex
+
+	stagesProgress    map[string]*StageProgress
+	stagesOrder       []string
+	currentStage      string
+	aggregation       DomainAggregationStatus
+	syncProgressMutex sync.Mutex
 }
 
 func NewDiagnosticClient(metricsMux *http.ServeMux, dataDirPath string) *DiagnosticClient {
@@ -37,7 +43,8 @@ func NewDiagnosticClient(metricsMux *http.ServeMux, dataDirPath string) *Diagnos
 		resourcesUsage: ResourcesUsage{
 			MemoryUsage: []MemoryStats{},
 		},
-		peersStats: NewPeerStats(1000), // 1000 is the limit of peers; TODO: make it configurable through a flag
+		peersStats:     NewPeerStats(1000), // 1000 is the limit of peers; TODO: make it configurable through a flag
+		stagesProgress: map[string]*StageProgress{},
 	}
 }
 
@@ -54,6 +61,7 @@ func (d *DiagnosticClient) Setup() {
 	d.setupBodiesDiagnostics(rootCtx)
 	d.setupResourcesUsageDiagnostics(rootCtx)
 	d.setupSpeedtestDiagnostics(rootCtx)
+	d.setupSyncProgressDiagnostics(rootCtx)
 
 	//d.logDiagMsgs()
 }
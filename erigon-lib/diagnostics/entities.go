@@ -104,6 +104,13 @@ type SegmentPeer struct {
 type SnapshotIndexingStatistics struct {
 	Segments    []SnapshotSegmentIndexingStatistics `json:"segments"`
 	TimeElapsed float64                             `json:"timeElapsed"`
+	Progress    SnapshotIndexingProgress            `json:"progress"`
+}
+
+type SnapshotIndexingProgress struct {
+	Workers      int `json:"workers"`
+	FilesTotal   int `json:"filesTotal"`
+	FilesIndexed int `json:"filesIndexed"`
 }
 
 type SnapshotSegmentIndexingStatistics struct {
@@ -114,7 +121,9 @@ type SnapshotSegmentIndexingStatistics struct {
 }
 
 type SnapshotSegmentIndexingFinishedUpdate struct {
-	SegmentName string `json:"segmentName"`
+	SegmentName string                   `json:"segmentName"`
+	TimeElapsed float64                  `json:"timeElapsed"` // time spent on indexing of the segment
+	Progress    SnapshotIndexingProgress `json:"progress"`
 }
 
 type SyncStagesList struct {
@@ -144,6 +153,65 @@ type BlockExecutionStatistics struct {
 	TimeElapsed float64 `json:"timeElapsed"`
 }
 
+// StageProgressUpdate - block progress of a sync stage, sent by the stage while it runs
+type StageProgressUpdate struct {
+	Stage    string `json:"stage"`
+	From     uint64 `json:"from"`
+	Current  uint64 `json:"current"`
+	To       uint64 `json:"to"` // 0 if the target is not known yet
+	Finished bool   `json:"finished"`
+}
+
+// DomainAggregationUpdate - collation/build of the files of a step, or merge of files, by the aggregator
+type DomainAggregationUpdate struct {
+	Phase    string  `json:"phase"` // "build" or "merge"
+	Step     uint64  `json:"step"`  // step being built, or the first step of the merged range
+	ToStep   uint64  `json:"toStep"`
+	Finished bool    `json:"finished"`
+	Took     float64 `json:"took"` // seconds, once finished
+}
+
+type StageProgress struct {
+	Stage    string    `json:"stage"`
+	From     uint64    `json:"from"`
+	Current  uint64    `json:"current"`
+	To       uint64    `json:"to"`
+	Done     uint64    `json:"done"`  // blocks done in this run
+	Total    uint64    `json:"total"` // blocks to do in this run, 0 if unknown
+	Rate     float64   `json:"rate"`  // blocks per second
+	ETA      float64   `json:"eta"`   // seconds, 0 if unknown
+	Finished bool      `json:"finished"`
+	Updated  time.Time `json:"updated"`
+}
+
+type DownloadProgress struct {
+	Downloaded uint64  `json:"downloaded"`
+	Total      uint64  `json:"total"`
+	Rate       uint64  `json:"rate"` // bytes per second
+	ETA        float64 `json:"eta"`  // seconds, 0 if unknown
+	Peers      int32   `json:"peers"`
+	Finished   bool    `json:"finished"`
+}
+
+type DomainAggregationStatus struct {
+	Building      bool      `json:"building"`
+	BuildingStep  uint64    `json:"buildingStep"`
+	LastBuiltStep uint64    `json:"lastBuiltStep"`
+	LastBuildTook float64   `json:"lastBuildTook"` // seconds
+	Merging       bool      `json:"merging"`
+	MergeFromStep uint64    `json:"mergeFromStep"`
+	MergeToStep   uint64    `json:"mergeToStep"`
+	Updated       time.Time `json:"updated"`
+}
+
+// SyncProgress is the snapshot of the sync progress streamed to diagnostics UIs
+type SyncProgress struct {
+	CurrentStage string                  `json:"currentStage"`
+	Stages       []StageProgress         `json:"stages"`
+	Download     DownloadProgress        `json:"download"`
+	Aggregation  DomainAggregationStatus `json:"aggregation"`
+}
+
 type SnapshoFilesList struct {
 	Files []string `json:"files"`
 }
@@ -335,3 +403,11 @@ func (ti HeaderCanonicalMarkerUpdate) Type() Type {
 func (ti HeadersProcessedUpdate) Type() Type {
 	return TypeOf(ti)
 }
+
+func (ti StageProgressUpdate) Type() Type {
+	return TypeOf(ti)
+}
+
+func (ti DomainAggregationUpdate) Type() Type {
+	return TypeOf(ti)
+}
@@ -128,6 +128,7 @@ func (d *DiagnosticClient) runSegmentIndexingFinishedListener(rootCtx context.Co
 						Sys:         0,
 					})
 				}
+				d.syncStats.SnapshotIndexing.Progress = info.Progress
 				d.mu.Unlock()
 			}
 		}
@@ -159,6 +160,7 @@ func (d *DiagnosticClient) addOrUpdateSegmentIndexingState(upd SnapshotIndexingS
 	}
 
 	d.syncStats.SnapshotIndexing.TimeElapsed = upd.TimeElapsed
+	d.syncStats.SnapshotIndexing.Progress = upd.Progress
 }
 
 func (d *DiagnosticClient) runSnapshotFilesListListener(rootCtx context.Context) {
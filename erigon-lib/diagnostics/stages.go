@@ -45,6 +45,7 @@ func (d *DiagnosticClient) runCurrentSyncStageListener(rootCtx context.Context)
 				d.mu.Lock()
 				d.syncStats.SyncStages.CurrentStage = info.Stage
 				if int(d.syncStats.SyncStages.CurrentStage) >= len(d.syncStats.SyncStages.StagesList) {
+					d.mu.Unlock()
 					return
 				}
 				d.mu.Unlock()
@@ -0,0 +1,150 @@
+package diagnostics
+
+import (
+	"context"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+// rateSmoothing - weight of the latest measurement in the moving average of stage rates
+const rateSmoothing = 0.3
+
+func (d *DiagnosticClient) setupSyncProgressDiagnostics(rootCtx context.Context) {
+	d.runStageProgressListener(rootCtx)
+	d.runDomainAggregationListener(rootCtx)
+}
+
+func (d *DiagnosticClient) runStageProgressListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[StageProgressUpdate](rootCtx, 1)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(StageProgressUpdate{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				d.UpdateStageProgress(info, time.Now())
+			}
+		}
+	}()
+}
+
+func (d *DiagnosticClient) runDomainAggregationListener(rootCtx context.Context) {
+	go func() {
+		ctx, ch, closeChannel := Context[DomainAggregationUpdate](rootCtx, 1)
+		defer closeChannel()
+
+		StartProviders(ctx, TypeOf(DomainAggregationUpdate{}), log.Root())
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case info := <-ch:
+				d.UpdateDomainAggregation(info, time.Now())
+			}
+		}
+	}()
+}
+
+// UpdateStageProgress records the progress of a stage, the rate is averaged over the updates of the stage's run
+func (d *DiagnosticClient) UpdateStageProgress(info StageProgressUpdate, now time.Time) {
+	d.syncProgressMutex.Lock()
+	defer d.syncProgressMutex.Unlock()
+
+	stage, ok := d.stagesProgress[info.Stage]
+	if !ok {
+		stage = &StageProgress{Stage: info.Stage}
+		d.stagesProgress[info.Stage] = stage
+		d.stagesOrder = append(d.stagesOrder, info.Stage)
+	}
+
+	newRun := stage.Finished || info.From != stage.From || info.Current < stage.Current
+	if newRun {
+		stage.Rate = 0
+	} else if elapsed := now.Sub(stage.Updated).Seconds(); elapsed > 0 && info.Current > stage.Current {
+		rate := float64(info.Current-stage.Current) / elapsed
+		if stage.Rate == 0 {
+			stage.Rate = rate
+		} else {
+			stage.Rate = rateSmoothing*rate + (1-rateSmoothing)*stage.Rate
+		}
+	}
+
+	stage.From = info.From
+	stage.Current = info.Current
+	if info.To != 0 || newRun {
+		stage.To = info.To
+	}
+	stage.Finished = info.Finished
+	stage.Updated = now
+
+	if !info.Finished {
+		d.currentStage = info.Stage
+	} else if d.currentStage == info.Stage {
+		d.currentStage = ""
+	}
+}
+
+func (d *DiagnosticClient) UpdateDomainAggregation(info DomainAggregationUpdate, now time.Time) {
+	d.syncProgressMutex.Lock()
+	defer d.syncProgressMutex.Unlock()
+
+	agg := &d.aggregation
+	switch info.Phase {
+	case "build":
+		agg.Building = !info.Finished
+		agg.BuildingStep = info.Step
+		if info.Finished {
+			agg.LastBuiltStep = info.Step
+			agg.LastBuildTook = info.Took
+		}
+	case "merge":
+		agg.Merging = !info.Finished
+		agg.MergeFromStep = info.Step
+		agg.MergeToStep = info.ToStep
+	}
+	agg.Updated = now
+}
+
+// SyncProgress returns the progress of the stages, in the order they first reported, of the snapshots download
+// and of the files aggregation.
+func (d *DiagnosticClient) SyncProgress() SyncProgress {
+	d.syncProgressMutex.Lock()
+	progress := SyncProgress{
+		CurrentStage: d.currentStage,
+		Stages:       make([]StageProgress, 0, len(d.stagesOrder)),
+		Aggregation:  d.aggregation,
+	}
+	for _, name := range d.stagesOrder {
+		stage := *d.stagesProgress[name]
+		if stage.Current > stage.From {
+			stage.Done = stage.Current - stage.From
+		}
+		if stage.To > stage.From {
+			stage.Total = stage.To - stage.From
+		}
+		if !stage.Finished && stage.Rate > 0 && stage.To > stage.Current {
+			stage.ETA = float64(stage.To-stage.Current) / stage.Rate
+		}
+		progress.Stages = append(progress.Stages, stage)
+	}
+	d.syncProgressMutex.Unlock()
+
+	d.mu.Lock()
+	download := d.syncStats.SnapshotDownload
+	d.mu.Unlock()
+	progress.Download = DownloadProgress{
+		Downloaded: download.Downloaded,
+		Total:      download.Total,
+		Rate:       download.DownloadRate,
+		Peers:      download.Peers,
+		Finished:   download.DownloadFinished,
+	}
+	if !download.DownloadFinished && download.DownloadRate > 0 && download.Total > download.Downloaded {
+		progress.Download.ETA = float64(download.Total-download.Downloaded) / float64(download.DownloadRate)
+	}
+	return progress
+}
@@ -0,0 +1,64 @@
+package diagnostics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageProgressRateAndETA(t *testing.T) {
+	d := diagnostics.NewDiagnosticClient(nil, "test")
+	now := time.Now()
+
+	d.UpdateStageProgress(diagnostics.StageProgressUpdate{Stage: "Execution", From: 100, Current: 100}, now)
+	d.UpdateStageProgress(diagnostics.StageProgressUpdate{Stage: "Execution", From: 100, Current: 200, To: 1100}, now.Add(10*time.Second))
+
+	progress := d.SyncProgress()
+	require.Equal(t, "Execution", progress.CurrentStage)
+	require.Len(t, progress.Stages, 1)
+	stage := progress.Stages[0]
+	require.EqualValues(t, 100, stage.Done)
+	require.EqualValues(t, 1000, stage.Total)
+	require.InDelta(t, 10, stage.Rate, 0.001)
+	require.InDelta(t, 90, stage.ETA, 0.001)
+
+	// the rate is smoothed over the updates
+	d.UpdateStageProgress(diagnostics.StageProgressUpdate{Stage: "Execution", From: 100, Current: 400}, now.Add(20*time.Second))
+	stage = d.SyncProgress().Stages[0]
+	require.InDelta(t, 13, stage.Rate, 0.001)
+	require.EqualValues(t, 1100, stage.To, "target is kept until the next run")
+
+	d.UpdateStageProgress(diagnostics.StageProgressUpdate{Stage: "Execution", From: 100, Current: 1100, Finished: true}, now.Add(30*time.Second))
+	progress = d.SyncProgress()
+	require.Empty(t, progress.CurrentStage)
+	require.True(t, progress.Stages[0].Finished)
+	require.Zero(t, progress.Stages[0].ETA)
+
+	// next run starts over
+	d.UpdateStageProgress(diagnostics.StageProgressUpdate{Stage: "Execution", From: 1100, Current: 1100}, now.Add(40*time.Second))
+	stage = d.SyncProgress().Stages[0]
+	require.False(t, stage.Finished)
+	require.Zero(t, stage.Rate)
+	require.Zero(t, stage.Total)
+}
+
+func TestDomainAggregationStatus(t *testing.T) {
+	d := diagnostics.NewDiagnosticClient(nil, "test")
+	now := time.Now()
+
+	d.UpdateDomainAggregation(diagnostics.DomainAggregationUpdate{Phase: "build", Step: 5}, now)
+	agg := d.SyncProgress().Aggregation
+	require.True(t, agg.Building)
+	require.EqualValues(t, 5, agg.BuildingStep)
+
+	d.UpdateDomainAggregation(diagnostics.DomainAggregationUpdate{Phase: "build", Step: 5, Finished: true, Took: 3}, now)
+	d.UpdateDomainAggregation(diagnostics.DomainAggregationUpdate{Phase: "merge", Step: 0, ToStep: 6}, now)
+	agg = d.SyncProgress().Aggregation
+	require.False(t, agg.Building)
+	require.EqualValues(t, 5, agg.LastBuiltStep)
+	require.EqualValues(t, 3, agg.LastBuildTook)
+	require.True(t, agg.Merging)
+	require.EqualValues(t, 6, agg.MergeToStep)
+}
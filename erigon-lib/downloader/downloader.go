@@ -190,7 +190,7 @@ func (r *requestHandler) RoundTrip(req *http.Request) (resp *http.Response, err
 
 	insertCloudflareHeaders(req)
 
-	resp, err = r.Transport.RoundTrip(req)
+	resp, err = r.roundTrip(req)
 
 	attempts := 1
 	retry := true
@@ -241,7 +241,7 @@ func (r *requestHandler) RoundTrip(req *http.Request) (resp *http.Response, err
 			select {
 			case <-delayTimer.C:
 				// Note this assumes the req.Body is nil
-				resp, err = r.Transport.RoundTrip(req)
+				resp, err = r.roundTrip(req)
 				r.downloader.stats.WebseedTripCount.Add(1)
 
 			case <-req.Context().Done():
@@ -258,6 +258,29 @@ func (r *requestHandler) RoundTrip(req *http.Request) (resp *http.Response, err
 	return resp, err
 }
 
+// roundTrip - single http trip, result is accounted in webseed health
+func (r *requestHandler) roundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := r.Transport.RoundTrip(req)
+	if r.downloader == nil || r.downloader.webseeds == nil {
+		return resp, err
+	}
+
+	var failed bool
+	var bytes int64
+	switch {
+	case err != nil:
+		failed = !errors.Is(err, context.Canceled)
+	case resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusNotFound:
+		failed = true
+	default:
+		bytes = resp.ContentLength
+	}
+	r.downloader.webseeds.Health().Record(req.URL.Host, time.Since(start), bytes, failed)
+	return resp, err
+}
+
 func New(ctx context.Context, cfg *downloadercfg.Cfg, logger log.Logger, verbosity log.Lvl, discover bool) (*Downloader, error) {
 	requestHandler := &requestHandler{
 		Transport: http.Transport{
@@ -799,6 +822,40 @@ type downloadStatus struct {
 	err      error
 }
 
+func (d *Downloader) discoverWebSeeds() {
+	// webseeds.Discover may create new .torrent files on disk
+	d.webseeds.Discover(d.ctx, d.cfg.WebSeedFiles, d.cfg.Dirs.Snap)
+	// apply webseeds to existing torrents
+	if err := d.addTorrentFilesFromDisk(true); err != nil && !errors.Is(err, context.Canceled) {
+		d.logger.Warn("[snapshots] addTorrentFilesFromDisk", "err", err)
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, t := range d.torrentClient.Torrents() {
+		if urls, ok := d.webseeds.ByFileName(t.Name()); ok {
+			t.AddWebSeeds(urls)
+		}
+	}
+}
+
+// AddWebSeeds - hot-add webseed providers (without restart): their manifests are discovered in background
+// and files they serve are applied to existing torrents
+func (d *Downloader) AddWebSeeds(seeds []*url.URL) {
+	added := d.webseeds.AddSeeds(seeds)
+	if len(added) == 0 {
+		return
+	}
+	d.logger.Info("[snapshots] webseeds added", "count", len(added))
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.discoverWebSeeds()
+	}()
+}
+
 type seedHash struct {
 	url      *url.URL
 	hash     *infohash.T
@@ -812,21 +869,7 @@ func (d *Downloader) mainLoop(silent bool) error {
 		d.wg.Add(1)
 		go func() {
 			defer d.wg.Done()
-			// webseeds.Discover may create new .torrent files on disk
-			d.webseeds.Discover(d.ctx, d.cfg.WebSeedFiles, d.cfg.Dirs.Snap)
-			// apply webseeds to existing torrents
-			if err := d.addTorrentFilesFromDisk(true); err != nil && !errors.Is(err, context.Canceled) {
-				d.logger.Warn("[snapshots] addTorrentFilesFromDisk", "err", err)
-			}
-
-			d.lock.Lock()
-			defer d.lock.Unlock()
-
-			for _, t := range d.torrentClient.Torrents() {
-				if urls, ok := d.webseeds.ByFileName(t.Name()); ok {
-					t.AddWebSeeds(urls)
-				}
-			}
+			d.discoverWebSeeds()
 		}()
 	}
 
@@ -2684,8 +2727,8 @@ func (d *Downloader) StopSeeding(hash metainfo.Hash) error {
 
 func (d *Downloader) TorrentClient() *torrent.Client { return d.torrentClient }
 
-func openClient(ctx context.Context, dbDir, snapDir string, cfg *torrent.ClientConfig) (db kv.RwDB, c storage.PieceCompletion, m storage.ClientImplCloser, torrentClient *torrent.Client, err error) {
-	db, err = mdbx.NewMDBX(log.New()).
+func openDownloaderDB(ctx context.Context, dbDir string) (kv.RwDB, error) {
+	return mdbx.NewMDBX(log.New()).
 		Label(kv.DownloaderDB).
 		WithTableCfg(func(defaultBuckets kv.TableCfg) kv.TableCfg { return kv.DownloaderTablesCfg }).
 		GrowthStep(16 * datasize.MB).
@@ -2696,6 +2739,10 @@ func openClient(ctx context.Context, dbDir, snapDir string, cfg *torrent.ClientC
 		RoTxsLimiter(semaphore.NewWeighted(9_000)).
 		Path(dbDir).
 		Open(ctx)
+}
+
+func openClient(ctx context.Context, dbDir, snapDir string, cfg *torrent.ClientConfig) (db kv.RwDB, c storage.PieceCompletion, m storage.ClientImplCloser, torrentClient *torrent.Client, err error) {
+	db, err = openDownloaderDB(ctx, dbDir)
 	if err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("torrentcfg.openClient: %w", err)
 	}
@@ -19,6 +19,7 @@ package downloader
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
@@ -112,6 +113,23 @@ func (s *GrpcServer) Delete(ctx context.Context, request *proto_downloader.Delet
 	return &emptypb.Empty{}, nil
 }
 
+// AddWebSeeds - hot-add webseed urls (without restart). New providers are discovered in background
+func (s *GrpcServer) AddWebSeeds(ctx context.Context, request *proto_downloader.AddWebSeedsRequest) (*emptypb.Empty, error) {
+	seeds := make([]*url.URL, 0, len(request.Urls))
+	for _, u := range request.Urls {
+		if u == "" {
+			return nil, fmt.Errorf("field 'urls' contains empty url")
+		}
+		uri, err := url.ParseRequestURI(u)
+		if err != nil {
+			return nil, fmt.Errorf("webseed url is invalid: %s, %w", u, err)
+		}
+		seeds = append(seeds, uri)
+	}
+	s.d.AddWebSeeds(seeds)
+	return &emptypb.Empty{}, nil
+}
+
 func (s *GrpcServer) Verify(ctx context.Context, request *proto_downloader.VerifyRequest) (*emptypb.Empty, error) {
 	err := s.d.VerifyData(ctx, nil, false)
 	if err != nil {
@@ -8,7 +8,7 @@ var (
 			Current:      1,
 			MinSupported: 1,
 		},
-		indexes: []Index{CaplinIndexes.BeaconBlockSlot},
+		indexes: []Index{CaplinIndexes.BeaconBlockSlot, CaplinIndexes.BeaconBlockRoot},
 	}
 	BlobSidecars = snapType{
 		enum: CaplinEnums.BlobSidecars,
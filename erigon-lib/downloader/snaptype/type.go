@@ -119,9 +119,11 @@ type Index struct {
 
 var CaplinIndexes = struct {
 	BeaconBlockSlot,
+	BeaconBlockRoot,
 	BlobSidecarSlot Index
 }{
 	BeaconBlockSlot: Index{Name: "beaconblocks"},
+	BeaconBlockRoot: Index{Name: "beaconblockroots", Offset: 1},
 	BlobSidecarSlot: Index{Name: "blocksidecars"},
 }
 
@@ -0,0 +1,283 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/types/infohash"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// VerifyReportFileName - default location (inside `datadir/downloader`) of the report of `downloader verify`.
+// The report is also the progress of the verification: it's saved periodically, and next run continues
+// from where the previous one stopped.
+const VerifyReportFileName = "verify_report.json"
+
+// VerifyReport - machine-readable result of hashing local snapshot pieces against their .torrent metadata
+type VerifyReport struct {
+	Updated time.Time           `json:"updated"`
+	Files   []*FileVerifyReport `json:"files"`
+}
+
+type FileVerifyReport struct {
+	Name     string    `json:"name"`
+	InfoHash string    `json:"infoHash"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Missing  bool      `json:"missing,omitempty"` // no data file for the .torrent file
+
+	Pieces    int   `json:"pieces"`
+	Verified  int   `json:"verified"`            // pieces [0, Verified) are hashed - resume point
+	Corrupted []int `json:"corrupted,omitempty"` // indices of pieces with hash mismatch
+}
+
+func (f *FileVerifyReport) Done() bool { return f.Missing || f.Verified >= f.Pieces }
+
+// CorruptedFiles - files which have at least one corrupted piece or have no data at all
+func (r *VerifyReport) CorruptedFiles() (res []*FileVerifyReport) {
+	for _, f := range r.Files {
+		if f.Missing || len(f.Corrupted) > 0 {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func LoadVerifyReport(fPath string) (*VerifyReport, error) {
+	data, err := os.ReadFile(fPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &VerifyReport{}, nil
+		}
+		return nil, err
+	}
+	r := &VerifyReport{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("LoadVerifyReport: %w, file=%s", err, fPath)
+	}
+	return r, nil
+}
+
+func (r *VerifyReport) save(fPath string) error {
+	r.Updated = time.Now()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write+rename - to not lose progress if process killed in the middle of write
+	if err := os.WriteFile(fPath+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(fPath+".tmp", fPath)
+}
+
+// VerifyPieces - hashes all pieces of local snapshot files against their .torrent files, without starting a torrent client.
+// Progress and results are saved to reportPath. If reportPath already has a report: files which didn't change since
+// (same infohash, size and modification time) are not re-hashed - verification continues from the last saved piece.
+// whiteList - if not empty, only files with exactly or partially matching names are verified.
+func VerifyPieces(ctx context.Context, dirs datadir.Dirs, reportPath string, whiteList []string, logger log.Logger) (*VerifyReport, error) {
+	prev, err := LoadVerifyReport(reportPath)
+	if err != nil {
+		return nil, err
+	}
+	prevByName := make(map[string]*FileVerifyReport, len(prev.Files))
+	for _, f := range prev.Files {
+		prevByName[f.Name] = f
+	}
+
+	torrentPaths, err := AllTorrentPaths(dirs)
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{}
+	infos := map[string]*metainfo.Info{}
+	var total, resumed uint64
+	for _, fPath := range torrentPaths {
+		mi, err := metainfo.LoadFromFile(fPath)
+		if err != nil {
+			return nil, fmt.Errorf("VerifyPieces: %w, file=%s", err, fPath)
+		}
+		info, err := mi.UnmarshalInfo()
+		if err != nil {
+			return nil, fmt.Errorf("VerifyPieces: %w, file=%s", err, fPath)
+		}
+		name := info.Name
+		if len(whiteList) > 0 && !slices.ContainsFunc(whiteList, func(s string) bool {
+			return name == s || strings.HasSuffix(name, s) || strings.HasPrefix(name, s)
+		}) {
+			continue
+		}
+
+		f := &FileVerifyReport{Name: name, InfoHash: mi.HashInfoBytes().HexString(), Pieces: info.NumPieces()}
+		st, err := os.Stat(filepath.Join(dirs.Snap, name))
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			f.Missing = true
+		} else {
+			f.Size, f.ModTime = st.Size(), st.ModTime().UTC()
+		}
+		if p, ok := prevByName[name]; ok && !f.Missing && p.InfoHash == f.InfoHash && p.Size == f.Size && p.ModTime.Equal(f.ModTime) {
+			f.Verified, f.Corrupted = p.Verified, p.Corrupted
+			resumed += uint64(f.Verified)
+		}
+		report.Files = append(report.Files, f)
+		infos[name] = &info
+		total += uint64(f.Pieces)
+	}
+
+	logger.Info("[snapshots] Verify pieces start", "files", len(report.Files), "pieces", total, "resumed_pieces", resumed, "report", reportPath)
+
+	var mu sync.Mutex // guards report
+	completedPieces := &atomic.Uint64{}
+	completedPieces.Store(resumed)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(-1))
+	for _, f := range report.Files {
+		if f.Done() {
+			continue
+		}
+		f := f
+		g.Go(func() error {
+			return verifyFilePieces(gctx, dirs.Snap, infos[f.Name], f, &mu, completedPieces)
+		})
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for {
+		select {
+		case err = <-done:
+			mu.Lock()
+			saveErr := report.save(reportPath)
+			mu.Unlock()
+			if err != nil {
+				return report, err
+			}
+			if saveErr != nil {
+				return report, saveErr
+			}
+			logger.Info("[snapshots] Verify pieces done", "files", len(report.Files), "corrupted_files", len(report.CorruptedFiles()), "report", reportPath)
+			return report, nil
+		case <-logEvery.C:
+			mu.Lock()
+			err := report.save(reportPath)
+			mu.Unlock()
+			if err != nil {
+				logger.Warn("[snapshots] Verify pieces: can't save progress", "err", err)
+			}
+			logger.Info("[snapshots] Verify pieces",
+				"progress", fmt.Sprintf("%.2f%%", 100*float64(completedPieces.Load())/float64(total)),
+				"sz_gb", downloadercfg.DefaultPieceSize*completedPieces.Load()/1024/1024/1024,
+			)
+		}
+	}
+}
+
+func verifyFilePieces(ctx context.Context, root string, info *metainfo.Info, report *FileVerifyReport, mu *sync.Mutex, completedPieces *atomic.Uint64) error {
+	f, err := os.Open(filepath.Join(root, info.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	mu.Lock()
+	from := report.Verified
+	mu.Unlock()
+
+	hasher := sha1.New()
+	for i := from; i < info.NumPieces(); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		p := info.Piece(i)
+		hasher.Reset()
+		// short file is a corruption of its last pieces, not an error
+		if _, err := io.Copy(hasher, io.NewSectionReader(f, p.Offset(), p.Length())); err != nil {
+			return err
+		}
+		good := bytes.Equal(hasher.Sum(nil), p.Hash().Bytes())
+
+		mu.Lock()
+		if !good {
+			report.Corrupted = append(report.Corrupted, i)
+		}
+		report.Verified = i + 1
+		mu.Unlock()
+		completedPieces.Add(1)
+	}
+	return nil
+}
+
+// MarkCorruptedPieces - marks corrupted pieces from report as incomplete (and all other verified pieces as complete)
+// in downloader's db, so next start of downloader re-downloads only corrupted pieces - instead of whole files.
+// Missing files are not touched: downloader will download them as usual.
+func MarkCorruptedPieces(ctx context.Context, dirs datadir.Dirs, report *VerifyReport, logger log.Logger) error {
+	db, err := openDownloaderDB(ctx, dirs.Downloader)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(ctx, func(tx kv.RwTx) error {
+		for _, f := range report.CorruptedFiles() {
+			if f.Missing {
+				continue
+			}
+			if !f.Done() {
+				return fmt.Errorf("MarkCorruptedPieces: %s is not fully verified yet", f.Name)
+			}
+			var ih infohash.T
+			if err := ih.FromHexString(f.InfoHash); err != nil {
+				return fmt.Errorf("MarkCorruptedPieces: %w, file=%s", err, f.Name)
+			}
+
+			var key [infohash.Size + 4]byte
+			copy(key[:], ih[:])
+			for i := 0; i < f.Pieces; i++ {
+				binary.BigEndian.PutUint32(key[infohash.Size:], uint32(i))
+				v := []byte(complete)
+				if _, corrupted := slices.BinarySearch(f.Corrupted, i); corrupted {
+					v = []byte(incomplete)
+				}
+				if err := tx.Put(kv.BittorrentCompletion, key[:], v); err != nil {
+					return err
+				}
+			}
+			if err := torrentInfoReset(f.Name, ih.Bytes(), 0)(tx); err != nil {
+				return err
+			}
+			logger.Info("[snapshots] marked corrupted pieces for re-download", "file", f.Name, "pieces", len(f.Corrupted))
+		}
+		return nil
+	})
+}
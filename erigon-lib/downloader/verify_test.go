@@ -0,0 +1,75 @@
+package downloader
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anacrolix/torrent/types/infohash"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/downloader/downloadercfg"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPieces(t *testing.T) {
+	require := require.New(t)
+	dirs := datadir.New(t.TempDir())
+	ctx := context.Background()
+	logger := log.New()
+
+	fPath := filepath.Join(dirs.Snap, "v1-000000-000500-headers.seg")
+	data := make([]byte, 3*downloadercfg.DefaultPieceSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	require.NoError(os.WriteFile(fPath, data, 0644))
+	_, err := BuildTorrentIfNeed(ctx, "v1-000000-000500-headers.seg", dirs.Snap, NewAtomicTorrentFS(dirs.Snap))
+	require.NoError(err)
+
+	reportPath := filepath.Join(dirs.Downloader, VerifyReportFileName)
+	report, err := VerifyPieces(ctx, dirs, reportPath, nil, logger)
+	require.NoError(err)
+	require.Len(report.Files, 1)
+	require.Empty(report.CorruptedFiles())
+	require.Equal(4, report.Files[0].Verified)
+
+	// corrupt 2nd piece
+	data[downloadercfg.DefaultPieceSize+1]++
+	require.NoError(os.WriteFile(fPath, data, 0644))
+	report, err = VerifyPieces(ctx, dirs, reportPath, nil, logger)
+	require.NoError(err)
+	require.Len(report.CorruptedFiles(), 1)
+	require.Equal([]int{1}, report.Files[0].Corrupted)
+
+	// unchanged file is resumed from report, not re-hashed
+	saved, err := LoadVerifyReport(reportPath)
+	require.NoError(err)
+	saved.Files[0].Verified, saved.Files[0].Corrupted = 3, []int{0}
+	require.NoError(saved.save(reportPath))
+	report, err = VerifyPieces(ctx, dirs, reportPath, nil, logger)
+	require.NoError(err)
+	require.Equal([]int{0}, report.Files[0].Corrupted)
+	require.Equal(4, report.Files[0].Verified)
+
+	require.NoError(MarkCorruptedPieces(ctx, dirs, report, logger))
+	db, err := openDownloaderDB(ctx, dirs.Downloader)
+	require.NoError(err)
+	defer db.Close()
+	var ih infohash.T
+	require.NoError(ih.FromHexString(report.Files[0].InfoHash))
+	require.NoError(db.View(ctx, func(tx kv.Tx) error {
+		var key [infohash.Size + 4]byte
+		copy(key[:], ih[:])
+		for i, expect := range []string{incomplete, complete, complete, complete} {
+			binary.BigEndian.PutUint32(key[infohash.Size:], uint32(i))
+			v, err := tx.GetOne(kv.BittorrentCompletion, key[:])
+			require.NoError(err)
+			require.Equal(expect, string(v))
+		}
+		return nil
+	}))
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -37,6 +38,7 @@ type WebSeeds struct {
 	downloadTorrentFile bool
 	torrentsWhitelist   snapcfg.Preverified
 	seeds               []*url.URL
+	health              *WebSeedHealth
 
 	logger    log.Logger
 	verbosity log.Lvl
@@ -48,6 +50,7 @@ type WebSeeds struct {
 func NewWebSeeds(seeds []*url.URL, verbosity log.Lvl, logger log.Logger) *WebSeeds {
 	ws := &WebSeeds{
 		seeds:     seeds,
+		health:    NewWebSeedHealth(),
 		logger:    logger,
 		verbosity: verbosity,
 	}
@@ -62,7 +65,7 @@ func NewWebSeeds(seeds []*url.URL, verbosity log.Lvl, logger log.Logger) *WebSee
 func (d *WebSeeds) getWebDownloadInfo(ctx context.Context, t *torrent.Torrent) (infos []webDownloadInfo, seedHashMismatches []*seedHash, err error) {
 	torrentHash := t.InfoHash().Bytes()
 
-	for _, webseed := range d.seeds {
+	for _, webseed := range d.Seeds() {
 		downloadUrl := webseed.JoinPath(t.Name())
 
 		if headRequest, err := http.NewRequestWithContext(ctx, http.MethodHead, downloadUrl.String(), nil); err == nil {
@@ -108,6 +111,30 @@ func (d *WebSeeds) getWebDownloadInfo(ctx context.Context, t *torrent.Torrent) (
 	return infos, seedHashMismatches, nil
 }
 
+// Seeds - webseed providers, healthiest first
+func (d *WebSeeds) Seeds() []*url.URL {
+	d.lock.Lock()
+	seeds := d.seeds
+	d.lock.Unlock()
+	return d.health.Sort(seeds)
+}
+
+// AddSeeds - hot-add webseed providers. Returns only new ones
+func (d *WebSeeds) AddSeeds(seeds []*url.URL) (added []*url.URL) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, seed := range seeds {
+		if slices.ContainsFunc(d.seeds, func(u *url.URL) bool { return u.String() == seed.String() }) {
+			continue
+		}
+		d.seeds = append(d.seeds, seed)
+		added = append(added, seed)
+	}
+	return added
+}
+
+func (d *WebSeeds) Health() *WebSeedHealth { return d.health }
+
 func (d *WebSeeds) SetTorrent(torrentFS *AtomicTorrentFS, whiteList snapcfg.Preverified, downloadTorrentFile bool) {
 	d.downloadTorrentFile = downloadTorrentFile
 	d.torrentsWhitelist = whiteList
@@ -324,7 +351,7 @@ func (d *WebSeeds) VerifyManifestedBucket(ctx context.Context, webSeedProviderUR
 }
 
 func (d *WebSeeds) Discover(ctx context.Context, files []string, rootDir string) {
-	listsOfFiles := d.constructListsOfFiles(ctx, d.seeds, files)
+	listsOfFiles := d.constructListsOfFiles(ctx, d.Seeds(), files)
 	torrentMap := d.makeTorrentUrls(listsOfFiles)
 	webSeedMap := d.downloadTorrentFilesFromProviders(ctx, rootDir, torrentMap)
 	d.makeWebSeedUrls(listsOfFiles, webSeedMap)
@@ -436,7 +463,10 @@ func (d *WebSeeds) ByFileName(name string) (metainfo.UrlList, bool) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 	v, ok := d.byFileName[name]
-	return v, ok
+	if !ok {
+		return nil, false
+	}
+	return d.health.Healthy(v), true
 }
 
 var ErrInvalidEtag = fmt.Errorf("invalid etag")
@@ -0,0 +1,168 @@
+package downloader
+
+import (
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// webSeedHealthAlpha - weight of the latest sample in moving averages
+	webSeedHealthAlpha = 0.2
+	// webSeedMinSamples - don't judge mirror until it served this amount of requests
+	webSeedMinSamples = 10
+	// webSeedBrokenErrRate - mirror with higher (moving) error rate is considered broken
+	webSeedBrokenErrRate = 0.5
+	// webSeedBrokenCooldown - broken mirror gets next chance after this period
+	webSeedBrokenCooldown = 5 * time.Minute
+)
+
+// webSeedStats - health of one webseed mirror (host). All averages are exponentially weighted
+type webSeedStats struct {
+	requests   uint64
+	failures   uint64
+	bytes      uint64
+	errRate    float64
+	latency    time.Duration
+	throughput float64 // bytes per second
+	brokenAt   time.Time
+}
+
+// WebSeedHealth - tracks latency, error rate and throughput of webseed mirrors.
+// Used to order mirrors from best to worst and to deprioritize broken ones
+type WebSeedHealth struct {
+	lock  sync.Mutex
+	hosts map[string]*webSeedStats
+	now   func() time.Time
+}
+
+func NewWebSeedHealth() *WebSeedHealth {
+	return &WebSeedHealth{hosts: map[string]*webSeedStats{}, now: time.Now}
+}
+
+func ewma(avg, sample float64) float64 { return avg + webSeedHealthAlpha*(sample-avg) }
+
+// Record - account result of one http round trip to the mirror
+func (h *WebSeedHealth) Record(host string, latency time.Duration, bytes int64, failed bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s, ok := h.hosts[host]
+	if !ok {
+		s = &webSeedStats{latency: latency}
+		h.hosts[host] = s
+	}
+	s.requests++
+	var errSample float64
+	if failed {
+		s.failures++
+		errSample = 1
+	} else {
+		s.latency = time.Duration(ewma(float64(s.latency), float64(latency)))
+		if bytes > 0 {
+			s.bytes += uint64(bytes)
+			if latency > 0 {
+				s.throughput = ewma(s.throughput, float64(bytes)/latency.Seconds())
+			}
+		}
+	}
+	s.errRate = ewma(s.errRate, errSample)
+
+	switch {
+	case s.requests >= webSeedMinSamples && s.errRate > webSeedBrokenErrRate:
+		if s.brokenAt.IsZero() {
+			s.brokenAt = h.now()
+		}
+	case !failed:
+		s.brokenAt = time.Time{}
+	}
+}
+
+// Broken - mirror failed too often recently. It's re-checked after cooldown
+func (h *WebSeedHealth) Broken(host string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.broken(host)
+}
+
+func (h *WebSeedHealth) broken(host string) bool {
+	s, ok := h.hosts[host]
+	if !ok || s.brokenAt.IsZero() {
+		return false
+	}
+	return h.now().Sub(s.brokenAt) < webSeedBrokenCooldown
+}
+
+// Score - higher is better. Unknown mirrors get neutral score - to give them a chance
+func (h *WebSeedHealth) Score(host string) float64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.score(host)
+}
+
+func (h *WebSeedHealth) score(host string) float64 {
+	s, ok := h.hosts[host]
+	if !ok || s.requests == 0 {
+		return 1
+	}
+	if h.broken(host) {
+		return 0
+	}
+	score := 1 - s.errRate
+	if s.latency > 0 {
+		// 1 at zero latency, 0.5 at 1 second
+		score *= 1 / (1 + s.latency.Seconds())
+	}
+	if s.throughput > 0 {
+		// mirrors with better throughput win ties
+		score *= 1 + s.throughput/(s.throughput+float64(1<<20))
+	}
+	return score
+}
+
+// Sort - order urls from healthiest mirror to most broken. Broken mirrors are not removed:
+// they stay at the end of list as a last resort
+func (h *WebSeedHealth) Sort(urls []*url.URL) []*url.URL {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	res := make([]*url.URL, len(urls))
+	copy(res, urls)
+	sort.SliceStable(res, func(i, j int) bool { return h.score(res[i].Host) > h.score(res[j].Host) })
+	return res
+}
+
+// SortStrings - same as Sort, but for raw urls. Unparsable urls go to the end
+func (h *WebSeedHealth) SortStrings(urls []string) []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	scores := make(map[string]float64, len(urls))
+	for _, u := range urls {
+		if parsed, err := url.Parse(u); err == nil {
+			scores[u] = h.score(parsed.Host)
+		} else {
+			scores[u] = -1
+		}
+	}
+	res := make([]string, len(urls))
+	copy(res, urls)
+	sort.SliceStable(res, func(i, j int) bool { return scores[res[i]] > scores[res[j]] })
+	return res
+}
+
+// Healthy - urls of mirrors which are not broken. If all are broken - returns all of them (best first)
+func (h *WebSeedHealth) Healthy(urls []string) []string {
+	sorted := h.SortStrings(urls)
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	res := make([]string, 0, len(sorted))
+	for _, u := range sorted {
+		if parsed, err := url.Parse(u); err == nil && h.broken(parsed.Host) {
+			continue
+		}
+		res = append(res, u)
+	}
+	if len(res) == 0 {
+		return sorted
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package downloader
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSeedHealth(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	h := NewWebSeedHealth()
+	h.now = func() time.Time { return now }
+
+	for i := 0; i < 20; i++ {
+		h.Record("fast.com", 50*time.Millisecond, 1<<20, false)
+		h.Record("slow.com", 2*time.Second, 1<<20, false)
+		h.Record("broken.com", 100*time.Millisecond, 0, true)
+	}
+	require.False(t, h.Broken("fast.com"))
+	require.False(t, h.Broken("slow.com"))
+	require.True(t, h.Broken("broken.com"))
+	require.Greater(t, h.Score("fast.com"), h.Score("slow.com"))
+	require.Greater(t, h.Score("unknown.com"), h.Score("slow.com"))
+	require.Zero(t, h.Score("broken.com"))
+
+	mustParse := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		require.NoError(t, err)
+		return u
+	}
+	sorted := h.Sort([]*url.URL{mustParse("https://broken.com/v1"), mustParse("https://slow.com/v1"), mustParse("https://fast.com/v1")})
+	require.Equal(t, []string{"fast.com", "slow.com", "broken.com"}, []string{sorted[0].Host, sorted[1].Host, sorted[2].Host})
+
+	urls := []string{"https://broken.com/a.seg", "https://slow.com/a.seg", "https://fast.com/a.seg"}
+	require.Equal(t, []string{"https://fast.com/a.seg", "https://slow.com/a.seg"}, h.Healthy(urls))
+	// all mirrors broken - keep them as a last resort
+	require.Equal(t, []string{"https://broken.com/a.seg"}, h.Healthy([]string{"https://broken.com/a.seg"}))
+
+	// broken mirror gets next chance after cooldown
+	now = now.Add(webSeedBrokenCooldown)
+	require.False(t, h.Broken("broken.com"))
+
+	// and recovers after successful requests
+	now = now.Add(-webSeedBrokenCooldown)
+	for i := 0; i < 20; i++ {
+		h.Record("broken.com", 100*time.Millisecond, 1<<20, false)
+	}
+	require.False(t, h.Broken("broken.com"))
+}
@@ -0,0 +1,60 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package etl
+
+import (
+	"sync/atomic"
+
+	"github.com/c2h5oh/datasize"
+)
+
+// MemoryBudget - RAM held by the buffers of all collectors sharing it (including the ones being flushed in background).
+// When the budget is exceeded - collector spills its buffer before reaching buffer's own size limit, so
+// stages running many collectors in parallel don't need `N * BufferOptimalSize` of RAM.
+type MemoryBudget struct {
+	limit atomic.Int64 // 0 - means unlimited, only accounting
+	used  atomic.Int64
+	peak  atomic.Int64
+}
+
+// DefaultMemoryBudget - shared by all collectors created by NewCollector
+var DefaultMemoryBudget = NewMemoryBudget(0)
+
+func NewMemoryBudget(limit datasize.ByteSize) *MemoryBudget {
+	b := &MemoryBudget{}
+	b.SetLimit(limit)
+	return b
+}
+
+func (b *MemoryBudget) SetLimit(limit datasize.ByteSize) { b.limit.Store(int64(limit.Bytes())) }
+func (b *MemoryBudget) Limit() datasize.ByteSize         { return datasize.ByteSize(b.limit.Load()) }
+func (b *MemoryBudget) Used() datasize.ByteSize          { return datasize.ByteSize(b.used.Load()) }
+func (b *MemoryBudget) Peak() datasize.ByteSize          { return datasize.ByteSize(b.peak.Load()) }
+
+// reserve accounts `delta` bytes (can be negative) and returns true if the budget is exceeded
+func (b *MemoryBudget) reserve(delta int) (exceeded bool) {
+	used := b.used.Add(int64(delta))
+	for peak := b.peak.Load(); used > peak; peak = b.peak.Load() {
+		if b.peak.CompareAndSwap(peak, used) {
+			break
+		}
+	}
+	limit := b.limit.Load()
+	return limit > 0 && used > limit
+}
+
+func (b *MemoryBudget) release(n int) { b.used.Add(-int64(n)) }
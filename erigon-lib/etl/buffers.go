@@ -49,6 +49,7 @@ type Buffer interface {
 	Get(i int, keyBuf, valBuf []byte) ([]byte, []byte)
 	Len() int
 	Reset()
+	Size() int // RAM used by the buffer
 	SizeLimit() int
 	Prealloc(predictKeysAmount, predictDataAmount int)
 	Write(io.Writer) error
@@ -24,10 +24,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/c2h5oh/datasize"
 	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/dir"
@@ -55,6 +57,31 @@ type Collector struct {
 	//   - if disk is over-loaded - app may have much background threads which waiting for flush - and each thread whill hold own `buf` (can't free RAM until flush is done)
 	//   - enable it only when writing to `etl` is a bottleneck and unlikely to have many parallel collectors (to not overload CPU/Disk)
 	sortAndFlushInBackground bool
+
+	compression Compression
+	budget      *MemoryBudget
+	reserved    int // bytes of `buf` accounted in `budget`
+	stats       collectorStats
+}
+
+// CollectorStats - what the collector did so far, useful to tune buffer sizes and the memory budget of the stages
+type CollectorStats struct {
+	Collected      uint64 // entries passed to the collector
+	Flushes        uint64 // buffers spilled to disk
+	BudgetFlushes  uint64 // buffers spilled early, because the shared memory budget was exceeded
+	SpilledBytes   uint64 // bytes written to spill files before compression
+	SpillFileBytes uint64 // size of spill files on disk
+	PeakBufferSize int
+}
+
+type collectorStats struct {
+	collected      uint64
+	flushes        uint64
+	budgetFlushes  uint64
+	peakBufferSize int
+	// updated by background flushes
+	spilledBytes   atomic.Uint64
+	spillFileBytes atomic.Uint64
 }
 
 // NewCollectorFromFiles creates collector from existing files (left over from previous unsuccessful loading)
@@ -75,7 +102,7 @@ func NewCollectorFromFiles(logPrefix, tmpdir string, logger log.Logger) (*Collec
 		if err != nil {
 			return nil, fmt.Errorf("collector from files - reading file info %s: %w", dirEntry.Name(), err)
 		}
-		var dataProvider fileDataProvider
+		dataProvider := fileDataProvider{compression: compressionByFileName(fileInfo.Name()), wg: &errgroup.Group{}}
 		dataProvider.file, err = os.Open(filepath.Join(tmpdir, fileInfo.Name()))
 		if err != nil {
 			return nil, fmt.Errorf("collector from files - opening file %s: %w", fileInfo.Name(), err)
@@ -93,16 +120,52 @@ func NewCriticalCollector(logPrefix, tmpdir string, sortableBuffer Buffer, logge
 }
 
 func NewCollector(logPrefix, tmpdir string, sortableBuffer Buffer, logger log.Logger) *Collector {
-	return &Collector{autoClean: true, bufType: getTypeByBuffer(sortableBuffer), buf: sortableBuffer, logPrefix: logPrefix, tmpdir: tmpdir, logLvl: log.LvlInfo, logger: logger}
+	return &Collector{autoClean: true, bufType: getTypeByBuffer(sortableBuffer), buf: sortableBuffer, logPrefix: logPrefix, tmpdir: tmpdir, logLvl: log.LvlInfo, logger: logger,
+		compression: DefaultCompression, budget: DefaultMemoryBudget}
 }
 
 func (c *Collector) SortAndFlushInBackground(v bool) { c.sortAndFlushInBackground = v }
 
+// Compression - codec of spill files, must be set before first Collect
+func (c *Collector) Compression(v Compression) { c.compression = v }
+
+// MemoryBudget - moves the collector to another shared budget (nil - no accounting), must be set before first Collect
+func (c *Collector) MemoryBudget(b *MemoryBudget) { c.budget = b }
+
+func (c *Collector) Stats() CollectorStats {
+	return CollectorStats{
+		Collected:      c.stats.collected,
+		Flushes:        c.stats.flushes,
+		BudgetFlushes:  c.stats.budgetFlushes,
+		SpilledBytes:   c.stats.spilledBytes.Load(),
+		SpillFileBytes: c.stats.spillFileBytes.Load(),
+		PeakBufferSize: c.stats.peakBufferSize,
+	}
+}
+
+// minBudgetFlushRatio - when the budget is exceeded, collector doesn't spill buffers smaller than `SizeLimit/minBudgetFlushRatio`:
+// many tiny files are slow to merge, and the budget is likely exceeded by other collectors
+const minBudgetFlushRatio = 16
+
 func (c *Collector) extractNextFunc(originalK, k []byte, v []byte) error {
 	c.buf.Put(k, v)
-	if !c.buf.CheckFlushSize() {
+	c.stats.collected++
+	size := c.buf.Size()
+	if size > c.stats.peakBufferSize {
+		c.stats.peakBufferSize = size
+	}
+	if c.buf.CheckFlushSize() {
+		return c.flushBuffer(false)
+	}
+	if c.budget == nil {
+		return nil
+	}
+	exceeded := c.budget.reserve(size - c.reserved)
+	c.reserved = size
+	if !exceeded || size < c.buf.SizeLimit()/minBudgetFlushRatio {
 		return nil
 	}
+	c.stats.budgetFlushes++
 	return c.flushBuffer(false)
 }
 
@@ -126,18 +189,33 @@ func (c *Collector) flushBuffer(canStoreInRam bool) error {
 		doFsync := !c.autoClean /* is critical collector */
 		var err error
 
+		// the flushed buffer stays accounted in the budget until it's written
+		if c.budget != nil {
+			c.budget.reserve(c.buf.Size() - c.reserved)
+		}
+		reserved := c.buf.Size()
+		c.reserved = 0
+		c.stats.flushes++
+		flushed := func(spilled, onDisk int) {
+			c.stats.spilledBytes.Add(uint64(spilled))
+			c.stats.spillFileBytes.Add(uint64(onDisk))
+			if c.budget != nil {
+				c.budget.release(reserved)
+			}
+		}
+
 		if c.sortAndFlushInBackground {
 			fullBuf := c.buf // can't `.Reset()` because this `buf` will move to another goroutine
 			prevLen, prevSize := fullBuf.Len(), fullBuf.SizeLimit()
 			c.buf = getBufferByType(c.bufType, datasize.ByteSize(c.buf.SizeLimit()), c.buf)
 
-			provider, err = FlushToDiskAsync(c.logPrefix, fullBuf, c.tmpdir, doFsync, c.logLvl)
+			provider, err = flushToDiskAsync(c.logPrefix, fullBuf, c.tmpdir, doFsync, c.compression, c.logLvl, flushed)
 			if err != nil {
 				return err
 			}
 			c.buf.Prealloc(prevLen/8, prevSize/8)
 		} else {
-			provider, err = FlushToDisk(c.logPrefix, c.buf, c.tmpdir, doFsync, c.logLvl)
+			provider, err = flushToDisk(c.logPrefix, c.buf, c.tmpdir, doFsync, c.compression, c.logLvl, flushed)
 			if err != nil {
 				return err
 			}
@@ -268,6 +346,10 @@ func (c *Collector) reset() {
 		}
 		c.dataProviders = nil
 	}
+	if c.budget != nil && c.reserved > 0 {
+		c.budget.release(c.reserved)
+	}
+	c.reserved = 0
 	c.buf.Reset()
 	c.allFlushed = false
 }
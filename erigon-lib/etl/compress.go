@@ -0,0 +1,124 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package etl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Compression - codec of the files the collectors spill their buffers to.
+// Spill files are written once and read once, so only the fast codecs make sense here.
+type Compression int
+
+const (
+	CompressNone Compression = iota
+	CompressLz4
+	CompressZstd
+)
+
+// DefaultCompression - codec of the spill files of new collectors. var because we want to change it from command-line flags
+var DefaultCompression = CompressNone
+
+func ParseCompression(s string) (Compression, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return CompressNone, nil
+	case "lz4":
+		return CompressLz4, nil
+	case "zstd":
+		return CompressZstd, nil
+	default:
+		return CompressNone, fmt.Errorf("unknown etl compression: %q, supported: none, lz4, zstd", s)
+	}
+}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressNone:
+		return "none"
+	case CompressLz4:
+		return "lz4"
+	case CompressZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// fileExt - the codec is recorded in the name of the spill file, so NewCollectorFromFiles can read the leftovers
+func (c Compression) fileExt() string {
+	switch c {
+	case CompressLz4:
+		return ".lz4"
+	case CompressZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+func compressionByFileName(name string) Compression {
+	switch {
+	case strings.HasSuffix(name, CompressLz4.fileExt()):
+		return CompressLz4
+	case strings.HasSuffix(name, CompressZstd.fileExt()):
+		return CompressZstd
+	default:
+		return CompressNone
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// newWriter - Close must be called to flush the compressed stream, it doesn't close `w`
+func (c Compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case CompressNone:
+		return nopWriteCloser{w}, nil
+	case CompressLz4:
+		return lz4.NewWriter(w), nil
+	case CompressZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1), zstd.WithLowerEncoderMem(true))
+	default:
+		return nil, fmt.Errorf("unknown etl compression: %s", c)
+	}
+}
+
+// newReader - the returned closer releases the resources of the decoder, it doesn't close `r`
+func (c Compression) newReader(r io.Reader) (io.Reader, func(), error) {
+	switch c {
+	case CompressNone:
+		return r, func() {}, nil
+	case CompressLz4:
+		return lz4.NewReader(r), func() {}, nil
+	case CompressZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, nil, err
+		}
+		return d, d.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown etl compression: %s", c)
+	}
+}
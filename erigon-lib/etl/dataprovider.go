@@ -35,21 +35,34 @@ type dataProvider interface {
 }
 
 type fileDataProvider struct {
-	file       *os.File
-	reader     io.Reader
-	byteReader io.ByteReader // Different interface to the same object as reader
-	wg         *errgroup.Group
+	file        *os.File
+	compression Compression
+	reader      io.Reader
+	byteReader  io.ByteReader // Different interface to the same object as reader
+	closeReader func()        // releases the decompressor
+	wg          *errgroup.Group
 }
 
+// flushedFunc - is called when the buffer is flushed (also on error): `spilled` - bytes written before compression, `onDisk` - size of the file
+type flushedFunc func(spilled, onDisk int)
+
 // FlushToDiskAsync - `doFsync` is true only for 'critical' collectors (which should not loose).
-func FlushToDiskAsync(logPrefix string, b Buffer, tmpdir string, doFsync bool, lvl log.Lvl) (dataProvider, error) {
+func FlushToDiskAsync(logPrefix string, b Buffer, tmpdir string, doFsync bool, compression Compression, lvl log.Lvl) (dataProvider, error) {
+	return flushToDiskAsync(logPrefix, b, tmpdir, doFsync, compression, lvl, nil)
+}
+
+func flushToDiskAsync(logPrefix string, b Buffer, tmpdir string, doFsync bool, compression Compression, lvl log.Lvl, flushed flushedFunc) (dataProvider, error) {
 	if b.Len() == 0 {
 		return nil, nil
 	}
 
-	provider := &fileDataProvider{reader: nil, wg: &errgroup.Group{}}
+	provider := &fileDataProvider{reader: nil, compression: compression, wg: &errgroup.Group{}}
 	provider.wg.Go(func() (err error) {
-		provider.file, err = sortAndFlush(b, tmpdir, doFsync)
+		var spilled, onDisk int
+		if flushed != nil {
+			defer func() { flushed(spilled, onDisk) }()
+		}
+		provider.file, spilled, onDisk, err = sortAndFlush(b, tmpdir, doFsync, compression)
 		if err != nil {
 			return err
 		}
@@ -62,14 +75,22 @@ func FlushToDiskAsync(logPrefix string, b Buffer, tmpdir string, doFsync bool, l
 }
 
 // FlushToDisk - `doFsync` is true only for 'critical' collectors (which should not loose).
-func FlushToDisk(logPrefix string, b Buffer, tmpdir string, doFsync bool, lvl log.Lvl) (dataProvider, error) {
+func FlushToDisk(logPrefix string, b Buffer, tmpdir string, doFsync bool, compression Compression, lvl log.Lvl) (dataProvider, error) {
+	return flushToDisk(logPrefix, b, tmpdir, doFsync, compression, lvl, nil)
+}
+
+func flushToDisk(logPrefix string, b Buffer, tmpdir string, doFsync bool, compression Compression, lvl log.Lvl, flushed flushedFunc) (dataProvider, error) {
 	if b.Len() == 0 {
 		return nil, nil
 	}
 
 	var err error
-	provider := &fileDataProvider{reader: nil, wg: &errgroup.Group{}}
-	provider.file, err = sortAndFlush(b, tmpdir, doFsync)
+	var spilled, onDisk int
+	if flushed != nil {
+		defer func() { flushed(spilled, onDisk) }()
+	}
+	provider := &fileDataProvider{reader: nil, compression: compression, wg: &errgroup.Group{}}
+	provider.file, spilled, onDisk, err = sortAndFlush(b, tmpdir, doFsync, compression)
 	if err != nil {
 		return nil, err
 	}
@@ -78,33 +99,61 @@ func FlushToDisk(logPrefix string, b Buffer, tmpdir string, doFsync bool, lvl lo
 	return provider, nil
 }
 
-func sortAndFlush(b Buffer, tmpdir string, doFsync bool) (*os.File, error) {
+// countingWriter - counts bytes before compression
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+// sortAndFlush returns the file, amount of bytes written before compression and size of the file
+func sortAndFlush(b Buffer, tmpdir string, doFsync bool, compression Compression) (*os.File, int, int, error) {
 	b.Sort()
 
 	// if we are going to create files in the system temp dir, we don't need any
 	// subfolders.
 	if tmpdir != "" {
 		if err := os.MkdirAll(tmpdir, 0755); err != nil {
-			return nil, err
+			return nil, 0, 0, err
 		}
 	}
 
-	bufferFile, err := os.CreateTemp(tmpdir, "erigon-sortable-buf-")
+	bufferFile, err := os.CreateTemp(tmpdir, "erigon-sortable-buf-*"+compression.fileExt())
 	if err != nil {
-		return nil, err
+		return nil, 0, 0, err
 	}
 
-	if doFsync {
-		defer bufferFile.Sync() //nolint:errcheck
+	cw, err := compression.newWriter(bufferFile)
+	if err != nil {
+		return bufferFile, 0, 0, err
 	}
-
-	w := bufio.NewWriterSize(bufferFile, BufIOSize)
-	defer w.Flush() //nolint:errcheck
+	counter := &countingWriter{w: cw}
+	w := bufio.NewWriterSize(counter, BufIOSize)
 
 	if err = b.Write(w); err != nil {
-		return bufferFile, fmt.Errorf("error writing entries to disk: %w", err)
+		return bufferFile, counter.n, 0, fmt.Errorf("error writing entries to disk: %w", err)
+	}
+	if err = w.Flush(); err != nil {
+		return bufferFile, counter.n, 0, fmt.Errorf("error writing entries to disk: %w", err)
 	}
-	return bufferFile, nil
+	if err = cw.Close(); err != nil {
+		return bufferFile, counter.n, 0, fmt.Errorf("error writing entries to disk: %w", err)
+	}
+	if doFsync {
+		if err = bufferFile.Sync(); err != nil {
+			return bufferFile, counter.n, 0, err
+		}
+	}
+	onDisk, err := bufferFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return bufferFile, counter.n, 0, err
+	}
+	return bufferFile, counter.n, int(onDisk), nil
 }
 
 func (p *fileDataProvider) Next(keyBuf, valBuf []byte) ([]byte, []byte, error) {
@@ -113,16 +162,24 @@ func (p *fileDataProvider) Next(keyBuf, valBuf []byte) ([]byte, []byte, error) {
 		if err != nil {
 			return nil, nil, err
 		}
-		r := bufio.NewReaderSize(p.file, BufIOSize)
+		dr, closeReader, err := p.compression.newReader(p.file)
+		if err != nil {
+			return nil, nil, err
+		}
+		r := bufio.NewReaderSize(dr, BufIOSize)
 		p.reader = r
 		p.byteReader = r
-
+		p.closeReader = closeReader
 	}
 	return readElementFromDisk(p.reader, p.byteReader, keyBuf, valBuf)
 }
 
 func (p *fileDataProvider) Wait() error { return p.wg.Wait() }
 func (p *fileDataProvider) Dispose() {
+	if p.closeReader != nil {
+		p.closeReader()
+		p.closeReader = nil
+	}
 	if p.file != nil { //invariant: safe to call multiple time
 		p.Wait()
 		_ = p.file.Close()
@@ -132,7 +189,7 @@ func (p *fileDataProvider) Dispose() {
 }
 
 func (p *fileDataProvider) String() string {
-	return fmt.Sprintf("%T(file: %s)", p, p.file.Name())
+	return fmt.Sprintf("%T(file: %s, compression: %s)", p, p.file.Name(), p.compression)
 }
 
 func readElementFromDisk(r io.Reader, br io.ByteReader, keyBuf, valBuf []byte) ([]byte, []byte, error) {
@@ -631,3 +631,114 @@ func TestSortable(t *testing.T) {
 	require.Equal([][]byte{{1}, {2}, {3}, {4}, {5}, {6}, {7}, {1}, {20}, nil}, vals)
 
 }
+
+func TestCompressedSpillFiles(t *testing.T) {
+	logger := log.New()
+	for _, compression := range []Compression{CompressNone, CompressLz4, CompressZstd} {
+		compression := compression
+		t.Run(compression.String(), func(t *testing.T) {
+			for _, background := range []bool{false, true} {
+				tmpdir := t.TempDir()
+				c := NewCollector(t.Name(), tmpdir, NewSortableBuffer(1024), logger)
+				c.Compression(compression)
+				c.SortAndFlushInBackground(background)
+				for i := 999; i >= 0; i-- {
+					require.NoError(t, c.Collect([]byte(fmt.Sprintf("key-%05d", i)), bytes.Repeat([]byte{byte(i)}, 32)))
+				}
+				require.NoError(t, c.Flush())
+				for _, p := range c.dataProviders {
+					require.NoError(t, p.Wait())
+				}
+
+				stats := c.Stats()
+				require.Equal(t, uint64(1000), stats.Collected)
+				require.NotZero(t, stats.Flushes)
+				require.Equal(t, stats.Flushes, uint64(len(c.dataProviders)))
+				if compression == CompressNone {
+					require.Equal(t, stats.SpilledBytes, stats.SpillFileBytes)
+				} else {
+					require.Less(t, stats.SpillFileBytes, stats.SpilledBytes)
+				}
+
+				// leftovers of the failed loading are readable
+				files, err := os.ReadDir(tmpdir)
+				require.NoError(t, err)
+				require.Len(t, files, len(c.dataProviders))
+				require.Equal(t, compression, compressionByFileName(files[0].Name()))
+
+				i := 0
+				require.NoError(t, c.Load(nil, "", func(k, v []byte, _ CurrentTableReader, _ LoadNextFunc) error {
+					require.Equal(t, fmt.Sprintf("key-%05d", i), string(k))
+					require.Equal(t, bytes.Repeat([]byte{byte(i)}, 32), v)
+					i++
+					return nil
+				}, TransformArgs{}))
+				require.Equal(t, 1000, i)
+			}
+		})
+	}
+}
+
+func TestCollectorFromCompressedFiles(t *testing.T) {
+	logger := log.New()
+	tmpdir := t.TempDir()
+	c := NewCriticalCollector(t.Name(), tmpdir, NewSortableBuffer(64), logger)
+	c.Compression(CompressZstd)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, c.Collect([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	require.NoError(t, c.Flush())
+
+	leftovers, err := NewCollectorFromFiles(t.Name(), tmpdir, logger)
+	require.NoError(t, err)
+	i := 0
+	require.NoError(t, leftovers.Load(nil, "", func(k, v []byte, _ CurrentTableReader, _ LoadNextFunc) error {
+		require.Equal(t, []byte{byte(i)}, k)
+		i++
+		return nil
+	}, TransformArgs{}))
+	require.Equal(t, 100, i)
+}
+
+func TestParseCompression(t *testing.T) {
+	for _, c := range []Compression{CompressNone, CompressLz4, CompressZstd} {
+		parsed, err := ParseCompression(c.String())
+		require.NoError(t, err)
+		require.Equal(t, c, parsed)
+	}
+	_, err := ParseCompression("gzip")
+	require.Error(t, err)
+}
+
+func TestMemoryBudget(t *testing.T) {
+	logger := log.New()
+	budget := NewMemoryBudget(4 * 1024)
+
+	// each buffer alone fits, but not together
+	collectors := make([]*Collector, 4)
+	for i := range collectors {
+		collectors[i] = NewCollector(t.Name(), t.TempDir(), NewSortableBuffer(16*1024), logger)
+		collectors[i].MemoryBudget(budget)
+	}
+	for i := 0; i < 1000; i++ {
+		for _, c := range collectors {
+			require.NoError(t, c.Collect([]byte(fmt.Sprintf("key-%05d", i)), []byte{1}))
+		}
+	}
+
+	var budgetFlushes uint64
+	for _, c := range collectors {
+		stats := c.Stats()
+		require.Equal(t, uint64(1000), stats.Collected)
+		require.LessOrEqual(t, stats.PeakBufferSize, 16*1024)
+		budgetFlushes += stats.BudgetFlushes
+	}
+	require.NotZero(t, budgetFlushes)
+	// every collector may overshoot by the last entry and the buffers smaller than the flush threshold
+	require.LessOrEqual(t, int(budget.Peak()), 4*1024+len(collectors)*16*1024/minBudgetFlushRatio+len(collectors)*64)
+
+	for _, c := range collectors {
+		c.Close()
+	}
+	require.Zero(t, budget.Used())
+}
@@ -85,6 +85,50 @@ func (c *MockDownloaderClientAddCall) DoAndReturn(f func(context.Context, *AddRe
 	return c
 }
 
+// AddWebSeeds mocks base method.
+func (m *MockDownloaderClient) AddWebSeeds(arg0 context.Context, arg1 *AddWebSeedsRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddWebSeeds", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWebSeeds indicates an expected call of AddWebSeeds.
+func (mr *MockDownloaderClientMockRecorder) AddWebSeeds(arg0, arg1 any, arg2 ...any) *MockDownloaderClientAddWebSeedsCall {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebSeeds", reflect.TypeOf((*MockDownloaderClient)(nil).AddWebSeeds), varargs...)
+	return &MockDownloaderClientAddWebSeedsCall{Call: call}
+}
+
+// MockDownloaderClientAddWebSeedsCall wrap *gomock.Call
+type MockDownloaderClientAddWebSeedsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDownloaderClientAddWebSeedsCall) Return(arg0 *emptypb.Empty, arg1 error) *MockDownloaderClientAddWebSeedsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDownloaderClientAddWebSeedsCall) Do(f func(context.Context, *AddWebSeedsRequest, ...grpc.CallOption) (*emptypb.Empty, error)) *MockDownloaderClientAddWebSeedsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDownloaderClientAddWebSeedsCall) DoAndReturn(f func(context.Context, *AddWebSeedsRequest, ...grpc.CallOption) (*emptypb.Empty, error)) *MockDownloaderClientAddWebSeedsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Delete mocks base method.
 func (m *MockDownloaderClient) Delete(arg0 context.Context, arg1 *DeleteRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -25,6 +25,7 @@ const (
 	Downloader_Delete_FullMethodName               = "/downloader.Downloader/Delete"
 	Downloader_Verify_FullMethodName               = "/downloader.Downloader/Verify"
 	Downloader_Stats_FullMethodName                = "/downloader.Downloader/Stats"
+	Downloader_AddWebSeeds_FullMethodName          = "/downloader.Downloader/AddWebSeeds"
 )
 
 // DownloaderClient is the client API for Downloader service.
@@ -42,6 +43,8 @@ type DownloaderClient interface {
 	// If some part of file is bad - such part will be re-downloaded (without returning error)
 	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsReply, error)
+	// Hot-add webseed urls (without restart)
+	AddWebSeeds(ctx context.Context, in *AddWebSeedsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type downloaderClient struct {
@@ -97,6 +100,15 @@ func (c *downloaderClient) Stats(ctx context.Context, in *StatsRequest, opts ...
 	return out, nil
 }
 
+func (c *downloaderClient) AddWebSeeds(ctx context.Context, in *AddWebSeedsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Downloader_AddWebSeeds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DownloaderServer is the server API for Downloader service.
 // All implementations must embed UnimplementedDownloaderServer
 // for forward compatibility
@@ -112,6 +124,8 @@ type DownloaderServer interface {
 	// If some part of file is bad - such part will be re-downloaded (without returning error)
 	Verify(context.Context, *VerifyRequest) (*emptypb.Empty, error)
 	Stats(context.Context, *StatsRequest) (*StatsReply, error)
+	// Hot-add webseed urls (without restart)
+	AddWebSeeds(context.Context, *AddWebSeedsRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedDownloaderServer()
 }
 
@@ -134,6 +148,9 @@ func (UnimplementedDownloaderServer) Verify(context.Context, *VerifyRequest) (*e
 func (UnimplementedDownloaderServer) Stats(context.Context, *StatsRequest) (*StatsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
 }
+func (UnimplementedDownloaderServer) AddWebSeeds(context.Context, *AddWebSeedsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWebSeeds not implemented")
+}
 func (UnimplementedDownloaderServer) mustEmbedUnimplementedDownloaderServer() {}
 
 // UnsafeDownloaderServer may be embedded to opt out of forward compatibility for this service.
@@ -237,6 +254,24 @@ func _Downloader_Stats_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Downloader_AddWebSeeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWebSeedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloaderServer).AddWebSeeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Downloader_AddWebSeeds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloaderServer).AddWebSeeds(ctx, req.(*AddWebSeedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Downloader_ServiceDesc is the grpc.ServiceDesc for Downloader service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -264,6 +299,10 @@ var Downloader_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Stats",
 			Handler:    _Downloader_Stats_Handler,
 		},
+		{
+			MethodName: "AddWebSeeds",
+			Handler:    _Downloader_AddWebSeeds_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "downloader/downloader.proto",
@@ -30,6 +30,8 @@ const (
 	Txpool_OnAdd_FullMethodName        = "/txpool.Txpool/OnAdd"
 	Txpool_Status_FullMethodName       = "/txpool.Txpool/Status"
 	Txpool_Nonce_FullMethodName        = "/txpool.Txpool/Nonce"
+	Txpool_Locals_FullMethodName       = "/txpool.Txpool/Locals"
+	Txpool_OnEvent_FullMethodName      = "/txpool.Txpool/OnEvent"
 )
 
 // TxpoolClient is the client API for Txpool service.
@@ -55,6 +57,10 @@ type TxpoolClient interface {
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(ctx context.Context, in *NonceRequest, opts ...grpc.CallOption) (*NonceReply, error)
+	// returns journaled local transactions: submitted to this node and not yet included or expired
+	Locals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalsReply, error)
+	// subscribe to additions and drops of pool transactions, drops come with the reason
+	OnEvent(ctx context.Context, in *OnEventRequest, opts ...grpc.CallOption) (Txpool_OnEventClient, error)
 }
 
 type txpoolClient struct {
@@ -169,6 +175,47 @@ func (c *txpoolClient) Nonce(ctx context.Context, in *NonceRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *txpoolClient) Locals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*LocalsReply, error) {
+	out := new(LocalsReply)
+	err := c.cc.Invoke(ctx, Txpool_Locals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *txpoolClient) OnEvent(ctx context.Context, in *OnEventRequest, opts ...grpc.CallOption) (Txpool_OnEventClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Txpool_ServiceDesc.Streams[1], Txpool_OnEvent_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &txpoolOnEventClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Txpool_OnEventClient interface {
+	Recv() (*OnEventReply, error)
+	grpc.ClientStream
+}
+
+type txpoolOnEventClient struct {
+	grpc.ClientStream
+}
+
+func (x *txpoolOnEventClient) Recv() (*OnEventReply, error) {
+	m := new(OnEventReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TxpoolServer is the server API for Txpool service.
 // All implementations must embed UnimplementedTxpoolServer
 // for forward compatibility
@@ -192,6 +239,10 @@ type TxpoolServer interface {
 	Status(context.Context, *StatusRequest) (*StatusReply, error)
 	// returns nonce for given account
 	Nonce(context.Context, *NonceRequest) (*NonceReply, error)
+	// returns journaled local transactions: submitted to this node and not yet included or expired
+	Locals(context.Context, *emptypb.Empty) (*LocalsReply, error)
+	// subscribe to additions and drops of pool transactions, drops come with the reason
+	OnEvent(*OnEventRequest, Txpool_OnEventServer) error
 	mustEmbedUnimplementedTxpoolServer()
 }
 
@@ -226,6 +277,12 @@ func (UnimplementedTxpoolServer) Status(context.Context, *StatusRequest) (*Statu
 func (UnimplementedTxpoolServer) Nonce(context.Context, *NonceRequest) (*NonceReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Nonce not implemented")
 }
+func (UnimplementedTxpoolServer) Locals(context.Context, *emptypb.Empty) (*LocalsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Locals not implemented")
+}
+func (UnimplementedTxpoolServer) OnEvent(*OnEventRequest, Txpool_OnEventServer) error {
+	return status.Errorf(codes.Unimplemented, "method OnEvent not implemented")
+}
 func (UnimplementedTxpoolServer) mustEmbedUnimplementedTxpoolServer() {}
 
 // UnsafeTxpoolServer may be embedded to opt out of forward compatibility for this service.
@@ -404,6 +461,45 @@ func _Txpool_Nonce_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Txpool_Locals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxpoolServer).Locals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Txpool_Locals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxpoolServer).Locals(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Txpool_OnEvent_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OnEventRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TxpoolServer).OnEvent(m, &txpoolOnEventServer{stream})
+}
+
+type Txpool_OnEventServer interface {
+	Send(*OnEventReply) error
+	grpc.ServerStream
+}
+
+type txpoolOnEventServer struct {
+	grpc.ServerStream
+}
+
+func (x *txpoolOnEventServer) Send(m *OnEventReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Txpool_ServiceDesc is the grpc.ServiceDesc for Txpool service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -443,6 +539,10 @@ var Txpool_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Nonce",
 			Handler:    _Txpool_Nonce_Handler,
 		},
+		{
+			MethodName: "Locals",
+			Handler:    _Txpool_Locals_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -450,6 +550,11 @@ var Txpool_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Txpool_OnAdd_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "OnEvent",
+			Handler:       _Txpool_OnEvent_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "txpool/txpool.proto",
 }
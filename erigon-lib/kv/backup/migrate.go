@@ -0,0 +1,333 @@
+package backup
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/erigontech/mdbx-go/mdbx"
+	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	mdbx2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/semaphore"
+)
+
+// Geometry - mdbx geometry of the migration target. Zero fields are taken from the source db
+type Geometry struct {
+	PageSize   datasize.ByteSize
+	GrowthStep datasize.ByteSize
+	MapSize    datasize.ByteSize // upper bound of the db file size
+}
+
+func (g Geometry) Validate() error {
+	if g.PageSize != 0 && (g.PageSize < mdbx.MinPageSize || g.PageSize > mdbx.MaxPageSize || bits.OnesCount64(uint64(g.PageSize)) != 1) {
+		return fmt.Errorf("page size must be a power of 2 in [%s, %s], got %s", datasize.ByteSize(mdbx.MinPageSize).HR(), datasize.ByteSize(mdbx.MaxPageSize).HR(), g.PageSize.HR())
+	}
+	if g.MapSize != 0 && g.GrowthStep > g.MapSize {
+		return fmt.Errorf("growth step %s is bigger than map size %s", g.GrowthStep.HR(), g.MapSize.HR())
+	}
+	return nil
+}
+
+// Converter - rewrites entries of a table while it's migrated. Returning nil key drops the entry.
+type Converter func(k, v []byte) ([]byte, []byte, error)
+
+type MigrateCfg struct {
+	Tables  []string             // tables to copy, empty means all non-deprecated tables
+	Exclude []string             // tables to skip, they are created empty in the target
+	Convert map[string]Converter // per-table converters, entries of other tables are copied as is
+	Verify  bool                 // re-read every copied table and compare it to the source
+
+	ReadAheadThreads int
+}
+
+// OpenMigrationPair - like OpenPair, but the target db must not exist yet: the page size of existing db can't be changed
+func OpenMigrationPair(from, to string, label kv.Label, geometry Geometry, logger log.Logger) (kv.RoDB, kv.RwDB, error) {
+	if err := geometry.Validate(); err != nil {
+		return nil, nil, err
+	}
+	if dir.FileExist(filepath.Join(to, "mdbx.dat")) {
+		return nil, nil, fmt.Errorf("target db already exists: %s", to)
+	}
+	if !dir.FileExist(filepath.Join(from, "mdbx.dat")) {
+		return nil, nil, fmt.Errorf("source db not found: %s", from)
+	}
+
+	const ThreadsHardLimit = 9_000
+	src, err := mdbx2.NewMDBX(logger).Path(from).
+		Label(label).
+		RoTxsLimiter(semaphore.NewWeighted(ThreadsHardLimit)).
+		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.TablesCfgByLabel(label) }).
+		Flags(func(flags uint) uint { return flags | mdbx.Accede }).
+		Open(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := src.(*mdbx2.MdbxKV).Env().Info(nil)
+	if err != nil {
+		src.Close()
+		return nil, nil, err
+	}
+	if geometry.PageSize == 0 {
+		geometry.PageSize = datasize.ByteSize(src.PageSize())
+	}
+	if geometry.MapSize == 0 {
+		geometry.MapSize = datasize.ByteSize(info.Geo.Upper)
+	}
+	if geometry.GrowthStep == 0 {
+		geometry.GrowthStep = datasize.ByteSize(info.Geo.Grow)
+	}
+	if err := geometry.Validate(); err != nil {
+		src.Close()
+		return nil, nil, err
+	}
+	if uint64(geometry.MapSize) < info.Geo.Current {
+		src.Close()
+		return nil, nil, fmt.Errorf("map size %s is smaller than the source db %s", geometry.MapSize.HR(), datasize.ByteSize(info.Geo.Current).HR())
+	}
+
+	dst, err := mdbx2.NewMDBX(logger).Path(to).
+		Label(label).
+		PageSize(geometry.PageSize.Bytes()).
+		MapSize(geometry.MapSize).
+		GrowthStep(geometry.GrowthStep).
+		Flags(func(flags uint) uint { return flags | mdbx.WriteMap }).
+		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.TablesCfgByLabel(label) }).
+		Open(context.Background())
+	if err != nil {
+		src.Close()
+		return nil, nil, err
+	}
+	logger.Info("[migrate] opened", "from", from, "to", to,
+		"page_size", geometry.PageSize.HR(), "growth_step", geometry.GrowthStep.HR(), "map_size", geometry.MapSize.HR())
+	return src, dst, nil
+}
+
+// Migrate - copies tables of src to dst table-by-table. Unlike Kv2kv it supports excluding and converting tables
+// and verifies the result.
+func Migrate(ctx context.Context, src kv.RoDB, dst kv.RwDB, cfg MigrateCfg, logger log.Logger) error {
+	tables, err := migrationTables(src.AllTables(), cfg)
+	if err != nil {
+		return err
+	}
+
+	srcTx, err := src.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcTx.Rollback()
+
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	seed := maphash.MakeSeed()
+	for i, table := range tables {
+		started := time.Now()
+		logger.Info("[migrate] table", "table", table, "progress", fmt.Sprintf("%d/%d", i+1, len(tables)))
+		written, err := migrateTable(ctx, src, srcTx, dst, table, cfg.Convert[table], seed, cfg.ReadAheadThreads, logEvery, logger)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+		if cfg.Verify {
+			if err := verifyTable(ctx, dst, table, written, seed); err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+		}
+		logger.Info("[migrate] table done", "table", table, "entries", written.count, "verified", cfg.Verify, "took", time.Since(started))
+	}
+	logger.Info("[migrate] done", "tables", len(tables))
+	return nil
+}
+
+func migrationTables(all kv.TableCfg, cfg MigrateCfg) ([]string, error) {
+	for _, name := range cfg.Tables {
+		if _, ok := all[name]; !ok {
+			return nil, fmt.Errorf("unknown table: %s", name)
+		}
+	}
+	for _, name := range cfg.Exclude {
+		if _, ok := all[name]; !ok {
+			return nil, fmt.Errorf("unknown excluded table: %s", name)
+		}
+	}
+	for name := range cfg.Convert {
+		if _, ok := all[name]; !ok {
+			return nil, fmt.Errorf("unknown converted table: %s", name)
+		}
+		if slices.Contains(cfg.Exclude, name) {
+			return nil, fmt.Errorf("table is both excluded and converted: %s", name)
+		}
+	}
+
+	tables := make([]string, 0, len(all))
+	for name, b := range all {
+		if b.IsDeprecated || slices.Contains(cfg.Exclude, name) {
+			continue
+		}
+		if len(cfg.Tables) > 0 && !slices.Contains(cfg.Tables, name) {
+			continue
+		}
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// tableDigest - order-independent digest of table entries: converters may reorder keys
+type tableDigest struct {
+	count uint64
+	sum   uint64
+}
+
+func (d *tableDigest) add(seed maphash.Seed, k, v []byte) {
+	var h maphash.Hash
+	var kLen [4]byte
+	binary.BigEndian.PutUint32(kLen[:], uint32(len(k)))
+	h.SetSeed(seed)
+	h.Write(kLen[:])
+	h.Write(k)
+	h.Write(v)
+	d.count++
+	d.sum += h.Sum64()
+}
+
+func migrateTable(ctx context.Context, src kv.RoDB, srcTx kv.Tx, dst kv.RwDB, table string, convert Converter, seed maphash.Seed, readAheadThreads int, logEvery *time.Ticker, logger log.Logger) (written tableDigest, err error) {
+	if readAheadThreads > 0 {
+		wg := sync.WaitGroup{}
+		defer wg.Wait()
+		warmupCtx, warmupCancel := context.WithCancel(ctx)
+		defer warmupCancel()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			WarmupTable(warmupCtx, src, table, log.LvlTrace, readAheadThreads)
+		}()
+	}
+
+	srcC, err := srcTx.Cursor(table)
+	if err != nil {
+		return written, err
+	}
+	defer srcC.Close()
+	total, _ := srcC.Count()
+
+	dstTx, err := dst.BeginRw(ctx)
+	if err != nil {
+		return written, err
+	}
+	defer dstTx.Rollback()
+	if err := dstTx.ClearBucket(table); err != nil {
+		return written, err
+	}
+
+	c, err := dstTx.RwCursor(table)
+	if err != nil {
+		return written, err
+	}
+	defer c.Close()
+	casted, isDupsort := c.(kv.RwCursorDupSort)
+
+	var i uint64
+	for k, v, err := srcC.First(); k != nil; k, v, err = srcC.Next() {
+		if err != nil {
+			return written, err
+		}
+		i++
+
+		if convert != nil {
+			// converters may break the order of keys, so Append can't be used
+			srcK := k
+			if k, v, err = convert(k, v); err != nil {
+				return written, fmt.Errorf("convert %x: %w", srcK, err)
+			}
+			if k != nil {
+				if err = putConverted(c, casted, isDupsort, k, v); err != nil {
+					return written, fmt.Errorf("convert %x: %w", srcK, err)
+				}
+				written.add(seed, k, v)
+			}
+		} else {
+			if isDupsort {
+				err = casted.AppendDup(k, v)
+			} else {
+				err = c.Append(k, v)
+			}
+			if err != nil {
+				return written, err
+			}
+			written.add(seed, k, v)
+		}
+
+		if i%100_000 == 0 {
+			select {
+			case <-ctx.Done():
+				return written, ctx.Err()
+			case <-logEvery.C:
+				logger.Info("[migrate] progress", "table", table, "progress", fmt.Sprintf("%.1fm/%.1fm", float64(i)/1_000_000, float64(total)/1_000_000), "key", hex.EncodeToString(k))
+			default:
+			}
+		}
+	}
+	return written, dstTx.Commit()
+}
+
+// putConverted - converted entries must not overwrite each other, otherwise data is silently lost
+func putConverted(c kv.RwCursor, casted kv.RwCursorDupSort, isDupsort bool, k, v []byte) error {
+	if isDupsort {
+		if existing, _, err := casted.SeekBothExact(k, v); err != nil {
+			return err
+		} else if existing != nil {
+			return fmt.Errorf("duplicated entry %x: %x", k, v)
+		}
+	} else {
+		if existing, _, err := c.SeekExact(k); err != nil {
+			return err
+		} else if existing != nil {
+			return fmt.Errorf("duplicated key %x", k)
+		}
+	}
+	return c.Put(k, v)
+}
+
+func verifyTable(ctx context.Context, dst kv.RoDB, table string, expected tableDigest, seed maphash.Seed) error {
+	return dst.View(ctx, func(tx kv.Tx) error {
+		c, err := tx.Cursor(table)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		var got tableDigest
+		for k, v, err := c.First(); k != nil; k, v, err = c.Next() {
+			if err != nil {
+				return err
+			}
+			got.add(seed, k, v)
+			if got.count%1_000_000 == 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+			}
+		}
+		if got.count != expected.count {
+			return fmt.Errorf("verification failed: %d entries copied, %d found", expected.count, got.count)
+		}
+		if got.sum != expected.sum {
+			return fmt.Errorf("verification failed: content of the table differs from the source")
+		}
+		return nil
+	})
+}
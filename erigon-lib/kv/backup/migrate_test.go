@@ -0,0 +1,129 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+)
+
+func fillSrc(t *testing.T, path string) {
+	t.Helper()
+	db := mdbx.NewMDBX(log.New()).Path(path).Label(kv.ChainDB).PageSize(4 * 1024).MustOpen()
+	defer db.Close()
+
+	require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := 0; i < 200; i++ {
+			if err := tx.Put(kv.Headers, []byte(fmt.Sprintf("header%04d", i)), bytes.Repeat([]byte{byte(i)}, 100)); err != nil {
+				return err
+			}
+			if err := tx.Put(kv.Code, []byte(fmt.Sprintf("code%04d", i)), []byte{1}); err != nil {
+				return err
+			}
+			// dupsort table
+			for j := 0; j < 3; j++ {
+				if err := tx.Put(kv.AccountChangeSet, []byte(fmt.Sprintf("acc%04d", i)), []byte(fmt.Sprintf("storage%d", j))); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}))
+}
+
+func TestMigrate(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	from, to := t.TempDir(), t.TempDir()
+	fillSrc(t, from)
+
+	src, dst, err := OpenMigrationPair(from, to, kv.ChainDB, Geometry{PageSize: 16 * datasize.KB, MapSize: 1 * datasize.GB}, logger)
+	require.NoError(t, err)
+	defer src.Close()
+	defer dst.Close()
+	require.Equal(t, uint64(16*1024), dst.PageSize())
+
+	err = Migrate(ctx, src, dst, MigrateCfg{
+		Exclude: []string{kv.Code},
+		Convert: map[string]Converter{
+			// drop odd headers, reverse the order of the rest
+			kv.Headers: func(k, v []byte) ([]byte, []byte, error) {
+				if v[0]%2 == 1 {
+					return nil, nil, nil
+				}
+				return []byte{255 - v[0]}, v[:1], nil
+			},
+		},
+		Verify: true,
+	}, logger)
+	require.NoError(t, err)
+
+	require.NoError(t, dst.View(ctx, func(tx kv.Tx) error {
+		count := func(table string) (uint64, error) {
+			c, err := tx.Cursor(table)
+			if err != nil {
+				return 0, err
+			}
+			defer c.Close()
+			var n uint64
+			for k, _, err := c.First(); k != nil; k, _, err = c.Next() {
+				if err != nil {
+					return 0, err
+				}
+				n++
+			}
+			return n, nil
+		}
+		headers, err := count(kv.Headers)
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), headers)
+		code, err := count(kv.Code)
+		require.NoError(t, err)
+		require.Zero(t, code)
+		state, err := count(kv.AccountChangeSet)
+		require.NoError(t, err)
+		require.Equal(t, uint64(600), state)
+
+		v, err := tx.GetOne(kv.Headers, []byte{255 - 10})
+		require.NoError(t, err)
+		require.Equal(t, []byte{10}, v)
+		return nil
+	}))
+}
+
+func TestMigrateErrors(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	from := t.TempDir()
+	fillSrc(t, from)
+
+	_, _, err := OpenMigrationPair(from, t.TempDir(), kv.ChainDB, Geometry{PageSize: 3 * datasize.KB}, logger)
+	require.ErrorContains(t, err, "power of 2")
+
+	// page size of existing db can't be changed
+	_, _, err = OpenMigrationPair(from, from, kv.ChainDB, Geometry{PageSize: 8 * datasize.KB}, logger)
+	require.ErrorContains(t, err, "already exists")
+
+	src, dst, err := OpenMigrationPair(from, t.TempDir(), kv.ChainDB, Geometry{}, logger)
+	require.NoError(t, err)
+	defer src.Close()
+	defer dst.Close()
+	require.Equal(t, src.PageSize(), dst.PageSize())
+
+	err = Migrate(ctx, src, dst, MigrateCfg{Exclude: []string{"NoSuchTable"}}, logger)
+	require.ErrorContains(t, err, "unknown excluded table")
+
+	// converted entries must not overwrite each other
+	err = Migrate(ctx, src, dst, MigrateCfg{
+		Tables: []string{kv.Headers},
+		Convert: map[string]Converter{
+			kv.Headers: func(k, v []byte) ([]byte, []byte, error) { return []byte{1}, v, nil },
+		},
+	}, logger)
+	require.ErrorContains(t, err, "duplicated key")
+}
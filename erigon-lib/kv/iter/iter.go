@@ -17,6 +17,10 @@
 package iter
 
 import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
 	"slices"
 
 	"github.com/ledgerwatch/erigon-lib/kv/order"
@@ -98,17 +102,12 @@ func Union[T constraints.Ordered](x, y Uno[T], asc order.By, limit int) Uno[T] {
 	if x == nil && y == nil {
 		return &Empty[T]{}
 	}
+	// empty streams are not short-cut: limit must be applied and both streams closed
 	if x == nil {
-		return y
+		x = &Empty[T]{}
 	}
 	if y == nil {
-		return x
-	}
-	if !x.HasNext() {
-		return y
-	}
-	if !y.HasNext() {
-		return x
+		y = &Empty[T]{}
 	}
 	m := &UnionUno[T]{x: x, y: y, asc: bool(asc), limit: limit}
 	m.advanceX()
@@ -185,15 +184,21 @@ type Intersected[T constraints.Ordered] struct {
 	x, y               Uno[T]
 	xHasNext, yHasNext bool
 	xNextK, yNextK     T
+	asc                bool
 	limit              int
 	err                error
 }
 
-func Intersect[T constraints.Ordered](x, y Uno[T], limit int) Uno[T] {
+func Intersect[T constraints.Ordered](x, y Uno[T], asc order.By, limit int) Uno[T] {
 	if x == nil || y == nil || !x.HasNext() || !y.HasNext() {
+		for _, it := range []Uno[T]{x, y} {
+			if c, ok := it.(Closer); ok {
+				c.Close()
+			}
+		}
 		return &Empty[T]{}
 	}
-	m := &Intersected[T]{x: x, y: y, limit: limit}
+	m := &Intersected[T]{x: x, y: y, asc: bool(asc), limit: limit}
 	m.advance()
 	return m
 }
@@ -207,7 +212,7 @@ func (m *Intersected[T]) advance() {
 		if m.err != nil {
 			break
 		}
-		if m.xNextK < m.yNextK {
+		if (m.asc && m.xNextK < m.yNextK) || (!m.asc && m.xNextK > m.yNextK) {
 			m.advanceX()
 			continue
 		} else if m.xNextK == m.yNextK {
@@ -388,11 +393,22 @@ type Paginated[T any] struct {
 	i             int
 	err           error
 	nextPage      NextPageUno[T]
+	pageToken     string // token of the current page
 	nextPageToken string
+	skip          int // elements of the first page already returned before resume
 	initialized   bool
 }
 
 func Paginate[T any](f NextPageUno[T]) *Paginated[T] { return &Paginated[T]{nextPage: f} }
+
+// ResumePaginate - re-creates the stream from a token returned by ResumeToken. `f` must request the same range.
+func ResumePaginate[T any](f NextPageUno[T], token string) (*Paginated[T], error) {
+	pageToken, skip, err := decodeResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &Paginated[T]{nextPage: f, nextPageToken: pageToken, skip: skip}, nil
+}
 func (it *Paginated[T]) HasNext() bool {
 	if it.err != nil || it.i < len(it.arr) {
 		return true
@@ -401,8 +417,12 @@ func (it *Paginated[T]) HasNext() bool {
 		return false
 	}
 	it.initialized = true
-	it.i = 0
-	it.arr, it.nextPageToken, it.err = it.nextPage(it.nextPageToken)
+	it.pageToken = it.nextPageToken
+	it.arr, it.nextPageToken, it.err = it.nextPage(it.pageToken)
+	it.i, it.skip = min(it.skip, len(it.arr)), 0
+	if it.err == nil && it.i == len(it.arr) && it.nextPageToken != "" {
+		return it.HasNext()
+	}
 	return it.err != nil || it.i < len(it.arr)
 }
 func (it *Paginated[T]) Close() {}
@@ -414,6 +434,12 @@ func (it *Paginated[T]) Next() (v T, err error) {
 	it.i++
 	return v, nil
 }
+func (it *Paginated[T]) ResumeToken() string {
+	if !it.initialized {
+		return encodeResumeToken(it.nextPageToken, it.skip)
+	}
+	return encodeResumeToken(it.pageToken, it.i)
+}
 
 type PaginatedDuo[K, V any] struct {
 	keys          []K
@@ -421,13 +447,24 @@ type PaginatedDuo[K, V any] struct {
 	i             int
 	err           error
 	nextPage      NextPageDuo[K, V]
+	pageToken     string // token of the current page
 	nextPageToken string
+	skip          int // elements of the first page already returned before resume
 	initialized   bool
 }
 
 func PaginateDuo[K, V any](f NextPageDuo[K, V]) *PaginatedDuo[K, V] {
 	return &PaginatedDuo[K, V]{nextPage: f}
 }
+
+// ResumePaginateDuo - re-creates the stream from a token returned by ResumeToken. `f` must request the same range.
+func ResumePaginateDuo[K, V any](f NextPageDuo[K, V], token string) (*PaginatedDuo[K, V], error) {
+	pageToken, skip, err := decodeResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &PaginatedDuo[K, V]{nextPage: f, nextPageToken: pageToken, skip: skip}, nil
+}
 func (it *PaginatedDuo[K, V]) HasNext() bool {
 	if it.err != nil || it.i < len(it.keys) {
 		return true
@@ -436,8 +473,12 @@ func (it *PaginatedDuo[K, V]) HasNext() bool {
 		return false
 	}
 	it.initialized = true
-	it.i = 0
-	it.keys, it.values, it.nextPageToken, it.err = it.nextPage(it.nextPageToken)
+	it.pageToken = it.nextPageToken
+	it.keys, it.values, it.nextPageToken, it.err = it.nextPage(it.pageToken)
+	it.i, it.skip = min(it.skip, len(it.keys)), 0
+	if it.err == nil && it.i == len(it.keys) && it.nextPageToken != "" {
+		return it.HasNext()
+	}
 	return it.err != nil || it.i < len(it.keys)
 }
 func (it *PaginatedDuo[K, V]) Close() {}
@@ -449,3 +490,140 @@ func (it *PaginatedDuo[K, V]) Next() (k K, v V, err error) {
 	it.i++
 	return k, v, nil
 }
+func (it *PaginatedDuo[K, V]) ResumeToken() string {
+	if !it.initialized {
+		return encodeResumeToken(it.nextPageToken, it.skip)
+	}
+	return encodeResumeToken(it.pageToken, it.i)
+}
+
+// resume token: uvarint position within the page + page token, base64 encoded
+func encodeResumeToken(pageToken string, position int) string {
+	buf := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(pageToken)), uint64(position))
+	return base64.RawURLEncoding.EncodeToString(append(buf, pageToken...))
+}
+
+func decodeResumeToken(token string) (pageToken string, position int, err error) {
+	if token == "" {
+		return "", 0, nil
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid resume token: %w", err)
+	}
+	pos, n := binary.Uvarint(buf)
+	if n <= 0 || pos > math.MaxInt32 {
+		return "", 0, fmt.Errorf("invalid resume token: bad position")
+	}
+	return string(buf[n:]), int(pos), nil
+}
+
+// Limited - stops after `limit` elements of underlying stream. Doesn't read underlying stream after limit reached.
+type Limited[T any] struct {
+	it    Uno[T]
+	limit int
+}
+
+func Limit[T any](it Uno[T], limit int) *Limited[T] { return &Limited[T]{it: it, limit: limit} }
+func (m *Limited[T]) HasNext() bool                 { return m.limit > 0 && m.it.HasNext() }
+func (m *Limited[T]) Next() (v T, err error) {
+	if m.limit <= 0 {
+		return v, nil
+	}
+	m.limit--
+	return m.it.Next()
+}
+func (m *Limited[T]) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// LimitedDuo - stops after `limit` elements of underlying stream. Doesn't read underlying stream after limit reached.
+type LimitedDuo[K, V any] struct {
+	it    Duo[K, V]
+	limit int
+}
+
+func LimitDuo[K, V any](it Duo[K, V], limit int) *LimitedDuo[K, V] {
+	return &LimitedDuo[K, V]{it: it, limit: limit}
+}
+func (m *LimitedDuo[K, V]) HasNext() bool { return m.limit > 0 && m.it.HasNext() }
+func (m *LimitedDuo[K, V]) Next() (k K, v V, err error) {
+	if m.limit <= 0 {
+		return k, v, nil
+	}
+	m.limit--
+	return m.it.Next()
+}
+func (m *LimitedDuo[K, V]) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// Skipped - skips first `skip` elements of underlying stream. Skipping is lazy: happens on first HasNext/Next call,
+// error happened during skipping is returned by Next.
+type Skipped[T any] struct {
+	it   Uno[T]
+	skip int
+	err  error
+}
+
+func Skip[T any](it Uno[T], skip int) *Skipped[T] { return &Skipped[T]{it: it, skip: skip} }
+func (m *Skipped[T]) advance() {
+	for ; m.skip > 0 && m.err == nil && m.it.HasNext(); m.skip-- {
+		_, m.err = m.it.Next()
+	}
+	m.skip = 0
+}
+func (m *Skipped[T]) HasNext() bool {
+	m.advance()
+	return m.err != nil || m.it.HasNext()
+}
+func (m *Skipped[T]) Next() (v T, err error) {
+	m.advance()
+	if m.err != nil {
+		return v, m.err
+	}
+	return m.it.Next()
+}
+func (m *Skipped[T]) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// SkippedDuo - skips first `skip` elements of underlying stream. Skipping is lazy: happens on first HasNext/Next call,
+// error happened during skipping is returned by Next.
+type SkippedDuo[K, V any] struct {
+	it   Duo[K, V]
+	skip int
+	err  error
+}
+
+func SkipDuo[K, V any](it Duo[K, V], skip int) *SkippedDuo[K, V] {
+	return &SkippedDuo[K, V]{it: it, skip: skip}
+}
+func (m *SkippedDuo[K, V]) advance() {
+	for ; m.skip > 0 && m.err == nil && m.it.HasNext(); m.skip-- {
+		_, _, m.err = m.it.Next()
+	}
+	m.skip = 0
+}
+func (m *SkippedDuo[K, V]) HasNext() bool {
+	m.advance()
+	return m.err != nil || m.it.HasNext()
+}
+func (m *SkippedDuo[K, V]) Next() (k K, v V, err error) {
+	m.advance()
+	if m.err != nil {
+		return k, v, m.err
+	}
+	return m.it.Next()
+}
+func (m *SkippedDuo[K, V]) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
@@ -2,6 +2,11 @@ package iter
 
 import (
 	"bytes"
+	"container/heap"
+	"fmt"
+	"slices"
+
+	"github.com/ledgerwatch/erigon-lib/kv/order"
 )
 
 // often used shortcuts
@@ -58,10 +63,195 @@ type (
 func PaginateKV(f NextPageDuo[[]byte, []byte]) *PaginatedDuo[[]byte, []byte] {
 	return PaginateDuo[[]byte, []byte](f)
 }
+
 func PaginateU64(f NextPageUno[uint64]) *Paginated[uint64] {
 	return Paginate[uint64](f)
 }
 
+func ResumePaginateKV(f NextPageDuo[[]byte, []byte], token string) (*PaginatedDuo[[]byte, []byte], error) {
+	return ResumePaginateDuo[[]byte, []byte](f, token)
+}
+
+func ResumePaginateU64(f NextPageUno[uint64], token string) (*Paginated[uint64], error) {
+	return ResumePaginate[uint64](f, token)
+}
+
+func LimitKV(it KV, limit int) *LimitedDuo[[]byte, []byte] {
+	return LimitDuo[[]byte, []byte](it, limit)
+}
+func SkipKV(it KV, skip int) *SkippedDuo[[]byte, []byte] {
+	return SkipDuo[[]byte, []byte](it, skip)
+}
+func LimitU64(it U64, limit int) *Limited[uint64] {
+	return Limit[uint64](it, limit)
+}
+func SkipU64(it U64, skip int) *Skipped[uint64] {
+	return Skip[uint64](it, skip)
+}
+
+// WindowedKV - keys of ascending stream in [from, to) range. nil `from` or `to` means unbounded.
+// Pairs before `from` are skipped (better push-down `from` to lower-level iterator when possible),
+// underlying stream is not read after first key >= `to`.
+type WindowedKV struct {
+	it       KV
+	from, to []byte
+	hasNext  bool
+	done     bool // key >= `to` seen
+	err      error
+	nextK    []byte
+	nextV    []byte
+}
+
+func WindowKV(it KV, from, to []byte) *WindowedKV {
+	m := &WindowedKV{it: it, from: from, to: to}
+	m.advance()
+	return m
+}
+func (m *WindowedKV) advance() {
+	if m.err != nil {
+		return
+	}
+	m.hasNext = false
+	for !m.done && m.it.HasNext() {
+		k, v, err := m.it.Next()
+		if err != nil {
+			m.err = err
+			return
+		}
+		if m.from != nil && bytes.Compare(k, m.from) < 0 {
+			continue
+		}
+		if m.to != nil && bytes.Compare(k, m.to) >= 0 {
+			m.done = true
+			return
+		}
+		m.from = nil // stream is ascending: no need to compare with `from` anymore
+		m.hasNext, m.nextK, m.nextV = true, k, v
+		return
+	}
+}
+func (m *WindowedKV) HasNext() bool { return m.err != nil || m.hasNext }
+func (m *WindowedKV) Next() (k, v []byte, err error) {
+	k, v, err = m.nextK, m.nextV, m.err
+	m.advance()
+	return k, v, err
+}
+func (m *WindowedKV) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// WrappedErrKV - decorates errors of underlying stream by `wrap`, which receives last successfully returned key
+// (nil if nothing was returned yet). Key of the failure is often lost when error bubbles up through several
+// layers of merged iterators - wrap each layer to keep it.
+type WrappedErrKV struct {
+	it      KV
+	wrap    func(lastKey []byte, err error) error
+	lastKey []byte
+	hasLast bool
+}
+
+func WrapErrKV(it KV, wrap func(lastKey []byte, err error) error) *WrappedErrKV {
+	return &WrappedErrKV{it: it, wrap: wrap}
+}
+func (m *WrappedErrKV) HasNext() bool { return m.it.HasNext() }
+func (m *WrappedErrKV) Next() ([]byte, []byte, error) {
+	k, v, err := m.it.Next()
+	if err != nil {
+		var lastKey []byte
+		if m.hasLast {
+			lastKey = m.lastKey
+		}
+		return k, v, m.wrap(lastKey, err)
+	}
+	// copy: underlying stream may reuse key's memory
+	m.lastKey, m.hasLast = append(m.lastKey[:0], k...), true
+	return k, v, nil
+}
+func (m *WrappedErrKV) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// ErrWithKey - `wrap` func for WrapErrKV: adds source label and last returned key to error
+func ErrWithKey(label string) func(lastKey []byte, err error) error {
+	return func(lastKey []byte, err error) error {
+		if lastKey == nil {
+			return fmt.Errorf("%s: before first key: %w", label, err)
+		}
+		return fmt.Errorf("%s: after key %x: %w", label, lastKey, err)
+	}
+}
+
+// ZippedKV - joins 2 ascending kv.Pairs streams on equal keys: emits (key, xValue, yValue) only for keys present in both
+type ZippedKV struct {
+	x, y               KV
+	xHasNext, yHasNext bool
+	xNextK, xNextV     []byte
+	yNextK, yNextV     []byte
+	err                error
+}
+
+func ZipKV(x, y KV) *ZippedKV {
+	m := &ZippedKV{x: x, y: y}
+	m.advanceX()
+	m.advanceY()
+	m.advance()
+	return m
+}
+func (m *ZippedKV) HasNext() bool { return m.err != nil || (m.xHasNext && m.yHasNext) }
+func (m *ZippedKV) advance() {
+	for m.err == nil && m.xHasNext && m.yHasNext {
+		cmp := bytes.Compare(m.xNextK, m.yNextK)
+		if cmp == 0 {
+			return
+		}
+		if cmp < 0 {
+			m.advanceX()
+		} else {
+			m.advanceY()
+		}
+	}
+}
+func (m *ZippedKV) advanceX() {
+	if m.err != nil {
+		return
+	}
+	m.xHasNext = m.x.HasNext()
+	if m.xHasNext {
+		m.xNextK, m.xNextV, m.err = m.x.Next()
+	}
+}
+func (m *ZippedKV) advanceY() {
+	if m.err != nil {
+		return
+	}
+	m.yHasNext = m.y.HasNext()
+	if m.yHasNext {
+		m.yNextK, m.yNextV, m.err = m.y.Next()
+	}
+}
+func (m *ZippedKV) Next() (k, v1, v2 []byte, err error) {
+	if m.err != nil {
+		return nil, nil, nil, m.err
+	}
+	k, v1, v2 = m.xNextK, m.xNextV, m.yNextV
+	m.advanceX()
+	m.advanceY()
+	m.advance()
+	return k, v1, v2, nil
+}
+func (m *ZippedKV) Close() {
+	if x, ok := m.x.(Closer); ok {
+		x.Close()
+	}
+	if y, ok := m.y.(Closer); ok {
+		y.Close()
+	}
+}
+
 type TransformKV2U64Iter[K, V []byte] struct {
 	it        KV
 	transform func(K, V) (uint64, error)
@@ -84,18 +274,81 @@ func (m *TransformKV2U64Iter[K, v]) Close() {
 	}
 }
 
-// UnionKVIter - merge 2 kv.Pairs streams to 1 in lexicographically order
+// BatchedKV - groups pairs of underlying stream to batches of up to `n` pairs, to amortize per-pair
+// interface calls in hot loops (etl collection, snapshot building).
+// Keys and values are copied to internal buffer (underlying stream may reuse memory): returned batch is valid
+// only until next `Next` call. Error of underlying stream is returned by `Next` after pairs read before it.
+type BatchedKV struct {
+	it           KV
+	n            int
+	keys, values [][]byte
+	offsets      []int
+	buf          []byte
+	err          error
+}
+
+func BatchKV(it KV, n int) *BatchedKV {
+	if n <= 0 {
+		n = 1
+	}
+	return &BatchedKV{it: it, n: n}
+}
+func (m *BatchedKV) HasNext() bool { return m.err != nil || m.it.HasNext() }
+func (m *BatchedKV) Next() (keys, values [][]byte, err error) {
+	if m.err != nil {
+		err, m.err = m.err, nil
+		return nil, nil, err
+	}
+	m.buf, m.offsets = m.buf[:0], m.offsets[:0]
+	for len(m.offsets) < 2*m.n && m.it.HasNext() {
+		k, v, err := m.it.Next()
+		if err != nil {
+			if len(m.offsets) == 0 {
+				return nil, nil, err
+			}
+			m.err = err
+			break
+		}
+		m.buf = append(m.buf, k...)
+		m.offsets = append(m.offsets, len(m.buf))
+		m.buf = append(m.buf, v...)
+		m.offsets = append(m.offsets, len(m.buf))
+	}
+	// slice after all appends: buffer may be reallocated while growing
+	m.keys, m.values = m.keys[:0], m.values[:0]
+	var from int
+	for i := 0; i < len(m.offsets); i += 2 {
+		m.keys = append(m.keys, m.buf[from:m.offsets[i]:m.offsets[i]])
+		m.values = append(m.values, m.buf[m.offsets[i]:m.offsets[i+1]:m.offsets[i+1]])
+		from = m.offsets[i+1]
+	}
+	return m.keys, m.values, nil
+}
+func (m *BatchedKV) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
+
+// UnionKVIter - merge 2 kv.Pairs streams to 1 in lexicographically order (or reverse lexicographically order for UnionKVDesc)
 // 1-st stream has higher priority - when 2 streams return same key
 type UnionKVIter struct {
 	x, y               KV
+	asc                order.By
 	xHasNext, yHasNext bool
 	xNextK, xNextV     []byte
 	yNextK, yNextV     []byte
+	xPrevK, yPrevK     []byte
 	limit              int
 	err                error
 }
 
-func UnionKV(x, y KV, limit int) KV {
+func UnionKV(x, y KV, limit int) KV { return unionKV(x, y, order.Asc, limit) }
+
+// UnionKVDesc - same as UnionKV, but both streams must be in descending order (for example, produced by RangeDescend)
+func UnionKVDesc(x, y KV, limit int) KV { return unionKV(x, y, order.Desc, limit) }
+
+func unionKV(x, y KV, asc order.By, limit int) KV {
 	if x == nil && y == nil {
 		return EmptyKV
 	}
@@ -105,7 +358,7 @@ func UnionKV(x, y KV, limit int) KV {
 	if y == nil {
 		return x
 	}
-	m := &UnionKVIter{x: x, y: y, limit: limit}
+	m := &UnionKVIter{x: x, y: y, asc: asc, limit: limit}
 	m.advanceX()
 	m.advanceY()
 	return m
@@ -117,18 +370,28 @@ func (m *UnionKVIter) advanceX() {
 	if m.err != nil {
 		return
 	}
+	// keep a copy: streams are allowed to reuse key buffer
+	m.xPrevK = append(m.xPrevK[:0], m.xNextK...)
 	m.xHasNext = m.x.HasNext()
 	if m.xHasNext {
 		m.xNextK, m.xNextV, m.err = m.x.Next()
+		if m.err == nil && m.xNextK != nil && len(m.xPrevK) > 0 {
+			m.err = checkOrder(m.xPrevK, m.xNextK, m.asc)
+		}
 	}
 }
 func (m *UnionKVIter) advanceY() {
 	if m.err != nil {
 		return
 	}
+	// keep a copy: streams are allowed to reuse key buffer
+	m.yPrevK = append(m.yPrevK[:0], m.yNextK...)
 	m.yHasNext = m.y.HasNext()
 	if m.yHasNext {
 		m.yNextK, m.yNextV, m.err = m.y.Next()
+		if m.err == nil && m.yNextK != nil && len(m.yPrevK) > 0 {
+			m.err = checkOrder(m.yPrevK, m.yNextK, m.asc)
+		}
 	}
 }
 func (m *UnionKVIter) Next() ([]byte, []byte, error) {
@@ -137,7 +400,7 @@ func (m *UnionKVIter) Next() ([]byte, []byte, error) {
 	}
 	m.limit--
 	if m.xHasNext && m.yHasNext {
-		cmp := bytes.Compare(m.xNextK, m.yNextK)
+		cmp := compareKeys(m.xNextK, m.yNextK, m.asc)
 		if cmp < 0 {
 			k, v, err := m.xNextK, m.xNextV, m.err
 			m.advanceX()
@@ -172,6 +435,25 @@ func (m *UnionKVIter) Close() {
 	}
 }
 
+// compareKeys - bytes.Compare in the direction of iteration: negative result means `a` goes first
+func compareKeys(a, b []byte, asc order.By) int {
+	if asc {
+		return bytes.Compare(a, b)
+	}
+	return bytes.Compare(b, a)
+}
+
+// checkOrder - merging streams of different order silently produces garbage, so fail loudly instead
+func checkOrder(prev, next []byte, asc order.By) error {
+	if compareKeys(prev, next, asc) > 0 {
+		if asc {
+			return fmt.Errorf("iter: stream is not in ascending order: %x after %x", next, prev)
+		}
+		return fmt.Errorf("iter: stream is not in descending order: %x after %x", next, prev)
+	}
+	return nil
+}
+
 type WrapKVSIter struct {
 	y KV
 }
@@ -222,20 +504,27 @@ func (m *WrapKVIter) Close() {
 
 // MergedKV - merge 2 kv.Pairs streams (without replacements, or "shadowing",
 // meaning that all input pairs will appear in the output stream - this is
-// difference to UnionKVIter), to 1 in lexicographically order
+// difference to UnionKVIter), to 1 in lexicographically order (or reverse lexicographically order for MergeKVSDesc)
 // 1-st stream has higher priority - when 2 streams return same key
 type MergedKV struct {
 	x                  KVS
 	y                  KV
+	asc                order.By
 	xHasNext, yHasNext bool
 	xNextK, xNextV     []byte
 	yNextK, yNextV     []byte
+	xPrevK, yPrevK     []byte
 	xStep              uint64
 	limit              int
 	err                error
 }
 
-func MergeKVS(x KVS, y KV, limit int) KVS {
+func MergeKVS(x KVS, y KV, limit int) KVS { return mergeKVS(x, y, order.Asc, limit) }
+
+// MergeKVSDesc - same as MergeKVS, but both streams must be in descending order
+func MergeKVSDesc(x KVS, y KV, limit int) KVS { return mergeKVS(x, y, order.Desc, limit) }
+
+func mergeKVS(x KVS, y KV, asc order.By, limit int) KVS {
 	if x == nil && y == nil {
 		return EmptyKVS
 	}
@@ -245,7 +534,7 @@ func MergeKVS(x KVS, y KV, limit int) KVS {
 	if y == nil {
 		return x
 	}
-	m := &MergedKV{x: x, y: y, limit: limit}
+	m := &MergedKV{x: x, y: y, asc: asc, limit: limit}
 	m.advanceX()
 	m.advanceY()
 	return m
@@ -257,18 +546,28 @@ func (m *MergedKV) advanceX() {
 	if m.err != nil {
 		return
 	}
+	// keep a copy: streams are allowed to reuse key buffer
+	m.xPrevK = append(m.xPrevK[:0], m.xNextK...)
 	m.xHasNext = m.x.HasNext()
 	if m.xHasNext {
 		m.xNextK, m.xNextV, m.xStep, m.err = m.x.Next()
+		if m.err == nil && m.xNextK != nil && len(m.xPrevK) > 0 {
+			m.err = checkOrder(m.xPrevK, m.xNextK, m.asc)
+		}
 	}
 }
 func (m *MergedKV) advanceY() {
 	if m.err != nil {
 		return
 	}
+	// keep a copy: streams are allowed to reuse key buffer
+	m.yPrevK = append(m.yPrevK[:0], m.yNextK...)
 	m.yHasNext = m.y.HasNext()
 	if m.yHasNext {
 		m.yNextK, m.yNextV, m.err = m.y.Next()
+		if m.err == nil && m.yNextK != nil && len(m.yPrevK) > 0 {
+			m.err = checkOrder(m.yPrevK, m.yNextK, m.asc)
+		}
 	}
 }
 func (m *MergedKV) Next() ([]byte, []byte, uint64, error) {
@@ -277,7 +576,7 @@ func (m *MergedKV) Next() ([]byte, []byte, uint64, error) {
 	}
 	m.limit--
 	if m.xHasNext && m.yHasNext {
-		cmp := bytes.Compare(m.xNextK, m.yNextK)
+		cmp := compareKeys(m.xNextK, m.yNextK, m.asc)
 		if cmp <= 0 {
 			k, v, step, err := m.xNextK, m.xNextV, m.xStep, m.err
 			m.advanceX()
@@ -307,6 +606,177 @@ func (m *MergedKV) Close() {
 	}
 }
 
+// NWayMergedKVS - merge any amount of kv.Pairs streams (without replacements, like MergedKV) to 1 in
+// lexicographically order (or reverse lexicographically order for NWayMergeKVSDesc). Streams are kept in a heap
+// by their next key, so emitting a pair costs O(log(streams)) comparisons - instead of O(streams) of nested
+// 2-way MergedKV wrappers. Earlier stream has higher priority - when several streams return same key
+type NWayMergedKVS struct {
+	its   nWayMergeHeap
+	asc   order.By
+	limit int
+	err   error
+}
+
+type nWayMergeSource struct {
+	it           KVS
+	priority     int
+	nextK, nextV []byte
+	nextStep     uint64
+	prevK        []byte
+}
+
+type nWayMergeHeap struct {
+	sources []*nWayMergeSource
+	asc     order.By
+}
+
+func (h *nWayMergeHeap) Len() int { return len(h.sources) }
+func (h *nWayMergeHeap) Less(i, j int) bool {
+	if cmp := compareKeys(h.sources[i].nextK, h.sources[j].nextK, h.asc); cmp != 0 {
+		return cmp < 0
+	}
+	return h.sources[i].priority < h.sources[j].priority
+}
+func (h *nWayMergeHeap) Swap(i, j int)      { h.sources[i], h.sources[j] = h.sources[j], h.sources[i] }
+func (h *nWayMergeHeap) Push(x interface{}) { h.sources = append(h.sources, x.(*nWayMergeSource)) }
+func (h *nWayMergeHeap) Pop() interface{} {
+	old := h.sources
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	h.sources = old[:n-1]
+	return x
+}
+
+func NWayMergeKVS(its []KVS, limit int) KVS { return nWayMergeKVS(its, order.Asc, limit) }
+
+// NWayMergeKVSDesc - same as NWayMergeKVS, but all streams must be in descending order
+func NWayMergeKVSDesc(its []KVS, limit int) KVS { return nWayMergeKVS(its, order.Desc, limit) }
+
+func nWayMergeKVS(its []KVS, asc order.By, limit int) KVS {
+	m := &NWayMergedKVS{its: nWayMergeHeap{sources: make([]*nWayMergeSource, 0, len(its)), asc: asc}, asc: asc, limit: limit}
+	for i, it := range its {
+		if it == nil {
+			continue
+		}
+		src := &nWayMergeSource{it: it, priority: i}
+		if m.advance(src) {
+			m.its.sources = append(m.its.sources, src)
+		} else if x, ok := it.(Closer); ok {
+			x.Close()
+		}
+		if m.err != nil {
+			break
+		}
+	}
+	heap.Init(&m.its)
+	return m
+}
+
+// advance - reads next pair of src, returns false if src is exhausted
+func (m *NWayMergedKVS) advance(src *nWayMergeSource) bool {
+	if m.err != nil {
+		return false
+	}
+	if !src.it.HasNext() {
+		return false
+	}
+	// keep a copy: streams are allowed to reuse key buffer
+	src.prevK = append(src.prevK[:0], src.nextK...)
+	src.nextK, src.nextV, src.nextStep, m.err = src.it.Next()
+	if m.err == nil && src.nextK != nil && len(src.prevK) > 0 {
+		m.err = checkOrder(src.prevK, src.nextK, m.asc)
+	}
+	return m.err == nil
+}
+
+func (m *NWayMergedKVS) HasNext() bool {
+	return m.err != nil || (m.limit != 0 && m.its.Len() > 0)
+}
+
+func (m *NWayMergedKVS) Next() ([]byte, []byte, uint64, error) {
+	if m.err != nil {
+		return nil, nil, 0, m.err
+	}
+	m.limit--
+	top := m.its.sources[0]
+	k, v, step := top.nextK, top.nextV, top.nextStep
+	if m.advance(top) {
+		heap.Fix(&m.its, 0)
+	} else {
+		if m.err != nil {
+			return nil, nil, 0, m.err
+		}
+		if x, ok := top.it.(Closer); ok {
+			x.Close()
+		}
+		heap.Pop(&m.its)
+	}
+	return k, v, step, nil
+}
+
+func (m *NWayMergedKVS) Close() {
+	for _, src := range m.its.sources {
+		if x, ok := src.it.(Closer); ok {
+			x.Close()
+		}
+	}
+	m.its.sources = nil
+}
+
 type Closer interface {
 	Close()
 }
+
+// KeyRange - [From, To) range of keys, nil From or To means unbounded
+type KeyRange struct {
+	From, To []byte
+}
+
+// SplitRange - splits [from, to) into at most n adjacent ranges of balanced estimated size, for parallel consumers.
+// `samples` are keys of the range (in any order), each of them standing for the same amount of data - for example keys
+// at evenly spaced ordinals of domain files, see state.DomainRoTx.RangeSamples. Each range gets the same number of
+// samples. Samples out of [from, to) are ignored, without samples the whole range is returned.
+func SplitRange(from, to []byte, n int, samples [][]byte) []KeyRange {
+	inRange := make([][]byte, 0, len(samples))
+	for _, k := range samples {
+		if (from != nil && bytes.Compare(k, from) < 0) || (to != nil && bytes.Compare(k, to) >= 0) {
+			continue
+		}
+		inRange = append(inRange, k)
+	}
+	slices.SortFunc(inRange, bytes.Compare)
+
+	ranges := make([]KeyRange, 0, n)
+	start := from
+	for i := 1; i < n && len(inRange) > 0; i++ {
+		boundary := inRange[i*len(inRange)/n]
+		if bytes.Compare(boundary, start) <= 0 {
+			continue // many samples of same key, or samples of the range start
+		}
+		ranges = append(ranges, KeyRange{From: start, To: boundary})
+		start = boundary
+	}
+	return append(ranges, KeyRange{From: start, To: to})
+}
+
+// PartitionKV - sub-streams of [from, to) split by SplitRange, opened by `open`. Each consumer must own its stream:
+// `open` has to create own read transaction (or cursors, files readers) if they are not thread-safe.
+// Streams are windowed by their ranges, so `open` may push-down only `from`.
+func PartitionKV(from, to []byte, n int, samples [][]byte, open func(from, to []byte) (KV, error)) ([]KV, error) {
+	ranges := SplitRange(from, to, n, samples)
+	its := make([]KV, 0, len(ranges))
+	for _, r := range ranges {
+		it, err := open(r.From, r.To)
+		if err != nil {
+			for _, opened := range its {
+				if x, ok := opened.(Closer); ok {
+					x.Close()
+				}
+			}
+			return nil, err
+		}
+		its = append(its, WindowKV(it, r.From, r.To))
+	}
+	return its, nil
+}
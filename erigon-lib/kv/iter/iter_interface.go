@@ -78,6 +78,13 @@ type Trio[K, V1, V2 any] interface {
 	Close()
 }
 
+// Resumable - stream which can export its position as an opaque token, e.g. to continue a long remote stream after
+// the connection was dropped. A stream re-created from the token (see ResumePaginate) over the same range continues
+// right after the last element returned by Next.
+type Resumable interface {
+	ResumeToken() string
+}
+
 // Deprecated - use Trio
 type DualS[K, V any] interface {
 	Next() (K, V, uint64, error)
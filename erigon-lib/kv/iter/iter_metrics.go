@@ -0,0 +1,54 @@
+package iter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+type kvMetrics struct {
+	pairs  metrics.Counter
+	bytes  metrics.Counter
+	errors metrics.Counter
+	next   metrics.Histogram
+}
+
+func getKVMetrics(label string) *kvMetrics {
+	return &kvMetrics{
+		pairs:  metrics.GetOrCreateCounter(fmt.Sprintf(`iter_pairs_total{name="%s"}`, label)),
+		bytes:  metrics.GetOrCreateCounter(fmt.Sprintf(`iter_bytes_total{name="%s"}`, label)),
+		errors: metrics.GetOrCreateCounter(fmt.Sprintf(`iter_errors_total{name="%s"}`, label)),
+		next:   metrics.GetOrCreateHistogram(fmt.Sprintf(`iter_next_seconds{name="%s"}`, label)),
+	}
+}
+
+// InstrumentedKV - reports pairs and bytes (keys + values) yielded by underlying stream, errors and Next() latency
+// under metrics labeled by `label`. Wrap each layer of iterators composition (with own label) to find which one is slow.
+// All streams instrumented with same label share metrics.
+type InstrumentedKV struct {
+	it KV
+	m  *kvMetrics
+}
+
+func InstrumentKV(label string, it KV) *InstrumentedKV {
+	return &InstrumentedKV{it: it, m: getKVMetrics(label)}
+}
+func (m *InstrumentedKV) HasNext() bool { return m.it.HasNext() }
+func (m *InstrumentedKV) Next() ([]byte, []byte, error) {
+	start := time.Now()
+	k, v, err := m.it.Next()
+	m.m.next.ObserveDuration(start)
+	if err != nil {
+		m.m.errors.Inc()
+		return k, v, err
+	}
+	m.m.pairs.Inc()
+	m.m.bytes.AddInt(len(k) + len(v))
+	return k, v, nil
+}
+func (m *InstrumentedKV) Close() {
+	if x, ok := m.it.(Closer); ok {
+		x.Close()
+	}
+}
@@ -0,0 +1,440 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package iter_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"pgregory.net/rapid"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+)
+
+// Property-based tests of iterators composition: random sorted streams are combined into random stacks of
+// combinators, output of the stack is checked against a model - same combinators implemented over plain slices.
+// Checked properties: order of output, completeness (output is exactly the model's one, which also covers
+// limit/skip semantics) and Close propagation: closing the top of a stack closes every source stream,
+// also when the stack was not read to the end.
+//
+// Run `go test -run=TestProperties -rapid.checks=10000` for a longer run, or fuzz stacks with
+// `go test -fuzz=FuzzKVStack`. Failures are shrunk to a minimal stack and can be replayed by `-rapid.failfile`.
+
+func TestPropertiesKV(t *testing.T)  { rapid.Check(t, checkKVStack) }
+func TestPropertiesU64(t *testing.T) { rapid.Check(t, checkU64Stack) }
+
+func FuzzKVStack(f *testing.F)  { f.Fuzz(rapid.MakeFuzz(checkKVStack)) }
+func FuzzU64Stack(f *testing.F) { f.Fuzz(rapid.MakeFuzz(checkU64Stack)) }
+
+// maxStackDepth - deeper stacks rarely find anything new, but make shrinking slow
+const maxStackDepth = 4
+
+type pair struct{ k, v []byte }
+
+func (p pair) String() string { return fmt.Sprintf("%x:%x", p.k, p.v) }
+
+// sourceKV - leaf stream, counts Close calls
+type sourceKV struct {
+	pairs  []pair
+	i      int
+	closed int
+}
+
+func (s *sourceKV) HasNext() bool { return s.i < len(s.pairs) }
+func (s *sourceKV) Next() ([]byte, []byte, error) {
+	if s.closed > 0 {
+		return nil, nil, fmt.Errorf("read after close")
+	}
+	p := s.pairs[s.i]
+	s.i++
+	return p.k, p.v, nil
+}
+func (s *sourceKV) Close() { s.closed++ }
+
+// kvStack - stack of combinators and the model of its output
+type kvStack struct {
+	it      iter.KV
+	model   []pair
+	sources []*sourceKV
+	name    string
+}
+
+func compareKeys(a, b []byte, asc order.By) int {
+	if asc {
+		return bytes.Compare(a, b)
+	}
+	return bytes.Compare(b, a)
+}
+
+func drawSourceKV(t *rapid.T, asc order.By) kvStack {
+	// small alphabet and short keys - to get many equal keys in different streams
+	keys := rapid.SliceOfNDistinct(rapid.SliceOfN(rapid.ByteRange(0, 3), 1, 2), 0, 8, func(k []byte) string { return string(k) }).Draw(t, "keys")
+	sort.Slice(keys, func(i, j int) bool { return compareKeys(keys[i], keys[j], asc) < 0 })
+	src := &sourceKV{pairs: make([]pair, len(keys))}
+	for i, k := range keys {
+		src.pairs[i] = pair{k: k, v: rapid.SliceOfN(rapid.Byte(), 0, 2).Draw(t, "value")}
+	}
+	return kvStack{it: src, model: src.pairs, sources: []*sourceKV{src}, name: fmt.Sprintf("src%v", src.pairs)}
+}
+
+func drawLimit(t *rapid.T, n int) int { return rapid.IntRange(-1, n+1).Draw(t, "limit") }
+
+func limitModel[T any](model []T, limit int) []T {
+	if limit >= 0 && limit < len(model) {
+		return model[:limit]
+	}
+	return model
+}
+
+// unionModel - x has priority on equal keys, both streams are advanced
+func unionModel(x, y []pair, asc order.By) (res []pair) {
+	for len(x) > 0 && len(y) > 0 {
+		switch c := compareKeys(x[0].k, y[0].k, asc); {
+		case c < 0:
+			res, x = append(res, x[0]), x[1:]
+		case c == 0:
+			res, x, y = append(res, x[0]), x[1:], y[1:]
+		default:
+			res, y = append(res, y[0]), y[1:]
+		}
+	}
+	return append(append(res, x...), y...)
+}
+
+// mergeModel - stable merge: all pairs are kept, x goes first on equal keys
+func mergeModel(x, y []pair, asc order.By) (res []pair) {
+	res = append(append(res, x...), y...)
+	sort.SliceStable(res, func(i, j int) bool { return compareKeys(res[i].k, res[j].k, asc) < 0 })
+	return res
+}
+
+func filterModel[T any](model []T, f func(T) bool) (res []T) {
+	for _, p := range model {
+		if f(p) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func drawKVStack(t *rapid.T, asc order.By, depth int) kvStack {
+	kinds := []string{"source", "union", "merge", "nway", "filter", "limit", "skip", "transform", "wrapErr"}
+	if asc {
+		kinds = append(kinds, "window") // WindowKV supports only ascending streams
+	}
+	kind := "source"
+	if depth > 0 {
+		kind = rapid.SampledFrom(kinds).Draw(t, "kind")
+	}
+	switch kind {
+	case "union":
+		x, y := drawKVStack(t, asc, depth-1), drawKVStack(t, asc, depth-1)
+		limit := drawLimit(t, len(x.model)+len(y.model))
+		s := joinKV(fmt.Sprintf("union(limit=%d)", limit), x, y)
+		if asc {
+			s.it = iter.UnionKV(x.it, y.it, limit)
+		} else {
+			s.it = iter.UnionKVDesc(x.it, y.it, limit)
+		}
+		s.model = limitModel(unionModel(x.model, y.model, asc), limit)
+		return s
+	case "merge":
+		x, y := drawKVStack(t, asc, depth-1), drawKVStack(t, asc, depth-1)
+		limit := drawLimit(t, len(x.model)+len(y.model))
+		s := joinKV(fmt.Sprintf("merge(limit=%d)", limit), x, y)
+		if asc {
+			s.it = iter.WrapKV(iter.MergeKVS(iter.WrapKVS(x.it), y.it, limit))
+		} else {
+			s.it = iter.WrapKV(iter.MergeKVSDesc(iter.WrapKVS(x.it), y.it, limit))
+		}
+		s.model = limitModel(mergeModel(x.model, y.model, asc), limit)
+		return s
+	case "nway":
+		children := make([]kvStack, rapid.IntRange(0, 4).Draw(t, "streams"))
+		its, total := make([]iter.KVS, len(children)), 0
+		for i := range children {
+			children[i] = drawKVStack(t, asc, depth-1)
+			its[i], total = iter.WrapKVS(children[i].it), total+len(children[i].model)
+		}
+		limit := drawLimit(t, total)
+		s := joinKV(fmt.Sprintf("nway(limit=%d)", limit), children...)
+		if asc {
+			s.it = iter.WrapKV(iter.NWayMergeKVS(its, limit))
+		} else {
+			s.it = iter.WrapKV(iter.NWayMergeKVSDesc(its, limit))
+		}
+		for _, c := range children {
+			s.model = mergeModel(s.model, c.model, asc)
+		}
+		s.model = limitModel(s.model, limit)
+		return s
+	case "filter":
+		x := drawKVStack(t, asc, depth-1)
+		b := rapid.ByteRange(0, 3).Draw(t, "filterByte")
+		f := func(k, v []byte) bool { return k[len(k)-1] != b }
+		s := joinKV(fmt.Sprintf("filter(last key byte != %d)", b), x)
+		s.it = iter.FilterKV(x.it, f)
+		s.model = filterModel(x.model, func(p pair) bool { return f(p.k, p.v) })
+		return s
+	case "limit":
+		x := drawKVStack(t, asc, depth-1)
+		limit := drawLimit(t, len(x.model))
+		s := joinKV(fmt.Sprintf("limit(%d)", limit), x)
+		s.it = iter.LimitKV(x.it, limit)
+		if limit < 0 { // Limited doesn't treat negative limit as unlimited
+			limit = 0
+		}
+		s.model = limitModel(x.model, limit)
+		return s
+	case "skip":
+		x := drawKVStack(t, asc, depth-1)
+		skip := rapid.IntRange(0, len(x.model)+1).Draw(t, "skip")
+		s := joinKV(fmt.Sprintf("skip(%d)", skip), x)
+		s.it = iter.SkipKV(x.it, skip)
+		s.model = x.model[min(skip, len(x.model)):]
+		return s
+	case "transform":
+		x := drawKVStack(t, asc, depth-1)
+		s := joinKV("transform(value+ff)", x)
+		s.it = iter.TransformKV(x.it, func(k, v []byte) ([]byte, []byte, error) {
+			return k, append(common.Copy(v), 0xff), nil
+		})
+		for _, p := range x.model {
+			s.model = append(s.model, pair{p.k, append(common.Copy(p.v), 0xff)})
+		}
+		return s
+	case "wrapErr":
+		x := drawKVStack(t, asc, depth-1)
+		s := joinKV("wrapErr", x)
+		s.it, s.model = iter.WrapErrKV(x.it, iter.ErrWithKey("prop")), x.model
+		return s
+	case "window":
+		x := drawKVStack(t, asc, depth-1)
+		var from, to []byte
+		if rapid.Bool().Draw(t, "hasFrom") {
+			from = rapid.SliceOfN(rapid.ByteRange(0, 3), 1, 2).Draw(t, "from")
+		}
+		if rapid.Bool().Draw(t, "hasTo") {
+			to = rapid.SliceOfN(rapid.ByteRange(0, 3), 1, 2).Draw(t, "to")
+		}
+		s := joinKV(fmt.Sprintf("window(%x, %x)", from, to), x)
+		s.it = iter.WindowKV(x.it, from, to)
+		for _, p := range x.model {
+			if to != nil && bytes.Compare(p.k, to) >= 0 {
+				break
+			}
+			if from == nil || bytes.Compare(p.k, from) >= 0 {
+				s.model = append(s.model, p)
+			}
+		}
+		return s
+	default:
+		return drawSourceKV(t, asc)
+	}
+}
+
+// joinKV - stack named `name` over children: collects their sources, `it` and `model` are set by caller
+func joinKV(name string, children ...kvStack) kvStack {
+	s := kvStack{}
+	names := make([]string, len(children))
+	for i, c := range children {
+		s.sources = append(s.sources, c.sources...)
+		names[i] = c.name
+	}
+	s.name = fmt.Sprintf("%s[%s]", name, strings.Join(names, ", "))
+	return s
+}
+
+func checkKVStack(t *rapid.T) {
+	asc := order.By(rapid.Bool().Draw(t, "asc"))
+	s := drawKVStack(t, asc, rapid.IntRange(0, maxStackDepth).Draw(t, "depth"))
+	// read only a part of the stack sometimes - Close must propagate anyway
+	read := rapid.IntRange(0, len(s.model)).Draw(t, "read")
+	if rapid.Bool().Draw(t, "readAll") {
+		read = len(s.model) + 1
+	}
+
+	var got []pair
+	for len(got) < read && s.it.HasNext() {
+		k, v, err := s.it.Next()
+		if err != nil {
+			t.Fatalf("%s: unexpected error after %v: %v", s.name, got, err)
+		}
+		if len(got) > 0 && compareKeys(got[len(got)-1].k, k, asc) > 0 {
+			t.Fatalf("%s: order is broken: %x after %v", s.name, k, got)
+		}
+		// copy: streams may reuse memory of returned key/value
+		got = append(got, pair{common.Copy(k), common.Copy(v)})
+	}
+	expected := s.model
+	if read < len(expected) {
+		expected = expected[:read]
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("%s:\ngot      %v\nexpected %v", s.name, got, expected)
+	}
+	if read > len(s.model) && s.it.HasNext() {
+		t.Fatalf("%s: HasNext after all %d pairs of model", s.name, len(s.model))
+	}
+
+	s.it.Close()
+	for i, src := range s.sources {
+		if src.closed == 0 {
+			t.Fatalf("%s: source %d is not closed", s.name, i)
+		}
+	}
+}
+
+// sourceU64 - leaf stream, counts Close calls
+type sourceU64 struct {
+	vals   []uint64
+	i      int
+	closed int
+}
+
+func (s *sourceU64) HasNext() bool { return s.i < len(s.vals) }
+func (s *sourceU64) Next() (uint64, error) {
+	if s.closed > 0 {
+		return 0, fmt.Errorf("read after close")
+	}
+	s.i++
+	return s.vals[s.i-1], nil
+}
+func (s *sourceU64) Close() { s.closed++ }
+
+type u64Stack struct {
+	it      iter.U64
+	model   []uint64
+	sources []*sourceU64
+	name    string
+}
+
+func lessU64(a, b uint64, asc order.By) bool { return bool(asc) && a < b || !bool(asc) && a > b }
+
+func drawU64Stack(t *rapid.T, asc order.By, depth int) u64Stack {
+	kind := "source"
+	if depth > 0 {
+		kind = rapid.SampledFrom([]string{"source", "union", "intersect", "filter", "limit", "skip"}).Draw(t, "kind")
+	}
+	switch kind {
+	case "union", "intersect":
+		x, y := drawU64Stack(t, asc, depth-1), drawU64Stack(t, asc, depth-1)
+		limit := drawLimit(t, len(x.model)+len(y.model))
+		s := joinU64(fmt.Sprintf("%s(limit=%d)", kind, limit), x, y)
+		inY := map[uint64]bool{}
+		for _, v := range y.model {
+			inY[v] = true
+		}
+		if kind == "union" {
+			s.it = iter.Union[uint64](x.it, y.it, asc, limit)
+			s.model = append(append(s.model, x.model...), filterModel(y.model, func(v uint64) bool { return !contains(x.model, v) })...)
+			sort.Slice(s.model, func(i, j int) bool { return lessU64(s.model[i], s.model[j], asc) })
+		} else {
+			s.it = iter.Intersect[uint64](x.it, y.it, asc, limit)
+			s.model = filterModel(x.model, func(v uint64) bool { return inY[v] })
+		}
+		s.model = limitModel(s.model, limit)
+		return s
+	case "filter":
+		x := drawU64Stack(t, asc, depth-1)
+		mod := rapid.Uint64Range(2, 3).Draw(t, "mod")
+		f := func(v uint64) bool { return v%mod != 0 }
+		s := joinU64(fmt.Sprintf("filter(v%%%d != 0)", mod), x)
+		s.it, s.model = iter.FilterU64(x.it, f), filterModel(x.model, f)
+		return s
+	case "limit":
+		x := drawU64Stack(t, asc, depth-1)
+		limit := rapid.IntRange(0, len(x.model)+1).Draw(t, "limit")
+		s := joinU64(fmt.Sprintf("limit(%d)", limit), x)
+		s.it, s.model = iter.LimitU64(x.it, limit), limitModel(x.model, limit)
+		return s
+	case "skip":
+		x := drawU64Stack(t, asc, depth-1)
+		skip := rapid.IntRange(0, len(x.model)+1).Draw(t, "skip")
+		s := joinU64(fmt.Sprintf("skip(%d)", skip), x)
+		s.it, s.model = iter.SkipU64(x.it, skip), x.model[min(skip, len(x.model)):]
+		return s
+	default:
+		vals := rapid.SliceOfNDistinct(rapid.Uint64Range(0, 16), 0, 8, rapid.ID[uint64]).Draw(t, "vals")
+		sort.Slice(vals, func(i, j int) bool { return lessU64(vals[i], vals[j], asc) })
+		src := &sourceU64{vals: vals}
+		return u64Stack{it: src, model: vals, sources: []*sourceU64{src}, name: fmt.Sprintf("src%v", vals)}
+	}
+}
+
+func contains(vals []uint64, v uint64) bool {
+	for _, x := range vals {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinU64(name string, children ...u64Stack) u64Stack {
+	s := u64Stack{}
+	names := make([]string, len(children))
+	for i, c := range children {
+		s.sources = append(s.sources, c.sources...)
+		names[i] = c.name
+	}
+	s.name = fmt.Sprintf("%s[%s]", name, strings.Join(names, ", "))
+	return s
+}
+
+func checkU64Stack(t *rapid.T) {
+	asc := order.By(rapid.Bool().Draw(t, "asc"))
+	s := drawU64Stack(t, asc, rapid.IntRange(0, maxStackDepth).Draw(t, "depth"))
+	read := rapid.IntRange(0, len(s.model)).Draw(t, "read")
+	if rapid.Bool().Draw(t, "readAll") {
+		read = len(s.model) + 1
+	}
+
+	var got []uint64
+	for len(got) < read && s.it.HasNext() {
+		v, err := s.it.Next()
+		if err != nil {
+			t.Fatalf("%s: unexpected error after %v: %v", s.name, got, err)
+		}
+		if len(got) > 0 && !lessU64(got[len(got)-1], v, asc) {
+			t.Fatalf("%s: order is broken: %d after %v", s.name, v, got)
+		}
+		got = append(got, v)
+	}
+	expected := s.model
+	if read < len(expected) {
+		expected = expected[:read]
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("%s:\ngot      %v\nexpected %v", s.name, got, expected)
+	}
+	if read > len(s.model) && s.it.HasNext() {
+		t.Fatalf("%s: HasNext after all %d values of model", s.name, len(s.model))
+	}
+
+	s.it.Close()
+	for i, src := range s.sources {
+		if src.closed == 0 {
+			t.Fatalf("%s: source %d is not closed", s.name, i)
+		}
+	}
+}
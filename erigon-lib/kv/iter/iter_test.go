@@ -19,6 +19,7 @@ package iter_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/metrics"
 	"github.com/stretchr/testify/require"
 )
 
@@ -141,34 +143,206 @@ func TestUnionPairs(t *testing.T) {
 		require.Equal("expected error at iteration: 10", err.Error())
 		require.Equal(10, len(keys))
 	})
+	t.Run("desc", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRw(ctx)
+		defer tx.Rollback()
+		_ = tx.Put(kv.E2AccountsHistory, []byte{1}, []byte{1})
+		_ = tx.Put(kv.E2AccountsHistory, []byte{3}, []byte{1})
+		_ = tx.Put(kv.E2AccountsHistory, []byte{4}, []byte{1})
+		_ = tx.Put(kv.PlainState, []byte{2}, []byte{9})
+		_ = tx.Put(kv.PlainState, []byte{3}, []byte{9})
+		it, _ := tx.RangeDescend(kv.E2AccountsHistory, nil, nil, -1)
+		it2, _ := tx.RangeDescend(kv.PlainState, nil, nil, -1)
+		keys, values, err := iter.ToArrayKV(iter.UnionKVDesc(it, it2, -1))
+		require.NoError(err)
+		require.Equal([][]byte{{4}, {3}, {2}, {1}}, keys)
+		require.Equal([][]byte{{1}, {1}, {9}, {1}}, values)
+
+		it, _ = tx.RangeDescend(kv.E2AccountsHistory, nil, nil, -1)
+		it2, _ = tx.RangeDescend(kv.PlainState, nil, nil, -1)
+		keys, _, err = iter.ToArrayKV(iter.UnionKVDesc(it, it2, 2))
+		require.NoError(err)
+		require.Equal([][]byte{{4}, {3}}, keys)
+	})
+	t.Run("wrong order", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRw(ctx)
+		defer tx.Rollback()
+		_ = tx.Put(kv.E2AccountsHistory, []byte{1}, []byte{1})
+		_ = tx.Put(kv.E2AccountsHistory, []byte{3}, []byte{1})
+		_ = tx.Put(kv.PlainState, []byte{2}, []byte{9})
+		it, _ := tx.Range(kv.E2AccountsHistory, nil, nil)
+		it2, _ := tx.Range(kv.PlainState, nil, nil)
+		_, _, err := iter.ToArrayKV(iter.UnionKVDesc(it, it2, -1))
+		require.ErrorContains(err, "not in descending order")
+	})
+}
+
+func TestMergeKVS(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		_ = tx.Put(kv.E2AccountsHistory, []byte{1}, []byte{1})
+		_ = tx.Put(kv.E2AccountsHistory, []byte{3}, []byte{1})
+		_ = tx.Put(kv.PlainState, []byte{2}, []byte{9})
+		return tx.Put(kv.PlainState, []byte{3}, []byte{9})
+	}))
+
+	t.Run("asc", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		it, _ := tx.Range(kv.E2AccountsHistory, nil, nil)
+		it2, _ := tx.Range(kv.PlainState, nil, nil)
+		keys, values, err := iter.ToArrayKV(iter.WrapKV(iter.MergeKVS(iter.WrapKVS(it), it2, -1)))
+		require.NoError(err)
+		require.Equal([][]byte{{1}, {2}, {3}, {3}}, keys)
+		require.Equal([][]byte{{1}, {9}, {1}, {9}}, values)
+	})
+	t.Run("desc", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		it, _ := tx.RangeDescend(kv.E2AccountsHistory, nil, nil, -1)
+		it2, _ := tx.RangeDescend(kv.PlainState, nil, nil, -1)
+		keys, values, err := iter.ToArrayKV(iter.WrapKV(iter.MergeKVSDesc(iter.WrapKVS(it), it2, -1)))
+		require.NoError(err)
+		require.Equal([][]byte{{3}, {3}, {2}, {1}}, keys)
+		require.Equal([][]byte{{1}, {9}, {9}, {1}}, values)
+	})
+	t.Run("wrong order", func(t *testing.T) {
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		it, _ := tx.RangeDescend(kv.E2AccountsHistory, nil, nil, -1)
+		it2, _ := tx.RangeDescend(kv.PlainState, nil, nil, -1)
+		_, _, err := iter.ToArrayKV(iter.WrapKV(iter.MergeKVS(iter.WrapKVS(it), it2, -1)))
+		require.ErrorContains(t, err, "not in ascending order")
+	})
+}
+
+func TestNWayMergeKVS(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	ctx := context.Background()
+	tables := []string{kv.E2AccountsHistory, kv.E2StorageHistory, kv.PlainState, kv.Code}
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		for i, table := range tables {
+			for k := byte(i); k < 20; k += byte(i) + 1 {
+				if err := tx.Put(table, []byte{k}, []byte{byte(i)}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}))
+	streams := func(tx kv.Tx, asc order.By) (its []iter.KVS) {
+		for _, table := range tables {
+			var it iter.KV
+			if asc {
+				it, _ = tx.Range(table, nil, nil)
+			} else {
+				it, _ = tx.RangeDescend(table, nil, nil, -1)
+			}
+			its = append(its, iter.WrapKVS(it))
+		}
+		return its
+	}
+	// nested 2-way merges produce same output: earlier stream wins on equal keys
+	nested := func(its []iter.KVS, asc order.By) iter.KVS {
+		res := its[len(its)-1]
+		for i := len(its) - 2; i >= 0; i-- {
+			if asc {
+				res = iter.MergeKVS(its[i], iter.WrapKV(res), -1)
+			} else {
+				res = iter.MergeKVSDesc(its[i], iter.WrapKV(res), -1)
+			}
+		}
+		return res
+	}
+
+	t.Run("asc", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		keys, values, err := iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVS(streams(tx, order.Asc), -1)))
+		require.NoError(err)
+		expectKeys, expectValues, err := iter.ToArrayKV(iter.WrapKV(nested(streams(tx, order.Asc), order.Asc)))
+		require.NoError(err)
+		require.Equal(expectKeys, keys)
+		require.Equal(expectValues, values)
+		require.Equal([][]byte{{0}, {1}, {1}, {2}}, keys[:4])
+		require.Equal([][]byte{{0}, {0}, {1}, {0}}, values[:4])
+	})
+	t.Run("desc", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		keys, values, err := iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVSDesc(streams(tx, order.Desc), -1)))
+		require.NoError(err)
+		expectKeys, expectValues, err := iter.ToArrayKV(iter.WrapKV(nested(streams(tx, order.Desc), order.Desc)))
+		require.NoError(err)
+		require.Equal(expectKeys, keys)
+		require.Equal(expectValues, values)
+	})
+	t.Run("limit", func(t *testing.T) {
+		require := require.New(t)
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		keys, _, err := iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVS(streams(tx, order.Asc), 3)))
+		require.NoError(err)
+		require.Equal([][]byte{{0}, {1}, {1}}, keys)
+	})
+	t.Run("empty", func(t *testing.T) {
+		require := require.New(t)
+		keys, _, err := iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVS(nil, -1)))
+		require.NoError(err)
+		require.Nil(keys)
+		keys, _, err = iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVS([]iter.KVS{nil, iter.EmptyKVS}, -1)))
+		require.NoError(err)
+		require.Nil(keys)
+	})
+	t.Run("wrong order", func(t *testing.T) {
+		tx, _ := db.BeginRo(ctx)
+		defer tx.Rollback()
+		_, _, err := iter.ToArrayKV(iter.WrapKV(iter.NWayMergeKVS(streams(tx, order.Desc), -1)))
+		require.ErrorContains(t, err, "not in ascending order")
+	})
 }
 
 func TestIntersect(t *testing.T) {
 	t.Run("intersect", func(t *testing.T) {
 		s1 := iter.Array[uint64]([]uint64{1, 3, 4, 5, 6, 7})
 		s2 := iter.Array[uint64]([]uint64{2, 3, 7})
-		s3 := iter.Intersect[uint64](s1, s2, -1)
+		s3 := iter.Intersect[uint64](s1, s2, order.Asc, -1)
 		res, err := iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Equal(t, []uint64{3, 7}, res)
 
 		s1 = iter.Array[uint64]([]uint64{1, 3, 4, 5, 6, 7})
 		s2 = iter.Array[uint64]([]uint64{2, 3, 7})
-		s3 = iter.Intersect[uint64](s1, s2, 1)
+		s3 = iter.Intersect[uint64](s1, s2, order.Asc, 1)
 		res, err = iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Equal(t, []uint64{3}, res)
 	})
+	t.Run("desc", func(t *testing.T) {
+		s1 := iter.ReverseArray[uint64]([]uint64{1, 3, 4, 5, 6, 7})
+		s2 := iter.ReverseArray[uint64]([]uint64{2, 3, 7})
+		s3 := iter.Intersect[uint64](s1, s2, order.Desc, -1)
+		res, err := iter.ToArray[uint64](s3)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{7, 3}, res)
+	})
 	t.Run("empty left", func(t *testing.T) {
 		s1 := iter.EmptyU64
 		s2 := iter.Array[uint64]([]uint64{2, 3, 7, 8})
-		s3 := iter.Intersect[uint64](s1, s2, -1)
+		s3 := iter.Intersect[uint64](s1, s2, order.Asc, -1)
 		res, err := iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, res)
 
 		s2 = iter.Array[uint64]([]uint64{2, 3, 7, 8})
-		s3 = iter.Intersect[uint64](nil, s2, -1)
+		s3 = iter.Intersect[uint64](nil, s2, order.Asc, -1)
 		res, err = iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, res)
@@ -176,13 +350,13 @@ func TestIntersect(t *testing.T) {
 	t.Run("empty right", func(t *testing.T) {
 		s1 := iter.Array[uint64]([]uint64{1, 3, 4, 5, 6, 7})
 		s2 := iter.EmptyU64
-		s3 := iter.Intersect[uint64](s1, s2, -1)
+		s3 := iter.Intersect[uint64](s1, s2, order.Asc, -1)
 		res, err := iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, nil, res)
 
 		s1 = iter.Array[uint64]([]uint64{1, 3, 4, 5, 6, 7})
-		s3 = iter.Intersect[uint64](s1, nil, -1)
+		s3 = iter.Intersect[uint64](s1, nil, order.Asc, -1)
 		res, err = iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, res)
@@ -190,12 +364,12 @@ func TestIntersect(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		s1 := iter.EmptyU64
 		s2 := iter.EmptyU64
-		s3 := iter.Intersect[uint64](s1, s2, -1)
+		s3 := iter.Intersect[uint64](s1, s2, order.Asc, -1)
 		res, err := iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, res)
 
-		s3 = iter.Intersect[uint64](nil, nil, -1)
+		s3 = iter.Intersect[uint64](nil, nil, order.Asc, -1)
 		res, err = iter.ToArray[uint64](s3)
 		require.NoError(t, err)
 		require.Nil(t, res)
@@ -281,6 +455,43 @@ func TestPaginated(t *testing.T) {
 	})
 }
 
+func TestPaginatedResume(t *testing.T) {
+	// stateless pages, like remote ones: page token is the offset of the page
+	pages := func(pageToken string) (arr []uint64, nextPageToken string, err error) {
+		from := 0
+		if pageToken != "" {
+			from = int(pageToken[0] - '0')
+		}
+		to := min(from+3, 7)
+		for i := from; i < to; i++ {
+			arr = append(arr, uint64(i+1))
+		}
+		if to < 7 {
+			nextPageToken = string(rune('0' + to))
+		}
+		return arr, nextPageToken, nil
+	}
+	for stop := 0; stop <= 7; stop++ {
+		s1 := iter.Paginate[uint64](pages)
+		var res []uint64
+		for len(res) < stop && s1.HasNext() {
+			v, err := s1.Next()
+			require.NoError(t, err)
+			res = append(res, v)
+		}
+		s2, err := iter.ResumePaginate[uint64](pages, s1.ResumeToken())
+		require.NoError(t, err)
+		rest, err := iter.ToArray[uint64](s2)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7}, append(res, rest...), stop)
+	}
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := iter.ResumePaginateKV(nil, "!")
+		require.Error(t, err)
+	})
+}
+
 func TestPaginatedDual(t *testing.T) {
 	t.Run("paginated", func(t *testing.T) {
 		i := 0
@@ -414,3 +625,312 @@ func TestFiler(t *testing.T) {
 		require.Nil(t, res)
 	})
 }
+
+type closeCounterKV struct {
+	iter.KV
+	closed int
+}
+
+func (c *closeCounterKV) Close() { c.closed++ }
+
+func TestLimitSkip(t *testing.T) {
+	createKVIter := func() iter.KV {
+		return iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+			if pageToken == "" {
+				return [][]byte{{1}, {2}}, [][]byte{{1}, {2}}, "test", nil
+			}
+			return [][]byte{{3}}, [][]byte{{3}}, "", nil
+		})
+	}
+	t.Run("dual", func(t *testing.T) {
+		keys, _, err := iter.ToArrayKV(iter.LimitKV(createKVIter(), 2))
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{{1}, {2}}, keys)
+
+		keys, _, err = iter.ToArrayKV(iter.LimitKV(createKVIter(), 0))
+		require.NoError(t, err)
+		require.Nil(t, keys)
+
+		keys, _, err = iter.ToArrayKV(iter.SkipKV(createKVIter(), 2))
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{{3}}, keys)
+
+		keys, _, err = iter.ToArrayKV(iter.SkipKV(createKVIter(), 10))
+		require.NoError(t, err)
+		require.Nil(t, keys)
+
+		// offset/limit pagination
+		keys, values, err := iter.ToArrayKV(iter.LimitKV(iter.SkipKV(createKVIter(), 1), 1))
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{{2}}, keys)
+		require.Equal(t, [][]byte{{2}}, values)
+	})
+	t.Run("unary", func(t *testing.T) {
+		res, err := iter.ToArrayU64(iter.LimitU64(iter.Array[uint64]([]uint64{1, 2, 3}), 2))
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2}, res)
+
+		res, err = iter.ToArrayU64(iter.SkipU64(iter.Array[uint64]([]uint64{1, 2, 3}), 1))
+		require.NoError(t, err)
+		require.Equal(t, []uint64{2, 3}, res)
+
+		res, err = iter.ToArrayU64(iter.SkipU64(iter.EmptyU64, 1))
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+	t.Run("error", func(t *testing.T) {
+		_, _, err := iter.ToArrayKV(iter.SkipKV(iter.PairsWithError(2), 5))
+		require.Error(t, err)
+
+		keys, _, err := iter.ToArrayKV(iter.LimitKV(iter.PairsWithError(2), 2))
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+	})
+	t.Run("close", func(t *testing.T) {
+		src := &closeCounterKV{KV: createKVIter()}
+		iter.LimitKV(src, 1).Close()
+		iter.SkipKV(src, 1).Close()
+		iter.WindowKV(src, nil, nil).Close()
+		require.Equal(t, 3, src.closed)
+	})
+}
+
+func TestWindowKV(t *testing.T) {
+	createKVIter := func() iter.KV {
+		return iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+			if pageToken == "" {
+				return [][]byte{{1}, {2}, {3}}, [][]byte{{1}, {2}, {3}}, "test", nil
+			}
+			return [][]byte{{4}, {5}}, [][]byte{{4}, {5}}, "", nil
+		})
+	}
+	keys, values, err := iter.ToArrayKV(iter.WindowKV(createKVIter(), []byte{2}, []byte{4}))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{2}, {3}}, keys)
+	require.Equal(t, [][]byte{{2}, {3}}, values)
+
+	keys, _, err = iter.ToArrayKV(iter.WindowKV(createKVIter(), nil, []byte{2}))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1}}, keys)
+
+	keys, _, err = iter.ToArrayKV(iter.WindowKV(createKVIter(), []byte{4}, nil))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{4}, {5}}, keys)
+
+	keys, _, err = iter.ToArrayKV(iter.WindowKV(createKVIter(), []byte{6}, nil))
+	require.NoError(t, err)
+	require.Nil(t, keys)
+
+	// second page must not be requested after window end
+	pages := 0
+	it := iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+		pages++
+		return [][]byte{{1}, {2}}, [][]byte{{1}, {2}}, "test", nil
+	})
+	keys, _, err = iter.ToArrayKV(iter.WindowKV(it, nil, []byte{2}))
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1}}, keys)
+	require.Equal(t, 1, pages)
+
+	_, _, err = iter.ToArrayKV(iter.WindowKV(iter.PairsWithError(2), nil, nil))
+	require.Error(t, err)
+}
+
+func TestSplitRange(t *testing.T) {
+	keys := func(ks ...byte) (res [][]byte) {
+		for _, k := range ks {
+			res = append(res, []byte{k})
+		}
+		return res
+	}
+	require.Equal(t, []iter.KeyRange{{From: nil, To: nil}}, iter.SplitRange(nil, nil, 4, nil))
+	require.Equal(t, []iter.KeyRange{{From: []byte{1}, To: []byte{9}}}, iter.SplitRange([]byte{1}, []byte{9}, 1, keys(2, 3, 4)))
+
+	// samples in any order, same number of samples per range
+	require.Equal(t, []iter.KeyRange{
+		{From: nil, To: []byte{3}},
+		{From: []byte{3}, To: []byte{5}},
+		{From: []byte{5}, To: []byte{7}},
+		{From: []byte{7}, To: nil},
+	}, iter.SplitRange(nil, nil, 4, keys(8, 1, 2, 3, 4, 5, 6, 7)))
+
+	// samples out of range are ignored, duplicated boundaries are merged
+	require.Equal(t, []iter.KeyRange{
+		{From: []byte{2}, To: []byte{5}},
+		{From: []byte{5}, To: []byte{6}},
+	}, iter.SplitRange([]byte{2}, []byte{6}, 4, keys(0, 1, 2, 2, 2, 5, 7, 9)))
+
+	// less samples than ranges
+	require.Equal(t, []iter.KeyRange{
+		{From: nil, To: []byte{5}},
+		{From: []byte{5}, To: nil},
+	}, iter.SplitRange(nil, nil, 8, keys(5)))
+}
+
+func TestPartitionKV(t *testing.T) {
+	all := [][]byte{{1}, {2}, {3}, {4}, {5}, {6}}
+	open := func(from, to []byte) (iter.KV, error) {
+		// ranges are enforced even if the source ignores them
+		return iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+			return all, all, "", nil
+		}), nil
+	}
+	its, err := iter.PartitionKV([]byte{2}, nil, 3, [][]byte{{3}, {4}, {5}, {6}}, open)
+	require.NoError(t, err)
+	require.Len(t, its, 3)
+	var got [][]byte
+	for _, it := range its {
+		keys, _, err := iter.ToArrayKV(it)
+		require.NoError(t, err)
+		require.NotEmpty(t, keys)
+		got = append(got, keys...)
+	}
+	require.Equal(t, all[1:], got)
+
+	opened := 0
+	src := &closeCounterKV{KV: iter.EmptyKV}
+	_, err = iter.PartitionKV(nil, nil, 3, [][]byte{{3}, {4}, {5}}, func(from, to []byte) (iter.KV, error) {
+		if opened++; opened == 3 {
+			return nil, errors.New("open")
+		}
+		return src, nil
+	})
+	require.Error(t, err)
+	require.Equal(t, 2, src.closed)
+}
+
+func TestWrapErrKV(t *testing.T) {
+	keys, _, err := iter.ToArrayKV(iter.WrapErrKV(iter.PairsWithError(2), iter.ErrWithKey("src")))
+	require.ErrorContains(t, err, "src: after key 32: expected error at iteration: 2")
+	require.Equal(t, [][]byte{[]byte("1"), []byte("2")}, keys)
+
+	_, _, err = iter.ToArrayKV(iter.WrapErrKV(iter.PairsWithError(0), iter.ErrWithKey("src")))
+	require.ErrorContains(t, err, "src: before first key")
+
+	// wrapping layers of iterators keeps failing key of each layer
+	testErr := fmt.Errorf("test")
+	failing := iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+		if pageToken == "" {
+			return [][]byte{{1}}, [][]byte{{1}}, "test", nil
+		}
+		return nil, nil, "", testErr
+	})
+	it := iter.UnionKV(iter.WrapErrKV(failing, iter.ErrWithKey("x")), iter.EmptyKV, -1)
+	_, _, err = iter.ToArrayKV(iter.WrapErrKV(it, iter.ErrWithKey("union")))
+	require.ErrorIs(t, err, testErr)
+	require.ErrorContains(t, err, "union: after key 01: x: after key 01: test")
+
+	src := &closeCounterKV{KV: iter.EmptyKV}
+	iter.WrapErrKV(src, iter.ErrWithKey("src")).Close()
+	require.Equal(t, 1, src.closed)
+}
+
+func TestZipKV(t *testing.T) {
+	toArr := func(it *iter.ZippedKV) (keys, xValues, yValues [][]byte, err error) {
+		for it.HasNext() {
+			k, v1, v2, err := it.Next()
+			if err != nil {
+				return keys, xValues, yValues, err
+			}
+			keys, xValues, yValues = append(keys, k), append(xValues, v1), append(yValues, v2)
+		}
+		return keys, xValues, yValues, nil
+	}
+	pairs := func(keys, values [][]byte) iter.KV {
+		return iter.PaginateKV(func(pageToken string) ([][]byte, [][]byte, string, error) { return keys, values, "", nil })
+	}
+	t.Run("arrays", func(t *testing.T) {
+		x := iter.PairsWithError(10)
+		y := pairs([][]byte{[]byte("0"), []byte("2"), []byte("5"), []byte("a")}, [][]byte{{0}, {2}, {5}, {10}})
+		keys, xValues, yValues, err := toArr(iter.ZipKV(iter.LimitKV(x, 9), y))
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("2"), []byte("5")}, keys)
+		require.Equal(t, [][]byte{[]byte("2"), []byte("5")}, xValues)
+		require.Equal(t, [][]byte{{2}, {5}}, yValues)
+	})
+	t.Run("empty", func(t *testing.T) {
+		keys, _, _, err := toArr(iter.ZipKV(iter.EmptyKV, iter.PairsWithError(10)))
+		require.NoError(t, err)
+		require.Nil(t, keys)
+		keys, _, _, err = toArr(iter.ZipKV(iter.PairsWithError(10), iter.EmptyKV))
+		require.NoError(t, err)
+		require.Nil(t, keys)
+	})
+	t.Run("error", func(t *testing.T) {
+		y := pairs([][]byte{[]byte("1"), []byte("3"), []byte("5")}, [][]byte{{1}, {3}, {5}})
+		keys, _, _, err := toArr(iter.ZipKV(iter.PairsWithError(4), y))
+		require.Error(t, err)
+		require.Equal(t, [][]byte{[]byte("1"), []byte("3")}, keys)
+	})
+	t.Run("close", func(t *testing.T) {
+		x, y := &closeCounterKV{KV: iter.EmptyKV}, &closeCounterKV{KV: iter.EmptyKV}
+		iter.ZipKV(x, y).Close()
+		require.Equal(t, 1, x.closed)
+		require.Equal(t, 1, y.closed)
+	})
+}
+
+func TestBatchKV(t *testing.T) {
+	t.Run("batches", func(t *testing.T) {
+		keys := [][]byte{{1}, {2, 2}, {3}, {4}, {5}}
+		values := [][]byte{{10}, {}, {30, 30}, {40}, {50}}
+		it := iter.BatchKV(iter.PaginateKV(func(pageToken string) ([][]byte, [][]byte, string, error) {
+			return keys, values, "", nil
+		}), 2)
+		var gotKeys, gotValues [][]byte
+		var sizes []int
+		for it.HasNext() {
+			k, v, err := it.Next()
+			require.NoError(t, err)
+			sizes = append(sizes, len(k))
+			for i := range k {
+				gotKeys = append(gotKeys, bytes.Clone(k[i]))
+				gotValues = append(gotValues, bytes.Clone(v[i]))
+			}
+		}
+		require.Equal(t, []int{2, 2, 1}, sizes)
+		require.Equal(t, keys, gotKeys)
+		require.Equal(t, values, gotValues)
+	})
+	t.Run("error", func(t *testing.T) {
+		it := iter.BatchKV(iter.PairsWithError(3), 2)
+		k, _, err := it.Next()
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("1"), []byte("2")}, k)
+
+		// pairs read before error are returned first
+		require.True(t, it.HasNext())
+		k, _, err = it.Next()
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("3")}, k)
+		_, _, err = it.Next()
+		require.ErrorContains(t, err, "expected error at iteration: 3")
+	})
+	t.Run("close", func(t *testing.T) {
+		src := &closeCounterKV{KV: iter.EmptyKV}
+		it := iter.BatchKV(src, 10)
+		require.False(t, it.HasNext())
+		it.Close()
+		require.Equal(t, 1, src.closed)
+	})
+}
+
+func TestInstrumentKV(t *testing.T) {
+	label := "test_instrument_kv"
+	it := iter.InstrumentKV(label, iter.PairsWithError(3))
+	for i := 0; i < 3; i++ {
+		_, _, err := it.Next()
+		require.NoError(t, err)
+	}
+	_, _, err := it.Next()
+	require.Error(t, err)
+
+	// streams with same label share metrics
+	it = iter.InstrumentKV(label, iter.PairsWithError(1))
+	_, _, err = it.Next()
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(4), metrics.GetOrCreateCounter(`iter_pairs_total{name="`+label+`"}`).GetValueUint64())
+	require.Equal(t, uint64(8), metrics.GetOrCreateCounter(`iter_bytes_total{name="`+label+`"}`).GetValueUint64())
+	require.Equal(t, uint64(1), metrics.GetOrCreateCounter(`iter_errors_total{name="`+label+`"}`).GetValueUint64())
+}
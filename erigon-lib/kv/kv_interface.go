@@ -545,6 +545,9 @@ type TemporalTx interface {
 	Tx
 	TemporalGetter
 	DomainGetAsOf(name Domain, k, k2 []byte, ts uint64) (v []byte, ok bool, err error)
+	// DomainGetAsOfBatch - DomainGetAsOf of many (domain, key, ts) lookups in one call. Results are written into reqs.
+	// Cheaper than serial DomainGetAsOf calls: implementations may reorder lookups for locality.
+	DomainGetAsOfBatch(reqs []DomainGetAsOfReq) error
 	HistorySeek(name History, k []byte, ts uint64) (v []byte, ok bool, err error)
 
 	// IndexRange - return iterator over range of inverted index for given key `k`
@@ -561,6 +564,27 @@ type TemporalTx interface {
 	//   no duplicates
 	HistoryRange(name History, fromTs, toTs int, asc order.By, limit int) (it iter.KV, err error)
 }
+
+// DomainGetAsOfReq - one lookup of TemporalTx.DomainGetAsOfBatch
+type DomainGetAsOfReq struct {
+	Domain Domain
+	Key    []byte // full key (k+k2)
+	Ts     uint64
+
+	V  []byte // result
+	Ok bool   // result
+}
+
+// ResumableRangeTx - tx of remote db: its range streams implement iter.Resumable and can be re-created from the token,
+// e.g. within a new transaction after the connection was dropped, without rescanning from the beginning. A new
+// transaction may see newer data than the one which produced the token.
+type ResumableRangeTx interface {
+	RangeResume(table string, fromPrefix, toPrefix []byte, asc order.By, limit int, token string) (iter.KV, error)
+	IndexRangeResume(name InvertedIdx, k []byte, fromTs, toTs int, asc order.By, limit int, token string) (timestamps iter.U64, err error)
+	HistoryRangeResume(name History, fromTs, toTs int, asc order.By, limit int, token string) (it iter.KV, err error)
+	DomainRangeResume(name Domain, fromKey, toKey []byte, ts uint64, asc order.By, limit int, token string) (it iter.KV, err error)
+}
+
 type TemporalCommitment interface {
 	ComputeCommitment(ctx context.Context, saveStateAfter, trace bool) (rootHash []byte, err error)
 }
@@ -0,0 +1,233 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package kvttl - expiring tables on top of kv.RwDB: every key has a deadline after which it's invisible to readers
+// and eventually deleted by GC. Useful for caches which must survive restarts (seen messages, transient mappings, ...).
+//
+// Table layout:
+//   - name: key -> deadline_u64 + value
+//   - name + "Deadlines": deadline_u64 + key -> nil, GC walks it from the beginning until first not-expired deadline
+//
+// Deadlines are stored as unix milliseconds. Both tables must be registered in the DB's TableCfg, see Tables.
+package kvttl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const deadlineSize = 8
+
+// DefaultGCBatch - how many expired keys are deleted by one GC write transaction
+const DefaultGCBatch = 10_000
+
+type Table struct {
+	name      string
+	deadlines string
+
+	now func() time.Time
+}
+
+func New(name string) *Table {
+	return &Table{name: name, deadlines: name + "Deadlines", now: time.Now}
+}
+
+// Tables - tables which must be created in DB to use given expiring tables
+func Tables(tables ...*Table) []string {
+	res := make([]string, 0, len(tables)*2)
+	for _, t := range tables {
+		res = append(res, t.name, t.deadlines)
+	}
+	return res
+}
+
+// TablesCfg - adds tables of given expiring tables to cfg, can be used in mdbx.WithTableCfg
+func TablesCfg(cfg kv.TableCfg, tables ...*Table) kv.TableCfg {
+	res := make(kv.TableCfg, len(cfg)+len(tables)*2)
+	for name, item := range cfg {
+		res[name] = item
+	}
+	for _, name := range Tables(tables...) {
+		if _, ok := res[name]; !ok {
+			res[name] = kv.TableCfgItem{}
+		}
+	}
+	return res
+}
+
+func (t *Table) Name() string { return t.name }
+
+func (t *Table) expired(deadline uint64) bool {
+	return deadline <= uint64(t.now().UnixMilli())
+}
+
+// Put - stores value until deadline, overwrites value and deadline of existing key
+func (t *Table) Put(tx kv.RwTx, k, v []byte, deadline time.Time) error {
+	if err := t.Delete(tx, k); err != nil {
+		return err
+	}
+	d := uint64(deadline.UnixMilli())
+	val := make([]byte, deadlineSize+len(v))
+	binary.BigEndian.PutUint64(val, d)
+	copy(val[deadlineSize:], v)
+	if err := tx.Put(t.name, k, val); err != nil {
+		return err
+	}
+	return tx.Put(t.deadlines, deadlineKey(d, k), nil)
+}
+
+// PutTTL - stores value for ttl from now
+func (t *Table) PutTTL(tx kv.RwTx, k, v []byte, ttl time.Duration) error {
+	return t.Put(tx, k, v, t.now().Add(ttl))
+}
+
+// Get - returns value and its deadline, expired keys are not visible even if GC didn't delete them yet
+func (t *Table) Get(tx kv.Tx, k []byte) (v []byte, deadline time.Time, ok bool, err error) {
+	val, err := tx.GetOne(t.name, k)
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	if val == nil {
+		return nil, time.Time{}, false, nil
+	}
+	if len(val) < deadlineSize {
+		return nil, time.Time{}, false, fmt.Errorf("kvttl: %s: value of key %x is too short: %d", t.name, k, len(val))
+	}
+	d := binary.BigEndian.Uint64(val)
+	if t.expired(d) {
+		return nil, time.Time{}, false, nil
+	}
+	return val[deadlineSize:], time.UnixMilli(int64(d)), true, nil
+}
+
+func (t *Table) Has(tx kv.Tx, k []byte) (bool, error) {
+	_, _, ok, err := t.Get(tx, k)
+	return ok, err
+}
+
+func (t *Table) Delete(tx kv.RwTx, k []byte) error {
+	val, err := tx.GetOne(t.name, k)
+	if err != nil {
+		return err
+	}
+	if val == nil {
+		return nil
+	}
+	if len(val) >= deadlineSize {
+		if err := tx.Delete(t.deadlines, deadlineKey(binary.BigEndian.Uint64(val), k)); err != nil {
+			return err
+		}
+	}
+	return tx.Delete(t.name, k)
+}
+
+// ForEach - walks over not-expired keys in key order
+func (t *Table) ForEach(tx kv.Tx, walker func(k, v []byte, deadline time.Time) error) error {
+	return tx.ForEach(t.name, nil, func(k, val []byte) error {
+		if len(val) < deadlineSize {
+			return fmt.Errorf("kvttl: %s: value of key %x is too short: %d", t.name, k, len(val))
+		}
+		d := binary.BigEndian.Uint64(val)
+		if t.expired(d) {
+			return nil
+		}
+		return walker(k, val[deadlineSize:], time.UnixMilli(int64(d)))
+	})
+}
+
+// GC - deletes at most limit expired keys, returns amount of deleted keys. limit <= 0 means no limit
+func (t *Table) GC(ctx context.Context, tx kv.RwTx, limit int) (deleted int, err error) {
+	c, err := tx.RwCursor(t.deadlines)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	for k, _, err := c.First(); k != nil; k, _, err = c.First() {
+		if err != nil {
+			return deleted, err
+		}
+		if limit > 0 && deleted >= limit {
+			return deleted, nil
+		}
+		if !t.expired(binary.BigEndian.Uint64(k)) {
+			return deleted, nil
+		}
+		if err := tx.Delete(t.name, k[deadlineSize:]); err != nil {
+			return deleted, err
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return deleted, err
+		}
+		deleted++
+
+		select {
+		case <-ctx.Done():
+			return deleted, ctx.Err()
+		default:
+		}
+	}
+	return deleted, nil
+}
+
+// Run - background GC: every interval deletes expired keys of given tables by batches of DefaultGCBatch,
+// each batch in own write transaction to not block other writers for long. Blocks until ctx is done
+func Run(ctx context.Context, db kv.RwDB, interval time.Duration, logger log.Logger, tables ...*Table) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, t := range tables {
+			if err := t.gcAll(ctx, db); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logger.Warn("[kvttl] gc failed", "table", t.name, "err", err)
+			}
+		}
+	}
+}
+
+func (t *Table) gcAll(ctx context.Context, db kv.RwDB) error {
+	for {
+		var deleted int
+		if err := db.Update(ctx, func(tx kv.RwTx) (err error) {
+			deleted, err = t.GC(ctx, tx, DefaultGCBatch)
+			return err
+		}); err != nil {
+			return err
+		}
+		if deleted < DefaultGCBatch {
+			return nil
+		}
+	}
+}
+
+func deadlineKey(deadline uint64, k []byte) []byte {
+	key := make([]byte, deadlineSize+len(k))
+	binary.BigEndian.PutUint64(key, deadline)
+	copy(key[deadlineSize:], k)
+	return key
+}
@@ -0,0 +1,125 @@
+package kvttl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+)
+
+func newTestDB(t *testing.T, tables ...*Table) kv.RwDB {
+	t.Helper()
+	db := mdbx.NewMDBX(log.New()).InMem(t.TempDir()).WithTableCfg(func(_ kv.TableCfg) kv.TableCfg {
+		return TablesCfg(kv.TableCfg{}, tables...)
+	}).MustOpen()
+	t.Cleanup(db.Close)
+	return db
+}
+
+func count(tx kv.Tx, table string) (uint64, error) {
+	c, err := tx.Cursor(table)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	return c.Count()
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	table := New("Seen")
+	db := newTestDB(t, table)
+
+	now := time.UnixMilli(1_000_000)
+	table.now = func() time.Time { return now }
+
+	tx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	require.NoError(t, table.PutTTL(tx, []byte("a"), []byte("1"), time.Second))
+	require.NoError(t, table.PutTTL(tx, []byte("b"), []byte("2"), 2*time.Second))
+	require.NoError(t, table.PutTTL(tx, []byte("c"), []byte("3"), 3*time.Second))
+
+	v, deadline, ok, err := table.Get(tx, []byte("a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), v)
+	require.Equal(t, now.Add(time.Second), deadline)
+
+	// overwrite moves deadline
+	require.NoError(t, table.PutTTL(tx, []byte("c"), []byte("33"), time.Second))
+
+	now = now.Add(time.Second)
+
+	// expired keys are invisible before GC
+	ok, err = table.Has(tx, []byte("a"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	var keys []string
+	require.NoError(t, table.ForEach(tx, func(k, v []byte, _ time.Time) error {
+		keys = append(keys, string(k))
+		return nil
+	}))
+	require.Equal(t, []string{"b"}, keys)
+
+	deleted, err := table.GC(ctx, tx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	deleted, err = table.GC(ctx, tx, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	cnt, err := count(tx, table.name)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), cnt)
+	cnt, err = count(tx, table.deadlines)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), cnt)
+
+	require.NoError(t, table.Delete(tx, []byte("b")))
+	cnt, err = count(tx, table.deadlines)
+	require.NoError(t, err)
+	require.Zero(t, cnt)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	table := New("Seen")
+	db := newTestDB(t, table)
+
+	require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := 0; i < DefaultGCBatch+10; i++ {
+			if err := table.Put(tx, []byte{byte(i >> 8), byte(i)}, nil, time.Now().Add(-time.Second)); err != nil {
+				return err
+			}
+		}
+		return table.PutTTL(tx, []byte("alive"), nil, time.Hour)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, db, 10*time.Millisecond, log.New(), table)
+	}()
+
+	require.Eventually(t, func() bool {
+		var cnt uint64
+		require.NoError(t, db.View(context.Background(), func(tx kv.Tx) (err error) {
+			cnt, err = count(tx, table.name)
+			return err
+		}))
+		return cnt == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
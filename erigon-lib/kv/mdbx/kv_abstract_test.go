@@ -17,7 +17,9 @@
 package mdbx_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"net"
 	"runtime"
@@ -26,14 +28,18 @@ import (
 	"github.com/ledgerwatch/log/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/remotedb"
 	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
 )
@@ -333,6 +339,98 @@ func TestRemoteKvRange(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestRemoteKvRangePagination(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fix me on win please")
+	}
+	logger := log.New()
+	ctx, writeDB := context.Background(), memdb.NewTestDB(t)
+	grpcServer, conn := grpc.NewServer(), bufconn.Listen(1024*1024)
+	go func() {
+		kvServer := remotedbserver.NewKvServer(ctx, writeDB, nil, nil, nil, logger)
+		remote.RegisterKVServer(grpcServer, kvServer)
+		if err := grpcServer.Serve(conn); err != nil {
+			log.Error("private RPC server fail", "err", err)
+		}
+	}()
+
+	cc, err := grpc.Dial("", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, url string) (net.Conn, error) { return conn.Dial() }))
+	require.NoError(t, err)
+	db, err := remotedb.NewRemote(gointerfaces.VersionFromProto(remotedbserver.KvServiceAPIVersion), logger, remote.NewKVClient(cc)).Open()
+	require.NoError(t, err)
+
+	// range spans multiple pages, dups of the last key of the first page must not be split or repeated
+	require := require.New(t)
+	keysAmount := 2*remotedbserver.PageSizeLimit + 10
+	require.NoError(writeDB.Update(ctx, func(tx kv.RwTx) error {
+		wc, err := tx.RwCursorDupSort(kv.PlainState)
+		require.NoError(err)
+		for i := 0; i < keysAmount; i++ {
+			k := binary.BigEndian.AppendUint64(nil, uint64(i))
+			require.NoError(wc.Append(k, []byte{1}))
+			if i == remotedbserver.PageSizeLimit-1 {
+				require.NoError(wc.AppendDup(k, []byte{2}))
+				require.NoError(wc.AppendDup(k, []byte{3}))
+			}
+		}
+		return nil
+	}))
+	pairsAmount := keysAmount + 2
+
+	require.NoError(db.View(ctx, func(tx kv.Tx) error {
+		it, err := tx.Range(kv.PlainState, nil, nil)
+		require.NoError(err)
+		var prevK, prevV []byte
+		cnt := 0
+		for it.HasNext() {
+			k, v, err := it.Next()
+			require.NoError(err)
+			if cnt > 0 {
+				cmpK := bytes.Compare(prevK, k)
+				require.True(cmpK < 0 || (cmpK == 0 && bytes.Compare(prevV, v) < 0), "pairs must be unique and ordered: %x %x", k, v)
+			}
+			prevK, prevV = common.Copy(k), common.Copy(v)
+			cnt++
+		}
+		require.Equal(pairsAmount, cnt)
+
+		cntRange := func(it iter.KV, err error) (i int) {
+			require.NoError(err)
+			for it.HasNext() {
+				_, _, err := it.Next()
+				require.NoError(err)
+				i++
+			}
+			return i
+		}
+		require.Equal(remotedbserver.PageSizeLimit+5, cntRange(tx.RangeAscend(kv.PlainState, nil, nil, remotedbserver.PageSizeLimit+5)))
+		require.Equal(pairsAmount, cntRange(tx.RangeDescend(kv.PlainState, nil, nil, -1)))
+
+		// concurrent ranges over the same tx take turns per page
+		g := errgroup.Group{}
+		for i := 0; i < 4; i++ {
+			g.Go(func() error {
+				it, err := tx.Range(kv.PlainState, nil, nil)
+				if err != nil {
+					return err
+				}
+				cnt := 0
+				for it.HasNext() {
+					if _, _, err := it.Next(); err != nil {
+						return err
+					}
+					cnt++
+				}
+				if cnt != pairsAmount {
+					return fmt.Errorf("unexpected amount of pairs: %d", cnt)
+				}
+				return nil
+			})
+		}
+		return g.Wait()
+	}))
+}
+
 func setupDatabases(t *testing.T, logger log.Logger, f mdbx.TableCfgFunc) (writeDBs []kv.RwDB, readDBs []kv.RwDB) {
 	t.Helper()
 	ctx := context.Background()
@@ -634,3 +732,69 @@ func testMultiCursor(t *testing.T, db kv.RwDB, bucket1, bucket2 string) {
 //		})
 //	}
 //}
+
+func TestRemoteKvRangeFiltered(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fix me on win please")
+	}
+	logger := log.New()
+	ctx, writeDB := context.Background(), memdb.NewTestDB(t)
+	grpcServer, conn := grpc.NewServer(), bufconn.Listen(1024*1024)
+	go func() {
+		kvServer := remotedbserver.NewKvServer(ctx, writeDB, nil, nil, nil, logger)
+		remote.RegisterKVServer(grpcServer, kvServer)
+		if err := grpcServer.Serve(conn); err != nil {
+			log.Error("private RPC server fail", "err", err)
+		}
+	}()
+
+	cc, err := grpc.Dial("", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, url string) (net.Conn, error) { return conn.Dial() }))
+	require.NoError(t, err)
+	db, err := remotedb.NewRemote(gointerfaces.VersionFromProto(remotedbserver.KvServiceAPIVersion), logger, remote.NewKVClient(cc)).Open()
+	require.NoError(t, err)
+
+	// matching keys are spread over multiple pages of the unfiltered range
+	require := require.New(t)
+	keysAmount := 2*remotedbserver.PageSizeLimit + 10
+	require.NoError(writeDB.Update(ctx, func(tx kv.RwTx) error {
+		for i := 0; i < keysAmount; i++ {
+			require.NoError(tx.Put(kv.HeaderNumber, binary.BigEndian.AppendUint64(nil, uint64(i)), []byte{1, 2, 3, 4}))
+		}
+		return nil
+	}))
+
+	rangeFiltered := func(tx kv.Tx, asc order.By, limit int, filter kv.RangeFilter) (keys, values [][]byte) {
+		it, err := kv.RangeFiltered(tx, kv.HeaderNumber, nil, nil, asc, limit, filter)
+		require.NoError(err)
+		keys, values, err = iter.ToArrayKV(it)
+		require.NoError(err)
+		return keys, values
+	}
+	suffix := kv.RangeFilter{KeySuffix: []byte{7}, KeysOnly: true}
+	prefix := kv.RangeFilter{KeyPrefix: []byte{0, 0, 0, 0, 0, 0, 0x40}, ValueMaxLen: 2}
+
+	require.NoError(db.View(ctx, func(tx kv.Tx) error {
+		keys, values := rangeFiltered(tx, order.Asc, -1, suffix)
+		require.Len(keys, keysAmount/256+1)
+		for i, k := range keys {
+			require.Equal(byte(7), k[7])
+			require.Nil(values[i])
+		}
+		descKeys, _ := rangeFiltered(tx, order.Desc, 3, suffix)
+		require.Equal([][]byte{keys[len(keys)-1], keys[len(keys)-2], keys[len(keys)-3]}, descKeys)
+
+		keys, values = rangeFiltered(tx, order.Asc, -1, prefix)
+		require.Len(keys, 256)
+		require.Equal([]byte{1, 2}, values[0])
+
+		// local transactions filter on the client side, with the same results
+		return writeDB.View(ctx, func(localTx kv.Tx) error {
+			localKeys, localValues := rangeFiltered(localTx, order.Asc, -1, prefix)
+			require.Equal(keys, localKeys)
+			require.Equal(values, localValues)
+			localKeys, _ = rangeFiltered(localTx, order.Desc, 3, suffix)
+			require.Equal(descKeys, localKeys)
+			return nil
+		})
+	}))
+}
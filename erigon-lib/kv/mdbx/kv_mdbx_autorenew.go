@@ -0,0 +1,479 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mdbx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+)
+
+const (
+	DefaultAutoRenewPageSize   = 1_024
+	DefaultAutoRenewRenewAfter = 5 * time.Second
+	DefaultAutoRenewPoolSize   = 16
+)
+
+// AutoRenewRoDB - opt-in kv.RoDB wrapper for long-running read-only scans (for example RPC range queries).
+//
+// Long-living read transaction pins MDBX pages which were freed after it started - and DB file grows.
+// Transactions produced by this wrapper don't pin one snapshot for whole scan:
+//   - every page of Range/Prefix/ForEach stream is read in own short-living read transaction,
+//     next page continues from the last returned key
+//   - snapshot used for point-reads (GetOne, Has, ...) is opened lazily, released before every page of a stream
+//     and renewed when it's older than `renewAfter`,
+//     but only until first Cursor is opened (cursor is bound to snapshot - then snapshot is kept until Rollback)
+//
+// Short-living read transactions are taken from a pool of reset MDBX read transactions (see readerPool).
+// AutoRenewRoDB.Close must be used instead of closing the wrapped db: parked transactions are closed there.
+//
+// Trade-off: different pages and reads may observe different snapshots. Don't use it where consistency across reads is required.
+// DupSort tables are not paginated (resuming from key is not enough for them) - they are read from current snapshot.
+type AutoRenewRoDB struct {
+	kv.RoDB
+	pageSize   int
+	renewAfter time.Duration
+	pool       *readerPool
+}
+
+func NewAutoRenewRoDB(db kv.RoDB, pageSize int, renewAfter time.Duration, poolSize int) *AutoRenewRoDB {
+	if pageSize <= 0 {
+		pageSize = DefaultAutoRenewPageSize
+	}
+	if renewAfter <= 0 {
+		renewAfter = DefaultAutoRenewRenewAfter
+	}
+	if poolSize <= 0 {
+		poolSize = DefaultAutoRenewPoolSize
+	}
+	return &AutoRenewRoDB{RoDB: db, pageSize: pageSize, renewAfter: renewAfter, pool: newReaderPool(db, poolSize)}
+}
+
+func (db *AutoRenewRoDB) BeginRo(ctx context.Context) (kv.Tx, error) {
+	return &autoRenewTx{db: db, ctx: ctx}, nil
+}
+
+func (db *AutoRenewRoDB) View(ctx context.Context, f func(tx kv.Tx) error) error {
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return f(tx)
+}
+
+// Close - closes parked read transactions and the wrapped db
+func (db *AutoRenewRoDB) Close() {
+	db.pool.close()
+	db.RoDB.Close()
+}
+
+// readerPool - hands out read transactions of short-living snapshots.
+// Returned MDBX transactions are parked reset (mdbx_txn_reset: snapshot is released, reader slot is kept)
+// and renewed (mdbx_txn_renew) by next get - pages and point-reads don't register a new reader every time.
+// Parked transactions hold a slot of roTxsLimiter. Transactions of other kv.RoDB implementations are not pooled.
+type readerPool struct {
+	db     kv.RoDB
+	size   int
+	inUse  atomic.Int64 // snapshots handed out and not returned yet
+	mu     sync.Mutex
+	idle   []*MdbxTx
+	closed bool
+}
+
+func newReaderPool(db kv.RoDB, size int) *readerPool {
+	return &readerPool{db: db, size: size}
+}
+
+func (p *readerPool) get(ctx context.Context) (kv.Tx, error) {
+	for {
+		p.mu.Lock()
+		n := len(p.idle)
+		if n == 0 {
+			p.mu.Unlock()
+			break
+		}
+		tx := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		if err := tx.tx.Renew(); err != nil {
+			tx.Rollback()
+			continue
+		}
+		tx.ctx = ctx
+		p.inUse.Add(1)
+		return tx, nil
+	}
+	tx, err := p.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.inUse.Add(1)
+	return tx, nil
+}
+
+// put - releases snapshot of `tx` and parks it if pool has space
+func (p *readerPool) put(tx kv.Tx) {
+	p.inUse.Add(-1)
+	mdbxTx, ok := tx.(*MdbxTx)
+	if !ok {
+		tx.Rollback()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || len(p.idle) >= p.size {
+		mdbxTx.Rollback()
+		return
+	}
+	mdbxTx.closeCursors()
+	mdbxTx.tx.Reset()
+	p.idle = append(p.idle, mdbxTx)
+}
+
+func (p *readerPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, tx := range p.idle {
+		tx.Rollback()
+	}
+	p.idle = nil
+}
+
+var _ kv.Tx = (*autoRenewTx)(nil)
+
+type autoRenewTx struct {
+	db     *AutoRenewRoDB
+	ctx    context.Context
+	tx     kv.Tx // snapshot for point-reads, nil until first read
+	born   time.Time
+	pinned bool // cursor was opened on current snapshot - it can't be renewed anymore
+	closed bool
+}
+
+// current - returns snapshot for point-reads, opens it on first use and renews it if it's too old
+func (tx *autoRenewTx) current() (kv.Tx, error) {
+	if tx.closed {
+		return nil, fmt.Errorf("autoRenewTx: tx closed")
+	}
+	if tx.tx != nil && (tx.pinned || time.Since(tx.born) < tx.db.renewAfter) {
+		return tx.tx, nil
+	}
+	tx.release()
+	newTx, err := tx.db.pool.get(tx.ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx.tx, tx.born = newTx, time.Now()
+	return tx.tx, nil
+}
+
+// release - returns snapshot of point-reads to the pool, unless a cursor is bound to it
+func (tx *autoRenewTx) release() {
+	if tx.tx == nil || tx.pinned {
+		return
+	}
+	tx.db.pool.put(tx.tx)
+	tx.tx = nil
+}
+
+func (tx *autoRenewTx) pin() (kv.Tx, error) {
+	t, err := tx.current()
+	if err != nil {
+		return nil, err
+	}
+	tx.pinned = true
+	return t, nil
+}
+
+func (tx *autoRenewTx) isDupSort(table string) bool {
+	return tx.db.AllTables()[table].Flags&kv.DupSort != 0
+}
+
+func (tx *autoRenewTx) Commit() error {
+	tx.Rollback()
+	return nil
+}
+
+func (tx *autoRenewTx) Rollback() {
+	if tx.closed {
+		return
+	}
+	tx.closed = true
+	if tx.tx == nil {
+		return
+	}
+	tx.db.pool.put(tx.tx)
+	tx.tx = nil
+}
+
+func (tx *autoRenewTx) Has(table string, key []byte) (bool, error) {
+	t, err := tx.current()
+	if err != nil {
+		return false, err
+	}
+	return t.Has(table, key)
+}
+
+func (tx *autoRenewTx) GetOne(table string, key []byte) ([]byte, error) {
+	t, err := tx.current()
+	if err != nil {
+		return nil, err
+	}
+	return t.GetOne(table, key)
+}
+
+func (tx *autoRenewTx) ReadSequence(table string) (uint64, error) {
+	t, err := tx.current()
+	if err != nil {
+		return 0, err
+	}
+	return t.ReadSequence(table)
+}
+
+func (tx *autoRenewTx) ListBuckets() ([]string, error) {
+	t, err := tx.current()
+	if err != nil {
+		return nil, err
+	}
+	return t.ListBuckets()
+}
+
+func (tx *autoRenewTx) ViewID() uint64 {
+	if tx.tx == nil {
+		return 0
+	}
+	return tx.tx.ViewID()
+}
+
+func (tx *autoRenewTx) DBSize() (uint64, error) {
+	t, err := tx.current()
+	if err != nil {
+		return 0, err
+	}
+	return t.DBSize()
+}
+
+func (tx *autoRenewTx) BucketSize(table string) (uint64, error) {
+	t, err := tx.current()
+	if err != nil {
+		return 0, err
+	}
+	return t.BucketSize(table)
+}
+
+func (tx *autoRenewTx) CHandle() unsafe.Pointer {
+	t, err := tx.pin()
+	if err != nil {
+		return nil
+	}
+	return t.CHandle()
+}
+
+func (tx *autoRenewTx) Cursor(table string) (kv.Cursor, error) {
+	t, err := tx.pin()
+	if err != nil {
+		return nil, err
+	}
+	return t.Cursor(table)
+}
+
+func (tx *autoRenewTx) CursorDupSort(table string) (kv.CursorDupSort, error) {
+	t, err := tx.pin()
+	if err != nil {
+		return nil, err
+	}
+	return t.CursorDupSort(table)
+}
+
+func (tx *autoRenewTx) RangeDupSort(table string, key []byte, fromPrefix, toPrefix []byte, asc order.By, limit int) (iter.KV, error) {
+	t, err := tx.pin()
+	if err != nil {
+		return nil, err
+	}
+	return t.RangeDupSort(table, key, fromPrefix, toPrefix, asc, limit)
+}
+
+func (tx *autoRenewTx) Prefix(table string, prefix []byte) (iter.KV, error) {
+	nextPrefix, ok := kv.NextSubtree(prefix)
+	if !ok {
+		return tx.Range(table, prefix, nil)
+	}
+	return tx.Range(table, prefix, nextPrefix)
+}
+
+func (tx *autoRenewTx) Range(table string, fromPrefix, toPrefix []byte) (iter.KV, error) {
+	return tx.RangeAscend(table, fromPrefix, toPrefix, -1)
+}
+
+func (tx *autoRenewTx) RangeAscend(table string, fromPrefix, toPrefix []byte, limit int) (iter.KV, error) {
+	return tx.rangeOrderLimit(table, fromPrefix, toPrefix, order.Asc, limit)
+}
+
+func (tx *autoRenewTx) RangeDescend(table string, fromPrefix, toPrefix []byte, limit int) (iter.KV, error) {
+	return tx.rangeOrderLimit(table, fromPrefix, toPrefix, order.Desc, limit)
+}
+
+func (tx *autoRenewTx) rangeOrderLimit(table string, fromPrefix, toPrefix []byte, asc order.By, limit int) (iter.KV, error) {
+	if tx.isDupSort(table) {
+		t, err := tx.pin()
+		if err != nil {
+			return nil, err
+		}
+		if asc {
+			return t.RangeAscend(table, fromPrefix, toPrefix, limit)
+		}
+		return t.RangeDescend(table, fromPrefix, toPrefix, limit)
+	}
+	if asc && fromPrefix != nil && toPrefix != nil && bytes.Compare(fromPrefix, toPrefix) >= 0 {
+		return nil, fmt.Errorf("autoRenewTx: %x must be lexicographicaly before %x", fromPrefix, toPrefix)
+	}
+	if !asc && fromPrefix != nil && toPrefix != nil && bytes.Compare(fromPrefix, toPrefix) <= 0 {
+		return nil, fmt.Errorf("autoRenewTx: %x must be lexicographicaly before %x", toPrefix, fromPrefix)
+	}
+
+	var lastKey []byte // nil - nothing returned yet
+	return iter.PaginateKV(func(pageToken string) (keys, values [][]byte, nextPageToken string, err error) {
+		pageSize := tx.db.pageSize
+		if limit >= 0 && limit < pageSize {
+			pageSize = limit
+		}
+		if pageSize == 0 {
+			return nil, nil, "", nil
+		}
+		keys, values, err = tx.readPage(table, lastKey, fromPrefix, toPrefix, asc, pageSize)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if limit >= 0 {
+			limit -= len(keys)
+		}
+		if len(keys) < pageSize || limit == 0 {
+			return keys, values, "", nil
+		}
+		lastKey = keys[len(keys)-1]
+		return keys, values, "next", nil
+	}), nil
+}
+
+// readPage - reads up to `pageSize` pairs which go after `lastKey` (in `asc` order) in own short-living read transaction.
+// The transaction goes back to the pool before the page is returned.
+// Returned pairs are copied: they must stay valid after transaction is closed.
+func (tx *autoRenewTx) readPage(table string, lastKey, fromPrefix, toPrefix []byte, asc order.By, pageSize int) (keys, values [][]byte, err error) {
+	from := fromPrefix
+	if lastKey != nil {
+		from = lastKey
+	}
+	// the point-reads snapshot is not kept between pages of the stream
+	tx.release()
+	roTx, err := tx.db.pool.get(tx.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.db.pool.put(roTx)
+
+	var it iter.KV
+	if asc {
+		it, err = roTx.RangeAscend(table, from, toPrefix, -1)
+	} else {
+		it, err = roTx.RangeDescend(table, from, toPrefix, -1)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	for it.HasNext() && len(keys) < pageSize {
+		k, v, err := it.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		// `from` is inclusive (for Descend: it's a prefix) - skip keys returned by previous pages
+		if lastKey != nil {
+			cmp := bytes.Compare(k, lastKey)
+			if (asc && cmp <= 0) || (!asc && cmp >= 0) {
+				continue
+			}
+		}
+		keys = append(keys, common.Copy(k))
+		values = append(values, common.Copy(v))
+	}
+	return keys, values, nil
+}
+
+func (tx *autoRenewTx) ForEach(table string, fromPrefix []byte, walker func(k, v []byte) error) error {
+	it, err := tx.Range(table, fromPrefix, nil)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err := walker(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *autoRenewTx) ForPrefix(table string, prefix []byte, walker func(k, v []byte) error) error {
+	it, err := tx.Prefix(table, prefix)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err := walker(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *autoRenewTx) ForAmount(table string, prefix []byte, amount uint32, walker func(k, v []byte) error) error {
+	it, err := tx.RangeAscend(table, prefix, nil, int(amount))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if err := walker(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
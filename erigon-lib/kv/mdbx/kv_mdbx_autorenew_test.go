@@ -0,0 +1,151 @@
+/*
+   Copyright 2024 Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mdbx
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func autoRenewCaseDB(t *testing.T, n int) kv.RwDB {
+	t.Helper()
+	db := NewMDBX(log.New()).InMem(t.TempDir()).WithTableCfg(func(defaultBuckets kv.TableCfg) kv.TableCfg {
+		return kv.TableCfg{
+			"Table":     kv.TableCfgItem{},
+			kv.Sequence: kv.TableCfgItem{},
+		}
+	}).MapSize(128 * datasize.MB).MustOpen()
+	t.Cleanup(db.Close)
+
+	require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		for i := 0; i < n; i++ {
+			k := binary.BigEndian.AppendUint16(nil, uint16(i))
+			if err := tx.Put("Table", k, k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return db
+}
+
+func TestAutoRenewRange(t *testing.T) {
+	db := autoRenewCaseDB(t, 10)
+	rdb := NewAutoRenewRoDB(db, 3, time.Hour, 0)
+	t.Cleanup(rdb.Close)
+
+	tx, err := rdb.BeginRo(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	t.Run("Asc", func(t *testing.T) {
+		it, err := tx.Range("Table", nil, nil)
+		require.NoError(t, err)
+		keys, _, err := iter.ToArrayKV(it)
+		require.NoError(t, err)
+		require.Equal(t, 10, len(keys))
+		for i, k := range keys {
+			require.Equal(t, uint16(i), binary.BigEndian.Uint16(k))
+		}
+	})
+	t.Run("AscLimit", func(t *testing.T) {
+		it, err := tx.RangeAscend("Table", []byte{0, 2}, []byte{0, 9}, 5)
+		require.NoError(t, err)
+		keys, _, err := iter.ToArrayKV(it)
+		require.NoError(t, err)
+		require.Equal(t, 5, len(keys))
+		require.Equal(t, []byte{0, 2}, keys[0])
+		require.Equal(t, []byte{0, 6}, keys[4])
+	})
+	t.Run("Desc", func(t *testing.T) {
+		it, err := tx.RangeDescend("Table", []byte{0, 8}, []byte{0, 1}, -1)
+		require.NoError(t, err)
+		keys, _, err := iter.ToArrayKV(it)
+		require.NoError(t, err)
+		require.Equal(t, 7, len(keys))
+		require.Equal(t, []byte{0, 8}, keys[0])
+		require.Equal(t, []byte{0, 2}, keys[6])
+	})
+}
+
+func TestAutoRenewSeesNewSnapshot(t *testing.T) {
+	db := autoRenewCaseDB(t, 3)
+	rdb := NewAutoRenewRoDB(db, 2, time.Hour, 0)
+	t.Cleanup(rdb.Close)
+
+	tx, err := rdb.BeginRo(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	it, err := tx.Range("Table", nil, nil)
+	require.NoError(t, err)
+	require.True(t, it.HasNext()) // first page is read
+
+	// data appended after stream creation is visible by next pages
+	require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		return tx.Put("Table", []byte{0, 5}, []byte{0, 5})
+	}))
+	keys, _, err := iter.ToArrayKV(it)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(keys))
+
+	// point-reads snapshot is renewed
+	rdb.renewAfter = time.Nanosecond
+	v, err := tx.GetOne("Table", []byte{0, 5})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 5}, v)
+}
+
+func TestAutoRenewReleasedBetweenPages(t *testing.T) {
+	db := autoRenewCaseDB(t, 10)
+	rdb := NewAutoRenewRoDB(db, 3, time.Hour, 1)
+	t.Cleanup(rdb.Close)
+
+	tx, err := rdb.BeginRo(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	// snapshot is not opened until first read
+	require.Zero(t, rdb.pool.inUse.Load())
+
+	it, err := tx.Range("Table", nil, nil)
+	require.NoError(t, err)
+	for i := 0; it.HasNext(); i++ {
+		if i%3 == 0 {
+			// page is read: neither the page snapshot nor the point-reads one is held
+			require.Zero(t, rdb.pool.inUse.Load(), i)
+		}
+		_, _, err := it.Next()
+		require.NoError(t, err)
+
+		_, err = tx.GetOne("Table", []byte{0, 1})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), rdb.pool.inUse.Load(), i)
+	}
+
+	tx.Rollback()
+	require.Zero(t, rdb.pool.inUse.Load())
+	// pages and point-reads reused one reset reader
+	require.Len(t, rdb.pool.idle, 1)
+}
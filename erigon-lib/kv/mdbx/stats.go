@@ -0,0 +1,100 @@
+package mdbx
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+)
+
+// TableStats - pages used by a table. Size is the size of all its pages, PayloadSize - size of its keys
+// and values, it's filled only on request, because requires full scan of the table. FillFactor is
+// PayloadSize/Size: low values mean that pages are mostly empty, which happens after deletions.
+type TableStats struct {
+	Name          string  `json:"name"`
+	Depth         uint    `json:"depth"`
+	Entries       uint64  `json:"entries"`
+	BranchPages   uint64  `json:"branchPages"`
+	LeafPages     uint64  `json:"leafPages"`
+	OverflowPages uint64  `json:"overflowPages"`
+	Size          uint64  `json:"size"`
+	PayloadSize   uint64  `json:"payloadSize,omitempty"`
+	FillFactor    float64 `json:"fillFactor,omitempty"`
+}
+
+// TableStats returns page statistics of the table from mdbx metadata, with withPayload the table is scanned
+// to compute its fill factor
+func (tx *MdbxTx) TableStats(ctx context.Context, name string, withPayload bool) (*TableStats, error) {
+	st, err := tx.BucketStat(name)
+	if err != nil {
+		return nil, err
+	}
+	stats := &TableStats{
+		Name:          name,
+		Depth:         st.Depth,
+		Entries:       st.Entries,
+		BranchPages:   st.BranchPages,
+		LeafPages:     st.LeafPages,
+		OverflowPages: st.OverflowPages,
+		Size:          (st.BranchPages + st.LeafPages + st.OverflowPages) * tx.db.opts.pageSize,
+	}
+	// gc and root aren't regular tables and can't be opened by cursor
+	if !withPayload || stats.Size == 0 || name == "gc" || name == "root" {
+		return stats, nil
+	}
+
+	c, err := tx.Cursor(name)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	var i int
+	var prevKey []byte
+	for k, v, err := c.First(); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, err
+		}
+		// keys of dupsort tables are stored once for all their values
+		if !bytes.Equal(k, prevKey) {
+			stats.PayloadSize += uint64(len(k))
+			prevKey = append(prevKey[:0], k...)
+		}
+		stats.PayloadSize += uint64(len(v))
+		if i++; i%100_000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	stats.FillFactor = float64(stats.PayloadSize) / float64(stats.Size)
+	return stats, nil
+}
+
+// TablesStats returns statistics of all tables of the db, including gc (free pages), sorted by size
+func (tx *MdbxTx) TablesStats(ctx context.Context, withPayload bool) ([]TableStats, error) {
+	tables, err := tx.ListBuckets()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]TableStats, 0, len(tables)+1)
+	for _, name := range append(tables, "gc") {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, ok := tx.db.buckets[name]; !ok && name != "gc" {
+			continue // tables not known to this version of erigon
+		}
+		stats, err := tx.TableStats(ctx, name, withPayload)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *stats)
+	}
+	slices.SortFunc(res, func(a, b TableStats) int {
+		if c := cmp.Compare(b.Size, a.Size); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
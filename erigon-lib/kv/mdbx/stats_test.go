@@ -0,0 +1,54 @@
+package mdbx
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+func TestTablesStats(t *testing.T) {
+	db, tx, _ := BaseCase(t)
+	ctx := context.Background()
+
+	var key [8]byte
+	value := make([]byte, 100)
+	for i := 0; i < 1000; i++ {
+		binary.BigEndian.PutUint64(key[:], uint64(i))
+		require.NoError(t, tx.Put(kv.Sequence, key[:], value))
+	}
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+		stats, err := tx.(*MdbxTx).TablesStats(ctx, true)
+		require.NoError(t, err)
+
+		byName := map[string]TableStats{}
+		for _, st := range stats {
+			byName[st.Name] = st
+		}
+		require.Contains(t, byName, "gc")
+
+		sequence := byName[kv.Sequence]
+		require.Equal(t, kv.Sequence, stats[0].Name, "largest table goes first")
+		require.Equal(t, uint64(1000), sequence.Entries)
+		require.NotZero(t, sequence.LeafPages)
+		require.Equal(t, (sequence.LeafPages+sequence.BranchPages+sequence.OverflowPages)*db.PageSize(), sequence.Size)
+		require.Equal(t, uint64(1000*(8+100)), sequence.PayloadSize)
+		require.Greater(t, sequence.FillFactor, 0.5)
+		require.Less(t, sequence.FillFactor, 1.0)
+
+		dupsort := byName["Table"]
+		require.Equal(t, uint64(4), dupsort.Entries)
+		require.Equal(t, uint64(2*4+4*8), dupsort.PayloadSize, "dupsort keys are counted once")
+
+		st, err := tx.(*MdbxTx).TableStats(ctx, kv.Sequence, false)
+		require.NoError(t, err)
+		require.Zero(t, st.PayloadSize)
+		require.Equal(t, sequence.Size, st.Size)
+		return nil
+	}))
+}
@@ -731,6 +731,9 @@ func (m *MemoryMutation) DomainGet(name kv.Domain, k, k2 []byte) (v []byte, step
 func (m *MemoryMutation) DomainGetAsOf(name kv.Domain, k, k2 []byte, ts uint64) (v []byte, ok bool, err error) {
 	return m.db.(kv.TemporalTx).DomainGetAsOf(name, k, k2, ts)
 }
+func (m *MemoryMutation) DomainGetAsOfBatch(reqs []kv.DomainGetAsOfReq) error {
+	return m.db.(kv.TemporalTx).DomainGetAsOfBatch(reqs)
+}
 func (m *MemoryMutation) HistorySeek(name kv.History, k []byte, ts uint64) (v []byte, ok bool, err error) {
 	return m.db.(kv.TemporalTx).HistorySeek(name, k, ts)
 }
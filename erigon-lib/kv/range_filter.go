@@ -0,0 +1,79 @@
+package kv
+
+import (
+	"bytes"
+
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+)
+
+// RangeFilter - filters and projections of Range results. Remote transactions apply them server-side,
+// to not send over the network the pairs and the bytes the caller discards anyway.
+type RangeFilter struct {
+	KeyPrefix   []byte // only keys starting with KeyPrefix
+	KeySuffix   []byte // only keys ending with KeySuffix
+	KeysOnly    bool   // values are nil
+	ValueMaxLen int    // only first ValueMaxLen bytes of values, <= 0 means whole values
+}
+
+func (f RangeFilter) Empty() bool {
+	return len(f.KeyPrefix) == 0 && len(f.KeySuffix) == 0 && !f.KeysOnly && f.ValueMaxLen <= 0
+}
+
+// Match - the key passes the filters
+func (f RangeFilter) Match(k []byte) bool {
+	return bytes.HasPrefix(k, f.KeyPrefix) && bytes.HasSuffix(k, f.KeySuffix)
+}
+
+// Project - the part of the value to return
+func (f RangeFilter) Project(v []byte) []byte {
+	if f.KeysOnly {
+		return nil
+	}
+	if f.ValueMaxLen > 0 && len(v) > f.ValueMaxLen {
+		return v[:f.ValueMaxLen]
+	}
+	return v
+}
+
+// FilteredRanger - transactions filtering and projecting ranges at the source
+type FilteredRanger interface {
+	RangeFiltered(table string, fromPrefix, toPrefix []byte, asc order.By, limit int, filter RangeFilter) (iter.KV, error)
+}
+
+// RangeFiltered - like RangeAscend/RangeDescend, but returns only the pairs matching the filter, projected.
+// limit counts the returned pairs. Transactions implementing FilteredRanger (remote) filter at the source.
+func RangeFiltered(tx Tx, table string, fromPrefix, toPrefix []byte, asc order.By, limit int, filter RangeFilter) (iter.KV, error) {
+	if ranger, ok := tx.(FilteredRanger); ok {
+		return ranger.RangeFiltered(table, fromPrefix, toPrefix, asc, limit, filter)
+	}
+	if filter.Empty() {
+		if asc {
+			return tx.RangeAscend(table, fromPrefix, toPrefix, limit)
+		}
+		return tx.RangeDescend(table, fromPrefix, toPrefix, limit)
+	}
+
+	var it iter.KV
+	var err error
+	if asc {
+		it, err = tx.RangeAscend(table, fromPrefix, toPrefix, -1)
+	} else {
+		it, err = tx.RangeDescend(table, fromPrefix, toPrefix, -1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return FilterRange(it, limit, filter), nil
+}
+
+// FilterRange applies the filter to it, limit < 0 means no limit
+func FilterRange(it iter.KV, limit int, filter RangeFilter) iter.KV {
+	it = iter.TransformKV(iter.FilterKV(it, func(k, _ []byte) bool { return filter.Match(k) }), func(k, v []byte) ([]byte, []byte, error) {
+		return k, filter.Project(v), nil
+	})
+	if limit >= 0 {
+		it = iter.LimitKV(it, limit)
+	}
+	return it
+}
@@ -48,6 +48,7 @@ type remoteOpts struct {
 }
 
 var _ kv.TemporalTx = (*tx)(nil)
+var _ kv.ResumableRangeTx = (*tx)(nil)
 
 type DB struct {
 	remoteKV     remote.KVClient
@@ -657,6 +658,16 @@ func (tx *tx) DomainGetAsOf(name kv.Domain, k, k2 []byte, ts uint64) (v []byte,
 	return reply.V, reply.Ok, nil
 }
 
+// DomainGetAsOfBatch - remote protocol has no batch request yet: lookups are sent one by one
+func (tx *tx) DomainGetAsOfBatch(reqs []kv.DomainGetAsOfReq) (err error) {
+	for i := range reqs {
+		if reqs[i].V, reqs[i].Ok, err = tx.DomainGetAsOf(reqs[i].Domain, reqs[i].Key, nil, reqs[i].Ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (tx *tx) DomainGet(name kv.Domain, k, k2 []byte) (v []byte, step uint64, err error) {
 	reply, err := tx.db.remoteKV.DomainGet(tx.ctx, &remote.DomainGetReq{TxId: tx.id, Table: name.String(), K: k, K2: k2, Latest: true})
 	if err != nil {
@@ -666,13 +677,16 @@ func (tx *tx) DomainGet(name kv.Domain, k, k2 []byte) (v []byte, step uint64, er
 }
 
 func (tx *tx) DomainRange(name kv.Domain, fromKey, toKey []byte, ts uint64, asc order.By, limit int) (it iter.KV, err error) {
-	return iter.PaginateKV(func(pageToken string) (keys, vals [][]byte, nextPageToken string, err error) {
-		reply, err := tx.db.remoteKV.DomainRange(tx.ctx, &remote.DomainRangeReq{TxId: tx.id, Table: name.String(), FromKey: fromKey, ToKey: toKey, Ts: ts, OrderAscend: bool(asc), Limit: int64(limit)})
+	return tx.DomainRangeResume(name, fromKey, toKey, ts, asc, limit, "")
+}
+func (tx *tx) DomainRangeResume(name kv.Domain, fromKey, toKey []byte, ts uint64, asc order.By, limit int, token string) (it iter.KV, err error) {
+	return iter.ResumePaginateKV(func(pageToken string) (keys, vals [][]byte, nextPageToken string, err error) {
+		reply, err := tx.db.remoteKV.DomainRange(tx.ctx, &remote.DomainRangeReq{TxId: tx.id, Table: name.String(), FromKey: fromKey, ToKey: toKey, Ts: ts, OrderAscend: bool(asc), Limit: int64(limit), PageToken: pageToken})
 		if err != nil {
 			return nil, nil, "", err
 		}
 		return reply.Keys, reply.Values, reply.NextPageToken, nil
-	}), nil
+	}, token)
 }
 func (tx *tx) HistorySeek(name kv.History, k []byte, ts uint64) (v []byte, ok bool, err error) {
 	reply, err := tx.db.remoteKV.HistorySeek(tx.ctx, &remote.HistorySeekReq{TxId: tx.id, Table: string(name), K: k, Ts: ts})
@@ -682,24 +696,30 @@ func (tx *tx) HistorySeek(name kv.History, k []byte, ts uint64) (v []byte, ok bo
 	return reply.V, reply.Ok, nil
 }
 func (tx *tx) HistoryRange(name kv.History, fromTs, toTs int, asc order.By, limit int) (it iter.KV, err error) {
-	return iter.PaginateKV(func(pageToken string) (keys, vals [][]byte, nextPageToken string, err error) {
-		reply, err := tx.db.remoteKV.HistoryRange(tx.ctx, &remote.HistoryRangeReq{TxId: tx.id, Table: string(name), FromTs: int64(fromTs), ToTs: int64(toTs), OrderAscend: bool(asc), Limit: int64(limit)})
+	return tx.HistoryRangeResume(name, fromTs, toTs, asc, limit, "")
+}
+func (tx *tx) HistoryRangeResume(name kv.History, fromTs, toTs int, asc order.By, limit int, token string) (it iter.KV, err error) {
+	return iter.ResumePaginateKV(func(pageToken string) (keys, vals [][]byte, nextPageToken string, err error) {
+		reply, err := tx.db.remoteKV.HistoryRange(tx.ctx, &remote.HistoryRangeReq{TxId: tx.id, Table: string(name), FromTs: int64(fromTs), ToTs: int64(toTs), OrderAscend: bool(asc), Limit: int64(limit), PageToken: pageToken})
 		if err != nil {
 			return nil, nil, "", err
 		}
 		return reply.Keys, reply.Values, reply.NextPageToken, nil
-	}), nil
+	}, token)
 }
 
 func (tx *tx) IndexRange(name kv.InvertedIdx, k []byte, fromTs, toTs int, asc order.By, limit int) (timestamps iter.U64, err error) {
-	return iter.PaginateU64(func(pageToken string) (arr []uint64, nextPageToken string, err error) {
-		req := &remote.IndexRangeReq{TxId: tx.id, Table: string(name), K: k, FromTs: int64(fromTs), ToTs: int64(toTs), OrderAscend: bool(asc), Limit: int64(limit)}
+	return tx.IndexRangeResume(name, k, fromTs, toTs, asc, limit, "")
+}
+func (tx *tx) IndexRangeResume(name kv.InvertedIdx, k []byte, fromTs, toTs int, asc order.By, limit int, token string) (timestamps iter.U64, err error) {
+	return iter.ResumePaginateU64(func(pageToken string) (arr []uint64, nextPageToken string, err error) {
+		req := &remote.IndexRangeReq{TxId: tx.id, Table: string(name), K: k, FromTs: int64(fromTs), ToTs: int64(toTs), OrderAscend: bool(asc), Limit: int64(limit), PageToken: pageToken}
 		reply, err := tx.db.remoteKV.IndexRange(tx.ctx, req)
 		if err != nil {
 			return nil, "", err
 		}
 		return reply.Timestamps, reply.NextPageToken, nil
-	}), nil
+	}, token)
 }
 
 func (tx *tx) Prefix(table string, prefix []byte) (iter.KV, error) {
@@ -711,14 +731,32 @@ func (tx *tx) Prefix(table string, prefix []byte) (iter.KV, error) {
 }
 
 func (tx *tx) rangeOrderLimit(table string, fromPrefix, toPrefix []byte, asc order.By, limit int) (iter.KV, error) {
-	return iter.PaginateKV(func(pageToken string) (keys [][]byte, values [][]byte, nextPageToken string, err error) {
-		req := &remote.RangeReq{TxId: tx.id, Table: table, FromPrefix: fromPrefix, ToPrefix: toPrefix, OrderAscend: bool(asc), Limit: int64(limit)}
+	return tx.RangeResume(table, fromPrefix, toPrefix, asc, limit, "")
+}
+func (tx *tx) RangeResume(table string, fromPrefix, toPrefix []byte, asc order.By, limit int, token string) (iter.KV, error) {
+	return tx.rangeFilteredResume(table, fromPrefix, toPrefix, asc, limit, kv.RangeFilter{}, token)
+}
+
+// RangeFiltered - the server filters and projects the pairs, to not send what the caller discards
+func (tx *tx) RangeFiltered(table string, fromPrefix, toPrefix []byte, asc order.By, limit int, filter kv.RangeFilter) (iter.KV, error) {
+	return tx.rangeFilteredResume(table, fromPrefix, toPrefix, asc, limit, filter, "")
+}
+
+func (tx *tx) rangeFilteredResume(table string, fromPrefix, toPrefix []byte, asc order.By, limit int, filter kv.RangeFilter, token string) (iter.KV, error) {
+	it, err := iter.ResumePaginateKV(func(pageToken string) (keys [][]byte, values [][]byte, nextPageToken string, err error) {
+		req := &remote.RangeReq{TxId: tx.id, Table: table, FromPrefix: fromPrefix, ToPrefix: toPrefix, OrderAscend: bool(asc), Limit: int64(limit), PageToken: pageToken,
+			KeyPrefix: filter.KeyPrefix, KeySuffix: filter.KeySuffix, KeysOnly: filter.KeysOnly, ValueMaxLen: int32(filter.ValueMaxLen)}
 		reply, err := tx.db.remoteKV.Range(tx.ctx, req)
 		if err != nil {
 			return nil, nil, "", err
 		}
 		return reply.Keys, reply.Values, reply.NextPageToken, nil
-	}), nil
+	}, token)
+	if err != nil || filter.Empty() {
+		return it, err
+	}
+	// no-op for the pairs filtered by the server, but servers before 6.4.0 ignore the filter
+	return kv.FilterRange(it, -1, filter), nil
 }
 func (tx *tx) Range(table string, fromPrefix, toPrefix []byte) (iter.KV, error) {
 	return tx.rangeOrderLimit(table, fromPrefix, toPrefix, order.Asc, -1)
@@ -17,6 +17,7 @@
 package remotedbserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -37,7 +38,6 @@ import (
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
 	types "github.com/ledgerwatch/erigon-lib/gointerfaces/typesproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
-	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 )
 
@@ -64,7 +64,9 @@ const MaxTxTTL = 60 * time.Second
 // 6.0.0 - Blocks now have system-txs - in the begin/end of block
 // 6.1.0 - Add methods Range, IndexRange, HistorySeek, HistoryRange
 // 6.2.0 - Add HistoryFiles to reply of Snapshots() method
-var KvServiceAPIVersion = &types.VersionReply{Major: 6, Minor: 2, Patch: 0}
+// 6.3.0 - Range, IndexRange reply pages of at most PageSize items, clients must send back NextPageToken
+// 6.4.0 - Range supports server-side key filters and value projections
+var KvServiceAPIVersion = &types.VersionReply{Major: 6, Minor: 4, Patch: 0}
 
 type KvServer struct {
 	remote.UnimplementedKVServer // must be embedded to have forward compatible implementations.
@@ -581,8 +583,20 @@ func (s *KvServer) HistorySeek(_ context.Context, req *remote.HistorySeekReq) (r
 
 const PageSizeLimit = 4 * 4096
 
+// pageSize - amount of items to put in a single reply. Pages are read inside `with`, so many concurrent
+// range streams over the same tx take turns per page instead of waiting for each other's full range.
+func pageSize(requested int32, limit int) int {
+	size := PageSizeLimit
+	if requested > 0 && requested < PageSizeLimit {
+		size = int(requested)
+	}
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	return size
+}
+
 func (s *KvServer) IndexRange(_ context.Context, req *remote.IndexRangeReq) (*remote.IndexRangeReply, error) {
-	reply := &remote.IndexRangeReply{}
 	from, limit := int(req.FromTs), int(req.Limit)
 	if req.PageToken != "" {
 		var pagination remote.IndexPagination
@@ -591,10 +605,9 @@ func (s *KvServer) IndexRange(_ context.Context, req *remote.IndexRangeReq) (*re
 		}
 		from, limit = int(pagination.NextTimeStamp), int(pagination.Limit)
 	}
-	if req.PageSize <= 0 || req.PageSize > PageSizeLimit {
-		req.PageSize = PageSizeLimit
-	}
+	size := pageSize(req.PageSize, limit)
 
+	reply := &remote.IndexRangeReply{Timestamps: make([]uint64, 0, size)}
 	if err := s.with(req.TxId, func(tx kv.Tx) error {
 		ttx, ok := tx.(kv.TemporalTx)
 		if !ok {
@@ -605,7 +618,7 @@ func (s *KvServer) IndexRange(_ context.Context, req *remote.IndexRangeReq) (*re
 			return err
 		}
 		defer it.Close()
-		for it.HasNext() {
+		for len(reply.Timestamps) < size && it.HasNext() {
 			v, err := it.Next()
 			if err != nil {
 				return err
@@ -613,7 +626,7 @@ func (s *KvServer) IndexRange(_ context.Context, req *remote.IndexRangeReq) (*re
 			reply.Timestamps = append(reply.Timestamps, v)
 			limit--
 		}
-		if len(reply.Timestamps) == PageSizeLimit && it.HasNext() {
+		if it.HasNext() {
 			next, err := it.Next()
 			if err != nil {
 				return err
@@ -639,26 +652,18 @@ func (s *KvServer) Range(_ context.Context, req *remote.RangeReq) (*remote.Pairs
 		}
 		from, limit = pagination.NextKey, int(pagination.Limit)
 	}
-	if req.PageSize <= 0 || req.PageSize > PageSizeLimit {
-		req.PageSize = PageSizeLimit
-	}
+	size := pageSize(req.PageSize, limit)
+	filter := kv.RangeFilter{KeyPrefix: req.KeyPrefix, KeySuffix: req.KeySuffix, KeysOnly: req.KeysOnly, ValueMaxLen: int(req.ValueMaxLen)}
 
-	reply := &remote.Pairs{}
+	reply := &remote.Pairs{Keys: make([][]byte, 0, size), Values: make([][]byte, 0, size)}
 	var err error
 	if err = s.with(req.TxId, func(tx kv.Tx) error {
-		var it iter.KV
-		if req.OrderAscend {
-			it, err = tx.RangeAscend(req.Table, from, req.ToPrefix, limit)
-			if err != nil {
-				return err
-			}
-		} else {
-			it, err = tx.RangeDescend(req.Table, from, req.ToPrefix, limit)
-			if err != nil {
-				return err
-			}
+		it, err := kv.RangeFiltered(tx, req.Table, from, req.ToPrefix, order.By(req.OrderAscend), limit, filter)
+		if err != nil {
+			return err
 		}
-		for it.HasNext() {
+		defer it.Close()
+		for len(reply.Keys) < size && it.HasNext() {
 			k, v, err := it.Next()
 			if err != nil {
 				return err
@@ -667,15 +672,23 @@ func (s *KvServer) Range(_ context.Context, req *remote.RangeReq) (*remote.Pairs
 			reply.Values = append(reply.Values, v)
 			limit--
 		}
-		if len(reply.Keys) == PageSizeLimit && it.HasNext() {
-			nextK, _, err := it.Next()
+		// next page starts from a key, so dups of the last key of DupSort tables must not be split across pages
+		for it.HasNext() {
+			nextK, nextV, err := it.Next()
 			if err != nil {
 				return err
 			}
+			if bytes.Equal(nextK, reply.Keys[len(reply.Keys)-1]) {
+				reply.Keys = append(reply.Keys, nextK)
+				reply.Values = append(reply.Values, nextV)
+				limit--
+				continue
+			}
 			reply.NextPageToken, err = marshalPagination(&remote.ParisPagination{NextKey: nextK, Limit: int64(limit)})
 			if err != nil {
 				return err
 			}
+			break
 		}
 		return nil
 	}); err != nil {
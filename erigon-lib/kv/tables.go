@@ -297,6 +297,23 @@ const (
 	CallFromIndex = "CallFromIndex"
 	CallToIndex   = "CallToIndex"
 
+	// ExecutionWitness - witnesses of executed blocks, emitted by the execution stage for stateless validation
+	// 8-byte BE block number + block hash -> rlp-encoded witness (accessed accounts/storage/code plus proofs)
+	ExecutionWitness = "ExecutionWitness"
+
+	// ExecutionProfile - per-opcode gas/time histograms and hottest contracts of blocks re-executed by `integration profile_exec`
+	// 8-byte BE block number + block hash -> json-encoded profile
+	ExecutionProfile = "ExecutionProfile"
+
+	// OtsAppearances - address appearances emitted by the execution stage for ots2_* methods. It is DupSort-ed table
+	// address -> kind (1 byte) + counterpart address + 8-byte BE block number
+	// kinds: contract created by deployer, contract self-destructed to beneficiary, ERC-20/721 transfer of token
+	// from/to holder (only first appearance of a token is stored)
+	OtsAppearances = "OtsAppearances"
+	// OtsAppearancesByBlock - same appearances keyed by block, used by unwind. It is DupSort-ed table
+	// 8-byte BE block number -> address + kind (1 byte) + counterpart address
+	OtsAppearancesByBlock = "OtsAppearancesByBlock"
+
 	// Cumulative indexes for estimation of stage execution
 	CumulativeGasIndex         = "CumulativeGasIndex"
 	CumulativeTransactionIndex = "CumulativeTransactionIndex"
@@ -362,6 +379,7 @@ const (
 	BorSeparate       = "BorSeparate"               // persisted snapshots of the Validator Sets, with their proposer priorities
 	BorEvents         = "BorEvents"                 // event_id -> event_payload
 	BorEventNums      = "BorEventNums"              // block_num -> event_id (first event_id in that block)
+	BorEventsByRootTx = "BorEventsByRootTx"         // root_chain_tx_hash + log_index_u64 -> event_id + block_num (block which committed the event)
 	BorSpans          = "BorSpans"                  // span_id -> span (in JSON encoding)
 	BorMilestones     = "BorMilestones"             // milestone_id -> milestone (in JSON encoding)
 	BorMilestoneEnds  = "BorMilestoneEnds"          // start block_num -> milestone_id (first block of milestone)
@@ -510,6 +528,13 @@ const (
 	Proposers        = "BlockProposers"   // epoch => proposers indicies
 
 	StatesProcessingProgress = "StatesProcessingProgress"
+
+	// Slasher, keys start with the epoch (slot for proposals) so that the history is pruned from the beginning of the table
+	SlasherAttestations        = "SlasherAttestations"        // [target_epoch+validator_index] => [source_epoch+data_root+attestation_root]
+	SlasherIndexedAttestations = "SlasherIndexedAttestations" // [target_epoch+attestation_root] => [indexed_attestation_ssz]
+	SlasherMinSpans            = "SlasherMinSpans"            // [chunk_index+validator_index] => [uint16 distances]
+	SlasherMaxSpans            = "SlasherMaxSpans"            // [chunk_index+validator_index] => [uint16 distances]
+	SlasherProposals           = "SlasherProposals"           // [slot+proposer_index] => [signed_block_header_ssz]
 )
 
 // Keys
@@ -584,6 +609,10 @@ var ChaindataTables = []string{
 	CallTraceSet,
 	CallFromIndex,
 	CallToIndex,
+	ExecutionWitness,
+	ExecutionProfile,
+	OtsAppearances,
+	OtsAppearancesByBlock,
 	CumulativeGasIndex,
 	CumulativeTransactionIndex,
 	Log,
@@ -610,6 +639,7 @@ var ChaindataTables = []string{
 	BorSeparate,
 	BorEvents,
 	BorEventNums,
+	BorEventsByRootTx,
 	BorSpans,
 	BorMilestones,
 	BorMilestoneEnds,
@@ -715,18 +745,26 @@ var ChaindataTables = []string{
 	ActiveValidatorIndicies,
 	EffectiveBalancesDump,
 	BalancesDump,
+	// Slasher
+	SlasherAttestations,
+	SlasherIndexedAttestations,
+	SlasherMinSpans,
+	SlasherMaxSpans,
+	SlasherProposals,
 }
 
 const (
 	RecentLocalTransaction = "RecentLocalTransaction" // sequence_u64 -> tx_hash
 	PoolTransaction        = "PoolTransaction"        // txHash -> sender+tx_rlp
 	PoolInfo               = "PoolInfo"               // option_key -> option_value
+	PoolLocalsJournal      = "PoolLocalsJournal"      // txHash -> added_unix_u64+sender+tx_rlp
 )
 
 var TxPoolTables = []string{
 	RecentLocalTransaction,
 	PoolTransaction,
 	PoolInfo,
+	PoolLocalsJournal,
 }
 var SentryTables = []string{}
 var DownloaderTables = []string{
@@ -799,6 +837,9 @@ var ChaindataTablesCfg = TableCfg{
 	},
 	CallTraceSet: {Flags: DupSort},
 
+	OtsAppearances:        {Flags: DupSort},
+	OtsAppearancesByBlock: {Flags: DupSort},
+
 	TblAccountKeys:           {Flags: DupSort},
 	TblAccountHistoryKeys:    {Flags: DupSort},
 	TblAccountHistoryVals:    {Flags: DupSort},
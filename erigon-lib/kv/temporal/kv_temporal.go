@@ -204,6 +204,10 @@ func (tx *Tx) DomainGetAsOf(name kv.Domain, key, key2 []byte, ts uint64) (v []by
 	return tx.aggCtx.DomainGetAsOf(tx.MdbxTx, name, key, ts)
 }
 
+func (tx *Tx) DomainGetAsOfBatch(reqs []kv.DomainGetAsOfReq) error {
+	return tx.aggCtx.DomainGetAsOfBatch(tx.MdbxTx, reqs)
+}
+
 func (tx *Tx) HistorySeek(name kv.History, key []byte, ts uint64) (v []byte, ok bool, err error) {
 	return tx.aggCtx.HistorySeek(name, key, ts, tx.MdbxTx)
 }
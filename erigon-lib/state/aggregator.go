@@ -44,6 +44,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
 	"github.com/ledgerwatch/erigon-lib/kv/iter"
@@ -98,6 +99,8 @@ type Aggregator struct {
 	logger       log.Logger
 
 	ctxAutoIncrement atomic.Uint64
+
+	merges *mergeScheduler // paces background merges
 }
 
 type OnFreezeFunc func(frozenFileNames []string)
@@ -126,6 +129,7 @@ func NewAggregator(ctx context.Context, dirs datadir.Dirs, aggregationStep uint6
 		logger:                 logger,
 		collateAndBuildWorkers: 1,
 		mergeWorkers:           1,
+		merges:                 newMergeScheduler(),
 
 		commitmentValuesTransform: AggregatorSqueezeCommitmentValues,
 	}
@@ -316,6 +320,22 @@ func (a *Aggregator) closeDirtyFiles() {
 	a.tracesTo.Close()
 }
 
+// SetMergeIOLimit - bytes per second written by background merges, 0 - unlimited
+func (a *Aggregator) SetMergeIOLimit(limit datasize.ByteSize) { a.merges.setIOLimit(uint64(limit)) }
+func (a *Aggregator) MergeIOLimit() datasize.ByteSize         { return datasize.ByteSize(a.merges.ioLimit()) }
+
+// SetMergeIdleWait - max time a background merge waits for the block execution to be done, 0 - merges don't wait
+func (a *Aggregator) SetMergeIdleWait(d time.Duration) { a.merges.idleWait.Store(int64(d)) }
+
+// PauseMerges pauses background merges, the running ones stop writing until ResumeMerges
+func (a *Aggregator) PauseMerges()       { a.merges.pause() }
+func (a *Aggregator) ResumeMerges()      { a.merges.resume() }
+func (a *Aggregator) MergesPaused() bool { return a.merges.isPaused() }
+
+// StartForegroundWork marks block execution in progress: background merges wait for it to be done (see SetMergeIdleWait).
+// The returned func marks it done.
+func (a *Aggregator) StartForegroundWork() (finish func()) { return a.merges.startForegroundWork() }
+
 func (a *Aggregator) SetCollateAndBuildWorkers(i int) { a.collateAndBuildWorkers = i }
 func (a *Aggregator) SetMergeWorkers(i int)           { a.mergeWorkers = i }
 func (a *Aggregator) SetCompressWorkers(i int) {
@@ -518,6 +538,7 @@ func (sf AggV3StaticFiles) CleanupOnError() {
 
 func (a *Aggregator) buildFiles(ctx context.Context, step uint64) error {
 	a.logger.Debug("[agg] collate and build", "step", step, "collate_workers", a.collateAndBuildWorkers, "merge_workers", a.mergeWorkers, "compress_workers", a.d[kv.AccountsDomain].compressWorkers)
+	diagnostics.Send(diagnostics.DomainAggregationUpdate{Phase: "build", Step: step})
 
 	var (
 		logEvery      = time.NewTicker(time.Second * 30)
@@ -622,6 +643,7 @@ func (a *Aggregator) buildFiles(ctx context.Context, step uint64) error {
 	mxStepTook.ObserveDuration(stepStartedAt)
 	a.integrateDirtyFiles(static, txFrom, txTo)
 	a.logger.Info("[snapshots] aggregated", "step", step, "took", time.Since(stepStartedAt))
+	diagnostics.Send(diagnostics.DomainAggregationUpdate{Phase: "build", Step: step, Finished: true, Took: time.Since(stepStartedAt).Seconds()})
 
 	return nil
 }
@@ -669,6 +691,12 @@ func (a *Aggregator) mergeLoopStep(ctx context.Context) (somethingDone bool, err
 	if !r.any() {
 		return false, nil
 	}
+	mergeStartedAt := time.Now()
+	fromStep, toStep := r.stepsRange()
+	diagnostics.Send(diagnostics.DomainAggregationUpdate{Phase: "merge", Step: fromStep, ToStep: toStep})
+	defer func() {
+		diagnostics.Send(diagnostics.DomainAggregationUpdate{Phase: "merge", Step: fromStep, ToStep: toStep, Finished: true, Took: time.Since(mergeStartedAt).Seconds()})
+	}()
 
 	outs, err := aggTx.staticFilesInRange(r)
 	defer func() {
@@ -711,6 +739,23 @@ func (a *Aggregator) MergeLoop(ctx context.Context) error {
 	}
 }
 
+// backgroundMergeLoop - same as MergeLoop, but merges are paced by the merge scheduler
+func (a *Aggregator) backgroundMergeLoop(ctx context.Context) error {
+	ctx = withMergeScheduler(ctx, a.merges)
+	for {
+		if err := a.merges.waitTurn(ctx); err != nil {
+			return err
+		}
+		somethingMerged, err := a.mergeLoopStep(ctx)
+		if err != nil {
+			return err
+		}
+		if !somethingMerged {
+			return nil
+		}
+	}
+}
+
 func (a *Aggregator) integrateDirtyFiles(sf AggV3StaticFiles, txNumFrom, txNumTo uint64) {
 	defer a.needSaveFilesListInDB.Store(true)
 	defer a.recalcVisibleFiles()
@@ -1247,6 +1292,31 @@ func (r RangesV3) String() string {
 	}
 	return strings.Join(ss, ", ")
 }
+
+// stepsRange returns the smallest range of steps covering all the merged files
+func (r RangesV3) stepsRange() (from, to uint64) {
+	from = math2.MaxUint64
+	cover := func(ok bool, startTxNum, endTxNum uint64) {
+		if ok {
+			from, to = min(from, startTxNum), max(to, endTxNum)
+		}
+	}
+	for _, d := range r.d {
+		cover(d.values, d.valuesStartTxNum, d.valuesEndTxNum)
+		cover(d.history, d.historyStartTxNum, d.historyEndTxNum)
+		cover(d.index, d.indexStartTxNum, d.indexEndTxNum)
+	}
+	cover(r.logAddrs, r.logAddrsStartTxNum, r.logAddrsEndTxNum)
+	cover(r.logTopics, r.logTopicsStartTxNum, r.logTopicsEndTxNum)
+	cover(r.tracesFrom, r.tracesFromStartTxNum, r.tracesFromEndTxNum)
+	cover(r.tracesTo, r.tracesToStartTxNum, r.tracesToEndTxNum)
+	if to == 0 {
+		return 0, 0
+	}
+	aggStep := r.d[kv.AccountsDomain].aggStep
+	return from / aggStep, to / aggStep
+}
+
 func (r RangesV3) any() bool {
 	for _, d := range r.d {
 		if d.any() {
@@ -1651,7 +1721,7 @@ func (a *Aggregator) BuildFilesInBackground(txNum uint64) chan struct{} {
 			//TODO: merge must have own semphore
 
 			defer func() { close(fin) }()
-			if err := a.MergeLoop(a.ctx); err != nil {
+			if err := a.backgroundMergeLoop(a.ctx); err != nil {
 				if errors.Is(err, context.Canceled) || errors.Is(err, common2.ErrStopped) {
 					return
 				}
@@ -1792,10 +1862,56 @@ func (ac *AggregatorRoTx) DomainRangeLatest(tx kv.Tx, domain kv.Domain, from, to
 	return ac.d[domain].DomainRangeLatest(tx, from, to, limit)
 }
 
+// DomainRangeSamples - see DomainRoTx.RangeSamples, for parallel processing of domain range by iter.PartitionKV
+func (ac *AggregatorRoTx) DomainRangeSamples(domain kv.Domain, from, to []byte, n int) ([][]byte, error) {
+	return ac.d[domain].RangeSamples(from, to, n)
+}
+
 func (ac *AggregatorRoTx) DomainGetAsOf(tx kv.Tx, name kv.Domain, key []byte, ts uint64) (v []byte, ok bool, err error) {
 	v, err = ac.d[name].GetAsOf(key, ts, tx)
 	return v, v != nil, err
 }
+
+// DomainGetAsOfBatch - DomainGetAsOf of many lookups at once. Lookups are resolved grouped by domain, then by the first
+// history file which may have them, then by key: so index and history pages of a file are touched once per batch instead
+// of once per lookup, and db cursors only move forward. Equal lookups are resolved once.
+func (ac *AggregatorRoTx) DomainGetAsOfBatch(tx kv.Tx, reqs []kv.DomainGetAsOfReq) (err error) {
+	type lookup struct {
+		i, file int
+	}
+	lookups := make([]lookup, len(reqs))
+	for i := range reqs {
+		lookups[i] = lookup{i: i, file: ac.d[reqs[i].Domain].ht.iit.firstFileAfter(reqs[i].Ts)}
+	}
+	sort.Slice(lookups, func(a, b int) bool {
+		ra, rb := &reqs[lookups[a].i], &reqs[lookups[b].i]
+		if ra.Domain != rb.Domain {
+			return ra.Domain < rb.Domain
+		}
+		if lookups[a].file != lookups[b].file {
+			return lookups[a].file < lookups[b].file
+		}
+		if c := bytes.Compare(ra.Key, rb.Key); c != 0 {
+			return c < 0
+		}
+		return ra.Ts < rb.Ts
+	})
+
+	var prev *kv.DomainGetAsOfReq
+	for _, l := range lookups {
+		r := &reqs[l.i]
+		if prev != nil && prev.Domain == r.Domain && prev.Ts == r.Ts && bytes.Equal(prev.Key, r.Key) {
+			r.V, r.Ok = prev.V, prev.Ok
+			continue
+		}
+		if r.V, r.Ok, err = ac.DomainGetAsOf(tx, r.Domain, r.Key, r.Ts); err != nil {
+			return err
+		}
+		prev = r
+	}
+	return nil
+}
+
 func (ac *AggregatorRoTx) GetLatest(domain kv.Domain, k, k2 []byte, tx kv.Tx) (v []byte, step uint64, ok bool, err error) {
 	return ac.d[domain].GetLatest(k, k2, tx)
 }
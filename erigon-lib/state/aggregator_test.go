@@ -1225,3 +1225,68 @@ func Test_helper_decodeAccountv3Bytes(t *testing.T) {
 	n, b, ch := types.DecodeAccountBytesV3(input)
 	fmt.Printf("input %x nonce %d balance %d codeHash %d\n", input, n, b.Uint64(), ch)
 }
+
+func TestAggregatorV3_DomainGetAsOfBatch(t *testing.T) {
+	aggStep := uint64(10)
+	db, agg := testDbAndAggregatorv3(t, aggStep)
+	ctx := context.Background()
+
+	tx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	ac := agg.BeginFilesRo()
+	defer ac.Close()
+	domains, err := NewSharedDomains(WrapTxWithCtx(tx, ac), log.New())
+	require.NoError(t, err)
+	defer domains.Close()
+
+	keys, _ := generateInputData(t, length.Addr, 16, 5)
+	txs := aggStep * 5
+	for txNum := uint64(1); txNum <= txs; txNum++ {
+		domains.SetTxNum(txNum)
+		addr := keys[txNum%uint64(len(keys))]
+		prev, step, err := domains.DomainGet(kv.AccountsDomain, addr, nil)
+		require.NoError(t, err)
+		buf := types.EncodeAccountBytesV3(txNum, uint256.NewInt(txNum*1000), nil, 0)
+		require.NoError(t, domains.DomainPut(kv.AccountsDomain, addr, nil, buf, prev, step))
+		prev, step, err = domains.DomainGet(kv.StorageDomain, addr, addr[:4])
+		require.NoError(t, err)
+		require.NoError(t, domains.DomainPut(kv.StorageDomain, addr, addr[:4], []byte{byte(txNum)}, prev, step))
+	}
+	require.NoError(t, domains.Flush(ctx, tx))
+	domains.Close()
+	ac.Close()
+	require.NoError(t, tx.Commit())
+	require.NoError(t, agg.BuildFiles(txs-aggStep))
+
+	roTx, err := db.BeginRo(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+	ac = agg.BeginFilesRo()
+	defer ac.Close()
+
+	var reqs []kv.DomainGetAsOfReq
+	for ts := uint64(0); ts <= txs+1; ts += 3 {
+		for _, k := range keys {
+			reqs = append(reqs,
+				kv.DomainGetAsOfReq{Domain: kv.AccountsDomain, Key: k, Ts: ts},
+				kv.DomainGetAsOfReq{Domain: kv.StorageDomain, Key: append(common.Copy(k), k[:4]...), Ts: ts},
+				kv.DomainGetAsOfReq{Domain: kv.AccountsDomain, Key: k, Ts: ts}, // duplicate
+			)
+		}
+	}
+	rand.New(rand.NewSource(0)).Shuffle(len(reqs), func(i, j int) { reqs[i], reqs[j] = reqs[j], reqs[i] })
+
+	require.NoError(t, ac.DomainGetAsOfBatch(roTx, reqs))
+	var found int
+	for _, r := range reqs {
+		v, ok, err := ac.DomainGetAsOf(roTx, r.Domain, r.Key, r.Ts)
+		require.NoError(t, err)
+		require.Equal(t, ok, r.Ok, "%s %x %d", r.Domain, r.Key, r.Ts)
+		require.Equal(t, v, r.V, "%s %x %d", r.Domain, r.Key, r.Ts)
+		if ok {
+			found++
+		}
+	}
+	require.NotZero(t, found)
+}
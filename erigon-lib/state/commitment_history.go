@@ -0,0 +1,163 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/ledgerwatch/erigon-lib/commitment"
+	"github.com/ledgerwatch/erigon-lib/common/cryptozerocopy"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+)
+
+var ErrCommitmentHistoryPruned = errors.New("commitment history is pruned")
+
+// historicalCommitmentContext - PatriciaContext reading the domains as of the end of txNum. Branches updated
+// by the trie are kept in memory, so the domains are never modified.
+type historicalCommitmentContext struct {
+	ac       *AggregatorRoTx
+	tx       kv.Tx
+	txNum    uint64
+	branches map[string][]byte
+	keccak   cryptozerocopy.KeccakState
+}
+
+func (hc *historicalCommitmentContext) GetBranch(prefix []byte) ([]byte, uint64, error) {
+	if v, ok := hc.branches[string(prefix)]; ok {
+		return v, 0, nil
+	}
+	cd := hc.ac.d[kv.CommitmentDomain]
+	v, ok, err := cd.ht.HistorySeek(prefix, hc.txNum+1, hc.tx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetBranch %x history: %w", prefix, err)
+	}
+	if ok {
+		return v, 0, nil
+	}
+	v, step, found, err := cd.getLatestFromDb(prefix, hc.tx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetBranch %x: %w", prefix, err)
+	}
+	if found {
+		return v, step, nil
+	}
+	v, found, startTx, endTx, err := cd.getFromFiles(prefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetBranch %x: %w", prefix, err)
+	}
+	if !found {
+		return nil, 0, nil
+	}
+	// replace shortened keys in the branch with full keys to allow HPH work seamlessly
+	v, err = hc.ac.replaceShortenedKeysInBranch(prefix, v, startTx, endTx)
+	return v, endTx / hc.ac.a.StepSize(), err
+}
+
+func (hc *historicalCommitmentContext) PutBranch(prefix []byte, data []byte, prevData []byte, prevStep uint64) error {
+	hc.branches[string(prefix)] = data
+	return nil
+}
+
+func (hc *historicalCommitmentContext) GetAccount(plainKey []byte, cell *commitment.Cell) error {
+	encAccount, _, err := hc.ac.DomainGetAsOf(hc.tx, kv.AccountsDomain, plainKey, hc.txNum+1)
+	if err != nil {
+		return fmt.Errorf("GetAccount failed: %w", err)
+	}
+	return fillAccountCell(cell, encAccount, hc.keccak, func() ([]byte, error) {
+		code, _, err := hc.ac.DomainGetAsOf(hc.tx, kv.CodeDomain, plainKey, hc.txNum+1)
+		if err != nil {
+			return nil, fmt.Errorf("GetAccount: failed to read code: %w", err)
+		}
+		return code, nil
+	})
+}
+
+func (hc *historicalCommitmentContext) GetStorage(plainKey []byte, cell *commitment.Cell) error {
+	enc, _, err := hc.ac.DomainGetAsOf(hc.tx, kv.StorageDomain, plainKey, hc.txNum+1)
+	if err != nil {
+		return err
+	}
+	fillStorageCell(cell, enc)
+	return nil
+}
+
+func (hc *historicalCommitmentContext) TempDir() string {
+	return hc.ac.a.dirs.Tmp
+}
+
+// CommitmentAsOf restores the commitment trie as of the end of txNum: the last commitment state stored
+// at or before txNum is restored and keys changed after it are replayed. The returned trie reads the
+// domains history, it may be used to generate proofs but must not be used to produce commitment.
+func (ac *AggregatorRoTx) CommitmentAsOf(ctx context.Context, tx kv.Tx, txNum uint64) (*commitment.HexPatriciaHashed, error) {
+	cd := ac.d[kv.CommitmentDomain]
+	_, stateTxNum, state, err := seekCommitmentState(tx, cd, 0, txNum)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no commitment state found at or before txNum %d", txNum)
+	}
+
+	// commitment history isn't stored in files, once pruned from db branches can't be read as of stateTxNum,
+	// unless they weren't changed since then
+	if stateTxNum+1 < cd.ht.iit.smallestTxNum(tx) {
+		latest, _, _, err := cd.GetLatest(keyCommitmentState, nil, tx)
+		if err != nil {
+			return nil, err
+		}
+		var cs commitmentState
+		if err := cs.Decode(latest); err != nil {
+			return nil, err
+		}
+		if cs.txNum != stateTxNum {
+			return nil, fmt.Errorf("%w: txNum %d", ErrCommitmentHistoryPruned, txNum)
+		}
+	}
+
+	var cs commitmentState
+	if err := cs.Decode(state); err != nil {
+		return nil, fmt.Errorf("failed to decode commitment state: %w", err)
+	}
+	hc := &historicalCommitmentContext{
+		ac:       ac,
+		tx:       tx,
+		txNum:    txNum,
+		branches: make(map[string][]byte),
+		keccak:   sha3.NewLegacyKeccak256().(cryptozerocopy.KeccakState),
+	}
+	trie := commitment.NewHexPatriciaHashed(length.Addr, hc)
+	if err := trie.SetState(cs.trieState); err != nil {
+		return nil, fmt.Errorf("failed restore state: %w", err)
+	}
+	if stateTxNum == txNum {
+		return trie, nil
+	}
+
+	// replay keys changed in (stateTxNum, txNum]
+	touched := make(map[string]struct{})
+	for _, h := range []kv.History{kv.AccountsHistory, kv.StorageHistory, kv.CodeHistory} {
+		it, err := ac.HistoryRange(h, int(stateTxNum+1), int(txNum+1), order.Asc, -1, tx)
+		if err != nil {
+			return nil, err
+		}
+		for it.HasNext() {
+			k, _, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+			touched[string(k)] = struct{}{}
+		}
+	}
+	plainKeys := make([][]byte, 0, len(touched))
+	for k := range touched {
+		plainKeys = append(plainKeys, []byte(k))
+	}
+	if _, err := trie.ProcessKeys(ctx, plainKeys, ""); err != nil {
+		return nil, fmt.Errorf("replay of %d keys: %w", len(plainKeys), err)
+	}
+	return trie, nil
+}
@@ -1719,6 +1719,61 @@ func (dt *DomainRoTx) DomainRangeLatest(roTx kv.Tx, fromKey, toKey []byte, limit
 	return fit, nil
 }
 
+// RangeSamples - about n keys of [fromKey, toKey) (nil means unbounded) at evenly spaced ordinals over all files,
+// so each of them stands for the same amount of data - boundaries for iter.SplitRange. Keys which are only in db are not sampled.
+func (dt *DomainRoTx) RangeSamples(fromKey, toKey []byte, n int) ([][]byte, error) {
+	type ordinals struct{ from, to uint64 }
+	spans := make([]ordinals, len(dt.files))
+	var total uint64
+	for i := range dt.files {
+		bt := dt.statelessBtree(i)
+		if bt.Empty() {
+			continue
+		}
+		g := dt.statelessGetter(i)
+		span := ordinals{to: bt.KeyCount()}
+		if fromKey != nil {
+			c, err := bt.Seek(g, fromKey)
+			if err != nil {
+				return nil, err
+			}
+			if c == nil { // all keys of file are before range
+				continue
+			}
+			span.from = c.Di()
+		}
+		if toKey != nil {
+			c, err := bt.Seek(g, toKey)
+			if err != nil {
+				return nil, err
+			}
+			if c != nil {
+				span.to = c.Di()
+			}
+		}
+		if span.to > span.from {
+			spans[i] = span
+			total += span.to - span.from
+		}
+	}
+	if total == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	stride := max(total/uint64(n), 1)
+	samples := make([][]byte, 0, n+len(spans))
+	for i, span := range spans {
+		for di := span.from + stride/2; di < span.to; di += stride {
+			c := dt.statelessBtree(i).OrdinalLookup(dt.statelessGetter(i), di)
+			if c == nil {
+				return nil, fmt.Errorf("%s: key %d not found in %s", dt.d.filenameBase, di, dt.files[i].src.decompressor.FileName())
+			}
+			samples = append(samples, c.Key())
+		}
+	}
+	return samples, nil
+}
+
 // CanPruneUntil returns true if domain OR history tables can be pruned until txNum
 func (dt *DomainRoTx) CanPruneUntil(tx kv.Tx, untilTx uint64) bool {
 	canDomain, _ := dt.canPruneDomainTables(tx, untilTx)
@@ -27,7 +27,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
-	"github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -326,7 +326,7 @@ func (sd *SharedDomains) LatestCommitment(prefix []byte) ([]byte, uint64, error)
 	}
 
 	// replace shortened keys in the branch with full keys to allow HPH work seamlessly
-	rv, err := sd.replaceShortenedKeysInBranch(prefix, commitment.BranchData(v), startTx, endTx)
+	rv, err := sd.aggTx.replaceShortenedKeysInBranch(prefix, commitment.BranchData(v), startTx, endTx)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -334,21 +334,21 @@ func (sd *SharedDomains) LatestCommitment(prefix []byte) ([]byte, uint64, error)
 }
 
 // replaceShortenedKeysInBranch replaces shortened keys in the branch with full keys
-func (sd *SharedDomains) replaceShortenedKeysInBranch(prefix []byte, branch commitment.BranchData, fStartTxNum uint64, fEndTxNum uint64) (commitment.BranchData, error) {
-	if !sd.aggTx.d[kv.CommitmentDomain].d.replaceKeysInValues && sd.aggTx.a.commitmentValuesTransform {
+func (ac *AggregatorRoTx) replaceShortenedKeysInBranch(prefix []byte, branch commitment.BranchData, fStartTxNum uint64, fEndTxNum uint64) (commitment.BranchData, error) {
+	if !ac.d[kv.CommitmentDomain].d.replaceKeysInValues && ac.a.commitmentValuesTransform {
 		panic("domain.replaceKeysInValues is disabled, but agg.commitmentValuesTransform is enabled")
 	}
 
-	if !sd.aggTx.a.commitmentValuesTransform ||
+	if !ac.a.commitmentValuesTransform ||
 		len(branch) == 0 ||
-		sd.aggTx.minimaxTxNumInDomainFiles(false) == 0 ||
+		ac.minimaxTxNumInDomainFiles(false) == 0 ||
 		bytes.Equal(prefix, keyCommitmentState) {
 
 		return branch, nil // do not transform, return as is
 	}
 
-	sto := sd.aggTx.d[kv.StorageDomain]
-	acc := sd.aggTx.d[kv.AccountsDomain]
+	sto := ac.d[kv.StorageDomain]
+	acc := ac.d[kv.AccountsDomain]
 	storageItem := sto.lookupFileByItsRange(fStartTxNum, fEndTxNum)
 	accountItem := acc.lookupFileByItsRange(fStartTxNum, fEndTxNum)
 	storageGetter := NewArchiveGetter(storageItem.decompressor.MakeGetter(), sto.d.compression)
@@ -363,8 +363,8 @@ func (sd *SharedDomains) replaceShortenedKeysInBranch(prefix []byte, branch comm
 			// Optimised key referencing a state file record (file number and offset within the file)
 			storagePlainKey, found := sto.lookupByShortenedKey(key, storageGetter)
 			if !found {
-				s0, s1 := fStartTxNum/sd.aggTx.a.StepSize(), fEndTxNum/sd.aggTx.a.StepSize()
-				sd.logger.Crit("replace back lost storage full key", "shortened", fmt.Sprintf("%x", key),
+				s0, s1 := fStartTxNum/ac.a.StepSize(), fEndTxNum/ac.a.StepSize()
+				ac.a.logger.Crit("replace back lost storage full key", "shortened", fmt.Sprintf("%x", key),
 					"decoded", fmt.Sprintf("step %d-%d; offt %d", s0, s1, decodeShorterKey(key)))
 				return nil, fmt.Errorf("replace back lost storage full key: %x", key)
 			}
@@ -377,8 +377,8 @@ func (sd *SharedDomains) replaceShortenedKeysInBranch(prefix []byte, branch comm
 
 		apkBuf, found := acc.lookupByShortenedKey(key, accountGetter)
 		if !found {
-			s0, s1 := fStartTxNum/sd.aggTx.a.StepSize(), fEndTxNum/sd.aggTx.a.StepSize()
-			sd.logger.Crit("replace back lost account full key", "shortened", fmt.Sprintf("%x", key),
+			s0, s1 := fStartTxNum/ac.a.StepSize(), fEndTxNum/ac.a.StepSize()
+			ac.a.logger.Crit("replace back lost account full key", "shortened", fmt.Sprintf("%x", key),
 				"decoded", fmt.Sprintf("step %d-%d; offt %d", s0, s1, decodeShorterKey(key)))
 			return nil, fmt.Errorf("replace back lost account full key: %x", key)
 		}
@@ -571,6 +571,12 @@ func (sd *SharedDomains) ComputeCommitment(ctx context.Context, saveStateAfter b
 	return sd.sdCtx.ComputeCommitment(ctx, saveStateAfter, blockNum, logPrefix)
 }
 
+// PrecomputeCommitment - starts background preparation of keys touched so far for the next ComputeCommitment.
+// Safe to call after each block: it doesn't read state, so doesn't interfere with execution of next blocks.
+func (sd *SharedDomains) PrecomputeCommitment() {
+	sd.sdCtx.updates.Precompute()
+}
+
 // IterateStoragePrefix iterates over key-value pairs of the storage domain that start with given prefix
 // Such iteration is not intended to be used in public API, therefore it uses read-write transaction
 // inside the domain. Another version of this for public API use needs to be created, that uses
@@ -817,6 +823,20 @@ func (sd *SharedDomains) DomainGet(domain kv.Domain, k, k2 []byte) (v []byte, st
 	return v, step, nil
 }
 
+// DomainGetWithTx - same as DomainGet, but values which are not in RAM are read by given `tx` (and it's AggTx).
+// Allows concurrent readers (each one with own tx) - while nobody writes to SharedDomains.
+// `tx` must see same state as sd.roTx does (for example: RoTx opened right after commit)
+func (sd *SharedDomains) DomainGetWithTx(tx kv.Tx, domain kv.Domain, k []byte) (v []byte, step uint64, err error) {
+	if v, ok := sd.get(domain, k); ok {
+		return v, 0, nil
+	}
+	v, step, _, err = tx.(HasAggTx).AggTx().(*AggregatorRoTx).GetLatest(domain, k, nil, tx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage %x read error: %w", k, err)
+	}
+	return v, step, nil
+}
+
 // DomainPut
 // Optimizations:
 //   - user can prvide `prevVal != nil` - then it will not read prev value from storage
@@ -996,10 +1016,25 @@ func (sdc *SharedDomainsCommitmentContext) GetAccount(plainKey []byte, cell *com
 	if err != nil {
 		return fmt.Errorf("GetAccount failed: %w", err)
 	}
+	return fillAccountCell(cell, encAccount, sdc.keccak, func() ([]byte, error) {
+		code, _, err := sdc.sd.DomainGet(kv.CodeDomain, plainKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("GetAccount: failed to read latest code: %w", err)
+		}
+		return code, nil
+	})
+}
+
+// fillAccountCell sets account fields of the cell from encoded account, code hash is computed from the code if the
+// account has one
+func fillAccountCell(cell *commitment.Cell, encAccount []byte, keccak cryptozerocopy.KeccakState, readCode func() ([]byte, error)) error {
 	cell.Nonce = 0
 	cell.Balance.Clear()
 	if len(encAccount) > 0 {
-		nonce, balance, chash := types.DecodeAccountBytesV3(encAccount)
+		nonce, balance, chash, _, err := statecodec.Active().DecodeAccount(encAccount)
+		if err != nil {
+			return fmt.Errorf("GetAccount: %w", err)
+		}
 		cell.Nonce = nonce
 		cell.Balance.Set(balance)
 		if len(chash) > 0 {
@@ -1011,14 +1046,14 @@ func (sdc *SharedDomainsCommitmentContext) GetAccount(plainKey []byte, cell *com
 		return nil
 	}
 
-	code, _, err := sdc.sd.DomainGet(kv.CodeDomain, plainKey, nil)
+	code, err := readCode()
 	if err != nil {
-		return fmt.Errorf("GetAccount: failed to read latest code: %w", err)
+		return err
 	}
 	if len(code) > 0 {
-		sdc.keccak.Reset()
-		sdc.keccak.Write(code)
-		sdc.keccak.Read(cell.CodeHash[:])
+		keccak.Reset()
+		keccak.Write(code)
+		keccak.Read(cell.CodeHash[:])
 	} else {
 		cell.CodeHash = commitment.EmptyCodeHashArray
 	}
@@ -1032,10 +1067,15 @@ func (sdc *SharedDomainsCommitmentContext) GetStorage(plainKey []byte, cell *com
 	if err != nil {
 		return err
 	}
+	fillStorageCell(cell, enc)
+	return nil
+}
+
+func fillStorageCell(cell *commitment.Cell, enc []byte) {
+	enc = statecodec.Active().DecodeStorage(enc)
 	cell.StorageLen = len(enc)
 	copy(cell.Storage[:], enc)
 	cell.Delete = cell.StorageLen == 0
-	return nil
 }
 
 func (sdc *SharedDomainsCommitmentContext) Reset() {
@@ -1194,7 +1234,11 @@ func (sdc *SharedDomainsCommitmentContext) LatestCommitmentState(tx kv.Tx, cd *D
 	if sdc.patriciaTrie.Variant() != commitment.VariantHexPatriciaTrie {
 		return 0, 0, nil, fmt.Errorf("state storing is only supported hex patricia trie")
 	}
+	return seekCommitmentState(tx, cd, sinceTx, untilTx)
+}
 
+// seekCommitmentState [sinceTx, untilTx] searches for last encoded commitment state in the domain and its history
+func seekCommitmentState(tx kv.Tx, cd *DomainRoTx, sinceTx, untilTx uint64) (blockNum, txNum uint64, state []byte, err error) {
 	// Domain storing only 1 latest commitment (for each step). Erigon can unwind behind this - it means we must look into History (instead of Domain)
 	// IdxRange: looking into DB and Files (.ef). Using `order.Desc` to find latest txNum with commitment
 	it, err := cd.ht.IdxRange(keyCommitmentState, int(untilTx), int(sinceTx)-1, order.Desc, -1, tx) //[from, to)
@@ -745,6 +745,57 @@ func TestDomain_ScanFiles(t *testing.T) {
 	checkHistory(t, db, d, txs)
 }
 
+func TestDomain_RangeSamples(t *testing.T) {
+	logger := log.New()
+	db, d, txs := filledDomain(t, logger)
+	collateAndMerge(t, db, nil, d, txs)
+
+	tx, err := db.BeginRo(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	dc := d.BeginFilesRo()
+	defer dc.Close()
+
+	key := func(i uint64) []byte {
+		k := make([]byte, 8)
+		binary.BigEndian.PutUint64(k, i)
+		return k
+	}
+	from, to := key(3), key(28)
+	samples, err := dc.RangeSamples(from, to, 4)
+	require.NoError(t, err)
+	require.NotEmpty(t, samples)
+	for _, k := range samples {
+		require.True(t, bytes.Compare(k, from) >= 0 && bytes.Compare(k, to) < 0, "%x", k)
+	}
+
+	samples, err = dc.RangeSamples(key(32), nil, 4)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+
+	// parts of the range together are the whole range
+	samples, err = dc.RangeSamples(nil, nil, 4)
+	require.NoError(t, err)
+	parts, err := iter.PartitionKV(nil, nil, 4, samples, func(from, to []byte) (iter.KV, error) {
+		return dc.DomainRangeLatest(tx, from, to, -1)
+	})
+	require.NoError(t, err)
+	require.Greater(t, len(parts), 1)
+	var keys [][]byte
+	for _, part := range parts {
+		for part.HasNext() {
+			k, _, err := part.Next()
+			require.NoError(t, err)
+			keys = append(keys, common.Copy(k))
+		}
+		part.Close()
+	}
+	require.Equal(t, 31, len(keys))
+	for i, k := range keys {
+		require.Equal(t, key(uint64(i+1)), k)
+	}
+}
+
 func TestDomain_Delete(t *testing.T) {
 
 	logger := log.New()
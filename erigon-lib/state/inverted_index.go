@@ -29,6 +29,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -561,6 +562,11 @@ func (iit *InvertedIndexRoTx) seekInFiles(key []byte, txNum uint64) (found bool,
 	return false, 0
 }
 
+// firstFileAfter - index of the first file seekInFiles looks into for txNum
+func (iit *InvertedIndexRoTx) firstFileAfter(txNum uint64) int {
+	return sort.Search(len(iit.files), func(i int) bool { return iit.files[i].endTxNum > txNum })
+}
+
 // it is assumed files are always sorted
 func (iit *InvertedIndexRoTx) lastTxNumInFiles() uint64 {
 	return iit.files[len(iit.files)-1].endTxNum
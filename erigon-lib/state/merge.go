@@ -503,6 +503,7 @@ func (dt *DomainRoTx) mergeFiles(ctx context.Context, domainFiles, indexFiles, h
 		return
 	}
 
+	merges := mergeSchedulerFrom(ctx)
 	closeItem := true
 	var kvWriter ArchiveWriter
 	defer func() {
@@ -613,6 +614,9 @@ func (dt *DomainRoTx) mergeFiles(ctx context.Context, domainFiles, indexFiles, h
 				if err = kvWriter.AddWord(valBuf); err != nil {
 					return nil, nil, nil, err
 				}
+				if err = merges.throttle(ctx, len(keyBuf)+len(valBuf)); err != nil {
+					return nil, nil, nil, err
+				}
 			}
 			keyBuf = append(keyBuf[:0], lastKey...)
 			valBuf = append(valBuf[:0], lastVal...)
@@ -683,6 +687,7 @@ func (iit *InvertedIndexRoTx) mergeFiles(ctx context.Context, files []*filesItem
 		defer h.decompressor.EnableReadAhead().DisableReadAhead()
 	}
 
+	merges := mergeSchedulerFrom(ctx)
 	var outItem *filesItem
 	var comp *seg.Compressor
 	var decomp *seg.Decompressor
@@ -775,6 +780,9 @@ func (iit *InvertedIndexRoTx) mergeFiles(ctx context.Context, files []*filesItem
 			if err = write.AddWord(valBuf); err != nil {
 				return nil, err
 			}
+			if err = merges.throttle(ctx, len(keyBuf)+len(valBuf)); err != nil {
+				return nil, err
+			}
 		}
 		keyBuf = append(keyBuf[:0], lastKey...)
 		if keyBuf == nil {
@@ -818,6 +826,7 @@ func (ht *HistoryRoTx) mergeFiles(ctx context.Context, indexFiles, historyFiles
 	if !r.any() {
 		return nil, nil, nil
 	}
+	merges := mergeSchedulerFrom(ctx)
 	var closeIndex = true
 	defer func() {
 		if closeIndex {
@@ -925,6 +934,9 @@ func (ht *HistoryRoTx) mergeFiles(ctx context.Context, indexFiles, historyFiles
 					if err = compr.AddWord(valBuf); err != nil {
 						return nil, nil, err
 					}
+					if err = merges.throttle(ctx, len(valBuf)); err != nil {
+						return nil, nil, err
+					}
 				}
 				// fmt.Printf("fput '%x'->%x\n", lastKey, ci1.val)
 				keyCount += int(count)
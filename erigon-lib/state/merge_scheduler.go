@@ -0,0 +1,161 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// mergeThrottleChunk - merges account written bytes in chunks, to not touch the limiter for every word
+const mergeThrottleChunk = 256 * 1024
+
+// mergeScheduler paces background merges of domain/history/index files, so they don't compete with
+// block execution for IO on slow disks:
+//   - merges can be paused and resumed (for example by operator's RPC call)
+//   - a merge can wait for an idle period: for the foreground work (block execution) to be done, but not longer
+//     than idleWait - files still have to be merged during long executions (initial sync)
+//   - bytes written by merges are throttled to the IO budget
+//
+// Merges requested explicitly (MergeLoop) are not scheduled.
+type mergeScheduler struct {
+	limiter  *rate.Limiter
+	pending  atomic.Int64 // bytes written by merges, not accounted by the limiter yet
+	idleWait atomic.Int64 // time.Duration, 0 - merges don't wait for idle periods
+	busy     atomic.Int32 // foreground work in progress
+
+	mu      sync.Mutex
+	paused  bool
+	resumed chan struct{} // closed on resume
+}
+
+func newMergeScheduler() *mergeScheduler {
+	return &mergeScheduler{limiter: rate.NewLimiter(rate.Inf, mergeThrottleChunk)}
+}
+
+// setIOLimit - bytesPerSecond 0 means unlimited
+func (s *mergeScheduler) setIOLimit(bytesPerSecond uint64) {
+	if bytesPerSecond == 0 {
+		s.limiter.SetLimit(rate.Inf)
+		return
+	}
+	s.limiter.SetBurst(int(max(bytesPerSecond, mergeThrottleChunk)))
+	s.limiter.SetLimit(rate.Limit(bytesPerSecond))
+}
+
+func (s *mergeScheduler) ioLimit() uint64 {
+	if s.limiter.Limit() == rate.Inf {
+		return 0
+	}
+	return uint64(s.limiter.Limit())
+}
+
+func (s *mergeScheduler) pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.paused {
+		s.paused = true
+		s.resumed = make(chan struct{})
+	}
+}
+
+func (s *mergeScheduler) resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused {
+		s.paused = false
+		close(s.resumed)
+	}
+}
+
+func (s *mergeScheduler) isPaused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+func (s *mergeScheduler) waitResumed(ctx context.Context) error {
+	s.mu.Lock()
+	paused, resumed := s.paused, s.resumed
+	s.mu.Unlock()
+	if !paused {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumed:
+		return nil
+	}
+}
+
+// startForegroundWork marks foreground work in progress until the returned func is called
+func (s *mergeScheduler) startForegroundWork() (finish func()) {
+	s.busy.Add(1)
+	var once sync.Once
+	return func() { once.Do(func() { s.busy.Add(-1) }) }
+}
+
+// waitTurn is called before each merge: it waits for merges to be resumed, and for an idle period
+func (s *mergeScheduler) waitTurn(ctx context.Context) error {
+	if err := s.waitResumed(ctx); err != nil {
+		return err
+	}
+	idleWait := time.Duration(s.idleWait.Load())
+	if idleWait <= 0 || s.busy.Load() == 0 {
+		return nil
+	}
+
+	deadline := time.NewTimer(idleWait)
+	defer deadline.Stop()
+	check := time.NewTicker(time.Second)
+	defer check.Stop()
+	for s.busy.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return s.waitResumed(ctx)
+		case <-check.C:
+		}
+	}
+	return s.waitResumed(ctx)
+}
+
+// throttle accounts n bytes written by a merge, it blocks while merges are paused or over the IO budget.
+// nil scheduler doesn't throttle.
+func (s *mergeScheduler) throttle(ctx context.Context, n int) error {
+	if s == nil || s.pending.Add(int64(n)) < mergeThrottleChunk {
+		return nil
+	}
+	written := s.pending.Swap(0)
+	if err := s.waitResumed(ctx); err != nil {
+		return err
+	}
+	if s.limiter.Limit() == rate.Inf {
+		return nil
+	}
+	for written > 0 {
+		chunk := min(written, int64(s.limiter.Burst()))
+		if err := s.limiter.WaitN(ctx, int(chunk)); err != nil {
+			return err
+		}
+		written -= chunk
+	}
+	return nil
+}
+
+type mergeSchedulerKey struct{}
+
+// withMergeScheduler - merges running with the returned context are throttled by s
+func withMergeScheduler(ctx context.Context, s *mergeScheduler) context.Context {
+	return context.WithValue(ctx, mergeSchedulerKey{}, s)
+}
+
+// mergeSchedulerFrom returns the scheduler throttling merges in ctx, nil if they are not throttled
+func mergeSchedulerFrom(ctx context.Context) *mergeScheduler {
+	s, _ := ctx.Value(mergeSchedulerKey{}).(*mergeScheduler)
+	return s
+}
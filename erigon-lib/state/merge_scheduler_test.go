@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSchedulerPause(t *testing.T) {
+	ctx := context.Background()
+	s := newMergeScheduler()
+	s.pause()
+	require.True(t, s.isPaused())
+
+	done := make(chan error, 1)
+	go func() { done <- s.throttle(ctx, mergeThrottleChunk) }()
+	select {
+	case <-done:
+		t.Fatal("paused merge must not write")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.resume()
+	require.NoError(t, <-done)
+	require.False(t, s.isPaused())
+
+	// cancellation unblocks a paused merge
+	s.pause()
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, s.waitTurn(cancelledCtx), context.Canceled)
+}
+
+func TestMergeSchedulerIOLimit(t *testing.T) {
+	ctx := context.Background()
+	s := newMergeScheduler()
+	require.NoError(t, s.throttle(ctx, 100*mergeThrottleChunk), "unlimited by default")
+
+	s.setIOLimit(4 * mergeThrottleChunk)
+	require.EqualValues(t, 4*mergeThrottleChunk, s.ioLimit())
+	start := time.Now()
+	// a second of burst, then the budget allows a chunk each 250ms
+	for i := 0; i < 8; i++ {
+		require.NoError(t, s.throttle(ctx, mergeThrottleChunk))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+
+	s.setIOLimit(0)
+	require.Zero(t, s.ioLimit())
+}
+
+func TestMergeSchedulerIdleWait(t *testing.T) {
+	ctx := context.Background()
+	s := newMergeScheduler()
+
+	finish := s.startForegroundWork()
+	require.NoError(t, s.waitTurn(ctx), "merges don't wait for idle periods by default")
+
+	s.idleWait.Store(int64(time.Minute))
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		finish()
+		finish() // idempotent
+	}()
+	start := time.Now()
+	require.NoError(t, s.waitTurn(ctx))
+	require.Less(t, time.Since(start), 30*time.Second)
+	require.Zero(t, s.busy.Load())
+
+	// long foreground work doesn't postpone merges for more than idleWait
+	s.idleWait.Store(int64(200 * time.Millisecond))
+	defer s.startForegroundWork()()
+	start = time.Now()
+	require.NoError(t, s.waitTurn(ctx))
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
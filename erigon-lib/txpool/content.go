@@ -0,0 +1,154 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// ContentFilter - selects transactions of TxPool.Content, zero value selects all of them
+type ContentFilter struct {
+	Senders []common.Address // any of them, all senders if empty
+	MinTip  *uint256.Int     // min tip (max priority fee per gas) of transaction
+	Types   []byte           // any of them, all types if empty
+	Cursor  []byte           // next cursor returned by previous page, from the start if empty
+	Limit   int              // max amount of transactions in page, all if 0
+}
+
+func (f *ContentFilter) match(slot *types.TxSlot) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, slot.Type) {
+		return false
+	}
+	if f.MinTip != nil && slot.Tip.Lt(f.MinTip) {
+		return false
+	}
+	return true
+}
+
+// contentCursor - position of transaction in the pool: txs are iterated by senderID, then by nonce.
+// Sender IDs are not re-assigned while the pool is running, so pages don't overlap even if the pool changes between
+// them. Cursor is opaque for clients.
+type contentCursor struct {
+	senderID, nonce uint64
+}
+
+func (c contentCursor) encode() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, c.senderID)
+	binary.BigEndian.PutUint64(b[8:], c.nonce)
+	return b
+}
+
+func decodeContentCursor(b []byte) (contentCursor, error) {
+	if len(b) == 0 {
+		return contentCursor{}, nil
+	}
+	if len(b) != 16 {
+		return contentCursor{}, fmt.Errorf("invalid content cursor: %x", b)
+	}
+	return contentCursor{senderID: binary.BigEndian.Uint64(b), nonce: binary.BigEndian.Uint64(b[8:])}, nil
+}
+
+// Content - iterates over transactions selected by filter, page by page. Returns cursor of the next page,
+// nil if there are no more transactions.
+func (p *TxPool) Content(tx kv.Tx, filter ContentFilter, f func(rlp []byte, sender common.Address, t SubPoolType)) (next []byte, err error) {
+	from, err := decodeContentCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var count int
+	visit := func(mt *metaTx) bool {
+		if !filter.match(mt.Tx) {
+			return true
+		}
+		if filter.Limit > 0 && count == filter.Limit {
+			next = contentCursor{senderID: mt.Tx.SenderID, nonce: mt.Tx.Nonce}.encode()
+			return false
+		}
+		sender, found := p.senders.senderID2Addr[mt.Tx.SenderID]
+		if !found {
+			return true
+		}
+		rlp, err := p.slotRlp(tx, mt.Tx)
+		if err != nil {
+			p.logger.Warn("[txpool] content: get tx from db", "err", err)
+			return true
+		}
+		if rlp == nil {
+			return true
+		}
+		f(rlp, sender, mt.currentSubPool)
+		count++
+		return true
+	}
+
+	if len(filter.Senders) == 0 {
+		p.all.ascendFrom(from.senderID, from.nonce, visit)
+		return next, nil
+	}
+
+	senderIDs := make([]uint64, 0, len(filter.Senders))
+	for _, addr := range filter.Senders {
+		if id, ok := p.senders.getID(addr); ok && id >= from.senderID {
+			senderIDs = append(senderIDs, id)
+		}
+	}
+	slices.Sort(senderIDs)
+	for _, id := range slices.Compact(senderIDs) {
+		var nonce uint64
+		if id == from.senderID {
+			nonce = from.nonce
+		}
+		p.all.ascendFrom(id, nonce, func(mt *metaTx) bool {
+			if mt.Tx.SenderID != id {
+				return false
+			}
+			return visit(mt)
+		})
+		if next != nil {
+			break
+		}
+	}
+	return next, nil
+}
+
+// slotRlp - rlp of pool transaction, from db if it's not kept in memory. Returns nil if it's not found.
+func (p *TxPool) slotRlp(tx kv.Tx, slot *types.TxSlot) ([]byte, error) {
+	if slot.Rlp != nil {
+		return slot.Rlp, nil
+	}
+	v, err := tx.GetOne(kv.PoolTransaction, slot.IDHash[:])
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	return v[20:], nil
+}
@@ -0,0 +1,134 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+)
+
+// PoolEvent - transaction was added to the pool or dropped from it
+type PoolEvent struct {
+	Dropped bool
+	Reason  txpoolcfg.DiscardReason // why transaction was dropped
+	Hash    common.Hash
+	Sender  common.Address
+	Rlp     []byte // of added transaction, nil if it's not kept in memory
+}
+
+// poolEvents - fan-out of PoolEvent to subscribers. Events are collected only while there are subscribers
+// and are sent in batches by MainLoop. Subscriber which doesn't keep up is unsubscribed (its channel is closed),
+// instead of slowing down the pool or silently missing events.
+type poolEvents struct {
+	mu      sync.Mutex
+	subs    map[uint]chan []PoolEvent
+	id      uint
+	batch   []PoolEvent
+	enabled atomic.Bool
+}
+
+func newPoolEvents() *poolEvents {
+	return &poolEvents{subs: map[uint]chan []PoolEvent{}}
+}
+
+func (e *poolEvents) subscribe(size int) (<-chan []PoolEvent, func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.id++
+	id := e.id
+	ch := make(chan []PoolEvent, size)
+	e.subs[id] = ch
+	e.enabled.Store(true)
+	return ch, func() { e.unsubscribe(id) }
+}
+
+func (e *poolEvents) unsubscribe(id uint) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.unsubscribeLocked(id)
+}
+
+func (e *poolEvents) unsubscribeLocked(id uint) {
+	ch, ok := e.subs[id]
+	if !ok { // double-unsubscribe support
+		return
+	}
+	delete(e.subs, id)
+	close(ch)
+	if len(e.subs) == 0 {
+		e.enabled.Store(false)
+		e.batch = nil
+	}
+}
+
+func (e *poolEvents) add(ev PoolEvent) {
+	if !e.enabled.Load() {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.subs) == 0 {
+		return
+	}
+	e.batch = append(e.batch, ev)
+}
+
+func (e *poolEvents) flush(logger log.Logger) {
+	if !e.enabled.Load() {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.batch) == 0 {
+		return
+	}
+	batch := e.batch
+	e.batch = nil
+	for id, ch := range e.subs {
+		select {
+		case ch <- batch:
+		default:
+			logger.Debug("[txpool] pool events subscriber is too slow, unsubscribing")
+			e.unsubscribeLocked(id)
+		}
+	}
+}
+
+// SubscribeEvents - batches of additions and drops of pool transactions, in order of their happening.
+// Channel is closed when subscriber doesn't keep up with the pool or on unsubscribe.
+func (p *TxPool) SubscribeEvents(size int) (<-chan []PoolEvent, func()) {
+	return p.events.subscribe(size)
+}
+
+func (p *TxPool) onAddedLocked(mt *metaTx) {
+	if !p.events.enabled.Load() {
+		return
+	}
+	p.events.add(PoolEvent{Hash: mt.Tx.IDHash, Sender: p.senders.senderID2Addr[mt.Tx.SenderID], Rlp: mt.Tx.Rlp})
+}
+
+func (p *TxPool) onDroppedLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
+	if !p.events.enabled.Load() {
+		return
+	}
+	p.events.add(PoolEvent{Dropped: true, Reason: reason, Hash: mt.Tx.IDHash, Sender: p.senders.senderID2Addr[mt.Tx.SenderID]})
+}
@@ -0,0 +1,227 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// localsJournal - txs submitted to this node (AddLocalTxs). Journaled txs are persisted in kv.PoolLocalsJournal,
+// re-broadcast every cfg.RebroadcastLocalsEvery and never evicted by sub pool overflow - until they leave the pool
+// (included into a block, replaced, ...) or cfg.LocalsLifetime passes.
+type localsJournal struct {
+	byHash  map[string]*localTx
+	deleted []string // hashes removed since last flush
+}
+
+type localTx struct {
+	sender common.Address
+	added  uint64 // unix time of submission
+	rlp    []byte // nil once persisted
+}
+
+func newLocalsJournal() *localsJournal {
+	return &localsJournal{byHash: map[string]*localTx{}}
+}
+
+func (j *localsJournal) has(hash string) bool {
+	_, ok := j.byHash[hash]
+	return ok
+}
+
+func (j *localsJournal) add(hash string, sender common.Address, added uint64, rlp []byte) {
+	j.byHash[hash] = &localTx{sender: sender, added: added, rlp: rlp}
+}
+
+func (j *localsJournal) remove(hash string) {
+	if _, ok := j.byHash[hash]; !ok {
+		return
+	}
+	delete(j.byHash, hash)
+	j.deleted = append(j.deleted, hash)
+}
+
+// expire removes the txs submitted before `before`
+func (j *localsJournal) expire(before uint64) (expired int) {
+	for hash, ltx := range j.byHash {
+		if ltx.added < before {
+			j.remove(hash)
+			expired++
+		}
+	}
+	return expired
+}
+
+func (j *localsJournal) flush(tx kv.RwTx) error {
+	for _, hash := range j.deleted {
+		if err := tx.Delete(kv.PoolLocalsJournal, []byte(hash)); err != nil {
+			return err
+		}
+	}
+	for hash, ltx := range j.byHash {
+		if ltx.rlp == nil {
+			continue
+		}
+		if err := tx.Put(kv.PoolLocalsJournal, []byte(hash), encodeLocalTx(ltx)); err != nil {
+			return err
+		}
+	}
+	// clean in-memory state only after all writes succeeded
+	for _, ltx := range j.byHash {
+		ltx.rlp = nil
+	}
+	j.deleted = j.deleted[:0]
+	return nil
+}
+
+func encodeLocalTx(ltx *localTx) []byte {
+	v := make([]byte, 8+length.Addr+len(ltx.rlp))
+	binary.BigEndian.PutUint64(v, ltx.added)
+	copy(v[8:], ltx.sender[:])
+	copy(v[8+length.Addr:], ltx.rlp)
+	return v
+}
+
+func decodeLocalTx(v []byte) (*localTx, error) {
+	if len(v) < 8+length.Addr {
+		return nil, fmt.Errorf("locals journal: record too short: %d", len(v))
+	}
+	ltx := &localTx{added: binary.BigEndian.Uint64(v), rlp: v[8+length.Addr:]}
+	copy(ltx.sender[:], v[8:])
+	return ltx, nil
+}
+
+// journalLocalLocked - journals a local tx which made it into the pool, resubmitted txs keep their submission time
+func (p *TxPool) journalLocalLocked(txn *types.TxSlot, sender []byte, now uint64) {
+	hash := string(txn.IDHash[:])
+	if p.locals.has(hash) {
+		return
+	}
+	p.locals.add(hash, *(*[20]byte)(sender), now, txn.Rlp)
+}
+
+// isJournaledLocked - journaled txs are exempt from sub pool overflow eviction
+func (p *TxPool) isJournaledLocked(mt *metaTx) bool {
+	return p.locals.has(string(mt.Tx.IDHash[:]))
+}
+
+// expireLocals - txs journaled longer than cfg.LocalsLifetime stay in the pool as regular txs
+func (p *TxPool) expireLocals(now time.Time) {
+	if p.cfg.LocalsLifetime <= 0 {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if expired := p.locals.expire(uint64(now.Add(-p.cfg.LocalsLifetime).Unix())); expired > 0 {
+		p.logger.Debug("[txpool] local txs expired", "count", expired, "journaled", len(p.locals.byHash))
+	}
+}
+
+// appendJournaledAnnouncements - journaled txs which can be included into the next blocks, to re-broadcast
+func (p *TxPool) appendJournaledAnnouncements(announcements *types.Announcements) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for hash := range p.locals.byHash {
+		mt, ok := p.byHash[hash]
+		if !ok || (mt.currentSubPool != PendingSubPool && mt.currentSubPool != BaseFeeSubPool) {
+			continue
+		}
+		announcements.Append(mt.Tx.Type, mt.Tx.Size, mt.Tx.IDHash[:])
+	}
+}
+
+// rebroadcastLocals - sends journaled txs to peers again, with priority of fresh local txs
+func (p *TxPool) rebroadcastLocals(ctx context.Context, db kv.RoDB, send *Send) {
+	var announcements types.Announcements
+	p.appendJournaledAnnouncements(&announcements)
+	if announcements.Len() == 0 {
+		return
+	}
+
+	var (
+		txTypes         []byte
+		txSizes         []uint32
+		txHashes        types.Hashes
+		txRlps          [][]byte
+		broadcastHashes types.Hashes
+	)
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		for i := 0; i < announcements.Len(); i++ {
+			t, size, hash := announcements.At(i)
+			slotRlp, err := p.GetRlp(tx, hash)
+			if err != nil {
+				return err
+			}
+			if len(slotRlp) == 0 {
+				continue
+			}
+			txTypes = append(txTypes, t)
+			txSizes = append(txSizes, size)
+			txHashes = append(txHashes, hash...)
+			// "Nodes MUST NOT automatically broadcast blob transactions to their peers" - EIP-4844
+			if t == types.BlobTxType {
+				continue
+			}
+			if slotRlp, err = types.UnwrapTxPlayloadRlp(slotRlp); err != nil {
+				continue
+			}
+			txRlps = append(txRlps, slotRlp)
+			broadcastHashes = append(broadcastHashes, hash...)
+		}
+		return nil
+	}); err != nil {
+		p.logger.Warn("[txpool] collect local txs to rebroadcast", "err", err)
+		return
+	}
+
+	send.BroadcastPooledTxs(txRlps, localTxsBroadcastMaxPeers)
+	send.AnnouncePooledTxs(txTypes, txSizes, txHashes, localTxsBroadcastMaxPeers*2)
+	p.logger.Debug("[txpool] local txs rebroadcast", "broadcast", broadcastHashes.Len(), "announced", txHashes.Len())
+}
+
+// Locals - journaled local txs
+func (p *TxPool) Locals(tx kv.Tx, f func(sender common.Address, rlp []byte, added uint64)) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for hash, ltx := range p.locals.byHash {
+		rlp := ltx.rlp
+		if rlp == nil {
+			v, err := tx.GetOne(kv.PoolLocalsJournal, []byte(hash))
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				continue
+			}
+			persisted, err := decodeLocalTx(v)
+			if err != nil {
+				return err
+			}
+			rlp = persisted.rlp
+		}
+		f(ltx.sender, rlp, ltx.added)
+	}
+	return nil
+}
@@ -45,7 +45,6 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/cmp"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
-	"github.com/ledgerwatch/erigon-lib/common/u256"
 	libkzg "github.com/ledgerwatch/erigon-lib/crypto/kzg"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
@@ -58,6 +57,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 	"github.com/ledgerwatch/erigon-lib/types"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -132,6 +132,7 @@ type metaTx struct {
 	subPool                   SubPoolMarker
 	currentSubPool            SubPoolType
 	minedBlockNum             uint64
+	replacements              uint64 // how many times txs with this sender and nonce were replaced, see ReplacementPolicy
 }
 
 func newMetaTx(slot *types.TxSlot, isLocal bool, timestamp uint64) *metaTx {
@@ -202,12 +203,17 @@ type TxPool struct {
 	unprocessedRemoteByHash map[string]int                                  // to reject duplicates
 	byHash                  map[string]*metaTx                              // tx_hash => tx : only those records not committed to db yet
 	discardReasonsLRU       *simplelru.LRU[string, txpoolcfg.DiscardReason] // tx_hash => discard_reason : non-persisted
+	admissionLimiter        *admissionLimiter                               // rate limits of remote txs
+	replacementPolicy       ReplacementPolicy
+	priority                *priorityLane // allow-listed senders: exempt from limits and yielded first
 	pending                 *PendingPool
 	baseFee                 *SubPool
 	queued                  *SubPool
 	minedBlobTxsByBlock     map[uint64][]*metaTx             // (blockNum => slice): cache of recently mined blobs
 	minedBlobTxsByHash      map[string]*metaTx               // (hash => mt): map of recently mined blobs
 	isLocalLRU              *simplelru.LRU[string, struct{}] // tx_hash => is_local : to restore isLocal flag of unwinded transactions
+	locals                  *localsJournal                   // txs submitted to this node: re-broadcast and not evicted until included or expired
+	events                  *poolEvents                      // additions and drops of txs for subscribers
 	newPendingTxs           chan types.Announcements         // notifications about new txs in Pending sub-pool
 	all                     *BySenderAndNonce                // senderID => (sorted map of tx nonce => *metaTx)
 	deletedTxs              []*metaTx                        // list of discarded txs since last db commit
@@ -250,6 +256,15 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		return nil, err
 	}
 
+	limiter, err := newAdmissionLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	replacementPolicy, err := newReplacementPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	byNonce := &BySenderAndNonce{
 		tree:              btree.NewG[*metaTx](32, SortByNonceLess),
 		search:            &metaTx{Tx: &types.TxSlot{}},
@@ -262,13 +277,19 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 	}
 
 	lock := &sync.Mutex{}
+	senders := newSendersCache(tracedSenders)
 
 	res := &TxPool{
 		lock:                    lock,
 		lastSeenCond:            sync.NewCond(lock),
 		byHash:                  map[string]*metaTx{},
 		isLocalLRU:              localsHistory,
+		locals:                  newLocalsJournal(),
+		events:                  newPoolEvents(),
 		discardReasonsLRU:       discardHistory,
+		admissionLimiter:        limiter,
+		replacementPolicy:       replacementPolicy,
+		priority:                newPriorityLane(cfg, senders, logger),
 		all:                     byNonce,
 		recentlyConnectedPeers:  &recentlyConnectedPeers{},
 		pending:                 NewPendingSubPool(PendingSubPool, cfg.PendingSubPoolLimit),
@@ -276,7 +297,7 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		queued:                  NewSubPool(QueuedSubPool, cfg.QueuedSubPoolLimit),
 		newPendingTxs:           newTxs,
 		_stateCache:             cache,
-		senders:                 newSendersCache(tracedSenders),
+		senders:                 senders,
 		_chainDB:                coreDB,
 		cfg:                     cfg,
 		chainID:                 chainID,
@@ -450,7 +471,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 		return err
 	}
 
-	_, unwindTxs, err = p.validateTxs(&unwindTxs, cacheView)
+	_, unwindTxs, err = p.validateTxs(&unwindTxs, cacheView, false /* limitRemote */)
 
 	if err != nil {
 		return err
@@ -535,7 +556,7 @@ func (p *TxPool) processRemoteTxs(ctx context.Context) error {
 		return err
 	}
 
-	_, newTxs, err := p.validateTxs(p.unprocessedRemoteTxs, cacheView)
+	_, newTxs, err := p.validateTxs(p.unprocessedRemoteTxs, cacheView, true /* limitRemote */)
 	if err != nil {
 		return err
 	}
@@ -704,6 +725,32 @@ func (p *TxPool) IsLocal(idHash []byte) bool {
 func (p *TxPool) AddNewGoodPeer(peerID types.PeerID) { p.recentlyConnectedPeers.AddPeer(peerID) }
 func (p *TxPool) Started() bool                      { return p.started.Load() }
 
+// SetReplacementPolicy - replaces the policy chosen by txpoolcfg.Config.ReplacementPolicy with a custom one
+func (p *TxPool) SetReplacementPolicy(policy ReplacementPolicy) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.replacementPolicy = policy
+}
+
+// SetLimits - changes sizes of sub-pools and the limit of txs per sender at runtime, zero keeps the current value.
+// If a sub-pool is over its new limit, its worst txs are evicted on the next promotion.
+func (p *TxPool) SetLimits(pending, baseFee, queued int, accountSlots uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if pending > 0 {
+		p.cfg.PendingSubPoolLimit, p.pending.limit = pending, pending
+	}
+	if baseFee > 0 {
+		p.cfg.BaseFeeSubPoolLimit, p.baseFee.limit = baseFee, baseFee
+	}
+	if queued > 0 {
+		p.cfg.QueuedSubPoolLimit, p.queued.limit = queued, queued
+	}
+	if accountSlots > 0 {
+		p.cfg.AccountSlots = accountSlots
+	}
+}
+
 func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableGas, availableBlobGas uint64, yielded mapset.Set[[32]byte]) (bool, int, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -726,36 +773,30 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 		p.logger.Debug("[txpool] Processing best request", "last", onTopOf, "txRequested", n, "txAvailable", len(best.ms), "txProcessed", i, "txReturned", count)
 	}()
 
-	for ; count < int(n) && i < len(best.ms); i++ {
-		// if we wouldn't have enough gas for a standard transaction then quit out early
-		if availableGas < fixedgas.TxGas {
-			break
-		}
-
-		mt := best.ms[i]
-
+	// add - appends mt to txs if it still fits into the block
+	add := func(mt *metaTx) (bool, error) {
 		if yielded.Contains(mt.Tx.IDHash) {
-			continue
+			return false, nil
 		}
 
 		if mt.Tx.Gas >= p.blockGasLimit.Load() {
 			// Skip transactions with very large gas limit
-			continue
+			return false, nil
 		}
 
 		rlpTx, sender, isLocal, err := p.getRlpLocked(tx, mt.Tx.IDHash[:])
 		if err != nil {
-			return false, count, err
+			return false, err
 		}
 		if len(rlpTx) == 0 {
 			toRemove = append(toRemove, mt)
-			continue
+			return false, nil
 		}
 
 		// Skip transactions that require more blob gas than is available
 		blobCount := uint64(len(mt.Tx.BlobHashes))
 		if blobCount*fixedgas.BlobGasPerBlob > availableBlobGas {
-			continue
+			return false, nil
 		}
 		availableBlobGas -= blobCount * fixedgas.BlobGasPerBlob
 
@@ -765,7 +806,7 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 		intrinsicGas, _ := txpoolcfg.CalcIntrinsicGas(uint64(mt.Tx.DataLen), uint64(mt.Tx.DataNonZeroLen), nil, mt.Tx.Creation, true, true, isShanghai)
 		if intrinsicGas > availableGas {
 			// we might find another TX with a low enough intrinsic gas to include so carry on
-			continue
+			return false, nil
 		}
 		availableGas -= intrinsicGas
 
@@ -774,6 +815,62 @@ func (p *TxPool) best(n uint16, txs *types.TxsRlp, tx kv.Tx, onTopOf, availableG
 		txs.IsLocal[count] = isLocal
 		yielded.Add(mt.Tx.IDHash)
 		count++
+		return true, nil
+	}
+
+	// priority lane goes first, up to its per-block gas limit. Lane gas is accounted by txs gas limits,
+	// including lane txs yielded for this block by previous calls. Lane txs which were considered here
+	// are not considered again below: they wouldn't fit anyway
+	var laneDone map[*metaTx]struct{}
+	if p.priority.enabled() {
+		laneDone = map[*metaTx]struct{}{}
+		var lane []*metaTx
+		laneGas := uint64(0)
+		for _, mt := range best.ms {
+			if !p.isPriorityLocked(mt) {
+				continue
+			}
+			if yielded.Contains(mt.Tx.IDHash) {
+				laneGas += mt.Tx.Gas
+				continue
+			}
+			lane = append(lane, mt)
+		}
+		for _, mt := range lane {
+			if count >= int(n) || availableGas < fixedgas.TxGas {
+				break
+			}
+			if !p.priority.fits(laneGas, mt.Tx.Gas) {
+				priorityOverGasLimitCounter.Inc()
+				p.logger.Debug("[txpool] priority lane gas limit reached", "block", onTopOf+1, "laneGas", laneGas, "hash", fmt.Sprintf("%x", mt.Tx.IDHash))
+				continue
+			}
+			laneDone[mt] = struct{}{}
+			added, err := add(mt)
+			if err != nil {
+				return false, count, err
+			}
+			if added {
+				laneGas += mt.Tx.Gas
+				priorityYieldedCounter.Inc()
+				p.auditPriorityLocked("yielded", mt, "block", onTopOf+1, "laneGas", laneGas)
+			}
+		}
+	}
+
+	for ; count < int(n) && i < len(best.ms); i++ {
+		// if we wouldn't have enough gas for a standard transaction then quit out early
+		if availableGas < fixedgas.TxGas {
+			break
+		}
+
+		mt := best.ms[i]
+		if _, ok := laneDone[mt]; ok {
+			continue
+		}
+		if _, err := add(mt); err != nil {
+			return false, count, err
+		}
 	}
 
 	txs.Resize(uint(count))
@@ -833,6 +930,8 @@ func toBlobs(_blobs [][]byte) []gokzg4844.Blob {
 }
 
 func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.CacheView) txpoolcfg.DiscardReason {
+	// local and priority lane txs are not subject to per-account and min fee limits
+	exempt := isLocal || p.priority.has(txn.SenderID)
 	isShanghai := p.isShanghai() || p.isAgra()
 	if isShanghai {
 		if txn.DataLen > fixedgas.MaxInitCodeSize {
@@ -874,7 +973,7 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 			return txpoolcfg.UnmatchedBlobTxExt
 		}
 
-		if !isLocal && (p.all.blobCount(txn.SenderID)+uint64(len(txn.BlobHashes))) > p.cfg.BlobSlots {
+		if !exempt && (p.all.blobCount(txn.SenderID)+uint64(len(txn.BlobHashes))) > p.cfg.BlobSlots {
 			if txn.Traced {
 				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx marked as spamming (too many blobs) idHash=%x slots=%d, limit=%d", txn.IDHash, p.all.count(txn.SenderID), p.cfg.AccountSlots))
 			}
@@ -889,7 +988,7 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 	}
 
 	// Drop non-local transactions under our own minimal accepted gas price or tip
-	if !isLocal && uint256.NewInt(p.cfg.MinFeeCap).Cmp(&txn.FeeCap) == 1 {
+	if !exempt && uint256.NewInt(p.cfg.MinFeeCap).Cmp(&txn.FeeCap) == 1 {
 		if txn.Traced {
 			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx underpriced idHash=%x local=%t, feeCap=%d, cfg.MinFeeCap=%d", txn.IDHash, isLocal, txn.FeeCap, p.cfg.MinFeeCap))
 		}
@@ -911,7 +1010,7 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 		return txpoolcfg.IntrinsicGas
 	}
-	if !isLocal && uint64(p.all.count(txn.SenderID)) > p.cfg.AccountSlots {
+	if !exempt && uint64(p.all.count(txn.SenderID)) > p.cfg.AccountSlots {
 		if txn.Traced {
 			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx marked as spamming idHash=%x slots=%d, limit=%d", txn.IDHash, p.all.count(txn.SenderID), p.cfg.AccountSlots))
 		}
@@ -1088,7 +1187,9 @@ func (p *TxPool) ValidateSerializedTxn(serializedTxn []byte) error {
 	return nil
 }
 
-func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (reasons []txpoolcfg.DiscardReason, goodTxs types.TxSlots, err error) {
+// validateTxs - limitRemote applies the admission rate limits to remote txs, only new txs coming from peers are
+// limited: txs of unwound blocks are re-injected regardless
+func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView, limitRemote bool) (reasons []txpoolcfg.DiscardReason, goodTxs types.TxSlots, err error) {
 	// reasons is pre-sized for direct indexing, with the default zero
 	// value DiscardReason of NotSet
 	reasons = make([]txpoolcfg.DiscardReason, len(txs.Txs))
@@ -1098,7 +1199,14 @@ func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (
 	}
 
 	goodCount := 0
+	now := time.Now()
 	for i, txn := range txs.Txs {
+		if limitRemote && !txs.IsLocal[i] && !p.priority.has(txn.SenderID) {
+			if reason := p.admissionLimiter.admit(txn.SenderID, now); reason != txpoolcfg.Success {
+				reasons[i] = reason
+				continue
+			}
+		}
 		reason := p.validateTx(txn, txs.IsLocal[i], stateCache)
 		if reason == txpoolcfg.Success {
 			goodCount++
@@ -1188,7 +1296,7 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 		return nil, err
 	}
 
-	reasons, newTxs, err := p.validateTxs(&newTransactions, cacheView)
+	reasons, newTxs, err := p.validateTxs(&newTransactions, cacheView, false /* limitRemote */)
 	if err != nil {
 		return nil, err
 	}
@@ -1253,6 +1361,7 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 	sendersWithChangedState := map[uint64]struct{}{}
 	discardReasons := make([]txpoolcfg.DiscardReason, len(newTxs.Txs))
 	announcements := types.Announcements{}
+	now := uint64(time.Now().Unix())
 	for i, txn := range newTxs.Txs {
 		if found, ok := p.byHash[string(txn.IDHash[:])]; ok {
 			discardReasons[i] = txpoolcfg.DuplicateHash
@@ -1268,6 +1377,10 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 			continue
 		}
 		discardReasons[i] = txpoolcfg.NotSet // unnecessary
+		// journaled before promotion, to not be evicted by it
+		if collect && newTxs.IsLocal[i] {
+			p.journalLocalLocked(txn, newTxs.Senders.At(i), now)
+		}
 		if txn.Traced {
 			logger.Info(fmt.Sprintf("TX TRACING: schedule sendersWithChangedState idHash=%x senderId=%d", txn.IDHash, mt.Tx.SenderID))
 		}
@@ -1371,42 +1484,17 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 		if found.Tx.Type == types.BlobTxType && mt.Tx.Type != types.BlobTxType {
 			return txpoolcfg.BlobTxReplace
 		}
-		priceBump := p.cfg.PriceBump
-
-		//Blob txn threshold checks for replace txn
-		if mt.Tx.Type == types.BlobTxType {
-			priceBump = p.cfg.BlobPriceBump
-			blobFeeThreshold, overflow := (&uint256.Int{}).MulDivOverflow(
-				&found.Tx.BlobFeeCap,
-				uint256.NewInt(100+priceBump),
-				uint256.NewInt(100),
-			)
-			if mt.Tx.BlobFeeCap.Lt(blobFeeThreshold) && !overflow {
-				if bytes.Equal(found.Tx.IDHash[:], mt.Tx.IDHash[:]) {
-					return txpoolcfg.NotSet
-				}
-				return txpoolcfg.ReplaceUnderpriced // TODO: This is the same as NotReplaced
-			}
-		}
-
-		//Regular txn threshold checks
-		tipThreshold := uint256.NewInt(0)
-		tipThreshold = tipThreshold.Mul(&found.Tx.Tip, uint256.NewInt(100+priceBump))
-		tipThreshold.Div(tipThreshold, u256.N100)
-		feecapThreshold := uint256.NewInt(0)
-		feecapThreshold.Mul(&found.Tx.FeeCap, uint256.NewInt(100+priceBump))
-		feecapThreshold.Div(feecapThreshold, u256.N100)
-		if mt.Tx.Tip.Cmp(tipThreshold) < 0 || mt.Tx.FeeCap.Cmp(feecapThreshold) < 0 {
-			// Both tip and feecap need to be larger than previously to replace the transaction
+		if reason := p.replacementPolicy.CanReplace(found.Tx, mt.Tx, found.replacements); reason != txpoolcfg.Success {
 			// In case if the transition is stuck, "poke" it to rebroadcast
-			if mt.subPool&IsLocal != 0 && (found.currentSubPool == PendingSubPool || found.currentSubPool == BaseFeeSubPool) {
+			if reason == txpoolcfg.NotReplaced && mt.subPool&IsLocal != 0 && (found.currentSubPool == PendingSubPool || found.currentSubPool == BaseFeeSubPool) {
 				announcements.Append(found.Tx.Type, found.Tx.Size, found.Tx.IDHash[:])
 			}
 			if bytes.Equal(found.Tx.IDHash[:], mt.Tx.IDHash[:]) {
 				return txpoolcfg.NotSet
 			}
-			return txpoolcfg.NotReplaced
+			return reason
 		}
+		mt.replacements = found.replacements + 1
 
 		switch found.currentSubPool {
 		case PendingSubPool:
@@ -1441,6 +1529,10 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 	}
 	// All transactions are first added to the queued pool and then immediately promoted from there if required
 	p.queued.Add(mt, "addLocked", p.logger)
+	if p.isPriorityLocked(mt) {
+		priorityAdmittedCounter.Inc()
+		p.auditPriorityLocked("admitted", mt)
+	}
 	if mt.Tx.Type == types.BlobTxType {
 		t := p.totalBlobsInPool.Load()
 		p.totalBlobsInPool.Store(t + (uint64(len(mt.Tx.BlobHashes))))
@@ -1448,6 +1540,7 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 
 	// Remove from mined cache as we are now "resurrecting" it to a sub-pool
 	p.deleteMinedBlobTxn(hashStr)
+	p.onAddedLocked(mt)
 	return txpoolcfg.NotSet
 }
 
@@ -1455,7 +1548,11 @@ func (p *TxPool) addLocked(mt *metaTx, announcements *types.Announcements) txpoo
 // Important: don't call it while iterating by all
 func (p *TxPool) discardLocked(mt *metaTx, reason txpoolcfg.DiscardReason) {
 	hashStr := string(mt.Tx.IDHash[:])
+	if _, ok := p.byHash[hashStr]; ok {
+		p.onDroppedLocked(mt, reason)
+	}
 	delete(p.byHash, hashStr)
+	p.locals.remove(hashStr)
 	p.deletedTxs = append(p.deletedTxs, mt)
 	p.all.delete(mt, reason, p.logger)
 	p.discardReasonsLRU.Add(hashStr, reason)
@@ -1738,18 +1835,43 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 	// <FUNCTIONALITY REMOVED>
 
 	// Discard worst transactions from pending pool until it is within capacity limit
-	for p.pending.Len() > p.pending.limit {
-		p.discardLocked(p.pending.PopWorst(), txpoolcfg.PendingPoolOverflow)
+	// journaled local and priority lane transactions are not evicted - they are put back, even if the sub pool stays over its limit
+	var journaled []*metaTx
+	for p.pending.Len() > 0 && p.pending.Len()+len(journaled) > p.pending.limit {
+		if worst := p.pending.PopWorst(); p.isJournaledLocked(worst) || p.isPriorityLocked(worst) {
+			journaled = append(journaled, worst)
+		} else {
+			p.discardLocked(worst, txpoolcfg.PendingPoolOverflow)
+		}
+	}
+	for _, mt := range journaled {
+		p.pending.Add(mt, logger)
 	}
 
 	// Discard worst transactions from pending sub pool until it is within capacity limits
-	for p.baseFee.Len() > p.baseFee.limit {
-		p.discardLocked(p.baseFee.PopWorst(), txpoolcfg.BaseFeePoolOverflow)
+	journaled = journaled[:0]
+	for p.baseFee.Len() > 0 && p.baseFee.Len()+len(journaled) > p.baseFee.limit {
+		if worst := p.baseFee.PopWorst(); p.isJournaledLocked(worst) || p.isPriorityLocked(worst) {
+			journaled = append(journaled, worst)
+		} else {
+			p.discardLocked(worst, txpoolcfg.BaseFeePoolOverflow)
+		}
+	}
+	for _, mt := range journaled {
+		p.baseFee.Add(mt, "keep-journaled", logger)
 	}
 
 	// Discard worst transactions from the queued sub pool until it is within its capacity limits
-	for _ = p.queued.Worst(); p.queued.Len() > p.queued.limit; _ = p.queued.Worst() {
-		p.discardLocked(p.queued.PopWorst(), txpoolcfg.QueuedPoolOverflow)
+	journaled = journaled[:0]
+	for p.queued.Len() > 0 && p.queued.Len()+len(journaled) > p.queued.limit {
+		if worst := p.queued.PopWorst(); p.isJournaledLocked(worst) || p.isPriorityLocked(worst) {
+			journaled = append(journaled, worst)
+		} else {
+			p.discardLocked(worst, txpoolcfg.QueuedPoolOverflow)
+		}
+	}
+	for _, mt := range journaled {
+		p.queued.Add(mt, "keep-journaled", logger)
 	}
 }
 
@@ -1758,6 +1880,9 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 // by the peer.
 const txMaxBroadcastSize = 4 * 1024
 
+// localTxsBroadcastMaxPeers - local transactions are broadcast to more peers than remote ones
+const localTxsBroadcastMaxPeers uint64 = 10
+
 // MainLoop - does:
 // send pending byHash to p2p:
 //   - new byHash
@@ -1775,6 +1900,12 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 	defer commitEvery.Stop()
 	logEvery := time.NewTicker(p.cfg.LogEvery)
 	defer logEvery.Stop()
+	var rebroadcastLocalsEvery <-chan time.Time
+	if p.cfg.RebroadcastLocalsEvery > 0 {
+		ticker := time.NewTicker(p.cfg.RebroadcastLocalsEvery)
+		defer ticker.Stop()
+		rebroadcastLocalsEvery = ticker.C
+	}
 
 	err := p.Start(ctx, db)
 
@@ -1786,11 +1917,27 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 	for {
 		select {
 		case <-ctx.Done():
-			_, _ = p.flush(ctx, db)
+			// ctx is already cancelled - flush with own one, to not lose txs received since the last commit
+			t := time.Now()
+			if _, err := p.flush(context.Background(), db); err != nil {
+				p.logger.Warn("[txpool] flush on shutdown", "err", err)
+			} else {
+				p.logger.Info("[txpool] Flushed to disk", "pending", p.pending.Len(), "baseFee", p.baseFee.Len(), "queued", p.queued.Len(), "in", time.Since(t))
+			}
 			return
 		case <-logEvery.C:
 			p.logStats()
+		case <-rebroadcastLocalsEvery:
+			if !p.Started() {
+				continue
+			}
+			p.expireLocals(time.Now())
+			if p.cfg.NoGossip {
+				continue
+			}
+			go p.rebroadcastLocals(ctx, db, send)
 		case <-processRemoteTxsEvery.C:
+			p.events.flush(p.logger)
 			if !p.Started() {
 				continue
 			}
@@ -1902,7 +2049,6 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 				}
 
 				// broadcast local transactions
-				const localTxsBroadcastMaxPeers uint64 = 10
 				txSentTo := send.BroadcastPooledTxs(localTxRlps, localTxsBroadcastMaxPeers)
 				for i, peer := range txSentTo {
 					p.logger.Trace("Local tx broadcast", "txHash", hex.EncodeToString(broadcastHashes.At(i)), "to peer", peer)
@@ -1999,6 +2145,10 @@ func (p *TxPool) flushLocked(tx kv.RwTx) (err error) {
 		p.deletedTxs[i] = nil // for gc
 	}
 
+	if err := p.locals.flush(tx); err != nil {
+		return err
+	}
+
 	txHashes := p.isLocalLRU.Keys()
 	encID := make([]byte, 8)
 	if err := tx.ClearBucket(kv.RecentLocalTransaction); err != nil {
@@ -2101,12 +2251,9 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		p.isLocalLRU.Add(string(v), struct{}{})
 	}
 
-	txs := types.TxSlots{}
-	parseCtx := types.NewTxParseContext(p.chainID)
-	parseCtx.WithSender(false)
-
-	i := 0
-	it, err = tx.Range(kv.PoolTransaction, nil, nil)
+	// journaled txs are local, those missing in kv.PoolTransaction are restored from the journal
+	journaled := map[string]*localTx{}
+	it, err = tx.Range(kv.PoolLocalsJournal, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -2115,7 +2262,22 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		if err != nil {
 			return err
 		}
-		addr, txRlp := *(*[20]byte)(v[:20]), v[20:]
+		ltx, err := decodeLocalTx(v)
+		if err != nil {
+			p.logger.Warn("[txpool] fromDB: locals journal", "err", err)
+			continue
+		}
+		journaled[string(k)] = ltx
+		p.locals.byHash[string(k)] = &localTx{sender: ltx.sender, added: ltx.added}
+		p.isLocalLRU.Add(string(k), struct{}{})
+	}
+
+	txs := types.TxSlots{}
+	parseCtx := types.NewTxParseContext(p.chainID)
+	parseCtx.WithSender(false)
+
+	i, dropped := 0, 0
+	restore := func(k []byte, addr common.Address, txRlp []byte, persisted bool) {
 		txn := &types.TxSlot{}
 
 		// TODO(eip-4844) ensure wrappedWithBlobs when transactions are saved to the DB
@@ -2123,17 +2285,28 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		if err != nil {
 			err = fmt.Errorf("err: %w, rlp: %x", err, txRlp)
 			p.logger.Warn("[txpool] fromDB: parseTransaction", "err", err)
-			continue
+			p.locals.remove(string(k))
+			return
 		}
 		txn.Rlp = nil // means that we don't need store it in db anymore
+		if !persisted {
+			txn.Rlp = common.Copy(txRlp)
+		}
 
 		txn.SenderID, txn.Traced = p.senders.getOrCreateID(addr, p.logger)
-		binary.BigEndian.Uint64(v) // TODO - unnecessary line, remove
 
 		isLocalTx := p.isLocalLRU.Contains(string(k))
 
+		// txs which became invalid while node was down (mined, sender's balance spent, etc...) are dropped
+		// one by one - the rest of the pool is still restored
 		if reason := p.validateTx(txn, isLocalTx, cacheView); reason != txpoolcfg.NotSet && reason != txpoolcfg.Success {
-			return nil // TODO: Clarify - if one of the txs has the wrong reason, no pooled txs!
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: fromDB dropped idHash=%x reason=%s", txn.IDHash, reason))
+			}
+			p.deletedTxs = append(p.deletedTxs, &metaTx{Tx: txn})
+			p.locals.remove(string(k))
+			dropped++
+			return
 		}
 		txs.Resize(uint(i + 1))
 		txs.Txs[i] = txn
@@ -2142,6 +2315,22 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 		i++
 	}
 
+	it, err = tx.Range(kv.PoolTransaction, nil, nil)
+	if err != nil {
+		return err
+	}
+	for it.HasNext() {
+		k, v, err := it.Next()
+		if err != nil {
+			return err
+		}
+		delete(journaled, string(k))
+		restore(k, *(*[20]byte)(v[:20]), v[20:], true)
+	}
+	for k, ltx := range journaled {
+		restore([]byte(k), ltx.sender, ltx.rlp, false)
+	}
+
 	var pendingBaseFee, pendingBlobFee, minBlobGasPrice, blockGasLimit uint64
 
 	if p.feeCalculator != nil {
@@ -2192,6 +2381,9 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.Tx) error {
 	p.pendingBaseFee.Store(pendingBaseFee)
 	p.pendingBlobFee.Store(pendingBlobFee)
 	p.blockGasLimit.Store(blockGasLimit)
+	if i > 0 || dropped > 0 {
+		p.logger.Info("[txpool] Restored from disk", "txs", i, "dropped", dropped, "pending", p.pending.Len(), "baseFee", p.baseFee.Len(), "queued", p.queued.Len())
+	}
 	return nil
 }
 
@@ -2307,18 +2499,14 @@ func (p *TxPool) deprecatedForEach(_ context.Context, f func(rlp []byte, sender
 	defer p.lock.Unlock()
 	p.all.ascendAll(func(mt *metaTx) bool {
 		slot := mt.Tx
-		slotRlp := slot.Rlp
-		if slot.Rlp == nil {
-			v, err := tx.GetOne(kv.PoolTransaction, slot.IDHash[:])
-			if err != nil {
-				p.logger.Warn("[txpool] foreach: get tx from db", "err", err)
-				return true
-			}
-			if v == nil {
-				p.logger.Warn("[txpool] foreach: tx not found in db")
-				return true
-			}
-			slotRlp = v[20:]
+		slotRlp, err := p.slotRlp(tx, slot)
+		if err != nil {
+			p.logger.Warn("[txpool] foreach: get tx from db", "err", err)
+			return true
+		}
+		if slotRlp == nil {
+			p.logger.Warn("[txpool] foreach: tx not found in db")
+			return true
 		}
 		if sender, found := p.senders.senderID2Addr[slot.SenderID]; found {
 			f(slotRlp, sender, mt.currentSubPool)
@@ -2414,8 +2602,10 @@ func (sc *sendersBatch) info(cacheView kvcache.CacheView, id uint64) (nonce uint
 	}
 	if cacheView.StateV3() {
 		var bp *uint256.Int
-		nonce, bp, _ = types.DecodeAccountBytesV3(encoded)
-		balance = *bp
+		nonce, bp, _, _, err = statecodec.Active().DecodeAccount(encoded)
+		if err == nil {
+			balance = *bp
+		}
 	} else {
 		nonce, balance, err = types.DecodeSender(encoded)
 	}
@@ -2484,6 +2674,14 @@ func (b *BySenderAndNonce) ascendAll(f func(*metaTx) bool) {
 	})
 }
 
+// ascendFrom - iterates over all txs starting from the given sender and nonce
+func (b *BySenderAndNonce) ascendFrom(senderID, nonce uint64, f func(*metaTx) bool) {
+	s := b.search
+	s.Tx.SenderID = senderID
+	s.Tx.Nonce = nonce
+	b.tree.AscendGreaterOrEqual(s, f)
+}
+
 func (b *BySenderAndNonce) ascend(senderID uint64, f func(*metaTx) bool) {
 	s := b.search
 	s.Tx.SenderID = senderID
@@ -23,8 +23,10 @@ import (
 	"math"
 	"math/big"
 	"testing"
+	"time"
 
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/kv/temporal/temporaltest"
@@ -1209,3 +1211,448 @@ func TestGasLimitChanged(t *testing.T) {
 
 	assert.Zero(mtx.subPool&NotTooMuchGas, "Should now have block space (again) for the tx")
 }
+
+func TestRestoreFromDB(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+
+	var addr [20]byte
+	addr[0] = 1
+	v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), nil, 0)
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 1,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})},
+		},
+	}
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	// legacy txs of the same sender, signatures aren't checked when parsed from db:
+	// the 1st one is valid, the 2nd one has gas below intrinsic and must be dropped alone
+	legacyTx := func(nonce byte, gas []byte) []byte {
+		payload := append([]byte{nonce, 0x0a}, gas...) // gasPrice 10
+		payload = append(payload, 0x94)
+		payload = append(payload, make([]byte, 20)...)
+		payload = append(payload, 0x80, 0x80, 0x1b, 0x01, 0x01) // value, data, v, r, s
+		return append([]byte{0xc0 + byte(len(payload))}, payload...)
+	}
+	valid, invalid := legacyTx(0x80, []byte{0x82, 0x52, 0x08}), legacyTx(0x01, []byte{0x01})
+	parseCtx := types.NewTxParseContext(*u256.N1)
+	parseCtx.WithSender(false)
+	for i, txRlp := range [][]byte{valid, invalid} {
+		var slot types.TxSlot
+		_, err := parseCtx.ParseTransaction(txRlp, 0, &slot, nil, false, true, nil)
+		require.NoError(err, i)
+		require.NoError(tx.Put(kv.PoolTransaction, slot.IDHash[:], append(addr[:], txRlp...)))
+	}
+
+	restored, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	require.NoError(coreDB.View(ctx, func(coreTx kv.Tx) error { return restored.fromDB(ctx, tx, coreTx) }))
+	assert.Equal(1, restored.pending.Len())
+	assert.Len(restored.deletedTxs, 1)
+
+	// dropped tx is removed from db by the next flush
+	require.NoError(restored.flushLocked(tx))
+	c, err := tx.Cursor(kv.PoolTransaction)
+	require.NoError(err)
+	defer c.Close()
+	n, err := c.Count()
+	require.NoError(err)
+	assert.Equal(uint64(1), n)
+}
+
+func TestLocalsJournal(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	cfg.PendingSubPoolLimit = 1
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+
+	var addr [20]byte
+	addr[0] = 1
+	v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), nil, 0)
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 1,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})},
+		},
+	}
+	change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+		Action:  remote.Action_UPSERT,
+		Address: gointerfaces.ConvertAddressToH160(addr),
+		Data:    v,
+	})
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	// legacy txs with nonces 0 and 1, gasPrice 10, gas 21000
+	legacyTx := func(nonce byte) []byte {
+		payload := []byte{nonce, 0x0a, 0x82, 0x52, 0x08, 0x94}
+		payload = append(payload, make([]byte, 20)...)
+		payload = append(payload, 0x80, 0x80, 0x1b, 0x01, 0x01) // value, data, v, r, s
+		return append([]byte{0xc0 + byte(len(payload))}, payload...)
+	}
+	parseCtx := types.NewTxParseContext(*u256.N1)
+	parseCtx.WithSender(false)
+	var txSlots types.TxSlots
+	for _, nonce := range []byte{0x80, 0x01} {
+		slot := &types.TxSlot{}
+		_, err := parseCtx.ParseTransaction(legacyTx(nonce), 0, slot, nil, false, true, nil)
+		require.NoError(err)
+		txSlots.Append(slot, addr[:], true)
+	}
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+	require.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+	// journaled txs are not evicted by the pending sub pool overflow
+	assert.Equal(2, pool.pending.Len())
+	assert.Len(pool.locals.byHash, 2)
+
+	// journal survives restart, also for txs missing in kv.PoolTransaction
+	require.NoError(pool.flushLocked(tx))
+	require.NoError(tx.Delete(kv.PoolTransaction, txSlots.Txs[1].IDHash[:]))
+	restored, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	require.NoError(coreDB.View(ctx, func(coreTx kv.Tx) error { return restored.fromDB(ctx, tx, coreTx) }))
+	assert.Equal(2, restored.pending.Len())
+	var locals [][]byte
+	require.NoError(restored.Locals(tx, func(sender common.Address, rlp []byte, added uint64) {
+		assert.Equal(common.Address(addr), sender)
+		locals = append(locals, rlp)
+	}))
+	assert.ElementsMatch([][]byte{legacyTx(0x80), legacyTx(0x01)}, locals)
+
+	// included txs leave the journal
+	var minedTxs types.TxSlots
+	minedTxs.Append(txSlots.Txs[0], addr[:], false)
+	change.ChangeBatch[0].BlockHeight = 1
+	change.ChangeBatch[0].Changes[0].Data = types.EncodeAccountBytesV3(1, uint256.NewInt(1*common.Ether), nil, 0)
+	require.NoError(restored.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, minedTxs, tx))
+	assert.Len(restored.locals.byHash, 1)
+
+	// expired txs leave the journal, but stay in the pool
+	restored.expireLocals(time.Now().Add(cfg.LocalsLifetime + time.Minute))
+	assert.Empty(restored.locals.byHash)
+	assert.Equal(1, restored.pending.Len())
+	require.NoError(restored.flushLocked(tx))
+	c, err := tx.Cursor(kv.PoolLocalsJournal)
+	require.NoError(err)
+	defer c.Close()
+	n, err := c.Count()
+	require.NoError(err)
+	assert.Zero(n)
+}
+
+func TestPriorityLane(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	var addr, priorityAddr [20]byte
+	addr[0], priorityAddr[0] = 1, 2
+	cfg := txpoolcfg.DefaultConfig
+	cfg.MinFeeCap = 20
+	cfg.PrioritySenders = []common.Address{priorityAddr}
+	cfg.PriorityLaneGasLimit = 21_000 // one transfer per block
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+
+	v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), nil, 0)
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 1,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})},
+		},
+	}
+	for _, a := range [][20]byte{addr, priorityAddr} {
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(a),
+			Data:    v,
+		})
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	// legacy transfers, gas 21000
+	legacyTx := func(nonce, gasPrice byte) []byte {
+		payload := []byte{nonce, gasPrice, 0x82, 0x52, 0x08, 0x94}
+		payload = append(payload, make([]byte, 20)...)
+		payload = append(payload, 0x80, 0x80, 0x1b, 0x01, 0x01) // value, data, v, r, s
+		return append([]byte{0xc0 + byte(len(payload))}, payload...)
+	}
+	parseCtx := types.NewTxParseContext(*u256.N1)
+	parseCtx.WithSender(false)
+	parse := func(rlp []byte) *types.TxSlot {
+		slot := &types.TxSlot{}
+		_, err := parseCtx.ParseTransaction(rlp, 0, slot, nil, false, true, nil)
+		require.NoError(err)
+		return slot
+	}
+	regular, lane0, lane1 := legacyTx(0x80, 0x64), legacyTx(0x80, 0x0a), legacyTx(0x01, 0x0a)
+	var txSlots types.TxSlots
+	txSlots.Append(parse(regular), addr[:], true)
+	txSlots.Append(parse(lane0), priorityAddr[:], true)
+	txSlots.Append(parse(lane1), priorityAddr[:], true)
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+	require.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+
+	// remote txs of priority senders are not checked against min fee cap
+	coreTx, err := coreDB.BeginRo(ctx)
+	require.NoError(err)
+	defer coreTx.Rollback()
+	view, err := sendersCache.View(ctx, coreTx)
+	require.NoError(err)
+	underpriced := parse(legacyTx(0x02, 0x0a))
+	underpriced.SenderID, _ = pool.senders.getID(priorityAddr)
+	assert.Equal(txpoolcfg.Success, pool.validateTx(underpriced, false, view))
+	underpriced.SenderID, _ = pool.senders.getID(addr)
+	assert.Equal(txpoolcfg.UnderPriced, pool.validateTx(underpriced, false, view))
+
+	// lane goes first, the rest of its txs compete by tip once lane gas limit is reached
+	yielded := mapset.NewThreadUnsafeSet[[32]byte]()
+	var txs types.TxsRlp
+	_, count, err := pool.YieldBest(1, &txs, tx, 0, 1_000_000, 0, yielded)
+	require.NoError(err)
+	require.Equal(1, count)
+	assert.Equal(lane0, txs.Txs[0])
+
+	_, count, err = pool.YieldBest(10, &txs, tx, 0, 1_000_000, 0, yielded)
+	require.NoError(err)
+	require.Equal(2, count)
+	assert.Equal([][]byte{regular, lane1}, txs.Txs)
+}
+
+func TestContentAndEvents(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	cfg := txpoolcfg.DefaultConfig
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+	events, unsubscribe := pool.SubscribeEvents(16)
+	defer unsubscribe()
+
+	addr1, addr2 := common.Address{1}, common.Address{2}
+	change := &remote.StateChangeBatch{
+		PendingBlockBaseFee: 1,
+		BlockGasLimit:       1_000_000,
+		ChangeBatch: []*remote.StateChange{
+			{BlockHeight: 0, BlockHash: gointerfaces.ConvertHashToH256([32]byte{})},
+		},
+	}
+	for _, addr := range []common.Address{addr1, addr2} {
+		change.ChangeBatch[0].Changes = append(change.ChangeBatch[0].Changes, &remote.AccountChange{
+			Action:  remote.Action_UPSERT,
+			Address: gointerfaces.ConvertAddressToH160(addr),
+			Data:    types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), nil, 0),
+		})
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	// legacy txs with gas 21000
+	legacyTx := func(nonce, gasPrice byte) []byte {
+		payload := []byte{nonce, gasPrice, 0x82, 0x52, 0x08, 0x94}
+		payload = append(payload, make([]byte, 20)...)
+		payload = append(payload, 0x80, 0x80, 0x1b, 0x01, 0x01) // value, data, v, r, s
+		return append([]byte{0xc0 + byte(len(payload))}, payload...)
+	}
+	parseCtx := types.NewTxParseContext(*u256.N1)
+	parseCtx.WithSender(false)
+	var txSlots types.TxSlots
+	for _, txn := range []struct {
+		sender          common.Address
+		nonce, gasPrice byte
+	}{{addr1, 0x80, 10}, {addr1, 0x01, 20}, {addr1, 0x02, 30}, {addr2, 0x80, 20}} {
+		slot := &types.TxSlot{}
+		_, err := parseCtx.ParseTransaction(legacyTx(txn.nonce, txn.gasPrice), 0, slot, nil, false, true, nil)
+		require.NoError(err)
+		txSlots.Append(slot, txn.sender[:], true)
+	}
+	reasons, err := pool.AddLocalTxs(ctx, txSlots, tx)
+	require.NoError(err)
+	for _, reason := range reasons {
+		assert.Equal(txpoolcfg.Success, reason, reason.String())
+	}
+
+	content := func(filter ContentFilter) (senders []common.Address, next []byte) {
+		next, err := pool.Content(tx, filter, func(rlp []byte, sender common.Address, t SubPoolType) {
+			senders = append(senders, sender)
+		})
+		require.NoError(err)
+		return senders, next
+	}
+
+	// pages
+	senders, next := content(ContentFilter{Limit: 3})
+	assert.Equal([]common.Address{addr1, addr1, addr1}, senders)
+	require.NotNil(next)
+	senders, next = content(ContentFilter{Limit: 3, Cursor: next})
+	assert.Equal([]common.Address{addr2}, senders)
+	assert.Nil(next)
+	_, err = pool.Content(tx, ContentFilter{Cursor: []byte{1}}, func([]byte, common.Address, SubPoolType) {})
+	require.Error(err)
+
+	// filters
+	senders, next = content(ContentFilter{Senders: []common.Address{addr2, {3}}})
+	assert.Equal([]common.Address{addr2}, senders)
+	assert.Nil(next)
+	senders, next = content(ContentFilter{Senders: []common.Address{addr1}, Limit: 2})
+	assert.Equal([]common.Address{addr1, addr1}, senders)
+	senders, _ = content(ContentFilter{Senders: []common.Address{addr1, addr2}, Cursor: next})
+	assert.Equal([]common.Address{addr1, addr2}, senders)
+	senders, _ = content(ContentFilter{MinTip: uint256.NewInt(20)})
+	assert.Equal([]common.Address{addr1, addr1, addr2}, senders)
+	senders, _ = content(ContentFilter{Types: []byte{types.DynamicFeeTxType}})
+	assert.Empty(senders)
+
+	// events
+	pool.events.flush(pool.logger)
+	batch := <-events
+	require.Len(batch, 4)
+	for i, ev := range batch {
+		assert.False(ev.Dropped)
+		assert.Equal(common.Hash(txSlots.Txs[i].IDHash), ev.Hash)
+		assert.Equal(common.Address(txSlots.Senders.At(i)), ev.Sender)
+	}
+
+	var minedTxs types.TxSlots
+	minedTxs.Append(txSlots.Txs[3], addr2[:], false)
+	change.ChangeBatch[0].BlockHeight = 1
+	change.ChangeBatch[0].Changes = change.ChangeBatch[0].Changes[1:]
+	change.ChangeBatch[0].Changes[0].Data = types.EncodeAccountBytesV3(1, uint256.NewInt(1*common.Ether), nil, 0)
+	require.NoError(pool.OnNewBlock(ctx, change, types.TxSlots{}, types.TxSlots{}, minedTxs, tx))
+	pool.events.flush(pool.logger)
+	batch = <-events
+	require.Len(batch, 1)
+	assert.True(batch[0].Dropped)
+	assert.Equal(txpoolcfg.Mined, batch[0].Reason)
+	assert.Equal(addr2, batch[0].Sender)
+
+	// slow subscriber is unsubscribed
+	slow, _ := pool.SubscribeEvents(0)
+	pool.events.add(PoolEvent{})
+	pool.events.flush(pool.logger)
+	_, ok := <-slow
+	assert.False(ok)
+}
+
+func TestRateLimitSkipsUnwoundTxs(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	ch := make(chan types.Announcements, 100)
+
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	db := memdb.NewTestPoolDB(t)
+	var addr [20]byte
+	addr[0] = 1
+	cfg := txpoolcfg.DefaultConfig
+	cfg.SenderRateLimit = 1
+	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	require.NoError(err)
+	ctx := context.Background()
+
+	v := types.EncodeAccountBytesV3(0, uint256.NewInt(1*common.Ether), nil, 0)
+	newChange := func(height uint64) *remote.StateChangeBatch {
+		return &remote.StateChangeBatch{
+			StateVersionId:      height,
+			PendingBlockBaseFee: 1,
+			BlockGasLimit:       1_000_000,
+			ChangeBatch: []*remote.StateChange{{
+				BlockHeight: height,
+				BlockHash:   gointerfaces.ConvertHashToH256([32]byte{byte(height)}),
+				Changes: []*remote.AccountChange{{
+					Action:  remote.Action_UPSERT,
+					Address: gointerfaces.ConvertAddressToH160(addr),
+					Data:    v,
+				}},
+			}},
+		}
+	}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	require.NoError(pool.OnNewBlock(ctx, newChange(0), types.TxSlots{}, types.TxSlots{}, types.TxSlots{}, tx))
+
+	remoteTxs := func(firstNonce uint64, count int) types.TxSlots {
+		var txSlots types.TxSlots
+		for i := 0; i < count; i++ {
+			txSlot := &types.TxSlot{
+				Tip:    *uint256.NewInt(300000),
+				FeeCap: *uint256.NewInt(300000),
+				Gas:    100000,
+				Nonce:  firstNonce + uint64(i),
+			}
+			txSlot.IDHash[0] = byte(firstNonce) + byte(i) + 1
+			txSlots.Append(txSlot, addr[:], false)
+		}
+		return txSlots
+	}
+
+	// txs of an unwound block are re-injected, all of them, though they are remote and over the sender's rate
+	require.NoError(pool.OnNewBlock(ctx, newChange(1), remoteTxs(0, 3), types.TxSlots{}, types.TxSlots{}, tx))
+	senderID, ok := pool.senders.getID(addr)
+	require.True(ok)
+	assert.Equal(3, pool.all.count(senderID))
+
+	// new txs of peers are limited
+	coreTx, err := coreDB.BeginRo(ctx)
+	require.NoError(err)
+	defer coreTx.Rollback()
+	view, err := sendersCache.View(ctx, coreTx)
+	require.NoError(err)
+	newTxs := remoteTxs(3, 2)
+	for _, txn := range newTxs.Txs {
+		txn.SenderID = senderID
+	}
+	pool.lock.Lock()
+	reasons, _, err := pool.validateTxs(&newTxs, view, true /* limitRemote */)
+	pool.lock.Unlock()
+	require.NoError(err)
+	assert.Equal([]txpoolcfg.DiscardReason{txpoolcfg.NotSet, txpoolcfg.RateLimited}, reasons)
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+)
+
+var (
+	priorityAdmittedCounter     = metrics.GetOrCreateCounter(`txpool_priority_lane{event="admitted"}`)
+	priorityYieldedCounter      = metrics.GetOrCreateCounter(`txpool_priority_lane{event="yielded"}`)
+	priorityOverGasLimitCounter = metrics.GetOrCreateCounter(`txpool_priority_lane{event="over_gas_limit"}`)
+)
+
+// priorityLane - txs of allow-listed senders (e.g. Polygon validator operational txs):
+// they bypass per-account limits, min fee cap and rate limits like local txs, are never evicted
+// on overflow and are yielded to block building ahead of all other txs, until the lane's per-block
+// gas limit is used up. Txs which don't fit into the lane anymore compete with the rest of the pool by tip.
+// Every admitted and yielded txn is logged. Not thread-safe: must be used under TxPool.lock
+type priorityLane struct {
+	senders  map[uint64]common.Address // senderID => address; empty - lane disabled
+	gasLimit uint64                    // max sum of txs gas limits yielded per block, 0 - unlimited
+}
+
+func newPriorityLane(cfg txpoolcfg.Config, senders *sendersBatch, logger log.Logger) *priorityLane {
+	l := &priorityLane{senders: make(map[uint64]common.Address, len(cfg.PrioritySenders)), gasLimit: cfg.PriorityLaneGasLimit}
+	for _, addr := range cfg.PrioritySenders {
+		id, _ := senders.getOrCreateID(addr, logger)
+		l.senders[id] = addr
+	}
+	if len(l.senders) > 0 {
+		logger.Info("[txpool] priority lane enabled", "senders", len(l.senders), "gasLimit", l.gasLimit)
+	}
+	return l
+}
+
+func (l *priorityLane) enabled() bool { return len(l.senders) > 0 }
+
+func (l *priorityLane) has(senderID uint64) bool {
+	_, ok := l.senders[senderID]
+	return ok
+}
+
+// fits - whether a txn with given gas limit can be yielded, when laneGas was already yielded by the lane for the block
+func (l *priorityLane) fits(laneGas, gas uint64) bool {
+	return l.gasLimit == 0 || laneGas+gas <= l.gasLimit
+}
+
+// isPriorityLocked - whether mt belongs to the priority lane, which exempts it from pool limits and eviction
+func (p *TxPool) isPriorityLocked(mt *metaTx) bool {
+	return p.priority.has(mt.Tx.SenderID)
+}
+
+// auditPriorityLocked - every priority lane txn is logged, as they are not checked against the usual pool limits
+func (p *TxPool) auditPriorityLocked(event string, mt *metaTx, ctx ...interface{}) {
+	ctx = append([]interface{}{"event", event, "sender", p.priority.senders[mt.Tx.SenderID], "nonce", mt.Tx.Nonce,
+		"hash", fmt.Sprintf("%x", mt.Tx.IDHash), "gas", mt.Tx.Gas}, ctx...)
+	p.logger.Info("[txpool] priority lane", ctx...)
+}
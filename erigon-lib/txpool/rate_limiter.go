@@ -0,0 +1,88 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"math"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"golang.org/x/time/rate"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+)
+
+// rateLimitedSenders - how many per-sender buckets to keep. Evicted sender starts with full bucket again.
+const rateLimitedSenders = 100_000
+
+var (
+	rateLimitedBySenderCounter = metrics.GetOrCreateCounter(`txpool_rate_limited{scope="sender"}`)
+	rateLimitedGlobalCounter   = metrics.GetOrCreateCounter(`txpool_rate_limited{scope="global"}`)
+)
+
+// admissionLimiter - token buckets for remote txs admission: one per sender and one for the whole pool.
+// Txs rejected by it are dropped before any validation - to not spend CPU on spam bursts.
+// Not thread-safe: must be used under TxPool.lock
+type admissionLimiter struct {
+	global      *rate.Limiter                         // nil - unlimited
+	senders     *simplelru.LRU[uint64, *rate.Limiter] // senderID => bucket; nil - unlimited
+	senderLimit rate.Limit
+	senderBurst int
+}
+
+func newAdmissionLimiter(cfg txpoolcfg.Config) (*admissionLimiter, error) {
+	l := &admissionLimiter{}
+	if cfg.GlobalRateLimit > 0 {
+		l.global = rate.NewLimiter(rate.Limit(cfg.GlobalRateLimit), rateLimitBurst(cfg.GlobalRateLimit))
+	}
+	if cfg.SenderRateLimit > 0 {
+		senders, err := simplelru.NewLRU[uint64, *rate.Limiter](rateLimitedSenders, nil)
+		if err != nil {
+			return nil, err
+		}
+		l.senders = senders
+		l.senderLimit, l.senderBurst = rate.Limit(cfg.SenderRateLimit), rateLimitBurst(cfg.SenderRateLimit)
+	}
+	return l, nil
+}
+
+// rateLimitBurst - allow 1 second worth of txs at once (but at least 1 tx)
+func rateLimitBurst(perSecond float64) int {
+	return int(math.Max(1, math.Ceil(perSecond)))
+}
+
+// admit - takes token for 1 tx of given sender. Sender bucket is checked first:
+// spammer's excess txs must not eat global budget of other senders.
+func (l *admissionLimiter) admit(senderID uint64, now time.Time) txpoolcfg.DiscardReason {
+	if l.senders != nil {
+		bucket, ok := l.senders.Get(senderID)
+		if !ok {
+			bucket = rate.NewLimiter(l.senderLimit, l.senderBurst)
+			l.senders.Add(senderID, bucket)
+		}
+		if !bucket.AllowN(now, 1) {
+			rateLimitedBySenderCounter.Inc()
+			return txpoolcfg.RateLimited
+		}
+	}
+	if l.global != nil && !l.global.AllowN(now, 1) {
+		rateLimitedGlobalCounter.Inc()
+		return txpoolcfg.RateLimited
+	}
+	return txpoolcfg.Success
+}
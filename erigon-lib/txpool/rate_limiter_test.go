@@ -0,0 +1,81 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+)
+
+func TestAdmissionLimiter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("unlimited", func(t *testing.T) {
+		l, err := newAdmissionLimiter(txpoolcfg.DefaultConfig)
+		require.NoError(t, err)
+		for i := 0; i < 1_000; i++ {
+			require.Equal(t, txpoolcfg.Success, l.admit(1, now))
+		}
+	})
+
+	t.Run("sender", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.SenderRateLimit = 2
+		l, err := newAdmissionLimiter(cfg)
+		require.NoError(t, err)
+
+		require.Equal(t, txpoolcfg.Success, l.admit(1, now))
+		require.Equal(t, txpoolcfg.Success, l.admit(1, now))
+		require.Equal(t, txpoolcfg.RateLimited, l.admit(1, now))
+		// other senders have own budget
+		require.Equal(t, txpoolcfg.Success, l.admit(2, now))
+		// bucket refills with time
+		require.Equal(t, txpoolcfg.Success, l.admit(1, now.Add(time.Second)))
+	})
+
+	t.Run("global", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.GlobalRateLimit = 3
+		l, err := newAdmissionLimiter(cfg)
+		require.NoError(t, err)
+
+		for senderID := uint64(1); senderID <= 3; senderID++ {
+			require.Equal(t, txpoolcfg.Success, l.admit(senderID, now))
+		}
+		require.Equal(t, txpoolcfg.RateLimited, l.admit(4, now))
+		require.Equal(t, txpoolcfg.Success, l.admit(4, now.Add(time.Second)))
+	})
+
+	t.Run("sender excess doesn't consume global budget", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.SenderRateLimit = 1
+		cfg.GlobalRateLimit = 2
+		l, err := newAdmissionLimiter(cfg)
+		require.NoError(t, err)
+
+		require.Equal(t, txpoolcfg.Success, l.admit(1, now))
+		for i := 0; i < 10; i++ {
+			require.Equal(t, txpoolcfg.RateLimited, l.admit(1, now))
+		}
+		require.Equal(t, txpoolcfg.Success, l.admit(2, now))
+		require.Equal(t, txpoolcfg.RateLimited, l.admit(3, now))
+	})
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common/u256"
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// ReplacementPolicy - decides whether an incoming txn may replace the pooled one with the same sender and nonce.
+// Called under TxPool.lock, so implementations don't need to be thread-safe.
+type ReplacementPolicy interface {
+	// CanReplace returns txpoolcfg.Success if newTxn may replace oldTxn, or the reason to discard newTxn.
+	// replacements - how many times oldTxn's nonce was already replaced since the first txn was added to the pool.
+	// Returning txpoolcfg.NotReplaced makes the pool re-announce oldTxn if newTxn is local.
+	CanReplace(oldTxn, newTxn *types.TxSlot, replacements uint64) txpoolcfg.DiscardReason
+}
+
+const (
+	PriceBumpReplacementPolicy = "pricebump"
+	BorReplacementPolicy       = "bor"
+)
+
+func newReplacementPolicy(cfg txpoolcfg.Config) (ReplacementPolicy, error) {
+	bump := &PriceBumpPolicy{PriceBump: cfg.PriceBump, BlobPriceBump: cfg.BlobPriceBump}
+	switch cfg.ReplacementPolicy {
+	case "", PriceBumpReplacementPolicy:
+		return bump, nil
+	case BorReplacementPolicy:
+		return &BorPolicy{PriceBumpPolicy: *bump, MaxReplacements: cfg.MaxReplacements}, nil
+	default:
+		return nil, fmt.Errorf("unknown txpool replacement policy: %s", cfg.ReplacementPolicy)
+	}
+}
+
+// PriceBumpPolicy - default policy: tip and fee cap (and blob fee cap for blob txns)
+// must be at least PriceBump (BlobPriceBump for blob txns) percent higher than of the replaced txn
+type PriceBumpPolicy struct {
+	PriceBump     uint64
+	BlobPriceBump uint64
+}
+
+func (p *PriceBumpPolicy) CanReplace(oldTxn, newTxn *types.TxSlot, _ uint64) txpoolcfg.DiscardReason {
+	priceBump := p.PriceBump
+
+	//Blob txn threshold checks for replace txn
+	if newTxn.Type == types.BlobTxType {
+		priceBump = p.BlobPriceBump
+		blobFeeThreshold, overflow := (&uint256.Int{}).MulDivOverflow(
+			&oldTxn.BlobFeeCap,
+			uint256.NewInt(100+priceBump),
+			uint256.NewInt(100),
+		)
+		if newTxn.BlobFeeCap.Lt(blobFeeThreshold) && !overflow {
+			return txpoolcfg.ReplaceUnderpriced // TODO: This is the same as NotReplaced
+		}
+	}
+
+	//Regular txn threshold checks
+	tipThreshold := uint256.NewInt(0)
+	tipThreshold = tipThreshold.Mul(&oldTxn.Tip, uint256.NewInt(100+priceBump))
+	tipThreshold.Div(tipThreshold, u256.N100)
+	feecapThreshold := uint256.NewInt(0)
+	feecapThreshold.Mul(&oldTxn.FeeCap, uint256.NewInt(100+priceBump))
+	feecapThreshold.Div(feecapThreshold, u256.N100)
+	if newTxn.Tip.Cmp(tipThreshold) < 0 || newTxn.FeeCap.Cmp(feecapThreshold) < 0 {
+		// Both tip and feecap need to be larger than previously to replace the transaction
+		return txpoolcfg.NotReplaced
+	}
+	return txpoolcfg.Success
+}
+
+// BorPolicy - price bump policy which also limits how many times a nonce can be replaced.
+// On Bor cheap gas makes it affordable to cycle same-nonce txns through the pool (every replacement is re-gossiped
+// to all peers), so after MaxReplacements the nonce is pinned until its txn is mined or evicted. 0 - unlimited.
+type BorPolicy struct {
+	PriceBumpPolicy
+	MaxReplacements uint64
+}
+
+func (p *BorPolicy) CanReplace(oldTxn, newTxn *types.TxSlot, replacements uint64) txpoolcfg.DiscardReason {
+	if p.MaxReplacements > 0 && replacements >= p.MaxReplacements {
+		return txpoolcfg.TooManyReplacements
+	}
+	return p.PriceBumpPolicy.CanReplace(oldTxn, newTxn, replacements)
+}
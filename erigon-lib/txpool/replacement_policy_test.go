@@ -0,0 +1,80 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package txpool
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+func TestReplacementPolicy(t *testing.T) {
+	txn := func(tip, feeCap, blobFeeCap uint64) *types.TxSlot {
+		slot := &types.TxSlot{Tip: *uint256.NewInt(tip), FeeCap: *uint256.NewInt(feeCap)}
+		if blobFeeCap > 0 {
+			slot.Type = types.BlobTxType
+			slot.BlobFeeCap = *uint256.NewInt(blobFeeCap)
+		}
+		return slot
+	}
+
+	t.Run("pricebump", func(t *testing.T) {
+		p, err := newReplacementPolicy(txpoolcfg.DefaultConfig)
+		require.NoError(t, err)
+		old := txn(100, 1000, 0)
+		require.Equal(t, txpoolcfg.Success, p.CanReplace(old, txn(110, 1100, 0), 100))
+		require.Equal(t, txpoolcfg.NotReplaced, p.CanReplace(old, txn(109, 2000, 0), 0))
+		require.Equal(t, txpoolcfg.NotReplaced, p.CanReplace(old, txn(200, 1099, 0), 0))
+
+		oldBlob := txn(100, 1000, 100)
+		require.Equal(t, txpoolcfg.ReplaceUnderpriced, p.CanReplace(oldBlob, txn(200, 2000, 199), 0))
+		require.Equal(t, txpoolcfg.NotReplaced, p.CanReplace(oldBlob, txn(199, 2000, 200), 0))
+		require.Equal(t, txpoolcfg.Success, p.CanReplace(oldBlob, txn(200, 2000, 200), 0))
+	})
+
+	t.Run("custom bump", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.PriceBump = 50
+		p, err := newReplacementPolicy(cfg)
+		require.NoError(t, err)
+		require.Equal(t, txpoolcfg.NotReplaced, p.CanReplace(txn(100, 1000, 0), txn(149, 1500, 0), 0))
+		require.Equal(t, txpoolcfg.Success, p.CanReplace(txn(100, 1000, 0), txn(150, 1500, 0), 0))
+	})
+
+	t.Run("bor", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.ReplacementPolicy = BorReplacementPolicy
+		cfg.MaxReplacements = 2
+		p, err := newReplacementPolicy(cfg)
+		require.NoError(t, err)
+		old := txn(100, 1000, 0)
+		require.Equal(t, txpoolcfg.Success, p.CanReplace(old, txn(110, 1100, 0), 1))
+		require.Equal(t, txpoolcfg.NotReplaced, p.CanReplace(old, txn(100, 1000, 0), 1))
+		require.Equal(t, txpoolcfg.TooManyReplacements, p.CanReplace(old, txn(1000, 10000, 0), 2))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		cfg := txpoolcfg.DefaultConfig
+		cfg.ReplacementPolicy = "nope"
+		_, err := newReplacementPolicy(cfg)
+		require.Error(t, err)
+	})
+}
@@ -47,7 +47,7 @@ import (
 )
 
 // TxPoolAPIVersion
-var TxPoolAPIVersion = &types2.VersionReply{Major: 1, Minor: 0, Patch: 0}
+var TxPoolAPIVersion = &types2.VersionReply{Major: 1, Minor: 1, Patch: 0}
 
 type txPool interface {
 	ValidateSerializedTxn(serializedTxn []byte) error
@@ -59,6 +59,9 @@ type txPool interface {
 	CountContent() (int, int, int)
 	IdHashKnown(tx kv.Tx, hash []byte) (bool, error)
 	NonceFromAddress(addr [20]byte) (nonce uint64, inPool bool)
+	Locals(tx kv.Tx, f func(sender common.Address, rlp []byte, added uint64)) error
+	Content(tx kv.Tx, filter ContentFilter, f func(rlp []byte, sender common.Address, t SubPoolType)) (next []byte, err error)
+	SubscribeEvents(size int) (<-chan []PoolEvent, func())
 }
 
 var _ txpool_proto.TxpoolServer = (*GrpcServer)(nil)   // compile-time interface check
@@ -97,6 +100,12 @@ func (*GrpcDisabled) Status(ctx context.Context, request *txpool_proto.StatusReq
 func (*GrpcDisabled) Nonce(ctx context.Context, request *txpool_proto.NonceRequest) (*txpool_proto.NonceReply, error) {
 	return nil, ErrPoolDisabled
 }
+func (*GrpcDisabled) Locals(ctx context.Context, empty *emptypb.Empty) (*txpool_proto.LocalsReply, error) {
+	return nil, ErrPoolDisabled
+}
+func (*GrpcDisabled) OnEvent(request *txpool_proto.OnEventRequest, server txpool_proto.Txpool_OnEventServer) error {
+	return ErrPoolDisabled
+}
 
 type GrpcServer struct {
 	txpool_proto.UnimplementedTxpoolServer
@@ -128,7 +137,7 @@ func convertSubPoolType(t SubPoolType) txpool_proto.AllReply_TxnType {
 		panic("unknown")
 	}
 }
-func (s *GrpcServer) All(ctx context.Context, _ *txpool_proto.AllRequest) (*txpool_proto.AllReply, error) {
+func (s *GrpcServer) All(ctx context.Context, in *txpool_proto.AllRequest) (*txpool_proto.AllReply, error) {
 	tx, err := s.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -136,13 +145,34 @@ func (s *GrpcServer) All(ctx context.Context, _ *txpool_proto.AllRequest) (*txpo
 	defer tx.Rollback()
 	reply := &txpool_proto.AllReply{}
 	reply.Txs = make([]*txpool_proto.AllReply_Tx, 0, 32)
-	s.txPool.deprecatedForEach(ctx, func(rlp []byte, sender common.Address, t SubPoolType) {
+	collect := func(rlp []byte, sender common.Address, t SubPoolType) {
 		reply.Txs = append(reply.Txs, &txpool_proto.AllReply_Tx{
 			Sender:  gointerfaces.ConvertAddressToH160(sender),
 			TxnType: convertSubPoolType(t),
 			RlpTx:   common.Copy(rlp),
 		})
-	}, tx)
+	}
+	if len(in.Senders) == 0 && in.MinTip == nil && len(in.TxTypes) == 0 && len(in.Cursor) == 0 && in.Limit == 0 {
+		s.txPool.deprecatedForEach(ctx, collect, tx)
+		return reply, nil
+	}
+
+	filter := ContentFilter{Cursor: in.Cursor, Limit: int(in.Limit)}
+	for _, sender := range in.Senders {
+		filter.Senders = append(filter.Senders, gointerfaces.ConvertH160toAddress(sender))
+	}
+	if in.MinTip != nil {
+		filter.MinTip = gointerfaces.ConvertH256ToUint256Int(in.MinTip)
+	}
+	for _, t := range in.TxTypes {
+		if t > math.MaxUint8 {
+			return nil, fmt.Errorf("invalid transaction type %d", t)
+		}
+		filter.Types = append(filter.Types, byte(t))
+	}
+	if reply.NextCursor, err = s.txPool.Content(tx, filter, collect); err != nil {
+		return nil, err
+	}
 	return reply, nil
 }
 
@@ -170,6 +200,25 @@ func (s *GrpcServer) Pending(ctx context.Context, _ *emptypb.Empty) (*txpool_pro
 	return reply, nil
 }
 
+func (s *GrpcServer) Locals(ctx context.Context, _ *emptypb.Empty) (*txpool_proto.LocalsReply, error) {
+	tx, err := s.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	reply := &txpool_proto.LocalsReply{}
+	if err := s.txPool.Locals(tx, func(sender common.Address, rlp []byte, added uint64) {
+		reply.Txs = append(reply.Txs, &txpool_proto.LocalsReply_Tx{
+			Sender: gointerfaces.ConvertAddressToH160(sender),
+			RlpTx:  common.Copy(rlp),
+			Added:  added,
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
 func (s *GrpcServer) FindUnknown(ctx context.Context, in *txpool_proto.TxHashes) (*txpool_proto.TxHashes, error) {
 	return nil, fmt.Errorf("unimplemented")
 }
@@ -260,6 +309,41 @@ func (s *GrpcServer) OnAdd(req *txpool_proto.OnAddRequest, stream txpool_proto.T
 	}
 }
 
+func (s *GrpcServer) OnEvent(req *txpool_proto.OnEventRequest, stream txpool_proto.Txpool_OnEventServer) error {
+	s.logger.Info("New pool events subscriber joined")
+	events, unsubscribe := s.txPool.SubscribeEvents(128)
+	defer unsubscribe()
+	for {
+		select {
+		case batch, ok := <-events:
+			if !ok {
+				return errors.New("pool events subscriber is too slow")
+			}
+			reply := &txpool_proto.OnEventReply{Events: make([]*txpool_proto.OnEventReply_Event, 0, len(batch))}
+			for _, ev := range batch {
+				event := &txpool_proto.OnEventReply_Event{
+					Type:   txpool_proto.OnEventReply_ADDED,
+					Hash:   gointerfaces.ConvertHashToH256(ev.Hash),
+					Sender: gointerfaces.ConvertAddressToH160(ev.Sender),
+					RlpTx:  ev.Rlp,
+				}
+				if ev.Dropped {
+					event.Type = txpool_proto.OnEventReply_DROPPED
+					event.Reason = ev.Reason.String()
+				}
+				reply.Events = append(reply.Events, event)
+			}
+			if err := stream.Send(reply); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+}
+
 func (s *GrpcServer) Transactions(ctx context.Context, in *txpool_proto.TransactionsRequest) (*txpool_proto.TransactionsReply, error) {
 	tx, err := s.db.BeginRo(ctx)
 	if err != nil {
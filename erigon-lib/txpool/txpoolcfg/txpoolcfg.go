@@ -44,12 +44,18 @@ type Config struct {
 	TotalBlobPoolLimit  uint64 // Total number of blobs (not txs) allowed within the txpool
 	PriceBump           uint64 // Price bump percentage to replace an already existing transaction
 	BlobPriceBump       uint64 //Price bump percentage to replace an existing 4844 blob tx (type-3)
+	ReplacementPolicy   string // Which txpool.ReplacementPolicy decides if a tx can replace a pooled one: "pricebump" (default) or "bor"
+	MaxReplacements     uint64 // "bor" replacement policy: how many times a nonce can be replaced, 0 - unlimited
 
 	// regular batch tasks processing
-	SyncToNewPeersEvery   time.Duration
-	ProcessRemoteTxsEvery time.Duration
-	CommitEvery           time.Duration
-	LogEvery              time.Duration
+	SyncToNewPeersEvery    time.Duration
+	ProcessRemoteTxsEvery  time.Duration
+	CommitEvery            time.Duration
+	LogEvery               time.Duration
+	RebroadcastLocalsEvery time.Duration
+
+	// LocalsLifetime - how long txs submitted to this node are journaled: re-broadcast and protected from eviction
+	LocalsLifetime time.Duration
 
 	//txpool db
 	MdbxPageSize    datasize.ByteSize
@@ -57,13 +63,23 @@ type Config struct {
 	MdbxGrowthStep  datasize.ByteSize
 
 	NoGossip bool // this mode doesn't broadcast any txs, and if receive remote-txn - skip it
+
+	// rate limits of remote txs admission (local txs are not limited), 0 - unlimited
+	SenderRateLimit float64 // max new txs per second from one sender
+	GlobalRateLimit float64 // max new txs per second from all senders
+
+	// priority lane: txs of these senders bypass pool limits and go first into blocks (e.g. Bor validators operational txs)
+	PrioritySenders      []common.Address
+	PriorityLaneGasLimit uint64 // max gas (by txs gas limits) of priority txs per block, 0 - unlimited
 }
 
 var DefaultConfig = Config{
-	SyncToNewPeersEvery:   5 * time.Second,
-	ProcessRemoteTxsEvery: 100 * time.Millisecond,
-	CommitEvery:           15 * time.Second,
-	LogEvery:              30 * time.Second,
+	SyncToNewPeersEvery:    5 * time.Second,
+	ProcessRemoteTxsEvery:  100 * time.Millisecond,
+	CommitEvery:            15 * time.Second,
+	LogEvery:               30 * time.Second,
+	RebroadcastLocalsEvery: time.Minute,
+	LocalsLifetime:         3 * time.Hour,
 
 	PendingSubPoolLimit: 10_000,
 	BaseFeeSubPoolLimit: 10_000,
@@ -75,8 +91,15 @@ var DefaultConfig = Config{
 	TotalBlobPoolLimit: 480, // Default for a total of 10 different accounts hitting the above limit
 	PriceBump:          10,  // Price bump percentage to replace an already existing transaction
 	BlobPriceBump:      100,
+	ReplacementPolicy:  "pricebump",
+	MaxReplacements:    4,
 
 	NoGossip: false,
+
+	SenderRateLimit: 0,
+	GlobalRateLimit: 0,
+
+	PriorityLaneGasLimit: 5_000_000,
 }
 
 type DiscardReason uint8
@@ -114,6 +137,8 @@ const (
 	UnmatchedBlobTxExt  DiscardReason = 29 // KZGcommitments must match the corresponding blobs and proofs
 	BlobTxReplace       DiscardReason = 30 // Cannot replace type-3 blob txn with another type of txn
 	BlobPoolOverflow    DiscardReason = 31 // The total number of blobs (through blob txs) in the pool has reached its limit
+	RateLimited         DiscardReason = 32 // Sender or the whole pool exceeded admission rate of remote txs
+	TooManyReplacements DiscardReason = 33 // Replacement policy doesn't allow to replace the txn with same sender and nonce anymore
 
 )
 
@@ -177,6 +202,10 @@ func (r DiscardReason) String() string {
 		return "can't replace blob-txn with a non-blob-txn"
 	case BlobPoolOverflow:
 		return "blobs limit in txpool is full"
+	case RateLimited:
+		return "rate limited"
+	case TooManyReplacements:
+		return "too many replacements of transaction with the same nonce"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -0,0 +1,114 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package statecodec - encodings of account and storage values in AccountsDomain/StorageDomain (and their histories).
+// Readers/writers of the domains must go through Active(), so the flat state layout can change
+// (for example before a verkle tree migration) without touching every one of them.
+package statecodec
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// StateCodec - encoding of AccountsDomain and StorageDomain values. Empty value always means "no account/slot"
+// and is never passed to Decode*/returned by Encode*.
+type StateCodec interface {
+	Name() string
+	// EncodeAccount - codeHash is nil or empty for accounts without code
+	EncodeAccount(nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64) ([]byte, error)
+	// DecodeAccount - codeHash is nil for accounts without code, may be sub-slice of enc
+	DecodeAccount(enc []byte) (nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64, err error)
+	// EncodeStorage - value is big-endian without leading zeros (as produced by uint256.Int.Bytes())
+	EncodeStorage(value []byte) []byte
+	// DecodeStorage - returns value without leading zeros, may return sub-slice of enc
+	DecodeStorage(enc []byte) []byte
+}
+
+var codecs = map[string]StateCodec{}
+
+// Register - makes codec available by name. Not thread-safe, must be called from init().
+func Register(c StateCodec) {
+	if _, ok := codecs[c.Name()]; ok {
+		panic("statecodec: codec registered twice: " + c.Name())
+	}
+	codecs[c.Name()] = c
+}
+
+func ByName(name string) (StateCodec, error) {
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("statecodec: unknown codec %q, available: %v", name, Names())
+	}
+	return c, nil
+}
+
+func Names() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var active atomic.Pointer[StateCodec]
+
+// Active - codec of the opened chaindata. V3 until SetActive is called.
+func Active() StateCodec {
+	if c := active.Load(); c != nil {
+		return *c
+	}
+	return V3
+}
+
+// SetActive - switches codec of the whole process. Must be called right after opening chaindata,
+// before any state is read or written.
+func SetActive(c StateCodec) { active.Store(&c) }
+
+// dbKey - name of the codec in kv.DatabaseInfo. Absent for databases created before codecs were introduced - means V3.
+var dbKey = []byte("stateCodec")
+
+// ReadFromDB - codec chaindata was written with
+func ReadFromDB(tx kv.Getter) (StateCodec, error) {
+	v, err := tx.GetOne(kv.DatabaseInfo, dbKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return V3, nil
+	}
+	return ByName(string(v))
+}
+
+func WriteToDB(tx kv.Putter, c StateCodec) error {
+	return tx.Put(kv.DatabaseInfo, dbKey, []byte(c.Name()))
+}
+
+// LoadActive - reads codec of chaindata and makes it active
+func LoadActive(tx kv.Getter) (StateCodec, error) {
+	c, err := ReadFromDB(tx)
+	if err != nil {
+		return nil, err
+	}
+	SetActive(c)
+	return c, nil
+}
@@ -0,0 +1,171 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statecodec
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+func TestAccountRoundTrip(t *testing.T) {
+	codeHash := common.HexToHash("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470").Bytes()
+	cases := []struct {
+		nonce       uint64
+		balance     *uint256.Int
+		codeHash    []byte
+		incarnation uint64
+	}{
+		{0, uint256.NewInt(0), nil, 0},
+		{1, uint256.NewInt(1), nil, 0},
+		{^uint64(0), new(uint256.Int).Lsh(uint256.NewInt(1), 127), codeHash, 1},
+		{42, uint256.NewInt(1e18), codeHash, ^uint64(0)},
+	}
+	for _, c := range []StateCodec{V3, Verkle} {
+		c := c
+		t.Run(c.Name(), func(t *testing.T) {
+			for _, tc := range cases {
+				enc, err := c.EncodeAccount(tc.nonce, tc.balance, tc.codeHash, tc.incarnation)
+				require.NoError(t, err)
+				nonce, balance, ch, incarnation, err := c.DecodeAccount(enc)
+				require.NoError(t, err)
+				require.Equal(t, tc.nonce, nonce)
+				require.Equal(t, tc.balance, balance)
+				require.Equal(t, tc.codeHash, ch)
+				require.Equal(t, tc.incarnation, incarnation)
+			}
+			_, _, _, _, err := c.DecodeAccount([]byte{1})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestVerkleAccountLayout(t *testing.T) {
+	enc, err := Verkle.EncodeAccount(0x0102, uint256.NewInt(0x0304), nil, 0)
+	require.NoError(t, err)
+	require.Len(t, enc, 64)
+	require.Equal(t, hexutility.MustDecodeHex("0x0000000000000000000000000000010200000000000000000000000000000304"), enc[:32])
+	require.Equal(t, make([]byte, 32), enc[32:])
+
+	_, err = Verkle.EncodeAccount(0, new(uint256.Int).Lsh(uint256.NewInt(1), 128), nil, 0)
+	require.Error(t, err)
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	for _, c := range []StateCodec{V3, Verkle} {
+		for _, v := range [][]byte{{1}, {0}, {1, 2, 3}, common.HexToHash("0xff").Bytes()[1:], common.HexToHash("0x01").Bytes()} {
+			trimmed := v
+			for len(trimmed) > 1 && trimmed[0] == 0 {
+				trimmed = trimmed[1:]
+			}
+			enc := c.EncodeStorage(trimmed)
+			require.Equal(t, trimmed, c.DecodeStorage(enc), c.Name())
+		}
+	}
+	require.Len(t, Verkle.EncodeStorage([]byte{1}), 32)
+	require.Empty(t, Verkle.EncodeStorage(nil))
+}
+
+func TestByName(t *testing.T) {
+	require.Equal(t, []string{"v3", "verkle"}, Names())
+	c, err := ByName("verkle")
+	require.NoError(t, err)
+	require.Equal(t, Verkle, c)
+	_, err = ByName("unknown")
+	require.Error(t, err)
+}
+
+func TestReencode(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	c, err := ReadFromDB(tx)
+	require.NoError(t, err)
+	require.Equal(t, V3, c)
+
+	addr := common.HexToAddress("0x01").Bytes()
+	step := make([]byte, 8)
+	binary.BigEndian.PutUint64(step, ^uint64(1))
+	txNum := func(n uint64) []byte { return binary.BigEndian.AppendUint64(nil, n) }
+	acc := func(nonce uint64) []byte {
+		enc, err := V3.EncodeAccount(nonce, uint256.NewInt(nonce*10), nil, 0)
+		require.NoError(t, err)
+		return enc
+	}
+	slot := append(common.Copy(addr), common.HexToHash("0x02").Bytes()...)
+
+	require.NoError(t, tx.Put(kv.TblAccountVals, append(common.Copy(addr), step...), acc(2)))
+	require.NoError(t, tx.Put(kv.TblStorageVals, append(common.Copy(slot), step...), []byte{7}))
+	require.NoError(t, tx.Put(kv.TblStorageVals, append(common.Copy(addr), step...), nil)) // deleted
+	require.NoError(t, tx.Put(kv.TblAccountHistoryVals, addr, txNum(1)))                    // didn't exist
+	require.NoError(t, tx.Put(kv.TblAccountHistoryVals, addr, append(txNum(2), acc(1)...)))
+	require.NoError(t, tx.Put(kv.TblStorageHistoryVals, slot, append(txNum(3), 5)))
+
+	require.NoError(t, Reencode(context.Background(), tx, V3, Verkle, log.New()))
+	c, err = ReadFromDB(tx)
+	require.NoError(t, err)
+	require.Equal(t, Verkle, c)
+
+	v, err := tx.GetOne(kv.TblAccountVals, append(common.Copy(addr), step...))
+	require.NoError(t, err)
+	nonce, balance, _, _, err := Verkle.DecodeAccount(v)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), nonce)
+	require.Equal(t, uint64(20), balance.Uint64())
+
+	v, err = tx.GetOne(kv.TblStorageVals, append(common.Copy(slot), step...))
+	require.NoError(t, err)
+	require.Len(t, v, 32)
+	require.Equal(t, []byte{7}, Verkle.DecodeStorage(v))
+	v, err = tx.GetOne(kv.TblStorageVals, append(common.Copy(addr), step...))
+	require.NoError(t, err)
+	require.Empty(t, v)
+
+	var history [][]byte
+	require.NoError(t, tx.ForEach(kv.TblAccountHistoryVals, nil, func(k, v []byte) error {
+		history = append(history, common.Copy(v))
+		return nil
+	}))
+	require.Len(t, history, 2)
+	require.Equal(t, txNum(1), history[0])
+	require.Equal(t, txNum(2), history[1][:8])
+	nonce, _, _, _, err = Verkle.DecodeAccount(history[1][8:])
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nonce)
+
+	v, err = tx.GetOne(kv.TblStorageHistoryVals, slot)
+	require.NoError(t, err)
+	require.Equal(t, txNum(3), v[:8])
+	require.Equal(t, []byte{5}, Verkle.DecodeStorage(v[8:]))
+
+	// and back
+	require.NoError(t, Reencode(context.Background(), tx, Verkle, V3, log.New()))
+	v, err = tx.GetOne(kv.TblAccountVals, append(common.Copy(addr), step...))
+	require.NoError(t, err)
+	require.Equal(t, acc(2), v)
+	v, err = tx.GetOne(kv.TblStorageHistoryVals, slot)
+	require.NoError(t, err)
+	require.Equal(t, append(txNum(3), 5), v)
+}
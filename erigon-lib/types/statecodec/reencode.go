@@ -0,0 +1,159 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statecodec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// Reencode - offline migration of chaindata from one codec to another: re-encodes values of AccountsDomain,
+// StorageDomain and their histories, then records `to` in kv.DatabaseInfo.
+// Only DB is migrated - frozen state files are encoded with the codec they were built with,
+// so caller must ensure there are none.
+func Reencode(ctx context.Context, tx kv.RwTx, from, to StateCodec, logger log.Logger) error {
+	if from.Name() == to.Name() {
+		return nil
+	}
+	account := func(v []byte) ([]byte, error) {
+		nonce, balance, codeHash, incarnation, err := from.DecodeAccount(v)
+		if err != nil {
+			return nil, err
+		}
+		return to.EncodeAccount(nonce, balance, codeHash, incarnation)
+	}
+	storage := func(v []byte) ([]byte, error) {
+		return common.Copy(to.EncodeStorage(from.DecodeStorage(v))), nil
+	}
+
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+	for _, t := range []struct {
+		table   string
+		history bool
+		recode  func([]byte) ([]byte, error)
+	}{
+		{kv.TblAccountVals, false, account},
+		{kv.TblStorageVals, false, storage},
+		{kv.TblAccountHistoryVals, true, account},
+		{kv.TblStorageHistoryVals, true, storage},
+	} {
+		var n int
+		var err error
+		if t.history {
+			n, err = reencodeHistory(ctx, tx, t.table, t.recode, logEvery, logger)
+		} else {
+			n, err = reencodeVals(ctx, tx, t.table, t.recode, logEvery, logger)
+		}
+		if err != nil {
+			return fmt.Errorf("statecodec: re-encode %s from %s to %s: %w", t.table, from.Name(), to.Name(), err)
+		}
+		logger.Info("[statecodec] re-encoded", "table", t.table, "values", n, "from", from.Name(), "to", to.Name())
+	}
+	return WriteToDB(tx, to)
+}
+
+// reencodeVals - domain values: key+^step -> value, empty value is a deletion marker
+func reencodeVals(ctx context.Context, tx kv.RwTx, table string, recode func([]byte) ([]byte, error), logEvery *time.Ticker, logger log.Logger) (n int, err error) {
+	c, err := tx.RwCursor(table)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	for k, v, err := c.First(); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return n, err
+		}
+		if len(v) == 0 {
+			continue
+		}
+		newV, err := recode(v)
+		if err != nil {
+			return n, fmt.Errorf("key %x: %w", k, err)
+		}
+		if err := c.Put(common.Copy(k), newV); err != nil {
+			return n, err
+		}
+		n++
+		select {
+		case <-ctx.Done():
+			return n, ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[statecodec] re-encoding", "table", table, "values", n, "key", fmt.Sprintf("%x", k))
+		default:
+		}
+	}
+	return n, nil
+}
+
+// reencodeHistory - DupSort history values: key -> txNum(8)+value, empty value means "didn't exist"
+func reencodeHistory(ctx context.Context, tx kv.RwTx, table string, recode func([]byte) ([]byte, error), logEvery *time.Ticker, logger log.Logger) (n int, err error) {
+	c, err := tx.RwCursorDupSort(table)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+	var dups [][]byte
+	for k, v, err := c.First(); k != nil; k, v, err = c.NextNoDup() {
+		if err != nil {
+			return n, err
+		}
+		k = common.Copy(k)
+		dups = dups[:0]
+		for ; v != nil; _, v, err = c.NextDup() {
+			if err != nil {
+				return n, err
+			}
+			if len(v) < 8 {
+				return n, fmt.Errorf("key %x: history value too short: %x", k, v)
+			}
+			newV := common.Copy(v)
+			if len(v) > 8 {
+				recoded, err := recode(v[8:])
+				if err != nil {
+					return n, fmt.Errorf("key %x, txNum %x: %w", k, v[:8], err)
+				}
+				newV = append(newV[:8], recoded...)
+				n++
+			}
+			dups = append(dups, newV)
+		}
+		if err := c.DeleteCurrentDuplicates(); err != nil {
+			return n, err
+		}
+		for _, v := range dups {
+			if err := c.Put(k, v); err != nil {
+				return n, err
+			}
+		}
+		// cursor is positioned on the last put duplicate, NextNoDup moves to the next key
+		select {
+		case <-ctx.Done():
+			return n, ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[statecodec] re-encoding", "table", table, "values", n, "key", fmt.Sprintf("%x", k))
+		default:
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statecodec
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/types"
+)
+
+// V3 - default codec. Account: length-prefixed nonce, balance, code hash and incarnation without leading zeros.
+// Storage: value as is.
+var V3 StateCodec = v3Codec{}
+
+func init() { Register(V3) }
+
+type v3Codec struct{}
+
+func (v3Codec) Name() string { return "v3" }
+
+func (v3Codec) EncodeAccount(nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64) ([]byte, error) {
+	return types.EncodeAccountBytesV3(nonce, balance, codeHash, incarnation), nil
+}
+
+func (v3Codec) DecodeAccount(enc []byte) (nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64, err error) {
+	balance = new(uint256.Int)
+	pos := 0
+	field := func() ([]byte, error) {
+		if pos >= len(enc) {
+			return nil, fmt.Errorf("statecodec v3: account too short: %x", enc)
+		}
+		l := int(enc[pos])
+		pos++
+		if pos+l > len(enc) {
+			return nil, fmt.Errorf("statecodec v3: account too short: %x", enc)
+		}
+		pos += l
+		return enc[pos-l : pos], nil
+	}
+	var b []byte
+	if b, err = field(); err != nil {
+		return
+	}
+	if len(b) > 8 {
+		return 0, nil, nil, 0, fmt.Errorf("statecodec v3: nonce overflow: %x", enc)
+	}
+	nonce = bytesToUint64(b)
+	if b, err = field(); err != nil {
+		return
+	}
+	if len(b) > 32 {
+		return 0, nil, nil, 0, fmt.Errorf("statecodec v3: balance overflow: %x", enc)
+	}
+	balance.SetBytes(b)
+	if b, err = field(); err != nil {
+		return
+	}
+	if len(b) == length.Hash {
+		codeHash = b
+	}
+	if b, err = field(); err != nil {
+		return
+	}
+	if len(b) > 8 {
+		return 0, nil, nil, 0, fmt.Errorf("statecodec v3: incarnation overflow: %x", enc)
+	}
+	incarnation = bytesToUint64(b)
+	return
+}
+
+func (v3Codec) EncodeStorage(value []byte) []byte { return value }
+func (v3Codec) DecodeStorage(enc []byte) []byte   { return enc }
+
+func bytesToUint64(buf []byte) (x uint64) {
+	for _, b := range buf {
+		x = x<<8 + uint64(b)
+	}
+	return
+}
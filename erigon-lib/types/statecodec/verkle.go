@@ -0,0 +1,123 @@
+/*
+   Copyright 2024 The Erigon contributors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package statecodec
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/common/length"
+)
+
+// Verkle - experimental codec with fixed-size values in the layout of verkle tree leaves (EIP-6800/EIP-7545),
+// so a future verkle commitment can take them from the domains without re-encoding:
+//
+//	account: basic data leaf (32 bytes) | code hash leaf (32 bytes) | [incarnation (8 bytes), if not 0]
+//	basic data leaf: version (1) | reserved (4) | code size (3) | nonce (8) | balance (16), big-endian
+//	storage: value left-padded to 32 bytes
+//
+// Code size is not known to the account writers and is always 0 for now. Balances above 2^128-1 can't be encoded.
+var Verkle StateCodec = verkleCodec{}
+
+func init() { Register(Verkle) }
+
+const (
+	verkleVersion        = 0
+	verkleLeafSize       = 32
+	verkleNonceOffset    = 8
+	verkleBalanceOffset  = 16
+	verkleAccountSize    = 2 * verkleLeafSize
+	verkleIncarnationLen = 8
+)
+
+type verkleCodec struct{}
+
+func (verkleCodec) Name() string { return "verkle" }
+
+func (verkleCodec) EncodeAccount(nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64) ([]byte, error) {
+	if balance.BitLen() > 128 {
+		return nil, fmt.Errorf("statecodec verkle: balance doesn't fit 16 bytes: %d", balance)
+	}
+	l := verkleAccountSize
+	if incarnation > 0 {
+		l += verkleIncarnationLen
+	}
+	enc := make([]byte, l)
+	enc[0] = verkleVersion
+	binary.BigEndian.PutUint64(enc[verkleNonceOffset:], nonce)
+	var b [32]byte
+	balance.WriteToArray32(&b)
+	copy(enc[verkleBalanceOffset:verkleLeafSize], b[16:])
+	if len(codeHash) == length.Hash {
+		copy(enc[verkleLeafSize:], codeHash)
+	}
+	if incarnation > 0 {
+		binary.BigEndian.PutUint64(enc[verkleAccountSize:], incarnation)
+	}
+	return enc, nil
+}
+
+func (verkleCodec) DecodeAccount(enc []byte) (nonce uint64, balance *uint256.Int, codeHash []byte, incarnation uint64, err error) {
+	switch len(enc) {
+	case verkleAccountSize:
+	case verkleAccountSize + verkleIncarnationLen:
+		incarnation = binary.BigEndian.Uint64(enc[verkleAccountSize:])
+	default:
+		return 0, nil, nil, 0, fmt.Errorf("statecodec verkle: unexpected account length %d: %x", len(enc), enc)
+	}
+	if enc[0] != verkleVersion {
+		return 0, nil, nil, 0, fmt.Errorf("statecodec verkle: unsupported account version %d", enc[0])
+	}
+	nonce = binary.BigEndian.Uint64(enc[verkleNonceOffset:])
+	balance = new(uint256.Int).SetBytes(enc[verkleBalanceOffset:verkleLeafSize])
+	if h := enc[verkleLeafSize:verkleAccountSize]; !isZero(h) {
+		codeHash = h
+	}
+	return nonce, balance, codeHash, incarnation, nil
+}
+
+func (verkleCodec) EncodeStorage(value []byte) []byte {
+	if len(value) == 0 || len(value) >= verkleLeafSize {
+		return value
+	}
+	enc := make([]byte, verkleLeafSize)
+	copy(enc[verkleLeafSize-len(value):], value)
+	return enc
+}
+
+func (verkleCodec) DecodeStorage(enc []byte) []byte {
+	for i, b := range enc {
+		if b != 0 {
+			return enc[i:]
+		}
+	}
+	if len(enc) == 0 {
+		return enc
+	}
+	return enc[len(enc)-1:] // all-zero value: keep 1 byte, empty value means deleted slot
+}
+
+func isZero(b []byte) bool {
+	for _, x := range b {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package aapoolcfg
+
+import (
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// EntryPointV06 - canonical address of the ERC-4337 v0.6 EntryPoint contract
+var EntryPointV06 = libcommon.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+// Config of the account abstraction lane: user operations kept aside of the txpool and bundled by the
+// block producer into a single EntryPoint.handleOps transaction
+type Config struct {
+	Enabled         bool
+	EntryPoint      libcommon.Address
+	MaxOps          int           // max user operations in the pool
+	MaxOpsPerSender int           // max pending user operations of one sender (account)
+	BundleSize      int           // max user operations in a bundle of a block
+	Lifetime        time.Duration // user operations not bundled for this long are dropped, 0 - never
+}
+
+var DefaultConfig = Config{
+	EntryPoint:      EntryPointV06,
+	MaxOps:          4096,
+	MaxOpsPerSender: 4,
+	BundleSize:      32,
+	Lifetime:        time.Hour,
+}
@@ -0,0 +1,105 @@
+package aapool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/accounts/abi"
+)
+
+const userOpTuple = `{"type":"tuple","name":"userOp","components":[
+	{"name":"sender","type":"address"},{"name":"nonce","type":"uint256"},{"name":"initCode","type":"bytes"},
+	{"name":"callData","type":"bytes"},{"name":"callGasLimit","type":"uint256"},{"name":"verificationGasLimit","type":"uint256"},
+	{"name":"preVerificationGas","type":"uint256"},{"name":"maxFeePerGas","type":"uint256"},{"name":"maxPriorityFeePerGas","type":"uint256"},
+	{"name":"paymasterAndData","type":"bytes"},{"name":"signature","type":"bytes"}]}`
+
+const stakeInfoTuple = `{"type":"tuple","components":[{"name":"stake","type":"uint256"},{"name":"unstakeDelaySec","type":"uint256"}]}`
+
+// entryPointABIJSON - subset of EntryPoint v0.6 ABI used by the lane
+var entryPointABIJSON = `[
+{"type":"function","name":"handleOps","inputs":[` + strings.Replace(userOpTuple, `"tuple","name":"userOp"`, `"tuple[]","name":"ops"`, 1) + `,{"name":"beneficiary","type":"address"}],"outputs":[]},
+{"type":"function","name":"simulateValidation","inputs":[` + userOpTuple + `],"outputs":[]},
+{"type":"error","name":"FailedOp","inputs":[{"name":"opIndex","type":"uint256"},{"name":"reason","type":"string"}]},
+{"type":"error","name":"ValidationResult","inputs":[
+	{"name":"returnInfo","type":"tuple","components":[{"name":"preOpGas","type":"uint256"},{"name":"prefund","type":"uint256"},
+		{"name":"sigFailed","type":"bool"},{"name":"validAfter","type":"uint48"},{"name":"validUntil","type":"uint48"},{"name":"paymasterContext","type":"bytes"}]},
+	` + stakeInfoTuple + `,` + stakeInfoTuple + `,` + stakeInfoTuple + `]}
+]`
+
+var entryPointABI, _ = abi.JSON(strings.NewReader(entryPointABIJSON))
+
+// PackHandleOps - calldata of EntryPoint.handleOps bundling ops, fees are paid to beneficiary
+func PackHandleOps(ops []*UserOperation, beneficiary libcommon.Address) ([]byte, error) {
+	abiOps := make([]abiUserOp, len(ops))
+	for i, op := range ops {
+		abiOps[i] = op.toABI()
+	}
+	return entryPointABI.Pack("handleOps", abiOps, beneficiary)
+}
+
+// PackSimulateValidation - calldata of EntryPoint.simulateValidation, the call always reverts
+func PackSimulateValidation(op *UserOperation) ([]byte, error) {
+	return entryPointABI.Pack("simulateValidation", op.toABI())
+}
+
+// ValidationResult - outcome of successful validation phase of user operation
+type ValidationResult struct {
+	PreOpGas   *big.Int
+	Prefund    *big.Int
+	SigFailed  bool
+	ValidAfter uint64
+	ValidUntil uint64
+}
+
+// validationReturnInfo - returnInfo tuple of ValidationResult revert
+type validationReturnInfo struct {
+	PreOpGas         *big.Int
+	Prefund          *big.Int
+	SigFailed        bool
+	ValidAfter       *big.Int
+	ValidUntil       *big.Int
+	PaymasterContext []byte
+}
+
+var ErrValidation = errors.New("user operation validation failed")
+
+// UnpackValidationRevert interprets revert data of simulateValidation: a ValidationResult revert means
+// the operation passed validation, FailedOp and other reverts are returned as errors
+func UnpackValidationRevert(revertData []byte) (*ValidationResult, error) {
+	if len(revertData) < 4 {
+		return nil, fmt.Errorf("%w: simulateValidation didn't revert", ErrValidation)
+	}
+
+	if failedOp, ok := entryPointABI.Errors["FailedOp"]; ok && bytes.Equal(revertData[:4], failedOp.ID[:4]) {
+		args, err := failedOp.Unpack(revertData)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrValidation, args.([]interface{})[1])
+	}
+
+	validationResult := entryPointABI.Errors["ValidationResult"]
+	if !bytes.Equal(revertData[:4], validationResult.ID[:4]) {
+		return nil, fmt.Errorf("%w: unexpected revert 0x%x", ErrValidation, revertData)
+	}
+
+	args, err := validationResult.Unpack(revertData)
+	if err != nil {
+		return nil, err
+	}
+
+	returnInfo := abi.ConvertType(args.([]interface{})[0], new(validationReturnInfo)).(*validationReturnInfo)
+
+	return &ValidationResult{
+		PreOpGas:   returnInfo.PreOpGas,
+		Prefund:    returnInfo.Prefund,
+		SigFailed:  returnInfo.SigFailed,
+		ValidAfter: returnInfo.ValidAfter.Uint64(),
+		ValidUntil: returnInfo.ValidUntil.Uint64(),
+	}, nil
+}
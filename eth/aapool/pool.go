@@ -0,0 +1,229 @@
+package aapool
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/eth/aapool/aapoolcfg"
+)
+
+var (
+	ErrKnownOp      = errors.New("user operation already known")
+	ErrPoolFull     = errors.New("user operation pool is full")
+	ErrSenderLimit  = errors.New("too many pending user operations of sender")
+	ErrUnderpriced  = errors.New("replacement user operation underpriced")
+	ErrInvalidOpGas = errors.New("user operation gas overflow")
+)
+
+// replacementBump - percentage by which both fees of a replacing operation must be higher
+const replacementBump = 10
+
+type poolOp struct {
+	op         *UserOperation
+	hash       libcommon.Hash
+	validUntil uint64 // unix time the op expires at as reported by validation, 0 if it doesn't expire
+	addedAt    uint64 // unix time the op was added at
+	includedAt uint64 // block number of a successful bundle the op was included into, 0 if not bundled yet
+}
+
+// Pool - account abstraction lane: validated user operations waiting to be bundled by the block producer.
+// An operation is dropped once the block it was bundled into (or any other block at that height) becomes
+// the parent of a produced block, once its validUntil or the pool's Lifetime passes, or once it fails
+// validation again while the bundle is built (e.g. its nonce was used by another bundler).
+//
+// Operations aren't propagated to peers: the ERC-4337 p2p mempool requires the ERC-7562 opcode, storage
+// and reputation rules to be enforced on every op received, the lane only runs EntryPoint.simulateValidation.
+// Without those rules a peer could flood the pool with ops which pass validation but are invalidated
+// together by one state change, so the lane only accepts ops over RPC of the block producer.
+type Pool struct {
+	cfg     aapoolcfg.Config
+	chainID *big.Int
+
+	lock     sync.Mutex
+	ops      map[libcommon.Hash]*poolOp
+	bySender map[libcommon.Address]map[libcommon.Hash]*poolOp // by nonce
+}
+
+func New(cfg aapoolcfg.Config, chainID *big.Int) *Pool {
+	return &Pool{
+		cfg:      cfg,
+		chainID:  chainID,
+		ops:      map[libcommon.Hash]*poolOp{},
+		bySender: map[libcommon.Address]map[libcommon.Hash]*poolOp{},
+	}
+}
+
+func (p *Pool) EntryPoint() libcommon.Address { return p.cfg.EntryPoint }
+func (p *Pool) ChainID() *big.Int             { return p.chainID }
+
+// Hash - hash of op for the pool's entry point and chain
+func (p *Pool) Hash(op *UserOperation) libcommon.Hash {
+	return op.Hash(p.cfg.EntryPoint, p.chainID)
+}
+
+func nonceKey(op *UserOperation) libcommon.Hash {
+	return libcommon.BytesToHash(common.LeftPadBytes(bigOrZero(op.Nonce).Bytes(), 32))
+}
+
+// Add puts an already validated operation into the pool, an operation of same sender and nonce is replaced
+// if both fees of op are higher by replacementBump percent. validUntil is the one reported by validation.
+func (p *Pool) Add(op *UserOperation, validUntil uint64) (libcommon.Hash, error) {
+	hash := p.Hash(op)
+	if op.Gas() == ^uint64(0) {
+		return hash, ErrInvalidOpGas
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.ops[hash]; ok {
+		return hash, ErrKnownOp
+	}
+
+	senderOps := p.bySender[op.Sender]
+	if old, ok := senderOps[nonceKey(op)]; ok {
+		if !bumped(old.op.MaxFeePerGas, op.MaxFeePerGas) || !bumped(old.op.MaxPriorityFeePerGas, op.MaxPriorityFeePerGas) {
+			return hash, ErrUnderpriced
+		}
+		p.remove(old)
+	} else {
+		if len(p.ops) >= p.cfg.MaxOps {
+			return hash, ErrPoolFull
+		}
+		if len(senderOps) >= p.cfg.MaxOpsPerSender {
+			return hash, ErrSenderLimit
+		}
+	}
+
+	if p.bySender[op.Sender] == nil {
+		p.bySender[op.Sender] = map[libcommon.Hash]*poolOp{}
+	}
+	pop := &poolOp{op: op, hash: hash, validUntil: validUntil, addedAt: uint64(time.Now().Unix())}
+	p.ops[hash] = pop
+	p.bySender[op.Sender][nonceKey(op)] = pop
+	return hash, nil
+}
+
+func bumped(oldFee, newFee *hexutil.Big) bool {
+	threshold := new(big.Int).Mul(bigOrZero(oldFee), big.NewInt(100+replacementBump))
+	return new(big.Int).Mul(bigOrZero(newFee), big.NewInt(100)).Cmp(threshold) >= 0
+}
+
+func (p *Pool) remove(pop *poolOp) {
+	delete(p.ops, pop.hash)
+	senderOps := p.bySender[pop.op.Sender]
+	delete(senderOps, nonceKey(pop.op))
+	if len(senderOps) == 0 {
+		delete(p.bySender, pop.op.Sender)
+	}
+}
+
+// Get returns pending operation by its hash
+func (p *Pool) Get(hash libcommon.Hash) (*UserOperation, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	pop, ok := p.ops[hash]
+	if !ok {
+		return nil, false
+	}
+	return pop.op, true
+}
+
+func (p *Pool) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.ops)
+}
+
+// Best returns up to BundleSize operations for block blockNum paying the highest tip at baseFee and fitting
+// into gasLimit, at most one (lowest nonce) operation per sender: ops of a sender validated against same
+// state may conflict. Operations bundled into another payload of the same block are returned again.
+func (p *Pool) Best(blockNum uint64, baseFee *big.Int, gasLimit uint64) (ops []*UserOperation, hashes []libcommon.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	candidates := make([]*poolOp, 0, len(p.bySender))
+	for _, senderOps := range p.bySender {
+		var first *poolOp
+		for _, pop := range senderOps {
+			if first == nil || bigOrZero(pop.op.Nonce).Cmp(bigOrZero(first.op.Nonce)) < 0 {
+				first = pop
+			}
+		}
+		if first.includedAt != 0 && first.includedAt != blockNum {
+			continue
+		}
+		if baseFee != nil && bigOrZero(first.op.MaxFeePerGas).Cmp(baseFee) < 0 {
+			continue
+		}
+		candidates = append(candidates, first)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if c := candidates[i].op.Tip(baseFee).Cmp(candidates[j].op.Tip(baseFee)); c != 0 {
+			return c > 0
+		}
+		return candidates[i].hash.Hex() < candidates[j].hash.Hex()
+	})
+
+	for _, pop := range candidates {
+		if len(ops) >= p.cfg.BundleSize {
+			break
+		}
+		gas := pop.op.Gas()
+		if gas > gasLimit {
+			continue
+		}
+		gasLimit -= gas
+		ops = append(ops, pop.op)
+		hashes = append(hashes, pop.hash)
+	}
+	return ops, hashes
+}
+
+// MarkIncluded marks operations bundled into block blockNum by a successful handleOps transaction,
+// they are not returned by Best for other blocks
+func (p *Pool) MarkIncluded(hashes []libcommon.Hash, blockNum uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, hash := range hashes {
+		if pop, ok := p.ops[hash]; ok {
+			pop.includedAt = blockNum
+		}
+	}
+}
+
+// Remove drops operations which failed validation
+func (p *Pool) Remove(hashes []libcommon.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, hash := range hashes {
+		if pop, ok := p.ops[hash]; ok {
+			p.remove(pop)
+		}
+	}
+}
+
+// Prune drops operations bundled into blocks up to headNum and operations expired at unix time now
+func (p *Pool) Prune(headNum uint64, now uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	lifetime := uint64(p.cfg.Lifetime / time.Second)
+	for _, pop := range p.ops {
+		switch {
+		case pop.includedAt != 0 && pop.includedAt <= headNum:
+			p.remove(pop)
+		case pop.validUntil != 0 && pop.validUntil <= now:
+			p.remove(pop)
+		case lifetime != 0 && pop.includedAt == 0 && pop.addedAt+lifetime <= now:
+			p.remove(pop)
+		}
+	}
+}
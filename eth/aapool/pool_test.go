@@ -0,0 +1,145 @@
+package aapool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+
+	"github.com/ledgerwatch/erigon/eth/aapool/aapoolcfg"
+)
+
+func testOp(sender byte, nonce, maxFee, tip int64) *UserOperation {
+	return &UserOperation{
+		Sender:               libcommon.Address{sender},
+		Nonce:                (*hexutil.Big)(big.NewInt(nonce)),
+		CallGasLimit:         (*hexutil.Big)(big.NewInt(50_000)),
+		VerificationGasLimit: (*hexutil.Big)(big.NewInt(100_000)),
+		PreVerificationGas:   (*hexutil.Big)(big.NewInt(21_000)),
+		MaxFeePerGas:         (*hexutil.Big)(big.NewInt(maxFee)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(tip)),
+	}
+}
+
+func TestPool(t *testing.T) {
+	cfg := aapoolcfg.DefaultConfig
+	cfg.MaxOpsPerSender = 2
+	cfg.MaxOps = 3
+	pool := New(cfg, big.NewInt(1))
+	baseFee := big.NewInt(10)
+
+	_, err := pool.Add(testOp(1, 0, 20, 1), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(1, 0, 20, 1), 0)
+	require.ErrorIs(t, err, ErrKnownOp)
+	// replacement needs both fees bumped
+	_, err = pool.Add(testOp(1, 0, 20, 5), 0)
+	require.ErrorIs(t, err, ErrUnderpriced)
+	replaced, err := pool.Add(testOp(1, 0, 22, 5), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(1, 1, 30, 5), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(1, 2, 30, 5), 0)
+	require.ErrorIs(t, err, ErrSenderLimit)
+	second, err := pool.Add(testOp(2, 0, 30, 7), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(3, 0, 30, 7), 0)
+	require.ErrorIs(t, err, ErrPoolFull)
+	require.Equal(t, 3, pool.Len())
+
+	// one op per sender, ordered by tip
+	_, hashes := pool.Best(5, baseFee, 30_000_000)
+	require.Equal(t, []libcommon.Hash{second, replaced}, hashes)
+	// ops which don't fit are skipped
+	_, hashes = pool.Best(5, baseFee, 200_000)
+	require.Equal(t, []libcommon.Hash{second}, hashes)
+	// ops not paying base fee are skipped
+	_, hashes = pool.Best(5, big.NewInt(25), 30_000_000)
+	require.Equal(t, []libcommon.Hash{second}, hashes)
+
+	pool.MarkIncluded([]libcommon.Hash{second, replaced}, 5)
+	_, hashes = pool.Best(5, baseFee, 30_000_000)
+	require.Len(t, hashes, 2)
+	_, hashes = pool.Best(6, baseFee, 30_000_000)
+	require.Empty(t, hashes)
+
+	pool.Prune(5, 0)
+	require.Equal(t, 1, pool.Len())
+	ops, _ := pool.Best(6, baseFee, 30_000_000)
+	require.Equal(t, int64(1), ops[0].Nonce.ToInt().Int64())
+}
+
+func TestPoolExpiry(t *testing.T) {
+	cfg := aapoolcfg.DefaultConfig
+	cfg.MaxOps = 3
+	cfg.Lifetime = time.Hour
+	pool := New(cfg, big.NewInt(1))
+	now := uint64(time.Now().Unix())
+
+	expiring, err := pool.Add(testOp(1, 0, 20, 1), now+60)
+	require.NoError(t, err)
+	invalid, err := pool.Add(testOp(2, 0, 20, 1), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(3, 0, 20, 1), 0)
+	require.NoError(t, err)
+	_, err = pool.Add(testOp(4, 0, 20, 1), 0)
+	require.ErrorIs(t, err, ErrPoolFull)
+
+	// ops failing validation while bundling free their slots
+	pool.Remove([]libcommon.Hash{invalid})
+	_, ok := pool.Get(invalid)
+	require.False(t, ok)
+	_, err = pool.Add(testOp(4, 0, 20, 1), 0)
+	require.NoError(t, err)
+
+	pool.Prune(0, now+59)
+	require.Equal(t, 3, pool.Len())
+	pool.Prune(0, now+60)
+	require.Equal(t, 2, pool.Len())
+	_, ok = pool.Get(expiring)
+	require.False(t, ok)
+
+	// ops never bundled are dropped after Lifetime
+	pool.Prune(0, now+uint64(time.Hour/time.Second)+1)
+	require.Zero(t, pool.Len())
+}
+
+func TestEntryPointABI(t *testing.T) {
+	op := testOp(1, 0, 20, 1)
+
+	data, err := PackHandleOps([]*UserOperation{op, op}, libcommon.Address{9})
+	require.NoError(t, err)
+	require.Equal(t, entryPointABI.Methods["handleOps"].ID, data[:4])
+
+	data, err = PackSimulateValidation(op)
+	require.NoError(t, err)
+	require.Equal(t, entryPointABI.Methods["simulateValidation"].ID, data[:4])
+
+	failedOp := entryPointABI.Errors["FailedOp"]
+	revert, err := failedOp.Inputs.Pack(big.NewInt(0), "AA21 didn't pay prefund")
+	require.NoError(t, err)
+	_, err = UnpackValidationRevert(append(failedOp.ID[:4:4], revert...))
+	require.ErrorIs(t, err, ErrValidation)
+	require.ErrorContains(t, err, "AA21")
+
+	validationResult := entryPointABI.Errors["ValidationResult"]
+	stake := struct {
+		Stake           *big.Int
+		UnstakeDelaySec *big.Int
+	}{big.NewInt(0), big.NewInt(0)}
+	revert, err = validationResult.Inputs.Pack(validationReturnInfo{
+		PreOpGas:   big.NewInt(100),
+		Prefund:    big.NewInt(200),
+		ValidAfter: big.NewInt(0),
+		ValidUntil: big.NewInt(1000),
+	}, stake, stake, stake)
+	require.NoError(t, err)
+	res, err := UnpackValidationRevert(append(validationResult.ID[:4:4], revert...))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), res.ValidUntil)
+	require.False(t, res.SigFailed)
+}
@@ -0,0 +1,112 @@
+package aapool
+
+import (
+	"math/big"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+// UserOperation - ERC-4337 (EntryPoint v0.6) user operation, as sent to eth_sendUserOperation
+type UserOperation struct {
+	Sender               libcommon.Address `json:"sender"`
+	Nonce                *hexutil.Big      `json:"nonce"`
+	InitCode             hexutility.Bytes  `json:"initCode"`
+	CallData             hexutility.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big      `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big      `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big      `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutility.Bytes  `json:"paymasterAndData"`
+	Signature            hexutility.Bytes  `json:"signature"`
+}
+
+func bigOrZero(b *hexutil.Big) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b.ToInt()
+}
+
+// Hash - user operation hash as computed by EntryPoint.getUserOpHash, the signature isn't part of it
+func (op *UserOperation) Hash(entryPoint libcommon.Address, chainID *big.Int) libcommon.Hash {
+	packed := make([]byte, 0, 10*32)
+	packed = append(packed, common.LeftPadBytes(op.Sender[:], 32)...)
+	packed = append(packed, common.LeftPadBytes(bigOrZero(op.Nonce).Bytes(), 32)...)
+	packed = append(packed, crypto.Keccak256(op.InitCode)...)
+	packed = append(packed, crypto.Keccak256(op.CallData)...)
+	for _, v := range []*hexutil.Big{op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas, op.MaxFeePerGas, op.MaxPriorityFeePerGas} {
+		packed = append(packed, common.LeftPadBytes(bigOrZero(v).Bytes(), 32)...)
+	}
+	packed = append(packed, crypto.Keccak256(op.PaymasterAndData)...)
+
+	enc := make([]byte, 0, 3*32)
+	enc = append(enc, crypto.Keccak256(packed)...)
+	enc = append(enc, common.LeftPadBytes(entryPoint[:], 32)...)
+	enc = append(enc, common.LeftPadBytes(chainID.Bytes(), 32)...)
+	return libcommon.BytesToHash(crypto.Keccak256(enc))
+}
+
+// Gas - upper bound of gas used by the operation in handleOps, same as EntryPoint prefund computation:
+// paymaster validation and postOp are limited by verificationGasLimit each
+func (op *UserOperation) Gas() uint64 {
+	verificationGas := bigOrZero(op.VerificationGasLimit)
+	if len(op.PaymasterAndData) > 0 {
+		verificationGas = new(big.Int).Mul(verificationGas, big.NewInt(3))
+	}
+	gas := new(big.Int).Add(bigOrZero(op.CallGasLimit), verificationGas)
+	gas.Add(gas, bigOrZero(op.PreVerificationGas))
+	if !gas.IsUint64() {
+		return ^uint64(0)
+	}
+	return gas.Uint64()
+}
+
+// Tip - priority fee per gas the operation pays at given base fee
+func (op *UserOperation) Tip(baseFee *big.Int) *big.Int {
+	maxFee, maxTip := bigOrZero(op.MaxFeePerGas), bigOrZero(op.MaxPriorityFeePerGas)
+	if baseFee == nil {
+		return maxTip
+	}
+	tip := new(big.Int).Sub(maxFee, baseFee)
+	if tip.Cmp(maxTip) > 0 {
+		tip.Set(maxTip)
+	}
+	return tip
+}
+
+// abiUserOp - tuple of UserOperation in EntryPoint ABI
+type abiUserOp struct {
+	Sender               libcommon.Address
+	Nonce                *big.Int
+	InitCode             []byte
+	CallData             []byte
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	PaymasterAndData     []byte
+	Signature            []byte
+}
+
+func (op *UserOperation) toABI() abiUserOp {
+	return abiUserOp{
+		Sender:               op.Sender,
+		Nonce:                bigOrZero(op.Nonce),
+		InitCode:             op.InitCode,
+		CallData:             op.CallData,
+		CallGasLimit:         bigOrZero(op.CallGasLimit),
+		VerificationGasLimit: bigOrZero(op.VerificationGasLimit),
+		PreVerificationGas:   bigOrZero(op.PreVerificationGas),
+		MaxFeePerGas:         bigOrZero(op.MaxFeePerGas),
+		MaxPriorityFeePerGas: bigOrZero(op.MaxPriorityFeePerGas),
+		PaymasterAndData:     op.PaymasterAndData,
+		Signature:            op.Signature,
+	}
+}
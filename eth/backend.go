@@ -76,6 +76,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/txpool/txpoolcfg"
 	"github.com/ledgerwatch/erigon-lib/txpool/txpooluitl"
 	libtypes "github.com/ledgerwatch/erigon-lib/types"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/erigon-lib/wrap"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/persistence/format/snapshot_format/getters"
@@ -96,6 +97,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/vm"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/aapool"
 	"github.com/ledgerwatch/erigon/eth/consensuschain"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/eth/ethconsensusconfig"
@@ -163,6 +165,7 @@ type Ethereum struct {
 
 	ethBackendRPC      *privateapi.EthBackendServer
 	engineBackendRPC   *engineapi.EngineServer
+	engineJournal      *engineapi.EngineJournal
 	miningRPC          txpoolproto.MiningServer
 	stateChangesClient txpool.StateChangesClient
 
@@ -188,6 +191,7 @@ type Ethereum struct {
 
 	waitForStageLoopStop chan struct{}
 	waitForMiningStop    chan struct{}
+	waitForTxPoolStop    chan struct{} // txpool flushes itself to txPoolDB on stop
 
 	txPoolDB                kv.RwDB
 	txPool                  *txpool.TxPool
@@ -195,6 +199,7 @@ type Ethereum struct {
 	txPoolFetch             *txpool.Fetch
 	txPoolSend              *txpool.Send
 	txPoolGrpcServer        txpoolproto.TxpoolServer
+	aaPool                  *aapool.Pool // account abstraction lane, nil if disabled
 	notifyMiningAboutNewTxs chan struct{}
 	forkValidator           *engine_helpers.ForkValidator
 	downloader              *downloader.Downloader
@@ -260,6 +265,13 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			return err
 		}
 
+		stateCodec, err := statecodec.LoadActive(tx)
+		if err != nil {
+			return err
+		}
+		if stateCodec != statecodec.V3 {
+			logger.Warn("Experimental state codec", "codec", stateCodec.Name())
+		}
 		return nil
 	}); err != nil {
 		return nil, err
@@ -540,7 +552,13 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 	if chainConfig.Bor != nil {
 		if !config.WithoutHeimdall {
-			heimdallClient = heimdall.NewHeimdallClient(config.HeimdallURL, logger)
+			httpClient := heimdall.NewHeimdallClient(config.HeimdallURL, logger)
+			heimdallClient = httpClient
+			if config.HeimdallgRPCAddress != "" {
+				if heimdallClient, err = heimdall.NewGrpcClient(config.HeimdallgRPCAddress, httpClient); err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		flags.Milestone = config.WithHeimdallMilestones
@@ -575,6 +593,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 	statusDataProvider := sentry.NewStatusDataProvider(
 		chainKv,
+		backend.blockReader,
 		chainConfig,
 		genesis,
 		backend.config.NetworkID,
@@ -638,6 +657,10 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			return nil, err
 		}
 	}
+	if config.AAPool.Enabled {
+		backend.aaPool = aapool.New(config.AAPool, backend.chainConfig.ChainID)
+	}
+	backend.registerReloadableFlags(config, heimdallClient)
 
 	backend.notifyMiningAboutNewTxs = make(chan struct{}, 1)
 	backend.miningSealingQuit = make(chan struct{})
@@ -683,7 +706,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 				stages2.SilkwormForExecutionStage(backend.silkworm, config),
 			),
 			stagedsync.StageSendersCfg(backend.chainDB, chainConfig, config.Sync, false, dirs.Tmp, config.Prune, blockReader, backend.sentriesClient.Hd, loopBreakCheck),
-			stagedsync.StageMiningExecCfg(backend.chainDB, miner, backend.notifications.Events, *backend.chainConfig, backend.engine, &vm.Config{}, tmpdir, nil, 0, backend.txPool, backend.txPoolDB, blockReader),
+			stagedsync.StageMiningExecCfg(backend.chainDB, miner, backend.notifications.Events, *backend.chainConfig, backend.engine, &vm.Config{}, tmpdir, nil, 0, backend.txPool, backend.txPoolDB, backend.aaPool, blockReader),
 			stagedsync.StageMiningFinishCfg(backend.chainDB, *backend.chainConfig, backend.engine, miner, backend.miningSealingQuit, backend.blockReader, latestBlockBuiltStore),
 		), stagedsync.MiningUnwindOrder, stagedsync.MiningPruneOrder,
 		logger)
@@ -722,7 +745,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 					stages2.SilkwormForExecutionStage(backend.silkworm, config),
 				),
 				stagedsync.StageSendersCfg(backend.chainDB, chainConfig, config.Sync, false, dirs.Tmp, config.Prune, blockReader, backend.sentriesClient.Hd, loopBreakCheck),
-				stagedsync.StageMiningExecCfg(backend.chainDB, miningStatePos, backend.notifications.Events, *backend.chainConfig, backend.engine, &vm.Config{}, tmpdir, interrupt, param.PayloadId, backend.txPool, backend.txPoolDB, blockReader),
+				stagedsync.StageMiningExecCfg(backend.chainDB, miningStatePos, backend.notifications.Events, *backend.chainConfig, backend.engine, &vm.Config{}, tmpdir, interrupt, param.PayloadId, backend.txPool, backend.txPoolDB, backend.aaPool, blockReader),
 				stagedsync.StageMiningFinishCfg(backend.chainDB, *backend.chainConfig, backend.engine, miningStatePos, backend.miningSealingQuit, backend.blockReader, latestBlockBuiltStore)), stagedsync.MiningUnwindOrder, stagedsync.MiningPruneOrder, logger)
 		// We start the mining step
 		if err := stages2.MiningStep(ctx, backend.chainDB, proposingSync, tmpdir, logger); err != nil {
@@ -781,14 +804,18 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		if casted, ok := backend.txPoolGrpcServer.(*txpool.GrpcServer); ok {
 			newTxsBroadcaster = casted.NewSlotsStreams
 		}
-		go txpool.MainLoop(backend.sentryCtx,
-			backend.txPoolDB, backend.txPool, backend.newTxs, backend.txPoolSend, newTxsBroadcaster,
-			func() {
-				select {
-				case backend.notifyMiningAboutNewTxs <- struct{}{}:
-				default:
-				}
-			})
+		backend.waitForTxPoolStop = make(chan struct{})
+		go func() {
+			defer close(backend.waitForTxPoolStop)
+			txpool.MainLoop(backend.sentryCtx,
+				backend.txPoolDB, backend.txPool, backend.newTxs, backend.txPoolSend, newTxsBroadcaster,
+				func() {
+					select {
+					case backend.notifyMiningAboutNewTxs <- struct{}{}:
+					default:
+					}
+				})
+		}()
 	}
 
 	go func() {
@@ -910,6 +937,13 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			return nil, err
 		}
 	}
+	var engineJournal *engineapi.EngineJournal
+	if config.EngineJournalSize > 0 {
+		if engineJournal, err = engineapi.OpenEngineJournal(filepath.Join(config.Dirs.DataDir, "engine-journal"), config.EngineJournalSize, logger); err != nil {
+			return nil, err
+		}
+	}
+	backend.engineJournal = engineJournal
 	engineBackendRPC := engineapi.NewEngineServer(
 		logger,
 		chainConfig,
@@ -919,6 +953,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 			logger, backend.sentriesClient.Hd, executionRpc,
 			backend.sentriesClient.Bd, backend.sentriesClient.BroadcastNewBlock, backend.sentriesClient.SendBodyRequest, blockReader,
 			backend.chainDB, chainConfig, tmpdir, config.Sync),
+		engineJournal,
 		config.InternalCL && !caplinUseEngineAPI, // If the chain supports the engine API, then we should not make the server fail.
 		false,
 		config.Miner.EnabledPOS)
@@ -930,8 +965,11 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		if err != nil {
 			return nil, err
 		}
-		state, err := clcore.RetrieveBeaconState(ctx, beaconCfg,
-			clparams.GetCheckpointSyncEndpoint(clparams.NetworkType(config.NetworkID)))
+		checkpointSyncUrls := config.CaplinConfig.CheckpointSyncUrls
+		if len(checkpointSyncUrls) == 0 {
+			checkpointSyncUrls = clparams.GetCheckpointSyncEndpoints(clparams.NetworkType(config.NetworkID))
+		}
+		state, err := clcore.RetrieveBeaconStateFromProviders(ctx, beaconCfg, checkpointSyncUrls)
 		if err != nil {
 			return nil, err
 		}
@@ -963,7 +1001,7 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		//      - for now we just use 1 sentry
 		var sentryClient direct.SentryClient
 		for _, client := range sentries {
-			if client.Protocol() == direct.ETH68 {
+			if client.Protocol() >= direct.ETH68 {
 				sentryClient = client
 				break
 			}
@@ -1036,7 +1074,16 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 		}
 	}
 
-	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, s.agg, &httpRpcCfg, s.engine, s.logger)
+	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, s.agg, &httpRpcCfg, s.engine, s.aaPool, s.logger)
+	if len(s.sentryServers) > 0 {
+		// peer reputation is managed only by in-process sentries
+		s.apiList = append(s.apiList, rpc.API{
+			Namespace: "sentry",
+			Public:    false,
+			Service:   sentry.NewAPI(s.sentryServers),
+			Version:   "1.0",
+		})
+	}
 
 	if config.SilkwormRpcDaemon && httpRpcCfg.Enabled {
 		interface_log_settings := silkworm.RpcInterfaceLogSettings{
@@ -1063,7 +1110,8 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 		s.silkwormRPCDaemonService = &silkwormRPCDaemonService
 	} else {
 		go func() {
-			if err := cli.StartRpcServer(ctx, &httpRpcCfg, s.apiList, s.logger); err != nil {
+			responseCache := jsonrpc.NewResponseCache(chainKv, blockReader, httpRpcCfg.ResponseCacheSize)
+			if err := cli.StartRpcServer(ctx, &httpRpcCfg, s.apiList, responseCache, s.logger); err != nil {
 				s.logger.Error("cli.StartRpcServer error", "err", err)
 			}
 		}()
@@ -1446,6 +1494,8 @@ func setUpBlockReader(ctx context.Context, db kv.RwDB, dirs datadir.Dirs, snConf
 	if err != nil {
 		return nil, nil, nil, nil, nil, err
 	}
+	agg.SetMergeIOLimit(snConfig.Snapshot.MergeIOLimit)
+	agg.SetMergeIdleWait(snConfig.Snapshot.MergeIdleWait)
 
 	g := &errgroup.Group{}
 	g.Go(func() error {
@@ -1585,6 +1635,7 @@ func (s *Ethereum) Stop() error {
 	}
 	libcommon.SafeClose(s.sentriesClient.Hd.QuitPoWMining)
 	_ = s.engine.Close()
+	_ = s.engineJournal.Close()
 	if s.waitForStageLoopStop != nil {
 		<-s.waitForStageLoopStop
 	}
@@ -1594,6 +1645,9 @@ func (s *Ethereum) Stop() error {
 	for _, sentryServer := range s.sentryServers {
 		sentryServer.Close()
 	}
+	if s.waitForTxPoolStop != nil {
+		<-s.waitForTxPoolStop
+	}
 	if s.txPoolDB != nil {
 		s.txPoolDB.Close()
 	}
@@ -37,6 +37,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/consensus/ethash/ethashcfg"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/aapool/aapoolcfg"
 	"github.com/ledgerwatch/erigon/eth/ethconfig/estimate"
 	"github.com/ledgerwatch/erigon/eth/gasprice/gaspricecfg"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
@@ -95,6 +96,7 @@ var Defaults = Config{
 	},
 	DeprecatedTxPool: DeprecatedDefaultTxPoolConfig,
 	TxPool:           txpoolcfg.DefaultConfig,
+	AAPool:           aapoolcfg.DefaultConfig,
 	RPCGasCap:        50000000,
 	GPO:              FullNodeGPO,
 	RPCTxFeeCap:      1, // 1 ether
@@ -140,6 +142,18 @@ type BlocksFreezing struct {
 	NoDownloader   bool // possible to use snapshots without calling Downloader
 	Verify         bool // verify snapshots on startup
 	DownloaderAddr string
+	IndexWorkers   int // workers building missed snapshot indices, 0 - estimate by available RAM and CPUs
+
+	MergeIOLimit  datasize.ByteSize // bytes per second written by background merges of state files, 0 - unlimited
+	MergeIdleWait time.Duration     // max time background merges wait for the block execution to be done, 0 - don't wait
+}
+
+// IndexBuildWorkers - amount of workers building missed snapshot indices in parallel
+func (s BlocksFreezing) IndexBuildWorkers() int {
+	if s.IndexWorkers > 0 {
+		return s.IndexWorkers
+	}
+	return estimate.IndexSnapshot.Workers()
 }
 
 func (s BlocksFreezing) String() string {
@@ -157,8 +171,11 @@ func (s BlocksFreezing) String() string {
 }
 
 var (
-	FlagSnapKeepBlocks = "snap.keepblocks"
-	FlagSnapStop       = "snap.stop"
+	FlagSnapKeepBlocks   = "snap.keepblocks"
+	FlagSnapStop         = "snap.stop"
+	FlagSnapIndexWorkers = "snap.index.workers"
+	FlagSnapMergeIOLimit = "snap.merge.iolimit"
+	FlagSnapMergeIdle    = "snap.merge.idlewait"
 )
 
 func NewSnapCfg(enabled, keepBlocks, produce bool) BlocksFreezing {
@@ -183,6 +200,8 @@ type Config struct {
 	Prune     prune.Mode
 	BatchSize datasize.ByteSize // Batch size for execution stage
 
+	EngineJournalSize datasize.ByteSize // Max disk size of the Engine API calls journal, 0 disables it
+
 	ImportMode bool
 
 	BadBlockHash common.Hash // hash of the block marked as bad
@@ -213,6 +232,7 @@ type Config struct {
 	// Transaction pool options
 	DeprecatedTxPool DeprecatedTxPoolConfig
 	TxPool           txpoolcfg.Config
+	AAPool           aapoolcfg.Config // account abstraction lane
 
 	// Gas Price Oracle options
 	GPO gaspricecfg.Config
@@ -228,6 +248,8 @@ type Config struct {
 
 	// URL to connect to Heimdall node
 	HeimdallURL string
+	// gRPC address of Heimdall node, state sync events and spans are fetched and streamed over it if set
+	HeimdallgRPCAddress string
 	// No heimdall service
 	WithoutHeimdall bool
 	// Heimdall services active
@@ -280,6 +302,10 @@ type Sync struct {
 	PruneLimit                 int //the maximum records to delete from the DB during pruning
 	BreakAfterStage            string
 	LoopBlockLimit             uint
+	ExecCommitEvery            time.Duration // Execution stage commits its progress at least this often, 0 - only when batch is full
+	ExecParallel               bool          // Experimental: execute txs of block concurrently and re-execute conflicting ones
+	ExecWitnessBlocks          uint64        // Experimental: emit execution witnesses of this many last blocks, 0 - disabled
+	ExecOtsIndex               bool          // Execution stage indexes contract creations, self-destructs and token holders for ots2_* methods
 
 	UploadLocation   string
 	UploadFrom       rpc.BlockNumber
@@ -72,6 +72,14 @@ var DefaultTxPool2Config = func(fullCfg *Config) txpoolcfg.Config {
 	cfg.AccountSlots = pool1Cfg.AccountSlots
 	cfg.BlobSlots = fullCfg.TxPool.BlobSlots
 	cfg.TotalBlobPoolLimit = fullCfg.TxPool.TotalBlobPoolLimit
+	cfg.SenderRateLimit = fullCfg.TxPool.SenderRateLimit
+	cfg.GlobalRateLimit = fullCfg.TxPool.GlobalRateLimit
+	cfg.LocalsLifetime = fullCfg.TxPool.LocalsLifetime
+	cfg.RebroadcastLocalsEvery = fullCfg.TxPool.RebroadcastLocalsEvery
+	cfg.ReplacementPolicy = fullCfg.TxPool.ReplacementPolicy
+	cfg.MaxReplacements = fullCfg.TxPool.MaxReplacements
+	cfg.PrioritySenders = fullCfg.TxPool.PrioritySenders
+	cfg.PriorityLaneGasLimit = fullCfg.TxPool.PriorityLaneGasLimit
 	cfg.LogEvery = 3 * time.Minute
 	cfg.CommitEvery = 5 * time.Minute
 	cfg.TracedSenders = pool1Cfg.TracedSenders
@@ -28,6 +28,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/length"
 
 	ethereum "github.com/ledgerwatch/erigon"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -581,6 +582,94 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// PendingTxsCriteria - options of the newPendingTransactions subscription. It's either a bool, true for full
+// transaction objects instead of hashes, or an object with fullTx and filters. Each filter which is set must
+// match, an address or type filter matches if any of its items does.
+type PendingTxsCriteria struct {
+	FullTx      bool                `json:"fullTx"`
+	From        []libcommon.Address `json:"from"`
+	To          []libcommon.Address `json:"to"`
+	MinGasPrice *hexutil.Big        `json:"minGasPrice"` // compared to gas price or max fee per gas of the transaction
+	Types       []hexutil.Uint64    `json:"types"`
+
+	from, to map[libcommon.Address]struct{}
+	types    map[byte]struct{}
+}
+
+// UnmarshalJSON sets *c fields with given data.
+func (c *PendingTxsCriteria) UnmarshalJSON(data []byte) error {
+	var fullTx bool
+	if err := json.Unmarshal(data, &fullTx); err == nil {
+		*c = PendingTxsCriteria{FullTx: fullTx}
+		return nil
+	}
+
+	type input PendingTxsCriteria
+	var raw input
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = PendingTxsCriteria(raw)
+
+	c.from, c.to = addressSet(c.From), addressSet(c.To)
+	if len(c.Types) > 0 {
+		c.types = make(map[byte]struct{}, len(c.Types))
+		for _, typ := range c.Types {
+			if typ > 0xff {
+				return fmt.Errorf("invalid transaction type %d", typ)
+			}
+			c.types[byte(typ)] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// NeedsSender - whether Match needs the sender of transactions
+func (c *PendingTxsCriteria) NeedsSender() bool {
+	return c != nil && c.from != nil
+}
+
+// Match - whether the pending transaction passes the filters, sender is used only if NeedsSender
+func (c *PendingTxsCriteria) Match(txn types.Transaction, sender libcommon.Address) bool {
+	if c == nil {
+		return true
+	}
+	if c.types != nil {
+		if _, ok := c.types[txn.Type()]; !ok {
+			return false
+		}
+	}
+	if c.to != nil {
+		to := txn.GetTo()
+		if to == nil {
+			return false
+		}
+		if _, ok := c.to[*to]; !ok {
+			return false
+		}
+	}
+	if c.from != nil {
+		if _, ok := c.from[sender]; !ok {
+			return false
+		}
+	}
+	if c.MinGasPrice != nil && txn.GetFeeCap().ToBig().Cmp(c.MinGasPrice.ToInt()) < 0 {
+		return false
+	}
+	return true
+}
+
+func addressSet(addrs []libcommon.Address) map[libcommon.Address]struct{} {
+	if len(addrs) == 0 {
+		return nil
+	}
+	set := make(map[libcommon.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
 func decodeAddress(s string) (libcommon.Address, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != length.Addr {
@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/holiman/uint256"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -184,3 +186,52 @@ func TestUnmarshalJSONNewFilterArgs(t *testing.T) {
 		t.Fatalf("expected 0 topics, got %d topics", len(test7.Topics[2]))
 	}
 }
+
+func TestPendingTxsCriteria(t *testing.T) {
+	var (
+		sender    = libcommon.HexToAddress("70c87d191324e6712a591f304b4eedef6ad9bb9d")
+		recipient = libcommon.HexToAddress("9b2055d370f73ec7d8a03e965129118dc8f5bf83")
+		legacy    = &types.LegacyTx{CommonTx: types.CommonTx{To: &recipient}, GasPrice: uint256.NewInt(10)}
+		dynamic   = &types.DynamicFeeTransaction{CommonTx: types.CommonTx{To: &recipient}, FeeCap: uint256.NewInt(30)}
+		create    = &types.DynamicFeeTransaction{FeeCap: uint256.NewInt(30)}
+	)
+
+	var fullTx PendingTxsCriteria
+	if err := json.Unmarshal([]byte("true"), &fullTx); err != nil {
+		t.Fatal(err)
+	}
+	if !fullTx.FullTx || fullTx.NeedsSender() || !fullTx.Match(create, libcommon.Address{}) {
+		t.Fatalf("bool criteria must be full tx without filters: %+v", fullTx)
+	}
+
+	var crit PendingTxsCriteria
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`{"to":["%s"],"minGasPrice":"0x14","types":["0x0","0x2"]}`, recipient.Hex())), &crit); err != nil {
+		t.Fatal(err)
+	}
+	if crit.FullTx || crit.NeedsSender() {
+		t.Fatalf("unexpected criteria: %+v", crit)
+	}
+	if crit.Match(legacy, sender) {
+		t.Fatal("legacy tx is below min gas price")
+	}
+	if !crit.Match(dynamic, sender) {
+		t.Fatal("dynamic fee tx must match")
+	}
+	if crit.Match(create, sender) {
+		t.Fatal("contract creation has no recipient")
+	}
+
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`{"fullTx":true,"from":["%s"]}`, sender.Hex())), &crit); err != nil {
+		t.Fatal(err)
+	}
+	if !crit.FullTx || !crit.NeedsSender() {
+		t.Fatalf("unexpected criteria: %+v", crit)
+	}
+	if !crit.Match(legacy, sender) || crit.Match(legacy, recipient) {
+		t.Fatal("from filter must match sender only")
+	}
+
+	if err := json.Unmarshal([]byte(`{"types":["0x100"]}`), &crit); err == nil {
+		t.Fatal("invalid type must be rejected")
+	}
+}
@@ -48,13 +48,23 @@ type blockFees struct {
 	// set by the caller
 	blockNumber uint64
 	header      *types.Header
-	block       *types.Block // only set if reward percentiles are requested
-	receipts    types.Receipts
+	tips        *BlockTips // only set if reward percentiles are requested
 	// filled by processBlock
-	reward               []*big.Int
-	baseFee, nextBaseFee *big.Int
-	gasUsedRatio         float64
-	err                  error
+	reward                       []*big.Int
+	baseFee, nextBaseFee         *big.Int
+	blobBaseFee, nextBlobBaseFee *big.Int
+	gasUsedRatio                 float64
+	blobGasUsedRatio             float64
+	err                          error
+}
+
+// BlockTips - effective tips of the transactions of a block in ascending order, with the gas used by the transactions
+// up to each of them. A reward percentile of the block is found by a binary search, without sorting the transactions
+// again, so the tips of recent blocks are computed once and cached across fee history requests.
+type BlockTips struct {
+	tips          []*big.Int
+	cumulativeGas []uint64
+	gasUsed       uint64
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -74,9 +84,79 @@ func (s sortGasAndReward) Less(i, j int) bool {
 	return s[i].reward.Cmp(s[j].reward) < 0
 }
 
+// NewBlockTips sorts the transactions of the block by their effective tips
+func NewBlockTips(block *types.Block, receipts types.Receipts) (*BlockTips, error) {
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return nil, fmt.Errorf("block %d: %d receipts of %d transactions", block.NumberU64(), len(receipts), len(txs))
+	}
+
+	sorter := make(sortGasAndReward, len(txs))
+	baseFee := uint256.NewInt(0)
+	if block.BaseFee() != nil {
+		baseFee.SetFromBig(block.BaseFee())
+	}
+	for i, tx := range txs {
+		reward := tx.GetEffectiveGasTip(baseFee)
+		sorter[i] = txGasAndReward{gasUsed: receipts[i].GasUsed, reward: reward.ToBig()}
+	}
+	sort.Sort(sorter)
+
+	bt := &BlockTips{
+		tips:          make([]*big.Int, len(sorter)),
+		cumulativeGas: make([]uint64, len(sorter)),
+		gasUsed:       block.GasUsed(),
+	}
+	var sumGasUsed uint64
+	for i := range sorter {
+		sumGasUsed += sorter[i].gasUsed
+		bt.tips[i], bt.cumulativeGas[i] = sorter[i].reward, sumGasUsed
+	}
+	return bt, nil
+}
+
+// Percentile returns the tip of the first transaction at which the gas used by the block, weighted by tips,
+// reaches p percent. Zero if the block has no transactions.
+func (bt *BlockTips) Percentile(p float64) *big.Int {
+	if len(bt.tips) == 0 {
+		return new(big.Int)
+	}
+	thresholdGasUsed := uint64(float64(bt.gasUsed) * p / 100)
+	i := sort.Search(len(bt.cumulativeGas), func(i int) bool { return bt.cumulativeGas[i] >= thresholdGasUsed })
+	if i == len(bt.tips) {
+		i--
+	}
+	return bt.tips[i]
+}
+
+// blockTips returns the tips of the block from the cache, the block and its receipts are read on a miss.
+// nil without an error means that the block or its receipts are not available.
+func (oracle *Oracle) blockTips(ctx context.Context, header *types.Header) (*BlockTips, error) {
+	hash := header.Hash()
+	if tips, ok := oracle.cache.GetBlockTips(hash); ok {
+		return tips, nil
+	}
+
+	block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(header.Number.Uint64()))
+	if err != nil || block == nil {
+		return nil, err
+	}
+	var receipts types.Receipts
+	if len(block.Transactions()) != 0 {
+		if receipts, err = oracle.backend.GetReceipts(ctx, block); err != nil || len(receipts) != len(block.Transactions()) {
+			return nil, err
+		}
+	}
+	tips, err := NewBlockTips(block, receipts)
+	if err != nil {
+		return nil, err
+	}
+	oracle.cache.SetBlockTips(block.Hash(), tips)
+	return tips, nil
+}
+
 // processBlock takes a blockFees structure with the blockNumber, the header and optionally
-// the block field filled in, retrieves the block from the backend if not present yet and
-// fills in the rest of the fields.
+// the tips of the block filled in and fills in the rest of the fields.
 func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 	chainconfig := oracle.backend.ChainConfig()
 	if bf.baseFee = bf.header.BaseFee; bf.baseFee == nil {
@@ -88,45 +168,37 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		bf.nextBaseFee = new(big.Int)
 	}
 	bf.gasUsedRatio = float64(bf.header.GasUsed) / float64(bf.header.GasLimit)
+
+	bf.blobBaseFee, bf.nextBlobBaseFee = new(big.Int), new(big.Int)
+	if excessBlobGas := bf.header.ExcessBlobGas; excessBlobGas != nil {
+		blobBaseFee, err := misc.GetBlobGasPrice(chainconfig, *excessBlobGas)
+		if err != nil {
+			bf.err = err
+			return
+		}
+		nextBlobBaseFee, err := misc.GetBlobGasPrice(chainconfig, misc.CalcExcessBlobGas(chainconfig, bf.header))
+		if err != nil {
+			bf.err = err
+			return
+		}
+		bf.blobBaseFee, bf.nextBlobBaseFee = blobBaseFee.ToBig(), nextBlobBaseFee.ToBig()
+		if maxBlobGas := chainconfig.GetMaxBlobGasPerBlock(); maxBlobGas != 0 && bf.header.BlobGasUsed != nil {
+			bf.blobGasUsedRatio = float64(*bf.header.BlobGasUsed) / float64(maxBlobGas)
+		}
+	}
+
 	if len(percentiles) == 0 {
 		// rewards were not requested, return null
 		return
 	}
-	if bf.block == nil || (bf.receipts == nil && len(bf.block.Transactions()) != 0) {
+	if bf.tips == nil {
 		log.Error("[GasPriceOracle] Block or receipts are missing while reward percentiles are requested")
 		return
 	}
 
 	bf.reward = make([]*big.Int, len(percentiles))
-	if len(bf.block.Transactions()) == 0 {
-		// return an all zero row if there are no transactions to gather data from
-		for i := range bf.reward {
-			bf.reward[i] = new(big.Int)
-		}
-		return
-	}
-
-	sorter := make(sortGasAndReward, len(bf.block.Transactions()))
-	baseFee := uint256.NewInt(0)
-	if bf.block.BaseFee() != nil {
-		baseFee.SetFromBig(bf.block.BaseFee())
-	}
-	for i, tx := range bf.block.Transactions() {
-		reward := tx.GetEffectiveGasTip(baseFee)
-		sorter[i] = txGasAndReward{gasUsed: bf.receipts[i].GasUsed, reward: reward.ToBig()}
-	}
-	sort.Sort(sorter)
-
-	var txIndex int
-	sumGasUsed := sorter[0].gasUsed
-
 	for i, p := range percentiles {
-		thresholdGasUsed := uint64(float64(bf.block.GasUsed()) * p / 100)
-		for sumGasUsed < thresholdGasUsed && txIndex < len(bf.block.Transactions())-1 {
-			txIndex++
-			sumGasUsed += sorter[txIndex].gasUsed
-		}
-		bf.reward[i] = sorter[txIndex].reward
+		bf.reward[i] = bf.tips.Percentile(p)
 	}
 }
 
@@ -197,12 +269,14 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, lastBlock rpc.Block
 //     block, sorted in ascending order and weighted by gas used.
 //   - baseFee: base fee per gas in the given block
 //   - gasUsedRatio: gasUsed/gasLimit in the given block
+//   - blobBaseFee: blob base fee per gas in the given block, zero before Cancun
+//   - blobGasUsedRatio: blobGasUsed/maxBlobGasPerBlock in the given block
 //
-// Note: baseFee includes the next block after the newest of the returned range, because this
-// value can be derived from the newest block.
-func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+// Note: baseFee and blobBaseFee include the next block after the newest of the returned range,
+// because these values can be derived from the newest block.
+func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*big.Int, []float64, error) {
 	if blocks < 1 {
-		return libcommon.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return libcommon.Big0, nil, nil, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	if blocks > maxFeeHistory {
 		log.Warn("[GasPriceOracle] Sanitizing fee history length", "requested", blocks, "truncated", maxFeeHistory)
@@ -210,10 +284,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return libcommon.Big0, nil, nil, nil, fmt.Errorf("%w: %f", ErrInvalidPercentile, p)
+			return libcommon.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: %f", ErrInvalidPercentile, p)
 		}
 		if i > 0 && p < rewardPercentiles[i-1] {
-			return libcommon.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", ErrInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return libcommon.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", ErrInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	// Only process blocks if reward percentiles were requested
@@ -228,7 +302,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks, maxHistory)
 	if err != nil || blocks == 0 {
-		return libcommon.Big0, nil, nil, nil, err
+		return libcommon.Big0, nil, nil, nil, nil, nil, err
 	}
 	oldestBlock := lastBlock + 1 - uint64(blocks)
 
@@ -236,14 +310,16 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		next = oldestBlock
 	)
 	var (
-		reward       = make([][]*big.Int, blocks)
-		baseFee      = make([]*big.Int, blocks+1)
-		gasUsedRatio = make([]float64, blocks)
-		firstMissing = blocks
+		reward           = make([][]*big.Int, blocks)
+		baseFee          = make([]*big.Int, blocks+1)
+		gasUsedRatio     = make([]float64, blocks)
+		blobBaseFee      = make([]*big.Int, blocks+1)
+		blobGasUsedRatio = make([]float64, blocks)
+		firstMissing     = blocks
 	)
 	for ; blocks > 0; blocks-- {
 		if err = libcommon.Stopped(ctx.Done()); err != nil {
-			return libcommon.Big0, nil, nil, nil, err
+			return libcommon.Big0, nil, nil, nil, nil, nil, err
 		}
 		// Retrieve the next block number to fetch with this goroutine
 		blockNumber := atomic.AddUint64(&next, 1) - 1
@@ -253,30 +329,28 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 
 		fees := &blockFees{blockNumber: blockNumber}
 		if pendingBlock != nil && blockNumber >= pendingBlock.NumberU64() {
-			fees.block, fees.receipts = pendingBlock, pendingReceipts
+			fees.header = pendingBlock.Header()
+			if len(rewardPercentiles) != 0 && len(pendingReceipts) == len(pendingBlock.Transactions()) {
+				// the pending block changes, its tips are not cached
+				fees.tips, fees.err = NewBlockTips(pendingBlock, pendingReceipts)
+			}
 		} else {
-			if len(rewardPercentiles) != 0 {
-				fees.block, fees.err = oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
-				if fees.block != nil && fees.err == nil {
-					fees.receipts, fees.err = oracle.backend.GetReceipts(ctx, fees.block)
-				}
-			} else {
-				fees.header, fees.err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+			fees.header, fees.err = oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+			if fees.header != nil && fees.err == nil && len(rewardPercentiles) != 0 {
+				fees.tips, fees.err = oracle.blockTips(ctx, fees.header)
 			}
 		}
-		if fees.block != nil {
-			fees.header = fees.block.Header()
-		}
-		if fees.header != nil {
+		if fees.header != nil && fees.err == nil {
 			oracle.processBlock(fees, rewardPercentiles)
 		}
 
 		if fees.err != nil {
-			return libcommon.Big0, nil, nil, nil, fees.err
+			return libcommon.Big0, nil, nil, nil, nil, nil, fees.err
 		}
 		i := int(fees.blockNumber - oldestBlock)
 		if fees.header != nil {
 			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.reward, fees.baseFee, fees.nextBaseFee, fees.gasUsedRatio
+			blobBaseFee[i], blobBaseFee[i+1], blobGasUsedRatio[i] = fees.blobBaseFee, fees.nextBlobBaseFee, fees.blobGasUsedRatio
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -285,7 +359,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		}
 	}
 	if firstMissing == 0 {
-		return libcommon.Big0, nil, nil, nil, nil
+		return libcommon.Big0, nil, nil, nil, nil, nil, nil
 	}
 	if len(rewardPercentiles) != 0 {
 		reward = reward[:firstMissing]
@@ -293,5 +367,6 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		reward = nil
 	}
 	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+	blobBaseFee, blobGasUsedRatio = blobBaseFee[:firstMissing+1], blobGasUsedRatio[:firstMissing]
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, blobBaseFee, blobGasUsedRatio, nil
 }
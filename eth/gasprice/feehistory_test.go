@@ -19,8 +19,14 @@ package gasprice_test
 import (
 	"context"
 	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/gasprice"
 	"github.com/ledgerwatch/erigon/eth/gasprice/gaspricecfg"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -62,7 +68,7 @@ func TestFeeHistory(t *testing.T) {
 		cache := jsonrpc.NewGasPriceCache()
 		oracle := gasprice.NewOracle(backend, config, cache)
 
-		first, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
+		first, reward, baseFee, ratio, blobBaseFee, blobRatio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
 
 		expReward := c.expCount
 		if len(c.percent) == 0 {
@@ -85,8 +91,86 @@ func TestFeeHistory(t *testing.T) {
 		if len(ratio) != c.expCount {
 			t.Fatalf("Test case %d: gasUsedRatio array length mismatch, want %d, got %d", i, c.expCount, len(ratio))
 		}
+		if len(blobBaseFee) != expBaseFee {
+			t.Fatalf("Test case %d: blobBaseFee array length mismatch, want %d, got %d", i, expBaseFee, len(blobBaseFee))
+		}
+		if len(blobRatio) != c.expCount {
+			t.Fatalf("Test case %d: blobGasUsedRatio array length mismatch, want %d, got %d", i, c.expCount, len(blobRatio))
+		}
 		if err != c.expErr && !errors.Is(err, c.expErr) {
 			t.Fatalf("Test case %d: error mismatch, want %v, got %v", i, c.expErr, err)
 		}
 	}
 }
+
+func TestBlockTipsPercentile(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), BaseFee: big.NewInt(10), GasUsed: 1000}
+	var txs []types.Transaction
+	var receipts types.Receipts
+	for _, tx := range []struct{ gasPrice, gasUsed uint64 }{{15, 100}, {12, 300}, {30, 100}, {20, 500}} {
+		txs = append(txs, types.NewTransaction(0, libcommon.Address{}, uint256.NewInt(0), tx.gasUsed, uint256.NewInt(tx.gasPrice), nil))
+		receipts = append(receipts, &types.Receipt{GasUsed: tx.gasUsed})
+	}
+	block := types.NewBlockFromStorage(libcommon.Hash{1}, header, txs, nil, nil, nil)
+
+	tips, err := gasprice.NewBlockTips(block, receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tips sorted: 2 (300 gas), 5 (100 gas), 10 (500 gas), 20 (100 gas)
+	for _, c := range []struct {
+		percentile float64
+		tip        int64
+	}{{0, 2}, {30, 2}, {35, 5}, {50, 10}, {90, 10}, {91, 20}, {100, 20}} {
+		if tip := tips.Percentile(c.percentile); tip.Cmp(big.NewInt(c.tip)) != 0 {
+			t.Fatalf("percentile %f: want %d, got %d", c.percentile, c.tip, tip)
+		}
+	}
+
+	if _, err := gasprice.NewBlockTips(block, receipts[:3]); err == nil {
+		t.Fatal("expected an error for missing receipts")
+	}
+
+	empty, err := gasprice.NewBlockTips(types.NewBlockFromStorage(libcommon.Hash{2}, header, nil, nil, nil, nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip := empty.Percentile(50); tip.Sign() != 0 {
+		t.Fatalf("empty block: want 0, got %d", tip)
+	}
+}
+
+func TestFeeHistoryCachedTips(t *testing.T) {
+	backend := newTestBackend(t)
+	cache := jsonrpc.NewGasPriceCache()
+	oracle := gasprice.NewOracle(backend, gaspricecfg.Config{}, cache)
+	percentiles := []float64{0, 25, 50, 100}
+
+	_, reward, _, _, _, _, err := oracle.FeeHistory(context.Background(), 10, rpc.LatestBlockNumber, percentiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := backend.CurrentHeader()
+	if _, ok := cache.GetBlockTips(head.Hash()); !ok {
+		t.Fatal("tips of the head block are not cached")
+	}
+
+	// served from the cache
+	_, cachedReward, _, _, _, _, err := oracle.FeeHistory(context.Background(), 10, rpc.LatestBlockNumber, percentiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reward, cachedReward) {
+		t.Fatalf("rewards mismatch: %v != %v", reward, cachedReward)
+	}
+	// one transaction per block, all the percentiles are its tip
+	last := reward[len(reward)-1]
+	block := backend.GetBlockByNumber(head.Number.Uint64())
+	expected := block.Transactions()[0].GetPrice().ToBig()
+	if block.BaseFee() != nil {
+		expected.Sub(expected, block.BaseFee())
+	}
+	if last[0].Cmp(expected) != 0 || last[3].Cmp(expected) != 0 {
+		t.Fatalf("head block reward: want %d, got %v", expected, last)
+	}
+}
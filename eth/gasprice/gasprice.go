@@ -47,6 +47,10 @@ type OracleBackend interface {
 type Cache interface {
 	GetLatest() (libcommon.Hash, *big.Int)
 	SetLatest(hash libcommon.Hash, price *big.Int)
+
+	// GetBlockTips and SetBlockTips keep the sorted tips of recent blocks for fee history, by block hash
+	GetBlockTips(hash libcommon.Hash) (*BlockTips, bool)
+	SetBlockTips(hash libcommon.Hash, tips *BlockTips)
 }
 
 // Oracle recommends gas prices based on the content of recent
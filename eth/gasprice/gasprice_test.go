@@ -44,6 +44,7 @@ type testBackend struct {
 	db          kv.RwDB
 	cfg         *chain.Config
 	blockReader services.FullBlockReader
+	receipts    map[libcommon.Hash]types.Receipts // receipts of the generated blocks
 }
 
 func (b *testBackend) GetReceipts(ctx context.Context, block *types.Block) (types.Receipts, error) {
@@ -54,6 +55,9 @@ func (b *testBackend) GetReceipts(ctx context.Context, block *types.Block) (type
 	defer tx.Rollback()
 
 	receipts := rawdb.ReadReceipts(tx, block, nil)
+	if receipts == nil {
+		receipts = b.receipts[block.Hash()]
+	}
 	return receipts, nil
 }
 
@@ -121,7 +125,11 @@ func newTestBackend(t *testing.T) *testBackend {
 	if err = m.InsertChain(chain); err != nil {
 		t.Error(err)
 	}
-	return &testBackend{db: m.DB, cfg: params.TestChainConfig, blockReader: m.BlockReader}
+	receipts := make(map[libcommon.Hash]types.Receipts, chain.Length())
+	for i, block := range chain.Blocks {
+		receipts[block.Hash()] = chain.Receipts[i]
+	}
+	return &testBackend{db: m.DB, cfg: params.TestChainConfig, blockReader: m.BlockReader, receipts: receipts}
 }
 
 func (b *testBackend) CurrentHeader() *types.Header {
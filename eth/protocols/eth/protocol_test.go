@@ -229,3 +229,54 @@ func TestEth66Messages(t *testing.T) {
 		}
 	}
 }
+
+func TestEth69Messages(t *testing.T) {
+	receipts := types.Receipts{
+		{
+			Status:            types.ReceiptStatusFailed,
+			CumulativeGasUsed: 1,
+			Logs: []*types.Log{
+				{
+					Address: libcommon.BytesToAddress([]byte{0x11}),
+					Topics:  []libcommon.Hash{libcommon.HexToHash("dead"), libcommon.HexToHash("beef")},
+					Data:    []byte{0x01, 0x00, 0xff},
+				},
+			},
+		},
+	}
+	receipts[0].Bloom = types.CreateBloom(receipts)
+
+	for i, tc := range []struct {
+		message interface{}
+		want    []byte
+	}{
+		{
+			NewReceiptsPacket69(1111, []types.Receipts{receipts}),
+			common.FromHex("f86d820457f868f866f864808001f85ff85d940000000000000000000000000000000000000011f842a0000000000000000000000000000000000000000000000000000000000000deada0000000000000000000000000000000000000000000000000000000000000beef830100ff"),
+		},
+		{
+			BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: 1111, LatestBlockHash: libcommon.HexToHash("deadc0de")},
+			common.FromHex("e501820457a000000000000000000000000000000000000000000000000000000000deadc0de"),
+		},
+	} {
+		if have, _ := rlp.EncodeToBytes(tc.message); !bytes.Equal(have, tc.want) {
+			t.Errorf("test %d, type %T, have\n\t%x\nwant\n\t%x", i, tc.message, have, tc.want)
+		}
+	}
+
+	enc, err := rlp.EncodeToBytes(NewReceiptsPacket69(1111, []types.Receipts{receipts}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var packet ReceiptsPacket69
+	if err := rlp.DecodeBytes(enc, &packet); err != nil {
+		t.Fatal(err)
+	}
+	unpacked := packet.Unpack()
+	if len(unpacked) != 1 || len(unpacked[0]) != 1 {
+		t.Fatalf("unexpected receipts: %v", unpacked)
+	}
+	if have := unpacked[0][0]; have.Bloom != receipts[0].Bloom || have.CumulativeGasUsed != 1 || have.Status != types.ReceiptStatusFailed {
+		t.Errorf("decoded receipt mismatch: %+v", have)
+	}
+}
@@ -0,0 +1,43 @@
+package eth
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+)
+
+// registerReloadableFlags - flags of the backend which are applied on config reload (see debug.ReloadConfig).
+// Prune distances are not among them: prune mode is written to the db on first start and must match the flags.
+func (s *Ethereum) registerReloadableFlags(config *ethconfig.Config, heimdallClient heimdall.HeimdallClient) {
+	if s.txPool != nil {
+		txPool := s.txPool
+		registerUint := func(name string, value uint64, set func(v uint64)) {
+			debug.RegisterReloadable(name, strconv.FormatUint(value, 10), func(value string) error {
+				v, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return err
+				}
+				if v == 0 {
+					return errors.New("must be positive")
+				}
+				set(v)
+				return nil
+			})
+		}
+		registerUint(utils.TxPoolGlobalSlotsFlag.Name, uint64(config.TxPool.PendingSubPoolLimit), func(v uint64) { txPool.SetLimits(int(v), 0, 0, 0) })
+		registerUint(utils.TxPoolGlobalBaseFeeSlotsFlag.Name, uint64(config.TxPool.BaseFeeSubPoolLimit), func(v uint64) { txPool.SetLimits(0, int(v), 0, 0) })
+		registerUint(utils.TxPoolGlobalQueueFlag.Name, uint64(config.TxPool.QueuedSubPoolLimit), func(v uint64) { txPool.SetLimits(0, 0, int(v), 0) })
+		registerUint(utils.TxPoolAccountSlotsFlag.Name, config.TxPool.AccountSlots, func(v uint64) { txPool.SetLimits(0, 0, 0, v) })
+	}
+
+	if client, ok := heimdallClient.(interface{ SetURL(string) }); ok {
+		debug.RegisterReloadable(utils.HeimdallURLFlag.Name, config.HeimdallURL, func(value string) error {
+			client.SetURL(value)
+			return nil
+		})
+	}
+}
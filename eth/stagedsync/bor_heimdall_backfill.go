@@ -0,0 +1,181 @@
+package stagedsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+// stateSyncEventsBackfillBatch - how many events are requested from Heimdall at once during backfill
+const stateSyncEventsBackfillBatch = 1000
+
+type StateSyncEventsBackfillCfg struct {
+	HeimdallClient    heimdall.HeimdallClient
+	StateReceiverABI  abi.ABI
+	ChainID           string
+	FromID            uint64    // first event id to check, 0 - first event after LastFrozenEventID
+	LastFrozenEventID uint64    // events up to this id are in snapshots and aren't checked
+	ToID              uint64    // last event id to check (inclusive), 0 - last event id stored in db
+	ToTime            time.Time // events with record time >= ToTime are not checked, zero - now
+	DryRun            bool      // only report missing events, don't write them
+}
+
+type StateSyncEventMismatch struct {
+	ID           uint64
+	DiskHash     libcommon.Hash
+	HeimdallHash libcommon.Hash
+}
+
+type StateSyncEventsBackfillReport struct {
+	FromID     uint64
+	LastID     uint64 // last event id received from Heimdall, 0 if none
+	Checked    int
+	Missing    []uint64 // events which were absent in db (written unless DryRun)
+	Mismatched []StateSyncEventMismatch
+}
+
+// BackfillHeimdallStateSyncEvents re-fetches state sync events in [cfg.FromID, cfg.ToID] from Heimdall and
+// writes the ones missing in kv.BorEvents. Events which are present but differ from Heimdall are only reported,
+// as repairing them requires unwinding of execution. Fetched events are validated the same way as in BorHeimdall
+// stage: ids must be contiguous, record times non-decreasing and chain id must match.
+// Events which were already moved to snapshots (ids up to cfg.LastFrozenEventID) are not stored in db, the check
+// starts after them.
+func BackfillHeimdallStateSyncEvents(
+	ctx context.Context,
+	tx kv.RwTx,
+	cfg StateSyncEventsBackfillCfg,
+	logger log.Logger,
+) (*StateSyncEventsBackfillReport, error) {
+	fromID := cfg.FromID
+	if fromID <= cfg.LastFrozenEventID {
+		fromID = cfg.LastFrozenEventID + 1
+	}
+
+	toID := cfg.ToID
+	if toID == 0 {
+		lastID, err := lastStateSyncEventID(tx)
+		if err != nil {
+			return nil, err
+		}
+		if lastID < fromID {
+			// nothing in db after the snapshots
+			return &StateSyncEventsBackfillReport{FromID: fromID}, nil
+		}
+		toID = lastID
+	}
+	if toID < fromID {
+		return nil, fmt.Errorf("invalid event id range: [%d, %d], events up to %d are frozen", fromID, toID, cfg.LastFrozenEventID)
+	}
+
+	toTime := cfg.ToTime
+	if toTime.IsZero() {
+		toTime = time.Now()
+	}
+
+	report := &StateSyncEventsBackfillReport{FromID: fromID}
+	logEvery := time.NewTicker(logInterval)
+	defer logEvery.Stop()
+
+	var prevTime time.Time
+	nextID := fromID
+	for nextID <= toID {
+		eventRecords, err := cfg.HeimdallClient.FetchStateSyncEvents(ctx, nextID, toTime, stateSyncEventsBackfillBatch)
+		if err != nil {
+			return report, err
+		}
+		if len(eventRecords) == 0 {
+			break
+		}
+
+		for _, eventRecord := range eventRecords {
+			if eventRecord.ID > toID {
+				break
+			}
+
+			if eventRecord.ID != nextID || eventRecord.ChainID != cfg.ChainID || eventRecord.Time.Before(prevTime) || !eventRecord.Time.Before(toTime) {
+				return report, fmt.Errorf(
+					"invalid event record received %s, %s, %s",
+					fmt.Sprintf("eventId=%d (exp %d)", eventRecord.ID, nextID),
+					fmt.Sprintf("chainId=%s (exp %s)", eventRecord.ChainID, cfg.ChainID),
+					fmt.Sprintf("time=%s (exp from %s to %s)", eventRecord.Time, prevTime, toTime),
+				)
+			}
+
+			data, err := packStateSyncEvent(cfg.StateReceiverABI, eventRecord)
+			if err != nil {
+				return report, err
+			}
+
+			var eventIdBuf [8]byte
+			binary.BigEndian.PutUint64(eventIdBuf[:], eventRecord.ID)
+			onDisk, err := tx.GetOne(kv.BorEvents, eventIdBuf[:])
+			if err != nil {
+				return report, err
+			}
+
+			switch {
+			case onDisk == nil:
+				report.Missing = append(report.Missing, eventRecord.ID)
+				if !cfg.DryRun {
+					if err = tx.Put(kv.BorEvents, eventIdBuf[:], data); err != nil {
+						return report, err
+					}
+				}
+			case !bytes.Equal(onDisk, data):
+				report.Mismatched = append(report.Mismatched, StateSyncEventMismatch{
+					ID:           eventRecord.ID,
+					DiskHash:     crypto.Keccak256Hash(onDisk),
+					HeimdallHash: crypto.Keccak256Hash(data),
+				})
+			}
+
+			report.Checked++
+			report.LastID = eventRecord.ID
+			prevTime = eventRecord.Time
+			nextID++
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[bridge backfill] Progress", "eventId", report.LastID, "to", toID, "missing", len(report.Missing), "mismatched", len(report.Mismatched))
+		default:
+		}
+
+		if len(eventRecords) < stateSyncEventsBackfillBatch {
+			// Heimdall has no more events before toTime
+			break
+		}
+	}
+
+	return report, nil
+}
+
+func lastStateSyncEventID(tx kv.Tx) (uint64, error) {
+	cursor, err := tx.Cursor(kv.BorEvents)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close()
+
+	k, _, err := cursor.Last()
+	if err != nil {
+		return 0, err
+	}
+	if k == nil {
+		return 0, nil
+	}
+
+	return binary.BigEndian.Uint64(k), nil
+}
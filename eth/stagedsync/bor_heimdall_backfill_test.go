@@ -0,0 +1,140 @@
+package stagedsync_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/polygon/bor"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+func TestBackfillHeimdallStateSyncEvents(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	_, tx := memdb.NewTestTx(t)
+
+	start := time.Unix(1_000, 0)
+	events := make([]*heimdall.EventRecordWithTime, 5)
+	for i := range events {
+		events[i] = &heimdall.EventRecordWithTime{
+			EventRecord: heimdall.EventRecord{ID: uint64(i + 1), ChainID: "137", Data: []byte{byte(i)}},
+			Time:        start.Add(time.Duration(i) * time.Second),
+		}
+	}
+	heimdallClient := heimdall.NewMockHeimdallClient(ctrl)
+	heimdallClient.EXPECT().
+		FetchStateSyncEvents(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).
+		Return(events, nil).
+		Times(2)
+
+	cfg := stagedsync.StateSyncEventsBackfillCfg{
+		HeimdallClient:   heimdallClient,
+		StateReceiverABI: bor.GenesisContractStateReceiverABI(),
+		ChainID:          "137",
+		FromID:           1,
+		ToID:             5,
+		ToTime:           start.Add(time.Hour),
+	}
+
+	// first run writes all events
+	report, err := stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, log.New())
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, report.Missing)
+
+	// drop event 2, corrupt event 4 and check up to event 4 only
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], 2)
+	require.NoError(t, tx.Delete(kv.BorEvents, key[:]))
+	binary.BigEndian.PutUint64(key[:], 4)
+	require.NoError(t, tx.Put(kv.BorEvents, key[:], []byte{0xde, 0xad}))
+
+	cfg.ToID = 4
+	report, err = stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, log.New())
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Checked)
+	require.Equal(t, uint64(4), report.LastID)
+	require.Equal(t, []uint64{2}, report.Missing)
+	require.Len(t, report.Mismatched, 1)
+	require.Equal(t, uint64(4), report.Mismatched[0].ID)
+
+	binary.BigEndian.PutUint64(key[:], 2)
+	restored, err := tx.GetOne(kv.BorEvents, key[:])
+	require.NoError(t, err)
+	require.NotEmpty(t, restored)
+}
+
+func TestBackfillHeimdallStateSyncEventsRejectsGaps(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	_, tx := memdb.NewTestTx(t)
+
+	heimdallClient := heimdall.NewMockHeimdallClient(ctrl)
+	heimdallClient.EXPECT().
+		FetchStateSyncEvents(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).
+		Return([]*heimdall.EventRecordWithTime{
+			{EventRecord: heimdall.EventRecord{ID: 1, ChainID: "137"}, Time: time.Unix(1, 0)},
+			{EventRecord: heimdall.EventRecord{ID: 3, ChainID: "137"}, Time: time.Unix(2, 0)},
+		}, nil).
+		Times(1)
+
+	cfg := stagedsync.StateSyncEventsBackfillCfg{
+		HeimdallClient:   heimdallClient,
+		StateReceiverABI: bor.GenesisContractStateReceiverABI(),
+		ChainID:          "137",
+		FromID:           1,
+		ToID:             3,
+	}
+	report, err := stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, log.New())
+	require.Error(t, err)
+	require.Equal(t, uint64(1), report.LastID)
+}
+
+func TestBackfillHeimdallStateSyncEventsSkipsFrozen(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	_, tx := memdb.NewTestTx(t)
+
+	heimdallClient := heimdall.NewMockHeimdallClient(ctrl)
+	cfg := stagedsync.StateSyncEventsBackfillCfg{
+		HeimdallClient:    heimdallClient,
+		StateReceiverABI:  bor.GenesisContractStateReceiverABI(),
+		ChainID:           "137",
+		LastFrozenEventID: 3,
+	}
+
+	// all events are frozen, nothing to check and Heimdall isn't asked
+	report, err := stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, log.New())
+	require.NoError(t, err)
+	require.Zero(t, report.Checked)
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], 5)
+	require.NoError(t, tx.Put(kv.BorEvents, key[:], []byte{0xde, 0xad}))
+
+	heimdallClient.EXPECT().
+		FetchStateSyncEvents(gomock.Any(), uint64(4), gomock.Any(), gomock.Any()).
+		Return([]*heimdall.EventRecordWithTime{
+			{EventRecord: heimdall.EventRecord{ID: 4, ChainID: "137"}, Time: time.Unix(1, 0)},
+			{EventRecord: heimdall.EventRecord{ID: 5, ChainID: "137"}, Time: time.Unix(2, 0)},
+		}, nil).
+		Times(1)
+
+	// ids covered by snapshots are skipped even if asked for
+	cfg.FromID = 1
+	report, err = stagedsync.BackfillHeimdallStateSyncEvents(ctx, tx, cfg, log.New())
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), report.FromID)
+	require.Equal(t, 2, report.Checked)
+	require.Equal(t, []uint64{4}, report.Missing)
+	require.Len(t, report.Mismatched, 1)
+	require.Equal(t, uint64(5), report.Mismatched[0].ID)
+}
@@ -13,7 +13,10 @@ import (
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/polygon/bridge"
 	"github.com/ledgerwatch/erigon/polygon/heimdall"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/services"
@@ -379,8 +382,13 @@ func fetchAndWriteHeimdallStateSyncEvents(
 	cfg BorHeimdallCfg,
 	logPrefix string,
 	logger log.Logger,
-) (uint64, int, time.Duration, error) {
+) (lastFetchedEventID uint64, records int, fetchTime time.Duration, err error) {
 	fetchStart := time.Now()
+	var lastEventTime time.Time
+	defer func() {
+		heimdall.RecordStateSyncFetch(fetchStart, lastFetchedEventID, lastEventTime, err)
+	}()
+
 	config := cfg.borConfig
 	blockReader := cfg.blockReader
 	heimdallClient := cfg.heimdallClient
@@ -394,15 +402,18 @@ func fetchAndWriteHeimdallStateSyncEvents(
 
 	blockNum := header.Number.Uint64()
 
-	if config.IsIndore(blockNum) {
-		stateSyncDelay := config.CalculateStateSyncDelay(blockNum)
-		to = time.Unix(int64(header.Time-stateSyncDelay), 0)
-	} else {
-		pHeader, err := blockReader.HeaderByNumber(ctx, tx, blockNum-config.CalculateSprintLength(blockNum))
+	to, err = bridge.EventsTimeLimit(config, blockNum, header.Time, func(number uint64) (uint64, error) {
+		pHeader, err := blockReader.HeaderByNumber(ctx, tx, number)
 		if err != nil {
-			return lastStateSyncEventID, 0, time.Since(fetchStart), err
+			return 0, err
 		}
-		to = time.Unix(int64(pHeader.Time), 0)
+		if pHeader == nil {
+			return 0, fmt.Errorf("header not found: %d", number)
+		}
+		return pHeader.Time, nil
+	})
+	if err != nil {
+		return lastStateSyncEventID, 0, time.Since(fetchStart), err
 	}
 
 	fetchTo := to
@@ -466,14 +477,7 @@ func fetchAndWriteHeimdallStateSyncEvents(
 			)
 		}
 
-		eventRecordWithoutTime := eventRecord.BuildEventRecord()
-
-		recordBytes, err := rlp.EncodeToBytes(eventRecordWithoutTime)
-		if err != nil {
-			return lastStateSyncEventID, i, time.Since(fetchStart), err
-		}
-
-		data, err := stateReceiverABI.Pack("commitState", big.NewInt(eventRecord.Time.Unix()), recordBytes)
+		data, err := packStateSyncEvent(stateReceiverABI, eventRecord)
 		if err != nil {
 			logger.Error(fmt.Sprintf("[%s] Unable to pack tx for commitState", logPrefix), "err", err)
 			return lastStateSyncEventID, i, time.Since(fetchStart), err
@@ -485,6 +489,10 @@ func fetchAndWriteHeimdallStateSyncEvents(
 			return lastStateSyncEventID, i, time.Since(fetchStart), err
 		}
 
+		if err = rawdb.WriteBorEventRootTx(tx, eventRecord.TxHash, eventRecord.LogIndex, eventRecord.ID, blockNum); err != nil {
+			return lastStateSyncEventID, i, time.Since(fetchStart), err
+		}
+
 		if !wroteIndex {
 			var blockNumBuf [8]byte
 			binary.BigEndian.PutUint64(blockNumBuf[:], blockNum)
@@ -497,7 +505,18 @@ func fetchAndWriteHeimdallStateSyncEvents(
 		}
 
 		lastStateSyncEventID++
+		lastEventTime = eventRecord.Time
 	}
 
 	return lastStateSyncEventID, len(eventRecords), time.Since(fetchStart), nil
 }
+
+// packStateSyncEvent encodes event record the way it is stored in kv.BorEvents: as commitState call data of the state receiver contract.
+func packStateSyncEvent(stateReceiverABI abi.ABI, eventRecord *heimdall.EventRecordWithTime) ([]byte, error) {
+	recordBytes, err := rlp.EncodeToBytes(eventRecord.BuildEventRecord())
+	if err != nil {
+		return nil, err
+	}
+
+	return stateReceiverABI.Pack("commitState", big.NewInt(eventRecord.Time.Unix()), recordBytes)
+}
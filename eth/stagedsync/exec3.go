@@ -25,6 +25,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
 	"github.com/ledgerwatch/erigon-lib/etl"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
@@ -51,8 +52,8 @@ var execStepsInDB = metrics.NewGauge(`exec_steps_in_db`) //nolint
 var execRepeats = metrics.NewCounter(`exec_repeats`)     //nolint
 var execTriggers = metrics.NewCounter(`exec_triggers`)   //nolint
 
-func NewProgress(prevOutputBlockNum, commitThreshold uint64, workersCount int, logPrefix string, logger log.Logger) *Progress {
-	return &Progress{prevTime: time.Now(), prevOutputBlockNum: prevOutputBlockNum, commitThreshold: commitThreshold, workersCount: workersCount, logPrefix: logPrefix, logger: logger}
+func NewProgress(prevOutputBlockNum, maxBlockNum, commitThreshold uint64, workersCount int, logPrefix string, logger log.Logger) *Progress {
+	return &Progress{prevTime: time.Now(), fromBlockNum: prevOutputBlockNum, maxBlockNum: maxBlockNum, prevOutputBlockNum: prevOutputBlockNum, commitThreshold: commitThreshold, workersCount: workersCount, logPrefix: logPrefix, logger: logger}
 }
 
 type Progress struct {
@@ -61,6 +62,8 @@ type Progress struct {
 	prevOutputBlockNum uint64
 	prevRepeatCount    uint64
 	commitThreshold    uint64
+	fromBlockNum       uint64
+	maxBlockNum        uint64
 
 	workersCount int
 	logPrefix    string
@@ -91,6 +94,7 @@ func (p *Progress) Log(rs *state.StateV3, in *state.QueueWithRetry, rws *state.R
 		"step", fmt.Sprintf("%.1f", float64(outTxNum)/float64(config3.HistoryV3AggregationStep)),
 		"alloc", common.ByteCount(m.Alloc), "sys", common.ByteCount(m.Sys),
 	)
+	diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(stages.Execution), From: p.fromBlockNum, Current: outputBlockNum, To: p.maxBlockNum})
 
 	p.prevTime = currentTime
 	p.prevCount = doneCount
@@ -154,6 +158,7 @@ func ExecV3(ctx context.Context,
 	agg, engine := cfg.agg, cfg.engine
 	chainConfig, genesis := cfg.chainConfig, cfg.genesis
 	blocksFreezeCfg := cfg.blockReader.FreezingCfg()
+	defer agg.StartForegroundWork()()
 
 	if initialCycle {
 		agg.SetCollateAndBuildWorkers(min(2, estimate.StateV3Collate.Workers()))
@@ -329,9 +334,15 @@ func ExecV3(ctx context.Context,
 	applyWorker.DiscardReadList()
 
 	commitThreshold := batchSize.Bytes()
-	progress := NewProgress(blockNum, commitThreshold, workerCount, execStage.LogPrefix(), logger)
+	progress := NewProgress(blockNum, maxBlockNum, commitThreshold, workerCount, execStage.LogPrefix(), logger)
 	logEvery := time.NewTicker(20 * time.Second)
 	defer logEvery.Stop()
+	// periodic commits are pointless if tx is owned by caller, and impossible for in-memory execution
+	commitEvery := cfg.syncCfg.ExecCommitEvery
+	if useExternalTx || inMemExec {
+		commitEvery = 0
+	}
+	lastCommitTime := time.Now()
 	pruneEvery := time.NewTicker(2 * time.Second)
 	defer pruneEvery.Stop()
 
@@ -566,9 +577,18 @@ func ExecV3(ctx context.Context,
 		defer agg.KeepStepsInDB(0).KeepStepsInDB(1)
 	}
 
+	// Experimental: optimistic parallel execution of txs inside block. Workers have own RoTx - so they see only committed
+	// data (external tx is not supported), and don't produce notifications. Used only in initial sync.
+	var parallelExec *exec3.ParallelExecutor
+	if cfg.syncCfg.ExecParallel && initialCycle && !parallel && !useExternalTx && !inMemExec && accumulator == nil && workerCount > 1 {
+		parallelExec = exec3.NewParallelExecutor(ctx, workerCount, chainDb, rs, blockReader, chainConfig, genesis, engine, cfg.dirs, logger)
+		defer parallelExec.Close()
+		logger.Info(fmt.Sprintf("[%s] parallel execution of txs (experimental)", execStage.LogPrefix()), "workers", workerCount)
+	}
+
 	getHeaderFunc := func(hash common.Hash, number uint64) (h *types.Header) {
 		var err error
-		if parallel {
+		if parallel || parallelExec != nil { // applyTx can't be used by parallel workers
 			if err = chainDb.View(ctx, func(tx kv.Tx) error {
 				h, err = blockReader.Header(ctx, tx, hash, number)
 				if err != nil {
@@ -686,16 +706,22 @@ Loop:
 		// So we skip that check for the first block, if we find half-executed data.
 		skipPostEvaluation := false
 		var usedGas, blobGasUsed uint64
-		for txIndex := -1; txIndex <= len(txs); txIndex++ {
-			// Do not oversend, wait for the result heap to go under certain size
-			txTask := &state.TxTask{
+		// resumed from a mid-block checkpoint: committed txs are skipped, but their gas was saved with the checkpoint
+		resumedMidBlock := false
+		if blockNum == initialBlockNum && txNumInDB > 0 {
+			if usedGas, blobGasUsed, resumedMidBlock, err = readMidBlockGas(applyTx, blockNum, txNumInDB); err != nil {
+				return err
+			}
+		}
+		newTxTask := func(txIndex int, txNum uint64) *state.TxTask {
+			return &state.TxTask{
 				BlockNum:        blockNum,
 				Header:          header,
 				Coinbase:        b.Coinbase(),
 				Uncles:          b.Uncles(),
 				Rules:           rules,
 				Txs:             txs,
-				TxNum:           inputTxNum,
+				TxNum:           txNum,
 				TxIndex:         txIndex,
 				BlockHash:       b.Hash(),
 				SkipAnalysis:    skipAnalysis,
@@ -709,9 +735,145 @@ Loop:
 
 				BlockReceipts: receipts,
 			}
+		}
+		prepareTx := func(txTask *state.TxTask) error {
+			txTask.Tx = txs[txTask.TxIndex]
+			txTask.TxAsMessage, err = txTask.Tx.AsMessage(signer, header.BaseFee, txTask.Rules)
+			if err != nil {
+				return err
+			}
+
+			if sender, ok := txTask.Tx.GetSender(); ok {
+				txTask.Sender = &sender
+			} else {
+				sender, err := signer.Sender(txTask.Tx)
+				if err != nil {
+					return err
+				}
+				txTask.Sender = &sender
+				logger.Warn("[Execution] expensive lazy sender recovery", "blockNum", txTask.BlockNum, "txIdx", txTask.TxIndex)
+			}
+			return nil
+		}
+
+		// optimistically execute all txs of block on top of state before block. Results are validated (and conflicting
+		// txs re-executed) below - in txs order
+		var speculated []*state.TxTask
+		if parallelExec != nil && len(txs) > 1 && offsetFromBlockBeginning == 0 && inputTxNum > txNumInDB {
+			speculated = make([]*state.TxTask, len(txs))
+			for i := range txs {
+				speculated[i] = newTxTask(i, inputTxNum+1+uint64(i)) // +1 for block initialisation tx
+				if err := prepareTx(speculated[i]); err != nil {
+					return err
+				}
+			}
+			parallelExec.Execute(ctx, speculated)
+		}
+
+		// commit - flushes state and commits applyTx. Mid-block there is no state root to check yet: commitment is saved
+		// for the last executed txNum, and after restart execution continues from the next tx of the block (see restoreTxNum).
+		commit := func(midBlock bool) (bool, error) {
+			var (
+				commitStart = time.Now()
+				tt          = time.Now()
+
+				t1, t2, t3 time.Duration
+			)
+
+			executedBlockNum := outputBlockNum.GetValueUint64()
+			if midBlock {
+				executedBlockNum = blockNum - 1
+				if _, err := doms.ComputeCommitment(ctx, true, blockNum, execStage.LogPrefix()); err != nil {
+					return false, err
+				}
+				if err := doms.Flush(ctx, applyTx); err != nil {
+					return false, err
+				}
+				// gas of the committed txs is needed for post-validation of the block after restart
+				if err := writeMidBlockGas(applyTx, blockNum, doms.TxNum(), usedGas, blobGasUsed); err != nil {
+					return false, err
+				}
+			} else if ok, err := flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), applyTx, doms, cfg, execStage, stageProgress, parallel, logger, u, inMemExec); err != nil {
+				return false, err
+			} else if !ok {
+				return false, nil
+			} else if err := applyTx.Delete(kv.SyncStageProgress, midBlockGasKey); err != nil {
+				return false, err
+			}
+			t1 = time.Since(tt)
+
+			tt = time.Now()
+			// If execute more than 100 blocks then, it is safe to assume that we are not on the tip of the chain.
+			// In this case, we can prune the state to save memory.
+			pruneBlockMargin := uint64(100)
+
+			if blockNum-initialBlockNum > pruneBlockMargin {
+				if _, err := applyTx.(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneSmallBatches(ctx, 10*time.Minute, applyTx); err != nil {
+					return false, err
+				}
+			}
+			t3 = time.Since(tt)
+
+			if err := func() error {
+				doms.Close()
+				if err = execStage.Update(applyTx, executedBlockNum); err != nil {
+					return err
+				}
+
+				tt = time.Now()
+				applyTx.CollectMetrics()
+				if !useExternalTx {
+					tt = time.Now()
+					if err = applyTx.Commit(); err != nil {
+						return err
+					}
+
+					t2 = time.Since(tt)
+					if blocksFreezeCfg.Produce {
+						agg.BuildFilesInBackground(outputTxNum.Load())
+					}
+
+					applyTx, err = cfg.db.BeginRw(context.Background()) //nolint
+					if err != nil {
+						return err
+					}
+				}
+				doms, err = state2.NewSharedDomains(applyTx, logger)
+				if err != nil {
+					return err
+				}
+				doms.SetTxNum(inputTxNum)
+				rs = state.NewStateV3(doms, logger)
+
+				applyWorker.ResetTx(applyTx)
+				applyWorker.ResetState(rs, accumulator)
+				if parallelExec != nil {
+					parallelExec.ResetState(rs)
+				}
+
+				return nil
+			}(); err != nil {
+				return false, err
+			}
+			lastCommitTime = time.Now()
+			logger.Info("Committed", "time", time.Since(commitStart),
+				"block", doms.BlockNum(), "txNum", doms.TxNum(), "midBlock", midBlock,
+				"step", fmt.Sprintf("%.1f", float64(doms.TxNum())/float64(agg.StepSize())),
+				"flush+commitment", t1, "tx.commit", t2, "prune", t3)
+			return true, nil
+		}
+
+		for txIndex := -1; txIndex <= len(txs); txIndex++ {
+			// Do not oversend, wait for the result heap to go under certain size
+			var txTask *state.TxTask
+			if speculated != nil && txIndex >= 0 && txIndex < len(txs) {
+				txTask = speculated[txIndex]
+			} else {
+				txTask = newTxTask(txIndex, inputTxNum)
+			}
 			if txTask.TxNum <= txNumInDB && txTask.TxNum > 0 {
 				inputTxNum++
-				skipPostEvaluation = true
+				skipPostEvaluation = !resumedMidBlock
 				continue
 			}
 			doms.SetTxNum(txTask.TxNum)
@@ -720,23 +882,10 @@ Loop:
 			//if txTask.HistoryExecution { // nolint
 			//	fmt.Printf("[dbg] txNum: %d, hist=%t\n", txTask.TxNum, txTask.HistoryExecution)
 			//}
-			if txIndex >= 0 && txIndex < len(txs) {
-				txTask.Tx = txs[txIndex]
-				txTask.TxAsMessage, err = txTask.Tx.AsMessage(signer, header.BaseFee, txTask.Rules)
-				if err != nil {
+			if speculated == nil && txIndex >= 0 && txIndex < len(txs) {
+				if err := prepareTx(txTask); err != nil {
 					return err
 				}
-
-				if sender, ok := txs[txIndex].GetSender(); ok {
-					txTask.Sender = &sender
-				} else {
-					sender, err := signer.Sender(txTask.Tx)
-					if err != nil {
-						return err
-					}
-					txTask.Sender = &sender
-					logger.Warn("[Execution] expensive lazy sender recovery", "blockNum", txTask.BlockNum, "txIdx", txTask.TxIndex)
-				}
 			}
 
 			if parallel {
@@ -749,10 +898,25 @@ Loop:
 				}
 			} else {
 				count++
+				optimistic := speculated != nil && txIndex >= 0 && txIndex < len(txs)
+				if optimistic && (txTask.Error != nil || !rs.ReadsValid(txTask.ReadLists)) {
+					// conflict with one of previous txs of block (or can't be applied as write-set) - re-execute sequentially
+					execRepeats.AddInt(1)
+					txTask.Reset()
+					txTask.Error = nil
+					optimistic = false
+				}
+				if optimistic {
+					if err := exec3.SettleDeferredFees(rs, txTask); err != nil {
+						return err
+					}
+				}
 				if txTask.Error != nil {
 					break Loop
 				}
-				applyWorker.RunTxTaskNoLock(txTask)
+				if !optimistic {
+					applyWorker.RunTxTaskNoLock(txTask)
+				}
 				if err := func() error {
 					if errors.Is(txTask.Error, context.Canceled) {
 						return err
@@ -825,12 +989,27 @@ Loop:
 				if err := rs.ApplyState4(ctx, txTask); err != nil {
 					return err
 				}
+				if cfg.syncCfg.ExecOtsIndex && !cfg.blockProduction {
+					if err := rawdb.WriteOtsAppearances(applyTx, txTask.BlockNum, otsAppearances(txTask)); err != nil {
+						return err
+					}
+				}
 
 				execTriggers.AddInt(rs.CommitTxNum(txTask.Sender, txTask.TxNum, in))
 				outputTxNum.Add(1)
 			}
 			stageProgress = blockNum
 			inputTxNum++
+
+			// huge blocks take a while: checkpoint between their txs too, restart skips txs which are already committed.
+			// Not after block initialisation: it may change nothing, and then commitment state is not saved
+			if !parallel && txIndex >= 0 && txIndex < len(txs) && commitEvery > 0 && time.Since(lastCommitTime) >= commitEvery {
+				if ok, err := commit(true); err != nil {
+					return err
+				} else if !ok {
+					break Loop
+				}
+			}
 		}
 		if offsetFromBlockBeginning > 0 {
 			// after history execution no offset will be required
@@ -848,84 +1027,34 @@ Loop:
 			//}
 
 			outputBlockNum.SetUint64(blockNum)
+			// keys touched by finished blocks get hashed and sorted in background, while next blocks execute
+			doms.PrecomputeCommitment()
+
+			// commit progress checkpoint regardless of batch size, so restart loses at most `commitEvery` of work
+			commitDue := commitEvery > 0 && time.Since(lastCommitTime) >= commitEvery
 
 			select {
 			case <-logEvery.C:
 				stepsInDB := rawdbhelpers.IdxStepsCountV3(applyTx)
 				progress.Log(rs, in, rws, count, inputBlockNum.Load(), outputBlockNum.GetValueUint64(), outputTxNum.Load(), execRepeats.GetValueUint64(), stepsInDB)
 				// If we skip post evaluation, then we should compute root hash ASAP for fail-fast
-				if !skipPostEvaluation && (rs.SizeEstimate() < commitThreshold || inMemExec) {
+				if !commitDue && !skipPostEvaluation && (rs.SizeEstimate() < commitThreshold || inMemExec) {
 					break
 				}
-				var (
-					commitStart = time.Now()
-					tt          = time.Now()
-
-					t1, t2, t3 time.Duration
-				)
-
-				if ok, err := flushAndCheckCommitmentV3(ctx, b.HeaderNoCopy(), applyTx, doms, cfg, execStage, stageProgress, parallel, logger, u, inMemExec); err != nil {
+				if ok, err := commit(false); err != nil {
 					return err
 				} else if !ok {
 					break Loop
 				}
-				t1 = time.Since(tt)
-
-				tt = time.Now()
-				// If execute more than 100 blocks then, it is safe to assume that we are not on the tip of the chain.
-				// In this case, we can prune the state to save memory.
-				pruneBlockMargin := uint64(100)
-
-				if blockNum-initialBlockNum > pruneBlockMargin {
-					if _, err := applyTx.(state2.HasAggTx).AggTx().(*state2.AggregatorRoTx).PruneSmallBatches(ctx, 10*time.Minute, applyTx); err != nil {
-						return err
-					}
+			default:
+				if !commitDue {
+					break
 				}
-				t3 = time.Since(tt)
-
-				if err := func() error {
-					doms.Close()
-					if err = execStage.Update(applyTx, outputBlockNum.GetValueUint64()); err != nil {
-						return err
-					}
-
-					tt = time.Now()
-					applyTx.CollectMetrics()
-					if !useExternalTx {
-						tt = time.Now()
-						if err = applyTx.Commit(); err != nil {
-							return err
-						}
-
-						t2 = time.Since(tt)
-						if blocksFreezeCfg.Produce {
-							agg.BuildFilesInBackground(outputTxNum.Load())
-						}
-
-						applyTx, err = cfg.db.BeginRw(context.Background()) //nolint
-						if err != nil {
-							return err
-						}
-					}
-					doms, err = state2.NewSharedDomains(applyTx, logger)
-					if err != nil {
-						return err
-					}
-					doms.SetTxNum(inputTxNum)
-					rs = state.NewStateV3(doms, logger)
-
-					applyWorker.ResetTx(applyTx)
-					applyWorker.ResetState(rs, accumulator)
-
-					return nil
-				}(); err != nil {
+				if ok, err := commit(false); err != nil {
 					return err
+				} else if !ok {
+					break Loop
 				}
-				logger.Info("Committed", "time", time.Since(commitStart),
-					"block", doms.BlockNum(), "txNum", doms.TxNum(),
-					"step", fmt.Sprintf("%.1f", float64(doms.TxNum())/float64(agg.StepSize())),
-					"flush+commitment", t1, "tx.commit", t2, "prune", t3)
-			default:
 			}
 		}
 
@@ -991,7 +1120,7 @@ func dumpPlainStateDebug(tx kv.RwTx, doms *state2.SharedDomains) {
 				panic(err)
 			}
 			a := accounts.NewAccount()
-			accounts.DeserialiseV3(&a, v)
+			accounts.DeserialiseState(&a, v)
 			fmt.Printf("%x, %d, %d, %d, %x\n", k, &a.Balance, a.Nonce, a.Incarnation, a.CodeHash)
 		}
 	}
@@ -1115,6 +1244,31 @@ func flushAndCheckCommitmentV3(ctx context.Context, header *types.Header, applyT
 	return false, nil
 }
 
+// midBlockGasKey - gas used by the txs of a block committed by a mid-block checkpoint:
+// blockNum, txNum of the checkpoint, usedGas, blobGasUsed
+var midBlockGasKey = []byte("exec_mid_block_gas")
+
+func writeMidBlockGas(tx kv.Putter, blockNum, txNum, usedGas, blobGasUsed uint64) error {
+	v := make([]byte, 32)
+	binary.BigEndian.PutUint64(v, blockNum)
+	binary.BigEndian.PutUint64(v[8:], txNum)
+	binary.BigEndian.PutUint64(v[16:], usedGas)
+	binary.BigEndian.PutUint64(v[24:], blobGasUsed)
+	return tx.Put(kv.SyncStageProgress, midBlockGasKey, v)
+}
+
+// readMidBlockGas - ok=false if the last commit wasn't a mid-block checkpoint at `txNum` of `blockNum`
+func readMidBlockGas(tx kv.Getter, blockNum, txNum uint64) (usedGas, blobGasUsed uint64, ok bool, err error) {
+	v, err := tx.GetOne(kv.SyncStageProgress, midBlockGasKey)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if len(v) != 32 || binary.BigEndian.Uint64(v) != blockNum || binary.BigEndian.Uint64(v[8:]) != txNum {
+		return 0, 0, false, nil
+	}
+	return binary.BigEndian.Uint64(v[16:]), binary.BigEndian.Uint64(v[24:]), true, nil
+}
+
 func blockWithSenders(ctx context.Context, db kv.RoDB, tx kv.Tx, blockReader services.BlockReader, blockNum uint64) (b *types.Block, err error) {
 	if tx == nil {
 		tx, err = db.BeginRo(ctx)
@@ -0,0 +1,176 @@
+package stagedsync_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/dbutils"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/erigon-lib/wrap"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/ethdb/prune"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/shards"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+// cancelOnMidBlockCommit - cancels execution once it committed a mid-block checkpoint
+type cancelOnMidBlockCommit struct {
+	cancel context.CancelFunc
+}
+
+func (h cancelOnMidBlockCommit) Log(r *log.Record) error {
+	if r.Msg != "Committed" {
+		return nil
+	}
+	for i := 0; i+1 < len(r.Ctx); i += 2 {
+		if r.Ctx[i] == "midBlock" && r.Ctx[i+1] == true {
+			h.cancel()
+		}
+	}
+	return nil
+}
+
+// interruptedMidBlock - executes 2 blocks with a checkpoint after every tx and stops right after the first one, in the middle of block 1
+func interruptedMidBlock(t *testing.T) (m *mock.MockSentry, exec func(ctx context.Context, logger log.Logger) error) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	gspec := &types.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	m = mock.MockWithGenesis(t, gspec, key, false)
+	signer := types.LatestSigner(m.ChainConfig)
+
+	const blocks, txsPerBlock = 2, 4
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, blocks, func(i int, b *core.BlockGen) {
+		for j := 0; j < txsPerBlock; j++ {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(addr), libcommon.Address{byte(j + 1)}, uint256.NewInt(1), params.TxGas, uint256.NewInt(params.GWei), nil), *signer, key)
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+	})
+	require.NoError(t, err)
+
+	// blocks are downloaded, but not executed
+	_, bw := m.BlocksIO()
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		for _, b := range chain.Blocks {
+			if err := rawdb.WriteBlock(tx, b); err != nil {
+				return err
+			}
+			if err := rawdb.WriteCanonicalHash(tx, b.Hash(), b.NumberU64()); err != nil {
+				return err
+			}
+			senders := make([]libcommon.Address, len(b.Transactions()))
+			for i := range senders {
+				senders[i] = addr
+			}
+			if err := rawdb.WriteSenders(tx, b.Hash(), b.NumberU64(), senders); err != nil {
+				return err
+			}
+		}
+		if err := bw.MakeBodiesCanonical(tx, 1); err != nil {
+			return err
+		}
+		for _, stage := range []stages.SyncStage{stages.Headers, stages.Bodies, stages.Senders} {
+			if err := stages.SaveStageProgress(tx, stage, blocks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	syncCfg := ethconfig.Defaults.Sync
+	syncCfg.ExecCommitEvery = time.Nanosecond
+	cfg := stagedsync.StageExecuteBlocksCfg(m.DB, prune.DefaultMode, ethconfig.Defaults.BatchSize, nil, m.ChainConfig, m.Engine, &vm.Config{}, shards.NewAccumulator(), false, true, m.Dirs, m.BlockReader, nil, gspec, syncCfg, m.HistoryV3Components(), nil)
+	u := stagedsync.New(syncCfg, []*stagedsync.Stage{{ID: stages.Execution}}, nil, nil, log.New())
+	exec = func(ctx context.Context, logger log.Logger) error {
+		var s *stagedsync.StageState
+		if err := m.DB.View(ctx, func(tx kv.Tx) (err error) {
+			s, err = u.StageState(stages.Execution, tx, nil)
+			return err
+		}); err != nil {
+			return err
+		}
+		return stagedsync.ExecBlockV3(s, u, wrap.TxContainer{}, 0, ctx, cfg, true, logger)
+	}
+
+	// interrupted right after first checkpoint in the middle of block 1
+	ctx, cancel := context.WithCancel(m.Ctx)
+	defer cancel()
+	logger := log.New()
+	logger.SetHandler(cancelOnMidBlockCommit{cancel: cancel})
+	require.Error(t, exec(ctx, logger))
+
+	require.NoError(t, m.DB.View(m.Ctx, func(tx kv.Tx) error {
+		doms, err := libstate.NewSharedDomains(tx, log.New())
+		require.NoError(t, err)
+		defer doms.Close()
+		fromTxNum, err := rawdbv3.TxNums.Min(tx, 1)
+		require.NoError(t, err)
+		toTxNum, err := rawdbv3.TxNums.Max(tx, 1)
+		require.NoError(t, err)
+		require.Greater(t, doms.TxNum(), fromTxNum)
+		require.Less(t, doms.TxNum(), toTxNum)
+		progress, err := stages.GetStageProgress(tx, stages.Execution)
+		require.NoError(t, err)
+		require.Zero(t, progress)
+		return nil
+	}))
+	return m, exec
+}
+
+func TestExecCommitEveryMidBlock(t *testing.T) {
+	t.Run("Resume", func(t *testing.T) {
+		m, exec := interruptedMidBlock(t)
+
+		// restart continues from the checkpoint, state root of every block is checked
+		require.NoError(t, exec(m.Ctx, log.New()))
+		require.NoError(t, m.DB.View(m.Ctx, func(tx kv.Tx) error {
+			progress, err := stages.GetStageProgress(tx, stages.Execution)
+			require.NoError(t, err)
+			require.Equal(t, uint64(2), progress)
+			return nil
+		}))
+	})
+	t.Run("WrongGasUsed", func(t *testing.T) {
+		m, exec := interruptedMidBlock(t)
+
+		// gasUsed of the resumed block is still checked: the txs committed before the checkpoint count too
+		require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+			hash, err := rawdb.ReadCanonicalHash(tx, 1)
+			if err != nil {
+				return err
+			}
+			header := rawdb.ReadHeader(tx, hash, 1)
+			header.GasUsed++
+			enc, err := rlp.EncodeToBytes(header)
+			if err != nil {
+				return err
+			}
+			return tx.Put(kv.Headers, dbutils.HeaderKey(1, hash), enc)
+		}))
+		err := exec(m.Ctx, log.New())
+		require.ErrorIs(t, err, consensus.ErrInvalidBlock)
+		require.ErrorContains(t, err, "gas used")
+	})
+}
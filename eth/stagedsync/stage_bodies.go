@@ -203,6 +203,7 @@ func BodiesForward(
 			select {
 			case <-logEvery.C:
 				logWritingBodies(logPrefix, bodyProgress, headerProgress, logger)
+				diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(s.ID), From: s.BlockNumber, Current: bodyProgress, To: headerProgress})
 			default:
 			}
 			nextBlock := requestedLow + i
@@ -22,6 +22,7 @@ import (
 	"github.com/ledgerwatch/erigon/accounts/abi"
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/dataflow"
 	"github.com/ledgerwatch/erigon/eth/ethconfig/estimate"
@@ -859,7 +860,11 @@ func BorHeimdallUnwind(u *UnwindState, ctx context.Context, _ *StageState, tx kv
 				return err
 			}
 			defer eventCursor.Close()
-			for v, _, err = eventCursor.Seek(v); err == nil && v != nil; v, _, err = eventCursor.Next() {
+			var event []byte
+			for v, event, err = eventCursor.Seek(v); err == nil && v != nil; v, event, err = eventCursor.Next() {
+				if err = unwindBorEventRootTx(tx, cfg.stateReceiverABI, event); err != nil {
+					return err
+				}
 				if err = eventCursor.DeleteCurrent(); err != nil {
 					return err
 				}
@@ -979,3 +984,12 @@ func BorHeimdallPrune(_ *PruneState, _ context.Context, _ kv.RwTx, cfg BorHeimda
 
 	return
 }
+
+// unwindBorEventRootTx - removes the root chain transaction index entry of the state sync event being unwound
+func unwindBorEventRootTx(tx kv.RwTx, stateReceiverABI abi.ABI, event []byte) error {
+	eventRecord, err := heimdall.UnpackEventRecordWithTime(stateReceiverABI, event)
+	if err != nil {
+		return err
+	}
+	return rawdb.DeleteBorEventRootTx(tx, eventRecord.TxHash, eventRecord.LogIndex)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/eth/stagedsync"
@@ -180,6 +181,16 @@ func TestBorHeimdallForwardPersistsStateSyncEvents(t *testing.T) {
 	require.Equal(t, uint64(4), firstEventNumPerBlock[64])
 	require.Equal(t, uint64(5), firstEventNumPerBlock[80])
 	require.Equal(t, uint64(6), firstEventNumPerBlock[96])
+
+	// events are indexed by the root chain transaction and log index which emitted them
+	for eventID := uint64(1); eventID <= 6; eventID++ {
+		entries, err := testHarness.ReadStateSyncEventsByRootTxFromDB(ctx, stagedsynctest.MockRootTxHash(eventID))
+		require.NoError(t, err)
+		require.Equal(t, []rawdb.BorEventRootTxEntry{{LogIndex: eventID, EventID: eventID, BlockNum: 16 * eventID}}, entries)
+	}
+	entries, err := testHarness.ReadStateSyncEventsByRootTxFromDB(ctx, stagedsynctest.MockRootTxHash(7))
+	require.NoError(t, err)
+	require.Empty(t, entries)
 }
 
 func TestBorHeimdallForwardErrHeaderValidatorsLengthMismatch(t *testing.T) {
@@ -370,6 +370,12 @@ func unwindExec3(u *UnwindState, s *StageState, txc wrap.TxContainer, ctx contex
 	if err := rawdb.DeleteNewerEpochs(txc.Tx, u.UnwindPoint+1); err != nil {
 		return fmt.Errorf("delete newer epochs: %w", err)
 	}
+	if err := rawdb.TruncateExecutionWitnesses(txc.Tx, u.UnwindPoint+1); err != nil {
+		return fmt.Errorf("truncate execution witnesses: %w", err)
+	}
+	if err := rawdb.TruncateOtsAppearances(txc.Tx, u.UnwindPoint+1); err != nil {
+		return fmt.Errorf("truncate ots appearances: %w", err)
+	}
 	fmt.Printf("unwindv3: %d -> %d done within %s\n", s.BlockNumber, u.UnwindPoint, time.Since(start))
 	return nil
 }
@@ -401,9 +407,16 @@ func SpawnExecuteBlocksStage(s *StageState, u Unwinder, txc wrap.TxContainer, to
 		return nil
 	}
 	if cfg.historyV3 {
+		from := s.BlockNumber
 		if err = ExecBlockV3(s, u, txc, toBlock, ctx, cfg, initialCycle, logger); err != nil {
 			return err
 		}
+		// in-memory execution doesn't flush state to the tx, witnesses are emitted once blocks are executed for real
+		if cfg.syncCfg.ExecWitnessBlocks > 0 && txc.Doms == nil && !cfg.blockProduction {
+			if err = spawnExecutionWitnesses(ctx, txc.Tx, from, cfg, s.LogPrefix(), logger); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 	if config3.EnableHistoryV4InTest {
@@ -779,6 +792,8 @@ func logProgress(logPrefix string, prevBlock uint64, prevTime time.Time, current
 		TimeElapsed: time.Since(startTime).Round(time.Second).Seconds(),
 	})
 
+	diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(stages.Execution), From: from, Current: currentBlock, To: to})
+
 	logger.Info(fmt.Sprintf("[%s] Executed blocks", logPrefix), logpairs...)
 
 	return currentBlock, currentTx, currentTime
@@ -866,6 +881,9 @@ func PruneExecutionStage(s *PruneState, tx kv.RwTx, cfg ExecuteBlockCfg, ctx con
 	if _, err = tx.(*temporal.Tx).AggTx().(*libstate.AggregatorRoTx).PruneSmallBatches(ctx, pruneTimeout, tx); err != nil { // prune part of retired data, before commit
 		return err
 	}
+	if err = pruneExecutionWitnesses(ctx, tx, s.ForwardProgress, cfg); err != nil {
+		return err
+	}
 
 	if err = s.Done(tx); err != nil {
 		return err
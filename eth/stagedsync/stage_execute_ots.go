@@ -0,0 +1,45 @@
+package stagedsync
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+)
+
+// transferTopic - Transfer(address,address,uint256), same signature for ERC-20 and ERC-721
+var transferTopic = libcommon.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// otsAppearances - address appearances of executed tx for ots2_* methods: contract creations, self-destructs
+// and holders of transferred tokens. ERC-20 and ERC-721 transfers are told apart by indexed tokenId.
+func otsAppearances(txTask *state.TxTask) []rawdb.OtsAppearance {
+	var res []rawdb.OtsAppearance
+	for contract, deployer := range txTask.Creations {
+		res = append(res, rawdb.OtsAppearance{Kind: rawdb.OtsContractCreation, Address: deployer, Counterpart: contract})
+	}
+	for contract, beneficiary := range txTask.SelfDestructs {
+		res = append(res, rawdb.OtsAppearance{Kind: rawdb.OtsSelfDestruct, Address: contract, Counterpart: beneficiary})
+	}
+	for _, lg := range txTask.Logs {
+		if len(lg.Topics) == 0 || lg.Topics[0] != transferTopic {
+			continue
+		}
+		var kind rawdb.OtsAppearanceKind
+		switch {
+		case len(lg.Topics) == 3 && len(lg.Data) == 32:
+			kind = rawdb.OtsERC20Transfer
+		case len(lg.Topics) == 4 && len(lg.Data) == 0:
+			kind = rawdb.OtsERC721Transfer
+		default:
+			continue
+		}
+		for _, topic := range lg.Topics[1:3] {
+			holder := libcommon.BytesToAddress(topic[12:])
+			if holder == (libcommon.Address{}) { // mint or burn
+				continue
+			}
+			res = append(res, rawdb.OtsAppearance{Kind: kind, Address: holder, Counterpart: lg.Address})
+		}
+	}
+	return res
+}
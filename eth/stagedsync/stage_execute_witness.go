@@ -0,0 +1,84 @@
+package stagedsync
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/stateless"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+)
+
+// writeExecutionWitnesses - emits witnesses of blocks executed in (from, to] which are within
+// ExecWitnessBlocks of `to`. Executed state must be flushed to tx already: blocks are re-executed on top of
+// history of their parents.
+func writeExecutionWitnesses(ctx context.Context, tx kv.RwTx, from, to uint64, cfg ExecuteBlockCfg, logPrefix string, logger log.Logger) error {
+	limit := cfg.syncCfg.ExecWitnessBlocks
+	if limit == 0 || to <= from {
+		return nil
+	}
+	if to-from > limit {
+		from = to - limit
+	}
+
+	chainReader := consensuschain.NewReader(cfg.chainConfig, tx, cfg.blockReader, logger)
+	for blockNum := from + 1; blockNum <= to; blockNum++ {
+		hash, err := cfg.blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return err
+		}
+		block, _, err := cfg.blockReader.BlockWithSenders(ctx, tx, hash, blockNum)
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			return fmt.Errorf("[%s] witness: block %d not found", logPrefix, blockNum)
+		}
+		witness, err := stateless.Generate(ctx, tx, block, cfg.chainConfig, cfg.engine, chainReader, nil, logger)
+		if err != nil {
+			return fmt.Errorf("[%s] witness: %w", logPrefix, err)
+		}
+		enc, err := witness.EncodeRLP()
+		if err != nil {
+			return err
+		}
+		if err := rawdb.WriteExecutionWitness(tx, hash, blockNum, enc); err != nil {
+			return err
+		}
+		logger.Debug(fmt.Sprintf("[%s] Emitted execution witness", logPrefix), "block", blockNum, "nodes", len(witness.State), "keys", len(witness.Keys), "codes", len(witness.Codes))
+	}
+	return nil
+}
+
+// spawnExecutionWitnesses - writes witnesses of blocks executed by the current stage run, in its tx or in
+// a new one if the stage committed its own txs
+func spawnExecutionWitnesses(ctx context.Context, tx kv.RwTx, from uint64, cfg ExecuteBlockCfg, logPrefix string, logger log.Logger) error {
+	if tx != nil {
+		to, err := stages.GetStageProgress(tx, stages.Execution)
+		if err != nil {
+			return err
+		}
+		return writeExecutionWitnesses(ctx, tx, from, to, cfg, logPrefix, logger)
+	}
+	return cfg.db.Update(ctx, func(tx kv.RwTx) error {
+		to, err := stages.GetStageProgress(tx, stages.Execution)
+		if err != nil {
+			return err
+		}
+		return writeExecutionWitnesses(ctx, tx, from, to, cfg, logPrefix, logger)
+	})
+}
+
+// pruneExecutionWitnesses - keeps witnesses of the last ExecWitnessBlocks blocks only
+func pruneExecutionWitnesses(ctx context.Context, tx kv.RwTx, progress uint64, cfg ExecuteBlockCfg) error {
+	limit := cfg.syncCfg.ExecWitnessBlocks
+	if limit == 0 || progress <= limit {
+		return nil
+	}
+	return rawdb.PruneTable(tx, kv.ExecutionWitness, progress-limit+1, ctx, math.MaxInt32)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon-lib/kv/temporal/historyv2"
+	"github.com/ledgerwatch/erigon-lib/types/statecodec"
 	"github.com/ledgerwatch/log/v3"
 	"golang.org/x/sync/errgroup"
 
@@ -732,7 +733,7 @@ func (p *Promoter) UnwindOnHistoryV3(logPrefix string, unwindFrom, unwindTo uint
 			if len(v) == 0 {
 				continue
 			}
-			if err := accounts.DeserialiseV3(&acc, v); err != nil {
+			if err := accounts.DeserialiseState(&acc, v); err != nil {
 				return err
 			}
 
@@ -782,7 +783,7 @@ func (p *Promoter) UnwindOnHistoryV3(logPrefix string, unwindFrom, unwindTo uint
 			if err != nil {
 				return err
 			}
-			if err := collector.Collect(newK, v); err != nil {
+			if err := collector.Collect(newK, statecodec.Active().DecodeStorage(v)); err != nil {
 				return err
 			}
 		}
@@ -810,7 +811,7 @@ func (p *Promoter) UnwindOnHistoryV3(logPrefix string, unwindFrom, unwindTo uint
 			}
 			continue
 		}
-		if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		if err := accounts.DeserialiseState(&acc, v); err != nil {
 			return err
 		}
 		if acc.Incarnation > 0 && acc.IsEmptyCodeHash() {
@@ -298,6 +298,7 @@ Loop:
 			progress := cfg.hd.Progress()
 			stats := cfg.hd.ExtractStats()
 			logProgressHeaders(logPrefix, prevProgress, progress, stats, logger)
+			diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(s.ID), From: startProgress, Current: progress})
 			if prevProgress == progress {
 				noProgressCounter++
 			} else {
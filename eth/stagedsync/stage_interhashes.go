@@ -439,7 +439,7 @@ func (p *HashPromoter) UnwindOnHistoryV3(logPrefix string, unwindFrom, unwindTo
 					deletedAccounts = append(deletedAccounts, newK)
 				} else {
 					var newAccount accounts.Account
-					if err = accounts.DeserialiseV3(&newAccount, v); err != nil {
+					if err = accounts.DeserialiseState(&newAccount, v); err != nil {
 						return err
 					}
 					if newAccount.Incarnation > oldInc {
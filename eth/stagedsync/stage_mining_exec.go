@@ -28,6 +28,9 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/aapool"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/turbo/services"
@@ -46,6 +49,7 @@ type MiningExecCfg struct {
 	payloadId   uint64
 	txPool      TxPoolForMining
 	txPoolDB    kv.RoDB
+	aaPool      *aapool.Pool
 }
 
 type TxPoolForMining interface {
@@ -57,7 +61,7 @@ func StageMiningExecCfg(
 	notifier ChainEventNotifier, chainConfig chain.Config,
 	engine consensus.Engine, vmConfig *vm.Config,
 	tmpdir string, interrupt *int32, payloadId uint64,
-	txPool TxPoolForMining, txPoolDB kv.RoDB,
+	txPool TxPoolForMining, txPoolDB kv.RoDB, aaPool *aapool.Pool,
 	blockReader services.FullBlockReader,
 ) MiningExecCfg {
 	return MiningExecCfg{
@@ -73,6 +77,7 @@ func StageMiningExecCfg(
 		payloadId:   payloadId,
 		txPool:      txPool,
 		txPoolDB:    txPoolDB,
+		aaPool:      aaPool,
 	}
 }
 
@@ -135,6 +140,10 @@ func SpawnMiningExecStage(s *StageState, txc wrap.TxContainer, cfg MiningExecCfg
 				return err
 			}
 
+			if err := addUserOperationsBundle(logPrefix, cfg, current, getHeader, ibs, ctx, logger); err != nil {
+				return err
+			}
+
 			for {
 				txs, y, err := getNextTransactions(cfg, chainID, current.Header, 50, executionAt, yielded, simStateReader, simStateWriter, logger)
 				if err != nil {
@@ -229,6 +238,127 @@ func SpawnMiningExecStage(s *StageState, txc wrap.TxContainer, cfg MiningExecCfg
 	return nil
 }
 
+// bundleOverheadGas - gas of handleOps transaction on top of gas limits of the bundled user operations
+const bundleOverheadGas = 100_000
+
+// addUserOperationsBundle puts user operations of the account abstraction lane at the top of the block,
+// bundled into one EntryPoint.handleOps transaction signed by the block producer key. Operations are
+// validated again on top of the block, they are marked as included only if the bundle succeeds.
+func addUserOperationsBundle(logPrefix string, cfg MiningExecCfg, current *MiningBlock, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
+	ibs *state.IntraBlockState, ctx context.Context, logger log.Logger) error {
+	sigKey := cfg.miningState.MiningConfig.SigKey
+	header := current.Header
+	if cfg.aaPool == nil || sigKey == nil || header.BaseFee == nil {
+		return nil
+	}
+	blockNum := header.Number.Uint64()
+	cfg.aaPool.Prune(blockNum-1, header.Time)
+
+	availableGas := header.GasLimit - header.GasUsed
+	if availableGas <= bundleOverheadGas {
+		return nil
+	}
+	ops, hashes := cfg.aaPool.Best(blockNum, header.BaseFee, availableGas-bundleOverheadGas)
+	if len(ops) == 0 {
+		return nil
+	}
+
+	beneficiary := cfg.miningState.MiningConfig.Etherbase
+	entryPoint := cfg.aaPool.EntryPoint()
+	bundler := crypto.PubkeyToAddress(sigKey.PublicKey)
+
+	var invalid []libcommon.Hash
+	ops, hashes, invalid = simulateUserOperations(cfg, header, getHeader, ibs, beneficiary, bundler, entryPoint, ops, hashes)
+	if len(invalid) > 0 {
+		cfg.aaPool.Remove(invalid)
+		logger.Debug(fmt.Sprintf("[%s] dropped invalid user operations", logPrefix), "ops", len(invalid))
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	data, err := aapool.PackHandleOps(ops, beneficiary)
+	if err != nil {
+		return err
+	}
+	gas := uint64(bundleOverheadGas)
+	for _, op := range ops {
+		gas += op.Gas()
+	}
+	var bundle types.Transaction = &types.DynamicFeeTransaction{
+		CommonTx: types.CommonTx{
+			Nonce: ibs.GetNonce(bundler),
+			Gas:   gas,
+			To:    &entryPoint,
+			Value: new(uint256.Int),
+			Data:  data,
+		},
+		ChainID: uint256.MustFromBig(cfg.chainConfig.ChainID),
+		Tip:     new(uint256.Int),
+		FeeCap:  uint256.MustFromBig(header.BaseFee),
+	}
+	bundle, err = types.SignTx(bundle, *types.LatestSigner(&cfg.chainConfig), sigKey)
+	if err != nil {
+		return err
+	}
+	bundle.SetSender(bundler)
+
+	txCount := len(current.Txs)
+	logs, _, err := addTransactionsToMiningBlock(logPrefix, current, cfg.chainConfig, cfg.vmConfig, getHeader, cfg.engine, types.NewTransactionsFixedOrder(types.Transactions{bundle}), beneficiary, ibs, ctx, cfg.interrupt, cfg.payloadId, logger)
+	if err != nil {
+		return err
+	}
+	if len(current.Txs) == txCount {
+		logger.Warn(fmt.Sprintf("[%s] user operations bundle not included", logPrefix), "ops", len(ops), "bundler", bundler)
+		return nil
+	}
+	NotifyPendingLogs(logPrefix, cfg.notifier, logs, logger)
+	if receipt := current.Receipts[len(current.Receipts)-1]; receipt.Status != types.ReceiptStatusSuccessful {
+		// ops stay in the pool, those invalidated by the bundle are dropped by validation of the next one
+		logger.Warn(fmt.Sprintf("[%s] user operations bundle reverted", logPrefix), "ops", len(ops), "tx", bundle.Hash())
+		return nil
+	}
+	cfg.aaPool.MarkIncluded(hashes, blockNum)
+	logger.Debug(fmt.Sprintf("[%s] user operations bundle included", logPrefix), "ops", len(ops), "tx", bundle.Hash())
+	return nil
+}
+
+// simulateUserOperations runs EntryPoint.simulateValidation of every op on top of the block being built.
+// Ops failing it, or expiring by the block time, are returned as invalid, ops not valid yet are skipped.
+func simulateUserOperations(cfg MiningExecCfg, header *types.Header, getHeader func(hash libcommon.Hash, number uint64) *types.Header,
+	ibs *state.IntraBlockState, beneficiary, bundler, entryPoint libcommon.Address,
+	ops []*aapool.UserOperation, hashes []libcommon.Hash) (validOps []*aapool.UserOperation, validHashes, invalid []libcommon.Hash) {
+	blockContext := core.NewEVMBlockContext(header, core.GetHashFn(header, getHeader), cfg.engine, &beneficiary)
+	txContext := evmtypes.TxContext{Origin: bundler, GasPrice: uint256.MustFromBig(header.BaseFee)}
+	evm := vm.NewEVM(blockContext, txContext, ibs, &cfg.chainConfig, vm.Config{})
+
+	for i, op := range ops {
+		data, err := aapool.PackSimulateValidation(op)
+		if err != nil {
+			invalid = append(invalid, hashes[i])
+			continue
+		}
+		snap := ibs.Snapshot()
+		ret, _, err := evm.Call(vm.AccountRef(bundler), entryPoint, data, header.GasLimit, new(uint256.Int), false /* bailout */)
+		ibs.RevertToSnapshot(snap)
+		if !errors.Is(err, vm.ErrExecutionReverted) {
+			invalid = append(invalid, hashes[i])
+			continue
+		}
+		validation, err := aapool.UnpackValidationRevert(ret)
+		if err != nil || validation.SigFailed || (validation.ValidUntil != 0 && validation.ValidUntil <= header.Time) {
+			invalid = append(invalid, hashes[i])
+			continue
+		}
+		if validation.ValidAfter > header.Time {
+			continue
+		}
+		validOps = append(validOps, op)
+		validHashes = append(validHashes, hashes[i])
+	}
+	return validOps, validHashes, invalid
+}
+
 func getNextTransactions(
 	cfg MiningExecCfg,
 	chainID *uint256.Int,
@@ -262,7 +262,7 @@ func DownloadAndIndexSnapshotsIfNeed(s *StageState, ctx context.Context, tx kv.R
 		}
 	}
 
-	indexWorkers := estimate.IndexSnapshot.Workers()
+	indexWorkers := cfg.blockReader.FreezingCfg().IndexBuildWorkers()
 	if err := cfg.agg.BuildOptionalMissedIndices(ctx, indexWorkers); err != nil {
 		return err
 	}
@@ -373,6 +373,19 @@ func (h *Harness) ReadFirstStateSyncEventNumPerBlockFromDB(ctx context.Context)
 	return nums, nil
 }
 
+// MockRootTxHash - hash of the root chain transaction which emitted the mocked state sync event
+func MockRootTxHash(eventID uint64) libcommon.Hash {
+	return libcommon.BigToHash(new(big.Int).SetUint64(eventID))
+}
+
+func (h *Harness) ReadStateSyncEventsByRootTxFromDB(ctx context.Context, rootTxHash libcommon.Hash) (entries []rawdb.BorEventRootTxEntry, err error) {
+	err = h.chainDataDB.View(ctx, func(tx kv.Tx) error {
+		entries, err = rawdb.ReadBorEventsByRootTx(tx, rootTxHash)
+		return err
+	})
+	return entries, err
+}
+
 func (h *Harness) ReadHeaderByNumber(ctx context.Context, number uint64) (header *types.Header, err error) {
 	err = h.chainDataDB.View(ctx, func(tx kv.Tx) error {
 		header = rawdb.ReadHeaderByNumber(tx, number)
@@ -643,8 +656,10 @@ func (h *Harness) mockHeimdallClient() {
 			stateSyncDelay := h.borConfig.CalculateStateSyncDelay(h.heimdallLastEventHeaderNum)
 			newEvent := heimdall.EventRecordWithTime{
 				EventRecord: heimdall.EventRecord{
-					ID:      h.heimdallLastEventID,
-					ChainID: h.chainConfig.ChainID.String(),
+					ID:       h.heimdallLastEventID,
+					TxHash:   MockRootTxHash(h.heimdallLastEventID),
+					LogIndex: h.heimdallLastEventID,
+					ChainID:  h.chainConfig.ChainID.String(),
 				},
 				Time: time.Unix(int64(h.sealedHeaders[h.heimdallLastEventHeaderNum].Time-stateSyncDelay-1), 0),
 			}
@@ -532,11 +532,13 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, txc wrap.TxContainer, firstCyc
 		return err
 	}
 
+	diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(stage.ID), From: stageState.BlockNumber, Current: stageState.BlockNumber})
 	if err = stage.Forward(firstCycle, badBlockUnwind, stageState, s, txc, s.logger); err != nil {
 		wrappedError := fmt.Errorf("[%s] %w", s.LogPrefix(), err)
 		s.logger.Debug("Error while executing stage", "err", wrappedError)
 		return wrappedError
 	}
+	s.sendStageFinished(stage.ID, stageState.BlockNumber, db, txc.Tx)
 
 	took := time.Since(start)
 	logPrefix := s.LogPrefix()
@@ -549,6 +551,27 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, txc wrap.TxContainer, firstCyc
 	return nil
 }
 
+// sendStageFinished reports to diagnostics the progress a stage reached in its run
+func (s *Sync) sendStageFinished(id stages.SyncStage, from uint64, db kv.RoDB, tx kv.Tx) {
+	if !diagnostics.TypeOf(diagnostics.StageProgressUpdate{}).Enabled() || (tx == nil && db == nil) {
+		return
+	}
+	var progress uint64
+	var err error
+	if tx != nil {
+		progress, err = stages.GetStageProgress(tx, id)
+	} else {
+		err = db.View(context.Background(), func(tx kv.Tx) error {
+			progress, err = stages.GetStageProgress(tx, id)
+			return err
+		})
+	}
+	if err != nil {
+		return
+	}
+	diagnostics.Send(diagnostics.StageProgressUpdate{Stage: string(id), From: from, Current: progress, Finished: true})
+}
+
 func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, txc wrap.TxContainer) error {
 	start := time.Now()
 	s.logger.Trace("Unwind...", "stage", stage.ID)
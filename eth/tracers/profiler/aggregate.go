@@ -0,0 +1,100 @@
+package profiler
+
+import (
+	"encoding/json"
+	"sort"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+func (p *BlockProfile) Encode() ([]byte, error) { return json.Marshal(p) }
+
+func DecodeBlockProfile(enc []byte) (*BlockProfile, error) {
+	p := &BlockProfile{}
+	if err := json.Unmarshal(enc, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// RangeProfile - block profiles of a range merged together, contracts and transactions are the top ones
+type RangeProfile struct {
+	FromBlock uint64                  `json:"fromBlock"`
+	ToBlock   uint64                  `json:"toBlock"`
+	Blocks    uint64                  `json:"blocks"`
+	GasUsed   uint64                  `json:"gasUsed"`
+	TimeNs    uint64                  `json:"timeNs"`
+	Opcodes   map[string]*OpcodeStats `json:"opcodes"`
+	Contracts []*ContractStats        `json:"contracts"` // by gas, descending
+	Txs       []TxProfile             `json:"txs"`       // by time, descending
+}
+
+// Aggregator merges block profiles, keeping `limit` hottest contracts and slowest transactions
+type Aggregator struct {
+	limit     int
+	profile   RangeProfile
+	contracts map[libcommon.Address]*ContractStats
+}
+
+func NewAggregator(fromBlock, toBlock uint64, limit int) *Aggregator {
+	return &Aggregator{
+		limit:     limit,
+		profile:   RangeProfile{FromBlock: fromBlock, ToBlock: toBlock, Opcodes: map[string]*OpcodeStats{}},
+		contracts: map[libcommon.Address]*ContractStats{},
+	}
+}
+
+func (a *Aggregator) Add(p *BlockProfile) {
+	r := &a.profile
+	r.Blocks++
+	r.GasUsed += p.GasUsed
+	r.TimeNs += p.TimeNs
+	for name, stats := range p.Opcodes {
+		if s, ok := r.Opcodes[name]; ok {
+			s.Merge(stats)
+		} else {
+			cpy := *stats
+			r.Opcodes[name] = &cpy
+		}
+	}
+	for address, stats := range p.Contracts {
+		if s, ok := a.contracts[address]; ok {
+			s.Merge(stats)
+		} else {
+			cpy := *stats
+			a.contracts[address] = &cpy
+		}
+	}
+	// keep only top of transactions, so long ranges don't hold all of them
+	r.Txs = append(r.Txs, p.Txs...)
+	if len(r.Txs) > 2*a.limit {
+		a.sortTxs()
+	}
+}
+
+func (a *Aggregator) sortTxs() {
+	txs := a.profile.Txs
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].TimeNs > txs[j].TimeNs })
+	if len(txs) > a.limit {
+		a.profile.Txs = txs[:a.limit]
+	}
+}
+
+func (a *Aggregator) Result() *RangeProfile {
+	a.sortTxs()
+	contracts := make([]*ContractStats, 0, len(a.contracts))
+	for _, stats := range a.contracts {
+		contracts = append(contracts, stats)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		if contracts[i].Gas != contracts[j].Gas {
+			return contracts[i].Gas > contracts[j].Gas
+		}
+		return contracts[i].TimeNs > contracts[j].TimeNs
+	})
+	if len(contracts) > a.limit {
+		contracts = contracts[:a.limit]
+	}
+	a.profile.Contracts = contracts
+	return &a.profile
+}
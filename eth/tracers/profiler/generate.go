@@ -0,0 +1,49 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// Generate re-executes the block on top of the historical state of its parent with profiling tracers.
+// Time of the whole block includes the overhead of tracing, only relative times are meaningful.
+func Generate(
+	ctx context.Context, tx kv.Tx, block *types.Block,
+	chainConfig *chain.Config, engine consensus.Engine, chainReader consensus.ChainReader, logger log.Logger,
+) (*BlockProfile, error) {
+	blockNum := block.NumberU64()
+	minTxNum, err := rawdbv3.TxNums.Min(tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	reader := state.NewHistoryReaderV3()
+	reader.SetTx(tx)
+	reader.SetTxNum(minTxNum)
+
+	getHeader := func(hash libcommon.Hash, number uint64) *types.Header {
+		return chainReader.GetHeader(hash, number)
+	}
+
+	profile := NewBlockProfile(blockNum, block.Hash())
+	vmConfig := vm.Config{Debug: true}
+	start := time.Now()
+	if _, err := core.ExecuteBlockEphemerally(chainConfig, &vmConfig, core.GetHashFn(block.Header(), getHeader), engine, block, reader, state.NewNoopWriter(), chainReader, profile.TxTracer, logger); err != nil {
+		return nil, fmt.Errorf("re-execution of block %d: %w", blockNum, err)
+	}
+	profile.TimeNs = uint64(time.Since(start))
+	profile.GasUsed = block.GasUsed()
+	return profile, ctx.Err()
+}
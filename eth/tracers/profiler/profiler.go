@@ -0,0 +1,214 @@
+// Package profiler collects per-opcode gas and time histograms, per-transaction costs and the hottest
+// contracts of re-executed blocks.
+package profiler
+
+import (
+	"math/bits"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+)
+
+// HistogramBuckets - bucket 0 counts zeros, bucket i counts values in [2^(i-1), 2^i), the last one everything above
+const HistogramBuckets = 40
+
+type Histogram [HistogramBuckets]uint64
+
+func (h *Histogram) Add(v uint64) {
+	i := bits.Len64(v)
+	if i >= HistogramBuckets {
+		i = HistogramBuckets - 1
+	}
+	h[i]++
+}
+
+func (h *Histogram) Merge(other *Histogram) {
+	for i := range h {
+		h[i] += other[i]
+	}
+}
+
+// OpcodeStats - gas is the cost charged by the opcode, for calls and creates it includes the gas passed to the callee.
+// Time of calls and creates doesn't include the execution of the callee.
+type OpcodeStats struct {
+	Count    uint64    `json:"count"`
+	Gas      uint64    `json:"gas"`
+	TimeNs   uint64    `json:"timeNs"`
+	GasHist  Histogram `json:"gasHistogram"`
+	TimeHist Histogram `json:"timeNsHistogram"`
+}
+
+func (s *OpcodeStats) Merge(other *OpcodeStats) {
+	s.Count += other.Count
+	s.Gas += other.Gas
+	s.TimeNs += other.TimeNs
+	s.GasHist.Merge(&other.GasHist)
+	s.TimeHist.Merge(&other.TimeHist)
+}
+
+// ContractStats - opcodes executed by the code of the contract (delegate calls are attributed to the code address)
+type ContractStats struct {
+	Address libcommon.Address `json:"address"`
+	Ops     uint64            `json:"ops"`
+	Gas     uint64            `json:"gas"`
+	TimeNs  uint64            `json:"timeNs"`
+}
+
+func (s *ContractStats) Merge(other *ContractStats) {
+	s.Ops += other.Ops
+	s.Gas += other.Gas
+	s.TimeNs += other.TimeNs
+}
+
+type TxProfile struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	Index       int            `json:"index"`
+	Hash        libcommon.Hash `json:"hash"`
+	GasUsed     uint64         `json:"gasUsed"`
+	TimeNs      uint64         `json:"timeNs"`
+	Ops         uint64         `json:"ops"`
+}
+
+type BlockProfile struct {
+	Number    uint64                               `json:"number"`
+	Hash      libcommon.Hash                       `json:"hash"`
+	GasUsed   uint64                               `json:"gasUsed"`
+	TimeNs    uint64                               `json:"timeNs"`
+	Txs       []TxProfile                          `json:"txs"`
+	Opcodes   map[string]*OpcodeStats              `json:"opcodes"`
+	Contracts map[libcommon.Address]*ContractStats `json:"contracts"`
+}
+
+func NewBlockProfile(number uint64, hash libcommon.Hash) *BlockProfile {
+	return &BlockProfile{
+		Number:    number,
+		Hash:      hash,
+		Opcodes:   map[string]*OpcodeStats{},
+		Contracts: map[libcommon.Address]*ContractStats{},
+	}
+}
+
+// TxTracer returns the tracer of the txIndex-th transaction of the block, it matches `getTracer` of
+// core.ExecuteBlockEphemerally. The transaction is added to the profile by Flush.
+func (p *BlockProfile) TxTracer(txIndex int, txHash libcommon.Hash) (vm.EVMLogger, error) {
+	return &txTracer{
+		block:     p,
+		tx:        TxProfile{BlockNumber: p.Number, Index: txIndex, Hash: txHash},
+		opcodes:   map[vm.OpCode]*OpcodeStats{},
+		contracts: map[libcommon.Address]*ContractStats{},
+	}, nil
+}
+
+// txTracer - time of an opcode is measured from its CaptureState till the next event of the same frame
+type txTracer struct {
+	block     *BlockProfile
+	tx        TxProfile
+	start     time.Time
+	gasLimit  uint64
+	opcodes   map[vm.OpCode]*OpcodeStats
+	contracts map[libcommon.Address]*ContractStats
+
+	pending   bool // op is being executed
+	op        vm.OpCode
+	opAddress libcommon.Address
+	opStart   time.Time
+}
+
+var _ vm.FlushableTracer = &txTracer{}
+
+func (t *txTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+	t.start = time.Now()
+}
+
+func (t *txTracer) CaptureTxEnd(restGas uint64) {
+	t.endOp(time.Now())
+	t.tx.TimeNs = uint64(time.Since(t.start))
+	t.tx.GasUsed = t.gasLimit - restGas
+}
+
+func (t *txTracer) CaptureStart(env *vm.EVM, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+}
+
+func (t *txTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	t.endOp(time.Now())
+}
+
+func (t *txTracer) CaptureEnter(typ vm.OpCode, from libcommon.Address, to libcommon.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.endOp(time.Now())
+}
+
+func (t *txTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	t.endOp(time.Now())
+}
+
+func (t *txTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	now := time.Now()
+	t.endOp(now)
+
+	address := scope.Contract.Address()
+	if scope.Contract.CodeAddr != nil {
+		address = *scope.Contract.CodeAddr
+	}
+
+	stats, ok := t.opcodes[op]
+	if !ok {
+		stats = &OpcodeStats{}
+		t.opcodes[op] = stats
+	}
+	stats.Count++
+	stats.Gas += cost
+	stats.GasHist.Add(cost)
+
+	contract, ok := t.contracts[address]
+	if !ok {
+		contract = &ContractStats{Address: address}
+		t.contracts[address] = contract
+	}
+	contract.Ops++
+	contract.Gas += cost
+
+	t.tx.Ops++
+	t.pending, t.op, t.opAddress, t.opStart = true, op, address, now
+}
+
+func (t *txTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *txTracer) endOp(now time.Time) {
+	if !t.pending {
+		return
+	}
+	t.pending = false
+	elapsed := uint64(now.Sub(t.opStart))
+	stats := t.opcodes[t.op]
+	stats.TimeNs += elapsed
+	stats.TimeHist.Add(elapsed)
+	t.contracts[t.opAddress].TimeNs += elapsed
+}
+
+// Flush adds the transaction to the block profile
+func (t *txTracer) Flush(tx types.Transaction) {
+	p := t.block
+	p.Txs = append(p.Txs, t.tx)
+	for op, stats := range t.opcodes {
+		name := op.String()
+		if s, ok := p.Opcodes[name]; ok {
+			s.Merge(stats)
+		} else {
+			p.Opcodes[name] = stats
+		}
+	}
+	for address, stats := range t.contracts {
+		if s, ok := p.Contracts[address]; ok {
+			s.Merge(stats)
+		} else {
+			p.Contracts[address] = stats
+		}
+	}
+}
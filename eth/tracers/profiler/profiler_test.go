@@ -0,0 +1,58 @@
+package profiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+func TestHistogram(t *testing.T) {
+	var h Histogram
+	for _, v := range []uint64{0, 1, 2, 3, 4, 1 << 50} {
+		h.Add(v)
+	}
+	require.Equal(t, uint64(1), h[0])
+	require.Equal(t, uint64(1), h[1])
+	require.Equal(t, uint64(2), h[2])
+	require.Equal(t, uint64(1), h[3])
+	require.Equal(t, uint64(1), h[HistogramBuckets-1])
+}
+
+func TestAggregator(t *testing.T) {
+	a, b := libcommon.HexToAddress("0x0a"), libcommon.HexToAddress("0x0b")
+
+	p1 := NewBlockProfile(1, libcommon.Hash{1})
+	p1.GasUsed = 100
+	p1.Opcodes["ADD"] = &OpcodeStats{Count: 2, Gas: 6}
+	p1.Contracts[a] = &ContractStats{Address: a, Gas: 10}
+	p1.Txs = []TxProfile{{BlockNumber: 1, TimeNs: 5}, {BlockNumber: 1, Index: 1, TimeNs: 1}}
+
+	p2 := NewBlockProfile(2, libcommon.Hash{2})
+	p2.GasUsed = 200
+	p2.Opcodes["ADD"] = &OpcodeStats{Count: 1, Gas: 3}
+	p2.Contracts[a] = &ContractStats{Address: a, Gas: 10}
+	p2.Contracts[b] = &ContractStats{Address: b, Gas: 15}
+	p2.Txs = []TxProfile{{BlockNumber: 2, TimeNs: 3}}
+
+	enc, err := p2.Encode()
+	require.NoError(t, err)
+	decoded, err := DecodeBlockProfile(enc)
+	require.NoError(t, err)
+	require.Equal(t, p2, decoded)
+
+	aggregator := NewAggregator(1, 2, 2)
+	aggregator.Add(p1)
+	aggregator.Add(decoded)
+	r := aggregator.Result()
+
+	require.Equal(t, uint64(2), r.Blocks)
+	require.Equal(t, uint64(300), r.GasUsed)
+	require.Equal(t, &OpcodeStats{Count: 3, Gas: 9}, r.Opcodes["ADD"])
+	require.Equal(t, []*ContractStats{{Address: a, Gas: 20}, {Address: b, Gas: 15}}, r.Contracts)
+	require.Equal(t, []TxProfile{{BlockNumber: 1, TimeNs: 5}, {BlockNumber: 2, TimeNs: 3}}, r.Txs)
+
+	// block profiles are not modified by aggregation
+	require.Equal(t, uint64(2), p1.Opcodes["ADD"].Count)
+}
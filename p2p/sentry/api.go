@@ -0,0 +1,62 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// API the data structure for the sentry_* RPC commands, managing peer reputation of in-process sentries
+type API struct {
+	servers []*GrpcServer
+}
+
+// NewAPI returns API instance serving the sentries of all protocols
+func NewAPI(servers []*GrpcServer) *API {
+	return &API{servers: servers}
+}
+
+// PeerScores returns the scores and bans of misbehaving peers, banned peers first
+func (api *API) PeerScores(_ context.Context) ([]PeerScore, error) {
+	seen := map[string]struct{}{}
+	res := []PeerScore{}
+	for _, ss := range api.servers {
+		for _, score := range ss.PeerScores() {
+			if _, ok := seen[score.ID]; ok {
+				continue
+			}
+			seen[score.ID] = struct{}{}
+			res = append(res, score)
+		}
+	}
+	return res, nil
+}
+
+// BanPeer bans the peer, given as an enode URL or hex public key, for the number of seconds (zero - until unbanned)
+// and disconnects it
+func (api *API) BanPeer(_ context.Context, id string, seconds uint64) (bool, error) {
+	if len(api.servers) == 0 {
+		return false, errors.New("no sentries running in-process")
+	}
+	peerID, err := ParsePeerID(id)
+	if err != nil {
+		return false, err
+	}
+	for _, ss := range api.servers {
+		ss.BanPeer(peerID, time.Duration(seconds)*time.Second, "banned by operator")
+	}
+	return true, nil
+}
+
+// UnbanPeer lifts the ban of the peer and resets its score. Returns false if the peer isn't known.
+func (api *API) UnbanPeer(_ context.Context, id string) (bool, error) {
+	peerID, err := ParsePeerID(id)
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	for _, ss := range api.servers {
+		found = ss.UnbanPeer(peerID) || found
+	}
+	return found, nil
+}
@@ -3,6 +3,7 @@ package sentry
 import (
 	"fmt"
 
+	"github.com/ledgerwatch/erigon-lib/direct"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentryproto"
 	"github.com/ledgerwatch/erigon/core/forkid"
@@ -10,46 +11,71 @@ import (
 	"github.com/ledgerwatch/erigon/p2p"
 )
 
+// readAndValidatePeerStatusMessage - the block range is advertised by eth/69 peers only, it's nil for older versions
 func readAndValidatePeerStatusMessage(
 	rw p2p.MsgReadWriter,
 	status *proto_sentry.StatusData,
 	version uint,
 	minVersion uint,
-) (*eth.StatusPacket, *p2p.PeerError) {
+) (*eth.StatusPacket, *eth.BlockRangeUpdatePacket, *p2p.PeerError) {
 	msg, err := rw.ReadMsg()
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatusMessage rw.ReadMsg error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatusMessage rw.ReadMsg error")
 	}
 
-	reply, err := tryDecodeStatusMessage(&msg)
+	reply, blockRange, err := tryDecodeStatusMessage(&msg, version)
 	msg.Discard()
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatusMessage tryDecodeStatusMessage error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatusMessage tryDecodeStatusMessage error")
 	}
 
 	err = checkPeerStatusCompatibility(reply, status, version, minVersion)
 	if err != nil {
-		return nil, p2p.NewPeerError(p2p.PeerErrorStatusIncompatible, p2p.DiscUselessPeer, err, "readAndValidatePeerStatusMessage checkPeerStatusCompatibility error")
+		return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusIncompatible, p2p.DiscUselessPeer, err, "readAndValidatePeerStatusMessage checkPeerStatusCompatibility error")
 	}
 
-	return reply, nil
+	return reply, blockRange, nil
 }
 
-func tryDecodeStatusMessage(msg *p2p.Msg) (*eth.StatusPacket, error) {
+// tryDecodeStatusMessage - eth/69 status is converted to the StatusPacket (without TD) and the advertised block range
+func tryDecodeStatusMessage(msg *p2p.Msg, version uint) (*eth.StatusPacket, *eth.BlockRangeUpdatePacket, error) {
 	if msg.Code != eth.StatusMsg {
-		return nil, fmt.Errorf("first msg has code %x (!= %x)", msg.Code, eth.StatusMsg)
+		return nil, nil, fmt.Errorf("first msg has code %x (!= %x)", msg.Code, eth.StatusMsg)
 	}
 
 	if msg.Size > eth.ProtocolMaxMsgSize {
-		return nil, fmt.Errorf("message is too large %d, limit %d", msg.Size, eth.ProtocolMaxMsgSize)
+		return nil, nil, fmt.Errorf("message is too large %d, limit %d", msg.Size, eth.ProtocolMaxMsgSize)
 	}
 
-	var reply eth.StatusPacket
+	if version < direct.ETH69 {
+		var reply eth.StatusPacket
+		if err := msg.Decode(&reply); err != nil {
+			return nil, nil, fmt.Errorf("decode message %v: %w", msg, err)
+		}
+		return &reply, nil, nil
+	}
+
+	var reply eth.StatusPacket69
 	if err := msg.Decode(&reply); err != nil {
-		return nil, fmt.Errorf("decode message %v: %w", msg, err)
+		return nil, nil, fmt.Errorf("decode message %v: %w", msg, err)
+	}
+	if reply.EarliestBlock > reply.LatestBlock {
+		return nil, nil, fmt.Errorf("invalid block range: earliest %d > latest %d", reply.EarliestBlock, reply.LatestBlock)
 	}
 
-	return &reply, nil
+	status := &eth.StatusPacket{
+		ProtocolVersion: reply.ProtocolVersion,
+		NetworkID:       reply.NetworkID,
+		Head:            reply.LatestBlockHash,
+		Genesis:         reply.Genesis,
+		ForkID:          reply.ForkID,
+	}
+	blockRange := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   reply.EarliestBlock,
+		LatestBlock:     reply.LatestBlock,
+		LatestBlockHash: reply.LatestBlockHash,
+	}
+	return status, blockRange, nil
 }
 
 func checkPeerStatusCompatibility(
@@ -0,0 +1,336 @@
+package sentry
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+)
+
+const (
+	reputationFileName = "reputation.json"
+
+	// score weights of peer events, a peer reaching reputationBanScore is banned for reputationBanDuration
+	uselessPeerScore       = 1
+	timeoutScore           = 2
+	protocolViolationScore = 10
+	reputationBanScore     = 100
+	reputationBanDuration  = time.Hour
+
+	reputationSaveInterval = 5 * time.Minute
+
+	// maxReputationEntries - peers without bans and with the oldest events are forgotten above this limit
+	maxReputationEntries = 10_000
+)
+
+type peerEvent int
+
+const (
+	peerEventUseless peerEvent = iota
+	peerEventTimeout
+	peerEventViolation
+)
+
+// PeerScore - misbehaviour of a peer recorded by the sentry, it is kept across restarts
+type PeerScore struct {
+	ID          string    `json:"id"`
+	Useless     uint64    `json:"useless"`
+	Timeouts    uint64    `json:"timeouts"`
+	Violations  uint64    `json:"violations"`
+	Score       uint64    `json:"score"`
+	LastEvent   time.Time `json:"lastEvent"`
+	Banned      bool      `json:"banned"`
+	BannedUntil time.Time `json:"bannedUntil,omitempty"` // zero - until unbanned
+	BanReason   string    `json:"banReason,omitempty"`
+}
+
+func (s *PeerScore) isBanned(now time.Time) bool {
+	return s.Banned && (s.BannedUntil.IsZero() || now.Before(s.BannedUntil))
+}
+
+// peerReputation - scores and bans of peers, persisted in the node database directory
+type peerReputation struct {
+	lock  sync.Mutex
+	peers map[[64]byte]*PeerScore
+	path  string // empty - not persisted
+	dirty bool
+}
+
+func newPeerReputation(nodeDatabase string, logger log.Logger) *peerReputation {
+	r := &peerReputation{peers: map[[64]byte]*PeerScore{}}
+	if nodeDatabase == "" {
+		return r
+	}
+	r.path = filepath.Join(nodeDatabase, reputationFileName)
+	if err := r.load(); err != nil {
+		logger.Warn("[p2p] failed to load peer reputation", "file", r.path, "err", err)
+	}
+	return r
+}
+
+func (r *peerReputation) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var scores []*PeerScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return err
+	}
+	for _, score := range scores {
+		peerID, err := ParsePeerID(score.ID)
+		if err != nil {
+			return err
+		}
+		r.peers[peerID] = score
+	}
+	return nil
+}
+
+// save writes the reputation file if anything changed since the last save
+func (r *peerReputation) save() error {
+	r.lock.Lock()
+	if r.path == "" || !r.dirty {
+		r.lock.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(r.scoresLocked())
+	r.dirty = false
+	r.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// record adds the event to the score of the peer. Returns true if the peer got banned.
+func (r *peerReputation) record(peerID [64]byte, event peerEvent, canBan bool) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	score := r.getOrCreateLocked(peerID)
+	switch event {
+	case peerEventUseless:
+		score.Useless++
+		score.Score += uselessPeerScore
+	case peerEventTimeout:
+		score.Timeouts++
+		score.Score += timeoutScore
+	case peerEventViolation:
+		score.Violations++
+		score.Score += protocolViolationScore
+	}
+	score.LastEvent = now
+	r.dirty = true
+
+	if !canBan || score.isBanned(now) || score.Score < reputationBanScore {
+		return false
+	}
+	score.Banned = true
+	score.BannedUntil = now.Add(reputationBanDuration)
+	score.BanReason = fmt.Sprintf("score %d", score.Score)
+	score.Score = 0 // counters are kept for operators, score starts over after the ban
+	return true
+}
+
+// recordError records the event corresponding to the error of the peer connection, if any
+func (r *peerReputation) recordError(peerID [64]byte, err *p2p.PeerError, canBan bool) bool {
+	if err == nil {
+		return false
+	}
+	switch err.Code {
+	case p2p.PeerErrorStatusIncompatible, p2p.PeerErrorStatusDecode:
+		return r.record(peerID, peerEventUseless, canBan)
+	case p2p.PeerErrorStatusHandshakeTimeout:
+		return r.record(peerID, peerEventTimeout, canBan)
+	case p2p.PeerErrorInvalidMessageCode, p2p.PeerErrorInvalidMessage, p2p.PeerErrorMessageSizeLimit,
+		p2p.PeerErrorMessageObsolete, p2p.PeerErrorStatusUnexpected:
+		return r.record(peerID, peerEventViolation, canBan)
+	default:
+		return false
+	}
+}
+
+func (r *peerReputation) isBanned(peerID [64]byte) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	score, ok := r.peers[peerID]
+	return ok && score.isBanned(time.Now())
+}
+
+// ban bans the peer for the duration, zero duration - until unbanned
+func (r *peerReputation) ban(peerID [64]byte, duration time.Duration, reason string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	score := r.getOrCreateLocked(peerID)
+	score.Banned = true
+	score.BannedUntil = time.Time{}
+	if duration > 0 {
+		score.BannedUntil = time.Now().Add(duration)
+	}
+	score.BanReason = reason
+	r.dirty = true
+}
+
+// unban lifts the ban and resets the score of the peer. Returns false if the peer is unknown.
+func (r *peerReputation) unban(peerID [64]byte) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.peers[peerID]; !ok {
+		return false
+	}
+	delete(r.peers, peerID)
+	r.dirty = true
+	return true
+}
+
+// scores returns copies of the scores ordered by score, banned peers first
+func (r *peerReputation) scores() []PeerScore {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	scores := r.scoresLocked()
+	res := make([]PeerScore, len(scores))
+	for i, score := range scores {
+		res[i] = *score
+	}
+	return res
+}
+
+func (r *peerReputation) scoresLocked() []*PeerScore {
+	now := time.Now()
+	scores := make([]*PeerScore, 0, len(r.peers))
+	for _, score := range r.peers {
+		scores = append(scores, score)
+	}
+	slices.SortFunc(scores, func(a, b *PeerScore) int {
+		if banned := a.isBanned(now); banned != b.isBanned(now) {
+			if banned {
+				return -1
+			}
+			return 1
+		}
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return scores
+}
+
+func (r *peerReputation) getOrCreateLocked(peerID [64]byte) *PeerScore {
+	if score, ok := r.peers[peerID]; ok {
+		return score
+	}
+	if len(r.peers) >= maxReputationEntries {
+		r.evictLocked()
+	}
+	score := &PeerScore{ID: hex.EncodeToString(peerID[:])}
+	r.peers[peerID] = score
+	return score
+}
+
+// evictLocked forgets the tenth of peers which aren't banned and have the oldest events
+func (r *peerReputation) evictLocked() {
+	now := time.Now()
+	ids := make([][64]byte, 0, len(r.peers))
+	for id, score := range r.peers {
+		if !score.isBanned(now) {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b [64]byte) int {
+		return r.peers[a].LastEvent.Compare(r.peers[b].LastEvent)
+	})
+	for _, id := range ids[:min(len(ids), maxReputationEntries/10+1)] {
+		delete(r.peers, id)
+	}
+}
+
+// ParsePeerID parses the public key of a peer given either as an enode URL or as hex
+func ParsePeerID(s string) ([64]byte, error) {
+	var peerID [64]byte
+	if strings.HasPrefix(s, "enode://") {
+		node, err := enode.ParseV4(s)
+		if err != nil {
+			return peerID, err
+		}
+		copy(peerID[:], crypto.MarshalPubkey(node.Pubkey()))
+		return peerID, nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return peerID, fmt.Errorf("invalid peer id %q: %w", s, err)
+	}
+	if len(b) != len(peerID) {
+		return peerID, fmt.Errorf("invalid peer id %q: expected %d bytes, got %d", s, len(peerID), len(b))
+	}
+	copy(peerID[:], b)
+	return peerID, nil
+}
+
+// recordPeerError records the error which terminated the peer connection, banned peers are logged
+func (ss *GrpcServer) recordPeerError(peerID [64]byte, err *p2p.PeerError, canBan bool) {
+	if ss.reputation.recordError(peerID, err, canBan) {
+		ss.logger.Debug("[p2p] peer banned", "peerId", hex.EncodeToString(peerID[:])[:20], "duration", reputationBanDuration, "lastErr", err)
+	}
+}
+
+// saveReputationLoop periodically persists peer reputation, so it isn't lost on crashes
+func (ss *GrpcServer) saveReputationLoop(ctx context.Context) {
+	ticker := time.NewTicker(reputationSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ss.reputation.save(); err != nil {
+				ss.logger.Warn("[p2p] failed to save peer reputation", "err", err)
+			}
+		}
+	}
+}
+
+// PeerScores returns the recorded scores and bans of peers
+func (ss *GrpcServer) PeerScores() []PeerScore {
+	return ss.reputation.scores()
+}
+
+// BanPeer bans the peer for the duration (zero - until unbanned) and disconnects it
+func (ss *GrpcServer) BanPeer(peerID [64]byte, duration time.Duration, reason string) {
+	ss.reputation.ban(peerID, duration, reason)
+	ss.removePeer(peerID, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscRequested, nil, "banned peer"))
+}
+
+// UnbanPeer lifts the ban of the peer and forgets its score. Returns false if the peer is unknown.
+func (ss *GrpcServer) UnbanPeer(peerID [64]byte) bool {
+	return ss.reputation.unban(peerID)
+}
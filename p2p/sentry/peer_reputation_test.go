@@ -0,0 +1,66 @@
+package sentry
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/p2p"
+)
+
+func TestPeerReputation(t *testing.T) {
+	dir := t.TempDir()
+	r := newPeerReputation(dir, nil)
+
+	var peerID, staticPeerID [64]byte
+	peerID[0], staticPeerID[0] = 1, 2
+	violation := p2p.NewPeerError(p2p.PeerErrorMessageSizeLimit, p2p.DiscProtocolError, nil, "too big")
+
+	for i := 0; i < reputationBanScore/protocolViolationScore-1; i++ {
+		require.False(t, r.recordError(peerID, violation, true))
+		require.False(t, r.recordError(staticPeerID, violation, false))
+	}
+	require.False(t, r.recordError(peerID, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, nil, ""), true))
+	require.False(t, r.isBanned(peerID))
+
+	require.True(t, r.recordError(peerID, violation, true))
+	require.False(t, r.recordError(staticPeerID, violation, false))
+	require.True(t, r.isBanned(peerID))
+	require.False(t, r.isBanned(staticPeerID))
+
+	var manualID [64]byte
+	manualID[0] = 3
+	r.ban(manualID, 0, "manual")
+	require.NoError(t, r.save())
+
+	// reputation is restored after restart
+	r = newPeerReputation(dir, nil)
+	require.True(t, r.isBanned(peerID))
+	require.True(t, r.isBanned(manualID))
+	scores := r.scores()
+	require.Len(t, scores, 3)
+	require.Equal(t, uint64(reputationBanScore/protocolViolationScore), scores[2].Violations)
+	require.Equal(t, hex.EncodeToString(staticPeerID[:]), scores[2].ID)
+
+	require.True(t, r.unban(peerID))
+	require.False(t, r.unban(peerID))
+	require.False(t, r.isBanned(peerID))
+
+	r.ban(peerID, time.Nanosecond, "expired")
+	time.Sleep(time.Millisecond)
+	require.False(t, r.isBanned(peerID))
+}
+
+func TestParsePeerID(t *testing.T) {
+	const pubkey = "d860a01f9722d78051619d1e2351aba3f43f943f6f00718d1b9baa4101932a1f5011f16bb2b1bb35db20d6fe28fa0bf09636d26a87d31de9ec6203eeedb1f666"
+	fromHex, err := ParsePeerID(pubkey)
+	require.NoError(t, err)
+	fromEnode, err := ParsePeerID("enode://" + pubkey + "@127.0.0.1:30303")
+	require.NoError(t, err)
+	require.Equal(t, fromHex, fromEnode)
+
+	_, err = ParsePeerID("0x1234")
+	require.Error(t, err)
+}
@@ -50,6 +50,8 @@ const (
 	// complete before dropping the connection.= as malicious.
 	handshakeTimeout  = 5 * time.Second
 	maxPermitsPerPeer = 4 // How many outstanding requests per peer we may have
+	// blockRangeUpdateInterval - eth/69 peers are notified about our block range once per this many blocks
+	blockRangeUpdateInterval = 32
 )
 
 // PeerInfo collects various extra bits of information about the peer,
@@ -60,6 +62,7 @@ type PeerInfo struct {
 	deadlines     []time.Time // Request deadlines
 	latestDealine time.Time
 	height        uint64
+	earliest      uint64 // the lowest block the peer can serve, advertised by eth/69 peers only
 	rw            p2p.MsgReadWriter
 	protocol      uint
 
@@ -180,6 +183,21 @@ func (pi *PeerInfo) SetIncreasedHeight(newHeight uint64) {
 	}
 }
 
+func (pi *PeerInfo) EarliestBlock() uint64 {
+	return atomic.LoadUint64(&pi.earliest)
+}
+
+// SetBlockRange updates the range of blocks advertised by eth/69 peer in its status and BlockRangeUpdate messages
+func (pi *PeerInfo) SetBlockRange(earliest, latest uint64) {
+	atomic.StoreUint64(&pi.earliest, earliest)
+	pi.SetIncreasedHeight(latest)
+}
+
+// HasBlock - the peer is expected to serve the block, the range is known only for eth/69 peers
+func (pi *PeerInfo) HasBlock(blockNum uint64) bool {
+	return pi.EarliestBlock() <= blockNum && blockNum <= pi.Height()
+}
+
 // ClearDeadlines goes through the deadlines of
 // given peers and removes the ones that have passed
 // Optionally, it also clears one extra deadline - this is used when response is received
@@ -285,10 +303,11 @@ func handShake(
 	rw p2p.MsgReadWriter,
 	version uint,
 	minVersion uint,
-) (*libcommon.Hash, *p2p.PeerError) {
+) (*libcommon.Hash, *eth.BlockRangeUpdatePacket, *p2p.PeerError) {
 	// Send out own handshake in a new thread
 	errChan := make(chan *p2p.PeerError, 2)
 	resultChan := make(chan *eth.StatusPacket, 1)
+	blockRangeChan := make(chan *eth.BlockRangeUpdatePacket, 1)
 
 	ourTD := gointerfaces.ConvertH256ToUint256Int(status.TotalDifficulty)
 	// Convert proto status data into the one required by devp2p
@@ -296,15 +315,29 @@ func handShake(
 
 	go func() {
 		defer debug.LogPanic()
-		status := &eth.StatusPacket{
-			ProtocolVersion: uint32(version),
-			NetworkID:       status.NetworkId,
-			TD:              ourTD.ToBig(),
-			Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
-			Genesis:         genesisHash,
-			ForkID:          forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime),
+		forkID := forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime)
+		var packet eth.Packet
+		if version >= direct.ETH69 {
+			packet = &eth.StatusPacket69{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+				EarliestBlock:   status.MinBlockHeight,
+				LatestBlock:     status.MaxBlockHeight,
+				LatestBlockHash: gointerfaces.ConvertH256ToHash(status.BestHash),
+			}
+		} else {
+			packet = &eth.StatusPacket{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				TD:              ourTD.ToBig(),
+				Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+			}
 		}
-		err := p2p.Send(rw, eth.StatusMsg, status)
+		err := p2p.Send(rw, eth.StatusMsg, packet)
 
 		if err == nil {
 			errChan <- nil
@@ -315,10 +348,11 @@ func handShake(
 
 	go func() {
 		defer debug.LogPanic()
-		status, err := readAndValidatePeerStatusMessage(rw, status, version, minVersion)
+		status, blockRange, err := readAndValidatePeerStatusMessage(rw, status, version, minVersion)
 
 		if err == nil {
 			resultChan <- status
+			blockRangeChan <- blockRange
 			errChan <- nil
 		} else {
 			errChan <- err
@@ -331,17 +365,17 @@ func handShake(
 		select {
 		case err := <-errChan:
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		case <-timeout.C:
-			return nil, p2p.NewPeerError(p2p.PeerErrorStatusHandshakeTimeout, p2p.DiscReadTimeout, nil, "sentry.handShake timeout")
+			return nil, nil, p2p.NewPeerError(p2p.PeerErrorStatusHandshakeTimeout, p2p.DiscReadTimeout, nil, "sentry.handShake timeout")
 		case <-ctx.Done():
-			return nil, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, ctx.Err(), "sentry.handShake ctx.Done")
+			return nil, nil, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, ctx.Err(), "sentry.handShake ctx.Done")
 		}
 	}
 
 	peerStatus := <-resultChan
-	return &peerStatus.Head, nil
+	return &peerStatus.Head, <-blockRangeChan, nil
 }
 
 func runPeer(
@@ -531,6 +565,15 @@ func runPeer(
 				logger.Error(fmt.Sprintf("%s: reading msg into bytes: %v", peerID, err))
 			}
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
+		case eth.BlockRangeUpdateMsg:
+			var blockRange eth.BlockRangeUpdatePacket
+			if err := msg.Decode(&blockRange); err != nil {
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, err, "sentry.runPeer: BlockRangeUpdate decode error")
+			}
+			if blockRange.EarliestBlock > blockRange.LatestBlock {
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, nil, fmt.Sprintf("sentry.runPeer: invalid block range: earliest %d > latest %d", blockRange.EarliestBlock, blockRange.LatestBlock))
+			}
+			peerInfo.SetBlockRange(blockRange.EarliestBlock, blockRange.LatestBlock)
 		case 11:
 			// Ignore
 			// TODO: Investigate why BSC peers for eth/67 send these messages
@@ -601,23 +644,29 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 		ctx:          ctx,
 		p2p:          cfg,
 		peersStreams: NewPeersStreams(),
+		reputation:   newPeerReputation(cfg.NodeDatabase, logger),
 		logger:       logger,
 	}
+	go ss.saveReputationLoop(ctx)
 
 	var disc enode.Iterator
 	if dialCandidates != nil {
 		disc = dialCandidates()
 	}
 	protocols := []uint{protocol}
-	if protocol == direct.ETH67 {
+	switch protocol {
+	case direct.ETH67:
 		protocols = append(protocols, direct.ETH66)
+	case direct.ETH69:
+		// keep serving the peers which didn't upgrade yet
+		protocols = append(protocols, direct.ETH68)
 	}
 	for _, p := range protocols {
 		protocol := p
 		ss.Protocols = append(ss.Protocols, p2p.Protocol{
 			Name:           eth.ProtocolName,
 			Version:        protocol,
-			Length:         17,
+			Length:         eth.ProtocolLengths[protocol],
 			DialCandidates: disc,
 			Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) *p2p.PeerError {
 				peerID := peer.Pubkey()
@@ -625,6 +674,10 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 				if ss.getPeer(peerID) != nil {
 					return p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscAlreadyConnected, nil, "peer already has connection")
 				}
+				if ss.reputation.isBanned(peerID) {
+					return p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscUselessPeer, nil, "peer is banned")
+				}
+				canBan := !peer.Info().Network.Static && !peer.Info().Network.Trusted
 				logger.Trace("[p2p] start with peer", "peerId", printablePeerID)
 
 				peerInfo := NewPeerInfo(peer, rw)
@@ -639,10 +692,14 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 					return p2p.NewPeerError(p2p.PeerErrorLocalStatusNeeded, p2p.DiscProtocolError, nil, "could not get status message from core")
 				}
 
-				peerBestHash, err := handShake(ctx, status, rw, protocol, protocol)
+				peerBestHash, peerBlockRange, err := handShake(ctx, status, rw, protocol, protocol)
 				if err != nil {
+					ss.recordPeerError(peerID, err, canBan)
 					return err
 				}
+				if peerBlockRange != nil {
+					peerInfo.SetBlockRange(peerBlockRange.EarliestBlock, peerBlockRange.LatestBlock)
+				}
 
 				// handshake is successful
 				logger.Trace("[p2p] Received status message OK", "peerId", printablePeerID, "name", peer.Name())
@@ -657,7 +714,7 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 
 				cap := p2p.Cap{Name: eth.ProtocolName, Version: protocol}
 
-				return runPeer(
+				err = runPeer(
 					ctx,
 					peerID,
 					cap,
@@ -667,6 +724,8 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 					ss.hasSubscribers,
 					logger,
 				)
+				ss.recordPeerError(peerID, err, canBan)
+				return err
 			},
 			NodeInfo: func() interface{} {
 				return readNodeInfo()
@@ -718,10 +777,12 @@ type GrpcServer struct {
 	p2pServerLock        sync.RWMutex
 	statusData           *proto_sentry.StatusData
 	statusDataLock       sync.RWMutex
+	rangeUpdateHeight    uint64 // head height of the latest BlockRangeUpdate sent to eth/69 peers
 	messageStreams       map[proto_sentry.MessageId]map[uint64]chan *proto_sentry.InboundMessage
 	messagesSubscriberID uint64
 	messageStreamsLock   sync.RWMutex
 	peersStreams         *PeersStreams
+	reputation           *peerReputation
 	p2p                  *p2p.Config
 	logger               log.Logger
 }
@@ -805,6 +866,7 @@ func (ss *GrpcServer) PenalizePeer(_ context.Context, req *proto_sentry.Penalize
 	peerID := ConvertH512ToPeerID(req.PeerId)
 	peerInfo := ss.getPeer(peerID)
 	if ss.statusData != nil && peerInfo != nil && !peerInfo.peer.Info().Network.Static && !peerInfo.peer.Info().Network.Trusted {
+		ss.reputation.record(peerID, peerEventViolation, true)
 		ss.removePeer(peerID, p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscRequested, nil, "penalized peer"))
 	}
 	return &emptypb.Empty{}, nil
@@ -861,7 +923,7 @@ func (ss *GrpcServer) findPeerByMinBlock(minBlock uint64) (*PeerInfo, bool) {
 	var maxPermits int
 	now := time.Now()
 	ss.rangePeers(func(peerInfo *PeerInfo) bool {
-		if peerInfo.Height() >= minBlock {
+		if peerInfo.HasBlock(minBlock) {
 			deadlines := peerInfo.ClearDeadlines(now, false /* givePermit */)
 			//fmt.Printf("%d deadlines for peer %s\n", deadlines, peerID)
 			if deadlines < maxPermitsPerPeer {
@@ -882,6 +944,7 @@ func (ss *GrpcServer) SendMessageByMinBlock(_ context.Context, inreq *proto_sent
 	msgcode := eth.FromProto[ss.Protocols[0].Version][inreq.Data.Id]
 	if msgcode != eth.GetBlockHeadersMsg &&
 		msgcode != eth.GetBlockBodiesMsg &&
+		msgcode != eth.GetReceiptsMsg &&
 		msgcode != eth.GetPooledTransactionsMsg {
 		return reply, fmt.Errorf("sendMessageByMinBlock not implemented for message Id: %s", inreq.Data.Id)
 	}
@@ -924,6 +987,10 @@ func (ss *GrpcServer) SendMessageById(_ context.Context, inreq *proto_sentry.Sen
 		//return reply, fmt.Errorf("peer not found: %s", peerID)
 		return reply, nil
 	}
+	if _, ok := eth.FromProto[peerInfo.protocol][inreq.Data.Id]; !ok {
+		// eth/69 sentry also serves eth/68 peers, the messages with changed encoding must not reach them
+		return reply, fmt.Errorf("sendMessageById: message Id %s is not supported by eth/%d peer", inreq.Data.Id, peerInfo.protocol)
+	}
 
 	ss.writePeer("[sentry] sendMessageById", peerInfo, msgcode, inreq.Data.Data, 0)
 	reply.Peers = []*proto_types.H512{inreq.PeerId}
@@ -994,6 +1061,8 @@ func (ss *GrpcServer) HandShake(context.Context, *emptypb.Empty) (*proto_sentry.
 		reply.Protocol = proto_sentry.Protocol_ETH67
 	case direct.ETH68:
 		reply.Protocol = proto_sentry.Protocol_ETH68
+	case direct.ETH69:
+		reply.Protocol = proto_sentry.Protocol_ETH69
 	}
 	return reply, nil
 }
@@ -1056,9 +1125,32 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 		// Not overwrite statusData if the message contains zero MaxBlock (comes from standalone transaction pool)
 		ss.statusData = statusData
 	}
+	if statusData.MaxBlockHeight >= ss.rangeUpdateHeight+blockRangeUpdateInterval {
+		ss.rangeUpdateHeight = statusData.MaxBlockHeight
+		ss.sendBlockRangeUpdate(statusData)
+	}
 	return reply, nil
 }
 
+// sendBlockRangeUpdate notifies eth/69 peers about the range of blocks we can serve
+func (ss *GrpcServer) sendBlockRangeUpdate(statusData *proto_sentry.StatusData) {
+	b, err := rlp.EncodeToBytes(&eth.BlockRangeUpdatePacket{
+		EarliestBlock:   statusData.MinBlockHeight,
+		LatestBlock:     statusData.MaxBlockHeight,
+		LatestBlockHash: gointerfaces.ConvertH256ToHash(statusData.BestHash),
+	})
+	if err != nil {
+		ss.logger.Error("[sentry] sendBlockRangeUpdate encode packet failed", "err", err)
+		return
+	}
+	ss.rangePeers(func(peerInfo *PeerInfo) bool {
+		if peerInfo.protocol >= direct.ETH69 {
+			ss.writePeer("[sentry] sendBlockRangeUpdate", peerInfo, eth.BlockRangeUpdateMsg, b, 0)
+		}
+		return true
+	})
+}
+
 func (ss *GrpcServer) Peers(_ context.Context, _ *emptypb.Empty) (*proto_sentry.PeersReply, error) {
 	p2pServer := ss.getP2PServer()
 	if p2pServer == nil {
@@ -1236,6 +1328,9 @@ func (ss *GrpcServer) Close() {
 	if p2pServer != nil {
 		p2pServer.Stop()
 	}
+	if err := ss.reputation.save(); err != nil {
+		ss.logger.Warn("[p2p] failed to save peer reputation", "err", err)
+	}
 }
 
 func (ss *GrpcServer) sendNewPeerToClients(peerID *proto_types.H512) {
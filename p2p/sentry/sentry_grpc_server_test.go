@@ -21,7 +21,9 @@ import (
 	"github.com/ledgerwatch/erigon/core/forkid"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/protocols/eth"
 	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/log/v3"
 )
 
@@ -63,7 +65,7 @@ func startHandshake(
 	errChan chan *p2p.PeerError,
 ) {
 	go func() {
-		_, err := handShake(ctx, status, pipe, protocolVersion, protocolVersion)
+		_, _, err := handShake(ctx, status, pipe, protocolVersion, protocolVersion)
 		errChan <- err
 	}()
 }
@@ -72,6 +74,8 @@ func startHandshake(
 // fork IDs in the protocol handshake.
 func TestForkIDSplit66(t *testing.T) { testForkIDSplit(t, direct.ETH66) }
 
+func TestForkIDSplit69(t *testing.T) { testForkIDSplit(t, direct.ETH69) }
+
 func testForkIDSplit(t *testing.T, protocol uint) {
 	var (
 		ctx           = context.Background()
@@ -196,3 +200,34 @@ func TestSentryServerImpl_SetStatusInitPanic(t *testing.T) {
 		t.Fatalf("error expected")
 	}
 }
+
+func TestHandShake69BlockRange(t *testing.T) {
+	ctx := context.Background()
+	heightForks, timeForks := forkid.GatherForks(params.MainnetChainConfig, 0 /* genesisTime */)
+	bestHash := libcommon.HexToHash("deadc0de")
+	status := &proto_sentry.StatusData{
+		NetworkId:       1,
+		TotalDifficulty: gointerfaces.ConvertUint256IntToH256(new(uint256.Int)),
+		BestHash:        gointerfaces.ConvertHashToH256(bestHash),
+		MinBlockHeight:  10,
+		MaxBlockHeight:  100,
+		ForkData: &proto_sentry.Forks{
+			Genesis:     gointerfaces.ConvertHashToH256(params.MainnetGenesisHash),
+			HeightForks: heightForks,
+			TimeForks:   timeForks,
+		},
+	}
+
+	p1, p2 := p2p.MsgPipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errc := make(chan *p2p.PeerError, 1)
+	startHandshake(ctx, status, p1, direct.ETH69, errc)
+
+	head, blockRange, err := handShake(ctx, status, p2, direct.ETH69, direct.ETH69)
+	require.Nil(t, err)
+	require.Nil(t, <-errc)
+	require.Equal(t, bestHash, *head)
+	require.Equal(t, &eth.BlockRangeUpdatePacket{EarliestBlock: 10, LatestBlock: 100, LatestBlockHash: bestHash}, blockRange)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/forkid"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/services"
 )
 
 var ErrNoHead = errors.New("ReadChainHead: ReadCurrentHeader error")
@@ -28,7 +29,8 @@ type ChainHead struct {
 }
 
 type StatusDataProvider struct {
-	db kv.RoDB
+	db          kv.RoDB
+	blockReader services.FullBlockReader
 
 	networkId   uint64
 	genesisHash libcommon.Hash
@@ -38,12 +40,14 @@ type StatusDataProvider struct {
 
 func NewStatusDataProvider(
 	db kv.RoDB,
+	blockReader services.FullBlockReader,
 	chainConfig *chain.Config,
 	genesis *types.Block,
 	networkId uint64,
 ) *StatusDataProvider {
 	s := &StatusDataProvider{
 		db:          db,
+		blockReader: blockReader,
 		networkId:   networkId,
 		genesisHash: genesis.Hash(),
 	}
@@ -58,6 +62,7 @@ func (s *StatusDataProvider) makeStatusData(head ChainHead) *proto_sentry.Status
 		NetworkId:       s.networkId,
 		TotalDifficulty: gointerfaces.ConvertUint256IntToH256(head.HeadTd),
 		BestHash:        gointerfaces.ConvertHashToH256(head.HeadHash),
+		MinBlockHeight:  s.earliestBlock(),
 		MaxBlockHeight:  head.HeadHeight,
 		MaxBlockTime:    head.HeadTime,
 		ForkData: &proto_sentry.Forks{
@@ -68,6 +73,15 @@ func (s *StatusDataProvider) makeStatusData(head ChainHead) *proto_sentry.Status
 	}
 }
 
+// earliestBlock - first block which bodies and receipts are served for: with --prune.mode blocks the segments
+// below SegmentsMin are deleted, the database only holds blocks after the segments
+func (s *StatusDataProvider) earliestBlock() uint64 {
+	if s.blockReader == nil {
+		return 0
+	}
+	return s.blockReader.Snapshots().SegmentsMin()
+}
+
 func (s *StatusDataProvider) GetStatusData(ctx context.Context) (*proto_sentry.StatusData, error) {
 	chainHead, err := ReadChainHead(ctx, s.db)
 	if err != nil {
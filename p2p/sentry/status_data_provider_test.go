@@ -0,0 +1,32 @@
+package sentry
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+func TestStatusDataBlockRange(t *testing.T) {
+	config := &chain.Config{HomesteadBlock: big.NewInt(1), ChainID: big.NewInt(1)}
+	genesis := types.NewBlockWithHeader(&types.Header{Number: new(big.Int)})
+	snapshots := freezeblocks.NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, t.TempDir(), 0, log.New())
+	head := ChainHead{HeadHeight: 2_000, HeadTime: 100, HeadTd: uint256.NewInt(1)}
+
+	provider := NewStatusDataProvider(nil, freezeblocks.NewBlockReader(snapshots, nil), config, genesis, 1)
+	status := provider.makeStatusData(head)
+	require.Equal(t, uint64(0), status.MinBlockHeight)
+	require.Equal(t, uint64(2_000), status.MaxBlockHeight)
+
+	// blocks below the pruned segments are not served
+	snapshots.SetSegmentsMin(1_000)
+	status = provider.makeStatusData(head)
+	require.Equal(t, uint64(1_000), status.MinBlockHeight)
+}
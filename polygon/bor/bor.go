@@ -43,6 +43,7 @@ import (
 	"github.com/ledgerwatch/erigon/polygon/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/polygon/bor/statefull"
 	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/bridge"
 	"github.com/ledgerwatch/erigon/polygon/heimdall"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -57,6 +58,7 @@ const (
 	snapshotPersistInterval = 1024 // Number of blocks after which to persist the vote snapshot to the database
 	inmemorySnapshots       = 128  // Number of recent vote snapshots to keep in memory
 	inmemorySignatures      = 4096 // Number of recent block signatures to keep in memory
+	inmemorySprintSets      = 1024 // Number of validator sets of recent sprints to keep in memory
 )
 
 // Bor protocol constants.
@@ -290,6 +292,8 @@ type Bor struct {
 	Recents    *lru.ARCCache[libcommon.Hash, *Snapshot]         // Snapshots for recent block to speed up reorgs
 	Signatures *lru.ARCCache[libcommon.Hash, libcommon.Address] // Signatures of recent blocks to speed up mining
 
+	SprintValidatorSets *valset.SprintCache[libcommon.Hash] // Validator sets of recent sprint end headers to speed up snapshots
+
 	authorizedSigner atomic.Pointer[signer] // Ethereum address and sign function of the signing key
 
 	execCtx context.Context // context of caller execution stage
@@ -297,6 +301,7 @@ type Bor struct {
 	spanner                Spanner
 	GenesisContractsClient GenesisContracts
 	HeimdallClient         heimdall.HeimdallClient
+	bridge                 bridge.Bridge
 
 	// scope event.SubscriptionScope
 	// The fields below are for testing only
@@ -344,9 +349,11 @@ func New(
 		blockReader:            blockReader,
 		Recents:                recents,
 		Signatures:             signatures,
+		SprintValidatorSets:    valset.NewSprintCache[libcommon.Hash](inmemorySprintSets),
 		spanner:                spanner,
 		GenesisContractsClient: genesisContracts,
 		HeimdallClient:         heimdallClient,
+		bridge:                 bridge.NewBridge(chainConfig.ChainID, borConfig, heimdallClient, stateReceiverABI, logger),
 		execCtx:                context.Background(),
 		logger:                 logger,
 		closeCh:                make(chan struct{}),
@@ -405,15 +412,16 @@ func NewRo(chainConfig *chain.Config, db kv.RoDB, blockReader services.FullBlock
 	signatures, _ := lru.NewARC[libcommon.Hash, libcommon.Address](inmemorySignatures)
 
 	return &Bor{
-		chainConfig: chainConfig,
-		config:      borConfig,
-		DB:          rwWrapper{db},
-		blockReader: blockReader,
-		logger:      logger,
-		Recents:     recents,
-		Signatures:  signatures,
-		execCtx:     context.Background(),
-		closeCh:     make(chan struct{}),
+		chainConfig:         chainConfig,
+		config:              borConfig,
+		DB:                  rwWrapper{db},
+		blockReader:         blockReader,
+		logger:              logger,
+		Recents:             recents,
+		Signatures:          signatures,
+		execCtx:             context.Background(),
+		SprintValidatorSets: valset.NewSprintCache[libcommon.Hash](inmemorySprintSets),
+		closeCh:             make(chan struct{}),
 	}
 }
 
@@ -641,6 +649,7 @@ func (c *Bor) initFrozenSnapshot(chain consensus.ChainHeaderReader, number uint6
 
 		// new snap shot
 		snap = NewSnapshot(c.config, c.Signatures, 0, hash, validators, c.logger)
+		snap.sprintSets = c.SprintValidatorSets
 
 		if err = snap.Store(c.DB); err != nil {
 			return nil, err
@@ -714,6 +723,7 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash li
 			if s, err := LoadSnapshot(c.config, c.Signatures, c.DB, hash); err == nil {
 				c.logger.Trace("Loaded snapshot from disk", "number", number, "hash", hash)
 
+				s.sprintSets = c.SprintValidatorSets
 				snap = s
 				break
 			}
@@ -1450,57 +1460,9 @@ func (c *Bor) CommitStates(
 	chain statefull.ChainContext,
 	syscall consensus.SystemCall,
 ) error {
-	events := chain.Chain.BorEventsByBlock(header.Hash(), header.Number.Uint64())
-
-	//if len(events) == 50 || len(events) == 0 { // we still sometime could get 0 events from borevent file
-	if len(events) == 50 { // we still sometime could get 0 events from borevent file
-		blockNum := header.Number.Uint64()
-
-		var to time.Time
-		if c.config.IsIndore(blockNum) {
-			stateSyncDelay := c.config.CalculateStateSyncDelay(blockNum)
-			to = time.Unix(int64(header.Time-stateSyncDelay), 0)
-		} else {
-			pHeader := chain.Chain.GetHeaderByNumber(blockNum - c.config.CalculateSprintLength(blockNum))
-			to = time.Unix(int64(pHeader.Time), 0)
-		}
-
-		startEventID := chain.Chain.BorStartEventID(header.Hash(), blockNum)
-		log.Warn("[dbg] fallback to remote bor events", "blockNum", blockNum, "startEventID", startEventID, "events_from_db_or_snaps", len(events))
-		remote, err := c.HeimdallClient.FetchStateSyncEvents(context.Background(), startEventID, to, 0)
-		if err != nil {
-			return err
-		}
-		if len(remote) > 0 {
-			chainID := c.chainConfig.ChainID.String()
-
-			var merged []*heimdall.EventRecordWithTime
-			events = events[:0]
-			for _, event := range remote {
-				if event.ChainID != chainID {
-					continue
-				}
-				if event.Time.After(to) {
-					continue
-				}
-				merged = append(merged, event)
-			}
-
-			for _, ev := range merged {
-				eventRecordWithoutTime := ev.BuildEventRecord()
-
-				recordBytes, err := rlp.EncodeToBytes(eventRecordWithoutTime)
-				if err != nil {
-					panic(err)
-				}
-
-				data, err := stateReceiverABI.Pack("commitState", big.NewInt(ev.Time.Unix()), recordBytes)
-				if err != nil {
-					panic(err)
-				}
-				events = append(events, data)
-			}
-		}
+	events, err := c.bridge.Events(context.Background(), chain.Chain, header)
+	if err != nil {
+		return err
 	}
 
 	for _, event := range events {
@@ -1514,6 +1476,7 @@ func (c *Bor) CommitStates(
 
 func (c *Bor) SetHeimdallClient(h heimdall.HeimdallClient) {
 	c.HeimdallClient = h
+	c.bridge = bridge.NewBridge(c.chainConfig.ChainID, c.config, h, stateReceiverABI, c.logger)
 }
 
 //
@@ -5,13 +5,58 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	finalityrawdb "github.com/ledgerwatch/erigon/polygon/bor/finality/rawdb"
 	"github.com/ledgerwatch/erigon/polygon/bor/finality/whitelist"
 )
 
+// Tracker - source of the latest milestone and checkpoint whitelisted from heimdall, which define finality on bor chains
+type Tracker interface {
+	GetWhitelistedMilestone() (bool, uint64, common.Hash)
+	GetWhitelistedCheckpoint() (bool, uint64, common.Hash)
+}
+
+var registeredDBTracker Tracker
+
+// RegisterDBTracker - allows processes which don't run the whitelisting service (like rpcdaemon with --datadir)
+// to follow milestones and checkpoints which erigon persists in bor db
+func RegisterDBTracker(db kv.RoDB) {
+	registeredDBTracker = &dbTracker{db}
+}
+
+// GetTracker - returns nil if finality is not milestone based
+func GetTracker() Tracker {
+	if service := whitelist.GetWhitelistingService(); service != nil {
+		return service
+	}
+	return registeredDBTracker
+}
+
+type dbTracker struct {
+	db kv.RoDB
+}
+
+func (t *dbTracker) GetWhitelistedMilestone() (bool, uint64, common.Hash) {
+	number, hash, err := finalityrawdb.ReadFinality[*finalityrawdb.Milestone](t.db)
+	return err == nil, number, hash
+}
+
+func (t *dbTracker) GetWhitelistedCheckpoint() (bool, uint64, common.Hash) {
+	number, hash, err := finalityrawdb.ReadFinality[*finalityrawdb.Checkpoint](t.db)
+	return err == nil, number, hash
+}
+
+// GetFinalizedBlockNumber - latest canonical block covered by a milestone (or checkpoint if there are no milestones),
+// 0 if there is no such block
 func GetFinalizedBlockNumber(tx kv.Tx) uint64 {
-	currentBlockNum := rawdb.ReadCurrentHeader(tx)
+	service := GetTracker()
+	if service == nil {
+		return 0
+	}
 
-	service := whitelist.GetWhitelistingService()
+	currentBlockNum := rawdb.ReadCurrentHeader(tx)
+	if currentBlockNum == nil {
+		return 0
+	}
 
 	doExist, number, hash := service.GetWhitelistedMilestone()
 	if doExist && number <= currentBlockNum.Number.Uint64() {
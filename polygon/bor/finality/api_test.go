@@ -0,0 +1,59 @@
+package finality
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	finalityrawdb "github.com/ledgerwatch/erigon/polygon/bor/finality/rawdb"
+)
+
+func TestDBTrackerFinalizedBlockNumber(t *testing.T) {
+	t.Cleanup(func() { registeredDBTracker = nil })
+
+	borDB := memdb.NewTestDB(t)
+	_, tx := memdb.NewTestTx(t)
+
+	var headers []*types.Header
+	for i := 0; i <= 10; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte("test")}
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+		headers = append(headers, header)
+		rawdb.WriteHeader(tx, header)
+		require.NoError(t, rawdb.WriteCanonicalHash(tx, header.Hash(), header.Number.Uint64()))
+	}
+	rawdb.WriteHeadHeaderHash(tx, headers[10].Hash())
+
+	require.Nil(t, GetTracker())
+	require.Zero(t, GetFinalizedBlockNumber(tx))
+
+	RegisterDBTracker(borDB)
+	require.NotNil(t, GetTracker())
+	require.Zero(t, GetFinalizedBlockNumber(tx))
+
+	// checkpoint is used while there are no milestones
+	require.NoError(t, finalityrawdb.WriteLastFinality[*finalityrawdb.Checkpoint](borDB, 4, headers[4].Hash()))
+	require.Equal(t, uint64(4), GetFinalizedBlockNumber(tx))
+
+	// tracker follows milestones persisted after registration
+	require.NoError(t, finalityrawdb.WriteLastFinality[*finalityrawdb.Milestone](borDB, 7, headers[7].Hash()))
+	require.Equal(t, uint64(7), GetFinalizedBlockNumber(tx))
+
+	// milestone ahead of current header is not final yet
+	require.NoError(t, finalityrawdb.WriteLastFinality[*finalityrawdb.Milestone](borDB, 12, headers[7].Hash()))
+	require.Equal(t, uint64(4), GetFinalizedBlockNumber(tx))
+
+	// milestone of non-canonical block
+	require.NoError(t, finalityrawdb.WriteLastFinality[*finalityrawdb.Milestone](borDB, 8, headers[7].Hash()))
+	require.Equal(t, uint64(4), GetFinalizedBlockNumber(tx))
+
+	number, _, err := finalityrawdb.ReadFinality[*finalityrawdb.Milestone](borDB)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), number)
+}
@@ -51,7 +51,7 @@ func (m *Milestone) block() (uint64, libcommon.Hash) {
 	return m.Block, m.Hash
 }
 
-func ReadFinality[T BlockFinality[T]](db kv.RwDB) (uint64, libcommon.Hash, error) {
+func ReadFinality[T BlockFinality[T]](db kv.RoDB) (uint64, libcommon.Hash, error) {
 	lastTV, key := getKey[T]()
 
 	var data []byte
@@ -22,6 +22,8 @@ type Snapshot struct {
 	config   *borcfg.BorConfig                          // Consensus engine parameters to fine tune behavior
 	sigcache *lru.ARCCache[common.Hash, common.Address] // Cache of recent block signatures to speed up ecrecover
 
+	sprintSets *valset.SprintCache[common.Hash] // Validator sets set by sprint end headers, optional
+
 	Number       uint64               `json:"number"`       // Block number where the snapshot was created
 	Hash         common.Hash          `json:"hash"`         // Block hash where the snapshot was created
 	ValidatorSet *valset.ValidatorSet `json:"validatorSet"` // Validator set at this moment
@@ -129,6 +131,7 @@ func (s *Snapshot) copy() *Snapshot {
 	cpy := &Snapshot{
 		config:       s.config,
 		sigcache:     s.sigcache,
+		sprintSets:   s.sprintSets,
 		Number:       s.Number,
 		Hash:         s.Hash,
 		ValidatorSet: s.ValidatorSet.Copy(),
@@ -178,13 +181,21 @@ func (s *Snapshot) Apply(parent *types.Header, headers []*types.Header, logger l
 			if err := ValidateHeaderExtraLength(header.Extra); err != nil {
 				return snap, err
 			}
-			validatorBytes := GetValidatorBytes(header, s.config)
 
-			// get validators from headers and use that for new validator set
-			newVals, _ := valset.ParseValidators(validatorBytes)
-			v := getUpdatedValidatorSet(snap.ValidatorSet.Copy(), newVals, logger)
-			v.IncrementProposerPriority(1)
-			snap.ValidatorSet = v
+			// the header hash commits to the parent validator set as well as to the new validators,
+			// so a set derived once for a sprint end header can be reused by any later snapshot
+			hash := header.Hash()
+			v, ok := s.sprintSets.Get(hash)
+			if !ok {
+				validatorBytes := GetValidatorBytes(header, s.config)
+
+				// get validators from headers and use that for new validator set
+				newVals, _ := valset.ParseValidators(validatorBytes)
+				v = getUpdatedValidatorSet(snap.ValidatorSet.Copy(), newVals, logger)
+				v.IncrementProposerPriority(1)
+				s.sprintSets.Add(hash, v)
+			}
+			snap.ValidatorSet = v.Copy()
 		}
 
 		parent = header
@@ -0,0 +1,91 @@
+package valset
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SprintCache keeps validator sets in effect at sprint boundaries, so that verification of the headers
+// of a sprint doesn't derive the same set again and again. Reads are lock-free: writers publish a new
+// immutable map on every Add. Cached sets are shared between readers and must not be modified, Copy them
+// first. The oldest sets are evicted once limit is reached. A nil cache is valid and caches nothing.
+type SprintCache[K comparable] struct {
+	limit int
+	mu    sync.Mutex // serialises writers
+	state atomic.Pointer[sprintCacheState[K]]
+}
+
+type sprintCacheState[K comparable] struct {
+	sets map[K]*ValidatorSet
+	keys []K // in order of insertion
+}
+
+func NewSprintCache[K comparable](limit int) *SprintCache[K] {
+	return &SprintCache[K]{limit: limit}
+}
+
+// Get returns the cached validator set of the sprint identified by key.
+func (c *SprintCache[K]) Get(key K) (*ValidatorSet, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	state := c.state.Load()
+	if state == nil {
+		return nil, false
+	}
+
+	vals, ok := state.sets[key]
+	return vals, ok
+}
+
+// Add caches the validator set of the sprint identified by key, first set added for a key wins.
+func (c *SprintCache[K]) Add(key K, vals *ValidatorSet) {
+	if c == nil || c.limit <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.state.Load()
+	if old == nil {
+		old = &sprintCacheState[K]{}
+	}
+
+	if _, ok := old.sets[key]; ok {
+		return
+	}
+
+	keys := old.keys
+	if len(keys) >= c.limit {
+		keys = keys[len(keys)-c.limit+1:]
+	}
+
+	state := &sprintCacheState[K]{
+		sets: make(map[K]*ValidatorSet, len(keys)+1),
+		keys: make([]K, 0, len(keys)+1),
+	}
+
+	for _, k := range keys {
+		state.sets[k] = old.sets[k]
+	}
+
+	state.sets[key] = vals
+	state.keys = append(append(state.keys, keys...), key)
+	c.state.Store(state)
+}
+
+// Len returns the number of cached validator sets.
+func (c *SprintCache[K]) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	state := c.state.Load()
+	if state == nil {
+		return 0
+	}
+
+	return len(state.keys)
+}
@@ -0,0 +1,126 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// maxStoredEventsPerBlock - local store may return a truncated list of events of a block (e.g. from bor event snapshots),
+// it's detected by hitting this limit and then the full list is fetched from Heimdall.
+const maxStoredEventsPerBlock = 50
+
+// Bridge - source of the state sync events (L1 -> Bor messages relayed by Heimdall) for the Bor block builder and validator
+type Bridge interface {
+	// Events returns inputs of the commitState system calls, in order, which the Bor block with the given header
+	// has to make while being built or validated. Only sprint start blocks commit state sync events.
+	Events(ctx context.Context, chain ChainReader, header *types.Header) ([]rlp.RawValue, error)
+}
+
+// ChainReader - local (db or snapshots) view of the chain the block is built on
+type ChainReader interface {
+	GetHeaderByNumber(number uint64) *types.Header
+	BorEventsByBlock(hash libcommon.Hash, number uint64) []rlp.RawValue
+	BorStartEventID(hash libcommon.Hash, number uint64) uint64
+}
+
+type bridge struct {
+	chainID          string
+	borConfig        *borcfg.BorConfig
+	heimdallClient   heimdall.HeimdallClient
+	stateReceiverABI abi.ABI
+	logger           log.Logger
+}
+
+func NewBridge(chainID *big.Int, borConfig *borcfg.BorConfig, heimdallClient heimdall.HeimdallClient, stateReceiverABI abi.ABI, logger log.Logger) Bridge {
+	return &bridge{
+		chainID:          chainID.String(),
+		borConfig:        borConfig,
+		heimdallClient:   heimdallClient,
+		stateReceiverABI: stateReceiverABI,
+		logger:           logger,
+	}
+}
+
+func (b *bridge) Events(ctx context.Context, chain ChainReader, header *types.Header) ([]rlp.RawValue, error) {
+	blockNum := header.Number.Uint64()
+	if blockNum == 0 || blockNum%b.borConfig.CalculateSprintLength(blockNum) != 0 {
+		return nil, nil
+	}
+
+	events := chain.BorEventsByBlock(header.Hash(), blockNum)
+	if len(events) != maxStoredEventsPerBlock {
+		return events, nil
+	}
+
+	to, err := EventsTimeLimit(b.borConfig, blockNum, header.Time, func(number uint64) (uint64, error) {
+		h := chain.GetHeaderByNumber(number)
+		if h == nil {
+			return 0, fmt.Errorf("header not found: %d", number)
+		}
+		return h.Time, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	startEventID := chain.BorStartEventID(header.Hash(), blockNum)
+	b.logger.Warn("[bridge] fallback to remote bor events", "blockNum", blockNum, "startEventID", startEventID, "events_from_db_or_snaps", len(events))
+	if b.heimdallClient == nil {
+		return nil, fmt.Errorf("[bridge] no heimdall client to fetch events of block %d", blockNum)
+	}
+	remote, err := b.heimdallClient.FetchStateSyncEvents(ctx, startEventID, to, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(remote) == 0 {
+		return events, nil
+	}
+
+	events = events[:0]
+	for _, event := range remote {
+		if event.ChainID != b.chainID || event.Time.After(to) {
+			continue
+		}
+		data, err := b.packCommitState(event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, data)
+	}
+	return events, nil
+}
+
+func (b *bridge) packCommitState(event *heimdall.EventRecordWithTime) (rlp.RawValue, error) {
+	recordBytes, err := rlp.EncodeToBytes(event.BuildEventRecord())
+	if err != nil {
+		return nil, err
+	}
+	return b.stateReceiverABI.Pack("commitState", big.NewInt(event.Time.Unix()), recordBytes)
+}
+
+// EventsTimeLimit returns the time up to which state sync events recorded on Heimdall are committed by the sprint
+// start block blockNum:
+//   - before Indore - time of the block one sprint back, given by headerTime;
+//   - since Indore - blockTime minus state sync confirmation delay, so the window doesn't depend on previous sprint timing.
+func EventsTimeLimit(config *borcfg.BorConfig, blockNum, blockTime uint64, headerTime func(blockNum uint64) (uint64, error)) (time.Time, error) {
+	if config.IsIndore(blockNum) {
+		stateSyncDelay := config.CalculateStateSyncDelay(blockNum)
+		return time.Unix(int64(blockTime-stateSyncDelay), 0), nil
+	}
+	t, err := headerTime(blockNum - config.CalculateSprintLength(blockNum))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(t), 0), nil
+}
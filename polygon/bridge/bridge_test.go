@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/accounts/abi"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+const commitStateABI = `[{"constant":false,"inputs":[{"internalType":"uint256","name":"syncTime","type":"uint256"},{"internalType":"bytes","name":"recordBytes","type":"bytes"}],"name":"commitState","outputs":[{"internalType":"bool","name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+type testChain struct {
+	headers map[uint64]*types.Header
+	events  []rlp.RawValue
+}
+
+func (c testChain) GetHeaderByNumber(number uint64) *types.Header { return c.headers[number] }
+func (c testChain) BorEventsByBlock(libcommon.Hash, uint64) []rlp.RawValue {
+	return c.events
+}
+func (c testChain) BorStartEventID(libcommon.Hash, uint64) uint64 { return 7 }
+
+func TestEventsTimeLimit(t *testing.T) {
+	config := &borcfg.BorConfig{
+		Sprint:                     map[string]uint64{"0": 16},
+		IndoreBlock:                big.NewInt(64),
+		StateSyncConfirmationDelay: map[string]uint64{"0": 128},
+	}
+	headerTime := func(number uint64) (uint64, error) { return 1000 + number, nil }
+
+	to, err := EventsTimeLimit(config, 32, 5000, headerTime)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1000+16, 0), to)
+
+	to, err = EventsTimeLimit(config, 64, 5000, headerTime)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(5000-128, 0), to)
+}
+
+func TestEventsFallbackToHeimdall(t *testing.T) {
+	config := &borcfg.BorConfig{
+		Sprint:                     map[string]uint64{"0": 16},
+		IndoreBlock:                big.NewInt(0),
+		StateSyncConfirmationDelay: map[string]uint64{"0": 128},
+	}
+	stateReceiverABI, err := abi.JSON(strings.NewReader(commitStateABI))
+	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	client := heimdall.NewMockHeimdallClient(ctrl)
+	b := NewBridge(big.NewInt(137), config, client, stateReceiverABI, log.New())
+	ctx := context.Background()
+
+	// not a sprint start
+	events, err := b.Events(ctx, testChain{}, &types.Header{Number: big.NewInt(17)})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// stored events are complete - no heimdall calls
+	chain := testChain{events: []rlp.RawValue{{1}, {2}}}
+	events, err = b.Events(ctx, chain, &types.Header{Number: big.NewInt(32), Time: 1000})
+	require.NoError(t, err)
+	require.Equal(t, chain.events, events)
+
+	// stored events are truncated - re-fetched from heimdall, foreign chain and too recent events are skipped
+	chain.events = make([]rlp.RawValue, maxStoredEventsPerBlock)
+	to := time.Unix(1000-128, 0)
+	client.EXPECT().FetchStateSyncEvents(gomock.Any(), uint64(7), to, 0).Return([]*heimdall.EventRecordWithTime{
+		{EventRecord: heimdall.EventRecord{ID: 7, ChainID: "137"}, Time: to.Add(-time.Second)},
+		{EventRecord: heimdall.EventRecord{ID: 8, ChainID: "80001"}, Time: to.Add(-time.Second)},
+		{EventRecord: heimdall.EventRecord{ID: 9, ChainID: "137"}, Time: to.Add(time.Second)},
+	}, nil)
+	events, err = b.Events(ctx, chain, &types.Header{Number: big.NewInt(32), Time: 1000})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event, err := heimdall.UnpackEventRecordWithTime(stateReceiverABI, events[0])
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), event.ID)
+}
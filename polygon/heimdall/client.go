@@ -11,6 +11,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ledgerwatch/log/v3"
@@ -66,7 +67,7 @@ type HeimdallClient interface {
 var _ HeimdallClient = &Client{}
 
 type Client struct {
-	urlString    string
+	urlString    atomic.Pointer[string]
 	client       HttpClient
 	retryBackOff time.Duration
 	maxRetries   int
@@ -94,14 +95,24 @@ func NewHeimdallClient(urlString string, logger log.Logger) *Client {
 }
 
 func newHeimdallClient(urlString string, httpClient HttpClient, retryBackOff time.Duration, maxRetries int, logger log.Logger) *Client {
-	return &Client{
-		urlString:    urlString,
+	c := &Client{
 		logger:       logger,
 		client:       httpClient,
 		retryBackOff: retryBackOff,
 		maxRetries:   maxRetries,
 		closeCh:      make(chan struct{}),
 	}
+	c.SetURL(urlString)
+	return c
+}
+
+// SetURL - changes heimdall url at runtime, requests in flight are finished with the old one
+func (c *Client) SetURL(urlString string) {
+	c.urlString.Store(&urlString)
+}
+
+func (c *Client) URL() string {
+	return *c.urlString.Load()
 }
 
 const (
@@ -132,7 +143,7 @@ func (c *Client) FetchStateSyncEvents(ctx context.Context, fromID uint64, to tim
 	eventRecords := make([]*EventRecordWithTime, 0)
 
 	for {
-		url, err := stateSyncListURL(c.urlString, fromID, to.Unix())
+		url, err := stateSyncListURL(c.URL(), fromID, to.Unix())
 		if err != nil {
 			return nil, err
 		}
@@ -176,7 +187,7 @@ func (c *Client) FetchStateSyncEvents(ctx context.Context, fromID uint64, to tim
 }
 
 func (c *Client) FetchStateSyncEvent(ctx context.Context, id uint64) (*EventRecordWithTime, error) {
-	url, err := stateSyncURL(c.urlString, id)
+	url, err := stateSyncURL(c.URL(), id)
 
 	if err != nil {
 		return nil, err
@@ -202,7 +213,7 @@ func (c *Client) FetchStateSyncEvent(ctx context.Context, id uint64) (*EventReco
 }
 
 func (c *Client) FetchLatestSpan(ctx context.Context) (*Span, error) {
-	url, err := latestSpanURL(c.urlString)
+	url, err := latestSpanURL(c.URL())
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +229,7 @@ func (c *Client) FetchLatestSpan(ctx context.Context) (*Span, error) {
 }
 
 func (c *Client) FetchSpan(ctx context.Context, spanID uint64) (*Span, error) {
-	url, err := spanURL(c.urlString, spanID)
+	url, err := spanURL(c.URL(), spanID)
 	if err != nil {
 		return nil, fmt.Errorf("%w, spanID=%d", err, spanID)
 	}
@@ -235,7 +246,7 @@ func (c *Client) FetchSpan(ctx context.Context, spanID uint64) (*Span, error) {
 
 // FetchCheckpoint fetches the checkpoint from heimdall
 func (c *Client) FetchCheckpoint(ctx context.Context, number int64) (*Checkpoint, error) {
-	url, err := checkpointURL(c.urlString, number)
+	url, err := checkpointURL(c.URL(), number)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +262,7 @@ func (c *Client) FetchCheckpoint(ctx context.Context, number int64) (*Checkpoint
 }
 
 func (c *Client) FetchCheckpoints(ctx context.Context, page uint64, limit uint64) ([]*Checkpoint, error) {
-	url, err := checkpointListURL(c.urlString, page, limit)
+	url, err := checkpointListURL(c.URL(), page, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +284,7 @@ func isInvalidMilestoneIndexError(err error) bool {
 
 // FetchMilestone fetches a milestone from heimdall
 func (c *Client) FetchMilestone(ctx context.Context, number int64) (*Milestone, error) {
-	url, err := milestoneURL(c.urlString, number)
+	url, err := milestoneURL(c.URL(), number)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +310,7 @@ func (c *Client) FetchMilestone(ctx context.Context, number int64) (*Milestone,
 
 // FetchCheckpointCount fetches the checkpoint count from heimdall
 func (c *Client) FetchCheckpointCount(ctx context.Context) (int64, error) {
-	url, err := checkpointCountURL(c.urlString)
+	url, err := checkpointCountURL(c.URL())
 	if err != nil {
 		return 0, err
 	}
@@ -316,7 +327,7 @@ func (c *Client) FetchCheckpointCount(ctx context.Context) (int64, error) {
 
 // FetchMilestoneCount fetches the milestone count from heimdall
 func (c *Client) FetchMilestoneCount(ctx context.Context) (int64, error) {
-	url, err := milestoneCountURL(c.urlString)
+	url, err := milestoneCountURL(c.URL())
 	if err != nil {
 		return 0, err
 	}
@@ -333,7 +344,7 @@ func (c *Client) FetchMilestoneCount(ctx context.Context) (int64, error) {
 
 // FetchLastNoAckMilestone fetches the last no-ack-milestone from heimdall
 func (c *Client) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
-	url, err := lastNoAckMilestoneURL(c.urlString)
+	url, err := lastNoAckMilestoneURL(c.URL())
 	if err != nil {
 		return "", err
 	}
@@ -350,7 +361,7 @@ func (c *Client) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
 
 // FetchNoAckMilestone fetches the last no-ack-milestone from heimdall
 func (c *Client) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
-	url, err := noAckMilestoneURL(c.urlString, milestoneID)
+	url, err := noAckMilestoneURL(c.URL(), milestoneID)
 	if err != nil {
 		return err
 	}
@@ -372,7 +383,7 @@ func (c *Client) FetchNoAckMilestone(ctx context.Context, milestoneID string) er
 // FetchMilestoneID fetches the bool result from Heimdall whether the ID corresponding
 // to the given milestone is in process in Heimdall
 func (c *Client) FetchMilestoneID(ctx context.Context, milestoneID string) error {
-	url, err := milestoneIDURL(c.urlString, milestoneID)
+	url, err := milestoneIDURL(c.URL(), milestoneID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,211 @@
+package heimdall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall/heimdallproto"
+)
+
+var _ HeimdallClient = &GrpcClient{}
+
+// GrpcClient - fetches state sync events and spans by id over the gRPC api of Heimdall (the one bor uses, see heimdallproto).
+// The latest span, checkpoints and milestones are fetched over http by the embedded client.
+type GrpcClient struct {
+	*Client
+	conn   *grpc.ClientConn
+	client heimdallproto.HeimdallClient
+}
+
+func NewGrpcClient(grpcAddr string, httpClient *Client) (*GrpcClient, error) {
+	backoffCfg := backoff.DefaultConfig
+	backoffCfg.BaseDelay = 500 * time.Millisecond
+	backoffCfg.MaxDelay = 10 * time.Second
+
+	conn, err := grpc.Dial(grpcAddr,
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: backoffCfg, MinConnectTimeout: apiHeimdallTimeout}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(int(16*datasize.MB))),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating client connection to heimdall grpc %s: %w", grpcAddr, err)
+	}
+
+	return newGrpcClient(conn, httpClient), nil
+}
+
+func newGrpcClient(conn *grpc.ClientConn, httpClient *Client) *GrpcClient {
+	return &GrpcClient{
+		Client: httpClient,
+		conn:   conn,
+		client: heimdallproto.NewHeimdallClient(conn),
+	}
+}
+
+func (c *GrpcClient) FetchStateSyncEvents(ctx context.Context, fromID uint64, to time.Time, limit int) ([]*EventRecordWithTime, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiHeimdallTimeout)
+	defer cancel()
+
+	// heimdall streams all the events till `to`, in pages of stateFetchLimit like its http api returns them
+	stream, err := c.client.StateSyncEvents(ctx, &heimdallproto.StateSyncEventsRequest{
+		FromID: fromID,
+		ToTime: uint64(to.Unix()),
+		Limit:  stateFetchLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventRecords := make([]*EventRecordWithTime, 0)
+	for limit <= 0 || len(eventRecords) < limit {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range response.Result {
+			eventRecord, err := eventRecordFromProto(event)
+			if err != nil {
+				return nil, err
+			}
+			eventRecords = append(eventRecords, eventRecord)
+		}
+	}
+
+	sort.SliceStable(eventRecords, func(i, j int) bool {
+		return eventRecords[i].ID < eventRecords[j].ID
+	})
+
+	return eventRecords, nil
+}
+
+func (c *GrpcClient) FetchSpan(ctx context.Context, spanID uint64) (*Span, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiHeimdallTimeout)
+	defer cancel()
+
+	response, err := c.client.Span(ctx, &heimdallproto.SpanRequest{ID: strconv.FormatUint(spanID, 10)})
+	if err != nil {
+		return nil, err
+	}
+	if response.Result == nil {
+		return nil, fmt.Errorf("%w: span %d", ErrNoResponse, spanID)
+	}
+	return spanFromProto(response.Result), nil
+}
+
+func (c *GrpcClient) Close() {
+	c.Client.Close()
+	if err := c.conn.Close(); err != nil {
+		c.logger.Debug(heimdallLogPrefix("closing grpc connection"), "err", err)
+	}
+}
+
+func eventRecordFromProto(event *heimdallproto.EventRecord) (*EventRecordWithTime, error) {
+	data, err := hexutil.Decode(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("state sync event %d data: %w", event.ID, err)
+	}
+
+	return &EventRecordWithTime{
+		EventRecord: EventRecord{
+			ID:       event.ID,
+			Contract: libcommon.HexToAddress(event.Contract),
+			Data:     data,
+			TxHash:   libcommon.HexToHash(event.TxHash),
+			LogIndex: event.LogIndex,
+			ChainID:  event.ChainID,
+		},
+		Time: event.Time.AsTime(),
+	}, nil
+}
+
+func eventRecordToProto(event *EventRecordWithTime) *heimdallproto.EventRecord {
+	return &heimdallproto.EventRecord{
+		ID:       event.ID,
+		Contract: event.Contract.Hex(),
+		Data:     hexutility.Encode(event.Data),
+		TxHash:   event.TxHash.Hex(),
+		LogIndex: event.LogIndex,
+		ChainID:  event.ChainID,
+		Time:     timestamppb.New(event.Time),
+	}
+}
+
+func spanFromProto(span *heimdallproto.Span) *Span {
+	validatorFromProto := func(v *heimdallproto.Validator) *valset.Validator {
+		return &valset.Validator{
+			ID:               v.ID,
+			Address:          gointerfaces.ConvertH160toAddress(v.Address),
+			VotingPower:      v.VotingPower,
+			ProposerPriority: v.ProposerPriority,
+		}
+	}
+
+	result := &Span{
+		Id:         SpanId(span.ID),
+		StartBlock: span.StartBlock,
+		EndBlock:   span.EndBlock,
+		ChainID:    span.ChainID,
+	}
+	if span.ValidatorSet != nil {
+		for _, v := range span.ValidatorSet.Validators {
+			result.ValidatorSet.Validators = append(result.ValidatorSet.Validators, validatorFromProto(v))
+		}
+		if span.ValidatorSet.Proposer != nil {
+			result.ValidatorSet.Proposer = validatorFromProto(span.ValidatorSet.Proposer)
+		}
+	}
+	for _, v := range span.SelectedProducers {
+		result.SelectedProducers = append(result.SelectedProducers, *validatorFromProto(v))
+	}
+	return result
+}
+
+func spanToProto(span *Span) *heimdallproto.Span {
+	validatorToProto := func(v *valset.Validator) *heimdallproto.Validator {
+		return &heimdallproto.Validator{
+			ID:               v.ID,
+			Address:          gointerfaces.ConvertAddressToH160(v.Address),
+			VotingPower:      v.VotingPower,
+			ProposerPriority: v.ProposerPriority,
+		}
+	}
+
+	result := &heimdallproto.Span{
+		ID:           uint64(span.Id),
+		StartBlock:   span.StartBlock,
+		EndBlock:     span.EndBlock,
+		ValidatorSet: &heimdallproto.ValidatorSet{},
+		ChainID:      span.ChainID,
+	}
+	for _, v := range span.ValidatorSet.Validators {
+		result.ValidatorSet.Validators = append(result.ValidatorSet.Validators, validatorToProto(v))
+	}
+	if span.ValidatorSet.Proposer != nil {
+		result.ValidatorSet.Proposer = validatorToProto(span.ValidatorSet.Proposer)
+	}
+	for i := range span.SelectedProducers {
+		result.SelectedProducers = append(result.SelectedProducers, validatorToProto(&span.SelectedProducers[i]))
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package heimdall
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall/heimdallproto"
+	"github.com/ledgerwatch/erigon/turbo/testlog"
+)
+
+// testHeimdallServer - serves recorded events and spans like the gRPC server of Heimdall does
+type testHeimdallServer struct {
+	heimdallproto.UnimplementedHeimdallServer
+	events []*EventRecordWithTime
+	spans  []*Span
+}
+
+func (s *testHeimdallServer) StateSyncEvents(req *heimdallproto.StateSyncEventsRequest, stream heimdallproto.Heimdall_StateSyncEventsServer) error {
+	// pages of req.Limit events, until an empty one
+	for fromID := req.FromID; ; fromID += req.Limit {
+		var page []*heimdallproto.EventRecord
+		for _, event := range s.events {
+			if event.ID >= fromID && event.Time.Unix() < int64(req.ToTime) && uint64(len(page)) < req.Limit {
+				page = append(page, eventRecordToProto(event))
+			}
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := stream.Send(&heimdallproto.StateSyncEventsResponse{Height: 1, Result: page}); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *testHeimdallServer) Span(_ context.Context, req *heimdallproto.SpanRequest) (*heimdallproto.SpanResponse, error) {
+	id, err := strconv.ParseUint(req.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &heimdallproto.SpanResponse{Height: 1, Result: spanToProto(s.spans[id])}, nil
+}
+
+func newTestGrpcClient(t *testing.T, server *testHeimdallServer) *GrpcClient {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	heimdallproto.RegisterHeimdallServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	logger := testlog.Logger(t, log.LvlDebug)
+	client := newGrpcClient(conn, newHeimdallClient("https://dummyheimdal.com", &http.Client{}, time.Millisecond, 1, logger))
+	t.Cleanup(client.Close)
+	return client
+}
+
+func testEvent(id uint64, recorded time.Time) *EventRecordWithTime {
+	return &EventRecordWithTime{
+		EventRecord: EventRecord{
+			ID:       id,
+			Contract: libcommon.Address{1},
+			Data:     []byte{byte(id)},
+			TxHash:   libcommon.Hash{byte(id)},
+			LogIndex: id,
+			ChainID:  "137",
+		},
+		Time: recorded,
+	}
+}
+
+func TestGrpcClientFetch(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(time.Now().Unix(), 0).UTC()
+	validator := &valset.Validator{ID: 1, Address: libcommon.Address{2}, VotingPower: 10, ProposerPriority: -5}
+	server := &testHeimdallServer{
+		spans: []*Span{
+			{Id: 0, StartBlock: 0, EndBlock: 255},
+			{
+				Id: 1, StartBlock: 256, EndBlock: 6655, ChainID: "137",
+				ValidatorSet:      valset.ValidatorSet{Validators: []*valset.Validator{validator}, Proposer: validator},
+				SelectedProducers: []valset.Validator{*validator},
+			},
+		},
+	}
+	// more than one page
+	for id := uint64(1); id <= stateFetchLimit+10; id++ {
+		server.events = append(server.events, testEvent(id, now))
+	}
+	server.events = append(server.events, testEvent(stateFetchLimit+11, now.Add(time.Minute)))
+	client := newTestGrpcClient(t, server)
+
+	events, err := client.FetchStateSyncEvents(ctx, 2, now.Add(time.Second), 0)
+	require.NoError(t, err)
+	require.Equal(t, server.events[1:stateFetchLimit+10], events)
+
+	events, err = client.FetchStateSyncEvents(ctx, 1, now.Add(time.Hour), 2)
+	require.NoError(t, err)
+	require.Equal(t, server.events[:stateFetchLimit], events)
+
+	span, err := client.FetchSpan(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, server.spans[1], span)
+
+	span, err = client.FetchSpan(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, SpanId(0), span.Id)
+	require.Equal(t, uint64(255), span.EndBlock)
+}
@@ -0,0 +1,4 @@
+package heimdallproto
+
+// types.proto is taken from the interfaces repository vendored by erigon-lib (see `make grpc` there)
+//go:generate protoc --proto_path=. --proto_path=../../../erigon-lib/vendor/github.com/ledgerwatch/interfaces --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative heimdall.proto
@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.2
+// source: heimdall.proto
+
+package heimdallproto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Heimdall_Span_FullMethodName            = "/heimdall.Heimdall/Span"
+	Heimdall_StateSyncEvents_FullMethodName = "/heimdall.Heimdall/StateSyncEvents"
+)
+
+// HeimdallClient is the client API for Heimdall service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HeimdallClient interface {
+	Span(ctx context.Context, in *SpanRequest, opts ...grpc.CallOption) (*SpanResponse, error)
+	// StateSyncEvents - events with id >= fromID recorded before toTime, streamed in pages of limit events.
+	StateSyncEvents(ctx context.Context, in *StateSyncEventsRequest, opts ...grpc.CallOption) (Heimdall_StateSyncEventsClient, error)
+}
+
+type heimdallClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHeimdallClient(cc grpc.ClientConnInterface) HeimdallClient {
+	return &heimdallClient{cc}
+}
+
+func (c *heimdallClient) Span(ctx context.Context, in *SpanRequest, opts ...grpc.CallOption) (*SpanResponse, error) {
+	out := new(SpanResponse)
+	err := c.cc.Invoke(ctx, Heimdall_Span_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heimdallClient) StateSyncEvents(ctx context.Context, in *StateSyncEventsRequest, opts ...grpc.CallOption) (Heimdall_StateSyncEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Heimdall_ServiceDesc.Streams[0], Heimdall_StateSyncEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &heimdallStateSyncEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Heimdall_StateSyncEventsClient interface {
+	Recv() (*StateSyncEventsResponse, error)
+	grpc.ClientStream
+}
+
+type heimdallStateSyncEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *heimdallStateSyncEventsClient) Recv() (*StateSyncEventsResponse, error) {
+	m := new(StateSyncEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// HeimdallServer is the server API for Heimdall service.
+// All implementations must embed UnimplementedHeimdallServer
+// for forward compatibility
+type HeimdallServer interface {
+	Span(context.Context, *SpanRequest) (*SpanResponse, error)
+	// StateSyncEvents - events with id >= fromID recorded before toTime, streamed in pages of limit events.
+	StateSyncEvents(*StateSyncEventsRequest, Heimdall_StateSyncEventsServer) error
+	mustEmbedUnimplementedHeimdallServer()
+}
+
+// UnimplementedHeimdallServer must be embedded to have forward compatible implementations.
+type UnimplementedHeimdallServer struct {
+}
+
+func (UnimplementedHeimdallServer) Span(context.Context, *SpanRequest) (*SpanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Span not implemented")
+}
+func (UnimplementedHeimdallServer) StateSyncEvents(*StateSyncEventsRequest, Heimdall_StateSyncEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StateSyncEvents not implemented")
+}
+func (UnimplementedHeimdallServer) mustEmbedUnimplementedHeimdallServer() {}
+
+// UnsafeHeimdallServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HeimdallServer will
+// result in compilation errors.
+type UnsafeHeimdallServer interface {
+	mustEmbedUnimplementedHeimdallServer()
+}
+
+func RegisterHeimdallServer(s grpc.ServiceRegistrar, srv HeimdallServer) {
+	s.RegisterService(&Heimdall_ServiceDesc, srv)
+}
+
+func _Heimdall_Span_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeimdallServer).Span(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Heimdall_Span_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeimdallServer).Span(ctx, req.(*SpanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Heimdall_StateSyncEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StateSyncEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HeimdallServer).StateSyncEvents(m, &heimdallStateSyncEventsServer{stream})
+}
+
+type Heimdall_StateSyncEventsServer interface {
+	Send(*StateSyncEventsResponse) error
+	grpc.ServerStream
+}
+
+type heimdallStateSyncEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *heimdallStateSyncEventsServer) Send(m *StateSyncEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Heimdall_ServiceDesc is the grpc.ServiceDesc for Heimdall service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Heimdall_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "heimdall.Heimdall",
+	HandlerType: (*HeimdallServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Span",
+			Handler:    _Heimdall_Span_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StateSyncEvents",
+			Handler:       _Heimdall_StateSyncEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "heimdall.proto",
+}
@@ -93,14 +93,24 @@ func (m *Milestone) UnmarshalJSON(b []byte) error {
 	// (example format: 043353d6-d83f-47f8-a38f-f5062e82a6d4 - 0x142987cad41cf7111b2f186da6ab89e460037f7f)
 	dto := struct {
 		WaypointFields
-		RootHash libcommon.Hash `json:"hash"`
-		Id       MilestoneId    `json:"id"`
+		RootHash    libcommon.Hash  `json:"hash"`
+		Id          MilestoneId     `json:"id"`
+		MilestoneId json.RawMessage `json:"milestone_id"`
 	}{}
 
 	if err := json.Unmarshal(b, &dto); err != nil {
 		return err
 	}
 
+	// MarshalJSON (db and snapshot files) stores the id as numeric milestone_id,
+	// heimdall API sends it as "id" with milestone_id being a string
+	if dto.Id == 0 && len(dto.MilestoneId) > 0 {
+		var id MilestoneId
+		if err := json.Unmarshal(dto.MilestoneId, &id); err == nil {
+			dto.Id = id
+		}
+	}
+
 	m.Id = dto.Id
 	m.Fields = dto.WaypointFields
 	m.Fields.RootHash = dto.RootHash
@@ -1,11 +1,24 @@
 package heimdall
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/ledgerwatch/erigon/polygon/heimdall/heimdalltest"
 )
 
 func TestMilestoneJsonMarshall(t *testing.T) {
 	heimdalltest.AssertJsonMarshalUnmarshal(t, makeMilestone(10, 100))
+
+	m := makeMilestone(10, 100)
+	m.Id = 7
+	heimdalltest.AssertJsonMarshalUnmarshal(t, m)
+}
+
+func TestMilestoneJsonUnmarshalHeimdall(t *testing.T) {
+	var m Milestone
+	require.NoError(t, json.Unmarshal([]byte(`{"id":7,"milestone_id":"043353d6-d83f-47f8-a38f-f5062e82a6d4 - 0x142987cad41cf7111b2f186da6ab89e460037f7f","start_block":1,"end_block":2}`), &m))
+	require.Equal(t, MilestoneId(7), m.Id)
 }
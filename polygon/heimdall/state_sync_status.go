@@ -0,0 +1,75 @@
+package heimdall
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+var (
+	stateSyncLastFetchedEventId = metrics.GetOrCreateGauge("heimdall_state_sync_last_fetched_event_id")
+	stateSyncLastEventTime      = metrics.GetOrCreateGauge("heimdall_state_sync_last_event_time")
+	stateSyncFetchDuration      = metrics.GetOrCreateSummary("heimdall_state_sync_fetch_duration")
+	stateSyncFetchErrors        = metrics.GetOrCreateCounter("heimdall_state_sync_fetch_errors")
+	stateSyncReady              = metrics.GetOrCreateGauge("heimdall_state_sync_ready")
+)
+
+// StateSyncStatus - progress of state sync events ingestion from Heimdall, exposed by the diagnostics endpoint
+type StateSyncStatus struct {
+	Ready              bool      `json:"ready"`
+	LastFetchedEventId uint64    `json:"lastFetchedEventId"`
+	LastEventTime      time.Time `json:"lastEventTime"`
+	LastFetchTime      time.Time `json:"lastFetchTime"`
+	LastFetchDuration  string    `json:"lastFetchDuration"`
+	LastError          string    `json:"lastError,omitempty"`
+	LastErrorTime      time.Time `json:"lastErrorTime"`
+	FetchErrors        uint64    `json:"fetchErrors"`
+	ConsecutiveErrors  uint64    `json:"consecutiveErrors"`
+}
+
+var stateSyncStatus struct {
+	mu     sync.Mutex
+	status StateSyncStatus
+}
+
+// RecordStateSyncFetch updates the state sync metrics after events up to lastEventId are fetched
+// and stored. lastEventTime is the time of the last stored event, zero if no events were received.
+func RecordStateSyncFetch(start time.Time, lastEventId uint64, lastEventTime time.Time, err error) {
+	stateSyncFetchDuration.ObserveDuration(start)
+
+	stateSyncStatus.mu.Lock()
+	defer stateSyncStatus.mu.Unlock()
+
+	status := &stateSyncStatus.status
+	status.LastFetchTime = time.Now()
+	status.LastFetchDuration = status.LastFetchTime.Sub(start).String()
+	status.LastFetchedEventId = lastEventId
+	if !lastEventTime.IsZero() {
+		status.LastEventTime = lastEventTime
+		stateSyncLastEventTime.SetInt(int(lastEventTime.Unix()))
+	}
+	stateSyncLastFetchedEventId.SetUint64(lastEventId)
+
+	if err != nil {
+		stateSyncFetchErrors.Inc()
+		status.LastError = err.Error()
+		status.LastErrorTime = status.LastFetchTime
+		status.FetchErrors++
+		status.ConsecutiveErrors++
+		status.Ready = false
+		stateSyncReady.SetInt(0)
+		return
+	}
+
+	status.ConsecutiveErrors = 0
+	status.Ready = true
+	stateSyncReady.SetInt(1)
+}
+
+// GetStateSyncStatus returns a copy of the current state sync ingestion status
+func GetStateSyncStatus() StateSyncStatus {
+	stateSyncStatus.mu.Lock()
+	defer stateSyncStatus.mu.Unlock()
+	return stateSyncStatus.status
+}
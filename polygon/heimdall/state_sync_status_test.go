@@ -0,0 +1,39 @@
+package heimdall
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordStateSyncFetch(t *testing.T) {
+	eventTime := time.Unix(1700000000, 0)
+	RecordStateSyncFetch(time.Now(), 10, eventTime, nil)
+
+	status := GetStateSyncStatus()
+	require.True(t, status.Ready)
+	require.Equal(t, uint64(10), status.LastFetchedEventId)
+	require.Equal(t, eventTime, status.LastEventTime)
+	require.Equal(t, uint64(1), stateSyncReady.GetValueUint64())
+	require.Equal(t, uint64(10), stateSyncLastFetchedEventId.GetValueUint64())
+
+	errorsBefore := stateSyncFetchErrors.GetValueUint64()
+	RecordStateSyncFetch(time.Now(), 10, time.Time{}, errors.New("heimdall unavailable"))
+	RecordStateSyncFetch(time.Now(), 10, time.Time{}, errors.New("heimdall unavailable"))
+
+	status = GetStateSyncStatus()
+	require.False(t, status.Ready)
+	require.Equal(t, "heimdall unavailable", status.LastError)
+	require.Equal(t, uint64(2), status.ConsecutiveErrors)
+	require.Equal(t, eventTime, status.LastEventTime)
+	require.Equal(t, errorsBefore+2, stateSyncFetchErrors.GetValueUint64())
+	require.Zero(t, stateSyncReady.GetValueUint64())
+
+	RecordStateSyncFetch(time.Now(), 12, eventTime.Add(time.Minute), nil)
+	status = GetStateSyncStatus()
+	require.True(t, status.Ready)
+	require.Zero(t, status.ConsecutiveErrors)
+	require.Equal(t, uint64(12), status.LastFetchedEventId)
+}
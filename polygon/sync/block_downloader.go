@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,9 +31,17 @@ const (
 	blockDownloaderEstimatedRamPerWorker = estimate.EstimatedRamPerWorker(1 * datasize.GB)
 )
 
+// ErrDisconnectedWaypoints happens when the headers of consecutive waypoints, or of the lowest waypoint and the
+// local tip, do not link by hash
+var ErrDisconnectedWaypoints = errors.New("waypoints are not connected to the local tip")
+
 type BlockDownloader interface {
 	DownloadBlocksUsingCheckpoints(ctx context.Context, start uint64) (tip *types.Header, err error)
 	DownloadBlocksUsingMilestones(ctx context.Context, start uint64) (tip *types.Header, err error)
+	// DownloadBlocksBackwards anchors on the latest milestone and downloads the headers between it and the local
+	// tip backwards, verifying each waypoint against its root hash and the links between waypoints. Bodies of the
+	// verified headers are then downloaded and inserted in ascending order.
+	DownloadBlocksBackwards(ctx context.Context, tip *types.Header) (newTip *types.Header, err error)
 }
 
 func NewBlockDownloader(
@@ -266,11 +277,379 @@ func (d *blockDownloader) downloadBlocksUsingWaypoints(ctx context.Context, wayp
 	return lastBlock.Header(), nil
 }
 
+func (d *blockDownloader) DownloadBlocksBackwards(ctx context.Context, tip *types.Header) (*types.Header, error) {
+	waypoints, err := d.fetchWaypointsToLatestMilestone(ctx, tip.Number.Uint64()+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(waypoints) == 0 {
+		return nil, nil
+	}
+
+	d.logger.Debug(
+		syncLogPrefix("downloading blocks backwards"),
+		"waypointsLen", len(waypoints),
+		"tip", tip.Number.Uint64(),
+		"anchor", waypoints[len(waypoints)-1].EndBlock().Uint64(),
+	)
+
+	segments, err := d.downloadHeadersBackwards(ctx, tip, waypoints)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	return d.downloadBodies(ctx, segments)
+}
+
+// fetchWaypointsToLatestMilestone returns the waypoints covering [start, latest milestone end]. Heimdall only
+// keeps recent milestones, so after a long offline period the range below the oldest kept milestone is covered
+// by checkpoints. Waypoints may overlap, overlapping blocks are dropped when the headers are linked.
+func (d *blockDownloader) fetchWaypointsToLatestMilestone(ctx context.Context, start uint64) (heimdall.Waypoints, error) {
+	milestones, err := d.heimdall.FetchMilestonesFromBlock(ctx, start)
+	if err != nil && !errors.Is(err, heimdall.ErrIncompleteMilestoneRange) {
+		return nil, err
+	}
+	if len(milestones) == 0 {
+		// start is past the latest milestone, checkpoints are behind milestones so there is nothing to anchor on
+		return nil, nil
+	}
+
+	anchorStart := milestones[0].StartBlock().Uint64()
+	if anchorStart <= start {
+		return milestones, nil
+	}
+
+	checkpoints, err := d.heimdall.FetchCheckpointsFromBlock(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	waypoints := make(heimdall.Waypoints, 0, len(checkpoints)+len(milestones))
+	for _, checkpoint := range checkpoints {
+		if checkpoint.StartBlock().Uint64() >= anchorStart {
+			break
+		}
+
+		waypoints = append(waypoints, checkpoint)
+	}
+
+	if len(waypoints) == 0 || waypoints[len(waypoints)-1].EndBlock().Uint64()+1 < anchorStart {
+		return nil, fmt.Errorf(
+			"%w: no checkpoints cover blocks from %d to oldest milestone start %d",
+			ErrDisconnectedWaypoints, start, anchorStart,
+		)
+	}
+
+	return append(waypoints, milestones...), nil
+}
+
+// waypointHeaders are the verified headers of a waypoint, trimmed to the blocks which are neither known locally
+// nor covered by the waypoint above it
+type waypointHeaders struct {
+	waypoint heimdall.Waypoint
+	headers  []*types.Header
+}
+
+// downloadHeadersBackwards fetches the headers of waypoints in parallel batches starting from the latest one and
+// links them by hash down to the local tip. The returned segments are in ascending order.
+func (d *blockDownloader) downloadHeadersBackwards(
+	ctx context.Context,
+	tip *types.Header,
+	waypoints heimdall.Waypoints,
+) ([]waypointHeaders, error) {
+	start := tip.Number.Uint64() + 1
+	anchorEnd := waypoints[len(waypoints)-1].EndBlock().Uint64()
+
+	progressLogTicker := time.NewTicker(30 * time.Second)
+	defer progressLogTicker.Stop()
+
+	// segments are accumulated in descending order, next and parentHash are the number and parent hash
+	// of the lowest header linked so far
+	segments := make([]waypointHeaders, 0, len(waypoints))
+	next := uint64(math.MaxUint64)
+	var parentHash common.Hash
+
+	for len(waypoints) > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			// carry-on
+		}
+
+		endBlockNum := waypoints[len(waypoints)-1].EndBlock().Uint64()
+		peers := d.p2pService.ListPeersMayHaveBlockNum(endBlockNum)
+		if len(peers) == 0 {
+			d.logger.Warn(
+				syncLogPrefix("can't use any peers to download headers, will try again in a bit"),
+				"start", waypoints[0].StartBlock(),
+				"end", endBlockNum,
+				"sleepSeconds", d.notEnoughPeersBackOffDuration.Seconds(),
+			)
+
+			time.Sleep(d.notEnoughPeersBackOffDuration)
+			continue
+		}
+
+		numWorkers := cmp.Min(cmp.Min(d.maxWorkers, len(peers)), len(waypoints))
+		waypointsBatch := waypoints[len(waypoints)-numWorkers:]
+
+		select {
+		case <-progressLogTicker.C:
+			d.logger.Info(
+				syncLogPrefix("downloading headers backwards progress"),
+				"waypointsBatchLength", len(waypointsBatch),
+				"startBlockNum", waypointsBatch[0].StartBlock(),
+				"endBlockNum", waypointsBatch[len(waypointsBatch)-1].EndBlock(),
+				"tip", tip.Number.Uint64(),
+				"anchor", anchorEnd,
+				"peerCount", len(peers),
+			)
+		default:
+			// carry on
+		}
+
+		headerBatches := make([][]*types.Header, len(waypointsBatch))
+		wg := sync.WaitGroup{}
+		for i, waypoint := range waypointsBatch {
+			wg.Add(1)
+			go func(i int, waypoint heimdall.Waypoint, peerId *p2p.PeerId) {
+				defer wg.Done()
+
+				headers, _, err := d.fetchVerifiedHeaders(ctx, waypoint, peerId)
+				if err != nil {
+					d.logger.Debug(
+						syncLogPrefix("issue downloading waypoint headers - will try again"),
+						"err", err,
+						"start", waypoint.StartBlock(),
+						"end", waypoint.EndBlock(),
+						"rootHash", waypoint.RootHash(),
+						"kind", reflect.TypeOf(waypoint),
+						"peerId", peerId,
+					)
+
+					return
+				}
+
+				headerBatches[i] = headers
+			}(i, waypoint, peers[i])
+		}
+
+		wg.Wait()
+
+		// link from the latest waypoint of the batch down, waypoints below a gap are fetched again
+		i := len(headerBatches) - 1
+		for ; i >= 0; i-- {
+			headers := headerBatches[i]
+			if len(headers) == 0 {
+				d.logger.Debug(
+					syncLogPrefix("no headers - will try again"),
+					"start", waypointsBatch[i].StartBlock(),
+					"end", waypointsBatch[i].EndBlock(),
+					"rootHash", waypointsBatch[i].RootHash(),
+					"kind", reflect.TypeOf(waypointsBatch[i]),
+				)
+
+				break
+			}
+
+			from := sort.Search(len(headers), func(j int) bool { return headers[j].Number.Uint64() >= start })
+			to := sort.Search(len(headers), func(j int) bool { return headers[j].Number.Uint64() >= next })
+			headers = headers[from:to]
+			if len(headers) == 0 {
+				continue
+			}
+
+			last := headers[len(headers)-1]
+			if len(segments) > 0 && (last.Number.Uint64()+1 != next || last.Hash() != parentHash) {
+				return nil, fmt.Errorf(
+					"%w: header %d (%s) of waypoint %s is not the parent of header %d",
+					ErrDisconnectedWaypoints, last.Number.Uint64(), last.Hash(), waypointsBatch[i].RootHash(), next,
+				)
+			}
+
+			segments = append(segments, waypointHeaders{waypoint: waypointsBatch[i], headers: headers})
+			next = headers[0].Number.Uint64()
+			parentHash = headers[0].ParentHash
+		}
+
+		waypoints = waypoints[:len(waypoints)-len(waypointsBatch)+i+1]
+	}
+
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	if next != start || parentHash != tip.Hash() {
+		return nil, fmt.Errorf(
+			"%w: lowest header %d has parent %s, local tip is %d (%s)",
+			ErrDisconnectedWaypoints, next, parentHash, tip.Number.Uint64(), tip.Hash(),
+		)
+	}
+
+	slices.Reverse(segments)
+
+	d.logger.Debug(syncLogPrefix("linked headers backwards to the local tip"), "tip", tip.Number.Uint64(), "anchor", anchorEnd)
+
+	return segments, nil
+}
+
+// downloadBodies fetches the bodies of verified headers in parallel batches and inserts the blocks in ascending order
+func (d *blockDownloader) downloadBodies(ctx context.Context, segments []waypointHeaders) (*types.Header, error) {
+	// waypoint rootHash->[blocks part of waypoint]
+	waypointBlocksMemo, err := lru.New[common.Hash, []*types.Block](d.p2pService.MaxPeers())
+	if err != nil {
+		return nil, err
+	}
+
+	progressLogTicker := time.NewTicker(30 * time.Second)
+	defer progressLogTicker.Stop()
+
+	var lastBlock *types.Block
+	fetchStartTime := time.Now()
+	var blockCount, blocksTotalSize atomic.Uint64
+
+	for len(segments) > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			// carry-on
+		}
+
+		lastHeaders := segments[len(segments)-1].headers
+		endBlockNum := lastHeaders[len(lastHeaders)-1].Number.Uint64()
+		peers := d.p2pService.ListPeersMayHaveBlockNum(endBlockNum)
+		if len(peers) == 0 {
+			d.logger.Warn(
+				syncLogPrefix("can't use any peers to download bodies, will try again in a bit"),
+				"start", segments[0].headers[0].Number.Uint64(),
+				"end", endBlockNum,
+				"sleepSeconds", d.notEnoughPeersBackOffDuration.Seconds(),
+			)
+
+			time.Sleep(d.notEnoughPeersBackOffDuration)
+			continue
+		}
+
+		numWorkers := cmp.Min(cmp.Min(d.maxWorkers, len(peers)), len(segments))
+		segmentsBatch := segments[:numWorkers]
+
+		select {
+		case <-progressLogTicker.C:
+			d.logger.Info(
+				syncLogPrefix("downloading bodies progress"),
+				"segmentsBatchLength", len(segmentsBatch),
+				"startBlockNum", segmentsBatch[0].headers[0].Number.Uint64(),
+				"endBlockNum", endBlockNum,
+				"peerCount", len(peers),
+				"maxWorkers", d.maxWorkers,
+				"blk/s", fmt.Sprintf("%.2f", float64(blockCount.Load())/time.Since(fetchStartTime).Seconds()),
+				"bytes/s", fmt.Sprintf("%s", common.ByteCount(uint64(float64(blocksTotalSize.Load())/time.Since(fetchStartTime).Seconds()))),
+			)
+
+			blockCount.Store(0)
+			blocksTotalSize.Store(0)
+			fetchStartTime = time.Now()
+
+		default:
+			// carry on
+		}
+
+		blockBatches := make([][]*types.Block, len(segmentsBatch))
+		wg := sync.WaitGroup{}
+		for i, segment := range segmentsBatch {
+			wg.Add(1)
+			go func(i int, segment waypointHeaders, peerId *p2p.PeerId) {
+				defer wg.Done()
+
+				if blocks, ok := waypointBlocksMemo.Get(segment.waypoint.RootHash()); ok {
+					blockBatches[i] = blocks
+					return
+				}
+
+				blocks, totalSize, err := d.fetchVerifiedBodies(ctx, segment.headers, peerId)
+				if err != nil {
+					d.logger.Debug(
+						syncLogPrefix("issue downloading waypoint bodies - will try again"),
+						"err", err,
+						"start", segment.headers[0].Number.Uint64(),
+						"end", segment.headers[len(segment.headers)-1].Number.Uint64(),
+						"rootHash", segment.waypoint.RootHash(),
+						"peerId", peerId,
+					)
+
+					return
+				}
+
+				blocksTotalSize.Add(uint64(totalSize))
+				blockCount.Add(uint64(len(blocks)))
+
+				waypointBlocksMemo.Add(segment.waypoint.RootHash(), blocks)
+				blockBatches[i] = blocks
+			}(i, segment, peers[i])
+		}
+
+		wg.Wait()
+		blocks := make([]*types.Block, 0, len(blockBatches))
+		gapIndex := -1
+		for i, blockBatch := range blockBatches {
+			if len(blockBatch) == 0 {
+				gapIndex = i
+				break
+			}
+
+			blocks = append(blocks, blockBatch...)
+		}
+
+		if gapIndex >= 0 {
+			segments = segments[gapIndex:]
+		} else {
+			segments = segments[len(segmentsBatch):]
+		}
+
+		if len(blocks) == 0 {
+			continue
+		}
+
+		if err := d.store.InsertBlocks(ctx, blocks); err != nil {
+			return nil, err
+		}
+
+		lastBlock = blocks[len(blocks)-1]
+	}
+
+	d.logger.Debug(syncLogPrefix("finished downloading blocks backwards"), "tip", lastBlock.NumberU64())
+
+	return lastBlock.Header(), nil
+}
+
 func (d *blockDownloader) fetchVerifiedBlocks(
 	ctx context.Context,
 	waypoint heimdall.Waypoint,
 	peerId *p2p.PeerId,
 ) ([]*types.Block, int, error) {
+	headers, headersSize, err := d.fetchVerifiedHeaders(ctx, waypoint, peerId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	blocks, bodiesSize, err := d.fetchVerifiedBodies(ctx, headers, peerId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return blocks, headersSize + bodiesSize, nil
+}
+
+func (d *blockDownloader) fetchVerifiedHeaders(
+	ctx context.Context,
+	waypoint heimdall.Waypoint,
+	peerId *p2p.PeerId,
+) ([]*types.Header, int, error) {
 	// 1. Fetch headers in waypoint from a peer
 	start := waypoint.StartBlock().Uint64()
 	end := waypoint.EndBlock().Uint64() + 1 // waypoint end is inclusive, fetch headers is [start, end)
@@ -290,8 +669,16 @@ func (d *blockDownloader) fetchVerifiedBlocks(
 		return nil, 0, err
 	}
 
-	// 3. Fetch bodies for the verified waypoint headers
-	bodies, err := d.p2pService.FetchBodies(ctx, headers.Data, peerId)
+	return headers.Data, headers.TotalSize, nil
+}
+
+func (d *blockDownloader) fetchVerifiedBodies(
+	ctx context.Context,
+	headers []*types.Header,
+	peerId *p2p.PeerId,
+) ([]*types.Block, int, error) {
+	// 1. Fetch bodies for the verified waypoint headers
+	bodies, err := d.p2pService.FetchBodies(ctx, headers, peerId)
 	if err != nil {
 		if errors.Is(err, &p2p.ErrMissingBodies{}) {
 			d.logger.Debug(syncLogPrefix("penalizing peer - missing bodies"), "peerId", peerId, "err", err)
@@ -304,13 +691,13 @@ func (d *blockDownloader) fetchVerifiedBlocks(
 		return nil, 0, err
 	}
 
-	// 4. Assemble blocks
-	blocks := make([]*types.Block, len(headers.Data))
-	for i, header := range headers.Data {
+	// 2. Assemble blocks
+	blocks := make([]*types.Block, len(headers))
+	for i, header := range headers {
 		blocks[i] = types.NewBlockFromNetwork(header, bodies.Data[i])
 	}
 
-	// 5. Verify blocks
+	// 3. Verify blocks
 	if err = d.blocksVerifier(blocks); err != nil {
 		d.logger.Debug(syncLogPrefix("penalizing peer - invalid blocks"), "peerId", peerId, "err", err)
 
@@ -321,5 +708,5 @@ func (d *blockDownloader) fetchVerifiedBlocks(
 		return nil, 0, err
 	}
 
-	return blocks, headers.TotalSize + bodies.TotalSize, nil
+	return blocks, bodies.TotalSize, nil
 }
@@ -539,3 +539,143 @@ func TestBlockDownloaderDownloadBlocksRespectsMaxWorkers(t *testing.T) {
 	require.Len(t, blocksBatch1, 1)
 	require.Len(t, blocksBatch2, 1)
 }
+
+func (hdt blockDownloaderTest) fakeChain(length int) []*types.Header {
+	chain := make([]*types.Header, length+1)
+	for num := range chain {
+		chain[num] = &types.Header{
+			Number: big.NewInt(int64(num)),
+		}
+		if num > 0 {
+			chain[num].ParentHash = chain[num-1].Hash()
+		}
+	}
+
+	return chain
+}
+
+func (hdt blockDownloaderTest) fetchHeadersFromChainMock(chain []*types.Header) fetchHeadersMock {
+	return func(ctx context.Context, start uint64, end uint64, _ *p2p.PeerId) (p2p.FetcherResponse[[]*types.Header], error) {
+		res := chain[start:end]
+		size := 0
+		for _, header := range res {
+			size += header.EncodingSize()
+		}
+
+		return p2p.FetcherResponse[[]*types.Header]{Data: res, TotalSize: size}, nil
+	}
+}
+
+func fakeWaypointFields(start, end int64) heimdall.WaypointFields {
+	return heimdall.WaypointFields{
+		StartBlock: big.NewInt(start),
+		EndBlock:   big.NewInt(end),
+		RootHash:   common.BytesToHash([]byte(fmt.Sprintf("0x%d-%d", start, end))),
+	}
+}
+
+func TestBlockDownloaderDownloadBlocksBackwards(t *testing.T) {
+	test := newBlockDownloaderTest(t)
+	chain := test.fakeChain(12)
+	// milestones before block 7 have been evicted from heimdall, checkpoints cover the rest and overlap them
+	test.heimdall.EXPECT().
+		FetchMilestonesFromBlock(gomock.Any(), uint64(3)).
+		Return(heimdall.Waypoints{
+			&heimdall.Milestone{Fields: fakeWaypointFields(7, 9)},
+			&heimdall.Milestone{Fields: fakeWaypointFields(10, 12)},
+		}, heimdall.ErrIncompleteMilestoneRange).
+		Times(1)
+	test.heimdall.EXPECT().
+		FetchCheckpointsFromBlock(gomock.Any(), uint64(3)).
+		Return(heimdall.Waypoints{
+			&heimdall.Checkpoint{Fields: fakeWaypointFields(1, 4)},
+			&heimdall.Checkpoint{Fields: fakeWaypointFields(5, 8)},
+		}, nil).
+		Times(1)
+	test.p2pService.EXPECT().
+		ListPeersMayHaveBlockNum(gomock.Any()).
+		Return(test.fakePeers(2)).
+		// headers: milestones (10-12, 7-9), checkpoints (5-8, 1-4), bodies: segments (3-4, 5-6), (7-9, 10-12)
+		Times(4)
+	test.p2pService.EXPECT().
+		FetchHeaders(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(test.fetchHeadersFromChainMock(chain)).
+		Times(4)
+	test.p2pService.EXPECT().
+		FetchBodies(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(test.defaultFetchBodiesMock()).
+		Times(4)
+	var blocks []*types.Block
+	test.store.EXPECT().
+		InsertBlocks(gomock.Any(), gomock.Any()).
+		DoAndReturn(test.defaultInsertBlocksMock(&blocks)).
+		Times(2)
+
+	tip, err := test.blockDownloader.DownloadBlocksBackwards(context.Background(), chain[2])
+	require.NoError(t, err)
+	require.Len(t, blocks, 10)
+	// check blocks are written in order, without the overlap of waypoints and blocks known locally
+	for i, block := range blocks {
+		require.Equal(t, uint64(i+3), block.NumberU64())
+		require.Equal(t, chain[i+3].Hash(), block.Hash())
+	}
+	require.Equal(t, chain[12].Hash(), tip.Hash())
+}
+
+func TestBlockDownloaderDownloadBlocksBackwardsWhenTipIsNotAncestorThenErr(t *testing.T) {
+	test := newBlockDownloaderTest(t)
+	chain := test.fakeChain(8)
+	test.heimdall.EXPECT().
+		FetchMilestonesFromBlock(gomock.Any(), gomock.Any()).
+		Return(heimdall.Waypoints{
+			&heimdall.Milestone{Fields: fakeWaypointFields(1, 4)},
+			&heimdall.Milestone{Fields: fakeWaypointFields(5, 8)},
+		}, nil).
+		Times(1)
+	test.p2pService.EXPECT().
+		ListPeersMayHaveBlockNum(gomock.Any()).
+		Return(test.fakePeers(2)).
+		Times(1)
+	test.p2pService.EXPECT().
+		FetchHeaders(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(test.fetchHeadersFromChainMock(chain)).
+		Times(2)
+	// bodies are never downloaded, satisfy the MaxPeers expectation of the test setup
+	test.p2pService.MaxPeers()
+
+	forkedTip := &types.Header{Number: big.NewInt(2), Extra: []byte("fork")}
+	_, err := test.blockDownloader.DownloadBlocksBackwards(context.Background(), forkedTip)
+	require.ErrorIs(t, err, ErrDisconnectedWaypoints)
+}
+
+func TestBlockDownloaderDownloadBlocksBackwardsWhenWaypointsNotLinkedThenErr(t *testing.T) {
+	test := newBlockDownloaderTest(t)
+	chain := test.fakeChain(8)
+	otherChain := test.fakeChain(8)
+	otherChain[4] = &types.Header{Number: big.NewInt(4), Extra: []byte("fork")}
+	test.heimdall.EXPECT().
+		FetchMilestonesFromBlock(gomock.Any(), gomock.Any()).
+		Return(heimdall.Waypoints{
+			&heimdall.Milestone{Fields: fakeWaypointFields(1, 4)},
+			&heimdall.Milestone{Fields: fakeWaypointFields(5, 8)},
+		}, nil).
+		Times(1)
+	test.p2pService.EXPECT().
+		ListPeersMayHaveBlockNum(gomock.Any()).
+		Return(test.fakePeers(2)).
+		Times(1)
+	test.p2pService.EXPECT().
+		FetchHeaders(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, start uint64, end uint64, peerId *p2p.PeerId) (p2p.FetcherResponse[[]*types.Header], error) {
+			if start == 1 {
+				return test.fetchHeadersFromChainMock(otherChain)(ctx, start, end, peerId)
+			}
+			return test.fetchHeadersFromChainMock(chain)(ctx, start, end, peerId)
+		}).
+		Times(2)
+	// bodies are never downloaded, satisfy the MaxPeers expectation of the test setup
+	test.p2pService.MaxPeers()
+
+	_, err := test.blockDownloader.DownloadBlocksBackwards(context.Background(), chain[0])
+	require.ErrorIs(t, err, ErrDisconnectedWaypoints)
+}
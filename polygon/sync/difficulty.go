@@ -11,7 +11,6 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/polygon/bor"
 	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
-	"github.com/ledgerwatch/erigon/polygon/bor/valset"
 )
 
 type DifficultyCalculator interface {
@@ -20,7 +19,6 @@ type DifficultyCalculator interface {
 
 type difficultyCalculator struct {
 	borConfig           *borcfg.BorConfig
-	spans               *SpansCache
 	validatorSetFactory func(headerNum uint64) validatorSetInterface
 	signaturesCache     *lru.ARCCache[libcommon.Hash, libcommon.Address]
 }
@@ -41,26 +39,17 @@ func NewDifficultyCalculator(
 
 	calc := difficultyCalculator{
 		borConfig:           borConfig,
-		spans:               spans,
 		validatorSetFactory: validatorSetFactory,
 		signaturesCache:     signaturesCache,
 	}
 
 	if validatorSetFactory == nil {
-		calc.validatorSetFactory = calc.makeValidatorSet
+		calc.validatorSetFactory = NewSprintValidatorSets(borConfig, spans).validatorSetFactory()
 	}
 
 	return &calc
 }
 
-func (calc *difficultyCalculator) makeValidatorSet(headerNum uint64) validatorSetInterface {
-	span := calc.spans.SpanAt(headerNum)
-	if span == nil {
-		return nil
-	}
-	return valset.NewValidatorSet(span.ValidatorSet.Validators)
-}
-
 func (calc *difficultyCalculator) HeaderDifficulty(header *types.Header) (uint64, error) {
 	signer, err := bor.Ecrecover(header, calc.signaturesCache, calc.borConfig)
 	if err != nil {
@@ -75,10 +64,5 @@ func (calc *difficultyCalculator) signerDifficulty(signer libcommon.Address, hea
 		return 0, fmt.Errorf("difficultyCalculator.signerDifficulty: no span at %d", headerNum)
 	}
 
-	sprintNum := calc.borConfig.CalculateSprintNumber(headerNum)
-	if sprintNum > 0 {
-		validatorSet.IncrementProposerPriority(int(sprintNum))
-	}
-
 	return validatorSet.Difficulty(signer)
 }
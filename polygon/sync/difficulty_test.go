@@ -55,7 +55,11 @@ func TestSignerDifficulty(t *testing.T) {
 		libcommon.HexToAddress("01"),
 		libcommon.HexToAddress("02"),
 	}
-	validatorSetFactory := func(uint64) validatorSetInterface { return &testValidatorSetInterface{signers: signers} }
+	validatorSetFactory := func(headerNum uint64) validatorSetInterface {
+		validatorSet := &testValidatorSetInterface{signers: signers}
+		validatorSet.IncrementProposerPriority(int(borConfig.CalculateSprintNumber(headerNum)))
+		return validatorSet
+	}
 	calc := NewDifficultyCalculator(&borConfig, nil, validatorSetFactory, nil).(*difficultyCalculator)
 
 	var d uint64
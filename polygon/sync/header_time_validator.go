@@ -11,7 +11,6 @@ import (
 	"github.com/ledgerwatch/erigon/eth/stagedsync"
 	"github.com/ledgerwatch/erigon/polygon/bor"
 	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
-	"github.com/ledgerwatch/erigon/polygon/bor/valset"
 )
 
 type HeaderTimeValidator interface {
@@ -20,7 +19,6 @@ type HeaderTimeValidator interface {
 
 type headerTimeValidator struct {
 	borConfig           *borcfg.BorConfig
-	spans               *SpansCache
 	validatorSetFactory func(headerNum uint64) validatorSetInterface
 	signaturesCache     *lru.ARCCache[libcommon.Hash, libcommon.Address]
 }
@@ -41,26 +39,17 @@ func NewHeaderTimeValidator(
 
 	htv := headerTimeValidator{
 		borConfig:           borConfig,
-		spans:               spans,
 		validatorSetFactory: validatorSetFactory,
 		signaturesCache:     signaturesCache,
 	}
 
 	if validatorSetFactory == nil {
-		htv.validatorSetFactory = htv.makeValidatorSet
+		htv.validatorSetFactory = NewSprintValidatorSets(borConfig, spans).validatorSetFactory()
 	}
 
 	return &htv
 }
 
-func (htv *headerTimeValidator) makeValidatorSet(headerNum uint64) validatorSetInterface {
-	span := htv.spans.SpanAt(headerNum)
-	if span == nil {
-		return nil
-	}
-	return valset.NewValidatorSet(span.ValidatorSet.Validators)
-}
-
 func (htv *headerTimeValidator) ValidateHeaderTime(header *types.Header, now time.Time, parent *types.Header) error {
 	headerNum := header.Number.Uint64()
 	validatorSet := htv.validatorSetFactory(headerNum)
@@ -68,10 +57,5 @@ func (htv *headerTimeValidator) ValidateHeaderTime(header *types.Header, now tim
 		return fmt.Errorf("headerTimeValidator.ValidateHeaderTime: no span at %d", headerNum)
 	}
 
-	sprintNum := htv.borConfig.CalculateSprintNumber(headerNum)
-	if sprintNum > 0 {
-		validatorSet.IncrementProposerPriority(int(sprintNum))
-	}
-
 	return bor.ValidateHeaderTime(header, now, parent, validatorSet, htv.borConfig, htv.signaturesCache)
 }
@@ -69,8 +69,9 @@ func NewService(
 	if err != nil {
 		panic(err)
 	}
-	difficultyCalculator := NewDifficultyCalculator(borConfig, spansCache, nil, signaturesCache)
-	headerTimeValidator := NewHeaderTimeValidator(borConfig, spansCache, nil, signaturesCache)
+	validatorSets := NewSprintValidatorSets(borConfig, spansCache)
+	difficultyCalculator := NewDifficultyCalculator(borConfig, spansCache, validatorSets.validatorSetFactory(), signaturesCache)
+	headerTimeValidator := NewHeaderTimeValidator(borConfig, spansCache, validatorSets.validatorSetFactory(), signaturesCache)
 	headerValidator := NewHeaderValidator(chainConfig, borConfig, headerTimeValidator)
 	ccBuilderFactory := func(root *types.Header, span *heimdall.Span) CanonicalChainBuilder {
 		if span == nil {
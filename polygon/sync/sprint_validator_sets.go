@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+const sprintValidatorSetsLimit = 1024
+
+type sprintValidatorSetKey struct {
+	spanId    heimdall.SpanId
+	sprintNum uint64
+}
+
+// SprintValidatorSets provides validator sets of spans in SpansCache with proposer priorities of the sprint
+// of a header. Deriving a set takes a proposer priority increment per sprint since genesis, so sets are
+// derived once per sprint and shared by all header verifiers. When a sprint is derived, the set of the
+// next sprint is precomputed in the background, to be ready once its headers arrive.
+type SprintValidatorSets struct {
+	borConfig *borcfg.BorConfig
+	spans     *SpansCache
+	cache     *valset.SprintCache[sprintValidatorSetKey]
+}
+
+func NewSprintValidatorSets(borConfig *borcfg.BorConfig, spans *SpansCache) *SprintValidatorSets {
+	return &SprintValidatorSets{
+		borConfig: borConfig,
+		spans:     spans,
+		cache:     valset.NewSprintCache[sprintValidatorSetKey](sprintValidatorSetsLimit),
+	}
+}
+
+// ValidatorSetAt returns the validator set in effect at headerNum or nil if its span is unknown.
+// The returned set is shared and must not be modified.
+func (s *SprintValidatorSets) ValidatorSetAt(headerNum uint64) *valset.ValidatorSet {
+	span := s.spans.SpanAt(headerNum)
+	if span == nil {
+		return nil
+	}
+
+	key := sprintValidatorSetKey{span.Id, s.borConfig.CalculateSprintNumber(headerNum)}
+	if vals, ok := s.cache.Get(key); ok {
+		return vals
+	}
+
+	vals := s.compute(span, key)
+
+	nextHeaderNum := headerNum + s.borConfig.CalculateSprintLength(headerNum)
+	if nextHeaderNum <= span.EndBlock {
+		nextKey := sprintValidatorSetKey{span.Id, s.borConfig.CalculateSprintNumber(nextHeaderNum)}
+		if _, ok := s.cache.Get(nextKey); !ok {
+			go s.compute(span, nextKey)
+		}
+	}
+
+	return vals
+}
+
+func (s *SprintValidatorSets) compute(span *heimdall.Span, key sprintValidatorSetKey) *valset.ValidatorSet {
+	vals := valset.NewValidatorSet(span.ValidatorSet.Validators)
+	if key.sprintNum > 0 {
+		vals.IncrementProposerPriority(int(key.sprintNum))
+	}
+
+	s.cache.Add(key, vals)
+	return vals
+}
+
+func (s *SprintValidatorSets) validatorSetFactory() func(headerNum uint64) validatorSetInterface {
+	return func(headerNum uint64) validatorSetInterface {
+		// avoid a non-nil interface holding a nil set
+		if vals := s.ValidatorSetAt(headerNum); vals != nil {
+			return vals
+		}
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
+	"github.com/ledgerwatch/erigon/polygon/bor/valset"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+func TestSprintValidatorSets(t *testing.T) {
+	borConfig := borcfg.BorConfig{
+		Sprint: map[string]uint64{"0": 16},
+	}
+	validators := []*valset.Validator{
+		valset.NewValidator(libcommon.HexToAddress("00"), 10),
+		valset.NewValidator(libcommon.HexToAddress("01"), 20),
+		valset.NewValidator(libcommon.HexToAddress("02"), 30),
+	}
+	spans := NewSpansCache()
+	spans.Add(&heimdall.Span{
+		Id:           1,
+		StartBlock:   0,
+		EndBlock:     255,
+		ValidatorSet: *valset.NewValidatorSet(validators),
+	})
+	validatorSets := NewSprintValidatorSets(&borConfig, spans)
+
+	for _, headerNum := range []uint64{0, 15, 16, 100, 255} {
+		expected := valset.NewValidatorSet(validators)
+		if sprintNum := borConfig.CalculateSprintNumber(headerNum); sprintNum > 0 {
+			expected.IncrementProposerPriority(int(sprintNum))
+		}
+
+		vals := validatorSets.ValidatorSetAt(headerNum)
+		require.Equal(t, expected.GetProposer().Address, vals.GetProposer().Address, headerNum)
+		for _, signer := range []libcommon.Address{libcommon.HexToAddress("00"), libcommon.HexToAddress("02")} {
+			expectedDifficulty, err := expected.Difficulty(signer)
+			require.NoError(t, err)
+			difficulty, err := vals.Difficulty(signer)
+			require.NoError(t, err)
+			require.Equal(t, expectedDifficulty, difficulty, headerNum)
+		}
+	}
+
+	// headers of a sprint share its set
+	require.Same(t, validatorSets.ValidatorSetAt(16), validatorSets.ValidatorSetAt(31))
+	require.Nil(t, validatorSets.ValidatorSetAt(256))
+	require.Nil(t, validatorSets.validatorSetFactory()(256))
+}
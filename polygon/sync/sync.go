@@ -235,15 +235,9 @@ func (s *Sync) Run(ctx context.Context) error {
 	for tip != prevTip {
 		prevTip = tip
 
-		newTip, err := s.blockDownloader.DownloadBlocksUsingCheckpoints(ctx, tip.Number.Uint64()+1)
-		if err != nil {
-			return err
-		}
-		if newTip != nil {
-			tip = newTip
-		}
-
-		newTip, err = s.blockDownloader.DownloadBlocksUsingMilestones(ctx, tip.Number.Uint64()+1)
+		// anchor on the latest milestone and download backwards to the tip, after a long offline period
+		// milestones evicted by heimdall are covered by checkpoints
+		newTip, err := s.blockDownloader.DownloadBlocksBackwards(ctx, tip)
 		if err != nil {
 			return err
 		}
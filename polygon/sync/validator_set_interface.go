@@ -8,6 +8,5 @@ import (
 // valset.ValidatorSet abstraction for unit tests
 type validatorSetInterface interface {
 	bor.ValidateHeaderTimeSignerSuccessionNumber
-	IncrementProposerPriority(times int)
 	Difficulty(signer libcommon.Address) (uint64, error)
 }
@@ -45,7 +45,12 @@ func TraceBorStateSyncTxnDebugAPI(
 	}
 
 	txCtx := initStateSyncTxContext(blockNum, blockHash)
-	tracer, streaming, cancel, err := transactions.AssembleTracer(ctx, traceConfig, txCtx.TxHash, stream, callTimeout)
+	tracer, streaming, cancel, err := transactions.AssembleTracer(ctx, traceConfig, &tracers.Context{
+		BlockHash:   blockHash,
+		BlockNumber: blockNum,
+		TxIndex:     ibs.TxIndex(),
+		TxHash:      txCtx.TxHash,
+	}, stream, callTimeout)
 	if err != nil {
 		stream.WriteNil()
 		return err
@@ -0,0 +1,203 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/time/rate"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	apiKeyQuery  = "apikey"
+)
+
+// APIKey describes what a client presenting the key (or a JWT with the key name as subject) is allowed to do.
+// Allow entries are either namespaces ("eth") or methods ("eth_call"), empty Allow permits everything.
+// RateLimit is in requests per second (every call of a batch counts), zero means unlimited.
+type APIKey struct {
+	Name      string   `json:"name"`
+	Key       string   `json:"key"`
+	Allow     []string `json:"allow"`
+	RateLimit float64  `json:"rateLimit"`
+	Burst     int      `json:"burst"`
+}
+
+// AuthConfig - content of the --rpc.auth.keys file
+type AuthConfig struct {
+	// JwtSecret - hex encoded HS256 secret, enables `Authorization: Bearer <jwt>` where `sub` claim is a key name
+	JwtSecret string   `json:"jwtSecret"`
+	Keys      []APIKey `json:"keys"`
+}
+
+type apiKey struct {
+	name       string
+	namespaces map[string]struct{}
+	methods    map[string]struct{}
+	limiter    *rate.Limiter
+
+	allowed, denied, limited metrics.Counter
+}
+
+// Authorizer authenticates HTTP and WebSocket clients by API key or JWT and authorizes every call
+// against the permissions and rate limit of the key.
+type Authorizer struct {
+	byKey     map[string]*apiKey
+	byName    map[string]*apiKey
+	jwtSecret []byte
+}
+
+func NewAuthorizer(cfg AuthConfig) (*Authorizer, error) {
+	a := &Authorizer{byKey: map[string]*apiKey{}, byName: map[string]*apiKey{}}
+	if cfg.JwtSecret != "" {
+		a.jwtSecret = libcommon.FromHex(strings.TrimSpace(cfg.JwtSecret))
+		if len(a.jwtSecret) == 0 {
+			return nil, errors.New("rpc auth: invalid jwtSecret")
+		}
+	}
+	for _, k := range cfg.Keys {
+		if k.Name == "" {
+			return nil, errors.New("rpc auth: key without name")
+		}
+		if _, ok := a.byName[k.Name]; ok {
+			return nil, fmt.Errorf("rpc auth: duplicated key name %s", k.Name)
+		}
+		if k.Key == "" && a.jwtSecret == nil {
+			return nil, fmt.Errorf("rpc auth: key %s has neither key nor jwtSecret", k.Name)
+		}
+		if _, ok := a.byKey[k.Key]; ok && k.Key != "" {
+			return nil, fmt.Errorf("rpc auth: key %s is duplicated", k.Name)
+		}
+		if k.RateLimit < 0 || k.Burst < 0 {
+			return nil, fmt.Errorf("rpc auth: key %s has negative rate limit", k.Name)
+		}
+
+		key := &apiKey{
+			name:       k.Name,
+			namespaces: map[string]struct{}{},
+			methods:    map[string]struct{}{},
+			allowed:    metrics.GetOrCreateCounter(fmt.Sprintf(`rpc_auth_requests{key="%s",result="allowed"}`, k.Name)),
+			denied:     metrics.GetOrCreateCounter(fmt.Sprintf(`rpc_auth_requests{key="%s",result="denied"}`, k.Name)),
+			limited:    metrics.GetOrCreateCounter(fmt.Sprintf(`rpc_auth_requests{key="%s",result="limited"}`, k.Name)),
+		}
+		for _, allow := range k.Allow {
+			if strings.Contains(allow, serviceMethodSeparator) {
+				key.methods[allow] = struct{}{}
+			} else {
+				key.namespaces[allow] = struct{}{}
+			}
+		}
+		if k.RateLimit > 0 {
+			burst := k.Burst
+			if burst == 0 {
+				burst = max(1, int(k.RateLimit))
+			}
+			key.limiter = rate.NewLimiter(rate.Limit(k.RateLimit), burst)
+		}
+
+		a.byName[k.Name] = key
+		if k.Key != "" {
+			a.byKey[k.Key] = key
+		}
+	}
+	return a, nil
+}
+
+// authenticate finds the key of request: `X-API-Key` header, `apikey` query parameter or `Authorization: Bearer <jwt>`
+func (a *Authorizer) authenticate(r *http.Request) (*apiKey, error) {
+	if k := r.Header.Get(apiKeyHeader); k != "" {
+		return a.lookup(k)
+	}
+	if k := r.URL.Query().Get(apiKeyQuery); k != "" {
+		return a.lookup(k)
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") && a.jwtSecret != nil {
+		return a.verifyJwt(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return nil, errors.New("missing API key")
+}
+
+func (a *Authorizer) lookup(k string) (*apiKey, error) {
+	if key, ok := a.byKey[k]; ok {
+		return key, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+func (a *Authorizer) verifyJwt(tokenStr string) (*apiKey, error) {
+	claims := jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+		return a.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	key, ok := a.byName[claims.Subject]
+	if !ok {
+		return nil, errors.New("invalid token subject")
+	}
+	return key, nil
+}
+
+// authorize checks the method against permissions and rate limit of the key
+func (k *apiKey) authorize(method string) error {
+	if !k.permits(method) {
+		k.denied.Inc()
+		return &unauthorizedError{method: method}
+	}
+	if k.limiter != nil && !k.limiter.Allow() {
+		k.limited.Inc()
+		return &limitExceededError{}
+	}
+	k.allowed.Inc()
+	return nil
+}
+
+func (k *apiKey) permits(method string) bool {
+	if len(k.namespaces) == 0 && len(k.methods) == 0 {
+		return true
+	}
+	if _, ok := k.methods[method]; ok {
+		return true
+	}
+	namespace, _, _ := strings.Cut(method, serviceMethodSeparator)
+	_, ok := k.namespaces[namespace]
+	return ok
+}
+
+type apiKeyContextKey struct{}
+
+func contextWithAPIKey(ctx context.Context, key *apiKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+func apiKeyFromContext(ctx context.Context) *apiKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*apiKey)
+	return key
+}
+
+// apiKeyCodec carries the key of an authenticated WebSocket connection to its handler
+type apiKeyCodec struct {
+	ServerCodec
+	key *apiKey
+}
+
+// authorizeRequest authenticates request if authorizer is set, on failure it writes 401 and returns false
+func authorizeRequest(a *Authorizer, w http.ResponseWriter, r *http.Request) (*apiKey, bool) {
+	if a == nil {
+		return nil, true
+	}
+	key, err := a.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, false
+	}
+	return key, true
+}
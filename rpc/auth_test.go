@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizer(t *testing.T) {
+	t.Parallel()
+	logger := log.New()
+	srv := newTestServer(logger)
+	defer srv.Stop()
+
+	authorizer, err := NewAuthorizer(AuthConfig{
+		JwtSecret: "0x0102030405060708",
+		Keys: []APIKey{
+			{Name: "full", Key: "a", Allow: []string{"test", "nftest"}},
+			{Name: "echo", Key: "b", Allow: []string{"test_echo"}},
+			{Name: "limited", Key: "c", RateLimit: 1, Burst: 1},
+		},
+	})
+	require.NoError(t, err)
+	srv.SetAuthorizer(authorizer)
+
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	call := func(header, value, body string) (int, string) {
+		req, err := http.NewRequest(http.MethodPost, httpsrv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+	const rets = `{"jsonrpc":"2.0","id":1,"method":"test_rets"}`
+	const echo = `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1,null]}`
+
+	code, _ := call("", "", rets)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = call(apiKeyHeader, "unknown", rets)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code, resp := call(apiKeyHeader, "a", rets)
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, resp, `"result"`)
+
+	// method-level permission
+	_, resp = call(apiKeyHeader, "b", echo)
+	require.Contains(t, resp, `"result"`)
+	_, resp = call(apiKeyHeader, "b", rets)
+	require.Contains(t, resp, `-32003`)
+
+	// every call of a batch is rate limited
+	_, resp = call(apiKeyHeader, "c", "["+rets+","+rets+"]")
+	require.Equal(t, 1, strings.Count(resp, `-32005`))
+
+	// jwt subject is a key name
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "echo",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	require.NoError(t, err)
+	_, resp = call("Authorization", "Bearer "+token, echo)
+	require.Contains(t, resp, `"result"`)
+	code, _ = call("Authorization", "Bearer "+token+"x", echo)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	// websocket connection is authenticated once, calls are authorized by the handler
+	wssrv := httptest.NewServer(srv.WebsocketHandler([]string{"*"}, nil, false, logger))
+	defer wssrv.Close()
+	wsURL := "ws:" + strings.TrimPrefix(wssrv.URL, "http:")
+
+	_, err = DialWebsocket(context.Background(), wsURL, "", logger)
+	require.Error(t, err)
+
+	client, err := DialWebsocket(context.Background(), wsURL+"?"+apiKeyQuery+"=b", "", logger)
+	require.NoError(t, err)
+	defer client.Close()
+	var result echoResult
+	require.NoError(t, client.Call(&result, "test_echo", "x", 1))
+	var s string
+	err = client.Call(&s, "test_rets")
+	require.Error(t, err)
+	require.Equal(t, -32003, err.(Error).ErrorCode())
+}
+
+func TestNewAuthorizerValidation(t *testing.T) {
+	t.Parallel()
+	_, err := NewAuthorizer(AuthConfig{Keys: []APIKey{{Name: "a", Key: "a"}, {Name: "a", Key: "b"}}})
+	require.Error(t, err)
+	_, err = NewAuthorizer(AuthConfig{Keys: []APIKey{{Name: "a", Key: "a"}, {Name: "b", Key: "a"}}})
+	require.Error(t, err)
+	_, err = NewAuthorizer(AuthConfig{Keys: []APIKey{{Name: "a"}}})
+	require.Error(t, err)
+}
@@ -26,6 +26,9 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(unauthorizedError)
+	_ Error = new(limitExceededError)
+	_ Error = new(overloadedError)
 )
 
 const defaultErrorCode = -32000
@@ -81,6 +84,29 @@ func (e *UnsupportedForkError) ErrorCode() int { return -38005 }
 
 func (e *UnsupportedForkError) Error() string { return e.Message }
 
+// the API key of the client is not allowed to call the method
+type unauthorizedError struct{ method string }
+
+func (e *unauthorizedError) ErrorCode() int { return -32003 }
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("the method %s is not allowed for this API key", e.method)
+}
+
+// the API key of the client exceeded its rate limit
+type limitExceededError struct{}
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string { return "rate limit exceeded" }
+
+// the server is overloaded and sheds the call
+type overloadedError struct{}
+
+func (e *overloadedError) ErrorCode() int { return -32005 }
+
+func (e *overloadedError) Error() string { return "server is overloaded, retry later" }
+
 type CustomError struct {
 	Code    int
 	Message string
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -67,6 +68,8 @@ type handler struct {
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
+	responseCache ResponseCache       // nil - responses aren't cached
+	overload      *overloadController // nil - calls are never shed
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -81,6 +84,7 @@ type handler struct {
 type callProc struct {
 	ctx       context.Context
 	notifiers []*Notifier
+	shed      atomic.Int32 // calls rejected by the overload controller
 }
 
 func HandleError(err error, stream *jsoniter.Stream) {
@@ -176,6 +180,9 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	if len(calls) == 0 {
 		return
 	}
+	if h.overload != nil {
+		h.overload.enqueue(len(calls))
+	}
 	// Process calls on a goroutine because they may block indefinitely:
 	h.startCallProc(func(cp *callProc) {
 		// All goroutines will place results right to this array. Because requests order must match reply orders.
@@ -192,6 +199,9 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 					wg.Done()
 					<-boundedConcurrency
 				}()
+				if h.overload != nil {
+					h.overload.dequeue()
+				}
 
 				select {
 				case <-cp.ctx.Done():
@@ -211,6 +221,9 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 			}(i)
 		}
 		wg.Wait()
+		if int(cp.shed.Load()) == len(calls) {
+			respondOverloaded(cp.ctx)
+		}
 		answers := make([]interface{}, 0, len(msgs))
 		for _, answer := range answersWithNils {
 			if answer != nil {
@@ -232,7 +245,13 @@ func (h *handler) handleMsg(msg *jsonrpcMessage, stream *jsoniter.Stream) {
 	if ok := h.handleImmediate(msg); ok {
 		return
 	}
+	if h.overload != nil {
+		h.overload.enqueue(1)
+	}
 	h.startCallProc(func(cp *callProc) {
+		if h.overload != nil {
+			h.overload.dequeue()
+		}
 		needWriteStream := false
 		if stream == nil {
 			stream = jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096)
@@ -240,6 +259,9 @@ func (h *handler) handleMsg(msg *jsonrpcMessage, stream *jsoniter.Stream) {
 		}
 		answer := h.handleCallMsg(cp, msg, stream)
 		h.addSubscriptions(cp.notifiers)
+		if cp.shed.Load() > 0 {
+			respondOverloaded(cp.ctx)
+		}
 		if answer != nil {
 			buffer, _ := json.Marshal(answer)
 			stream.Write(buffer)
@@ -407,6 +429,13 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage, stream *json
 		}
 		return nil
 	case msg.isCall():
+		if h.overload != nil {
+			if err := h.overload.admit(msg.Method); err != nil {
+				ctx.shed.Add(1)
+				return msg.errorResponse(err)
+			}
+			defer h.overload.done(msg.Method, start)
+		}
 		var doSlowLog bool
 		if h.slowLogThreshold > 0 {
 			doSlowLog = h.isRpcMethodNeedsCheck(msg.Method)
@@ -462,6 +491,11 @@ func (h *handler) isMethodAllowedByGranularControl(method string) bool {
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter.Stream) *jsonrpcMessage {
+	if key := apiKeyFromContext(cp.ctx); key != nil && !msg.isUnsubscribe() {
+		if err := key.authorize(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg, stream)
 	}
@@ -479,7 +513,12 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args, stream)
+	var answer *jsonrpcMessage
+	if h.responseCache != nil && callb != h.unsubscribeCb {
+		answer = h.runCachedMethod(cp.ctx, msg, callb, args, stream)
+	} else {
+		answer = h.runMethod(cp.ctx, msg, callb, args, stream)
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
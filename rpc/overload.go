@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+)
+
+var (
+	overloadInFlightGauge = metrics.GetOrCreateGauge("rpc_overload_in_flight")
+	overloadQueuedGauge   = metrics.GetOrCreateGauge("rpc_overload_queued")
+	overloadShedCounter   = metrics.GetOrCreateCounter("rpc_overload_shed")
+)
+
+type overloadLevel int32
+
+const (
+	notOverloaded      overloadLevel = iota
+	overloaded                       // shed first methods are rejected
+	severelyOverloaded               // all but protected methods are rejected
+)
+
+func (l overloadLevel) String() string {
+	switch l {
+	case overloaded:
+		return "overloaded"
+	case severelyOverloaded:
+		return "severely overloaded"
+	default:
+		return "normal"
+	}
+}
+
+// overloadController - graceful degradation of the HTTP server, see rpccfg.OverloadConfig. It tracks calls
+// waiting to be executed, calls being executed and latency of the cheap calls, and rejects the calls of
+// expensive methods first, so that eth_blockNumber and alike stay available during traffic spikes.
+type overloadController struct {
+	cfg       rpccfg.OverloadConfig
+	protected map[string]struct{}
+	logger    log.Logger
+
+	inFlight, queued atomic.Int64
+	latency          latencyWindow
+	level            atomic.Int32 // last observed, to log transitions
+}
+
+func newOverloadController(cfg rpccfg.OverloadConfig, logger log.Logger) *overloadController {
+	c := &overloadController{cfg: cfg, protected: map[string]struct{}{}, logger: logger}
+	for _, m := range rpccfg.OverloadProtectedMethods {
+		c.protected[m] = struct{}{}
+	}
+	return c
+}
+
+func (c *overloadController) enqueue(n int) {
+	overloadQueuedGauge.SetInt(int(c.queued.Add(int64(n))))
+}
+
+func (c *overloadController) dequeue() {
+	overloadQueuedGauge.SetInt(int(c.queued.Add(-1)))
+}
+
+// admit - whether the call may be executed now, an admitted call must be finished by done
+func (c *overloadController) admit(method string) error {
+	level := c.currentLevel()
+	if old := overloadLevel(c.level.Swap(int32(level))); old != level {
+		c.logger.Warn("[rpc] overload level changed", "from", old, "to", level,
+			"inFlight", c.inFlight.Load(), "queued", c.queued.Load(), "latency", c.latency.average())
+	}
+	_, protected := c.protected[method]
+	if !protected && (level == severelyOverloaded || (level == overloaded && c.shedFirst(method))) {
+		overloadShedCounter.Inc()
+		return &overloadedError{}
+	}
+	overloadInFlightGauge.SetInt(int(c.inFlight.Add(1)))
+	return nil
+}
+
+func (c *overloadController) done(method string, start time.Time) {
+	overloadInFlightGauge.SetInt(int(c.inFlight.Add(-1)))
+	// expensive methods are slow anyway, the latency of the others is what the controller protects
+	if !c.shedFirst(method) {
+		c.latency.add(time.Since(start))
+	}
+}
+
+func (c *overloadController) shedFirst(method string) bool {
+	for _, prefix := range c.cfg.ShedMethods {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *overloadController) currentLevel() overloadLevel {
+	level := notOverloaded
+	check := func(value, limit int64) {
+		switch {
+		case limit <= 0:
+		case value > 2*limit:
+			level = severelyOverloaded
+		case value > limit && level < overloaded:
+			level = overloaded
+		}
+	}
+	check(c.inFlight.Load(), int64(c.cfg.MaxInFlight))
+	check(c.queued.Load(), int64(c.cfg.MaxQueued))
+	if c.cfg.MaxLatency > 0 {
+		check(int64(c.latency.average()), int64(c.cfg.MaxLatency))
+	}
+	return level
+}
+
+// respond429 - HTTP status of the request of which all calls were shed
+func (c *overloadController) respond429(w http.ResponseWriter) {
+	if c.cfg.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(c.cfg.RetryAfter.Seconds()))))
+	}
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+const latencyWindowSeconds = 10
+
+// latencyWindow - average duration of the calls finished in the last latencyWindowSeconds, it decays
+// to zero without calls, so that the shedding doesn't stick once the load is gone
+type latencyWindow struct {
+	mu      sync.Mutex
+	buckets [latencyWindowSeconds]struct {
+		second int64
+		sum    time.Duration
+		count  int64
+	}
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	now := time.Now().Unix()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := &w.buckets[now%latencyWindowSeconds]
+	if b.second != now {
+		b.second, b.sum, b.count = now, 0, 0
+	}
+	b.sum += d
+	b.count++
+}
+
+func (w *latencyWindow) average() time.Duration {
+	now := time.Now().Unix()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var sum time.Duration
+	var count int64
+	for _, b := range w.buckets {
+		if now-b.second < latencyWindowSeconds {
+			sum += b.sum
+			count += b.count
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / time.Duration(count)
+}
+
+type overloadResponseContextKey struct{}
+
+// contextWithOverloadResponse - how to answer the HTTP request of which all calls were shed
+func contextWithOverloadResponse(ctx context.Context, respond func()) context.Context {
+	return context.WithValue(ctx, overloadResponseContextKey{}, respond)
+}
+
+func respondOverloaded(ctx context.Context) {
+	if respond, ok := ctx.Value(overloadResponseContextKey{}).(func()); ok {
+		respond()
+	}
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+)
+
+func TestOverloadController(t *testing.T) {
+	t.Parallel()
+	c := newOverloadController(rpccfg.OverloadConfig{MaxInFlight: 2, MaxLatency: time.Second, ShedMethods: []string{"debug_"}}, log.New())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.admit("eth_call"))
+	}
+	// overloaded: the expensive methods are shed first
+	require.Equal(t, overloaded, c.currentLevel())
+	require.IsType(t, &overloadedError{}, c.admit("debug_traceTransaction"))
+	require.NoError(t, c.admit("eth_call"))
+	require.NoError(t, c.admit("eth_call"))
+
+	// twice over: only the protected methods are served
+	require.Equal(t, severelyOverloaded, c.currentLevel())
+	require.IsType(t, &overloadedError{}, c.admit("eth_call"))
+	require.NoError(t, c.admit("eth_blockNumber"))
+
+	for i := 0; i < 6; i++ {
+		c.done("eth_call", time.Now())
+	}
+	require.Equal(t, notOverloaded, c.currentLevel())
+	require.NoError(t, c.admit("debug_traceTransaction"))
+	c.done("debug_traceTransaction", time.Now())
+
+	// slow expensive calls don't count, slow cheap ones do
+	c = newOverloadController(rpccfg.OverloadConfig{MaxLatency: time.Second, ShedMethods: []string{"debug_"}}, log.New())
+	require.NoError(t, c.admit("debug_traceTransaction"))
+	c.done("debug_traceTransaction", time.Now().Add(-time.Minute))
+	require.Equal(t, notOverloaded, c.currentLevel())
+	require.NoError(t, c.admit("eth_call"))
+	c.done("eth_call", time.Now().Add(-1500*time.Millisecond))
+	require.Equal(t, overloaded, c.currentLevel())
+}
+
+func TestOverloadHTTP(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(log.New())
+	defer srv.Stop()
+	srv.SetOverload(rpccfg.OverloadConfig{MaxInFlight: 1, ShedMethods: []string{"test_sleep"}, RetryAfter: 1500 * time.Millisecond})
+
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	call := func(body string) (*http.Response, string) {
+		resp, err := http.Post(httpsrv.URL, contentType, strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, strings.TrimSpace(string(data))
+	}
+
+	const sleep = `{"jsonrpc":"2.0","id":1,"method":"test_sleep","params":[1000000000]}`
+	const echo = `{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",1,null]}`
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _ := call(sleep)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}()
+	}
+	require.Eventually(t, func() bool { return srv.overload.inFlight.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	resp, body := call(sleep)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("Retry-After"))
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"server is overloaded, retry later"}}`, body)
+
+	resp, body = call(echo)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `{"jsonrpc":"2.0","id":2,"result":{"String":"x","Int":1,"Args":null}}`, body)
+
+	// a batch is rejected only if all of its calls are shed
+	resp, _ = call("[" + sleep + "," + echo + "]")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = call("[" + sleep + "," + sleep + "]")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	wg.Wait()
+	resp, _ = call(`{"jsonrpc":"2.0","id":1,"method":"test_sleep","params":[1]}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
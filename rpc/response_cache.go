@@ -0,0 +1,59 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+var (
+	responseCacheHits   = metrics.GetOrCreateCounter("rpc_response_cache_hits")
+	responseCacheMisses = metrics.GetOrCreateCounter("rpc_response_cache_misses")
+)
+
+// ResponseCache - cache of results of idempotent methods, see Server.SetResponseCache
+type ResponseCache interface {
+	// Get returns the cached result of the call. Empty key - the call can't be cached,
+	// otherwise result of the successful call may be added with the key.
+	Get(ctx context.Context, method string, params json.RawMessage) (key string, result json.RawMessage, ok bool)
+	Add(key string, result json.RawMessage)
+}
+
+// runCachedMethod serves the call from the response cache, falls back to runMethod for calls which can't be cached.
+// Results of streamable methods are buffered, so they can be cached.
+func (h *handler) runCachedMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value, stream *jsoniter.Stream) *jsonrpcMessage {
+	key, cached, ok := h.responseCache.Get(ctx, msg.Method, msg.Params)
+	if ok {
+		responseCacheHits.Inc()
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: cached}
+	}
+	if key == "" {
+		return h.runMethod(ctx, msg, callb, args, stream)
+	}
+	responseCacheMisses.Inc()
+
+	var buf bytes.Buffer
+	resultStream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	result, err := callb.call(ctx, msg.Method, args, resultStream)
+	if err != nil {
+		return msg.errorResponse(err)
+	}
+
+	var enc json.RawMessage
+	if callb.streamable {
+		if err := resultStream.Flush(); err != nil {
+			return msg.errorResponse(err)
+		}
+		enc = buf.Bytes()
+	} else if enc, err = json.Marshal(result); err != nil {
+		return msg.errorResponse(err)
+	}
+
+	h.responseCache.Add(key, enc)
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: enc}
+}
@@ -0,0 +1,98 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+type testResponseCache struct {
+	lock    sync.Mutex
+	entries map[string]json.RawMessage
+}
+
+func (c *testResponseCache) Get(_ context.Context, method string, params json.RawMessage) (string, json.RawMessage, bool) {
+	if method != "test_echo" && method != "stream_numbers" {
+		return "", nil, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := method + string(params)
+	result, ok := c.entries[key]
+	return key, result, ok
+}
+
+func (c *testResponseCache) Add(key string, result json.RawMessage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[key] = result
+}
+
+type streamTestService struct {
+	calls int
+}
+
+func (s *streamTestService) Numbers(_ context.Context, n int, stream *jsoniter.Stream) error {
+	s.calls++
+	stream.WriteArrayStart()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteInt(i)
+	}
+	stream.WriteArrayEnd()
+	return nil
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(log.New())
+	defer srv.Stop()
+
+	streamService := new(streamTestService)
+	require.NoError(t, srv.RegisterName("stream", streamService))
+
+	cache := &testResponseCache{entries: map[string]json.RawMessage{}}
+	srv.SetResponseCache(cache)
+
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	call := func(body string) string {
+		resp, err := http.Post(httpsrv.URL, contentType, strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return strings.TrimSpace(string(data))
+	}
+
+	const echo = `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1,null]}`
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":{"String":"x","Int":1,"Args":null}}`, call(echo))
+	require.Len(t, cache.entries, 1)
+
+	// served from the cache
+	cache.entries[`test_echo["x",1,null]`] = json.RawMessage(`"cached"`)
+	require.Equal(t, `{"jsonrpc":"2.0","id":2,"result":"cached"}`, call(`{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["x",1,null]}`))
+
+	// streamed results are cached as well
+	const numbers = `{"jsonrpc":"2.0","id":3,"method":"stream_numbers","params":[3]}`
+	require.Equal(t, `{"jsonrpc":"2.0","id":3,"result":[0,1,2]}`, call(numbers))
+	require.Equal(t, `{"jsonrpc":"2.0","id":3,"result":[0,1,2]}`, call(numbers))
+	require.Equal(t, 1, streamService.calls)
+
+	// errors and not cacheable methods
+	require.Contains(t, call(`{"jsonrpc":"2.0","id":4,"method":"test_returnError"}`), `"error"`)
+	require.Contains(t, call(`{"jsonrpc":"2.0","id":5,"method":"test_echo","params":["x"]}`), `"error"`)
+	require.Len(t, cache.entries, 2)
+}
@@ -45,3 +45,27 @@ var SlowLogBlackList = []string{
 	"erigon_blockNumber", "erigon_getHeaderByNumber", "erigon_getHeaderByHash", "erigon_getBlockByTimestamp",
 	"eth_call",
 }
+
+// OverloadConfig - thresholds of the overload controller of the HTTP server, a zero threshold is disabled.
+// Once any threshold is exceeded the ShedMethods are rejected with HTTP 429, twice over - all but the
+// OverloadProtectedMethods.
+type OverloadConfig struct {
+	MaxInFlight int           // calls being executed
+	MaxQueued   int           // calls received but waiting to be executed, e.g. behind the batch concurrency limit
+	MaxLatency  time.Duration // average duration of the calls which aren't shed first, over the last seconds
+	ShedMethods []string      // prefixes of expensive methods to shed first: "trace_", "debug_", "eth_getLogs"
+	RetryAfter  time.Duration // sent to clients in the Retry-After header
+}
+
+func (c OverloadConfig) Enabled() bool {
+	return c.MaxInFlight > 0 || c.MaxQueued > 0 || c.MaxLatency > 0
+}
+
+var DefaultOverloadShedMethods = []string{"trace_", "debug_"}
+
+const DefaultOverloadRetryAfter = 5 * time.Second
+
+// OverloadProtectedMethods - cheap methods which are served even when the server is overloaded
+var OverloadProtectedMethods = []string{
+	"eth_blockNumber", "eth_getBalance", "eth_chainId", "eth_syncing", "eth_gasPrice", "net_version", "web3_clientVersion",
+}
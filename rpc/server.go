@@ -26,6 +26,8 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 )
 
 const MetadataApi = "rpc"
@@ -47,15 +49,18 @@ const (
 type Server struct {
 	services        serviceRegistry
 	methodAllowList AllowList
+	authorizer      atomic.Pointer[Authorizer]
+	responseCache   ResponseCache
+	overload        *overloadController
 	idgen           func() ID
 	run             int32
 	codecs          mapset.Set // mapset.Set[ServerCodec] requires go 1.20
 
 	batchConcurrency    uint
 	disableStreaming    bool
-	traceRequests       bool // Whether to print requests at INFO level
-	debugSingleRequest  bool // Whether to print requests at INFO level
-	batchLimit          int  // Maximum number of requests in a batch
+	traceRequests       bool         // Whether to print requests at INFO level
+	debugSingleRequest  bool         // Whether to print requests at INFO level
+	batchLimit          atomic.Int64 // Maximum number of requests in a batch
 	logger              log.Logger
 	rpcSlowLogThreshold time.Duration
 }
@@ -76,9 +81,25 @@ func (s *Server) SetAllowList(allowList AllowList) {
 	s.methodAllowList = allowList
 }
 
-// SetBatchLimit sets limit of number of requests in a batch
+// SetAuthorizer enables API key authentication of HTTP and WebSocket clients and per-key authorization of methods.
+// It can be replaced at runtime, WebSocket connections keep the key they were authenticated with.
+func (s *Server) SetAuthorizer(authorizer *Authorizer) {
+	s.authorizer.Store(authorizer)
+}
+
+// SetResponseCache enables caching of results of idempotent methods called over HTTP
+func (s *Server) SetResponseCache(cache ResponseCache) {
+	s.responseCache = cache
+}
+
+// SetOverload enables shedding of the calls over HTTP when the server is overloaded
+func (s *Server) SetOverload(cfg rpccfg.OverloadConfig) {
+	s.overload = newOverloadController(cfg, s.logger)
+}
+
+// SetBatchLimit sets limit of number of requests in a batch, can be changed at runtime
 func (s *Server) SetBatchLimit(limit int) {
-	s.batchLimit = limit
+	s.batchLimit.Store(int64(limit))
 }
 
 // RegisterName creates a service for the given receiver type under the given name. When no
@@ -122,6 +143,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 	h.allowSubscribe = false
+	h.responseCache = s.responseCache
+	h.overload = s.overload
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.ReadBatch()
@@ -132,8 +155,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 		return
 	}
 	if batch {
-		if s.batchLimit > 0 && len(reqs) > s.batchLimit {
-			codec.WriteJSON(ctx, errorMessage(fmt.Errorf("batch limit %d exceeded (can increase by --rpc.batch.limit). Requested batch of size: %d", s.batchLimit, len(reqs))))
+		if batchLimit := int(s.batchLimit.Load()); batchLimit > 0 && len(reqs) > batchLimit {
+			codec.WriteJSON(ctx, errorMessage(fmt.Errorf("batch limit %d exceeded (can increase by --rpc.batch.limit). Requested batch of size: %d", batchLimit, len(reqs))))
 		} else {
 			h.handleBatch(reqs)
 		}
@@ -58,12 +58,19 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 		if jwtSecret != nil && !CheckJwtSecret(w, r, jwtSecret) {
 			return
 		}
+		key, ok := authorizeRequest(s.authorizer.Load(), w, r)
+		if !ok {
+			return
+		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			logger.Warn("WebSocket upgrade failed", "err", err)
 			return
 		}
 		codec := NewWebsocketCodec(conn)
+		if key != nil {
+			codec = &apiKeyCodec{ServerCodec: codec, key: key}
+		}
 		s.ServeCodec(codec, 0)
 	})
 }
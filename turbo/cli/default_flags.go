@@ -26,7 +26,19 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolGlobalQueueFlag,
 	&utils.TxPoolLifetimeFlag,
 	&utils.TxPoolTraceSendersFlag,
+	&utils.TxPoolPrioritySendersFlag,
+	&utils.TxPoolPriorityGasLimitFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolSenderRateLimitFlag,
+	&utils.TxPoolGlobalRateLimitFlag,
+	&utils.TxPoolLocalsLifetimeFlag,
+	&utils.TxPoolLocalsRebroadcastFlag,
+	&utils.TxPoolReplacementPolicyFlag,
+	&utils.TxPoolMaxReplacementsFlag,
+	&utils.TxPoolAAFlag,
+	&utils.TxPoolAAEntryPointFlag,
+	&utils.TxPoolAAMaxOpsFlag,
+	&utils.TxPoolAABundleSizeFlag,
 	&PruneFlag,
 	&PruneBlocksFlag,
 	&PruneHistoryFlag,
@@ -44,6 +56,8 @@ var DefaultFlags = []cli.Flag{
 	&PrivateApiAddr,
 	&PrivateApiRateLimit,
 	&EtlBufferSizeFlag,
+	&EtlCompressionFlag,
+	&EtlMemoryBudgetFlag,
 	&TLSFlag,
 	&TLSCertFlag,
 	&TLSKeyFlag,
@@ -60,6 +74,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.AuthRpcAddr,
 	&utils.AuthRpcPort,
 	&utils.JWTSecretPath,
+	&utils.AuthRpcJournalSizeFlag,
 	&utils.HttpCompressionFlag,
 	&utils.HTTPCORSDomainFlag,
 	&utils.HTTPVirtualHostsFlag,
@@ -75,15 +90,26 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcStreamingDisableFlag,
 	&utils.DBReadConcurrencyFlag,
 	&utils.RpcAccessListFlag,
+	&utils.RpcAuthKeysFlag,
+	&utils.RpcResponseCacheFlag,
+	&utils.RpcOverloadInFlightFlag,
+	&utils.RpcOverloadQueuedFlag,
+	&utils.RpcOverloadLatencyFlag,
+	&utils.RpcOverloadShedFlag,
+	&utils.RpcOverloadRetryAfterFlag,
 	&utils.RpcTraceCompatFlag,
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
 	&utils.RpcReturnDataLimit,
 	&utils.AllowUnprotectedTxs,
 	&utils.RpcMaxGetProofRewindBlockCount,
+	&utils.RpcTraceBlockWorkersFlag,
+	&utils.RpcTraceBlockMemoryFlag,
+	&utils.RpcLogsPageBudgetFlag,
 	&utils.RPCGlobalTxFeeCapFlag,
 	&utils.TxpoolApiAddrFlag,
 	&utils.TraceMaxtracesFlag,
+	&utils.TraceReplayRangeLimitFlag,
 	&HTTPReadTimeoutFlag,
 	&HTTPWriteTimeoutFlag,
 	&HTTPIdleTimeoutFlag,
@@ -96,6 +122,9 @@ var DefaultFlags = []cli.Flag{
 
 	&utils.SnapKeepBlocksFlag,
 	&utils.SnapStopFlag,
+	&utils.SnapIndexWorkersFlag,
+	&utils.SnapMergeIOLimitFlag,
+	&utils.SnapMergeIdleWaitFlag,
 	&utils.DbPageSizeFlag,
 	&utils.DbSizeLimitFlag,
 	&utils.TorrentPortFlag,
@@ -151,6 +180,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.DownloaderVerifyFlag,
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
+	&utils.HeimdallgRPCAddressFlag,
 	&utils.WebSeedsFlag,
 	&utils.WithoutHeimdallFlag,
 	&utils.BorBlockPeriodFlag,
@@ -196,6 +226,15 @@ var DefaultFlags = []cli.Flag{
 	&utils.CaplinBlobBackfillingFlag,
 	&utils.CaplinDisableBlobPruningFlag,
 	&utils.CaplinArchiveFlag,
+	&utils.CaplinMonitorFlag,
+	&utils.CaplinSlasherFlag,
+	&utils.CaplinMevRelayUrlFlag,
+	&utils.CaplinMevMinBidFlag,
+	&utils.CaplinProposerConfigFlag,
+	&utils.CaplinCheckpointSyncUrlFlag,
+	&utils.CaplinAttestationSeenCacheEpochsFlag,
+	&utils.CaplinAttestationSeenCacheEpochSizeFlag,
+	&utils.CaplinPeerDASFlag,
 
 	&utils.TrustedSetupFile,
 	&utils.RPCSlowFlag,
@@ -203,5 +242,9 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolGossipDisableFlag,
 	&SyncLoopBlockLimitFlag,
 	&SyncLoopBreakAfterFlag,
+	&SyncExecCommitEveryFlag,
+	&SyncExecParallelFlag,
+	&SyncExecWitnessBlocksFlag,
+	&SyncExecOtsIndexFlag,
 	&SyncLoopPruneLimitFlag,
 }
@@ -45,6 +45,16 @@ var (
 		Usage: "Buffer size for ETL operations.",
 		Value: etl.BufferOptimalSize.String(),
 	}
+	EtlCompressionFlag = cli.StringFlag{
+		Name:  "etl.compression",
+		Usage: "Compression of ETL spill files: none, lz4, zstd. Saves disk space and IO at the cost of CPU.",
+		Value: etl.DefaultCompression.String(),
+	}
+	EtlMemoryBudgetFlag = cli.StringFlag{
+		Name:  "etl.memoryBudget",
+		Usage: "Limit on RAM held by the buffers of all ETL collectors together, collectors spill to disk earlier when it's exceeded. 0 - unlimited.",
+		Value: "0",
+	}
 	BodyCacheLimitFlag = cli.StringFlag{
 		Name:  "bodies.cache",
 		Usage: "Limit on the cache for block bodies",
@@ -176,6 +186,30 @@ var (
 		Value: 5_000,
 	}
 
+	SyncExecCommitEveryFlag = cli.DurationFlag{
+		Name:  "sync.exec.commit-every",
+		Usage: "Execution stage commits its progress at least this often (e.g. 30s), also between txs of a block, so restart doesn't lose more work than that. 0 - commit only when batch is full",
+		Value: 0,
+	}
+
+	SyncExecParallelFlag = cli.BoolFlag{
+		Name:  "exec.parallel",
+		Usage: "Experimental: execute transactions of block concurrently (optimistic concurrency: conflicting transactions are detected by read/write sets and re-executed in block order). Used only in initial sync",
+		Value: false,
+	}
+
+	SyncExecWitnessBlocksFlag = cli.Uint64Flag{
+		Name:  "exec.witness.blocks",
+		Usage: "Experimental: execution stage emits witnesses (accessed accounts, storage and code plus proofs) of this many last blocks into the ExecutionWitness table, served by debug_executionWitness. 0 - disabled",
+		Value: 0,
+	}
+
+	SyncExecOtsIndexFlag = cli.BoolFlag{
+		Name:  "exec.ots.index",
+		Usage: "Execution stage indexes contract creations, self-destructs and ERC-20/721 holders per address, served by ots2_* methods (enable \"ots2\" in --http.api)",
+		Value: false,
+	}
+
 	UploadLocationFlag = cli.StringFlag{
 		Name:  "upload.location",
 		Usage: "Location to upload snapshot segments to",
@@ -311,6 +345,20 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		}
 		etl.BufferOptimalSize = *size
 	}
+	if ctx.String(EtlCompressionFlag.Name) != "" {
+		compression, err := etl.ParseCompression(ctx.String(EtlCompressionFlag.Name))
+		if err != nil {
+			utils.Fatalf("Invalid etl compression provided: %v", err)
+		}
+		etl.DefaultCompression = compression
+	}
+	if ctx.String(EtlMemoryBudgetFlag.Name) != "" {
+		var budget datasize.ByteSize
+		if err := budget.UnmarshalText([]byte(ctx.String(EtlMemoryBudgetFlag.Name))); err != nil {
+			utils.Fatalf("Invalid etl memory budget provided: %v", err)
+		}
+		etl.DefaultMemoryBudget.SetLimit(budget)
+	}
 
 	if minimal {
 		// Prune them all.
@@ -349,6 +397,14 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		cfg.Sync.LoopBlockLimit = limit
 	}
 
+	if commitEvery := ctx.Duration(SyncExecCommitEveryFlag.Name); commitEvery > 0 {
+		cfg.Sync.ExecCommitEvery = commitEvery
+	}
+
+	cfg.Sync.ExecParallel = ctx.Bool(SyncExecParallelFlag.Name)
+	cfg.Sync.ExecWitnessBlocks = ctx.Uint64(SyncExecWitnessBlocksFlag.Name)
+	cfg.Sync.ExecOtsIndex = ctx.Bool(SyncExecOtsIndexFlag.Name)
+
 	if location := ctx.String(UploadLocationFlag.Name); len(location) > 0 {
 		cfg.Sync.UploadLocation = location
 	}
@@ -521,13 +577,17 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		RpcStreamingDisable:               ctx.Bool(utils.RpcStreamingDisableFlag.Name),
 		DBReadConcurrency:                 ctx.Int(utils.DBReadConcurrencyFlag.Name),
 		RpcAllowListFilePath:              ctx.String(utils.RpcAccessListFlag.Name),
+		RpcAuthKeysFilePath:               ctx.String(utils.RpcAuthKeysFlag.Name),
 		Gascap:                            ctx.Uint64(utils.RpcGasCapFlag.Name),
 		MaxTraces:                         ctx.Uint64(utils.TraceMaxtracesFlag.Name),
+		TraceReplayRangeLimit:             ctx.Uint64(utils.TraceReplayRangeLimitFlag.Name),
 		TraceCompatibility:                ctx.Bool(utils.RpcTraceCompatFlag.Name),
 		BatchLimit:                        ctx.Int(utils.RpcBatchLimit.Name),
 		ReturnDataLimit:                   ctx.Int(utils.RpcReturnDataLimit.Name),
 		AllowUnprotectedTxs:               ctx.Bool(utils.AllowUnprotectedTxs.Name),
 		MaxGetProofRewindBlockCount:       ctx.Int(utils.RpcMaxGetProofRewindBlockCount.Name),
+		TraceBlockWorkers:                 ctx.Int(utils.RpcTraceBlockWorkersFlag.Name),
+		LogsPageBudget:                    ctx.Int(utils.RpcLogsPageBudgetFlag.Name),
 
 		OtsMaxPageSize: ctx.Uint64(utils.OtsSearchMaxCapFlag.Name),
 
@@ -535,6 +595,14 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 
 		StateCache:          kvcache.DefaultCoherentConfig,
 		RPCSlowLogThreshold: ctx.Duration(utils.RPCSlowFlag.Name),
+
+		Overload: rpccfg.OverloadConfig{
+			MaxInFlight: ctx.Int(utils.RpcOverloadInFlightFlag.Name),
+			MaxQueued:   ctx.Int(utils.RpcOverloadQueuedFlag.Name),
+			MaxLatency:  ctx.Duration(utils.RpcOverloadLatencyFlag.Name),
+			ShedMethods: libcommon.CliString2Array(ctx.String(utils.RpcOverloadShedFlag.Name)),
+			RetryAfter:  ctx.Duration(utils.RpcOverloadRetryAfterFlag.Name),
+		},
 	}
 
 	if c.Enabled {
@@ -562,6 +630,16 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		utils.Fatalf("Invalid state.cache value provided")
 	}
 
+	err = c.ResponseCacheSize.UnmarshalText([]byte(ctx.String(utils.RpcResponseCacheFlag.Name)))
+	if err != nil {
+		utils.Fatalf("Invalid %s value provided", utils.RpcResponseCacheFlag.Name)
+	}
+
+	err = c.TraceBlockMemoryBudget.UnmarshalText([]byte(ctx.String(utils.RpcTraceBlockMemoryFlag.Name)))
+	if err != nil {
+		utils.Fatalf("Invalid %s value provided", utils.RpcTraceBlockMemoryFlag.Name)
+	}
+
 	/*
 		rootCmd.PersistentFlags().BoolVar(&cfg.GRPCServerEnabled, "grpc", false, "Enable GRPC server")
 		rootCmd.PersistentFlags().StringVar(&cfg.GRPCListenAddress, "grpc.addr", node.DefaultGRPCHost, "GRPC server listening interface")
@@ -101,6 +101,11 @@ func SetupCobra(cmd *cobra.Command, filePrefix string) log.Logger {
 	flags := cmd.Flags()
 
 	logger := logging.SetupLoggerCmd(filePrefix, cmd)
+	if configFile := flags.Lookup(configFlag.Name); configFile != nil {
+		SetReloadConfigFile(configFile.Value.String(), os.Args[1:])
+	}
+	registerReloadableLogLevels(flags.Lookup(logging.LogVerbosityFlag.Name).Value.String(),
+		flags.Lookup(logging.LogConsoleVerbosityFlag.Name).Value.String(), flags.Lookup(logging.LogDirVerbosityFlag.Name).Value.String())
 
 	traceFile, err := flags.GetString(traceFlag.Name)
 	if err != nil {
@@ -193,6 +198,9 @@ func Setup(ctx *cli.Context, rootLogger bool) (log.Logger, *http.ServeMux, *http
 	RaiseFdLimit()
 
 	logger := logging.SetupLoggerCtx("erigon", ctx, log.LvlInfo, log.LvlInfo, rootLogger)
+	SetReloadConfigFile(ctx.String(configFlag.Name), os.Args[1:])
+	registerReloadableLogLevels(ctx.String(logging.LogVerbosityFlag.Name), ctx.String(logging.LogConsoleVerbosityFlag.Name),
+		ctx.String(logging.LogDirVerbosityFlag.Name))
 
 	if traceFile := ctx.String(traceFlag.Name); traceFile != "" {
 		if err := Handler.StartGoTrace(traceFile); err != nil {
@@ -0,0 +1,137 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/turbo/logging"
+)
+
+// reloadable - flag whose new value can be applied at runtime, by the subsystem which registered it
+type reloadable struct {
+	name  string
+	value string // applied on start or by the last reload
+	apply func(value string) error
+}
+
+// Reloader reapplies a safe subset of flags at runtime, on SIGHUP or admin_reloadConfig: the config file (--config)
+// is read again and the values of reloadable flags in it are applied by the subsystems which registered them.
+// Flags given on the command line are not reloaded - they take precedence over the config file, same as on start.
+// Flags which are missing from the config file keep their values.
+type Reloader struct {
+	mu      sync.Mutex
+	path    string
+	cmdline map[string]struct{}
+	flags   []*reloadable // in order of registration, it's the order of applying
+}
+
+var reloader = &Reloader{}
+
+// SetReloadConfigFile - config file to read reloadable flags from, args - command line of the process
+func SetReloadConfigFile(path string, args []string) {
+	reloader.setConfigFile(path, args)
+}
+
+// RegisterReloadable - apply is called on every reload with the value of the flag in the config file.
+// value is the current value of the flag. If apply fails, the flag keeps its value.
+func RegisterReloadable(name, value string, apply func(value string) error) {
+	reloader.register(name, value, apply)
+}
+
+// ReloadConfig - reads the config file and applies the values of reloadable flags in it, returns the applied ones
+func ReloadConfig(logger log.Logger) (map[string]string, error) {
+	return reloader.Reload(logger)
+}
+
+func (r *Reloader) setConfigFile(path string, args []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.path = path
+	r.cmdline = flagsOnCommandLine(args)
+}
+
+func (r *Reloader) register(name, value string, apply func(value string) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range r.flags {
+		if f.name == name {
+			f.value, f.apply = value, apply
+			return
+		}
+	}
+	r.flags = append(r.flags, &reloadable{name: name, value: value, apply: apply})
+}
+
+func (r *Reloader) Reload(logger log.Logger) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.path == "" {
+		return nil, errors.New("config reload: no config file, set --config")
+	}
+	fileConfig, err := readConfigAsMap(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("config reload: %w", err)
+	}
+
+	applied := map[string]string{}
+	var errs []error
+	for _, f := range r.flags {
+		v, ok := fileConfig[f.name]
+		if !ok {
+			continue
+		}
+		if _, ok := r.cmdline[f.name]; ok {
+			logger.Warn("[config] flag is set on command line, not reloaded", "flag", f.name)
+			continue
+		}
+		value := configValueString(v)
+		if err := f.apply(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%s: %w", f.name, value, err))
+			continue
+		}
+		if value != f.value {
+			logger.Info("[config] flag reloaded", "flag", f.name, "from", f.value, "to", value)
+		}
+		f.value = value
+		applied[f.name] = value
+	}
+	return applied, errors.Join(errs...)
+}
+
+// configValueString - value of the config file in the format of command line, same as cli.SetFlagsFromConfigFile
+func configValueString(v interface{}) string {
+	if reflect.ValueOf(v).Kind() == reflect.Slice {
+		sliceInterface := v.([]interface{})
+		s := make([]string, len(sliceInterface))
+		for i, v := range sliceInterface {
+			s[i] = fmt.Sprintf("%v", v)
+		}
+		return strings.Join(s, ",")
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// flagsOnCommandLine - names of flags given as `--name value`, `--name=value` or `-name`
+func flagsOnCommandLine(args []string) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// registerReloadableLogLevels - console level is set by --log.console.verbosity if both it and --verbosity are given
+func registerReloadableLogLevels(verbosity, consoleVerbosity, dirVerbosity string) {
+	RegisterReloadable(logging.LogVerbosityFlag.Name, verbosity, logging.SetConsoleLevel)
+	RegisterReloadable(logging.LogConsoleVerbosityFlag.Name, consoleVerbosity, logging.SetConsoleLevel)
+	RegisterReloadable(logging.LogDirVerbosityFlag.Name, dirVerbosity, logging.SetDirLevel)
+}
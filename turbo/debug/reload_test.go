@@ -0,0 +1,42 @@
+package debug
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("a: 1\nb: [x, z]\nc: 3\nd: bad\n"), 0o600))
+
+	r := &Reloader{}
+	_, err := r.Reload(log.New())
+	require.Error(t, err)
+
+	r.setConfigFile(path, []string{"--c=2", "--datadir", "/tmp"})
+	var order []string
+	register := func(name string) {
+		r.register(name, "", func(value string) error {
+			if value == "bad" {
+				return errors.New("bad value")
+			}
+			order = append(order, name+"="+value)
+			return nil
+		})
+	}
+	register("b")
+	register("a")
+	register("c") // on command line
+	register("d") // fails to apply
+	register("e") // missing from file
+
+	applied, err := r.Reload(log.New())
+	require.ErrorContains(t, err, "d=bad")
+	require.Equal(t, map[string]string{"a": "1", "b": "x,z"}, applied)
+	require.Equal(t, []string{"b=x,z", "a=1"}, order)
+}
@@ -21,6 +21,8 @@ func ListenSignals(stack io.Closer, logger log.Logger) {
 
 	usr1 := make(chan os.Signal, 1)
 	signal.Notify(usr1, unix.SIGUSR1)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, unix.SIGHUP)
 	for {
 		select {
 		case <-sigc:
@@ -38,6 +40,10 @@ func ListenSignals(stack io.Closer, logger log.Logger) {
 			LoudPanic("boom")
 		case <-usr1:
 			pprof.Lookup("goroutine").WriteTo(os.Stdout, 1)
+		case <-hup:
+			if _, err := ReloadConfig(logger); err != nil {
+				logger.Warn("[config] reload failed", "err", err)
+			}
 		}
 	}
 }
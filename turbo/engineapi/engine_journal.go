@@ -0,0 +1,258 @@
+package engineapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+)
+
+const (
+	engineJournalFile    = "journal.jsonl"
+	engineJournalOldFile = "journal.1.jsonl"
+
+	defaultEngineJournalLimit = 100
+)
+
+// EngineJournalEntry - one Engine API call. Payloads are recorded as short summaries, to keep the journal small.
+type EngineJournalEntry struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Latency  time.Duration   `json:"latency"` // nanoseconds
+}
+
+// EngineJournal - rolling on-disk journal of forkchoiceUpdated/newPayload/getPayload calls, to diagnose missed
+// proposals (slow getPayload, late forkchoiceUpdated with attributes, etc.) after the fact.
+// When the current file reaches half of maxSize it replaces the previous one, so the journal never takes more than maxSize.
+type EngineJournal struct {
+	lock    sync.Mutex
+	dir     string
+	maxSize int64
+	file    *os.File
+	size    int64
+	logger  log.Logger
+}
+
+func OpenEngineJournal(dir string, maxSize datasize.ByteSize, logger log.Logger) (*EngineJournal, error) {
+	if maxSize == 0 {
+		return nil, errors.New("engine journal: size must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	j := &EngineJournal{dir: dir, maxSize: int64(maxSize.Bytes()), logger: logger}
+	if err := j.openFile(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *EngineJournal) openFile() error {
+	f, err := os.OpenFile(filepath.Join(j.dir, engineJournalFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	j.file, j.size = f, st.Size()
+	return nil
+}
+
+func (j *EngineJournal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(j.dir, engineJournalFile), filepath.Join(j.dir, engineJournalOldFile)); err != nil {
+		return err
+	}
+	return j.openFile()
+}
+
+// Record - appends the call to the journal. nil journal is valid and records nothing.
+func (j *EngineJournal) Record(method string, start time.Time, params, response any, callErr error) {
+	if j == nil {
+		return
+	}
+	entry := EngineJournalEntry{Time: start, Method: method, Latency: time.Since(start)}
+	var err error
+	if params != nil {
+		if entry.Params, err = json.Marshal(params); err != nil {
+			j.logger.Warn("[engine journal] failed to encode params", "method", method, "err", err)
+		}
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	} else if response != nil {
+		if entry.Response, err = json.Marshal(response); err != nil {
+			j.logger.Warn("[engine journal] failed to encode response", "method", method, "err", err)
+		}
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		j.logger.Warn("[engine journal] failed to encode entry", "method", method, "err", err)
+		return
+	}
+	line = append(line, '\n')
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.file == nil {
+		return
+	}
+	if j.size+int64(len(line)) > j.maxSize/2 && j.size > 0 {
+		if err := j.rotate(); err != nil {
+			j.logger.Warn("[engine journal] failed to rotate", "err", err)
+			return
+		}
+	}
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	if err != nil {
+		j.logger.Warn("[engine journal] failed to write", "err", err)
+	}
+}
+
+// Entries - returns up to limit most recent entries, oldest first
+func (j *EngineJournal) Entries(limit int) ([]EngineJournalEntry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	var entries []EngineJournalEntry
+	for _, name := range []string{engineJournalOldFile, engineJournalFile} {
+		f, err := os.Open(filepath.Join(j.dir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, int(j.maxSize))
+		for scanner.Scan() {
+			var entry EngineJournalEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				continue // torn write on crash
+			}
+			entries = append(entries, entry)
+			if limit > 0 && len(entries) > 2*limit {
+				entries = append(entries[:0], entries[len(entries)-limit:]...)
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("engine journal: %s: %w", name, err)
+		}
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
+
+func (j *EngineJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// engineMethodName - e.g. engine_newPayloadV3 for Deneb
+func engineMethodName(method string, version clparams.StateVersion) string {
+	return fmt.Sprintf("engine_%sV%d", method, version-clparams.BellatrixVersion+1)
+}
+
+// journalPayload - what is recorded instead of a full execution payload
+type journalPayload struct {
+	BlockNumber  hexutil.Uint64  `json:"blockNumber"`
+	BlockHash    libcommon.Hash  `json:"blockHash"`
+	ParentHash   libcommon.Hash  `json:"parentHash"`
+	Timestamp    hexutil.Uint64  `json:"timestamp"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Transactions int             `json:"transactions"`
+	Withdrawals  int             `json:"withdrawals"`
+	BlobGasUsed  *hexutil.Uint64 `json:"blobGasUsed,omitempty"`
+}
+
+func summarizePayload(payload *engine_types.ExecutionPayload) *journalPayload {
+	if payload == nil {
+		return nil
+	}
+	return &journalPayload{
+		BlockNumber:  payload.BlockNumber,
+		BlockHash:    payload.BlockHash,
+		ParentHash:   payload.ParentHash,
+		Timestamp:    payload.Timestamp,
+		GasUsed:      payload.GasUsed,
+		Transactions: len(payload.Transactions),
+		Withdrawals:  len(payload.Withdrawals),
+		BlobGasUsed:  payload.BlobGasUsed,
+	}
+}
+
+type journalNewPayloadParams struct {
+	Payload               *journalPayload `json:"executionPayload"`
+	ExpectedBlobHashes    int             `json:"expectedBlobHashes"`
+	ParentBeaconBlockRoot *libcommon.Hash `json:"parentBeaconBlockRoot,omitempty"`
+}
+
+type journalGetPayloadResponse struct {
+	Payload               *journalPayload `json:"executionPayload"`
+	BlockValue            *hexutil.Big    `json:"blockValue,omitempty"`
+	Blobs                 int             `json:"blobs"`
+	ShouldOverrideBuilder bool            `json:"shouldOverrideBuilder"`
+}
+
+func summarizeGetPayloadResponse(resp *engine_types.GetPayloadResponse) *journalGetPayloadResponse {
+	if resp == nil {
+		return nil
+	}
+	summary := &journalGetPayloadResponse{
+		Payload:               summarizePayload(resp.ExecutionPayload),
+		BlockValue:            resp.BlockValue,
+		ShouldOverrideBuilder: resp.ShouldOverrideBuilder,
+	}
+	if resp.BlobsBundle != nil {
+		summary.Blobs = len(resp.BlobsBundle.Blobs)
+	}
+	return summary
+}
+
+// EngineJournalAPI - serves the journal as `debug_engineJournal` on the Engine API port
+type EngineJournalAPI struct {
+	journal *EngineJournal
+}
+
+// EngineJournal returns up to limit (default 100) most recent Engine API calls, oldest first
+func (api *EngineJournalAPI) EngineJournal(_ context.Context, limit *int) ([]EngineJournalEntry, error) {
+	l := defaultEngineJournalLimit
+	if limit != nil {
+		l = *limit
+	}
+	return api.journal.Entries(l)
+}
@@ -0,0 +1,55 @@
+package engineapi
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+)
+
+func TestEngineJournal(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	j, err := OpenEngineJournal(dir, 4*datasize.KB, log.New())
+	require.NoError(t, err)
+	defer j.Close()
+
+	for i := 0; i < 100; i++ {
+		j.Record(engineMethodName("getPayload", clparams.DenebVersion), time.Now(), []any{i}, map[string]int{"n": i}, nil)
+	}
+	j.Record(engineMethodName("newPayload", clparams.ElectraVersion), time.Now(), nil, nil, errors.New("boom"))
+
+	var total int64
+	for _, name := range []string{engineJournalFile, engineJournalOldFile} {
+		st, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		total += st.Size()
+	}
+	require.LessOrEqual(t, total, int64(4*datasize.KB))
+
+	entries, err := j.Entries(3)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, "engine_getPayloadV3", entries[0].Method)
+	require.JSONEq(t, `[98]`, string(entries[0].Params))
+	require.JSONEq(t, `{"n":99}`, string(entries[1].Response))
+	require.Equal(t, "engine_newPayloadV4", entries[2].Method)
+	require.Equal(t, "boom", entries[2].Error)
+
+	// journal survives restart
+	require.NoError(t, j.Close())
+	j, err = OpenEngineJournal(dir, 4*datasize.KB, log.New())
+	require.NoError(t, err)
+	defer j.Close()
+	entries, err = j.Entries(0)
+	require.NoError(t, err)
+	require.Greater(t, len(entries), 3)
+	require.Equal(t, "boom", entries[len(entries)-1].Error)
+}
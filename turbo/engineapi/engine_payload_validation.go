@@ -0,0 +1,184 @@
+package engineapi
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/secp256k1"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethutils"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+)
+
+// minTxsPerSendersWorker - smaller blocks don't benefit from more goroutines recovering their senders
+const minTxsPerSendersWorker = 16
+
+// payloadPreValidation - checks of newPayload which don't need the state: block hash (including the transactions root),
+// encoding and blobs of transactions and their signatures. They are independent, so they run concurrently and
+// before EngineServer.lock is taken, but their results are reported in the order of the sequential checks -
+// the response doesn't depend on which of them finished first.
+type payloadPreValidation struct {
+	blockHash    *engine_types.PayloadStatus
+	transactions *engine_types.PayloadStatus
+	blobsErr     error // invalid params, not an invalid payload
+	blobs        *engine_types.PayloadStatus
+	senders      *engine_types.PayloadStatus
+}
+
+func (v *payloadPreValidation) result() (*engine_types.PayloadStatus, error) {
+	switch {
+	case v.blockHash != nil:
+		return v.blockHash, nil
+	case v.transactions != nil:
+		return v.transactions, nil
+	case v.blobsErr != nil:
+		return nil, v.blobsErr
+	case v.blobs != nil:
+		return v.blobs, nil
+	case v.senders != nil:
+		return v.senders, nil
+	}
+	return nil, nil
+}
+
+// preValidatePayload sets the transactions root of the header, decodes txs and recovers their senders.
+// Returns non-nil status if the payload is invalid.
+func (s *EngineServer) preValidatePayload(header *types.Header, blockHash libcommon.Hash, txs [][]byte,
+	expectedBlobHashes []libcommon.Hash, version clparams.StateVersion,
+) ([]types.Transaction, *engine_types.PayloadStatus, error) {
+	signer := types.MakeSigner(s.config, header.Number.Uint64(), header.Time)
+	var blobGasUsed uint64
+	if header.BlobGasUsed != nil {
+		blobGasUsed = *header.BlobGasUsed
+	}
+
+	var v payloadPreValidation
+	var transactions []types.Transaction
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		header.TxHash = types.DeriveSha(types.BinaryTransactions(txs))
+		if header.Hash() != blockHash {
+			s.logger.Error("[NewPayload] invalid block hash", "stated", blockHash, "actual", header.Hash())
+			v.blockHash = &engine_types.PayloadStatus{
+				Status:          engine_types.InvalidStatus,
+				ValidationError: engine_types.NewStringifiedErrorFromString("invalid block hash"),
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		transactions, v.transactions = s.decodePayloadTransactions(txs)
+		if v.transactions != nil {
+			return
+		}
+		var blobsWg sync.WaitGroup
+		if version >= clparams.DenebVersion {
+			blobsWg.Add(1)
+			go func() {
+				defer blobsWg.Done()
+				v.blobs, v.blobsErr = s.validatePayloadBlobs(header.ParentHash, blobGasUsed, expectedBlobHashes, transactions)
+			}()
+		}
+		v.senders = s.recoverPayloadSenders(header.ParentHash, signer, transactions)
+		blobsWg.Wait()
+	}()
+	wg.Wait()
+
+	status, err := v.result()
+	if status != nil || err != nil {
+		return nil, status, err
+	}
+	return transactions, nil, nil
+}
+
+func (s *EngineServer) decodePayloadTransactions(txs [][]byte) ([]types.Transaction, *engine_types.PayloadStatus) {
+	for _, txn := range txs {
+		if types.TypedTransactionMarshalledAsRlpString(txn) {
+			s.logger.Warn("[NewPayload] typed txn marshalled as RLP string", "txn", common.Bytes2Hex(txn))
+			return nil, &engine_types.PayloadStatus{
+				Status:          engine_types.InvalidStatus,
+				ValidationError: engine_types.NewStringifiedErrorFromString("typed txn marshalled as RLP string"),
+			}
+		}
+	}
+
+	transactions, err := types.DecodeTransactions(txs)
+	if err != nil {
+		s.logger.Warn("[NewPayload] failed to decode transactions", "err", err)
+		return nil, &engine_types.PayloadStatus{
+			Status:          engine_types.InvalidStatus,
+			ValidationError: engine_types.NewStringifiedError(err),
+		}
+	}
+	return transactions, nil
+}
+
+func (s *EngineServer) validatePayloadBlobs(parentHash libcommon.Hash, blobGasUsed uint64, expectedBlobHashes []libcommon.Hash,
+	transactions []types.Transaction,
+) (*engine_types.PayloadStatus, error) {
+	err := ethutils.ValidateBlobs(blobGasUsed, s.config.GetMaxBlobGasPerBlock(), s.config.GetMaxBlobsPerBlock(), expectedBlobHashes, &transactions)
+	if errors.Is(err, ethutils.ErrNilBlobHashes) {
+		return nil, &rpc.InvalidParamsError{Message: "nil blob hashes array"}
+	}
+	if errors.Is(err, ethutils.ErrMaxBlobGasUsed) {
+		return s.invalidPayloadStatus(parentHash, "blobs/blobgas exceeds max"), nil
+	}
+	if errors.Is(err, ethutils.ErrMismatchBlobHashes) || errors.Is(err, ethutils.ErrInvalidVersiondHash) {
+		return &engine_types.PayloadStatus{
+			Status:          engine_types.InvalidStatus,
+			ValidationError: engine_types.NewStringifiedErrorFromString(err.Error()),
+		}, nil
+	}
+	return nil, nil
+}
+
+// recoverPayloadSenders recovers senders of transactions by several goroutines and caches them in the transactions.
+func (s *EngineServer) recoverPayloadSenders(parentHash libcommon.Hash, signer *types.Signer, transactions []types.Transaction) *engine_types.PayloadStatus {
+	workers := min(len(transactions)/minTxsPerSendersWorker+1, secp256k1.NumOfContexts())
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			cryptoContext := secp256k1.ContextForThread(worker)
+			// interleaved, so every worker gets the same amount of txs
+			for i := worker; i < len(transactions); i += workers {
+				from, err := signer.SenderWithContext(cryptoContext, transactions[i])
+				if err != nil {
+					errs[worker] = fmt.Errorf("error recovering sender for tx=%x, %w", transactions[i].Hash(), err)
+					return
+				}
+				transactions[i].SetSender(from)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		s.logger.Warn("[NewPayload] invalid transaction signature", "err", err)
+		return s.invalidPayloadStatus(parentHash, err.Error())
+	}
+	return nil
+}
+
+// invalidPayloadStatus - payload is invalid by itself, so its parent is the latest valid block unless it's invalid too
+func (s *EngineServer) invalidPayloadStatus(parentHash libcommon.Hash, validationError string) *engine_types.PayloadStatus {
+	bad, latestValidHash := s.hd.IsBadHeaderPoS(parentHash)
+	if !bad {
+		latestValidHash = parentHash
+	}
+	return &engine_types.PayloadStatus{
+		Status:          engine_types.InvalidStatus,
+		ValidationError: engine_types.NewStringifiedErrorFromString(validationError),
+		LatestValidHash: &latestValidHash,
+	}
+}
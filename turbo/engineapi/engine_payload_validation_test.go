@@ -0,0 +1,82 @@
+package engineapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/turbo/engineapi/engine_types"
+	"github.com/ledgerwatch/erigon/turbo/stages/headerdownload"
+)
+
+func TestPreValidatePayload(t *testing.T) {
+	t.Parallel()
+	logger := log.New()
+	s := &EngineServer{
+		config: params.TestChainConfig,
+		hd:     headerdownload.NewHeaderDownload(16, 16, nil, nil, logger),
+		logger: logger,
+	}
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+
+	var txs types.Transactions
+	for nonce := uint64(0); nonce < 3*minTxsPerSendersWorker; nonce++ {
+		txn := types.NewTransaction(nonce, libcommon.Address{1}, uint256.NewInt(1), 21000, uint256.NewInt(1), nil)
+		signed, err := types.SignTx(txn, *signer, key)
+		require.NoError(t, err)
+		txs = append(txs, signed)
+	}
+
+	// blockHash of the header with the txs, which newPayload builds without the transactions root
+	payload := func(txs types.Transactions) (*types.Header, libcommon.Hash, [][]byte) {
+		rawTxs, err := types.MarshalTransactionsBinary(txs)
+		require.NoError(t, err)
+		header := &types.Header{ParentHash: libcommon.Hash{2}, Number: big.NewInt(10), Time: 100, BaseFee: big.NewInt(1)}
+		withRoot := types.CopyHeader(header)
+		withRoot.TxHash = types.DeriveSha(txs)
+		return header, withRoot.Hash(), rawTxs
+	}
+
+	header, blockHash, rawTxs := payload(txs)
+	transactions, status, err := s.preValidatePayload(header, blockHash, rawTxs, nil, clparams.CapellaVersion)
+	require.NoError(t, err)
+	require.Nil(t, status)
+	require.Len(t, transactions, len(txs))
+	for _, txn := range transactions {
+		from, ok := txn.GetSender()
+		require.True(t, ok)
+		require.Equal(t, sender, from)
+	}
+
+	header, _, rawTxs = payload(txs)
+	_, status, err = s.preValidatePayload(header, libcommon.Hash{3}, rawTxs, nil, clparams.CapellaVersion)
+	require.NoError(t, err)
+	require.Equal(t, engine_types.InvalidStatus, status.Status)
+	require.Equal(t, "invalid block hash", status.ValidationError.Error().Error())
+
+	bad := txs[len(txs)-1].(*types.LegacyTx)
+	bad.R.Clear()
+	header, blockHash, rawTxs = payload(txs)
+	_, status, err = s.preValidatePayload(header, blockHash, rawTxs, nil, clparams.CapellaVersion)
+	require.NoError(t, err)
+	require.Equal(t, engine_types.InvalidStatus, status.Status)
+	require.Equal(t, header.ParentHash, *status.LatestValidHash)
+
+	// invalid block hash is reported before invalid signature, whichever of them is found first
+	header, _, rawTxs = payload(txs)
+	_, status, err = s.preValidatePayload(header, libcommon.Hash{3}, rawTxs, nil, clparams.CapellaVersion)
+	require.NoError(t, err)
+	require.Equal(t, "invalid block hash", status.ValidationError.Error().Error())
+	require.Nil(t, status.LatestValidHash)
+}
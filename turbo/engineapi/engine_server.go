@@ -11,7 +11,6 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon/cl/clparams"
-	"github.com/ledgerwatch/erigon/eth/ethutils"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -26,7 +25,6 @@ import (
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
-	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/merge"
@@ -58,13 +56,14 @@ type EngineServer struct {
 	chainRW eth1_chain_reader.ChainReaderWriterEth1
 	lock    sync.Mutex
 	logger  log.Logger
+	journal *EngineJournal // nil if disabled
 }
 
 const fcuTimeout = 1000 // according to mathematics: 1000 millisecods = 1 second
 
 func NewEngineServer(logger log.Logger, config *chain.Config, executionService execution.ExecutionClient,
 	hd *headerdownload.HeaderDownload,
-	blockDownloader *engine_block_downloader.EngineBlockDownloader, caplin, test, proposing bool) *EngineServer {
+	blockDownloader *engine_block_downloader.EngineBlockDownloader, journal *EngineJournal, caplin, test, proposing bool) *EngineServer {
 	chainRW := eth1_chain_reader.NewChainReaderEth1(config, executionService, fcuTimeout)
 	return &EngineServer{
 		logger:           logger,
@@ -75,6 +74,7 @@ func NewEngineServer(logger log.Logger, config *chain.Config, executionService e
 		proposing:        proposing,
 		hd:               hd,
 		caplin:           caplin,
+		journal:          journal,
 	}
 }
 
@@ -109,6 +109,14 @@ func (e *EngineServer) Start(
 			Service:   EngineAPI(e),
 			Version:   "1.0",
 		}}
+	if e.journal != nil {
+		apiList = append(apiList, rpc.API{
+			Namespace: "debug",
+			Public:    true,
+			Service:   &EngineJournalAPI{journal: e.journal},
+			Version:   "1.0",
+		})
+	}
 
 	if err := cli.StartRpcServerWithJwtAuthentication(ctx, httpConfig, apiList, e.logger); err != nil {
 		e.logger.Error(err.Error())
@@ -138,7 +146,11 @@ func (s *EngineServer) checkRequestsPresence(time uint64, requests types.Request
 // EngineNewPayload validates and possibly executes payload
 func (s *EngineServer) newPayload(ctx context.Context, req *engine_types.ExecutionPayload,
 	expectedBlobHashes []libcommon.Hash, parentBeaconBlockRoot *libcommon.Hash, version clparams.StateVersion,
-) (*engine_types.PayloadStatus, error) {
+) (status *engine_types.PayloadStatus, err error) {
+	defer func(start time.Time) {
+		params := &journalNewPayloadParams{Payload: summarizePayload(req), ExpectedBlobHashes: len(expectedBlobHashes), ParentBeaconBlockRoot: parentBeaconBlockRoot}
+		s.journal.Record(engineMethodName("newPayload", version), start, params, status, err)
+	}(time.Now())
 	if s.caplin {
 		s.logger.Crit(caplinEnabledLog)
 		return nil, errCaplinEnabled
@@ -167,7 +179,6 @@ func (s *EngineServer) newPayload(ctx context.Context, req *engine_types.Executi
 		Difficulty:  merge.ProofOfStakeDifficulty,
 		Nonce:       merge.ProofOfStakeNonce,
 		ReceiptHash: req.ReceiptsRoot,
-		TxHash:      types.DeriveSha(types.BinaryTransactions(txs)),
 	}
 
 	var withdrawals types.Withdrawals
@@ -218,55 +229,12 @@ func (s *EngineServer) newPayload(ctx context.Context, req *engine_types.Executi
 	}
 
 	blockHash := req.BlockHash
-	if header.Hash() != blockHash {
-		s.logger.Error("[NewPayload] invalid block hash", "stated", blockHash, "actual", header.Hash())
-		return &engine_types.PayloadStatus{
-			Status:          engine_types.InvalidStatus,
-			ValidationError: engine_types.NewStringifiedErrorFromString("invalid block hash"),
-		}, nil
-	}
-
-	for _, txn := range req.Transactions {
-		if types.TypedTransactionMarshalledAsRlpString(txn) {
-			s.logger.Warn("[NewPayload] typed txn marshalled as RLP string", "txn", common.Bytes2Hex(txn))
-			return &engine_types.PayloadStatus{
-				Status:          engine_types.InvalidStatus,
-				ValidationError: engine_types.NewStringifiedErrorFromString("typed txn marshalled as RLP string"),
-			}, nil
-		}
-	}
-
-	transactions, err := types.DecodeTransactions(txs)
+	transactions, invalidStatus, err := s.preValidatePayload(&header, blockHash, txs, expectedBlobHashes, version)
 	if err != nil {
-		s.logger.Warn("[NewPayload] failed to decode transactions", "err", err)
-		return &engine_types.PayloadStatus{
-			Status:          engine_types.InvalidStatus,
-			ValidationError: engine_types.NewStringifiedError(err),
-		}, nil
+		return nil, err
 	}
-
-	if version >= clparams.DenebVersion {
-		err := ethutils.ValidateBlobs(req.BlobGasUsed.Uint64(), s.config.GetMaxBlobGasPerBlock(), s.config.GetMaxBlobsPerBlock(), expectedBlobHashes, &transactions)
-		if errors.Is(err, ethutils.ErrNilBlobHashes) {
-			return nil, &rpc.InvalidParamsError{Message: "nil blob hashes array"}
-		}
-		if errors.Is(err, ethutils.ErrMaxBlobGasUsed) {
-			bad, latestValidHash := s.hd.IsBadHeaderPoS(req.ParentHash)
-			if !bad {
-				latestValidHash = req.ParentHash
-			}
-			return &engine_types.PayloadStatus{
-				Status:          engine_types.InvalidStatus,
-				ValidationError: engine_types.NewStringifiedErrorFromString("blobs/blobgas exceeds max"),
-				LatestValidHash: &latestValidHash,
-			}, nil
-		}
-		if errors.Is(err, ethutils.ErrMismatchBlobHashes) || errors.Is(err, ethutils.ErrInvalidVersiondHash) {
-			return &engine_types.PayloadStatus{
-				Status:          engine_types.InvalidStatus,
-				ValidationError: engine_types.NewStringifiedErrorFromString(err.Error()),
-			}, nil
-		}
+	if invalidStatus != nil {
+		return invalidStatus, nil
 	}
 
 	possibleStatus, err := s.getQuickPayloadStatusIfPossible(ctx, blockHash, uint64(req.BlockNumber), header.ParentHash, nil, true)
@@ -420,7 +388,10 @@ func (s *EngineServer) getQuickPayloadStatusIfPossible(ctx context.Context, bloc
 }
 
 // EngineGetPayload retrieves previously assembled payload (Validators only)
-func (s *EngineServer) getPayload(ctx context.Context, payloadId uint64, version clparams.StateVersion) (*engine_types.GetPayloadResponse, error) {
+func (s *EngineServer) getPayload(ctx context.Context, payloadId uint64, version clparams.StateVersion) (payloadResponse *engine_types.GetPayloadResponse, err error) {
+	defer func(start time.Time) {
+		s.journal.Record(engineMethodName("getPayload", version), start, []any{hexutil.Uint64(payloadId)}, summarizeGetPayloadResponse(payloadResponse), err)
+	}(time.Now())
 	if s.caplin {
 		s.logger.Crit("[NewPayload] caplin is enabled")
 		return nil, errCaplinEnabled
@@ -470,7 +441,10 @@ func (s *EngineServer) getPayload(ctx context.Context, payloadId uint64, version
 
 // engineForkChoiceUpdated either states new block head or request the assembling of a new block
 func (s *EngineServer) forkchoiceUpdated(ctx context.Context, forkchoiceState *engine_types.ForkChoiceState, payloadAttributes *engine_types.PayloadAttributes, version clparams.StateVersion,
-) (*engine_types.ForkChoiceUpdatedResponse, error) {
+) (fcuResponse *engine_types.ForkChoiceUpdatedResponse, err error) {
+	defer func(start time.Time) {
+		s.journal.Record(engineMethodName("forkchoiceUpdated", version), start, []any{forkchoiceState, payloadAttributes}, fcuResponse, err)
+	}(time.Now())
 	if s.caplin {
 		s.logger.Crit("[NewPayload] caplin is enabled")
 		return nil, errCaplinEnabled
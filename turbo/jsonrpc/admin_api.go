@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+	"github.com/ledgerwatch/log/v3"
+
 	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/turbo/debug"
 
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
@@ -22,17 +27,33 @@ type AdminAPI interface {
 
 	// AddPeer requests connecting to a remote node.
 	AddPeer(ctx context.Context, url string) (bool, error)
+
+	// PauseMerges pauses the background merges of state files, ResumeMerges resumes them.
+	// They fail unless the RPC is served by the erigon process itself.
+	PauseMerges(ctx context.Context) (bool, error)
+	ResumeMerges(ctx context.Context) (bool, error)
+
+	// MergesStatus returns the state of the background merges of state files. Same restriction as PauseMerges.
+	MergesStatus(ctx context.Context) (*MergesStatus, error)
+
+	// ReloadConfig re-reads the config file (--config) and applies the reloadable flags in it, same as SIGHUP.
+	// Returns the applied flags. It takes effect when the RPC is served by the erigon process itself.
+	ReloadConfig(ctx context.Context) (map[string]string, error)
 }
 
 // AdminAPIImpl data structure to store things needed for admin_* commands.
 type AdminAPIImpl struct {
 	ethBackend rpchelper.ApiBackend
+	agg        *libstate.Aggregator // nil unless running in the erigon process
+	logger     log.Logger
 }
 
-// NewAdminAPI returns AdminAPIImpl instance.
-func NewAdminAPI(eth rpchelper.ApiBackend) *AdminAPIImpl {
+// NewAdminAPI returns AdminAPIImpl instance. agg is the aggregator of the erigon process, nil for a separate rpcdaemon.
+func NewAdminAPI(eth rpchelper.ApiBackend, agg *libstate.Aggregator, logger log.Logger) *AdminAPIImpl {
 	return &AdminAPIImpl{
 		ethBackend: eth,
+		agg:        agg,
+		logger:     logger,
 	}
 }
 
@@ -63,3 +84,42 @@ func (api *AdminAPIImpl) AddPeer(ctx context.Context, url string) (bool, error)
 	}
 	return result.Success, nil
 }
+
+var errMergesNotInProcess = errors.New("merges of state files can only be managed by the rpc of the erigon process, not by a separate rpcdaemon")
+
+type MergesStatus struct {
+	Paused  bool           `json:"paused"`
+	Running bool           `json:"running"` // files build or merge in progress
+	IOLimit hexutil.Uint64 `json:"ioLimit"` // bytes per second, 0 - unlimited
+}
+
+func (api *AdminAPIImpl) PauseMerges(ctx context.Context) (bool, error) {
+	if api.agg == nil {
+		return false, errMergesNotInProcess
+	}
+	api.agg.PauseMerges()
+	return true, nil
+}
+
+func (api *AdminAPIImpl) ResumeMerges(ctx context.Context) (bool, error) {
+	if api.agg == nil {
+		return false, errMergesNotInProcess
+	}
+	api.agg.ResumeMerges()
+	return true, nil
+}
+
+func (api *AdminAPIImpl) MergesStatus(ctx context.Context) (*MergesStatus, error) {
+	if api.agg == nil {
+		return nil, errMergesNotInProcess
+	}
+	return &MergesStatus{
+		Paused:  api.agg.MergesPaused(),
+		Running: api.agg.HasBackgroundFilesBuild(),
+		IOLimit: hexutil.Uint64(api.agg.MergeIOLimit()),
+	}, nil
+}
+
+func (api *AdminAPIImpl) ReloadConfig(ctx context.Context) (map[string]string, error) {
+	return debug.ReloadConfig(api.logger)
+}
@@ -0,0 +1,21 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminMergesNotInProcess(t *testing.T) {
+	api := NewAdminAPI(nil, nil, log.New())
+	ctx := context.Background()
+
+	_, err := api.PauseMerges(ctx)
+	require.ErrorIs(t, err, errMergesNotInProcess)
+	_, err = api.ResumeMerges(ctx)
+	require.ErrorIs(t, err, errMergesNotInProcess)
+	_, err = api.MergesStatus(ctx)
+	require.ErrorIs(t, err, errMergesNotInProcess)
+}
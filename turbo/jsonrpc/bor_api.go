@@ -1,8 +1,11 @@
 package jsonrpc
 
 import (
+	"context"
 	"fmt"
 
+	lru "github.com/hashicorp/golang-lru/v2"
+
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 
@@ -20,24 +23,58 @@ type BorAPI interface {
 	GetSnapshotAtHash(hash common.Hash) (*Snapshot, error)
 	GetSigners(number *rpc.BlockNumber) ([]common.Address, error)
 	GetSignersAtHash(hash common.Hash) ([]common.Address, error)
-	GetCurrentProposer() (common.Address, error)
-	GetCurrentValidators() ([]*valset.Validator, error)
+	GetCurrentProposer(blockNrOrHash *rpc.BlockNumberOrHash) (common.Address, error)
+	GetCurrentValidators(blockNrOrHash *rpc.BlockNumberOrHash) ([]*valset.Validator, error)
 	GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (common.Address, error)
 	GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash) (BlockSigners, error)
 	GetRootHash(start uint64, end uint64) (string, error)
+
+	// State sync events (see ./bor_state_sync.go)
+	GetStateSyncEventsByRootTxHash(ctx context.Context, rootTxHash common.Hash) ([]*StateSyncEvent, error)
 }
 
+const (
+	borSnapshotsCacheSize  = 128  // snapshots are keyed by block hash, enough for the recent blocks tooling asks for
+	borSignaturesCacheSize = 4096 // same as the engine's in-memory signatures
+	borRootHashesCacheSize = 128
+)
+
 // BorImpl is implementation of the BorAPI interface
 type BorImpl struct {
 	*BaseAPI
 	db kv.RoDB // the chain db
+
+	snapshots  *lru.Cache[common.Hash, *Snapshot]      // block hash -> snapshot at the block
+	signatures *lru.Cache[common.Hash, common.Address] // header hash -> block signer
+	rootHashes *lru.Cache[rootHashKey, string]
+}
+
+// rootHashKey - the hash of the end block keeps the cached root of a reorged range from being served
+type rootHashKey struct {
+	start, end uint64
+	endHash    common.Hash
 }
 
 // NewBorAPI returns BorImpl instance
 func NewBorAPI(base *BaseAPI, db kv.RoDB) *BorImpl {
+	snapshots, err := lru.New[common.Hash, *Snapshot](borSnapshotsCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	signatures, err := lru.New[common.Hash, common.Address](borSignaturesCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	rootHashes, err := lru.New[rootHashKey, string](borRootHashesCacheSize)
+	if err != nil {
+		panic(err)
+	}
 	return &BorImpl{
-		BaseAPI: base,
-		db:      db,
+		BaseAPI:    base,
+		db:         db,
+		snapshots:  snapshots,
+		signatures: signatures,
+		rootHashes: rootHashes,
 	}
 }
 
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"sort"
 
+	lru "github.com/hashicorp/golang-lru/v2"
+
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/polygon/bor/borcfg"
 
+	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/polygon/bor"
@@ -88,8 +91,33 @@ func getHeaderByHash(ctx context.Context, api *BorImpl, tx kv.Tx, hash common.Ha
 	return header, nil
 }
 
+// getHeaderByNumberOrHash returns the header of the requested block, or the current header if none is requested.
+func getHeaderByNumberOrHash(ctx context.Context, api *BorImpl, tx kv.Tx, blockNrOrHash *rpc.BlockNumberOrHash) (*types.Header, error) {
+	if blockNrOrHash == nil {
+		return rawdb.ReadCurrentHeader(tx), nil
+	}
+	if blockNr, ok := blockNrOrHash.Number(); ok {
+		if blockNr == rpc.LatestBlockNumber {
+			return rawdb.ReadCurrentHeader(tx), nil
+		}
+		return getHeaderByNumber(ctx, blockNr, api, tx)
+	}
+	if blockHash, ok := blockNrOrHash.Hash(); ok {
+		return getHeaderByHash(ctx, api, tx, blockHash)
+	}
+	return nil, errUnknownBlock
+}
+
 // ecrecover extracts the Ethereum account address from a signed header.
-func ecrecover(header *types.Header, c *borcfg.BorConfig) (common.Address, error) {
+func ecrecover(header *types.Header, sigcache *lru.Cache[common.Hash, common.Address], c *borcfg.BorConfig) (common.Address, error) {
+	// If the signature's already cached, return that
+	hash := header.Hash()
+	if sigcache != nil {
+		if address, known := sigcache.Get(hash); known {
+			return address, nil
+		}
+	}
+
 	// Retrieve the signature from the header extra-data
 	if len(header.Extra) < extraSeal {
 		return common.Address{}, errMissingSignature
@@ -104,6 +132,9 @@ func ecrecover(header *types.Header, c *borcfg.BorConfig) (common.Address, error
 	var signer common.Address
 	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
 
+	if sigcache != nil {
+		sigcache.Add(hash, signer)
+	}
 	return signer, nil
 }
 
@@ -147,9 +178,16 @@ func getUpdatedValidatorSet(oldValidatorSet *ValidatorSet, newVals []*valset.Val
 
 // author returns the Ethereum address recovered
 // from the signature in the header's extra-data section.
-func author(api *BorImpl, tx kv.Tx, header *types.Header) (common.Address, error) {
-	borEngine, _ := api.bor()
-	return ecrecover(header, borEngine.Config())
+func author(ctx context.Context, api *BorImpl, tx kv.Tx, header *types.Header) (common.Address, error) {
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	borConfig, ok := chainConfig.Bor.(*borcfg.BorConfig)
+	if !ok {
+		return common.Address{}, fmt.Errorf("not a bor chain: %s", chainConfig.ChainName)
+	}
+	return ecrecover(header, api.signatures, borConfig)
 }
 
 func rankMapDifficulties(values map[common.Address]uint64) []difficultiesKV {
@@ -2,10 +2,12 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/common"
@@ -22,7 +24,8 @@ import (
 )
 
 type Snapshot struct {
-	config *borcfg.BorConfig // Consensus engine parameters to fine tune behavior
+	config   *borcfg.BorConfig                       // Consensus engine parameters to fine tune behavior
+	sigcache *lru.Cache[common.Hash, common.Address] // Signatures of recent blocks to speed up ecrecover
 
 	Number       uint64        `json:"number"`       // Block number where the snapshot was created
 	Hash         common.Hash   `json:"hash"`         // Block hash where the snapshot was created
@@ -68,13 +71,6 @@ func (api *BorImpl) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 
 // GetAuthor retrieves the author a block.
 func (api *BorImpl) GetAuthor(blockNrOrHash *rpc.BlockNumberOrHash) (*common.Address, error) {
-	// init consensus db
-	bor, err := api.bor()
-
-	if err != nil {
-		return nil, err
-	}
-
 	ctx := context.Background()
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
@@ -82,33 +78,17 @@ func (api *BorImpl) GetAuthor(blockNrOrHash *rpc.BlockNumberOrHash) (*common.Add
 	}
 	defer tx.Rollback()
 
-	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-
-	//nolint:nestif
-	if blockNrOrHash == nil {
-		header = rawdb.ReadCurrentHeader(tx)
-	} else {
-		if blockNr, ok := blockNrOrHash.Number(); ok {
-			header = rawdb.ReadHeaderByNumber(tx, uint64(blockNr))
-			if blockNr == rpc.LatestBlockNumber {
-				header = rawdb.ReadCurrentHeader(tx)
-			}
-		} else {
-			if blockHash, ok := blockNrOrHash.Hash(); ok {
-				header, err = rawdb.ReadHeaderByHash(tx, blockHash)
-			}
-		}
-	}
-
-	// Ensure we have an actually valid block and return its snapshot
+	// Retrieve the requested block (or current if none requested)
+	header, err := getHeaderByNumberOrHash(ctx, api, tx, blockNrOrHash)
 	if header == nil || err != nil {
 		return nil, errUnknownBlock
 	}
 
-	author, err := bor.Author(header)
-
-	return &author, err
+	author, err := author(ctx, api, tx, header)
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
 }
 
 // GetSnapshotAtHash retrieves the state snapshot at a given block.
@@ -217,24 +197,50 @@ func (api *BorImpl) GetSignersAtHash(hash common.Hash) ([]common.Address, error)
 	return snap.signers(), err
 }
 
-// GetCurrentProposer gets the current proposer
-func (api *BorImpl) GetCurrentProposer() (common.Address, error) {
-	snap, err := api.GetSnapshot(nil)
+// GetCurrentProposer gets the proposer at the given block, or the current one if none is given
+func (api *BorImpl) GetCurrentProposer(blockNrOrHash *rpc.BlockNumberOrHash) (common.Address, error) {
+	snap, err := api.snapshotAt(blockNrOrHash)
 	if err != nil {
 		return common.Address{}, err
 	}
 	return snap.ValidatorSet.GetProposer().Address, nil
 }
 
-// GetCurrentValidators gets the current validators
-func (api *BorImpl) GetCurrentValidators() ([]*valset.Validator, error) {
-	snap, err := api.GetSnapshot(nil)
+// GetCurrentValidators gets the validators at the given block, or the current ones if none is given
+func (api *BorImpl) GetCurrentValidators(blockNrOrHash *rpc.BlockNumberOrHash) ([]*valset.Validator, error) {
+	snap, err := api.snapshotAt(blockNrOrHash)
 	if err != nil {
 		return make([]*valset.Validator, 0), err
 	}
 	return snap.ValidatorSet.Validators, nil
 }
 
+// snapshotAt retrieves the snapshot at the requested block, or the current one if none is requested
+func (api *BorImpl) snapshotAt(blockNrOrHash *rpc.BlockNumberOrHash) (*Snapshot, error) {
+	ctx := context.Background()
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	header, err := getHeaderByNumberOrHash(ctx, api, tx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, errUnknownBlock
+	}
+
+	bor, err := api.bor()
+	if err != nil {
+		return nil, err
+	}
+	borTx, err := bor.DB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer borTx.Rollback()
+	return snapshot(ctx, api, tx, borTx, header)
+}
+
 // GetVoteOnHash gets the vote on milestone hash
 func (api *BorImpl) GetVoteOnHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64, hash string, milestoneId string) (bool, error) {
 	tx, err := api.db.BeginRo(context.Background())
@@ -296,7 +302,6 @@ type difficultiesKV struct {
 
 // GetSnapshotProposer retrieves the in-turn signer at a given block.
 func (api *BorImpl) GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (common.Address, error) {
-	// init chain db
 	ctx := context.Background()
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
@@ -304,31 +309,14 @@ func (api *BorImpl) GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (c
 	}
 	defer tx.Rollback()
 
-	var header *types.Header
-	//nolint:nestif
-	if blockNrOrHash == nil {
-		header = rawdb.ReadCurrentHeader(tx)
-	} else {
-		if blockNr, ok := blockNrOrHash.Number(); ok {
-			if blockNr == rpc.LatestBlockNumber {
-				header = rawdb.ReadCurrentHeader(tx)
-			} else {
-				header = rawdb.ReadHeaderByNumber(tx, uint64(blockNr))
-			}
-		} else {
-			if blockHash, ok := blockNrOrHash.Hash(); ok {
-				header, err = rawdb.ReadHeaderByHash(tx, blockHash)
-			}
-		}
-	}
-
+	header, err := getHeaderByNumberOrHash(ctx, api, tx, blockNrOrHash)
 	if header == nil || err != nil {
 		return common.Address{}, errUnknownBlock
 	}
 
-	snapNumber := rpc.BlockNumber(header.Number.Int64() - 1)
-	snap, err := api.GetSnapshot(&snapNumber)
-
+	// the in-turn signer of a block is the proposer of its parent's snapshot
+	parentHash := header.ParentHash
+	snap, err := api.snapshotAt(&rpc.BlockNumberOrHash{BlockHash: &parentHash})
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -336,6 +324,7 @@ func (api *BorImpl) GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (c
 	return snap.ValidatorSet.GetProposer().Address, nil
 }
 
+// GetSnapshotProposerSequence retrieves the signers of a block ranked by their difficulty, the in-turn signer first.
 func (api *BorImpl) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash) (BlockSigners, error) {
 	// init chain db
 	ctx := context.Background()
@@ -345,25 +334,8 @@ func (api *BorImpl) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOr
 	}
 	defer tx.Rollback()
 
-	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-	if blockNrOrHash == nil {
-		header = rawdb.ReadCurrentHeader(tx)
-	} else {
-		if blockNr, ok := blockNrOrHash.Number(); ok {
-			if blockNr == rpc.LatestBlockNumber {
-				header = rawdb.ReadCurrentHeader(tx)
-			} else {
-				header, err = getHeaderByNumber(ctx, blockNr, api, tx)
-			}
-		} else {
-			if blockHash, ok := blockNrOrHash.Hash(); ok {
-				header, err = getHeaderByHash(ctx, api, tx, blockHash)
-			}
-		}
-	}
-
-	// Ensure we have an actually valid block
+	// Retrieve the requested block (or current if none requested)
+	header, err := getHeaderByNumberOrHash(ctx, api, tx, blockNrOrHash)
 	if header == nil || err != nil {
 		return BlockSigners{}, errUnknownBlock
 	}
@@ -381,7 +353,7 @@ func (api *BorImpl) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOr
 	}
 	defer borTx.Rollback()
 
-	parent, err := getHeaderByNumber(ctx, rpc.BlockNumber(int64(header.Number.Uint64()-1)), api, tx)
+	parent, err := getHeaderByHash(ctx, api, tx, header.ParentHash)
 	if parent == nil || err != nil {
 		return BlockSigners{}, errUnknownBlock
 	}
@@ -408,7 +380,7 @@ func (api *BorImpl) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOr
 
 	rankedDifficulties := rankMapDifficulties(difficulties)
 
-	author, err := author(api, tx, header)
+	author, err := author(ctx, api, tx, header)
 	if err != nil {
 		return BlockSigners{}, err
 	}
@@ -425,10 +397,9 @@ func (api *BorImpl) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOr
 
 // GetRootHash returns the merkle root of the start to end block headers
 func (api *BorImpl) GetRootHash(start, end uint64) (string, error) {
-	bor, err := api.bor()
-
-	if err != nil {
-		return "", err
+	length := end - start + 1
+	if length > bor.MaxCheckpointLength {
+		return "", &bor.MaxCheckpointLengthExceededError{Start: start, End: end}
 	}
 
 	ctx := context.Background()
@@ -438,7 +409,37 @@ func (api *BorImpl) GetRootHash(start, end uint64) (string, error) {
 	}
 	defer tx.Rollback()
 
-	return bor.GetRootHash(ctx, tx, start, end)
+	var currentHeaderNumber uint64
+	if header := rawdb.ReadCurrentHeader(tx); header != nil {
+		currentHeaderNumber = header.Number.Uint64()
+	}
+	if start > end || end > currentHeaderNumber {
+		return "", &valset.InvalidStartEndBlockError{Start: start, End: end, CurrentHeader: currentHeaderNumber}
+	}
+
+	endHash, err := api._blockReader.CanonicalHash(ctx, tx, end)
+	if err != nil {
+		return "", err
+	}
+	key := rootHashKey{start: start, end: end, endHash: endHash}
+	if root, ok := api.rootHashes.Get(key); ok {
+		return root, nil
+	}
+
+	headers := make([]*types.Header, length)
+	for number := start; number <= end; number++ {
+		if headers[number-start], err = getHeaderByNumber(ctx, rpc.BlockNumber(number), api, tx); err != nil {
+			return "", err
+		}
+	}
+	root, err := bor.ComputeHeadersRootHash(headers)
+	if err != nil {
+		return "", err
+	}
+
+	rootHex := hex.EncodeToString(root)
+	api.rootHashes.Add(key, rootHex)
+	return rootHex, nil
 }
 
 // Helper functions for Snapshot Type
@@ -447,6 +448,7 @@ func (api *BorImpl) GetRootHash(start, end uint64) (string, error) {
 func (s *Snapshot) copy() *Snapshot {
 	cpy := &Snapshot{
 		config:       s.config,
+		sigcache:     s.sigcache,
 		Number:       s.Number,
 		Hash:         s.Hash,
 		ValidatorSet: s.ValidatorSet.Copy(),
@@ -487,7 +489,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		currentLen := s.config.CalculateSprintLength(number)
 
 		// Resolve the authorization key and check against signers
-		signer, err := ecrecover(header, s.config)
+		signer, err := ecrecover(header, s.sigcache, s.config)
 		if err != nil {
 			return nil, err
 		}
@@ -530,11 +532,18 @@ func snapshot(ctx context.Context, api *BorImpl, db kv.Tx, borDb kv.Tx, header *
 	hash := header.Hash()
 
 	for snap == nil {
+		// If an in-memory snapshot was found, use that
+		if s, ok := api.snapshots.Get(hash); ok {
+			snap = s
+			break
+		}
+
 		// If an on-disk checkpoint snapshot can be found, use that
 		if number%checkpointInterval == 0 {
 			if s, err := loadSnapshot(api, db, borDb, hash); err == nil {
-				log.Info("Loaded snapshot from disk", "number", number, "hash", hash)
+				log.Debug("Loaded snapshot from disk", "number", number, "hash", hash)
 				snap = s
+				api.snapshots.Add(hash, snap)
 			}
 			break
 		}
@@ -564,7 +573,9 @@ func snapshot(ctx context.Context, api *BorImpl, db kv.Tx, borDb kv.Tx, header *
 	if err != nil {
 		return nil, err
 	}
-	return snap, nil
+	api.snapshots.Add(snap.Hash, snap)
+	// callers get their own copy: the proposer of a validator set is resolved lazily
+	return snap.copy(), nil
 }
 
 // loadSnapshot loads an existing snapshot from the database.
@@ -577,8 +588,12 @@ func loadSnapshot(api *BorImpl, db kv.Tx, borDb kv.Tx, hash common.Hash) (*Snaps
 	if err := json.Unmarshal(blob, snap); err != nil {
 		return nil, err
 	}
-	borEngine, _ := api.bor()
+	borEngine, err := api.bor()
+	if err != nil {
+		return nil, err
+	}
 	snap.config = borEngine.Config()
+	snap.sigcache = api.signatures
 
 	// update total voting power
 	if err := snap.ValidatorSet.UpdateTotalVotingPower(); err != nil {
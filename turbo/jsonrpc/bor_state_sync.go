@@ -0,0 +1,83 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/polygon/bor"
+	bortypes "github.com/ledgerwatch/erigon/polygon/bor/types"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
+)
+
+// StateSyncEvent - state sync event together with the root chain (L1) transaction which emitted it
+// and the Bor block whose state sync transaction applied it
+type StateSyncEvent struct {
+	ID              hexutil.Uint64   `json:"id"`
+	RootTxHash      common.Hash      `json:"rootTxHash"`
+	RootLogIndex    hexutil.Uint64   `json:"rootLogIndex"`
+	Contract        common.Address   `json:"contract"`
+	Data            hexutility.Bytes `json:"data"`
+	Time            hexutil.Uint64   `json:"time"`
+	BlockNumber     hexutil.Uint64   `json:"blockNumber"`
+	BlockHash       common.Hash      `json:"blockHash"`
+	TransactionHash common.Hash      `json:"transactionHash"` // hash of the state sync transaction of the block
+}
+
+// GetStateSyncEventsByRootTxHash returns the state sync events emitted by the root chain transaction, ordered by log index.
+// Only events fetched from Heimdall by this node are indexed: events of blocks which came in snapshots are not found.
+func (api *BorImpl) GetStateSyncEventsByRootTxHash(ctx context.Context, rootTxHash common.Hash) ([]*StateSyncEvent, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	entries, err := rawdb.ReadBorEventsByRootTx(tx, rootTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	stateReceiverABI := bor.GenesisContractStateReceiverABI()
+	result := make([]*StateSyncEvent, 0, len(entries))
+	for _, entry := range entries {
+		blockHash, err := api._blockReader.CanonicalHash(ctx, tx, entry.BlockNum)
+		if err != nil {
+			return nil, err
+		}
+		startEventID, err := api._blockReader.BorStartEventID(ctx, tx, blockHash, entry.BlockNum)
+		if err != nil {
+			return nil, err
+		}
+		events, err := api._blockReader.EventsByBlock(ctx, tx, blockHash, entry.BlockNum)
+		if err != nil {
+			return nil, err
+		}
+		if entry.EventID < startEventID || entry.EventID-startEventID >= uint64(len(events)) {
+			return nil, fmt.Errorf("state sync event %d not found in block %d", entry.EventID, entry.BlockNum)
+		}
+
+		event, err := heimdall.UnpackEventRecordWithTime(stateReceiverABI, events[entry.EventID-startEventID])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &StateSyncEvent{
+			ID:              hexutil.Uint64(event.ID),
+			RootTxHash:      event.TxHash,
+			RootLogIndex:    hexutil.Uint64(event.LogIndex),
+			Contract:        event.Contract,
+			Data:            event.Data,
+			Time:            hexutil.Uint64(event.Time.Unix()),
+			BlockNumber:     hexutil.Uint64(entry.BlockNum),
+			BlockHash:       blockHash,
+			TransactionHash: bortypes.ComputeBorTxHash(entry.BlockNum, blockHash),
+		})
+	}
+
+	return result, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli/httpcfg"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/clique"
+	"github.com/ledgerwatch/erigon/eth/aapool"
 	"github.com/ledgerwatch/erigon/polygon/bor"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
@@ -19,18 +20,23 @@ import (
 func APIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient,
 	filters *rpchelper.Filters, stateCache kvcache.Cache,
 	blockReader services.FullBlockReader, agg *libstate.Aggregator, cfg *httpcfg.HttpCfg, engine consensus.EngineReader,
-	logger log.Logger,
+	aaPool *aapool.Pool, logger log.Logger,
 ) (list []rpc.API) {
 	base := NewBaseApi(filters, stateCache, blockReader, agg, cfg.WithDatadir, cfg.EvmCallTimeout, engine, cfg.Dirs)
 	ethImpl := NewEthAPI(base, db, eth, txPool, mining, cfg.Gascap, cfg.ReturnDataLimit, cfg.AllowUnprotectedTxs, cfg.MaxGetProofRewindBlockCount, cfg.WebsocketSubscribeLogsChannelSize, logger)
-	erigonImpl := NewErigonAPI(base, db, eth)
+	erigonImpl := NewErigonAPI(base, db, eth, cfg.LogsPageBudget)
 	txpoolImpl := NewTxPoolAPI(base, db, txPool)
 	netImpl := NewNetAPIImpl(eth)
-	debugImpl := NewPrivateDebugAPI(base, db, cfg.Gascap)
+	debugImpl := NewPrivateDebugAPI(base, db, cfg.Gascap, cfg.TraceBlockWorkers, cfg.TraceBlockMemoryBudget)
 	traceImpl := NewTraceAPI(base, db, cfg)
 	web3Impl := NewWeb3APIImpl(eth)
 	dbImpl := NewDBAPIImpl() /* deprecated */
-	adminImpl := NewAdminAPI(eth)
+	// a separate rpcdaemon (--datadir) has its own read-only aggregator: merges of the erigon process are not reachable
+	mergesAgg := agg
+	if cfg.WithDatadir {
+		mergesAgg = nil
+	}
+	adminImpl := NewAdminAPI(eth, mergesAgg, logger)
 	parityImpl := NewParityAPIImpl(base, db)
 
 	var borImpl *BorImpl
@@ -50,6 +56,7 @@ func APIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, m
 	}
 
 	otsImpl := NewOtterscanAPI(base, db, cfg.OtsMaxPageSize)
+	ots2Impl := NewOtterscan2API(base, db, cfg.OtsMaxPageSize)
 	gqlImpl := NewGraphQLAPI(base, db)
 	overlayImpl := NewOverlayAPI(base, db, cfg.Gascap, cfg.OverlayGetLogsTimeout, cfg.OverlayReplayBlockTimeout, otsImpl)
 
@@ -71,6 +78,14 @@ func APIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, m
 				Service:   EthAPI(ethImpl),
 				Version:   "1.0",
 			})
+			if aaPool != nil {
+				list = append(list, rpc.API{
+					Namespace: "eth",
+					Public:    true,
+					Service:   UserOperationAPI(NewUserOperationAPI(base, db, aaPool, cfg.Gascap)),
+					Version:   "1.0",
+				})
+			}
 		case "debug":
 			list = append(list, rpc.API{
 				Namespace: "debug",
@@ -150,6 +165,13 @@ func APIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, m
 				Service:   OtterscanAPI(otsImpl),
 				Version:   "1.0",
 			})
+		case "ots2":
+			list = append(list, rpc.API{
+				Namespace: "ots2",
+				Public:    true,
+				Service:   Otterscan2API(ots2Impl),
+				Version:   "1.0",
+			})
 		case "clique":
 			list = append(list, clique.NewCliqueAPI(db, engine, blockReader))
 		case "overlay":
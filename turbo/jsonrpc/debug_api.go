@@ -6,18 +6,24 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
+	"github.com/c2h5oh/datasize"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/stateless"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/eth/tracers/profiler"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
@@ -40,6 +46,8 @@ type PrivateDebugAPI interface {
 	AccountAt(ctx context.Context, blockHash common.Hash, txIndex uint64, account common.Address) (*AccountResult, error)
 	GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
 	GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
+	ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*stateless.Witness, error)
+	ExecutionProfile(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, limit *int) (*profiler.RangeProfile, error)
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
@@ -47,14 +55,19 @@ type PrivateDebugAPIImpl struct {
 	*BaseAPI
 	db     kv.RoDB
 	GasCap uint64
+
+	traceBlockWorkers      int               // debug_traceBlock* traces transactions concurrently if > 1
+	traceBlockMemoryBudget datasize.ByteSize // max size of traces waiting to be streamed out, unbounded if 0
 }
 
 // NewPrivateDebugAPI returns PrivateDebugAPIImpl instance
-func NewPrivateDebugAPI(base *BaseAPI, db kv.RoDB, gascap uint64) *PrivateDebugAPIImpl {
+func NewPrivateDebugAPI(base *BaseAPI, db kv.RoDB, gascap uint64, traceBlockWorkers int, traceBlockMemoryBudget datasize.ByteSize) *PrivateDebugAPIImpl {
 	return &PrivateDebugAPIImpl{
-		BaseAPI: base,
-		db:      db,
-		GasCap:  gascap,
+		BaseAPI:                base,
+		db:                     db,
+		GasCap:                 gascap,
+		traceBlockWorkers:      traceBlockWorkers,
+		traceBlockMemoryBudget: traceBlockMemoryBudget,
 	}
 }
 
@@ -280,16 +293,20 @@ func (api *PrivateDebugAPIImpl) AccountAt(ctx context.Context, blockHash common.
 		return nil, err
 	}
 	ttx := tx.(kv.TemporalTx)
-	v, ok, err := ttx.DomainGetAsOf(kv.AccountsDomain, address[:], nil, minTxNum+txIndex+1)
-	if err != nil {
+	reqs := []kv.DomainGetAsOfReq{
+		{Domain: kv.AccountsDomain, Key: address[:], Ts: minTxNum + txIndex + 1},
+		{Domain: kv.CodeDomain, Key: address[:], Ts: minTxNum + txIndex},
+	}
+	if err := ttx.DomainGetAsOfBatch(reqs); err != nil {
 		return nil, err
 	}
-	if !ok || len(v) == 0 {
+	v := reqs[0].V
+	if !reqs[0].Ok || len(v) == 0 {
 		return &AccountResult{}, nil
 	}
 
 	var a accounts.Account
-	if err := accounts.DeserialiseV3(&a, v); err != nil {
+	if err := accounts.DeserialiseState(&a, v); err != nil {
 		return nil, err
 	}
 	result := &AccountResult{}
@@ -297,11 +314,7 @@ func (api *PrivateDebugAPIImpl) AccountAt(ctx context.Context, blockHash common.
 	result.Nonce = hexutil.Uint64(a.Nonce)
 	result.CodeHash = a.CodeHash
 
-	code, _, err := ttx.DomainGetAsOf(kv.CodeDomain, address[:], nil, minTxNum+txIndex)
-	if err != nil {
-		return nil, err
-	}
-	result.Code = code
+	result.Code = reqs[1].V
 	return result, nil
 }
 
@@ -351,3 +364,45 @@ func (api *PrivateDebugAPIImpl) GetRawBlock(ctx context.Context, blockNrOrHash r
 	}
 	return rlp.EncodeToBytes(block)
 }
+
+// ExecutionWitness implements debug_executionWitness. Returns the witness of the block - accessed accounts, storage
+// and code plus proofs against the parent state root. Witnesses emitted by the execution stage (--exec.witness.blocks)
+// are served from the db, others are generated by re-execution of the block.
+func (api *PrivateDebugAPIImpl) ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*stateless.Witness, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	n, h, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := rawdb.ReadExecutionWitness(tx, h, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc) > 0 {
+		return stateless.DecodeWitness(enc)
+	}
+
+	block, err := api.blockWithSenders(ctx, tx, h, n)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block not found")
+	}
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	engine, ok := api.engine().(consensus.Engine)
+	if !ok {
+		return nil, fmt.Errorf("consensus engine doesn't support block execution")
+	}
+	logger := log.New("debug_executionWitness")
+	chainReader := consensuschain.NewReader(chainConfig, tx, api._blockReader, logger)
+	return stateless.Generate(ctx, tx, block, chainConfig, engine, chainReader, nil, logger)
+}
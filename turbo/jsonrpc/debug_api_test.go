@@ -15,8 +15,13 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/stateless"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/eth/tracers/profiler"
+	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
@@ -54,7 +59,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
 	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
 	ethApi := NewEthAPI(baseApi, m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -99,7 +104,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 func TestTraceBlockByHash(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -128,9 +133,35 @@ func TestTraceBlockByHash(t *testing.T) {
 	}
 }
 
+func TestTraceBlockParallel(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	sequential := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
+	// 1 byte budget: every trace but the one the writer is waiting for has to wait for the writer
+	parallel := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 3, 1)
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	head := rawdb.ReadCurrentHeader(tx)
+	require.NotNil(t, head)
+
+	callTracer := "callTracer"
+	for _, config := range []*tracers.TraceConfig{{}, {Tracer: &callTracer}} {
+		for blockNum := uint64(1); blockNum <= head.Number.Uint64(); blockNum++ {
+			var expected, got bytes.Buffer
+			stream := jsoniter.NewStream(jsoniter.ConfigDefault, &expected, 4096)
+			require.NoError(t, sequential.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(blockNum), config, stream))
+			require.NoError(t, stream.Flush())
+			stream = jsoniter.NewStream(jsoniter.ConfigDefault, &got, 4096)
+			require.NoError(t, parallel.TraceBlockByNumber(m.Ctx, rpc.BlockNumber(blockNum), config, stream))
+			require.NoError(t, stream.Flush())
+			require.Equal(t, expected.String(), got.String(), "block %d", blockNum)
+		}
+	}
+}
+
 func TestTraceTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -159,7 +190,7 @@ func TestTraceTransaction(t *testing.T) {
 
 func TestTraceTransactionNoRefund(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 	for _, tt := range debugTraceTransactionNoRefundTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -189,7 +220,7 @@ func TestTraceTransactionNoRefund(t *testing.T) {
 
 func TestStorageRangeAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 	t.Run("invalid addr", func(t *testing.T) {
 		var block4 *types.Block
 		var err error
@@ -283,7 +314,7 @@ func TestStorageRangeAt(t *testing.T) {
 
 func TestAccountRange(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 
 	t.Run("valid account", func(t *testing.T) {
 		addr := common.HexToAddress("0x537e697c7ab75a26f9ecf0ce810e3154dfcaaf55")
@@ -342,7 +373,7 @@ func TestAccountRange(t *testing.T) {
 
 func TestGetModifiedAccountsByNumber(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 
 	t.Run("correct input", func(t *testing.T) {
 		n, n2 := rpc.BlockNumber(1), rpc.BlockNumber(2)
@@ -444,7 +475,7 @@ func TestMapTxNum2BlockNum(t *testing.T) {
 
 func TestAccountAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
 
 	var blockHash0, blockHash1, blockHash3, blockHash10, blockHash12 common.Hash
 	_ = m.DB.View(m.Ctx, func(tx kv.Tx) error {
@@ -504,3 +535,118 @@ func TestAccountAt(t *testing.T) {
 		require.Equal(0, int(results.Nonce))
 	})
 }
+
+func TestExecutionWitness(t *testing.T) {
+	m, bankAddr, contractAddr := chainWithDeployedContract(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
+
+	t.Run("generated", func(t *testing.T) {
+		require := require.New(t)
+		witness, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(3))
+		require.NoError(err)
+
+		tx, err := m.DB.BeginRo(m.Ctx)
+		require.NoError(err)
+		defer tx.Rollback()
+		parent, err := api._blockReader.HeaderByNumber(m.Ctx, tx, 2)
+		require.NoError(err)
+		parentEnc, err := rlp.EncodeToBytes(parent)
+		require.NoError(err)
+
+		require.Contains(witness.Headers, parentEnc)
+		require.Contains(witness.Keys, bankAddr.Bytes())
+		require.Contains(witness.Keys, contractAddr.Bytes())
+		require.Len(witness.Codes, 1)
+
+		// proofs start from the parent state root
+		var hasRoot bool
+		for _, node := range witness.State {
+			if crypto.Keccak256Hash(node) == parent.Root {
+				hasRoot = true
+			}
+		}
+		require.True(hasRoot)
+
+		// witness of the same block is deterministic
+		again, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(3))
+		require.NoError(err)
+		require.Equal(witness, again)
+	})
+	t.Run("stored", func(t *testing.T) {
+		require := require.New(t)
+		stored := &stateless.Witness{Keys: [][]byte{bankAddr.Bytes()}}
+		enc, err := stored.EncodeRLP()
+		require.NoError(err)
+
+		tx, err := m.DB.BeginRw(m.Ctx)
+		require.NoError(err)
+		defer tx.Rollback()
+		hash, err := api._blockReader.CanonicalHash(m.Ctx, tx, 2)
+		require.NoError(err)
+		require.NoError(rawdb.WriteExecutionWitness(tx, hash, 2, enc))
+		require.NoError(tx.Commit())
+
+		witness, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(2))
+		require.NoError(err)
+		require.Equal(stored.Keys, witness.Keys)
+	})
+	t.Run("genesis", func(t *testing.T) {
+		_, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(0))
+		require.Error(t, err)
+	})
+}
+
+func TestExecutionProfile(t *testing.T) {
+	m, _, contractAddr := chainWithDeployedContract(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, 0, 0)
+
+	t.Run("generated", func(t *testing.T) {
+		require := require.New(t)
+		profile, err := api.ExecutionProfile(m.Ctx, 1, 3, nil)
+		require.NoError(err)
+		require.Equal(uint64(3), profile.Blocks)
+		require.Len(profile.Txs, 3)
+		require.NotZero(profile.GasUsed)
+
+		// the contract is deployed in block 1 and called in blocks 2 and 3
+		require.NotEmpty(profile.Contracts)
+		require.Equal(contractAddr, profile.Contracts[0].Address)
+		sstore := profile.Opcodes["SSTORE"]
+		require.NotNil(sstore)
+		require.NotZero(sstore.Count)
+		var histogramCount uint64
+		for _, c := range sstore.GasHist {
+			histogramCount += c
+		}
+		require.Equal(sstore.Count, histogramCount)
+
+		limit := 1
+		profile, err = api.ExecutionProfile(m.Ctx, 1, 3, &limit)
+		require.NoError(err)
+		require.Len(profile.Txs, 1)
+		require.Len(profile.Contracts, 1)
+	})
+	t.Run("stored", func(t *testing.T) {
+		require := require.New(t)
+		tx, err := m.DB.BeginRw(m.Ctx)
+		require.NoError(err)
+		defer tx.Rollback()
+		hash, err := api._blockReader.CanonicalHash(m.Ctx, tx, 2)
+		require.NoError(err)
+		stored := profiler.NewBlockProfile(2, hash)
+		stored.GasUsed = 42
+		enc, err := stored.Encode()
+		require.NoError(err)
+		require.NoError(rawdb.WriteExecutionProfile(tx, hash, 2, enc))
+		require.NoError(tx.Commit())
+
+		profile, err := api.ExecutionProfile(m.Ctx, 2, 2, nil)
+		require.NoError(err)
+		require.Equal(uint64(42), profile.GasUsed)
+		require.Empty(profile.Txs)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := api.ExecutionProfile(m.Ctx, 3, 1, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/consensuschain"
+	"github.com/ledgerwatch/erigon/eth/tracers/profiler"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+const (
+	executionProfileMaxRange     = 1024 // blocks per debug_executionProfile call
+	executionProfileDefaultLimit = 20
+	executionProfileMaxLimit     = 1000
+)
+
+// ExecutionProfile implements debug_executionProfile. Returns per-opcode gas/time histograms, the hottest contracts
+// and the slowest transactions of blocks [fromBlock, toBlock]. Profiles stored by `integration profile_exec` are
+// served from the db, other blocks are re-executed. Times are wall-clock of the traced execution - only relative
+// values are meaningful.
+func (api *PrivateDebugAPIImpl) ExecutionProfile(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, limit *int) (*profiler.RangeProfile, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	from, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(fromBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	to, _, _, err := rpchelper.GetCanonicalBlockNumber(rpc.BlockNumberOrHashWithNumber(toBlock), tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is after toBlock %d", from, to)
+	}
+	if to-from+1 > executionProfileMaxRange {
+		return nil, fmt.Errorf("too many blocks: %d, max %d per request", to-from+1, executionProfileMaxRange)
+	}
+	top := executionProfileDefaultLimit
+	if limit != nil {
+		if *limit <= 0 || *limit > executionProfileMaxLimit {
+			return nil, fmt.Errorf("limit must be in [1, %d]", executionProfileMaxLimit)
+		}
+		top = *limit
+	}
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.New("debug_executionProfile")
+	chainReader := consensuschain.NewReader(chainConfig, tx, api._blockReader, logger)
+
+	aggregator := profiler.NewAggregator(from, to, top)
+	for blockNum := from; blockNum <= to; blockNum++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hash, err := api._blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := rawdb.ReadExecutionProfile(tx, hash, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if len(enc) > 0 {
+			profile, err := profiler.DecodeBlockProfile(enc)
+			if err != nil {
+				return nil, fmt.Errorf("execution profile of block %d: %w", blockNum, err)
+			}
+			aggregator.Add(profile)
+			continue
+		}
+
+		if err := api.BaseAPI.checkPruneHistory(tx, blockNum); err != nil {
+			return nil, err
+		}
+		block, err := api.blockWithSenders(ctx, tx, hash, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", blockNum)
+		}
+		engine, ok := api.engine().(consensus.Engine)
+		if !ok {
+			return nil, fmt.Errorf("consensus engine doesn't support block execution")
+		}
+		profile, err := profiler.Generate(ctx, tx, block, chainConfig, engine, chainReader, logger)
+		if err != nil {
+			return nil, err
+		}
+		aggregator.Add(profile)
+	}
+	return aggregator.Result(), nil
+}
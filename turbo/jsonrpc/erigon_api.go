@@ -33,6 +33,7 @@ type ErigonAPI interface {
 	//GetLogsByNumber(ctx context.Context, number rpc.BlockNumber) ([][]*types.Log, error)
 	GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.ErigonLogs, error)
 	GetLatestLogs(ctx context.Context, crit filters.FilterCriteria, logOptions filters.LogFilterOptions) (types.ErigonLogs, error)
+	GetLogsPaged(ctx context.Context, crit filters.FilterCriteria, continuation *hexutil.Uint64) (*LogsPage, error)
 	// Gets cannonical block receipt through hash. If the block is not cannonical returns error
 	GetBlockReceiptsByBlockHash(ctx context.Context, cannonicalBlockHash common.Hash) ([]map[string]interface{}, error)
 
@@ -45,13 +46,16 @@ type ErigonImpl struct {
 	*BaseAPI
 	db         kv.RoDB
 	ethBackend rpchelper.ApiBackend
+	// logsPageBudget - max amount of transactions re-executed by one erigon_getLogsPaged call
+	logsPageBudget int
 }
 
 // NewErigonAPI returns ErigonImpl instance
-func NewErigonAPI(base *BaseAPI, db kv.RoDB, eth rpchelper.ApiBackend) *ErigonImpl {
+func NewErigonAPI(base *BaseAPI, db kv.RoDB, eth rpchelper.ApiBackend, logsPageBudget int) *ErigonImpl {
 	return &ErigonImpl{
-		BaseAPI:    base,
-		db:         db,
-		ethBackend: eth,
+		BaseAPI:        base,
+		db:             db,
+		ethBackend:     eth,
+		logsPageBudget: logsPageBudget,
 	}
 }
@@ -231,7 +231,7 @@ func (api *ErigonImpl) GetBalanceChangesInBlock(ctx context.Context, blockNrOrHa
 
 		var oldAcc accounts.Account
 		if len(v) > 0 {
-			if err = accounts.DeserialiseV3(&oldAcc, v); err != nil {
+			if err = accounts.DeserialiseState(&oldAcc, v); err != nil {
 				return nil, err
 			}
 		}
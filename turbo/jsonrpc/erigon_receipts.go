@@ -10,6 +10,7 @@ import (
 	bortypes "github.com/ledgerwatch/erigon/polygon/bor/types"
 
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/bitmapdb"
@@ -193,6 +194,42 @@ func (api *ErigonImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria)
 	return erigonLogs, nil
 }
 
+// LogsPage - logs returned by erigon_getLogsPaged
+type LogsPage struct {
+	Logs types.Logs `json:"logs"`
+	// Continuation - pass it with the same filter to get the following logs, null once all of them are returned
+	Continuation *hexutil.Uint64 `json:"continuation"`
+}
+
+// GetLogsPaged implements erigon_getLogsPaged. Returns the logs matching a given filter object, like eth_getLogs, but
+// re-executes at most --rpc.logs.pagebudget transactions per call: the logs of the remaining ones are returned by
+// the calls with the continuation. The filter should have an explicit toBlock, so that all pages cover the same range.
+func (api *ErigonImpl) GetLogsPaged(ctx context.Context, crit filters.FilterCriteria, continuation *hexutil.Uint64) (*LogsPage, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	begin, end, err := api.logsBlockRange(ctx, tx, crit)
+	if err != nil {
+		return nil, err
+	}
+	var from uint64
+	if continuation != nil {
+		from = uint64(*continuation)
+	}
+	logs, next, err := api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit, from, api.logsPageBudget)
+	if err != nil {
+		return nil, err
+	}
+	page := &LogsPage{Logs: logs}
+	if next != 0 {
+		page.Continuation = (*hexutil.Uint64)(&next)
+	}
+	return page, nil
+}
+
 // GetLatestLogs implements erigon_getLatestLogs.
 // Return specific number of logs or block matching a give filter objects by descend.
 // IgnoreTopicsOrder option provide a way to match the logs with addresses and topics without caring about the topics' orders
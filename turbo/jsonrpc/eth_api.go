@@ -16,6 +16,7 @@ import (
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/commitment"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
@@ -32,6 +33,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	ethFilters "github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/eth/gasprice"
 	"github.com/ledgerwatch/erigon/ethdb/prune"
 	"github.com/ledgerwatch/erigon/rpc"
 	ethapi2 "github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
@@ -91,6 +93,7 @@ type EthAPI interface {
 	// Sending related (see ./eth_call.go)
 	Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides) (hexutility.Bytes, error)
 	EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error)
+	SimulateV1(ctx context.Context, opts SimulationOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error)
 	SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error)
 	SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	Sign(ctx context.Context, _ common.Address, _ hexutility.Bytes) (hexutility.Bytes, error)
@@ -337,14 +340,23 @@ type APIImpl struct {
 	MaxGetProofRewindBlockCount int
 	SubscribeLogsChannelSize    int
 	logger                      log.Logger
+	proofCache                  *commitment.ProofCache // branches of the historical tries used by eth_getProof
 }
 
+// proofCacheSize - number of trie branches cached for eth_getProof, a branch takes ~7Kb
+const proofCacheSize = 1024
+
 // NewEthAPI returns APIImpl instance
 func NewEthAPI(base *BaseAPI, db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient, gascap uint64, returnDataLimit int, allowUnprotectedTxs bool, maxGetProofRewindBlockCount int, subscribeLogsChannelSize int, logger log.Logger) *APIImpl {
 	if gascap == 0 {
 		gascap = uint64(math.MaxUint64 / 2)
 	}
 
+	proofCache, err := commitment.NewProofCache(proofCacheSize)
+	if err != nil {
+		panic(err)
+	}
+
 	return &APIImpl{
 		BaseAPI:                     base,
 		db:                          db,
@@ -358,6 +370,7 @@ func NewEthAPI(base *BaseAPI, db kv.RoDB, eth rpchelper.ApiBackend, txPool txpoo
 		MaxGetProofRewindBlockCount: maxGetProofRewindBlockCount,
 		SubscribeLogsChannelSize:    subscribeLogsChannelSize,
 		logger:                      logger,
+		proofCache:                  proofCache,
 	}
 }
 
@@ -520,16 +533,25 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) (hexutilit
 	return buf.Bytes(), err
 }
 
+// feeHistoryTipsCacheSize - number of blocks with sorted tips cached for eth_feeHistory, the max fee history window
+const feeHistoryTipsCacheSize = 1024
+
 type GasPriceCache struct {
 	latestPrice *big.Int
 	latestHash  common.Hash
 	mtx         sync.Mutex
+	tips        *lru.Cache[common.Hash, *gasprice.BlockTips] // block hash -> sorted tips of the block
 }
 
 func NewGasPriceCache() *GasPriceCache {
+	tips, err := lru.New[common.Hash, *gasprice.BlockTips](feeHistoryTipsCacheSize)
+	if err != nil {
+		panic(err)
+	}
 	return &GasPriceCache{
 		latestPrice: big.NewInt(0),
 		latestHash:  common.Hash{},
+		tips:        tips,
 	}
 }
 
@@ -549,3 +571,11 @@ func (c *GasPriceCache) SetLatest(hash common.Hash, price *big.Int) {
 	c.latestHash = hash
 	c.mtx.Unlock()
 }
+
+func (c *GasPriceCache) GetBlockTips(hash common.Hash) (*gasprice.BlockTips, bool) {
+	return c.tips.Get(hash)
+}
+
+func (c *GasPriceCache) SetBlockTips(hash common.Hash, tips *gasprice.BlockTips) {
+	c.tips.Add(hash, tips)
+}
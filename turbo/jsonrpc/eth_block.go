@@ -294,7 +294,7 @@ func (api *APIImpl) GetBlockByHash(ctx context.Context, numberOrHash rpc.BlockNu
 
 	if chainConfig.Bor != nil {
 		borConfig := chainConfig.Bor.(*borcfg.BorConfig)
-		response["miner"], _ = ecrecover(block.Header(), borConfig)
+		response["miner"], _ = ecrecover(block.Header(), nil, borConfig)
 	}
 
 	if err == nil && int64(number) == rpc.PendingBlockNumber.Int64() {
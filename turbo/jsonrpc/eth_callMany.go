@@ -37,6 +37,7 @@ type BlockOverrides struct {
 type Bundle struct {
 	Transactions  []ethapi.CallArgs
 	BlockOverride BlockOverrides
+	StateOverride *ethapi.StateOverrides // applied on top of the state left by the previous bundles
 }
 
 type StateContext struct {
@@ -44,6 +45,15 @@ type StateContext struct {
 	TransactionIndex *int
 }
 
+// bundleStateOverride applies the state overrides of the bundle, the state persists across bundles,
+// so they are visible to all the later bundles too.
+func bundleStateOverride(ibs evmtypes.IntraBlockState, bundle Bundle) error {
+	if bundle.StateOverride == nil {
+		return nil
+	}
+	return bundle.StateOverride.Override(ibs.(*state.IntraBlockState))
+}
+
 func blockHeaderOverride(blockCtx *evmtypes.BlockContext, blockOverride BlockOverrides, overrideBlockHash map[uint64]common.Hash) {
 	if blockOverride.BlockNumber != nil {
 		blockCtx.BlockNumber = uint64(*blockOverride.BlockNumber)
@@ -226,28 +236,9 @@ func (api *APIImpl) CallMany(ctx context.Context, bundles []Bundle, simulateCont
 
 	for _, bundle := range bundles {
 		// first change blockContext
-		if bundle.BlockOverride.BlockNumber != nil {
-			blockCtx.BlockNumber = uint64(*bundle.BlockOverride.BlockNumber)
-		}
-		if bundle.BlockOverride.BaseFee != nil {
-			blockCtx.BaseFee = bundle.BlockOverride.BaseFee
-		}
-		if bundle.BlockOverride.Coinbase != nil {
-			blockCtx.Coinbase = *bundle.BlockOverride.Coinbase
-		}
-		if bundle.BlockOverride.Difficulty != nil {
-			blockCtx.Difficulty = big.NewInt(int64(*bundle.BlockOverride.Difficulty))
-		}
-		if bundle.BlockOverride.Timestamp != nil {
-			blockCtx.Time = uint64(*bundle.BlockOverride.Timestamp)
-		}
-		if bundle.BlockOverride.GasLimit != nil {
-			blockCtx.GasLimit = uint64(*bundle.BlockOverride.GasLimit)
-		}
-		if bundle.BlockOverride.BlockHash != nil {
-			for blockNum, hash := range *bundle.BlockOverride.BlockHash {
-				overrideBlockHash[blockNum] = hash
-			}
+		blockHeaderOverride(&blockCtx, bundle.BlockOverride, overrideBlockHash)
+		if err = bundleStateOverride(evm.IntraBlockState(), bundle); err != nil {
+			return nil, err
 		}
 		results := []map[string]interface{}{}
 		for _, txn := range bundle.Transactions {
@@ -16,6 +16,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/accounts/abi/bind"
 	"github.com/ledgerwatch/erigon/accounts/abi/bind/backends"
+	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/params"
@@ -169,4 +170,26 @@ func TestCallMany(t *testing.T) {
 	if addr1Balance != 100 || addr2Balance != 0 {
 		t.Errorf("eth_callMany: %s", "balanceUnmatch")
 	}
+
+	// the state override of the 2nd bundle replaces the token with a contract returning 42,
+	// the overridden state is kept for the 3rd bundle
+	returns42 := hexutility.Bytes(common.FromHex("602a60005260206000f3"))
+	override := ethapi.StateOverrides{tokenAddr: ethapi.Account{Code: &returns42}}
+	res, err = api.CallMany(ctx, []Bundle{
+		{Transactions: []ethapi.CallArgs{callArgAddr1}},
+		{Transactions: []ethapi.CallArgs{callArgAddr1}, StateOverride: &override},
+		{Transactions: []ethapi.CallArgs{callArgAddr1}},
+	}, StateContext{BlockNumber: rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), TransactionIndex: &txIndex}, nil, &timeout)
+	if err != nil {
+		t.Fatalf("eth_callMany: %v", err)
+	}
+	for i, expected := range []int64{100, 42, 42} {
+		ret, err := strconv.ParseInt(fmt.Sprintf("%v", res[i][0]["value"])[2:], 16, 64)
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+		if ret != expected {
+			t.Errorf("eth_callMany: bundle %d returned %d, expected %d", i, ret, expected)
+		}
+	}
 }
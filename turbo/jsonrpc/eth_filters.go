@@ -2,10 +2,13 @@ package jsonrpc
 
 import (
 	"context"
+	"math/big"
 	"strings"
 
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon-lib/common"
+
 	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/filters"
@@ -28,7 +31,7 @@ func (api *APIImpl) NewPendingTransactionFilter(_ context.Context) (string, erro
 }
 
 // NewBlockFilter implements eth_newBlockFilter. Creates a filter in the node, to notify when a new block arrives.
-func (api *APIImpl) NewBlockFilter(_ context.Context) (string, error) {
+func (api *APIImpl) NewBlockFilter(ctx context.Context) (string, error) {
 	if api.filters == nil {
 		return "", rpc.ErrNotificationsUnsupported
 	}
@@ -38,11 +41,19 @@ func (api *APIImpl) NewBlockFilter(_ context.Context) (string, error) {
 			api.filters.AddPendingBlock(id, block)
 		}
 	}()
+	if api.filters.Persistent() {
+		latest, err := api.latestExecutedBlockNumber(ctx)
+		if err != nil {
+			api.filters.UnsubscribeHeads(id)
+			return "", err
+		}
+		api.filters.PersistBlockFilter(id, latest)
+	}
 	return "0x" + string(id), nil
 }
 
 // NewFilter implements eth_newFilter. Creates an arbitrary filter object, based on filter options, to notify when the state changes (logs).
-func (api *APIImpl) NewFilter(_ context.Context, crit filters.FilterCriteria) (string, error) {
+func (api *APIImpl) NewFilter(ctx context.Context, crit filters.FilterCriteria) (string, error) {
 	if api.filters == nil {
 		return "", rpc.ErrNotificationsUnsupported
 	}
@@ -52,6 +63,14 @@ func (api *APIImpl) NewFilter(_ context.Context, crit filters.FilterCriteria) (s
 			api.filters.AddLogs(id, lg)
 		}
 	}()
+	if api.filters.Persistent() {
+		latest, err := api.latestExecutedBlockNumber(ctx)
+		if err != nil {
+			api.filters.UnsubscribeLogs(id)
+			return "", err
+		}
+		api.filters.PersistLogsFilter(id, crit, latest)
+	}
 	return "0x" + string(id), nil
 }
 
@@ -77,17 +96,43 @@ func (api *APIImpl) UninstallFilter(_ context.Context, index string) (isDeleted
 // GetFilterChanges implements eth_getFilterChanges.
 // Polling method for a previously-created filter
 // returns an array of logs, block headers, or pending transactions which occurred since last poll.
-func (api *APIImpl) GetFilterChanges(_ context.Context, index string) ([]any, error) {
+func (api *APIImpl) GetFilterChanges(ctx context.Context, index string) ([]any, error) {
 	if api.filters == nil {
 		return nil, rpc.ErrNotificationsUnsupported
 	}
 	stub := make([]any, 0)
 	// remove 0x
 	cutIndex := strings.TrimPrefix(index, "0x")
+	if backlog, ok := api.filters.Backlog(rpchelper.SubscriptionID(cutIndex)); ok {
+		switch backlog.Kind {
+		case rpchelper.FilterKindBlocks:
+			hashes, err := api.blockFilterBacklog(ctx, rpchelper.HeadsSubID(cutIndex), backlog)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range hashes {
+				stub = append(stub, v)
+			}
+		case rpchelper.FilterKindLogs:
+			logs, err := api.logsFilterBacklog(ctx, rpchelper.LogsSubID(cutIndex), backlog)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range logs {
+				stub = append(stub, v)
+			}
+		}
+		return stub, nil
+	}
+	polledHead, err := api.persistentFilterPolledHead(ctx)
+	if err != nil {
+		return nil, err
+	}
 	if blocks, ok := api.filters.ReadPendingBlocks(rpchelper.HeadsSubID(cutIndex)); ok {
 		for _, v := range blocks {
 			stub = append(stub, v.Hash())
 		}
+		api.filters.MarkPolled(rpchelper.SubscriptionID(cutIndex), polledHead)
 		return stub, nil
 	}
 	if txs, ok := api.filters.ReadPendingTxs(rpchelper.PendingTxsSubID(cutIndex)); ok {
@@ -103,6 +148,7 @@ func (api *APIImpl) GetFilterChanges(_ context.Context, index string) ([]any, er
 		for _, v := range logs {
 			stub = append(stub, v)
 		}
+		api.filters.MarkPolled(rpchelper.SubscriptionID(cutIndex), polledHead)
 		return stub, nil
 	}
 	return stub, nil
@@ -111,18 +157,106 @@ func (api *APIImpl) GetFilterChanges(_ context.Context, index string) ([]any, er
 // GetFilterLogs implements eth_getFilterLogs.
 // Polling method for a previously-created filter
 // returns an array of logs which occurred since last poll.
-func (api *APIImpl) GetFilterLogs(_ context.Context, index string) ([]*types.Log, error) {
+func (api *APIImpl) GetFilterLogs(ctx context.Context, index string) ([]*types.Log, error) {
 	if api.filters == nil {
 		return nil, rpc.ErrNotificationsUnsupported
 	}
 	cutIndex := strings.TrimPrefix(index, "0x")
+	if backlog, ok := api.filters.Backlog(rpchelper.SubscriptionID(cutIndex)); ok && backlog.Kind == rpchelper.FilterKindLogs {
+		return api.logsFilterBacklog(ctx, rpchelper.LogsSubID(cutIndex), backlog)
+	}
+	polledHead, err := api.persistentFilterPolledHead(ctx)
+	if err != nil {
+		return nil, err
+	}
 	logs, ok := api.filters.ReadLogs(rpchelper.LogsSubID(cutIndex))
+	if ok {
+		api.filters.MarkPolled(rpchelper.SubscriptionID(cutIndex), polledHead)
+	}
 	if len(logs) == 0 || !ok {
 		return []*types.Log{}, nil
 	}
 	return logs, nil
 }
 
+// persistentFilterPolledHead - position of a persistent filter after a poll: logs and blocks up to the latest block
+// are already in the filter's buffer when it's drained, so latest is read before. 0 if filters are not persistent.
+func (api *APIImpl) persistentFilterPolledHead(ctx context.Context) (uint64, error) {
+	if !api.filters.Persistent() {
+		return 0, nil
+	}
+	return api.latestExecutedBlockNumber(ctx)
+}
+
+// logsFilterBacklog - logs of filter restored after rpcdaemon restart: matching logs of blocks
+// produced while rpcdaemon was down, followed by logs which arrived since restart.
+func (api *APIImpl) logsFilterBacklog(ctx context.Context, id rpchelper.LogsSubID, backlog rpchelper.PersistedFilter) ([]*types.Log, error) {
+	latest, err := api.latestExecutedBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := []*types.Log{}
+	if from := backlog.BacklogFrom(latest); from <= latest {
+		logs, err := api.GetLogs(ctx, filters.FilterCriteria{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(latest),
+			Addresses: backlog.Addresses,
+			Topics:    backlog.Topics,
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, logs...)
+	}
+	// logs of blocks up to latest are already in the backlog
+	logs, _ := api.filters.ReadLogs(id)
+	for _, lg := range logs {
+		if lg.BlockNumber > latest {
+			res = append(res, lg)
+		}
+	}
+	api.filters.MarkPolled(rpchelper.SubscriptionID(id), latest)
+	return res, nil
+}
+
+// blockFilterBacklog - hashes of blocks produced since last poll of filter restored after rpcdaemon restart
+func (api *APIImpl) blockFilterBacklog(ctx context.Context, id rpchelper.HeadsSubID, backlog rpchelper.PersistedFilter) ([]common.Hash, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	latest, err := rpchelper.GetLatestExecutedBlockNumber(tx)
+	if err != nil {
+		return nil, err
+	}
+	res := []common.Hash{}
+	for blockNum := backlog.BacklogFrom(latest); blockNum <= latest; blockNum++ {
+		hash, err := api._blockReader.CanonicalHash(ctx, tx, blockNum)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, hash)
+	}
+	headers, _ := api.filters.ReadPendingBlocks(id)
+	for _, h := range headers {
+		if h.Number.Uint64() > latest {
+			res = append(res, h.Hash())
+		}
+	}
+	api.filters.MarkPolled(rpchelper.SubscriptionID(id), latest)
+	return res, nil
+}
+
+func (api *APIImpl) latestExecutedBlockNumber(ctx context.Context) (uint64, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	return rpchelper.GetLatestExecutedBlockNumber(tx)
+}
+
 // NewHeads send a notification each time a new (header) block is appended to the chain.
 func (api *APIImpl) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 	if api.filters == nil {
@@ -162,7 +296,9 @@ func (api *APIImpl) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 }
 
 // NewPendingTransactions send a notification each time when a transaction had added into mempool.
-func (api *APIImpl) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+// crit is either a bool (full transaction objects instead of hashes) or an object with fullTx and filters
+// of the transactions to notify about, see filters.PendingTxsCriteria.
+func (api *APIImpl) NewPendingTransactions(ctx context.Context, crit *filters.PendingTxsCriteria) (*rpc.Subscription, error) {
 	if api.filters == nil {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
@@ -172,6 +308,7 @@ func (api *APIImpl) NewPendingTransactions(ctx context.Context, fullTx *bool) (*
 	}
 
 	rpcSub := notifier.CreateSubscription()
+	fullTx := crit != nil && crit.FullTx
 
 	go func() {
 		defer debug.LogPanic()
@@ -182,17 +319,31 @@ func (api *APIImpl) NewPendingTransactions(ctx context.Context, fullTx *bool) (*
 			select {
 			case txs, ok := <-txsCh:
 				for _, t := range txs {
-					if t != nil {
-						var err error
-						if fullTx != nil && *fullTx {
-							err = notifier.Notify(rpcSub.ID, t)
-						} else {
-							err = notifier.Notify(rpcSub.ID, t.Hash())
-						}
+					if t == nil {
+						continue
+					}
+					// rpc representation recovers the sender, so it's built only when needed
+					var rpcTx *RPCTransaction
+					if fullTx || crit.NeedsSender() {
+						rpcTx = NewRPCTransaction(t, common.Hash{}, 0, 0, nil)
+					}
+					var sender common.Address
+					if rpcTx != nil {
+						sender = rpcTx.From
+					}
+					if !crit.Match(t, sender) {
+						continue
+					}
 
-						if err != nil {
-							log.Warn("[rpc] error while notifying subscription", "err", err)
-						}
+					var err error
+					if fullTx {
+						err = notifier.Notify(rpcSub.ID, rpcTx)
+					} else {
+						err = notifier.Notify(rpcSub.ID, t.Hash())
+					}
+
+					if err != nil {
+						log.Warn("[rpc] error while notifying subscription", "err", err)
 					}
 				}
 				if !ok {
@@ -1,7 +1,10 @@
 package jsonrpc
 
 import (
+	"context"
+	"math/big"
 	"math/rand"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -14,9 +17,11 @@ import (
 	txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/stages/mock"
 	"github.com/ledgerwatch/log/v3"
@@ -100,3 +105,62 @@ func TestLogsSubscribeAndUnsubscribe_WithoutConcurrentMapIssue(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestPersistentFilters(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	agg := m.HistoryV3Components()
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	newAPI := func() (*APIImpl, *rpchelper.FilterStore) {
+		store, err := rpchelper.OpenFilterStore(ctx, dir, m.Log)
+		require.NoError(t, err)
+		ff := rpchelper.New(ctx, nil, nil, nil, func() {}, m.Log)
+		require.NoError(t, ff.Restore(ctx, store, time.Hour))
+		return NewEthAPI(NewBaseApi(ff, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New()), store
+	}
+
+	api, store := newAPI()
+	latest, err := api.latestExecutedBlockNumber(ctx)
+	require.NoError(t, err)
+	require.Greater(t, latest, uint64(2))
+
+	lf, err := api.NewFilter(ctx, filters.FilterCriteria{})
+	require.NoError(t, err)
+	bf, err := api.NewBlockFilter(ctx)
+	require.NoError(t, err)
+	// pretend the client stopped polling a few blocks ago
+	api.filters.PersistLogsFilter(rpchelper.LogsSubID(strings.TrimPrefix(lf, "0x")), filters.FilterCriteria{}, 0)
+	api.filters.PersistBlockFilter(rpchelper.HeadsSubID(strings.TrimPrefix(bf, "0x")), latest-2)
+	store.Close()
+
+	api, store = newAPI()
+	defer store.Close()
+
+	expectedLogs, err := api.GetLogs(ctx, filters.FilterCriteria{FromBlock: big.NewInt(1), ToBlock: new(big.Int).SetUint64(latest)})
+	require.NoError(t, err)
+	require.NotEmpty(t, expectedLogs)
+	changes, err := api.GetFilterChanges(ctx, lf)
+	require.NoError(t, err)
+	require.Len(t, changes, len(expectedLogs))
+
+	changes, err = api.GetFilterChanges(ctx, bf)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	hash, err := api.GetBlockByNumber(ctx, rpc.BlockNumber(latest), false)
+	require.NoError(t, err)
+	require.Equal(t, hash["hash"], changes[1])
+
+	// backlog is delivered only once
+	changes, err = api.GetFilterChanges(ctx, lf)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	changes, err = api.GetFilterChanges(ctx, bf)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	ok, err := api.UninstallFilter(ctx, lf)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ledgerwatch/erigon/cmd/state/exec3"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
@@ -88,7 +89,6 @@ func (api *BaseAPI) getReceipts(ctx context.Context, tx kv.Tx, block *types.Bloc
 
 // GetLogs implements eth_getLogs. Returns an array of logs matching a given filter object.
 func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.Logs, error) {
-	var begin, end uint64
 	logs := types.Logs{}
 
 	tx, beginErr := api.db.BeginRo(ctx)
@@ -97,69 +97,77 @@ func (api *APIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (t
 	}
 	defer tx.Rollback()
 
+	begin, end, err := api.logsBlockRange(ctx, tx, crit)
+	if err != nil {
+		return nil, err
+	}
+	logs, _, err = api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit, 0, 0)
+	return logs, err
+}
+
+// logsBlockRange converts the block range of a logs filter into block numbers, [begin, end] inclusive.
+func (api *BaseAPI) logsBlockRange(ctx context.Context, tx kv.Tx, crit filters.FilterCriteria) (begin, end uint64, err error) {
 	if crit.BlockHash != nil {
 		block, err := api.blockByHashWithSenders(ctx, tx, *crit.BlockHash)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
 		}
 		if block == nil {
-			return nil, fmt.Errorf("block not found: %x", *crit.BlockHash)
+			return 0, 0, fmt.Errorf("block not found: %x", *crit.BlockHash)
 		}
 
 		num := block.NumberU64()
-		begin = num
-		end = num
-	} else {
-		// Convert the RPC block numbers into internal representations
-		latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestExecutedBlockNumber), tx, nil)
-		if err != nil {
-			return nil, err
-		}
+		return num, num, nil
+	}
 
-		begin = latest
-		if crit.FromBlock != nil {
-			fromBlock := crit.FromBlock.Int64()
-			if fromBlock > 0 {
-				begin = uint64(fromBlock)
-			} else {
-				blockNum := rpc.BlockNumber(fromBlock)
-				begin, _, _, err = rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNum), tx, api.filters)
-				if err != nil {
-					return nil, err
-				}
-			}
+	// Convert the RPC block numbers into internal representations
+	latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestExecutedBlockNumber), tx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
 
+	begin = latest
+	if crit.FromBlock != nil {
+		fromBlock := crit.FromBlock.Int64()
+		if fromBlock > 0 {
+			begin = uint64(fromBlock)
+		} else {
+			blockNum := rpc.BlockNumber(fromBlock)
+			begin, _, _, err = rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNum), tx, api.filters)
+			if err != nil {
+				return 0, 0, err
+			}
 		}
-		end = latest
-		if crit.ToBlock != nil {
-			toBlock := crit.ToBlock.Int64()
-			if toBlock > 0 {
-				end = uint64(toBlock)
-			} else {
-				blockNum := rpc.BlockNumber(toBlock)
-				end, _, _, err = rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNum), tx, api.filters)
-				if err != nil {
-					return nil, err
-				}
+
+	}
+	end = latest
+	if crit.ToBlock != nil {
+		toBlock := crit.ToBlock.Int64()
+		if toBlock > 0 {
+			end = uint64(toBlock)
+		} else {
+			blockNum := rpc.BlockNumber(toBlock)
+			end, _, _, err = rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNum), tx, api.filters)
+			if err != nil {
+				return 0, 0, err
 			}
 		}
 	}
 
 	if end < begin {
-		return nil, fmt.Errorf("end (%d) < begin (%d)", end, begin)
+		return 0, 0, fmt.Errorf("end (%d) < begin (%d)", end, begin)
 	}
 	if end > roaring.MaxUint32 {
 		latest, err := rpchelper.GetLatestBlockNumber(tx)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
 		}
 		if begin > latest {
-			return nil, fmt.Errorf("begin (%d) > latest (%d)", begin, latest)
+			return 0, 0, fmt.Errorf("begin (%d) > latest (%d)", begin, latest)
 		}
 		end = latest
 	}
-
-	return api.getLogsV3(ctx, tx.(kv.TemporalTx), begin, end, crit)
+	return begin, end, nil
 }
 
 // The Topic list restricts matches to particular event topics. Each event has a list
@@ -240,84 +248,49 @@ func applyFilters(out *roaring.Bitmap, tx kv.Tx, begin, end uint64, crit filters
 	return nil
 }
 
-/*
-
-func applyFiltersV3(out *roaring64.Bitmap, tx kv.TemporalTx, begin, end uint64, crit filters.FilterCriteria) error {
+// logsTxNumsV3 returns the txNums of the blocks [begin, end] matching the addresses and topics of crit, as
+// intersection of the log inverted indices (see getLogsTxNumsV3), so that only transactions which may have matching
+// logs are re-executed. The indices cover the whole history: both files and the db.
+func logsTxNumsV3(tx kv.TemporalTx, begin, end uint64, crit filters.FilterCriteria) (*roaring64.Bitmap, error) {
 	//[from,to)
 	var fromTxNum, toTxNum uint64
 	var err error
 	if begin > 0 {
 		fromTxNum, err = rawdbv3.TxNums.Min(tx, begin)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	toTxNum, err = rawdbv3.TxNums.Max(tx, end)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	toTxNum++
 
-	out.AddRange(fromTxNum, toTxNum) // [from,to)
-	topicsBitmap, err := getTopicsBitmapV3(tx, crit.Topics, fromTxNum, toTxNum)
-	if err != nil {
-		return err
-	}
-	if topicsBitmap != nil {
-		out.And(topicsBitmap)
-	}
-	addrBitmap, err := getAddrsBitmapV3(tx, crit.Addresses, fromTxNum, toTxNum)
+	out := roaring64.New()
+	it, err := getLogsTxNumsV3(tx, crit.Addresses, crit.Topics, int(fromTxNum), int(toTxNum), order.Asc)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if addrBitmap != nil {
-		out.And(addrBitmap)
+	if it == nil {
+		out.AddRange(fromTxNum, toTxNum)
+		return out, nil
 	}
-	return nil
-}
-*/
-
-func applyFiltersV3(tx kv.TemporalTx, begin, end uint64, crit filters.FilterCriteria) (out iter.U64, err error) {
-	//[from,to)
-	var fromTxNum, toTxNum uint64
-	if begin > 0 {
-		fromTxNum, err = rawdbv3.TxNums.Min(tx, begin)
+	for it.HasNext() {
+		txNum, err := it.Next()
 		if err != nil {
-			return out, err
-		}
-	}
-	toTxNum, err = rawdbv3.TxNums.Max(tx, end)
-	if err != nil {
-		return out, err
-	}
-	toTxNum++
-
-	topicsBitmap, err := getTopicsBitmapV3(tx, crit.Topics, fromTxNum, toTxNum)
-	if err != nil {
-		return out, err
-	}
-	if topicsBitmap != nil {
-		out = topicsBitmap
-	}
-	addrBitmap, err := getAddrsBitmapV3(tx, crit.Addresses, fromTxNum, toTxNum)
-	if err != nil {
-		return out, err
-	}
-	if addrBitmap != nil {
-		if out == nil {
-			out = addrBitmap
-		} else {
-			out = iter.Intersect[uint64](out, addrBitmap, -1)
+			return nil, err
 		}
-	}
-	if out == nil {
-		out = iter.Range[uint64](fromTxNum, toTxNum)
+		out.Add(txNum)
 	}
 	return out, nil
 }
 
-func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end uint64, crit filters.FilterCriteria) ([]*types.Log, error) {
-	logs := []*types.Log{}
+// getLogsV3 returns the logs of the blocks [begin, end] matching crit. Transactions before the txNum continuation are
+// skipped. If budget > 0, at most budget transactions are re-executed, and next is the continuation to get the
+// remaining logs from, 0 once all of them are returned.
+func (api *BaseAPI) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end uint64, crit filters.FilterCriteria, continuation uint64, budget int) (logs []*types.Log, next uint64, err error) {
+	logs = []*types.Log{}
 
 	addrMap := make(map[common.Address]struct{}, len(crit.Addresses))
 	for _, v := range crit.Addresses {
@@ -326,35 +299,40 @@ func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end
 
 	chainConfig, err := api.chainConfig(ctx, tx)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	exec := exec3.NewTraceWorker(tx, chainConfig, api.engine(), api._blockReader, nil)
 
 	var blockHash common.Hash
 	var header *types.Header
 
-	txNumbers, err := applyFiltersV3(tx, begin, end, crit)
+	txNumbers, err := logsTxNumsV3(tx, begin, end, crit)
 	if err != nil {
-		return logs, err
+		return logs, 0, err
 	}
-	iter := rawdbv3.TxNums2BlockNums(tx, txNumbers, order.Asc)
+	txNumbers.RemoveRange(0, continuation)
+	iter := rawdbv3.TxNums2BlockNums(tx, bitmapdb.NewBitmapStream(txNumbers), order.Asc)
 	defer iter.Close()
+	var executed int
 	for iter.HasNext() {
 		if err = ctx.Err(); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		txNum, blockNum, txIndex, isFinalTxn, blockNumChanged, err := iter.Next()
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		if isFinalTxn {
 			continue
 		}
+		if budget > 0 && executed >= budget {
+			return logs, txNum, nil
+		}
 
 		// if block number changed, calculate all related field
-		if blockNumChanged {
+		if blockNumChanged || header == nil {
 			if header, err = api._blockReader.HeaderByNumber(ctx, tx, blockNum); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 			if header == nil {
 				log.Warn("[rpc] header is nil", "blockNum", blockNum)
@@ -367,7 +345,7 @@ func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end
 		//fmt.Printf("txNum=%d, blockNum=%d, txIndex=%d, maxTxNumInBlock=%d,mixTxNumInBlock=%d\n", txNum, blockNum, txIndex, maxTxNumInBlock, minTxNumInBlock)
 		txn, err := api._txnReader.TxnByIdxInBlock(ctx, tx, blockNum, txIndex)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		if txn == nil {
 			continue
@@ -375,8 +353,9 @@ func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end
 
 		_, err = exec.ExecTxn(txNum, txIndex, txn)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		executed++
 		rawLogs := exec.GetLogs(txIndex, txn)
 		//TODO: logIndex within the block! no way to calc it now
 		//logIndex := uint(0)
@@ -395,7 +374,7 @@ func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end
 
 	//stats := api._agg.GetAndResetStats()
 	//log.Info("Finished", "duration", time.Since(start), "history queries", stats.FilesQueries, "ef search duration", stats.EfSearchTime)
-	return logs, nil
+	return logs, 0, nil
 }
 
 // The Topic list restricts matches to particular event topics. Each event has a list
@@ -409,38 +388,64 @@ func (api *APIImpl) getLogsV3(ctx context.Context, tx kv.TemporalTx, begin, end
 // {{}, {B}}          matches any topic in first position AND B in second position
 // {{A}, {B}}         matches topic A in first position AND B in second position
 // {{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
-func getTopicsBitmapV3(tx kv.TemporalTx, topics [][]common.Hash, from, to uint64) (res iter.U64, err error) {
+func getTopicsBitmapV3(tx kv.TemporalTx, topics [][]common.Hash, from, to int, asc order.By) (res iter.U64, err error) {
 	for _, sub := range topics {
 
 		var topicsUnion iter.U64
 		for _, topic := range sub {
-			it, err := tx.IndexRange(kv.LogTopicIdx, topic.Bytes(), int(from), int(to), order.Asc, kv.Unlim)
+			it, err := tx.IndexRange(kv.LogTopicIdx, topic.Bytes(), from, to, asc, kv.Unlim)
 			if err != nil {
 				return nil, err
 			}
-			topicsUnion = iter.Union[uint64](topicsUnion, it, order.Asc, -1)
+			topicsUnion = iter.Union[uint64](topicsUnion, it, asc, -1)
+		}
+		// Empty sub-list matches any topic, so it doesn't narrow down the txNums. Intersecting with it (nil)
+		// used to return no txNums at all, e.g. for {{A}, {}}. The index doesn't record topic positions:
+		// the result is a superset, positions and topics count are checked by Logs.Filter.
+		if topicsUnion == nil {
+			continue
 		}
 
 		if res == nil {
 			res = topicsUnion
 			continue
 		}
-		res = iter.Intersect[uint64](res, topicsUnion, -1)
+		res = iter.Intersect[uint64](res, topicsUnion, asc, -1)
 	}
 	return res, nil
 }
 
-func getAddrsBitmapV3(tx kv.TemporalTx, addrs []common.Address, from, to uint64) (res iter.U64, err error) {
+func getAddrsBitmapV3(tx kv.TemporalTx, addrs []common.Address, from, to int, asc order.By) (res iter.U64, err error) {
 	for _, addr := range addrs {
-		it, err := tx.IndexRange(kv.LogAddrIdx, addr[:], int(from), int(to), true, kv.Unlim)
+		it, err := tx.IndexRange(kv.LogAddrIdx, addr[:], from, to, asc, kv.Unlim)
 		if err != nil {
 			return nil, err
 		}
-		res = iter.Union[uint64](res, it, order.Asc, -1)
+		res = iter.Union[uint64](res, it, asc, -1)
 	}
 	return res, nil
 }
 
+// getLogsTxNumsV3 - txNums which may have logs matching addresses and topics: intersection of getTopicsBitmapV3 and
+// getAddrsBitmapV3. nil if neither addresses nor topics restrict the txNums.
+func getLogsTxNumsV3(tx kv.TemporalTx, addrs []common.Address, topics [][]common.Hash, from, to int, asc order.By) (iter.U64, error) {
+	txNums, err := getTopicsBitmapV3(tx, topics, from, to, asc)
+	if err != nil {
+		return nil, err
+	}
+	addrTxNums, err := getAddrsBitmapV3(tx, addrs, from, to, asc)
+	if err != nil {
+		return nil, err
+	}
+	if addrTxNums == nil {
+		return txNums, nil
+	}
+	if txNums == nil {
+		return addrTxNums, nil
+	}
+	return iter.Intersect[uint64](txNums, addrTxNums, asc, kv.Unlim), nil
+}
+
 // GetTransactionReceipt implements eth_getTransactionReceipt. Returns the receipt of a transaction given the transaction's hash.
 func (api *APIImpl) GetTransactionReceipt(ctx context.Context, txnHash common.Hash) (map[string]interface{}, error) {
 	tx, err := api.db.BeginRo(ctx)
@@ -0,0 +1,542 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+const (
+	// maxSimulateBlocks - limit of simulated blocks per request, including the ones filling gaps between requested blocks
+	maxSimulateBlocks = 256
+	// simulateTimestampIncrement - default time between simulated blocks
+	simulateTimestampIncrement = 12
+)
+
+// error codes defined by eth_simulateV1 spec
+const (
+	simulateErrCodeReverted         = 3
+	simulateErrCodeVMError          = -32015
+	simulateErrCodeInvalidTx        = -38014
+	simulateErrCodeBlockNumInvalid  = -38020
+	simulateErrCodeTimestampInvalid = -38021
+	simulateErrCodeTooManyBlocks    = -38026
+)
+
+var (
+	// transferAddress - pseudo address which emits logs of ether transfers when SimulationOpts.TraceTransfers is set
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+	// transferTopic - keccak256("Transfer(address,address,uint256)"), same as ERC-20 transfer event
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// SimulationOpts - parameters of eth_simulateV1
+type SimulationOpts struct {
+	BlockStateCalls        []SimulatedBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers"`
+	Validation             bool             `json:"validation"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions"`
+}
+
+type SimulatedBlock struct {
+	BlockOverrides *SimulatedBlockOverrides `json:"blockOverrides"`
+	StateOverrides *ethapi.StateOverrides   `json:"stateOverrides"`
+	Calls          []ethapi.CallArgs        `json:"calls"`
+}
+
+type SimulatedBlockOverrides struct {
+	Number        *hexutil.Uint64 `json:"number"`
+	Time          *hexutil.Uint64 `json:"time"`
+	GasLimit      *hexutil.Uint64 `json:"gasLimit"`
+	FeeRecipient  *common.Address `json:"feeRecipient"`
+	PrevRandao    *common.Hash    `json:"prevRandao"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas"`
+}
+
+type SimulatedCallResult struct {
+	ReturnData hexutility.Bytes    `json:"returnData"`
+	Logs       []*types.Log        `json:"logs"`
+	GasUsed    hexutil.Uint64      `json:"gasUsed"`
+	Status     hexutil.Uint64      `json:"status"`
+	Error      *SimulatedCallError `json:"error,omitempty"`
+}
+
+type SimulatedCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. Executes calls in a sequence of simulated blocks on top of blockNrOrHash
+// (latest if not set), each block may override block header fields and state before its calls.
+// Returns simulated blocks with results of their calls.
+// Note: state root of simulated blocks is not calculated and is always empty.
+func (api *APIImpl) SimulateV1(ctx context.Context, opts SimulationOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &rpc.InvalidParamsError{Message: "empty input"}
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &rpc.CustomError{Code: simulateErrCodeTooManyBlocks, Message: fmt.Sprintf("too many blocks, max %d", maxSimulateBlocks)}
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNum, hash, _, err := rpchelper.GetBlockNumber(bNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := api._blockReader.Header(ctx, tx, hash, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("block %d(%x) not found", blockNum, hash)
+	}
+
+	blocks, err := sanitizeSimulatedBlocks(parent, opts.BlockStateCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, bNrOrHash, 0, api.filters, api.stateCache, chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+
+	defer func(start time.Time) { log.Trace("Executing EVM simulateV1 finished", "runtime", time.Since(start)) }(time.Now())
+
+	timeout := api.evmCallTimeout
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// simulated blocks are not in db, so their hashes are resolved here
+	simulatedHashes := make(map[uint64]common.Hash, len(blocks))
+	getHash := func(i uint64) common.Hash {
+		if hash, ok := simulatedHashes[i]; ok {
+			return hash
+		}
+		hash, err := api._blockReader.CanonicalHash(ctx, tx, i)
+		if err != nil {
+			log.Debug("Can't get block hash by number", "number", i, "only-canonical", true)
+		}
+		return hash
+	}
+
+	results := make([]map[string]interface{}, 0, len(blocks))
+	for _, simBlock := range blocks {
+		block, callResults, err := api.simulateBlock(ctx, chainConfig, ibs, parent, simBlock, getHash, &opts)
+		if err != nil {
+			return nil, err
+		}
+		simulatedHashes[block.NumberU64()] = block.Hash()
+		parent = block.Header()
+
+		fields, err := ethapi.RPCMarshalBlock(block, true, false, map[string]interface{}{"calls": callResults})
+		if err != nil {
+			return nil, err
+		}
+		if opts.ReturnFullTransactions {
+			txs := make([]interface{}, len(block.Transactions()))
+			for i, txn := range block.Transactions() {
+				txs[i] = NewRPCTransaction(txn, block.Hash(), block.NumberU64(), uint64(i), block.BaseFee())
+			}
+			fields["transactions"] = txs
+		}
+		results = append(results, fields)
+	}
+	return results, nil
+}
+
+// sanitizeSimulatedBlocks checks that block numbers and timestamps are increasing, sets defaults of
+// omitted block numbers and timestamps and fills gaps between requested block numbers with empty blocks
+func sanitizeSimulatedBlocks(parent *types.Header, blocks []SimulatedBlock) ([]SimulatedBlock, error) {
+	res := make([]SimulatedBlock, 0, len(blocks))
+	prevNumber, prevTime := parent.Number.Uint64(), parent.Time
+	for _, block := range blocks {
+		var overrides SimulatedBlockOverrides
+		if block.BlockOverrides != nil {
+			overrides = *block.BlockOverrides
+		}
+		if overrides.Number == nil {
+			n := hexutil.Uint64(prevNumber + 1)
+			overrides.Number = &n
+		}
+		number := uint64(*overrides.Number)
+		if number <= prevNumber {
+			return nil, &rpc.CustomError{Code: simulateErrCodeBlockNumInvalid, Message: fmt.Sprintf("block numbers must be in order: %d <= %d", number, prevNumber)}
+		}
+		if number-parent.Number.Uint64() > maxSimulateBlocks {
+			return nil, &rpc.CustomError{Code: simulateErrCodeTooManyBlocks, Message: fmt.Sprintf("too many blocks, max %d", maxSimulateBlocks)}
+		}
+		for gap := prevNumber + 1; gap < number; gap++ {
+			prevTime += simulateTimestampIncrement
+			n, t := hexutil.Uint64(gap), hexutil.Uint64(prevTime)
+			res = append(res, SimulatedBlock{BlockOverrides: &SimulatedBlockOverrides{Number: &n, Time: &t}})
+		}
+
+		if overrides.Time == nil {
+			t := hexutil.Uint64(prevTime + simulateTimestampIncrement)
+			overrides.Time = &t
+		}
+		if uint64(*overrides.Time) <= prevTime {
+			return nil, &rpc.CustomError{Code: simulateErrCodeTimestampInvalid, Message: fmt.Sprintf("block timestamps must be in order: %d <= %d", uint64(*overrides.Time), prevTime)}
+		}
+
+		prevNumber, prevTime = number, uint64(*overrides.Time)
+		block.BlockOverrides = &overrides
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+func (api *APIImpl) simulateBlock(
+	ctx context.Context,
+	chainConfig *chain.Config,
+	ibs *state.IntraBlockState,
+	parent *types.Header,
+	simBlock SimulatedBlock,
+	getHash func(uint64) common.Hash,
+	opts *SimulationOpts,
+) (*types.Block, []SimulatedCallResult, error) {
+	header := makeSimulatedHeader(chainConfig, parent, simBlock.BlockOverrides, opts.Validation)
+	blockNum := header.Number.Uint64()
+
+	if simBlock.StateOverrides != nil {
+		if err := simBlock.StateOverrides.Override(ibs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	blockCtx := core.NewEVMBlockContext(header, getHash, api.engine(), &header.Coinbase)
+	rules := chainConfig.Rules(blockNum, header.Time)
+	signer := types.MakeSigner(chainConfig, blockNum, header.Time)
+	gp := new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(math.MaxUint64)
+
+	var (
+		txs         = make(types.Transactions, 0, len(simBlock.Calls))
+		receipts    = make(types.Receipts, 0, len(simBlock.Calls))
+		callResults = make([]SimulatedCallResult, 0, len(simBlock.Calls))
+		callLogs    = make([][]*types.Log, 0, len(simBlock.Calls))
+	)
+	for i, args := range simBlock.Calls {
+		txn, msg, err := simulatedTransaction(chainConfig, ibs, header, gp, args, api.GasCap, signer, rules, opts.Validation)
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simulateErrCodeInvalidTx, Message: fmt.Sprintf("call %d of block %d: %v", i, blockNum, err)}
+		}
+
+		ibs.SetTxContext(txn.Hash(), common.Hash{}, i)
+		vmConfig := vm.Config{NoBaseFee: !opts.Validation}
+		var tracer *transferTracer
+		if opts.TraceTransfers {
+			tracer = newTransferTracer(ibs, txn.Hash())
+			vmConfig.Debug, vmConfig.Tracer = true, tracer
+		}
+		evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), ibs, chainConfig, vmConfig)
+
+		// Wait for the context to be done and cancel the evm. Even if the
+		// EVM has finished, cancelling may be done (repeatedly)
+		callCtx, cancelCall := context.WithCancel(ctx)
+		go func() {
+			<-callCtx.Done()
+			evm.Cancel()
+		}()
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+		cancelCall()
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("execution aborted (timeout = %v)", api.evmCallTimeout)
+		}
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simulateErrCodeInvalidTx, Message: fmt.Sprintf("call %d of block %d: %v", i, blockNum, err)}
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, nil, err
+		}
+
+		header.GasUsed += result.UsedGas
+		receipt := &types.Receipt{
+			Type:              txn.Type(),
+			CumulativeGasUsed: header.GasUsed,
+			GasUsed:           result.UsedGas,
+			TxHash:            txn.Hash(),
+			TransactionIndex:  uint(i),
+			Logs:              ibs.GetLogs(txn.Hash()),
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), txn.GetNonce())
+		}
+
+		callResult := SimulatedCallResult{
+			ReturnData: result.Return(),
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+			Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+			callResult.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			if len(result.Revert()) > 0 {
+				revertErr := ethapi.NewRevertError(result)
+				callResult.Error = &SimulatedCallError{Code: simulateErrCodeReverted, Message: revertErr.Error(), Data: hexutility.Encode(result.Revert())}
+			} else {
+				callResult.Error = &SimulatedCallError{Code: simulateErrCodeVMError, Message: result.Err.Error()}
+			}
+		} else {
+			receipt.Status = types.ReceiptStatusSuccessful
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		logs := receipt.Logs
+		if tracer != nil {
+			// transfers are reported in results only, receipts keep logs which were actually emitted
+			logs = tracer.mergeLogs(logs)
+		}
+		txs = append(txs, txn)
+		receipts = append(receipts, receipt)
+		callResults = append(callResults, callResult)
+		callLogs = append(callLogs, logs)
+	}
+
+	var withdrawals []*types.Withdrawal
+	if chainConfig.IsShanghai(header.Time) {
+		withdrawals = []*types.Withdrawal{}
+	}
+	block := types.NewBlock(header, txs, nil, receipts, withdrawals, nil)
+
+	// fill in block related log fields once the block hash is known
+	var logIndex uint
+	for i := range callResults {
+		logs := make([]*types.Log, len(callLogs[i]))
+		for j, l := range callLogs[i] {
+			cpy := *l
+			cpy.BlockNumber = blockNum
+			cpy.BlockHash = block.Hash()
+			cpy.TxHash = txs[i].Hash()
+			cpy.TxIndex = uint(i)
+			cpy.Index = logIndex
+			logs[j] = &cpy
+			logIndex++
+		}
+		callResults[i].Logs = logs
+	}
+	return block, callResults, nil
+}
+
+func makeSimulatedHeader(chainConfig *chain.Config, parent *types.Header, overrides *SimulatedBlockOverrides, validation bool) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int).Set(parent.Difficulty),
+		Number:     new(big.Int).SetUint64(uint64(*overrides.Number)),
+		GasLimit:   parent.GasLimit,
+		Time:       uint64(*overrides.Time),
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.PrevRandao != nil {
+		header.MixDigest = *overrides.PrevRandao
+	}
+	if chainConfig.IsLondon(header.Number.Uint64()) {
+		switch {
+		case overrides.BaseFeePerGas != nil:
+			header.BaseFee = overrides.BaseFeePerGas.ToInt()
+		case validation:
+			header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+		default:
+			// without validation calls don't have to pay for gas
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if chainConfig.IsCancun(header.Time) {
+		header.BlobGasUsed, header.ExcessBlobGas = new(uint64), new(uint64)
+		header.ParentBeaconBlockRoot = new(common.Hash)
+	}
+	return header
+}
+
+// simulatedTransaction makes unsigned transaction from call args to be included into simulated block. Omitted nonce
+// and gas are taken from state and remaining block gas.
+func simulatedTransaction(
+	chainConfig *chain.Config,
+	ibs *state.IntraBlockState,
+	header *types.Header,
+	gp *core.GasPool,
+	args ethapi.CallArgs,
+	gasCap uint64,
+	signer *types.Signer,
+	rules *chain.Rules,
+	validation bool,
+) (types.Transaction, types.Message, error) {
+	from := common.Address{}
+	if args.From != nil {
+		from = *args.From
+	}
+	nonce := ibs.GetNonce(from)
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	}
+	if args.Gas == nil {
+		gas := hexutil.Uint64(gp.Gas())
+		args.Gas = &gas
+	}
+
+	var baseFee *uint256.Int
+	if header.BaseFee != nil {
+		// ToMessage modifies passed base fee
+		baseFee, _ = uint256.FromBig(header.BaseFee)
+	}
+	msg, err := args.ToMessage(gasCap, baseFee)
+	if err != nil {
+		return nil, types.Message{}, err
+	}
+
+	commonTx := types.CommonTx{
+		Nonce: nonce,
+		Gas:   msg.Gas(),
+		To:    msg.To(),
+		Value: msg.Value(),
+		Data:  msg.Data(),
+	}
+	var txn types.Transaction
+	if header.BaseFee == nil {
+		txn = &types.LegacyTx{CommonTx: commonTx, GasPrice: msg.GasPrice()}
+	} else {
+		txn = &types.DynamicFeeTransaction{
+			CommonTx:   commonTx,
+			ChainID:    uint256.MustFromBig(chainConfig.ChainID),
+			Tip:        msg.Tip(),
+			FeeCap:     msg.FeeCap(),
+			AccessList: msg.AccessList(),
+		}
+	}
+	txn.SetSender(from)
+
+	if validation {
+		// message made of transaction checks nonce
+		msg, err = txn.AsMessage(*signer, header.BaseFee, rules)
+		if err != nil {
+			return nil, types.Message{}, err
+		}
+	}
+	return txn, msg, nil
+}
+
+// transferTracer collects ether transfers of a transaction as ERC-20 like Transfer logs emitted by transferAddress
+type transferTracer struct {
+	ibs    *state.IntraBlockState
+	txHash common.Hash
+
+	transfers []simulatedTransfer
+	frames    []int // number of transfers at the beginning of each call frame, to drop the ones of reverted frames
+}
+
+type simulatedTransfer struct {
+	log      *types.Log
+	logCount int // number of logs emitted by transaction before the transfer
+}
+
+func newTransferTracer(ibs *state.IntraBlockState, txHash common.Hash) *transferTracer {
+	return &transferTracer{ibs: ibs, txHash: txHash}
+}
+
+func (t *transferTracer) enter(typ vm.OpCode, from, to common.Address, value *uint256.Int) {
+	t.frames = append(t.frames, len(t.transfers))
+	if typ == vm.DELEGATECALL || value == nil || value.IsZero() {
+		return
+	}
+	amount := value.Bytes32()
+	t.transfers = append(t.transfers, simulatedTransfer{
+		log: &types.Log{
+			Address: transferAddress,
+			Topics:  []common.Hash{transferTopic, common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+			Data:    amount[:],
+		},
+		logCount: len(t.ibs.GetLogs(t.txHash)),
+	})
+}
+
+func (t *transferTracer) exit(err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	start := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if err != nil {
+		t.transfers = t.transfers[:start]
+	}
+}
+
+// mergeLogs inserts transfers between logs emitted by transaction in order of their occurrence
+func (t *transferTracer) mergeLogs(logs []*types.Log) []*types.Log {
+	res := make([]*types.Log, 0, len(logs)+len(t.transfers))
+	next := 0
+	for i := 0; i <= len(logs); i++ {
+		for ; next < len(t.transfers) && t.transfers[next].logCount <= i; next++ {
+			res = append(res, t.transfers[next].log)
+		}
+		if i < len(logs) {
+			res = append(res, logs[i])
+		}
+	}
+	return res
+}
+
+func (t *transferTracer) CaptureTxStart(gasLimit uint64) {}
+func (t *transferTracer) CaptureTxEnd(restGas uint64)    {}
+func (t *transferTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.enter(vm.CALL, from, to, value)
+}
+func (t *transferTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
+	t.exit(err)
+}
+func (t *transferTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.enter(typ, from, to, value)
+}
+func (t *transferTracer) CaptureExit(output []byte, usedGas uint64, err error) {
+	t.exit(err)
+}
+func (t *transferTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *transferTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
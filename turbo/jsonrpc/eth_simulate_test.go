@@ -0,0 +1,114 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+
+	"github.com/ledgerwatch/erigon/accounts/abi/bind"
+	"github.com/ledgerwatch/erigon/accounts/abi/bind/backends"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc/contracts"
+)
+
+func TestSimulateV1(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address  = crypto.PubkeyToAddress(key.PublicKey)
+		address1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		poor     = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		gspec    = &types.Genesis{
+			Config:   params.TestChainConfig,
+			Alloc:    types.GenesisAlloc{address: {Balance: big.NewInt(9000000000000000000)}},
+			GasLimit: 10000000,
+		}
+		chainID = big.NewInt(1337)
+		ctx     = context.Background()
+	)
+
+	transactOpts, _ := bind.NewKeyedTransactorWithChainID(key, chainID)
+	contractBackend := backends.NewTestSimulatedBackendWithConfig(t, gspec.Alloc, gspec.Config, gspec.GasLimit)
+	defer contractBackend.Close()
+	tokenAddr, _, _, err := contracts.DeployToken(transactOpts, contractBackend, address)
+	require.NoError(t, err)
+	contractBackend.Commit()
+
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, contractBackend.BlockReader(), contractBackend.Agg(), false, rpccfg.DefaultEvmCallTimeout, contractBackend.Engine(),
+		datadir.New(t.TempDir())), contractBackend.DB(), nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New())
+
+	value := (*hexutil.Big)(big.NewInt(1000))
+	poorBalance := (*hexutil.Big)(big.NewInt(1_000_000))
+	unknownMethod := hexutility.Bytes{0xde, 0xad, 0xbe, 0xef}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	gapNumber := hexutil.Uint64(4)
+
+	res, err := api.SimulateV1(ctx, SimulationOpts{
+		TraceTransfers: true,
+		BlockStateCalls: []SimulatedBlock{
+			{
+				Calls: []ethapi.CallArgs{
+					{From: &address, To: &address1, Value: value},
+					{From: &address, To: &tokenAddr, Data: &unknownMethod},
+				},
+			},
+			{
+				BlockOverrides: &SimulatedBlockOverrides{Number: &gapNumber},
+				StateOverrides: &ethapi.StateOverrides{poor: {Balance: &poorBalance}},
+				Calls:          []ethapi.CallArgs{{From: &poor, To: &address1, Value: value}},
+			},
+		},
+	}, &latest)
+	require.NoError(t, err)
+	// block 3 fills the gap between requested blocks 2 and 4
+	require.Len(t, res, 3)
+	for i, block := range res {
+		require.Equal(t, uint64(i+2), block["number"].(*hexutil.Big).ToInt().Uint64())
+		if i > 0 {
+			require.Equal(t, res[i-1]["hash"], block["parentHash"])
+		}
+	}
+
+	calls := res[0]["calls"].([]SimulatedCallResult)
+	require.Len(t, calls, 2)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	require.Len(t, calls[0].Logs, 1)
+	require.Equal(t, transferAddress, calls[0].Logs[0].Address)
+	require.Equal(t, []common.Hash{transferTopic, common.BytesToHash(address[:]), common.BytesToHash(address1[:])}, calls[0].Logs[0].Topics)
+	require.Equal(t, res[0]["hash"], calls[0].Logs[0].BlockHash)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusFailed), calls[1].Status)
+	require.NotNil(t, calls[1].Error)
+	require.Empty(t, calls[1].Logs)
+
+	require.Empty(t, res[1]["calls"].([]SimulatedCallResult))
+
+	// balance of poor account comes from state override
+	calls = res[2]["calls"].([]SimulatedCallResult)
+	require.Len(t, calls, 1)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	require.Equal(t, common.BytesToHash(poor[:]), calls[0].Logs[0].Topics[1])
+
+	// block numbers must increase
+	prevNumber := hexutil.Uint64(1)
+	_, err = api.SimulateV1(ctx, SimulationOpts{
+		BlockStateCalls: []SimulatedBlock{{BlockOverrides: &SimulatedBlockOverrides{Number: &prevNumber}}},
+	}, &latest)
+	var rpcErr *rpc.CustomError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, simulateErrCodeBlockNumInvalid, rpcErr.Code)
+}
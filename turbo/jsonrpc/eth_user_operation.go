@@ -0,0 +1,120 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/eth/aapool"
+	"github.com/ledgerwatch/erigon/rpc"
+	ethapi2 "github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+// UserOperationAPI - ERC-4337 bundler methods of the eth namespace, served by nodes running the account abstraction lane
+type UserOperationAPI interface {
+	SendUserOperation(ctx context.Context, op aapool.UserOperation, entryPoint libcommon.Address) (libcommon.Hash, error)
+	SupportedEntryPoints(ctx context.Context) ([]libcommon.Address, error)
+	GetUserOperationByHash(ctx context.Context, hash libcommon.Hash) (*aapool.UserOperation, error)
+}
+
+type UserOperationAPIImpl struct {
+	*BaseAPI
+	db     kv.RoDB
+	pool   *aapool.Pool
+	GasCap uint64
+}
+
+// NewUserOperationAPI returns UserOperationAPIImpl instance
+func NewUserOperationAPI(base *BaseAPI, db kv.RoDB, pool *aapool.Pool, gascap uint64) *UserOperationAPIImpl {
+	return &UserOperationAPIImpl{
+		BaseAPI: base,
+		db:      db,
+		pool:    pool,
+		GasCap:  gascap,
+	}
+}
+
+// SendUserOperation implements eth_sendUserOperation. The operation is validated by EntryPoint.simulateValidation
+// on top of the latest block and kept in the node's pool until the block producer bundles it.
+func (api *UserOperationAPIImpl) SendUserOperation(ctx context.Context, op aapool.UserOperation, entryPoint libcommon.Address) (libcommon.Hash, error) {
+	if entryPoint != api.pool.EntryPoint() {
+		return libcommon.Hash{}, fmt.Errorf("unsupported entry point %x", entryPoint)
+	}
+
+	data, err := aapool.PackSimulateValidation(&op)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	blockNumber, hash, _, err := rpchelper.GetCanonicalBlockNumber(latest, tx, api.filters)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	block, err := api.blockWithSenders(ctx, tx, hash, blockNumber)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if block == nil {
+		return libcommon.Hash{}, fmt.Errorf("block %d not found", blockNumber)
+	}
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, latest, 0, api.filters, api.stateCache, chainConfig.ChainName)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	args := ethapi2.CallArgs{
+		To:   &entryPoint,
+		Gas:  (*hexutil.Uint64)(&api.GasCap),
+		Data: (*hexutility.Bytes)(&data),
+	}
+	result, err := transactions.DoCall(ctx, api.engine(), args, tx, latest, block.HeaderNoCopy(), nil, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+
+	validation, err := aapool.UnpackValidationRevert(result.Revert())
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if validation.SigFailed {
+		return libcommon.Hash{}, fmt.Errorf("%w: invalid signature", aapool.ErrValidation)
+	}
+	if validation.ValidUntil != 0 && validation.ValidUntil <= uint64(time.Now().Unix()) {
+		return libcommon.Hash{}, fmt.Errorf("%w: expired at %d", aapool.ErrValidation, validation.ValidUntil)
+	}
+
+	return api.pool.Add(&op, validation.ValidUntil)
+}
+
+// SupportedEntryPoints implements eth_supportedEntryPoints
+func (api *UserOperationAPIImpl) SupportedEntryPoints(ctx context.Context) ([]libcommon.Address, error) {
+	return []libcommon.Address{api.pool.EntryPoint()}, nil
+}
+
+// GetUserOperationByHash returns operation pending in the pool, nil if not found
+func (api *UserOperationAPIImpl) GetUserOperationByHash(ctx context.Context, hash libcommon.Hash) (*aapool.UserOperation, error) {
+	op, ok := api.pool.Get(hash)
+	if !ok {
+		return nil, nil
+	}
+	return op, nil
+}
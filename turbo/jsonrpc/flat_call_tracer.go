@@ -0,0 +1,84 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ledgerwatch/erigon/eth/tracers"
+)
+
+const flatCallTracerName = "flatCallTracer"
+
+func init() {
+	tracers.RegisterLookup(false, func(name string, ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
+		if name != flatCallTracerName {
+			return nil, errors.New("no tracer found")
+		}
+		return newFlatCallTracer(ctx, cfg)
+	})
+}
+
+type flatCallTracerConfig struct {
+	VmTrace bool `json:"vmTrace"` // If true, the result also has the vmTrace of the transaction
+}
+
+// flatCallTracer - debug_trace* tracer which returns the Parity-style traces of trace_transaction, built by the
+// same OeTracer as the trace_ module. With `vmTrace` the result is the one of trace_replayTransaction
+// with ["trace", "vmTrace"]: {output, trace, vmTrace}.
+type flatCallTracer struct {
+	OeTracer
+	ctx    *tracers.Context
+	config flatCallTracerConfig
+	reason error // Textual reason for the interruption
+}
+
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*flatCallTracer, error) {
+	var config flatCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	if ctx == nil {
+		ctx = &tracers.Context{}
+	}
+
+	t := &flatCallTracer{ctx: ctx, config: config}
+	t.r = &TraceCallResult{Trace: []*ParityTrace{}}
+	t.traceAddr = []int{}
+	if config.VmTrace {
+		t.r.VmTrace = &VmTrace{Ops: []*VmTraceOp{}}
+	}
+	return t, nil
+}
+
+// GetResult returns the json-encoded flat list of traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	blockNumber := t.ctx.BlockNumber
+	txPosition := uint64(t.ctx.TxIndex)
+	for _, trace := range t.r.Trace {
+		trace.BlockHash = &t.ctx.BlockHash
+		trace.BlockNumber = &blockNumber
+		trace.TransactionHash = &t.ctx.TxHash
+		trace.TransactionPosition = &txPosition
+	}
+
+	var res []byte
+	var err error
+	if t.config.VmTrace {
+		t.r.TransactionHash = &t.ctx.TxHash
+		res, err = json.Marshal(t.r)
+	} else {
+		res, err = json.Marshal(t.r.Trace)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop records the reason of the interruption, the traces collected so far are discarded with it.
+func (t *flatCallTracer) Stop(err error) {
+	t.reason = err
+}
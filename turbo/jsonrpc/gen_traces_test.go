@@ -31,7 +31,7 @@ func TestGeneratedDebugApi(t *testing.T) {
 	agg := m.HistoryV3Components()
 	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
 	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
-	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0, 0, 0)
 	var buf bytes.Buffer
 	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
 	callTracer := "callTracer"
@@ -339,3 +339,40 @@ func TestGeneratedTraceApiCollision(t *testing.T) {
 		t.Fatalf("not equal")
 	}
 }
+
+func TestGeneratedFlatCallTracer(t *testing.T) {
+	m := rpcdaemontest.CreateTestSentryForTracesCollision(t)
+	baseApi := newBaseApiForTest(m)
+	txHash := common.HexToHash("0xb2b9fa4c999c1c8370ce1fbd1c4315a9ce7f8421fe2ebed8a9051ff2e4e7e3da")
+
+	traces, err := NewTraceAPI(baseApi, m.DB, &httpcfg.HttpCfg{}).Transaction(context.Background(), txHash, new(bool))
+	if err != nil {
+		t.Fatalf("trace_transaction: %v", err)
+	}
+	buf, err := json.Marshal(traces)
+	if err != nil {
+		t.Fatalf("marshall result into JSON: %v", err)
+	}
+	var expected interface{}
+	if err = json.Unmarshal(buf, &expected); err != nil {
+		t.Fatalf("parsing expected: %v", err)
+	}
+
+	var debugBuf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &debugBuf, 4096)
+	flatCallTracer := flatCallTracerName
+	err = NewPrivateDebugAPI(baseApi, m.DB, 0, 0, 0).TraceTransaction(context.Background(), txHash, &tracers.TraceConfig{Tracer: &flatCallTracer}, stream)
+	if err != nil {
+		t.Fatalf("debug_traceTransaction: %v", err)
+	}
+	if err = stream.Flush(); err != nil {
+		t.Fatalf("error flushing: %v", err)
+	}
+	var result interface{}
+	if err = json.Unmarshal(debugBuf.Bytes(), &result); err != nil {
+		t.Fatalf("parsing result: %v", err)
+	}
+	if !assert.Equal(t, expected, result) {
+		t.Fatalf("not equal")
+	}
+}
@@ -0,0 +1,90 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+)
+
+// Otterscan2API - ots2_* methods served from address appearances indexed by execution stage (--exec.ots.index),
+// they don't trace or scan history. Results are paged by offset and ordered by counterpart address.
+type Otterscan2API interface {
+	GetContractsCreatedBy(ctx context.Context, addr common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error)
+	GetSelfDestructs(ctx context.Context, addr common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error)
+	GetERC20Holdings(ctx context.Context, holder common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error)
+	GetERC721Holdings(ctx context.Context, holder common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error)
+}
+
+type Appearance struct {
+	Address     common.Address `json:"address"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+type AppearancesPage struct {
+	Results []*Appearance `json:"results"`
+	HasMore bool          `json:"hasMore"`
+}
+
+type Otterscan2APIImpl struct {
+	*BaseAPI
+	db          kv.RoDB
+	maxPageSize uint64
+}
+
+func NewOtterscan2API(base *BaseAPI, db kv.RoDB, maxPageSize uint64) *Otterscan2APIImpl {
+	return &Otterscan2APIImpl{
+		BaseAPI:     base,
+		db:          db,
+		maxPageSize: maxPageSize,
+	}
+}
+
+// GetContractsCreatedBy - contracts deployed by addr (by tx or by internal CREATE/CREATE2) with block of deployment
+func (api *Otterscan2APIImpl) GetContractsCreatedBy(ctx context.Context, addr common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error) {
+	return api.appearances(ctx, addr, rawdb.OtsContractCreation, offset, pageSize)
+}
+
+// GetSelfDestructs - beneficiaries of self-destructs of contract addr with block of self-destruct
+func (api *Otterscan2APIImpl) GetSelfDestructs(ctx context.Context, addr common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error) {
+	return api.appearances(ctx, addr, rawdb.OtsSelfDestruct, offset, pageSize)
+}
+
+// GetERC20Holdings - ERC-20 tokens ever sent or received by holder with block of first transfer
+func (api *Otterscan2APIImpl) GetERC20Holdings(ctx context.Context, holder common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error) {
+	return api.appearances(ctx, holder, rawdb.OtsERC20Transfer, offset, pageSize)
+}
+
+// GetERC721Holdings - ERC-721 collections ever sent or received by holder with block of first transfer
+func (api *Otterscan2APIImpl) GetERC721Holdings(ctx context.Context, holder common.Address, offset uint64, pageSize uint16) (*AppearancesPage, error) {
+	return api.appearances(ctx, holder, rawdb.OtsERC721Transfer, offset, pageSize)
+}
+
+func (api *Otterscan2APIImpl) appearances(ctx context.Context, addr common.Address, kind rawdb.OtsAppearanceKind, offset uint64, pageSize uint16) (*AppearancesPage, error) {
+	if pageSize == 0 {
+		return nil, fmt.Errorf("page size must be positive")
+	}
+	if uint64(pageSize) > api.maxPageSize {
+		return nil, fmt.Errorf("max allowed page size: %v", api.maxPageSize)
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	found, hasMore, err := rawdb.ReadOtsAppearances(tx, addr, kind, offset, uint64(pageSize))
+	if err != nil {
+		return nil, err
+	}
+	page := &AppearancesPage{Results: make([]*Appearance, 0, len(found)), HasMore: hasMore}
+	for _, a := range found {
+		page.Results = append(page.Results, &Appearance{Address: a.Counterpart, BlockNumber: hexutil.Uint64(a.BlockNum)})
+	}
+	return page, nil
+}
@@ -48,6 +48,7 @@ type OtterscanAPI interface {
 	GetTransactionError(ctx context.Context, hash common.Hash) (hexutility.Bytes, error)
 	GetTransactionBySenderAndNonce(ctx context.Context, addr common.Address, nonce uint64) (*common.Hash, error)
 	GetContractCreator(ctx context.Context, addr common.Address) (*ContractCreatorData, error)
+	SearchTransactionsByLogs(ctx context.Context, addresses []common.Address, topics [][]common.Hash, blockNum uint64, backward bool, pageSize uint16) (*TransactionsWithReceipts, error)
 }
 
 type OtterscanAPIImpl struct {
@@ -180,7 +181,7 @@ func (api *OtterscanAPIImpl) SearchTransactionsBefore(ctx context.Context, addr
 	}
 	defer dbtx.Rollback()
 
-	return api.searchTransactionsBeforeV3(dbtx.(kv.TemporalTx), ctx, addr, blockNum, pageSize)
+	return api.searchTransactionsBeforeV3(dbtx.(kv.TemporalTx), ctx, createBackwardTxNumIter, nil, addr, blockNum, pageSize)
 }
 
 // Search transactions that touch a certain address.
@@ -202,7 +203,7 @@ func (api *OtterscanAPIImpl) SearchTransactionsAfter(ctx context.Context, addr c
 	}
 	defer dbtx.Rollback()
 
-	return api.searchTransactionsAfterV3(dbtx.(kv.TemporalTx), ctx, addr, blockNum, pageSize)
+	return api.searchTransactionsAfterV3(dbtx.(kv.TemporalTx), ctx, createForwardTxNumIter, nil, addr, blockNum, pageSize)
 }
 
 func (api *OtterscanAPIImpl) traceBlocks(ctx context.Context, addr common.Address, chainConfig *chain.Config, pageSize, resultCount uint16, callFromToProvider BlockProvider) ([]*TransactionsWithReceipts, bool, error) {
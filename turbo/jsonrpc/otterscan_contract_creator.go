@@ -93,7 +93,7 @@ func (api *OtterscanAPIImpl) GetContractCreator(ctx context.Context, addr common
 			continue
 		}
 
-		if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		if err := accounts.DeserialiseState(&acc, v); err != nil {
 			return nil, err
 		}
 		// Found the shard where the incarnation change happens; ignore all next index values
@@ -131,7 +131,7 @@ func (api *OtterscanAPIImpl) GetContractCreator(ctx context.Context, addr common
 			return false
 		}
 
-		if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		if err := accounts.DeserialiseState(&acc, v); err != nil {
 			searchErr = err
 			return false
 		}
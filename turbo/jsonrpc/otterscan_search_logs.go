@@ -0,0 +1,71 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// Search transactions which emitted logs matching addresses and topics, e.g. ERC-20 transfers of an address:
+// addresses == [token], topics == [[Transfer], [], [address]].
+//
+// Topics have the same semantics as in eth_getLogs: each position is a list of alternatives, an empty
+// list matches any topic. At least one address or topic is required.
+//
+// If backward is true, it searches back from a certain block (excluding), otherwise forward from it (excluding),
+// the results are sorted descending in both cases. Pagination follows ots_searchTransactionsBefore/After:
+// it may return a little more than pageSize if the last found block contains more matching txs.
+func (api *OtterscanAPIImpl) SearchTransactionsByLogs(ctx context.Context, addresses []common.Address, topics [][]common.Hash, blockNum uint64, backward bool, pageSize uint16) (*TransactionsWithReceipts, error) {
+	if uint64(pageSize) > api.maxPageSize {
+		return nil, fmt.Errorf("max allowed page size: %v", api.maxPageSize)
+	}
+	if len(addresses) == 0 && !hasTopics(topics) {
+		return nil, errors.New("at least one address or topic is required")
+	}
+
+	dbtx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dbtx.Rollback()
+
+	addrMap := make(map[common.Address]struct{}, len(addresses))
+	for _, addr := range addresses {
+		addrMap[addr] = struct{}{}
+	}
+	// indices don't store topic position and log address of a topic, so re-check the logs
+	matchLogs := func(logs types.Logs) bool {
+		return len(logs.Filter(addrMap, topics)) > 0
+	}
+
+	if backward {
+		return api.searchTransactionsBeforeV3(dbtx.(kv.TemporalTx), ctx, createLogsTxNumIter(addresses, topics, order.Desc), matchLogs, common.Address{}, blockNum, pageSize)
+	}
+	return api.searchTransactionsAfterV3(dbtx.(kv.TemporalTx), ctx, createLogsTxNumIter(addresses, topics, order.Asc), matchLogs, common.Address{}, blockNum, pageSize)
+}
+
+func hasTopics(topics [][]common.Hash) bool {
+	for _, sub := range topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func createLogsTxNumIter(addresses []common.Address, topics [][]common.Hash, asc order.By) txNumsIterFactory {
+	return func(tx kv.TemporalTx, _ common.Address, fromTxNum int) (*rawdbv3.MapTxNum2BlockNumIter, error) {
+		// unbounded limit on purpose, results are limited later
+		txNums, err := getLogsTxNumsV3(tx, addresses, topics, fromTxNum, -1, asc)
+		if err != nil {
+			return nil, err
+		}
+		return rawdbv3.TxNums2BlockNums(tx, txNums, asc), nil
+	}
+}
@@ -0,0 +1,103 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/eth/filters"
+)
+
+func TestSearchTransactionsByLogs(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New())
+	api := NewOtterscanAPI(newBaseApiForTest(m), m.DB, 25)
+
+	logs, err := ethApi.GetLogs(m.Ctx, filters.FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(10)})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	addr, topic := logs[0].Address, logs[0].Topics[0]
+
+	// expected tx hashes, descending
+	var expected []libcommon.Hash
+	for i := len(logs) - 1; i >= 0; i-- {
+		if logs[i].Address != addr || logs[i].Topics[0] != topic {
+			continue
+		}
+		if len(expected) == 0 || expected[len(expected)-1] != logs[i].TxHash {
+			expected = append(expected, logs[i].TxHash)
+		}
+	}
+	txHashes := func(results *TransactionsWithReceipts) (hashes []libcommon.Hash) {
+		for _, txn := range results.Txs {
+			hashes = append(hashes, txn.Hash)
+		}
+		return hashes
+	}
+
+	t.Run("backward", func(t *testing.T) {
+		results, err := api.SearchTransactionsByLogs(m.Ctx, []libcommon.Address{addr}, [][]libcommon.Hash{{topic}}, 0, true, 25)
+		require.NoError(t, err)
+		require.True(t, results.FirstPage)
+		require.True(t, results.LastPage)
+		require.Equal(t, expected, txHashes(results))
+		require.Len(t, results.Receipts, len(expected))
+	})
+	t.Run("forward", func(t *testing.T) {
+		results, err := api.SearchTransactionsByLogs(m.Ctx, nil, [][]libcommon.Hash{{topic}}, 1, false, 25)
+		require.NoError(t, err)
+		require.True(t, results.FirstPage)
+		require.Equal(t, expected, txHashes(results))
+	})
+	t.Run("no match", func(t *testing.T) {
+		results, err := api.SearchTransactionsByLogs(m.Ctx, []libcommon.Address{{1}}, [][]libcommon.Hash{{topic}}, 0, true, 25)
+		require.NoError(t, err)
+		require.Empty(t, results.Txs)
+	})
+	t.Run("no criteria", func(t *testing.T) {
+		_, err := api.SearchTransactionsByLogs(m.Ctx, nil, [][]libcommon.Hash{{}}, 0, true, 25)
+		require.Error(t, err)
+	})
+	t.Run("page size", func(t *testing.T) {
+		_, err := api.SearchTransactionsByLogs(m.Ctx, []libcommon.Address{addr}, nil, 0, true, 26)
+		require.Error(t, err)
+	})
+}
+
+func TestTopicsBitmapEmptyPosition(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, log.New())
+	logs, err := ethApi.GetLogs(m.Ctx, filters.FilterCriteria{FromBlock: big.NewInt(0), ToBlock: big.NewInt(10)})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	topic := logs[0].Topics[0]
+
+	dbtx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	defer dbtx.Rollback()
+	tx := dbtx.(kv.TemporalTx)
+	txNums := func(topics [][]libcommon.Hash) []uint64 {
+		it, err := getTopicsBitmapV3(tx, topics, 0, -1, order.Asc)
+		require.NoError(t, err)
+		res, err := iter.ToArray[uint64](it)
+		require.NoError(t, err)
+		return res
+	}
+
+	expected := txNums([][]libcommon.Hash{{topic}})
+	require.NotEmpty(t, expected)
+	// empty position doesn't narrow down txNums, wherever it is
+	require.Equal(t, expected, txNums([][]libcommon.Hash{{topic}, {}}))
+	require.Equal(t, expected, txNums([][]libcommon.Hash{{}, {topic}}))
+	// nil - no restriction by topics at all
+	it, err := getTopicsBitmapV3(tx, [][]libcommon.Hash{{}, {}}, 0, -1, order.Asc)
+	require.NoError(t, err)
+	require.Nil(t, it)
+}
@@ -16,7 +16,10 @@ import (
 
 type txNumsIterFactory func(tx kv.TemporalTx, addr common.Address, fromTxNum int) (*rawdbv3.MapTxNum2BlockNumIter, error)
 
-func (api *OtterscanAPIImpl) buildSearchResults(ctx context.Context, tx kv.TemporalTx, iterFactory txNumsIterFactory, addr common.Address, fromTxNum int, pageSize uint16) ([]*RPCTransaction, []map[string]interface{}, bool, error) {
+// logsMatcher discards txs found by an index which don't match the search precisely, nil matches all txs
+type logsMatcher func(logs types.Logs) bool
+
+func (api *OtterscanAPIImpl) buildSearchResults(ctx context.Context, tx kv.TemporalTx, iterFactory txNumsIterFactory, matchLogs logsMatcher, addr common.Address, fromTxNum int, pageSize uint16) ([]*RPCTransaction, []map[string]interface{}, bool, error) {
 	chainConfig, err := api.chainConfig(ctx, tx)
 	if err != nil {
 		return nil, nil, false, err
@@ -85,6 +88,9 @@ func (api *OtterscanAPIImpl) buildSearchResults(ctx context.Context, tx kv.Tempo
 			return nil, nil, false, err
 		}
 		rawLogs := exec.GetLogs(txIndex, txn)
+		if matchLogs != nil && !matchLogs(rawLogs) {
+			continue
+		}
 		rpcTx := NewRPCTransaction(txn, blockHash, blockNum, uint64(txIndex), header.BaseFee)
 		txs = append(txs, rpcTx)
 		receipt := &types.Receipt{
@@ -130,7 +136,7 @@ func createBackwardTxNumIter(tx kv.TemporalTx, addr common.Address, fromTxNum in
 	return rawdbv3.TxNums2BlockNums(tx, txNums, order.Desc), nil
 }
 
-func (api *OtterscanAPIImpl) searchTransactionsBeforeV3(tx kv.TemporalTx, ctx context.Context, addr common.Address, fromBlockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
+func (api *OtterscanAPIImpl) searchTransactionsBeforeV3(tx kv.TemporalTx, ctx context.Context, iterFactory txNumsIterFactory, matchLogs logsMatcher, addr common.Address, fromBlockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
 	isFirstPage := false
 	if fromBlockNum == 0 {
 		isFirstPage = true
@@ -149,7 +155,7 @@ func (api *OtterscanAPIImpl) searchTransactionsBeforeV3(tx kv.TemporalTx, ctx co
 		fromTxNum = int(_txNum)
 	}
 
-	txs, receipts, hasMore, err := api.buildSearchResults(ctx, tx, createBackwardTxNumIter, addr, fromTxNum, pageSize)
+	txs, receipts, hasMore, err := api.buildSearchResults(ctx, tx, iterFactory, matchLogs, addr, fromTxNum, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +178,7 @@ func createForwardTxNumIter(tx kv.TemporalTx, addr common.Address, fromTxNum int
 	return rawdbv3.TxNums2BlockNums(tx, txNums, order.Asc), nil
 }
 
-func (api *OtterscanAPIImpl) searchTransactionsAfterV3(tx kv.TemporalTx, ctx context.Context, addr common.Address, fromBlockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
+func (api *OtterscanAPIImpl) searchTransactionsAfterV3(tx kv.TemporalTx, ctx context.Context, iterFactory txNumsIterFactory, matchLogs logsMatcher, addr common.Address, fromBlockNum uint64, pageSize uint16) (*TransactionsWithReceipts, error) {
 	isLastPage := false
 	fromTxNum := -1
 	if fromBlockNum == 0 {
@@ -186,7 +192,7 @@ func (api *OtterscanAPIImpl) searchTransactionsAfterV3(tx kv.TemporalTx, ctx con
 		fromTxNum = int(_txNum)
 	}
 
-	txs, receipts, hasMore, err := api.buildSearchResults(ctx, tx, createForwardTxNumIter, addr, fromTxNum, pageSize)
+	txs, receipts, hasMore, err := api.buildSearchResults(ctx, tx, iterFactory, matchLogs, addr, fromTxNum, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +57,7 @@ func (api *OtterscanAPIImpl) GetTransactionBySenderAndNonce(ctx context.Context,
 			continue
 		}
 
-		if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		if err := accounts.DeserialiseState(&acc, v); err != nil {
 			return nil, err
 		}
 		// Desired nonce was found in this chunk
@@ -94,7 +94,7 @@ func (api *OtterscanAPIImpl) GetTransactionBySenderAndNonce(ctx context.Context,
 			return false
 		}
 
-		if err := accounts.DeserialiseV3(&acc, v); err != nil {
+		if err := accounts.DeserialiseState(&acc, v); err != nil {
 			searchErr = err
 			return false
 		}
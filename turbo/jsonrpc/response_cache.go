@@ -0,0 +1,150 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// responseCacheMaxEntries - bound of the number of entries, the cache is limited by the size of responses
+const responseCacheMaxEntries = 1 << 20
+
+type blockOfCall func(ctx context.Context, tx kv.Tx, blockReader services.FullBlockReader, params []json.RawMessage) (uint64, bool, error)
+
+// cachedMethods - idempotent methods whose responses are cached, with the lookup of the block they are about
+var cachedMethods = map[string]blockOfCall{
+	"eth_getBlockByNumber":      blockOfNumberParam,
+	"eth_getTransactionReceipt": blockOfTxnParam,
+	"trace_block":               blockOfNumberParam,
+}
+
+// ResponseCache - LRU cache of responses of idempotent methods about finalized blocks. Keys contain the canonical
+// hash of the block of the call - finalized blocks don't reorg, so responses can't become stale, and blocks above
+// the finalized one aren't cached at all. Responses about blocks which became non-canonical (e.g. after an unwind)
+// aren't served, as their keys don't match the canonical hash anymore - they are evicted by newer entries.
+type ResponseCache struct {
+	db          kv.RoDB
+	blockReader services.FullBlockReader
+	limit       uint64
+
+	lock    sync.Mutex
+	entries *simplelru.LRU[string, json.RawMessage]
+	size    uint64
+}
+
+// NewResponseCache returns response cache limited by the total size of responses, nil if limit is 0
+func NewResponseCache(db kv.RoDB, blockReader services.FullBlockReader, limit datasize.ByteSize) rpc.ResponseCache {
+	if limit == 0 {
+		return nil
+	}
+
+	c := &ResponseCache{db: db, blockReader: blockReader, limit: limit.Bytes()}
+	entries, err := simplelru.NewLRU[string, json.RawMessage](responseCacheMaxEntries, func(key string, result json.RawMessage) {
+		c.size -= uint64(len(key) + len(result))
+	})
+	if err != nil {
+		panic(err)
+	}
+	c.entries = entries
+	return c
+}
+
+func (c *ResponseCache) Get(ctx context.Context, method string, params json.RawMessage) (string, json.RawMessage, bool) {
+	blockOf, ok := cachedMethods[method]
+	if !ok {
+		return "", nil, false
+	}
+
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+		return "", nil, false
+	}
+
+	blockHash, ok := c.finalizedBlockOf(ctx, blockOf, args)
+	if !ok {
+		return "", nil, false
+	}
+
+	var key bytes.Buffer
+	key.WriteString(method)
+	key.Write(blockHash[:])
+	if err := json.Compact(&key, params); err != nil {
+		return "", nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	result, ok := c.entries.Get(key.String())
+	return key.String(), result, ok
+}
+
+func (c *ResponseCache) Add(key string, result json.RawMessage) {
+	size := uint64(len(key) + len(result))
+	if size > c.limit {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.entries.Contains(key) {
+		return
+	}
+	c.entries.Add(key, result)
+	c.size += size
+	for c.size > c.limit {
+		c.entries.RemoveOldest()
+	}
+}
+
+// finalizedBlockOf returns canonical hash of the block of the call, false if the block isn't known or finalized yet
+func (c *ResponseCache) finalizedBlockOf(ctx context.Context, blockOf blockOfCall, args []json.RawMessage) (common.Hash, bool) {
+	tx, err := c.db.BeginRo(ctx)
+	if err != nil {
+		return common.Hash{}, false
+	}
+	defer tx.Rollback()
+
+	blockNum, ok, err := blockOf(ctx, tx, c.blockReader, args)
+	if err != nil || !ok {
+		return common.Hash{}, false
+	}
+
+	finalized, err := rpchelper.GetFinalizedBlockNumber(tx)
+	if err != nil || blockNum > finalized {
+		return common.Hash{}, false
+	}
+
+	blockHash, err := c.blockReader.CanonicalHash(ctx, tx, blockNum)
+	if err != nil || blockHash == (common.Hash{}) {
+		return common.Hash{}, false
+	}
+	return blockHash, true
+}
+
+// blockOfNumberParam - the first param is an explicit block number, tags like `latest` or `finalized` aren't cached
+func blockOfNumberParam(_ context.Context, _ kv.Tx, _ services.FullBlockReader, params []json.RawMessage) (uint64, bool, error) {
+	var blockNum rpc.BlockNumber
+	if err := json.Unmarshal(params[0], &blockNum); err != nil || blockNum < 0 {
+		return 0, false, nil
+	}
+	return uint64(blockNum), true, nil
+}
+
+// blockOfTxnParam - the first param is a transaction hash
+func blockOfTxnParam(ctx context.Context, tx kv.Tx, blockReader services.FullBlockReader, params []json.RawMessage) (uint64, bool, error) {
+	var txnHash common.Hash
+	if err := json.Unmarshal(params[0], &txnHash); err != nil {
+		return 0, false, nil
+	}
+	return blockReader.TxnLookup(ctx, tx, txnHash)
+}
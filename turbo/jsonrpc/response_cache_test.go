@@ -0,0 +1,100 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+)
+
+func TestResponseCache(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+
+	require.Nil(t, NewResponseCache(m.DB, m.BlockReader, 0))
+	cache := NewResponseCache(m.DB, m.BlockReader, datasize.MB)
+
+	key, _, _ := cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`["0x3",false]`))
+	require.Empty(t, key, "nothing is cached without finalized block")
+
+	var txnHash common.Hash
+	require.NoError(t, m.DB.Update(ctx, func(tx kv.RwTx) error {
+		finalized, err := m.BlockReader.CanonicalHash(ctx, tx, 5)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteForkchoiceFinalized(tx, finalized)
+
+		for blockNum := uint64(1); blockNum <= 5 && txnHash == (common.Hash{}); blockNum++ {
+			block, err := m.BlockReader.BlockByNumber(ctx, tx, blockNum)
+			if err != nil {
+				return err
+			}
+			if txs := block.Transactions(); len(txs) > 0 {
+				txnHash = txs[0].Hash()
+			}
+		}
+		return nil
+	}))
+	require.NotEqual(t, common.Hash{}, txnHash)
+
+	key, _, ok := cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`["0x3",false]`))
+	require.NotEmpty(t, key)
+	require.False(t, ok)
+	cache.Add(key, json.RawMessage(`{"number":"0x3"}`))
+
+	cachedKey, result, ok := cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`[ "0x3", false ]`))
+	require.True(t, ok)
+	require.Equal(t, key, cachedKey)
+	require.Equal(t, `{"number":"0x3"}`, string(result))
+
+	key, _, ok = cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`["0x3",true]`))
+	require.NotEmpty(t, key)
+	require.False(t, ok)
+
+	for _, call := range []struct{ method, params string }{
+		{"eth_getBlockByNumber", `["0x6",false]`},
+		{"eth_getBlockByNumber", `["latest",false]`},
+		{"eth_getBlockByNumber", `["finalized",false]`},
+		{"eth_getBlockByNumber", `[]`},
+		{"trace_block", `["0x100"]`},
+		{"eth_getTransactionReceipt", `["0x0000000000000000000000000000000000000000000000000000000000000001"]`},
+		{"eth_getBalance", `["0x0000000000000000000000000000000000000001","0x1"]`},
+	} {
+		key, _, _ := cache.Get(ctx, call.method, json.RawMessage(call.params))
+		require.Empty(t, key, "%s %s", call.method, call.params)
+	}
+
+	receiptParams, err := json.Marshal([]common.Hash{txnHash})
+	require.NoError(t, err)
+	key, _, _ = cache.Get(ctx, "eth_getTransactionReceipt", receiptParams)
+	require.NotEmpty(t, key)
+
+	key, _, _ = cache.Get(ctx, "trace_block", json.RawMessage(`["5"]`))
+	require.NotEmpty(t, key)
+}
+
+func TestResponseCacheLimit(t *testing.T) {
+	cache := NewResponseCache(nil, nil, 100).(*ResponseCache)
+
+	cache.Add("a", make(json.RawMessage, 59))
+	cache.Add("b", make(json.RawMessage, 39))
+	require.Equal(t, uint64(100), cache.size)
+	require.Equal(t, 2, cache.entries.Len())
+
+	cache.Add("c", make(json.RawMessage, 9))
+	require.False(t, cache.entries.Contains("a"))
+	require.True(t, cache.entries.Contains("b"))
+	require.True(t, cache.entries.Contains("c"))
+	require.Equal(t, uint64(50), cache.size)
+
+	cache.Add("d", make(json.RawMessage, 100))
+	require.False(t, cache.entries.Contains("d"), "responses above the limit aren't cached")
+}
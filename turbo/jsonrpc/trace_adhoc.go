@@ -9,8 +9,10 @@ import (
 	"strings"
 
 	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
@@ -836,7 +838,7 @@ func (api *TraceAPIImpl) ReplayBlockTransactions(ctx context.Context, blockNrOrH
 		return nil, bErr
 	}
 	if block == nil {
-		return nil, fmt.Errorf("could not find block  %d", blockNumber)
+		return nil, fmt.Errorf("could not find block %d", blockNumber)
 	}
 	var traceTypeTrace, traceTypeStateDiff, traceTypeVmTrace bool
 	for _, traceType := range traceTypes {
@@ -881,6 +883,193 @@ func (api *TraceAPIImpl) ReplayBlockTransactions(ctx context.Context, blockNrOrH
 	return result, nil
 }
 
+// ReplayBlockTransactionsStream implements trace_replayBlockTransactionsStream. It's a streaming form of
+// trace_replayBlockTransactions with the same result: traces of every transaction are written to the response
+// as soon as the transaction is replayed, instead of materializing stateDiff of the whole block in memory.
+func (api *TraceAPIImpl) ReplayBlockTransactionsStream(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, traceTypes []string, gasBailOut *bool, stream *jsoniter.Stream) error {
+	if gasBailOut == nil {
+		gasBailOut = new(bool) // false by default
+	}
+	tx, err := api.kv.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err := checkTraceTypes(traceTypes); err != nil {
+		return err
+	}
+
+	blockNumber, blockHash, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return err
+	}
+	block, err := api.blockWithSenders(ctx, tx, blockHash, blockNumber)
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("could not find block %d", blockNumber)
+	}
+
+	// replay error is written into the result as its last element, the response is already started by then
+	_ = api.replayBlockTransactionsStream(ctx, tx, block, traceTypes, *gasBailOut, chainConfig, stream)
+	return stream.Flush()
+}
+
+// ReplayBlockRange implements trace_replayBlockRange. It's a streaming range form of trace_replayBlockTransactions:
+// traces of every transaction are written to the response as soon as the transaction is replayed, so
+// stateDiff of thousands of blocks can be pulled without materializing it in memory.
+func (api *TraceAPIImpl) ReplayBlockRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, traceTypes []string, gasBailOut *bool, stream *jsoniter.Stream) error {
+	if gasBailOut == nil {
+		gasBailOut = new(bool) // false by default
+	}
+	tx, err := api.kv.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err := checkTraceTypes(traceTypes); err != nil {
+		return err
+	}
+
+	from, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(fromBlock), tx, api.filters)
+	if err != nil {
+		return err
+	}
+	to, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(toBlock), tx, api.filters)
+	if err != nil {
+		return err
+	}
+	if from > to {
+		return fmt.Errorf("invalid parameters: fromBlock cannot be greater than toBlock")
+	}
+	if api.replayRangeLimit > 0 && to-from+1 > api.replayRangeLimit {
+		return fmt.Errorf("invalid parameters: block range %d-%d is over the limit of %d blocks", from, to, api.replayRangeLimit)
+	}
+	for _, blockNum := range []uint64{from, to} {
+		header, err := api.headerByRPCNumber(ctx, rpc.BlockNumber(blockNum), tx)
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			return fmt.Errorf("could not find block %d", blockNum)
+		}
+	}
+
+	// after the array is started errors are written into it as the last element, like trace_filter does
+	stream.WriteArrayStart()
+	for blockNum := from; blockNum <= to; blockNum++ {
+		if blockNum != from {
+			stream.WriteMore()
+		}
+		block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+		if err == nil && block == nil {
+			err = fmt.Errorf("could not find block %d", blockNum)
+		}
+		if err == nil {
+			err = libcommon.Stopped(ctx.Done())
+		}
+		if err != nil {
+			stream.WriteObjectStart()
+			rpc.HandleError(err, stream)
+			stream.WriteObjectEnd()
+			break
+		}
+
+		stream.WriteObjectStart()
+		stream.WriteObjectField("blockNumber")
+		stream.WriteUint64(blockNum)
+		stream.WriteMore()
+		stream.WriteObjectField("blockHash")
+		stream.WriteString(block.Hash().Hex())
+		stream.WriteMore()
+		stream.WriteObjectField("transactions")
+		err = api.replayBlockTransactionsStream(ctx, tx, block, traceTypes, *gasBailOut, chainConfig, stream)
+		stream.WriteObjectEnd()
+		if err != nil {
+			break
+		}
+	}
+	stream.WriteArrayEnd()
+	return stream.Flush()
+}
+
+func checkTraceTypes(traceTypes []string) error {
+	for _, traceType := range traceTypes {
+		switch traceType {
+		case TraceTypeTrace, TraceTypeStateDiff, TraceTypeVmTrace:
+		default:
+			return fmt.Errorf("unrecognized trace type: %s", traceType)
+		}
+	}
+	return nil
+}
+
+// replayBlockTransactionsStream - writes array of TraceCallResult of all block transactions to the stream, flushing it after each transaction.
+// If the replay fails, the error is written as the last element of the array, and returned. The array is closed in both cases.
+func (api *TraceAPIImpl) replayBlockTransactionsStream(ctx context.Context, tx kv.Tx, block *types.Block, traceTypes []string, gasBailOut bool, chainConfig *chain.Config, stream *jsoniter.Stream) error {
+	var traceTypeTrace, traceTypeStateDiff, traceTypeVmTrace bool
+	for _, traceType := range traceTypes {
+		switch traceType {
+		case TraceTypeTrace:
+			traceTypeTrace = true
+		case TraceTypeStateDiff:
+			traceTypeStateDiff = true
+		case TraceTypeVmTrace:
+			traceTypeVmTrace = true
+		}
+	}
+
+	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
+	first := true
+	stream.WriteArrayStart()
+	_, err := api.callManyTransactionsStream(ctx, tx, block, traceTypes, -1 /* all tx indices */, gasBailOut, signer, chainConfig, func(trace *TraceCallResult) error {
+		tr := &TraceCallResult{Output: trace.Output, TransactionHash: trace.TransactionHash}
+		if traceTypeTrace {
+			tr.Trace = trace.Trace
+		} else {
+			tr.Trace = []*ParityTrace{}
+		}
+		if traceTypeStateDiff {
+			tr.StateDiff = trace.StateDiff
+		}
+		if traceTypeVmTrace {
+			tr.VmTrace = trace.VmTrace
+		}
+		b, err := json.Marshal(tr)
+		if err != nil {
+			return err
+		}
+		if first {
+			first = false
+		} else {
+			stream.WriteMore()
+		}
+		if _, err := stream.Write(b); err != nil {
+			return err
+		}
+		return stream.Flush()
+	})
+	if err != nil {
+		if !first {
+			stream.WriteMore()
+		}
+		stream.WriteObjectStart()
+		rpc.HandleError(err, stream)
+		stream.WriteObjectEnd()
+	}
+	stream.WriteArrayEnd()
+	return err
+}
+
 // Call implements trace_call.
 func (api *TraceAPIImpl) Call(ctx context.Context, args TraceCallParam, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*TraceCallResult, error) {
 	tx, err := api.kv.BeginRo(ctx)
@@ -1103,10 +1292,25 @@ func (api *TraceAPIImpl) CallMany(ctx context.Context, calls json.RawMessage, pa
 func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []types.Message, callParams []TraceCallParam,
 	parentNrOrHash *rpc.BlockNumberOrHash, header *types.Header, gasBailout bool, txIndexNeeded int,
 ) ([]*TraceCallResult, *state.IntraBlockState, error) {
-	chainConfig, err := api.chainConfig(ctx, dbtx)
+	results := make([]*TraceCallResult, 0, len(msgs))
+	ibs, err := api.doCallManyStream(ctx, dbtx, msgs, callParams, parentNrOrHash, header, gasBailout, txIndexNeeded, func(traceResult *TraceCallResult) error {
+		results = append(results, traceResult)
+		return nil
+	})
 	if err != nil {
 		return nil, nil, err
 	}
+	return results, ibs, nil
+}
+
+// doCallManyStream - is like doCallMany, but passes result of each call to `onResult` as soon as it's ready (instead of materializing all results)
+func (api *TraceAPIImpl) doCallManyStream(ctx context.Context, dbtx kv.Tx, msgs []types.Message, callParams []TraceCallParam,
+	parentNrOrHash *rpc.BlockNumberOrHash, header *types.Header, gasBailout bool, txIndexNeeded int, onResult func(traceResult *TraceCallResult) error,
+) (*state.IntraBlockState, error) {
+	chainConfig, err := api.chainConfig(ctx, dbtx)
+	if err != nil {
+		return nil, err
+	}
 	engine := api.engine()
 
 	if parentNrOrHash == nil {
@@ -1115,11 +1319,11 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 	}
 	blockNumber, hash, _, err := rpchelper.GetBlockNumber(*parentNrOrHash, dbtx, api.filters)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	stateReader, err := rpchelper.CreateStateReader(ctx, dbtx, *parentNrOrHash, 0, api.filters, api.stateCache, chainConfig.ChainName)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	stateCache := shards.NewStateCache(32, 0 /* no limit */) // this cache living only during current RPC call, but required to store state writes
 	cachedReader := state.NewCachedReader(stateReader, stateCache)
@@ -1130,14 +1334,14 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 	// TODO: can read here only parent header
 	parentBlock, err := api.blockWithSenders(ctx, dbtx, hash, blockNumber)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if parentBlock == nil {
-		return nil, nil, fmt.Errorf("parent block %d(%x) not found", blockNumber, hash)
+		return nil, fmt.Errorf("parent block %d(%x) not found", blockNumber, hash)
 	}
 	parentHeader := parentBlock.Header()
 	if parentHeader == nil {
-		return nil, nil, fmt.Errorf("parent header %d(%x) not found", blockNumber, hash)
+		return nil, fmt.Errorf("parent header %d(%x) not found", blockNumber, hash)
 	}
 
 	// Setup context so it may be cancelled the call has completed
@@ -1152,8 +1356,6 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 	// Make sure the context is cancelled when the call has completed
 	// this makes sure resources are cleaned up.
 	defer cancel()
-	results := make([]*TraceCallResult, 0, len(msgs))
-
 	useParent := false
 	if header == nil {
 		header = parentHeader
@@ -1162,7 +1364,7 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 
 	for txIndex, msg := range msgs {
 		if err := libcommon.Stopped(ctx.Done()); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		var traceTypeTrace, traceTypeStateDiff, traceTypeVmTrace bool
@@ -1176,7 +1378,7 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 			case TraceTypeVmTrace:
 				traceTypeVmTrace = true
 			default:
-				return nil, nil, fmt.Errorf("unrecognized trace type: %s", traceType)
+				return nil, fmt.Errorf("unrecognized trace type: %s", traceType)
 			}
 		}
 
@@ -1255,7 +1457,7 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 			execResult, err = core.ApplyMessage(evm, msg, gp, true /* refunds */, gasBailout /* gasBailout */)
 		}
 		if err != nil {
-			return nil, nil, fmt.Errorf("first run for txIndex %d error: %w", txIndex, err)
+			return nil, fmt.Errorf("first run for txIndex %d error: %w", txIndex, err)
 		}
 
 		chainRules := chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Time)
@@ -1264,27 +1466,29 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 			initialIbs := state.New(cloneReader)
 			if !txFinalized {
 				if err = ibs.FinalizeTx(chainRules, sd); err != nil {
-					return nil, nil, err
+					return nil, err
 				}
 			}
 			sd.CompareStates(initialIbs, ibs)
 			if err = ibs.CommitBlock(chainRules, cachedWriter); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		} else {
 			if !txFinalized {
 				if err = ibs.FinalizeTx(chainRules, noop); err != nil {
-					return nil, nil, err
+					return nil, err
 				}
 			}
 			if err = ibs.CommitBlock(chainRules, cachedWriter); err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		}
 		if !traceTypeTrace {
 			traceResult.Trace = []*ParityTrace{}
 		}
-		results = append(results, traceResult)
+		if err := onResult(traceResult); err != nil {
+			return nil, err
+		}
 		// When txIndexNeeded is not -1, we are tracing specific transaction in the block and not the entire block, so we stop after we've traced
 		// the required transaction
 		if txIndexNeeded != -1 && txIndex == txIndexNeeded {
@@ -1292,7 +1496,7 @@ func (api *TraceAPIImpl) doCallMany(ctx context.Context, dbtx kv.Tx, msgs []type
 		}
 	}
 
-	return results, ibs, nil
+	return ibs, nil
 }
 
 // RawTransaction implements trace_rawTransaction.
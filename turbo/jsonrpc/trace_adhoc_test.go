@@ -1,10 +1,13 @@
 package jsonrpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"testing"
 
+	jsoniter "github.com/json-iterator/go"
+
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -101,3 +104,67 @@ func TestReplayBlockTransactions(t *testing.T) {
 	v := addrDiff.Balance.(map[string]*hexutil.Big)["+"].ToInt().Uint64()
 	require.Equal(t, uint64(1_000_000_000_000_000), v)
 }
+
+func TestReplayBlockRange(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewTraceAPI(newBaseApiForTest(m), m.DB, &httpcfg.HttpCfg{})
+
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	err := api.ReplayBlockRange(m.Ctx, rpc.BlockNumber(5), rpc.BlockNumber(6), []string{"stateDiff"}, new(bool), stream)
+	require.NoError(t, err)
+
+	var results []struct {
+		BlockNumber  uint64             `json:"blockNumber"`
+		BlockHash    libcommon.Hash     `json:"blockHash"`
+		Transactions []*TraceCallResult `json:"transactions"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &results))
+	require.Equal(t, 2, len(results))
+	require.Equal(t, uint64(5), results[0].BlockNumber)
+	require.Equal(t, uint64(6), results[1].BlockNumber)
+
+	// must match non-streaming trace_replayBlockTransactions
+	n := rpc.BlockNumber(6)
+	expect, err := api.ReplayBlockTransactions(m.Ctx, rpc.BlockNumberOrHash{BlockNumber: &n}, []string{"stateDiff"}, new(bool))
+	require.NoError(t, err)
+	require.Equal(t, len(expect), len(results[1].Transactions))
+	for i := range expect {
+		require.Equal(t, expect[i].TransactionHash, results[1].Transactions[i].TransactionHash)
+		require.Equal(t, len(expect[i].StateDiff), len(results[1].Transactions[i].StateDiff))
+	}
+
+	buf.Reset()
+	err = api.ReplayBlockRange(m.Ctx, rpc.BlockNumber(6), rpc.BlockNumber(5), []string{"stateDiff"}, new(bool), stream)
+	require.Error(t, err)
+	require.NoError(t, stream.Flush())
+	require.Zero(t, buf.Len())
+
+	limited := NewTraceAPI(newBaseApiForTest(m), m.DB, &httpcfg.HttpCfg{TraceReplayRangeLimit: 2})
+	err = limited.ReplayBlockRange(m.Ctx, rpc.BlockNumber(4), rpc.BlockNumber(6), []string{"stateDiff"}, new(bool), stream)
+	require.ErrorContains(t, err, "over the limit")
+	require.NoError(t, stream.Flush())
+	require.Zero(t, buf.Len())
+
+	err = api.ReplayBlockRange(m.Ctx, rpc.BlockNumber(5), rpc.BlockNumber(100_000), []string{"stateDiff"}, new(bool), stream)
+	require.Error(t, err)
+	require.NoError(t, stream.Flush())
+	require.Zero(t, buf.Len())
+}
+
+func TestReplayBlockTransactionsStream(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewTraceAPI(newBaseApiForTest(m), m.DB, &httpcfg.HttpCfg{})
+
+	n := rpc.BlockNumber(6)
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	err := api.ReplayBlockTransactionsStream(m.Ctx, rpc.BlockNumberOrHash{BlockNumber: &n}, []string{"trace", "stateDiff"}, new(bool), stream)
+	require.NoError(t, err)
+
+	expect, err := api.ReplayBlockTransactions(m.Ctx, rpc.BlockNumberOrHash{BlockNumber: &n}, []string{"trace", "stateDiff"}, new(bool))
+	require.NoError(t, err)
+	expectJson, err := json.Marshal(expect)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expectJson), buf.String())
+}
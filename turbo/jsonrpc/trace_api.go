@@ -19,6 +19,8 @@ type TraceAPI interface {
 
 	ReplayBlockTransactions(ctx context.Context, blockNr rpc.BlockNumberOrHash, traceTypes []string, gasBailOut *bool) ([]*TraceCallResult, error)
 	ReplayTransaction(ctx context.Context, txHash libcommon.Hash, traceTypes []string, gasBailOut *bool) (*TraceCallResult, error)
+	ReplayBlockTransactionsStream(ctx context.Context, blockNr rpc.BlockNumberOrHash, traceTypes []string, gasBailOut *bool, stream *jsoniter.Stream) error
+	ReplayBlockRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, traceTypes []string, gasBailOut *bool, stream *jsoniter.Stream) error
 	Call(ctx context.Context, call TraceCallParam, types []string, blockNr *rpc.BlockNumberOrHash) (*TraceCallResult, error)
 	CallMany(ctx context.Context, calls json.RawMessage, blockNr *rpc.BlockNumberOrHash) ([]*TraceCallResult, error)
 	RawTransaction(ctx context.Context, txHash libcommon.Hash, traceTypes []string) ([]interface{}, error)
@@ -34,19 +36,21 @@ type TraceAPI interface {
 // TraceAPIImpl is implementation of the TraceAPI interface based on remote Db access
 type TraceAPIImpl struct {
 	*BaseAPI
-	kv            kv.RoDB
-	maxTraces     uint64
-	gasCap        uint64
-	compatibility bool // Bug for bug compatiblity with OpenEthereum
+	kv               kv.RoDB
+	maxTraces        uint64
+	replayRangeLimit uint64 // Max blocks of one trace_replayBlockRange call, 0 - unlimited
+	gasCap           uint64
+	compatibility    bool // Bug for bug compatiblity with OpenEthereum
 }
 
 // NewTraceAPI returns NewTraceAPI instance
 func NewTraceAPI(base *BaseAPI, kv kv.RoDB, cfg *httpcfg.HttpCfg) *TraceAPIImpl {
 	return &TraceAPIImpl{
-		BaseAPI:       base,
-		kv:            kv,
-		maxTraces:     cfg.MaxTraces,
-		gasCap:        cfg.Gascap,
-		compatibility: cfg.TraceCompatibility,
+		BaseAPI:          base,
+		kv:               kv,
+		maxTraces:        cfg.MaxTraces,
+		replayRangeLimit: cfg.TraceReplayRangeLimit,
+		gasCap:           cfg.Gascap,
+		compatibility:    cfg.TraceCompatibility,
 	}
 }
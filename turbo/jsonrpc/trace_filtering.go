@@ -262,7 +262,7 @@ func traceFilterBitmapsV3(tx kv.TemporalTx, req TraceFilterRequest, from, to uin
 
 	switch req.Mode {
 	case TraceFilterModeIntersection:
-		allBlocks = iter.Intersect[uint64](allBlocks, blocksTo, -1)
+		allBlocks = iter.Intersect[uint64](allBlocks, blocksTo, order.Asc, -1)
 	case TraceFilterModeUnion:
 		fallthrough
 	default:
@@ -686,6 +686,29 @@ func (api *TraceAPIImpl) callManyTransactions(
 	signer *types.Signer,
 	cfg *chain.Config,
 ) ([]*TraceCallResult, consensus.SystemCall, error) {
+	traces := make([]*TraceCallResult, 0, block.Transactions().Len())
+	syscall, err := api.callManyTransactionsStream(ctx, dbtx, block, traceTypes, txIndex, gasBailOut, signer, cfg, func(traceResult *TraceCallResult) error {
+		traces = append(traces, traceResult)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return traces, syscall, nil
+}
+
+// callManyTransactionsStream - is like callManyTransactions, but passes trace of each transaction to `onResult` as soon as it's ready
+func (api *TraceAPIImpl) callManyTransactionsStream(
+	ctx context.Context,
+	dbtx kv.Tx,
+	block *types.Block,
+	traceTypes []string,
+	txIndex int,
+	gasBailOut bool,
+	signer *types.Signer,
+	cfg *chain.Config,
+	onResult func(traceResult *TraceCallResult) error,
+) (consensus.SystemCall, error) {
 	blockNumber := block.NumberU64()
 	pNo := blockNumber
 	if pNo > 0 {
@@ -704,7 +727,7 @@ func (api *TraceAPIImpl) callManyTransactions(
 		borStateSyncTxnHash = bortypes.ComputeBorTxHash(blockNumber, blockHash)
 		_, ok, err := api._blockReader.EventLookup(ctx, dbtx, borStateSyncTxnHash)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		if ok {
 			borStateSyncTxn = bortypes.NewBorTransaction()
@@ -715,12 +738,12 @@ func (api *TraceAPIImpl) callManyTransactions(
 	callParams := make([]TraceCallParam, 0, len(txs))
 	reader, err := rpchelper.CreateHistoryStateReader(dbtx, blockNumber, txIndex, cfg.ChainName)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	initialState := state.New(reader)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	engine := api.engine()
@@ -728,7 +751,7 @@ func (api *TraceAPIImpl) callManyTransactions(
 	logger := log.New("trace_filtering")
 	err = core.InitializeBlockExecution(engine.(consensus.Engine), consensusHeaderReader, block.HeaderNoCopy(), cfg, initialState, logger)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	msgs := make([]types.Message, len(txs))
@@ -744,7 +767,7 @@ func (api *TraceAPIImpl) callManyTransactions(
 			txnHash = tx.Hash()
 			msg, err = tx.AsMessage(*signer, header.BaseFee, rules)
 			if err != nil {
-				return nil, nil, fmt.Errorf("convert tx into msg: %w", err)
+				return nil, fmt.Errorf("convert tx into msg: %w", err)
 			}
 
 			// gnosis might have a fee free account here
@@ -767,21 +790,21 @@ func (api *TraceAPIImpl) callManyTransactions(
 
 	parentHash := block.ParentHash()
 
-	traces, lastState, cmErr := api.doCallMany(ctx, dbtx, msgs, callParams, &rpc.BlockNumberOrHash{
+	lastState, cmErr := api.doCallManyStream(ctx, dbtx, msgs, callParams, &rpc.BlockNumberOrHash{
 		BlockNumber:      &parentNo,
 		BlockHash:        &parentHash,
 		RequireCanonical: true,
-	}, header, gasBailOut /* gasBailout */, txIndex)
+	}, header, gasBailOut /* gasBailout */, txIndex, onResult)
 
 	if cmErr != nil {
-		return nil, nil, cmErr
+		return nil, cmErr
 	}
 
 	syscall := func(contract common.Address, data []byte) ([]byte, error) {
 		return core.SysCallContract(contract, data, cfg, lastState, header, engine, false /* constCall */)
 	}
 
-	return traces, syscall, nil
+	return syscall, nil
 }
 
 // TraceFilterRequest represents the arguments for trace_filter
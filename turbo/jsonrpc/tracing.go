@@ -9,10 +9,13 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
 
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
@@ -84,23 +87,13 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 	}
 	engine := api.engine()
 
-	_, blockCtx, _, ibs, _, err := transactions.ComputeTxEnv(ctx, engine, block, chainConfig, api._blockReader, tx, 0)
-	if err != nil {
-		stream.WriteNil()
-		return err
-	}
-
-	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
-	rules := chainConfig.Rules(block.NumberU64(), block.Time())
-	stream.WriteArrayStart()
-
 	txns := block.Transactions()
 	var borStateSyncTxn types.Transaction
 	if *config.BorTraceEnabled {
 		borStateSyncTxHash := bortypes.ComputeBorTxHash(block.NumberU64(), block.Hash())
 		_, ok, err := api._blockReader.EventLookup(ctx, tx, borStateSyncTxHash)
 		if err != nil {
-			stream.WriteArrayEnd()
+			stream.WriteNil()
 			return err
 		}
 		if ok {
@@ -109,74 +102,33 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 		}
 	}
 
-	for idx, txn := range txns {
-		isBorStateSyncTxn := borStateSyncTxn == txn
-		var txnHash common.Hash
-		if isBorStateSyncTxn {
-			txnHash = bortypes.ComputeBorTxHash(block.NumberU64(), block.Hash())
-		} else {
-			txnHash = txn.Hash()
-		}
-
-		stream.WriteObjectStart()
-		stream.WriteObjectField("txHash")
-		stream.WriteString(txnHash.Hex())
-		stream.WriteMore()
-		stream.WriteObjectField("result")
-		select {
-		default:
-		case <-ctx.Done():
-			stream.WriteNil()
-			stream.WriteObjectEnd()
-			stream.WriteArrayEnd()
-			return ctx.Err()
-		}
-		ibs.SetTxContext(txnHash, block.Hash(), idx)
-		msg, _ := txn.AsMessage(*signer, block.BaseFee(), rules)
-
-		if msg.FeeCap().IsZero() && engine != nil {
-			syscall := func(contract common.Address, data []byte) ([]byte, error) {
-				return core.SysCallContract(contract, data, chainConfig, ibs, block.Header(), engine, true /* constCall */)
-			}
-			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
-		}
-
-		txCtx := evmtypes.TxContext{
-			TxHash:     txnHash,
-			Origin:     msg.From(),
-			GasPrice:   msg.GasPrice(),
-			BlobHashes: msg.BlobHashes(),
-		}
+	env := &blockTraceEnv{
+		block:           block,
+		chainConfig:     chainConfig,
+		engine:          engine,
+		signer:          types.MakeSigner(chainConfig, block.NumberU64(), block.Time()),
+		rules:           chainConfig.Rules(block.NumberU64(), block.Time()),
+		config:          config,
+		txns:            txns,
+		borStateSyncTxn: borStateSyncTxn,
+	}
+	if api.traceBlockWorkers > 1 && len(txns) > 1 {
+		return api.traceBlockParallel(ctx, env, stream)
+	}
 
-		if isBorStateSyncTxn {
-			err = polygontracer.TraceBorStateSyncTxnDebugAPI(
-				ctx,
-				tx,
-				chainConfig,
-				config,
-				ibs,
-				api._blockReader,
-				block.Hash(),
-				block.NumberU64(),
-				block.Time(),
-				blockCtx,
-				stream,
-				api.evmCallTimeout,
-			)
-		} else {
-			err = transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
-		}
-		if err == nil {
-			err = ibs.FinalizeTx(rules, state.NewNoopWriter())
-		}
+	_, blockCtx, _, ibs, _, err := transactions.ComputeTxEnv(ctx, engine, block, chainConfig, api._blockReader, tx, 0)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	env.blockCtx = blockCtx
 
-		// if we have an error we want to output valid json for it before continuing after clearing down potential writes to the stream
-		if err != nil {
-			stream.WriteMore()
-			rpc.HandleError(err, stream)
+	stream.WriteArrayStart()
+	for idx, txn := range txns {
+		if err := api.traceBlockTxn(ctx, tx, env, ibs, idx, txn, stream); err != nil {
+			stream.WriteArrayEnd()
+			return err
 		}
-
-		stream.WriteObjectEnd()
 		if idx != len(txns)-1 {
 			stream.WriteMore()
 		}
@@ -194,6 +146,95 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 	return nil
 }
 
+// blockTraceEnv holds everything needed to trace the transactions of one block, shared by the
+// sequential and the parallel debug_traceBlock paths.
+type blockTraceEnv struct {
+	block           *types.Block
+	chainConfig     *chain.Config
+	engine          consensus.EngineReader
+	signer          *types.Signer
+	rules           *chain.Rules
+	blockCtx        evmtypes.BlockContext
+	config          *tracers.TraceConfig
+	txns            types.Transactions
+	borStateSyncTxn types.Transaction
+}
+
+// traceBlockTxn traces the idx-th transaction of the block on top of ibs and writes a complete
+// {"txHash", "result"} object to stream. Tracing failures are reported inside the object, only
+// context cancellation is returned.
+func (api *PrivateDebugAPIImpl) traceBlockTxn(ctx context.Context, tx kv.Tx, env *blockTraceEnv, ibs *state.IntraBlockState, idx int, txn types.Transaction, stream *jsoniter.Stream) error {
+	block := env.block
+	isBorStateSyncTxn := env.borStateSyncTxn == txn
+	var txnHash common.Hash
+	if isBorStateSyncTxn {
+		txnHash = bortypes.ComputeBorTxHash(block.NumberU64(), block.Hash())
+	} else {
+		txnHash = txn.Hash()
+	}
+
+	stream.WriteObjectStart()
+	stream.WriteObjectField("txHash")
+	stream.WriteString(txnHash.Hex())
+	stream.WriteMore()
+	stream.WriteObjectField("result")
+	select {
+	default:
+	case <-ctx.Done():
+		stream.WriteNil()
+		stream.WriteObjectEnd()
+		return ctx.Err()
+	}
+	ibs.SetTxContext(txnHash, block.Hash(), idx)
+	msg, _ := txn.AsMessage(*env.signer, block.BaseFee(), env.rules)
+
+	if msg.FeeCap().IsZero() && env.engine != nil {
+		syscall := func(contract common.Address, data []byte) ([]byte, error) {
+			return core.SysCallContract(contract, data, env.chainConfig, ibs, block.Header(), env.engine, true /* constCall */)
+		}
+		msg.SetIsFree(env.engine.IsServiceTransaction(msg.From(), syscall))
+	}
+
+	txCtx := evmtypes.TxContext{
+		TxHash:     txnHash,
+		Origin:     msg.From(),
+		GasPrice:   msg.GasPrice(),
+		BlobHashes: msg.BlobHashes(),
+	}
+
+	var err error
+	if isBorStateSyncTxn {
+		err = polygontracer.TraceBorStateSyncTxnDebugAPI(
+			ctx,
+			tx,
+			env.chainConfig,
+			env.config,
+			ibs,
+			api._blockReader,
+			block.Hash(),
+			block.NumberU64(),
+			block.Time(),
+			env.blockCtx,
+			stream,
+			api.evmCallTimeout,
+		)
+	} else {
+		err = transactions.TraceTx(ctx, msg, env.blockCtx, txCtx, ibs, env.config, env.chainConfig, stream, api.evmCallTimeout)
+	}
+	if err == nil {
+		err = ibs.FinalizeTx(env.rules, state.NewNoopWriter())
+	}
+
+	// if we have an error we want to output valid json for it before continuing after clearing down potential writes to the stream
+	if err != nil {
+		stream.WriteMore()
+		rpc.HandleError(err, stream)
+	}
+
+	stream.WriteObjectEnd()
+	return nil
+}
+
 // TraceTransaction implements debug_traceTransaction. Returns Geth style transaction traces.
 func (api *PrivateDebugAPIImpl) TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
 	tx, err := api.db.BeginRo(ctx)
@@ -514,6 +555,11 @@ func (api *PrivateDebugAPIImpl) TraceCallMany(ctx context.Context, bundles []Bun
 		stream.WriteArrayStart()
 		// first change blockContext
 		blockHeaderOverride(&blockCtx, bundle.BlockOverride, overrideBlockHash)
+		if err = bundleStateOverride(evm.IntraBlockState(), bundle); err != nil {
+			stream.WriteArrayEnd()
+			stream.WriteArrayEnd()
+			return err
+		}
 		for txnIndex, txn := range bundle.Transactions {
 			if txn.Gas == nil || *(txn.Gas) == 0 {
 				txn.Gas = (*hexutil.Uint64)(&api.GasCap)
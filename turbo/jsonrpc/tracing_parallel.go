@@ -0,0 +1,215 @@
+package jsonrpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+// maxTraceBlockChunkSize is the maximum number of consecutive transactions one worker traces
+// on top of a single pre-state. Bigger chunks re-use more warm state, smaller ones spread a
+// block better over the workers and let results stream out earlier.
+const maxTraceBlockChunkSize = 32
+
+// traceBlockParallel traces the transactions of a block with a pool of api.traceBlockWorkers workers.
+// The block is split in chunks of consecutive transactions. A worker takes the next chunk, opens its
+// own read transaction and history reader positioned right before the first transaction of the chunk,
+// and then traces the chunk incrementally on top of that IntraBlockState - so no worker ever has to
+// replay the block from its beginning.
+// Each transaction is traced into its own buffer. Buffers are written to stream in transaction order as
+// soon as they are ready, and the amount of finished-but-not-yet-written traces is bounded by
+// api.traceBlockMemoryBudget.
+func (api *PrivateDebugAPIImpl) traceBlockParallel(ctx context.Context, env *blockTraceEnv, stream *jsoniter.Stream) error {
+	txns := env.txns
+	workers := api.traceBlockWorkers
+	chunkSize := (len(txns) + workers - 1) / workers
+	if chunkSize > maxTraceBlockChunkSize {
+		chunkSize = maxTraceBlockChunkSize
+	}
+	chunks := (len(txns) + chunkSize - 1) / chunkSize
+	if workers > chunks {
+		workers = chunks
+	}
+
+	results := newTraceResultQueue(len(txns), uint64(api.traceBlockMemoryBudget))
+	stopAbortOnCancel := context.AfterFunc(ctx, func() { results.abort(ctx.Err()) })
+	defer stopAbortOnCancel()
+
+	var nextChunk atomic.Int64
+	g, gCtx := errgroup.WithContext(ctx)
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			for {
+				chunk := int(nextChunk.Add(1) - 1)
+				if chunk >= chunks {
+					return nil
+				}
+				from, to := chunk*chunkSize, (chunk+1)*chunkSize
+				if to > len(txns) {
+					to = len(txns)
+				}
+				if err := api.traceBlockChunk(gCtx, env, from, to, results); err != nil {
+					results.abort(err)
+					return err
+				}
+			}
+		})
+	}
+
+	stream.WriteArrayStart()
+	var err error
+	for idx := range txns {
+		var res []byte
+		if res, err = results.take(idx); err != nil {
+			break
+		}
+		if _, err = stream.Write(res); err != nil {
+			break
+		}
+		if idx != len(txns)-1 {
+			stream.WriteMore()
+		}
+		if err = stream.Flush(); err != nil {
+			break
+		}
+		results.release(idx)
+	}
+	// stop the workers (no-op if all of them are done) before the read transactions go away
+	results.abort(err)
+	if werr := g.Wait(); err == nil {
+		err = werr
+	}
+	stream.WriteArrayEnd()
+	if err != nil {
+		return err
+	}
+	return stream.Flush()
+}
+
+// traceBlockChunk traces txns[from:to) of the block on top of the state right before txns[from].
+func (api *PrivateDebugAPIImpl) traceBlockChunk(ctx context.Context, env *blockTraceEnv, from, to int, results *traceResultQueue) error {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	block := env.block
+	reader, err := rpchelper.CreateHistoryStateReader(tx, block.NumberU64(), from, env.chainConfig.ChainName)
+	if err != nil {
+		return err
+	}
+	ibs := state.New(reader)
+
+	// the block context reads headers for BLOCKHASH, so it has to use this worker's own read transaction
+	header := block.HeaderNoCopy()
+	getHeader := func(hash common.Hash, n uint64) *types.Header {
+		h, _ := api._blockReader.HeaderByNumber(ctx, tx, n)
+		return h
+	}
+	chunkEnv := *env
+	chunkEnv.blockCtx = core.NewEVMBlockContext(header, core.GetHashFn(header, getHeader), env.engine, nil)
+
+	for idx := from; idx < to; idx++ {
+		s := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096)
+		if err := api.traceBlockTxn(ctx, tx, &chunkEnv, ibs, idx, env.txns[idx], s); err != nil {
+			return err
+		}
+		if err := results.put(idx, s.Buffer()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceResultQueue hands per-transaction traces from the workers over to the writer in transaction order.
+// put blocks while the traces waiting to be written exceed the memory budget, except for the trace the
+// writer is waiting for: it is always accepted, so the pool can't deadlock on the budget.
+type traceResultQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	results  [][]byte
+	ready    []bool
+	next     int    // index of the transaction the writer is waiting for
+	inFlight uint64 // bytes of traces put but not yet released
+	budget   uint64 // 0 means unbounded
+	aborted  bool
+	err      error
+}
+
+func newTraceResultQueue(n int, budget uint64) *traceResultQueue {
+	q := &traceResultQueue{
+		results: make([][]byte, n),
+		ready:   make([]bool, n),
+		budget:  budget,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *traceResultQueue) put(idx int, res []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	size := uint64(len(res))
+	for !q.aborted && idx != q.next && q.budget > 0 && q.inFlight+size > q.budget {
+		q.cond.Wait()
+	}
+	if q.aborted {
+		return q.abortErr()
+	}
+	q.results[idx], q.ready[idx] = res, true
+	q.inFlight += size
+	q.cond.Broadcast()
+	return nil
+}
+
+func (q *traceResultQueue) take(idx int) ([]byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for !q.aborted && !q.ready[idx] {
+		q.cond.Wait()
+	}
+	if q.aborted {
+		return nil, q.abortErr()
+	}
+	return q.results[idx], nil
+}
+
+// release frees the memory of an already written trace and moves on to the next transaction.
+func (q *traceResultQueue) release(idx int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inFlight -= uint64(len(q.results[idx]))
+	q.results[idx] = nil
+	q.next = idx + 1
+	q.cond.Broadcast()
+}
+
+// abort wakes up and fails all waiters. The first non-nil err is kept.
+func (q *traceResultQueue) abort(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.aborted = true
+	if q.err == nil {
+		q.err = err
+	}
+	q.cond.Broadcast()
+}
+
+func (q *traceResultQueue) abortErr() error {
+	if q.err != nil {
+		return q.err
+	}
+	return context.Canceled
+}
@@ -3,22 +3,38 @@ package jsonrpc
 import (
 	"context"
 	"fmt"
+	"sort"
 
+	"github.com/holiman/uint256"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	proto_txpool "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
+	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/typesproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/common/debug"
+	"github.com/ledgerwatch/erigon/consensus/misc"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
 )
 
 // TxPoolAPI the interface for the txpool_ RPC commands
 type TxPoolAPI interface {
 	Content(ctx context.Context) (map[string]map[string]map[string]*RPCTransaction, error)
 	ContentFrom(ctx context.Context, addr libcommon.Address) (map[string]map[string]*RPCTransaction, error)
+	ContentPage(ctx context.Context, filter ContentPageFilter) (*ContentPage, error)
+	PoolEvents(ctx context.Context, crit *PoolEventsCriteria) (*rpc.Subscription, error)
+	BlobFeeEstimate(ctx context.Context, blocks hexutil.Uint64) (*BlobFeeEstimate, error)
+	Locals(ctx context.Context) ([]*LocalTransaction, error)
 }
 
 // TxPoolAPIImpl data structure to store things needed for net_ commands
@@ -119,7 +135,7 @@ func (api *TxPoolAPIImpl) Content(ctx context.Context) (map[string]map[string]ma
 }
 
 func (api *TxPoolAPIImpl) ContentFrom(ctx context.Context, addr libcommon.Address) (map[string]map[string]*RPCTransaction, error) {
-	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{})
+	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{Senders: []*types2.H160{gointerfaces.ConvertAddressToH160(addr)}})
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +204,197 @@ func (api *TxPoolAPIImpl) ContentFrom(ctx context.Context, addr libcommon.Addres
 	return content, nil
 }
 
+const (
+	contentPageDefaultLimit = 1_000
+	contentPageMaxLimit     = 10_000
+)
+
+// ContentPageFilter - parameters of txpool_contentPage. Each filter which is set must match, a sender or type filter
+// matches if any of its items does.
+type ContentPageFilter struct {
+	Cursor hexutility.Bytes    `json:"cursor"` // next of the previous page, from the start if empty
+	Limit  hexutil.Uint64      `json:"limit"`  // max amount of transactions in the page, contentPageDefaultLimit if 0
+	From   []libcommon.Address `json:"from"`
+	MinTip *hexutil.Big        `json:"minTip"` // compared to max priority fee per gas, or to gas price of legacy transactions
+	Types  []hexutil.Uint64    `json:"types"`
+}
+
+// ContentPage is the result of txpool_contentPage
+type ContentPage struct {
+	Pending map[string]map[string]*RPCTransaction `json:"pending"`
+	BaseFee map[string]map[string]*RPCTransaction `json:"baseFee"`
+	Queued  map[string]map[string]*RPCTransaction `json:"queued"`
+	Next    hexutility.Bytes                      `json:"next,omitempty"` // cursor of the next page, empty on the last one
+}
+
+// ContentPage returns the page of pool transactions selected by filter, in the layout of txpool_content.
+// Transactions are ordered by sender, then by nonce - pages are not overlapping, even if the pool changes between calls.
+func (api *TxPoolAPIImpl) ContentPage(ctx context.Context, filter ContentPageFilter) (*ContentPage, error) {
+	req := &proto_txpool.AllRequest{Cursor: filter.Cursor, Limit: uint32(filter.Limit)}
+	if filter.Limit == 0 {
+		req.Limit = contentPageDefaultLimit
+	}
+	if filter.Limit > contentPageMaxLimit {
+		return nil, fmt.Errorf("limit must not exceed %d", contentPageMaxLimit)
+	}
+	for _, addr := range filter.From {
+		req.Senders = append(req.Senders, gointerfaces.ConvertAddressToH160(addr))
+	}
+	if filter.MinTip != nil {
+		minTip, overflow := uint256.FromBig(filter.MinTip.ToInt())
+		if overflow || filter.MinTip.ToInt().Sign() < 0 {
+			return nil, fmt.Errorf("invalid minTip %s", filter.MinTip)
+		}
+		req.MinTip = gointerfaces.ConvertUint256IntToH256(minTip)
+	}
+	for _, typ := range filter.Types {
+		if typ > 0xff {
+			return nil, fmt.Errorf("invalid transaction type %d", typ)
+		}
+		req.TxTypes = append(req.TxTypes, uint32(typ))
+	}
+	reply, err := api.pool.All(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	cc, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	curHeader := rawdb.ReadCurrentHeader(tx)
+	if curHeader == nil {
+		return nil, nil
+	}
+
+	page := &ContentPage{
+		Pending: make(map[string]map[string]*RPCTransaction),
+		BaseFee: make(map[string]map[string]*RPCTransaction),
+		Queued:  make(map[string]map[string]*RPCTransaction),
+		Next:    reply.NextCursor,
+	}
+	for i := range reply.Txs {
+		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
+		}
+		var subPool map[string]map[string]*RPCTransaction
+		switch reply.Txs[i].TxnType {
+		case proto_txpool.AllReply_PENDING:
+			subPool = page.Pending
+		case proto_txpool.AllReply_BASE_FEE:
+			subPool = page.BaseFee
+		case proto_txpool.AllReply_QUEUED:
+			subPool = page.Queued
+		default:
+			continue
+		}
+		account := libcommon.Address(gointerfaces.ConvertH160toAddress(reply.Txs[i].Sender)).Hex()
+		if _, ok := subPool[account]; !ok {
+			subPool[account] = make(map[string]*RPCTransaction)
+		}
+		subPool[account][fmt.Sprintf("%d", txn.GetNonce())] = newRPCPendingTransaction(txn, curHeader, cc)
+	}
+	return page, nil
+}
+
+// PoolEventsCriteria - options of the txpool_subscribe("poolEvents") subscription
+type PoolEventsCriteria struct {
+	FullTx bool                `json:"fullTx"` // notifications about added transactions carry the transaction object
+	From   []libcommon.Address `json:"from"`   // only transactions of these senders, all if empty
+}
+
+// PoolEvent is a notification of txpool_subscribe("poolEvents")
+type PoolEvent struct {
+	Type        string            `json:"type"` // "added" or "dropped"
+	Hash        libcommon.Hash    `json:"hash"`
+	From        libcommon.Address `json:"from"`
+	Reason      string            `json:"reason,omitempty"`      // why transaction was dropped
+	Transaction *RPCTransaction   `json:"transaction,omitempty"` // added transaction, with fullTx
+}
+
+// PoolEvents send a notification each time when a transaction is added to the pool or dropped from it, with the reason
+// of drop (mined, replaced, evicted on overflow, ...). Subscription ends if the subscriber doesn't keep up with the pool.
+func (api *TxPoolAPIImpl) PoolEvents(ctx context.Context, crit *PoolEventsCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var fullTx bool
+	var from map[libcommon.Address]struct{}
+	if crit != nil {
+		fullTx = crit.FullTx
+		if len(crit.From) > 0 {
+			from = make(map[libcommon.Address]struct{}, len(crit.From))
+			for _, addr := range crit.From {
+				from[addr] = struct{}{}
+			}
+		}
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := api.pool.OnEvent(streamCtx, &proto_txpool.OnEventRequest{})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer debug.LogPanic()
+		defer cancel()
+		go func() {
+			select {
+			case <-rpcSub.Err():
+				cancel()
+			case <-streamCtx.Done():
+			}
+		}()
+
+		for {
+			reply, err := stream.Recv()
+			if err != nil {
+				if streamCtx.Err() == nil {
+					log.Warn("[rpc] pool events stream closed", "err", err)
+				}
+				return
+			}
+			for _, ev := range reply.Events {
+				event := &PoolEvent{
+					Type: "added",
+					Hash: gointerfaces.ConvertH256ToHash(ev.Hash),
+					From: gointerfaces.ConvertH160toAddress(ev.Sender),
+				}
+				if from != nil {
+					if _, ok := from[event.From]; !ok {
+						continue
+					}
+				}
+				if ev.Type == proto_txpool.OnEventReply_DROPPED {
+					event.Type, event.Reason = "dropped", ev.Reason
+				} else if fullTx && len(ev.RlpTx) > 0 {
+					txn, err := types.DecodeWrappedTransaction(ev.RlpTx)
+					if err != nil {
+						log.Warn("[rpc] decoding pool event transaction", "hash", event.Hash, "err", err)
+					} else {
+						event.Transaction = NewRPCTransaction(txn, libcommon.Hash{}, 0, 0, nil)
+					}
+				}
+				if err := notifier.Notify(rpcSub.ID, event); err != nil {
+					log.Warn("[rpc] error while notifying subscription", "err", err)
+				}
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (api *TxPoolAPIImpl) Status(ctx context.Context) (map[string]hexutil.Uint, error) {
 	reply, err := api.pool.Status(ctx, &proto_txpool.StatusRequest{})
@@ -201,6 +408,193 @@ func (api *TxPoolAPIImpl) Status(ctx context.Context) (map[string]hexutil.Uint,
 	}, nil
 }
 
+// LocalTransaction is an element of txpool_locals result
+type LocalTransaction struct {
+	Added       hexutil.Uint64  `json:"added"` // unix time the transaction was submitted to this node
+	Transaction *RPCTransaction `json:"transaction"`
+}
+
+// Locals returns transactions submitted to this node which are journaled and re-broadcast until inclusion or expiry,
+// in order of submission.
+func (api *TxPoolAPIImpl) Locals(ctx context.Context) ([]*LocalTransaction, error) {
+	reply, err := api.pool.Locals(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	cc, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	curHeader := rawdb.ReadCurrentHeader(tx)
+	if curHeader == nil {
+		return nil, nil
+	}
+	locals := make([]*LocalTransaction, 0, len(reply.Txs))
+	for i := range reply.Txs {
+		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
+		}
+		locals = append(locals, &LocalTransaction{
+			Added:       hexutil.Uint64(reply.Txs[i].Added),
+			Transaction: newRPCPendingTransaction(txn, curHeader, cc),
+		})
+	}
+	sort.SliceStable(locals, func(i, j int) bool {
+		if locals[i].Added != locals[j].Added {
+			return locals[i].Added < locals[j].Added
+		}
+		return locals[i].Transaction.Nonce < locals[j].Transaction.Nonce
+	})
+	return locals, nil
+}
+
+const (
+	maxBlobFeeEstimateBlocks = 1024 // max number of future blocks txpool_blobFeeEstimate projects
+	blobFeeHistoryBlocks     = 32   // number of recent blocks used to estimate blob demand not yet seen by the pool
+)
+
+// BlobFeeEstimate is the result of txpool_blobFeeEstimate
+type BlobFeeEstimate struct {
+	BlobBaseFee          *hexutil.Big     `json:"blobBaseFee"`          // blob base fee of the next block
+	EstimatedBlobBaseFee *hexutil.Big     `json:"estimatedBlobBaseFee"` // max projected blob base fee within requested blocks
+	ExcessBlobGas        []hexutil.Uint64 `json:"excessBlobGas"`        // projected excess blob gas of each of requested blocks
+	PendingBlobs         hexutil.Uint64   `json:"pendingBlobs"`         // number of blobs waiting for inclusion in the pool
+	RecentBlobGasUsed    hexutil.Uint64   `json:"recentBlobGasUsed"`    // average blob gas used by recent blocks
+}
+
+// pendingBlobTx - blob demand of a single pool transaction
+type pendingBlobTx struct {
+	blobGas          uint64
+	maxFeePerBlobGas *uint256.Int
+}
+
+// BlobFeeEstimate estimates blob base fee which is enough for inclusion within the given number of blocks.
+// Blocks are projected by including pending blob transactions of the pool (highest max fee per blob gas first),
+// while recent blocks' average blob gas usage is used as lower bound of demand per block.
+func (api *TxPoolAPIImpl) BlobFeeEstimate(ctx context.Context, blocks hexutil.Uint64) (*BlobFeeEstimate, error) {
+	if blocks == 0 || blocks > maxBlobFeeEstimateBlocks {
+		return nil, fmt.Errorf("blocks must be in range [1, %d]", maxBlobFeeEstimateBlocks)
+	}
+	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var pending []pendingBlobTx
+	for i := range reply.Txs {
+		if reply.Txs[i].TxnType == proto_txpool.AllReply_QUEUED {
+			continue
+		}
+		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
+		if err != nil {
+			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
+		}
+		var blobTx *types.BlobTx
+		switch t := txn.(type) {
+		case *types.BlobTx:
+			blobTx = t
+		case *types.BlobTxWrapper:
+			blobTx = &t.Tx
+		default:
+			continue
+		}
+		pending = append(pending, pendingBlobTx{blobGas: blobTx.GetBlobGas(), maxFeePerBlobGas: blobTx.MaxFeePerBlobGas})
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	cc, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	curHeader := rawdb.ReadCurrentHeader(tx)
+	if curHeader == nil {
+		return nil, nil
+	}
+	if curHeader.ExcessBlobGas == nil {
+		return nil, fmt.Errorf("blob transactions are not activated at block %d", curHeader.Number.Uint64())
+	}
+
+	var recentBlobGasUsed, recentBlocks uint64
+	for i := uint64(0); i < blobFeeHistoryBlocks && i <= curHeader.Number.Uint64(); i++ {
+		header, err := api._blockReader.HeaderByNumber(ctx, tx, curHeader.Number.Uint64()-i)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil || header.BlobGasUsed == nil {
+			break
+		}
+		recentBlobGasUsed += *header.BlobGasUsed
+		recentBlocks++
+	}
+	if recentBlocks > 0 {
+		recentBlobGasUsed /= recentBlocks
+	}
+
+	estimate, err := projectBlobFees(cc, curHeader, pending, recentBlobGasUsed, uint64(blocks))
+	if err != nil {
+		return nil, err
+	}
+	estimate.RecentBlobGasUsed = hexutil.Uint64(recentBlobGasUsed)
+	return estimate, nil
+}
+
+// projectBlobFees simulates blob fee market for next n blocks after parent.
+// Each block includes pending blob transactions which pay its blob base fee (highest max fee first) until block is full,
+// but not less than backgroundBlobGas - demand which is not visible in the pool yet.
+func projectBlobFees(cc *chain.Config, parent *types.Header, pending []pendingBlobTx, backgroundBlobGas, n uint64) (*BlobFeeEstimate, error) {
+	maxBlobGas := cc.GetMaxBlobGasPerBlock()
+	backgroundBlobGas = min(backgroundBlobGas, maxBlobGas)
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].maxFeePerBlobGas.Gt(pending[j].maxFeePerBlobGas)
+	})
+	estimate := &BlobFeeEstimate{ExcessBlobGas: make([]hexutil.Uint64, 0, n)}
+	for _, p := range pending {
+		estimate.PendingBlobs += hexutil.Uint64(p.blobGas / fixedgas.BlobGasPerBlob)
+	}
+
+	maxFee := new(uint256.Int)
+	included := make([]bool, len(pending))
+	excessBlobGas := misc.CalcExcessBlobGas(cc, parent)
+	for i := uint64(0); i < n; i++ {
+		fee, err := misc.GetBlobGasPrice(cc, excessBlobGas)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			estimate.BlobBaseFee = (*hexutil.Big)(fee.ToBig())
+		}
+		if fee.Gt(maxFee) {
+			maxFee.Set(fee)
+		}
+		estimate.ExcessBlobGas = append(estimate.ExcessBlobGas, hexutil.Uint64(excessBlobGas))
+
+		var blobGasUsed uint64
+		for j, p := range pending {
+			if included[j] || p.maxFeePerBlobGas.Lt(fee) || blobGasUsed+p.blobGas > maxBlobGas {
+				continue
+			}
+			blobGasUsed += p.blobGas
+			included[j] = true
+		}
+		blobGasUsed = max(blobGasUsed, backgroundBlobGas)
+		excessBlobGas = misc.CalcExcessBlobGas(cc, &types.Header{ExcessBlobGas: &excessBlobGas, BlobGasUsed: &blobGasUsed})
+	}
+	estimate.EstimatedBlobBaseFee = (*hexutil.Big)(maxFee.ToBig())
+	return estimate, nil
+}
+
 /*
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -3,10 +3,12 @@ package jsonrpc
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 	"github.com/ledgerwatch/erigon-lib/config3"
 	txPoolProto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpoolproto"
@@ -15,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/consensus/misc"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/params"
@@ -69,3 +72,101 @@ func TestTxPoolContent(t *testing.T) {
 	require.Equal(status["pending"], hexutil.Uint(1))
 	require.Equal(status["queued"], hexutil.Uint(0))
 }
+
+func TestTxPoolContentPage(t *testing.T) {
+	if config3.EnableHistoryV4InTest {
+		t.Skip("TODO: [e4] implement me")
+	}
+
+	m, require := mock.MockWithTxPool(t), require.New(t)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(libcommon.Address{1})
+	})
+	require.NoError(err)
+	err = m.InsertChain(chain)
+	require.NoError(err)
+
+	ctx, conn := rpcdaemontest.CreateTestGrpcConn(t, m)
+	txPool := txpool.NewTxpoolClient(conn)
+	ff := rpchelper.New(ctx, nil, txPool, txpool.NewMiningClient(conn), func() {}, m.Log)
+	agg := m.HistoryV3Components()
+	api := NewTxPoolAPI(NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, txPool)
+
+	var rlpTxs [][]byte
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		txn, err := types.SignTx(types.NewTransaction(nonce, libcommon.Address{1}, uint256.NewInt(1), params.TxGas, uint256.NewInt((nonce+1)*params.GWei), nil), *types.LatestSignerForChainID(m.ChainConfig.ChainID), m.Key)
+		require.NoError(err)
+		buf := bytes.NewBuffer(nil)
+		require.NoError(txn.MarshalBinary(buf))
+		rlpTxs = append(rlpTxs, buf.Bytes())
+	}
+	reply, err := txPool.Add(ctx, &txpool.AddRequest{RlpTxs: rlpTxs})
+	require.NoError(err)
+	for _, res := range reply.Imported {
+		require.Equal(res, txPoolProto.ImportResult_SUCCESS, fmt.Sprintf("%s", reply.Errors))
+	}
+
+	sender := m.Address.String()
+	page, err := api.ContentPage(ctx, ContentPageFilter{Limit: 2})
+	require.NoError(err)
+	require.Len(page.Pending[sender], 2)
+	require.NotEmpty(page.Next)
+	page, err = api.ContentPage(ctx, ContentPageFilter{Limit: 2, Cursor: page.Next})
+	require.NoError(err)
+	require.Len(page.Pending[sender], 1)
+	require.NotNil(page.Pending[sender]["2"])
+	require.Empty(page.Next)
+
+	page, err = api.ContentPage(ctx, ContentPageFilter{MinTip: (*hexutil.Big)(big.NewInt(2 * params.GWei))})
+	require.NoError(err)
+	require.Len(page.Pending[sender], 2)
+	page, err = api.ContentPage(ctx, ContentPageFilter{From: []libcommon.Address{{1}}})
+	require.NoError(err)
+	require.Empty(page.Pending)
+	page, err = api.ContentPage(ctx, ContentPageFilter{Types: []hexutil.Uint64{types.DynamicFeeTxType}})
+	require.NoError(err)
+	require.Empty(page.Pending)
+	_, err = api.ContentPage(ctx, ContentPageFilter{Limit: contentPageMaxLimit + 1})
+	require.Error(err)
+
+	content, err := api.ContentFrom(ctx, m.Address)
+	require.NoError(err)
+	require.Len(content["pending"], 3)
+}
+
+func TestProjectBlobFees(t *testing.T) {
+	require := require.New(t)
+	cc := params.TestChainConfig
+	maxBlobGas, targetBlobGas := cc.GetMaxBlobGasPerBlock(), cc.GetTargetBlobGasPerBlock()
+	excessBlobGas, blobGasUsed := 10*targetBlobGas, targetBlobGas
+	parent := &types.Header{ExcessBlobGas: &excessBlobGas, BlobGasUsed: &blobGasUsed}
+
+	// no demand - fee goes down
+	estimate, err := projectBlobFees(cc, parent, nil, 0, 3)
+	require.NoError(err)
+	require.Equal([]hexutil.Uint64{hexutil.Uint64(excessBlobGas), hexutil.Uint64(excessBlobGas - targetBlobGas), hexutil.Uint64(excessBlobGas - 2*targetBlobGas)}, estimate.ExcessBlobGas)
+	require.Equal(estimate.BlobBaseFee.ToInt(), estimate.EstimatedBlobBaseFee.ToInt())
+	require.Zero(estimate.PendingBlobs)
+
+	// background demand at target - fee stays the same
+	estimate, err = projectBlobFees(cc, parent, nil, targetBlobGas, 3)
+	require.NoError(err)
+	require.Equal([]hexutil.Uint64{hexutil.Uint64(excessBlobGas), hexutil.Uint64(excessBlobGas), hexutil.Uint64(excessBlobGas)}, estimate.ExcessBlobGas)
+
+	// pool fills 2 blocks completely, then fee goes down again
+	var pending []pendingBlobTx
+	for i := uint64(0); i < 2*cc.GetMaxBlobsPerBlock(); i++ {
+		pending = append(pending, pendingBlobTx{blobGas: fixedgas.BlobGasPerBlob, maxFeePerBlobGas: uint256.NewInt(params.GWei)})
+	}
+	// too cheap to be included
+	pending = append(pending, pendingBlobTx{blobGas: fixedgas.BlobGasPerBlob, maxFeePerBlobGas: uint256.NewInt(0)})
+	estimate, err = projectBlobFees(cc, parent, pending, 0, 4)
+	require.NoError(err)
+	grown := excessBlobGas + 2*(maxBlobGas-targetBlobGas)
+	require.Equal([]hexutil.Uint64{hexutil.Uint64(excessBlobGas), hexutil.Uint64(excessBlobGas + maxBlobGas - targetBlobGas), hexutil.Uint64(grown), hexutil.Uint64(grown - targetBlobGas)}, estimate.ExcessBlobGas)
+	require.Equal(hexutil.Uint64(2*cc.GetMaxBlobsPerBlock()+1), estimate.PendingBlobs)
+	expectedFee, err := misc.GetBlobGasPrice(cc, grown)
+	require.NoError(err)
+	require.Equal(expectedFee.ToBig(), estimate.EstimatedBlobBaseFee.ToInt())
+	require.Equal(-1, estimate.BlobBaseFee.ToInt().Cmp(estimate.EstimatedBlobBaseFee.ToInt()))
+}
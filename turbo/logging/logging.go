@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/ledgerwatch/log/v3"
 	"github.com/spf13/cobra"
@@ -183,6 +184,35 @@ func SetupLogger(filePrefix string) log.Logger {
 	return log.Root()
 }
 
+// consoleLevel, dirLevel - levels of the handlers set by initSeparatedLogging, can be changed at runtime by SetLogLevels
+var consoleLevel, dirLevel atomic.Int32
+
+func lvlFilterHandler(lvl *atomic.Int32, h log.Handler) log.Handler {
+	return log.FilterHandler(func(r *log.Record) bool {
+		return r.Lvl <= log.Lvl(lvl.Load())
+	}, h)
+}
+
+// SetConsoleLevel - changes level of console logs at runtime, accepts same values as --log.console.verbosity
+func SetConsoleLevel(s string) error {
+	lvl, err := tryGetLogLevel(s)
+	if err != nil {
+		return err
+	}
+	consoleLevel.Store(int32(lvl))
+	return nil
+}
+
+// SetDirLevel - changes level of logs stored to disk at runtime, accepts same values as --log.dir.verbosity
+func SetDirLevel(s string) error {
+	lvl, err := tryGetLogLevel(s)
+	if err != nil {
+		return err
+	}
+	dirLevel.Store(int32(lvl))
+	return nil
+}
+
 // initSeparatedLogging construct a log handler accrosing to the configuration parameters passed to it
 // and sets the constructed handler to be the handler of the given logger. It then uses that logger
 // to report the status of this initialisation
@@ -190,17 +220,19 @@ func initSeparatedLogging(
 	logger log.Logger,
 	filePrefix string,
 	dirPath string,
-	consoleLevel log.Lvl,
-	dirLevel log.Lvl,
+	consoleLvl log.Lvl,
+	dirLvl log.Lvl,
 	consoleJson bool,
 	dirJson bool) {
 
+	consoleLevel.Store(int32(consoleLvl))
+	dirLevel.Store(int32(dirLvl))
 	var consoleHandler log.Handler
 
 	if consoleJson {
-		consoleHandler = log.LvlFilterHandler(consoleLevel, log.StreamHandler(os.Stderr, log.JsonFormat()))
+		consoleHandler = lvlFilterHandler(&consoleLevel, log.StreamHandler(os.Stderr, log.JsonFormat()))
 	} else {
-		consoleHandler = log.LvlFilterHandler(consoleLevel, log.StderrHandler)
+		consoleHandler = lvlFilterHandler(&consoleLevel, log.StderrHandler)
 	}
 	logger.SetHandler(consoleHandler)
 
@@ -228,9 +260,9 @@ func initSeparatedLogging(
 	}
 	userLog := log.StreamHandler(lumberjack, dirFormat)
 
-	mux := log.MultiHandler(consoleHandler, log.LvlFilterHandler(dirLevel, userLog))
+	mux := log.MultiHandler(consoleHandler, lvlFilterHandler(&dirLevel, userLog))
 	logger.SetHandler(mux)
-	logger.Info("logging to file system", "log dir", dirPath, "file prefix", filePrefix, "log level", dirLevel, "json", dirJson)
+	logger.Info("logging to file system", "log dir", dirPath, "file prefix", filePrefix, "log level", dirLvl, "json", dirJson)
 }
 
 func tryGetLogLevel(s string) (log.Lvl, error) {
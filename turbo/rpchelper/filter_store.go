@@ -0,0 +1,286 @@
+package rpchelper
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+
+	"github.com/ledgerwatch/erigon/eth/filters"
+)
+
+const (
+	FilterKindLogs   = "logs"
+	FilterKindBlocks = "blocks"
+)
+
+const (
+	// FilterBacklogMaxBlocks - a restored filter receives what it missed while rpcdaemon was down, but not more than this many latest blocks
+	FilterBacklogMaxBlocks    = 4096
+	filterExpiryCheckInterval = time.Minute
+)
+
+// PersistedFilter - polling filter installed by eth_newFilter or eth_newBlockFilter, as it is kept in FilterStore
+type PersistedFilter struct {
+	Kind      string              `json:"kind"`
+	Addresses []libcommon.Address `json:"addresses,omitempty"`
+	Topics    [][]libcommon.Hash  `json:"topics,omitempty"`
+	// LastBlock - highest block which client already received (or filter creation head if it never polled)
+	LastBlock uint64 `json:"lastBlock"`
+	// LastPoll - unix time of the last poll (or of creation), filters not polled for a while are expired
+	LastPoll int64 `json:"lastPoll"`
+}
+
+// BacklogFrom - first block of the backlog of a restored filter, given the latest block
+func (f PersistedFilter) BacklogFrom(latest uint64) uint64 {
+	if latest >= FilterBacklogMaxBlocks && f.LastBlock+1 < latest-FilterBacklogMaxBlocks+1 {
+		return latest - FilterBacklogMaxBlocks + 1
+	}
+	return f.LastBlock + 1
+}
+
+// FilterStore - small on-disk table of polling filters, allows them to survive rpcdaemon restarts
+type FilterStore struct {
+	db kv.RwDB
+}
+
+const filtersTableName = "Filters"
+
+func OpenFilterStore(ctx context.Context, path string, logger log.Logger) (*FilterStore, error) {
+	db, err := mdbx.NewMDBX(logger).
+		Path(path).
+		WithTableCfg(func(_ kv.TableCfg) kv.TableCfg { return kv.TableCfg{filtersTableName: {}} }).
+		MapSize(1 * datasize.GB).
+		GrowthStep(1 * datasize.MB).
+		Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &FilterStore{db: db}, nil
+}
+
+func (s *FilterStore) Close() {
+	s.db.Close()
+}
+
+func (s *FilterStore) put(ctx context.Context, id SubscriptionID, f PersistedFilter) error {
+	v, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(filtersTableName, []byte(id), v)
+	})
+}
+
+func (s *FilterStore) delete(ctx context.Context, id SubscriptionID) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Delete(filtersTableName, []byte(id))
+	})
+}
+
+// advance - records a poll of filter which received everything up to blockNum, does nothing for unknown filters
+func (s *FilterStore) advance(ctx context.Context, id SubscriptionID, blockNum uint64, now time.Time) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		v, err := tx.GetOne(filtersTableName, []byte(id))
+		if err != nil || v == nil {
+			return err
+		}
+		var f PersistedFilter
+		if err := json.Unmarshal(v, &f); err != nil {
+			return err
+		}
+		f.LastBlock = max(f.LastBlock, blockNum)
+		f.LastPoll = now.Unix()
+		if v, err = json.Marshal(f); err != nil {
+			return err
+		}
+		return tx.Put(filtersTableName, []byte(id), v)
+	})
+}
+
+func (s *FilterStore) all(ctx context.Context) (map[SubscriptionID]PersistedFilter, error) {
+	res := map[SubscriptionID]PersistedFilter{}
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		return tx.ForEach(filtersTableName, nil, func(k, v []byte) error {
+			var f PersistedFilter
+			if err := json.Unmarshal(v, &f); err != nil {
+				return err
+			}
+			res[SubscriptionID(k)] = f
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Restore - attaches store to filters and re-installs polling filters persisted by previous run.
+// Logs and blocks which restored filters missed while rpcdaemon was down are available via Backlog.
+// Filters not polled for longer than expiry are uninstalled, never if expiry is 0.
+func (ff *Filters) Restore(ctx context.Context, store *FilterStore, expiry time.Duration) error {
+	persisted, err := store.all(ctx)
+	if err != nil {
+		return err
+	}
+	ff.store = store
+	ff.filterExpiry = expiry
+	var restoredLogs bool
+	for id, f := range persisted {
+		if ff.expired(f, time.Now()) {
+			ff.forget(id)
+			delete(persisted, id)
+			continue
+		}
+		switch f.Kind {
+		case FilterKindLogs:
+			logsID := LogsSubID(id)
+			logs := ff.subscribeLogs(logsID, 256, filters.FilterCriteria{Addresses: f.Addresses, Topics: f.Topics})
+			go func() {
+				for lg := range logs {
+					ff.AddLogs(logsID, lg)
+				}
+			}()
+			restoredLogs = true
+		case FilterKindBlocks:
+			headsID := HeadsSubID(id)
+			headers := ff.subscribeNewHeads(headsID, 32)
+			go func() {
+				for h := range headers {
+					ff.AddPendingBlock(headsID, h)
+				}
+			}()
+		default:
+			ff.logger.Warn("rpc filters: unknown persisted filter kind", "id", id, "kind", f.Kind)
+			continue
+		}
+		ff.backlogs.Put(id, f)
+	}
+	if restoredLogs {
+		// logs subscription to Erigon is likely not established yet - so restored filters
+		// can't be sent to it right away
+		go ff.requestRestoredLogs(ctx)
+	}
+	if expiry > 0 {
+		go ff.expireFiltersLoop(ctx)
+	}
+	ff.logger.Info("rpc filters: restored persisted filters", "count", len(persisted))
+	return nil
+}
+
+func (ff *Filters) expired(f PersistedFilter, now time.Time) bool {
+	return ff.filterExpiry > 0 && now.Sub(time.Unix(f.LastPoll, 0)) > ff.filterExpiry
+}
+
+func (ff *Filters) expireFiltersLoop(ctx context.Context) {
+	ticker := time.NewTicker(filterExpiryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := ff.expireFilters(ctx, now); err != nil {
+				ff.logger.Warn("rpc filters: could not expire filters", "err", err)
+			}
+		}
+	}
+}
+
+// expireFilters - uninstalls persisted filters which were not polled for longer than expiry
+func (ff *Filters) expireFilters(ctx context.Context, now time.Time) error {
+	persisted, err := ff.store.all(ctx)
+	if err != nil {
+		return err
+	}
+	for id, f := range persisted {
+		if !ff.expired(f, now) {
+			continue
+		}
+		switch f.Kind {
+		case FilterKindLogs:
+			ff.UnsubscribeLogs(LogsSubID(id))
+		case FilterKindBlocks:
+			ff.UnsubscribeHeads(HeadsSubID(id))
+		}
+		ff.forget(id)
+		ff.logger.Debug("rpc filters: expired filter", "id", id, "kind", f.Kind, "lastPoll", time.Unix(f.LastPoll, 0))
+	}
+	return nil
+}
+
+func (ff *Filters) requestRestoredLogs(ctx context.Context) {
+	for {
+		if loaded := ff.loadLogsRequester(); loaded != nil {
+			if err := loaded.(func(*remote.LogsFilterRequest) error)(ff.logsFilterRequest()); err != nil {
+				ff.logger.Warn("Could not update remote logs filter", "err", err)
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Persistent - true if polling filters survive restarts
+func (ff *Filters) Persistent() bool {
+	return ff.store != nil
+}
+
+// PersistLogsFilter - saves filter installed by eth_newFilter, lastBlock is the head at the moment of creation
+func (ff *Filters) PersistLogsFilter(id LogsSubID, crit filters.FilterCriteria, lastBlock uint64) {
+	ff.persist(SubscriptionID(id), PersistedFilter{Kind: FilterKindLogs, Addresses: crit.Addresses, Topics: crit.Topics, LastBlock: lastBlock})
+}
+
+// PersistBlockFilter - saves filter installed by eth_newBlockFilter, lastBlock is the head at the moment of creation
+func (ff *Filters) PersistBlockFilter(id HeadsSubID, lastBlock uint64) {
+	ff.persist(SubscriptionID(id), PersistedFilter{Kind: FilterKindBlocks, LastBlock: lastBlock})
+}
+
+func (ff *Filters) persist(id SubscriptionID, f PersistedFilter) {
+	if ff.store == nil {
+		return
+	}
+	f.LastPoll = time.Now().Unix()
+	if err := ff.store.put(context.Background(), id, f); err != nil {
+		ff.logger.Warn("rpc filters: could not persist filter", "id", id, "err", err)
+	}
+}
+
+// Backlog - returns restored filter which was not polled since restart. Caller is responsible
+// for delivering everything after PersistedFilter.LastBlock and then calling MarkPolled.
+func (ff *Filters) Backlog(id SubscriptionID) (PersistedFilter, bool) {
+	return ff.backlogs.Get(id)
+}
+
+// MarkPolled - remembers that client polled the filter and received everything up to blockNum
+func (ff *Filters) MarkPolled(id SubscriptionID, blockNum uint64) {
+	ff.backlogs.Delete(id)
+	if ff.store == nil {
+		return
+	}
+	if err := ff.store.advance(context.Background(), id, blockNum, time.Now()); err != nil {
+		ff.logger.Warn("rpc filters: could not persist filter position", "id", id, "err", err)
+	}
+}
+
+func (ff *Filters) forget(id SubscriptionID) {
+	ff.backlogs.Delete(id)
+	if ff.store == nil {
+		return
+	}
+	if err := ff.store.delete(context.Background(), id); err != nil {
+		ff.logger.Warn("rpc filters: could not delete persisted filter", "id", id, "err", err)
+	}
+}
@@ -0,0 +1,147 @@
+package rpchelper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
+	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/typesproto"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
+)
+
+func TestFilters_PersistAndRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := log.New()
+	dir := t.TempDir()
+
+	store, err := OpenFilterStore(ctx, dir, logger)
+	require.NoError(t, err)
+	ff := New(ctx, nil, nil, nil, func() {}, logger)
+	require.NoError(t, ff.Restore(ctx, store, time.Hour))
+	require.True(t, ff.Persistent())
+
+	crit := filters.FilterCriteria{Addresses: []libcommon.Address{address1}, Topics: [][]libcommon.Hash{{topic1}}}
+	_, logsID := ff.SubscribeLogs(8, crit)
+	ff.PersistLogsFilter(logsID, crit, 10)
+	_, headsID := ff.SubscribeNewHeads(8)
+	ff.PersistBlockFilter(headsID, 10)
+	_, uninstalledID := ff.SubscribeLogs(8, crit)
+	ff.PersistLogsFilter(uninstalledID, crit, 10)
+	require.True(t, ff.UnsubscribeLogs(uninstalledID))
+	// websocket subscriptions are not persisted
+	ff.SubscribeLogs(8, crit)
+
+	ff.AddLogs(logsID, &types.Log{BlockNumber: 12})
+	logs, ok := ff.ReadLogs(logsID)
+	require.True(t, ok)
+	require.Len(t, logs, 1)
+	store.Close()
+
+	store, err = OpenFilterStore(ctx, dir, logger)
+	require.NoError(t, err)
+	defer store.Close()
+	ff = New(ctx, nil, nil, nil, func() {}, logger)
+	require.NoError(t, ff.Restore(ctx, store, time.Hour))
+
+	persisted, err := store.all(ctx)
+	require.NoError(t, err)
+	require.Len(t, persisted, 2)
+
+	backlog, ok := ff.Backlog(SubscriptionID(logsID))
+	require.True(t, ok)
+	backlog.LastPoll = 0
+	require.Equal(t, PersistedFilter{Kind: FilterKindLogs, Addresses: crit.Addresses, Topics: crit.Topics, LastBlock: 12}, backlog)
+	backlog, ok = ff.Backlog(SubscriptionID(headsID))
+	require.True(t, ok)
+	backlog.LastPoll = 0
+	require.Equal(t, PersistedFilter{Kind: FilterKindBlocks, LastBlock: 10}, backlog)
+	_, ok = ff.Backlog(SubscriptionID(uninstalledID))
+	require.False(t, ok)
+
+	// restored filter receives new logs under the same id
+	ff.OnNewLogs(&remote.SubscribeLogsReply{
+		Address:         address1H160,
+		BlockHash:       gointerfaces.ConvertHashToH256([32]byte{}),
+		BlockNumber:     13,
+		Topics:          []*types2.H256{topic1H256},
+		TransactionHash: gointerfaces.ConvertHashToH256([32]byte{}),
+	})
+	require.Eventually(t, func() bool {
+		logs, ok = ff.ReadLogs(logsID)
+		return ok && len(logs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// backlog is delivered once
+	_, ok = ff.Backlog(SubscriptionID(logsID))
+	require.False(t, ok)
+	ff.MarkPolled(SubscriptionID(headsID), 15)
+	_, ok = ff.Backlog(SubscriptionID(headsID))
+	require.False(t, ok)
+
+	persisted, err = store.all(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), persisted[SubscriptionID(logsID)].LastBlock)
+	require.Equal(t, uint64(15), persisted[SubscriptionID(headsID)].LastBlock)
+}
+
+func TestFilters_Expiry(t *testing.T) {
+	ctx := context.Background()
+	logger := log.New()
+	dir := t.TempDir()
+	expiry := time.Hour
+
+	store, err := OpenFilterStore(ctx, dir, logger)
+	require.NoError(t, err)
+	defer store.Close()
+	stale := PersistedFilter{Kind: FilterKindBlocks, LastBlock: 10, LastPoll: time.Now().Add(-2 * expiry).Unix()}
+	require.NoError(t, store.put(ctx, "stale", stale))
+	ff := New(ctx, nil, nil, nil, func() {}, logger)
+	require.NoError(t, ff.Restore(ctx, store, expiry))
+
+	// filter not polled for longer than expiry is not restored
+	_, ok := ff.Backlog("stale")
+	require.False(t, ok)
+	persisted, err := store.all(ctx)
+	require.NoError(t, err)
+	require.Empty(t, persisted)
+
+	_, headsID := ff.SubscribeNewHeads(8)
+	ff.PersistBlockFilter(headsID, 10)
+	_, polledID := ff.SubscribeNewHeads(8)
+	ff.PersistBlockFilter(polledID, 10)
+
+	// poll without new blocks still counts
+	ff.MarkPolled(SubscriptionID(polledID), 0)
+	persisted, err = store.all(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), persisted[SubscriptionID(polledID)].LastBlock)
+
+	require.NoError(t, ff.expireFilters(ctx, time.Now().Add(expiry/2)))
+	persisted, err = store.all(ctx)
+	require.NoError(t, err)
+	require.Len(t, persisted, 2)
+
+	require.NoError(t, store.advance(ctx, SubscriptionID(polledID), 10, time.Now().Add(expiry)))
+	require.NoError(t, ff.expireFilters(ctx, time.Now().Add(expiry*3/2)))
+	persisted, err = store.all(ctx)
+	require.NoError(t, err)
+	require.Len(t, persisted, 1)
+	require.Contains(t, persisted, SubscriptionID(polledID))
+	require.False(t, ff.UnsubscribeHeads(headsID))
+	require.True(t, ff.UnsubscribeHeads(polledID))
+}
+
+func TestPersistedFilter_BacklogFrom(t *testing.T) {
+	f := PersistedFilter{LastBlock: 10}
+	require.Equal(t, uint64(11), f.BacklogFrom(20))
+	require.Equal(t, uint64(11), f.BacklogFrom(FilterBacklogMaxBlocks+10))
+	require.Equal(t, uint64(12), f.BacklogFrom(FilterBacklogMaxBlocks+11))
+	require.Equal(t, uint64(100_000-FilterBacklogMaxBlocks+1), f.BacklogFrom(100_000))
+}
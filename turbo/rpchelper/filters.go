@@ -44,6 +44,10 @@ type Filters struct {
 	pendingHeadsStores *SyncMap[HeadsSubID, []*types.Header]
 	pendingTxsStores   *SyncMap[PendingTxsSubID, [][]types.Transaction]
 	logger             log.Logger
+
+	store        *FilterStore                              // nil if filters persistence is disabled
+	filterExpiry time.Duration                             // persisted filters not polled for longer are uninstalled, 0 - never
+	backlogs     *SyncMap[SubscriptionID, PersistedFilter] // restored filters which were not polled yet
 }
 
 func New(ctx context.Context, ethBackend ApiBackend, txPool txpool.TxpoolClient, mining txpool.MiningClient, onNewSnapshot func(), logger log.Logger) *Filters {
@@ -60,6 +64,7 @@ func New(ctx context.Context, ethBackend ApiBackend, txPool txpool.TxpoolClient,
 		pendingHeadsStores: NewSyncMap[HeadsSubID, []*types.Header](),
 		pendingTxsStores:   NewSyncMap[PendingTxsSubID, [][]types.Transaction](),
 		logger:             logger,
+		backlogs:           NewSyncMap[SubscriptionID, PersistedFilter](),
 	}
 
 	go func() {
@@ -297,9 +302,13 @@ func (ff *Filters) HandlePendingLogs(reply *txpool.OnPendingLogsReply) {
 
 func (ff *Filters) SubscribeNewHeads(size int) (<-chan *types.Header, HeadsSubID) {
 	id := HeadsSubID(generateSubscriptionID())
+	return ff.subscribeNewHeads(id, size), id
+}
+
+func (ff *Filters) subscribeNewHeads(id HeadsSubID, size int) <-chan *types.Header {
 	sub := newChanSub[*types.Header](size)
 	ff.headsSubs.Put(id, sub)
-	return sub.ch, id
+	return sub.ch
 }
 
 func (ff *Filters) UnsubscribeHeads(id HeadsSubID) bool {
@@ -312,6 +321,7 @@ func (ff *Filters) UnsubscribeHeads(id HeadsSubID) bool {
 		return false
 	}
 	ff.pendingHeadsStores.Delete(id)
+	ff.forget(SubscriptionID(id))
 	return true
 }
 
@@ -368,8 +378,13 @@ func (ff *Filters) UnsubscribePendingTxs(id PendingTxsSubID) bool {
 }
 
 func (ff *Filters) SubscribeLogs(size int, crit filters.FilterCriteria) (<-chan *types.Log, LogsSubID) {
+	id := LogsSubID(generateSubscriptionID())
+	return ff.subscribeLogs(id, size, crit), id
+}
+
+func (ff *Filters) subscribeLogs(id LogsSubID, size int, crit filters.FilterCriteria) <-chan *types.Log {
 	sub := newChanSub[*types.Log](size)
-	id, f := ff.logsSubs.insertLogsFilter(sub)
+	f := ff.logsSubs.insertLogsFilter(id, sub)
 	f.addrs = map[libcommon.Address]int{}
 	if len(crit.Addresses) == 0 {
 		f.allAddrs = 1
@@ -392,14 +407,7 @@ func (ff *Filters) SubscribeLogs(size int, crit filters.FilterCriteria) (<-chan
 	ff.logsSubs.addLogsFilters(f)
 	// if any filter in the aggregate needs all addresses or all topics then the global log subscription needs to
 	// allow all addresses or topics through
-	lfr := ff.logsSubs.createFilterRequest()
-	addresses, topics := ff.logsSubs.getAggMaps()
-	for addr := range addresses {
-		lfr.Addresses = append(lfr.Addresses, gointerfaces.ConvertAddressToH160(addr))
-	}
-	for topic := range topics {
-		lfr.Topics = append(lfr.Topics, gointerfaces.ConvertHashToH256(topic))
-	}
+	lfr := ff.logsFilterRequest()
 
 	loaded := ff.loadLogsRequester()
 	if loaded != nil {
@@ -409,7 +417,20 @@ func (ff *Filters) SubscribeLogs(size int, crit filters.FilterCriteria) (<-chan
 		}
 	}
 
-	return sub.ch, id
+	return sub.ch
+}
+
+// logsFilterRequest - request to the central log subscription which satisfies all filters in the aggregate
+func (ff *Filters) logsFilterRequest() *remote.LogsFilterRequest {
+	lfr := ff.logsSubs.createFilterRequest()
+	addresses, topics := ff.logsSubs.getAggMaps()
+	for addr := range addresses {
+		lfr.Addresses = append(lfr.Addresses, gointerfaces.ConvertAddressToH160(addr))
+	}
+	for topic := range topics {
+		lfr.Topics = append(lfr.Topics, gointerfaces.ConvertHashToH256(topic))
+	}
+	return lfr
 }
 
 func (ff *Filters) loadLogsRequester() any {
@@ -422,16 +443,7 @@ func (ff *Filters) UnsubscribeLogs(id LogsSubID) bool {
 	isDeleted := ff.logsSubs.removeLogsFilter(id)
 	// if any filters in the aggregate need all addresses or all topics then the request to the central
 	// log subscription needs to honour this
-	lfr := ff.logsSubs.createFilterRequest()
-
-	addresses, topics := ff.logsSubs.getAggMaps()
-
-	for addr := range addresses {
-		lfr.Addresses = append(lfr.Addresses, gointerfaces.ConvertAddressToH160(addr))
-	}
-	for topic := range topics {
-		lfr.Topics = append(lfr.Topics, gointerfaces.ConvertHashToH256(topic))
-	}
+	lfr := ff.logsFilterRequest()
 	loaded := ff.loadLogsRequester()
 	if loaded != nil {
 		if err := loaded.(func(*remote.LogsFilterRequest) error)(lfr); err != nil {
@@ -441,6 +453,7 @@ func (ff *Filters) UnsubscribeLogs(id LogsSubID) bool {
 	}
 
 	ff.deleteLogStore(id)
+	ff.forget(SubscriptionID(id))
 
 	return isDeleted
 }
@@ -561,6 +574,9 @@ func (ff *Filters) ReadLogs(id LogsSubID) ([]*types.Log, bool) {
 	if !ok {
 		return res, false
 	}
+	if len(res) > 0 {
+		ff.MarkPolled(SubscriptionID(id), res[len(res)-1].BlockNumber)
+	}
 	return res, true
 }
 
@@ -579,6 +595,9 @@ func (ff *Filters) ReadPendingBlocks(id HeadsSubID) ([]*types.Header, bool) {
 	if !ok {
 		return res, false
 	}
+	if len(res) > 0 {
+		ff.MarkPolled(SubscriptionID(id), res[len(res)-1].Number.Uint64())
+	}
 	return res, true
 }
 
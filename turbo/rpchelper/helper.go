@@ -2,7 +2,6 @@ package rpchelper
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -13,8 +12,6 @@ import (
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
-	borfinality "github.com/ledgerwatch/erigon/polygon/bor/finality"
-	"github.com/ledgerwatch/erigon/polygon/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -54,17 +51,6 @@ func _GetBlockNumber(requireCanonical bool, blockNrOrHash rpc.BlockNumberOrHash,
 		case rpc.EarliestBlockNumber:
 			blockNumber = 0
 		case rpc.FinalizedBlockNumber:
-			if whitelist.GetWhitelistingService() != nil {
-				num := borfinality.GetFinalizedBlockNumber(tx)
-				if num == 0 {
-					// nolint
-					return 0, libcommon.Hash{}, false, errors.New("No finalized block")
-				}
-
-				blockNum := borfinality.CurrentFinalizedBlock(tx, num).NumberU64()
-				blockHash := rawdb.ReadHeaderByNumber(tx, blockNum).Hash()
-				return blockNum, blockHash, false, nil
-			}
 			blockNumber, err = GetFinalizedBlockNumber(tx)
 			if err != nil {
 				return 0, libcommon.Hash{}, false, err
@@ -47,11 +47,10 @@ func NewLogsFilterAggregator() *LogsFilterAggregator {
 	}
 }
 
-func (a *LogsFilterAggregator) insertLogsFilter(sender Sub[*types2.Log]) (LogsSubID, *LogsFilter) {
-	filterId := LogsSubID(generateSubscriptionID())
+func (a *LogsFilterAggregator) insertLogsFilter(filterId LogsSubID, sender Sub[*types2.Log]) *LogsFilter {
 	filter := &LogsFilter{addrs: map[libcommon.Address]int{}, topics: map[libcommon.Hash]int{}, sender: sender}
 	a.logsFilters.Put(filterId, filter)
-	return filterId, filter
+	return filter
 }
 
 func (a *LogsFilterAggregator) removeLogsFilter(filterId LogsSubID) bool {
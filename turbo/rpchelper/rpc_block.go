@@ -1,6 +1,7 @@
 package rpchelper
 
 import (
+	"errors"
 	"fmt"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	borfinality "github.com/ledgerwatch/erigon/polygon/bor/finality"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
@@ -16,6 +18,8 @@ var UnknownBlockError = &rpc.CustomError{
 	Message: "Unknown block",
 }
 
+var ErrNoFinalizedBlock = errors.New("no finalized block")
+
 func GetLatestBlockNumber(tx kv.Tx) (uint64, error) {
 	forkchoiceHeadHash := rawdb.ReadForkchoiceHead(tx)
 	if forkchoiceHeadHash != (libcommon.Hash{}) {
@@ -34,6 +38,10 @@ func GetLatestBlockNumber(tx kv.Tx) (uint64, error) {
 }
 
 func GetFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
+	if borfinality.GetTracker() != nil {
+		return getBorFinalizedBlockNumber(tx)
+	}
+
 	forkchoiceFinalizedHash := rawdb.ReadForkchoiceFinalized(tx)
 	if forkchoiceFinalizedHash != (libcommon.Hash{}) {
 		forkchoiceFinalizedNum := rawdb.ReadHeaderNumber(tx, forkchoiceFinalizedHash)
@@ -46,6 +54,11 @@ func GetFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
 }
 
 func GetSafeBlockNumber(tx kv.Tx) (uint64, error) {
+	if borfinality.GetTracker() != nil {
+		// bor has no separate notion of safe block - milestone is the strongest guarantee available
+		return getBorFinalizedBlockNumber(tx)
+	}
+
 	forkchoiceSafeHash := rawdb.ReadForkchoiceSafe(tx)
 	if forkchoiceSafeHash != (libcommon.Hash{}) {
 		forkchoiceSafeNum := rawdb.ReadHeaderNumber(tx, forkchoiceSafeHash)
@@ -56,6 +69,15 @@ func GetSafeBlockNumber(tx kv.Tx) (uint64, error) {
 	return 0, UnknownBlockError
 }
 
+// getBorFinalizedBlockNumber - on bor chains finality comes from heimdall milestones instead of engine api forkchoice
+func getBorFinalizedBlockNumber(tx kv.Tx) (uint64, error) {
+	blockNum := borfinality.GetFinalizedBlockNumber(tx)
+	if blockNum == 0 {
+		return 0, ErrNoFinalizedBlock
+	}
+	return blockNum, nil
+}
+
 func GetLatestExecutedBlockNumber(tx kv.Tx) (uint64, error) {
 	blockNum, err := stages.GetStageProgress(tx, stages.Execution)
 	if err != nil {
@@ -183,10 +183,7 @@ func (r *beaconSnapshotReader) ReadBlockByRoot(ctx context.Context, tx kv.Tx, ro
 	if r.eth1Getter == nil {
 		return nil, nil
 	}
-	view := r.sn.View()
-	defer view.Close()
-
-	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, root)
+	slot, frozen, err := r.slotByBlockRoot(tx, root)
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +191,9 @@ func (r *beaconSnapshotReader) ReadBlockByRoot(ctx context.Context, tx kv.Tx, ro
 		return nil, nil
 	}
 
+	view := r.sn.View()
+	defer view.Close()
+
 	var buf []byte
 	if *slot > r.sn.BlocksAvailable() {
 		slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, root)
@@ -208,14 +208,16 @@ func (r *beaconSnapshotReader) ReadBlockByRoot(ctx context.Context, tx kv.Tx, ro
 			return nil, err
 		}
 	} else {
-		// Find canonical block
-		canonicalBlockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
-		if err != nil {
-			return nil, err
-		}
-		// root non-canonical? BAD
-		if canonicalBlockRoot != root {
-			return nil, nil
+		if !frozen {
+			// Find canonical block
+			canonicalBlockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
+			if err != nil {
+				return nil, err
+			}
+			// root non-canonical? BAD
+			if canonicalBlockRoot != root {
+				return nil, nil
+			}
 		}
 
 		seg, ok := view.BeaconBlocksSegment(*slot)
@@ -261,10 +263,7 @@ func (r *beaconSnapshotReader) ReadBlockByRoot(ctx context.Context, tx kv.Tx, ro
 }
 
 func (r *beaconSnapshotReader) ReadHeaderByRoot(ctx context.Context, tx kv.Tx, root libcommon.Hash) (*cltypes.SignedBeaconBlockHeader, error) {
-	view := r.sn.View()
-	defer view.Close()
-
-	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, root)
+	slot, frozen, err := r.slotByBlockRoot(tx, root)
 	if err != nil {
 		return nil, err
 	}
@@ -276,17 +275,35 @@ func (r *beaconSnapshotReader) ReadHeaderByRoot(ctx context.Context, tx kv.Tx, r
 		h, _, err := beacon_indicies.ReadSignedHeaderByBlockRoot(ctx, tx, root)
 		return h, err
 	}
-	// Find canonical block
-	canonicalBlockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
-	if err != nil {
-		return nil, err
-	}
-	// root non-canonical? BAD
-	if canonicalBlockRoot != root {
-		return nil, nil
+	if !frozen {
+		// Find canonical block
+		canonicalBlockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
+		if err != nil {
+			return nil, err
+		}
+		// root non-canonical? BAD
+		if canonicalBlockRoot != root {
+			return nil, nil
+		}
 	}
 
 	h, _, _, err := r.sn.ReadHeader(*slot)
 	// Use pooled buffers and readers to avoid allocations.
 	return h, err
 }
+
+// slotByBlockRoot finds the slot of a block in the database or, for blocks retired from it, in the block
+// root index of the frozen segments. frozen is true if the slot came from the segments, the block is
+// canonical then.
+func (r *beaconSnapshotReader) slotByBlockRoot(tx kv.Tx, root libcommon.Hash) (slot *uint64, frozen bool, err error) {
+	slot, err = beacon_indicies.ReadBlockSlotByBlockRoot(tx, root)
+	if err != nil || slot != nil {
+		return slot, false, err
+	}
+
+	frozenSlot, ok, err := r.sn.ReadSlotByBlockRoot(root)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &frozenSlot, true, nil
+}
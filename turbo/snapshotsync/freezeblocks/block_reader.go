@@ -419,7 +419,15 @@ func (r *BlockReader) HeaderByHash(ctx context.Context, tx kv.Getter, hash commo
 	segments := view.Headers()
 
 	buf := make([]byte, 128)
-	for i := len(segments) - 1; i >= 0; i-- {
+	h, covered, err := r.headerFromSnapshotByHashIdx(hash, view, buf)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil {
+		return h, nil
+	}
+	// segments below `covered` don't have this hash, no need to look there
+	for i := len(segments) - 1; i >= 0 && segments[i].to > covered; i-- {
 		if segments[i].Index() == nil {
 			continue
 		}
@@ -802,6 +810,33 @@ func (r *BlockReader) headerFromSnapshotByHash(hash common.Hash, sn *Segment, bu
 	return h, nil
 }
 
+// headerFromSnapshotByHashIdx - getting header by hash from headers-by-hash index.
+// Returns end of the blocks range covered by index: if header not found - it's not in this range.
+func (r *BlockReader) headerFromSnapshotByHashIdx(hash common.Hash, view *View, buf []byte) (*types.Header, uint64, error) {
+	index := r.sn.headersByHash
+	if index == nil {
+		return nil, 0, nil
+	}
+	blockNum, ok := recsplit.NewIndexReader(index).Lookup(hash[:])
+	if !ok || blockNum >= index.BaseDataID() {
+		return nil, 0, nil
+	}
+	sn, ok := view.HeadersSegment(blockNum)
+	if !ok {
+		// segment was removed after index built - fallback to search in all segments
+		return nil, 0, nil
+	}
+	h, _, err := r.headerFromSnapshot(blockNum, sn, buf)
+	if err != nil {
+		return nil, 0, err
+	}
+	// index is based on PerfectHashMap - it returns some block number for unknown hash too
+	if h == nil || h.Hash() != hash {
+		return nil, index.BaseDataID(), nil
+	}
+	return h, index.BaseDataID(), nil
+}
+
 func (r *BlockReader) bodyFromSnapshot(blockHeight uint64, sn *Segment, buf []byte) (*types.Body, uint64, uint32, []byte, error) {
 	b, buf, err := r.bodyForStorageFromSnapshot(blockHeight, sn, buf)
 	if err != nil {
@@ -1612,23 +1647,54 @@ func (r *BlockReader) LastSpanId(_ context.Context, tx kv.Tx) (uint64, bool, err
 }
 
 func (r *BlockReader) LastMilestoneId(ctx context.Context, tx kv.Tx) (uint64, bool, error) {
-	return lastId(ctx, tx, kv.BorMilestones)
+	lastMilestoneId, ok, err := lastId(ctx, tx, kv.BorMilestones)
+
+	snapshotLastMilestoneId := r.LastFrozenMilestoneId()
+
+	if snapshotLastMilestoneId > lastMilestoneId {
+		return snapshotLastMilestoneId, true, nil
+	}
+
+	return lastMilestoneId, ok, err
 }
 
 func (r *BlockReader) Milestone(ctx context.Context, tx kv.Getter, milestoneId uint64) ([]byte, error) {
 	var buf [8]byte
 	binary.BigEndian.PutUint64(buf[:], milestoneId)
-	v, err := tx.GetOne(kv.BorMilestones, buf[:])
 
-	if err != nil {
-		return nil, err
+	if tx != nil {
+		v, err := tx.GetOne(kv.BorMilestones, buf[:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			return common.Copy(v), nil
+		}
 	}
 
-	if v == nil {
-		return nil, fmt.Errorf("milestone %d not found (db)", milestoneId)
+	if r.borSn != nil {
+		view := r.borSn.View()
+		defer view.Close()
+
+		if v, ok := waypointFromSnapshots(view.Milestones(), milestoneId); ok {
+			return v, nil
+		}
 	}
 
-	return common.Copy(v), nil
+	return nil, fmt.Errorf("milestone %d not found (db, snapshots)", milestoneId)
+}
+
+func (r *BlockReader) LastFrozenMilestoneId() uint64 {
+	if r.borSn == nil {
+		return 0
+	}
+
+	view := r.borSn.View()
+	defer view.Close()
+
+	return lastFrozenWaypointId(view.Milestones())
 }
 
 func (r *BlockReader) LastCheckpointId(ctx context.Context, tx kv.Tx) (uint64, bool, error) {
@@ -1646,64 +1712,77 @@ func (r *BlockReader) LastCheckpointId(ctx context.Context, tx kv.Tx) (uint64, b
 func (r *BlockReader) Checkpoint(ctx context.Context, tx kv.Getter, checkpointId uint64) ([]byte, error) {
 	var buf [8]byte
 	binary.BigEndian.PutUint64(buf[:], checkpointId)
-	v, err := tx.GetOne(kv.BorCheckpoints, buf[:])
 
-	if err != nil {
-		return nil, err
+	if tx != nil {
+		v, err := tx.GetOne(kv.BorCheckpoints, buf[:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			return common.Copy(v), nil
+		}
 	}
 
-	if v != nil {
-		return common.Copy(v), nil
+	if r.borSn != nil {
+		view := r.borSn.View()
+		defer view.Close()
+
+		if v, ok := waypointFromSnapshots(view.Checkpoints(), checkpointId); ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("checkpoint %d not found (db, snapshots)", checkpointId)
+}
+
+func (r *BlockReader) LastFrozenCheckpointId() uint64 {
+	if r.borSn == nil {
+		return 0
 	}
 
 	view := r.borSn.View()
 	defer view.Close()
-	segments := view.Checkpoints()
+
+	return lastFrozenWaypointId(view.Checkpoints())
+}
+
+// waypointFromSnapshots - checkpoints and milestones segments are indexed by ordinal of the waypoint in
+// the segment, ids in a segment are consecutive starting from the BaseDataID of the index
+func waypointFromSnapshots(segments []*Segment, id uint64) ([]byte, bool) {
 	for i := len(segments) - 1; i >= 0; i-- {
 		sn := segments[i]
 		index := sn.Index()
 
-		if index == nil || index.KeyCount() == 0 || checkpointId < index.BaseDataID() {
+		if index == nil || index.KeyCount() == 0 || id < index.BaseDataID() || id >= index.BaseDataID()+index.KeyCount() {
 			continue
 		}
 
-		offset := index.OrdinalLookup(checkpointId - index.BaseDataID())
+		offset := index.OrdinalLookup(id - index.BaseDataID())
 		gg := sn.MakeGetter()
 		gg.Reset(offset)
 		result, _ := gg.Next(nil)
-		return common.Copy(result), nil
+		return common.Copy(result), true
 	}
 
-	return nil, fmt.Errorf("checkpoint %d not found (db)", checkpointId)
+	return nil, false
 }
 
-func (r *BlockReader) LastFrozenCheckpointId() uint64 {
-	if r.borSn == nil {
-		return 0
-	}
-
-	view := r.borSn.View()
-	defer view.Close()
-	segments := view.Checkpoints()
-	if len(segments) == 0 {
-		return 0
-	}
-	// find the last segment which has a built index
-	var lastSegment *Segment
+// lastFrozenWaypointId - id of the last waypoint in the last not empty segment which has a built index
+func lastFrozenWaypointId(segments []*Segment) uint64 {
 	for i := len(segments) - 1; i >= 0; i-- {
-		if segments[i].Index() != nil {
-			lastSegment = segments[i]
-			break
+		index := segments[i].Index()
+
+		// ranges without waypoints produce empty segments
+		if index == nil || index.KeyCount() == 0 {
+			continue
 		}
-	}
 
-	if lastSegment == nil {
-		return 0
+		return index.BaseDataID() + index.KeyCount() - 1
 	}
 
-	index := lastSegment.Index()
-
-	return index.BaseDataID() + index.KeyCount() - 1
+	return 0
 }
 
 // ---- Data Integrity part ----
@@ -3,6 +3,8 @@ package freezeblocks
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
@@ -16,6 +18,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/seg"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	borsnaptype "github.com/ledgerwatch/erigon/polygon/bor/snaptype"
+	"github.com/ledgerwatch/erigon/polygon/heimdall"
 	"github.com/ledgerwatch/erigon/turbo/testlog"
 )
 
@@ -187,6 +190,74 @@ func TestBlockReaderLastFrozenEventIdReturnsZeroWhenAllSegmentsDoNotHaveIdx(t *t
 	require.Equal(t, uint64(0), blockReader.LastFrozenEventId())
 }
 
+// not parallel: waypoint snapshot types are switched by global flag
+func TestBlockReaderWaypointsFromSnapshots(t *testing.T) {
+	borsnaptype.RecordWayPoints(true)
+	defer borsnaptype.RecordWayPoints(false)
+
+	logger := testlog.Logger(t, log.LvlInfo)
+	dir := t.TempDir()
+	createTestBorEventSegmentFile(t, 0, 500_000, 132, dir, logger)
+	createTestSegmentFile(t, 0, 500_000, borsnaptype.Enums.BorSpans, dir, 1, logger)
+	createTestWaypointSegmentFile(t, 0, 500_000, borsnaptype.BorCheckpoints, dir, logger, 1, 2, 3)
+	createTestWaypointSegmentFile(t, 0, 500_000, borsnaptype.BorMilestones, dir, logger, 10, 11, 12)
+	borRoSnapshots := NewBorRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dir, 0, logger)
+	defer borRoSnapshots.Close()
+	require.NoError(t, borRoSnapshots.ReopenFolder())
+
+	blockReader := &BlockReader{borSn: borRoSnapshots}
+	ctx := context.Background()
+
+	require.Equal(t, uint64(3), blockReader.LastFrozenCheckpointId())
+	lastCheckpointId, ok, err := blockReader.LastCheckpointId(ctx, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), lastCheckpointId)
+
+	data, err := blockReader.Checkpoint(ctx, nil, 2)
+	require.NoError(t, err)
+	var checkpoint heimdall.Checkpoint
+	require.NoError(t, json.Unmarshal(data, &checkpoint))
+	require.Equal(t, heimdall.CheckpointId(2), checkpoint.Id)
+	_, err = blockReader.Checkpoint(ctx, nil, 4)
+	require.Error(t, err)
+
+	require.Equal(t, uint64(12), blockReader.LastFrozenMilestoneId())
+	lastMilestoneId, ok, err := blockReader.LastMilestoneId(ctx, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(12), lastMilestoneId)
+
+	data, err = blockReader.Milestone(ctx, nil, 10)
+	require.NoError(t, err)
+	var milestone heimdall.Milestone
+	require.NoError(t, json.Unmarshal(data, &milestone))
+	require.Equal(t, heimdall.MilestoneId(10), milestone.Id)
+	_, err = blockReader.Milestone(ctx, nil, 9)
+	require.Error(t, err)
+}
+
+func createTestWaypointSegmentFile(t *testing.T, from, to uint64, snapType snaptype.Type, dir string, logger log.Logger, ids ...uint64) {
+	info := snapType.FileInfo(dir, from, to)
+	compressor, err := seg.NewCompressor(context.Background(), "test", info.Path, dir, 100, 1, log.LvlDebug, logger)
+	require.NoError(t, err)
+	defer compressor.Close()
+	compressor.DisableFsync()
+	for _, id := range ids {
+		fields := heimdall.WaypointFields{StartBlock: big.NewInt(int64(id * 16)), EndBlock: big.NewInt(int64(id*16 + 15))}
+		var data []byte
+		if snapType.Enum() == borsnaptype.Enums.BorCheckpoints {
+			data, err = json.Marshal(&heimdall.Checkpoint{Id: heimdall.CheckpointId(id), Fields: fields})
+		} else {
+			data, err = json.Marshal(&heimdall.Milestone{Id: heimdall.MilestoneId(id), Fields: fields})
+		}
+		require.NoError(t, err)
+		require.NoError(t, compressor.AddWord(data))
+	}
+	require.NoError(t, compressor.Compress())
+	require.NoError(t, snapType.BuildIndexes(context.Background(), info, nil, dir, nil, log.LvlDebug, logger))
+}
+
 func createTestBorEventSegmentFile(t *testing.T, from, to, eventId uint64, dir string, logger log.Logger) {
 	compressor, err := seg.NewCompressor(
 		context.Background(),
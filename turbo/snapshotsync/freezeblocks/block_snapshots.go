@@ -271,6 +271,8 @@ type RoSnapshots struct {
 
 	// allows for pruning segments - this is the min availible segment
 	segmentsMin atomic.Uint64
+
+	headersByHash *recsplit.Index // guarded by lock of segments, see HeadersByHashFileName
 }
 
 // NewRoSnapshots - opens all snapshots. But to simplify everything:
@@ -574,6 +576,15 @@ func (s *RoSnapshots) rebuildSegments(fileNames []string, open bool, optimistic
 		segmentsMaxSet = true
 	}
 
+	if open {
+		if err := s.reopenHeadersByHashIdx(); err != nil {
+			if !optimistic {
+				return err
+			}
+			s.logger.Warn("[snapshots] open headers-by-hash index", "err", err)
+		}
+	}
+
 	if segmentsMaxSet {
 		s.segmentsMax.Store(segmentsMax)
 	}
@@ -633,6 +644,10 @@ func (s *RoSnapshots) Close() {
 	s.lockSegments()
 	defer s.unlockSegments()
 	s.closeWhatNotInList(nil)
+	if s.headersByHash != nil {
+		s.headersByHash.Close()
+		s.headersByHash = nil
+	}
 }
 
 func (s *RoSnapshots) closeWhatNotInList(l []string) {
@@ -709,7 +724,7 @@ func (s *RoSnapshots) buildMissedIndicesIfNeed(ctx context.Context, logPrefix st
 	s.LogStat("missed-idx")
 
 	// wait for Downloader service to download all expected snapshots
-	indexWorkers := estimate.IndexSnapshot.Workers()
+	indexWorkers := s.Cfg().IndexBuildWorkers()
 	if err := s.buildMissedIndices(logPrefix, ctx, dirs, cc, indexWorkers, logger); err != nil {
 		return fmt.Errorf("can't build missed indices: %w", err)
 	}
@@ -773,8 +788,36 @@ func (s *RoSnapshots) buildMissedIndices(logPrefix string, ctx context.Context,
 	dir, tmpDir := dirs.Snap, dirs.Tmp
 	//log.Log(lvl, "[snapshots] Build indices", "from", min)
 
+	type missedIndex struct {
+		segtype snaptype.Enum
+		info    snaptype.FileInfo
+	}
+	var missed []missedIndex
+	s.segments.Scan(func(segtype snaptype.Enum, value *segments) bool {
+		for _, segment := range value.segments {
+			info := segment.FileInfo(dir)
+
+			if segtype.HasIndexFiles(info, logger) {
+				continue
+			}
+
+			segment.closeIdx()
+			missed = append(missed, missedIndex{segtype: segtype, info: info})
+		}
+
+		return true
+	})
+	if len(missed) == 0 {
+		return nil
+	}
+
 	ps := background.NewProgressSet()
 	startIndexingTime := time.Now()
+	var indexedFiles atomic.Int32
+	progress := func(filesIndexed int32) diagnostics.SnapshotIndexingProgress {
+		return diagnostics.SnapshotIndexingProgress{Workers: workers, FilesTotal: len(missed), FilesIndexed: int(filesIndexed)}
+	}
+	logger.Info(fmt.Sprintf("[%s] Indexing", logPrefix), "files", len(missed), "workers", workers)
 
 	logEvery := time.NewTicker(20 * time.Second)
 	defer logEvery.Stop()
@@ -789,8 +832,8 @@ func (s *RoSnapshots) buildMissedIndices(logPrefix string, ctx context.Context,
 			case <-logEvery.C:
 				var m runtime.MemStats
 				dbg.ReadMemStats(&m)
-				sendDiagnostics(startIndexingTime, ps.DiagnossticsData(), m.Alloc, m.Sys)
-				logger.Info(fmt.Sprintf("[%s] Indexing", logPrefix), "progress", ps.String(), "total-indexing-time", time.Since(startIndexingTime).Round(time.Second).String(), "alloc", common2.ByteCount(m.Alloc), "sys", common2.ByteCount(m.Sys))
+				sendDiagnostics(startIndexingTime, ps.DiagnossticsData(), progress(indexedFiles.Load()), m.Alloc, m.Sys)
+				logger.Info(fmt.Sprintf("[%s] Indexing", logPrefix), "progress", ps.String(), "files", fmt.Sprintf("%d/%d", indexedFiles.Load(), len(missed)), "total-indexing-time", time.Since(startIndexingTime).Round(time.Second).String(), "alloc", common2.ByteCount(m.Alloc), "sys", common2.ByteCount(m.Sys))
 			case <-finish:
 				return
 			case <-ctx.Done():
@@ -799,30 +842,23 @@ func (s *RoSnapshots) buildMissedIndices(logPrefix string, ctx context.Context,
 		}
 	}()
 
-	s.segments.Scan(func(segtype snaptype.Enum, value *segments) bool {
-		for _, segment := range value.segments {
-			info := segment.FileInfo(dir)
-
-			if segtype.HasIndexFiles(info, logger) {
-				continue
+	for _, m := range missed {
+		segtype, info := m.segtype, m.info
+		g.Go(func() error {
+			p := &background.Progress{}
+			ps.Add(p)
+			defer ps.Delete(p)
+			startFileIndexing := time.Now()
+			if err := segtype.BuildIndexes(gCtx, info, chainConfig, tmpDir, p, log.LvlInfo, logger); err != nil {
+				return fmt.Errorf("%s: %w", info.Name(), err)
 			}
-
-			segment.closeIdx()
-
-			g.Go(func() error {
-				p := &background.Progress{}
-				ps.Add(p)
-				defer notifySegmentIndexingFinished(info.Name())
-				defer ps.Delete(p)
-				if err := segtype.BuildIndexes(gCtx, info, chainConfig, tmpDir, p, log.LvlInfo, logger); err != nil {
-					return fmt.Errorf("%s: %w", info.Name(), err)
-				}
-				return nil
-			})
-		}
-
-		return true
-	})
+			filesIndexed := indexedFiles.Add(1)
+			took := time.Since(startFileIndexing)
+			notifySegmentIndexingFinished(info.Name(), took, progress(filesIndexed))
+			logger.Info(fmt.Sprintf("[%s] Indexed", logPrefix), "file", info.Name(), "took", took.Round(time.Millisecond), "files", fmt.Sprintf("%d/%d", filesIndexed, len(missed)))
+			return nil
+		})
+	}
 
 	go func() {
 		defer close(finish)
@@ -924,15 +960,17 @@ func buildIdx(ctx context.Context, sn snaptype.FileInfo, chainConfig *chain.Conf
 	return nil
 }
 
-func notifySegmentIndexingFinished(name string) {
+func notifySegmentIndexingFinished(name string, took time.Duration, progress diagnostics.SnapshotIndexingProgress) {
 	diagnostics.Send(
 		diagnostics.SnapshotSegmentIndexingFinishedUpdate{
 			SegmentName: name,
+			TimeElapsed: took.Round(time.Millisecond).Seconds(),
+			Progress:    progress,
 		},
 	)
 }
 
-func sendDiagnostics(startIndexingTime time.Time, indexPercent map[string]int, alloc uint64, sys uint64) {
+func sendDiagnostics(startIndexingTime time.Time, indexPercent map[string]int, progress diagnostics.SnapshotIndexingProgress, alloc uint64, sys uint64) {
 	segmentsStats := make([]diagnostics.SnapshotSegmentIndexingStatistics, 0, len(indexPercent))
 	for k, v := range indexPercent {
 		segmentsStats = append(segmentsStats, diagnostics.SnapshotSegmentIndexingStatistics{
@@ -945,6 +983,7 @@ func sendDiagnostics(startIndexingTime time.Time, indexPercent map[string]int, a
 	diagnostics.Send(diagnostics.SnapshotIndexingStatistics{
 		Segments:    segmentsStats,
 		TimeElapsed: time.Since(startIndexingTime).Round(time.Second).Seconds(),
+		Progress:    progress,
 	})
 }
 
@@ -1337,7 +1376,7 @@ func (br *BlockRetire) retireBlocks(ctx context.Context, minBlockNum uint64, max
 	merger := NewMerger(tmpDir, workers, lvl, db, br.chainConfig, logger)
 	rangesToMerge := merger.FindMergeRanges(snapshots.Ranges(), snapshots.BlocksAvailable())
 	if len(rangesToMerge) == 0 {
-		return ok, nil
+		return ok, br.buildHeadersByHashIdx(ctx)
 	}
 	ok = true // have something to merge
 	onMerge := func(r Range) error {
@@ -1360,7 +1399,15 @@ func (br *BlockRetire) retireBlocks(ctx context.Context, minBlockNum uint64, max
 		return ok, err
 	}
 
-	return ok, nil
+	return ok, br.buildHeadersByHashIdx(ctx)
+}
+
+// buildHeadersByHashIdx - noop until new headers segment of max size appears
+func (br *BlockRetire) buildHeadersByHashIdx(ctx context.Context) error {
+	if _, err := BuildHeadersByHashIdx(ctx, br.snapshots(), br.chainConfig.ChainName, br.tmpDir, br.logger); err != nil {
+		return fmt.Errorf("BuildHeadersByHashIdx: %w", err)
+	}
+	return nil
 }
 
 func (br *BlockRetire) PruneAncientBlocks(tx kv.RwTx, limit int) error {
@@ -2,7 +2,9 @@ package freezeblocks
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 	"testing/fstest"
 
@@ -12,6 +14,8 @@ import (
 
 	"github.com/ledgerwatch/erigon-lib/chain/networkname"
 	"github.com/ledgerwatch/erigon-lib/chain/snapcfg"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/diagnostics"
 	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
 	"github.com/ledgerwatch/erigon-lib/recsplit"
 	"github.com/ledgerwatch/erigon-lib/seg"
@@ -398,6 +402,45 @@ func TestOpenAllSnapshot(t *testing.T) {
 	}
 }
 
+func TestBuildMissedIndices(t *testing.T) {
+	logger := log.New()
+	dir, require := t.TempDir(), require.New(t)
+	dirs := datadir.Dirs{Snap: dir, Tmp: t.TempDir()}
+
+	ranges := [][2]uint64{{0, 500_000}, {500_000, 1_000_000}, {1_000_000, 1_500_000}}
+	for _, r := range ranges {
+		createTestSegmentFile(t, r[0], r[1], coresnaptype.Enums.Headers, dir, 1, logger)
+		require.NoError(os.Remove(filepath.Join(dir, snaptype.IdxFileName(1, r[0], r[1], coresnaptype.Enums.Headers.String()))))
+	}
+	s := NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: true, IndexWorkers: 2}, dir, 0, logger)
+	defer s.Close()
+	require.NoError(s.ReopenFolder())
+
+	ctx, finished, cancel := diagnostics.Context[diagnostics.SnapshotSegmentIndexingFinishedUpdate](context.Background(), len(ranges))
+	defer cancel()
+	diagnostics.StartProviders(ctx, diagnostics.TypeOf(diagnostics.SnapshotSegmentIndexingFinishedUpdate{}), logger)
+
+	require.NoError(s.buildMissedIndices("test", ctx, dirs, params.MainnetChainConfig, s.Cfg().IndexBuildWorkers(), logger))
+	for _, r := range ranges {
+		require.FileExists(filepath.Join(dir, snaptype.IdxFileName(1, r[0], r[1], coresnaptype.Enums.Headers.String())))
+	}
+
+	// every indexed file is reported with overall progress
+	var indexed []int
+	for range ranges {
+		upd := <-finished
+		require.Equal(2, upd.Progress.Workers)
+		require.Equal(len(ranges), upd.Progress.FilesTotal)
+		indexed = append(indexed, upd.Progress.FilesIndexed)
+	}
+	sort.Ints(indexed)
+	require.Equal([]int{1, 2, 3}, indexed)
+
+	// nothing is missed anymore
+	require.NoError(s.buildMissedIndices("test", ctx, dirs, params.MainnetChainConfig, 2, logger))
+	require.Empty(finished)
+}
+
 func TestParseCompressedFileName(t *testing.T) {
 	require := require.New(t)
 	fs := fstest.MapFS{
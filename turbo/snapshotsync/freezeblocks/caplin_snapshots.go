@@ -55,8 +55,109 @@ func BeaconSimpleIdx(ctx context.Context, sn snaptype.FileInfo, salt uint32, tmp
 	return nil
 }
 
+// BeaconBlockRootIdx builds the block root => slot index of a beacon blocks segment. Empty words of slots
+// without block are skipped. The index is built with an existence filter: roots are sent by peers, and a
+// false positive of the perfect hash costs reading and hashing a block header from the segment.
+func BeaconBlockRootIdx(ctx context.Context, sn snaptype.FileInfo, salt uint32, beaconCfg *clparams.BeaconChainConfig, tmpDir string, p *background.Progress, lvl log.Lvl, logger log.Logger) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("BeaconBlockRootIdx: at=%s, %v, %s", sn.Name(), rec, dbg.Stack())
+		}
+	}()
+
+	d, err := seg.NewDecompressor(sn.Path)
+	if err != nil {
+		return fmt.Errorf("can't open %s for indexing: %w", sn.Name(), err)
+	}
+	defer d.Close()
+
+	var keyCount int
+	g := d.MakeGetter()
+	for g.HasNext() {
+		if _, l := g.Skip(); l > 0 {
+			keyCount++
+		}
+	}
+
+	if p != nil {
+		name := sn.Name()
+		p.Name.Store(&name)
+		p.Total.Store(uint64(keyCount))
+	}
+
+	rs, err := recsplit.NewRecSplit(recsplit.RecSplitArgs{
+		KeyCount:           keyCount,
+		Enums:              true,
+		LessFalsePositives: true,
+		BucketSize:         2000,
+		LeafSize:           8,
+		TmpDir:             tmpDir,
+		IndexFile:          filepath.Join(sn.Dir(), sn.Type.IdxFileName(sn.Version, sn.From, sn.To, snaptype.CaplinIndexes.BeaconBlockRoot)),
+		BaseDataID:         sn.From,
+		Salt:               &salt,
+	}, logger)
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+	rs.LogLvl(log.LvlDebug)
+
+	defer d.EnableReadAhead().DisableReadAhead()
+
+	reader := decompressorPool.Get().(*zstd.Decoder)
+	defer decompressorPool.Put(reader)
+
+	buf := make([]byte, 0, 4096)
+	for {
+		g := d.MakeGetter()
+		for slot := sn.From; g.HasNext(); slot++ {
+			buf, _ = g.Next(buf[:0])
+			if len(buf) == 0 {
+				continue
+			}
+			if err := reader.Reset(bytes.NewReader(buf)); err != nil {
+				return err
+			}
+			header, _, _, err := snapshot_format.ReadBlockHeaderFromSnapshotWithExecutionData(reader, beaconCfg)
+			if err != nil {
+				return fmt.Errorf("slot %d: %w", slot, err)
+			}
+			root, err := header.Header.HashSSZ()
+			if err != nil {
+				return err
+			}
+			if err := rs.AddKey(root[:], slot); err != nil {
+				return err
+			}
+			if p != nil {
+				p.Processed.Add(1)
+			}
+			if slot%20_000 == 0 {
+				logger.Log(lvl, "Generating idx for beacon block roots", "progress", slot)
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+
+		if err = rs.Build(ctx); err != nil {
+			if errors.Is(err, recsplit.ErrCollision) {
+				logger.Info("Building recsplit. Collision happened. It's ok. Restarting with another salt...", "err", err)
+				rs.ResetNextSalt()
+				continue
+			}
+			return err
+		}
+		return nil
+	}
+}
+
 // value: chunked(ssz(SignedBeaconBlocks))
 // slot       -> beacon_slot_segment_offset
+// block root -> slot
 
 type CaplinSnapshots struct {
 	indicesReady  atomic.Bool
@@ -364,7 +465,7 @@ func (v *CaplinView) BlobSidecarsSegment(slot uint64) (*Segment, bool) {
 	return nil, false
 }
 
-func dumpBeaconBlocksRange(ctx context.Context, db kv.RoDB, fromSlot uint64, toSlot uint64, salt uint32, dirs datadir.Dirs, workers int, lvl log.Lvl, logger log.Logger) error {
+func dumpBeaconBlocksRange(ctx context.Context, db kv.RoDB, beaconCfg *clparams.BeaconChainConfig, fromSlot uint64, toSlot uint64, salt uint32, dirs datadir.Dirs, workers int, lvl log.Lvl, logger log.Logger) error {
 	tmpDir, snapDir := dirs.Tmp, dirs.Snap
 
 	segName := snaptype.BeaconBlocks.FileName(0, fromSlot, toSlot)
@@ -407,7 +508,11 @@ func dumpBeaconBlocksRange(ctx context.Context, db kv.RoDB, fromSlot uint64, toS
 	// Generate .idx file, which is the slot => offset mapping.
 	p := &background.Progress{}
 
-	return BeaconSimpleIdx(ctx, f, salt, tmpDir, p, lvl, logger)
+	if err := BeaconSimpleIdx(ctx, f, salt, tmpDir, p, lvl, logger); err != nil {
+		return err
+	}
+	// Generate block root => slot .idx file
+	return BeaconBlockRootIdx(ctx, f, salt, beaconCfg, tmpDir, p, lvl, logger)
 }
 
 func dumpBlobSidecarsRange(ctx context.Context, db kv.RoDB, storage blob_storage.BlobStorage, fromSlot uint64, toSlot uint64, salt uint32, dirs datadir.Dirs, workers int, lvl log.Lvl, logger log.Logger) error {
@@ -479,7 +584,7 @@ func dumpBlobSidecarsRange(ctx context.Context, db kv.RoDB, storage blob_storage
 	return BeaconSimpleIdx(ctx, f, salt, tmpDir, p, lvl, logger)
 }
 
-func DumpBeaconBlocks(ctx context.Context, db kv.RoDB, fromSlot, toSlot uint64, salt uint32, dirs datadir.Dirs, workers int, lvl log.Lvl, logger log.Logger) error {
+func DumpBeaconBlocks(ctx context.Context, db kv.RoDB, beaconCfg *clparams.BeaconChainConfig, fromSlot, toSlot uint64, salt uint32, dirs datadir.Dirs, workers int, lvl log.Lvl, logger log.Logger) error {
 
 	for i := fromSlot; i < toSlot; i = chooseSegmentEnd(i, toSlot, snaptype.CaplinEnums.BeaconBlocks, nil) {
 		blocksPerFile := snapcfg.MergeLimit("", snaptype.CaplinEnums.BeaconBlocks, i)
@@ -489,7 +594,7 @@ func DumpBeaconBlocks(ctx context.Context, db kv.RoDB, fromSlot, toSlot uint64,
 		}
 		to := chooseSegmentEnd(i, toSlot, snaptype.CaplinEnums.BeaconBlocks, nil)
 		logger.Log(lvl, "Dumping beacon blocks", "from", i, "to", to)
-		if err := dumpBeaconBlocksRange(ctx, db, i, to, salt, dirs, workers, lvl, logger); err != nil {
+		if err := dumpBeaconBlocksRange(ctx, db, beaconCfg, i, to, salt, dirs, workers, lvl, logger); err != nil {
 			return err
 		}
 	}
@@ -539,6 +644,12 @@ func (s *CaplinSnapshots) BuildMissingIndices(ctx context.Context, logger log.Lo
 		if err := BeaconSimpleIdx(ctx, segment, s.Salt, s.tmpdir, p, log.LvlDebug, logger); err != nil {
 			return err
 		}
+		if segment.Type.Enum() != snaptype.CaplinEnums.BeaconBlocks {
+			continue
+		}
+		if err := BeaconBlockRootIdx(ctx, segment, s.Salt, s.beaconCfg, s.tmpdir, p, log.LvlDebug, logger); err != nil {
+			return err
+		}
 	}
 
 	return s.ReopenFolder()
@@ -554,13 +665,17 @@ func (s *CaplinSnapshots) ReadHeader(slot uint64) (*cltypes.SignedBeaconBlockHea
 	view := s.View()
 	defer view.Close()
 
-	var buf []byte
-
 	seg, ok := view.BeaconBlocksSegment(slot)
 	if !ok {
 		return nil, 0, libcommon.Hash{}, nil
 	}
 
+	return s.readHeader(seg, slot)
+}
+
+func (s *CaplinSnapshots) readHeader(seg *Segment, slot uint64) (*cltypes.SignedBeaconBlockHeader, uint64, libcommon.Hash, error) {
+	var buf []byte
+
 	idxSlot := seg.Index()
 
 	if idxSlot == nil {
@@ -592,6 +707,48 @@ func (s *CaplinSnapshots) ReadHeader(slot uint64) (*cltypes.SignedBeaconBlockHea
 	return snapshot_format.ReadBlockHeaderFromSnapshotWithExecutionData(reader, s.beaconCfg)
 }
 
+// ReadSlotByBlockRoot finds the slot of a frozen block by its root, it needs no database and works for
+// blocks retired from it. Only canonical blocks are frozen.
+// Every segment is looked up, the existence filter of the root index keeps the expected number of headers
+// read for an unknown root at segments/256.
+func (s *CaplinSnapshots) ReadSlotByBlockRoot(root libcommon.Hash) (uint64, bool, error) {
+	view := s.View()
+	defer view.Close()
+
+	segments := view.BeaconBlocks()
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		idxRoot := seg.Index(snaptype.CaplinIndexes.BeaconBlockRoot)
+		if idxRoot == nil || idxRoot.KeyCount() == 0 {
+			continue
+		}
+		n, ok := recsplit.NewIndexReader(idxRoot).Lookup(root[:])
+		if !ok || n >= idxRoot.KeyCount() {
+			continue
+		}
+		slot := idxRoot.OrdinalLookup(n)
+		if slot < seg.from || slot >= seg.to {
+			continue
+		}
+		// the existence filter still lets 1/256 of unknown roots through, check the header
+		header, _, _, err := s.readHeader(seg, slot)
+		if err != nil {
+			return 0, false, err
+		}
+		if header == nil {
+			continue
+		}
+		headerRoot, err := header.Header.HashSSZ()
+		if err != nil {
+			return 0, false, err
+		}
+		if headerRoot == root {
+			return slot, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
 func (s *CaplinSnapshots) ReadBlobSidecars(slot uint64) ([]*cltypes.BlobSidecar, error) {
 	view := s.View()
 	defer view.Close()
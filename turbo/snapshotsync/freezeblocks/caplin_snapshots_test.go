@@ -0,0 +1,60 @@
+package freezeblocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/persistence/beacon_indicies"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+)
+
+func TestCaplinReadSlotByBlockRoot(t *testing.T) {
+	ctx := context.Background()
+	logger := log.New()
+	dirs := datadir.New(t.TempDir())
+	beaconCfg := &clparams.MainnetBeaconConfig
+	db := memdb.NewTestDB(t)
+
+	// blocks at some slots of the segment, others stay empty
+	roots := map[uint64]libcommon.Hash{}
+	tx, err := db.BeginRw(ctx)
+	require.NoError(t, err)
+	for _, slot := range []uint64{1, 2, 5, 64, 999} {
+		block := cltypes.NewSignedBeaconBlock(beaconCfg)
+		block.Block.Slot = slot
+		block.Block.ProposerIndex = slot * 7
+		require.NoError(t, beacon_indicies.WriteBeaconBlockAndIndicies(ctx, tx, block, true))
+		roots[slot], err = block.Block.HashSSZ()
+		require.NoError(t, err)
+	}
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, dumpBeaconBlocksRange(ctx, db, beaconCfg, 0, 1000, 0, dirs, 1, log.LvlDebug, logger))
+
+	sn := NewCaplinSnapshots(ethconfig.BlocksFreezing{Enabled: true}, beaconCfg, dirs, logger)
+	defer sn.Close()
+	require.NoError(t, sn.ReopenFolder())
+
+	for slot, root := range roots {
+		found, ok, err := sn.ReadSlotByBlockRoot(root)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, slot, found)
+	}
+
+	// unknown roots are not found, whatever the perfect hash returns for them
+	for i := 0; i < 1000; i++ {
+		root := libcommon.Hash{byte(i), byte(i >> 8), 0xff}
+		_, ok, err := sn.ReadSlotByBlockRoot(root)
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+}
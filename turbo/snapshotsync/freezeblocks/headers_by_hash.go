@@ -0,0 +1,186 @@
+package freezeblocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain/snapcfg"
+	"github.com/ledgerwatch/erigon-lib/common"
+	dir2 "github.com/ledgerwatch/erigon-lib/common/dir"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/recsplit"
+	"github.com/ledgerwatch/erigon-lib/seg"
+	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/crypto/cryptopool"
+)
+
+// HeadersByHashFileName - global index of frozen headers: header_hash -> block_number
+//
+// Per-segment indices of headers can't tell if hash belongs to segment, so HeaderByHash had to try every segment.
+// This index finds block number by one lookup, then header is read from its segment by number.
+// It covers only segments of max size (they are not merged anymore) and stores block numbers, not offsets -
+// so merges don't invalidate it and it's rebuilt only when new full segment appears.
+// BaseDataID of the index is the end of covered range [0, to).
+const HeadersByHashFileName = "headers-by-hash.idx"
+
+// fullHeadersTo - end of the range [0, to) covered by headers segments of max size
+func fullHeadersTo(chainName string, headers []*Segment) uint64 {
+	var to uint64
+	for _, sn := range headers {
+		if sn.Decompressor == nil || sn.from != to {
+			break
+		}
+		if sn.to-sn.from < snapcfg.MergeLimit(chainName, snaptype.Unknown, sn.from) {
+			break
+		}
+		to = sn.to
+	}
+	return to
+}
+
+// reopenHeadersByHashIdx - must be called under lock of segments
+func (s *RoSnapshots) reopenHeadersByHashIdx() error {
+	if s.headersByHash != nil {
+		s.headersByHash.Close()
+		s.headersByHash = nil
+	}
+	if !s.HasType(coresnaptype.Headers) {
+		return nil
+	}
+	fPath := filepath.Join(s.dir, HeadersByHashFileName)
+	if !dir2.FileExist(fPath) {
+		return nil
+	}
+	idx, err := recsplit.OpenIndex(fPath)
+	if err != nil {
+		return fmt.Errorf("%w, %s", err, HeadersByHashFileName)
+	}
+	s.headersByHash = idx
+	return nil
+}
+
+// HeadersByHashTo - end of the blocks range covered by headers-by-hash index, 0 if it's not available
+func (s *RoSnapshots) HeadersByHashTo() uint64 {
+	view := s.View()
+	defer view.Close()
+	if s.headersByHash == nil {
+		return 0
+	}
+	return s.headersByHash.BaseDataID()
+}
+
+// BuildHeadersByHashIdx - (re)builds headers-by-hash index if new headers segments of max size appeared since last build
+func BuildHeadersByHashIdx(ctx context.Context, snapshots *RoSnapshots, chainName string, tmpDir string, logger log.Logger) (built bool, err error) {
+	view := snapshots.View()
+	to := fullHeadersTo(chainName, view.Headers())
+	var files []string
+	var keyCount int
+	for _, sn := range view.Headers() {
+		if sn.to > to {
+			break
+		}
+		files = append(files, sn.FilePath())
+		keyCount += sn.Count()
+	}
+	var covered uint64
+	if snapshots.headersByHash != nil {
+		covered = snapshots.headersByHash.BaseDataID()
+	}
+	view.Close()
+
+	if to == 0 || to <= covered {
+		return false, nil
+	}
+
+	started := time.Now()
+	logger.Info("[snapshots] Building headers-by-hash index", "blocks", fmt.Sprintf("0-%dk", to/1000), "keys", keyCount)
+
+	salt, err := snaptype.GetIndexSalt(snapshots.Dir())
+	if err != nil {
+		return false, err
+	}
+	rs, err := recsplit.NewRecSplit(recsplit.RecSplitArgs{
+		KeyCount:   keyCount,
+		BucketSize: 2000,
+		LeafSize:   8,
+		TmpDir:     tmpDir,
+		IndexFile:  filepath.Join(snapshots.Dir(), HeadersByHashFileName),
+		BaseDataID: to,
+		Salt:       &salt,
+	}, logger)
+	if err != nil {
+		return false, err
+	}
+	defer rs.Close()
+	rs.LogLvl(log.LvlDebug)
+
+	for {
+		for _, fPath := range files {
+			if err := addHeaderHashes(ctx, rs, fPath); err != nil {
+				return false, err
+			}
+		}
+		if err = rs.Build(ctx); err != nil {
+			if errors.Is(err, recsplit.ErrCollision) {
+				logger.Info("Building recsplit. Collision happened. It's ok. Restarting with another salt...", "err", err)
+				rs.ResetNextSalt()
+				continue
+			}
+			return false, err
+		}
+		break
+	}
+
+	snapshots.lockSegments()
+	defer snapshots.unlockSegments()
+	if err := snapshots.reopenHeadersByHashIdx(); err != nil {
+		return false, err
+	}
+	logger.Info("[snapshots] Built headers-by-hash index", "blocks", fmt.Sprintf("0-%dk", to/1000), "took", time.Since(started))
+	return true, nil
+}
+
+func addHeaderHashes(ctx context.Context, rs *recsplit.RecSplit, fPath string) error {
+	info, _, ok := snaptype.ParseFileName("", filepath.Base(fPath))
+	if !ok {
+		return fmt.Errorf("can't parse file name: %s", fPath)
+	}
+	d, err := seg.NewDecompressor(fPath)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	defer d.EnableReadAhead().DisableReadAhead()
+
+	hasher := crypto.NewKeccakState()
+	defer cryptopool.ReturnToPoolKeccak256(hasher)
+	var h common.Hash
+
+	g := d.MakeGetter()
+	word := make([]byte, 0, 4096)
+	for blockNum := info.From; g.HasNext(); blockNum++ {
+		word, _ = g.Next(word[:0])
+		if len(word) == 0 {
+			return fmt.Errorf("empty header %d in %s", blockNum, info.Name())
+		}
+		hasher.Reset()
+		hasher.Write(word[1:])
+		hasher.Read(h[:])
+		if err := rs.AddKey(h[:], blockNum); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+	return nil
+}
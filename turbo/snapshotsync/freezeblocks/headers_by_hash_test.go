@@ -0,0 +1,125 @@
+package freezeblocks
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/seg"
+
+	coresnaptype "github.com/ledgerwatch/erigon/core/snaptype"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+func createTestHeadersSegment(t *testing.T, dir string, from, to uint64, count int, logger log.Logger) []common.Hash {
+	t.Helper()
+	info := coresnaptype.Headers.FileInfo(dir, from, to)
+	c, err := seg.NewCompressor(context.Background(), "test", info.Path, dir, 100, 1, log.LvlDebug, logger)
+	require.NoError(t, err)
+	defer c.Close()
+	c.DisableFsync()
+
+	hashes := make([]common.Hash, 0, count)
+	for i := 0; i < count; i++ {
+		h := &types.Header{Number: new(big.Int).SetUint64(from + uint64(i)), Difficulty: big.NewInt(1), Extra: []byte("headers-by-hash")}
+		headerRlp, err := rlp.EncodeToBytes(h)
+		require.NoError(t, err)
+		hash := h.Hash()
+		require.NoError(t, c.AddWord(append([]byte{hash[0]}, headerRlp...)))
+		hashes = append(hashes, hash)
+	}
+	require.NoError(t, c.Compress())
+	require.NoError(t, coresnaptype.Headers.BuildIndexes(context.Background(), info, nil, dir, nil, log.LvlDebug, logger))
+	return hashes
+}
+
+func TestHeadersByHashIdx(t *testing.T) {
+	logger := log.New()
+	dir, ctx := t.TempDir(), context.Background()
+
+	// full segments are [0, 100k) and [100k, 200k), the tail one is not covered by index
+	var hashes []common.Hash
+	hashes = append(hashes, createTestHeadersSegment(t, dir, 0, 100_000, 10, logger)...)
+	hashes = append(hashes, createTestHeadersSegment(t, dir, 100_000, 200_000, 10, logger)...)
+	tail := createTestHeadersSegment(t, dir, 200_000, 210_000, 10, logger)
+
+	s := newRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dir, []snaptype.Type{coresnaptype.Headers}, 0, logger)
+	defer s.Close()
+	require.NoError(t, s.ReopenFolder())
+	require.Zero(t, s.HeadersByHashTo())
+
+	built, err := BuildHeadersByHashIdx(ctx, s, "test", dir, logger)
+	require.NoError(t, err)
+	require.True(t, built)
+	require.Equal(t, uint64(200_000), s.HeadersByHashTo())
+
+	// nothing new to cover
+	built, err = BuildHeadersByHashIdx(ctx, s, "test", dir, logger)
+	require.NoError(t, err)
+	require.False(t, built)
+
+	// index survives reopen
+	require.NoError(t, s.ReopenFolder())
+	require.Equal(t, uint64(200_000), s.HeadersByHashTo())
+
+	_, tx := memdb.NewTestTx(t)
+	br := NewBlockReader(s, nil)
+	view := s.View()
+	buf := make([]byte, 128)
+	for _, hash := range hashes {
+		h, covered, err := br.headerFromSnapshotByHashIdx(hash, view, buf)
+		require.NoError(t, err)
+		require.NotNil(t, h)
+		require.Equal(t, hash, h.Hash())
+		require.Equal(t, uint64(200_000), covered)
+	}
+	for _, hash := range append(tail, common.Hash{1}) {
+		h, _, err := br.headerFromSnapshotByHashIdx(hash, view, buf)
+		require.NoError(t, err)
+		require.Nil(t, h)
+	}
+	view.Close()
+
+	for i, hash := range append(hashes, tail...) {
+		h, err := br.HeaderByHash(ctx, tx, hash)
+		require.NoError(t, err)
+		require.NotNil(t, h, i)
+		require.Equal(t, hash, h.Hash())
+	}
+	h, err := br.HeaderByHash(ctx, tx, common.Hash{1})
+	require.NoError(t, err)
+	require.Nil(t, h)
+
+	// without index all segments are searched
+	require.NoError(t, os.Remove(filepath.Join(dir, HeadersByHashFileName)))
+	require.NoError(t, s.ReopenFolder())
+	require.Zero(t, s.HeadersByHashTo())
+	h, err = br.HeaderByHash(ctx, tx, hashes[3])
+	require.NoError(t, err)
+	require.Equal(t, hashes[3], h.Hash())
+}
+
+func TestFullHeadersTo(t *testing.T) {
+	segs := func(ranges ...Range) (res []*Segment) {
+		for _, r := range ranges {
+			res = append(res, &Segment{Range: r, Decompressor: &seg.Decompressor{}})
+		}
+		return res
+	}
+	require.Zero(t, fullHeadersTo("test", nil))
+	require.Zero(t, fullHeadersTo("test", segs(Range{0, 10_000})))
+	require.Equal(t, uint64(200_000), fullHeadersTo("test", segs(Range{0, 100_000}, Range{100_000, 200_000}, Range{200_000, 210_000})))
+	// gaps are not covered
+	require.Equal(t, uint64(100_000), fullHeadersTo("test", segs(Range{0, 100_000}, Range{200_000, 300_000})))
+	require.Zero(t, fullHeadersTo("test", segs(Range{100_000, 200_000})))
+}
@@ -84,7 +84,13 @@ func TraceTx(
 	stream *jsoniter.Stream,
 	callTimeout time.Duration,
 ) error {
-	tracer, streaming, cancel, err := AssembleTracer(ctx, config, txCtx.TxHash, stream, callTimeout)
+	tracerCtx := &tracers.Context{TxHash: txCtx.TxHash, BlockNumber: blockCtx.BlockNumber}
+	if ibs, ok := ibs.(*state.IntraBlockState); ok {
+		// txCtx.TxHash is not set by ComputeTxEnv, the hash of the traced transaction is the one of the ibs tx context
+		tracerCtx.TxHash, tracerCtx.BlockHash, tracerCtx.TxIndex = ibs.TxHash(), ibs.BlockHash(), ibs.TxIndex()
+	}
+
+	tracer, streaming, cancel, err := AssembleTracer(ctx, config, tracerCtx, stream, callTimeout)
 	if err != nil {
 		stream.WriteNil()
 		return err
@@ -103,7 +109,7 @@ func TraceTx(
 func AssembleTracer(
 	ctx context.Context,
 	config *tracers.TraceConfig,
-	txHash libcommon.Hash,
+	tracerCtx *tracers.Context,
 	stream *jsoniter.Stream,
 	callTimeout time.Duration,
 ) (vm.EVMLogger, bool, context.CancelFunc, error) {
@@ -125,7 +131,7 @@ func AssembleTracer(
 		if config != nil && config.TracerConfig != nil {
 			cfg = *config.TracerConfig
 		}
-		tracer, err := tracers.New(*config.Tracer, &tracers.Context{TxHash: txHash}, cfg)
+		tracer, err := tracers.New(*config.Tracer, tracerCtx, cfg)
 		if err != nil {
 			return nil, false, func() {}, err
 		}